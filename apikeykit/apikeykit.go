@@ -0,0 +1,123 @@
+// Package apikeykit issues and verifies API keys. A key looks like "acme_<id>_<secret><checksum>": the
+// prefix names who issued it, so leaked keys are easy to spot in code and logs; the ID finds the key in
+// a Store; and the checksum lets Verify reject mistyped or made-up keys without a lookup. Only the key's
+// SHA-256 hash is stored, with its name, owner, scopes, and expiry.
+//
+// Keys are stored in DynamoDB with NewDynamoDBStore or Postgres with NewPostgresStore, and verified
+// for requests by the echokit APIKeyAuthenticator and the ginkit APIKeyAuthentication middleware.
+package apikeykit
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"net/http"
+	"regexp"
+	"strings"
+
+	"github.com/half-ogre/go-kit/kit"
+)
+
+// The lengths of the parts of a key after its prefix
+const (
+	idLength       = 12
+	secretLength   = 32
+	checksumLength = 6
+)
+
+const base62 = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz"
+
+// HeaderAPIKey is the header FromRequest reads a key from, besides an Authorization bearer token
+const HeaderAPIKey = "X-API-Key"
+
+// ErrMalformedKey is returned by Parse for a string that isn't a key, or whose checksum doesn't match
+var ErrMalformedKey = errors.New("malformed API key")
+
+var prefixPattern = regexp.MustCompile(`^[a-z][a-z0-9]*$`)
+
+// Generate returns a new key with prefix, which must be lowercase letters and digits starting with a
+// letter, and the key's ID
+func Generate(prefix string) (key string, id string, err error) {
+	if !prefixPattern.MatchString(prefix) {
+		return "", "", fmt.Errorf("invalid API key prefix %q: must be lowercase letters and digits", prefix)
+	}
+
+	id, err = randomBase62(idLength)
+	if err != nil {
+		return "", "", err
+	}
+	secret, err := randomBase62(secretLength)
+	if err != nil {
+		return "", "", err
+	}
+
+	body := prefix + "_" + id + "_" + secret
+	return body + checksum(body), id, nil
+}
+
+// Parse returns the prefix and ID of key, or ErrMalformedKey if it isn't a key or its checksum doesn't
+// match
+func Parse(key string) (prefix string, id string, err error) {
+	parts := strings.Split(key, "_")
+	if len(parts) != 3 || !prefixPattern.MatchString(parts[0]) || len(parts[1]) != idLength || len(parts[2]) != secretLength+checksumLength {
+		return "", "", ErrMalformedKey
+	}
+
+	body := key[:len(key)-checksumLength]
+	if checksum(body) != key[len(key)-checksumLength:] {
+		return "", "", ErrMalformedKey
+	}
+	return parts[0], parts[1], nil
+}
+
+// Hash returns the hex SHA-256 hash of key, which is what's stored. Keys are random enough that a
+// salted or slow hash adds nothing.
+func Hash(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+// FromRequest returns the key in the X-API-Key header or, without one, the Authorization bearer token,
+// or "" if there's neither
+func FromRequest(r *http.Request) string {
+	if key := r.Header.Get(HeaderAPIKey); key != "" {
+		return key
+	}
+
+	scheme, token, ok := strings.Cut(r.Header.Get("Authorization"), " ")
+	if !ok || !strings.EqualFold(scheme, "bearer") {
+		return ""
+	}
+	return strings.TrimSpace(token)
+}
+
+// checksum returns the CRC-32 of body in base62, padded to checksumLength
+func checksum(body string) string {
+	sum := crc32.ChecksumIEEE([]byte(body))
+	digits := make([]byte, checksumLength)
+	for i := checksumLength - 1; i >= 0; i-- {
+		digits[i] = base62[sum%62]
+		sum /= 62
+	}
+	return string(digits)
+}
+
+// randomBase62 returns n random base62 digits, rejecting bytes that would bias them
+func randomBase62(n int) (string, error) {
+	digits := make([]byte, 0, n)
+	buffer := make([]byte, n+n/2)
+	for len(digits) < n {
+		if _, err := rand.Read(buffer); err != nil {
+			return "", kit.WrapError(err, "error reading random bytes")
+		}
+		for _, b := range buffer {
+			if b < 248 && len(digits) < n {
+				digits = append(digits, base62[b%62])
+			}
+		}
+	}
+	return string(digits), nil
+}
@@ -0,0 +1,86 @@
+package apikeykit
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGenerate(t *testing.T) {
+	t.Run("returns_a_key_that_parses_to_its_prefix_and_id", func(t *testing.T) {
+		key, id, err := Generate("theprefix")
+
+		assert.NoError(t, err)
+		assert.True(t, strings.HasPrefix(key, "theprefix_"+id+"_"))
+		assert.Len(t, key, len("theprefix_")+idLength+1+secretLength+checksumLength)
+		prefix, parsedID, err := Parse(key)
+		assert.NoError(t, err)
+		assert.Equal(t, "theprefix", prefix)
+		assert.Equal(t, id, parsedID)
+	})
+
+	t.Run("returns_a_different_key_each_time", func(t *testing.T) {
+		key, _, _ := Generate("theprefix")
+		anotherKey, _, _ := Generate("theprefix")
+
+		assert.NotEqual(t, key, anotherKey)
+	})
+
+	t.Run("returns_an_error_for_an_invalid_prefix", func(t *testing.T) {
+		_, _, err := Generate("the_prefix")
+
+		assert.EqualError(t, err, `invalid API key prefix "the_prefix": must be lowercase letters and digits`)
+	})
+}
+
+func TestParse(t *testing.T) {
+	t.Run("returns_an_error_for_a_changed_key", func(t *testing.T) {
+		key, _, _ := Generate("theprefix")
+		changed := key[:len(key)-checksumLength-1] + "x" + key[len(key)-checksumLength:]
+		if changed == key {
+			changed = key[:len(key)-checksumLength-1] + "y" + key[len(key)-checksumLength:]
+		}
+
+		_, _, err := Parse(changed)
+
+		assert.ErrorIs(t, err, ErrMalformedKey)
+	})
+
+	t.Run("returns_an_error_for_a_string_that_is_not_a_key", func(t *testing.T) {
+		_, _, err := Parse("not a key")
+
+		assert.ErrorIs(t, err, ErrMalformedKey)
+	})
+}
+
+func TestHash(t *testing.T) {
+	t.Run("returns_the_hex_sha256", func(t *testing.T) {
+		assert.Equal(t, "2c26b46b68ffc68ff99b453c1d30413413422d706483bfa0f98a5e886266e7ae", Hash("foo"))
+	})
+}
+
+func TestFromRequest(t *testing.T) {
+	t.Run("returns_the_api_key_header", func(t *testing.T) {
+		r := httptest.NewRequest("GET", "/", nil)
+		r.Header.Set("X-API-Key", "theKey")
+		r.Header.Set("Authorization", "Bearer anotherKey")
+
+		assert.Equal(t, "theKey", FromRequest(r))
+	})
+
+	t.Run("returns_the_bearer_token", func(t *testing.T) {
+		r := httptest.NewRequest("GET", "/", nil)
+		r.Header.Set("Authorization", "bearer theKey")
+
+		assert.Equal(t, "theKey", FromRequest(r))
+	})
+
+	t.Run("returns_empty_for_another_scheme", func(t *testing.T) {
+		r := httptest.NewRequest("GET", "/", nil)
+		r.Header.Set("Authorization", "Basic dXNlcjpwYXNz")
+
+		assert.Equal(t, "", FromRequest(r))
+	})
+}
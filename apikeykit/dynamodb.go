@@ -0,0 +1,96 @@
+package apikeykit
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/expression"
+	"github.com/half-ogre/go-kit/dynamodbkit"
+)
+
+// DynamoDBStore is a Store in a DynamoDB table with the partition key "id", using the dynamodbkit
+// client carried by the context or its default client
+type DynamoDBStore struct {
+	tableName string
+}
+
+// NewDynamoDBStore returns a store for the table named tableName
+func NewDynamoDBStore(tableName string) *DynamoDBStore {
+	return &DynamoDBStore{tableName: tableName}
+}
+
+// dynamoDBKey is a Key as it's stored in DynamoDB
+type dynamoDBKey struct {
+	ID        string            `dynamodbav:"id"`
+	Prefix    string            `dynamodbav:"prefix"`
+	Hash      string            `dynamodbav:"hash"`
+	Name      string            `dynamodbav:"name,omitempty"`
+	Owner     string            `dynamodbav:"owner,omitempty"`
+	Scopes    []string          `dynamodbav:"scopes,omitempty,stringset"`
+	Metadata  map[string]string `dynamodbav:"metadata,omitempty"`
+	CreatedAt time.Time         `dynamodbav:"created_at"`
+	ExpiresAt *time.Time        `dynamodbav:"expires_at,omitempty"`
+	RevokedAt *time.Time        `dynamodbav:"revoked_at,omitempty"`
+}
+
+// PutKey puts key unless a key with its ID exists
+func (s *DynamoDBStore) PutKey(ctx context.Context, key Key) error {
+	item := dynamoDBKey{
+		ID:        key.ID,
+		Prefix:    key.Prefix,
+		Hash:      key.Hash,
+		Name:      key.Name,
+		Owner:     key.Owner,
+		Scopes:    key.Scopes,
+		Metadata:  key.Metadata,
+		CreatedAt: key.CreatedAt,
+		ExpiresAt: timePointer(key.ExpiresAt),
+		RevokedAt: timePointer(key.RevokedAt),
+	}
+	return dynamodbkit.PutItem(ctx, s.tableName, item, dynamodbkit.WithPutItemCondition("attribute_not_exists(id)"))
+}
+
+func (s *DynamoDBStore) GetKey(ctx context.Context, id string) (*Key, error) {
+	item, err := dynamodbkit.GetItem[dynamoDBKey](ctx, s.tableName, "id", id)
+	if err != nil || item == nil {
+		return nil, err
+	}
+
+	return &Key{
+		ID:        item.ID,
+		Prefix:    item.Prefix,
+		Hash:      item.Hash,
+		Name:      item.Name,
+		Owner:     item.Owner,
+		Scopes:    item.Scopes,
+		Metadata:  item.Metadata,
+		CreatedAt: item.CreatedAt,
+		ExpiresAt: timeValue(item.ExpiresAt),
+		RevokedAt: timeValue(item.RevokedAt),
+	}, nil
+}
+
+func (s *DynamoDBStore) RevokeKey(ctx context.Context, id string, revokedAt time.Time) error {
+	_, err := dynamodbkit.UpdateItem[dynamoDBKey](ctx, s.tableName, "id", id,
+		dynamodbkit.WithUpdateSet("revoked_at", revokedAt),
+		dynamodbkit.WithUpdateConditionExpression(expression.AttributeExists(expression.Name("id"))))
+	if errors.Is(err, dynamodbkit.ErrConditionFailed) {
+		return ErrKeyNotFound
+	}
+	return err
+}
+
+func timePointer(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+	return &t
+}
+
+func timeValue(t *time.Time) time.Time {
+	if t == nil {
+		return time.Time{}
+	}
+	return *t
+}
@@ -0,0 +1,96 @@
+package apikeykit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/half-ogre/go-kit/dynamodbkit"
+)
+
+// newFakeTable returns a context with a dynamodbkit client whose table keeps items by "id"
+func newFakeTable(t *testing.T) (context.Context, map[string]map[string]types.AttributeValue) {
+	items := map[string]map[string]types.AttributeValue{}
+	fakeDB := &dynamodbkit.FakeDynamoDB{
+		PutItemFake: func(ctx context.Context, params *dynamodb.PutItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.PutItemOutput, error) {
+			assert.Equal(t, "attribute_not_exists(id)", *params.ConditionExpression)
+			items[params.Item["id"].(*types.AttributeValueMemberS).Value] = params.Item
+			return &dynamodb.PutItemOutput{}, nil
+		},
+		GetItemFake: func(ctx context.Context, params *dynamodb.GetItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.GetItemOutput, error) {
+			return &dynamodb.GetItemOutput{Item: items[params.Key["id"].(*types.AttributeValueMemberS).Value]}, nil
+		},
+		UpdateItemFake: func(ctx context.Context, params *dynamodb.UpdateItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.UpdateItemOutput, error) {
+			item, ok := items[params.Key["id"].(*types.AttributeValueMemberS).Value]
+			if !ok {
+				return nil, &types.ConditionalCheckFailedException{}
+			}
+			var names []string
+			for _, name := range params.ExpressionAttributeNames {
+				names = append(names, name)
+			}
+			assert.Contains(t, names, "revoked_at")
+			for _, value := range params.ExpressionAttributeValues {
+				item["revoked_at"] = value
+			}
+			return &dynamodb.UpdateItemOutput{}, nil
+		},
+	}
+	return dynamodbkit.WithClient(context.Background(), dynamodbkit.NewClient(fakeDB)), items
+}
+
+func TestDynamoDBStore(t *testing.T) {
+	t.Run("gets_the_key_it_puts", func(t *testing.T) {
+		ctx, _ := newFakeTable(t)
+		store := NewDynamoDBStore("theTable")
+		theKey := Key{
+			ID:        "theID",
+			Prefix:    "theprefix",
+			Hash:      "theHash",
+			Name:      "theName",
+			Scopes:    []string{"orders:read"},
+			CreatedAt: time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
+			ExpiresAt: time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC),
+		}
+
+		err := store.PutKey(ctx, theKey)
+		assert.NoError(t, err)
+		key, err := store.GetKey(ctx, "theID")
+
+		assert.NoError(t, err)
+		assert.Equal(t, &theKey, key)
+	})
+
+	t.Run("returns_nil_for_a_missing_key", func(t *testing.T) {
+		ctx, _ := newFakeTable(t)
+
+		key, err := NewDynamoDBStore("theTable").GetKey(ctx, "theID")
+
+		assert.NoError(t, err)
+		assert.Nil(t, key)
+	})
+
+	t.Run("revokes_the_key", func(t *testing.T) {
+		ctx, items := newFakeTable(t)
+		store := NewDynamoDBStore("theTable")
+		_ = store.PutKey(ctx, Key{ID: "theID", Prefix: "theprefix", Hash: "theHash"})
+		theRevokedAt := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+
+		err := store.RevokeKey(ctx, "theID", theRevokedAt)
+
+		assert.NoError(t, err)
+		assert.Equal(t, &types.AttributeValueMemberS{Value: "2024-01-02T03:04:05Z"}, items["theID"]["revoked_at"])
+	})
+
+	t.Run("returns_not_found_revoking_a_missing_key", func(t *testing.T) {
+		ctx, _ := newFakeTable(t)
+
+		err := NewDynamoDBStore("theTable").RevokeKey(ctx, "theID", time.Now())
+
+		assert.ErrorIs(t, err, ErrKeyNotFound)
+	})
+}
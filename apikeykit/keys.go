@@ -0,0 +1,203 @@
+package apikeykit
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/half-ogre/go-kit/kit"
+)
+
+var (
+	// ErrInvalidKey is returned by Verify for a key that's malformed, unknown, or doesn't match the
+	// stored hash; which of these isn't said, so callers can't probe for IDs
+	ErrInvalidKey = errors.New("invalid API key")
+	// ErrKeyExpired is returned by Verify for a key past its expiry
+	ErrKeyExpired = errors.New("API key expired")
+	// ErrKeyRevoked is returned by Verify for a revoked key
+	ErrKeyRevoked = errors.New("API key revoked")
+	// ErrKeyNotFound is returned by Revoke, and by a Store's RevokeKey, for an ID without a key
+	ErrKeyNotFound = errors.New("API key not found")
+)
+
+// ScopeAll is the scope that grants every other scope
+const ScopeAll = "*"
+
+// Key is what's stored about an issued key; the key itself is only returned by Issue
+type Key struct {
+	ID        string
+	Prefix    string
+	Hash      string
+	Name      string
+	Owner     string
+	Scopes    []string
+	Metadata  map[string]string
+	CreatedAt time.Time
+	// ExpiresAt is when the key stops being valid; zero means never
+	ExpiresAt time.Time
+	// RevokedAt is when the key was revoked; zero means it hasn't been
+	RevokedAt time.Time
+}
+
+// HasScope reports whether the key has scope, or ScopeAll
+func (k *Key) HasScope(scope string) bool {
+	return slices.Contains(k.Scopes, scope) || slices.Contains(k.Scopes, ScopeAll)
+}
+
+// HasScopes reports whether the key has every one of scopes
+func (k *Key) HasScopes(scopes ...string) bool {
+	for _, scope := range scopes {
+		if !k.HasScope(scope) {
+			return false
+		}
+	}
+	return true
+}
+
+// Store stores keys by ID
+type Store interface {
+	// PutKey stores a new key
+	PutKey(ctx context.Context, key Key) error
+	// GetKey returns the key with id, or nil if there's none
+	GetKey(ctx context.Context, id string) (*Key, error)
+	// RevokeKey sets the revocation time of the key with id, or returns ErrKeyNotFound
+	RevokeKey(ctx context.Context, id string, revokedAt time.Time) error
+}
+
+// IssueOption configures Issue
+type IssueOption func(*Key)
+
+// WithName sets the key's name, e.g. what it's for, for showing in a list of keys
+func WithName(name string) IssueOption {
+	return func(k *Key) {
+		k.Name = name
+	}
+}
+
+// WithOwner sets who the key acts as, e.g. a user or service account ID
+func WithOwner(owner string) IssueOption {
+	return func(k *Key) {
+		k.Owner = owner
+	}
+}
+
+// WithScopes sets what the key may do, e.g. "orders:read"; ScopeAll grants everything
+func WithScopes(scopes ...string) IssueOption {
+	return func(k *Key) {
+		k.Scopes = scopes
+	}
+}
+
+// WithExpiresAt sets when the key stops being valid
+func WithExpiresAt(expiresAt time.Time) IssueOption {
+	return func(k *Key) {
+		k.ExpiresAt = expiresAt
+	}
+}
+
+// WithMetadata sets metadata stored with the key, e.g. the ticket it was issued for
+func WithMetadata(metadata map[string]string) IssueOption {
+	return func(k *Key) {
+		k.Metadata = metadata
+	}
+}
+
+// Issue generates a key with prefix, stores its Key, and returns the key, which is shown to its owner
+// once and can't be recovered, and its Key
+func Issue(ctx context.Context, store Store, prefix string, options ...IssueOption) (string, *Key, error) {
+	plaintext, id, err := Generate(prefix)
+	if err != nil {
+		return "", nil, err
+	}
+
+	key := Key{
+		ID:        id,
+		Prefix:    prefix,
+		Hash:      Hash(plaintext),
+		CreatedAt: time.Now().UTC(),
+	}
+	for _, option := range options {
+		option(&key)
+	}
+
+	if err := store.PutKey(ctx, key); err != nil {
+		return "", nil, kit.WrapError(err, "error storing API key %s", id)
+	}
+	return plaintext, &key, nil
+}
+
+// Verify returns the stored Key for key. It returns ErrInvalidKey for a key that isn't valid,
+// ErrKeyExpired or ErrKeyRevoked for one that no longer is, and errors getting the key from store.
+func Verify(ctx context.Context, store Store, key string) (*Key, error) {
+	prefix, id, err := Parse(key)
+	if err != nil {
+		return nil, ErrInvalidKey
+	}
+
+	stored, err := store.GetKey(ctx, id)
+	if err != nil {
+		return nil, kit.WrapError(err, "error getting API key %s", id)
+	}
+	if stored == nil || stored.Prefix != prefix || subtle.ConstantTimeCompare([]byte(stored.Hash), []byte(Hash(key))) != 1 {
+		return nil, ErrInvalidKey
+	}
+
+	if !stored.RevokedAt.IsZero() {
+		return nil, ErrKeyRevoked
+	}
+	if !stored.ExpiresAt.IsZero() && !time.Now().Before(stored.ExpiresAt) {
+		return nil, ErrKeyExpired
+	}
+	return stored, nil
+}
+
+// Revoke revokes the key with id, so Verify rejects it from now on
+func Revoke(ctx context.Context, store Store, id string) error {
+	if err := store.RevokeKey(ctx, id, time.Now().UTC()); err != nil {
+		return kit.WrapError(err, "error revoking API key %s", id)
+	}
+	return nil
+}
+
+// MemoryStore is a Store in memory, e.g. for tests
+type MemoryStore struct {
+	mu   sync.Mutex
+	keys map[string]Key
+}
+
+// NewMemoryStore returns an empty MemoryStore
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{keys: map[string]Key{}}
+}
+
+func (s *MemoryStore) PutKey(ctx context.Context, key Key) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.keys[key.ID] = key
+	return nil
+}
+
+func (s *MemoryStore) GetKey(ctx context.Context, id string) (*Key, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	key, ok := s.keys[id]
+	if !ok {
+		return nil, nil
+	}
+	return &key, nil
+}
+
+func (s *MemoryStore) RevokeKey(ctx context.Context, id string, revokedAt time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	key, ok := s.keys[id]
+	if !ok {
+		return ErrKeyNotFound
+	}
+	key.RevokedAt = revokedAt
+	s.keys[id] = key
+	return nil
+}
@@ -0,0 +1,139 @@
+package apikeykit
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// failingStore is a Store whose every method returns err
+type failingStore struct {
+	err error
+}
+
+func (s *failingStore) PutKey(ctx context.Context, key Key) error { return s.err }
+
+func (s *failingStore) GetKey(ctx context.Context, id string) (*Key, error) { return nil, s.err }
+
+func (s *failingStore) RevokeKey(ctx context.Context, id string, revokedAt time.Time) error {
+	return s.err
+}
+
+func TestIssue(t *testing.T) {
+	t.Run("stores_the_hash_and_options", func(t *testing.T) {
+		store := NewMemoryStore()
+		theExpiry := time.Now().Add(time.Hour)
+
+		plaintext, key, err := Issue(context.Background(), store, "theprefix",
+			WithName("theName"), WithOwner("theOwner"), WithScopes("orders:read"), WithExpiresAt(theExpiry), WithMetadata(map[string]string{"ticket": "theTicket"}))
+
+		assert.NoError(t, err)
+		stored, _ := store.GetKey(context.Background(), key.ID)
+		assert.Equal(t, key, stored)
+		assert.Equal(t, Hash(plaintext), stored.Hash)
+		assert.Equal(t, "theprefix", stored.Prefix)
+		assert.Equal(t, "theName", stored.Name)
+		assert.Equal(t, "theOwner", stored.Owner)
+		assert.Equal(t, []string{"orders:read"}, stored.Scopes)
+		assert.Equal(t, theExpiry, stored.ExpiresAt)
+		assert.Equal(t, map[string]string{"ticket": "theTicket"}, stored.Metadata)
+	})
+
+	t.Run("returns_the_store_error", func(t *testing.T) {
+		_, _, err := Issue(context.Background(), &failingStore{err: errors.New("the store error")}, "theprefix")
+
+		assert.ErrorContains(t, err, "the store error")
+	})
+}
+
+func TestVerify(t *testing.T) {
+	t.Run("returns_the_stored_key", func(t *testing.T) {
+		store := NewMemoryStore()
+		plaintext, key, _ := Issue(context.Background(), store, "theprefix", WithScopes("orders:read"))
+
+		verified, err := Verify(context.Background(), store, plaintext)
+
+		assert.NoError(t, err)
+		assert.Equal(t, key, verified)
+	})
+
+	t.Run("returns_invalid_for_an_unknown_key", func(t *testing.T) {
+		plaintext, _, _ := Generate("theprefix")
+
+		_, err := Verify(context.Background(), NewMemoryStore(), plaintext)
+
+		assert.ErrorIs(t, err, ErrInvalidKey)
+	})
+
+	t.Run("returns_invalid_for_a_key_with_another_secret", func(t *testing.T) {
+		store := NewMemoryStore()
+		_, key, _ := Issue(context.Background(), store, "theprefix")
+		key.Hash = Hash("anotherKey")
+		_ = store.PutKey(context.Background(), *key)
+		plaintext := "theprefix_" + key.ID + "_" + "aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa"
+		plaintext += checksum(plaintext)
+
+		_, err := Verify(context.Background(), store, plaintext)
+
+		assert.ErrorIs(t, err, ErrInvalidKey)
+	})
+
+	t.Run("returns_invalid_for_a_malformed_key", func(t *testing.T) {
+		_, err := Verify(context.Background(), &failingStore{err: errors.New("the store should not be called")}, "not a key")
+
+		assert.ErrorIs(t, err, ErrInvalidKey)
+	})
+
+	t.Run("returns_expired_for_a_key_past_its_expiry", func(t *testing.T) {
+		store := NewMemoryStore()
+		plaintext, _, _ := Issue(context.Background(), store, "theprefix", WithExpiresAt(time.Now().Add(-time.Second)))
+
+		_, err := Verify(context.Background(), store, plaintext)
+
+		assert.ErrorIs(t, err, ErrKeyExpired)
+	})
+
+	t.Run("returns_revoked_for_a_revoked_key", func(t *testing.T) {
+		store := NewMemoryStore()
+		plaintext, key, _ := Issue(context.Background(), store, "theprefix")
+		assert.NoError(t, Revoke(context.Background(), store, key.ID))
+
+		_, err := Verify(context.Background(), store, plaintext)
+
+		assert.ErrorIs(t, err, ErrKeyRevoked)
+	})
+
+	t.Run("returns_the_store_error", func(t *testing.T) {
+		plaintext, id, _ := Generate("theprefix")
+
+		_, err := Verify(context.Background(), &failingStore{err: errors.New("the store error")}, plaintext)
+
+		assert.EqualError(t, err, "error getting API key "+id+": the store error")
+	})
+}
+
+func TestRevoke(t *testing.T) {
+	t.Run("returns_not_found_for_an_unknown_id", func(t *testing.T) {
+		err := Revoke(context.Background(), NewMemoryStore(), "theID")
+
+		assert.ErrorIs(t, err, ErrKeyNotFound)
+	})
+}
+
+func TestKeyHasScopes(t *testing.T) {
+	t.Run("has_its_scopes", func(t *testing.T) {
+		key := &Key{Scopes: []string{"orders:read", "orders:write"}}
+
+		assert.True(t, key.HasScopes("orders:read", "orders:write"))
+		assert.False(t, key.HasScopes("orders:read", "users:read"))
+	})
+
+	t.Run("has_every_scope_with_scope_all", func(t *testing.T) {
+		key := &Key{Scopes: []string{ScopeAll}}
+
+		assert.True(t, key.HasScope("users:delete"))
+	})
+}
@@ -0,0 +1,104 @@
+package apikeykit
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/half-ogre/go-kit/kit"
+	"github.com/half-ogre/go-kit/pgkit"
+)
+
+// PostgresStore is a Store in a Postgres table created by CreatePostgresTableSQL
+type PostgresStore struct {
+	db    pgkit.DB
+	table string
+}
+
+// NewPostgresStore returns a store for the table named table in db
+func NewPostgresStore(db pgkit.DB, table string) *PostgresStore {
+	return &PostgresStore{db: db, table: pgx.Identifier(strings.Split(table, ".")).Sanitize()}
+}
+
+// CreatePostgresTableSQL returns the DDL for a key table, for use in a migration
+func CreatePostgresTableSQL(table string) string {
+	return fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s (
+	id TEXT PRIMARY KEY,
+	prefix TEXT NOT NULL,
+	hash TEXT NOT NULL,
+	name TEXT NOT NULL DEFAULT '',
+	owner TEXT NOT NULL DEFAULT '',
+	scopes JSONB NOT NULL DEFAULT '[]',
+	metadata JSONB NOT NULL DEFAULT '{}',
+	created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+	expires_at TIMESTAMPTZ,
+	revoked_at TIMESTAMPTZ
+)`, pgx.Identifier(strings.Split(table, ".")).Sanitize())
+}
+
+func (s *PostgresStore) PutKey(ctx context.Context, key Key) error {
+	scopes, err := json.Marshal(key.Scopes)
+	if err != nil {
+		return kit.WrapError(err, "error marshaling scopes")
+	}
+	metadata, err := json.Marshal(key.Metadata)
+	if err != nil {
+		return kit.WrapError(err, "error marshaling metadata")
+	}
+
+	query := fmt.Sprintf(
+		"INSERT INTO %s (id, prefix, hash, name, owner, scopes, metadata, created_at, expires_at, revoked_at) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)",
+		s.table)
+	_, err = s.db.Exec(ctx, query, key.ID, key.Prefix, key.Hash, key.Name, key.Owner, string(scopes), string(metadata), key.CreatedAt, timePointer(key.ExpiresAt), timePointer(key.RevokedAt))
+	if err != nil {
+		return kit.WrapError(err, "error inserting API key %s into %s", key.ID, s.table)
+	}
+	return nil
+}
+
+func (s *PostgresStore) GetKey(ctx context.Context, id string) (*Key, error) {
+	query := fmt.Sprintf("SELECT prefix, hash, name, owner, scopes, metadata, created_at, expires_at, revoked_at FROM %s WHERE id = $1", s.table)
+
+	key := Key{ID: id}
+	var scopes, metadata []byte
+	var expiresAt, revokedAt *time.Time
+	err := s.db.QueryRow(ctx, query, id).Scan(&key.Prefix, &key.Hash, &key.Name, &key.Owner, &scopes, &metadata, &key.CreatedAt, &expiresAt, &revokedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, kit.WrapError(err, "error selecting API key %s from %s", id, s.table)
+	}
+
+	if err := json.Unmarshal(scopes, &key.Scopes); err != nil {
+		return nil, kit.WrapError(err, "error unmarshaling scopes of API key %s", id)
+	}
+	if err := json.Unmarshal(metadata, &key.Metadata); err != nil {
+		return nil, kit.WrapError(err, "error unmarshaling metadata of API key %s", id)
+	}
+	key.ExpiresAt = timeValue(expiresAt)
+	key.RevokedAt = timeValue(revokedAt)
+	return &key, nil
+}
+
+func (s *PostgresStore) RevokeKey(ctx context.Context, id string, revokedAt time.Time) error {
+	query := fmt.Sprintf("UPDATE %s SET revoked_at = $2 WHERE id = $1", s.table)
+	result, err := s.db.Exec(ctx, query, id, revokedAt)
+	if err != nil {
+		return kit.WrapError(err, "error updating API key %s in %s", id, s.table)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return kit.WrapError(err, "error getting rows affected")
+	}
+	if rows == 0 {
+		return ErrKeyNotFound
+	}
+	return nil
+}
@@ -0,0 +1,97 @@
+package apikeykit
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/half-ogre/go-kit/pgkit"
+)
+
+type fakeResult struct {
+	rowsAffected int64
+}
+
+func (r fakeResult) LastInsertId() (int64, error) { return 0, errors.New("not supported") }
+
+func (r fakeResult) RowsAffected() (int64, error) { return r.rowsAffected, nil }
+
+func TestCreatePostgresTableSQL(t *testing.T) {
+	t.Run("quotes_the_table_name", func(t *testing.T) {
+		assert.Contains(t, CreatePostgresTableSQL("auth.api_keys"), `CREATE TABLE IF NOT EXISTS "auth"."api_keys" (`)
+	})
+}
+
+func TestPostgresStore(t *testing.T) {
+	t.Run("inserts_the_key", func(t *testing.T) {
+		var actualQuery string
+		var actualArgs []any
+		db := &pgkit.FakeDB{
+			ExecFake: func(ctx context.Context, query string, args ...any) (sql.Result, error) {
+				actualQuery, actualArgs = query, args
+				return fakeResult{rowsAffected: 1}, nil
+			},
+		}
+		theCreatedAt := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+
+		err := NewPostgresStore(db, "api_keys").PutKey(context.Background(), Key{ID: "theID", Prefix: "theprefix", Hash: "theHash", Scopes: []string{"orders:read"}, CreatedAt: theCreatedAt})
+
+		assert.NoError(t, err)
+		assert.Contains(t, actualQuery, `INSERT INTO "api_keys"`)
+		assert.Equal(t, []any{"theID", "theprefix", "theHash", "", "", `["orders:read"]`, "null", theCreatedAt, (*time.Time)(nil), (*time.Time)(nil)}, actualArgs)
+	})
+
+	t.Run("scans_the_key", func(t *testing.T) {
+		theExpiresAt := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+		db := &pgkit.FakeDB{
+			QueryRowFake: func(ctx context.Context, query string, args ...any) pgkit.Row {
+				assert.Equal(t, []any{"theID"}, args)
+				return &pgkit.FakeRow{
+					ScanFake: func(dest ...any) error {
+						*dest[0].(*string) = "theprefix"
+						*dest[1].(*string) = "theHash"
+						*dest[4].(*[]byte) = []byte(`["orders:read"]`)
+						*dest[5].(*[]byte) = []byte(`{"ticket":"theTicket"}`)
+						*dest[7].(**time.Time) = &theExpiresAt
+						return nil
+					},
+				}
+			},
+		}
+
+		key, err := NewPostgresStore(db, "api_keys").GetKey(context.Background(), "theID")
+
+		assert.NoError(t, err)
+		assert.Equal(t, &Key{ID: "theID", Prefix: "theprefix", Hash: "theHash", Scopes: []string{"orders:read"}, Metadata: map[string]string{"ticket": "theTicket"}, ExpiresAt: theExpiresAt}, key)
+	})
+
+	t.Run("returns_nil_for_a_missing_key", func(t *testing.T) {
+		db := &pgkit.FakeDB{
+			QueryRowFake: func(ctx context.Context, query string, args ...any) pgkit.Row {
+				return &pgkit.FakeRow{ScanFake: func(dest ...any) error { return pgx.ErrNoRows }}
+			},
+		}
+
+		key, err := NewPostgresStore(db, "api_keys").GetKey(context.Background(), "theID")
+
+		assert.NoError(t, err)
+		assert.Nil(t, key)
+	})
+
+	t.Run("returns_not_found_revoking_a_missing_key", func(t *testing.T) {
+		db := &pgkit.FakeDB{
+			ExecFake: func(ctx context.Context, query string, args ...any) (sql.Result, error) {
+				return fakeResult{}, nil
+			},
+		}
+
+		err := NewPostgresStore(db, "api_keys").RevokeKey(context.Background(), "theID", time.Now())
+
+		assert.ErrorIs(t, err, ErrKeyNotFound)
+	})
+}
@@ -0,0 +1,304 @@
+// Package authzkit is a policy-based authorization engine. Policies declare which actions on which
+// resource types they allow or deny, optionally with a condition on the principal's and resource's
+// attributes (e.g. `resource.owner == principal.id`). Every evaluation returns a Decision that
+// explains which policy decided it, for audit logs. Use an Engine directly in the service layer, or
+// through the echokit and ginkit RequirePolicy middleware.
+package authzkit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"path"
+	"slices"
+
+	"github.com/half-ogre/go-kit/logkit"
+	"github.com/half-ogre/go-kit/logkit/logfields"
+)
+
+var logger = logkit.Logger("authzkit")
+
+// ErrDenied is returned by Authorize when a request is not allowed
+var ErrDenied = errors.New("not authorized")
+
+// Effect is whether a policy allows or denies the requests it matches
+type Effect string
+
+const (
+	EffectAllow Effect = "allow"
+	EffectDeny  Effect = "deny"
+)
+
+// Principal is who is making a request, e.g. the authenticated user
+type Principal struct {
+	ID          string
+	Roles       []string
+	Permissions []string
+	Attributes  map[string]any
+}
+
+// Resource is what a request acts on. Type is matched against a policy's Resources and Attributes
+// are available to its condition, e.g. the resource's owner.
+type Resource struct {
+	Type       string
+	ID         string
+	Attributes map[string]any
+}
+
+// Request is a principal asking to perform an action on a resource. Context carries anything else a
+// condition can check, e.g. the time of day or the caller's IP.
+type Request struct {
+	Principal Principal
+	Action    string
+	Resource  Resource
+	Context   map[string]any
+}
+
+// Condition is an attribute-based check a policy applies to the requests it matches
+type Condition func(req Request) (bool, error)
+
+// Policy allows or denies the actions it names on the resource types it names. Actions and Resources
+// are path.Match patterns, so "*" matches anything and "documents:*" matches every documents action.
+// A policy only matches a principal with all of its Roles and Permissions, and whose request passes
+// its Condition.
+type Policy struct {
+	ID          string
+	Description string
+	Effect      Effect
+	Actions     []string
+	Resources   []string
+	Roles       []string
+	Permissions []string
+	Condition   Condition
+}
+
+// PolicyResult is how one policy evaluated against a request
+type PolicyResult struct {
+	PolicyID string `json:"policy_id"`
+	Effect   Effect `json:"effect"`
+	Matched  bool   `json:"matched"`
+	Reason   string `json:"reason"`
+	Error    string `json:"error,omitempty"`
+}
+
+// Decision is the outcome of evaluating a request, with an explanation suitable for an audit log
+type Decision struct {
+	Allowed   bool           `json:"allowed"`
+	Principal string         `json:"principal"`
+	Action    string         `json:"action"`
+	Resource  string         `json:"resource"`
+	PolicyID  string         `json:"policy_id,omitempty"`
+	Reason    string         `json:"reason"`
+	Results   []PolicyResult `json:"results"`
+}
+
+// LogValue logs a decision as a group of its principal, action, resource, outcome, and reason
+func (d Decision) LogValue() slog.Value {
+	return slog.GroupValue(
+		slog.Bool("allowed", d.Allowed),
+		slog.String("principal", d.Principal),
+		slog.String("action", d.Action),
+		slog.String("resource", d.Resource),
+		slog.String("policy_id", d.PolicyID),
+		slog.String("reason", d.Reason),
+	)
+}
+
+// DeniedError is the error Authorize returns for a denied request; it matches ErrDenied with errors.Is
+type DeniedError struct {
+	Decision Decision
+}
+
+func (e *DeniedError) Error() string {
+	return fmt.Sprintf("%s: %s", ErrDenied, e.Decision.Reason)
+}
+
+func (e *DeniedError) Unwrap() error {
+	return ErrDenied
+}
+
+// EngineOption configures NewEngine
+type EngineOption func(*engineConfig)
+
+type engineConfig struct {
+	onDecision func(ctx context.Context, decision Decision)
+}
+
+// WithDecisionHook calls hook with every decision the engine makes, e.g. to write it to an audit log
+func WithDecisionHook(hook func(ctx context.Context, decision Decision)) EngineOption {
+	return func(config *engineConfig) {
+		config.onDecision = hook
+	}
+}
+
+// Engine evaluates requests against a set of policies. A request is allowed when at least one allow
+// policy matches it and no deny policy does; anything else is denied.
+type Engine struct {
+	policies   []Policy
+	onDecision func(ctx context.Context, decision Decision)
+}
+
+// NewEngine returns an engine for policies, or an error if a policy is invalid
+func NewEngine(policies []Policy, options ...EngineOption) (*Engine, error) {
+	config := &engineConfig{}
+	for _, option := range options {
+		option(config)
+	}
+
+	ids := map[string]bool{}
+	for i, policy := range policies {
+		if err := policy.validate(); err != nil {
+			return nil, fmt.Errorf("policy %d is invalid: %w", i, err)
+		}
+		if ids[policy.ID] {
+			return nil, fmt.Errorf("policy ID %q is used more than once", policy.ID)
+		}
+		ids[policy.ID] = true
+	}
+
+	return &Engine{policies: slices.Clone(policies), onDecision: config.onDecision}, nil
+}
+
+func (p Policy) validate() error {
+	if p.ID == "" {
+		return errors.New("policy ID cannot be empty")
+	}
+	if p.Effect != EffectAllow && p.Effect != EffectDeny {
+		return fmt.Errorf("policy %q has an unknown effect %q", p.ID, p.Effect)
+	}
+	if len(p.Actions) == 0 {
+		return fmt.Errorf("policy %q must name at least one action", p.ID)
+	}
+	if len(p.Resources) == 0 {
+		return fmt.Errorf("policy %q must name at least one resource", p.ID)
+	}
+	for _, pattern := range slices.Concat(p.Actions, p.Resources) {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return fmt.Errorf("policy %q has an invalid pattern %q: %w", p.ID, pattern, err)
+		}
+	}
+	return nil
+}
+
+// Evaluate decides whether req is allowed. A condition that returns an error doesn't match, so a
+// broken allow policy fails closed, and a broken deny policy denies the request.
+func (e *Engine) Evaluate(ctx context.Context, req Request) Decision {
+	decision := Decision{
+		Principal: req.Principal.ID,
+		Action:    req.Action,
+		Resource:  resourceName(req.Resource),
+	}
+
+	allowedBy, deniedBy := -1, -1
+	for i, policy := range e.policies {
+		result := policy.evaluate(req)
+		decision.Results = append(decision.Results, result)
+
+		if policy.Effect == EffectDeny && (result.Matched || result.Error != "") && deniedBy < 0 {
+			deniedBy = i
+		}
+		if policy.Effect == EffectAllow && result.Matched && allowedBy < 0 {
+			allowedBy = i
+		}
+	}
+
+	switch {
+	case deniedBy >= 0:
+		result := decision.Results[deniedBy]
+		decision.PolicyID = result.PolicyID
+		decision.Reason = fmt.Sprintf("denied by policy %q: %s", result.PolicyID, result.Reason)
+		if result.Error != "" {
+			decision.Reason += ": " + result.Error
+		}
+	case allowedBy >= 0:
+		result := decision.Results[allowedBy]
+		decision.Allowed = true
+		decision.PolicyID = result.PolicyID
+		decision.Reason = fmt.Sprintf("allowed by policy %q: %s", result.PolicyID, result.Reason)
+	default:
+		decision.Reason = fmt.Sprintf("no policy allows %q on %q", req.Action, decision.Resource)
+	}
+
+	logger.DebugContext(ctx, "authorization decision", logfields.UserSub(req.Principal.ID), slog.Any("decision", decision))
+
+	if e.onDecision != nil {
+		e.onDecision(ctx, decision)
+	}
+
+	return decision
+}
+
+// Authorize returns nil if req is allowed, and a *DeniedError matching ErrDenied if it isn't
+func (e *Engine) Authorize(ctx context.Context, req Request) error {
+	decision := e.Evaluate(ctx, req)
+	if !decision.Allowed {
+		return &DeniedError{Decision: decision}
+	}
+	return nil
+}
+
+// Can reports whether principal may perform action on resource, e.g. to decide whether to show a button
+func (e *Engine) Can(ctx context.Context, principal Principal, action string, resource Resource) bool {
+	return e.Evaluate(ctx, Request{Principal: principal, Action: action, Resource: resource}).Allowed
+}
+
+func (p Policy) evaluate(req Request) PolicyResult {
+	result := PolicyResult{PolicyID: p.ID, Effect: p.Effect}
+
+	if !matchesAny(p.Actions, req.Action) {
+		result.Reason = fmt.Sprintf("action %q is not one of %q", req.Action, p.Actions)
+		return result
+	}
+	if !matchesAny(p.Resources, req.Resource.Type) {
+		result.Reason = fmt.Sprintf("resource type %q is not one of %q", req.Resource.Type, p.Resources)
+		return result
+	}
+	for _, role := range p.Roles {
+		if !slices.Contains(req.Principal.Roles, role) {
+			result.Reason = fmt.Sprintf("principal does not have role %q", role)
+			return result
+		}
+	}
+	for _, permission := range p.Permissions {
+		if !slices.Contains(req.Principal.Permissions, permission) {
+			result.Reason = fmt.Sprintf("principal does not have permission %q", permission)
+			return result
+		}
+	}
+	if p.Condition != nil {
+		ok, err := p.Condition(req)
+		if err != nil {
+			result.Reason = "condition failed"
+			result.Error = err.Error()
+			return result
+		}
+		if !ok {
+			result.Reason = "condition is false"
+			return result
+		}
+	}
+
+	result.Matched = true
+	result.Reason = fmt.Sprintf("%s matches", req.Action)
+	if p.Description != "" {
+		result.Reason = p.Description
+	}
+	return result
+}
+
+func matchesAny(patterns []string, value string) bool {
+	for _, pattern := range patterns {
+		if ok, _ := path.Match(pattern, value); ok {
+			return true
+		}
+	}
+	return false
+}
+
+func resourceName(resource Resource) string {
+	if resource.ID == "" {
+		return resource.Type
+	}
+	return resource.Type + ":" + resource.ID
+}
@@ -0,0 +1,153 @@
+package authzkit
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestEngine(t *testing.T, options ...EngineOption) *Engine {
+	engine, err := NewEngine([]Policy{
+		{ID: "read-documents", Effect: EffectAllow, Actions: []string{"read"}, Resources: []string{"document"}},
+		{ID: "owners-edit", Description: "owners can edit their documents", Effect: EffectAllow, Actions: []string{"edit", "delete"}, Resources: []string{"document"}, Condition: OwnerIs("owner")},
+		{ID: "admins", Effect: EffectAllow, Actions: []string{"*"}, Resources: []string{"*"}, Roles: []string{"admin"}},
+		{ID: "locked", Effect: EffectDeny, Actions: []string{"edit", "delete"}, Resources: []string{"document"}, Condition: MustParseCondition("resource.locked == true")},
+	}, options...)
+	assert.NoError(t, err)
+	return engine
+}
+
+func TestNewEngine(t *testing.T) {
+	t.Run("returns_an_error_for_an_invalid_policy", func(t *testing.T) {
+		for name, policy := range map[string]Policy{
+			"policy 0 is invalid: policy ID cannot be empty":                                              {Effect: EffectAllow, Actions: []string{"read"}, Resources: []string{"*"}},
+			"policy 0 is invalid: policy \"theID\" has an unknown effect \"maybe\"":                       {ID: "theID", Effect: "maybe", Actions: []string{"read"}, Resources: []string{"*"}},
+			"policy 0 is invalid: policy \"theID\" must name at least one action":                         {ID: "theID", Effect: EffectAllow, Resources: []string{"*"}},
+			"policy 0 is invalid: policy \"theID\" must name at least one resource":                       {ID: "theID", Effect: EffectAllow, Actions: []string{"read"}},
+			"policy 0 is invalid: policy \"theID\" has an invalid pattern \"[\": syntax error in pattern": {ID: "theID", Effect: EffectAllow, Actions: []string{"["}, Resources: []string{"*"}},
+		} {
+			engine, err := NewEngine([]Policy{policy})
+
+			assert.Nil(t, engine)
+			assert.EqualError(t, err, name)
+		}
+	})
+
+	t.Run("returns_an_error_for_a_duplicate_policy_id", func(t *testing.T) {
+		policy := Policy{ID: "theID", Effect: EffectAllow, Actions: []string{"read"}, Resources: []string{"*"}}
+
+		engine, err := NewEngine([]Policy{policy, policy})
+
+		assert.Nil(t, engine)
+		assert.EqualError(t, err, `policy ID "theID" is used more than once`)
+	})
+}
+
+func TestEngineEvaluate(t *testing.T) {
+	theUser := Principal{ID: "theUser"}
+	theDocument := Resource{Type: "document", ID: "theDocument", Attributes: map[string]any{"owner": "theUser"}}
+
+	t.Run("allows_a_request_an_allow_policy_matches", func(t *testing.T) {
+		decision := newTestEngine(t).Evaluate(context.Background(), Request{Principal: theUser, Action: "read", Resource: theDocument})
+
+		assert.True(t, decision.Allowed)
+		assert.Equal(t, "read-documents", decision.PolicyID)
+		assert.Equal(t, `allowed by policy "read-documents": read matches`, decision.Reason)
+		assert.Equal(t, "theUser", decision.Principal)
+		assert.Equal(t, "document:theDocument", decision.Resource)
+		assert.Len(t, decision.Results, 4)
+	})
+
+	t.Run("allows_the_owner_with_an_attribute_check", func(t *testing.T) {
+		decision := newTestEngine(t).Evaluate(context.Background(), Request{Principal: theUser, Action: "edit", Resource: theDocument})
+
+		assert.True(t, decision.Allowed)
+		assert.Equal(t, `allowed by policy "owners-edit": owners can edit their documents`, decision.Reason)
+	})
+
+	t.Run("denies_a_request_no_policy_allows", func(t *testing.T) {
+		decision := newTestEngine(t).Evaluate(context.Background(), Request{Principal: Principal{ID: "anotherUser"}, Action: "edit", Resource: theDocument})
+
+		assert.False(t, decision.Allowed)
+		assert.Empty(t, decision.PolicyID)
+		assert.Equal(t, `no policy allows "edit" on "document:theDocument"`, decision.Reason)
+		assert.Equal(t, "condition is false", decision.Results[1].Reason)
+		assert.Equal(t, `principal does not have role "admin"`, decision.Results[2].Reason)
+	})
+
+	t.Run("lets_a_deny_policy_override_an_allow_policy", func(t *testing.T) {
+		locked := Resource{Type: "document", Attributes: map[string]any{"owner": "theUser", "locked": true}}
+
+		decision := newTestEngine(t).Evaluate(context.Background(), Request{Principal: Principal{ID: "theUser", Roles: []string{"admin"}}, Action: "delete", Resource: locked})
+
+		assert.False(t, decision.Allowed)
+		assert.Equal(t, "locked", decision.PolicyID)
+		assert.Equal(t, `denied by policy "locked": delete matches`, decision.Reason)
+	})
+
+	t.Run("requires_the_policy_permissions", func(t *testing.T) {
+		engine, err := NewEngine([]Policy{{ID: "theID", Effect: EffectAllow, Actions: []string{"documents:*"}, Resources: []string{"*"}, Permissions: []string{"write:documents"}}})
+		assert.NoError(t, err)
+
+		denied := engine.Evaluate(context.Background(), Request{Principal: theUser, Action: "documents:write", Resource: theDocument})
+		allowed := engine.Evaluate(context.Background(), Request{Principal: Principal{Permissions: []string{"write:documents"}}, Action: "documents:write", Resource: theDocument})
+
+		assert.False(t, denied.Allowed)
+		assert.Equal(t, `principal does not have permission "write:documents"`, denied.Results[0].Reason)
+		assert.True(t, allowed.Allowed)
+	})
+
+	t.Run("fails_closed_when_a_condition_errors", func(t *testing.T) {
+		engine, err := NewEngine([]Policy{
+			{ID: "allow", Effect: EffectAllow, Actions: []string{"*"}, Resources: []string{"*"}},
+			{ID: "deny", Effect: EffectDeny, Actions: []string{"*"}, Resources: []string{"*"}, Condition: func(Request) (bool, error) { return false, errors.New("theError") }},
+		})
+		assert.NoError(t, err)
+
+		decision := engine.Evaluate(context.Background(), Request{Principal: theUser, Action: "read", Resource: theDocument})
+
+		assert.False(t, decision.Allowed)
+		assert.Equal(t, `denied by policy "deny": condition failed: theError`, decision.Reason)
+		assert.Equal(t, "theError", decision.Results[1].Error)
+	})
+
+	t.Run("calls_the_decision_hook", func(t *testing.T) {
+		var decisions []Decision
+		engine := newTestEngine(t, WithDecisionHook(func(ctx context.Context, decision Decision) {
+			decisions = append(decisions, decision)
+		}))
+
+		decision := engine.Evaluate(context.Background(), Request{Principal: theUser, Action: "read", Resource: theDocument})
+
+		assert.Equal(t, []Decision{decision}, decisions)
+	})
+}
+
+func TestEngineAuthorize(t *testing.T) {
+	theDocument := Resource{Type: "document", Attributes: map[string]any{"owner": "theUser"}}
+
+	t.Run("returns_nil_when_allowed", func(t *testing.T) {
+		err := newTestEngine(t).Authorize(context.Background(), Request{Principal: Principal{ID: "theUser"}, Action: "delete", Resource: theDocument})
+
+		assert.NoError(t, err)
+	})
+
+	t.Run("returns_a_denied_error_with_the_decision", func(t *testing.T) {
+		err := newTestEngine(t).Authorize(context.Background(), Request{Principal: Principal{ID: "anotherUser"}, Action: "delete", Resource: theDocument})
+
+		assert.ErrorIs(t, err, ErrDenied)
+		assert.EqualError(t, err, `not authorized: no policy allows "delete" on "document"`)
+		var denied *DeniedError
+		assert.ErrorAs(t, err, &denied)
+		assert.Equal(t, "anotherUser", denied.Decision.Principal)
+	})
+}
+
+func TestEngineCan(t *testing.T) {
+	engine := newTestEngine(t)
+
+	assert.True(t, engine.Can(context.Background(), Principal{Roles: []string{"admin"}}, "archive", Resource{Type: "folder"}))
+	assert.False(t, engine.Can(context.Background(), Principal{}, "archive", Resource{Type: "folder"}))
+}
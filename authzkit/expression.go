@@ -0,0 +1,412 @@
+package authzkit
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// ParseCondition parses a Cedar-style expression into a Condition, e.g.
+//
+//	resource.owner == principal.id || "admin" in principal.roles
+//
+// Operands are string and number literals, true and false, and attribute paths: principal.id,
+// principal.roles, principal.permissions, resource.type, resource.id, action, and context.<name>;
+// any other principal.<name> or resource.<name> reads from Attributes, and further dots read into
+// nested maps. An attribute that isn't set is null, so it equals nothing but another missing one.
+// Operators are ==, !=, <, <=, >, >=, in (membership of a list), !, &&, ||, and parentheses.
+func ParseCondition(expression string) (Condition, error) {
+	tokens, err := tokenize(expression)
+	if err != nil {
+		return nil, fmt.Errorf("invalid condition %q: %w", expression, err)
+	}
+
+	p := &parser{tokens: tokens}
+	eval, err := p.parseOr()
+	if err == nil && p.pos < len(p.tokens) {
+		err = fmt.Errorf("unexpected %q", p.tokens[p.pos].text)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid condition %q: %w", expression, err)
+	}
+
+	return func(req Request) (bool, error) {
+		value, err := eval(req)
+		if err != nil {
+			return false, err
+		}
+		return asBool(value)
+	}, nil
+}
+
+// MustParseCondition is ParseCondition for expressions known to be valid, e.g. in a policy literal;
+// it panics if expression is invalid
+func MustParseCondition(expression string) Condition {
+	condition, err := ParseCondition(expression)
+	if err != nil {
+		panic(err)
+	}
+	return condition
+}
+
+// OwnerIs is the condition that the resource's attribute names the principal, e.g. OwnerIs("owner")
+// for resource.owner == principal.id
+func OwnerIs(attribute string) Condition {
+	return func(req Request) (bool, error) {
+		owner, ok := req.Resource.Attributes[attribute]
+		return ok && owner == req.Principal.ID, nil
+	}
+}
+
+type tokenKind int
+
+const (
+	tokenOperator tokenKind = iota
+	tokenString
+	tokenNumber
+	tokenIdent
+)
+
+type token struct {
+	kind tokenKind
+	text string
+}
+
+func tokenize(expression string) ([]token, error) {
+	var tokens []token
+	runes := []rune(expression)
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case r == '"' || r == '\'':
+			end := i + 1
+			var text strings.Builder
+			for ; end < len(runes) && runes[end] != r; end++ {
+				if runes[end] == '\\' && end+1 < len(runes) {
+					end++
+				}
+				text.WriteRune(runes[end])
+			}
+			if end >= len(runes) {
+				return nil, fmt.Errorf("unterminated string at %d", i)
+			}
+			tokens = append(tokens, token{kind: tokenString, text: text.String()})
+			i = end + 1
+		case unicode.IsDigit(r) || (r == '-' && i+1 < len(runes) && unicode.IsDigit(runes[i+1])):
+			end := i + 1
+			for end < len(runes) && (unicode.IsDigit(runes[end]) || runes[end] == '.') {
+				end++
+			}
+			tokens = append(tokens, token{kind: tokenNumber, text: string(runes[i:end])})
+			i = end
+		case unicode.IsLetter(r) || r == '_':
+			end := i + 1
+			for end < len(runes) && (unicode.IsLetter(runes[end]) || unicode.IsDigit(runes[end]) || runes[end] == '_' || runes[end] == '.') {
+				end++
+			}
+			tokens = append(tokens, token{kind: tokenIdent, text: string(runes[i:end])})
+			i = end
+		default:
+			if i+1 < len(runes) && slices.Contains([]string{"==", "!=", "<=", ">=", "&&", "||"}, string(runes[i:i+2])) {
+				tokens = append(tokens, token{kind: tokenOperator, text: string(runes[i : i+2])})
+				i += 2
+				continue
+			}
+			if !strings.ContainsRune("!<>()", r) {
+				return nil, fmt.Errorf("unexpected %q at %d", r, i)
+			}
+			tokens = append(tokens, token{kind: tokenOperator, text: string(r)})
+			i++
+		}
+	}
+	return tokens, nil
+}
+
+type evaluator func(req Request) (any, error)
+
+type parser struct {
+	tokens []token
+	pos    int
+}
+
+func (p *parser) peek(texts ...string) bool {
+	if p.pos >= len(p.tokens) {
+		return false
+	}
+	t := p.tokens[p.pos]
+	return (t.kind == tokenOperator || t.kind == tokenIdent) && slices.Contains(texts, t.text)
+}
+
+func (p *parser) parseOr() (evaluator, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.peek("||") {
+		p.pos++
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = logical(left, right, true)
+	}
+	return left, nil
+}
+
+func (p *parser) parseAnd() (evaluator, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for p.peek("&&") {
+		p.pos++
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = logical(left, right, false)
+	}
+	return left, nil
+}
+
+// logical short-circuits: || stops at the first true operand and && at the first false one
+func logical(left, right evaluator, or bool) evaluator {
+	return func(req Request) (any, error) {
+		for _, operand := range []evaluator{left, right} {
+			value, err := operand(req)
+			if err != nil {
+				return nil, err
+			}
+			b, err := asBool(value)
+			if err != nil {
+				return nil, err
+			}
+			if b == or {
+				return or, nil
+			}
+		}
+		return !or, nil
+	}
+}
+
+func (p *parser) parseUnary() (evaluator, error) {
+	if !p.peek("!") {
+		return p.parseComparison()
+	}
+	p.pos++
+	operand, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	return func(req Request) (any, error) {
+		value, err := operand(req)
+		if err != nil {
+			return nil, err
+		}
+		b, err := asBool(value)
+		return !b, err
+	}, nil
+}
+
+func (p *parser) parseComparison() (evaluator, error) {
+	left, err := p.parsePrimary()
+	if err != nil {
+		return nil, err
+	}
+	if !p.peek("==", "!=", "<", "<=", ">", ">=", "in") {
+		return left, nil
+	}
+	operator := p.tokens[p.pos].text
+	p.pos++
+	right, err := p.parsePrimary()
+	if err != nil {
+		return nil, err
+	}
+
+	return func(req Request) (any, error) {
+		l, err := left(req)
+		if err != nil {
+			return nil, err
+		}
+		r, err := right(req)
+		if err != nil {
+			return nil, err
+		}
+		return compare(operator, l, r)
+	}, nil
+}
+
+func (p *parser) parsePrimary() (evaluator, error) {
+	if p.pos >= len(p.tokens) {
+		return nil, fmt.Errorf("unexpected end of expression")
+	}
+	t := p.tokens[p.pos]
+	p.pos++
+
+	switch {
+	case t.kind == tokenOperator && t.text == "(":
+		inner, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if !p.peek(")") {
+			return nil, fmt.Errorf("missing )")
+		}
+		p.pos++
+		return inner, nil
+	case t.kind == tokenString:
+		return constant(t.text), nil
+	case t.kind == tokenNumber:
+		n, err := strconv.ParseFloat(t.text, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid number %q", t.text)
+		}
+		return constant(n), nil
+	case t.kind == tokenIdent && (t.text == "true" || t.text == "false"):
+		return constant(t.text == "true"), nil
+	case t.kind == tokenIdent:
+		return attributePath(t.text)
+	default:
+		return nil, fmt.Errorf("unexpected %q", t.text)
+	}
+}
+
+func constant(value any) evaluator {
+	return func(Request) (any, error) { return value, nil }
+}
+
+func attributePath(path string) (evaluator, error) {
+	parts := strings.Split(path, ".")
+	if slices.Contains(parts, "") {
+		return nil, fmt.Errorf("invalid attribute %q", path)
+	}
+
+	var root func(req Request) any
+	rest := parts[1:]
+	switch {
+	case parts[0] == "action" && len(rest) == 0:
+		root = func(req Request) any { return req.Action }
+	case parts[0] == "principal" && len(rest) > 0:
+		root, rest = principalAttribute(rest[0]), rest[1:]
+	case parts[0] == "resource" && len(rest) > 0:
+		root, rest = resourceAttribute(rest[0]), rest[1:]
+	case parts[0] == "context" && len(rest) > 0:
+		root = func(req Request) any { return req.Context }
+	default:
+		return nil, fmt.Errorf("unknown attribute %q", path)
+	}
+
+	return func(req Request) (any, error) {
+		value := root(req)
+		for _, name := range rest {
+			m, ok := value.(map[string]any)
+			if !ok {
+				return nil, nil
+			}
+			value = m[name]
+		}
+		return value, nil
+	}, nil
+}
+
+func principalAttribute(name string) func(req Request) any {
+	switch name {
+	case "id":
+		return func(req Request) any { return req.Principal.ID }
+	case "roles":
+		return func(req Request) any { return req.Principal.Roles }
+	case "permissions":
+		return func(req Request) any { return req.Principal.Permissions }
+	default:
+		return func(req Request) any { return req.Principal.Attributes[name] }
+	}
+}
+
+func resourceAttribute(name string) func(req Request) any {
+	switch name {
+	case "type":
+		return func(req Request) any { return req.Resource.Type }
+	case "id":
+		return func(req Request) any { return req.Resource.ID }
+	default:
+		return func(req Request) any { return req.Resource.Attributes[name] }
+	}
+}
+
+func compare(operator string, left, right any) (any, error) {
+	left, right = normalize(left), normalize(right)
+
+	switch operator {
+	case "==":
+		return reflect.DeepEqual(left, right), nil
+	case "!=":
+		return !reflect.DeepEqual(left, right), nil
+	case "in":
+		if right == nil {
+			return false, nil
+		}
+		list := reflect.ValueOf(right)
+		if list.Kind() != reflect.Slice && list.Kind() != reflect.Array {
+			return nil, fmt.Errorf("right side of in must be a list, got %T", right)
+		}
+		for i := range list.Len() {
+			if reflect.DeepEqual(left, normalize(list.Index(i).Interface())) {
+				return true, nil
+			}
+		}
+		return false, nil
+	}
+
+	switch l := left.(type) {
+	case float64:
+		if r, ok := right.(float64); ok {
+			return ordered(operator, l, r), nil
+		}
+	case string:
+		if r, ok := right.(string); ok {
+			return ordered(operator, l, r), nil
+		}
+	}
+	return nil, fmt.Errorf("cannot compare %T %s %T", left, operator, right)
+}
+
+func ordered[T float64 | string](operator string, left, right T) bool {
+	switch operator {
+	case "<":
+		return left < right
+	case "<=":
+		return left <= right
+	case ">":
+		return left > right
+	default:
+		return left >= right
+	}
+}
+
+// normalize makes every number a float64, so an int attribute equals a number literal
+func normalize(value any) any {
+	v := reflect.ValueOf(value)
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(v.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(v.Uint())
+	case reflect.Float32, reflect.Float64:
+		return v.Float()
+	default:
+		return value
+	}
+}
+
+func asBool(value any) (bool, error) {
+	b, ok := value.(bool)
+	if !ok {
+		return false, fmt.Errorf("expected a boolean, got %T", value)
+	}
+	return b, nil
+}
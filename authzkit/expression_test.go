@@ -0,0 +1,92 @@
+package authzkit
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseCondition(t *testing.T) {
+	theRequest := Request{
+		Principal: Principal{ID: "theUser", Roles: []string{"editor"}, Attributes: map[string]any{"department": "sales", "level": 3}},
+		Action:    "edit",
+		Resource:  Resource{Type: "document", ID: "theDocument", Attributes: map[string]any{"owner": "theUser", "meta": map[string]any{"region": "eu"}}},
+		Context:   map[string]any{"hour": 14},
+	}
+
+	t.Run("evaluates_expressions", func(t *testing.T) {
+		for expression, expected := range map[string]bool{
+			`resource.owner == principal.id`:                               true,
+			`resource.owner != principal.id`:                               false,
+			`"editor" in principal.roles`:                                  true,
+			`"admin" in principal.roles || resource.owner == principal.id`: true,
+			`"admin" in principal.roles && resource.owner == principal.id`: false,
+			`!("admin" in principal.roles)`:                                true,
+			`principal.department == 'sales' && principal.level >= 3`:      true,
+			`context.hour < 9 || context.hour > 17`:                        false,
+			`resource.meta.region == "eu"`:                                 true,
+			`action == "edit" && resource.type == "document"`:              true,
+			`resource.id == "theDocument"`:                                 true,
+			`resource.missing == principal.missing`:                        true,
+			`resource.missing == "theUser"`:                                false,
+			`"x" in resource.missing`:                                      false,
+			`true`:                                                         true,
+		} {
+			condition, err := ParseCondition(expression)
+			assert.NoError(t, err, expression)
+
+			actual, err := condition(theRequest)
+
+			assert.NoError(t, err, expression)
+			assert.Equal(t, expected, actual, expression)
+		}
+	})
+
+	t.Run("returns_an_error_for_an_invalid_expression", func(t *testing.T) {
+		for expression, expected := range map[string]string{
+			`resource.owner ==`:    `invalid condition "resource.owner ==": unexpected end of expression`,
+			`(true`:                `invalid condition "(true": missing )`,
+			`true true`:            `invalid condition "true true": unexpected "true"`,
+			`user.id == "x"`:       `invalid condition "user.id == \"x\"": unknown attribute "user.id"`,
+			`resource.owner = "x"`: `invalid condition "resource.owner = \"x\"": unexpected '=' at 15`,
+			`resource.owner == "x`: `invalid condition "resource.owner == \"x": unterminated string at 18`,
+		} {
+			condition, err := ParseCondition(expression)
+
+			assert.Nil(t, condition, expression)
+			assert.EqualError(t, err, expected, expression)
+		}
+	})
+
+	t.Run("returns_an_error_for_mismatched_types", func(t *testing.T) {
+		for expression, expected := range map[string]string{
+			`principal.id`:          "expected a boolean, got string",
+			`principal.level < "3"`: "cannot compare float64 < string",
+			`"x" in principal.id`:   "right side of in must be a list, got string",
+		} {
+			condition, err := ParseCondition(expression)
+			assert.NoError(t, err, expression)
+
+			actual, err := condition(theRequest)
+
+			assert.False(t, actual, expression)
+			assert.EqualError(t, err, expected, expression)
+		}
+	})
+
+	t.Run("panics_from_must_parse_for_an_invalid_expression", func(t *testing.T) {
+		assert.Panics(t, func() { MustParseCondition("(") })
+	})
+}
+
+func TestOwnerIs(t *testing.T) {
+	condition := OwnerIs("owner")
+
+	owned, err := condition(Request{Principal: Principal{ID: "theUser"}, Resource: Resource{Attributes: map[string]any{"owner": "theUser"}}})
+	assert.NoError(t, err)
+	assert.True(t, owned)
+
+	owned, err = condition(Request{Principal: Principal{ID: "theUser"}, Resource: Resource{}})
+	assert.NoError(t, err)
+	assert.False(t, owned)
+}
@@ -0,0 +1,203 @@
+// Package awskit loads the AWS config every kit's AWS clients are made from, so region resolution,
+// endpoint overrides, role assumption, retries, and the user agent are handled the same way for all of
+// them. DefaultConfig is what dynamodbkit and bedrockkit load when they aren't given a client.
+//
+//	cfg, err := awskit.LoadConfig(ctx,
+//		awskit.WithDefaultRegion("us-east-1"),
+//		awskit.WithAssumeRole("arn:aws:iam::123456789012:role/deploy", "deploy"),
+//		awskit.WithUserAgent("orders", buildInfo))
+//	db := dynamodb.NewFromConfig(cfg)
+//
+// The endpoint comes from WithEndpoint, or else the AWS_ENDPOINT_URL environment variable, or a
+// service's own, e.g. AWS_ENDPOINT_URL_DYNAMODB, so tests can point clients at DynamoDB Local or
+// LocalStack without code changes.
+package awskit
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awsmiddleware "github.com/aws/aws-sdk-go-v2/aws/middleware"
+	"github.com/aws/aws-sdk-go-v2/aws/retry"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/credentials/stscreds"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
+	"github.com/aws/smithy-go/middleware"
+	"github.com/half-ogre/go-kit/kit"
+	"github.com/half-ogre/go-kit/versionkit"
+)
+
+// Option configures LoadConfig
+type Option func(*options)
+
+type options struct {
+	region          string
+	defaultRegion   string
+	profile         string
+	endpoint        string
+	roleARN         string
+	roleSessionName string
+	maxAttempts     int
+	maxBackoff      time.Duration
+	appName         string
+	buildInfo       *versionkit.BuildInfo
+	loadOptions     []func(*config.LoadOptions) error
+}
+
+// WithRegion sets the region, overriding AWS_REGION, AWS_DEFAULT_REGION, and the shared config
+func WithRegion(region string) Option {
+	return func(o *options) {
+		o.region = region
+	}
+}
+
+// WithDefaultRegion sets the region used when AWS_REGION, AWS_DEFAULT_REGION, and the shared config
+// don't set one
+func WithDefaultRegion(region string) Option {
+	return func(o *options) {
+		o.defaultRegion = region
+	}
+}
+
+// WithProfile sets the shared config profile, overriding AWS_PROFILE
+func WithProfile(profile string) Option {
+	return func(o *options) {
+		o.profile = profile
+	}
+}
+
+// WithEndpoint sends every client's requests to endpoint, e.g. http://localhost:8000 for DynamoDB
+// Local, overriding AWS_ENDPOINT_URL
+func WithEndpoint(endpoint string) Option {
+	return func(o *options) {
+		o.endpoint = endpoint
+	}
+}
+
+// WithAssumeRole makes clients use the credentials of the role roleARN, assumed with STS using the
+// loaded credentials. The role's credentials are cached and renewed before they expire. An empty
+// sessionName lets the SDK choose one.
+func WithAssumeRole(roleARN string, sessionName string) Option {
+	return func(o *options) {
+		o.roleARN = roleARN
+		o.roleSessionName = sessionName
+	}
+}
+
+// WithRetries sets the SDK's standard retryer to make at most maxAttempts attempts, waiting at most
+// maxBackoff between them; a zero maxBackoff keeps the SDK's default of 20 seconds
+func WithRetries(maxAttempts int, maxBackoff time.Duration) Option {
+	return func(o *options) {
+		o.maxAttempts = maxAttempts
+		o.maxBackoff = maxBackoff
+	}
+}
+
+// WithUserAgent adds appName and the version in info to the user agent of every request, e.g.
+// "orders/v1.4.2", so the caller can be found in CloudTrail. A nil info uses versionkit.GetBuildInfo.
+func WithUserAgent(appName string, info *versionkit.BuildInfo) Option {
+	return func(o *options) {
+		o.appName = appName
+		o.buildInfo = info
+	}
+}
+
+// WithLoadOptions passes options to config.LoadDefaultConfig, for settings awskit doesn't cover
+func WithLoadOptions(loadOptions ...func(*config.LoadOptions) error) Option {
+	return func(o *options) {
+		o.loadOptions = append(o.loadOptions, loadOptions...)
+	}
+}
+
+// LoadConfig loads the default AWS config, from the environment and the shared config files, changed
+// by options
+func LoadConfig(ctx context.Context, opts ...Option) (aws.Config, error) {
+	o := options{}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	loadOptions := []func(*config.LoadOptions) error{}
+	if o.region != "" {
+		loadOptions = append(loadOptions, config.WithRegion(o.region))
+	}
+	if o.profile != "" {
+		loadOptions = append(loadOptions, config.WithSharedConfigProfile(o.profile))
+	}
+	if o.maxAttempts > 0 {
+		loadOptions = append(loadOptions, config.WithRetryer(func() aws.Retryer {
+			return retry.NewStandard(func(so *retry.StandardOptions) {
+				so.MaxAttempts = o.maxAttempts
+				if o.maxBackoff > 0 {
+					so.MaxBackoff = o.maxBackoff
+				}
+			})
+		}))
+	}
+	if o.appName != "" {
+		info := o.buildInfo
+		if info == nil {
+			info = versionkit.GetBuildInfo()
+		}
+		loadOptions = append(loadOptions, config.WithAPIOptions([]func(*middleware.Stack) error{
+			awsmiddleware.AddUserAgentKeyValue(o.appName, info.GetBuildVersion()),
+		}))
+	}
+	loadOptions = append(loadOptions, o.loadOptions...)
+
+	cfg, err := config.LoadDefaultConfig(ctx, loadOptions...)
+	if err != nil {
+		return aws.Config{}, kit.WrapError(err, "error loading AWS config")
+	}
+
+	if cfg.Region == "" {
+		cfg.Region = o.defaultRegion
+	}
+	if o.endpoint != "" {
+		cfg.BaseEndpoint = aws.String(o.endpoint)
+	}
+	if o.roleARN != "" {
+		provider := stscreds.NewAssumeRoleProvider(sts.NewFromConfig(cfg), o.roleARN, func(ao *stscreds.AssumeRoleOptions) {
+			if o.roleSessionName != "" {
+				ao.RoleSessionName = o.roleSessionName
+			}
+		})
+		cfg.Credentials = aws.NewCredentialsCache(provider)
+	}
+
+	return cfg, nil
+}
+
+var defaultConfig struct {
+	mu      sync.Mutex
+	options []Option
+	cfg     *aws.Config
+}
+
+// DefaultConfig returns the config loaded with the options set by UseDefaultOptions. It's loaded once,
+// when first asked for, so clients made from it share its cached credentials.
+func DefaultConfig(ctx context.Context) (aws.Config, error) {
+	defaultConfig.mu.Lock()
+	defer defaultConfig.mu.Unlock()
+
+	if defaultConfig.cfg == nil {
+		cfg, err := LoadConfig(ctx, defaultConfig.options...)
+		if err != nil {
+			return aws.Config{}, err
+		}
+		defaultConfig.cfg = &cfg
+	}
+	return *defaultConfig.cfg, nil
+}
+
+// UseDefaultOptions sets the options DefaultConfig loads the config with, e.g. at startup, and makes it
+// load the config again the next time it's asked for
+func UseDefaultOptions(opts ...Option) {
+	defaultConfig.mu.Lock()
+	defer defaultConfig.mu.Unlock()
+
+	defaultConfig.options = opts
+	defaultConfig.cfg = nil
+}
@@ -0,0 +1,192 @@
+package awskit
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/aws/retry"
+	"github.com/half-ogre/go-kit/versionkit"
+	"github.com/stretchr/testify/assert"
+)
+
+// isolateEnv clears the AWS environment and points the shared config files at files that don't exist
+func isolateEnv(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"AWS_REGION", "AWS_DEFAULT_REGION", "AWS_PROFILE", "AWS_ENDPOINT_URL", "AWS_SESSION_TOKEN"} {
+		t.Setenv(name, "")
+	}
+	t.Setenv("AWS_CONFIG_FILE", filepath.Join(dir, "config"))
+	t.Setenv("AWS_SHARED_CREDENTIALS_FILE", filepath.Join(dir, "credentials"))
+	t.Setenv("AWS_EC2_METADATA_DISABLED", "true")
+	t.Setenv("AWS_ACCESS_KEY_ID", "anAccessKeyID")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "aSecretAccessKey")
+}
+
+const assumeRoleResponse = `<AssumeRoleResponse xmlns="https://sts.amazonaws.com/doc/2011-06-15/">
+  <AssumeRoleResult>
+    <Credentials>
+      <AccessKeyId>theRoleAccessKeyID</AccessKeyId>
+      <SecretAccessKey>theRoleSecretAccessKey</SecretAccessKey>
+      <SessionToken>theRoleSessionToken</SessionToken>
+      <Expiration>2099-01-01T00:00:00Z</Expiration>
+    </Credentials>
+    <AssumedRoleUser>
+      <Arn>arn:aws:sts::123456789012:assumed-role/theRole/theSession</Arn>
+      <AssumedRoleId>AROATHEROLE:theSession</AssumedRoleId>
+    </AssumedRoleUser>
+  </AssumeRoleResult>
+</AssumeRoleResponse>`
+
+// newSTSServer returns a fake STS endpoint answering AssumeRole, and the requests it received
+func newSTSServer(t *testing.T) (*httptest.Server, *[]*http.Request) {
+	var mu sync.Mutex
+	requests := []*http.Request{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.NoError(t, r.ParseForm())
+		mu.Lock()
+		requests = append(requests, r)
+		mu.Unlock()
+		w.Header().Set("Content-Type", "text/xml")
+		_, _ = w.Write([]byte(assumeRoleResponse))
+	}))
+	t.Cleanup(server.Close)
+	return server, &requests
+}
+
+func TestLoadConfig(t *testing.T) {
+	t.Run("uses_the_region_from_the_environment", func(t *testing.T) {
+		isolateEnv(t)
+		t.Setenv("AWS_REGION", "eu-west-1")
+
+		cfg, err := LoadConfig(context.Background(), WithDefaultRegion("us-east-1"))
+
+		assert.NoError(t, err)
+		assert.Equal(t, "eu-west-1", cfg.Region)
+	})
+
+	t.Run("uses_the_region_option_over_the_environment", func(t *testing.T) {
+		isolateEnv(t)
+		t.Setenv("AWS_REGION", "eu-west-1")
+
+		cfg, err := LoadConfig(context.Background(), WithRegion("ap-southeast-2"))
+
+		assert.NoError(t, err)
+		assert.Equal(t, "ap-southeast-2", cfg.Region)
+	})
+
+	t.Run("uses_the_default_region_when_none_is_set", func(t *testing.T) {
+		isolateEnv(t)
+
+		cfg, err := LoadConfig(context.Background(), WithDefaultRegion("us-east-1"))
+
+		assert.NoError(t, err)
+		assert.Equal(t, "us-east-1", cfg.Region)
+	})
+
+	t.Run("uses_the_endpoint_from_the_environment", func(t *testing.T) {
+		isolateEnv(t)
+		t.Setenv("AWS_ENDPOINT_URL", "http://localhost:4566")
+
+		cfg, err := LoadConfig(context.Background())
+
+		assert.NoError(t, err)
+		assert.Equal(t, "http://localhost:4566", aws.ToString(cfg.BaseEndpoint))
+	})
+
+	t.Run("uses_the_endpoint_option_over_the_environment", func(t *testing.T) {
+		isolateEnv(t)
+		t.Setenv("AWS_ENDPOINT_URL", "http://localhost:4566")
+
+		cfg, err := LoadConfig(context.Background(), WithEndpoint("http://localhost:8000"))
+
+		assert.NoError(t, err)
+		assert.Equal(t, "http://localhost:8000", aws.ToString(cfg.BaseEndpoint))
+	})
+
+	t.Run("sets_the_retries", func(t *testing.T) {
+		isolateEnv(t)
+
+		cfg, err := LoadConfig(context.Background(), WithRetries(7, 3*time.Second))
+
+		assert.NoError(t, err)
+		assert.Equal(t, 7, cfg.Retryer().MaxAttempts())
+		assert.IsType(t, &retry.Standard{}, cfg.Retryer())
+	})
+
+	t.Run("returns_an_error_for_a_profile_that_does_not_exist", func(t *testing.T) {
+		isolateEnv(t)
+
+		_, err := LoadConfig(context.Background(), WithProfile("aMissingProfile"))
+
+		assert.ErrorContains(t, err, "error loading AWS config: ")
+	})
+
+	t.Run("assumes_the_role_and_caches_its_credentials", func(t *testing.T) {
+		isolateEnv(t)
+		server, requests := newSTSServer(t)
+		cfg, err := LoadConfig(context.Background(),
+			WithRegion("us-east-1"),
+			WithEndpoint(server.URL),
+			WithAssumeRole("arn:aws:iam::123456789012:role/theRole", "theSession"))
+		assert.NoError(t, err)
+
+		first, err := cfg.Credentials.Retrieve(context.Background())
+		assert.NoError(t, err)
+		second, err := cfg.Credentials.Retrieve(context.Background())
+		assert.NoError(t, err)
+
+		assert.Equal(t, "theRoleAccessKeyID", first.AccessKeyID)
+		assert.Equal(t, "theRoleSessionToken", first.SessionToken)
+		assert.Equal(t, first, second)
+		if assert.Len(t, *requests, 1) {
+			request := (*requests)[0]
+			assert.Equal(t, "AssumeRole", request.PostForm.Get("Action"))
+			assert.Equal(t, "arn:aws:iam::123456789012:role/theRole", request.PostForm.Get("RoleArn"))
+			assert.Equal(t, "theSession", request.PostForm.Get("RoleSessionName"))
+		}
+	})
+
+	t.Run("adds_the_app_and_version_to_the_user_agent", func(t *testing.T) {
+		isolateEnv(t)
+		server, requests := newSTSServer(t)
+		cfg, err := LoadConfig(context.Background(),
+			WithRegion("us-east-1"),
+			WithEndpoint(server.URL),
+			WithAssumeRole("arn:aws:iam::123456789012:role/theRole", ""),
+			WithUserAgent("theApp", &versionkit.BuildInfo{Version: "v1.2.3"}))
+		assert.NoError(t, err)
+
+		_, err = cfg.Credentials.Retrieve(context.Background())
+
+		assert.NoError(t, err)
+		if assert.Len(t, *requests, 1) {
+			assert.Contains(t, (*requests)[0].UserAgent(), "theApp/v1.2.3")
+		}
+	})
+}
+
+func TestDefaultConfig(t *testing.T) {
+	t.Run("loads_the_config_once_with_the_default_options", func(t *testing.T) {
+		isolateEnv(t)
+		UseDefaultOptions(WithRegion("eu-west-1"))
+		t.Cleanup(func() { UseDefaultOptions() })
+
+		first, err := DefaultConfig(context.Background())
+		assert.NoError(t, err)
+		UseDefaultOptions(WithRegion("us-west-2"))
+		second, err := DefaultConfig(context.Background())
+		assert.NoError(t, err)
+		third, err := DefaultConfig(context.Background())
+		assert.NoError(t, err)
+
+		assert.Equal(t, "eu-west-1", first.Region)
+		assert.Equal(t, "us-west-2", second.Region)
+		assert.Same(t, second.Credentials, third.Credentials)
+	})
+}
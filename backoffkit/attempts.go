@@ -0,0 +1,90 @@
+package backoffkit
+
+import (
+	"context"
+	"iter"
+	"time"
+)
+
+// Sleep waits for d or until ctx is done, returning ctx's error if it's done first
+func Sleep(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// sleep is replaced in tests
+var sleep = Sleep
+
+// Delays returns the delays of strategy before each retry, by attempt, starting with attempt 2
+func Delays(strategy Strategy) iter.Seq2[int, time.Duration] {
+	return func(yield func(int, time.Duration) bool) {
+		var delay time.Duration
+		for attempt := 2; ; attempt++ {
+			delay = strategy(attempt, delay)
+			if !yield(attempt, delay) {
+				return
+			}
+		}
+	}
+}
+
+// Attempts returns an iterator over the attempt numbers of a retry loop, starting with 1, that waits
+// strategy's delay before each attempt after the first. It ends after maxAttempts, or never when
+// maxAttempts is 0 or less, or early when ctx is done while waiting; check ctx.Err() after the loop to
+// tell.
+func Attempts(ctx context.Context, strategy Strategy, maxAttempts int) iter.Seq[int] {
+	return func(yield func(int) bool) {
+		if !yield(1) {
+			return
+		}
+		for attempt, delay := range Delays(strategy) {
+			if maxAttempts > 0 && attempt > maxAttempts {
+				return
+			}
+			if sleep(ctx, delay) != nil || !yield(attempt) {
+				return
+			}
+		}
+	}
+}
+
+// Ticker delivers the attempt numbers of a retry loop on C, as Attempts yields them, for loops that
+// select on other channels too. C is closed after the last attempt, when ctx is done, or when Stop is
+// called.
+type Ticker struct {
+	C      <-chan int
+	cancel context.CancelFunc
+}
+
+// NewTicker returns a ticker delivering attempt 1 at once and each attempt after it strategy's delay
+// after the previous one is received, up to maxAttempts, or forever when maxAttempts is 0 or less
+func NewTicker(ctx context.Context, strategy Strategy, maxAttempts int) *Ticker {
+	ctx, cancel := context.WithCancel(ctx)
+	c := make(chan int)
+	go func() {
+		defer close(c)
+		for attempt := range Attempts(ctx, strategy, maxAttempts) {
+			select {
+			case c <- attempt:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return &Ticker{C: c, cancel: cancel}
+}
+
+// Stop stops the ticker and closes C; it's safe to call more than once
+func (t *Ticker) Stop() {
+	t.cancel()
+}
@@ -0,0 +1,111 @@
+package backoffkit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// recordSleeps replaces sleep with one recording the waits without waiting, returning ctx's error
+func recordSleeps(t *testing.T) *[]time.Duration {
+	waits := []time.Duration{}
+	previous := sleep
+	sleep = func(ctx context.Context, d time.Duration) error {
+		waits = append(waits, d)
+		return ctx.Err()
+	}
+	t.Cleanup(func() { sleep = previous })
+	return &waits
+}
+
+func TestSleep(t *testing.T) {
+	t.Run("waits_for_the_duration", func(t *testing.T) {
+		err := Sleep(context.Background(), time.Millisecond)
+
+		assert.NoError(t, err)
+	})
+
+	t.Run("returns_the_context_error_when_done_first", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		err := Sleep(ctx, time.Hour)
+
+		assert.ErrorIs(t, err, context.Canceled)
+	})
+}
+
+func TestAttempts(t *testing.T) {
+	t.Run("yields_each_attempt_waiting_between_them", func(t *testing.T) {
+		waits := recordSleeps(t)
+		attempts := []int{}
+
+		for attempt := range Attempts(context.Background(), Exponential(time.Second, 0), 4) {
+			attempts = append(attempts, attempt)
+		}
+
+		assert.Equal(t, []int{1, 2, 3, 4}, attempts)
+		assert.Equal(t, []time.Duration{time.Second, 2 * time.Second, 4 * time.Second}, *waits)
+	})
+
+	t.Run("stops_when_the_loop_breaks", func(t *testing.T) {
+		waits := recordSleeps(t)
+		attempts := []int{}
+
+		for attempt := range Attempts(context.Background(), Constant(time.Second), 0) {
+			attempts = append(attempts, attempt)
+			if attempt == 2 {
+				break
+			}
+		}
+
+		assert.Equal(t, []int{1, 2}, attempts)
+		assert.Len(t, *waits, 1)
+	})
+
+	t.Run("stops_when_the_context_is_done_while_waiting", func(t *testing.T) {
+		recordSleeps(t)
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		attempts := []int{}
+
+		for attempt := range Attempts(ctx, Constant(time.Second), 5) {
+			attempts = append(attempts, attempt)
+			cancel()
+		}
+
+		assert.Equal(t, []int{1}, attempts)
+		assert.ErrorIs(t, ctx.Err(), context.Canceled)
+	})
+}
+
+func TestTicker(t *testing.T) {
+	t.Run("delivers_each_attempt_and_closes_after_the_last", func(t *testing.T) {
+		ticker := NewTicker(context.Background(), Constant(time.Millisecond), 3)
+		defer ticker.Stop()
+		attempts := []int{}
+
+		for attempt := range ticker.C {
+			attempts = append(attempts, attempt)
+		}
+
+		assert.Equal(t, []int{1, 2, 3}, attempts)
+	})
+
+	t.Run("closes_when_stopped", func(t *testing.T) {
+		ticker := NewTicker(context.Background(), Constant(time.Hour), 0)
+		assert.Equal(t, 1, <-ticker.C)
+
+		ticker.Stop()
+		ticker.Stop()
+
+		select {
+		case _, ok := <-ticker.C:
+			assert.False(t, ok)
+		case <-time.After(time.Second):
+			assert.Fail(t, "ticker wasn't closed")
+		}
+	})
+}
@@ -0,0 +1,103 @@
+// Package backoffkit has the strategies the kit waits between retries with: constant, exponential,
+// fibonacci, and decorrelated jitter delays, and full or equal jitter for any of them. Attempts
+// iterates over the attempts of a retry loop, waiting the strategy's delays between them, and Ticker
+// delivers them on a channel; kit.Retry and the dynamodbkit retries are built on them.
+//
+//	for attempt := range backoffkit.Attempts(ctx, backoffkit.FullJitter(backoffkit.Exponential(100*time.Millisecond, 5*time.Second)), 5) {
+//		if err = send(ctx); err == nil {
+//			break
+//		}
+//	}
+package backoffkit
+
+import (
+	"math"
+	"math/rand/v2"
+	"time"
+)
+
+// Strategy returns how long to wait before attempt, where 2 is the first retry, given the delay it
+// returned before the previous attempt, which is 0 before the first retry
+type Strategy func(attempt int, previous time.Duration) time.Duration
+
+// Constant waits delay before every retry
+func Constant(delay time.Duration) Strategy {
+	return func(int, time.Duration) time.Duration {
+		return delay
+	}
+}
+
+// Exponential waits base before the first retry and twice as long before each retry after it, up to
+// max; a max of 0 or less doesn't cap the delay
+func Exponential(base time.Duration, max time.Duration) Strategy {
+	return func(attempt int, _ time.Duration) time.Duration {
+		return capped(float64(base)*math.Pow(2, float64(retry(attempt)-1)), max)
+	}
+}
+
+// Fibonacci waits base before the first two retries and then the sum of the two delays before, up to
+// max, growing more slowly than Exponential; a max of 0 or less doesn't cap the delay
+func Fibonacci(base time.Duration, max time.Duration) Strategy {
+	return func(attempt int, _ time.Duration) time.Duration {
+		a, b := 1.0, 1.0
+		for range retry(attempt) - 1 {
+			a, b = b, a+b
+		}
+		return capped(float64(base)*a, max)
+	}
+}
+
+// DecorrelatedJitter waits a random delay between base and three times the previous delay, up to max,
+// spreading out clients that fail together better than jitter on a fixed schedule
+func DecorrelatedJitter(base time.Duration, max time.Duration) Strategy {
+	return func(_ int, previous time.Duration) time.Duration {
+		if previous < base {
+			previous = base
+		}
+		upper := capped(float64(previous)*3, max)
+		if upper <= base {
+			return upper
+		}
+		return base + rand.N(upper-base+1)
+	}
+}
+
+// FullJitter waits a random delay between 0 and the delay of strategy
+func FullJitter(strategy Strategy) Strategy {
+	return func(attempt int, previous time.Duration) time.Duration {
+		delay := strategy(attempt, previous)
+		if delay <= 0 {
+			return 0
+		}
+		return rand.N(delay + 1)
+	}
+}
+
+// EqualJitter waits half the delay of strategy plus a random delay up to the other half, so it never
+// retries sooner than half the delay
+func EqualJitter(strategy Strategy) Strategy {
+	return func(attempt int, previous time.Duration) time.Duration {
+		delay := strategy(attempt, previous)
+		if delay <= 0 {
+			return 0
+		}
+		half := delay / 2
+		return half + rand.N(delay-half+1)
+	}
+}
+
+// retry returns which retry attempt is, 1 for the first
+func retry(attempt int) int {
+	return max(attempt-1, 1)
+}
+
+// capped returns delay as a duration up to max, or up to the longest duration when max is 0 or less
+func capped(delay float64, max time.Duration) time.Duration {
+	if max > 0 && delay > float64(max) {
+		return max
+	}
+	if delay >= math.MaxInt64 {
+		return math.MaxInt64
+	}
+	return time.Duration(delay)
+}
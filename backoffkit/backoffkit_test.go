@@ -0,0 +1,101 @@
+package backoffkit
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// delays returns the first n delays of strategy
+func delays(strategy Strategy, n int) []time.Duration {
+	result := []time.Duration{}
+	for _, delay := range Delays(strategy) {
+		if len(result) == n {
+			break
+		}
+		result = append(result, delay)
+	}
+	return result
+}
+
+func TestConstant(t *testing.T) {
+	t.Run("waits_the_same_before_every_retry", func(t *testing.T) {
+		assert.Equal(t, []time.Duration{time.Second, time.Second, time.Second}, delays(Constant(time.Second), 3))
+	})
+}
+
+func TestExponential(t *testing.T) {
+	t.Run("doubles_the_delay_up_to_max", func(t *testing.T) {
+		actual := delays(Exponential(100*time.Millisecond, time.Second), 6)
+
+		assert.Equal(t, []time.Duration{100 * time.Millisecond, 200 * time.Millisecond, 400 * time.Millisecond, 800 * time.Millisecond, time.Second, time.Second}, actual)
+	})
+
+	t.Run("doesnt_cap_without_max", func(t *testing.T) {
+		assert.Equal(t, 1024*time.Second, Exponential(time.Second, 0)(12, 0))
+	})
+
+	t.Run("doesnt_overflow", func(t *testing.T) {
+		assert.Equal(t, time.Duration(math.MaxInt64), Exponential(time.Second, 0)(200, 0))
+		assert.Equal(t, time.Minute, Exponential(time.Second, time.Minute)(200, 0))
+	})
+}
+
+func TestFibonacci(t *testing.T) {
+	t.Run("adds_the_two_delays_before_up_to_max", func(t *testing.T) {
+		actual := delays(Fibonacci(time.Second, 10*time.Second), 7)
+
+		assert.Equal(t, []time.Duration{time.Second, time.Second, 2 * time.Second, 3 * time.Second, 5 * time.Second, 8 * time.Second, 10 * time.Second}, actual)
+	})
+}
+
+func TestDecorrelatedJitter(t *testing.T) {
+	t.Run("waits_between_base_and_three_times_the_previous_delay_up_to_max", func(t *testing.T) {
+		strategy := DecorrelatedJitter(100*time.Millisecond, 2*time.Second)
+
+		previous := time.Duration(0)
+		for attempt := 2; attempt < 100; attempt++ {
+			delay := strategy(attempt, previous)
+
+			assert.GreaterOrEqual(t, delay, 100*time.Millisecond)
+			assert.LessOrEqual(t, delay, min(max(previous, 100*time.Millisecond)*3, 2*time.Second))
+			previous = delay
+		}
+	})
+
+	t.Run("waits_max_when_base_is_over_it", func(t *testing.T) {
+		assert.Equal(t, time.Second, DecorrelatedJitter(2*time.Second, time.Second)(2, 0))
+	})
+}
+
+func TestFullJitter(t *testing.T) {
+	t.Run("waits_up_to_the_delay", func(t *testing.T) {
+		strategy := FullJitter(Constant(time.Second))
+
+		for range 100 {
+			delay := strategy(2, 0)
+
+			assert.GreaterOrEqual(t, delay, time.Duration(0))
+			assert.LessOrEqual(t, delay, time.Second)
+		}
+	})
+
+	t.Run("waits_nothing_for_no_delay", func(t *testing.T) {
+		assert.Zero(t, FullJitter(Constant(0))(2, 0))
+	})
+}
+
+func TestEqualJitter(t *testing.T) {
+	t.Run("waits_between_half_the_delay_and_the_delay", func(t *testing.T) {
+		strategy := EqualJitter(Constant(time.Second))
+
+		for range 100 {
+			delay := strategy(2, 0)
+
+			assert.GreaterOrEqual(t, delay, 500*time.Millisecond)
+			assert.LessOrEqual(t, delay, time.Second)
+		}
+	})
+}
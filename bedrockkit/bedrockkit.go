@@ -0,0 +1,75 @@
+// Package bedrockkit calls Amazon Bedrock models: InvokeModel with typed request and response bodies,
+// Converse for chat, and streaming versions of both as iterators. Throttled calls are retried with
+// backoff, and each invocation's token usage is logged and can be recorded with UseInstrumentation.
+// Like dynamodbkit, calls use the client carried by their context (see WithClient) or the default
+// client, which uses awskit.DefaultConfig; FakeBedrockRuntime stands in for Bedrock in tests.
+package bedrockkit
+
+import (
+	"context"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/bedrockruntime"
+
+	"github.com/half-ogre/go-kit/logkit"
+)
+
+var logger = logkit.Logger("bedrockkit")
+
+// BedrockRuntime is the part of *bedrockruntime.Client bedrockkit uses
+type BedrockRuntime interface {
+	Converse(ctx context.Context, params *bedrockruntime.ConverseInput, optFns ...func(*bedrockruntime.Options)) (*bedrockruntime.ConverseOutput, error)
+	ConverseStream(ctx context.Context, params *bedrockruntime.ConverseStreamInput, optFns ...func(*bedrockruntime.Options)) (*bedrockruntime.ConverseStreamOutput, error)
+	InvokeModel(ctx context.Context, params *bedrockruntime.InvokeModelInput, optFns ...func(*bedrockruntime.Options)) (*bedrockruntime.InvokeModelOutput, error)
+	InvokeModelWithResponseStream(ctx context.Context, params *bedrockruntime.InvokeModelWithResponseStreamInput, optFns ...func(*bedrockruntime.Options)) (*bedrockruntime.InvokeModelWithResponseStreamOutput, error)
+}
+
+// Usage is the tokens used by an invocation
+type Usage struct {
+	InputTokens  int
+	OutputTokens int
+}
+
+// InvocationMetrics describes one invocation, for UseInstrumentation. Streaming invocations report
+// when their stream ends.
+type InvocationMetrics struct {
+	Operation string
+	ModelID   string
+	Attempts  int
+	Duration  time.Duration
+	Usage     Usage
+	Err       error
+}
+
+// invocation tracks one call for logging and instrumentation
+type invocation struct {
+	InvocationMetrics
+	client *Client
+	start  time.Time
+}
+
+func startInvocation(client *Client, operation string, modelID string) *invocation {
+	return &invocation{
+		InvocationMetrics: InvocationMetrics{Operation: operation, ModelID: modelID},
+		client:            client,
+		start:             time.Now(),
+	}
+}
+
+func (i *invocation) finish(ctx context.Context, err error) {
+	i.Duration = time.Since(i.start)
+	i.Err = err
+
+	logger.DebugContext(ctx, "bedrock invocation finished",
+		"operation", i.Operation,
+		"model_id", i.ModelID,
+		"attempts", i.Attempts,
+		"duration", i.Duration,
+		"input_tokens", i.Usage.InputTokens,
+		"output_tokens", i.Usage.OutputTokens,
+		"error", err)
+
+	if i.client.instrumentation != nil {
+		i.client.instrumentation(ctx, i.InvocationMetrics)
+	}
+}
@@ -0,0 +1,117 @@
+package bedrockkit
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/bedrockruntime"
+
+	"github.com/half-ogre/go-kit/awskit"
+)
+
+// Client is a Bedrock runtime connection together with the retry and instrumentation configuration
+// calls use with it. Calls use the client carried by their context (see WithClient) and otherwise the
+// default client, which uses awskit.DefaultConfig and is what the package-level Use functions
+// configure.
+type Client struct {
+	newRuntime      func(ctx context.Context) (BedrockRuntime, error)
+	maxAttempts     int
+	retryDelay      time.Duration
+	instrumentation func(ctx context.Context, metrics InvocationMetrics)
+}
+
+// ClientOption configures NewClient
+type ClientOption func(*Client)
+
+// NewClient returns a client for runtime, e.g. bedrockruntime.NewFromConfig(cfg) or a FakeBedrockRuntime
+func NewClient(runtime BedrockRuntime, options ...ClientOption) *Client {
+	client := &Client{
+		newRuntime:  func(ctx context.Context) (BedrockRuntime, error) { return runtime, nil },
+		maxAttempts: defaultMaxAttempts,
+		retryDelay:  defaultRetryDelay,
+	}
+	for _, option := range options {
+		option(client)
+	}
+	return client
+}
+
+// WithClientRetry sets how many times a throttled call is attempted and the delay before the first
+// retry, which doubles with each retry; the defaults are 4 attempts and 1 second
+func WithClientRetry(maxAttempts int, delay time.Duration) ClientOption {
+	return func(c *Client) {
+		c.maxAttempts = max(maxAttempts, 1)
+		c.retryDelay = delay
+	}
+}
+
+// WithClientInstrumentation sets the function that receives InvocationMetrics after each call; nil
+// turns it off
+func WithClientInstrumentation(record func(ctx context.Context, metrics InvocationMetrics)) ClientOption {
+	return func(c *Client) {
+		c.instrumentation = record
+	}
+}
+
+// UseRetry sets the default client's retry of throttled calls, as described on WithClientRetry
+func UseRetry(maxAttempts int, delay time.Duration) {
+	updateDefaultClient(WithClientRetry(maxAttempts, delay))
+}
+
+// UseInstrumentation sets a function that receives InvocationMetrics after each of the default
+// client's calls, e.g. to record token usage by model. Pass nil to turn it off.
+func UseInstrumentation(record func(ctx context.Context, metrics InvocationMetrics)) {
+	updateDefaultClient(WithClientInstrumentation(record))
+}
+
+type clientKey struct{}
+
+// WithClient returns a copy of ctx whose calls use client instead of the default client
+func WithClient(ctx context.Context, client *Client) context.Context {
+	return context.WithValue(ctx, clientKey{}, client)
+}
+
+// getClient returns the client carried by ctx, or the default client
+func getClient(ctx context.Context) *Client {
+	if client, ok := ctx.Value(clientKey{}).(*Client); ok && client != nil {
+		return client
+	}
+	return DefaultClient()
+}
+
+var defaultClient = &Client{newRuntime: loadBedrockRuntime, maxAttempts: defaultMaxAttempts, retryDelay: defaultRetryDelay}
+var defaultClientMu sync.Mutex
+
+// DefaultClient returns the client calls use when their context doesn't carry one
+func DefaultClient() *Client {
+	defaultClientMu.Lock()
+	defer defaultClientMu.Unlock()
+	return defaultClient
+}
+
+// SetDefaultClient replaces the client calls use when their context doesn't carry one
+func SetDefaultClient(client *Client) {
+	defaultClientMu.Lock()
+	defer defaultClientMu.Unlock()
+	defaultClient = client
+}
+
+// updateDefaultClient replaces the default client with a copy changed by option, so calls already
+// using the old one aren't changed under them
+func updateDefaultClient(option ClientOption) {
+	defaultClientMu.Lock()
+	defer defaultClientMu.Unlock()
+	updated := *defaultClient
+	option(&updated)
+	defaultClient = &updated
+}
+
+func loadBedrockRuntime(ctx context.Context) (BedrockRuntime, error) {
+	cfg, err := awskit.DefaultConfig(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return bedrockruntime.NewFromConfig(cfg), nil
+}
@@ -0,0 +1,249 @@
+package bedrockkit
+
+import (
+	"context"
+	"iter"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/bedrockruntime"
+	"github.com/aws/aws-sdk-go-v2/service/bedrockruntime/types"
+
+	"github.com/half-ogre/go-kit/kit"
+)
+
+// ConverseOption configures Converse and ConverseStream
+type ConverseOption func(*converseConfig)
+
+type converseConfig struct {
+	maxAttempts     int
+	system          []types.SystemContentBlock
+	inferenceConfig *types.InferenceConfiguration
+	toolConfig      *types.ToolConfiguration
+}
+
+// WithSystemPrompt adds a system prompt to the conversation
+func WithSystemPrompt(prompt string) ConverseOption {
+	return func(c *converseConfig) {
+		c.system = append(c.system, &types.SystemContentBlockMemberText{Value: prompt})
+	}
+}
+
+// WithMaxTokens limits the tokens the model generates
+func WithMaxTokens(maxTokens int) ConverseOption {
+	return func(c *converseConfig) {
+		c.inference().MaxTokens = aws.Int32(int32(maxTokens))
+	}
+}
+
+// WithTemperature sets the model's sampling temperature
+func WithTemperature(temperature float32) ConverseOption {
+	return func(c *converseConfig) {
+		c.inference().Temperature = aws.Float32(temperature)
+	}
+}
+
+// WithStopSequences sets sequences that stop the model generating
+func WithStopSequences(sequences ...string) ConverseOption {
+	return func(c *converseConfig) {
+		c.inference().StopSequences = sequences
+	}
+}
+
+// WithTools sets the tools the model may ask to use
+func WithTools(toolConfig *types.ToolConfiguration) ConverseOption {
+	return func(c *converseConfig) {
+		c.toolConfig = toolConfig
+	}
+}
+
+// WithConverseMaxAttempts sets how many times the call is attempted when throttled, instead of the
+// client's setting
+func WithConverseMaxAttempts(maxAttempts int) ConverseOption {
+	return func(c *converseConfig) {
+		c.maxAttempts = max(maxAttempts, 1)
+	}
+}
+
+func (c *converseConfig) inference() *types.InferenceConfiguration {
+	if c.inferenceConfig == nil {
+		c.inferenceConfig = &types.InferenceConfiguration{}
+	}
+	return c.inferenceConfig
+}
+
+func newConverseConfig(client *Client, options []ConverseOption) *converseConfig {
+	config := &converseConfig{maxAttempts: client.maxAttempts}
+	for _, option := range options {
+		option(config)
+	}
+	return config
+}
+
+// UserMessage returns a user message with text as its content
+func UserMessage(text string) types.Message {
+	return types.Message{Role: types.ConversationRoleUser, Content: []types.ContentBlock{&types.ContentBlockMemberText{Value: text}}}
+}
+
+// AssistantMessage returns an assistant message with text as its content, e.g. an earlier response
+func AssistantMessage(text string) types.Message {
+	return types.Message{Role: types.ConversationRoleAssistant, Content: []types.ContentBlock{&types.ContentBlockMemberText{Value: text}}}
+}
+
+// ConverseResponse is the model's reply to Converse
+type ConverseResponse struct {
+	// Text is the reply's text content blocks joined together
+	Text string
+	// Message is the whole reply, including any tool use requests
+	Message    types.Message
+	StopReason types.StopReason
+	Usage      Usage
+}
+
+// ConverseChunk is one piece of a streamed reply. The final chunks carry the stop reason and usage.
+type ConverseChunk struct {
+	Text       string
+	StopReason types.StopReason
+	Usage      *Usage
+}
+
+// Converse sends messages to the model with the Converse API, which takes the same request for every
+// model, and returns its reply
+func Converse(ctx context.Context, modelID string, messages []types.Message, options ...ConverseOption) (_ *ConverseResponse, err error) {
+	client := getClient(ctx)
+	inv := startInvocation(client, "Converse", modelID)
+	defer func() { inv.finish(ctx, err) }()
+
+	runtime, err := client.newRuntime(ctx)
+	if err != nil {
+		return nil, kit.WrapError(err, "error creating Bedrock runtime client")
+	}
+
+	config := newConverseConfig(client, options)
+	output, err := retry(ctx, inv, config.maxAttempts, client.retryDelay, func() (*bedrockruntime.ConverseOutput, error) {
+		return runtime.Converse(ctx, &bedrockruntime.ConverseInput{
+			ModelId:         aws.String(modelID),
+			Messages:        messages,
+			System:          config.system,
+			InferenceConfig: config.inferenceConfig,
+			ToolConfig:      config.toolConfig,
+		})
+	})
+	if err != nil {
+		return nil, kit.WrapError(err, "error conversing with model %s", modelID)
+	}
+
+	response := &ConverseResponse{StopReason: output.StopReason}
+	if message, ok := output.Output.(*types.ConverseOutputMemberMessage); ok {
+		response.Message = message.Value
+		response.Text = messageText(message.Value)
+	}
+	if output.Usage != nil {
+		response.Usage = tokenUsage(output.Usage)
+	}
+	inv.Usage = response.Usage
+
+	return response, nil
+}
+
+// ConverseStream sends messages to the model as Converse does and yields a chunk for each piece of
+// text in its reply as the model generates it
+func ConverseStream(ctx context.Context, modelID string, messages []types.Message, options ...ConverseOption) iter.Seq2[ConverseChunk, error] {
+	return func(yield func(ConverseChunk, error) bool) {
+		client := getClient(ctx)
+		inv := startInvocation(client, "ConverseStream", modelID)
+		var err error
+		defer func() { inv.finish(ctx, err) }()
+
+		runtime, err := client.newRuntime(ctx)
+		if err != nil {
+			err = kit.WrapError(err, "error creating Bedrock runtime client")
+			yield(ConverseChunk{}, err)
+			return
+		}
+
+		config := newConverseConfig(client, options)
+		output, err := retry(ctx, inv, config.maxAttempts, client.retryDelay, func() (*bedrockruntime.ConverseStreamOutput, error) {
+			return runtime.ConverseStream(ctx, &bedrockruntime.ConverseStreamInput{
+				ModelId:         aws.String(modelID),
+				Messages:        messages,
+				System:          config.system,
+				InferenceConfig: config.inferenceConfig,
+				ToolConfig:      config.toolConfig,
+			})
+		})
+		if err != nil {
+			err = kit.WrapError(err, "error conversing with model %s", modelID)
+			yield(ConverseChunk{}, err)
+			return
+		}
+
+		stream := converseEvents(runtime, output)
+		defer stream.Close()
+
+		for event := range stream.Events() {
+			chunk := ConverseChunk{}
+			switch e := event.(type) {
+			case *types.ConverseStreamOutputMemberContentBlockDelta:
+				text, ok := e.Value.Delta.(*types.ContentBlockDeltaMemberText)
+				if !ok {
+					continue
+				}
+				chunk.Text = text.Value
+			case *types.ConverseStreamOutputMemberMessageStop:
+				chunk.StopReason = e.Value.StopReason
+			case *types.ConverseStreamOutputMemberMetadata:
+				if e.Value.Usage == nil {
+					continue
+				}
+				usage := tokenUsage(e.Value.Usage)
+				inv.Usage = usage
+				chunk.Usage = &usage
+			default:
+				continue
+			}
+
+			if !yield(chunk, nil) {
+				return
+			}
+		}
+
+		if err = stream.Err(); err != nil {
+			err = kit.WrapError(err, "error streaming reply from model %s", modelID)
+			yield(ConverseChunk{}, err)
+		}
+	}
+}
+
+// converseEventStream is the part of *bedrockruntime.ConverseStreamEventStream used by ConverseStream
+type converseEventStream interface {
+	Events() <-chan types.ConverseStreamOutput
+	Close() error
+	Err() error
+}
+
+// converseEvents returns output's event stream, or the fake's events for a FakeBedrockRuntime, as the
+// stream can't be set outside the SDK
+func converseEvents(runtime BedrockRuntime, output *bedrockruntime.ConverseStreamOutput) converseEventStream {
+	if fake, ok := runtime.(*FakeBedrockRuntime); ok {
+		return fake.converseEvents(output)
+	}
+	return output.GetStream()
+}
+
+func messageText(message types.Message) string {
+	var text strings.Builder
+	for _, block := range message.Content {
+		if textBlock, ok := block.(*types.ContentBlockMemberText); ok {
+			text.WriteString(textBlock.Value)
+		}
+	}
+	return text.String()
+}
+
+func tokenUsage(usage *types.TokenUsage) Usage {
+	return Usage{
+		InputTokens:  int(aws.ToInt32(usage.InputTokens)),
+		OutputTokens: int(aws.ToInt32(usage.OutputTokens)),
+	}
+}
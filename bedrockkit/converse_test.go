@@ -0,0 +1,129 @@
+package bedrockkit
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/bedrockruntime"
+	"github.com/aws/aws-sdk-go-v2/service/bedrockruntime/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestContext(runtime BedrockRuntime, options ...ClientOption) context.Context {
+	return WithClient(context.Background(), NewClient(runtime, options...))
+}
+
+func TestConverse(t *testing.T) {
+	t.Run("sends_the_conversation_and_returns_the_reply", func(t *testing.T) {
+		var sent *bedrockruntime.ConverseInput
+		theReply := types.Message{Role: types.ConversationRoleAssistant, Content: []types.ContentBlock{&types.ContentBlockMemberText{Value: "the"}, &types.ContentBlockMemberText{Value: "Answer"}}}
+		ctx := newTestContext(&FakeBedrockRuntime{
+			ConverseFake: func(ctx context.Context, params *bedrockruntime.ConverseInput, optFns ...func(*bedrockruntime.Options)) (*bedrockruntime.ConverseOutput, error) {
+				sent = params
+				return &bedrockruntime.ConverseOutput{
+					Output:     &types.ConverseOutputMemberMessage{Value: theReply},
+					StopReason: types.StopReasonEndTurn,
+					Usage:      &types.TokenUsage{InputTokens: aws.Int32(3), OutputTokens: aws.Int32(5)},
+				}, nil
+			},
+		})
+
+		response, err := Converse(ctx, "theModel", []types.Message{UserMessage("theQuestion")}, WithSystemPrompt("theInstructions"), WithMaxTokens(100), WithTemperature(0.5))
+
+		assert.NoError(t, err)
+		assert.Equal(t, &ConverseResponse{Text: "theAnswer", Message: theReply, StopReason: types.StopReasonEndTurn, Usage: Usage{InputTokens: 3, OutputTokens: 5}}, response)
+		assert.Equal(t, "theModel", aws.ToString(sent.ModelId))
+		assert.Equal(t, []types.Message{UserMessage("theQuestion")}, sent.Messages)
+		assert.Equal(t, []types.SystemContentBlock{&types.SystemContentBlockMemberText{Value: "theInstructions"}}, sent.System)
+		assert.Equal(t, &types.InferenceConfiguration{MaxTokens: aws.Int32(100), Temperature: aws.Float32(0.5)}, sent.InferenceConfig)
+	})
+
+	t.Run("records_the_token_usage", func(t *testing.T) {
+		metrics := []InvocationMetrics{}
+		ctx := newTestContext(&FakeBedrockRuntime{
+			ConverseFake: func(ctx context.Context, params *bedrockruntime.ConverseInput, optFns ...func(*bedrockruntime.Options)) (*bedrockruntime.ConverseOutput, error) {
+				return &bedrockruntime.ConverseOutput{Usage: &types.TokenUsage{InputTokens: aws.Int32(3), OutputTokens: aws.Int32(5)}}, nil
+			},
+		}, WithClientInstrumentation(func(ctx context.Context, m InvocationMetrics) {
+			metrics = append(metrics, m)
+		}))
+
+		_, err := Converse(ctx, "theModel", []types.Message{UserMessage("theQuestion")})
+
+		assert.NoError(t, err)
+		assert.Len(t, metrics, 1)
+		assert.Equal(t, "Converse", metrics[0].Operation)
+		assert.Equal(t, "theModel", metrics[0].ModelID)
+		assert.Equal(t, 1, metrics[0].Attempts)
+		assert.Equal(t, Usage{InputTokens: 3, OutputTokens: 5}, metrics[0].Usage)
+		assert.NoError(t, metrics[0].Err)
+	})
+
+	t.Run("returns_an_error_when_converse_fails", func(t *testing.T) {
+		metrics := []InvocationMetrics{}
+		ctx := newTestContext(&FakeBedrockRuntime{
+			ConverseFake: func(ctx context.Context, params *bedrockruntime.ConverseInput, optFns ...func(*bedrockruntime.Options)) (*bedrockruntime.ConverseOutput, error) {
+				return nil, assert.AnError
+			},
+		}, WithClientInstrumentation(func(ctx context.Context, m InvocationMetrics) {
+			metrics = append(metrics, m)
+		}))
+
+		_, err := Converse(ctx, "theModel", []types.Message{UserMessage("theQuestion")})
+
+		assert.ErrorIs(t, err, assert.AnError)
+		assert.Contains(t, err.Error(), "error conversing with model theModel")
+		assert.ErrorIs(t, metrics[0].Err, assert.AnError)
+	})
+}
+
+func TestConverseStream(t *testing.T) {
+	t.Run("yields_a_chunk_for_each_text_delta", func(t *testing.T) {
+		metrics := []InvocationMetrics{}
+		ctx := newTestContext(&FakeBedrockRuntime{
+			ConverseStreamFake: func(ctx context.Context, params *bedrockruntime.ConverseStreamInput, optFns ...func(*bedrockruntime.Options)) ([]types.ConverseStreamOutput, error) {
+				return []types.ConverseStreamOutput{
+					&types.ConverseStreamOutputMemberMessageStart{},
+					&types.ConverseStreamOutputMemberContentBlockDelta{Value: types.ContentBlockDeltaEvent{Delta: &types.ContentBlockDeltaMemberText{Value: "the"}}},
+					&types.ConverseStreamOutputMemberContentBlockDelta{Value: types.ContentBlockDeltaEvent{Delta: &types.ContentBlockDeltaMemberText{Value: "Answer"}}},
+					&types.ConverseStreamOutputMemberMessageStop{Value: types.MessageStopEvent{StopReason: types.StopReasonEndTurn}},
+					&types.ConverseStreamOutputMemberMetadata{Value: types.ConverseStreamMetadataEvent{Usage: &types.TokenUsage{InputTokens: aws.Int32(3), OutputTokens: aws.Int32(2)}}},
+				}, nil
+			},
+		}, WithClientInstrumentation(func(ctx context.Context, m InvocationMetrics) {
+			metrics = append(metrics, m)
+		}))
+
+		chunks := []ConverseChunk{}
+		for chunk, err := range ConverseStream(ctx, "theModel", []types.Message{UserMessage("theQuestion")}) {
+			assert.NoError(t, err)
+			chunks = append(chunks, chunk)
+		}
+
+		assert.Equal(t, []ConverseChunk{
+			{Text: "the"},
+			{Text: "Answer"},
+			{StopReason: types.StopReasonEndTurn},
+			{Usage: &Usage{InputTokens: 3, OutputTokens: 2}},
+		}, chunks)
+		assert.Len(t, metrics, 1)
+		assert.Equal(t, Usage{InputTokens: 3, OutputTokens: 2}, metrics[0].Usage)
+	})
+
+	t.Run("yields_an_error_when_the_stream_cannot_be_opened", func(t *testing.T) {
+		ctx := newTestContext(&FakeBedrockRuntime{
+			ConverseStreamFake: func(ctx context.Context, params *bedrockruntime.ConverseStreamInput, optFns ...func(*bedrockruntime.Options)) ([]types.ConverseStreamOutput, error) {
+				return nil, assert.AnError
+			},
+		})
+
+		errs := []error{}
+		for _, err := range ConverseStream(ctx, "theModel", []types.Message{UserMessage("theQuestion")}) {
+			errs = append(errs, err)
+		}
+
+		assert.Len(t, errs, 1)
+		assert.ErrorIs(t, errs[0], assert.AnError)
+	})
+}
@@ -0,0 +1,112 @@
+package bedrockkit
+
+import (
+	"context"
+	"sync"
+
+	"github.com/aws/aws-sdk-go-v2/service/bedrockruntime"
+	"github.com/aws/aws-sdk-go-v2/service/bedrockruntime/types"
+)
+
+// FakeBedrockRuntime is a BedrockRuntime for tests, e.g. with NewClient and WithClient. The stream
+// fakes return the events the stream yields, as the SDK's streams can't be created outside it.
+type FakeBedrockRuntime struct {
+	ConverseFake          func(ctx context.Context, params *bedrockruntime.ConverseInput, optFns ...func(*bedrockruntime.Options)) (*bedrockruntime.ConverseOutput, error)
+	ConverseStreamFake    func(ctx context.Context, params *bedrockruntime.ConverseStreamInput, optFns ...func(*bedrockruntime.Options)) ([]types.ConverseStreamOutput, error)
+	InvokeModelFake       func(ctx context.Context, params *bedrockruntime.InvokeModelInput, optFns ...func(*bedrockruntime.Options)) (*bedrockruntime.InvokeModelOutput, error)
+	InvokeModelStreamFake func(ctx context.Context, params *bedrockruntime.InvokeModelWithResponseStreamInput, optFns ...func(*bedrockruntime.Options)) ([]types.ResponseStream, error)
+
+	mu                 sync.Mutex
+	converseStreams    map[*bedrockruntime.ConverseStreamOutput][]types.ConverseStreamOutput
+	invokeModelStreams map[*bedrockruntime.InvokeModelWithResponseStreamOutput][]types.ResponseStream
+}
+
+func (f *FakeBedrockRuntime) Converse(ctx context.Context, params *bedrockruntime.ConverseInput, optFns ...func(*bedrockruntime.Options)) (*bedrockruntime.ConverseOutput, error) {
+	if f.ConverseFake != nil {
+		return f.ConverseFake(ctx, params, optFns...)
+	} else {
+		panic("Converse fake not implemented")
+	}
+}
+
+func (f *FakeBedrockRuntime) ConverseStream(ctx context.Context, params *bedrockruntime.ConverseStreamInput, optFns ...func(*bedrockruntime.Options)) (*bedrockruntime.ConverseStreamOutput, error) {
+	if f.ConverseStreamFake == nil {
+		panic("ConverseStream fake not implemented")
+	}
+
+	events, err := f.ConverseStreamFake(ctx, params, optFns...)
+	if err != nil {
+		return nil, err
+	}
+
+	output := &bedrockruntime.ConverseStreamOutput{}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.converseStreams == nil {
+		f.converseStreams = map[*bedrockruntime.ConverseStreamOutput][]types.ConverseStreamOutput{}
+	}
+	f.converseStreams[output] = events
+	return output, nil
+}
+
+func (f *FakeBedrockRuntime) InvokeModel(ctx context.Context, params *bedrockruntime.InvokeModelInput, optFns ...func(*bedrockruntime.Options)) (*bedrockruntime.InvokeModelOutput, error) {
+	if f.InvokeModelFake != nil {
+		return f.InvokeModelFake(ctx, params, optFns...)
+	} else {
+		panic("InvokeModel fake not implemented")
+	}
+}
+
+func (f *FakeBedrockRuntime) InvokeModelWithResponseStream(ctx context.Context, params *bedrockruntime.InvokeModelWithResponseStreamInput, optFns ...func(*bedrockruntime.Options)) (*bedrockruntime.InvokeModelWithResponseStreamOutput, error) {
+	if f.InvokeModelStreamFake == nil {
+		panic("InvokeModelWithResponseStream fake not implemented")
+	}
+
+	events, err := f.InvokeModelStreamFake(ctx, params, optFns...)
+	if err != nil {
+		return nil, err
+	}
+
+	output := &bedrockruntime.InvokeModelWithResponseStreamOutput{}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.invokeModelStreams == nil {
+		f.invokeModelStreams = map[*bedrockruntime.InvokeModelWithResponseStreamOutput][]types.ResponseStream{}
+	}
+	f.invokeModelStreams[output] = events
+	return output, nil
+}
+
+func (f *FakeBedrockRuntime) converseEvents(output *bedrockruntime.ConverseStreamOutput) converseEventStream {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	events := f.converseStreams[output]
+	delete(f.converseStreams, output)
+	return newFakeEventStream(events)
+}
+
+func (f *FakeBedrockRuntime) invokeModelEvents(output *bedrockruntime.InvokeModelWithResponseStreamOutput) invokeModelEventStream {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	events := f.invokeModelStreams[output]
+	delete(f.invokeModelStreams, output)
+	return newFakeEventStream(events)
+}
+
+// fakeEventStream yields a fixed list of events
+type fakeEventStream[TEvent any] struct {
+	events chan TEvent
+}
+
+func newFakeEventStream[TEvent any](events []TEvent) *fakeEventStream[TEvent] {
+	stream := &fakeEventStream[TEvent]{events: make(chan TEvent, len(events))}
+	for _, event := range events {
+		stream.events <- event
+	}
+	close(stream.events)
+	return stream
+}
+
+func (s *fakeEventStream[TEvent]) Events() <-chan TEvent { return s.events }
+func (s *fakeEventStream[TEvent]) Close() error          { return nil }
+func (s *fakeEventStream[TEvent]) Err() error            { return nil }
@@ -0,0 +1,204 @@
+package bedrockkit
+
+import (
+	"context"
+	"encoding/json"
+	"iter"
+	"strconv"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awsmiddleware "github.com/aws/aws-sdk-go-v2/aws/middleware"
+	"github.com/aws/aws-sdk-go-v2/service/bedrockruntime"
+	"github.com/aws/aws-sdk-go-v2/service/bedrockruntime/types"
+	smithyhttp "github.com/aws/smithy-go/transport/http"
+
+	"github.com/half-ogre/go-kit/kit"
+)
+
+// InvokeModelOption configures InvokeModel and InvokeModelStream
+type InvokeModelOption func(*invokeModelConfig)
+
+type invokeModelConfig struct {
+	maxAttempts      int
+	guardrailID      *string
+	guardrailVersion *string
+}
+
+// WithInvokeModelMaxAttempts sets how many times the call is attempted when throttled, instead of the
+// client's setting
+func WithInvokeModelMaxAttempts(maxAttempts int) InvokeModelOption {
+	return func(c *invokeModelConfig) {
+		c.maxAttempts = max(maxAttempts, 1)
+	}
+}
+
+// WithInvokeModelGuardrail applies a Bedrock guardrail to the invocation
+func WithInvokeModelGuardrail(id string, version string) InvokeModelOption {
+	return func(c *invokeModelConfig) {
+		c.guardrailID = aws.String(id)
+		c.guardrailVersion = aws.String(version)
+	}
+}
+
+func newInvokeModelConfig(client *Client, options []InvokeModelOption) *invokeModelConfig {
+	config := &invokeModelConfig{maxAttempts: client.maxAttempts}
+	for _, option := range options {
+		option(config)
+	}
+	return config
+}
+
+// InvokeModel sends request to the model as its JSON body and decodes the model's JSON response into
+// a TResponse. The bodies are specific to each model's provider, e.g. Anthropic's messages API.
+func InvokeModel[TResponse any](ctx context.Context, modelID string, request any, options ...InvokeModelOption) (_ *TResponse, err error) {
+	client := getClient(ctx)
+	inv := startInvocation(client, "InvokeModel", modelID)
+	defer func() { inv.finish(ctx, err) }()
+
+	body, err := json.Marshal(request)
+	if err != nil {
+		return nil, kit.WrapError(err, "error marshalling request for model %s", modelID)
+	}
+
+	runtime, err := client.newRuntime(ctx)
+	if err != nil {
+		return nil, kit.WrapError(err, "error creating Bedrock runtime client")
+	}
+
+	config := newInvokeModelConfig(client, options)
+	output, err := retry(ctx, inv, config.maxAttempts, client.retryDelay, func() (*bedrockruntime.InvokeModelOutput, error) {
+		return runtime.InvokeModel(ctx, &bedrockruntime.InvokeModelInput{
+			ModelId:             aws.String(modelID),
+			Body:                body,
+			ContentType:         aws.String("application/json"),
+			Accept:              aws.String("application/json"),
+			GuardrailIdentifier: config.guardrailID,
+			GuardrailVersion:    config.guardrailVersion,
+		})
+	})
+	if err != nil {
+		return nil, kit.WrapError(err, "error invoking model %s", modelID)
+	}
+
+	inv.Usage = invokeModelUsage(output)
+
+	var response TResponse
+	if err := json.Unmarshal(output.Body, &response); err != nil {
+		return nil, kit.WrapError(err, "error unmarshalling response from model %s", modelID)
+	}
+
+	return &response, nil
+}
+
+// InvokeModelStream sends request to the model as InvokeModel does and yields each chunk of the
+// streamed response decoded into a TChunk, e.g. Anthropic's content_block_delta events
+func InvokeModelStream[TChunk any](ctx context.Context, modelID string, request any, options ...InvokeModelOption) iter.Seq2[TChunk, error] {
+	return func(yield func(TChunk, error) bool) {
+		var zero TChunk
+		client := getClient(ctx)
+		inv := startInvocation(client, "InvokeModelWithResponseStream", modelID)
+		var err error
+		defer func() { inv.finish(ctx, err) }()
+
+		body, err := json.Marshal(request)
+		if err != nil {
+			err = kit.WrapError(err, "error marshalling request for model %s", modelID)
+			yield(zero, err)
+			return
+		}
+
+		runtime, err := client.newRuntime(ctx)
+		if err != nil {
+			err = kit.WrapError(err, "error creating Bedrock runtime client")
+			yield(zero, err)
+			return
+		}
+
+		config := newInvokeModelConfig(client, options)
+		output, err := retry(ctx, inv, config.maxAttempts, client.retryDelay, func() (*bedrockruntime.InvokeModelWithResponseStreamOutput, error) {
+			return runtime.InvokeModelWithResponseStream(ctx, &bedrockruntime.InvokeModelWithResponseStreamInput{
+				ModelId:             aws.String(modelID),
+				Body:                body,
+				ContentType:         aws.String("application/json"),
+				Accept:              aws.String("application/json"),
+				GuardrailIdentifier: config.guardrailID,
+				GuardrailVersion:    config.guardrailVersion,
+			})
+		})
+		if err != nil {
+			err = kit.WrapError(err, "error invoking model %s", modelID)
+			yield(zero, err)
+			return
+		}
+
+		stream := invokeModelEvents(runtime, output)
+		defer stream.Close()
+
+		for event := range stream.Events() {
+			chunk, ok := event.(*types.ResponseStreamMemberChunk)
+			if !ok {
+				continue
+			}
+
+			addStreamUsage(&inv.Usage, chunk.Value.Bytes)
+
+			var decoded TChunk
+			if err = json.Unmarshal(chunk.Value.Bytes, &decoded); err != nil {
+				err = kit.WrapError(err, "error unmarshalling response chunk from model %s", modelID)
+				yield(zero, err)
+				return
+			}
+			if !yield(decoded, nil) {
+				return
+			}
+		}
+
+		if err = stream.Err(); err != nil {
+			err = kit.WrapError(err, "error streaming response from model %s", modelID)
+			yield(zero, err)
+		}
+	}
+}
+
+// invokeModelEventStream is the part of *bedrockruntime.InvokeModelWithResponseStreamEventStream used
+// by InvokeModelStream
+type invokeModelEventStream interface {
+	Events() <-chan types.ResponseStream
+	Close() error
+	Err() error
+}
+
+// invokeModelEvents returns output's event stream, or the fake's events for a FakeBedrockRuntime, as
+// the stream can't be set outside the SDK
+func invokeModelEvents(runtime BedrockRuntime, output *bedrockruntime.InvokeModelWithResponseStreamOutput) invokeModelEventStream {
+	if fake, ok := runtime.(*FakeBedrockRuntime); ok {
+		return fake.invokeModelEvents(output)
+	}
+	return output.GetStream()
+}
+
+// invokeModelUsage reads the token counts Bedrock returns in InvokeModel's response headers
+func invokeModelUsage(output *bedrockruntime.InvokeModelOutput) Usage {
+	response, ok := awsmiddleware.GetRawResponse(output.ResultMetadata).(*smithyhttp.Response)
+	if !ok {
+		return Usage{}
+	}
+	inputTokens, _ := strconv.Atoi(response.Header.Get("X-Amzn-Bedrock-Input-Token-Count"))
+	outputTokens, _ := strconv.Atoi(response.Header.Get("X-Amzn-Bedrock-Output-Token-Count"))
+	return Usage{InputTokens: inputTokens, OutputTokens: outputTokens}
+}
+
+// addStreamUsage adds the token counts Bedrock adds to the last chunk of a streamed response
+func addStreamUsage(usage *Usage, chunk []byte) {
+	var metrics struct {
+		InvocationMetrics *struct {
+			InputTokenCount  int `json:"inputTokenCount"`
+			OutputTokenCount int `json:"outputTokenCount"`
+		} `json:"amazon-bedrock-invocationMetrics"`
+	}
+	if json.Unmarshal(chunk, &metrics) != nil || metrics.InvocationMetrics == nil {
+		return
+	}
+	usage.InputTokens += metrics.InvocationMetrics.InputTokenCount
+	usage.OutputTokens += metrics.InvocationMetrics.OutputTokenCount
+}
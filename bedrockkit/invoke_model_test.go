@@ -0,0 +1,99 @@
+package bedrockkit
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/bedrockruntime"
+	"github.com/aws/aws-sdk-go-v2/service/bedrockruntime/types"
+	"github.com/stretchr/testify/assert"
+)
+
+type testModelRequest struct {
+	Prompt string `json:"prompt"`
+}
+
+type testModelResponse struct {
+	Completion string `json:"completion"`
+}
+
+func TestInvokeModel(t *testing.T) {
+	t.Run("sends_the_request_body_and_decodes_the_response", func(t *testing.T) {
+		var sent *bedrockruntime.InvokeModelInput
+		ctx := newTestContext(&FakeBedrockRuntime{
+			InvokeModelFake: func(ctx context.Context, params *bedrockruntime.InvokeModelInput, optFns ...func(*bedrockruntime.Options)) (*bedrockruntime.InvokeModelOutput, error) {
+				sent = params
+				return &bedrockruntime.InvokeModelOutput{Body: []byte(`{"completion":"theAnswer"}`)}, nil
+			},
+		})
+
+		response, err := InvokeModel[testModelResponse](ctx, "theModel", testModelRequest{Prompt: "theQuestion"}, WithInvokeModelGuardrail("theGuardrail", "1"))
+
+		assert.NoError(t, err)
+		assert.Equal(t, &testModelResponse{Completion: "theAnswer"}, response)
+		assert.Equal(t, "theModel", aws.ToString(sent.ModelId))
+		assert.JSONEq(t, `{"prompt":"theQuestion"}`, string(sent.Body))
+		assert.Equal(t, "application/json", aws.ToString(sent.ContentType))
+		assert.Equal(t, "theGuardrail", aws.ToString(sent.GuardrailIdentifier))
+		assert.Equal(t, "1", aws.ToString(sent.GuardrailVersion))
+	})
+
+	t.Run("returns_an_error_when_the_response_cannot_be_decoded", func(t *testing.T) {
+		ctx := newTestContext(&FakeBedrockRuntime{
+			InvokeModelFake: func(ctx context.Context, params *bedrockruntime.InvokeModelInput, optFns ...func(*bedrockruntime.Options)) (*bedrockruntime.InvokeModelOutput, error) {
+				return &bedrockruntime.InvokeModelOutput{Body: []byte(`not json`)}, nil
+			},
+		})
+
+		_, err := InvokeModel[testModelResponse](ctx, "theModel", testModelRequest{Prompt: "theQuestion"})
+
+		assert.ErrorContains(t, err, "error unmarshalling response from model theModel")
+	})
+}
+
+func TestInvokeModelStream(t *testing.T) {
+	t.Run("yields_each_decoded_chunk_and_records_the_token_usage", func(t *testing.T) {
+		metrics := []InvocationMetrics{}
+		ctx := newTestContext(&FakeBedrockRuntime{
+			InvokeModelStreamFake: func(ctx context.Context, params *bedrockruntime.InvokeModelWithResponseStreamInput, optFns ...func(*bedrockruntime.Options)) ([]types.ResponseStream, error) {
+				return []types.ResponseStream{
+					&types.ResponseStreamMemberChunk{Value: types.PayloadPart{Bytes: []byte(`{"completion":"the"}`)}},
+					&types.ResponseStreamMemberChunk{Value: types.PayloadPart{Bytes: []byte(`{"completion":"Answer","amazon-bedrock-invocationMetrics":{"inputTokenCount":3,"outputTokenCount":2}}`)}},
+				}, nil
+			},
+		}, WithClientInstrumentation(func(ctx context.Context, m InvocationMetrics) {
+			metrics = append(metrics, m)
+		}))
+
+		chunks := []testModelResponse{}
+		for chunk, err := range InvokeModelStream[testModelResponse](ctx, "theModel", testModelRequest{Prompt: "theQuestion"}) {
+			assert.NoError(t, err)
+			chunks = append(chunks, chunk)
+		}
+
+		assert.Equal(t, []testModelResponse{{Completion: "the"}, {Completion: "Answer"}}, chunks)
+		assert.Len(t, metrics, 1)
+		assert.Equal(t, "InvokeModelWithResponseStream", metrics[0].Operation)
+		assert.Equal(t, Usage{InputTokens: 3, OutputTokens: 2}, metrics[0].Usage)
+	})
+
+	t.Run("stops_when_the_caller_stops", func(t *testing.T) {
+		ctx := newTestContext(&FakeBedrockRuntime{
+			InvokeModelStreamFake: func(ctx context.Context, params *bedrockruntime.InvokeModelWithResponseStreamInput, optFns ...func(*bedrockruntime.Options)) ([]types.ResponseStream, error) {
+				return []types.ResponseStream{
+					&types.ResponseStreamMemberChunk{Value: types.PayloadPart{Bytes: []byte(`{"completion":"the"}`)}},
+					&types.ResponseStreamMemberChunk{Value: types.PayloadPart{Bytes: []byte(`{"completion":"Answer"}`)}},
+				}, nil
+			},
+		})
+
+		chunks := []testModelResponse{}
+		for chunk := range InvokeModelStream[testModelResponse](ctx, "theModel", testModelRequest{Prompt: "theQuestion"}) {
+			chunks = append(chunks, chunk)
+			break
+		}
+
+		assert.Equal(t, []testModelResponse{{Completion: "the"}}, chunks)
+	})
+}
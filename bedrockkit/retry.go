@@ -0,0 +1,62 @@
+package bedrockkit
+
+import (
+	"context"
+	"errors"
+	"math/rand/v2"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/bedrockruntime/types"
+)
+
+const (
+	defaultMaxAttempts = 4
+	defaultRetryDelay  = time.Second
+)
+
+// sleep is replaced in tests
+var sleep = func(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// IsThrottled reports whether err is Bedrock refusing a call for now: throttling, the service being
+// unavailable, or the model not being ready. These are the errors calls retry.
+func IsThrottled(err error) bool {
+	var throttling *types.ThrottlingException
+	var unavailable *types.ServiceUnavailableException
+	var notReady *types.ModelNotReadyException
+	return errors.As(err, &throttling) || errors.As(err, &unavailable) || errors.As(err, &notReady)
+}
+
+// retry calls call until it succeeds, fails with an error IsThrottled doesn't report, or has been
+// attempted maxAttempts times, waiting between attempts with exponential backoff and jitter. The AWS
+// SDK retries a throttled request a few times quickly itself; this waits longer, as Bedrock quotas
+// are per minute.
+func retry[T any](ctx context.Context, inv *invocation, maxAttempts int, delay time.Duration, call func() (T, error)) (T, error) {
+	for {
+		inv.Attempts++
+		result, err := call()
+		if err == nil || !IsThrottled(err) || inv.Attempts >= maxAttempts {
+			return result, err
+		}
+
+		wait := delay<<(inv.Attempts-1) + rand.N(delay/2+1)
+		logger.InfoContext(ctx, "bedrock call throttled, retrying",
+			"operation", inv.Operation,
+			"model_id", inv.ModelID,
+			"attempt", inv.Attempts,
+			"wait", wait,
+			"error", err)
+		if err := sleep(ctx, wait); err != nil {
+			var zero T
+			return zero, err
+		}
+	}
+}
@@ -0,0 +1,83 @@
+package bedrockkit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/bedrockruntime"
+	"github.com/aws/aws-sdk-go-v2/service/bedrockruntime/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRetry(t *testing.T) {
+	newThrottledRuntime := func(failures int, calls *int) *FakeBedrockRuntime {
+		return &FakeBedrockRuntime{
+			ConverseFake: func(ctx context.Context, params *bedrockruntime.ConverseInput, optFns ...func(*bedrockruntime.Options)) (*bedrockruntime.ConverseOutput, error) {
+				*calls++
+				if *calls <= failures {
+					return nil, &types.ThrottlingException{}
+				}
+				return &bedrockruntime.ConverseOutput{}, nil
+			},
+		}
+	}
+
+	t.Run("retries_a_throttled_call_with_backoff", func(t *testing.T) {
+		waits := []time.Duration{}
+		previous := sleep
+		sleep = func(ctx context.Context, d time.Duration) error {
+			waits = append(waits, d)
+			return nil
+		}
+		t.Cleanup(func() { sleep = previous })
+		calls := 0
+		ctx := newTestContext(newThrottledRuntime(2, &calls), WithClientRetry(3, time.Second))
+
+		_, err := Converse(ctx, "theModel", []types.Message{UserMessage("theQuestion")})
+
+		assert.NoError(t, err)
+		assert.Equal(t, 3, calls)
+		assert.Len(t, waits, 2)
+		assert.GreaterOrEqual(t, waits[0], time.Second)
+		assert.Less(t, waits[0], 2*time.Second)
+		assert.GreaterOrEqual(t, waits[1], 2*time.Second)
+		assert.Less(t, waits[1], 3*time.Second)
+	})
+
+	t.Run("returns_the_throttling_error_after_the_last_attempt", func(t *testing.T) {
+		calls := 0
+		ctx := newTestContext(newThrottledRuntime(5, &calls), WithClientRetry(3, 0))
+
+		_, err := Converse(ctx, "theModel", []types.Message{UserMessage("theQuestion")}, WithConverseMaxAttempts(2))
+
+		assert.True(t, IsThrottled(err))
+		assert.Equal(t, 2, calls)
+	})
+
+	t.Run("does_not_retry_other_errors", func(t *testing.T) {
+		calls := 0
+		ctx := newTestContext(&FakeBedrockRuntime{
+			ConverseFake: func(ctx context.Context, params *bedrockruntime.ConverseInput, optFns ...func(*bedrockruntime.Options)) (*bedrockruntime.ConverseOutput, error) {
+				calls++
+				return nil, &types.ValidationException{}
+			},
+		}, WithClientRetry(3, 0))
+
+		_, err := Converse(ctx, "theModel", []types.Message{UserMessage("theQuestion")})
+
+		assert.False(t, IsThrottled(err))
+		assert.Equal(t, 1, calls)
+	})
+
+	t.Run("stops_waiting_when_the_context_is_canceled", func(t *testing.T) {
+		calls := 0
+		ctx, cancel := context.WithCancel(newTestContext(newThrottledRuntime(5, &calls), WithClientRetry(3, time.Hour)))
+		cancel()
+
+		_, err := Converse(ctx, "theModel", []types.Message{UserMessage("theQuestion")})
+
+		assert.ErrorIs(t, err, context.Canceled)
+		assert.Equal(t, 1, calls)
+	})
+}
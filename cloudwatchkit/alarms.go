@@ -0,0 +1,143 @@
+package cloudwatchkit
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/half-ogre/go-kit/kit"
+)
+
+// ComparisonOperator is how an alarm compares a metric's statistic with its threshold
+type ComparisonOperator string
+
+const (
+	GreaterThanThreshold          ComparisonOperator = "GreaterThanThreshold"
+	GreaterThanOrEqualToThreshold ComparisonOperator = "GreaterThanOrEqualToThreshold"
+	LessThanThreshold             ComparisonOperator = "LessThanThreshold"
+	LessThanOrEqualToThreshold    ComparisonOperator = "LessThanOrEqualToThreshold"
+)
+
+// Alarm is the definition of a CloudWatch alarm on one metric
+type Alarm struct {
+	Name        string
+	Description string
+	Namespace   string
+	MetricName  string
+	Dimensions  []Dimension
+	// Statistic is a statistic such as Sum or Average, or a percentile such as p99
+	Statistic          string
+	Period             time.Duration
+	EvaluationPeriods  int
+	Threshold          float64
+	ComparisonOperator ComparisonOperator
+	// TreatMissingData is how periods without data are evaluated, e.g. notBreaching
+	TreatMissingData string
+	// AlarmActions are the ARNs of what's notified when the alarm fires, e.g. an SNS topic
+	AlarmActions []string
+}
+
+// AlarmOption configures StandardAlarms
+type AlarmOption func(*alarmConfig)
+
+type alarmConfig struct {
+	serverErrors      float64
+	latency           time.Duration
+	dynamoDBErrors    float64
+	period            time.Duration
+	evaluationPeriods int
+	actions           []string
+}
+
+// WithServerErrorThreshold sets how many server errors in a period fire the server errors alarm; the
+// default is 5
+func WithServerErrorThreshold(count float64) AlarmOption {
+	return func(c *alarmConfig) {
+		c.serverErrors = count
+	}
+}
+
+// WithLatencyThreshold sets the p99 latency in a period that fires the latency alarm; the default is 1s
+func WithLatencyThreshold(p99 time.Duration) AlarmOption {
+	return func(c *alarmConfig) {
+		c.latency = p99
+	}
+}
+
+// WithDynamoDBErrorThreshold sets how many DynamoDB errors in a period fire the DynamoDB errors alarm;
+// the default is 5
+func WithDynamoDBErrorThreshold(count float64) AlarmOption {
+	return func(c *alarmConfig) {
+		c.dynamoDBErrors = count
+	}
+}
+
+// WithAlarmPeriods sets the period over which each alarm's statistic is evaluated and how many
+// periods in a row must breach the threshold; the default is 5 periods of 1m
+func WithAlarmPeriods(period time.Duration, evaluationPeriods int) AlarmOption {
+	return func(c *alarmConfig) {
+		c.period = period
+		c.evaluationPeriods = evaluationPeriods
+	}
+}
+
+// WithAlarmActions sets the ARNs notified when an alarm fires, e.g. an SNS topic
+func WithAlarmActions(arns ...string) AlarmOption {
+	return func(c *alarmConfig) {
+		c.actions = arns
+	}
+}
+
+// StandardAlarms returns the alarms on the standard metrics a Publisher for service in environment
+// puts in namespace: the sum of ServerErrors, the p99 of Latency, and the sum of DynamoDBErrors. Their
+// names are "<service>-<environment>-" followed by "server-errors", "latency", and "dynamodb-errors".
+func StandardAlarms(namespace string, service string, environment string, options ...AlarmOption) []Alarm {
+	config := alarmConfig{
+		serverErrors:      5,
+		latency:           time.Second,
+		dynamoDBErrors:    5,
+		period:            time.Minute,
+		evaluationPeriods: 5,
+	}
+	for _, option := range options {
+		option(&config)
+	}
+
+	dimensions := []Dimension{
+		{Name: DimensionService, Value: service},
+		{Name: DimensionEnvironment, Value: environment},
+	}
+	alarm := func(suffix string, description string, metricName string, statistic string, threshold float64) Alarm {
+		return Alarm{
+			Name:               fmt.Sprintf("%s-%s-%s", service, environment, suffix),
+			Description:        description,
+			Namespace:          namespace,
+			MetricName:         metricName,
+			Dimensions:         dimensions,
+			Statistic:          statistic,
+			Period:             config.period,
+			EvaluationPeriods:  config.evaluationPeriods,
+			Threshold:          threshold,
+			ComparisonOperator: GreaterThanThreshold,
+			TreatMissingData:   "notBreaching",
+			AlarmActions:       config.actions,
+		}
+	}
+
+	return []Alarm{
+		alarm("server-errors", fmt.Sprintf("%s has server errors in %s", service, environment), MetricServerErrors, "Sum", config.serverErrors),
+		alarm("latency", fmt.Sprintf("%s is slow in %s", service, environment), MetricLatency, "p99", float64(config.latency)/float64(time.Millisecond)),
+		alarm("dynamodb-errors", fmt.Sprintf("%s has DynamoDB errors in %s", service, environment), MetricDynamoDBErrors, "Sum", config.dynamoDBErrors),
+	}
+}
+
+// RegisterAlarms creates or updates alarms, e.g. the StandardAlarms, at deployment or startup
+func RegisterAlarms(ctx context.Context, cloudWatch CloudWatch, alarms ...Alarm) error {
+	for _, alarm := range alarms {
+		if err := cloudWatch.PutMetricAlarm(ctx, alarm); err != nil {
+			return kit.WrapError(err, "error putting alarm %s", alarm.Name)
+		}
+		logger.InfoContext(ctx, "registered cloudwatch alarm", "alarm", alarm.Name)
+	}
+	return nil
+}
@@ -0,0 +1,81 @@
+package cloudwatchkit
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStandardAlarms(t *testing.T) {
+	t.Run("returns_the_alarms_on_the_standard_metrics", func(t *testing.T) {
+		alarms := StandardAlarms("theNamespace", "theService", "prod")
+
+		assert.Len(t, alarms, 3)
+		assert.Equal(t, Alarm{
+			Name:               "theService-prod-server-errors",
+			Description:        "theService has server errors in prod",
+			Namespace:          "theNamespace",
+			MetricName:         "ServerErrors",
+			Dimensions:         []Dimension{{Name: "Service", Value: "theService"}, {Name: "Environment", Value: "prod"}},
+			Statistic:          "Sum",
+			Period:             time.Minute,
+			EvaluationPeriods:  5,
+			Threshold:          5,
+			ComparisonOperator: GreaterThanThreshold,
+			TreatMissingData:   "notBreaching",
+		}, alarms[0])
+		assert.Equal(t, "theService-prod-latency", alarms[1].Name)
+		assert.Equal(t, "p99", alarms[1].Statistic)
+		assert.Equal(t, 1000.0, alarms[1].Threshold)
+		assert.Equal(t, "theService-prod-dynamodb-errors", alarms[2].Name)
+		assert.Equal(t, "DynamoDBErrors", alarms[2].MetricName)
+	})
+
+	t.Run("applies_the_options", func(t *testing.T) {
+		alarms := StandardAlarms("theNamespace", "theService", "prod",
+			WithServerErrorThreshold(1),
+			WithLatencyThreshold(250*time.Millisecond),
+			WithDynamoDBErrorThreshold(2),
+			WithAlarmPeriods(5*time.Minute, 3),
+			WithAlarmActions("theTopicARN"))
+
+		assert.Equal(t, []float64{1, 250, 2}, []float64{alarms[0].Threshold, alarms[1].Threshold, alarms[2].Threshold})
+		for _, alarm := range alarms {
+			assert.Equal(t, 5*time.Minute, alarm.Period)
+			assert.Equal(t, 3, alarm.EvaluationPeriods)
+			assert.Equal(t, []string{"theTopicARN"}, alarm.AlarmActions)
+		}
+	})
+}
+
+func TestRegisterAlarms(t *testing.T) {
+	t.Run("puts_each_alarm", func(t *testing.T) {
+		var names []string
+		cloudWatch := &FakeCloudWatch{
+			PutMetricAlarmFake: func(ctx context.Context, alarm Alarm) error {
+				names = append(names, alarm.Name)
+				return nil
+			},
+		}
+
+		err := RegisterAlarms(context.Background(), cloudWatch, StandardAlarms("theNamespace", "theService", "prod")...)
+
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"theService-prod-server-errors", "theService-prod-latency", "theService-prod-dynamodb-errors"}, names)
+	})
+
+	t.Run("returns_the_put_metric_alarm_error", func(t *testing.T) {
+		cloudWatch := &FakeCloudWatch{
+			PutMetricAlarmFake: func(ctx context.Context, alarm Alarm) error {
+				return errors.New("the put error")
+			},
+		}
+
+		err := RegisterAlarms(context.Background(), cloudWatch, Alarm{Name: "theAlarm"})
+
+		assert.EqualError(t, err, "error putting alarm theAlarm: the put error")
+	})
+}
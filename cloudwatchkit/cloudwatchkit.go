@@ -0,0 +1,78 @@
+// Package cloudwatchkit publishes Amazon CloudWatch metrics and defines alarms as code. A Publisher
+// buffers metric data with the service, environment, and optionally version dimensions every metric
+// shares, and sends it in batches with PutMetricData or writes it as embedded metric format (EMF) log
+// lines through logkit. RecordRequest, Middleware, and RecordDynamoDBOperation produce the standard
+// request and DynamoDB metrics, and StandardAlarms defines the alarms on them.
+//
+// The AWS SDK's CloudWatch client isn't a dependency of this module, so publishers use the small
+// CloudWatch interface instead. Adapting *cloudwatch.Client to it takes a few lines: map each Datum to
+// a types.MetricDatum and each Alarm to a PutMetricAlarmInput, using ExtendedStatistic for percentiles.
+package cloudwatchkit
+
+import (
+	"context"
+	"time"
+
+	"github.com/half-ogre/go-kit/logkit"
+)
+
+var logger = logkit.Logger("cloudwatchkit")
+
+// The limits of one PutMetricData request and of one metric
+const (
+	MaxBatchData  = 1000
+	MaxDimensions = 30
+)
+
+// CloudWatch is the part of the CloudWatch API publishers and RegisterAlarms use
+type CloudWatch interface {
+	// PutMetricData sends data to the metrics in namespace
+	PutMetricData(ctx context.Context, namespace string, data []Datum) error
+	// PutMetricAlarm creates an alarm, or updates the alarm with the same name
+	PutMetricAlarm(ctx context.Context, alarm Alarm) error
+}
+
+// Unit is the unit of a metric's values
+type Unit string
+
+const (
+	UnitNone         Unit = "None"
+	UnitCount        Unit = "Count"
+	UnitPercent      Unit = "Percent"
+	UnitBytes        Unit = "Bytes"
+	UnitSeconds      Unit = "Seconds"
+	UnitMilliseconds Unit = "Milliseconds"
+)
+
+// Dimension is a name and value that, with the metric name, identifies a metric
+type Dimension struct {
+	Name  string
+	Value string
+}
+
+// Datum is one value of a metric
+type Datum struct {
+	MetricName string
+	Dimensions []Dimension
+	Value      float64
+	Unit       Unit
+	Timestamp  time.Time
+}
+
+// The names of the standard dimensions every metric from a Publisher has
+const (
+	DimensionService     = "Service"
+	DimensionEnvironment = "Environment"
+	DimensionVersion     = "Version"
+)
+
+// The names of the standard metrics RecordRequest and RecordDynamoDBOperation put
+const (
+	MetricRequests                 = "Requests"
+	MetricClientErrors             = "ClientErrors"
+	MetricServerErrors             = "ServerErrors"
+	MetricLatency                  = "Latency"
+	MetricDynamoDBLatency          = "DynamoDBLatency"
+	MetricDynamoDBErrors           = "DynamoDBErrors"
+	MetricDynamoDBConsumedCapacity = "DynamoDBConsumedCapacity"
+)
@@ -0,0 +1,27 @@
+package cloudwatchkit
+
+import (
+	"context"
+)
+
+// FakeCloudWatch is a CloudWatch for tests
+type FakeCloudWatch struct {
+	PutMetricDataFake  func(ctx context.Context, namespace string, data []Datum) error
+	PutMetricAlarmFake func(ctx context.Context, alarm Alarm) error
+}
+
+func (f *FakeCloudWatch) PutMetricData(ctx context.Context, namespace string, data []Datum) error {
+	if f.PutMetricDataFake != nil {
+		return f.PutMetricDataFake(ctx, namespace, data)
+	} else {
+		panic("PutMetricData fake not implemented")
+	}
+}
+
+func (f *FakeCloudWatch) PutMetricAlarm(ctx context.Context, alarm Alarm) error {
+	if f.PutMetricAlarmFake != nil {
+		return f.PutMetricAlarmFake(ctx, alarm)
+	} else {
+		panic("PutMetricAlarm fake not implemented")
+	}
+}
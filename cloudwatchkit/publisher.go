@@ -0,0 +1,281 @@
+package cloudwatchkit
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/half-ogre/go-kit/envkit"
+	"github.com/half-ogre/go-kit/kit"
+	"github.com/half-ogre/go-kit/versionkit"
+)
+
+// PublisherOption configures NewPublisher
+type PublisherOption func(*Publisher)
+
+// WithEnvironment sets the Environment dimension; the default is the ENVIRONMENT variable, or "dev"
+func WithEnvironment(environment string) PublisherOption {
+	return func(p *Publisher) {
+		p.environment = environment
+	}
+}
+
+// WithVersionDimension also puts every metric with a Version dimension, versionkit's build version, so
+// a deployment can be compared with the one before it. The metrics are still put without it too, since
+// alarms and dashboards match a metric's dimensions exactly.
+func WithVersionDimension() PublisherOption {
+	return func(p *Publisher) {
+		p.version = versionkit.GetBuildInfo().GetBuildVersion()
+	}
+}
+
+// WithEMF writes metrics as embedded metric format log lines through logkit instead of sending them
+// with PutMetricData, e.g. in Lambda functions, where CloudWatch Logs extracts the metrics without
+// requests from the function. The default logger must write JSON for CloudWatch to read the lines.
+func WithEMF() PublisherOption {
+	return func(p *Publisher) {
+		p.emf = true
+	}
+}
+
+// WithMaxBatchData sets how many data a batch holds before it's sent; the default and most is
+// MaxBatchData
+func WithMaxBatchData(maxData int) PublisherOption {
+	return func(p *Publisher) {
+		p.maxData = min(max(maxData, 1), MaxBatchData)
+	}
+}
+
+// WithFlushInterval sets how often buffered data is sent even if the batch isn't full; the default is
+// 10s
+func WithFlushInterval(interval time.Duration) PublisherOption {
+	return func(p *Publisher) {
+		p.flushInterval = interval
+	}
+}
+
+// WithErrorHandler sets what's done with errors sending batches that nobody waits for, i.e. those sent
+// when full or at the flush interval; the default logs them
+func WithErrorHandler(handler func(err error)) PublisherOption {
+	return func(p *Publisher) {
+		p.errorHandler = handler
+	}
+}
+
+// Publisher buffers metric data for a service and sends it in batches, when a batch is full or at the
+// flush interval. It's safe for concurrent use; call Close at shutdown to send what's buffered.
+type Publisher struct {
+	cloudWatch    CloudWatch
+	namespace     string
+	service       string
+	environment   string
+	version       string
+	emf           bool
+	maxData       int
+	flushInterval time.Duration
+	errorHandler  func(err error)
+
+	mu     sync.Mutex
+	buffer []Datum
+	closed bool
+	sendMu sync.Mutex
+
+	full    chan struct{}
+	closing chan struct{}
+	stopped chan struct{}
+}
+
+// NewPublisher returns a publisher putting service's metrics in namespace, e.g. "MyCompany/Orders",
+// with cloudWatch, which may be nil with WithEMF
+func NewPublisher(cloudWatch CloudWatch, namespace string, service string, options ...PublisherOption) *Publisher {
+	p := &Publisher{
+		cloudWatch:    cloudWatch,
+		namespace:     namespace,
+		service:       service,
+		environment:   envkit.GetenvWithDefault("ENVIRONMENT", "dev"),
+		maxData:       MaxBatchData,
+		flushInterval: 10 * time.Second,
+		full:          make(chan struct{}, 1),
+		closing:       make(chan struct{}),
+		stopped:       make(chan struct{}),
+	}
+	for _, option := range options {
+		option(p)
+	}
+	if p.errorHandler == nil {
+		p.errorHandler = func(err error) {
+			logger.Error("error putting cloudwatch metrics", "namespace", namespace, "error", err)
+		}
+	}
+
+	go p.run()
+	return p
+}
+
+// Dimensions returns the Service and Environment dimensions every metric from the publisher has,
+// e.g. for alarms and dashboards on them
+func (p *Publisher) Dimensions() []Dimension {
+	return []Dimension{
+		{Name: DimensionService, Value: p.service},
+		{Name: DimensionEnvironment, Value: p.environment},
+	}
+}
+
+// Put buffers a value of the metric named name with the publisher's dimensions and dimensions. Values
+// put after Close are dropped.
+func (p *Publisher) Put(name string, value float64, unit Unit, dimensions ...Dimension) {
+	datum := Datum{
+		MetricName: name,
+		Dimensions: append(p.Dimensions(), dimensions...),
+		Value:      value,
+		Unit:       unit,
+		Timestamp:  time.Now(),
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.closed {
+		return
+	}
+	p.buffer = append(p.buffer, datum)
+	if len(p.buffer) >= p.maxData {
+		select {
+		case p.full <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// PutDuration buffers d as a value in milliseconds of the metric named name, as Put does
+func (p *Publisher) PutDuration(name string, d time.Duration, dimensions ...Dimension) {
+	p.Put(name, float64(d)/float64(time.Millisecond), UnitMilliseconds, dimensions...)
+}
+
+// Flush sends the buffered data and returns the error sending it
+func (p *Publisher) Flush(ctx context.Context) error {
+	return p.flush(ctx)
+}
+
+// Close stops buffering data, sends the buffered data, and stops the publisher. It returns the error
+// sending the buffered data.
+func (p *Publisher) Close(ctx context.Context) error {
+	p.mu.Lock()
+	if p.closed {
+		p.mu.Unlock()
+		return nil
+	}
+	p.closed = true
+	p.mu.Unlock()
+
+	close(p.closing)
+	<-p.stopped
+	return p.flush(ctx)
+}
+
+// run sends the buffer when it's full and at each flush interval, until the publisher closes
+func (p *Publisher) run() {
+	defer close(p.stopped)
+
+	ticker := time.NewTicker(p.flushInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-p.closing:
+			return
+		case <-p.full:
+		case <-ticker.C:
+		}
+		if err := p.flush(context.Background()); err != nil {
+			p.errorHandler(err)
+		}
+	}
+}
+
+// flush sends the buffered data in batches, one flush at a time so batches are sent in order
+func (p *Publisher) flush(ctx context.Context) error {
+	p.sendMu.Lock()
+	defer p.sendMu.Unlock()
+
+	p.mu.Lock()
+	data := p.buffer
+	p.buffer = nil
+	p.mu.Unlock()
+
+	if p.emf {
+		for _, datum := range data {
+			p.writeEMF(ctx, datum)
+		}
+		return nil
+	}
+
+	data = p.withVersions(data)
+	for start := 0; start < len(data); start += p.maxData {
+		end := min(start+p.maxData, len(data))
+		if err := p.cloudWatch.PutMetricData(ctx, p.namespace, data[start:end]); err != nil {
+			return kit.WrapError(err, "error putting %d metric data to %s", end-start, p.namespace)
+		}
+	}
+	return nil
+}
+
+// withVersions returns data followed by a copy of each datum with the Version dimension, if the
+// publisher has one
+func (p *Publisher) withVersions(data []Datum) []Datum {
+	if p.version == "" {
+		return data
+	}
+
+	versioned := make([]Datum, 0, 2*len(data))
+	versioned = append(versioned, data...)
+	for _, datum := range data {
+		datum.Dimensions = append(datum.Dimensions[:len(datum.Dimensions):len(datum.Dimensions)], Dimension{Name: DimensionVersion, Value: p.version})
+		versioned = append(versioned, datum)
+	}
+	return versioned
+}
+
+// emfMetadata is the _aws member of an embedded metric format log line
+type emfMetadata struct {
+	Timestamp         int64          `json:"Timestamp"`
+	CloudWatchMetrics []emfDirective `json:"CloudWatchMetrics"`
+}
+
+type emfDirective struct {
+	Namespace  string      `json:"Namespace"`
+	Dimensions [][]string  `json:"Dimensions"`
+	Metrics    []emfMetric `json:"Metrics"`
+}
+
+type emfMetric struct {
+	Name string `json:"Name"`
+	Unit Unit   `json:"Unit,omitempty"`
+}
+
+// writeEMF logs datum as an embedded metric format line; with a version its dimensions are declared
+// both with and without Version, as withVersions puts them
+func (p *Publisher) writeEMF(ctx context.Context, datum Datum) {
+	names := make([]string, 0, len(datum.Dimensions))
+	attrs := make([]slog.Attr, 0, len(datum.Dimensions)+3)
+	for _, dimension := range datum.Dimensions {
+		names = append(names, dimension.Name)
+		attrs = append(attrs, slog.String(dimension.Name, dimension.Value))
+	}
+	dimensionSets := [][]string{names}
+	if p.version != "" {
+		dimensionSets = append(dimensionSets, append(names[:len(names):len(names)], DimensionVersion))
+		attrs = append(attrs, slog.String(DimensionVersion, p.version))
+	}
+
+	metadata := emfMetadata{
+		Timestamp: datum.Timestamp.UnixMilli(),
+		CloudWatchMetrics: []emfDirective{{
+			Namespace:  p.namespace,
+			Dimensions: dimensionSets,
+			Metrics:    []emfMetric{{Name: datum.MetricName, Unit: datum.Unit}},
+		}},
+	}
+	attrs = append(attrs, slog.Any("_aws", metadata), slog.Float64(datum.MetricName, datum.Value))
+	logger.LogAttrs(ctx, slog.LevelInfo, "metric", attrs...)
+}
@@ -0,0 +1,159 @@
+package cloudwatchkit
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// recordingCloudWatch returns a fake recording the batches it's sent
+func recordingCloudWatch(batches *[][]Datum) *FakeCloudWatch {
+	var mu sync.Mutex
+	return &FakeCloudWatch{
+		PutMetricDataFake: func(ctx context.Context, namespace string, data []Datum) error {
+			mu.Lock()
+			defer mu.Unlock()
+			*batches = append(*batches, data)
+			return nil
+		},
+	}
+}
+
+func TestPublisher(t *testing.T) {
+	t.Run("puts_data_with_the_service_and_environment_dimensions", func(t *testing.T) {
+		var batches [][]Datum
+		var actualNamespace string
+		publisher := NewPublisher(&FakeCloudWatch{
+			PutMetricDataFake: func(ctx context.Context, namespace string, data []Datum) error {
+				actualNamespace = namespace
+				batches = append(batches, data)
+				return nil
+			},
+		}, "theNamespace", "theService", WithEnvironment("theEnvironment"), WithFlushInterval(time.Hour))
+
+		publisher.Put("theMetric", 2, UnitCount, Dimension{Name: "theDimension", Value: "theValue"})
+		err := publisher.Close(context.Background())
+
+		assert.NoError(t, err)
+		assert.Equal(t, "theNamespace", actualNamespace)
+		assert.Len(t, batches, 1)
+		datum := batches[0][0]
+		assert.Equal(t, "theMetric", datum.MetricName)
+		assert.Equal(t, 2.0, datum.Value)
+		assert.Equal(t, UnitCount, datum.Unit)
+		assert.Equal(t, []Dimension{{Name: "Service", Value: "theService"}, {Name: "Environment", Value: "theEnvironment"}, {Name: "theDimension", Value: "theValue"}}, datum.Dimensions)
+	})
+
+	t.Run("uses_the_environment_variable_by_default", func(t *testing.T) {
+		t.Setenv("ENVIRONMENT", "prod")
+
+		publisher := NewPublisher(&FakeCloudWatch{}, "theNamespace", "theService")
+		t.Cleanup(func() { _ = publisher.Close(context.Background()) })
+
+		assert.Equal(t, []Dimension{{Name: "Service", Value: "theService"}, {Name: "Environment", Value: "prod"}}, publisher.Dimensions())
+	})
+
+	t.Run("puts_data_again_with_the_version_dimension", func(t *testing.T) {
+		var batches [][]Datum
+		publisher := NewPublisher(recordingCloudWatch(&batches), "theNamespace", "theService", WithEnvironment("theEnvironment"), WithVersionDimension(), WithFlushInterval(time.Hour))
+
+		publisher.Put("theMetric", 1, UnitCount)
+		err := publisher.Close(context.Background())
+
+		assert.NoError(t, err)
+		assert.Len(t, batches[0], 2)
+		assert.Len(t, batches[0][0].Dimensions, 2)
+		assert.Equal(t, Dimension{Name: "Version", Value: "dev"}, batches[0][1].Dimensions[2])
+	})
+
+	t.Run("sends_a_full_batch", func(t *testing.T) {
+		var batches [][]Datum
+		sent := make(chan struct{})
+		publisher := NewPublisher(&FakeCloudWatch{
+			PutMetricDataFake: func(ctx context.Context, namespace string, data []Datum) error {
+				batches = append(batches, data)
+				close(sent)
+				return nil
+			},
+		}, "theNamespace", "theService", WithMaxBatchData(2), WithFlushInterval(time.Hour))
+		t.Cleanup(func() { _ = publisher.Close(context.Background()) })
+
+		publisher.Put("theMetric", 1, UnitCount)
+		publisher.Put("theMetric", 2, UnitCount)
+		<-sent
+
+		assert.Len(t, batches, 1)
+		assert.Len(t, batches[0], 2)
+	})
+
+	t.Run("sends_the_buffer_at_the_flush_interval", func(t *testing.T) {
+		sent := make(chan []Datum, 1)
+		publisher := NewPublisher(&FakeCloudWatch{
+			PutMetricDataFake: func(ctx context.Context, namespace string, data []Datum) error {
+				sent <- data
+				return nil
+			},
+		}, "theNamespace", "theService", WithFlushInterval(10*time.Millisecond))
+		t.Cleanup(func() { _ = publisher.Close(context.Background()) })
+
+		publisher.PutDuration("theLatency", 1500*time.Microsecond)
+
+		data := <-sent
+		assert.Equal(t, 1.5, data[0].Value)
+		assert.Equal(t, UnitMilliseconds, data[0].Unit)
+	})
+
+	t.Run("returns_the_put_metric_data_error_from_flush", func(t *testing.T) {
+		publisher := NewPublisher(&FakeCloudWatch{
+			PutMetricDataFake: func(ctx context.Context, namespace string, data []Datum) error {
+				return errors.New("the put error")
+			},
+		}, "theNamespace", "theService", WithFlushInterval(time.Hour))
+		t.Cleanup(func() { _ = publisher.Close(context.Background()) })
+
+		publisher.Put("theMetric", 1, UnitCount)
+		err := publisher.Flush(context.Background())
+
+		assert.EqualError(t, err, "error putting 1 metric data to theNamespace: the put error")
+	})
+
+	t.Run("drops_data_put_after_close", func(t *testing.T) {
+		publisher := NewPublisher(&FakeCloudWatch{}, "theNamespace", "theService")
+		_ = publisher.Close(context.Background())
+
+		publisher.Put("theMetric", 1, UnitCount)
+		err := publisher.Flush(context.Background())
+
+		assert.NoError(t, err)
+	})
+
+	t.Run("writes_embedded_metric_format_log_lines", func(t *testing.T) {
+		var output bytes.Buffer
+		defaultLogger := slog.Default()
+		slog.SetDefault(slog.New(slog.NewJSONHandler(&output, nil)))
+		t.Cleanup(func() { slog.SetDefault(defaultLogger) })
+		publisher := NewPublisher(nil, "theNamespace", "theService", WithEnvironment("theEnvironment"), WithVersionDimension(), WithEMF(), WithFlushInterval(time.Hour))
+
+		publisher.Put("theMetric", 3, UnitCount)
+		err := publisher.Close(context.Background())
+
+		assert.NoError(t, err)
+		var line map[string]any
+		assert.NoError(t, json.Unmarshal(output.Bytes(), &line))
+		assert.Equal(t, 3.0, line["theMetric"])
+		assert.Equal(t, "theService", line["Service"])
+		assert.Equal(t, "theEnvironment", line["Environment"])
+		assert.Equal(t, "dev", line["Version"])
+		directive := line["_aws"].(map[string]any)["CloudWatchMetrics"].([]any)[0].(map[string]any)
+		assert.Equal(t, "theNamespace", directive["Namespace"])
+		assert.Equal(t, []any{[]any{"Service", "Environment"}, []any{"Service", "Environment", "Version"}}, directive["Dimensions"])
+		assert.Equal(t, []any{map[string]any{"Name": "theMetric", "Unit": "Count"}}, directive["Metrics"])
+	})
+}
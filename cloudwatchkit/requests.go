@@ -0,0 +1,65 @@
+package cloudwatchkit
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/half-ogre/go-kit/dynamodbkit"
+)
+
+// RecordRequest puts the standard request metrics for a request that got a response with status
+// after latency: Requests, ClientErrors and ServerErrors, which are 0 or 1, and Latency
+func (p *Publisher) RecordRequest(status int, latency time.Duration) {
+	p.Put(MetricRequests, 1, UnitCount)
+	p.Put(MetricClientErrors, boolValue(status >= 400 && status < 500), UnitCount)
+	p.Put(MetricServerErrors, boolValue(status >= 500), UnitCount)
+	p.PutDuration(MetricLatency, latency)
+}
+
+// Middleware returns net/http middleware that records every request with RecordRequest, e.g. for
+// echo.WrapMiddleware
+func (p *Publisher) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		recorder := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+		next.ServeHTTP(recorder, r)
+		p.RecordRequest(recorder.status, time.Since(start))
+	})
+}
+
+// RecordDynamoDBOperation puts the standard DynamoDB metrics for an operation, with Table and
+// Operation dimensions: DynamoDBLatency, DynamoDBErrors, which is 0 or 1, and
+// DynamoDBConsumedCapacity. DynamoDBErrors is also put without them, for the alarm on all of the
+// service's operations. It's a dynamodbkit instrumentation function, e.g. for
+// dynamodbkit.UseInstrumentation(publisher.RecordDynamoDBOperation).
+func (p *Publisher) RecordDynamoDBOperation(ctx context.Context, metrics dynamodbkit.OperationMetrics) {
+	dimensions := []Dimension{{Name: "Table", Value: metrics.Table}, {Name: "Operation", Value: metrics.Operation}}
+	p.PutDuration(MetricDynamoDBLatency, metrics.Duration, dimensions...)
+	p.Put(MetricDynamoDBErrors, boolValue(metrics.Err != nil), UnitCount, dimensions...)
+	p.Put(MetricDynamoDBErrors, boolValue(metrics.Err != nil), UnitCount)
+	p.Put(MetricDynamoDBConsumedCapacity, metrics.ConsumedCapacity, UnitNone, dimensions...)
+}
+
+// statusRecorder records the status a handler writes
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (r *statusRecorder) WriteHeader(status int) {
+	r.status = status
+	r.ResponseWriter.WriteHeader(status)
+}
+
+// Unwrap lets http.ResponseController reach the wrapped writer, e.g. to flush
+func (r *statusRecorder) Unwrap() http.ResponseWriter {
+	return r.ResponseWriter
+}
+
+func boolValue(b bool) float64 {
+	if b {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,103 @@
+package cloudwatchkit
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/half-ogre/go-kit/dynamodbkit"
+	"github.com/stretchr/testify/assert"
+)
+
+// values returns each datum's metric name and value, ignoring the latency metrics
+func values(batches [][]Datum) map[string][]float64 {
+	values := map[string][]float64{}
+	for _, batch := range batches {
+		for _, datum := range batch {
+			if datum.Unit != UnitMilliseconds {
+				values[datum.MetricName] = append(values[datum.MetricName], datum.Value)
+			}
+		}
+	}
+	return values
+}
+
+func TestRecordRequest(t *testing.T) {
+	t.Run("puts_the_request_metrics", func(t *testing.T) {
+		var batches [][]Datum
+		publisher := NewPublisher(recordingCloudWatch(&batches), "theNamespace", "theService", WithFlushInterval(time.Hour))
+
+		publisher.RecordRequest(http.StatusServiceUnavailable, 20*time.Millisecond)
+		publisher.RecordRequest(http.StatusNotFound, 10*time.Millisecond)
+		err := publisher.Close(context.Background())
+
+		assert.NoError(t, err)
+		assert.Equal(t, map[string][]float64{
+			"Requests":     {1, 1},
+			"ClientErrors": {0, 1},
+			"ServerErrors": {1, 0},
+		}, values(batches))
+		assert.Equal(t, Datum{MetricName: "Latency", Dimensions: publisher.Dimensions(), Value: 20, Unit: UnitMilliseconds, Timestamp: batches[0][3].Timestamp}, batches[0][3])
+	})
+}
+
+func TestMiddleware(t *testing.T) {
+	t.Run("records_the_status_the_handler_writes", func(t *testing.T) {
+		var batches [][]Datum
+		publisher := NewPublisher(recordingCloudWatch(&batches), "theNamespace", "theService", WithFlushInterval(time.Hour))
+		handler := publisher.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusInternalServerError)
+		}))
+
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+		err := publisher.Close(context.Background())
+
+		assert.NoError(t, err)
+		assert.Equal(t, []float64{1}, values(batches)["ServerErrors"])
+	})
+
+	t.Run("records_ok_when_the_handler_does_not_write_a_status", func(t *testing.T) {
+		var batches [][]Datum
+		publisher := NewPublisher(recordingCloudWatch(&batches), "theNamespace", "theService", WithFlushInterval(time.Hour))
+		handler := publisher.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_, _ = w.Write([]byte("theBody"))
+		}))
+
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+		err := publisher.Close(context.Background())
+
+		assert.NoError(t, err)
+		assert.Equal(t, []float64{0}, values(batches)["ServerErrors"])
+		assert.Equal(t, []float64{0}, values(batches)["ClientErrors"])
+	})
+}
+
+func TestRecordDynamoDBOperation(t *testing.T) {
+	t.Run("puts_the_dynamodb_metrics_by_table_and_operation", func(t *testing.T) {
+		var batches [][]Datum
+		publisher := NewPublisher(recordingCloudWatch(&batches), "theNamespace", "theService", WithFlushInterval(time.Hour))
+
+		publisher.RecordDynamoDBOperation(context.Background(), dynamodbkit.OperationMetrics{
+			Operation:        "GetItem",
+			Table:            "theTable",
+			Duration:         5 * time.Millisecond,
+			ConsumedCapacity: 0.5,
+			Err:              errors.New("the error"),
+		})
+		err := publisher.Close(context.Background())
+
+		assert.NoError(t, err)
+		assert.Equal(t, map[string][]float64{
+			"DynamoDBErrors":           {1, 1},
+			"DynamoDBConsumedCapacity": {0.5},
+		}, values(batches))
+		latency := batches[0][0]
+		assert.Equal(t, "DynamoDBLatency", latency.MetricName)
+		assert.Equal(t, 5.0, latency.Value)
+		assert.Equal(t, []Dimension{{Name: "Table", Value: "theTable"}, {Name: "Operation", Value: "GetItem"}}, latency.Dimensions[2:])
+		assert.Len(t, batches[0][2].Dimensions, 2)
+	})
+}
@@ -0,0 +1,43 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+
+	"github.com/spf13/cobra"
+
+	"github.com/half-ogre/go-kit/envkit"
+)
+
+var envFile string
+
+var rootCmd = &cobra.Command{
+	Use:   "dotenv [-f file] -- command [args...]",
+	Short: "Run a command with variables from a .env file",
+	Long: `Loads a .env file and runs a command with the merged environment.
+Variables already set in the environment take precedence over the file.`,
+	Args:          cobra.MinimumNArgs(1),
+	SilenceUsage:  true,
+	SilenceErrors: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return envkit.RunWithEnvContext(cmd.Context(), envFile, args)
+	},
+}
+
+func init() {
+	rootCmd.Flags().StringVarP(&envFile, "file", "f", ".env", "Path to the .env file")
+}
+
+func main() {
+	if err := rootCmd.Execute(); err != nil {
+		// Pass the child's exit code through so dotenv is transparent in scripts
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			os.Exit(exitErr.ExitCode())
+		}
+		fmt.Fprintln(os.Stderr, "Error:", err)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,27 @@
+package main
+
+import (
+	"os"
+
+	"github.com/half-ogre/go-kit/cmd/gokit/subcmd"
+	"github.com/half-ogre/go-kit/versionkit"
+)
+
+// These variables are set via -ldflags at build time
+var (
+	version   = ""
+	gitCommit = ""
+	buildDate = ""
+)
+
+func main() {
+	subcmd.SetBuildInfo(&versionkit.BuildInfo{
+		Version:   version,
+		GitCommit: gitCommit,
+		BuildDate: buildDate,
+	})
+
+	if err := subcmd.Execute(); err != nil {
+		os.Exit(1)
+	}
+}
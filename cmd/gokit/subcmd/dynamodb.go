@@ -0,0 +1,281 @@
+package subcmd
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"iter"
+	"os"
+	"sort"
+
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/spf13/cobra"
+
+	"github.com/half-ogre/go-kit/dynamodbkit"
+	"github.com/half-ogre/go-kit/filekit"
+)
+
+// importBatchSize is how many items import puts in each BatchPutItems call
+const importBatchSize = 500
+
+var (
+	dynamoDBFile    string
+	dynamoDBSuffix  string
+	dynamoDBMaxRead int
+)
+
+var dynamoDBCmd = &cobra.Command{
+	Use:   "dynamodb",
+	Short: "DynamoDB export, import, and seeding",
+	Long: `Export, import, and seed DynamoDB tables as newline-delimited JSON, one item per line. The AWS
+config is loaded the usual way, so AWS_ENDPOINT_URL_DYNAMODB points the commands at DynamoDB Local.
+Numbers keep their full precision; sets are written as JSON arrays and binary values as base64
+strings, so they import as lists and strings.`,
+	PersistentPreRun: func(cmd *cobra.Command, args []string) {
+		if dynamoDBSuffix != "" {
+			dynamodbkit.UseTableNameSuffix(dynamoDBSuffix)
+		}
+	},
+}
+
+var dynamoDBExportCmd = &cobra.Command{
+	Use:   "export <table>",
+	Short: "Write every item in a table as newline-delimited JSON",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return withOutputFile(dynamoDBFile, func(w io.Writer) error {
+			var options []dynamodbkit.ScanAllOption
+			if dynamoDBMaxRead > 0 {
+				options = append(options, dynamodbkit.WithMaxItems(dynamoDBMaxRead))
+			}
+			count, err := exportItems(w, dynamodbkit.ScanItems[exportedItem](cmd.Context(), args[0], options...))
+			if err != nil || dynamoDBFile == "-" {
+				return err
+			}
+			return writeCount("Exported", count, args[0])
+		})
+	},
+}
+
+var dynamoDBImportCmd = &cobra.Command{
+	Use:   "import <table>",
+	Short: "Put the items from newline-delimited JSON into a table",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return withInputFile(dynamoDBFile, func(r io.Reader) error {
+			count, err := importItems(cmd.Context(), r, batchPutter(args[0]))
+			if err != nil {
+				return err
+			}
+			return writeCount("Imported", count, args[0])
+		})
+	},
+}
+
+var dynamoDBSeedCmd = &cobra.Command{
+	Use:   "seed <file>",
+	Short: "Put fixture items into one or more tables",
+	Long: `Puts the items in a JSON file of fixtures, an object of table names to arrays of items, e.g.
+{"users": [{"id": "1", "name": "Ada"}]}, into their tables.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return withInputFile(args[0], func(r io.Reader) error {
+			return seedTables(cmd.Context(), r, batchPutter)
+		})
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(dynamoDBCmd)
+	dynamoDBCmd.AddCommand(dynamoDBExportCmd, dynamoDBImportCmd, dynamoDBSeedCmd)
+
+	dynamoDBCmd.PersistentFlags().StringVar(&dynamoDBSuffix, "table-suffix", "", "Suffix appended to table names, as with dynamodbkit.UseTableNameSuffix")
+	dynamoDBExportCmd.Flags().StringVarP(&dynamoDBFile, "file", "f", "-", "File to write to, or - for stdout")
+	dynamoDBExportCmd.Flags().IntVar(&dynamoDBMaxRead, "max-items", 0, "Stop after this many items")
+	dynamoDBImportCmd.Flags().StringVarP(&dynamoDBFile, "file", "f", "-", "File to read from, or - for stdin")
+}
+
+// exportedItem unmarshals an item keeping its numbers as json.Number, so large ones aren't rounded
+// to float64
+type exportedItem map[string]any
+
+func (item *exportedItem) UnmarshalDynamoDBAttributeValue(av types.AttributeValue) error {
+	decoder := attributevalue.NewDecoder(func(o *attributevalue.DecoderOptions) {
+		o.UseNumber = true
+	})
+
+	var m map[string]any
+	if err := decoder.Decode(av, &m); err != nil {
+		return err
+	}
+	*item = convertNumbers(m, func(n attributevalue.Number) any { return json.Number(n) }).(map[string]any)
+	return nil
+}
+
+// convertNumbers replaces the numbers of one library's type in v with another's
+func convertNumbers[N ~string](v any, convert func(N) any) any {
+	switch value := v.(type) {
+	case N:
+		return convert(value)
+	case []N:
+		converted := make([]any, len(value))
+		for i, n := range value {
+			converted[i] = convert(n)
+		}
+		return converted
+	case map[string]any:
+		for k, elem := range value {
+			value[k] = convertNumbers(elem, convert)
+		}
+	case []any:
+		for i, elem := range value {
+			value[i] = convertNumbers(elem, convert)
+		}
+	}
+	return v
+}
+
+// exportItems writes each item as a line of JSON, returning how many were written
+func exportItems(w io.Writer, items iter.Seq2[exportedItem, error]) (int, error) {
+	encoder := json.NewEncoder(w)
+	count := 0
+	for item, err := range items {
+		if err != nil {
+			return count, fmt.Errorf("failed to read items after %d: %w", count, err)
+		}
+		if err := encoder.Encode(item); err != nil {
+			return count, fmt.Errorf("failed to write item %d: %w", count, err)
+		}
+		count++
+	}
+	return count, nil
+}
+
+// putFunc puts a batch of items into a table
+type putFunc func(ctx context.Context, items []map[string]any) error
+
+func batchPutter(tableName string) putFunc {
+	return func(ctx context.Context, items []map[string]any) error {
+		return dynamodbkit.BatchPutItems(ctx, tableName, items)
+	}
+}
+
+// importItems puts the items read from r, one JSON object per line, in batches, returning how many
+// were put
+func importItems(ctx context.Context, r io.Reader, put putFunc) (int, error) {
+	decoder := json.NewDecoder(bufio.NewReader(r))
+	decoder.UseNumber()
+
+	count := 0
+	batch := make([]map[string]any, 0, importBatchSize)
+	flush := func() error {
+		if len(batch) == 0 {
+			return nil
+		}
+		if err := put(ctx, batch); err != nil {
+			return fmt.Errorf("failed to put items %d to %d: %w", count, count+len(batch)-1, err)
+		}
+		count += len(batch)
+		batch = batch[:0]
+		return nil
+	}
+
+	for {
+		var item map[string]any
+		err := decoder.Decode(&item)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return count, fmt.Errorf("failed to read item %d: %w", count+len(batch), err)
+		}
+
+		batch = append(batch, toAttributeValueNumbers(item))
+		if len(batch) == importBatchSize {
+			if err := flush(); err != nil {
+				return count, err
+			}
+		}
+	}
+
+	return count, flush()
+}
+
+// toAttributeValueNumbers replaces json.Numbers with attributevalue.Numbers, which marshal as DynamoDB
+// numbers rather than strings
+func toAttributeValueNumbers(item map[string]any) map[string]any {
+	return convertNumbers(item, func(n json.Number) any { return attributevalue.Number(n) }).(map[string]any)
+}
+
+// seedTables puts the fixtures read from r into their tables, in table name order
+func seedTables(ctx context.Context, r io.Reader, putter func(tableName string) putFunc) error {
+	decoder := json.NewDecoder(r)
+	decoder.UseNumber()
+
+	var fixtures map[string][]map[string]any
+	if err := decoder.Decode(&fixtures); err != nil {
+		return fmt.Errorf("failed to read fixtures: %w", err)
+	}
+
+	tableNames := make([]string, 0, len(fixtures))
+	for tableName := range fixtures {
+		tableNames = append(tableNames, tableName)
+	}
+	sort.Strings(tableNames)
+
+	for _, tableName := range tableNames {
+		items := make([]map[string]any, 0, len(fixtures[tableName]))
+		for _, item := range fixtures[tableName] {
+			items = append(items, toAttributeValueNumbers(item))
+		}
+		if err := putter(tableName)(ctx, items); err != nil {
+			return fmt.Errorf("failed to seed %s: %w", tableName, err)
+		}
+		if err := writeCount("Seeded", len(items), tableName); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// countOutput is the JSON output of export, import, and seed
+type countOutput struct {
+	Table string `json:"table"`
+	Items int    `json:"items"`
+}
+
+// writeCount reports how many items a command handled, unless export wrote the items to stdout
+func writeCount(action string, count int, tableName string) error {
+	if isStructured(outputFlags.Output) {
+		return writeValue(outputFlags.Output, countOutput{Table: tableName, Items: count})
+	}
+	_, err := fmt.Fprintf(stdout, "%s %d items for %s\n", action, count, tableName)
+	return err
+}
+
+// withOutputFile calls fn with stdout when path is "-", or else with a file it renames to path once fn
+// succeeds, so a failed export never leaves a partial file behind
+func withOutputFile(path string, fn func(io.Writer) error) error {
+	if path == "-" {
+		return fn(stdout)
+	}
+
+	return filekit.WriteAtomic(path, 0o644, fn)
+}
+
+func withInputFile(path string, fn func(io.Reader) error) error {
+	if path == "-" {
+		return fn(os.Stdin)
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("failed to open %s: %w", path, err)
+	}
+	defer file.Close()
+	return fn(file)
+}
@@ -0,0 +1,148 @@
+package subcmd
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"iter"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func exportedItemsOf(t *testing.T, avs ...map[string]types.AttributeValue) iter.Seq2[exportedItem, error] {
+	return func(yield func(exportedItem, error) bool) {
+		for _, av := range avs {
+			var item exportedItem
+			assert.NoError(t, attributevalue.UnmarshalMap(av, &item))
+			if !yield(item, nil) {
+				return
+			}
+		}
+	}
+}
+
+func TestExportItems(t *testing.T) {
+	t.Run("writes_each_item_as_a_json_line_keeping_number_precision", func(t *testing.T) {
+		var buf bytes.Buffer
+		items := exportedItemsOf(t, map[string]types.AttributeValue{
+			"id":     &types.AttributeValueMemberS{Value: "theID"},
+			"count":  &types.AttributeValueMemberN{Value: "9007199254740993"},
+			"scores": &types.AttributeValueMemberNS{Value: []string{"1.5"}},
+			"nested": &types.AttributeValueMemberM{Value: map[string]types.AttributeValue{"n": &types.AttributeValueMemberN{Value: "2"}}},
+		})
+
+		count, err := exportItems(&buf, items)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 1, count)
+		assert.Equal(t, `{"count":9007199254740993,"id":"theID","nested":{"n":2},"scores":[1.5]}`+"\n", buf.String())
+	})
+
+	t.Run("returns_the_error_from_reading_items", func(t *testing.T) {
+		items := func(yield func(exportedItem, error) bool) {
+			if !yield(exportedItem{"id": "theID"}, nil) {
+				return
+			}
+			yield(nil, errors.New("the scan error"))
+		}
+
+		count, err := exportItems(&bytes.Buffer{}, items)
+
+		assert.Equal(t, 1, count)
+		assert.EqualError(t, err, "failed to read items after 1: the scan error")
+	})
+}
+
+func TestImportItems(t *testing.T) {
+	t.Run("puts_the_items_with_numbers_as_dynamodb_numbers", func(t *testing.T) {
+		var actualItems []map[string]any
+		put := func(ctx context.Context, items []map[string]any) error {
+			actualItems = append(actualItems, items...)
+			return nil
+		}
+
+		count, err := importItems(context.Background(), strings.NewReader(`{"id":"theID","count":9007199254740993}`+"\n"+`{"id":"anID","tags":[1]}`+"\n"), put)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 2, count)
+		av, err := attributevalue.MarshalMap(actualItems[0])
+		assert.NoError(t, err)
+		assert.Equal(t, &types.AttributeValueMemberN{Value: "9007199254740993"}, av["count"])
+		assert.Equal(t, []any{attributevalue.Number("1")}, actualItems[1]["tags"])
+	})
+
+	t.Run("puts_the_items_in_batches", func(t *testing.T) {
+		var batchSizes []int
+		put := func(ctx context.Context, items []map[string]any) error {
+			batchSizes = append(batchSizes, len(items))
+			return nil
+		}
+
+		count, err := importItems(context.Background(), strings.NewReader(strings.Repeat(`{"id":"anID"}`+"\n", importBatchSize+1)), put)
+
+		assert.NoError(t, err)
+		assert.Equal(t, importBatchSize+1, count)
+		assert.Equal(t, []int{importBatchSize, 1}, batchSizes)
+	})
+
+	t.Run("returns_an_error_for_a_line_that_is_not_json", func(t *testing.T) {
+		put := func(ctx context.Context, items []map[string]any) error { return nil }
+
+		count, err := importItems(context.Background(), strings.NewReader(`{"id":"anID"}`+"\nnotJSON\n"), put)
+
+		assert.Equal(t, 0, count)
+		assert.ErrorContains(t, err, "failed to read item 1")
+	})
+
+	t.Run("returns_the_error_from_putting_a_batch", func(t *testing.T) {
+		put := func(ctx context.Context, items []map[string]any) error { return errors.New("the put error") }
+
+		count, err := importItems(context.Background(), strings.NewReader(`{"id":"anID"}`+"\n"), put)
+
+		assert.Equal(t, 0, count)
+		assert.EqualError(t, err, "failed to put items 0 to 0: the put error")
+	})
+}
+
+func TestSeedTables(t *testing.T) {
+	t.Run("puts_each_tables_fixtures_in_table_name_order", func(t *testing.T) {
+		buf := captureOutput(t)
+		var actualTables []string
+		actualItems := map[string][]map[string]any{}
+		putter := func(tableName string) putFunc {
+			return func(ctx context.Context, items []map[string]any) error {
+				actualTables = append(actualTables, tableName)
+				actualItems[tableName] = items
+				return nil
+			}
+		}
+
+		err := seedTables(context.Background(), strings.NewReader(`{"users":[{"id":"theID","age":3}],"accounts":[]}`), putter)
+
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"accounts", "users"}, actualTables)
+		assert.Equal(t, []map[string]any{{"id": "theID", "age": attributevalue.Number("3")}}, actualItems["users"])
+		assert.Equal(t, "Seeded 0 items for accounts\nSeeded 1 items for users\n", buf.String())
+	})
+
+	t.Run("returns_an_error_for_fixtures_that_are_not_an_object_of_arrays", func(t *testing.T) {
+		err := seedTables(context.Background(), strings.NewReader(`["notFixtures"]`), nil)
+
+		assert.ErrorContains(t, err, "failed to read fixtures")
+	})
+
+	t.Run("returns_the_error_from_seeding_a_table", func(t *testing.T) {
+		captureOutput(t)
+		putter := func(tableName string) putFunc {
+			return func(ctx context.Context, items []map[string]any) error { return errors.New("the put error") }
+		}
+
+		err := seedTables(context.Background(), strings.NewReader(`{"users":[{"id":"theID"}]}`), putter)
+
+		assert.EqualError(t, err, "failed to seed users: the put error")
+	})
+}
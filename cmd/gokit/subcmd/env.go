@@ -0,0 +1,56 @@
+package subcmd
+
+import (
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/spf13/cobra"
+
+	"github.com/half-ogre/go-kit/envkit"
+)
+
+var envExampleFile string
+
+var envCmd = &cobra.Command{
+	Use:   "env",
+	Short: "Environment variable tools",
+}
+
+var envDocCmd = &cobra.Command{
+	Use:   "doc",
+	Short: "Document the variables in a .env example file",
+	Long: `Writes the variables in a .env example file as a Markdown table, or JSON or YAML with -o, in the
+same format as envkit.WriteEnvMarkdown. A variable's value in the file is its default; a variable with
+no value is required.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runEnvDoc(envExampleFile, stdout, outputFlags.Output)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(envCmd)
+	envCmd.AddCommand(envDocCmd)
+	envDocCmd.Flags().StringVarP(&envExampleFile, "file", "f", ".env.example", "Path to the .env example file")
+}
+
+func runEnvDoc(path string, w io.Writer, output string) error {
+	env, err := envkit.ReadEnvFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", path, err)
+	}
+
+	vars := make([]envkit.EnvVar, 0, len(env))
+	for name, value := range env {
+		vars = append(vars, envkit.EnvVar{Name: name, Type: "string", Default: value, Required: value == ""})
+	}
+	sort.Slice(vars, func(i, j int) bool { return vars[i].Name < vars[j].Name })
+
+	switch output {
+	case outputJSON:
+		return envkit.WriteEnvJSON(w, vars)
+	case outputYAML:
+		return newOutputWriter(w, output).WriteValue(vars)
+	}
+	return envkit.WriteEnvMarkdown(w, vars)
+}
@@ -0,0 +1,57 @@
+package subcmd
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func writeTestEnvFile(t *testing.T, content string) string {
+	path := filepath.Join(t.TempDir(), ".env.example")
+	assert.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	return path
+}
+
+func TestRunEnvDoc(t *testing.T) {
+	t.Run("writes_the_variables_as_a_markdown_table_in_name_order", func(t *testing.T) {
+		path := writeTestEnvFile(t, "PORT=8080\nDATABASE_URL=\n")
+		var buf bytes.Buffer
+
+		err := runEnvDoc(path, &buf, outputText)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "| Name | Type | Default | Required | Description |\n"+
+			"| --- | --- | --- | --- | --- |\n"+
+			"| `DATABASE_URL` | string |  | yes |  |\n"+
+			"| `PORT` | string | `8080` | no |  |\n", buf.String())
+	})
+
+	t.Run("writes_the_variables_as_json", func(t *testing.T) {
+		path := writeTestEnvFile(t, "PORT=8080\n")
+		var buf bytes.Buffer
+
+		err := runEnvDoc(path, &buf, outputJSON)
+
+		assert.NoError(t, err)
+		assert.JSONEq(t, `[{"name":"PORT","type":"string","default":"8080","required":false}]`, buf.String())
+	})
+
+	t.Run("writes_the_variables_as_yaml", func(t *testing.T) {
+		path := writeTestEnvFile(t, "PORT=8080\n")
+		var buf bytes.Buffer
+
+		err := runEnvDoc(path, &buf, outputYAML)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "- name: PORT\n  type: string\n  default: \"8080\"\n  required: false\n", buf.String())
+	})
+
+	t.Run("returns_an_error_when_the_file_does_not_exist", func(t *testing.T) {
+		err := runEnvDoc("aMissingFile", &bytes.Buffer{}, outputText)
+
+		assert.ErrorContains(t, err, "failed to read aMissingFile")
+	})
+}
@@ -0,0 +1,47 @@
+package subcmd
+
+import (
+	"io"
+	"os"
+
+	"github.com/half-ogre/go-kit/tablekit"
+)
+
+const (
+	outputText = "text"
+	outputJSON = "json"
+	outputYAML = "yaml"
+)
+
+// outputFlags are the --output, --columns, and --no-color flags
+var outputFlags tablekit.Flags
+
+// stdout is where command output is written; tests replace it
+var stdout io.Writer = os.Stdout
+
+// validateOutputFormat checks the --output flag
+func validateOutputFormat(format string) error {
+	_, err := tablekit.ParseFormat(format)
+	return err
+}
+
+// newOutputWriter returns a writer to w in output, with the columns and color set by the flags
+func newOutputWriter(w io.Writer, output string) *tablekit.Writer {
+	// output was checked by validateOutputFormat before any command ran
+	format, _ := tablekit.ParseFormat(output)
+	options := []tablekit.WriterOption{tablekit.WithColumns(outputFlags.Columns...)}
+	if outputFlags.NoColor {
+		options = append(options, tablekit.WithColor(false))
+	}
+	return tablekit.NewWriter(w, format, options...)
+}
+
+// isStructured reports whether output is JSON or YAML
+func isStructured(output string) bool {
+	return output == outputJSON || output == outputYAML
+}
+
+// writeValue writes v to stdout as indented JSON or YAML
+func writeValue(output string, v any) error {
+	return newOutputWriter(stdout, output).WriteValue(v)
+}
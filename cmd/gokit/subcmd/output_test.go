@@ -0,0 +1,31 @@
+package subcmd
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// captureOutput sends command output to a buffer for the rest of the test
+func captureOutput(t *testing.T) *bytes.Buffer {
+	var buf bytes.Buffer
+	previous := stdout
+	stdout = &buf
+	t.Cleanup(func() { stdout = previous })
+	return &buf
+}
+
+func TestValidateOutputFormat(t *testing.T) {
+	t.Run("accepts_the_tablekit_formats_and_text", func(t *testing.T) {
+		for _, format := range []string{"text", "table", "wide", "json", "yaml"} {
+			assert.NoError(t, validateOutputFormat(format), format)
+		}
+	})
+
+	t.Run("returns_an_error_for_an_unknown_format", func(t *testing.T) {
+		err := validateOutputFormat("xml")
+
+		assert.EqualError(t, err, `invalid output format "xml" (use table, wide, json, or yaml)`)
+	})
+}
@@ -0,0 +1,43 @@
+package subcmd
+
+import (
+	"github.com/spf13/cobra"
+
+	pgkitsubcmd "github.com/half-ogre/go-kit/cmd/pgkit/subcmd"
+	"github.com/half-ogre/go-kit/envkit"
+)
+
+var envFiles []string
+
+var rootCmd = &cobra.Command{
+	Use:   "gokit",
+	Short: "Operational toolkit for go-kit services",
+	Long: `One CLI for the go-kit operational commands: PostgreSQL migrations (gokit pgkit), DynamoDB
+export, import, and seeding (gokit dynamodb), environment documentation (gokit env), and version.`,
+	SilenceUsage: true,
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		if err := validateOutputFormat(outputFlags.Output); err != nil {
+			return err
+		}
+
+		// Variables already in the environment win over the files, as with envkit.LoadEnv
+		if len(envFiles) > 0 {
+			return envkit.LoadEnv(envFiles...)
+		}
+		return nil
+	},
+}
+
+func Execute() error {
+	return rootCmd.Execute()
+}
+
+func init() {
+	// Run this root's PersistentPreRunE before the pgkit root's, so --env-file can set DATABASE_URL
+	cobra.EnableTraverseRunHooks = true
+
+	rootCmd.PersistentFlags().StringArrayVar(&envFiles, "env-file", nil, "Load variables from a .env file or env directory before running (repeatable)")
+	outputFlags.Register(rootCmd.PersistentFlags())
+
+	rootCmd.AddCommand(pgkitsubcmd.Command())
+}
@@ -0,0 +1,53 @@
+package subcmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/half-ogre/go-kit/awskit"
+	pgkitsubcmd "github.com/half-ogre/go-kit/cmd/pgkit/subcmd"
+	"github.com/half-ogre/go-kit/versionkit"
+)
+
+var buildInfo *versionkit.BuildInfo
+
+// versionOutput is the JSON output of version
+type versionOutput struct {
+	Version   string `json:"version"`
+	GitCommit string `json:"git_commit"`
+	BuildDate string `json:"build_date"`
+}
+
+// SetBuildInfo sets the build information for the version command, for gokit pgkit version, and for
+// the user agent of the AWS requests of gokit dynamodb
+func SetBuildInfo(bi *versionkit.BuildInfo) {
+	buildInfo = bi
+	pgkitsubcmd.SetBuildInfo(bi)
+	awskit.UseDefaultOptions(awskit.WithUserAgent("gokit", bi.WithRuntimeDefaults()))
+}
+
+var versionCmd = &cobra.Command{
+	Use:   "version",
+	Short: "Print version information",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runVersion(buildInfo.WithRuntimeDefaults(), outputFlags.Output)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(versionCmd)
+}
+
+func runVersion(bi *versionkit.BuildInfo, output string) error {
+	if isStructured(output) {
+		return writeValue(output, versionOutput{
+			Version:   bi.GetBuildVersion(),
+			GitCommit: bi.GetBuildCommit(),
+			BuildDate: bi.GetBuildDate(),
+		})
+	}
+
+	_, err := fmt.Fprintf(stdout, "gokit %s\n", bi.String())
+	return err
+}
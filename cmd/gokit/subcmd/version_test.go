@@ -0,0 +1,29 @@
+package subcmd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/half-ogre/go-kit/versionkit"
+)
+
+func TestRunVersion(t *testing.T) {
+	t.Run("writes_the_version_as_text", func(t *testing.T) {
+		buf := captureOutput(t)
+
+		err := runVersion(&versionkit.BuildInfo{Version: "theVersion", GitCommit: "theCommit", BuildDate: "theDate"}, outputText)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "gokit version theVersion (commit: theCommit, built: theDate)\n", buf.String())
+	})
+
+	t.Run("writes_the_version_as_json", func(t *testing.T) {
+		buf := captureOutput(t)
+
+		err := runVersion(&versionkit.BuildInfo{Version: "theVersion"}, outputJSON)
+
+		assert.NoError(t, err)
+		assert.JSONEq(t, `{"version":"theVersion","git_commit":"unknown","build_date":"unknown"}`, buf.String())
+	})
+}
@@ -15,7 +15,7 @@ var createCmd = &cobra.Command{
 	Args:  cobra.MaximumNArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		return withAdminDBConnection(cmd, args, func(db pgkit.DB, dbName string) error {
-			return runCreate(db, dbName)
+			return runCreate(db, dbName, outputFlags.Output)
 		})
 	},
 }
@@ -24,8 +24,15 @@ func init() {
 	rootCmd.AddCommand(createCmd)
 }
 
+// databaseOutput is the JSON output of create and drop
+type databaseOutput struct {
+	Database string `json:"database"`
+	Created  bool   `json:"created,omitempty"`
+	Dropped  bool   `json:"dropped,omitempty"`
+}
+
 // runCreate contains the main logic for creating a database
-func runCreate(adminDB pgkit.DB, dbName string) error {
+func runCreate(adminDB pgkit.DB, dbName string, output string) error {
 	// Check if database already exists
 	var exists bool
 	err := adminDB.QueryRow(context.Background(), "SELECT EXISTS(SELECT 1 FROM pg_database WHERE datname = $1)", dbName).Scan(&exists)
@@ -43,6 +50,10 @@ func runCreate(adminDB pgkit.DB, dbName string) error {
 		return fmt.Errorf("failed to create database: %w", err)
 	}
 
+	if isStructured(output) {
+		return writeValue(output, databaseOutput{Database: dbName, Created: true})
+	}
+
 	fmt.Printf("Database '%s' created successfully\n", dbName)
 	return nil
 }
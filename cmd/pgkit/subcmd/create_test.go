@@ -36,7 +36,7 @@ func TestRunCreate(t *testing.T) {
 			},
 		}
 
-		err := runCreate(fakeDB, "theDatabase")
+		err := runCreate(fakeDB, "theDatabase", outputText)
 
 		assert.NoError(t, err)
 		assert.Equal(t, "SELECT EXISTS(SELECT 1 FROM pg_database WHERE datname = $1)", actualQueryRowQuery)
@@ -58,7 +58,7 @@ func TestRunCreate(t *testing.T) {
 			},
 		}
 
-		err := runCreate(fakeDB, "existing")
+		err := runCreate(fakeDB, "existing", outputText)
 
 		assert.EqualError(t, err, "database 'existing' already exists")
 	})
@@ -74,7 +74,7 @@ func TestRunCreate(t *testing.T) {
 			},
 		}
 
-		err := runCreate(fakeDB, "aDatabase")
+		err := runCreate(fakeDB, "aDatabase", outputText)
 
 		assert.EqualError(t, err, "failed to check if database exists: the query error")
 	})
@@ -96,7 +96,7 @@ func TestRunCreate(t *testing.T) {
 			},
 		}
 
-		err := runCreate(fakeDB, "aDatabase")
+		err := runCreate(fakeDB, "aDatabase", outputText)
 
 		assert.EqualError(t, err, "failed to create database: the exec error")
 	})
@@ -120,9 +120,31 @@ func TestRunCreate(t *testing.T) {
 			},
 		}
 
-		err := runCreate(fakeDB, `my"db`)
+		err := runCreate(fakeDB, `my"db`, outputText)
 
 		assert.NoError(t, err)
 		assert.Equal(t, `CREATE DATABASE "my""db"`, actualExecQuery)
 	})
+
+	t.Run("writes_the_result_as_json", func(t *testing.T) {
+		buf := captureOutput(t)
+		fakeDB := &pgkit.FakeDB{
+			QueryRowFake: func(ctx context.Context, query string, args ...any) pgkit.Row {
+				return &pgkit.FakeRow{
+					ScanFake: func(dest ...any) error {
+						*dest[0].(*bool) = false
+						return nil
+					},
+				}
+			},
+			ExecFake: func(ctx context.Context, query string, args ...any) (sql.Result, error) {
+				return nil, nil
+			},
+		}
+
+		err := runCreate(fakeDB, "theDatabase", outputJSON)
+
+		assert.NoError(t, err)
+		assert.JSONEq(t, `{"database": "theDatabase", "created": true}`, buf.String())
+	})
 }
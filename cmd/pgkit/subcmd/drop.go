@@ -2,10 +2,11 @@ package subcmd
 
 import (
 	"context"
+	"errors"
 	"fmt"
-	"strings"
 
 	"github.com/half-ogre/go-kit/pgkit"
+	"github.com/half-ogre/go-kit/promptkit"
 	"github.com/spf13/cobra"
 )
 
@@ -13,6 +14,9 @@ var (
 	force bool
 )
 
+// prompter asks for confirmation of destructive commands; tests replace it
+var prompter = promptkit.New()
+
 var dropCmd = &cobra.Command{
 	Use:   "drop [database-name]",
 	Short: "Drop a database",
@@ -20,7 +24,7 @@ var dropCmd = &cobra.Command{
 	Args:  cobra.MaximumNArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		return withAdminDBConnection(cmd, args, func(db pgkit.DB, dbName string) error {
-			return runDrop(db, dbName, force)
+			return runDrop(db, dbName, force, outputFlags.Output)
 		})
 	},
 }
@@ -31,13 +35,22 @@ func init() {
 }
 
 // runDrop contains the main logic for dropping a database
-func runDrop(adminDB pgkit.DB, dbName string, forceFlag bool) error {
+func runDrop(adminDB pgkit.DB, dbName string, forceFlag bool, output string) error {
+	// A prompt would corrupt JSON or YAML output, so it requires --force
+	if !forceFlag && isStructured(output) {
+		return fmt.Errorf("--force is required with --output %s", output)
+	}
+
 	// Confirm deletion unless --force is used
 	if !forceFlag {
-		fmt.Printf("Are you sure you want to drop database '%s'? (yes/no): ", dbName)
-		var response string
-		fmt.Scanln(&response)
-		if strings.ToLower(response) != "yes" {
+		confirmed, err := prompter.Confirm(fmt.Sprintf("Are you sure you want to drop database '%s'?", dbName))
+		if errors.Is(err, promptkit.ErrNoAnswer) {
+			return fmt.Errorf("--force is required when input is not a terminal")
+		}
+		if err != nil {
+			return err
+		}
+		if !confirmed {
 			fmt.Println("Drop cancelled")
 			return nil
 		}
@@ -60,6 +73,10 @@ func runDrop(adminDB pgkit.DB, dbName string, forceFlag bool) error {
 		return fmt.Errorf("failed to drop database: %w", err)
 	}
 
+	if isStructured(output) {
+		return writeValue(output, databaseOutput{Database: dbName, Dropped: true})
+	}
+
 	fmt.Printf("Database '%s' dropped successfully\n", dbName)
 	return nil
 }
@@ -1,15 +1,27 @@
 package subcmd
 
 import (
+	"bytes"
 	"context"
 	"database/sql"
 	"errors"
+	"strings"
 	"testing"
 
 	"github.com/half-ogre/go-kit/pgkit"
+	"github.com/half-ogre/go-kit/promptkit"
 	"github.com/stretchr/testify/assert"
 )
 
+// answerPrompts replaces the prompter with one reading answers, or one that can't ask when answers is empty
+func answerPrompts(t *testing.T, answers string) *bytes.Buffer {
+	var out bytes.Buffer
+	previous := prompter
+	prompter = promptkit.New(promptkit.WithInput(strings.NewReader(answers)), promptkit.WithOutput(&out), promptkit.WithInteractive(answers != ""))
+	t.Cleanup(func() { prompter = previous })
+	return &out
+}
+
 func TestRunDrop(t *testing.T) {
 	t.Run("checks_that_database_exists_and_executes_query_to_drop_the_database", func(t *testing.T) {
 		actualQueryRowQuery := ""
@@ -36,7 +48,7 @@ func TestRunDrop(t *testing.T) {
 			},
 		}
 
-		err := runDrop(fakeDB, "theDatabase", true)
+		err := runDrop(fakeDB, "theDatabase", true, outputText)
 
 		assert.NoError(t, err)
 		assert.Equal(t, "SELECT EXISTS(SELECT 1 FROM pg_database WHERE datname = $1)", actualQueryRowQuery)
@@ -58,7 +70,7 @@ func TestRunDrop(t *testing.T) {
 			},
 		}
 
-		err := runDrop(fakeDB, "nonexistent", true)
+		err := runDrop(fakeDB, "nonexistent", true, outputText)
 
 		assert.EqualError(t, err, "database 'nonexistent' does not exist")
 	})
@@ -74,7 +86,7 @@ func TestRunDrop(t *testing.T) {
 			},
 		}
 
-		err := runDrop(fakeDB, "aDatabase", true)
+		err := runDrop(fakeDB, "aDatabase", true, outputText)
 
 		assert.EqualError(t, err, "failed to check if database exists: the query error")
 	})
@@ -96,7 +108,7 @@ func TestRunDrop(t *testing.T) {
 			},
 		}
 
-		err := runDrop(fakeDB, "aDatabase", true)
+		err := runDrop(fakeDB, "aDatabase", true, outputText)
 
 		assert.EqualError(t, err, "failed to drop database: the exec error")
 	})
@@ -120,9 +132,78 @@ func TestRunDrop(t *testing.T) {
 			},
 		}
 
-		err := runDrop(fakeDB, `my"db`, true)
+		err := runDrop(fakeDB, `my"db`, true, outputText)
 
 		assert.NoError(t, err)
 		assert.Equal(t, `DROP DATABASE "my""db"`, actualExecQuery)
 	})
+
+	t.Run("requires_force_with_json_output", func(t *testing.T) {
+		err := runDrop(&pgkit.FakeDB{}, "aDatabase", false, outputJSON)
+
+		assert.EqualError(t, err, "--force is required with --output json")
+	})
+
+	t.Run("writes_the_result_as_json", func(t *testing.T) {
+		buf := captureOutput(t)
+		fakeDB := &pgkit.FakeDB{
+			QueryRowFake: func(ctx context.Context, query string, args ...any) pgkit.Row {
+				return &pgkit.FakeRow{
+					ScanFake: func(dest ...any) error {
+						*dest[0].(*bool) = true
+						return nil
+					},
+				}
+			},
+			ExecFake: func(ctx context.Context, query string, args ...any) (sql.Result, error) {
+				return nil, nil
+			},
+		}
+
+		err := runDrop(fakeDB, "theDatabase", true, outputJSON)
+
+		assert.NoError(t, err)
+		assert.JSONEq(t, `{"database": "theDatabase", "dropped": true}`, buf.String())
+	})
+
+	t.Run("drops_the_database_when_the_prompt_is_confirmed", func(t *testing.T) {
+		out := answerPrompts(t, "yes\n")
+		actualExecQuery := ""
+		fakeDB := &pgkit.FakeDB{
+			QueryRowFake: func(ctx context.Context, query string, args ...any) pgkit.Row {
+				return &pgkit.FakeRow{
+					ScanFake: func(dest ...any) error {
+						*dest[0].(*bool) = true
+						return nil
+					},
+				}
+			},
+			ExecFake: func(ctx context.Context, query string, args ...any) (sql.Result, error) {
+				actualExecQuery = query
+				return nil, nil
+			},
+		}
+
+		err := runDrop(fakeDB, "theDatabase", false, outputText)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "Are you sure you want to drop database 'theDatabase'? (yes/no): ", out.String())
+		assert.Equal(t, `DROP DATABASE "theDatabase"`, actualExecQuery)
+	})
+
+	t.Run("does_not_drop_the_database_when_the_prompt_is_declined", func(t *testing.T) {
+		answerPrompts(t, "no\n")
+
+		err := runDrop(&pgkit.FakeDB{}, "aDatabase", false, outputText)
+
+		assert.NoError(t, err)
+	})
+
+	t.Run("requires_force_when_input_is_not_a_terminal", func(t *testing.T) {
+		answerPrompts(t, "")
+
+		err := runDrop(&pgkit.FakeDB{}, "aDatabase", false, outputText)
+
+		assert.EqualError(t, err, "--force is required when input is not a terminal")
+	})
 }
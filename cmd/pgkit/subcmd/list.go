@@ -4,6 +4,7 @@ import (
 	"fmt"
 
 	"github.com/half-ogre/go-kit/pgkit"
+	"github.com/half-ogre/go-kit/tablekit"
 	"github.com/spf13/cobra"
 )
 
@@ -12,7 +13,7 @@ var listCmd = &cobra.Command{
 	Short: "List available migrations",
 	Long:  `List all migration files in the specified directory.`,
 	RunE: func(cmd *cobra.Command, args []string) error {
-		return runList(migrationsDir)
+		return runList(migrationsDir, outputFlags.Output)
 	},
 }
 
@@ -21,22 +22,26 @@ func init() {
 	listCmd.Flags().StringVarP(&migrationsDir, "dir", "d", "migrations", "Directory containing migration files")
 }
 
+// listOutput is the JSON output of list
+type listOutput struct {
+	Migrations []migrationOutput `json:"migrations"`
+}
+
 // runList contains the main logic for listing migrations from the directory
-func runList(dir string) error {
+func runList(dir string, output string) error {
 	migrations, err := pgkit.ListMigrationsFromDir(dir)
 	if err != nil {
 		return fmt.Errorf("failed to list migrations: %w", err)
 	}
 
-	if len(migrations) == 0 {
-		fmt.Println("No migrations found")
-		return nil
+	if isStructured(output) {
+		return writeValue(output, listOutput{Migrations: toMigrationOutputs(migrations)})
 	}
 
-	fmt.Printf("Found %d migration(s):\n\n", len(migrations))
-	for _, m := range migrations {
-		fmt.Printf("Version %d: %s (%s)\n", m.Version, m.Description, m.Filename)
+	if len(migrations) == 0 {
+		fmt.Fprintln(stdout, "No migrations found")
+		return nil
 	}
 
-	return nil
+	return tablekit.WriteTable(newOutputWriter(output), migrationColumns, migrations)
 }
@@ -8,28 +8,42 @@ import (
 
 func TestRunList(t *testing.T) {
 	t.Run("successfully_lists_migrations_from_directory", func(t *testing.T) {
-		err := runList("../../../pgkit/testdata")
+		err := runList("../../../pgkit/testdata", outputText)
 
 		assert.NoError(t, err)
 	})
 
 	t.Run("returns_error_when_directory_is_empty", func(t *testing.T) {
-		err := runList("")
+		err := runList("", outputText)
 
 		assert.Error(t, err)
 		assert.Contains(t, err.Error(), "failed to list migrations")
 	})
 
 	t.Run("returns_error_when_directory_does_not_exist", func(t *testing.T) {
-		err := runList("nonexistent")
+		err := runList("nonexistent", outputText)
 
 		assert.Error(t, err)
 		assert.Contains(t, err.Error(), "failed to list migrations")
 	})
 
 	t.Run("succeeds_when_directory_has_no_migrations", func(t *testing.T) {
-		err := runList(".")
+		err := runList(".", outputText)
 
 		assert.NoError(t, err)
 	})
+
+	t.Run("writes_migrations_as_json", func(t *testing.T) {
+		buf := captureOutput(t)
+
+		err := runList("../../../pgkit/testdata", outputJSON)
+
+		assert.NoError(t, err)
+		assert.JSONEq(t, `{
+			"migrations": [
+				{"version": 1, "description": "initial", "filename": "001_initial.sql", "applied": false},
+				{"version": 2, "description": "add_email", "filename": "002_add_email.sql", "applied": false}
+			]
+		}`, buf.String())
+	})
 }
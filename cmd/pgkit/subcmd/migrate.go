@@ -1,7 +1,9 @@
 package subcmd
 
 import (
+	"errors"
 	"fmt"
+	"time"
 
 	"github.com/half-ogre/go-kit/pgkit"
 	"github.com/spf13/cobra"
@@ -18,7 +20,7 @@ var migrateCmd = &cobra.Command{
 	Long:  `Run SQL migration files from a directory against the database.`,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		return withDBConnection(cmd, func(db pgkit.DB) error {
-			return runMigrate(db, migrationsDir, toVersion, pgkit.NewMigrator())
+			return runMigrate(db, migrationsDir, toVersion, pgkit.NewMigrator(), outputFlags.Output)
 		})
 	},
 }
@@ -29,20 +31,88 @@ func init() {
 	migrateCmd.Flags().IntVar(&toVersion, "to-version", 0, "Migrate up to and including this version number (e.g., 2)")
 }
 
+// migrateOutput is the JSON output of migrate
+type migrateOutput struct {
+	Dir        string            `json:"dir"`
+	ToVersion  int               `json:"to_version,omitempty"`
+	Applied    []migrationOutput `json:"applied"`
+	Pending    []migrationOutput `json:"pending"`
+	DurationMS int64             `json:"duration_ms"`
+	Error      string            `json:"error,omitempty"`
+}
+
 // runMigrate contains the main logic for running database migrations
-func runMigrate(db pgkit.DB, dir string, toVersion int, migrator pgkit.Migrator) error {
+func runMigrate(db pgkit.DB, dir string, toVersion int, migrator pgkit.Migrator, output string) error {
+	if isStructured(output) {
+		return runMigrateStructured(db, dir, toVersion, migrator, output)
+	}
+
 	if toVersion > 0 {
 		fmt.Printf("Running migrations from %s up to version %d...\n", dir, toVersion)
-		if err := migrator.RunMigrationsToVersion(db, dir, toVersion); err != nil {
-			return fmt.Errorf("migration failed: %w", err)
-		}
 	} else {
 		fmt.Printf("Running migrations from %s...\n", dir)
-		if err := migrator.RunMigrations(db, dir); err != nil {
+	}
+
+	if err := migrate(db, dir, toVersion, migrator); err != nil {
+		return err
+	}
+
+	fmt.Println("All migrations completed successfully")
+	return nil
+}
+
+// runMigrateStructured runs the migrations and reports which were applied and which are still pending,
+// comparing the migration list before and after. The report is written even if a migration fails.
+func runMigrateStructured(db pgkit.DB, dir string, toVersion int, migrator pgkit.Migrator, output string) error {
+	result := migrateOutput{Dir: dir, ToVersion: toVersion, Applied: []migrationOutput{}, Pending: []migrationOutput{}}
+
+	before, err := migrator.ListMigrations(db, dir)
+	if err != nil {
+		err = fmt.Errorf("failed to list migrations: %w", err)
+		result.Error = err.Error()
+		return errors.Join(err, writeValue(output, result))
+	}
+
+	start := time.Now()
+	migrateErr := migrate(db, dir, toVersion, migrator)
+	result.DurationMS = time.Since(start).Milliseconds()
+
+	after, err := migrator.ListMigrations(db, dir)
+	if err != nil {
+		migrateErr = errors.Join(migrateErr, fmt.Errorf("failed to list migrations: %w", err))
+		after = before
+	}
+
+	wasApplied := make(map[string]bool, len(before))
+	for _, m := range before {
+		wasApplied[m.Filename] = m.Applied
+	}
+	for _, m := range toMigrationOutputs(after) {
+		switch {
+		case !m.Applied:
+			result.Pending = append(result.Pending, m)
+		case !wasApplied[m.Filename]:
+			result.Applied = append(result.Applied, m)
+		}
+	}
+
+	if migrateErr != nil {
+		result.Error = migrateErr.Error()
+	}
+
+	return errors.Join(migrateErr, writeValue(output, result))
+}
+
+func migrate(db pgkit.DB, dir string, toVersion int, migrator pgkit.Migrator) error {
+	if toVersion > 0 {
+		if err := migrator.RunMigrationsToVersion(db, dir, toVersion); err != nil {
 			return fmt.Errorf("migration failed: %w", err)
 		}
+		return nil
 	}
 
-	fmt.Println("All migrations completed successfully")
+	if err := migrator.RunMigrations(db, dir); err != nil {
+		return fmt.Errorf("migration failed: %w", err)
+	}
 	return nil
 }
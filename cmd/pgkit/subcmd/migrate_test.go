@@ -1,8 +1,10 @@
 package subcmd
 
 import (
+	"encoding/json"
 	"errors"
 	"testing"
+	"time"
 
 	"github.com/half-ogre/go-kit/pgkit"
 	"github.com/stretchr/testify/assert"
@@ -19,7 +21,7 @@ func TestRunMigrate(t *testing.T) {
 			},
 		}
 
-		err := runMigrate(fakeDB, "theMigrationsDir", 0, fakeMigrator)
+		err := runMigrate(fakeDB, "theMigrationsDir", 0, fakeMigrator, outputText)
 
 		assert.NoError(t, err)
 		assert.Equal(t, "theMigrationsDir", actualDir)
@@ -33,7 +35,7 @@ func TestRunMigrate(t *testing.T) {
 			},
 		}
 
-		err := runMigrate(fakeDB, "aMigrationsDir", 0, fakeMigrator)
+		err := runMigrate(fakeDB, "aMigrationsDir", 0, fakeMigrator, outputText)
 
 		assert.EqualError(t, err, "migration failed: the migration error")
 	})
@@ -50,7 +52,7 @@ func TestRunMigrate(t *testing.T) {
 			},
 		}
 
-		err := runMigrate(fakeDB, "theMigrationsDir", 2, fakeMigrator)
+		err := runMigrate(fakeDB, "theMigrationsDir", 2, fakeMigrator, outputText)
 
 		assert.NoError(t, err)
 		assert.Equal(t, "theMigrationsDir", actualDir)
@@ -65,8 +67,66 @@ func TestRunMigrate(t *testing.T) {
 			},
 		}
 
-		err := runMigrate(fakeDB, "aMigrationsDir", 2, fakeMigrator)
+		err := runMigrate(fakeDB, "aMigrationsDir", 2, fakeMigrator, outputText)
 
 		assert.EqualError(t, err, "migration failed: the migration error")
 	})
+
+	t.Run("writes_applied_and_pending_migrations_as_json", func(t *testing.T) {
+		buf := captureOutput(t)
+		fakeDB := &pgkit.FakeDB{}
+		appliedAt := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+		ran := false
+		fakeMigrator := &pgkit.FakeMigrator{
+			ListMigrationsFake: func(db pgkit.DB, dirPath string) ([]pgkit.Migration, error) {
+				migrations := []pgkit.Migration{
+					{Version: 1, Description: "initial", Filename: "001_initial.sql", Applied: true, AppliedAt: &appliedAt},
+					{Version: 2, Description: "add_email", Filename: "002_add_email.sql"},
+					{Version: 3, Description: "add_phone", Filename: "003_add_phone.sql"},
+				}
+				if ran {
+					migrations[1].Applied = true
+					migrations[1].AppliedAt = &appliedAt
+				}
+				return migrations, nil
+			},
+			RunMigrationsToVersionFake: func(db pgkit.DB, dir string, toVersion int) error {
+				ran = true
+				return nil
+			},
+		}
+
+		err := runMigrate(fakeDB, "theMigrationsDir", 2, fakeMigrator, outputJSON)
+
+		assert.NoError(t, err)
+		var result migrateOutput
+		assert.NoError(t, json.Unmarshal(buf.Bytes(), &result))
+		assert.Equal(t, "theMigrationsDir", result.Dir)
+		assert.Equal(t, 2, result.ToVersion)
+		assert.Equal(t, []migrationOutput{{Version: 2, Description: "add_email", Filename: "002_add_email.sql", Applied: true, AppliedAt: &appliedAt}}, result.Applied)
+		assert.Equal(t, []migrationOutput{{Version: 3, Description: "add_phone", Filename: "003_add_phone.sql"}}, result.Pending)
+		assert.Empty(t, result.Error)
+	})
+
+	t.Run("writes_the_error_as_json_when_migration_fails", func(t *testing.T) {
+		buf := captureOutput(t)
+		fakeDB := &pgkit.FakeDB{}
+		fakeMigrator := &pgkit.FakeMigrator{
+			ListMigrationsFake: func(db pgkit.DB, dirPath string) ([]pgkit.Migration, error) {
+				return []pgkit.Migration{{Version: 1, Description: "initial", Filename: "001_initial.sql"}}, nil
+			},
+			RunMigrationsFake: func(db pgkit.DB, dir string) error {
+				return errors.New("the migration error")
+			},
+		}
+
+		err := runMigrate(fakeDB, "aMigrationsDir", 0, fakeMigrator, outputJSON)
+
+		assert.EqualError(t, err, "migration failed: the migration error")
+		var result migrateOutput
+		assert.NoError(t, json.Unmarshal(buf.Bytes(), &result))
+		assert.Equal(t, "migration failed: the migration error", result.Error)
+		assert.Empty(t, result.Applied)
+		assert.Len(t, result.Pending, 1)
+	})
 }
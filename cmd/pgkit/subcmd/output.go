@@ -0,0 +1,80 @@
+package subcmd
+
+import (
+	"io"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/half-ogre/go-kit/pgkit"
+	"github.com/half-ogre/go-kit/tablekit"
+)
+
+const (
+	outputText = "text"
+	outputJSON = "json"
+	outputYAML = "yaml"
+)
+
+// outputFlags are the --output, --columns, and --no-color flags
+var outputFlags tablekit.Flags
+
+// stdout is where command output is written; tests replace it
+var stdout io.Writer = os.Stdout
+
+// validateOutputFormat checks the --output flag
+func validateOutputFormat(format string) error {
+	_, err := tablekit.ParseFormat(format)
+	return err
+}
+
+// newOutputWriter returns a writer to stdout in output, with the columns and color set by the flags
+func newOutputWriter(output string) *tablekit.Writer {
+	// output was checked by validateOutputFormat before any command ran
+	format, _ := tablekit.ParseFormat(output)
+	options := []tablekit.WriterOption{tablekit.WithColumns(outputFlags.Columns...)}
+	if outputFlags.NoColor {
+		options = append(options, tablekit.WithColor(false))
+	}
+	return tablekit.NewWriter(stdout, format, options...)
+}
+
+// isStructured reports whether output is JSON or YAML
+func isStructured(output string) bool {
+	return output == outputJSON || output == outputYAML
+}
+
+// writeValue writes v to stdout as indented JSON or YAML
+func writeValue(output string, v any) error {
+	return newOutputWriter(output).WriteValue(v)
+}
+
+// migrationColumns are the table columns of a migration
+var migrationColumns = []tablekit.Column[pgkit.Migration]{
+	{Name: "version", Value: func(m pgkit.Migration) string { return strconv.Itoa(m.Version) }},
+	{Name: "description", Value: func(m pgkit.Migration) string { return m.Description }},
+	{Name: "filename", Value: func(m pgkit.Migration) string { return m.Filename }},
+}
+
+// migrationOutput is a migration in JSON or YAML output
+type migrationOutput struct {
+	Version     int        `json:"version"`
+	Description string     `json:"description"`
+	Filename    string     `json:"filename"`
+	Applied     bool       `json:"applied"`
+	AppliedAt   *time.Time `json:"applied_at,omitempty"`
+}
+
+func toMigrationOutputs(migrations []pgkit.Migration) []migrationOutput {
+	outputs := make([]migrationOutput, 0, len(migrations))
+	for _, m := range migrations {
+		outputs = append(outputs, migrationOutput{
+			Version:     m.Version,
+			Description: m.Description,
+			Filename:    m.Filename,
+			Applied:     m.Applied,
+			AppliedAt:   m.AppliedAt,
+		})
+	}
+	return outputs
+}
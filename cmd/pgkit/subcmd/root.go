@@ -18,6 +18,10 @@ var rootCmd = &cobra.Command{
 	Short: "PostgreSQL toolkit",
 	Long:  `A toolkit for managing PostgreSQL databases including migrations, creation, and deletion.`,
 	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		if err := validateOutputFormat(outputFlags.Output); err != nil {
+			return err
+		}
+
 		// Skip DB URL requirement for commands that don't need a database
 		if cmd.Name() == "version" || cmd.Name() == "list" {
 			return nil
@@ -40,8 +44,14 @@ func Execute() error {
 	return rootCmd.Execute()
 }
 
+// Command returns the pgkit root command, for adding its subcommands to another CLI such as gokit
+func Command() *cobra.Command {
+	return rootCmd
+}
+
 func init() {
 	rootCmd.PersistentFlags().StringVar(&dbURL, "db", "", "Database connection string (or use DATABASE_URL env var)")
+	outputFlags.Register(rootCmd.PersistentFlags())
 }
 
 // getDBURL returns the database URL from flag or environment variable
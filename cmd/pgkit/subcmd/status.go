@@ -5,6 +5,7 @@ import (
 	"time"
 
 	"github.com/half-ogre/go-kit/pgkit"
+	"github.com/half-ogre/go-kit/tablekit"
 	"github.com/spf13/cobra"
 )
 
@@ -14,41 +15,85 @@ var statusCmd = &cobra.Command{
 	Long:  `Show migration status - which migrations are available, which are applied, and when they were applied.`,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		return withDBConnection(cmd, func(db pgkit.DB) error {
-			return runStatus(db, migrationsDir, pgkit.NewMigrator())
+			return runStatus(db, migrationsDir, pgkit.NewMigrator(), outputFlags.Output)
 		})
 	},
 }
 
+// statusOutput is the JSON output of status
+type statusOutput struct {
+	Migrations []migrationOutput `json:"migrations"`
+	Applied    int               `json:"applied"`
+	Pending    int               `json:"pending"`
+}
+
 // runStatus contains the main logic for showing migration status
-func runStatus(db pgkit.DB, dir string, migrator pgkit.Migrator) error {
+func runStatus(db pgkit.DB, dir string, migrator pgkit.Migrator, output string) error {
 	migrations, err := migrator.ListMigrations(db, dir)
 	if err != nil {
 		return fmt.Errorf("failed to list migrations: %w", err)
 	}
 
+	if isStructured(output) {
+		result := statusOutput{Migrations: toMigrationOutputs(migrations)}
+		for _, m := range migrations {
+			if m.Applied {
+				result.Applied++
+			} else {
+				result.Pending++
+			}
+		}
+		return writeValue(output, result)
+	}
+
 	if len(migrations) == 0 {
-		fmt.Println("No migrations found")
+		fmt.Fprintln(stdout, "No migrations found")
 		return nil
 	}
 
-	fmt.Printf("Migration status:\n\n")
+	if err := tablekit.WriteTable(newOutputWriter(output), statusColumns, migrations); err != nil {
+		return err
+	}
 
 	appliedCount := 0
 	for _, m := range migrations {
 		if m.Applied {
 			appliedCount++
-			fmt.Printf("✓ Version %d: %s (%s) - applied at %s\n",
-				m.Version, m.Description, m.Filename, m.AppliedAt.Format(time.RFC3339))
-		} else {
-			fmt.Printf("  Version %d: %s (%s) - not applied\n",
-				m.Version, m.Description, m.Filename)
 		}
 	}
-
-	fmt.Printf("\n%d of %d migrations applied\n", appliedCount, len(migrations))
+	fmt.Fprintf(stdout, "\n%d of %d migrations applied\n", appliedCount, len(migrations))
 	return nil
 }
 
+// statusColumns are the table columns of status; filename is only in the wide table
+var statusColumns = append(migrationColumns[:2:2],
+	tablekit.Column[pgkit.Migration]{
+		Name: "status",
+		Value: func(m pgkit.Migration) string {
+			if m.Applied {
+				return "applied"
+			}
+			return "pending"
+		},
+		Color: func(m pgkit.Migration) tablekit.Color {
+			if m.Applied {
+				return tablekit.ColorGreen
+			}
+			return tablekit.ColorYellow
+		},
+	},
+	tablekit.Column[pgkit.Migration]{
+		Name: "applied_at",
+		Value: func(m pgkit.Migration) string {
+			if m.AppliedAt == nil {
+				return "-"
+			}
+			return m.AppliedAt.Format(time.RFC3339)
+		},
+	},
+	tablekit.Column[pgkit.Migration]{Name: "filename", Value: migrationColumns[2].Value, Wide: true},
+)
+
 func init() {
 	rootCmd.AddCommand(statusCmd)
 	statusCmd.Flags().StringVarP(&migrationsDir, "dir", "d", "migrations", "Directory containing migration files")
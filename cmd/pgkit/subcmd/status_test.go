@@ -11,6 +11,7 @@ import (
 
 func TestRunStatus(t *testing.T) {
 	t.Run("successfully_displays_migrations_with_applied_status", func(t *testing.T) {
+		buf := captureOutput(t)
 		fakeDB := &pgkit.FakeDB{}
 		time1 := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
 		fakeMigrator := &pgkit.FakeMigrator{
@@ -22,9 +23,33 @@ func TestRunStatus(t *testing.T) {
 			},
 		}
 
-		err := runStatus(fakeDB, "aMigrationsDir", fakeMigrator)
+		err := runStatus(fakeDB, "aMigrationsDir", fakeMigrator, outputText)
 
 		assert.NoError(t, err)
+		assert.Equal(t, `VERSION   DESCRIPTION   STATUS    APPLIED AT
+1         initial       applied   2025-01-01T12:00:00Z
+2         add_email     pending   -
+
+1 of 2 migrations applied
+`, buf.String())
+	})
+
+	t.Run("adds_the_filename_to_the_wide_table", func(t *testing.T) {
+		buf := captureOutput(t)
+		fakeMigrator := &pgkit.FakeMigrator{
+			ListMigrationsFake: func(db pgkit.DB, dirPath string) ([]pgkit.Migration, error) {
+				return []pgkit.Migration{{Version: 1, Description: "initial", Filename: "001_initial.sql"}}, nil
+			},
+		}
+
+		err := runStatus(&pgkit.FakeDB{}, "aMigrationsDir", fakeMigrator, "wide")
+
+		assert.NoError(t, err)
+		assert.Equal(t, `VERSION   DESCRIPTION   STATUS    APPLIED AT   FILENAME
+1         initial       pending   -            001_initial.sql
+
+0 of 1 migrations applied
+`, buf.String())
 	})
 
 	t.Run("succeeds_when_no_migrations_found", func(t *testing.T) {
@@ -35,7 +60,7 @@ func TestRunStatus(t *testing.T) {
 			},
 		}
 
-		err := runStatus(fakeDB, "aMigrationsDir", fakeMigrator)
+		err := runStatus(fakeDB, "aMigrationsDir", fakeMigrator, outputText)
 
 		assert.NoError(t, err)
 	})
@@ -48,8 +73,55 @@ func TestRunStatus(t *testing.T) {
 			},
 		}
 
-		err := runStatus(fakeDB, "aMigrationsDir", fakeMigrator)
+		err := runStatus(fakeDB, "aMigrationsDir", fakeMigrator, outputText)
 
 		assert.EqualError(t, err, "failed to list migrations: the list error")
 	})
+
+	t.Run("writes_migrations_and_counts_as_json", func(t *testing.T) {
+		buf := captureOutput(t)
+		fakeDB := &pgkit.FakeDB{}
+		time1 := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+		fakeMigrator := &pgkit.FakeMigrator{
+			ListMigrationsFake: func(db pgkit.DB, dirPath string) ([]pgkit.Migration, error) {
+				return []pgkit.Migration{
+					{Version: 1, Description: "initial", Filename: "001_initial.sql", Applied: true, AppliedAt: &time1},
+					{Version: 2, Description: "add_email", Filename: "002_add_email.sql"},
+				}, nil
+			},
+		}
+
+		err := runStatus(fakeDB, "aMigrationsDir", fakeMigrator, outputJSON)
+
+		assert.NoError(t, err)
+		assert.JSONEq(t, `{
+			"migrations": [
+				{"version": 1, "description": "initial", "filename": "001_initial.sql", "applied": true, "applied_at": "2025-01-01T12:00:00Z"},
+				{"version": 2, "description": "add_email", "filename": "002_add_email.sql", "applied": false}
+			],
+			"applied": 1,
+			"pending": 1
+		}`, buf.String())
+	})
+
+	t.Run("writes_migrations_and_counts_as_yaml", func(t *testing.T) {
+		buf := captureOutput(t)
+		fakeMigrator := &pgkit.FakeMigrator{
+			ListMigrationsFake: func(db pgkit.DB, dirPath string) ([]pgkit.Migration, error) {
+				return []pgkit.Migration{{Version: 1, Description: "initial", Filename: "001_initial.sql"}}, nil
+			},
+		}
+
+		err := runStatus(&pgkit.FakeDB{}, "aMigrationsDir", fakeMigrator, outputYAML)
+
+		assert.NoError(t, err)
+		assert.Equal(t, `migrations:
+  - version: 1
+    description: initial
+    filename: 001_initial.sql
+    applied: false
+applied: 0
+pending: 1
+`, buf.String())
+	})
 }
@@ -9,6 +9,13 @@ import (
 
 var buildInfo *versionkit.BuildInfo
 
+// versionOutput is the JSON output of version
+type versionOutput struct {
+	Version   string `json:"version"`
+	GitCommit string `json:"git_commit"`
+	BuildDate string `json:"build_date"`
+}
+
 // SetBuildInfo sets the build information for the version command
 func SetBuildInfo(bi *versionkit.BuildInfo) {
 	buildInfo = bi
@@ -17,26 +24,19 @@ func SetBuildInfo(bi *versionkit.BuildInfo) {
 var versionCmd = &cobra.Command{
 	Use:   "version",
 	Short: "Print version information",
-	Run: func(cmd *cobra.Command, args []string) {
-		// Start with provided build info (may be from ldflags)
-		bi := buildInfo
-		if bi == nil {
-			bi = &versionkit.BuildInfo{}
-		}
-
-		// Merge with runtime build info for any missing values
-		runtimeInfo := versionkit.GetBuildInfo()
-		if bi.Version == "" && runtimeInfo.Version != "" {
-			bi.Version = runtimeInfo.Version
-		}
-		if bi.GitCommit == "" && runtimeInfo.GitCommit != "" {
-			bi.GitCommit = runtimeInfo.GitCommit
-		}
-		if bi.BuildDate == "" && runtimeInfo.BuildDate != "" {
-			bi.BuildDate = runtimeInfo.BuildDate
+	RunE: func(cmd *cobra.Command, args []string) error {
+		bi := buildInfo.WithRuntimeDefaults()
+
+		if isStructured(outputFlags.Output) {
+			return writeValue(outputFlags.Output, versionOutput{
+				Version:   bi.GetBuildVersion(),
+				GitCommit: bi.GetBuildCommit(),
+				BuildDate: bi.GetBuildDate(),
+			})
 		}
 
 		fmt.Printf("pgkit %s\n", bi.String())
+		return nil
 	},
 }
 
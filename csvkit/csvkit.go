@@ -0,0 +1,219 @@
+// Package csvkit reads and writes CSV files as typed records. Columns map to struct fields with `csv`
+// tags, e.g. `csv:"email"`; untagged fields use the field name and `csv:"-"` skips a field. Add
+// ",optional" to a tag to let the column be missing from a file's header.
+package csvkit
+
+import (
+	"encoding"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Option configures Read and Write
+type Option func(*config)
+
+type config struct {
+	comma             rune
+	allowExtraColumns bool
+	timeLayout        string
+}
+
+func newConfig(options []Option) *config {
+	c := &config{comma: ',', timeLayout: time.RFC3339Nano}
+	for _, option := range options {
+		option(c)
+	}
+	return c
+}
+
+// WithComma sets the field delimiter; the default is ','
+func WithComma(comma rune) Option {
+	return func(c *config) {
+		c.comma = comma
+	}
+}
+
+// WithAllowExtraColumns makes Read ignore header columns that don't map to a field, instead of
+// failing
+func WithAllowExtraColumns() Option {
+	return func(c *config) {
+		c.allowExtraColumns = true
+	}
+}
+
+// WithTimeLayout sets the layout used to parse and format time.Time fields; the default is
+// time.RFC3339Nano
+func WithTimeLayout(layout string) Option {
+	return func(c *config) {
+		c.timeLayout = layout
+	}
+}
+
+// RowError is an error reading one row. Row is 1-based and counts the header, so it matches the
+// row number a spreadsheet shows.
+type RowError struct {
+	Row    int
+	Column string
+	Err    error
+}
+
+func (e *RowError) Error() string {
+	if e.Column == "" {
+		return fmt.Sprintf("row %d: %v", e.Row, e.Err)
+	}
+	return fmt.Sprintf("row %d, column %q: %v", e.Row, e.Column, e.Err)
+}
+
+func (e *RowError) Unwrap() error {
+	return e.Err
+}
+
+// column is a struct field mapped to a CSV column
+type column struct {
+	name     string
+	index    []int
+	optional bool
+}
+
+// columnsOf returns the columns of struct type t in field order
+func columnsOf(t reflect.Type) ([]column, error) {
+	if t.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("csvkit records must be structs, got %s", t)
+	}
+
+	var columns []column
+	seen := map[string]bool{}
+	for _, field := range reflect.VisibleFields(t) {
+		if !field.IsExported() || field.Anonymous {
+			continue
+		}
+
+		tag := field.Tag.Get("csv")
+		if tag == "-" {
+			continue
+		}
+		name, flags, _ := strings.Cut(tag, ",")
+		if name == "" {
+			name = field.Name
+		}
+		if seen[name] {
+			return nil, fmt.Errorf("duplicate csv column %q in %s", name, t)
+		}
+		seen[name] = true
+
+		columns = append(columns, column{name: name, index: field.Index, optional: flags == "optional"})
+	}
+
+	return columns, nil
+}
+
+var (
+	textUnmarshalerType = reflect.TypeFor[encoding.TextUnmarshaler]()
+	textMarshalerType   = reflect.TypeFor[encoding.TextMarshaler]()
+	timeType            = reflect.TypeFor[time.Time]()
+	durationType        = reflect.TypeFor[time.Duration]()
+)
+
+// parseValue sets v from text. Empty text leaves v at its zero value, so pointers stay nil.
+func parseValue(v reflect.Value, text string, c *config) error {
+	if text == "" {
+		return nil
+	}
+
+	if v.Kind() == reflect.Pointer {
+		v.Set(reflect.New(v.Type().Elem()))
+		v = v.Elem()
+	}
+
+	if v.Type() != timeType && reflect.PointerTo(v.Type()).Implements(textUnmarshalerType) {
+		return v.Addr().Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(text))
+	}
+
+	switch {
+	case v.Type() == timeType:
+		parsed, err := time.Parse(c.timeLayout, text)
+		if err != nil {
+			return err
+		}
+		v.Set(reflect.ValueOf(parsed))
+		return nil
+	case v.Type() == durationType:
+		parsed, err := time.ParseDuration(text)
+		if err != nil {
+			return err
+		}
+		v.SetInt(int64(parsed))
+		return nil
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(text)
+	case reflect.Bool:
+		parsed, err := strconv.ParseBool(text)
+		if err != nil {
+			return fmt.Errorf("invalid bool %q", text)
+		}
+		v.SetBool(parsed)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		parsed, err := strconv.ParseInt(text, 10, v.Type().Bits())
+		if err != nil {
+			return fmt.Errorf("invalid %s %q", v.Type(), text)
+		}
+		v.SetInt(parsed)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		parsed, err := strconv.ParseUint(text, 10, v.Type().Bits())
+		if err != nil {
+			return fmt.Errorf("invalid %s %q", v.Type(), text)
+		}
+		v.SetUint(parsed)
+	case reflect.Float32, reflect.Float64:
+		parsed, err := strconv.ParseFloat(text, v.Type().Bits())
+		if err != nil {
+			return fmt.Errorf("invalid %s %q", v.Type(), text)
+		}
+		v.SetFloat(parsed)
+	default:
+		return fmt.Errorf("unsupported field type %s", v.Type())
+	}
+
+	return nil
+}
+
+// formatValue returns v as text; nil pointers are empty
+func formatValue(v reflect.Value, c *config) (string, error) {
+	if v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			return "", nil
+		}
+		v = v.Elem()
+	}
+
+	switch {
+	case v.Type() == timeType:
+		return v.Interface().(time.Time).Format(c.timeLayout), nil
+	case v.Type() == durationType:
+		return time.Duration(v.Int()).String(), nil
+	case v.Type().Implements(textMarshalerType):
+		text, err := v.Interface().(encoding.TextMarshaler).MarshalText()
+		return string(text), err
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		return v.String(), nil
+	case reflect.Bool:
+		return strconv.FormatBool(v.Bool()), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(v.Int(), 10), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.FormatUint(v.Uint(), 10), nil
+	case reflect.Float32, reflect.Float64:
+		return strconv.FormatFloat(v.Float(), 'f', -1, v.Type().Bits()), nil
+	}
+
+	return "", fmt.Errorf("unsupported field type %s", v.Type())
+}
@@ -0,0 +1,88 @@
+package csvkit
+
+import (
+	"context"
+	"iter"
+	"reflect"
+)
+
+// Batch groups the records from Read into slices of up to size, for loaders that write in batches,
+// e.g. DynamoDB's BatchWriteItem (25 items) or a Postgres COPY. Errors are passed through unbatched
+// so callers still see each bad row.
+func Batch[T any](records iter.Seq2[T, error], size int) iter.Seq2[[]T, error] {
+	return func(yield func([]T, error) bool) {
+		batch := make([]T, 0, size)
+		for record, err := range records {
+			if err != nil {
+				if !yield(nil, err) {
+					return
+				}
+				continue
+			}
+
+			batch = append(batch, record)
+			if len(batch) >= size {
+				if !yield(batch, nil) {
+					return
+				}
+				batch = make([]T, 0, size)
+			}
+		}
+
+		if len(batch) > 0 {
+			yield(batch, nil)
+		}
+	}
+}
+
+// Load reads batches of up to size records from records and passes each to load, stopping at the
+// first error, and returns how many records were loaded. Use it to pipe Read into a data store, e.g.
+//
+//	loaded, err := csvkit.Load(ctx, csvkit.Read[User](file), 1000, func(ctx context.Context, users []User) error {
+//		_, err := conn.CopyFrom(ctx, pgx.Identifier{"users"}, csvkit.Columns[User](), pgx.CopyFromRows(csvkit.Values(users)))
+//		return err
+//	})
+func Load[T any](ctx context.Context, records iter.Seq2[T, error], size int, load func(ctx context.Context, batch []T) error) (int, error) {
+	loaded := 0
+	for batch, err := range Batch(records, size) {
+		if err != nil {
+			return loaded, err
+		}
+		if err := ctx.Err(); err != nil {
+			return loaded, err
+		}
+		if err := load(ctx, batch); err != nil {
+			return loaded, err
+		}
+		loaded += len(batch)
+	}
+	return loaded, nil
+}
+
+// Columns returns T's column names in field order, matching the values from Values
+func Columns[T any]() []string {
+	columns, err := columnsOf(reflect.TypeFor[T]())
+	if err != nil {
+		return nil
+	}
+	return columnNames(columns)
+}
+
+// Values returns each record's field values in column order, e.g. for pgx.CopyFromRows
+func Values[T any](records []T) [][]any {
+	columns, err := columnsOf(reflect.TypeFor[T]())
+	if err != nil {
+		return nil
+	}
+
+	rows := make([][]any, len(records))
+	for i, record := range records {
+		v := reflect.ValueOf(record)
+		row := make([]any, len(columns))
+		for j, col := range columns {
+			row[j] = v.FieldByIndex(col.index).Interface()
+		}
+		rows[i] = row
+	}
+	return rows
+}
@@ -0,0 +1,116 @@
+package csvkit
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type testRow struct {
+	Name  string `csv:"name"`
+	Count int    `csv:"count"`
+}
+
+func TestBatch(t *testing.T) {
+	t.Run("groups_records_into_batches_of_the_size", func(t *testing.T) {
+		records := Read[testRow](strings.NewReader("name,count\na,1\nb,2\nc,3\n"))
+
+		var batches [][]testRow
+		for batch, err := range Batch(records, 2) {
+			assert.NoError(t, err)
+			batches = append(batches, batch)
+		}
+
+		assert.Equal(t, [][]testRow{{{Name: "a", Count: 1}, {Name: "b", Count: 2}}, {{Name: "c", Count: 3}}}, batches)
+	})
+
+	t.Run("passes_errors_through", func(t *testing.T) {
+		records := Read[testRow](strings.NewReader("name,count\na,1\nb,notAnInt\nc,3\n"))
+
+		var batches [][]testRow
+		var errs []error
+		for batch, err := range Batch(records, 10) {
+			if err != nil {
+				errs = append(errs, err)
+				continue
+			}
+			batches = append(batches, batch)
+		}
+
+		assert.Len(t, errs, 1)
+		assert.EqualError(t, errs[0], `row 3, column "count": invalid int "notAnInt"`)
+		assert.Equal(t, [][]testRow{{{Name: "a", Count: 1}, {Name: "c", Count: 3}}}, batches)
+	})
+}
+
+func TestLoad(t *testing.T) {
+	t.Run("loads_each_batch_and_returns_the_count", func(t *testing.T) {
+		records := Read[testRow](strings.NewReader("name,count\na,1\nb,2\nc,3\n"))
+		var loaded []testRow
+
+		count, err := Load(context.Background(), records, 2, func(ctx context.Context, batch []testRow) error {
+			loaded = append(loaded, batch...)
+			return nil
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, 3, count)
+		assert.Len(t, loaded, 3)
+	})
+
+	t.Run("stops_at_the_first_row_error", func(t *testing.T) {
+		records := Read[testRow](strings.NewReader("name,count\na,1\nb,notAnInt\nc,3\n"))
+		calls := 0
+
+		count, err := Load(context.Background(), records, 1, func(ctx context.Context, batch []testRow) error {
+			calls++
+			return nil
+		})
+
+		assert.Equal(t, 1, count)
+		assert.Equal(t, 1, calls)
+		var rowErr *RowError
+		assert.True(t, errors.As(err, &rowErr))
+		assert.Equal(t, 3, rowErr.Row)
+	})
+
+	t.Run("returns_the_error_from_load", func(t *testing.T) {
+		records := Read[testRow](strings.NewReader("name,count\na,1\nb,2\n"))
+
+		count, err := Load(context.Background(), records, 1, func(ctx context.Context, batch []testRow) error {
+			return errors.New("theLoadError")
+		})
+
+		assert.Equal(t, 0, count)
+		assert.EqualError(t, err, "theLoadError")
+	})
+
+	t.Run("returns_the_context_error_when_canceled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		count, err := Load(ctx, Read[testRow](strings.NewReader("name,count\na,1\n")), 1, func(ctx context.Context, batch []testRow) error {
+			return nil
+		})
+
+		assert.Equal(t, 0, count)
+		assert.ErrorIs(t, err, context.Canceled)
+	})
+}
+
+func TestColumns(t *testing.T) {
+	t.Run("returns_the_column_names_in_field_order", func(t *testing.T) {
+		assert.Equal(t, []string{"name", "count"}, Columns[testRow]())
+	})
+}
+
+func TestValues(t *testing.T) {
+	t.Run("returns_the_field_values_in_column_order", func(t *testing.T) {
+		result := Values([]testRow{{Name: "a", Count: 1}, {Name: "b", Count: 2}})
+
+		assert.Equal(t, [][]any{{"a", 1}, {"b", 2}}, result)
+	})
+}
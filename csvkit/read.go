@@ -0,0 +1,136 @@
+package csvkit
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"iter"
+	"reflect"
+	"strings"
+)
+
+// Read yields a T for each row of the CSV in r, mapping columns by the header in its first row. The
+// header must include every column of T that isn't optional, and no others unless
+// WithAllowExtraColumns is set. A row that can't be converted yields a *RowError and reading
+// continues, so callers can collect bad rows or stop ranging at the first one; header and CSV syntax
+// errors end the sequence.
+func Read[T any](r io.Reader, options ...Option) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		var zero T
+		c := newConfig(options)
+
+		columns, err := columnsOf(reflect.TypeFor[T]())
+		if err != nil {
+			yield(zero, err)
+			return
+		}
+
+		reader := csv.NewReader(r)
+		reader.Comma = c.comma
+		reader.ReuseRecord = true
+
+		header, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			return
+		}
+		if err != nil {
+			yield(zero, fmt.Errorf("failed to read csv header: %w", err))
+			return
+		}
+
+		positions, err := headerPositions(header, columns, c)
+		if err != nil {
+			yield(zero, err)
+			return
+		}
+		// Rows may have a different field count than the header once it's been validated
+		reader.FieldsPerRecord = -1
+
+		for row := 2; ; row++ {
+			record, err := reader.Read()
+			if errors.Is(err, io.EOF) {
+				return
+			}
+			if err != nil {
+				yield(zero, &RowError{Row: row, Err: err})
+				return
+			}
+
+			var item T
+			v := reflect.ValueOf(&item).Elem()
+			rowErr := error(nil)
+			for i, col := range columns {
+				position := positions[i]
+				if position < 0 || position >= len(record) {
+					continue
+				}
+				if err := parseValue(v.FieldByIndex(col.index), record[position], c); err != nil {
+					rowErr = &RowError{Row: row, Column: col.name, Err: err}
+					break
+				}
+			}
+
+			if rowErr != nil {
+				if !yield(zero, rowErr) {
+					return
+				}
+				continue
+			}
+
+			if !yield(item, nil) {
+				return
+			}
+		}
+	}
+}
+
+// headerPositions returns the index in header of each column, or -1 for a missing optional column
+func headerPositions(header []string, columns []column, c *config) ([]int, error) {
+	names := make([]string, len(header))
+	indexes := make(map[string]int, len(header))
+	for i, name := range header {
+		name = strings.TrimSpace(name)
+		if i == 0 {
+			// Spreadsheet exports often start with a UTF-8 byte order mark
+			name = strings.TrimPrefix(name, "\ufeff")
+		}
+		names[i] = name
+		if _, ok := indexes[name]; ok {
+			return nil, fmt.Errorf("duplicate csv header column %q", name)
+		}
+		indexes[name] = i
+	}
+
+	positions := make([]int, len(columns))
+	known := make(map[string]bool, len(columns))
+	var missing []string
+	for i, col := range columns {
+		known[col.name] = true
+		position, ok := indexes[col.name]
+		if !ok {
+			position = -1
+			if !col.optional {
+				missing = append(missing, col.name)
+			}
+		}
+		positions[i] = position
+	}
+	if len(missing) > 0 {
+		return nil, fmt.Errorf("csv header is missing columns: %s", strings.Join(missing, ", "))
+	}
+
+	if !c.allowExtraColumns {
+		var extra []string
+		for _, name := range names {
+			if !known[name] {
+				extra = append(extra, name)
+			}
+		}
+		if len(extra) > 0 {
+			return nil, fmt.Errorf("csv header has unknown columns: %s", strings.Join(extra, ", "))
+		}
+	}
+
+	return positions, nil
+}
@@ -0,0 +1,180 @@
+package csvkit
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/half-ogre/go-kit/kit"
+)
+
+type testUser struct {
+	ID        int         `csv:"id"`
+	Email     string      `csv:"email"`
+	Active    bool        `csv:"active"`
+	Score     float64     `csv:"score"`
+	Nickname  *string     `csv:"nickname,optional"`
+	CreatedAt time.Time   `csv:"created_at"`
+	Balance   kit.Decimal `csv:"balance,optional"`
+	Internal  string      `csv:"-"`
+}
+
+func readAll[T any](t *testing.T, input string, options ...Option) ([]T, []error) {
+	var items []T
+	var errs []error
+	for item, err := range Read[T](strings.NewReader(input), options...) {
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		items = append(items, item)
+	}
+	return items, errs
+}
+
+func TestRead(t *testing.T) {
+	t.Run("reads_rows_into_records_by_header_name", func(t *testing.T) {
+		input := "email,id,active,score,nickname,created_at,balance\n" +
+			"a@example.com,1,true,9.5,ace,2024-01-02T03:04:05Z,12.34\n" +
+			"b@example.com,2,false,0,,2024-02-03T04:05:06Z,\n"
+
+		items, errs := readAll[testUser](t, input)
+
+		assert.Empty(t, errs)
+		nickname := "ace"
+		assert.Equal(t, []testUser{
+			{ID: 1, Email: "a@example.com", Active: true, Score: 9.5, Nickname: &nickname, CreatedAt: time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC), Balance: kit.MustParseDecimal("12.34")},
+			{ID: 2, Email: "b@example.com", CreatedAt: time.Date(2024, 2, 3, 4, 5, 6, 0, time.UTC)},
+		}, items)
+	})
+
+	t.Run("allows_optional_columns_to_be_missing", func(t *testing.T) {
+		items, errs := readAll[testUser](t, "id,email,active,score,created_at\n1,a@example.com,true,1,2024-01-02T03:04:05Z\n")
+
+		assert.Empty(t, errs)
+		assert.Len(t, items, 1)
+		assert.Nil(t, items[0].Nickname)
+	})
+
+	t.Run("strips_a_byte_order_mark_from_the_header", func(t *testing.T) {
+		items, errs := readAll[testUser](t, "\ufeffid,email,active,score,created_at\n1,a@example.com,true,1,\n")
+
+		assert.Empty(t, errs)
+		assert.Equal(t, 1, items[0].ID)
+	})
+
+	t.Run("returns_an_error_when_required_columns_are_missing", func(t *testing.T) {
+		items, errs := readAll[testUser](t, "id,active\n1,true\n")
+
+		assert.Empty(t, items)
+		assert.Len(t, errs, 1)
+		assert.EqualError(t, errs[0], "csv header is missing columns: email, score, created_at")
+	})
+
+	t.Run("returns_an_error_for_unknown_columns", func(t *testing.T) {
+		_, errs := readAll[testUser](t, "id,email,active,score,created_at,aColumn\n")
+
+		assert.Len(t, errs, 1)
+		assert.EqualError(t, errs[0], "csv header has unknown columns: aColumn")
+	})
+
+	t.Run("ignores_unknown_columns_when_allowed", func(t *testing.T) {
+		items, errs := readAll[testUser](t, "id,email,active,score,created_at,aColumn\n1,a@example.com,true,1,,anything\n", WithAllowExtraColumns())
+
+		assert.Empty(t, errs)
+		assert.Len(t, items, 1)
+	})
+
+	t.Run("returns_an_error_for_duplicate_header_columns", func(t *testing.T) {
+		_, errs := readAll[testUser](t, "id,id\n")
+
+		assert.EqualError(t, errs[0], `duplicate csv header column "id"`)
+	})
+
+	t.Run("yields_row_errors_and_keeps_reading", func(t *testing.T) {
+		input := "id,email,active,score,created_at\n" +
+			"1,a@example.com,true,1,\n" +
+			"notAnInt,b@example.com,true,1,\n" +
+			"3,c@example.com,maybe,1,\n" +
+			"4,d@example.com,false,1,\n"
+
+		items, errs := readAll[testUser](t, input)
+
+		assert.Len(t, items, 2)
+		assert.Equal(t, 4, items[1].ID)
+		assert.Len(t, errs, 2)
+		assert.EqualError(t, errs[0], `row 3, column "id": invalid int "notAnInt"`)
+		assert.EqualError(t, errs[1], `row 4, column "active": invalid bool "maybe"`)
+		var rowErr *RowError
+		assert.True(t, errors.As(errs[0], &rowErr))
+		assert.Equal(t, 3, rowErr.Row)
+	})
+
+	t.Run("stops_at_csv_syntax_errors", func(t *testing.T) {
+		items, errs := readAll[testUser](t, "id,email,active,score,created_at\n1,\"a@example.com,true,1,\n")
+
+		assert.Empty(t, items)
+		assert.Len(t, errs, 1)
+		assert.ErrorContains(t, errs[0], "row 2: ")
+	})
+
+	t.Run("stops_when_the_caller_stops_ranging", func(t *testing.T) {
+		count := 0
+		for range Read[testUser](strings.NewReader("id,email,active,score,created_at\n1,a,true,1,\n2,b,true,1,\n")) {
+			count++
+			break
+		}
+
+		assert.Equal(t, 1, count)
+	})
+
+	t.Run("yields_nothing_for_empty_input", func(t *testing.T) {
+		items, errs := readAll[testUser](t, "")
+
+		assert.Empty(t, items)
+		assert.Empty(t, errs)
+	})
+
+	t.Run("uses_the_configured_comma_and_time_layout", func(t *testing.T) {
+		type event struct {
+			Name string    `csv:"name"`
+			On   time.Time `csv:"on"`
+		}
+
+		items, errs := readAll[event](t, "name;on\naName;2024-05-06\n", WithComma(';'), WithTimeLayout(time.DateOnly))
+
+		assert.Empty(t, errs)
+		assert.Equal(t, []event{{Name: "aName", On: time.Date(2024, 5, 6, 0, 0, 0, 0, time.UTC)}}, items)
+	})
+
+	t.Run("uses_field_names_for_untagged_fields", func(t *testing.T) {
+		type record struct {
+			Name    string
+			Timeout time.Duration
+		}
+
+		items, errs := readAll[record](t, "Name,Timeout\naName,1m30s\n")
+
+		assert.Empty(t, errs)
+		assert.Equal(t, []record{{Name: "aName", Timeout: 90 * time.Second}}, items)
+	})
+
+	t.Run("returns_an_error_for_unsupported_field_types", func(t *testing.T) {
+		type record struct {
+			Tags []string `csv:"tags"`
+		}
+
+		_, errs := readAll[record](t, "tags\na\n")
+
+		assert.EqualError(t, errs[0], `row 2, column "tags": unsupported field type []string`)
+	})
+
+	t.Run("returns_an_error_when_the_record_is_not_a_struct", func(t *testing.T) {
+		_, errs := readAll[string](t, "a\n")
+
+		assert.EqualError(t, errs[0], "csvkit records must be structs, got string")
+	})
+}
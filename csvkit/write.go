@@ -0,0 +1,60 @@
+package csvkit
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"iter"
+	"reflect"
+)
+
+// Write writes a header row for T and then a row for each record, e.g.
+// Write(w, slices.Values(users)). Records are written as they're yielded, so exports can stream from
+// a query without holding every row.
+func Write[T any](w io.Writer, records iter.Seq[T], options ...Option) error {
+	c := newConfig(options)
+
+	columns, err := columnsOf(reflect.TypeFor[T]())
+	if err != nil {
+		return err
+	}
+
+	writer := csv.NewWriter(w)
+	writer.Comma = c.comma
+
+	if err := writer.Write(columnNames(columns)); err != nil {
+		return fmt.Errorf("failed to write csv header: %w", err)
+	}
+
+	row := 1
+	fields := make([]string, len(columns))
+	for record := range records {
+		row++
+		v := reflect.ValueOf(record)
+		for i, col := range columns {
+			text, err := formatValue(v.FieldByIndex(col.index), c)
+			if err != nil {
+				return &RowError{Row: row, Column: col.name, Err: err}
+			}
+			fields[i] = text
+		}
+		if err := writer.Write(fields); err != nil {
+			return &RowError{Row: row, Err: err}
+		}
+	}
+
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		return fmt.Errorf("failed to write csv: %w", err)
+	}
+
+	return nil
+}
+
+func columnNames(columns []column) []string {
+	names := make([]string, len(columns))
+	for i, col := range columns {
+		names[i] = col.name
+	}
+	return names
+}
@@ -0,0 +1,90 @@
+package csvkit
+
+import (
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/half-ogre/go-kit/kit"
+)
+
+func TestWrite(t *testing.T) {
+	t.Run("writes_a_header_and_a_row_for_each_record", func(t *testing.T) {
+		nickname := "ace"
+		users := []testUser{
+			{ID: 1, Email: "a@example.com", Active: true, Score: 9.5, Nickname: &nickname, CreatedAt: time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC), Balance: kit.MustParseDecimal("12.34"), Internal: "aSecret"},
+			{ID: 2, Email: "b,c@example.com"},
+		}
+		var output strings.Builder
+
+		err := Write(&output, slices.Values(users))
+
+		assert.NoError(t, err)
+		assert.Equal(t, "id,email,active,score,nickname,created_at,balance\n"+
+			"1,a@example.com,true,9.5,ace,2024-01-02T03:04:05Z,12.34\n"+
+			"2,\"b,c@example.com\",false,0,,0001-01-01T00:00:00Z,0\n", output.String())
+	})
+
+	t.Run("round_trips_through_read", func(t *testing.T) {
+		nickname := "ace"
+		users := []testUser{
+			{ID: 1, Email: "a@example.com", Active: true, Score: 9.5, Nickname: &nickname, CreatedAt: time.Date(2024, 1, 2, 3, 4, 5, 6, time.UTC), Balance: kit.MustParseDecimal("12.34")},
+		}
+		var output strings.Builder
+		assert.NoError(t, Write(&output, slices.Values(users)))
+
+		items, errs := readAll[testUser](t, output.String())
+
+		assert.Empty(t, errs)
+		assert.Equal(t, users, items)
+	})
+
+	t.Run("writes_only_a_header_when_there_are_no_records", func(t *testing.T) {
+		var output strings.Builder
+
+		err := Write(&output, slices.Values([]testUser{}))
+
+		assert.NoError(t, err)
+		assert.Equal(t, "id,email,active,score,nickname,created_at,balance\n", output.String())
+	})
+
+	t.Run("uses_the_configured_comma_and_time_layout", func(t *testing.T) {
+		type event struct {
+			Name    string        `csv:"name"`
+			On      time.Time     `csv:"on"`
+			Timeout time.Duration `csv:"timeout"`
+		}
+		var output strings.Builder
+
+		err := Write(&output, slices.Values([]event{{Name: "aName", On: time.Date(2024, 5, 6, 0, 0, 0, 0, time.UTC), Timeout: time.Minute}}), WithComma(';'), WithTimeLayout(time.DateOnly))
+
+		assert.NoError(t, err)
+		assert.Equal(t, "name;on;timeout\naName;2024-05-06;1m0s\n", output.String())
+	})
+
+	t.Run("returns_a_row_error_for_unsupported_field_types", func(t *testing.T) {
+		type record struct {
+			Tags []string `csv:"tags"`
+		}
+		var output strings.Builder
+
+		err := Write(&output, slices.Values([]record{{Tags: []string{"a"}}}))
+
+		assert.EqualError(t, err, `row 2, column "tags": unsupported field type []string`)
+	})
+
+	t.Run("returns_an_error_for_duplicate_columns", func(t *testing.T) {
+		type record struct {
+			A string `csv:"name"`
+			B string `csv:"name"`
+		}
+		var output strings.Builder
+
+		err := Write(&output, slices.Values([]record{}))
+
+		assert.ErrorContains(t, err, `duplicate csv column "name"`)
+	})
+}
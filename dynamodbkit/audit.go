@@ -0,0 +1,498 @@
+package dynamodbkit
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/half-ogre/go-kit/kit"
+	"github.com/half-ogre/go-kit/logkit/logfields"
+)
+
+// UseAuditing turns on change auditing. While on, every PutItem, UpdateItem, and DeleteItem also writes
+// an audit record to auditTableName in the same TransactWriteItems call, so a change is never made without its
+// record. Pass an empty table name to turn auditing off.
+//
+// The audit table's partition key is "item" (the audited table and key, e.g. "users#id=123") and its
+// sort key is "timestamp", so an item's history can be read with Query. Each record also has "table",
+// "action" (PUT, UPDATE, or DELETE), "actor" (see WithAuditActor), and "before" and "after" map attributes holding
+// the item's images. BatchPutItems and BatchDeleteItems write items one at a time while it's on.
+//
+// The before image is read with a consistent GetItem just ahead of the transaction, so the write is
+// guarded by a numeric version attribute (see WithAuditVersionAttribute): it only succeeds if the item
+// is still at the version read, and it increments the version. When another writer gets in between, the
+// item the failed check returns becomes the before image and the write is tried again. Writers that
+// don't go through dynamodbkit must increment the version too, or their changes can be missed.
+//
+// A transaction can't return the updated item, so an UPDATE record's after image is added once the
+// transaction commits, from a consistent read at the version it wrote. If another write lands first, the
+// record keeps only its before image; the next record's before image is this update's after image.
+func UseAuditing(auditTableName string, options ...AuditOption) {
+	updateDefaultClient(WithClientAuditing(auditTableName, options...))
+}
+
+func newAuditConfig(auditTableName string, options []AuditOption) *auditConfig {
+	if auditTableName == "" {
+		return nil
+	}
+
+	config := &auditConfig{
+		tableName:        auditTableName,
+		clock:            kit.NewClock(),
+		versionAttribute: "version",
+		maxConflicts:     3,
+		keyNames:         map[string][]string{},
+	}
+	for _, option := range options {
+		option(config)
+	}
+	return config
+}
+
+type auditConfig struct {
+	tableName        string
+	clock            kit.ClockInterface
+	versionAttribute string
+	maxConflicts     int
+
+	// keyNames caches each audited table's key attribute names for as long as the client is used
+	keyNamesMu sync.Mutex
+	keyNames   map[string][]string
+}
+
+type AuditOption func(*auditConfig)
+
+// WithAuditClock sets the clock used to timestamp audit records
+func WithAuditClock(clock kit.ClockInterface) AuditOption {
+	return func(c *auditConfig) {
+		c.clock = clock
+	}
+}
+
+// WithAuditVersionAttribute sets the numeric attribute audited writes check and increment; the default
+// is "version", the same attribute RunItemMigration uses, so the two don't overwrite each other's
+// changes. Updates must not set it themselves.
+func WithAuditVersionAttribute(name string) AuditOption {
+	return func(c *auditConfig) {
+		c.versionAttribute = name
+	}
+}
+
+// WithAuditMaxConflicts sets how many times an audited write is tried again after another writer
+// changes the item between the read of its before image and the transaction; the default is 3
+func WithAuditMaxConflicts(maxConflicts int) AuditOption {
+	return func(c *auditConfig) {
+		c.maxConflicts = maxConflicts
+	}
+}
+
+type auditActorKey struct{}
+
+// WithAuditActor returns a copy of ctx carrying the actor (e.g. a user ID) recorded on audit records
+func WithAuditActor(ctx context.Context, actor string) context.Context {
+	return context.WithValue(ctx, auditActorKey{}, actor)
+}
+
+// AuditActor returns the actor set with WithAuditActor, or "" if there isn't one
+func AuditActor(ctx context.Context) string {
+	actor, _ := ctx.Value(auditActorKey{}).(string)
+	return actor
+}
+
+// auditGuard holds the condition, names, and values that make an audited write succeed only if the item
+// is still at the version its before image was read at
+type auditGuard struct {
+	version   int
+	condition string
+	names     map[string]string
+	values    map[string]types.AttributeValue
+}
+
+func newAuditGuard(versionAttribute string, version int) auditGuard {
+	guard := auditGuard{
+		version: version,
+		names:   map[string]string{"#auditVersion": versionAttribute},
+		values:  map[string]types.AttributeValue{},
+	}
+	if version == 0 {
+		guard.condition = "attribute_not_exists(#auditVersion)"
+	} else {
+		guard.condition = "#auditVersion = :auditVersion"
+		guard.values[":auditVersion"] = &types.AttributeValueMemberN{Value: strconv.Itoa(version)}
+	}
+	return guard
+}
+
+func (g auditGuard) nextVersion() types.AttributeValue {
+	return &types.AttributeValueMemberN{Value: strconv.Itoa(g.version + 1)}
+}
+
+// apply ANDs the guard's condition with condition and merges its names and values into copies of names
+// and values, leaving the caller's input unchanged for a retry
+func (g auditGuard) apply(condition *string, names map[string]string, values map[string]types.AttributeValue) (*string, map[string]string, map[string]types.AttributeValue) {
+	mergedCondition := g.condition
+	if condition != nil {
+		mergedCondition = fmt.Sprintf("(%s) AND (%s)", *condition, g.condition)
+	}
+
+	mergedNames := make(map[string]string, len(names)+len(g.names))
+	for name, attribute := range names {
+		mergedNames[name] = attribute
+	}
+	for name, attribute := range g.names {
+		mergedNames[name] = attribute
+	}
+
+	var mergedValues map[string]types.AttributeValue
+	if len(values) > 0 || len(g.values) > 0 {
+		mergedValues = make(map[string]types.AttributeValue, len(values)+len(g.values))
+		for name, value := range values {
+			mergedValues[name] = value
+		}
+		for name, value := range g.values {
+			mergedValues[name] = value
+		}
+	}
+
+	return aws.String(mergedCondition), mergedNames, mergedValues
+}
+
+// auditedWrite builds the write of one attempt from the guard for the version read and returns it with
+// its after image, if it has one
+type auditedWrite func(before map[string]types.AttributeValue, guard auditGuard) (types.TransactWriteItem, map[string]types.AttributeValue)
+
+// writeWithAudit reads the before image and writes the change guarded by its version along with its
+// audit record, trying again with the current item when another writer changed it in between. It
+// returns the before image and the audit record of the attempt that committed.
+func writeWithAudit(ctx context.Context, db DynamoDB, config *auditConfig, tableName string, action string, key map[string]types.AttributeValue, write auditedWrite) (map[string]types.AttributeValue, *types.Put, auditGuard, error) {
+	transactor, err := requireAPI[TransactWriteItemsAPI](db, "TransactWriteItems")
+	if err != nil {
+		return nil, nil, auditGuard{}, err
+	}
+
+	before, err := getAuditBeforeImage(ctx, db, tableName, key)
+	if err != nil {
+		return nil, nil, auditGuard{}, err
+	}
+
+	for conflicts := 0; ; conflicts++ {
+		version, err := auditVersion(before, config.versionAttribute)
+		if err != nil {
+			return nil, nil, auditGuard{}, err
+		}
+		guard := newAuditGuard(config.versionAttribute, version)
+
+		writeItem, after := write(before, guard)
+		record, err := newAuditRecordPut(ctx, config, tableName, action, key, before, after)
+		if err != nil {
+			return nil, nil, auditGuard{}, err
+		}
+
+		_, err = transactor.TransactWriteItems(ctx, &dynamodb.TransactWriteItemsInput{
+			TransactItems: []types.TransactWriteItem{writeItem, {Put: record}},
+		})
+		if err == nil {
+			return before, record, guard, nil
+		}
+
+		current, conflict := auditConflict(err, before, config.versionAttribute)
+		if !conflict {
+			return nil, nil, auditGuard{}, err
+		}
+		if conflicts >= config.maxConflicts {
+			return nil, nil, auditGuard{}, fmt.Errorf("item or its audit record changed by another writer %d times", conflicts+1)
+		}
+
+		logger.DebugContext(ctx, "retrying audited write after a conflict", logfields.Table(tableName), "attempt", conflicts+2)
+		before = current
+	}
+}
+
+// auditConflict reports whether err is from another writer getting in first, rather than from the
+// write's own condition, and if so returns the item as it is now. The record's check failing means
+// another record has its timestamp, so the attempt is simply made again.
+func auditConflict(err error, before map[string]types.AttributeValue, versionAttribute string) (map[string]types.AttributeValue, bool) {
+	var canceledErr *types.TransactionCanceledException
+	if !errors.As(err, &canceledErr) || len(canceledErr.CancellationReasons) < 2 {
+		return nil, false
+	}
+
+	if aws.ToString(canceledErr.CancellationReasons[1].Code) == "ConditionalCheckFailed" {
+		return before, true
+	}
+
+	if aws.ToString(canceledErr.CancellationReasons[0].Code) != "ConditionalCheckFailed" {
+		return nil, false
+	}
+
+	current := canceledErr.CancellationReasons[0].Item
+	if len(current) == 0 {
+		current = nil
+	}
+	beforeVersion, _ := auditVersion(before, versionAttribute)
+	currentVersion, _ := auditVersion(current, versionAttribute)
+	if (before == nil) == (current == nil) && beforeVersion == currentVersion {
+		return nil, false
+	}
+
+	return current, true
+}
+
+func auditVersion(item map[string]types.AttributeValue, versionAttribute string) (int, error) {
+	value, ok := item[versionAttribute].(*types.AttributeValueMemberN)
+	if !ok {
+		return 0, nil
+	}
+
+	version, err := strconv.Atoi(value.Value)
+	if err != nil {
+		return 0, kit.WrapError(err, "error parsing version attribute %s", versionAttribute)
+	}
+	return version, nil
+}
+
+func putItemWithAudit(ctx context.Context, db DynamoDB, config *auditConfig, input *dynamodb.PutItemInput) error {
+	keyNames, err := getTableKeyNames(ctx, db, config, *input.TableName)
+	if err != nil {
+		return err
+	}
+
+	key := make(map[string]types.AttributeValue, len(keyNames))
+	for _, name := range keyNames {
+		value, ok := input.Item[name]
+		if !ok {
+			return fmt.Errorf("item is missing key attribute %s", name)
+		}
+		key[name] = value
+	}
+
+	_, _, _, err = writeWithAudit(ctx, db, config, *input.TableName, "PUT", key, func(before map[string]types.AttributeValue, guard auditGuard) (types.TransactWriteItem, map[string]types.AttributeValue) {
+		item := make(map[string]types.AttributeValue, len(input.Item)+1)
+		for name, value := range input.Item {
+			item[name] = value
+		}
+		item[config.versionAttribute] = guard.nextVersion()
+
+		condition, names, values := guard.apply(input.ConditionExpression, input.ExpressionAttributeNames, input.ExpressionAttributeValues)
+		return types.TransactWriteItem{Put: &types.Put{
+			TableName:                           input.TableName,
+			Item:                                item,
+			ConditionExpression:                 condition,
+			ExpressionAttributeNames:            names,
+			ExpressionAttributeValues:           values,
+			ReturnValuesOnConditionCheckFailure: types.ReturnValuesOnConditionCheckFailureAllOld,
+		}}, item
+	})
+	if err != nil {
+		return kit.WrapError(err, "error writing audited put")
+	}
+
+	return nil
+}
+
+// deleteItemWithAudit deletes an item and writes its audit record in one transaction. The item returned
+// is the before image for ReturnValues of ALL_OLD.
+func deleteItemWithAudit(ctx context.Context, db DynamoDB, config *auditConfig, input *dynamodb.DeleteItemInput) (map[string]types.AttributeValue, error) {
+	before, _, _, err := writeWithAudit(ctx, db, config, *input.TableName, "DELETE", input.Key, func(before map[string]types.AttributeValue, guard auditGuard) (types.TransactWriteItem, map[string]types.AttributeValue) {
+		condition, names, values := guard.apply(input.ConditionExpression, input.ExpressionAttributeNames, input.ExpressionAttributeValues)
+		return types.TransactWriteItem{Delete: &types.Delete{
+			TableName:                           input.TableName,
+			Key:                                 input.Key,
+			ConditionExpression:                 condition,
+			ExpressionAttributeNames:            names,
+			ExpressionAttributeValues:           values,
+			ReturnValuesOnConditionCheckFailure: types.ReturnValuesOnConditionCheckFailureAllOld,
+		}}, nil
+	})
+	if err != nil {
+		return nil, kit.WrapError(err, "error writing audited delete")
+	}
+
+	if input.ReturnValues == types.ReturnValueAllOld {
+		return before, nil
+	}
+	return nil, nil
+}
+
+var updateSetClause = regexp.MustCompile(`(?i)(^|\s)SET\s`)
+
+// updateItemWithAudit updates an item and writes its audit record in one transaction, then adds the
+// after image to the record. The item returned is the before image for ReturnValues of ALL_OLD or
+// UPDATED_OLD; otherwise it's read after the transaction, so it's a later write's item if one landed
+// in between.
+func updateItemWithAudit(ctx context.Context, db DynamoDB, config *auditConfig, input *dynamodb.UpdateItemInput) (map[string]types.AttributeValue, error) {
+	before, record, guard, err := writeWithAudit(ctx, db, config, *input.TableName, "UPDATE", input.Key, func(before map[string]types.AttributeValue, guard auditGuard) (types.TransactWriteItem, map[string]types.AttributeValue) {
+		condition, names, values := guard.apply(input.ConditionExpression, input.ExpressionAttributeNames, input.ExpressionAttributeValues)
+		if values == nil {
+			values = map[string]types.AttributeValue{}
+		}
+		values[":auditNextVersion"] = guard.nextVersion()
+
+		setVersion := "#auditVersion = :auditNextVersion"
+		updateExpression := aws.ToString(input.UpdateExpression)
+		if loc := updateSetClause.FindStringIndex(updateExpression); loc != nil {
+			updateExpression = updateExpression[:loc[1]] + setVersion + ", " + updateExpression[loc[1]:]
+		} else {
+			updateExpression = "SET " + setVersion + " " + updateExpression
+		}
+
+		return types.TransactWriteItem{Update: &types.Update{
+			TableName:                           input.TableName,
+			Key:                                 input.Key,
+			UpdateExpression:                    aws.String(updateExpression),
+			ConditionExpression:                 condition,
+			ExpressionAttributeNames:            names,
+			ExpressionAttributeValues:           values,
+			ReturnValuesOnConditionCheckFailure: types.ReturnValuesOnConditionCheckFailureAllOld,
+		}}, nil
+	})
+	if err != nil {
+		return nil, kit.WrapError(err, "error writing audited update")
+	}
+
+	output, err := db.GetItem(ctx, &dynamodb.GetItemInput{
+		TableName:      input.TableName,
+		Key:            input.Key,
+		ConsistentRead: aws.Bool(true),
+	})
+	if err != nil {
+		return nil, kit.WrapError(err, "error reading updated item")
+	}
+
+	if version, _ := auditVersion(output.Item, config.versionAttribute); version == guard.version+1 {
+		addAuditAfterImage(ctx, db, record, output.Item)
+	}
+
+	switch input.ReturnValues {
+	case types.ReturnValueNone:
+		return nil, nil
+	case types.ReturnValueAllOld, types.ReturnValueUpdatedOld:
+		return before, nil
+	}
+
+	return output.Item, nil
+}
+
+// addAuditAfterImage adds an update's after image to its committed audit record. The update has already
+// happened, so a failure is logged rather than returned.
+func addAuditAfterImage(ctx context.Context, db DynamoDB, record *types.Put, after map[string]types.AttributeValue) {
+	updater, err := requireAPI[UpdateItemAPI](db, "UpdateItem")
+	if err == nil {
+		_, err = updater.UpdateItem(ctx, &dynamodb.UpdateItemInput{
+			TableName:                 record.TableName,
+			Key:                       map[string]types.AttributeValue{"item": record.Item["item"], "timestamp": record.Item["timestamp"]},
+			UpdateExpression:          aws.String("SET #after = :after"),
+			ConditionExpression:       aws.String("attribute_exists(#item)"),
+			ExpressionAttributeNames:  map[string]string{"#after": "after", "#item": "item"},
+			ExpressionAttributeValues: map[string]types.AttributeValue{":after": &types.AttributeValueMemberM{Value: after}},
+		})
+	}
+	if err != nil {
+		logger.WarnContext(ctx, "failed to add the after image to an audit record", logfields.Table(aws.ToString(record.TableName)), "error", err)
+	}
+}
+
+func getAuditBeforeImage(ctx context.Context, db DynamoDB, tableName string, key map[string]types.AttributeValue) (map[string]types.AttributeValue, error) {
+	output, err := db.GetItem(ctx, &dynamodb.GetItemInput{
+		TableName:      aws.String(tableName),
+		Key:            key,
+		ConsistentRead: aws.Bool(true),
+	})
+	if err != nil {
+		return nil, kit.WrapError(err, "error reading before image")
+	}
+
+	return output.Item, nil
+}
+
+// newAuditRecordPut builds the put of an audit record, conditional on there being no record with its
+// key yet, so two changes to an item in the same clock tick can't overwrite each other's records
+func newAuditRecordPut(ctx context.Context, config *auditConfig, tableName string, action string, key map[string]types.AttributeValue, before map[string]types.AttributeValue, after map[string]types.AttributeValue) (*types.Put, error) {
+	timestamp, err := SortKeyFromTime(config.clock.Now(), TimePrecisionNanosecond)
+	if err != nil {
+		return nil, err
+	}
+
+	record := map[string]types.AttributeValue{
+		"item":      &types.AttributeValueMemberS{Value: fmt.Sprintf("%s#%s", tableName, encodeAuditKey(key))},
+		"timestamp": &types.AttributeValueMemberS{Value: timestamp},
+		"table":     &types.AttributeValueMemberS{Value: tableName},
+		"action":    &types.AttributeValueMemberS{Value: action},
+	}
+	if actor := AuditActor(ctx); actor != "" {
+		record["actor"] = &types.AttributeValueMemberS{Value: actor}
+	}
+	if before != nil {
+		record["before"] = &types.AttributeValueMemberM{Value: before}
+	}
+	if after != nil {
+		record["after"] = &types.AttributeValueMemberM{Value: after}
+	}
+
+	auditTableName := getClient(ctx).TableName(config.tableName)
+
+	return &types.Put{
+		TableName:                aws.String(auditTableName),
+		Item:                     record,
+		ConditionExpression:      aws.String("attribute_not_exists(#item)"),
+		ExpressionAttributeNames: map[string]string{"#item": "item"},
+	}, nil
+}
+
+// encodeAuditKey renders a key as name=value pairs sorted by name, e.g. "id=123,timestamp=2024-01-01"
+func encodeAuditKey(key map[string]types.AttributeValue) string {
+	names := make([]string, 0, len(key))
+	for name := range key {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	pairs := make([]string, 0, len(names))
+	for _, name := range names {
+		var value string
+		switch v := key[name].(type) {
+		case *types.AttributeValueMemberS:
+			value = v.Value
+		case *types.AttributeValueMemberN:
+			value = v.Value
+		case *types.AttributeValueMemberB:
+			value = base64.StdEncoding.EncodeToString(v.Value)
+		default:
+			value = fmt.Sprintf("%v", v)
+		}
+		pairs = append(pairs, fmt.Sprintf("%s=%s", name, value))
+	}
+
+	return strings.Join(pairs, ",")
+}
+
+// getTableKeyNames returns the key attribute names of a table, described once per UseAuditing call and
+// then remembered
+func getTableKeyNames(ctx context.Context, db DynamoDB, config *auditConfig, tableName string) ([]string, error) {
+	config.keyNamesMu.Lock()
+	keyNames, ok := config.keyNames[tableName]
+	config.keyNamesMu.Unlock()
+	if ok {
+		return keyNames, nil
+	}
+
+	keyNames, err := describeKeyNames(ctx, db, tableName)
+	if err != nil {
+		return nil, err
+	}
+
+	config.keyNamesMu.Lock()
+	config.keyNames[tableName] = keyNames
+	config.keyNamesMu.Unlock()
+
+	return keyNames, nil
+}
@@ -0,0 +1,484 @@
+package dynamodbkit
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/half-ogre/go-kit/kit"
+	"github.com/stretchr/testify/assert"
+)
+
+func useTestAuditing(t *testing.T) {
+	now := time.Date(2025, 1, 2, 3, 4, 5, 6, time.UTC)
+	UseAuditing("theAuditTable", WithAuditClock(kit.NewClock(kit.WithFake(func() time.Time { return now }))))
+	t.Cleanup(func() { UseAuditing("") })
+}
+
+func describeTableWithKeys(keyNames ...string) func(ctx context.Context, params *dynamodb.DescribeTableInput, optFns ...func(*dynamodb.Options)) (*dynamodb.DescribeTableOutput, error) {
+	return func(ctx context.Context, params *dynamodb.DescribeTableInput, optFns ...func(*dynamodb.Options)) (*dynamodb.DescribeTableOutput, error) {
+		keySchema := make([]types.KeySchemaElement, 0, len(keyNames))
+		for _, name := range keyNames {
+			keySchema = append(keySchema, types.KeySchemaElement{AttributeName: aws.String(name)})
+		}
+		return &dynamodb.DescribeTableOutput{Table: &types.TableDescription{KeySchema: keySchema}}, nil
+	}
+}
+
+func TestAuditActor(t *testing.T) {
+	t.Run("returns_the_actor_from_the_context", func(t *testing.T) {
+		ctx := WithAuditActor(context.Background(), "theActor")
+
+		assert.Equal(t, "theActor", AuditActor(ctx))
+	})
+
+	t.Run("returns_empty_when_there_is_no_actor", func(t *testing.T) {
+		assert.Equal(t, "", AuditActor(context.Background()))
+	})
+}
+
+func TestPutItemWithAuditing(t *testing.T) {
+	t.Run("writes_the_item_and_its_audit_record_in_one_transaction", func(t *testing.T) {
+		useTestAuditing(t)
+		before := mustMarshalMap(t, TestUser{ID: "theID", Name: "theOldName"})
+		var actualInput *dynamodb.TransactWriteItemsInput
+		fakeDB := &FakeDynamoDB{
+			DescribeTableFake: describeTableWithKeys("id"),
+			GetItemFake: func(ctx context.Context, params *dynamodb.GetItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.GetItemOutput, error) {
+				assert.True(t, aws.ToBool(params.ConsistentRead))
+				return &dynamodb.GetItemOutput{Item: before}, nil
+			},
+			TransactWriteItemsFake: func(ctx context.Context, params *dynamodb.TransactWriteItemsInput, optFns ...func(*dynamodb.Options)) (*dynamodb.TransactWriteItemsOutput, error) {
+				actualInput = params
+				return &dynamodb.TransactWriteItemsOutput{}, nil
+			},
+		}
+		setFake(func(ctx context.Context) (DynamoDB, error) { return fakeDB, nil })
+		t.Cleanup(func() { setFake(nil) })
+		ctx := WithAuditActor(context.Background(), "theActor")
+
+		err := PutItem(ctx, "anAuditedPutTable", TestUser{ID: "theID", Name: "theNewName"}, WithPutItemCondition("attribute_exists(id)"))
+
+		assert.NoError(t, err)
+		assert.Len(t, actualInput.TransactItems, 2)
+		put := actualInput.TransactItems[0].Put
+		assert.Equal(t, "anAuditedPutTable", *put.TableName)
+		expectedItem := mustMarshalMap(t, TestUser{ID: "theID", Name: "theNewName"})
+		expectedItem["version"] = &types.AttributeValueMemberN{Value: "1"}
+		assert.Equal(t, expectedItem, put.Item)
+		assert.Equal(t, "(attribute_exists(id)) AND (attribute_not_exists(#auditVersion))", *put.ConditionExpression)
+		assert.Equal(t, map[string]string{"#auditVersion": "version"}, put.ExpressionAttributeNames)
+		assert.Equal(t, types.ReturnValuesOnConditionCheckFailureAllOld, put.ReturnValuesOnConditionCheckFailure)
+		record := actualInput.TransactItems[1].Put
+		assert.Equal(t, "theAuditTable", *record.TableName)
+		assert.Equal(t, "attribute_not_exists(#item)", *record.ConditionExpression)
+		assert.Equal(t, map[string]string{"#item": "item"}, record.ExpressionAttributeNames)
+		assert.Equal(t, map[string]types.AttributeValue{
+			"item":      &types.AttributeValueMemberS{Value: "anAuditedPutTable#id=theID"},
+			"timestamp": &types.AttributeValueMemberS{Value: "2025-01-02T03:04:05.000000006Z"},
+			"table":     &types.AttributeValueMemberS{Value: "anAuditedPutTable"},
+			"action":    &types.AttributeValueMemberS{Value: "PUT"},
+			"actor":     &types.AttributeValueMemberS{Value: "theActor"},
+			"before":    &types.AttributeValueMemberM{Value: before},
+			"after":     &types.AttributeValueMemberM{Value: put.Item},
+		}, record.Item)
+	})
+
+	t.Run("omits_the_before_image_of_a_new_item", func(t *testing.T) {
+		useTestAuditing(t)
+		var actualInput *dynamodb.TransactWriteItemsInput
+		fakeDB := &FakeDynamoDB{
+			DescribeTableFake: describeTableWithKeys("id"),
+			GetItemFake: func(ctx context.Context, params *dynamodb.GetItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.GetItemOutput, error) {
+				return &dynamodb.GetItemOutput{}, nil
+			},
+			TransactWriteItemsFake: func(ctx context.Context, params *dynamodb.TransactWriteItemsInput, optFns ...func(*dynamodb.Options)) (*dynamodb.TransactWriteItemsOutput, error) {
+				actualInput = params
+				return &dynamodb.TransactWriteItemsOutput{}, nil
+			},
+		}
+		setFake(func(ctx context.Context) (DynamoDB, error) { return fakeDB, nil })
+		t.Cleanup(func() { setFake(nil) })
+
+		err := PutItem(context.Background(), "anAuditedNewItemTable", TestUser{ID: "theID"})
+
+		assert.NoError(t, err)
+		record := actualInput.TransactItems[1].Put.Item
+		assert.NotContains(t, record, "before")
+		assert.NotContains(t, record, "actor")
+		assert.Contains(t, record, "after")
+	})
+
+	t.Run("encodes_every_key_attribute_in_the_audit_item", func(t *testing.T) {
+		useTestAuditing(t)
+		var actualInput *dynamodb.TransactWriteItemsInput
+		fakeDB := &FakeDynamoDB{
+			DescribeTableFake: describeTableWithKeys("user_id", "timestamp"),
+			GetItemFake: func(ctx context.Context, params *dynamodb.GetItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.GetItemOutput, error) {
+				assert.Len(t, params.Key, 2)
+				return &dynamodb.GetItemOutput{}, nil
+			},
+			TransactWriteItemsFake: func(ctx context.Context, params *dynamodb.TransactWriteItemsInput, optFns ...func(*dynamodb.Options)) (*dynamodb.TransactWriteItemsOutput, error) {
+				actualInput = params
+				return &dynamodb.TransactWriteItemsOutput{}, nil
+			},
+		}
+		setFake(func(ctx context.Context) (DynamoDB, error) { return fakeDB, nil })
+		t.Cleanup(func() { setFake(nil) })
+
+		err := PutItem(context.Background(), "anAuditedSortKeyTable", TestUserWithSort{UserID: "theUser", Timestamp: "theTimestamp"})
+
+		assert.NoError(t, err)
+		assert.Equal(t, &types.AttributeValueMemberS{Value: "anAuditedSortKeyTable#timestamp=theTimestamp,user_id=theUser"}, actualInput.TransactItems[1].Put.Item["item"])
+	})
+
+	t.Run("guards_the_put_with_the_version_of_the_before_image", func(t *testing.T) {
+		useTestAuditing(t)
+		before := mustMarshalMap(t, TestUser{ID: "theID"})
+		before["version"] = &types.AttributeValueMemberN{Value: "7"}
+		var actualInput *dynamodb.TransactWriteItemsInput
+		fakeDB := &FakeDynamoDB{
+			DescribeTableFake: describeTableWithKeys("id"),
+			GetItemFake: func(ctx context.Context, params *dynamodb.GetItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.GetItemOutput, error) {
+				return &dynamodb.GetItemOutput{Item: before}, nil
+			},
+			TransactWriteItemsFake: func(ctx context.Context, params *dynamodb.TransactWriteItemsInput, optFns ...func(*dynamodb.Options)) (*dynamodb.TransactWriteItemsOutput, error) {
+				actualInput = params
+				return &dynamodb.TransactWriteItemsOutput{}, nil
+			},
+		}
+		setFake(func(ctx context.Context) (DynamoDB, error) { return fakeDB, nil })
+		t.Cleanup(func() { setFake(nil) })
+
+		err := PutItem(context.Background(), "anAuditedVersionTable", TestUser{ID: "theID"})
+
+		assert.NoError(t, err)
+		put := actualInput.TransactItems[0].Put
+		assert.Equal(t, "#auditVersion = :auditVersion", *put.ConditionExpression)
+		assert.Equal(t, map[string]types.AttributeValue{":auditVersion": &types.AttributeValueMemberN{Value: "7"}}, put.ExpressionAttributeValues)
+		assert.Equal(t, &types.AttributeValueMemberN{Value: "8"}, put.Item["version"])
+	})
+
+	t.Run("tries_again_with_the_current_item_when_another_writer_changes_it", func(t *testing.T) {
+		useTestAuditing(t)
+		current := mustMarshalMap(t, TestUser{ID: "theID", Name: "theOtherWritersName"})
+		current["version"] = &types.AttributeValueMemberN{Value: "1"}
+		var inputs []*dynamodb.TransactWriteItemsInput
+		fakeDB := &FakeDynamoDB{
+			DescribeTableFake: describeTableWithKeys("id"),
+			GetItemFake: func(ctx context.Context, params *dynamodb.GetItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.GetItemOutput, error) {
+				return &dynamodb.GetItemOutput{}, nil
+			},
+			TransactWriteItemsFake: func(ctx context.Context, params *dynamodb.TransactWriteItemsInput, optFns ...func(*dynamodb.Options)) (*dynamodb.TransactWriteItemsOutput, error) {
+				inputs = append(inputs, params)
+				if len(inputs) == 1 {
+					return nil, &types.TransactionCanceledException{CancellationReasons: []types.CancellationReason{
+						{Code: aws.String("ConditionalCheckFailed"), Item: current},
+						{Code: aws.String("None")},
+					}}
+				}
+				return &dynamodb.TransactWriteItemsOutput{}, nil
+			},
+		}
+		setFake(func(ctx context.Context) (DynamoDB, error) { return fakeDB, nil })
+		t.Cleanup(func() { setFake(nil) })
+
+		err := PutItem(context.Background(), "anAuditedConflictTable", TestUser{ID: "theID", Name: "theNewName"})
+
+		assert.NoError(t, err)
+		assert.Len(t, inputs, 2)
+		assert.Equal(t, "#auditVersion = :auditVersion", *inputs[1].TransactItems[0].Put.ConditionExpression)
+		assert.Equal(t, &types.AttributeValueMemberN{Value: "2"}, inputs[1].TransactItems[0].Put.Item["version"])
+		assert.Equal(t, &types.AttributeValueMemberM{Value: current}, inputs[1].TransactItems[1].Put.Item["before"])
+	})
+
+	t.Run("returns_an_error_when_the_item_keeps_changing", func(t *testing.T) {
+		useTestAuditing(t)
+		attempts := 0
+		fakeDB := &FakeDynamoDB{
+			DescribeTableFake: describeTableWithKeys("id"),
+			GetItemFake: func(ctx context.Context, params *dynamodb.GetItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.GetItemOutput, error) {
+				return &dynamodb.GetItemOutput{}, nil
+			},
+			TransactWriteItemsFake: func(ctx context.Context, params *dynamodb.TransactWriteItemsInput, optFns ...func(*dynamodb.Options)) (*dynamodb.TransactWriteItemsOutput, error) {
+				attempts++
+				current := mustMarshalMap(t, TestUser{ID: "theID"})
+				current["version"] = &types.AttributeValueMemberN{Value: strconv.Itoa(attempts)}
+				return nil, &types.TransactionCanceledException{CancellationReasons: []types.CancellationReason{
+					{Code: aws.String("ConditionalCheckFailed"), Item: current},
+					{Code: aws.String("None")},
+				}}
+			},
+		}
+		setFake(func(ctx context.Context) (DynamoDB, error) { return fakeDB, nil })
+		t.Cleanup(func() { setFake(nil) })
+
+		err := PutItem(context.Background(), "anAuditedBusyTable", TestUser{ID: "theID"})
+
+		assert.EqualError(t, err, "dynamodbkit.PutItem table=anAuditedBusyTable: error writing audited put: item or its audit record changed by another writer 4 times")
+		assert.NotErrorIs(t, err, ErrConditionFailed)
+		assert.Equal(t, 4, attempts)
+	})
+
+	t.Run("tries_again_when_another_audit_record_has_the_same_timestamp", func(t *testing.T) {
+		useTestAuditing(t)
+		attempts := 0
+		fakeDB := &FakeDynamoDB{
+			DescribeTableFake: describeTableWithKeys("id"),
+			GetItemFake: func(ctx context.Context, params *dynamodb.GetItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.GetItemOutput, error) {
+				return &dynamodb.GetItemOutput{}, nil
+			},
+			TransactWriteItemsFake: func(ctx context.Context, params *dynamodb.TransactWriteItemsInput, optFns ...func(*dynamodb.Options)) (*dynamodb.TransactWriteItemsOutput, error) {
+				attempts++
+				if attempts == 1 {
+					return nil, &types.TransactionCanceledException{CancellationReasons: []types.CancellationReason{
+						{Code: aws.String("None")},
+						{Code: aws.String("ConditionalCheckFailed")},
+					}}
+				}
+				return &dynamodb.TransactWriteItemsOutput{}, nil
+			},
+		}
+		setFake(func(ctx context.Context) (DynamoDB, error) { return fakeDB, nil })
+		t.Cleanup(func() { setFake(nil) })
+
+		err := PutItem(context.Background(), "anAuditedCollisionTable", TestUser{ID: "theID"})
+
+		assert.NoError(t, err)
+		assert.Equal(t, 2, attempts)
+	})
+
+	t.Run("describes_the_table_again_after_use_auditing_is_called_again", func(t *testing.T) {
+		describes := 0
+		fakeDB := &FakeDynamoDB{
+			DescribeTableFake: func(ctx context.Context, params *dynamodb.DescribeTableInput, optFns ...func(*dynamodb.Options)) (*dynamodb.DescribeTableOutput, error) {
+				describes++
+				return describeTableWithKeys("id")(ctx, params, optFns...)
+			},
+			GetItemFake: func(ctx context.Context, params *dynamodb.GetItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.GetItemOutput, error) {
+				return &dynamodb.GetItemOutput{}, nil
+			},
+			TransactWriteItemsFake: func(ctx context.Context, params *dynamodb.TransactWriteItemsInput, optFns ...func(*dynamodb.Options)) (*dynamodb.TransactWriteItemsOutput, error) {
+				return &dynamodb.TransactWriteItemsOutput{}, nil
+			},
+		}
+		setFake(func(ctx context.Context) (DynamoDB, error) { return fakeDB, nil })
+		t.Cleanup(func() { setFake(nil) })
+
+		useTestAuditing(t)
+		assert.NoError(t, PutItem(context.Background(), "anAuditedCachedTable", TestUser{ID: "theID"}))
+		assert.NoError(t, PutItem(context.Background(), "anAuditedCachedTable", TestUser{ID: "theID"}))
+		useTestAuditing(t)
+		assert.NoError(t, PutItem(context.Background(), "anAuditedCachedTable", TestUser{ID: "theID"}))
+
+		assert.Equal(t, 2, describes)
+	})
+
+	t.Run("returns_an_error_when_the_item_is_missing_a_key_attribute", func(t *testing.T) {
+		useTestAuditing(t)
+		fakeDB := &FakeDynamoDB{
+			DescribeTableFake: describeTableWithKeys("tenant_id"),
+		}
+		setFake(func(ctx context.Context) (DynamoDB, error) { return fakeDB, nil })
+		t.Cleanup(func() { setFake(nil) })
+
+		err := PutItem(context.Background(), "anAuditedMissingKeyTable", TestUser{ID: "theID"})
+
+		assert.ErrorContains(t, err, "item is missing key attribute tenant_id")
+	})
+
+	t.Run("returns_an_error_when_the_transaction_fails", func(t *testing.T) {
+		useTestAuditing(t)
+		fakeDB := &FakeDynamoDB{
+			DescribeTableFake: describeTableWithKeys("id"),
+			GetItemFake: func(ctx context.Context, params *dynamodb.GetItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.GetItemOutput, error) {
+				return &dynamodb.GetItemOutput{}, nil
+			},
+			TransactWriteItemsFake: func(ctx context.Context, params *dynamodb.TransactWriteItemsInput, optFns ...func(*dynamodb.Options)) (*dynamodb.TransactWriteItemsOutput, error) {
+				return nil, errors.New("the transaction error")
+			},
+		}
+		setFake(func(ctx context.Context) (DynamoDB, error) { return fakeDB, nil })
+		t.Cleanup(func() { setFake(nil) })
+
+		err := PutItem(context.Background(), "anAuditedFailingTable", TestUser{ID: "theID"})
+
+		assert.EqualError(t, err, "dynamodbkit.PutItem table=anAuditedFailingTable: error writing audited put: the transaction error")
+	})
+}
+
+func TestDeleteItemWithAuditing(t *testing.T) {
+	t.Run("deletes_the_item_and_writes_its_audit_record_in_one_transaction", func(t *testing.T) {
+		useTestAuditing(t)
+		before := mustMarshalMap(t, TestUser{ID: "theID", Name: "theName"})
+		var actualInput *dynamodb.TransactWriteItemsInput
+		fakeDB := &FakeDynamoDB{
+			GetItemFake: func(ctx context.Context, params *dynamodb.GetItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.GetItemOutput, error) {
+				return &dynamodb.GetItemOutput{Item: before}, nil
+			},
+			TransactWriteItemsFake: func(ctx context.Context, params *dynamodb.TransactWriteItemsInput, optFns ...func(*dynamodb.Options)) (*dynamodb.TransactWriteItemsOutput, error) {
+				actualInput = params
+				return &dynamodb.TransactWriteItemsOutput{}, nil
+			},
+		}
+		setFake(func(ctx context.Context) (DynamoDB, error) { return fakeDB, nil })
+		t.Cleanup(func() { setFake(nil) })
+
+		err := DeleteItem(context.Background(), "anAuditedDeleteTable", "id", "theID")
+
+		assert.NoError(t, err)
+		assert.Len(t, actualInput.TransactItems, 2)
+		assert.Equal(t, "anAuditedDeleteTable", *actualInput.TransactItems[0].Delete.TableName)
+		assert.Equal(t, map[string]types.AttributeValue{"id": &types.AttributeValueMemberS{Value: "theID"}}, actualInput.TransactItems[0].Delete.Key)
+		assert.Equal(t, "attribute_not_exists(#auditVersion)", *actualInput.TransactItems[0].Delete.ConditionExpression)
+		record := actualInput.TransactItems[1].Put.Item
+		assert.Equal(t, &types.AttributeValueMemberS{Value: "DELETE"}, record["action"])
+		assert.Equal(t, &types.AttributeValueMemberM{Value: before}, record["before"])
+		assert.NotContains(t, record, "after")
+	})
+
+	t.Run("returns_the_before_image_when_asked_for_the_old_item", func(t *testing.T) {
+		useTestAuditing(t)
+		before := mustMarshalMap(t, TestUser{ID: "theID", Name: "theName"})
+		fakeDB := &FakeDynamoDB{
+			GetItemFake: func(ctx context.Context, params *dynamodb.GetItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.GetItemOutput, error) {
+				return &dynamodb.GetItemOutput{Item: before}, nil
+			},
+			TransactWriteItemsFake: func(ctx context.Context, params *dynamodb.TransactWriteItemsInput, optFns ...func(*dynamodb.Options)) (*dynamodb.TransactWriteItemsOutput, error) {
+				return &dynamodb.TransactWriteItemsOutput{}, nil
+			},
+		}
+		setFake(func(ctx context.Context) (DynamoDB, error) { return fakeDB, nil })
+		t.Cleanup(func() { setFake(nil) })
+
+		item, err := DeleteItemReturning[TestUser](context.Background(), "anAuditedDeleteTable", "id", "theID", WithDeleteItemReturnOld())
+
+		assert.NoError(t, err)
+		assert.Equal(t, &TestUser{ID: "theID", Name: "theName"}, item)
+	})
+}
+
+func TestUpdateItemWithAuditing(t *testing.T) {
+	t.Run("updates_the_item_and_writes_its_audit_record_in_one_transaction", func(t *testing.T) {
+		useTestAuditing(t)
+		before := mustMarshalMap(t, TestUser{ID: "theID", Name: "theOldName"})
+		after := mustMarshalMap(t, TestUser{ID: "theID", Name: "theNewName"})
+		after["version"] = &types.AttributeValueMemberN{Value: "1"}
+		gets := 0
+		var actualInput *dynamodb.TransactWriteItemsInput
+		var actualAfterInput *dynamodb.UpdateItemInput
+		fakeDB := &FakeDynamoDB{
+			GetItemFake: func(ctx context.Context, params *dynamodb.GetItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.GetItemOutput, error) {
+				gets++
+				if gets == 1 {
+					return &dynamodb.GetItemOutput{Item: before}, nil
+				}
+				return &dynamodb.GetItemOutput{Item: after}, nil
+			},
+			TransactWriteItemsFake: func(ctx context.Context, params *dynamodb.TransactWriteItemsInput, optFns ...func(*dynamodb.Options)) (*dynamodb.TransactWriteItemsOutput, error) {
+				actualInput = params
+				return &dynamodb.TransactWriteItemsOutput{}, nil
+			},
+			UpdateItemFake: func(ctx context.Context, params *dynamodb.UpdateItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.UpdateItemOutput, error) {
+				actualAfterInput = params
+				return &dynamodb.UpdateItemOutput{}, nil
+			},
+		}
+		setFake(func(ctx context.Context) (DynamoDB, error) { return fakeDB, nil })
+		t.Cleanup(func() { setFake(nil) })
+
+		item, err := UpdateItem[TestUser](context.Background(), "anAuditedUpdateTable", "id", "theID", WithUpdateSet("name", "theNewName"))
+
+		assert.NoError(t, err)
+		assert.Equal(t, &TestUser{ID: "theID", Name: "theNewName"}, item)
+		assert.Len(t, actualInput.TransactItems, 2)
+		update := actualInput.TransactItems[0].Update
+		assert.Equal(t, "anAuditedUpdateTable", *update.TableName)
+		assert.Equal(t, "SET #auditVersion = :auditNextVersion, #0 = :0\n", *update.UpdateExpression)
+		assert.Equal(t, "attribute_not_exists(#auditVersion)", *update.ConditionExpression)
+		assert.Equal(t, &types.AttributeValueMemberN{Value: "1"}, update.ExpressionAttributeValues[":auditNextVersion"])
+		record := actualInput.TransactItems[1].Put.Item
+		assert.Equal(t, &types.AttributeValueMemberS{Value: "UPDATE"}, record["action"])
+		assert.Equal(t, &types.AttributeValueMemberM{Value: before}, record["before"])
+		assert.NotContains(t, record, "after")
+		assert.Equal(t, "theAuditTable", *actualAfterInput.TableName)
+		assert.Equal(t, map[string]types.AttributeValue{"item": record["item"], "timestamp": record["timestamp"]}, actualAfterInput.Key)
+		assert.Equal(t, &types.AttributeValueMemberM{Value: after}, actualAfterInput.ExpressionAttributeValues[":after"])
+	})
+
+	t.Run("adds_the_version_to_an_update_without_a_set_clause", func(t *testing.T) {
+		useTestAuditing(t)
+		var actualInput *dynamodb.TransactWriteItemsInput
+		fakeDB := &FakeDynamoDB{
+			GetItemFake: func(ctx context.Context, params *dynamodb.GetItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.GetItemOutput, error) {
+				return &dynamodb.GetItemOutput{}, nil
+			},
+			TransactWriteItemsFake: func(ctx context.Context, params *dynamodb.TransactWriteItemsInput, optFns ...func(*dynamodb.Options)) (*dynamodb.TransactWriteItemsOutput, error) {
+				actualInput = params
+				return &dynamodb.TransactWriteItemsOutput{}, nil
+			},
+		}
+		setFake(func(ctx context.Context) (DynamoDB, error) { return fakeDB, nil })
+		t.Cleanup(func() { setFake(nil) })
+
+		_, err := UpdateItem[TestUser](context.Background(), "anAuditedRemoveTable", "id", "theID", WithUpdateRemove("name"))
+
+		assert.NoError(t, err)
+		assert.Equal(t, "SET #auditVersion = :auditNextVersion REMOVE #0\n", *actualInput.TransactItems[0].Update.UpdateExpression)
+	})
+
+	t.Run("leaves_out_the_after_image_when_another_write_lands_first", func(t *testing.T) {
+		useTestAuditing(t)
+		later := mustMarshalMap(t, TestUser{ID: "theID", Name: "theLaterName"})
+		later["version"] = &types.AttributeValueMemberN{Value: "2"}
+		gets := 0
+		updates := 0
+		fakeDB := &FakeDynamoDB{
+			GetItemFake: func(ctx context.Context, params *dynamodb.GetItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.GetItemOutput, error) {
+				gets++
+				if gets == 1 {
+					return &dynamodb.GetItemOutput{}, nil
+				}
+				return &dynamodb.GetItemOutput{Item: later}, nil
+			},
+			TransactWriteItemsFake: func(ctx context.Context, params *dynamodb.TransactWriteItemsInput, optFns ...func(*dynamodb.Options)) (*dynamodb.TransactWriteItemsOutput, error) {
+				return &dynamodb.TransactWriteItemsOutput{}, nil
+			},
+			UpdateItemFake: func(ctx context.Context, params *dynamodb.UpdateItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.UpdateItemOutput, error) {
+				updates++
+				return &dynamodb.UpdateItemOutput{}, nil
+			},
+		}
+		setFake(func(ctx context.Context) (DynamoDB, error) { return fakeDB, nil })
+		t.Cleanup(func() { setFake(nil) })
+
+		item, err := UpdateItem[TestUser](context.Background(), "anAuditedRacedTable", "id", "theID", WithUpdateSet("name", "theNewName"))
+
+		assert.NoError(t, err)
+		assert.Equal(t, 0, updates)
+		assert.Equal(t, &TestUser{ID: "theID", Name: "theLaterName"}, item)
+	})
+
+	t.Run("returns_an_error_when_the_transaction_fails", func(t *testing.T) {
+		useTestAuditing(t)
+		fakeDB := &FakeDynamoDB{
+			GetItemFake: func(ctx context.Context, params *dynamodb.GetItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.GetItemOutput, error) {
+				return &dynamodb.GetItemOutput{}, nil
+			},
+			TransactWriteItemsFake: func(ctx context.Context, params *dynamodb.TransactWriteItemsInput, optFns ...func(*dynamodb.Options)) (*dynamodb.TransactWriteItemsOutput, error) {
+				return nil, errors.New("the transaction error")
+			},
+		}
+		setFake(func(ctx context.Context) (DynamoDB, error) { return fakeDB, nil })
+		t.Cleanup(func() { setFake(nil) })
+
+		_, err := UpdateItem[TestUser](context.Background(), "anAuditedFailingTable", "id", "theID", WithUpdateSet("name", "aName"))
+
+		assert.EqualError(t, err, "dynamodbkit.UpdateItem table=anAuditedFailingTable id=theID: error updating item: error writing audited update: the transaction error")
+	})
+}
@@ -0,0 +1,198 @@
+package dynamodbkit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/half-ogre/go-kit/backoffkit"
+	"github.com/half-ogre/go-kit/kit"
+	"github.com/half-ogre/go-kit/logkit/logfields"
+)
+
+// maxBatchWriteItems is the most writes DynamoDB takes in one BatchWriteItem request
+const maxBatchWriteItems = 25
+
+type batchWriteConfig struct {
+	tableNameSuffix *string
+	maxRetries      int
+	backoff         backoffkit.Strategy
+}
+
+type BatchWriteOption func(*batchWriteConfig)
+
+// WithBatchWriteRetries sets how many times unprocessed items, e.g. those throttled when a table is over
+// its capacity, are sent again and the initial backoff between attempts, which doubles after each retry;
+// the default is 5 retries starting at 50ms
+func WithBatchWriteRetries(maxRetries int, backoff time.Duration) BatchWriteOption {
+	return func(config *batchWriteConfig) {
+		config.maxRetries = maxRetries
+		config.backoff = backoffkit.Exponential(backoff, 0)
+	}
+}
+
+// WithBatchWriteBackoff sets how long to wait before sending unprocessed items again, e.g.
+// backoffkit.DecorrelatedJitter so concurrent writers throttled together spread out their retries
+func WithBatchWriteBackoff(strategy backoffkit.Strategy) BatchWriteOption {
+	return func(config *batchWriteConfig) {
+		config.backoff = strategy
+	}
+}
+
+func WithBatchWriteTableNameSuffix(suffix string) BatchWriteOption {
+	return func(config *batchWriteConfig) {
+		config.tableNameSuffix = &suffix
+	}
+}
+
+// BatchPutItems puts any number of items into a table, in BatchWriteItem requests of 25. Unprocessed
+// items are retried with backoff (see WithBatchWriteRetries). The writes aren't atomic: when it returns
+// an error, some items may have been written. With UseAuditing each item is put on its own with its
+// audit record instead, since a batch can't be written in a transaction.
+func BatchPutItems[T any](ctx context.Context, tableName string, items []T, options ...BatchWriteOption) (err error) {
+	op := newOperation(ctx, "BatchPutItems", tableName)
+	defer op.wrap(&err)
+
+	requests := make([]types.WriteRequest, 0, len(items))
+	for _, item := range items {
+		i, err := attributevalue.MarshalMap(item)
+		if err != nil {
+			return kit.WrapError(err, "error marshalling item")
+		}
+		requests = append(requests, types.WriteRequest{PutRequest: &types.PutRequest{Item: i}})
+	}
+
+	return batchWrite(ctx, op, requests, options)
+}
+
+// BatchDeleteItems deletes the items with keys from a table, in BatchWriteItem requests of 25. Each key
+// has every key attribute of the table, e.g. {"user_id": "123", "timestamp": "2024-01-02"}. Unprocessed
+// items are retried as with BatchPutItems.
+func BatchDeleteItems(ctx context.Context, tableName string, keys []map[string]any, options ...BatchWriteOption) (err error) {
+	op := newOperation(ctx, "BatchDeleteItems", tableName)
+	defer op.wrap(&err)
+
+	requests := make([]types.WriteRequest, 0, len(keys))
+	for _, key := range keys {
+		k, err := attributevalue.MarshalMap(key)
+		if err != nil {
+			return kit.WrapError(err, "error marshalling key")
+		}
+		requests = append(requests, types.WriteRequest{DeleteRequest: &types.DeleteRequest{Key: k}})
+	}
+
+	return batchWrite(ctx, op, requests, options)
+}
+
+func batchWrite(ctx context.Context, op *operation, requests []types.WriteRequest, options []BatchWriteOption) (err error) {
+	tableName := op.table
+	if tableName == "" {
+		return errors.New("table name cannot be empty")
+	}
+
+	config := &batchWriteConfig{maxRetries: 5, backoff: backoffkit.Exponential(50*time.Millisecond, 0)}
+	for _, option := range options {
+		option(config)
+	}
+
+	if config.tableNameSuffix != nil {
+		tableName = fmt.Sprintf("%s%s%s", op.client.tableNamePrefix, tableName, *config.tableNameSuffix)
+	} else {
+		tableName = op.client.TableName(tableName)
+	}
+	op.table = tableName
+
+	if len(requests) == 0 {
+		return nil
+	}
+
+	db, err := op.dynamoDB(ctx)
+	if err != nil {
+		return kit.WrapError(err, "error creating DynamoDB client")
+	}
+
+	metrics := startOperation(op, op.name, aws.String(tableName))
+	defer func() { metrics.finish(ctx, err) }()
+
+	if audit := op.client.audit; audit != nil {
+		err = batchWriteWithAudit(ctx, db, audit, tableName, requests, metrics)
+		return err
+	}
+
+	writer, err := requireAPI[BatchWriteItemAPI](db, "BatchWriteItem")
+	if err != nil {
+		return err
+	}
+
+	for start := 0; start < len(requests); start += maxBatchWriteItems {
+		end := min(start+maxBatchWriteItems, len(requests))
+		err = batchWriteChunk(ctx, writer, config, tableName, requests[start:end], metrics, op.client.returnConsumedCapacity(metrics))
+		if err != nil {
+			err = kit.WrapError(err, "error writing items %d to %d of %d", start, end-1, len(requests))
+			return err
+		}
+	}
+
+	return nil
+}
+
+// batchWriteChunk writes up to 25 requests, sending unprocessed ones again until they're all written or
+// the retries run out
+func batchWriteChunk(ctx context.Context, db BatchWriteItemAPI, config *batchWriteConfig, tableName string, requests []types.WriteRequest, metrics *operationMetrics, returnConsumedCapacity types.ReturnConsumedCapacity) error {
+	pending := requests
+	var wait time.Duration
+	for attempt := 0; ; attempt++ {
+		logger.DebugContext(ctx, "writing DynamoDB batch", logfields.Table(tableName), "requests", len(pending), "attempt", attempt+1)
+
+		output, err := db.BatchWriteItem(ctx, &dynamodb.BatchWriteItemInput{
+			RequestItems:           map[string][]types.WriteRequest{tableName: pending},
+			ReturnConsumedCapacity: returnConsumedCapacity,
+		})
+		if err != nil {
+			return err
+		}
+
+		written := len(pending) - len(output.UnprocessedItems[tableName])
+		capacities := make([]*types.ConsumedCapacity, len(output.ConsumedCapacity))
+		for i := range output.ConsumedCapacity {
+			capacities[i] = &output.ConsumedCapacity[i]
+		}
+		metrics.addResponse(written, capacities...)
+
+		pending = output.UnprocessedItems[tableName]
+		if len(pending) == 0 {
+			return nil
+		}
+		if attempt >= config.maxRetries {
+			return fmt.Errorf("%d items still unprocessed after %d retries", len(pending), config.maxRetries)
+		}
+
+		logger.WarnContext(ctx, "retrying unprocessed DynamoDB batch items", logfields.Table(tableName), "items", len(pending), "attempt", attempt+1)
+
+		wait = config.backoff(attempt+2, wait)
+		if err := sleep(ctx, wait); err != nil {
+			return err
+		}
+	}
+}
+
+func batchWriteWithAudit(ctx context.Context, db DynamoDB, audit *auditConfig, tableName string, requests []types.WriteRequest, metrics *operationMetrics) error {
+	for i, request := range requests {
+		var err error
+		if request.PutRequest != nil {
+			err = putItemWithAudit(ctx, db, audit, &dynamodb.PutItemInput{TableName: aws.String(tableName), Item: request.PutRequest.Item})
+		} else {
+			_, err = deleteItemWithAudit(ctx, db, audit, &dynamodb.DeleteItemInput{TableName: aws.String(tableName), Key: request.DeleteRequest.Key})
+		}
+		if err != nil {
+			return kit.WrapError(err, "error writing item %d of %d", i, len(requests))
+		}
+		metrics.addResponse(1)
+	}
+	return nil
+}
@@ -0,0 +1,242 @@
+package dynamodbkit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/half-ogre/go-kit/backoffkit"
+)
+
+func testUsers(n int) []TestUser {
+	users := make([]TestUser, n)
+	for i := range users {
+		users[i] = TestUser{ID: fmt.Sprintf("theID%d", i)}
+	}
+	return users
+}
+
+func TestBatchPutItems(t *testing.T) {
+	t.Run("returns_an_error_when_getting_a_new_dynamodb_connection_returns_an_error", func(t *testing.T) {
+		setFake(func(ctx context.Context) (DynamoDB, error) { return nil, errors.New("the fake error") })
+		t.Cleanup(func() { setFake(nil) })
+
+		err := BatchPutItems(context.Background(), "aTable", testUsers(1))
+
+		assert.EqualError(t, err, "dynamodbkit.BatchPutItems table=aTable: error creating DynamoDB client: the fake error")
+	})
+
+	t.Run("does_nothing_when_there_are_no_items", func(t *testing.T) {
+		err := BatchPutItems(context.Background(), "aTable", []TestUser{})
+
+		assert.NoError(t, err)
+	})
+
+	t.Run("chunks_the_items_into_requests_of_25", func(t *testing.T) {
+		var actualSizes []int
+		fakeDB := &FakeDynamoDB{
+			BatchWriteItemFake: func(ctx context.Context, params *dynamodb.BatchWriteItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.BatchWriteItemOutput, error) {
+				actualSizes = append(actualSizes, len(params.RequestItems["theTableName"]))
+				return &dynamodb.BatchWriteItemOutput{}, nil
+			},
+		}
+		setFake(func(ctx context.Context) (DynamoDB, error) { return fakeDB, nil })
+		t.Cleanup(func() { setFake(nil) })
+
+		err := BatchPutItems(context.Background(), "theTableName", testUsers(60))
+
+		assert.NoError(t, err)
+		assert.Equal(t, []int{25, 25, 10}, actualSizes)
+	})
+
+	t.Run("puts_the_marshalled_items", func(t *testing.T) {
+		var actualRequests []types.WriteRequest
+		fakeDB := &FakeDynamoDB{
+			BatchWriteItemFake: func(ctx context.Context, params *dynamodb.BatchWriteItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.BatchWriteItemOutput, error) {
+				actualRequests = params.RequestItems["aTable"]
+				return &dynamodb.BatchWriteItemOutput{}, nil
+			},
+		}
+		setFake(func(ctx context.Context) (DynamoDB, error) { return fakeDB, nil })
+		t.Cleanup(func() { setFake(nil) })
+
+		err := BatchPutItems(context.Background(), "aTable", []TestUser{{ID: "theID", Name: "theName"}})
+
+		assert.NoError(t, err)
+		assert.Len(t, actualRequests, 1)
+		assert.Equal(t, mustMarshalMap(t, TestUser{ID: "theID", Name: "theName"}), actualRequests[0].PutRequest.Item)
+	})
+
+	t.Run("retries_unprocessed_items", func(t *testing.T) {
+		var actualSizes []int
+		fakeDB := &FakeDynamoDB{
+			BatchWriteItemFake: func(ctx context.Context, params *dynamodb.BatchWriteItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.BatchWriteItemOutput, error) {
+				requests := params.RequestItems["aTable"]
+				actualSizes = append(actualSizes, len(requests))
+				if len(actualSizes) == 1 {
+					return &dynamodb.BatchWriteItemOutput{UnprocessedItems: map[string][]types.WriteRequest{"aTable": requests[:2]}}, nil
+				}
+				return &dynamodb.BatchWriteItemOutput{}, nil
+			},
+		}
+		setFake(func(ctx context.Context) (DynamoDB, error) { return fakeDB, nil })
+		t.Cleanup(func() { setFake(nil) })
+
+		err := BatchPutItems(context.Background(), "aTable", testUsers(5), WithBatchWriteRetries(3, time.Millisecond))
+
+		assert.NoError(t, err)
+		assert.Equal(t, []int{5, 2}, actualSizes)
+	})
+
+	t.Run("returns_an_error_when_items_are_still_unprocessed_after_the_retries", func(t *testing.T) {
+		calls := 0
+		fakeDB := &FakeDynamoDB{
+			BatchWriteItemFake: func(ctx context.Context, params *dynamodb.BatchWriteItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.BatchWriteItemOutput, error) {
+				calls++
+				return &dynamodb.BatchWriteItemOutput{UnprocessedItems: params.RequestItems}, nil
+			},
+		}
+		setFake(func(ctx context.Context) (DynamoDB, error) { return fakeDB, nil })
+		t.Cleanup(func() { setFake(nil) })
+
+		err := BatchPutItems(context.Background(), "aTable", testUsers(3), WithBatchWriteRetries(2, time.Millisecond))
+
+		assert.EqualError(t, err, "dynamodbkit.BatchPutItems table=aTable: error writing items 0 to 2 of 3: 3 items still unprocessed after 2 retries")
+		assert.Equal(t, 3, calls)
+	})
+
+	t.Run("waits_as_the_backoff_says_between_retries", func(t *testing.T) {
+		waits := recordSleeps(t)
+		calls := 0
+		fakeDB := &FakeDynamoDB{
+			BatchWriteItemFake: func(ctx context.Context, params *dynamodb.BatchWriteItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.BatchWriteItemOutput, error) {
+				calls++
+				if calls < 3 {
+					return &dynamodb.BatchWriteItemOutput{UnprocessedItems: params.RequestItems}, nil
+				}
+				return &dynamodb.BatchWriteItemOutput{}, nil
+			},
+		}
+		setFake(func(ctx context.Context) (DynamoDB, error) { return fakeDB, nil })
+		t.Cleanup(func() { setFake(nil) })
+
+		err := BatchPutItems(context.Background(), "aTable", testUsers(1), WithBatchWriteBackoff(backoffkit.Constant(time.Second)))
+
+		assert.NoError(t, err)
+		assert.Equal(t, []time.Duration{time.Second, time.Second}, *waits)
+	})
+
+	t.Run("doubles_the_wait_after_each_retry_by_default", func(t *testing.T) {
+		waits := recordSleeps(t)
+		fakeDB := &FakeDynamoDB{
+			BatchWriteItemFake: func(ctx context.Context, params *dynamodb.BatchWriteItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.BatchWriteItemOutput, error) {
+				return &dynamodb.BatchWriteItemOutput{UnprocessedItems: params.RequestItems}, nil
+			},
+		}
+		setFake(func(ctx context.Context) (DynamoDB, error) { return fakeDB, nil })
+		t.Cleanup(func() { setFake(nil) })
+
+		_ = BatchPutItems(context.Background(), "aTable", testUsers(1), WithBatchWriteRetries(3, 10*time.Millisecond))
+
+		assert.Equal(t, []time.Duration{10 * time.Millisecond, 20 * time.Millisecond, 40 * time.Millisecond}, *waits)
+	})
+
+	t.Run("returns_the_context_error_when_it_is_done_while_waiting_to_retry", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		fakeDB := &FakeDynamoDB{
+			BatchWriteItemFake: func(ctx context.Context, params *dynamodb.BatchWriteItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.BatchWriteItemOutput, error) {
+				cancel()
+				return &dynamodb.BatchWriteItemOutput{UnprocessedItems: params.RequestItems}, nil
+			},
+		}
+		setFake(func(ctx context.Context) (DynamoDB, error) { return fakeDB, nil })
+		t.Cleanup(func() { setFake(nil) })
+
+		err := BatchPutItems(ctx, "aTable", testUsers(1), WithBatchWriteRetries(3, time.Hour))
+
+		assert.ErrorIs(t, err, context.Canceled)
+	})
+
+	t.Run("returns_an_error_when_batch_write_item_returns_an_error", func(t *testing.T) {
+		fakeDB := &FakeDynamoDB{
+			BatchWriteItemFake: func(ctx context.Context, params *dynamodb.BatchWriteItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.BatchWriteItemOutput, error) {
+				return nil, errors.New("the fake error")
+			},
+		}
+		setFake(func(ctx context.Context) (DynamoDB, error) { return fakeDB, nil })
+		t.Cleanup(func() { setFake(nil) })
+
+		err := BatchPutItems(context.Background(), "aTable", testUsers(30))
+
+		assert.EqualError(t, err, "dynamodbkit.BatchPutItems table=aTable: error writing items 0 to 24 of 30: the fake error")
+	})
+
+	t.Run("applies_the_table_name_suffix_option", func(t *testing.T) {
+		var actualTableNames []string
+		fakeDB := &FakeDynamoDB{
+			BatchWriteItemFake: func(ctx context.Context, params *dynamodb.BatchWriteItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.BatchWriteItemOutput, error) {
+				for name := range params.RequestItems {
+					actualTableNames = append(actualTableNames, name)
+				}
+				return &dynamodb.BatchWriteItemOutput{}, nil
+			},
+		}
+		setFake(func(ctx context.Context) (DynamoDB, error) { return fakeDB, nil })
+		t.Cleanup(func() { setFake(nil) })
+
+		err := BatchPutItems(context.Background(), "aTable", testUsers(1), WithBatchWriteTableNameSuffix("-theSuffix"))
+
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"aTable-theSuffix"}, actualTableNames)
+	})
+
+	t.Run("returns_an_error_when_the_client_does_not_implement_batch_write_item", func(t *testing.T) {
+		setFake(func(ctx context.Context) (DynamoDB, error) { return coreOnlyDynamoDB{DynamoDB: &FakeDynamoDB{}}, nil })
+		t.Cleanup(func() { setFake(nil) })
+
+		err := BatchPutItems(context.Background(), "aCoreOnlyTable", []TestUser{{ID: "theID"}})
+
+		assert.ErrorContains(t, err, "dynamodbkit.coreOnlyDynamoDB does not implement BatchWriteItem")
+	})
+}
+
+func TestBatchDeleteItems(t *testing.T) {
+	t.Run("deletes_the_items_with_the_keys", func(t *testing.T) {
+		var actualRequests []types.WriteRequest
+		fakeDB := &FakeDynamoDB{
+			BatchWriteItemFake: func(ctx context.Context, params *dynamodb.BatchWriteItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.BatchWriteItemOutput, error) {
+				actualRequests = params.RequestItems["aTable"]
+				return &dynamodb.BatchWriteItemOutput{}, nil
+			},
+		}
+		setFake(func(ctx context.Context) (DynamoDB, error) { return fakeDB, nil })
+		t.Cleanup(func() { setFake(nil) })
+
+		err := BatchDeleteItems(context.Background(), "aTable", []map[string]any{{"user_id": "theUserID", "timestamp": 42}})
+
+		assert.NoError(t, err)
+		assert.Len(t, actualRequests, 1)
+		assert.Equal(t, map[string]types.AttributeValue{
+			"user_id":   &types.AttributeValueMemberS{Value: "theUserID"},
+			"timestamp": &types.AttributeValueMemberN{Value: "42"},
+		}, actualRequests[0].DeleteRequest.Key)
+	})
+
+	t.Run("returns_an_error_when_the_table_name_is_empty", func(t *testing.T) {
+		err := BatchDeleteItems(context.Background(), "", []map[string]any{{"id": "anID"}})
+
+		assert.ErrorContains(t, err, "table name cannot be empty")
+	})
+}
+
+// coreOnlyDynamoDB implements only the DynamoDB interface, like a client written before the optional
+// interfaces were added
+type coreOnlyDynamoDB struct {
+	DynamoDB
+}
@@ -0,0 +1,536 @@
+package dynamodbkit
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/half-ogre/go-kit/kit"
+)
+
+// Cache is the store used by read-through caching. Values are opaque bytes so the cache can be in
+// process (see MemoryCache) or shared, such as Redis or Memcached.
+type Cache interface {
+	Get(ctx context.Context, key string) ([]byte, bool, error)
+	Set(ctx context.Context, key string, value []byte, ttl time.Duration) error
+}
+
+// CachedDynamoDB adds read-through caching of GetItem and Query results to a DynamoDB, see WithCache
+type CachedDynamoDB struct {
+	db          DynamoDB
+	cache       Cache
+	ttl         time.Duration
+	loadTimeout time.Duration
+
+	generationsMu sync.Mutex
+	generations   map[string]uint64
+	epoch         uint64
+
+	getItemLoads kit.Singleflight[*dynamodb.GetItemOutput]
+	queryLoads   kit.Singleflight[*dynamodb.QueryOutput]
+}
+
+// CacheOption configures WithCache
+type CacheOption func(*CachedDynamoDB)
+
+// WithCacheLoadTimeout sets how long a cache miss may take to load from DynamoDB; the default is 10
+// seconds. The load doesn't stop when the reader that started it gives up, so readers waiting on the
+// same key still get its result.
+func WithCacheLoadTimeout(timeout time.Duration) CacheOption {
+	return func(c *CachedDynamoDB) {
+		c.loadTimeout = timeout
+	}
+}
+
+// WithCache returns db with read-through caching of GetItem and Query results for ttl, for use with
+// NewClient, e.g.
+//
+//	client := dynamodbkit.NewClient(dynamodbkit.WithCache(dynamodb.NewFromConfig(cfg), dynamodbkit.NewMemoryCache(), time.Minute))
+//
+// Reads with ConsistentRead set always go to DynamoDB, and missing items aren't cached.
+//
+// Invalidation is process-local: a write through the returned DynamoDB (PutItem, UpdateItem,
+// DeleteItem, BatchWriteItem, TransactWriteItems, or a PartiQL statement other than a SELECT)
+// invalidates every cached read of the tables it wrote, but only for readers using this same
+// CachedDynamoDB. Writes made by other processes, or through another client, are only seen once
+// entries expire, even when the Cache itself is shared, e.g. Redis. Pick ttl for how stale a read may
+// be.
+func WithCache(db DynamoDB, cache Cache, ttl time.Duration, options ...CacheOption) *CachedDynamoDB {
+	c := &CachedDynamoDB{
+		db:          db,
+		cache:       cache,
+		ttl:         ttl,
+		loadTimeout: 10 * time.Second,
+		generations: map[string]uint64{},
+	}
+	for _, option := range options {
+		option(c)
+	}
+	return c
+}
+
+// tableGeneration returns the generation of the table's cached reads, changed by invalidating the
+// table or every table
+func (c *CachedDynamoDB) tableGeneration(tableName string) string {
+	c.generationsMu.Lock()
+	defer c.generationsMu.Unlock()
+	return fmt.Sprintf("%d.%d", c.epoch, c.generations[tableName])
+}
+
+// invalidateTable orphans every cached read of the table by moving it to a new generation
+func (c *CachedDynamoDB) invalidateTable(tableName string) {
+	c.generationsMu.Lock()
+	defer c.generationsMu.Unlock()
+	c.generations[tableName]++
+}
+
+// invalidateAllTables orphans every cached read by moving every table, including ones not read yet, to
+// a new generation
+func (c *CachedDynamoDB) invalidateAllTables() {
+	c.generationsMu.Lock()
+	defer c.generationsMu.Unlock()
+	c.epoch++
+}
+
+// load runs fn once at a time for key on a context detached from the reader's, with its own timeout,
+// so an expired hot item doesn't send every reader to DynamoDB at once and one reader giving up doesn't
+// fail the others. Each reader still returns as soon as its own ctx is done.
+func load[T any](ctx context.Context, c *CachedDynamoDB, loads *kit.Singleflight[T], key string, fn func(ctx context.Context) (T, error)) (T, error) {
+	type result struct {
+		value T
+		err   error
+	}
+
+	done := make(chan result, 1)
+	go func() {
+		value, err, _ := loads.Do(key, func() (T, error) {
+			loadCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), c.loadTimeout)
+			defer cancel()
+			return fn(loadCtx)
+		})
+		done <- result{value: value, err: err}
+	}()
+
+	select {
+	case r := <-done:
+		return r.value, r.err
+	case <-ctx.Done():
+		var zero T
+		return zero, ctx.Err()
+	}
+}
+
+func (c *CachedDynamoDB) GetItem(ctx context.Context, params *dynamodb.GetItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.GetItemOutput, error) {
+	if aws.ToBool(params.ConsistentRead) {
+		return c.db.GetItem(ctx, params, optFns...)
+	}
+
+	key := c.cacheKey(aws.ToString(params.TableName), "get", map[string]any{
+		"key":        encodeCachedItem(params.Key),
+		"projection": aws.ToString(params.ProjectionExpression),
+		"names":      params.ExpressionAttributeNames,
+	})
+
+	var cached cachedItem
+	if readCachedValue(ctx, c.cache, key, &cached) {
+		return &dynamodb.GetItemOutput{Item: cached.decode()}, nil
+	}
+
+	return load(ctx, c, &c.getItemLoads, key, func(ctx context.Context) (*dynamodb.GetItemOutput, error) {
+		// A load that just finished may have filled the cache after this reader missed it
+		var cached cachedItem
+		if readCachedValue(ctx, c.cache, key, &cached) {
+			return &dynamodb.GetItemOutput{Item: cached.decode()}, nil
+		}
+
+		output, err := c.db.GetItem(ctx, params, optFns...)
+		if err != nil {
+			return nil, err
+		}
+
+		// Missing items aren't cached so an item created elsewhere shows up right away
+		if output.Item != nil {
+			writeCachedValue(ctx, c.cache, key, encodeCachedItem(output.Item), c.ttl)
+		}
+
+		return output, nil
+	})
+}
+
+type cachedQueryOutput struct {
+	Items            []cachedItem `json:"items"`
+	LastEvaluatedKey cachedItem   `json:"last_evaluated_key,omitempty"`
+}
+
+func (cached cachedQueryOutput) decode() *dynamodb.QueryOutput {
+	output := &dynamodb.QueryOutput{Items: make([]map[string]types.AttributeValue, 0, len(cached.Items))}
+	for _, item := range cached.Items {
+		output.Items = append(output.Items, item.decode())
+	}
+	output.LastEvaluatedKey = cached.LastEvaluatedKey.decode()
+	return output
+}
+
+func (c *CachedDynamoDB) Query(ctx context.Context, params *dynamodb.QueryInput, optFns ...func(*dynamodb.Options)) (*dynamodb.QueryOutput, error) {
+	if aws.ToBool(params.ConsistentRead) {
+		return c.db.Query(ctx, params, optFns...)
+	}
+
+	key := c.cacheKey(aws.ToString(params.TableName), "query", map[string]any{
+		"index":              aws.ToString(params.IndexName),
+		"key_condition":      aws.ToString(params.KeyConditionExpression),
+		"filter":             aws.ToString(params.FilterExpression),
+		"projection":         aws.ToString(params.ProjectionExpression),
+		"names":              params.ExpressionAttributeNames,
+		"values":             encodeCachedItem(params.ExpressionAttributeValues),
+		"exclusive_start":    encodeCachedItem(params.ExclusiveStartKey),
+		"limit":              params.Limit,
+		"scan_index_forward": params.ScanIndexForward,
+		"select":             params.Select,
+	})
+
+	var cached cachedQueryOutput
+	if readCachedValue(ctx, c.cache, key, &cached) {
+		return cached.decode(), nil
+	}
+
+	return load(ctx, c, &c.queryLoads, key, func(ctx context.Context) (*dynamodb.QueryOutput, error) {
+		// A load that just finished may have filled the cache after this reader missed it
+		var cached cachedQueryOutput
+		if readCachedValue(ctx, c.cache, key, &cached) {
+			return cached.decode(), nil
+		}
+
+		output, err := c.db.Query(ctx, params, optFns...)
+		if err != nil {
+			return nil, err
+		}
+
+		entry := cachedQueryOutput{Items: make([]cachedItem, 0, len(output.Items))}
+		for _, item := range output.Items {
+			entry.Items = append(entry.Items, encodeCachedItem(item))
+		}
+		entry.LastEvaluatedKey = encodeCachedItem(output.LastEvaluatedKey)
+		writeCachedValue(ctx, c.cache, key, entry, c.ttl)
+
+		return output, nil
+	})
+}
+
+// Writes invalidate their tables even when they fail, since a failed call may still have been applied
+
+func (c *CachedDynamoDB) PutItem(ctx context.Context, params *dynamodb.PutItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.PutItemOutput, error) {
+	defer c.invalidateTable(aws.ToString(params.TableName))
+	return c.db.PutItem(ctx, params, optFns...)
+}
+
+func (c *CachedDynamoDB) DeleteItem(ctx context.Context, params *dynamodb.DeleteItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.DeleteItemOutput, error) {
+	defer c.invalidateTable(aws.ToString(params.TableName))
+	return c.db.DeleteItem(ctx, params, optFns...)
+}
+
+func (c *CachedDynamoDB) UpdateItem(ctx context.Context, params *dynamodb.UpdateItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.UpdateItemOutput, error) {
+	defer c.invalidateTable(aws.ToString(params.TableName))
+	updater, err := requireAPI[UpdateItemAPI](c.db, "UpdateItem")
+	if err != nil {
+		return nil, err
+	}
+	return updater.UpdateItem(ctx, params, optFns...)
+}
+
+func (c *CachedDynamoDB) BatchWriteItem(ctx context.Context, params *dynamodb.BatchWriteItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.BatchWriteItemOutput, error) {
+	defer func() {
+		for tableName := range params.RequestItems {
+			c.invalidateTable(tableName)
+		}
+	}()
+	writer, err := requireAPI[BatchWriteItemAPI](c.db, "BatchWriteItem")
+	if err != nil {
+		return nil, err
+	}
+	return writer.BatchWriteItem(ctx, params, optFns...)
+}
+
+func (c *CachedDynamoDB) TransactWriteItems(ctx context.Context, params *dynamodb.TransactWriteItemsInput, optFns ...func(*dynamodb.Options)) (*dynamodb.TransactWriteItemsOutput, error) {
+	defer func() {
+		for _, item := range params.TransactItems {
+			switch {
+			case item.Put != nil:
+				c.invalidateTable(aws.ToString(item.Put.TableName))
+			case item.Update != nil:
+				c.invalidateTable(aws.ToString(item.Update.TableName))
+			case item.Delete != nil:
+				c.invalidateTable(aws.ToString(item.Delete.TableName))
+			}
+		}
+	}()
+	writer, err := requireAPI[TransactWriteItemsAPI](c.db, "TransactWriteItems")
+	if err != nil {
+		return nil, err
+	}
+	return writer.TransactWriteItems(ctx, params, optFns...)
+}
+
+func (c *CachedDynamoDB) Scan(ctx context.Context, params *dynamodb.ScanInput, optFns ...func(*dynamodb.Options)) (*dynamodb.ScanOutput, error) {
+	return c.db.Scan(ctx, params, optFns...)
+}
+
+// ExecuteStatement passes PartiQL statements through uncached. A statement other than a SELECT
+// invalidates its table, or every table when its table can't be told from the statement.
+func (c *CachedDynamoDB) ExecuteStatement(ctx context.Context, params *dynamodb.ExecuteStatementInput, optFns ...func(*dynamodb.Options)) (*dynamodb.ExecuteStatementOutput, error) {
+	statement := aws.ToString(params.Statement)
+	if !isSelectStatement(statement) {
+		defer func() {
+			if tableName := statementTableName(statement); tableName != "" {
+				c.invalidateTable(tableName)
+			} else {
+				c.invalidateAllTables()
+			}
+		}()
+	}
+	executor, err := requireAPI[ExecuteStatementAPI](c.db, "ExecuteStatement")
+	if err != nil {
+		return nil, err
+	}
+	return executor.ExecuteStatement(ctx, params, optFns...)
+}
+
+func (c *CachedDynamoDB) ListTables(ctx context.Context, params *dynamodb.ListTablesInput, optFns ...func(*dynamodb.Options)) (*dynamodb.ListTablesOutput, error) {
+	return c.db.ListTables(ctx, params, optFns...)
+}
+
+func (c *CachedDynamoDB) CreateTable(ctx context.Context, params *dynamodb.CreateTableInput, optFns ...func(*dynamodb.Options)) (*dynamodb.CreateTableOutput, error) {
+	creator, err := requireAPI[CreateTableAPI](c.db, "CreateTable")
+	if err != nil {
+		return nil, err
+	}
+	return creator.CreateTable(ctx, params, optFns...)
+}
+
+func (c *CachedDynamoDB) DeleteTable(ctx context.Context, params *dynamodb.DeleteTableInput, optFns ...func(*dynamodb.Options)) (*dynamodb.DeleteTableOutput, error) {
+	// A table created again under the same name mustn't see the old table's items
+	defer c.invalidateTable(aws.ToString(params.TableName))
+	deleter, err := requireAPI[DeleteTableAPI](c.db, "DeleteTable")
+	if err != nil {
+		return nil, err
+	}
+	return deleter.DeleteTable(ctx, params, optFns...)
+}
+
+func (c *CachedDynamoDB) DescribeTable(ctx context.Context, params *dynamodb.DescribeTableInput, optFns ...func(*dynamodb.Options)) (*dynamodb.DescribeTableOutput, error) {
+	describer, err := requireAPI[DescribeTableAPI](c.db, "DescribeTable")
+	if err != nil {
+		return nil, err
+	}
+	return describer.DescribeTable(ctx, params, optFns...)
+}
+
+// cacheKey hashes the parts of a read that affect its result; the table generation is part of the key
+// so invalidating a table never needs to find its entries
+func (c *CachedDynamoDB) cacheKey(tableName string, operation string, input map[string]any) string {
+	inputJSON, _ := json.Marshal(input)
+	hash := sha256.Sum256(inputJSON)
+	return fmt.Sprintf("dynamodbkit:%s:%s:%s:%s", tableName, c.tableGeneration(tableName), operation, hex.EncodeToString(hash[:]))
+}
+
+// readCachedValue reports whether key was found and decoded; cache errors are logged and treated as misses
+func readCachedValue(ctx context.Context, cache Cache, key string, v any) bool {
+	data, found, err := cache.Get(ctx, key)
+	if err != nil {
+		logger.WarnContext(ctx, "error reading DynamoDB cache", "key", key, "error", err)
+		return false
+	}
+	if !found {
+		return false
+	}
+
+	err = json.Unmarshal(data, v)
+	if err != nil {
+		logger.WarnContext(ctx, "error decoding DynamoDB cache entry", "key", key, "error", err)
+		return false
+	}
+
+	return true
+}
+
+func writeCachedValue(ctx context.Context, cache Cache, key string, v any, ttl time.Duration) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		logger.WarnContext(ctx, "error encoding DynamoDB cache entry", "key", key, "error", err)
+		return
+	}
+
+	err = cache.Set(ctx, key, data, ttl)
+	if err != nil {
+		logger.WarnContext(ctx, "error writing DynamoDB cache", "key", key, "error", err)
+	}
+}
+
+// cachedItem is an item in DynamoDB's own JSON form, e.g. {"id":{"S":"1"}}, which keeps every attribute type
+type cachedItem map[string]cachedAttributeValue
+
+type cachedAttributeValue struct {
+	S    *string                          `json:"S,omitempty"`
+	N    *string                          `json:"N,omitempty"`
+	B    *[]byte                          `json:"B,omitempty"`
+	BOOL *bool                            `json:"BOOL,omitempty"`
+	NULL bool                             `json:"NULL,omitempty"`
+	SS   []string                         `json:"SS,omitempty"`
+	NS   []string                         `json:"NS,omitempty"`
+	BS   [][]byte                         `json:"BS,omitempty"`
+	L    *[]cachedAttributeValue          `json:"L,omitempty"`
+	M    *map[string]cachedAttributeValue `json:"M,omitempty"`
+}
+
+func encodeCachedItem(item map[string]types.AttributeValue) cachedItem {
+	if item == nil {
+		return nil
+	}
+	encoded := make(cachedItem, len(item))
+	for name, value := range item {
+		encoded[name] = encodeCachedAttributeValue(value)
+	}
+	return encoded
+}
+
+func (item cachedItem) decode() map[string]types.AttributeValue {
+	if item == nil {
+		return nil
+	}
+	decoded := make(map[string]types.AttributeValue, len(item))
+	for name, value := range item {
+		decoded[name] = value.decode()
+	}
+	return decoded
+}
+
+func encodeCachedAttributeValue(value types.AttributeValue) cachedAttributeValue {
+	switch v := value.(type) {
+	case *types.AttributeValueMemberS:
+		return cachedAttributeValue{S: &v.Value}
+	case *types.AttributeValueMemberN:
+		return cachedAttributeValue{N: &v.Value}
+	case *types.AttributeValueMemberB:
+		return cachedAttributeValue{B: &v.Value}
+	case *types.AttributeValueMemberBOOL:
+		return cachedAttributeValue{BOOL: &v.Value}
+	case *types.AttributeValueMemberNULL:
+		return cachedAttributeValue{NULL: true}
+	case *types.AttributeValueMemberSS:
+		return cachedAttributeValue{SS: v.Value}
+	case *types.AttributeValueMemberNS:
+		return cachedAttributeValue{NS: v.Value}
+	case *types.AttributeValueMemberBS:
+		return cachedAttributeValue{BS: v.Value}
+	case *types.AttributeValueMemberL:
+		list := make([]cachedAttributeValue, 0, len(v.Value))
+		for _, element := range v.Value {
+			list = append(list, encodeCachedAttributeValue(element))
+		}
+		return cachedAttributeValue{L: &list}
+	case *types.AttributeValueMemberM:
+		m := map[string]cachedAttributeValue(encodeCachedItem(v.Value))
+		if m == nil {
+			m = map[string]cachedAttributeValue{}
+		}
+		return cachedAttributeValue{M: &m}
+	default:
+		return cachedAttributeValue{NULL: true}
+	}
+}
+
+func (v cachedAttributeValue) decode() types.AttributeValue {
+	switch {
+	case v.S != nil:
+		return &types.AttributeValueMemberS{Value: *v.S}
+	case v.N != nil:
+		return &types.AttributeValueMemberN{Value: *v.N}
+	case v.B != nil:
+		return &types.AttributeValueMemberB{Value: *v.B}
+	case v.BOOL != nil:
+		return &types.AttributeValueMemberBOOL{Value: *v.BOOL}
+	case v.SS != nil:
+		return &types.AttributeValueMemberSS{Value: v.SS}
+	case v.NS != nil:
+		return &types.AttributeValueMemberNS{Value: v.NS}
+	case v.BS != nil:
+		return &types.AttributeValueMemberBS{Value: v.BS}
+	case v.L != nil:
+		list := make([]types.AttributeValue, 0, len(*v.L))
+		for _, element := range *v.L {
+			list = append(list, element.decode())
+		}
+		return &types.AttributeValueMemberL{Value: list}
+	case v.M != nil:
+		return &types.AttributeValueMemberM{Value: cachedItem(*v.M).decode()}
+	default:
+		return &types.AttributeValueMemberNULL{Value: true}
+	}
+}
+
+// MemoryCache is an in-process Cache
+type MemoryCache struct {
+	mu        sync.Mutex
+	entries   map[string]memoryCacheEntry
+	nextSweep int
+	clock     kit.ClockInterface
+}
+
+type memoryCacheEntry struct {
+	value     []byte
+	expiresAt time.Time
+}
+
+// NewMemoryCache returns an empty MemoryCache
+func NewMemoryCache() *MemoryCache {
+	return &MemoryCache{
+		entries:   map[string]memoryCacheEntry{},
+		nextSweep: minMemoryCacheSweep,
+		clock:     kit.NewClock(),
+	}
+}
+
+const minMemoryCacheSweep = 1024
+
+func (c *MemoryCache) Get(ctx context.Context, key string) ([]byte, bool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, found := c.entries[key]
+	if !found {
+		return nil, false, nil
+	}
+	if !c.clock.Now().Before(entry.expiresAt) {
+		delete(c.entries, key)
+		return nil, false, nil
+	}
+
+	return entry.value, true, nil
+}
+
+func (c *MemoryCache) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := c.clock.Now()
+	c.entries[key] = memoryCacheEntry{value: value, expiresAt: now.Add(ttl)}
+
+	// Invalidated entries are never read again, so expired entries are swept as the cache grows
+	if len(c.entries) >= c.nextSweep {
+		for k, entry := range c.entries {
+			if !now.Before(entry.expiresAt) {
+				delete(c.entries, k)
+			}
+		}
+		c.nextSweep = max(minMemoryCacheSweep, 2*len(c.entries))
+	}
+
+	return nil
+}
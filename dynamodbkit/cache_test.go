@@ -0,0 +1,453 @@
+package dynamodbkit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/half-ogre/go-kit/kit"
+	"github.com/stretchr/testify/assert"
+)
+
+type erroringCache struct{}
+
+func (erroringCache) Get(ctx context.Context, key string) ([]byte, bool, error) {
+	return nil, false, errors.New("the cache error")
+}
+
+func (erroringCache) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	return errors.New("the cache error")
+}
+
+// signalingCache sends on gets after every Get, so a test can wait for readers to miss the cache
+type signalingCache struct {
+	Cache
+	gets chan struct{}
+}
+
+func (c signalingCache) Get(ctx context.Context, key string) ([]byte, bool, error) {
+	value, found, err := c.Cache.Get(ctx, key)
+	c.gets <- struct{}{}
+	return value, found, err
+}
+
+func newCachedTestContext(db DynamoDB, cache Cache) context.Context {
+	return WithClient(context.Background(), NewClient(WithCache(db, cache, time.Minute)))
+}
+
+func TestGetItemWithCache(t *testing.T) {
+	t.Run("reads_a_cached_item_without_calling_dynamodb", func(t *testing.T) {
+		calls := 0
+		fakeDB := &FakeDynamoDB{
+			GetItemFake: func(ctx context.Context, params *dynamodb.GetItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.GetItemOutput, error) {
+				calls++
+				return &dynamodb.GetItemOutput{Item: mustMarshalMap(t, TestUser{ID: "theID", Name: "theName"})}, nil
+			},
+		}
+		ctx := newCachedTestContext(fakeDB, NewMemoryCache())
+
+		first, err := GetItem[TestUser](ctx, "aCachedGetTable", "id", "theID")
+		assert.NoError(t, err)
+		second, err := GetItem[TestUser](ctx, "aCachedGetTable", "id", "theID")
+
+		assert.NoError(t, err)
+		assert.Equal(t, 1, calls)
+		assert.Equal(t, first, second)
+		assert.Equal(t, "theName", second.Name)
+	})
+
+	t.Run("caches_each_key_separately", func(t *testing.T) {
+		calls := 0
+		fakeDB := &FakeDynamoDB{
+			GetItemFake: func(ctx context.Context, params *dynamodb.GetItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.GetItemOutput, error) {
+				calls++
+				id := params.Key["id"].(*types.AttributeValueMemberS).Value
+				return &dynamodb.GetItemOutput{Item: mustMarshalMap(t, TestUser{ID: id})}, nil
+			},
+		}
+		ctx := newCachedTestContext(fakeDB, NewMemoryCache())
+
+		first, _ := GetItem[TestUser](ctx, "aCachedGetTable", "id", "theFirstID")
+		second, _ := GetItem[TestUser](ctx, "aCachedGetTable", "id", "theSecondID")
+
+		assert.Equal(t, 2, calls)
+		assert.Equal(t, "theFirstID", first.ID)
+		assert.Equal(t, "theSecondID", second.ID)
+	})
+
+	t.Run("does_not_cache_a_missing_item", func(t *testing.T) {
+		calls := 0
+		fakeDB := &FakeDynamoDB{
+			GetItemFake: func(ctx context.Context, params *dynamodb.GetItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.GetItemOutput, error) {
+				calls++
+				return &dynamodb.GetItemOutput{}, nil
+			},
+		}
+		ctx := newCachedTestContext(fakeDB, NewMemoryCache())
+
+		_, _ = GetItem[TestUser](ctx, "aCachedGetTable", "id", "theID")
+		result, err := GetItem[TestUser](ctx, "aCachedGetTable", "id", "theID")
+
+		assert.NoError(t, err)
+		assert.Nil(t, result)
+		assert.Equal(t, 2, calls)
+	})
+
+	t.Run("reads_again_after_a_put_to_the_same_table", func(t *testing.T) {
+		calls := 0
+		fakeDB := &FakeDynamoDB{
+			GetItemFake: func(ctx context.Context, params *dynamodb.GetItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.GetItemOutput, error) {
+				calls++
+				return &dynamodb.GetItemOutput{Item: mustMarshalMap(t, TestUser{ID: "theID"})}, nil
+			},
+			PutItemFake: func(ctx context.Context, params *dynamodb.PutItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.PutItemOutput, error) {
+				return &dynamodb.PutItemOutput{}, nil
+			},
+		}
+		ctx := newCachedTestContext(fakeDB, NewMemoryCache())
+
+		_, _ = GetItem[TestUser](ctx, "aCachedGetTable", "id", "theID")
+		err := PutItem(ctx, "aCachedGetTable", TestUser{ID: "theID"})
+		assert.NoError(t, err)
+		_, _ = GetItem[TestUser](ctx, "aCachedGetTable", "id", "theID")
+
+		assert.Equal(t, 2, calls)
+	})
+
+	t.Run("reads_again_after_a_delete_from_the_same_table", func(t *testing.T) {
+		calls := 0
+		fakeDB := &FakeDynamoDB{
+			GetItemFake: func(ctx context.Context, params *dynamodb.GetItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.GetItemOutput, error) {
+				calls++
+				return &dynamodb.GetItemOutput{Item: mustMarshalMap(t, TestUser{ID: "theID"})}, nil
+			},
+			DeleteItemFake: func(ctx context.Context, params *dynamodb.DeleteItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.DeleteItemOutput, error) {
+				return &dynamodb.DeleteItemOutput{}, nil
+			},
+		}
+		ctx := newCachedTestContext(fakeDB, NewMemoryCache())
+
+		_, _ = GetItem[TestUser](ctx, "aCachedGetTable", "id", "theID")
+		err := DeleteItem(ctx, "aCachedGetTable", "id", "theID")
+		assert.NoError(t, err)
+		_, _ = GetItem[TestUser](ctx, "aCachedGetTable", "id", "theID")
+
+		assert.Equal(t, 2, calls)
+	})
+
+	t.Run("loads_a_missed_item_once_for_concurrent_readers", func(t *testing.T) {
+		var calls atomic.Int32
+		release := make(chan struct{})
+		fakeDB := &FakeDynamoDB{
+			GetItemFake: func(ctx context.Context, params *dynamodb.GetItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.GetItemOutput, error) {
+				calls.Add(1)
+				<-release
+				return &dynamodb.GetItemOutput{Item: mustMarshalMap(t, TestUser{ID: "theID", Name: "theName"})}, nil
+			},
+		}
+		cache := signalingCache{Cache: NewMemoryCache(), gets: make(chan struct{}, 20)}
+		ctx := newCachedTestContext(fakeDB, cache)
+
+		var wg sync.WaitGroup
+		names := make([]string, 5)
+		for i := range names {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				user, err := GetItem[TestUser](ctx, "aConcurrentlyCachedGetTable", "id", "theID")
+				assert.NoError(t, err)
+				names[i] = user.Name
+			}()
+		}
+		// Every reader has missed the cache before the load is let go
+		for range names {
+			<-cache.gets
+		}
+		close(release)
+		wg.Wait()
+
+		assert.Equal(t, int32(1), calls.Load())
+		assert.Equal(t, []string{"theName", "theName", "theName", "theName", "theName"}, names)
+	})
+
+	t.Run("returns_when_a_waiting_reader_is_canceled_and_finishes_the_load", func(t *testing.T) {
+		release := make(chan struct{})
+		loadErrs := make(chan error, 1)
+		calls := 0
+		fakeDB := &FakeDynamoDB{
+			GetItemFake: func(ctx context.Context, params *dynamodb.GetItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.GetItemOutput, error) {
+				calls++
+				<-release
+				loadErrs <- ctx.Err()
+				return &dynamodb.GetItemOutput{Item: mustMarshalMap(t, TestUser{ID: "theID", Name: "theName"})}, nil
+			},
+		}
+		ctx := newCachedTestContext(fakeDB, NewMemoryCache())
+		canceledCtx, cancel := context.WithCancel(ctx)
+		cancel()
+
+		_, err := GetItem[TestUser](canceledCtx, "aCanceledCachedGetTable", "id", "theID")
+		assert.ErrorIs(t, err, context.Canceled)
+		close(release)
+		assert.NoError(t, <-loadErrs)
+		user, err := GetItem[TestUser](ctx, "aCanceledCachedGetTable", "id", "theID")
+
+		assert.NoError(t, err)
+		assert.Equal(t, "theName", user.Name)
+		assert.Equal(t, 1, calls)
+	})
+
+	t.Run("does_not_share_entries_or_invalidations_with_another_client", func(t *testing.T) {
+		calls := 0
+		fakeDB := &FakeDynamoDB{
+			GetItemFake: func(ctx context.Context, params *dynamodb.GetItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.GetItemOutput, error) {
+				calls++
+				return &dynamodb.GetItemOutput{Item: mustMarshalMap(t, TestUser{ID: "theID"})}, nil
+			},
+			PutItemFake: func(ctx context.Context, params *dynamodb.PutItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.PutItemOutput, error) {
+				return &dynamodb.PutItemOutput{}, nil
+			},
+		}
+		ctx := newCachedTestContext(fakeDB, NewMemoryCache())
+		uncachedCtx := WithClient(context.Background(), NewClient(fakeDB))
+
+		_, _ = GetItem[TestUser](ctx, "aCachedGetTable", "id", "theID")
+		err := PutItem(uncachedCtx, "aCachedGetTable", TestUser{ID: "theID"})
+		assert.NoError(t, err)
+		_, _ = GetItem[TestUser](ctx, "aCachedGetTable", "id", "theID")
+
+		assert.Equal(t, 1, calls)
+	})
+
+	t.Run("reads_again_after_an_update_to_the_same_table", func(t *testing.T) {
+		calls := 0
+		fakeDB := &FakeDynamoDB{
+			GetItemFake: func(ctx context.Context, params *dynamodb.GetItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.GetItemOutput, error) {
+				calls++
+				return &dynamodb.GetItemOutput{Item: mustMarshalMap(t, TestUser{ID: "theID"})}, nil
+			},
+			UpdateItemFake: func(ctx context.Context, params *dynamodb.UpdateItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.UpdateItemOutput, error) {
+				return &dynamodb.UpdateItemOutput{}, nil
+			},
+		}
+		cachedDB := WithCache(fakeDB, NewMemoryCache(), time.Minute)
+		ctx := WithClient(context.Background(), NewClient(cachedDB))
+
+		_, _ = GetItem[TestUser](ctx, "aCachedGetTable", "id", "theID")
+		_, err := cachedDB.UpdateItem(ctx, &dynamodb.UpdateItemInput{TableName: aws.String("aCachedGetTable")})
+		assert.NoError(t, err)
+		_, _ = GetItem[TestUser](ctx, "aCachedGetTable", "id", "theID")
+
+		assert.Equal(t, 2, calls)
+	})
+
+	t.Run("falls_back_to_dynamodb_when_the_cache_fails", func(t *testing.T) {
+		fakeDB := &FakeDynamoDB{
+			GetItemFake: func(ctx context.Context, params *dynamodb.GetItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.GetItemOutput, error) {
+				return &dynamodb.GetItemOutput{Item: mustMarshalMap(t, TestUser{ID: "theID"})}, nil
+			},
+		}
+		ctx := newCachedTestContext(fakeDB, erroringCache{})
+
+		result, err := GetItem[TestUser](ctx, "aCachedGetTable", "id", "theID")
+
+		assert.NoError(t, err)
+		assert.Equal(t, "theID", result.ID)
+	})
+}
+
+func TestQueryWithCache(t *testing.T) {
+	t.Run("reads_a_cached_page_without_calling_dynamodb", func(t *testing.T) {
+		calls := 0
+		fakeDB := &FakeDynamoDB{
+			QueryFake: func(ctx context.Context, params *dynamodb.QueryInput, optFns ...func(*dynamodb.Options)) (*dynamodb.QueryOutput, error) {
+				calls++
+				return &dynamodb.QueryOutput{
+					Items:            []map[string]types.AttributeValue{mustMarshalMap(t, TestUser{ID: "theID", Name: "theName"})},
+					LastEvaluatedKey: mustMarshalMap(t, map[string]string{"id": "theID"}),
+				}, nil
+			},
+		}
+		ctx := newCachedTestContext(fakeDB, NewMemoryCache())
+
+		first, err := Query[TestUser](ctx, "aCachedQueryTable", "id", "theID")
+		assert.NoError(t, err)
+		second, err := Query[TestUser](ctx, "aCachedQueryTable", "id", "theID")
+
+		assert.NoError(t, err)
+		assert.Equal(t, 1, calls)
+		assert.Equal(t, first, second)
+	})
+
+	t.Run("caches_each_page_separately", func(t *testing.T) {
+		calls := 0
+		fakeDB := &FakeDynamoDB{
+			QueryFake: func(ctx context.Context, params *dynamodb.QueryInput, optFns ...func(*dynamodb.Options)) (*dynamodb.QueryOutput, error) {
+				calls++
+				return &dynamodb.QueryOutput{Items: []map[string]types.AttributeValue{}}, nil
+			},
+		}
+		ctx := newCachedTestContext(fakeDB, NewMemoryCache())
+
+		_, _ = Query[TestUser](ctx, "aCachedQueryTable", "id", "theID")
+		_, _ = Query[TestUser](ctx, "aCachedQueryTable", "id", "theID", WithQueryLimit(10))
+
+		assert.Equal(t, 2, calls)
+	})
+
+	t.Run("reads_again_after_a_put_to_the_same_table", func(t *testing.T) {
+		calls := 0
+		fakeDB := &FakeDynamoDB{
+			QueryFake: func(ctx context.Context, params *dynamodb.QueryInput, optFns ...func(*dynamodb.Options)) (*dynamodb.QueryOutput, error) {
+				calls++
+				return &dynamodb.QueryOutput{Items: []map[string]types.AttributeValue{}}, nil
+			},
+			PutItemFake: func(ctx context.Context, params *dynamodb.PutItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.PutItemOutput, error) {
+				return &dynamodb.PutItemOutput{}, nil
+			},
+		}
+		ctx := newCachedTestContext(fakeDB, NewMemoryCache())
+
+		_, _ = Query[TestUser](ctx, "aCachedQueryTable", "id", "theID")
+		_ = PutItem(ctx, "aCachedQueryTable", TestUser{ID: "theID"})
+		_, _ = Query[TestUser](ctx, "aCachedQueryTable", "id", "theID")
+
+		assert.Equal(t, 2, calls)
+	})
+}
+
+func TestCachedItem(t *testing.T) {
+	t.Run("round_trips_every_attribute_type", func(t *testing.T) {
+		item := map[string]types.AttributeValue{
+			"s":    &types.AttributeValueMemberS{Value: "theString"},
+			"n":    &types.AttributeValueMemberN{Value: "12345678901234567890.5"},
+			"b":    &types.AttributeValueMemberB{Value: []byte("theBytes")},
+			"bool": &types.AttributeValueMemberBOOL{Value: false},
+			"null": &types.AttributeValueMemberNULL{Value: true},
+			"ss":   &types.AttributeValueMemberSS{Value: []string{"a", "b"}},
+			"ns":   &types.AttributeValueMemberNS{Value: []string{"1", "2"}},
+			"bs":   &types.AttributeValueMemberBS{Value: [][]byte{[]byte("a")}},
+			"l":    &types.AttributeValueMemberL{Value: []types.AttributeValue{&types.AttributeValueMemberS{Value: "theElement"}}},
+			"el":   &types.AttributeValueMemberL{Value: []types.AttributeValue{}},
+			"m":    &types.AttributeValueMemberM{Value: map[string]types.AttributeValue{"nested": &types.AttributeValueMemberN{Value: "1"}}},
+			"em":   &types.AttributeValueMemberM{Value: map[string]types.AttributeValue{}},
+		}
+
+		cache := NewMemoryCache()
+		writeCachedValue(context.Background(), cache, "theKey", encodeCachedItem(item), time.Minute)
+
+		var cached cachedItem
+		found := readCachedValue(context.Background(), cache, "theKey", &cached)
+
+		assert.True(t, found)
+		assert.Equal(t, item, cached.decode())
+	})
+}
+
+func TestMemoryCache(t *testing.T) {
+	t.Run("returns_a_value_until_it_expires", func(t *testing.T) {
+		now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+		cache := NewMemoryCache()
+		cache.clock = kit.NewClock(kit.WithFake(func() time.Time { return now }))
+
+		err := cache.Set(context.Background(), "theKey", []byte("theValue"), time.Minute)
+		assert.NoError(t, err)
+
+		value, found, err := cache.Get(context.Background(), "theKey")
+		assert.NoError(t, err)
+		assert.True(t, found)
+		assert.Equal(t, []byte("theValue"), value)
+
+		now = now.Add(time.Minute)
+		value, found, err = cache.Get(context.Background(), "theKey")
+		assert.NoError(t, err)
+		assert.False(t, found)
+		assert.Nil(t, value)
+	})
+
+	t.Run("sweeps_expired_entries_as_it_grows", func(t *testing.T) {
+		now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+		cache := NewMemoryCache()
+		cache.clock = kit.NewClock(kit.WithFake(func() time.Time { return now }))
+
+		for i := range minMemoryCacheSweep - 1 {
+			_ = cache.Set(context.Background(), fmt.Sprintf("aKey%d", i), []byte("aValue"), time.Second)
+		}
+		now = now.Add(time.Second)
+		_ = cache.Set(context.Background(), "theKey", []byte("theValue"), time.Minute)
+
+		assert.Len(t, cache.entries, 1)
+	})
+}
+
+func TestExecuteStatementWithCache(t *testing.T) {
+	t.Run("reads_again_after_a_statement_writes_the_same_table", func(t *testing.T) {
+		calls := 0
+		fakeDB := &FakeDynamoDB{
+			GetItemFake: func(ctx context.Context, params *dynamodb.GetItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.GetItemOutput, error) {
+				calls++
+				return &dynamodb.GetItemOutput{Item: mustMarshalMap(t, TestUser{ID: "theID"})}, nil
+			},
+			ExecuteStatementFake: func(ctx context.Context, params *dynamodb.ExecuteStatementInput, optFns ...func(*dynamodb.Options)) (*dynamodb.ExecuteStatementOutput, error) {
+				return &dynamodb.ExecuteStatementOutput{}, nil
+			},
+		}
+		ctx := newCachedTestContext(fakeDB, NewMemoryCache())
+
+		_, _ = GetItem[TestUser](ctx, "aCachedStatementTable", "id", "theID")
+		_, err := ExecuteStatement[TestUser](ctx, `UPDATE "aCachedStatementTable" SET name = ? WHERE id = ?`, "theName", "theID")
+		assert.NoError(t, err)
+		_, _ = GetItem[TestUser](ctx, "aCachedStatementTable", "id", "theID")
+
+		assert.Equal(t, 2, calls)
+	})
+
+	t.Run("reads_again_after_a_statement_writes_a_table_it_cannot_tell", func(t *testing.T) {
+		calls := 0
+		fakeDB := &FakeDynamoDB{
+			GetItemFake: func(ctx context.Context, params *dynamodb.GetItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.GetItemOutput, error) {
+				calls++
+				return &dynamodb.GetItemOutput{Item: mustMarshalMap(t, TestUser{ID: "theID"})}, nil
+			},
+			ExecuteStatementFake: func(ctx context.Context, params *dynamodb.ExecuteStatementInput, optFns ...func(*dynamodb.Options)) (*dynamodb.ExecuteStatementOutput, error) {
+				return &dynamodb.ExecuteStatementOutput{}, nil
+			},
+		}
+		ctx := newCachedTestContext(fakeDB, NewMemoryCache())
+
+		_, _ = GetItem[TestUser](ctx, "aCachedStatementTable", "id", "theID")
+		_, err := ExecuteStatement[TestUser](ctx, `the unparsable statement`)
+		assert.NoError(t, err)
+		_, _ = GetItem[TestUser](ctx, "aCachedStatementTable", "id", "theID")
+
+		assert.Equal(t, 2, calls)
+	})
+
+	t.Run("keeps_cached_reads_after_a_select", func(t *testing.T) {
+		calls := 0
+		fakeDB := &FakeDynamoDB{
+			GetItemFake: func(ctx context.Context, params *dynamodb.GetItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.GetItemOutput, error) {
+				calls++
+				return &dynamodb.GetItemOutput{Item: mustMarshalMap(t, TestUser{ID: "theID"})}, nil
+			},
+			ExecuteStatementFake: func(ctx context.Context, params *dynamodb.ExecuteStatementInput, optFns ...func(*dynamodb.Options)) (*dynamodb.ExecuteStatementOutput, error) {
+				return &dynamodb.ExecuteStatementOutput{}, nil
+			},
+		}
+		ctx := newCachedTestContext(fakeDB, NewMemoryCache())
+
+		_, _ = GetItem[TestUser](ctx, "aCachedStatementTable", "id", "theID")
+		_, err := ExecuteStatement[TestUser](ctx, `SELECT * FROM "aCachedStatementTable"`)
+		assert.NoError(t, err)
+		_, _ = GetItem[TestUser](ctx, "aCachedStatementTable", "id", "theID")
+
+		assert.Equal(t, 1, calls)
+	})
+}
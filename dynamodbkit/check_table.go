@@ -0,0 +1,86 @@
+package dynamodbkit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/half-ogre/go-kit/healthkit"
+	"github.com/half-ogre/go-kit/kit"
+)
+
+// CheckTableActive describes a table, with the client's table name prefix and suffix, and returns an error unless
+// it's ACTIVE or UPDATING, e.g. for a readiness check. A table being updated still serves reads and
+// writes, so only CREATING, DELETING, and ARCHIVED tables fail.
+func CheckTableActive(ctx context.Context, tableName string) (err error) {
+	op := newOperation(ctx, "CheckTableActive", tableName)
+	defer op.wrap(&err)
+
+	if ctx == nil {
+		return errors.New("context cannot be nil")
+	}
+	if tableName == "" {
+		return errors.New("table name cannot be empty")
+	}
+
+	db, err := op.dynamoDB(ctx)
+	if err != nil {
+		return kit.WrapError(err, "error creating DynamoDB client")
+	}
+
+	tableName = op.client.TableName(tableName)
+	op.table = tableName
+
+	table, err := describeTable(ctx, db, tableName)
+	if err != nil {
+		return err
+	}
+
+	switch status := table.TableStatus; status {
+	case types.TableStatusActive, types.TableStatusUpdating:
+		return nil
+	default:
+		return fmt.Errorf("table status is %s", status)
+	}
+}
+
+// TableHealthCheck returns a health check named "dynamodb:<table>" that runs CheckTableActive
+func TableHealthCheck(tableName string) healthkit.HealthCheck {
+	return healthkit.NewCheck("dynamodb:"+tableName, func(ctx context.Context) error {
+		return CheckTableActive(ctx, tableName)
+	})
+}
+
+func describeTable(ctx context.Context, db DynamoDB, tableName string) (*types.TableDescription, error) {
+	describer, err := requireAPI[DescribeTableAPI](db, "DescribeTable")
+	if err != nil {
+		return nil, err
+	}
+
+	output, err := describer.DescribeTable(ctx, &dynamodb.DescribeTableInput{TableName: aws.String(tableName)})
+	if err != nil {
+		return nil, kit.WrapError(err, "error describing table")
+	}
+	if output.Table == nil {
+		return nil, errors.New("describe table returned no table")
+	}
+
+	return output.Table, nil
+}
+
+// describeKeyNames returns the names of a table's key attributes, partition key first
+func describeKeyNames(ctx context.Context, db DynamoDB, tableName string) ([]string, error) {
+	table, err := describeTable(ctx, db, tableName)
+	if err != nil {
+		return nil, err
+	}
+
+	keyNames := make([]string, 0, len(table.KeySchema))
+	for _, element := range table.KeySchema {
+		keyNames = append(keyNames, aws.ToString(element.AttributeName))
+	}
+	return keyNames, nil
+}
@@ -0,0 +1,113 @@
+package dynamodbkit
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func setFakeTableStatus(t *testing.T, status types.TableStatus, actualTableName *string) {
+	fakeDB := &FakeDynamoDB{
+		DescribeTableFake: func(ctx context.Context, params *dynamodb.DescribeTableInput, optFns ...func(*dynamodb.Options)) (*dynamodb.DescribeTableOutput, error) {
+			if actualTableName != nil {
+				*actualTableName = aws.ToString(params.TableName)
+			}
+			return &dynamodb.DescribeTableOutput{Table: &types.TableDescription{TableStatus: status}}, nil
+		},
+	}
+	setFake(func(ctx context.Context) (DynamoDB, error) { return fakeDB, nil })
+	t.Cleanup(func() { setFake(nil) })
+}
+
+func TestCheckTableActive(t *testing.T) {
+	t.Run("returns_nil_for_an_active_table", func(t *testing.T) {
+		var actualTableName string
+		setFakeTableStatus(t, types.TableStatusActive, &actualTableName)
+
+		err := CheckTableActive(context.Background(), "theTable")
+
+		assert.NoError(t, err)
+		assert.Equal(t, "theTable", actualTableName)
+	})
+
+	t.Run("returns_nil_for_a_table_being_updated", func(t *testing.T) {
+		setFakeTableStatus(t, types.TableStatusUpdating, nil)
+
+		err := CheckTableActive(context.Background(), "theTable")
+
+		assert.NoError(t, err)
+	})
+
+	t.Run("returns_an_error_for_a_table_that_is_not_active", func(t *testing.T) {
+		setFakeTableStatus(t, types.TableStatusCreating, nil)
+
+		err := CheckTableActive(context.Background(), "theTable")
+
+		assert.EqualError(t, err, "dynamodbkit.CheckTableActive table=theTable: table status is CREATING")
+	})
+
+	t.Run("describes_the_table_with_the_global_suffix", func(t *testing.T) {
+		var actualTableName string
+		setFakeTableStatus(t, types.TableStatusActive, &actualTableName)
+		UseTableNameSuffix("-theSuffix")
+		t.Cleanup(func() { UseTableNameSuffix("") })
+
+		err := CheckTableActive(context.Background(), "theTable")
+
+		assert.NoError(t, err)
+		assert.Equal(t, "theTable-theSuffix", actualTableName)
+	})
+
+	t.Run("returns_the_error_from_describe_table", func(t *testing.T) {
+		fakeDB := &FakeDynamoDB{
+			DescribeTableFake: func(ctx context.Context, params *dynamodb.DescribeTableInput, optFns ...func(*dynamodb.Options)) (*dynamodb.DescribeTableOutput, error) {
+				return nil, errors.New("the describe error")
+			},
+		}
+		setFake(func(ctx context.Context) (DynamoDB, error) { return fakeDB, nil })
+		t.Cleanup(func() { setFake(nil) })
+
+		err := CheckTableActive(context.Background(), "theTable")
+
+		assert.EqualError(t, err, "dynamodbkit.CheckTableActive table=theTable: error describing table: the describe error")
+	})
+
+	t.Run("returns_an_error_when_the_table_is_missing_from_the_output", func(t *testing.T) {
+		fakeDB := &FakeDynamoDB{
+			DescribeTableFake: func(ctx context.Context, params *dynamodb.DescribeTableInput, optFns ...func(*dynamodb.Options)) (*dynamodb.DescribeTableOutput, error) {
+				return &dynamodb.DescribeTableOutput{}, nil
+			},
+		}
+		setFake(func(ctx context.Context) (DynamoDB, error) { return fakeDB, nil })
+		t.Cleanup(func() { setFake(nil) })
+
+		err := CheckTableActive(context.Background(), "theTable")
+
+		assert.EqualError(t, err, "dynamodbkit.CheckTableActive table=theTable: describe table returned no table")
+	})
+
+	t.Run("returns_an_error_when_table_name_is_empty", func(t *testing.T) {
+		err := CheckTableActive(context.Background(), "")
+
+		assert.EqualError(t, err, "dynamodbkit.CheckTableActive: table name cannot be empty")
+	})
+}
+
+func TestTableHealthCheck(t *testing.T) {
+	t.Run("is_named_for_the_table", func(t *testing.T) {
+		assert.Equal(t, "dynamodb:theTable", TableHealthCheck("theTable").Name())
+	})
+
+	t.Run("checks_the_table_is_active", func(t *testing.T) {
+		setFakeTableStatus(t, types.TableStatusDeleting, nil)
+
+		err := TableHealthCheck("theTable").Check(context.Background())
+
+		assert.EqualError(t, err, "dynamodbkit.CheckTableActive table=theTable: table status is DELETING")
+	})
+}
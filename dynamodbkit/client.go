@@ -0,0 +1,209 @@
+package dynamodbkit
+
+import (
+	"context"
+	"sync"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/half-ogre/go-kit/awskit"
+	"github.com/half-ogre/go-kit/tenantkit"
+)
+
+// Client is a DynamoDB connection together with the configuration operations use with it: the table
+// name prefix and suffix, auditing, instrumentation, consumed capacity, retries, debug logging, and
+// key redaction. Operations use the client carried by their context (see WithClient) and otherwise
+// the default client, which uses awskit.DefaultConfig and is what the package-level Use functions
+// configure. Giving each AWS account, or each parallel test, its own client keeps them from sharing
+// any state.
+type Client struct {
+	newDynamoDB      func(ctx context.Context) (DynamoDB, error)
+	tableNamePrefix  string
+	tableNameSuffix  string
+	audit            *auditConfig
+	instrumentation  func(ctx context.Context, metrics OperationMetrics)
+	consumedCapacity types.ReturnConsumedCapacity
+	retryPolicy      RetryPolicy
+	debugLogging     bool
+	keyRedaction     bool
+}
+
+// ClientOption configures NewClient
+type ClientOption func(*Client)
+
+// NewClient returns a client for db, e.g. dynamodb.NewFromConfig(cfg) or a FakeDynamoDB
+func NewClient(db DynamoDB, options ...ClientOption) *Client {
+	client := &Client{
+		newDynamoDB: func(ctx context.Context) (DynamoDB, error) { return db, nil },
+	}
+	for _, option := range options {
+		option(client)
+	}
+	return client
+}
+
+// WithClientTableNamePrefix adds prefix to the start of every table name, e.g. "tenant1-"
+func WithClientTableNamePrefix(prefix string) ClientOption {
+	return func(c *Client) {
+		c.tableNamePrefix = prefix
+	}
+}
+
+// WithClientTableNameSuffix adds suffix to the end of every table name, e.g. "-dev", unless an
+// operation's own table name suffix option replaces it
+func WithClientTableNameSuffix(suffix string) ClientOption {
+	return func(c *Client) {
+		c.tableNameSuffix = suffix
+	}
+}
+
+// WithClientAuditing turns on change auditing as described on UseAuditing; an empty table name turns it off
+func WithClientAuditing(auditTableName string, options ...AuditOption) ClientOption {
+	return func(c *Client) {
+		c.audit = newAuditConfig(auditTableName, options)
+	}
+}
+
+// WithClientInstrumentation sets the function that receives OperationMetrics, as described on
+// UseInstrumentation; nil turns it off
+func WithClientInstrumentation(record func(ctx context.Context, metrics OperationMetrics)) ClientOption {
+	return func(c *Client) {
+		c.instrumentation = record
+	}
+}
+
+// WithClientMetricsHook sets hook to receive OperationMetrics, as described on UseMetricsHook; nil
+// turns it off
+func WithClientMetricsHook(hook MetricsHook) ClientOption {
+	return func(c *Client) {
+		c.instrumentation = nil
+		if hook != nil {
+			c.instrumentation = hook.RecordOperation
+		}
+	}
+}
+
+// WithClientReturnConsumedCapacity sets the consumed capacity every operation asks DynamoDB for, as
+// described on UseReturnConsumedCapacity
+func WithClientReturnConsumedCapacity(level types.ReturnConsumedCapacity) ClientOption {
+	return func(c *Client) {
+		c.consumedCapacity = level
+	}
+}
+
+// WithClientDebugLogging turns query plan logging on or off, as described on UseDebugLogging
+func WithClientDebugLogging(enabled bool) ClientOption {
+	return func(c *Client) {
+		c.debugLogging = enabled
+	}
+}
+
+// WithClientKeyRedaction turns key redaction in errors on or off, as described on UseKeyRedaction
+func WithClientKeyRedaction(enabled bool) ClientOption {
+	return func(c *Client) {
+		c.keyRedaction = enabled
+	}
+}
+
+// TableName returns name with the client's prefix and suffix, the name operations use for it
+func (c *Client) TableName(name string) string {
+	return c.tableNamePrefix + name + c.tableNameSuffix
+}
+
+// TableName returns name with the prefix and suffix of the client carried by ctx, or the default
+// client, e.g. to write a table name into a PartiQL statement for ExecuteStatement
+func TableName(ctx context.Context, name string) string {
+	return getClient(ctx).TableName(name)
+}
+
+// qualifyTableName applies the client's prefix to an input's table name, and its suffix too unless an
+// option replaced the table name pointer, e.g. with an operation's own table name suffix
+func (c *Client) qualifyTableName(tableName *string, original *string) *string {
+	if tableName == original {
+		return aws.String(c.TableName(aws.ToString(tableName)))
+	}
+	return aws.String(c.tableNamePrefix + aws.ToString(tableName))
+}
+
+// ForTenant returns a copy of the client whose table names end with the tenant's table suffix, e.g.
+// users-dev-acme, for a table per tenant
+func (c *Client) ForTenant(tenant tenantkit.Tenant) *Client {
+	scoped := *c
+	scoped.tableNameSuffix = c.tableNameSuffix + tenant.TableSuffix()
+	return &scoped
+}
+
+type clientKey struct{}
+
+// WithClient returns a copy of ctx whose operations use client instead of the default client
+func WithClient(ctx context.Context, client *Client) context.Context {
+	return context.WithValue(ctx, clientKey{}, client)
+}
+
+// WithTenant returns a copy of ctx whose operations use the tenant's tables, as described on
+// Client.ForTenant, for the tenant carried by ctx, e.g. by tenantkit.Middleware. It returns
+// tenantkit.ErrNoTenant if ctx has no tenant.
+func WithTenant(ctx context.Context) (context.Context, error) {
+	tenant, err := tenantkit.Require(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return WithClient(ctx, getClient(ctx).ForTenant(tenant)), nil
+}
+
+// getClient returns the client carried by ctx, or the default client
+func getClient(ctx context.Context) *Client {
+	if ctx != nil {
+		if client, ok := ctx.Value(clientKey{}).(*Client); ok && client != nil {
+			return client
+		}
+	}
+	return DefaultClient()
+}
+
+var defaultClient = &Client{newDynamoDB: loadDynamoDB}
+var defaultClientMu sync.Mutex
+
+// DefaultClient returns the client operations use when their context doesn't carry one
+func DefaultClient() *Client {
+	defaultClientMu.Lock()
+	defer defaultClientMu.Unlock()
+	return defaultClient
+}
+
+// SetDefaultClient replaces the client operations use when their context doesn't carry one
+func SetDefaultClient(client *Client) {
+	defaultClientMu.Lock()
+	defer defaultClientMu.Unlock()
+	defaultClient = client
+}
+
+// updateDefaultClient replaces the default client with a copy changed by option, so operations
+// already using the old one aren't changed under them
+func updateDefaultClient(option ClientOption) {
+	defaultClientMu.Lock()
+	defer defaultClientMu.Unlock()
+	updated := *defaultClient
+	option(&updated)
+	defaultClient = &updated
+}
+
+func loadDynamoDB(ctx context.Context) (DynamoDB, error) {
+	cfg, err := awskit.DefaultConfig(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return dynamodb.NewFromConfig(cfg), nil
+}
+
+// setFake makes the default client use fake instead of awskit.DefaultConfig; nil restores it
+func setFake(fake func(ctx context.Context) (DynamoDB, error)) {
+	updateDefaultClient(func(c *Client) {
+		c.newDynamoDB = fake
+		if fake == nil {
+			c.newDynamoDB = loadDynamoDB
+		}
+	})
+}
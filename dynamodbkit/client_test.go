@@ -0,0 +1,149 @@
+package dynamodbkit
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/half-ogre/go-kit/tenantkit"
+)
+
+type clientTestItem struct {
+	ID string `dynamodbav:"id"`
+}
+
+func newTableNameRecordingDB(tableNames *[]string) *FakeDynamoDB {
+	return &FakeDynamoDB{
+		GetItemFake: func(ctx context.Context, params *dynamodb.GetItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.GetItemOutput, error) {
+			*tableNames = append(*tableNames, *params.TableName)
+			return &dynamodb.GetItemOutput{Item: map[string]types.AttributeValue{"id": &types.AttributeValueMemberS{Value: "theID"}}}, nil
+		},
+		PutItemFake: func(ctx context.Context, params *dynamodb.PutItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.PutItemOutput, error) {
+			*tableNames = append(*tableNames, *params.TableName)
+			return &dynamodb.PutItemOutput{}, nil
+		},
+	}
+}
+
+func TestWithClient(t *testing.T) {
+	t.Run("uses_the_client_carried_by_the_context", func(t *testing.T) {
+		t.Parallel()
+		tableNames := []string{}
+		client := NewClient(newTableNameRecordingDB(&tableNames))
+		ctx := WithClient(context.Background(), client)
+
+		item, err := GetItem[clientTestItem](ctx, "theTableName", "id", "theID")
+
+		assert.NoError(t, err)
+		assert.Equal(t, &clientTestItem{ID: "theID"}, item)
+		assert.Equal(t, []string{"theTableName"}, tableNames)
+	})
+
+	t.Run("applies_the_client_table_name_prefix_and_suffix", func(t *testing.T) {
+		t.Parallel()
+		tableNames := []string{}
+		client := NewClient(newTableNameRecordingDB(&tableNames), WithClientTableNamePrefix("thePrefix-"), WithClientTableNameSuffix("-theSuffix"))
+		ctx := WithClient(context.Background(), client)
+
+		_, err := GetItem[clientTestItem](ctx, "theTableName", "id", "theID")
+		assert.NoError(t, err)
+		err = PutItem(ctx, "theTableName", clientTestItem{ID: "theID"}, WithPutItemTableNameSuffix("-theOptionSuffix"))
+		assert.NoError(t, err)
+
+		assert.Equal(t, []string{"thePrefix-theTableName-theSuffix", "thePrefix-theTableName-theOptionSuffix"}, tableNames)
+	})
+
+	t.Run("keeps_clients_in_different_contexts_apart", func(t *testing.T) {
+		t.Parallel()
+		aTableNames, anotherTableNames := []string{}, []string{}
+		aCtx := WithClient(context.Background(), NewClient(newTableNameRecordingDB(&aTableNames), WithClientTableNameSuffix("-a")))
+		anotherCtx := WithClient(context.Background(), NewClient(newTableNameRecordingDB(&anotherTableNames), WithClientTableNameSuffix("-another")))
+
+		_, err := GetItem[clientTestItem](aCtx, "theTableName", "id", "theID")
+		assert.NoError(t, err)
+		_, err = GetItem[clientTestItem](anotherCtx, "theTableName", "id", "theID")
+		assert.NoError(t, err)
+
+		assert.Equal(t, []string{"theTableName-a"}, aTableNames)
+		assert.Equal(t, []string{"theTableName-another"}, anotherTableNames)
+	})
+
+	t.Run("redacts_keys_for_a_client_with_key_redaction", func(t *testing.T) {
+		t.Parallel()
+		client := NewClient(&FakeDynamoDB{
+			GetItemFake: func(ctx context.Context, params *dynamodb.GetItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.GetItemOutput, error) {
+				return nil, assert.AnError
+			},
+		}, WithClientKeyRedaction(true))
+
+		_, err := GetItem[clientTestItem](WithClient(context.Background(), client), "theTableName", "id", "theSecretID")
+
+		assert.ErrorIs(t, err, assert.AnError)
+		assert.NotContains(t, err.Error(), "theSecretID")
+	})
+}
+
+func TestWithTenant(t *testing.T) {
+	t.Run("uses_the_tables_of_the_tenant_carried_by_the_context", func(t *testing.T) {
+		t.Parallel()
+		tableNames := []string{}
+		ctx := WithClient(context.Background(), NewClient(newTableNameRecordingDB(&tableNames), WithClientTableNameSuffix("-dev")))
+		ctx, err := tenantkit.WithTenant(ctx, tenantkit.Tenant{ID: "the-tenant"})
+		assert.NoError(t, err)
+
+		ctx, err = WithTenant(ctx)
+		assert.NoError(t, err)
+		_, err = GetItem[clientTestItem](ctx, "theTableName", "id", "theID")
+
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"theTableName-dev-the-tenant"}, tableNames)
+	})
+
+	t.Run("returns_an_error_without_a_tenant_in_the_context", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := WithTenant(context.Background())
+
+		assert.ErrorIs(t, err, tenantkit.ErrNoTenant)
+	})
+}
+
+func TestDefaultClient(t *testing.T) {
+	t.Run("is_used_without_a_client_in_the_context", func(t *testing.T) {
+		tableNames := []string{}
+		previous := DefaultClient()
+		SetDefaultClient(NewClient(newTableNameRecordingDB(&tableNames), WithClientTableNameSuffix("-theDefault")))
+		t.Cleanup(func() { SetDefaultClient(previous) })
+
+		_, err := GetItem[clientTestItem](context.Background(), "theTableName", "id", "theID")
+
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"theTableName-theDefault"}, tableNames)
+	})
+
+	t.Run("is_configured_by_the_use_functions", func(t *testing.T) {
+		UseTableNameSuffix("-theSuffix")
+		UseDebugLogging(true)
+		t.Cleanup(func() {
+			UseTableNameSuffix("")
+			UseDebugLogging(false)
+		})
+
+		client := DefaultClient()
+
+		assert.Equal(t, "theTableName-theSuffix", client.TableName("theTableName"))
+		assert.True(t, client.debugLogging)
+	})
+
+	t.Run("is_not_changed_under_a_caller_holding_it", func(t *testing.T) {
+		client := DefaultClient()
+
+		UseTableNameSuffix("-theSuffix")
+		t.Cleanup(func() { UseTableNameSuffix("") })
+
+		assert.Equal(t, "theTableName", client.TableName("theTableName"))
+	})
+}
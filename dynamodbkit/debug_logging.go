@@ -0,0 +1,115 @@
+package dynamodbkit
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+
+	"github.com/half-ogre/go-kit/logkit/logfields"
+)
+
+// UseDebugLogging turns query plan logging on or off. When on, Query, Scan, and ExecuteStatement log the
+// final input they send to DynamoDB (table, index, expressions, attribute names, limit)
+// at DEBUG level before executing it. Attribute values are redacted down to their type.
+func UseDebugLogging(enabled bool) {
+	updateDefaultClient(WithClientDebugLogging(enabled))
+}
+
+func logQueryInput(ctx context.Context, input *dynamodb.QueryInput) {
+	if !getClient(ctx).debugLogging {
+		return
+	}
+
+	logger.DebugContext(ctx, "dynamodbkit query plan",
+		logfields.Table(aws.ToString(input.TableName)),
+		"index", aws.ToString(input.IndexName),
+		"key_condition_expression", aws.ToString(input.KeyConditionExpression),
+		"filter_expression", aws.ToString(input.FilterExpression),
+		"projection_expression", aws.ToString(input.ProjectionExpression),
+		"expression_attribute_names", input.ExpressionAttributeNames,
+		"expression_attribute_values", redactAttributeValues(input.ExpressionAttributeValues),
+		"limit", aws.ToInt32(input.Limit),
+		"exclusive_start_key", redactAttributeValues(input.ExclusiveStartKey),
+		// DynamoDB reads forward unless ScanIndexForward is set to false
+		"scan_index_forward", input.ScanIndexForward == nil || *input.ScanIndexForward,
+	)
+}
+
+func logScanInput(ctx context.Context, input *dynamodb.ScanInput) {
+	if !getClient(ctx).debugLogging {
+		return
+	}
+
+	logger.DebugContext(ctx, "dynamodbkit scan plan",
+		logfields.Table(aws.ToString(input.TableName)),
+		"index", aws.ToString(input.IndexName),
+		"filter_expression", aws.ToString(input.FilterExpression),
+		"projection_expression", aws.ToString(input.ProjectionExpression),
+		"expression_attribute_names", input.ExpressionAttributeNames,
+		"expression_attribute_values", redactAttributeValues(input.ExpressionAttributeValues),
+		"limit", aws.ToInt32(input.Limit),
+		"exclusive_start_key", redactAttributeValues(input.ExclusiveStartKey),
+	)
+}
+
+func logStatementInput(ctx context.Context, input *dynamodb.ExecuteStatementInput) {
+	if !getClient(ctx).debugLogging {
+		return
+	}
+
+	parameters := make([]string, len(input.Parameters))
+	for i, value := range input.Parameters {
+		parameters[i] = "<redacted " + attributeValueType(value) + ">"
+	}
+
+	logger.DebugContext(ctx, "dynamodbkit statement plan",
+		"statement", aws.ToString(input.Statement),
+		"parameters", parameters,
+		"limit", aws.ToInt32(input.Limit),
+		"consistent_read", aws.ToBool(input.ConsistentRead),
+	)
+}
+
+// redactAttributeValues replaces each attribute value with a placeholder naming its
+// DynamoDB type, so the shape of an expression can be logged without its contents.
+func redactAttributeValues(values map[string]types.AttributeValue) map[string]string {
+	if values == nil {
+		return nil
+	}
+
+	redacted := make(map[string]string, len(values))
+	for key, value := range values {
+		redacted[key] = "<redacted " + attributeValueType(value) + ">"
+	}
+
+	return redacted
+}
+
+func attributeValueType(value types.AttributeValue) string {
+	switch value.(type) {
+	case *types.AttributeValueMemberS:
+		return "S"
+	case *types.AttributeValueMemberN:
+		return "N"
+	case *types.AttributeValueMemberB:
+		return "B"
+	case *types.AttributeValueMemberBOOL:
+		return "BOOL"
+	case *types.AttributeValueMemberNULL:
+		return "NULL"
+	case *types.AttributeValueMemberM:
+		return "M"
+	case *types.AttributeValueMemberL:
+		return "L"
+	case *types.AttributeValueMemberSS:
+		return "SS"
+	case *types.AttributeValueMemberNS:
+		return "NS"
+	case *types.AttributeValueMemberBS:
+		return "BS"
+	default:
+		return "unknown"
+	}
+}
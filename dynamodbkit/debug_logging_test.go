@@ -0,0 +1,138 @@
+package dynamodbkit
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUseDebugLogging(t *testing.T) {
+	t.Run("query_logs_the_query_plan_with_redacted_values_when_enabled", func(t *testing.T) {
+		var logBuf bytes.Buffer
+		slog.SetDefault(slog.New(slog.NewJSONHandler(&logBuf, &slog.HandlerOptions{Level: slog.LevelDebug})))
+		t.Cleanup(func() { slog.SetDefault(slog.Default()) })
+		UseDebugLogging(true)
+		t.Cleanup(func() { UseDebugLogging(false) })
+
+		fakeDB := &FakeDynamoDB{
+			QueryFake: func(ctx context.Context, params *dynamodb.QueryInput, optFns ...func(*dynamodb.Options)) (*dynamodb.QueryOutput, error) {
+				return &dynamodb.QueryOutput{Items: []map[string]types.AttributeValue{}}, nil
+			},
+		}
+		setFake(func(ctx context.Context) (DynamoDB, error) { return fakeDB, nil })
+		t.Cleanup(func() { setFake(nil) })
+
+		_, err := Query[TestUser](context.Background(), "theTableName", "theKey", "theSecretValue", WithQueryIndexName("theIndex"), WithQueryLimit(7))
+
+		assert.NoError(t, err)
+		logOutput := logBuf.String()
+		assert.Contains(t, logOutput, `"msg":"dynamodbkit query plan"`)
+		assert.Contains(t, logOutput, `"table":"theTableName"`)
+		assert.Contains(t, logOutput, `"index":"theIndex"`)
+		assert.Contains(t, logOutput, `"key_condition_expression":"#0 = :0"`)
+		assert.Contains(t, logOutput, `"#0":"theKey"`)
+		assert.Contains(t, logOutput, `":0":"<redacted S>"`)
+		assert.Contains(t, logOutput, `"limit":7`)
+		assert.Contains(t, logOutput, `"scan_index_forward":true`)
+		assert.NotContains(t, logOutput, "theSecretValue")
+	})
+
+	t.Run("query_logs_scan_index_forward_when_it_is_set_to_false", func(t *testing.T) {
+		var logBuf bytes.Buffer
+		slog.SetDefault(slog.New(slog.NewJSONHandler(&logBuf, &slog.HandlerOptions{Level: slog.LevelDebug})))
+		t.Cleanup(func() { slog.SetDefault(slog.Default()) })
+		UseDebugLogging(true)
+		t.Cleanup(func() { UseDebugLogging(false) })
+
+		fakeDB := &FakeDynamoDB{
+			QueryFake: func(ctx context.Context, params *dynamodb.QueryInput, optFns ...func(*dynamodb.Options)) (*dynamodb.QueryOutput, error) {
+				return &dynamodb.QueryOutput{Items: []map[string]types.AttributeValue{}}, nil
+			},
+		}
+		setFake(func(ctx context.Context) (DynamoDB, error) { return fakeDB, nil })
+		t.Cleanup(func() { setFake(nil) })
+
+		_, err := Query[TestUser](context.Background(), "theTableName", "id", "theID", QueryOption(func(input *dynamodb.QueryInput) error {
+			input.ScanIndexForward = aws.Bool(false)
+			return nil
+		}))
+
+		assert.NoError(t, err)
+		assert.Contains(t, logBuf.String(), `"scan_index_forward":false`)
+	})
+
+	t.Run("query_does_not_log_the_query_plan_when_disabled", func(t *testing.T) {
+		var logBuf bytes.Buffer
+		slog.SetDefault(slog.New(slog.NewJSONHandler(&logBuf, &slog.HandlerOptions{Level: slog.LevelDebug})))
+		t.Cleanup(func() { slog.SetDefault(slog.Default()) })
+		UseDebugLogging(false)
+
+		fakeDB := &FakeDynamoDB{
+			QueryFake: func(ctx context.Context, params *dynamodb.QueryInput, optFns ...func(*dynamodb.Options)) (*dynamodb.QueryOutput, error) {
+				return &dynamodb.QueryOutput{Items: []map[string]types.AttributeValue{}}, nil
+			},
+		}
+		setFake(func(ctx context.Context) (DynamoDB, error) { return fakeDB, nil })
+		t.Cleanup(func() { setFake(nil) })
+
+		_, err := Query[TestUser](context.Background(), "aTable", "id", "aUserID")
+
+		assert.NoError(t, err)
+		assert.NotContains(t, logBuf.String(), "dynamodbkit query plan")
+	})
+
+	t.Run("scan_logs_the_scan_plan_with_redacted_values_when_enabled", func(t *testing.T) {
+		var logBuf bytes.Buffer
+		slog.SetDefault(slog.New(slog.NewJSONHandler(&logBuf, &slog.HandlerOptions{Level: slog.LevelDebug})))
+		t.Cleanup(func() { slog.SetDefault(slog.Default()) })
+		UseDebugLogging(true)
+		t.Cleanup(func() { UseDebugLogging(false) })
+
+		fakeDB := &FakeDynamoDB{
+			ScanFake: func(ctx context.Context, params *dynamodb.ScanInput, optFns ...func(*dynamodb.Options)) (*dynamodb.ScanOutput, error) {
+				return &dynamodb.ScanOutput{Items: []map[string]types.AttributeValue{}}, nil
+			},
+		}
+		setFake(func(ctx context.Context) (DynamoDB, error) { return fakeDB, nil })
+		t.Cleanup(func() { setFake(nil) })
+
+		_, err := Scan[TestUser](context.Background(), "theTableName", WithScanExclusiveStartKey("eyJpZCI6InRoZVNlY3JldElEIn0="))
+
+		assert.NoError(t, err)
+		logOutput := logBuf.String()
+		assert.Contains(t, logOutput, `"msg":"dynamodbkit scan plan"`)
+		assert.Contains(t, logOutput, `"table":"theTableName"`)
+		assert.Contains(t, logOutput, `"id":"<redacted S>"`)
+		assert.NotContains(t, logOutput, "theSecretID")
+	})
+}
+
+func TestRedactAttributeValues(t *testing.T) {
+	t.Run("returns_nil_for_nil_values", func(t *testing.T) {
+		assert.Nil(t, redactAttributeValues(nil))
+	})
+
+	t.Run("replaces_each_value_with_its_type", func(t *testing.T) {
+		values := map[string]types.AttributeValue{
+			":s":    &types.AttributeValueMemberS{Value: "aString"},
+			":n":    &types.AttributeValueMemberN{Value: "42"},
+			":bool": &types.AttributeValueMemberBOOL{Value: true},
+			":m":    &types.AttributeValueMemberM{Value: map[string]types.AttributeValue{}},
+		}
+
+		result := redactAttributeValues(values)
+
+		assert.Equal(t, map[string]string{
+			":s":    "<redacted S>",
+			":n":    "<redacted N>",
+			":bool": "<redacted BOOL>",
+			":m":    "<redacted M>",
+		}, result)
+	})
+}
@@ -0,0 +1,77 @@
+package dynamodbkit
+
+import (
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+
+	"github.com/half-ogre/go-kit/kit"
+)
+
+// Decimal is a kit.Decimal stored as a DynamoDB number. kit doesn't depend on the AWS SDK, so use
+// Decimal for item fields and convert with Decimal(d) and kit.Decimal(d).
+type Decimal kit.Decimal
+
+// MarshalDynamoDBAttributeValue implements attributevalue.Marshaler, storing d as a DynamoDB number
+func (d Decimal) MarshalDynamoDBAttributeValue() (types.AttributeValue, error) {
+	return &types.AttributeValueMemberN{Value: kit.Decimal(d).String()}, nil
+}
+
+// UnmarshalDynamoDBAttributeValue implements attributevalue.Unmarshaler, accepting numbers and strings
+func (d *Decimal) UnmarshalDynamoDBAttributeValue(av types.AttributeValue) error {
+	var s string
+	switch v := av.(type) {
+	case *types.AttributeValueMemberN:
+		s = v.Value
+	case *types.AttributeValueMemberS:
+		s = v.Value
+	default:
+		return fmt.Errorf("cannot unmarshal %T into Decimal", av)
+	}
+
+	parsed, err := kit.ParseDecimal(s)
+	if err != nil {
+		return err
+	}
+	*d = Decimal(parsed)
+	return nil
+}
+
+// Money is a kit.Money stored as a map with a numeric amount and a currency, e.g.
+// {"amount": 12.34, "currency": "USD"}. Convert with Money(m) and kit.Money(m).
+type Money kit.Money
+
+// MarshalDynamoDBAttributeValue implements attributevalue.Marshaler
+func (m Money) MarshalDynamoDBAttributeValue() (types.AttributeValue, error) {
+	amount, err := Decimal(m.Amount).MarshalDynamoDBAttributeValue()
+	if err != nil {
+		return nil, err
+	}
+	return &types.AttributeValueMemberM{Value: map[string]types.AttributeValue{
+		"amount":   amount,
+		"currency": &types.AttributeValueMemberS{Value: m.Currency},
+	}}, nil
+}
+
+// UnmarshalDynamoDBAttributeValue implements attributevalue.Unmarshaler
+func (m *Money) UnmarshalDynamoDBAttributeValue(av types.AttributeValue) error {
+	v, ok := av.(*types.AttributeValueMemberM)
+	if !ok {
+		return fmt.Errorf("cannot unmarshal %T into Money", av)
+	}
+
+	var amount Decimal
+	if amountAV, ok := v.Value["amount"]; ok {
+		if err := amount.UnmarshalDynamoDBAttributeValue(amountAV); err != nil {
+			return err
+		}
+	}
+
+	var currency string
+	if currencyAV, ok := v.Value["currency"].(*types.AttributeValueMemberS); ok {
+		currency = currencyAV.Value
+	}
+
+	*m = Money{Amount: kit.Decimal(amount), Currency: currency}
+	return nil
+}
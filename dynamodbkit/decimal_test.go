@@ -0,0 +1,96 @@
+package dynamodbkit
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/half-ogre/go-kit/kit"
+)
+
+func TestDecimal(t *testing.T) {
+	t.Run("marshals_as_a_number", func(t *testing.T) {
+		result, err := Decimal(kit.MustParseDecimal("12.34")).MarshalDynamoDBAttributeValue()
+
+		assert.NoError(t, err)
+		assert.Equal(t, &types.AttributeValueMemberN{Value: "12.34"}, result)
+	})
+
+	t.Run("unmarshals_numbers", func(t *testing.T) {
+		var result Decimal
+
+		err := result.UnmarshalDynamoDBAttributeValue(&types.AttributeValueMemberN{Value: "12.34"})
+
+		assert.NoError(t, err)
+		assert.Equal(t, "12.34", kit.Decimal(result).String())
+	})
+
+	t.Run("unmarshals_strings", func(t *testing.T) {
+		var result Decimal
+
+		err := result.UnmarshalDynamoDBAttributeValue(&types.AttributeValueMemberS{Value: "0.1"})
+
+		assert.NoError(t, err)
+		assert.Equal(t, "0.1", kit.Decimal(result).String())
+	})
+
+	t.Run("unmarshaling_other_types_returns_an_error", func(t *testing.T) {
+		var result Decimal
+
+		err := result.UnmarshalDynamoDBAttributeValue(&types.AttributeValueMemberBOOL{Value: true})
+
+		assert.EqualError(t, err, "cannot unmarshal *types.AttributeValueMemberBOOL into Decimal")
+	})
+
+	t.Run("round_trips_as_an_item_field", func(t *testing.T) {
+		type item struct {
+			Price Decimal `dynamodbav:"price"`
+		}
+
+		av, err := attributevalue.MarshalMap(item{Price: Decimal(kit.MustParseDecimal("19.99"))})
+		assert.NoError(t, err)
+		var result item
+		err = attributevalue.UnmarshalMap(av, &result)
+
+		assert.NoError(t, err)
+		assert.Equal(t, &types.AttributeValueMemberN{Value: "19.99"}, av["price"])
+		assert.Equal(t, "19.99", kit.Decimal(result.Price).String())
+	})
+}
+
+func TestMoney(t *testing.T) {
+	t.Run("marshals_as_a_map_with_a_numeric_amount", func(t *testing.T) {
+		money, _ := kit.NewMoney("12.34", "USD")
+
+		result, err := Money(money).MarshalDynamoDBAttributeValue()
+
+		assert.NoError(t, err)
+		assert.Equal(t, &types.AttributeValueMemberM{Value: map[string]types.AttributeValue{
+			"amount":   &types.AttributeValueMemberN{Value: "12.34"},
+			"currency": &types.AttributeValueMemberS{Value: "USD"},
+		}}, result)
+	})
+
+	t.Run("unmarshals_a_map", func(t *testing.T) {
+		var result Money
+
+		err := result.UnmarshalDynamoDBAttributeValue(&types.AttributeValueMemberM{Value: map[string]types.AttributeValue{
+			"amount":   &types.AttributeValueMemberN{Value: "12.34"},
+			"currency": &types.AttributeValueMemberS{Value: "USD"},
+		}})
+
+		assert.NoError(t, err)
+		assert.Equal(t, "12.34", kit.Money(result).Amount.String())
+		assert.Equal(t, "USD", result.Currency)
+	})
+
+	t.Run("unmarshaling_other_types_returns_an_error", func(t *testing.T) {
+		var result Money
+
+		err := result.UnmarshalDynamoDBAttributeValue(&types.AttributeValueMemberS{Value: "12.34 USD"})
+
+		assert.EqualError(t, err, "cannot unmarshal *types.AttributeValueMemberS into Money")
+	})
+}
@@ -2,24 +2,53 @@ package dynamodbkit
 
 import (
 	"context"
+	"errors"
 	"fmt"
-	"log/slog"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
 	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
 	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
 	"github.com/half-ogre/go-kit/kit"
 )
 
+// DeleteItem deletes an item by its key. WithDeleteItemConditionExpression guards the delete; use
+// DeleteItemReturning to get the deleted item back.
 func DeleteItem[TPartitionKey string | int](ctx context.Context, tableName string, partitionKey string, partitionKeyValue TPartitionKey, options ...DeleteItemOption) error {
-	db, err := newDynamoDB(ctx)
+	_, err := deleteItem(ctx, tableName, partitionKey, partitionKeyValue, options)
+	return err
+}
+
+// DeleteItemReturning deletes an item as DeleteItem does and returns the item as it was before the
+// delete when WithDeleteItemReturnOld asks for it, in the same round trip. The item is nil when there
+// wasn't one to delete or no values were asked for.
+func DeleteItemReturning[TItem any, TPartitionKey string | int](ctx context.Context, tableName string, partitionKey string, partitionKeyValue TPartitionKey, options ...DeleteItemOption) (*TItem, error) {
+	attributes, err := deleteItem(ctx, tableName, partitionKey, partitionKeyValue, options)
+	if err != nil || len(attributes) == 0 {
+		return nil, err
+	}
+
+	var item TItem
+	err = attributevalue.UnmarshalMap(attributes, &item)
+	if err != nil {
+		return nil, kit.WrapError(err, "failed to unmarshal deleted item")
+	}
+
+	return &item, nil
+}
+
+func deleteItem[TPartitionKey string | int](ctx context.Context, tableName string, partitionKey string, partitionKeyValue TPartitionKey, options []DeleteItemOption) (_ map[string]types.AttributeValue, err error) {
+	op := newOperation(ctx, "DeleteItem", tableName)
+	defer op.wrap(&err)
+
+	db, err := op.dynamoDB(ctx)
 	if err != nil {
-		return kit.WrapError(err, "error creating DynamoDB client")
+		return nil, kit.WrapError(err, "error creating DynamoDB client")
 	}
 
 	partitionKeyAttributeValue, err := getKeyAttributeValue(partitionKeyValue)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	deleteItemInput := &dynamodb.DeleteItemInput{
@@ -29,37 +58,81 @@ func DeleteItem[TPartitionKey string | int](ctx context.Context, tableName strin
 		},
 	}
 
+	op.setKey(partitionKey, deleteItemInput.Key)
 	originalTableNamePtr := deleteItemInput.TableName
 
 	for _, option := range options {
 		err := option(deleteItemInput)
 		if err != nil {
-			return kit.WrapError(err, "error processing option")
+			return nil, kit.WrapError(err, "error processing option")
 		}
 	}
 
-	// Apply global table name suffix if table name pointer wasn't changed by options
-	if deleteItemInput.TableName == originalTableNamePtr {
-		globalSuffix := getTableNameSuffix()
-		if globalSuffix != "" {
-			deleteItemInput.TableName = aws.String(fmt.Sprintf("%s%s", *deleteItemInput.TableName, globalSuffix))
+	// Apply the client's table name prefix, and its suffix if table name pointer wasn't changed by options
+	deleteItemInput.TableName = op.client.qualifyTableName(deleteItemInput.TableName, originalTableNamePtr)
+	op.table = *deleteItemInput.TableName
+
+	logger.Debug("deleting DynamoDB item", "input", deleteItemInput)
+
+	metrics := startOperation(op, "DeleteItem", deleteItemInput.TableName)
+	defer func() { metrics.finish(ctx, err) }()
+	deleteItemInput.ReturnConsumedCapacity = op.client.returnConsumedCapacity(metrics)
+
+	if audit := op.client.audit; audit != nil {
+		attributes, err := deleteItemWithAudit(ctx, db, audit, deleteItemInput)
+		if err != nil {
+			return nil, kit.WrapError(markConditionFailed(err), "error deleting item")
 		}
-	}
 
-	slog.Debug("deleting DynamoDB item", "input", deleteItemInput)
+		metrics.addResponse(1)
+		return attributes, nil
+	}
 
 	output, err := db.DeleteItem(ctx, deleteItemInput)
 	if err != nil {
-		return kit.WrapError(err, "error deleting item")
+		return nil, kit.WrapError(markConditionFailed(err), "error deleting item")
 	}
+	metrics.addResponse(1, output.ConsumedCapacity)
 
-	slog.Info("delete-item", "attributes", output.Attributes)
+	logger.Info("delete-item", "attributes", output.Attributes)
 
-	return nil
+	return output.Attributes, nil
 }
 
 type DeleteItemOption func(*dynamodb.DeleteItemInput) error
 
+// WithDeleteItemConditionExpression makes the delete conditional on expr, e.g. "#status = :status"
+// with names {"#status": "status"} and values {":status": "inactive"} to only delete inactive items.
+// It's ANDed with any condition from another option. When the condition isn't met, the error is
+// ErrConditionFailed.
+func WithDeleteItemConditionExpression(expr string, names map[string]string, values map[string]any) DeleteItemOption {
+	return func(input *dynamodb.DeleteItemInput) error {
+		if expr == "" {
+			return errors.New("condition expression cannot be empty")
+		}
+
+		mergedNames, err := mergeExpressionAttributeNames(input.ExpressionAttributeNames, names)
+		if err != nil {
+			return err
+		}
+		mergedValues, err := mergeConditionValues(input.ExpressionAttributeValues, values)
+		if err != nil {
+			return err
+		}
+
+		input.ExpressionAttributeNames = mergedNames
+		input.ExpressionAttributeValues = mergedValues
+		input.ConditionExpression = andCondition(input.ConditionExpression, expr)
+		return nil
+	}
+}
+
+// WithDeleteItemReturnOld asks for the item as it was before the delete, which DeleteItemReturning
+// returns
+func WithDeleteItemReturnOld() DeleteItemOption {
+	return WithDeleteItemReturnValues(types.ReturnValueAllOld)
+}
+
 func WithDeleteItemReturnValues(returnValues types.ReturnValue) DeleteItemOption {
 	return func(input *dynamodb.DeleteItemInput) error {
 		input.ReturnValues = returnValues
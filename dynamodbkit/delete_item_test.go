@@ -18,7 +18,7 @@ func TestDeleteItem(t *testing.T) {
 
 		err := DeleteItem(context.Background(), "aTable", "id", "aUserID")
 
-		assert.EqualError(t, err, "error creating DynamoDB client: the fake error")
+		assert.EqualError(t, err, "dynamodbkit.DeleteItem table=aTable: error creating DynamoDB client: the fake error")
 	})
 
 	t.Run("passes_the_table_name_to_delete_item", func(t *testing.T) {
@@ -87,7 +87,7 @@ func TestDeleteItem(t *testing.T) {
 
 		err := DeleteItem(context.Background(), "aTable", "id", "aUserID")
 
-		assert.EqualError(t, err, "error deleting item: the fake error")
+		assert.EqualError(t, err, "dynamodbkit.DeleteItem table=aTable id=aUserID: error deleting item: the fake error")
 	})
 
 	t.Run("applies_delete_item_options_correctly", func(t *testing.T) {
@@ -127,7 +127,7 @@ func TestDeleteItem(t *testing.T) {
 
 		err := DeleteItem(context.Background(), "aTable", "id", "aUserID", failingOption)
 
-		assert.EqualError(t, err, "error processing option: option processing failed")
+		assert.EqualError(t, err, "dynamodbkit.DeleteItem table=aTable id=aUserID: error processing option: option processing failed")
 	})
 
 	t.Run("succeeds_when_no_errors", func(t *testing.T) {
@@ -224,3 +224,96 @@ func TestWithDeleteItemReturnValues(t *testing.T) {
 		assert.Equal(t, types.ReturnValueNone, input.ReturnValues)
 	})
 }
+
+func TestDeleteItemReturning(t *testing.T) {
+	t.Run("returns_the_deleted_item", func(t *testing.T) {
+		var actualReturnValues types.ReturnValue
+		fakeDB := &FakeDynamoDB{
+			DeleteItemFake: func(ctx context.Context, params *dynamodb.DeleteItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.DeleteItemOutput, error) {
+				actualReturnValues = params.ReturnValues
+				return &dynamodb.DeleteItemOutput{Attributes: map[string]types.AttributeValue{
+					"id":   &types.AttributeValueMemberS{Value: "theID"},
+					"name": &types.AttributeValueMemberS{Value: "theName"},
+				}}, nil
+			},
+		}
+		setFake(func(ctx context.Context) (DynamoDB, error) { return fakeDB, nil })
+		t.Cleanup(func() { setFake(nil) })
+
+		item, err := DeleteItemReturning[TestUser](context.Background(), "aTable", "id", "theID", WithDeleteItemReturnOld())
+
+		assert.NoError(t, err)
+		assert.Equal(t, types.ReturnValueAllOld, actualReturnValues)
+		assert.Equal(t, &TestUser{ID: "theID", Name: "theName"}, item)
+	})
+
+	t.Run("returns_nil_when_there_was_no_item", func(t *testing.T) {
+		fakeDB := &FakeDynamoDB{
+			DeleteItemFake: func(ctx context.Context, params *dynamodb.DeleteItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.DeleteItemOutput, error) {
+				return &dynamodb.DeleteItemOutput{}, nil
+			},
+		}
+		setFake(func(ctx context.Context) (DynamoDB, error) { return fakeDB, nil })
+		t.Cleanup(func() { setFake(nil) })
+
+		item, err := DeleteItemReturning[TestUser](context.Background(), "aTable", "id", "theID", WithDeleteItemReturnOld())
+
+		assert.NoError(t, err)
+		assert.Nil(t, item)
+	})
+
+	t.Run("returns_err_condition_failed_when_the_condition_is_not_met", func(t *testing.T) {
+		fakeDB := &FakeDynamoDB{
+			DeleteItemFake: func(ctx context.Context, params *dynamodb.DeleteItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.DeleteItemOutput, error) {
+				return nil, &types.ConditionalCheckFailedException{Message: aws.String("the condition message")}
+			},
+		}
+		setFake(func(ctx context.Context) (DynamoDB, error) { return fakeDB, nil })
+		t.Cleanup(func() { setFake(nil) })
+
+		item, err := DeleteItemReturning[TestUser](context.Background(), "aTable", "id", "theID",
+			WithDeleteItemConditionExpression("#status = :status", map[string]string{"#status": "status"}, map[string]any{":status": "inactive"}))
+
+		assert.ErrorIs(t, err, ErrConditionFailed)
+		assert.Nil(t, item)
+	})
+}
+
+func TestWithDeleteItemConditionExpression(t *testing.T) {
+	t.Run("sets_the_condition_names_and_values", func(t *testing.T) {
+		input := &dynamodb.DeleteItemInput{}
+		option := WithDeleteItemConditionExpression("#status = :status", map[string]string{"#status": "status"}, map[string]any{":status": "inactive"})
+
+		err := option(input)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "#status = :status", *input.ConditionExpression)
+		assert.Equal(t, map[string]string{"#status": "status"}, input.ExpressionAttributeNames)
+		assert.Equal(t, map[string]types.AttributeValue{":status": &types.AttributeValueMemberS{Value: "inactive"}}, input.ExpressionAttributeValues)
+	})
+
+	t.Run("ands_with_an_existing_condition", func(t *testing.T) {
+		input := &dynamodb.DeleteItemInput{ConditionExpression: aws.String("attribute_exists(id)")}
+		option := WithDeleteItemConditionExpression("#status = :status", map[string]string{"#status": "status"}, map[string]any{":status": "inactive"})
+
+		err := option(input)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "(attribute_exists(id)) AND (#status = :status)", *input.ConditionExpression)
+	})
+
+	t.Run("returns_an_error_when_a_name_is_already_set_differently", func(t *testing.T) {
+		input := &dynamodb.DeleteItemInput{ExpressionAttributeNames: map[string]string{"#status": "state"}}
+		option := WithDeleteItemConditionExpression("#status = :status", map[string]string{"#status": "status"}, nil)
+
+		err := option(input)
+
+		assert.EqualError(t, err, "expression attribute name #status is already set to state")
+	})
+
+	t.Run("returns_an_error_when_the_expression_is_empty", func(t *testing.T) {
+		err := WithDeleteItemConditionExpression("", nil, nil)(&dynamodb.DeleteItemInput{})
+
+		assert.EqualError(t, err, "condition expression cannot be empty")
+	})
+}
@@ -3,18 +3,19 @@ package dynamodbkit
 import (
 	"context"
 	"fmt"
-	"sync"
 
-	"github.com/aws/aws-sdk-go-v2/config"
 	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
 	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
-	"github.com/half-ogre/go-kit/kit"
+	"github.com/half-ogre/go-kit/logkit"
 )
 
+// logger is the logger for the package's own records, so operators can turn them up or down with
+// logkit.SetLevelSpec, e.g. LOG_LEVEL=info,dynamodbkit=debug
+var logger = logkit.Logger("dynamodbkit")
+
+// UseTableNameSuffix sets the default client's table name suffix; see WithClientTableNameSuffix
 func UseTableNameSuffix(suffix string) {
-	tableNameSuffixMu.Lock()
-	defer tableNameSuffixMu.Unlock()
-	tableNameSuffix = suffix
+	updateDefaultClient(WithClientTableNameSuffix(suffix))
 }
 
 func getKeyAttributeValue[TKey string | int](keyValue TKey) (types.AttributeValue, error) {
@@ -35,6 +36,9 @@ func getKeyAttributeValue[TKey string | int](keyValue TKey) (types.AttributeValu
 	return keyAttributeValue, nil
 }
 
+// DynamoDB is the part of the DynamoDB client that every operation uses. Operations that need more
+// assert one of the optional interfaces below, which *dynamodb.Client and FakeDynamoDB implement, so
+// implementations written against DynamoDB keep compiling as operations are added.
 type DynamoDB interface {
 	Query(ctx context.Context, params *dynamodb.QueryInput, optFns ...func(*dynamodb.Options)) (*dynamodb.QueryOutput, error)
 	PutItem(ctx context.Context, params *dynamodb.PutItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.PutItemOutput, error)
@@ -44,35 +48,48 @@ type DynamoDB interface {
 	ListTables(ctx context.Context, params *dynamodb.ListTablesInput, optFns ...func(*dynamodb.Options)) (*dynamodb.ListTablesOutput, error)
 }
 
-func newDynamoDB(ctx context.Context) (DynamoDB, error) {
-	fakeMu.Lock()
-	defer fakeMu.Unlock()
-	if fakeNewDynamoDB != nil {
-		return fakeNewDynamoDB(ctx)
-	}
+// BatchWriteItemAPI is the optional interface BatchPutItems and BatchDeleteItems need
+type BatchWriteItemAPI interface {
+	BatchWriteItem(ctx context.Context, params *dynamodb.BatchWriteItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.BatchWriteItemOutput, error)
+}
 
-	cfg, err := config.LoadDefaultConfig(ctx)
-	if err != nil {
-		return nil, kit.WrapError(err, "error loading default AWS config")
-	}
+// CreateTableAPI is the optional interface CreateTable needs
+type CreateTableAPI interface {
+	CreateTable(ctx context.Context, params *dynamodb.CreateTableInput, optFns ...func(*dynamodb.Options)) (*dynamodb.CreateTableOutput, error)
+}
 
-	return dynamodb.NewFromConfig(cfg), nil
+// DeleteTableAPI is the optional interface DeleteTable needs
+type DeleteTableAPI interface {
+	DeleteTable(ctx context.Context, params *dynamodb.DeleteTableInput, optFns ...func(*dynamodb.Options)) (*dynamodb.DeleteTableOutput, error)
 }
 
-var fakeNewDynamoDB func(ctx context.Context) (DynamoDB, error)
-var fakeMu sync.Mutex
+// DescribeTableAPI is the optional interface CheckTableActive, DescribeTable, auditing, and item migrations need
+type DescribeTableAPI interface {
+	DescribeTable(ctx context.Context, params *dynamodb.DescribeTableInput, optFns ...func(*dynamodb.Options)) (*dynamodb.DescribeTableOutput, error)
+}
 
-func setFake(fake func(ctx context.Context) (DynamoDB, error)) {
-	fakeMu.Lock()
-	defer fakeMu.Unlock()
-	fakeNewDynamoDB = fake
+// ExecuteStatementAPI is the optional interface ExecuteStatement needs
+type ExecuteStatementAPI interface {
+	ExecuteStatement(ctx context.Context, params *dynamodb.ExecuteStatementInput, optFns ...func(*dynamodb.Options)) (*dynamodb.ExecuteStatementOutput, error)
 }
 
-var tableNameSuffix string
-var tableNameSuffixMu sync.Mutex
+// TransactWriteItemsAPI is the optional interface auditing needs
+type TransactWriteItemsAPI interface {
+	TransactWriteItems(ctx context.Context, params *dynamodb.TransactWriteItemsInput, optFns ...func(*dynamodb.Options)) (*dynamodb.TransactWriteItemsOutput, error)
+}
 
-func getTableNameSuffix() string {
-	tableNameSuffixMu.Lock()
-	defer tableNameSuffixMu.Unlock()
-	return tableNameSuffix
+// UpdateItemAPI is the optional interface UpdateItem and auditing need
+type UpdateItemAPI interface {
+	UpdateItem(ctx context.Context, params *dynamodb.UpdateItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.UpdateItemOutput, error)
+}
+
+// requireAPI returns db as the optional interface T, or an error naming method when db doesn't
+// implement it
+func requireAPI[T any](db DynamoDB, method string) (T, error) {
+	api, ok := db.(T)
+	if !ok {
+		var zero T
+		return zero, fmt.Errorf("%T does not implement %s", db, method)
+	}
+	return api, nil
 }
@@ -0,0 +1,136 @@
+package dynamodbkit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/half-ogre/go-kit/kit"
+)
+
+// Adjacency-list attribute and index names. An edge from node A to node B is stored with PK = A and
+// SK = B, and the overloaded GSI1 index inverts it (GSI1PK = B, GSI1SK = A), so a node's outgoing edges
+// are read from the table and its incoming edges from the index. A node's own item has PK = SK.
+const (
+	EdgePartitionKey      = "PK"
+	EdgeSortKey           = "SK"
+	EdgeIndexName         = "GSI1"
+	EdgeIndexPartitionKey = "GSI1PK"
+	EdgeIndexSortKey      = "GSI1SK"
+)
+
+// EdgeDirection is which of a node's edges QueryEdges reads
+type EdgeDirection int
+
+const (
+	// EdgesOutgoing are the edges from a node, read from the table
+	EdgesOutgoing EdgeDirection = iota
+	// EdgesIncoming are the edges to a node, read from GSI1
+	EdgesIncoming
+)
+
+// Edge holds an adjacency-list item's keys. Embed it in a struct to add the edge's attributes, e.g.
+//
+//	type Membership struct {
+//		dynamodbkit.Edge
+//		Role string `dynamodbav:"role"`
+//	}
+type Edge struct {
+	PK     string `dynamodbav:"PK"`
+	SK     string `dynamodbav:"SK"`
+	GSI1PK string `dynamodbav:"GSI1PK"`
+	GSI1SK string `dynamodbav:"GSI1SK"`
+}
+
+// EdgeItem is an item with adjacency-list keys, e.g. a struct embedding Edge
+type EdgeItem interface {
+	EdgeKeys() Edge
+}
+
+// NodeKey returns the key of a node, e.g. NodeKey("USER", "123") is "USER#123". Node keys start with
+// their type so QueryEdges can read the edges to one type of node.
+func NodeKey(nodeType string, id string) string {
+	return nodeType + "#" + id
+}
+
+// NewEdge returns the keys of the edge from node from to node to, including the inverted index keys
+func NewEdge(from string, to string) Edge {
+	return Edge{PK: from, SK: to, GSI1PK: to, GSI1SK: from}
+}
+
+func (e Edge) EdgeKeys() Edge {
+	return e
+}
+
+// Validate returns an error if the edge's keys are empty, aren't node keys from NodeKey, or aren't
+// mirrored in its index keys
+func (e Edge) Validate() error {
+	for _, key := range []struct{ name, value string }{{"PK", e.PK}, {"SK", e.SK}} {
+		nodeType, id, found := strings.Cut(key.value, "#")
+		if !found || nodeType == "" || id == "" {
+			return fmt.Errorf("%s must be a node key of the form TYPE#id, got %q", key.name, key.value)
+		}
+	}
+
+	if e.GSI1PK != e.SK || e.GSI1SK != e.PK {
+		return errors.New("GSI1PK and GSI1SK must invert PK and SK; use NewEdge to make edge keys")
+	}
+
+	return nil
+}
+
+// PutEdge validates item's edge keys and puts it
+func PutEdge[T EdgeItem](ctx context.Context, tableName string, item T, options ...PutItemOption) (err error) {
+	op := newOperation(ctx, "PutEdge", tableName)
+	defer op.wrap(&err)
+
+	if err := item.EdgeKeys().Validate(); err != nil {
+		return kit.WrapError(err, "invalid edge")
+	}
+
+	return PutItem(ctx, tableName, item, options...)
+}
+
+// QueryEdges returns node's outgoing or incoming edges. If neighborType isn't empty, only edges to (or
+// from) nodes of that type are returned. The node's own item is never returned.
+func QueryEdges[T EdgeItem](ctx context.Context, tableName string, node string, direction EdgeDirection, neighborType string, options ...QueryAllOption) (_ []T, err error) {
+	op := newOperation(ctx, "QueryEdges", tableName)
+	defer op.wrap(&err)
+
+	if node == "" {
+		return nil, errors.New("node cannot be empty")
+	}
+
+	partitionKey, sortKey := EdgePartitionKey, EdgeSortKey
+	var edgeOptions []QueryAllOption
+	switch direction {
+	case EdgesOutgoing:
+	case EdgesIncoming:
+		partitionKey, sortKey = EdgeIndexPartitionKey, EdgeIndexSortKey
+		edgeOptions = append(edgeOptions, WithQueryIndexName(EdgeIndexName))
+	default:
+		return nil, fmt.Errorf("unknown edge direction %d", direction)
+	}
+	op.setKey(partitionKey, map[string]types.AttributeValue{partitionKey: &types.AttributeValueMemberS{Value: node}})
+	if neighborType != "" {
+		edgeOptions = append(edgeOptions, WithQuerySortKeyBeginsWith(sortKey, neighborType+"#"))
+	}
+
+	output, err := QueryAll[T](ctx, tableName, partitionKey, node, append(edgeOptions, options...)...)
+	if err != nil {
+		return nil, kit.WrapError(err, "error querying edges")
+	}
+
+	edges := make([]T, 0, len(output.Items))
+	for _, item := range output.Items {
+		keys := item.EdgeKeys()
+		if keys.PK == keys.SK {
+			continue
+		}
+		edges = append(edges, item)
+	}
+
+	return edges, nil
+}
@@ -0,0 +1,140 @@
+package dynamodbkit
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/stretchr/testify/assert"
+)
+
+type testMembership struct {
+	Edge
+	Role string `dynamodbav:"role"`
+}
+
+func TestNodeKey(t *testing.T) {
+	t.Run("prefixes_the_id_with_the_node_type", func(t *testing.T) {
+		assert.Equal(t, "USER#123", NodeKey("USER", "123"))
+	})
+}
+
+func TestNewEdge(t *testing.T) {
+	t.Run("inverts_the_keys_for_the_index", func(t *testing.T) {
+		result := NewEdge("USER#1", "GROUP#2")
+
+		assert.Equal(t, Edge{PK: "USER#1", SK: "GROUP#2", GSI1PK: "GROUP#2", GSI1SK: "USER#1"}, result)
+	})
+}
+
+func TestEdge_Validate(t *testing.T) {
+	t.Run("returns_nil_for_a_valid_edge", func(t *testing.T) {
+		assert.NoError(t, NewEdge("USER#1", "GROUP#2").Validate())
+	})
+
+	t.Run("returns_an_error_when_a_key_is_not_a_node_key", func(t *testing.T) {
+		err := NewEdge("USER#1", "aGroup").Validate()
+
+		assert.EqualError(t, err, `SK must be a node key of the form TYPE#id, got "aGroup"`)
+	})
+
+	t.Run("returns_an_error_when_a_key_is_empty", func(t *testing.T) {
+		err := NewEdge("", "GROUP#2").Validate()
+
+		assert.EqualError(t, err, `PK must be a node key of the form TYPE#id, got ""`)
+	})
+
+	t.Run("returns_an_error_when_the_index_keys_do_not_invert_the_keys", func(t *testing.T) {
+		err := Edge{PK: "USER#1", SK: "GROUP#2", GSI1PK: "USER#1", GSI1SK: "GROUP#2"}.Validate()
+
+		assert.EqualError(t, err, "GSI1PK and GSI1SK must invert PK and SK; use NewEdge to make edge keys")
+	})
+}
+
+func TestPutEdge(t *testing.T) {
+	t.Run("puts_a_valid_edge", func(t *testing.T) {
+		var actualItem map[string]types.AttributeValue
+		fakeDB := &FakeDynamoDB{
+			PutItemFake: func(ctx context.Context, params *dynamodb.PutItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.PutItemOutput, error) {
+				actualItem = params.Item
+				return &dynamodb.PutItemOutput{}, nil
+			},
+		}
+		setFake(func(ctx context.Context) (DynamoDB, error) { return fakeDB, nil })
+		t.Cleanup(func() { setFake(nil) })
+		item := testMembership{Edge: NewEdge("USER#1", "GROUP#2"), Role: "aRole"}
+
+		err := PutEdge(context.Background(), "aTable", item)
+
+		assert.NoError(t, err)
+		assert.Equal(t, mustMarshalMap(t, item), actualItem)
+		assert.Equal(t, &types.AttributeValueMemberS{Value: "GROUP#2"}, actualItem["GSI1PK"])
+	})
+
+	t.Run("returns_an_error_for_an_invalid_edge", func(t *testing.T) {
+		err := PutEdge(context.Background(), "aTable", testMembership{Edge: Edge{PK: "USER#1", SK: "GROUP#2"}})
+
+		assert.EqualError(t, err, "dynamodbkit.PutEdge table=aTable: invalid edge: GSI1PK and GSI1SK must invert PK and SK; use NewEdge to make edge keys")
+	})
+}
+
+func TestQueryEdges(t *testing.T) {
+	t.Run("queries_outgoing_edges_from_the_table_and_skips_the_node_item", func(t *testing.T) {
+		var actualInput *dynamodb.QueryInput
+		fakeDB := &FakeDynamoDB{
+			QueryFake: func(ctx context.Context, params *dynamodb.QueryInput, optFns ...func(*dynamodb.Options)) (*dynamodb.QueryOutput, error) {
+				actualInput = params
+				return &dynamodb.QueryOutput{Items: []map[string]types.AttributeValue{
+					mustMarshalMap(t, testMembership{Edge: NewEdge("USER#1", "USER#1")}),
+					mustMarshalMap(t, testMembership{Edge: NewEdge("USER#1", "GROUP#2"), Role: "aRole"}),
+				}}, nil
+			},
+		}
+		setFake(func(ctx context.Context) (DynamoDB, error) { return fakeDB, nil })
+		t.Cleanup(func() { setFake(nil) })
+
+		result, err := QueryEdges[testMembership](context.Background(), "aTable", "USER#1", EdgesOutgoing, "")
+
+		assert.NoError(t, err)
+		assert.Equal(t, []testMembership{{Edge: NewEdge("USER#1", "GROUP#2"), Role: "aRole"}}, result)
+		assert.Nil(t, actualInput.IndexName)
+		assert.Equal(t, "PK", actualInput.ExpressionAttributeNames["#0"])
+		assert.Equal(t, &types.AttributeValueMemberS{Value: "USER#1"}, actualInput.ExpressionAttributeValues[":0"])
+	})
+
+	t.Run("queries_incoming_edges_from_the_index", func(t *testing.T) {
+		var actualInput *dynamodb.QueryInput
+		fakeDB := &FakeDynamoDB{
+			QueryFake: func(ctx context.Context, params *dynamodb.QueryInput, optFns ...func(*dynamodb.Options)) (*dynamodb.QueryOutput, error) {
+				actualInput = params
+				return &dynamodb.QueryOutput{Items: []map[string]types.AttributeValue{
+					mustMarshalMap(t, testMembership{Edge: NewEdge("USER#1", "GROUP#2")}),
+				}}, nil
+			},
+		}
+		setFake(func(ctx context.Context) (DynamoDB, error) { return fakeDB, nil })
+		t.Cleanup(func() { setFake(nil) })
+
+		result, err := QueryEdges[testMembership](context.Background(), "aTable", "GROUP#2", EdgesIncoming, "USER")
+
+		assert.NoError(t, err)
+		assert.Len(t, result, 1)
+		assert.Equal(t, "GSI1", *actualInput.IndexName)
+		assert.Equal(t, "GSI1PK", actualInput.ExpressionAttributeNames["#0"])
+		assert.Equal(t, "GSI1SK", actualInput.ExpressionAttributeNames["#sortKeyBeginsWith"])
+		assert.Equal(t, &types.AttributeValueMemberS{Value: "USER#"}, actualInput.ExpressionAttributeValues[":sortKeyBeginsWith"])
+	})
+
+	t.Run("returns_an_error_for_an_empty_node", func(t *testing.T) {
+		_, err := QueryEdges[testMembership](context.Background(), "aTable", "", EdgesOutgoing, "")
+
+		assert.EqualError(t, err, "dynamodbkit.QueryEdges table=aTable: node cannot be empty")
+	})
+
+	t.Run("returns_an_error_for_an_unknown_direction", func(t *testing.T) {
+		_, err := QueryEdges[testMembership](context.Background(), "aTable", "USER#1", EdgeDirection(7), "")
+
+		assert.EqualError(t, err, "dynamodbkit.QueryEdges table=aTable: unknown edge direction 7")
+	})
+}
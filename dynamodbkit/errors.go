@@ -0,0 +1,124 @@
+package dynamodbkit
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"sort"
+	"sync/atomic"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+
+	"github.com/half-ogre/go-kit/kit"
+	"github.com/half-ogre/go-kit/logkit/logfields"
+)
+
+// ErrConditionFailed is in the chain of errors from writes whose condition wasn't met, e.g. a put with
+// WithPutItemIfNotExists when the item already exists, so create-only writes can check it with
+// errors.Is. The DynamoDB exception stays in the chain too.
+var ErrConditionFailed = errors.New("condition failed")
+
+// markConditionFailed adds ErrConditionFailed to err's chain when it's a failed condition check. In an
+// audited write's transaction, the write is the first item, so only its check counts.
+func markConditionFailed(err error) error {
+	var conditionErr *types.ConditionalCheckFailedException
+	if errors.As(err, &conditionErr) {
+		return fmt.Errorf("%w: %w", ErrConditionFailed, err)
+	}
+
+	var canceledErr *types.TransactionCanceledException
+	if errors.As(err, &canceledErr) && len(canceledErr.CancellationReasons) > 0 && aws.ToString(canceledErr.CancellationReasons[0].Code) == "ConditionalCheckFailed" {
+		return fmt.Errorf("%w: %w", ErrConditionFailed, err)
+	}
+
+	return err
+}
+
+// UseKeyRedaction turns key redaction in errors on or off. Errors from every operation are a
+// *kit.OperationError naming the operation, table, and key, e.g.
+// "dynamodbkit.GetItem table=users id=123: ..."; when on, key values show as [REDACTED] for tables
+// keyed by personal data, e.g. an email address.
+func UseKeyRedaction(enabled bool) {
+	updateDefaultClient(WithClientKeyRedaction(enabled))
+}
+
+// operation names what an exported function is working on for its errors, and the client it works
+// with. The table and key are filled in as they're known, e.g. once the table name suffix is applied.
+type operation struct {
+	client       *Client
+	name         string
+	table        string
+	index        string
+	partitionKey string
+	key          map[string]types.AttributeValue
+	retries      atomic.Int64
+}
+
+func newOperation(ctx context.Context, name string, tableName string) *operation {
+	return &operation{client: getClient(ctx), name: name, table: tableName}
+}
+
+// dynamoDB returns the client's DynamoDB, retrying calls as its retry policy says
+func (o *operation) dynamoDB(ctx context.Context) (DynamoDB, error) {
+	db, err := o.client.newDynamoDB(ctx)
+	if err != nil || o.client.retryPolicy.MaxAttempts <= 1 {
+		return db, err
+	}
+	return &retryingDynamoDB{db: db, policy: o.client.retryPolicy, retries: &o.retries}, nil
+}
+
+// setKey names the item the operation is working on. Pass the input's key map so a sort key added by an
+// option is included.
+func (o *operation) setKey(partitionKey string, key map[string]types.AttributeValue) {
+	o.partitionKey = partitionKey
+	o.key = key
+}
+
+// wrap replaces *err, when it isn't nil, with a *kit.OperationError for the operation; defer it with
+// the function's named error result
+func (o *operation) wrap(err *error) {
+	if *err == nil {
+		return
+	}
+
+	var fields []kit.ErrorField
+	if o.table != "" {
+		fields = append(fields, kit.ErrorField{Key: logfields.TableKey, Value: o.table})
+	}
+	if o.index != "" {
+		fields = append(fields, kit.ErrorField{Key: "index", Value: o.index})
+	}
+
+	names := make([]string, 0, len(o.key))
+	for name := range o.key {
+		if name != o.partitionKey {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	if _, ok := o.key[o.partitionKey]; ok {
+		names = append([]string{o.partitionKey}, names...)
+	}
+
+	sensitive := o.client.keyRedaction
+	for _, name := range names {
+		fields = append(fields, kit.ErrorField{Key: name, Value: keyFieldValue(o.key[name]), Sensitive: sensitive})
+	}
+
+	*err = kit.NewOperationError("dynamodbkit."+o.name, *err, fields...)
+}
+
+func keyFieldValue(value types.AttributeValue) string {
+	switch v := value.(type) {
+	case *types.AttributeValueMemberS:
+		return v.Value
+	case *types.AttributeValueMemberN:
+		return v.Value
+	case *types.AttributeValueMemberB:
+		return base64.StdEncoding.EncodeToString(v.Value)
+	default:
+		return "?"
+	}
+}
@@ -0,0 +1,93 @@
+package dynamodbkit
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/half-ogre/go-kit/kit"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOperationErrors(t *testing.T) {
+	t.Run("returns_an_operation_error_with_the_table_and_key", func(t *testing.T) {
+		fakeDB := &FakeDynamoDB{
+			GetItemFake: func(ctx context.Context, params *dynamodb.GetItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.GetItemOutput, error) {
+				return nil, errors.New("the fake error")
+			},
+		}
+		setFake(func(ctx context.Context) (DynamoDB, error) { return fakeDB, nil })
+		t.Cleanup(func() { setFake(nil) })
+
+		_, err := GetItem[TestUser](context.Background(), "theTable", "id", "theID")
+
+		var operationErr *kit.OperationError
+		assert.True(t, errors.As(err, &operationErr))
+		assert.Equal(t, "dynamodbkit.GetItem", operationErr.Op)
+		table, _ := operationErr.Field("table")
+		assert.Equal(t, "theTable", table)
+		id, _ := operationErr.Field("id")
+		assert.Equal(t, "theID", id)
+	})
+
+	t.Run("names_the_partition_key_before_the_sort_key", func(t *testing.T) {
+		fakeDB := &FakeDynamoDB{
+			DeleteItemFake: func(ctx context.Context, params *dynamodb.DeleteItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.DeleteItemOutput, error) {
+				return nil, errors.New("the fake error")
+			},
+		}
+		setFake(func(ctx context.Context) (DynamoDB, error) { return fakeDB, nil })
+		t.Cleanup(func() { setFake(nil) })
+
+		err := DeleteItem(context.Background(), "theTable", "user_id", "theUserID", WithDeleteItemSortKey("at", 42))
+
+		assert.EqualError(t, err, "dynamodbkit.DeleteItem table=theTable user_id=theUserID at=42: error deleting item: the fake error")
+	})
+
+	t.Run("redacts_key_values_with_key_redaction", func(t *testing.T) {
+		UseKeyRedaction(true)
+		t.Cleanup(func() { UseKeyRedaction(false) })
+		fakeDB := &FakeDynamoDB{
+			GetItemFake: func(ctx context.Context, params *dynamodb.GetItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.GetItemOutput, error) {
+				return nil, errors.New("the fake error")
+			},
+		}
+		setFake(func(ctx context.Context) (DynamoDB, error) { return fakeDB, nil })
+		t.Cleanup(func() { setFake(nil) })
+
+		_, err := GetItem[TestUser](context.Background(), "theTable", "email", "someone@example.com")
+
+		assert.EqualError(t, err, "dynamodbkit.GetItem table=theTable email=[REDACTED]: error getting item: the fake error")
+	})
+
+	t.Run("keeps_the_cause_for_classification", func(t *testing.T) {
+		fakeDB := &FakeDynamoDB{
+			PutItemFake: func(ctx context.Context, params *dynamodb.PutItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.PutItemOutput, error) {
+				return nil, &types.ConditionalCheckFailedException{}
+			},
+		}
+		setFake(func(ctx context.Context) (DynamoDB, error) { return fakeDB, nil })
+		t.Cleanup(func() { setFake(nil) })
+
+		err := PutItem(context.Background(), "aTable", TestUser{ID: "anID"})
+
+		var conditionErr *types.ConditionalCheckFailedException
+		assert.True(t, errors.As(err, &conditionErr))
+	})
+
+	t.Run("keeps_the_inner_operation_of_an_operation_built_on_another", func(t *testing.T) {
+		fakeDB := &FakeDynamoDB{
+			QueryFake: func(ctx context.Context, params *dynamodb.QueryInput, optFns ...func(*dynamodb.Options)) (*dynamodb.QueryOutput, error) {
+				return nil, errors.New("the fake error")
+			},
+		}
+		setFake(func(ctx context.Context) (DynamoDB, error) { return fakeDB, nil })
+		t.Cleanup(func() { setFake(nil) })
+
+		_, err := QueryEdges[Edge](context.Background(), "theTable", "USER#theID", EdgesOutgoing, "")
+
+		assert.EqualError(t, err, "error querying edges: dynamodbkit.QueryAll table=theTable PK=USER#theID: error querying: the fake error")
+	})
+}
@@ -0,0 +1,152 @@
+package dynamodbkit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/half-ogre/go-kit/kit"
+)
+
+// ExecuteStatement runs a PartiQL statement with params bound to its ? placeholders, in order, e.g.
+//
+//	output, err := dynamodbkit.ExecuteStatement[User](ctx, `SELECT * FROM "`+dynamodbkit.TableName(ctx, "users")+`" WHERE id = ?`, userID)
+//
+// Params are marshaled like item attributes, except ExecuteStatementOptions, which configure the
+// statement instead, e.g. WithStatementNextToken to read the page after one whose NextToken was set.
+// Table names in the statement are sent as written, so use TableName for the client's prefix and
+// suffix. The items a SELECT reads are unmarshaled into TItem; other statements return none.
+func ExecuteStatement[TItem any](ctx context.Context, statement string, params ...any) (_ *ExecuteStatementOutput[TItem], err error) {
+	op := newOperation(ctx, "ExecuteStatement", statementTableName(statement))
+	defer op.wrap(&err)
+
+	if strings.TrimSpace(statement) == "" {
+		return nil, errors.New("statement cannot be empty")
+	}
+
+	input := &dynamodb.ExecuteStatementInput{Statement: aws.String(statement)}
+	for i, param := range params {
+		if option, ok := param.(ExecuteStatementOption); ok {
+			if err := option(input); err != nil {
+				return nil, kit.WrapError(err, "error processing option")
+			}
+			continue
+		}
+
+		value, err := attributevalue.Marshal(param)
+		if err != nil {
+			return nil, kit.WrapError(err, "error marshalling parameter %d", i+1)
+		}
+		input.Parameters = append(input.Parameters, value)
+	}
+
+	db, err := op.dynamoDB(ctx)
+	if err != nil {
+		return nil, kit.WrapError(err, "error creating DynamoDB client")
+	}
+
+	executor, err := requireAPI[ExecuteStatementAPI](db, "ExecuteStatement")
+	if err != nil {
+		return nil, err
+	}
+
+	metrics := startOperation(op, "ExecuteStatement", aws.String(op.table))
+	input.ReturnConsumedCapacity = op.client.returnConsumedCapacity(metrics)
+
+	logStatementInput(ctx, input)
+
+	output, err := executor.ExecuteStatement(ctx, input)
+	if err != nil {
+		err = kit.WrapError(err, "error executing statement")
+		metrics.finish(ctx, err)
+		return nil, err
+	}
+
+	result := &ExecuteStatementOutput[TItem]{Items: make([]TItem, 0, len(output.Items)), NextToken: output.NextToken}
+	for _, i := range output.Items {
+		var item TItem
+		if err := attributevalue.UnmarshalMap(i, &item); err != nil {
+			err = kit.WrapError(err, "error unmarshalling statement item")
+			metrics.finish(ctx, err)
+			return nil, err
+		}
+		result.Items = append(result.Items, item)
+	}
+
+	addPage(metrics, &page[TItem]{items: result.Items, consumedCapacity: output.ConsumedCapacity})
+	metrics.finish(ctx, nil)
+
+	return result, nil
+}
+
+// ExecuteStatementOutput is a page of the items a statement read. NextToken is set when there are
+// more, and reads the next page with WithStatementNextToken.
+type ExecuteStatementOutput[TItem any] struct {
+	NextToken *string
+	Items     []TItem
+}
+
+// Page returns the output as a kit.Page, with NextToken as its cursor
+func (o *ExecuteStatementOutput[TItem]) Page() kit.Page[TItem] {
+	return kit.NewPage(o.Items, o.NextToken)
+}
+
+// ExecuteStatementOption configures ExecuteStatement; pass it among the statement's params
+type ExecuteStatementOption func(*dynamodb.ExecuteStatementInput) error
+
+// WithStatementNextToken reads the page after the one whose output had nextToken
+func WithStatementNextToken(nextToken string) ExecuteStatementOption {
+	return func(input *dynamodb.ExecuteStatementInput) error {
+		input.NextToken = aws.String(nextToken)
+		return nil
+	}
+}
+
+// WithStatementLimit sets the most items the statement evaluates, as WithQueryLimit does for Query
+func WithStatementLimit(limit int64) ExecuteStatementOption {
+	return func(input *dynamodb.ExecuteStatementInput) error {
+		if limit < 0 {
+			return fmt.Errorf("limit must be non-negative, got %d", limit)
+		}
+		if limit > 2147483647 { // int32 max
+			return fmt.Errorf("limit exceeds maximum allowed value, got %d", limit)
+		}
+		input.Limit = aws.Int32(int32(limit))
+		return nil
+	}
+}
+
+// WithStatementConsistentRead makes a SELECT strongly consistent
+func WithStatementConsistentRead() ExecuteStatementOption {
+	return func(input *dynamodb.ExecuteStatementInput) error {
+		input.ConsistentRead = aws.Bool(true)
+		return nil
+	}
+}
+
+// statementTablePattern finds the table a PartiQL statement reads or writes: the name after FROM,
+// INTO, or UPDATE, quoted or not, without any "index" part
+var statementTablePattern = regexp.MustCompile(`(?i)\b(?:FROM|INTO|UPDATE)\s+(?:"([^"]+)"|([A-Za-z0-9_]+))`)
+
+// statementTableName returns the table a PartiQL statement reads or writes, or "" if it can't tell
+func statementTableName(statement string) string {
+	match := statementTablePattern.FindStringSubmatch(statement)
+	if match == nil {
+		return ""
+	}
+	if match[1] != "" {
+		return match[1]
+	}
+	return match[2]
+}
+
+// isSelectStatement reports whether a PartiQL statement only reads
+func isSelectStatement(statement string) bool {
+	fields := strings.Fields(statement)
+	return len(fields) > 0 && strings.EqualFold(fields[0], "SELECT")
+}
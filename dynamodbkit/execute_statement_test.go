@@ -0,0 +1,124 @@
+package dynamodbkit
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/half-ogre/go-kit/kit"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExecuteStatement(t *testing.T) {
+	t.Run("executes_the_statement_with_marshaled_parameters_and_returns_the_items", func(t *testing.T) {
+		var actualInput *dynamodb.ExecuteStatementInput
+		setFakeDB(t, &FakeDynamoDB{
+			ExecuteStatementFake: func(ctx context.Context, params *dynamodb.ExecuteStatementInput, optFns ...func(*dynamodb.Options)) (*dynamodb.ExecuteStatementOutput, error) {
+				actualInput = params
+				return &dynamodb.ExecuteStatementOutput{
+					Items:     []map[string]types.AttributeValue{mustMarshalMap(t, TestUser{ID: "theID", Name: "theName"})},
+					NextToken: aws.String("theNextToken"),
+				}, nil
+			},
+		})
+
+		output, err := ExecuteStatement[TestUser](context.Background(), `SELECT * FROM "theTable" WHERE id = ? AND age > ?`, "theID", 42)
+
+		assert.NoError(t, err)
+		assert.Equal(t, `SELECT * FROM "theTable" WHERE id = ? AND age > ?`, aws.ToString(actualInput.Statement))
+		assert.Equal(t, []types.AttributeValue{
+			&types.AttributeValueMemberS{Value: "theID"},
+			&types.AttributeValueMemberN{Value: "42"},
+		}, actualInput.Parameters)
+		assert.Equal(t, []TestUser{{ID: "theID", Name: "theName"}}, output.Items)
+		assert.Equal(t, "theNextToken", aws.ToString(output.NextToken))
+		assert.Equal(t, kit.NewPage(output.Items, aws.String("theNextToken")), output.Page())
+	})
+
+	t.Run("applies_options_passed_among_the_parameters", func(t *testing.T) {
+		var actualInput *dynamodb.ExecuteStatementInput
+		setFakeDB(t, &FakeDynamoDB{
+			ExecuteStatementFake: func(ctx context.Context, params *dynamodb.ExecuteStatementInput, optFns ...func(*dynamodb.Options)) (*dynamodb.ExecuteStatementOutput, error) {
+				actualInput = params
+				return &dynamodb.ExecuteStatementOutput{}, nil
+			},
+		})
+
+		output, err := ExecuteStatement[TestUser](context.Background(), `SELECT * FROM "theTable" WHERE id = ?`, "theID",
+			WithStatementNextToken("theNextToken"), WithStatementLimit(10), WithStatementConsistentRead())
+
+		assert.NoError(t, err)
+		assert.Equal(t, []types.AttributeValue{&types.AttributeValueMemberS{Value: "theID"}}, actualInput.Parameters)
+		assert.Equal(t, "theNextToken", aws.ToString(actualInput.NextToken))
+		assert.Equal(t, int32(10), aws.ToInt32(actualInput.Limit))
+		assert.True(t, aws.ToBool(actualInput.ConsistentRead))
+		assert.Empty(t, output.Items)
+		assert.Nil(t, output.NextToken)
+	})
+
+	t.Run("returns_an_error_for_an_empty_statement", func(t *testing.T) {
+		_, err := ExecuteStatement[TestUser](context.Background(), " ")
+
+		assert.EqualError(t, err, "dynamodbkit.ExecuteStatement: statement cannot be empty")
+	})
+
+	t.Run("returns_the_error_from_execute_statement_with_the_table", func(t *testing.T) {
+		setFakeDB(t, &FakeDynamoDB{
+			ExecuteStatementFake: func(ctx context.Context, params *dynamodb.ExecuteStatementInput, optFns ...func(*dynamodb.Options)) (*dynamodb.ExecuteStatementOutput, error) {
+				return nil, errors.New("the statement error")
+			},
+		})
+
+		_, err := ExecuteStatement[TestUser](context.Background(), `DELETE FROM "theTable" WHERE id = ?`, "theID")
+
+		assert.EqualError(t, err, "dynamodbkit.ExecuteStatement table=theTable: error executing statement: the statement error")
+	})
+
+	t.Run("reports_instrumentation_for_the_statement", func(t *testing.T) {
+		var actualMetrics OperationMetrics
+		setFakeDB(t, &FakeDynamoDB{
+			ExecuteStatementFake: func(ctx context.Context, params *dynamodb.ExecuteStatementInput, optFns ...func(*dynamodb.Options)) (*dynamodb.ExecuteStatementOutput, error) {
+				assert.Equal(t, types.ReturnConsumedCapacityTotal, params.ReturnConsumedCapacity)
+				return &dynamodb.ExecuteStatementOutput{
+					Items:            []map[string]types.AttributeValue{mustMarshalMap(t, TestUser{ID: "theID"})},
+					ConsumedCapacity: &types.ConsumedCapacity{CapacityUnits: aws.Float64(0.5)},
+				}, nil
+			},
+		})
+		UseInstrumentation(func(ctx context.Context, metrics OperationMetrics) { actualMetrics = metrics })
+		t.Cleanup(func() { UseInstrumentation(nil) })
+
+		_, err := ExecuteStatement[TestUser](context.Background(), `SELECT * FROM theTable."theIndex"`)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "ExecuteStatement", actualMetrics.Operation)
+		assert.Equal(t, "theTable", actualMetrics.Table)
+		assert.Equal(t, 1, actualMetrics.Items)
+		assert.Equal(t, 0.5, actualMetrics.ConsumedCapacity)
+	})
+}
+
+func TestTableName(t *testing.T) {
+	t.Run("returns_the_name_with_the_context_client_prefix_and_suffix", func(t *testing.T) {
+		ctx := WithClient(context.Background(), NewClient(&FakeDynamoDB{}, WithClientTableNamePrefix("thePrefix-"), WithClientTableNameSuffix("-theSuffix")))
+
+		assert.Equal(t, "thePrefix-theTable-theSuffix", TableName(ctx, "theTable"))
+	})
+}
+
+func TestStatementTableName(t *testing.T) {
+	for statement, expected := range map[string]string{
+		`SELECT * FROM "theTable" WHERE id = ?`:         "theTable",
+		`select id from theTable."theIndex"`:            "theTable",
+		`INSERT INTO "the-table" VALUE {'id': ?}`:       "the-table",
+		`UPDATE "theTable" SET name = ? WHERE id = ?`:   "theTable",
+		`DELETE FROM theTable WHERE id = ?`:             "theTable",
+		`EXISTS(SELECT * FROM "theTable" WHERE id = ?)`: "theTable",
+		`SELECT 1`: "",
+	} {
+		assert.Equal(t, expected, statementTableName(statement), statement)
+	}
+}
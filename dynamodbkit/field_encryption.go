@@ -0,0 +1,354 @@
+package dynamodbkit
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/half-ogre/go-kit/kit"
+)
+
+// FieldEncrypter encrypts and decrypts attribute values for WithFieldEncryption, e.g. a
+// *kmskit.Encrypter
+type FieldEncrypter interface {
+	Encrypt(ctx context.Context, plaintext []byte, associatedData []byte) ([]byte, error)
+	Decrypt(ctx context.Context, ciphertext []byte, associatedData []byte) ([]byte, error)
+}
+
+// EncryptedDynamoDB encrypts attributes of the items written through it and decrypts them in the items
+// read, see WithFieldEncryption
+type EncryptedDynamoDB struct {
+	db        DynamoDB
+	encrypter FieldEncrypter
+	fields    map[string][]string
+}
+
+// WithFieldEncryption returns db with the attributes named in fields encrypted, for use with NewClient,
+// e.g.
+//
+//	encrypter := kmskit.NewEncrypter(kms, keyARN)
+//	db := dynamodbkit.WithFieldEncryption(dynamodb.NewFromConfig(cfg), encrypter, map[string][]string{"users": {"ssn"}})
+//
+// fields maps table names, as sent to DynamoDB with any prefix or suffix, to the attributes to
+// encrypt. String, number, and binary attributes can be encrypted; they are stored as binary, so they
+// can't be keys, be used in conditions or filters, or be changed by UpdateItem. The attribute's name
+// is the associated data, so a value copied to another attribute fails to decrypt.
+//
+// To cache reads, wrap the cache around the encrypted DynamoDB's db rather than around it, so the
+// cache holds ciphertext, e.g. WithFieldEncryption(WithCache(db, cache, ttl), encrypter, fields).
+func WithFieldEncryption(db DynamoDB, encrypter FieldEncrypter, fields map[string][]string) *EncryptedDynamoDB {
+	return &EncryptedDynamoDB{db: db, encrypter: encrypter, fields: fields}
+}
+
+func (e *EncryptedDynamoDB) PutItem(ctx context.Context, params *dynamodb.PutItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.PutItemOutput, error) {
+	encrypted, err := e.encryptItem(ctx, aws.ToString(params.TableName), params.Item)
+	if err != nil {
+		return nil, err
+	}
+	input := *params
+	input.Item = encrypted
+
+	output, err := e.db.PutItem(ctx, &input, optFns...)
+	if err != nil {
+		return nil, err
+	}
+	return output, e.decryptItemInPlace(ctx, aws.ToString(params.TableName), &output.Attributes)
+}
+
+func (e *EncryptedDynamoDB) GetItem(ctx context.Context, params *dynamodb.GetItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.GetItemOutput, error) {
+	output, err := e.db.GetItem(ctx, params, optFns...)
+	if err != nil {
+		return nil, err
+	}
+	return output, e.decryptItemInPlace(ctx, aws.ToString(params.TableName), &output.Item)
+}
+
+func (e *EncryptedDynamoDB) Query(ctx context.Context, params *dynamodb.QueryInput, optFns ...func(*dynamodb.Options)) (*dynamodb.QueryOutput, error) {
+	output, err := e.db.Query(ctx, params, optFns...)
+	if err != nil {
+		return nil, err
+	}
+	return output, e.decryptItemsInPlace(ctx, aws.ToString(params.TableName), output.Items)
+}
+
+func (e *EncryptedDynamoDB) Scan(ctx context.Context, params *dynamodb.ScanInput, optFns ...func(*dynamodb.Options)) (*dynamodb.ScanOutput, error) {
+	output, err := e.db.Scan(ctx, params, optFns...)
+	if err != nil {
+		return nil, err
+	}
+	return output, e.decryptItemsInPlace(ctx, aws.ToString(params.TableName), output.Items)
+}
+
+func (e *EncryptedDynamoDB) DeleteItem(ctx context.Context, params *dynamodb.DeleteItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.DeleteItemOutput, error) {
+	output, err := e.db.DeleteItem(ctx, params, optFns...)
+	if err != nil {
+		return nil, err
+	}
+	return output, e.decryptItemInPlace(ctx, aws.ToString(params.TableName), &output.Attributes)
+}
+
+func (e *EncryptedDynamoDB) UpdateItem(ctx context.Context, params *dynamodb.UpdateItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.UpdateItemOutput, error) {
+	tableName := aws.ToString(params.TableName)
+	if err := e.checkUpdate(tableName, params.UpdateExpression, params.ExpressionAttributeNames); err != nil {
+		return nil, err
+	}
+
+	updater, err := requireAPI[UpdateItemAPI](e.db, "UpdateItem")
+	if err != nil {
+		return nil, err
+	}
+	output, err := updater.UpdateItem(ctx, params, optFns...)
+	if err != nil {
+		return nil, err
+	}
+	return output, e.decryptItemInPlace(ctx, tableName, &output.Attributes)
+}
+
+func (e *EncryptedDynamoDB) BatchWriteItem(ctx context.Context, params *dynamodb.BatchWriteItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.BatchWriteItemOutput, error) {
+	input := *params
+	input.RequestItems = map[string][]types.WriteRequest{}
+	for tableName, requests := range params.RequestItems {
+		encryptedRequests := make([]types.WriteRequest, len(requests))
+		for i, request := range requests {
+			encryptedRequests[i] = request
+			if request.PutRequest == nil {
+				continue
+			}
+			encrypted, err := e.encryptItem(ctx, tableName, request.PutRequest.Item)
+			if err != nil {
+				return nil, err
+			}
+			encryptedRequests[i].PutRequest = &types.PutRequest{Item: encrypted}
+		}
+		input.RequestItems[tableName] = encryptedRequests
+	}
+
+	writer, err := requireAPI[BatchWriteItemAPI](e.db, "BatchWriteItem")
+	if err != nil {
+		return nil, err
+	}
+	output, err := writer.BatchWriteItem(ctx, &input, optFns...)
+	if err != nil {
+		return nil, err
+	}
+
+	// Unprocessed items are decrypted so a retry through this DynamoDB doesn't encrypt them twice
+	for tableName, requests := range output.UnprocessedItems {
+		for i, request := range requests {
+			if request.PutRequest == nil {
+				continue
+			}
+			item := request.PutRequest.Item
+			if err := e.decryptItemInPlace(ctx, tableName, &item); err != nil {
+				return nil, err
+			}
+			requests[i].PutRequest = &types.PutRequest{Item: item}
+		}
+	}
+	return output, nil
+}
+
+func (e *EncryptedDynamoDB) TransactWriteItems(ctx context.Context, params *dynamodb.TransactWriteItemsInput, optFns ...func(*dynamodb.Options)) (*dynamodb.TransactWriteItemsOutput, error) {
+	input := *params
+	input.TransactItems = make([]types.TransactWriteItem, len(params.TransactItems))
+	for i, item := range params.TransactItems {
+		input.TransactItems[i] = item
+		switch {
+		case item.Put != nil:
+			encrypted, err := e.encryptItem(ctx, aws.ToString(item.Put.TableName), item.Put.Item)
+			if err != nil {
+				return nil, err
+			}
+			put := *item.Put
+			put.Item = encrypted
+			input.TransactItems[i].Put = &put
+		case item.Update != nil:
+			if err := e.checkUpdate(aws.ToString(item.Update.TableName), item.Update.UpdateExpression, item.Update.ExpressionAttributeNames); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	writer, err := requireAPI[TransactWriteItemsAPI](e.db, "TransactWriteItems")
+	if err != nil {
+		return nil, err
+	}
+	return writer.TransactWriteItems(ctx, &input, optFns...)
+}
+
+// ExecuteStatement decrypts the items a PartiQL SELECT reads. Other statements are rejected when they
+// write a table with encrypted attributes, or when their table can't be told from the statement, as
+// their values would be written unencrypted.
+func (e *EncryptedDynamoDB) ExecuteStatement(ctx context.Context, params *dynamodb.ExecuteStatementInput, optFns ...func(*dynamodb.Options)) (*dynamodb.ExecuteStatementOutput, error) {
+	statement := aws.ToString(params.Statement)
+	tableName := statementTableName(statement)
+	if !isSelectStatement(statement) {
+		if tableName == "" && len(e.fields) > 0 {
+			return nil, fmt.Errorf("cannot tell the table written by statement; it may have encrypted attributes")
+		}
+		if len(e.fields[tableName]) > 0 {
+			return nil, fmt.Errorf("cannot write table %s with a statement as it has encrypted attributes; put the whole item instead", tableName)
+		}
+	}
+
+	executor, err := requireAPI[ExecuteStatementAPI](e.db, "ExecuteStatement")
+	if err != nil {
+		return nil, err
+	}
+	output, err := executor.ExecuteStatement(ctx, params, optFns...)
+	if err != nil {
+		return nil, err
+	}
+	return output, e.decryptItemsInPlace(ctx, tableName, output.Items)
+}
+
+func (e *EncryptedDynamoDB) ListTables(ctx context.Context, params *dynamodb.ListTablesInput, optFns ...func(*dynamodb.Options)) (*dynamodb.ListTablesOutput, error) {
+	return e.db.ListTables(ctx, params, optFns...)
+}
+
+func (e *EncryptedDynamoDB) CreateTable(ctx context.Context, params *dynamodb.CreateTableInput, optFns ...func(*dynamodb.Options)) (*dynamodb.CreateTableOutput, error) {
+	creator, err := requireAPI[CreateTableAPI](e.db, "CreateTable")
+	if err != nil {
+		return nil, err
+	}
+	return creator.CreateTable(ctx, params, optFns...)
+}
+
+func (e *EncryptedDynamoDB) DeleteTable(ctx context.Context, params *dynamodb.DeleteTableInput, optFns ...func(*dynamodb.Options)) (*dynamodb.DeleteTableOutput, error) {
+	deleter, err := requireAPI[DeleteTableAPI](e.db, "DeleteTable")
+	if err != nil {
+		return nil, err
+	}
+	return deleter.DeleteTable(ctx, params, optFns...)
+}
+
+func (e *EncryptedDynamoDB) DescribeTable(ctx context.Context, params *dynamodb.DescribeTableInput, optFns ...func(*dynamodb.Options)) (*dynamodb.DescribeTableOutput, error) {
+	describer, err := requireAPI[DescribeTableAPI](e.db, "DescribeTable")
+	if err != nil {
+		return nil, err
+	}
+	return describer.DescribeTable(ctx, params, optFns...)
+}
+
+// The first byte of an encrypted value's plaintext is the type of the attribute it was
+const (
+	encryptedString byte = 'S'
+	encryptedNumber byte = 'N'
+	encryptedBinary byte = 'B'
+)
+
+// encryptItem returns a copy of item with the table's encrypted attributes encrypted
+func (e *EncryptedDynamoDB) encryptItem(ctx context.Context, tableName string, item map[string]types.AttributeValue) (map[string]types.AttributeValue, error) {
+	attributes := e.fields[tableName]
+	if len(attributes) == 0 || item == nil {
+		return item, nil
+	}
+
+	encrypted := maps.Clone(item)
+	for _, name := range attributes {
+		value, ok := item[name]
+		if !ok {
+			continue
+		}
+
+		var plaintext []byte
+		switch v := value.(type) {
+		case *types.AttributeValueMemberS:
+			plaintext = append([]byte{encryptedString}, v.Value...)
+		case *types.AttributeValueMemberN:
+			plaintext = append([]byte{encryptedNumber}, v.Value...)
+		case *types.AttributeValueMemberB:
+			plaintext = append([]byte{encryptedBinary}, v.Value...)
+		case *types.AttributeValueMemberNULL:
+			continue
+		default:
+			return nil, fmt.Errorf("cannot encrypt attribute %s of table %s: only string, number, and binary attributes can be encrypted", name, tableName)
+		}
+
+		ciphertext, err := e.encrypter.Encrypt(ctx, plaintext, []byte(name))
+		if err != nil {
+			return nil, kit.WrapError(err, "error encrypting attribute %s of table %s", name, tableName)
+		}
+		encrypted[name] = &types.AttributeValueMemberB{Value: ciphertext}
+	}
+	return encrypted, nil
+}
+
+// decryptItemInPlace replaces *item with a copy that has the table's encrypted attributes decrypted
+func (e *EncryptedDynamoDB) decryptItemInPlace(ctx context.Context, tableName string, item *map[string]types.AttributeValue) error {
+	attributes := e.fields[tableName]
+	if len(attributes) == 0 || *item == nil {
+		return nil
+	}
+
+	decrypted := maps.Clone(*item)
+	for _, name := range attributes {
+		value, ok := decrypted[name]
+		if !ok {
+			continue
+		}
+		binary, ok := value.(*types.AttributeValueMemberB)
+		if !ok {
+			// Written before the attribute was encrypted
+			continue
+		}
+
+		plaintext, err := e.encrypter.Decrypt(ctx, binary.Value, []byte(name))
+		if err != nil {
+			return kit.WrapError(err, "error decrypting attribute %s of table %s", name, tableName)
+		}
+		if len(plaintext) == 0 {
+			return fmt.Errorf("error decrypting attribute %s of table %s: empty plaintext", name, tableName)
+		}
+
+		switch plaintext[0] {
+		case encryptedString:
+			decrypted[name] = &types.AttributeValueMemberS{Value: string(plaintext[1:])}
+		case encryptedNumber:
+			decrypted[name] = &types.AttributeValueMemberN{Value: string(plaintext[1:])}
+		case encryptedBinary:
+			decrypted[name] = &types.AttributeValueMemberB{Value: plaintext[1:]}
+		default:
+			return fmt.Errorf("error decrypting attribute %s of table %s: unknown type %q", name, tableName, plaintext[0])
+		}
+	}
+	*item = decrypted
+	return nil
+}
+
+func (e *EncryptedDynamoDB) decryptItemsInPlace(ctx context.Context, tableName string, items []map[string]types.AttributeValue) error {
+	for i := range items {
+		if err := e.decryptItemInPlace(ctx, tableName, &items[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// checkUpdate returns an error if an update expression names one of the table's encrypted attributes,
+// as the new value would be written unencrypted
+func (e *EncryptedDynamoDB) checkUpdate(tableName string, expression *string, names map[string]string) error {
+	attributes := e.fields[tableName]
+	if len(attributes) == 0 {
+		return nil
+	}
+
+	isNamePart := func(r rune) bool {
+		return r == '_' || r == '-' || r == '#' || r == ':' || ('a' <= r && r <= 'z') || ('A' <= r && r <= 'Z') || ('0' <= r && r <= '9')
+	}
+	for _, token := range strings.FieldsFunc(aws.ToString(expression), func(r rune) bool { return !isNamePart(r) }) {
+		name := token
+		if strings.HasPrefix(token, "#") {
+			name = names[token]
+		}
+		if slices.Contains(attributes, name) {
+			return fmt.Errorf("cannot update encrypted attribute %s of table %s; put the whole item instead", name, tableName)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,188 @@
+package dynamodbkit
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/half-ogre/go-kit/kmskit"
+)
+
+type encryptedTestItem struct {
+	ID     string `dynamodbav:"id"`
+	Secret string `dynamodbav:"secret"`
+	Count  int    `dynamodbav:"count"`
+}
+
+func newEncryptedTestContext(db DynamoDB) context.Context {
+	encrypter := kmskit.NewEncrypter(kmskit.NewRandomLocalKMS("theKey"), "theKey")
+	return WithClient(context.Background(), NewClient(WithFieldEncryption(db, encrypter, map[string][]string{"theTableName": {"secret", "count"}})))
+}
+
+func TestWithFieldEncryption(t *testing.T) {
+	t.Run("stores_encrypted_attributes_encrypted_and_reads_them_decrypted", func(t *testing.T) {
+		t.Parallel()
+		var stored map[string]types.AttributeValue
+		db := &FakeDynamoDB{
+			PutItemFake: func(ctx context.Context, params *dynamodb.PutItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.PutItemOutput, error) {
+				stored = params.Item
+				return &dynamodb.PutItemOutput{}, nil
+			},
+			GetItemFake: func(ctx context.Context, params *dynamodb.GetItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.GetItemOutput, error) {
+				return &dynamodb.GetItemOutput{Item: stored}, nil
+			},
+		}
+		ctx := newEncryptedTestContext(db)
+		theItem := encryptedTestItem{ID: "theID", Secret: "theSecret", Count: 42}
+
+		err := PutItem(ctx, "theTableName", theItem)
+		assert.NoError(t, err)
+		item, err := GetItem[encryptedTestItem](ctx, "theTableName", "id", "theID")
+
+		assert.NoError(t, err)
+		assert.Equal(t, &theItem, item)
+		assert.Equal(t, &types.AttributeValueMemberS{Value: "theID"}, stored["id"])
+		assert.IsType(t, &types.AttributeValueMemberB{}, stored["secret"])
+		assert.NotContains(t, string(stored["secret"].(*types.AttributeValueMemberB).Value), "theSecret")
+		assert.IsType(t, &types.AttributeValueMemberB{}, stored["count"])
+	})
+
+	t.Run("decrypts_query_results", func(t *testing.T) {
+		t.Parallel()
+		var stored map[string]types.AttributeValue
+		db := &FakeDynamoDB{
+			PutItemFake: func(ctx context.Context, params *dynamodb.PutItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.PutItemOutput, error) {
+				stored = params.Item
+				return &dynamodb.PutItemOutput{}, nil
+			},
+			QueryFake: func(ctx context.Context, params *dynamodb.QueryInput, optFns ...func(*dynamodb.Options)) (*dynamodb.QueryOutput, error) {
+				return &dynamodb.QueryOutput{Items: []map[string]types.AttributeValue{stored}}, nil
+			},
+		}
+		ctx := newEncryptedTestContext(db)
+		theItem := encryptedTestItem{ID: "theID", Secret: "theSecret", Count: 42}
+		assert.NoError(t, PutItem(ctx, "theTableName", theItem))
+
+		output, err := Query[encryptedTestItem](ctx, "theTableName", "id", "theID")
+
+		assert.NoError(t, err)
+		assert.Equal(t, []encryptedTestItem{theItem}, output.Items)
+	})
+
+	t.Run("leaves_other_tables_unencrypted", func(t *testing.T) {
+		t.Parallel()
+		var stored map[string]types.AttributeValue
+		ctx := newEncryptedTestContext(&FakeDynamoDB{
+			PutItemFake: func(ctx context.Context, params *dynamodb.PutItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.PutItemOutput, error) {
+				stored = params.Item
+				return &dynamodb.PutItemOutput{}, nil
+			},
+		})
+
+		err := PutItem(ctx, "anotherTableName", encryptedTestItem{ID: "theID", Secret: "theSecret"})
+
+		assert.NoError(t, err)
+		assert.Equal(t, &types.AttributeValueMemberS{Value: "theSecret"}, stored["secret"])
+	})
+
+	t.Run("returns_an_error_for_an_update_of_an_encrypted_attribute", func(t *testing.T) {
+		t.Parallel()
+		ctx := newEncryptedTestContext(&FakeDynamoDB{
+			UpdateItemFake: func(ctx context.Context, params *dynamodb.UpdateItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.UpdateItemOutput, error) {
+				t.Fatal("update should not be sent")
+				return nil, nil
+			},
+		})
+
+		_, err := UpdateItem[encryptedTestItem](ctx, "theTableName", "id", "theID", WithUpdateSet("secret", "anotherSecret"))
+
+		assert.ErrorContains(t, err, "cannot update encrypted attribute secret of table theTableName")
+	})
+
+	t.Run("decrypts_statement_results", func(t *testing.T) {
+		t.Parallel()
+		var stored map[string]types.AttributeValue
+		db := &FakeDynamoDB{
+			PutItemFake: func(ctx context.Context, params *dynamodb.PutItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.PutItemOutput, error) {
+				stored = params.Item
+				return &dynamodb.PutItemOutput{}, nil
+			},
+			ExecuteStatementFake: func(ctx context.Context, params *dynamodb.ExecuteStatementInput, optFns ...func(*dynamodb.Options)) (*dynamodb.ExecuteStatementOutput, error) {
+				return &dynamodb.ExecuteStatementOutput{Items: []map[string]types.AttributeValue{stored}}, nil
+			},
+		}
+		ctx := newEncryptedTestContext(db)
+		theItem := encryptedTestItem{ID: "theID", Secret: "theSecret", Count: 42}
+
+		err := PutItem(ctx, "theTableName", theItem)
+		assert.NoError(t, err)
+		output, err := ExecuteStatement[encryptedTestItem](ctx, `SELECT * FROM "theTableName" WHERE id = ?`, "theID")
+
+		assert.NoError(t, err)
+		assert.Equal(t, []encryptedTestItem{theItem}, output.Items)
+	})
+
+	t.Run("returns_an_error_for_a_statement_writing_a_table_with_encrypted_attributes", func(t *testing.T) {
+		t.Parallel()
+		ctx := newEncryptedTestContext(&FakeDynamoDB{
+			ExecuteStatementFake: func(ctx context.Context, params *dynamodb.ExecuteStatementInput, optFns ...func(*dynamodb.Options)) (*dynamodb.ExecuteStatementOutput, error) {
+				t.Fatal("statement should not be sent")
+				return nil, nil
+			},
+		})
+
+		_, err := ExecuteStatement[encryptedTestItem](ctx, `INSERT INTO "theTableName" VALUE {'id': ?, 'secret': ?}`, "theID", "theSecret")
+
+		assert.ErrorContains(t, err, "cannot write table theTableName with a statement as it has encrypted attributes")
+	})
+
+	t.Run("returns_an_error_for_an_attribute_type_that_cannot_be_encrypted", func(t *testing.T) {
+		t.Parallel()
+		ctx := newEncryptedTestContext(&FakeDynamoDB{})
+
+		err := PutItem(ctx, "theTableName", map[string]any{"id": "theID", "secret": []string{"a", "b"}})
+
+		assert.ErrorContains(t, err, "cannot encrypt attribute secret of table theTableName")
+	})
+
+	t.Run("returns_unprocessed_batch_items_decrypted", func(t *testing.T) {
+		t.Parallel()
+		encrypter := kmskit.NewEncrypter(kmskit.NewRandomLocalKMS("theKey"), "theKey")
+		db := WithFieldEncryption(&FakeDynamoDB{
+			BatchWriteItemFake: func(ctx context.Context, params *dynamodb.BatchWriteItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.BatchWriteItemOutput, error) {
+				return &dynamodb.BatchWriteItemOutput{UnprocessedItems: params.RequestItems}, nil
+			},
+		}, encrypter, map[string][]string{"theTableName": {"secret"}})
+		theItem := map[string]types.AttributeValue{
+			"id":     &types.AttributeValueMemberS{Value: "theID"},
+			"secret": &types.AttributeValueMemberS{Value: "theSecret"},
+		}
+
+		output, err := db.BatchWriteItem(context.Background(), &dynamodb.BatchWriteItemInput{
+			RequestItems: map[string][]types.WriteRequest{"theTableName": {{PutRequest: &types.PutRequest{Item: theItem}}}},
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, theItem, output.UnprocessedItems["theTableName"][0].PutRequest.Item)
+	})
+
+	t.Run("reads_values_written_before_encryption", func(t *testing.T) {
+		t.Parallel()
+		ctx := newEncryptedTestContext(&FakeDynamoDB{
+			GetItemFake: func(ctx context.Context, params *dynamodb.GetItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.GetItemOutput, error) {
+				return &dynamodb.GetItemOutput{Item: map[string]types.AttributeValue{
+					"id":     &types.AttributeValueMemberS{Value: "theID"},
+					"secret": &types.AttributeValueMemberS{Value: "theSecret"},
+				}}, nil
+			},
+		})
+
+		item, err := GetItem[encryptedTestItem](ctx, "theTableName", "id", "theID")
+
+		assert.NoError(t, err)
+		assert.Equal(t, "theSecret", item.Secret)
+	})
+}
@@ -0,0 +1,85 @@
+package dynamodbkit
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/half-ogre/go-kit/kit"
+)
+
+// WithQueryFilterExpression filters the queried items on the server, e.g. "#status = :status" with
+// names {"#status": "status"} and values {":status": "active"}. It's ANDed with any filter from another
+// option. DynamoDB filters after reading, so filtered out items still count toward WithQueryLimit and
+// consumed capacity. The key condition uses the placeholders #0 and :0, so don't use those.
+func WithQueryFilterExpression(expr string, names map[string]string, values map[string]any) QueryOption {
+	return func(input *dynamodb.QueryInput) error {
+		filter, err := addFilterExpression(input.FilterExpression, expr, &input.ExpressionAttributeNames, &input.ExpressionAttributeValues, names, values)
+		if err != nil {
+			return err
+		}
+		input.FilterExpression = filter
+		return nil
+	}
+}
+
+// WithScanFilterExpression filters the scanned items on the server, as WithQueryFilterExpression does
+// for queries. Filtered out items still count toward WithScanLimit and consumed capacity.
+func WithScanFilterExpression(expr string, names map[string]string, values map[string]any) ScanOption {
+	return func(input *dynamodb.ScanInput) error {
+		filter, err := addFilterExpression(input.FilterExpression, expr, &input.ExpressionAttributeNames, &input.ExpressionAttributeValues, names, values)
+		if err != nil {
+			return err
+		}
+		input.FilterExpression = filter
+		return nil
+	}
+}
+
+// addFilterExpression adds names and values to the input's expression attributes and returns
+// existing ANDed with expr. A placeholder already in use for something else is an error rather than
+// being silently replaced.
+func addFilterExpression(existing *string, expr string, inputNames *map[string]string, inputValues *map[string]types.AttributeValue, names map[string]string, values map[string]any) (*string, error) {
+	if expr == "" {
+		return nil, errors.New("filter expression cannot be empty")
+	}
+
+	for name, attribute := range names {
+		if current, ok := (*inputNames)[name]; ok && current != attribute {
+			return nil, fmt.Errorf("expression attribute name %s is already used for %s", name, current)
+		}
+	}
+
+	attributeValues := make(map[string]types.AttributeValue, len(values))
+	for name, value := range values {
+		if _, ok := (*inputValues)[name]; ok {
+			return nil, fmt.Errorf("expression attribute value %s is already used", name)
+		}
+		attributeValue, err := attributevalue.Marshal(value)
+		if err != nil {
+			return nil, kit.WrapError(err, "error marshalling filter value %s", name)
+		}
+		attributeValues[name] = attributeValue
+	}
+
+	if len(names) > 0 && *inputNames == nil {
+		*inputNames = map[string]string{}
+	}
+	for name, attribute := range names {
+		(*inputNames)[name] = attribute
+	}
+	if len(attributeValues) > 0 && *inputValues == nil {
+		*inputValues = map[string]types.AttributeValue{}
+	}
+	for name, attributeValue := range attributeValues {
+		(*inputValues)[name] = attributeValue
+	}
+
+	if existing == nil {
+		return aws.String(expr), nil
+	}
+	return aws.String(fmt.Sprintf("(%s) AND (%s)", *existing, expr)), nil
+}
@@ -0,0 +1,129 @@
+package dynamodbkit
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithQueryFilterExpression(t *testing.T) {
+	t.Run("sets_the_filter_names_and_values", func(t *testing.T) {
+		input := &dynamodb.QueryInput{}
+
+		err := WithQueryFilterExpression("#status = :status", map[string]string{"#status": "status"}, map[string]any{":status": "theStatus"})(input)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "#status = :status", *input.FilterExpression)
+		assert.Equal(t, map[string]string{"#status": "status"}, input.ExpressionAttributeNames)
+		assert.Equal(t, map[string]types.AttributeValue{":status": &types.AttributeValueMemberS{Value: "theStatus"}}, input.ExpressionAttributeValues)
+	})
+
+	t.Run("ands_the_filter_with_an_existing_one", func(t *testing.T) {
+		input := &dynamodb.QueryInput{}
+
+		err := WithQueryFilterExpression("#status = :status", map[string]string{"#status": "status"}, map[string]any{":status": "aStatus"})(input)
+		assert.NoError(t, err)
+		err = WithQueryFilterExpression("#total > :total", map[string]string{"#total": "total"}, map[string]any{":total": 10})(input)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "(#status = :status) AND (#total > :total)", *input.FilterExpression)
+		assert.Len(t, input.ExpressionAttributeNames, 2)
+		assert.Len(t, input.ExpressionAttributeValues, 2)
+	})
+
+	t.Run("filters_the_query_alongside_the_key_condition", func(t *testing.T) {
+		var actualInput *dynamodb.QueryInput
+		fakeDB := &FakeDynamoDB{
+			QueryFake: func(ctx context.Context, params *dynamodb.QueryInput, optFns ...func(*dynamodb.Options)) (*dynamodb.QueryOutput, error) {
+				actualInput = params
+				return &dynamodb.QueryOutput{}, nil
+			},
+		}
+		setFake(func(ctx context.Context) (DynamoDB, error) { return fakeDB, nil })
+		t.Cleanup(func() { setFake(nil) })
+
+		_, err := Query[TestUser](context.Background(), "aTable", "id", "theID", WithQueryFilterExpression("#name = :name", map[string]string{"#name": "name"}, map[string]any{":name": "theName"}))
+
+		assert.NoError(t, err)
+		assert.Equal(t, "#0 = :0", *actualInput.KeyConditionExpression)
+		assert.Equal(t, "#name = :name", *actualInput.FilterExpression)
+		assert.Equal(t, map[string]string{"#0": "id", "#name": "name"}, actualInput.ExpressionAttributeNames)
+		assert.Equal(t, map[string]types.AttributeValue{
+			":0":    &types.AttributeValueMemberS{Value: "theID"},
+			":name": &types.AttributeValueMemberS{Value: "theName"},
+		}, actualInput.ExpressionAttributeValues)
+	})
+
+	t.Run("returns_an_error_when_a_placeholder_is_already_used_by_the_key_condition", func(t *testing.T) {
+		fakeDB := &FakeDynamoDB{}
+		setFake(func(ctx context.Context) (DynamoDB, error) { return fakeDB, nil })
+		t.Cleanup(func() { setFake(nil) })
+
+		_, err := Query[TestUser](context.Background(), "aTable", "id", "anID", WithQueryFilterExpression("#0 = :name", map[string]string{"#0": "name"}, map[string]any{":name": "aName"}))
+
+		assert.EqualError(t, err, "dynamodbkit.Query table=aTable id=anID: error processing option: expression attribute name #0 is already used for id")
+	})
+
+	t.Run("returns_an_error_when_a_value_placeholder_is_already_used", func(t *testing.T) {
+		input := &dynamodb.QueryInput{ExpressionAttributeValues: map[string]types.AttributeValue{":0": &types.AttributeValueMemberS{Value: "aValue"}}}
+
+		err := WithQueryFilterExpression("#name = :0", map[string]string{"#name": "name"}, map[string]any{":0": "aName"})(input)
+
+		assert.EqualError(t, err, "expression attribute value :0 is already used")
+		assert.Nil(t, input.ExpressionAttributeNames)
+	})
+
+	t.Run("returns_an_error_for_an_empty_expression", func(t *testing.T) {
+		err := WithQueryFilterExpression("", nil, nil)(&dynamodb.QueryInput{})
+
+		assert.EqualError(t, err, "filter expression cannot be empty")
+	})
+
+	t.Run("returns_an_error_when_a_value_cannot_be_marshalled", func(t *testing.T) {
+		err := WithQueryFilterExpression("#a = :a", map[string]string{"#a": "a"}, map[string]any{":a": failingMarshaler{}})(&dynamodb.QueryInput{})
+
+		assert.ErrorContains(t, err, "error marshalling filter value :a")
+	})
+}
+
+func TestWithScanFilterExpression(t *testing.T) {
+	t.Run("filters_the_scan", func(t *testing.T) {
+		var actualInput *dynamodb.ScanInput
+		fakeDB := &FakeDynamoDB{
+			ScanFake: func(ctx context.Context, params *dynamodb.ScanInput, optFns ...func(*dynamodb.Options)) (*dynamodb.ScanOutput, error) {
+				actualInput = params
+				return &dynamodb.ScanOutput{Items: []map[string]types.AttributeValue{mustMarshalMap(t, TestUser{ID: "theID", Email: "theEmail"})}}, nil
+			},
+		}
+		setFake(func(ctx context.Context) (DynamoDB, error) { return fakeDB, nil })
+		t.Cleanup(func() { setFake(nil) })
+
+		result, err := Scan[TestUser](context.Background(), "aTable", WithScanFilterExpression("attribute_exists(#email)", map[string]string{"#email": "email"}, nil))
+
+		assert.NoError(t, err)
+		assert.Equal(t, []TestUser{{ID: "theID", Email: "theEmail"}}, result.Items)
+		assert.Equal(t, "attribute_exists(#email)", *actualInput.FilterExpression)
+		assert.Equal(t, map[string]string{"#email": "email"}, actualInput.ExpressionAttributeNames)
+		assert.Nil(t, actualInput.ExpressionAttributeValues)
+	})
+
+	t.Run("ands_the_filter_with_an_existing_one", func(t *testing.T) {
+		input := &dynamodb.ScanInput{}
+
+		err := WithScanFilterExpression("attribute_exists(#email)", map[string]string{"#email": "email"}, nil)(input)
+		assert.NoError(t, err)
+		err = WithScanFilterExpression("#name = :name", map[string]string{"#name": "name"}, map[string]any{":name": "aName"})(input)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "(attribute_exists(#email)) AND (#name = :name)", *input.FilterExpression)
+	})
+
+	t.Run("returns_an_error_for_an_empty_expression", func(t *testing.T) {
+		err := WithScanFilterExpression("", nil, nil)(&dynamodb.ScanInput{})
+
+		assert.EqualError(t, err, "filter expression cannot be empty")
+	})
+}
@@ -0,0 +1,93 @@
+package dynamodbkit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+
+	"github.com/half-ogre/go-kit/geokit"
+	"github.com/half-ogre/go-kit/kit"
+)
+
+// SortKeyFromPoint encodes p as a geohash with precision characters (1 to 12), so items near each other
+// share sort key prefixes. Append a suffix (e.g. "#" + an ID) to keep sort keys unique.
+func SortKeyFromPoint(p geokit.Point, precision int) (string, error) {
+	hash, err := geokit.EncodeGeohash(p, precision)
+	if err != nil {
+		return "", kit.WrapError(err, "error encoding sort key")
+	}
+	return hash, nil
+}
+
+// QueryBox returns the items in a partition whose location is inside box. The partition's sort key
+// (or, with WithQueryIndexName, the index's sort key) must start with a geohash from SortKeyFromPoint.
+// The box is covered by at most maxCells geohash prefixes, each queried with begins_with; fewer cells
+// mean fewer queries but more items read and filtered out.
+func QueryBox[TItem any](ctx context.Context, tableName string, partitionKey string, partitionKeyValue string, sortKey string, box geokit.Box, maxCells int, location func(TItem) geokit.Point, options ...QueryOption) (_ []TItem, err error) {
+	op := newOperation(ctx, "QueryBox", tableName)
+	op.setKey(partitionKey, map[string]types.AttributeValue{partitionKey: &types.AttributeValueMemberS{Value: partitionKeyValue}})
+	defer op.wrap(&err)
+
+	if ctx == nil {
+		return nil, errors.New("context cannot be nil")
+	}
+
+	if location == nil {
+		return nil, errors.New("location function cannot be nil")
+	}
+
+	prefixes, err := geokit.CoverBox(box, maxCells)
+	if err != nil {
+		return nil, kit.WrapError(err, "error covering box")
+	}
+
+	items := make([]TItem, 0)
+	for _, prefix := range prefixes {
+		queryAllOptions := make([]QueryAllOption, 0, len(options)+1)
+		for _, option := range options {
+			queryAllOptions = append(queryAllOptions, option)
+		}
+		queryAllOptions = append(queryAllOptions, WithQuerySortKeyBeginsWith(sortKey, prefix))
+
+		output, err := QueryAll[TItem](ctx, tableName, partitionKey, partitionKeyValue, queryAllOptions...)
+		if err != nil {
+			return nil, kit.WrapError(err, "error querying geohash prefix %s", prefix)
+		}
+
+		for _, item := range output.Items {
+			if box.Contains(location(item)) {
+				items = append(items, item)
+			}
+		}
+	}
+
+	return items, nil
+}
+
+// QueryRadius returns the items in a partition whose location is within radiusMeters of center, using
+// QueryBox on the circle's bounding box and then filtering by distance
+func QueryRadius[TItem any](ctx context.Context, tableName string, partitionKey string, partitionKeyValue string, sortKey string, center geokit.Point, radiusMeters float64, maxCells int, location func(TItem) geokit.Point, options ...QueryOption) (_ []TItem, err error) {
+	op := newOperation(ctx, "QueryRadius", tableName)
+	op.setKey(partitionKey, map[string]types.AttributeValue{partitionKey: &types.AttributeValueMemberS{Value: partitionKeyValue}})
+	defer op.wrap(&err)
+
+	if radiusMeters < 0 {
+		return nil, fmt.Errorf("radius cannot be negative, got %v", radiusMeters)
+	}
+
+	items, err := QueryBox(ctx, tableName, partitionKey, partitionKeyValue, sortKey, geokit.BoundingBox(center, radiusMeters), maxCells, location, options...)
+	if err != nil {
+		return nil, err
+	}
+
+	inRadius := make([]TItem, 0, len(items))
+	for _, item := range items {
+		if geokit.Distance(center, location(item)) <= radiusMeters {
+			inRadius = append(inRadius, item)
+		}
+	}
+
+	return inRadius, nil
+}
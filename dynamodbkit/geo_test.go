@@ -0,0 +1,119 @@
+package dynamodbkit
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/half-ogre/go-kit/geokit"
+)
+
+type testPlace struct {
+	CityID  string  `dynamodbav:"city_id"`
+	Geohash string  `dynamodbav:"geohash"`
+	Lat     float64 `dynamodbav:"lat"`
+	Lon     float64 `dynamodbav:"lon"`
+}
+
+func placeLocation(p testPlace) geokit.Point {
+	return geokit.Point{Lat: p.Lat, Lon: p.Lon}
+}
+
+func newTestPlace(t *testing.T, lat float64, lon float64) testPlace {
+	hash, err := SortKeyFromPoint(geokit.Point{Lat: lat, Lon: lon}, 9)
+	assert.NoError(t, err)
+	return testPlace{CityID: "theCity", Geohash: hash, Lat: lat, Lon: lon}
+}
+
+// setFakePlaces serves the places whose geohash begins with each query's prefix, recording the prefixes
+func setFakePlaces(t *testing.T, places []testPlace) *[]string {
+	prefixes := []string{}
+	fakeDB := &FakeDynamoDB{
+		QueryFake: func(ctx context.Context, params *dynamodb.QueryInput, optFns ...func(*dynamodb.Options)) (*dynamodb.QueryOutput, error) {
+			assert.Equal(t, "geohash", params.ExpressionAttributeNames["#sortKeyBeginsWith"])
+			prefix := params.ExpressionAttributeValues[":sortKeyBeginsWith"].(*types.AttributeValueMemberS).Value
+			prefixes = append(prefixes, prefix)
+			items := make([]map[string]types.AttributeValue, 0)
+			for _, place := range places {
+				if strings.HasPrefix(place.Geohash, prefix) {
+					items = append(items, mustMarshalMap(t, place))
+				}
+			}
+			return &dynamodb.QueryOutput{Items: items}, nil
+		},
+	}
+	setFake(func(ctx context.Context) (DynamoDB, error) { return fakeDB, nil })
+	t.Cleanup(func() { setFake(nil) })
+	return &prefixes
+}
+
+func TestSortKeyFromPoint(t *testing.T) {
+	t.Run("encodes_the_point_as_a_geohash", func(t *testing.T) {
+		result, err := SortKeyFromPoint(geokit.Point{Lat: 42.6, Lon: -5.6}, 5)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "ezs42", result)
+	})
+
+	t.Run("returns_an_error_for_an_invalid_precision", func(t *testing.T) {
+		_, err := SortKeyFromPoint(geokit.Point{Lat: 42.6, Lon: -5.6}, 0)
+
+		assert.ErrorContains(t, err, "geohash precision must be between 1 and 12, got 0")
+	})
+}
+
+func TestQueryBox(t *testing.T) {
+	t.Run("queries_each_covering_prefix_and_filters_to_the_box", func(t *testing.T) {
+		inside := newTestPlace(t, 48.8566, 2.3522)
+		nearby := newTestPlace(t, 48.8600, 2.3400)
+		outside := newTestPlace(t, 48.9500, 2.3522)
+		prefixes := setFakePlaces(t, []testPlace{inside, nearby, outside})
+		box := geokit.Box{MinLat: 48.85, MinLon: 2.33, MaxLat: 48.87, MaxLon: 2.36}
+
+		result, err := QueryBox(context.Background(), "aTable", "city_id", "theCity", "geohash", box, 9, placeLocation)
+
+		assert.NoError(t, err)
+		assert.ElementsMatch(t, []testPlace{inside, nearby}, result)
+		expected, err := geokit.CoverBox(box, 9)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, *prefixes)
+	})
+
+	t.Run("returns_an_error_when_location_is_nil", func(t *testing.T) {
+		result, err := QueryBox[testPlace](context.Background(), "aTable", "city_id", "theCity", "geohash", geokit.Box{}, 9, nil)
+
+		assert.Nil(t, result)
+		assert.ErrorContains(t, err, "location function cannot be nil")
+	})
+
+	t.Run("returns_an_error_for_an_invalid_box", func(t *testing.T) {
+		result, err := QueryBox(context.Background(), "aTable", "city_id", "theCity", "geohash", geokit.Box{MinLat: 10, MaxLat: 5}, 9, placeLocation)
+
+		assert.Nil(t, result)
+		assert.ErrorContains(t, err, "error covering box")
+	})
+}
+
+func TestQueryRadius(t *testing.T) {
+	t.Run("returns_the_items_within_the_radius", func(t *testing.T) {
+		center := geokit.Point{Lat: 48.8566, Lon: 2.3522}
+		near := newTestPlace(t, 48.8570, 2.3530)
+		inBoxCorner := newTestPlace(t, 48.8650, 2.3640)
+		setFakePlaces(t, []testPlace{near, inBoxCorner})
+
+		result, err := QueryRadius(context.Background(), "aTable", "city_id", "theCity", "geohash", center, 1_000, 9, placeLocation)
+
+		assert.NoError(t, err)
+		assert.Equal(t, []testPlace{near}, result)
+	})
+
+	t.Run("returns_an_error_for_a_negative_radius", func(t *testing.T) {
+		_, err := QueryRadius(context.Background(), "aTable", "city_id", "theCity", "geohash", geokit.Point{}, -1, 9, placeLocation)
+
+		assert.ErrorContains(t, err, "radius cannot be negative, got -1")
+	})
+}
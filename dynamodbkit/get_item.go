@@ -11,8 +11,11 @@ import (
 	"github.com/half-ogre/go-kit/kit"
 )
 
-func GetItem[TItem any, TPartitionKey string | int](ctx context.Context, tableName string, partitionKey string, partitionKeyValue TPartitionKey, options ...GetItemOption) (*TItem, error) {
-	db, err := newDynamoDB(ctx)
+func GetItem[TItem any, TPartitionKey string | int](ctx context.Context, tableName string, partitionKey string, partitionKeyValue TPartitionKey, options ...GetItemOption) (_ *TItem, err error) {
+	op := newOperation(ctx, "GetItem", tableName)
+	defer op.wrap(&err)
+
+	db, err := op.dynamoDB(ctx)
 	if err != nil {
 		return nil, kit.WrapError(err, "error creating DynamoDB client")
 	}
@@ -29,6 +32,7 @@ func GetItem[TItem any, TPartitionKey string | int](ctx context.Context, tableNa
 		},
 	}
 
+	op.setKey(partitionKey, getItemInput.Key)
 	originalTableNamePtr := getItemInput.TableName
 
 	for _, option := range options {
@@ -38,22 +42,24 @@ func GetItem[TItem any, TPartitionKey string | int](ctx context.Context, tableNa
 		}
 	}
 
-	// Apply global table name suffix if table name pointer wasn't changed by options
-	if getItemInput.TableName == originalTableNamePtr {
-		globalSuffix := getTableNameSuffix()
-		if globalSuffix != "" {
-			getItemInput.TableName = aws.String(fmt.Sprintf("%s%s", *getItemInput.TableName, globalSuffix))
-		}
-	}
+	// Apply the client's table name prefix, and its suffix if table name pointer wasn't changed by options
+	getItemInput.TableName = op.client.qualifyTableName(getItemInput.TableName, originalTableNamePtr)
+	op.table = *getItemInput.TableName
+
+	metrics := startOperation(op, "GetItem", getItemInput.TableName)
+	defer func() { metrics.finish(ctx, err) }()
+	getItemInput.ReturnConsumedCapacity = op.client.returnConsumedCapacity(metrics)
 
 	output, err := db.GetItem(ctx, getItemInput)
 	if err != nil {
-		return nil, kit.WrapError(err, "error getting item %s=%v from table %v", partitionKey, partitionKeyValue, *getItemInput.TableName)
+		return nil, kit.WrapError(err, "error getting item")
 	}
 
 	if output.Item == nil {
+		metrics.addResponse(0, output.ConsumedCapacity)
 		return nil, nil
 	}
+	metrics.addResponse(1, output.ConsumedCapacity)
 
 	var item TItem
 	err = attributevalue.UnmarshalMap(output.Item, &item)
@@ -19,7 +19,7 @@ func TestGetItem(t *testing.T) {
 		result, err := GetItem[TestUser](context.Background(), "aTable", "id", "aUserID")
 
 		assert.Nil(t, result)
-		assert.EqualError(t, err, "error creating DynamoDB client: the fake error")
+		assert.EqualError(t, err, "dynamodbkit.GetItem table=aTable: error creating DynamoDB client: the fake error")
 	})
 
 	t.Run("passes_the_table_name_to_get_item", func(t *testing.T) {
@@ -92,7 +92,7 @@ func TestGetItem(t *testing.T) {
 		result, err := GetItem[TestUser](context.Background(), "aTable", "id", "aUserID")
 
 		assert.Nil(t, result)
-		assert.EqualError(t, err, "error getting item id=aUserID from table aTable: the fake error")
+		assert.EqualError(t, err, "dynamodbkit.GetItem table=aTable id=aUserID: error getting item: the fake error")
 	})
 
 	t.Run("returns_nil_when_item_not_found", func(t *testing.T) {
@@ -147,7 +147,7 @@ func TestGetItem(t *testing.T) {
 		result, err := GetItem[TestUser](context.Background(), "aTable", "id", "aUserID")
 
 		assert.Nil(t, result)
-		assert.EqualError(t, err, "failed to unmarshal item: unmarshal failed, cannot unmarshal list into Go value type string")
+		assert.EqualError(t, err, "dynamodbkit.GetItem table=aTable id=aUserID: failed to unmarshal item: unmarshal failed, cannot unmarshal list into Go value type string")
 	})
 
 	t.Run("applies_get_item_options_correctly", func(t *testing.T) {
@@ -187,7 +187,7 @@ func TestGetItem(t *testing.T) {
 		result, err := GetItem[TestUser](context.Background(), "aTable", "id", "aUserID", failingOption)
 
 		assert.Nil(t, result)
-		assert.EqualError(t, err, "error processing option: option processing failed")
+		assert.EqualError(t, err, "dynamodbkit.GetItem table=aTable id=aUserID: error processing option: option processing failed")
 	})
 }
 
@@ -0,0 +1,132 @@
+package dynamodbkit
+
+import (
+	"context"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+// OperationMetrics describes one logical operation, such as a GetItem, a Query, or every page of a
+// QueryAll
+type OperationMetrics struct {
+	Operation        string
+	Table            string
+	Pages            int
+	Items            int
+	Duration         time.Duration
+	ConsumedCapacity float64
+	// IndexConsumedCapacity is the capacity each index consumed, by index name, when the return consumed
+	// capacity level is INDEXES
+	IndexConsumedCapacity map[string]float64
+	// Retries is how many calls the operation retried, as its RetryPolicy says
+	Retries int
+	Err     error
+}
+
+// MetricsHook receives OperationMetrics after each operation, e.g. to feed CloudWatch or Prometheus
+type MetricsHook interface {
+	RecordOperation(ctx context.Context, metrics OperationMetrics)
+}
+
+// UseInstrumentation sets a function that receives OperationMetrics after each GetItem, PutItem,
+// DeleteItem, UpdateItem, BatchPutItems, BatchDeleteItems, Query, Scan, QueryAll, ScanAll, QueryCount,
+// and ExecuteStatement, e.g. to record dashboard metrics. Paginated helpers report once for all their
+// pages. While it is set, operations ask DynamoDB for their consumed capacity; writes with UseAuditing
+// report none. Pass nil to turn it off.
+func UseInstrumentation(record func(ctx context.Context, metrics OperationMetrics)) {
+	updateDefaultClient(WithClientInstrumentation(record))
+}
+
+// UseMetricsHook sets hook to receive OperationMetrics, as UseInstrumentation does for a function; nil
+// turns it off
+func UseMetricsHook(hook MetricsHook) {
+	updateDefaultClient(WithClientMetricsHook(hook))
+}
+
+// UseReturnConsumedCapacity sets the consumed capacity every operation asks DynamoDB for, e.g. INDEXES
+// to have OperationMetrics break it down by index; the default is TOTAL while instrumentation is on and
+// NONE otherwise
+func UseReturnConsumedCapacity(level types.ReturnConsumedCapacity) {
+	updateDefaultClient(WithClientReturnConsumedCapacity(level))
+}
+
+// returnConsumedCapacity returns the consumed capacity level an operation asks DynamoDB for
+func (c *Client) returnConsumedCapacity(metrics *operationMetrics) types.ReturnConsumedCapacity {
+	if c.consumedCapacity != "" {
+		return c.consumedCapacity
+	}
+	if metrics != nil {
+		return types.ReturnConsumedCapacityTotal
+	}
+	return ""
+}
+
+// operationMetrics accumulates pages for an operation; it is nil when instrumentation is off
+type operationMetrics struct {
+	OperationMetrics
+	record func(ctx context.Context, metrics OperationMetrics)
+	start  time.Time
+	op     *operation
+}
+
+func startOperation(op *operation, operation string, tableName *string) *operationMetrics {
+	record := op.client.instrumentation
+	if record == nil {
+		return nil
+	}
+
+	return &operationMetrics{
+		OperationMetrics: OperationMetrics{Operation: operation, Table: aws.ToString(tableName)},
+		record:           record,
+		start:            time.Now(),
+		op:               op,
+	}
+}
+
+func addPage[TItem any](m *operationMetrics, p *page[TItem]) {
+	if p == nil {
+		return
+	}
+	m.addResponse(len(p.items), p.consumedCapacity)
+}
+
+// addResponse adds a response from DynamoDB with items read or written and the capacity consumed, in
+// total and by index
+func (m *operationMetrics) addResponse(items int, capacities ...*types.ConsumedCapacity) {
+	if m == nil {
+		return
+	}
+	m.Pages++
+	m.Items += items
+	for _, capacity := range capacities {
+		if capacity == nil {
+			continue
+		}
+		m.ConsumedCapacity += aws.ToFloat64(capacity.CapacityUnits)
+		for name, index := range capacity.GlobalSecondaryIndexes {
+			m.addIndexConsumedCapacity(name, index)
+		}
+		for name, index := range capacity.LocalSecondaryIndexes {
+			m.addIndexConsumedCapacity(name, index)
+		}
+	}
+}
+
+func (m *operationMetrics) addIndexConsumedCapacity(name string, capacity types.Capacity) {
+	if m.IndexConsumedCapacity == nil {
+		m.IndexConsumedCapacity = map[string]float64{}
+	}
+	m.IndexConsumedCapacity[name] += aws.ToFloat64(capacity.CapacityUnits)
+}
+
+func (m *operationMetrics) finish(ctx context.Context, err error) {
+	if m == nil {
+		return
+	}
+	m.Duration = time.Since(m.start)
+	m.Retries = int(m.op.retries.Load())
+	m.Err = err
+	m.record(ctx, m.OperationMetrics)
+}
@@ -0,0 +1,196 @@
+package dynamodbkit
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func useTestInstrumentation(t *testing.T) *[]OperationMetrics {
+	records := []OperationMetrics{}
+	UseInstrumentation(func(ctx context.Context, metrics OperationMetrics) {
+		records = append(records, metrics)
+	})
+	t.Cleanup(func() { UseInstrumentation(nil) })
+	return &records
+}
+
+func TestUseInstrumentation(t *testing.T) {
+	t.Run("records_one_operation_for_every_page_of_query_all", func(t *testing.T) {
+		records := useTestInstrumentation(t)
+		fakeDB := &FakeDynamoDB{
+			QueryFake: func(ctx context.Context, params *dynamodb.QueryInput, optFns ...func(*dynamodb.Options)) (*dynamodb.QueryOutput, error) {
+				assert.Equal(t, types.ReturnConsumedCapacityTotal, params.ReturnConsumedCapacity)
+				output := &dynamodb.QueryOutput{
+					Items:            []map[string]types.AttributeValue{mustMarshalMap(t, TestUser{ID: "theID"}), mustMarshalMap(t, TestUser{ID: "theID"})},
+					ConsumedCapacity: &types.ConsumedCapacity{CapacityUnits: aws.Float64(1.5)},
+				}
+				if params.ExclusiveStartKey == nil {
+					output.LastEvaluatedKey = map[string]types.AttributeValue{"id": &types.AttributeValueMemberS{Value: "theID"}}
+				}
+				return output, nil
+			},
+		}
+		setFake(func(ctx context.Context) (DynamoDB, error) { return fakeDB, nil })
+		t.Cleanup(func() { setFake(nil) })
+
+		_, err := QueryAll[TestUser](context.Background(), "theTableName", "id", "theID")
+
+		assert.NoError(t, err)
+		assert.Len(t, *records, 1)
+		record := (*records)[0]
+		assert.Equal(t, "QueryAll", record.Operation)
+		assert.Equal(t, "theTableName", record.Table)
+		assert.Equal(t, 2, record.Pages)
+		assert.Equal(t, 4, record.Items)
+		assert.Equal(t, 3.0, record.ConsumedCapacity)
+		assert.NoError(t, record.Err)
+	})
+
+	t.Run("records_a_single_scan", func(t *testing.T) {
+		records := useTestInstrumentation(t)
+		fakeDB := &FakeDynamoDB{
+			ScanFake: func(ctx context.Context, params *dynamodb.ScanInput, optFns ...func(*dynamodb.Options)) (*dynamodb.ScanOutput, error) {
+				return &dynamodb.ScanOutput{Items: []map[string]types.AttributeValue{mustMarshalMap(t, TestUser{ID: "theID"})}}, nil
+			},
+		}
+		setFake(func(ctx context.Context) (DynamoDB, error) { return fakeDB, nil })
+		t.Cleanup(func() { setFake(nil) })
+
+		_, err := Scan[TestUser](context.Background(), "theTableName")
+
+		assert.NoError(t, err)
+		assert.Equal(t, []OperationMetrics{{Operation: "Scan", Table: "theTableName", Pages: 1, Items: 1, Duration: (*records)[0].Duration}}, *records)
+	})
+
+	t.Run("records_the_error_of_a_failed_page", func(t *testing.T) {
+		records := useTestInstrumentation(t)
+		fakeDB := &FakeDynamoDB{
+			ScanFake: func(ctx context.Context, params *dynamodb.ScanInput, optFns ...func(*dynamodb.Options)) (*dynamodb.ScanOutput, error) {
+				return nil, errors.New("the scan error")
+			},
+		}
+		setFake(func(ctx context.Context) (DynamoDB, error) { return fakeDB, nil })
+		t.Cleanup(func() { setFake(nil) })
+
+		_, _ = ScanAll[TestUser](context.Background(), "theTableName")
+
+		assert.Len(t, *records, 1)
+		assert.Equal(t, 0, (*records)[0].Pages)
+		assert.EqualError(t, (*records)[0].Err, "error scanning: the scan error")
+	})
+
+	t.Run("does_not_request_consumed_capacity_when_off", func(t *testing.T) {
+		fakeDB := &FakeDynamoDB{
+			QueryFake: func(ctx context.Context, params *dynamodb.QueryInput, optFns ...func(*dynamodb.Options)) (*dynamodb.QueryOutput, error) {
+				assert.Equal(t, types.ReturnConsumedCapacity(""), params.ReturnConsumedCapacity)
+				return &dynamodb.QueryOutput{}, nil
+			},
+		}
+		setFake(func(ctx context.Context) (DynamoDB, error) { return fakeDB, nil })
+		t.Cleanup(func() { setFake(nil) })
+
+		_, err := Query[TestUser](context.Background(), "theTableName", "id", "theID")
+
+		assert.NoError(t, err)
+	})
+	t.Run("records_get_item_and_put_item", func(t *testing.T) {
+		records := useTestInstrumentation(t)
+		fakeDB := &FakeDynamoDB{
+			GetItemFake: func(ctx context.Context, params *dynamodb.GetItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.GetItemOutput, error) {
+				assert.Equal(t, types.ReturnConsumedCapacityTotal, params.ReturnConsumedCapacity)
+				return &dynamodb.GetItemOutput{
+					Item:             mustMarshalMap(t, TestUser{ID: "theID"}),
+					ConsumedCapacity: &types.ConsumedCapacity{CapacityUnits: aws.Float64(0.5)},
+				}, nil
+			},
+			PutItemFake: func(ctx context.Context, params *dynamodb.PutItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.PutItemOutput, error) {
+				assert.Equal(t, types.ReturnConsumedCapacityTotal, params.ReturnConsumedCapacity)
+				return &dynamodb.PutItemOutput{ConsumedCapacity: &types.ConsumedCapacity{CapacityUnits: aws.Float64(1)}}, nil
+			},
+		}
+		setFake(func(ctx context.Context) (DynamoDB, error) { return fakeDB, nil })
+		t.Cleanup(func() { setFake(nil) })
+
+		_, err := GetItem[TestUser](context.Background(), "theTableName", "id", "theID")
+		assert.NoError(t, err)
+		err = PutItem(context.Background(), "theTableName", TestUser{ID: "theID"})
+		assert.NoError(t, err)
+
+		assert.Equal(t, []OperationMetrics{
+			{Operation: "GetItem", Table: "theTableName", Pages: 1, Items: 1, ConsumedCapacity: 0.5, Duration: (*records)[0].Duration},
+			{Operation: "PutItem", Table: "theTableName", Pages: 1, Items: 1, ConsumedCapacity: 1, Duration: (*records)[1].Duration},
+		}, *records)
+	})
+}
+
+type recordingMetricsHook struct {
+	records []OperationMetrics
+}
+
+func (h *recordingMetricsHook) RecordOperation(ctx context.Context, metrics OperationMetrics) {
+	h.records = append(h.records, metrics)
+}
+
+func TestWithClientMetricsHook(t *testing.T) {
+	t.Run("records_the_consumed_capacity_of_each_index", func(t *testing.T) {
+		hook := &recordingMetricsHook{}
+		fakeDB := &FakeDynamoDB{
+			QueryFake: func(ctx context.Context, params *dynamodb.QueryInput, optFns ...func(*dynamodb.Options)) (*dynamodb.QueryOutput, error) {
+				assert.Equal(t, types.ReturnConsumedCapacityIndexes, params.ReturnConsumedCapacity)
+				return &dynamodb.QueryOutput{
+					ConsumedCapacity: &types.ConsumedCapacity{
+						CapacityUnits:          aws.Float64(2),
+						GlobalSecondaryIndexes: map[string]types.Capacity{"theIndex": {CapacityUnits: aws.Float64(1.5)}},
+					},
+				}, nil
+			},
+		}
+		client := NewClient(fakeDB, WithClientMetricsHook(hook), WithClientReturnConsumedCapacity(types.ReturnConsumedCapacityIndexes))
+
+		_, err := Query[TestUser](WithClient(context.Background(), client), "theTableName", "id", "theID")
+
+		assert.NoError(t, err)
+		assert.Len(t, hook.records, 1)
+		assert.Equal(t, 2.0, hook.records[0].ConsumedCapacity)
+		assert.Equal(t, map[string]float64{"theIndex": 1.5}, hook.records[0].IndexConsumedCapacity)
+	})
+
+	t.Run("records_the_error_of_a_failed_delete_item", func(t *testing.T) {
+		hook := &recordingMetricsHook{}
+		fakeDB := &FakeDynamoDB{
+			DeleteItemFake: func(ctx context.Context, params *dynamodb.DeleteItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.DeleteItemOutput, error) {
+				return nil, errors.New("the delete error")
+			},
+		}
+		client := NewClient(fakeDB, WithClientMetricsHook(hook))
+
+		_ = DeleteItem(WithClient(context.Background(), client), "theTableName", "id", "theID")
+
+		assert.Len(t, hook.records, 1)
+		assert.Equal(t, "DeleteItem", hook.records[0].Operation)
+		assert.Equal(t, 0, hook.records[0].Pages)
+		assert.EqualError(t, hook.records[0].Err, "error deleting item: the delete error")
+	})
+}
+
+func TestWithClientReturnConsumedCapacity(t *testing.T) {
+	t.Run("asks_for_the_level_without_a_metrics_hook", func(t *testing.T) {
+		fakeDB := &FakeDynamoDB{
+			GetItemFake: func(ctx context.Context, params *dynamodb.GetItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.GetItemOutput, error) {
+				assert.Equal(t, types.ReturnConsumedCapacityIndexes, params.ReturnConsumedCapacity)
+				return &dynamodb.GetItemOutput{}, nil
+			},
+		}
+		client := NewClient(fakeDB, WithClientReturnConsumedCapacity(types.ReturnConsumedCapacityIndexes))
+
+		_, err := GetItem[TestUser](WithClient(context.Background(), client), "theTableName", "id", "theID")
+
+		assert.NoError(t, err)
+	})
+}
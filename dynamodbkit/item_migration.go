@@ -0,0 +1,411 @@
+package dynamodbkit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/half-ogre/go-kit/kit"
+	"github.com/half-ogre/go-kit/logkit/logfields"
+)
+
+// ItemTransform changes one item's shape during an item migration. It returns the new item and whether
+// it changed; unchanged items aren't written. It must not change the item's key or version attribute,
+// and it may run more than once for an item, so it must be idempotent.
+type ItemTransform func(ctx context.Context, item map[string]types.AttributeValue) (map[string]types.AttributeValue, bool, error)
+
+// ItemMigrationResult counts the items an item migration read and changed, including in earlier runs
+// it resumed from
+type ItemMigrationResult struct {
+	Scanned   int
+	Updated   int
+	Conflicts int
+}
+
+// ItemMigrationOption configures RunItemMigration
+type ItemMigrationOption func(*itemMigrationConfig)
+
+type itemMigrationConfig struct {
+	segments         int
+	pageSize         int32
+	versionAttribute string
+	maxConflicts     int
+	dryRun           bool
+	clock            kit.ClockInterface
+}
+
+// WithItemMigrationSegments sets how many segments the table is scanned in, in parallel; the default
+// is 4. A resumed migration must use the same number of segments as the run it resumes.
+func WithItemMigrationSegments(segments int) ItemMigrationOption {
+	return func(c *itemMigrationConfig) {
+		c.segments = segments
+	}
+}
+
+// WithItemMigrationPageSize sets the most items read per scan page, and so between checkpoints
+func WithItemMigrationPageSize(pageSize int32) ItemMigrationOption {
+	return func(c *itemMigrationConfig) {
+		c.pageSize = pageSize
+	}
+}
+
+// WithItemMigrationVersionAttribute sets the numeric attribute used for optimistic locking; the default
+// is "version". Each write increments it, so other writers should check it too.
+func WithItemMigrationVersionAttribute(name string) ItemMigrationOption {
+	return func(c *itemMigrationConfig) {
+		c.versionAttribute = name
+	}
+}
+
+// WithItemMigrationMaxConflicts sets how many times an item changed by another writer is read and
+// transformed again before the migration fails; the default is 3
+func WithItemMigrationMaxConflicts(maxConflicts int) ItemMigrationOption {
+	return func(c *itemMigrationConfig) {
+		c.maxConflicts = maxConflicts
+	}
+}
+
+// WithItemMigrationDryRun transforms and counts items without writing them or checkpoints
+func WithItemMigrationDryRun() ItemMigrationOption {
+	return func(c *itemMigrationConfig) {
+		c.dryRun = true
+	}
+}
+
+// WithItemMigrationClock sets the clock used to timestamp checkpoints
+func WithItemMigrationClock(clock kit.ClockInterface) ItemMigrationOption {
+	return func(c *itemMigrationConfig) {
+		c.clock = clock
+	}
+}
+
+// itemMigrationCheckpoint is one segment's progress, stored in the checkpoint table
+type itemMigrationCheckpoint struct {
+	ID               string                          `dynamodbav:"id"`
+	Migration        string                          `dynamodbav:"migration"`
+	Segment          int                             `dynamodbav:"segment"`
+	TotalSegments    int                             `dynamodbav:"total_segments"`
+	LastEvaluatedKey map[string]types.AttributeValue `dynamodbav:"-"`
+	Done             bool                            `dynamodbav:"done"`
+	Scanned          int                             `dynamodbav:"scanned"`
+	Updated          int                             `dynamodbav:"updated"`
+	Conflicts        int                             `dynamodbav:"conflicts"`
+	UpdatedAt        string                          `dynamodbav:"updated_at"`
+}
+
+// RunItemMigration applies transform to every item in tableName, the DynamoDB analogue of a pgkit
+// migration for changing item shapes. The table is scanned in parallel segments, and each changed item
+// is written back only if its version attribute hasn't changed since it was read; if it has, the item
+// is read and transformed again. Each segment's progress is saved after every page in
+// checkpointTableName, whose partition key is "id" (string), so a migration that fails or is stopped
+// resumes where it left off when run again with the same name, and a finished migration does nothing.
+func RunItemMigration(ctx context.Context, tableName string, checkpointTableName string, name string, transform ItemTransform, options ...ItemMigrationOption) (_ *ItemMigrationResult, err error) {
+	op := newOperation(ctx, "RunItemMigration", tableName)
+	defer op.wrap(&err)
+
+	if ctx == nil {
+		return nil, errors.New("context cannot be nil")
+	}
+	if tableName == "" || checkpointTableName == "" {
+		return nil, errors.New("table name and checkpoint table name cannot be empty")
+	}
+	if name == "" {
+		return nil, errors.New("migration name cannot be empty")
+	}
+	if transform == nil {
+		return nil, errors.New("transform cannot be nil")
+	}
+
+	config := &itemMigrationConfig{
+		segments:         4,
+		versionAttribute: "version",
+		maxConflicts:     3,
+		clock:            kit.NewClock(),
+	}
+	for _, option := range options {
+		option(config)
+	}
+	if config.segments < 1 {
+		return nil, fmt.Errorf("segments must be at least 1, got %d", config.segments)
+	}
+
+	db, err := op.dynamoDB(ctx)
+	if err != nil {
+		return nil, kit.WrapError(err, "error creating DynamoDB client")
+	}
+
+	migration := &itemMigration{
+		db:                  db,
+		tableName:           op.client.TableName(tableName),
+		checkpointTableName: op.client.TableName(checkpointTableName),
+		name:                name,
+		transform:           transform,
+		config:              config,
+	}
+
+	migration.keyNames, err = describeKeyNames(ctx, db, migration.tableName)
+	if err != nil {
+		return nil, err
+	}
+
+	logger.InfoContext(ctx, "running item migration", logfields.Migration(name), logfields.Table(migration.tableName), "segments", config.segments, "dry_run", config.dryRun)
+
+	results := make([]ItemMigrationResult, config.segments)
+	errs := make([]error, config.segments)
+	var wg sync.WaitGroup
+	for segment := range config.segments {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[segment], errs[segment] = migration.runSegment(ctx, segment)
+		}()
+	}
+	wg.Wait()
+
+	result := &ItemMigrationResult{}
+	for _, segmentResult := range results {
+		result.Scanned += segmentResult.Scanned
+		result.Updated += segmentResult.Updated
+		result.Conflicts += segmentResult.Conflicts
+	}
+
+	if err := errors.Join(errs...); err != nil {
+		return result, kit.WrapError(err, "error running item migration %s", name)
+	}
+
+	logger.InfoContext(ctx, "ran item migration", logfields.Migration(name), "scanned", result.Scanned, "updated", result.Updated, "conflicts", result.Conflicts)
+	return result, nil
+}
+
+type itemMigration struct {
+	db                  DynamoDB
+	tableName           string
+	checkpointTableName string
+	name                string
+	transform           ItemTransform
+	config              *itemMigrationConfig
+	keyNames            []string
+}
+
+func (m *itemMigration) runSegment(ctx context.Context, segment int) (ItemMigrationResult, error) {
+	checkpoint, err := m.loadCheckpoint(ctx, segment)
+	if err != nil {
+		return ItemMigrationResult{}, err
+	}
+	result := ItemMigrationResult{Scanned: checkpoint.Scanned, Updated: checkpoint.Updated, Conflicts: checkpoint.Conflicts}
+	if checkpoint.Done {
+		return result, nil
+	}
+
+	for {
+		if err := ctx.Err(); err != nil {
+			return result, err
+		}
+
+		scanInput := &dynamodb.ScanInput{
+			TableName:         aws.String(m.tableName),
+			Segment:           aws.Int32(int32(segment)),
+			TotalSegments:     aws.Int32(int32(m.config.segments)),
+			ExclusiveStartKey: checkpoint.LastEvaluatedKey,
+			ConsistentRead:    aws.Bool(true),
+		}
+		if m.config.pageSize > 0 {
+			scanInput.Limit = aws.Int32(m.config.pageSize)
+		}
+
+		output, err := m.db.Scan(ctx, scanInput)
+		if err != nil {
+			return result, kit.WrapError(err, "error scanning segment %d", segment)
+		}
+
+		for _, item := range output.Items {
+			result.Scanned++
+			updated, conflicts, err := m.migrateItem(ctx, item)
+			result.Conflicts += conflicts
+			if err != nil {
+				return result, kit.WrapError(err, "error migrating item in segment %d", segment)
+			}
+			if updated {
+				result.Updated++
+			}
+		}
+
+		checkpoint.LastEvaluatedKey = output.LastEvaluatedKey
+		checkpoint.Done = len(output.LastEvaluatedKey) == 0
+		checkpoint.Scanned, checkpoint.Updated, checkpoint.Conflicts = result.Scanned, result.Updated, result.Conflicts
+		if err := m.saveCheckpoint(ctx, checkpoint); err != nil {
+			return result, err
+		}
+
+		if checkpoint.Done {
+			return result, nil
+		}
+	}
+}
+
+// migrateItem transforms item and writes it back, reading it again after each version conflict
+func (m *itemMigration) migrateItem(ctx context.Context, item map[string]types.AttributeValue) (bool, int, error) {
+	conflicts := 0
+	for {
+		migrated, changed, err := m.transform(ctx, item)
+		if err != nil {
+			return false, conflicts, kit.WrapError(err, "error transforming item")
+		}
+		if !changed {
+			return false, conflicts, nil
+		}
+		for _, keyName := range m.keyNames {
+			if !attributeValuesEqual(item[keyName], migrated[keyName]) {
+				return false, conflicts, fmt.Errorf("transform changed key attribute %s", keyName)
+			}
+		}
+		if m.config.dryRun {
+			return true, conflicts, nil
+		}
+
+		err = m.putItem(ctx, item, migrated)
+		var conditionErr *types.ConditionalCheckFailedException
+		if !errors.As(err, &conditionErr) {
+			return err == nil, conflicts, err
+		}
+
+		conflicts++
+		if conflicts > m.config.maxConflicts {
+			return false, conflicts, fmt.Errorf("item changed by another writer %d times", conflicts)
+		}
+
+		key := make(map[string]types.AttributeValue, len(m.keyNames))
+		for _, keyName := range m.keyNames {
+			key[keyName] = item[keyName]
+		}
+		output, err := m.db.GetItem(ctx, &dynamodb.GetItemInput{TableName: aws.String(m.tableName), Key: key, ConsistentRead: aws.Bool(true)})
+		if err != nil {
+			return false, conflicts, kit.WrapError(err, "error reading changed item")
+		}
+		if output.Item == nil {
+			// Deleted since it was scanned
+			return false, conflicts, nil
+		}
+		item = output.Item
+	}
+}
+
+// putItem writes migrated with the next version, if the stored item still exists at the version read
+func (m *itemMigration) putItem(ctx context.Context, read map[string]types.AttributeValue, migrated map[string]types.AttributeValue) error {
+	version := 0
+	if versionValue, ok := read[m.config.versionAttribute].(*types.AttributeValueMemberN); ok {
+		parsed, err := strconv.Atoi(versionValue.Value)
+		if err != nil {
+			return kit.WrapError(err, "error parsing version attribute %s", m.config.versionAttribute)
+		}
+		version = parsed
+	}
+
+	item := make(map[string]types.AttributeValue, len(migrated)+1)
+	for name, value := range migrated {
+		item[name] = value
+	}
+	item[m.config.versionAttribute] = &types.AttributeValueMemberN{Value: strconv.Itoa(version + 1)}
+
+	input := &dynamodb.PutItemInput{
+		TableName: aws.String(m.tableName),
+		Item:      item,
+		ExpressionAttributeNames: map[string]string{
+			"#key":     m.keyNames[0],
+			"#version": m.config.versionAttribute,
+		},
+	}
+	if version == 0 {
+		input.ConditionExpression = aws.String("attribute_exists(#key) AND attribute_not_exists(#version)")
+	} else {
+		input.ConditionExpression = aws.String("#version = :version")
+		delete(input.ExpressionAttributeNames, "#key")
+		input.ExpressionAttributeValues = map[string]types.AttributeValue{
+			":version": &types.AttributeValueMemberN{Value: strconv.Itoa(version)},
+		}
+	}
+
+	_, err := m.db.PutItem(ctx, input)
+	return err
+}
+
+func (m *itemMigration) checkpointID(segment int) string {
+	return fmt.Sprintf("%s#%d", m.name, segment)
+}
+
+func (m *itemMigration) loadCheckpoint(ctx context.Context, segment int) (*itemMigrationCheckpoint, error) {
+	checkpoint := &itemMigrationCheckpoint{
+		ID:            m.checkpointID(segment),
+		Migration:     m.name,
+		Segment:       segment,
+		TotalSegments: m.config.segments,
+	}
+
+	output, err := m.db.GetItem(ctx, &dynamodb.GetItemInput{
+		TableName:      aws.String(m.checkpointTableName),
+		Key:            map[string]types.AttributeValue{"id": &types.AttributeValueMemberS{Value: checkpoint.ID}},
+		ConsistentRead: aws.Bool(true),
+	})
+	if err != nil {
+		return nil, kit.WrapError(err, "error reading checkpoint %s", checkpoint.ID)
+	}
+	if output.Item == nil {
+		return checkpoint, nil
+	}
+
+	if err := attributevalue.UnmarshalMap(output.Item, checkpoint); err != nil {
+		return nil, kit.WrapError(err, "error unmarshaling checkpoint %s", checkpoint.ID)
+	}
+	// The key is stored as is; attributevalue can't unmarshal into AttributeValues
+	if lastEvaluatedKey, ok := output.Item["last_evaluated_key"].(*types.AttributeValueMemberM); ok {
+		checkpoint.LastEvaluatedKey = lastEvaluatedKey.Value
+	}
+	if checkpoint.TotalSegments != m.config.segments {
+		return nil, fmt.Errorf("migration %s was started with %d segments, not %d", m.name, checkpoint.TotalSegments, m.config.segments)
+	}
+
+	return checkpoint, nil
+}
+
+func (m *itemMigration) saveCheckpoint(ctx context.Context, checkpoint *itemMigrationCheckpoint) error {
+	if m.config.dryRun {
+		return nil
+	}
+
+	checkpoint.UpdatedAt = m.config.clock.Now().UTC().Format(time.RFC3339)
+	av, err := attributevalue.MarshalMap(checkpoint)
+	if err != nil {
+		return kit.WrapError(err, "error marshaling checkpoint %s", checkpoint.ID)
+	}
+	if len(checkpoint.LastEvaluatedKey) > 0 {
+		av["last_evaluated_key"] = &types.AttributeValueMemberM{Value: checkpoint.LastEvaluatedKey}
+	}
+
+	_, err = m.db.PutItem(ctx, &dynamodb.PutItemInput{TableName: aws.String(m.checkpointTableName), Item: av})
+	if err != nil {
+		return kit.WrapError(err, "error saving checkpoint %s", checkpoint.ID)
+	}
+	return nil
+}
+
+func attributeValuesEqual(a types.AttributeValue, b types.AttributeValue) bool {
+	switch a := a.(type) {
+	case *types.AttributeValueMemberS:
+		b, ok := b.(*types.AttributeValueMemberS)
+		return ok && a.Value == b.Value
+	case *types.AttributeValueMemberN:
+		b, ok := b.(*types.AttributeValueMemberN)
+		return ok && a.Value == b.Value
+	case *types.AttributeValueMemberB:
+		b, ok := b.(*types.AttributeValueMemberB)
+		return ok && string(a.Value) == string(b.Value)
+	}
+	return false
+}
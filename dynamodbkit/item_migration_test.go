@@ -0,0 +1,288 @@
+package dynamodbkit
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeMigrationTables holds a table keyed by "id" and a checkpoint table, serving Scan segments one
+// item per page so checkpoints are saved between items
+type fakeMigrationTables struct {
+	mu          sync.Mutex
+	items       map[string]map[string]types.AttributeValue
+	checkpoints map[string]map[string]types.AttributeValue
+	beforePut   func(id string)
+	scanErr     error
+}
+
+func newFakeMigrationTables(t *testing.T, ids ...string) *fakeMigrationTables {
+	tables := &fakeMigrationTables{
+		items:       map[string]map[string]types.AttributeValue{},
+		checkpoints: map[string]map[string]types.AttributeValue{},
+	}
+	for _, id := range ids {
+		tables.items[id] = map[string]types.AttributeValue{
+			"id":   &types.AttributeValueMemberS{Value: id},
+			"name": &types.AttributeValueMemberS{Value: "aName"},
+		}
+	}
+
+	fakeDB := &FakeDynamoDB{
+		DescribeTableFake: func(ctx context.Context, params *dynamodb.DescribeTableInput, optFns ...func(*dynamodb.Options)) (*dynamodb.DescribeTableOutput, error) {
+			return &dynamodb.DescribeTableOutput{Table: &types.TableDescription{
+				KeySchema: []types.KeySchemaElement{{AttributeName: aws.String("id"), KeyType: types.KeyTypeHash}},
+			}}, nil
+		},
+		ScanFake: func(ctx context.Context, params *dynamodb.ScanInput, optFns ...func(*dynamodb.Options)) (*dynamodb.ScanOutput, error) {
+			tables.mu.Lock()
+			defer tables.mu.Unlock()
+			if tables.scanErr != nil {
+				return nil, tables.scanErr
+			}
+			var ids []string
+			for id := range tables.items {
+				if int32(len(id))%*params.TotalSegments == *params.Segment {
+					ids = append(ids, id)
+				}
+			}
+			sort.Strings(ids)
+			start := ""
+			if params.ExclusiveStartKey != nil {
+				start = params.ExclusiveStartKey["id"].(*types.AttributeValueMemberS).Value
+			}
+			for i, id := range ids {
+				if id <= start {
+					continue
+				}
+				output := &dynamodb.ScanOutput{Items: []map[string]types.AttributeValue{tables.items[id]}}
+				if i < len(ids)-1 {
+					output.LastEvaluatedKey = map[string]types.AttributeValue{"id": &types.AttributeValueMemberS{Value: id}}
+				}
+				return output, nil
+			}
+			return &dynamodb.ScanOutput{}, nil
+		},
+		GetItemFake: func(ctx context.Context, params *dynamodb.GetItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.GetItemOutput, error) {
+			tables.mu.Lock()
+			defer tables.mu.Unlock()
+			id := params.Key["id"].(*types.AttributeValueMemberS).Value
+			if *params.TableName == "theCheckpoints" {
+				return &dynamodb.GetItemOutput{Item: tables.checkpoints[id]}, nil
+			}
+			return &dynamodb.GetItemOutput{Item: tables.items[id]}, nil
+		},
+		PutItemFake: func(ctx context.Context, params *dynamodb.PutItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.PutItemOutput, error) {
+			id := params.Item["id"].(*types.AttributeValueMemberS).Value
+			if *params.TableName == "theCheckpoints" {
+				tables.mu.Lock()
+				defer tables.mu.Unlock()
+				tables.checkpoints[id] = params.Item
+				return &dynamodb.PutItemOutput{}, nil
+			}
+			if tables.beforePut != nil {
+				tables.beforePut(id)
+			}
+			tables.mu.Lock()
+			defer tables.mu.Unlock()
+			existing, exists := tables.items[id]
+			switch *params.ConditionExpression {
+			case "attribute_exists(#key) AND attribute_not_exists(#version)":
+				if _, hasVersion := existing["version"]; !exists || hasVersion {
+					return nil, &types.ConditionalCheckFailedException{}
+				}
+			case "#version = :version":
+				if !exists || !attributeValuesEqual(existing["version"], params.ExpressionAttributeValues[":version"]) {
+					return nil, &types.ConditionalCheckFailedException{}
+				}
+			}
+			tables.items[id] = params.Item
+			return &dynamodb.PutItemOutput{}, nil
+		},
+	}
+	setFake(func(ctx context.Context) (DynamoDB, error) { return fakeDB, nil })
+	t.Cleanup(func() { setFake(nil) })
+	return tables
+}
+
+// renameToDisplayName moves "name" to "display_name"
+func renameToDisplayName(ctx context.Context, item map[string]types.AttributeValue) (map[string]types.AttributeValue, bool, error) {
+	name, ok := item["name"]
+	if !ok {
+		return item, false, nil
+	}
+	migrated := map[string]types.AttributeValue{}
+	for key, value := range item {
+		migrated[key] = value
+	}
+	delete(migrated, "name")
+	migrated["display_name"] = name
+	return migrated, true, nil
+}
+
+func TestRunItemMigration(t *testing.T) {
+	t.Run("transforms_every_item_and_sets_its_version", func(t *testing.T) {
+		tables := newFakeMigrationTables(t, "a", "bb", "cc", "ddd")
+
+		result, err := RunItemMigration(context.Background(), "aTable", "theCheckpoints", "theMigration", renameToDisplayName, WithItemMigrationSegments(2))
+
+		assert.NoError(t, err)
+		assert.Equal(t, &ItemMigrationResult{Scanned: 4, Updated: 4}, result)
+		for _, item := range tables.items {
+			assert.Equal(t, &types.AttributeValueMemberS{Value: "aName"}, item["display_name"])
+			assert.NotContains(t, item, "name")
+			assert.Equal(t, &types.AttributeValueMemberN{Value: "1"}, item["version"])
+		}
+		assert.Len(t, tables.checkpoints, 2)
+		assert.Equal(t, &types.AttributeValueMemberBOOL{Value: true}, tables.checkpoints["theMigration#0"]["done"])
+	})
+
+	t.Run("increments_an_existing_version", func(t *testing.T) {
+		tables := newFakeMigrationTables(t, "a")
+		tables.items["a"]["version"] = &types.AttributeValueMemberN{Value: "7"}
+
+		_, err := RunItemMigration(context.Background(), "aTable", "theCheckpoints", "theMigration", renameToDisplayName, WithItemMigrationSegments(1))
+
+		assert.NoError(t, err)
+		assert.Equal(t, &types.AttributeValueMemberN{Value: "8"}, tables.items["a"]["version"])
+	})
+
+	t.Run("does_nothing_when_run_again_after_finishing", func(t *testing.T) {
+		newFakeMigrationTables(t, "a", "bb")
+		calls := 0
+		transform := func(ctx context.Context, item map[string]types.AttributeValue) (map[string]types.AttributeValue, bool, error) {
+			calls++
+			return renameToDisplayName(ctx, item)
+		}
+		_, err := RunItemMigration(context.Background(), "aTable", "theCheckpoints", "theMigration", transform, WithItemMigrationSegments(1))
+		assert.NoError(t, err)
+
+		result, err := RunItemMigration(context.Background(), "aTable", "theCheckpoints", "theMigration", transform, WithItemMigrationSegments(1))
+
+		assert.NoError(t, err)
+		assert.Equal(t, 2, calls)
+		assert.Equal(t, &ItemMigrationResult{Scanned: 2, Updated: 2}, result)
+	})
+
+	t.Run("resumes_from_the_last_checkpoint", func(t *testing.T) {
+		tables := newFakeMigrationTables(t, "a", "b", "c")
+		var transformed []string
+		failOn := "b"
+		transform := func(ctx context.Context, item map[string]types.AttributeValue) (map[string]types.AttributeValue, bool, error) {
+			id := item["id"].(*types.AttributeValueMemberS).Value
+			if id == failOn {
+				return nil, false, errors.New("theTransformError")
+			}
+			transformed = append(transformed, id)
+			return renameToDisplayName(ctx, item)
+		}
+
+		_, err := RunItemMigration(context.Background(), "aTable", "theCheckpoints", "theMigration", transform, WithItemMigrationSegments(1))
+		assert.ErrorContains(t, err, "error running item migration theMigration: error migrating item in segment 0: error transforming item: theTransformError")
+
+		failOn = ""
+		result, err := RunItemMigration(context.Background(), "aTable", "theCheckpoints", "theMigration", transform, WithItemMigrationSegments(1))
+
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"a", "b", "c"}, transformed)
+		assert.Equal(t, &ItemMigrationResult{Scanned: 3, Updated: 3}, result)
+		assert.Contains(t, tables.items["c"], "display_name")
+	})
+
+	t.Run("reads_and_transforms_an_item_again_after_a_conflict", func(t *testing.T) {
+		tables := newFakeMigrationTables(t, "a")
+		conflicted := false
+		tables.beforePut = func(id string) {
+			if conflicted {
+				return
+			}
+			conflicted = true
+			tables.mu.Lock()
+			defer tables.mu.Unlock()
+			tables.items[id]["name"] = &types.AttributeValueMemberS{Value: "aNewerName"}
+			tables.items[id]["version"] = &types.AttributeValueMemberN{Value: "1"}
+		}
+
+		result, err := RunItemMigration(context.Background(), "aTable", "theCheckpoints", "theMigration", renameToDisplayName, WithItemMigrationSegments(1))
+
+		assert.NoError(t, err)
+		assert.Equal(t, &ItemMigrationResult{Scanned: 1, Updated: 1, Conflicts: 1}, result)
+		assert.Equal(t, &types.AttributeValueMemberS{Value: "aNewerName"}, tables.items["a"]["display_name"])
+		assert.Equal(t, &types.AttributeValueMemberN{Value: "2"}, tables.items["a"]["version"])
+	})
+
+	t.Run("fails_after_too_many_conflicts", func(t *testing.T) {
+		tables := newFakeMigrationTables(t, "a")
+		version := 0
+		tables.beforePut = func(id string) {
+			tables.mu.Lock()
+			defer tables.mu.Unlock()
+			version++
+			tables.items[id]["version"] = &types.AttributeValueMemberN{Value: string(rune('0' + version))}
+		}
+
+		_, err := RunItemMigration(context.Background(), "aTable", "theCheckpoints", "theMigration", renameToDisplayName, WithItemMigrationSegments(1), WithItemMigrationMaxConflicts(1))
+
+		assert.ErrorContains(t, err, "item changed by another writer 2 times")
+	})
+
+	t.Run("does_not_write_in_a_dry_run", func(t *testing.T) {
+		tables := newFakeMigrationTables(t, "a", "bb")
+
+		result, err := RunItemMigration(context.Background(), "aTable", "theCheckpoints", "theMigration", renameToDisplayName, WithItemMigrationDryRun())
+
+		assert.NoError(t, err)
+		assert.Equal(t, &ItemMigrationResult{Scanned: 2, Updated: 2}, result)
+		assert.Contains(t, tables.items["a"], "name")
+		assert.Empty(t, tables.checkpoints)
+	})
+
+	t.Run("returns_an_error_when_the_transform_changes_the_key", func(t *testing.T) {
+		newFakeMigrationTables(t, "a")
+		transform := func(ctx context.Context, item map[string]types.AttributeValue) (map[string]types.AttributeValue, bool, error) {
+			return map[string]types.AttributeValue{"id": &types.AttributeValueMemberS{Value: "anotherID"}}, true, nil
+		}
+
+		_, err := RunItemMigration(context.Background(), "aTable", "theCheckpoints", "theMigration", transform, WithItemMigrationSegments(1))
+
+		assert.ErrorContains(t, err, "transform changed key attribute id")
+	})
+
+	t.Run("returns_an_error_when_resumed_with_different_segments", func(t *testing.T) {
+		tables := newFakeMigrationTables(t, "a")
+		tables.scanErr = errors.New("theScanError")
+		_, _ = RunItemMigration(context.Background(), "aTable", "theCheckpoints", "theMigration", renameToDisplayName, WithItemMigrationSegments(1))
+		tables.checkpoints["theMigration#0"] = mustMarshalMap(t, itemMigrationCheckpoint{ID: "theMigration#0", TotalSegments: 1})
+
+		_, err := RunItemMigration(context.Background(), "aTable", "theCheckpoints", "theMigration", renameToDisplayName, WithItemMigrationSegments(2))
+
+		assert.ErrorContains(t, err, "migration theMigration was started with 1 segments, not 2")
+	})
+
+	t.Run("returns_an_error_when_the_scan_fails", func(t *testing.T) {
+		tables := newFakeMigrationTables(t, "a")
+		tables.scanErr = errors.New("theScanError")
+
+		_, err := RunItemMigration(context.Background(), "aTable", "theCheckpoints", "theMigration", renameToDisplayName, WithItemMigrationSegments(1))
+
+		assert.ErrorContains(t, err, "error scanning segment 0: theScanError")
+	})
+
+	t.Run("returns_an_error_for_missing_arguments", func(t *testing.T) {
+		_, err := RunItemMigration(context.Background(), "aTable", "theCheckpoints", "", renameToDisplayName)
+		assert.EqualError(t, err, "dynamodbkit.RunItemMigration table=aTable: migration name cannot be empty")
+
+		_, err = RunItemMigration(context.Background(), "aTable", "theCheckpoints", "theMigration", nil)
+		assert.EqualError(t, err, "dynamodbkit.RunItemMigration table=aTable: transform cannot be nil")
+
+		_, err = RunItemMigration(context.Background(), "", "theCheckpoints", "theMigration", renameToDisplayName)
+		assert.EqualError(t, err, "dynamodbkit.RunItemMigration: table name and checkpoint table name cannot be empty")
+	})
+}
@@ -0,0 +1,62 @@
+package dynamodbkit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"unicode/utf8"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/half-ogre/go-kit/kit"
+)
+
+// Single-table key attribute names, the generic PK and SK used when one table holds many item types
+const (
+	SingleTablePartitionKey = "PK"
+	SingleTableSortKey      = "SK"
+)
+
+// maxSortKeyBytes is the longest sort key DynamoDB allows
+const maxSortKeyBytes = 1024
+
+// QueryByKeyPrefix returns every item in a single-table partition whose sort key starts with
+// sortKeyPrefix, e.g. a customer's orders with
+//
+//	dynamodbkit.QueryByKeyPrefix[Order](ctx, "app", "CUSTOMER#123", "ORDER#")
+//
+// The table's keys must be SingleTablePartitionKey and SingleTableSortKey; use QueryAll with
+// WithQuerySortKeyBeginsWith for other key names or an index. End the prefix with its separator, as
+// above, or "ORDER" also matches "ORDERLINE#1".
+func QueryByKeyPrefix[TItem any](ctx context.Context, tableName string, partitionKeyValue string, sortKeyPrefix string, options ...QueryAllOption) (_ []TItem, err error) {
+	op := newOperation(ctx, "QueryByKeyPrefix", tableName)
+	op.setKey(SingleTablePartitionKey, map[string]types.AttributeValue{SingleTablePartitionKey: &types.AttributeValueMemberS{Value: partitionKeyValue}})
+	defer op.wrap(&err)
+
+	if partitionKeyValue == "" {
+		return nil, errors.New("partition key value cannot be empty")
+	}
+	if err := validateSortKeyPrefix(sortKeyPrefix); err != nil {
+		return nil, err
+	}
+
+	keyPrefixOptions := append([]QueryAllOption{WithQuerySortKeyBeginsWith(SingleTableSortKey, sortKeyPrefix)}, options...)
+	output, err := QueryAll[TItem](ctx, tableName, SingleTablePartitionKey, partitionKeyValue, keyPrefixOptions...)
+	if err != nil {
+		return nil, kit.WrapError(err, "error querying sort key prefix %s", sortKeyPrefix)
+	}
+
+	return output.Items, nil
+}
+
+func validateSortKeyPrefix(prefix string) error {
+	if prefix == "" {
+		return errors.New("sort key prefix cannot be empty; use QueryAll to read the whole partition")
+	}
+	if !utf8.ValidString(prefix) {
+		return fmt.Errorf("sort key prefix %q is not valid UTF-8", prefix)
+	}
+	if len(prefix) > maxSortKeyBytes {
+		return fmt.Errorf("sort key prefix is %d bytes, longer than the %d byte sort key limit", len(prefix), maxSortKeyBytes)
+	}
+	return nil
+}
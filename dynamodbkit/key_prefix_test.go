@@ -0,0 +1,121 @@
+package dynamodbkit
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/stretchr/testify/assert"
+)
+
+type testOrder struct {
+	PK    string `dynamodbav:"PK"`
+	SK    string `dynamodbav:"SK"`
+	Total int    `dynamodbav:"total"`
+}
+
+func TestQueryByKeyPrefix(t *testing.T) {
+	t.Run("queries_the_partition_for_sort_keys_with_the_prefix", func(t *testing.T) {
+		var actualInput *dynamodb.QueryInput
+		fakeDB := &FakeDynamoDB{
+			QueryFake: func(ctx context.Context, params *dynamodb.QueryInput, optFns ...func(*dynamodb.Options)) (*dynamodb.QueryOutput, error) {
+				actualInput = params
+				return &dynamodb.QueryOutput{Items: []map[string]types.AttributeValue{
+					mustMarshalMap(t, testOrder{PK: "CUSTOMER#1", SK: "ORDER#1", Total: 42}),
+				}}, nil
+			},
+		}
+		setFake(func(ctx context.Context) (DynamoDB, error) { return fakeDB, nil })
+		t.Cleanup(func() { setFake(nil) })
+
+		result, err := QueryByKeyPrefix[testOrder](context.Background(), "theTable", "CUSTOMER#1", "ORDER#")
+
+		assert.NoError(t, err)
+		assert.Equal(t, []testOrder{{PK: "CUSTOMER#1", SK: "ORDER#1", Total: 42}}, result)
+		assert.Equal(t, "theTable", *actualInput.TableName)
+		assert.Equal(t, "#0 = :0 AND begins_with(#sortKeyBeginsWith, :sortKeyBeginsWith)", *actualInput.KeyConditionExpression)
+		assert.Equal(t, "PK", actualInput.ExpressionAttributeNames["#0"])
+		assert.Equal(t, "SK", actualInput.ExpressionAttributeNames["#sortKeyBeginsWith"])
+		assert.Equal(t, &types.AttributeValueMemberS{Value: "CUSTOMER#1"}, actualInput.ExpressionAttributeValues[":0"])
+		assert.Equal(t, &types.AttributeValueMemberS{Value: "ORDER#"}, actualInput.ExpressionAttributeValues[":sortKeyBeginsWith"])
+	})
+
+	t.Run("reads_every_page", func(t *testing.T) {
+		calls := 0
+		fakeDB := &FakeDynamoDB{
+			QueryFake: func(ctx context.Context, params *dynamodb.QueryInput, optFns ...func(*dynamodb.Options)) (*dynamodb.QueryOutput, error) {
+				calls++
+				output := &dynamodb.QueryOutput{Items: []map[string]types.AttributeValue{mustMarshalMap(t, testOrder{Total: calls})}}
+				if calls == 1 {
+					output.LastEvaluatedKey = map[string]types.AttributeValue{"PK": &types.AttributeValueMemberS{Value: "aKey"}}
+				}
+				return output, nil
+			},
+		}
+		setFake(func(ctx context.Context) (DynamoDB, error) { return fakeDB, nil })
+		t.Cleanup(func() { setFake(nil) })
+
+		result, err := QueryByKeyPrefix[testOrder](context.Background(), "aTable", "CUSTOMER#1", "ORDER#")
+
+		assert.NoError(t, err)
+		assert.Equal(t, []testOrder{{Total: 1}, {Total: 2}}, result)
+	})
+
+	t.Run("applies_the_query_options", func(t *testing.T) {
+		var actualInput *dynamodb.QueryInput
+		fakeDB := &FakeDynamoDB{
+			QueryFake: func(ctx context.Context, params *dynamodb.QueryInput, optFns ...func(*dynamodb.Options)) (*dynamodb.QueryOutput, error) {
+				actualInput = params
+				return &dynamodb.QueryOutput{}, nil
+			},
+		}
+		setFake(func(ctx context.Context) (DynamoDB, error) { return fakeDB, nil })
+		t.Cleanup(func() { setFake(nil) })
+
+		_, err := QueryByKeyPrefix[testOrder](context.Background(), "aTable", "CUSTOMER#1", "ORDER#", WithQueryLimit(10))
+
+		assert.NoError(t, err)
+		assert.Equal(t, int32(10), *actualInput.Limit)
+	})
+
+	t.Run("returns_an_error_for_an_empty_partition_key_value", func(t *testing.T) {
+		_, err := QueryByKeyPrefix[testOrder](context.Background(), "aTable", "", "ORDER#")
+
+		assert.EqualError(t, err, "dynamodbkit.QueryByKeyPrefix table=aTable PK=: partition key value cannot be empty")
+	})
+
+	t.Run("returns_an_error_for_an_empty_prefix", func(t *testing.T) {
+		_, err := QueryByKeyPrefix[testOrder](context.Background(), "aTable", "CUSTOMER#1", "")
+
+		assert.EqualError(t, err, "dynamodbkit.QueryByKeyPrefix table=aTable PK=CUSTOMER#1: sort key prefix cannot be empty; use QueryAll to read the whole partition")
+	})
+
+	t.Run("returns_an_error_for_an_invalid_utf8_prefix", func(t *testing.T) {
+		_, err := QueryByKeyPrefix[testOrder](context.Background(), "aTable", "CUSTOMER#1", "ORDER#\xff")
+
+		assert.ErrorContains(t, err, "is not valid UTF-8")
+	})
+
+	t.Run("returns_an_error_for_a_prefix_longer_than_a_sort_key", func(t *testing.T) {
+		_, err := QueryByKeyPrefix[testOrder](context.Background(), "aTable", "CUSTOMER#1", strings.Repeat("a", 1025))
+
+		assert.ErrorContains(t, err, "sort key prefix is 1025 bytes, longer than the 1024 byte sort key limit")
+	})
+
+	t.Run("returns_an_error_when_the_query_fails", func(t *testing.T) {
+		fakeDB := &FakeDynamoDB{
+			QueryFake: func(ctx context.Context, params *dynamodb.QueryInput, optFns ...func(*dynamodb.Options)) (*dynamodb.QueryOutput, error) {
+				return nil, errors.New("the fake error")
+			},
+		}
+		setFake(func(ctx context.Context) (DynamoDB, error) { return fakeDB, nil })
+		t.Cleanup(func() { setFake(nil) })
+
+		_, err := QueryByKeyPrefix[testOrder](context.Background(), "aTable", "CUSTOMER#1", "ORDER#")
+
+		assert.EqualError(t, err, "error querying sort key prefix ORDER#: dynamodbkit.QueryAll table=aTable PK=CUSTOMER#1: error querying: the fake error")
+	})
+}
@@ -2,18 +2,23 @@ package dynamodbkit
 
 import (
 	"context"
+	"errors"
+	"fmt"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
 	"github.com/half-ogre/go-kit/kit"
 )
 
-func ListTables(ctx context.Context, options ...ListTablesOption) (*ListTablesOutput, error) {
+func ListTables(ctx context.Context, options ...ListTablesOption) (_ *ListTablesOutput, err error) {
+	op := newOperation(ctx, "ListTables", "")
+	defer op.wrap(&err)
+
 	if ctx == nil {
-		return nil, kit.WrapError(nil, "context cannot be nil")
+		return nil, errors.New("context cannot be nil")
 	}
 
-	db, err := newDynamoDB(ctx)
+	db, err := op.dynamoDB(ctx)
 	if err != nil {
 		return nil, kit.WrapError(err, "error creating DynamoDB client")
 	}
@@ -53,7 +58,7 @@ type ListTablesOption func(*dynamodb.ListTablesInput) error
 func WithListTablesLimit(limit int32) ListTablesOption {
 	return func(input *dynamodb.ListTablesInput) error {
 		if limit < 0 {
-			return kit.WrapError(nil, "limit must be non-negative, got %d", limit)
+			return fmt.Errorf("limit must be non-negative, got %d", limit)
 		}
 		input.Limit = aws.Int32(limit)
 		return nil
@@ -63,7 +68,7 @@ func WithListTablesLimit(limit int32) ListTablesOption {
 func WithListTablesExclusiveStartTableName(tableName string) ListTablesOption {
 	return func(input *dynamodb.ListTablesInput) error {
 		if tableName == "" {
-			return kit.WrapError(nil, "exclusive start table name cannot be empty")
+			return errors.New("exclusive start table name cannot be empty")
 		}
 		input.ExclusiveStartTableName = aws.String(tableName)
 		return nil
@@ -26,7 +26,7 @@ func TestListTables(t *testing.T) {
 		result, err := ListTables(context.Background())
 
 		assert.Nil(t, result)
-		assert.EqualError(t, err, "error creating DynamoDB client: the fake error")
+		assert.EqualError(t, err, "dynamodbkit.ListTables: error creating DynamoDB client: the fake error")
 	})
 
 	t.Run("returns_the_expected_output_when_no_errors_and_no_last_evaluated_table_name", func(t *testing.T) {
@@ -84,7 +84,7 @@ func TestListTables(t *testing.T) {
 		result, err := ListTables(context.Background())
 
 		assert.Nil(t, result)
-		assert.EqualError(t, err, "error listing tables: the fake error")
+		assert.EqualError(t, err, "dynamodbkit.ListTables: error listing tables: the fake error")
 	})
 
 	t.Run("returns_empty_slice_when_no_tables_exist", func(t *testing.T) {
@@ -118,7 +118,7 @@ func TestListTables(t *testing.T) {
 		result, err := ListTables(context.Background(), badOption)
 
 		assert.Nil(t, result)
-		assert.EqualError(t, err, "error processing option: the option error")
+		assert.EqualError(t, err, "dynamodbkit.ListTables: error processing option: the option error")
 	})
 }
 
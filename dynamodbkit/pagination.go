@@ -0,0 +1,119 @@
+package dynamodbkit
+
+import (
+	"context"
+	"math"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+)
+
+// PageOption limits how QueryAll, ScanAll, QueryItems, and ScanItems read pages
+type PageOption func(*pageConfig)
+
+type pageConfig struct {
+	maxPages  int
+	maxItems  int
+	pageDelay time.Duration
+}
+
+// WithMaxPages stops after maxPages pages, returning the items read so far with a LastEvaluatedKey to
+// resume from, so a handler can't accidentally read a whole table
+func WithMaxPages(maxPages int) PageOption {
+	return func(config *pageConfig) {
+		config.maxPages = maxPages
+	}
+}
+
+// WithMaxItems stops after maxItems items, returning them with a LastEvaluatedKey to resume from. Each
+// page's limit is lowered to the items remaining, so no more than maxItems are read and resuming
+// doesn't skip any.
+func WithMaxItems(maxItems int) PageOption {
+	return func(config *pageConfig) {
+		config.maxItems = maxItems
+	}
+}
+
+// WithPageDelay waits between pages, to spread a large read's consumed capacity over time
+func WithPageDelay(delay time.Duration) PageOption {
+	return func(config *pageConfig) {
+		config.pageDelay = delay
+	}
+}
+
+// QueryAllOption is a QueryOption or a PageOption
+type QueryAllOption interface {
+	applyQueryAll(*queryAllConfig)
+}
+
+// ScanAllOption is a ScanOption or a PageOption
+type ScanAllOption interface {
+	applyScanAll(*scanAllConfig)
+}
+
+type queryAllConfig struct {
+	queryOptions []QueryOption
+	paging       pageConfig
+}
+
+type scanAllConfig struct {
+	scanOptions []ScanOption
+	paging      pageConfig
+}
+
+func (o QueryOption) applyQueryAll(config *queryAllConfig) {
+	config.queryOptions = append(config.queryOptions, o)
+}
+
+func (o ScanOption) applyScanAll(config *scanAllConfig) {
+	config.scanOptions = append(config.scanOptions, o)
+}
+
+func (o PageOption) applyQueryAll(config *queryAllConfig) {
+	o(&config.paging)
+}
+
+func (o PageOption) applyScanAll(config *scanAllConfig) {
+	o(&config.paging)
+}
+
+// pageLimit returns the Limit for the next page: limit, lowered if needed so the page can't take the
+// items read past maxItems
+func (c pageConfig) pageLimit(limit *int32, itemsRead int) *int32 {
+	if c.maxItems <= 0 {
+		return limit
+	}
+
+	remaining := int32(min(c.maxItems-itemsRead, math.MaxInt32))
+	if limit != nil && *limit < remaining {
+		return limit
+	}
+	return aws.Int32(remaining)
+}
+
+// beforeNextPage reports whether to stop before reading another page, because the page or item limit
+// was reached (with no error) or the context is done. It waits out any page delay.
+func (c pageConfig) beforeNextPage(ctx context.Context, pagesRead int, itemsRead int) (bool, error) {
+	if c.maxPages > 0 && pagesRead >= c.maxPages {
+		return true, nil
+	}
+	if c.maxItems > 0 && itemsRead >= c.maxItems {
+		return true, nil
+	}
+
+	if err := ctx.Err(); err != nil {
+		return true, err
+	}
+
+	if c.pageDelay > 0 {
+		timer := time.NewTimer(c.pageDelay)
+		defer timer.Stop()
+		select {
+		case <-ctx.Done():
+			return true, ctx.Err()
+		case <-timer.C:
+		}
+	}
+
+	return false, nil
+}
@@ -0,0 +1,285 @@
+package dynamodbkit
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/stretchr/testify/assert"
+)
+
+// endlessQuery returns a fake query that always has another page, with one item per page
+func endlessQuery(t *testing.T, onPage func()) func(ctx context.Context, params *dynamodb.QueryInput, optFns ...func(*dynamodb.Options)) (*dynamodb.QueryOutput, error) {
+	return func(ctx context.Context, params *dynamodb.QueryInput, optFns ...func(*dynamodb.Options)) (*dynamodb.QueryOutput, error) {
+		onPage()
+		return &dynamodb.QueryOutput{
+			Items:            []map[string]types.AttributeValue{mustMarshalMap(t, TestUser{ID: "theID"})},
+			LastEvaluatedKey: map[string]types.AttributeValue{"id": &types.AttributeValueMemberS{Value: "theLastID"}},
+		}, nil
+	}
+}
+
+func TestQueryAllPaging(t *testing.T) {
+	t.Run("stops_after_max_pages_with_a_resume_cursor", func(t *testing.T) {
+		calls := 0
+		fakeDB := &FakeDynamoDB{QueryFake: endlessQuery(t, func() { calls++ })}
+		setFake(func(ctx context.Context) (DynamoDB, error) { return fakeDB, nil })
+		t.Cleanup(func() { setFake(nil) })
+
+		result, err := QueryAll[TestUser](context.Background(), "aTable", "id", "theID", WithQueryLimit(1), WithMaxPages(3))
+
+		assert.NoError(t, err)
+		assert.Equal(t, 3, calls)
+		assert.Len(t, result.Items, 3)
+		assert.NotNil(t, result.LastEvaluatedKey)
+
+		resumeInput := &dynamodb.QueryInput{}
+		assert.NoError(t, WithQueryExclusiveStartKey(*result.LastEvaluatedKey)(resumeInput))
+		assert.Equal(t, map[string]types.AttributeValue{"id": &types.AttributeValueMemberS{Value: "theLastID"}}, resumeInput.ExclusiveStartKey)
+	})
+
+	t.Run("returns_partial_results_when_the_context_is_canceled_between_pages", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		t.Cleanup(cancel)
+		calls := 0
+		fakeDB := &FakeDynamoDB{QueryFake: endlessQuery(t, func() {
+			calls++
+			if calls == 2 {
+				cancel()
+			}
+		})}
+		setFake(func(ctx context.Context) (DynamoDB, error) { return fakeDB, nil })
+		t.Cleanup(func() { setFake(nil) })
+
+		result, err := QueryAll[TestUser](ctx, "theTableName", "id", "theID")
+
+		assert.EqualError(t, err, "dynamodbkit.QueryAll table=theTableName id=theID: stopped querying after page 2: context canceled")
+		assert.ErrorIs(t, err, context.Canceled)
+		assert.Equal(t, 2, calls)
+		assert.Len(t, result.Items, 2)
+		assert.NotNil(t, result.LastEvaluatedKey)
+	})
+
+	t.Run("returns_partial_results_with_a_resume_cursor_when_a_page_request_fails", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		t.Cleanup(cancel)
+		calls := 0
+		fakeDB := &FakeDynamoDB{
+			QueryFake: func(ctx context.Context, params *dynamodb.QueryInput, optFns ...func(*dynamodb.Options)) (*dynamodb.QueryOutput, error) {
+				calls++
+				if calls == 3 {
+					cancel()
+					return nil, ctx.Err()
+				}
+				return &dynamodb.QueryOutput{
+					Items:            []map[string]types.AttributeValue{mustMarshalMap(t, TestUser{ID: "theID"})},
+					LastEvaluatedKey: map[string]types.AttributeValue{"id": &types.AttributeValueMemberS{Value: fmt.Sprintf("theLastID%d", calls)}},
+				}, nil
+			},
+		}
+		setFake(func(ctx context.Context) (DynamoDB, error) { return fakeDB, nil })
+		t.Cleanup(func() { setFake(nil) })
+
+		result, err := QueryAll[TestUser](ctx, "theTableName", "id", "theID")
+
+		assert.EqualError(t, err, "dynamodbkit.QueryAll table=theTableName id=theID: stopped querying at page 3: error querying: context canceled")
+		assert.ErrorIs(t, err, context.Canceled)
+		assert.Len(t, result.Items, 2)
+		resumeInput := &dynamodb.QueryInput{}
+		assert.NoError(t, WithQueryExclusiveStartKey(*result.LastEvaluatedKey)(resumeInput))
+		assert.Equal(t, map[string]types.AttributeValue{"id": &types.AttributeValueMemberS{Value: "theLastID2"}}, resumeInput.ExclusiveStartKey)
+	})
+
+	t.Run("waits_the_page_delay_between_pages", func(t *testing.T) {
+		var pageTimes []time.Time
+		calls := 0
+		fakeDB := &FakeDynamoDB{
+			QueryFake: func(ctx context.Context, params *dynamodb.QueryInput, optFns ...func(*dynamodb.Options)) (*dynamodb.QueryOutput, error) {
+				calls++
+				pageTimes = append(pageTimes, time.Now())
+				output := &dynamodb.QueryOutput{}
+				if calls == 1 {
+					output.LastEvaluatedKey = map[string]types.AttributeValue{"id": &types.AttributeValueMemberS{Value: "theLastID"}}
+				}
+				return output, nil
+			},
+		}
+		setFake(func(ctx context.Context) (DynamoDB, error) { return fakeDB, nil })
+		t.Cleanup(func() { setFake(nil) })
+
+		_, err := QueryAll[TestUser](context.Background(), "aTable", "id", "theID", WithPageDelay(20*time.Millisecond))
+
+		assert.NoError(t, err)
+		assert.Len(t, pageTimes, 2)
+		assert.GreaterOrEqual(t, pageTimes[1].Sub(pageTimes[0]), 20*time.Millisecond)
+	})
+
+	t.Run("stops_waiting_for_the_page_delay_when_the_context_is_canceled", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		t.Cleanup(cancel)
+		fakeDB := &FakeDynamoDB{QueryFake: endlessQuery(t, func() {})}
+		setFake(func(ctx context.Context) (DynamoDB, error) { return fakeDB, nil })
+		t.Cleanup(func() { setFake(nil) })
+
+		result, err := QueryAll[TestUser](ctx, "aTable", "id", "theID", WithPageDelay(time.Hour))
+
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+		assert.Len(t, result.Items, 1)
+	})
+}
+
+func TestScanAllPaging(t *testing.T) {
+	t.Run("stops_after_max_pages_with_a_resume_cursor", func(t *testing.T) {
+		calls := 0
+		fakeDB := &FakeDynamoDB{
+			ScanFake: func(ctx context.Context, params *dynamodb.ScanInput, optFns ...func(*dynamodb.Options)) (*dynamodb.ScanOutput, error) {
+				calls++
+				return &dynamodb.ScanOutput{
+					Items:            []map[string]types.AttributeValue{mustMarshalMap(t, TestUser{ID: "theID"})},
+					LastEvaluatedKey: map[string]types.AttributeValue{"id": &types.AttributeValueMemberS{Value: "theLastID"}},
+				}, nil
+			},
+		}
+		setFake(func(ctx context.Context) (DynamoDB, error) { return fakeDB, nil })
+		t.Cleanup(func() { setFake(nil) })
+
+		result, err := ScanAll[TestUser](context.Background(), "aTable", WithScanLimit(1), WithMaxPages(2))
+
+		assert.NoError(t, err)
+		assert.Equal(t, 2, calls)
+		assert.Len(t, result.Items, 2)
+		assert.NotNil(t, result.LastEvaluatedKey)
+	})
+
+	t.Run("returns_partial_results_when_the_context_is_canceled_between_pages", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		t.Cleanup(cancel)
+		fakeDB := &FakeDynamoDB{
+			ScanFake: func(ctx context.Context, params *dynamodb.ScanInput, optFns ...func(*dynamodb.Options)) (*dynamodb.ScanOutput, error) {
+				cancel()
+				return &dynamodb.ScanOutput{
+					Items:            []map[string]types.AttributeValue{mustMarshalMap(t, TestUser{ID: "theID"})},
+					LastEvaluatedKey: map[string]types.AttributeValue{"id": &types.AttributeValueMemberS{Value: "theLastID"}},
+				}, nil
+			},
+		}
+		setFake(func(ctx context.Context) (DynamoDB, error) { return fakeDB, nil })
+		t.Cleanup(func() { setFake(nil) })
+
+		result, err := ScanAll[TestUser](ctx, "theTableName")
+
+		assert.EqualError(t, err, "dynamodbkit.ScanAll table=theTableName: stopped scanning after page 1: context canceled")
+		assert.Len(t, result.Items, 1)
+		assert.NotNil(t, result.LastEvaluatedKey)
+	})
+	t.Run("returns_partial_results_with_a_resume_cursor_when_a_page_request_fails", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		t.Cleanup(cancel)
+		calls := 0
+		fakeDB := &FakeDynamoDB{
+			ScanFake: func(ctx context.Context, params *dynamodb.ScanInput, optFns ...func(*dynamodb.Options)) (*dynamodb.ScanOutput, error) {
+				calls++
+				if calls == 2 {
+					cancel()
+					return nil, ctx.Err()
+				}
+				return &dynamodb.ScanOutput{
+					Items:            []map[string]types.AttributeValue{mustMarshalMap(t, TestUser{ID: "theID"})},
+					LastEvaluatedKey: map[string]types.AttributeValue{"id": &types.AttributeValueMemberS{Value: "theLastID"}},
+				}, nil
+			},
+		}
+		setFake(func(ctx context.Context) (DynamoDB, error) { return fakeDB, nil })
+		t.Cleanup(func() { setFake(nil) })
+
+		result, err := ScanAll[TestUser](ctx, "theTableName")
+
+		assert.EqualError(t, err, "dynamodbkit.ScanAll table=theTableName: stopped scanning at page 2: error scanning: context canceled")
+		assert.Len(t, result.Items, 1)
+		resumeInput := &dynamodb.ScanInput{}
+		assert.NoError(t, WithScanExclusiveStartKey(*result.LastEvaluatedKey)(resumeInput))
+		assert.Equal(t, map[string]types.AttributeValue{"id": &types.AttributeValueMemberS{Value: "theLastID"}}, resumeInput.ExclusiveStartKey)
+	})
+}
+
+func TestQueryShardsCancellation(t *testing.T) {
+	t.Run("returns_an_error_when_the_context_is_canceled_between_pages", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		t.Cleanup(cancel)
+		fakeDB := &FakeDynamoDB{QueryFake: endlessQuery(t, cancel)}
+		setFake(func(ctx context.Context) (DynamoDB, error) { return fakeDB, nil })
+		t.Cleanup(func() { setFake(nil) })
+
+		_, err := QueryShards(ctx, "aTable", "id", "theID", 2, func(a, b TestUser) bool { return a.ID < b.ID })
+
+		assert.ErrorIs(t, err, context.Canceled)
+	})
+}
+
+func TestWithMaxItems(t *testing.T) {
+	t.Run("lowers_each_page_limit_to_the_items_remaining_and_stops_with_a_resume_cursor", func(t *testing.T) {
+		var actualLimits []int32
+		fakeDB := &FakeDynamoDB{
+			QueryFake: func(ctx context.Context, params *dynamodb.QueryInput, optFns ...func(*dynamodb.Options)) (*dynamodb.QueryOutput, error) {
+				actualLimits = append(actualLimits, *params.Limit)
+				items := make([]map[string]types.AttributeValue, *params.Limit)
+				for i := range items {
+					items[i] = mustMarshalMap(t, TestUser{ID: "theID"})
+				}
+				return &dynamodb.QueryOutput{
+					Items:            items,
+					LastEvaluatedKey: map[string]types.AttributeValue{"id": &types.AttributeValueMemberS{Value: "theLastID"}},
+				}, nil
+			},
+		}
+		setFake(func(ctx context.Context) (DynamoDB, error) { return fakeDB, nil })
+		t.Cleanup(func() { setFake(nil) })
+
+		result, err := QueryAll[TestUser](context.Background(), "aTable", "id", "theID", WithQueryLimit(4), WithMaxItems(10))
+
+		assert.NoError(t, err)
+		assert.Equal(t, []int32{4, 4, 2}, actualLimits)
+		assert.Len(t, result.Items, 10)
+		assert.NotNil(t, result.LastEvaluatedKey)
+	})
+
+	t.Run("returns_every_item_when_there_are_fewer_than_max_items", func(t *testing.T) {
+		var actualLimit *int32
+		fakeDB := &FakeDynamoDB{
+			ScanFake: func(ctx context.Context, params *dynamodb.ScanInput, optFns ...func(*dynamodb.Options)) (*dynamodb.ScanOutput, error) {
+				actualLimit = params.Limit
+				return &dynamodb.ScanOutput{Items: []map[string]types.AttributeValue{mustMarshalMap(t, TestUser{ID: "theID"})}}, nil
+			},
+		}
+		setFake(func(ctx context.Context) (DynamoDB, error) { return fakeDB, nil })
+		t.Cleanup(func() { setFake(nil) })
+
+		result, err := ScanAll[TestUser](context.Background(), "aTable", WithMaxItems(100))
+
+		assert.NoError(t, err)
+		assert.Equal(t, int32(100), *actualLimit)
+		assert.Equal(t, []TestUser{{ID: "theID"}}, result.Items)
+		assert.Nil(t, result.LastEvaluatedKey)
+	})
+
+	t.Run("caps_the_page_limit_at_the_largest_int32_when_max_items_is_larger", func(t *testing.T) {
+		var actualLimit *int32
+		fakeDB := &FakeDynamoDB{
+			ScanFake: func(ctx context.Context, params *dynamodb.ScanInput, optFns ...func(*dynamodb.Options)) (*dynamodb.ScanOutput, error) {
+				actualLimit = params.Limit
+				return &dynamodb.ScanOutput{}, nil
+			},
+		}
+		setFake(func(ctx context.Context) (DynamoDB, error) { return fakeDB, nil })
+		t.Cleanup(func() { setFake(nil) })
+
+		_, err := ScanAll[TestUser](context.Background(), "aTable", WithMaxItems(math.MaxInt32+1))
+
+		assert.NoError(t, err)
+		assert.Equal(t, int32(math.MaxInt32), *actualLimit)
+	})
+}
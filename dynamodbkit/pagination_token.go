@@ -0,0 +1,191 @@
+package dynamodbkit
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/half-ogre/go-kit/kit"
+)
+
+// ErrPaginationTokenExpired is returned when resuming from a pagination token past its expiry
+var ErrPaginationTokenExpired = errors.New("pagination token expired")
+
+// PaginationToken is what a pagination token from NewPaginationToken carries: the LastEvaluatedKey to
+// resume from and, with WithPaginationTokenTTL, when the token stops being accepted
+type PaginationToken struct {
+	Position  any       `json:"position"`
+	ExpiresAt time.Time `json:"expires_at,omitzero"`
+}
+
+// PaginationTokenOption configures NewPaginationToken and how WithQueryExclusiveStartKey and
+// WithScanExclusiveStartKey read a token. Pass the same options to both.
+type PaginationTokenOption func(*paginationTokenConfig)
+
+type paginationTokenConfig struct {
+	secret  []byte
+	encrypt bool
+	ttl     time.Duration
+	clock   kit.ClockInterface
+}
+
+// WithPaginationTokenSecret signs tokens with an HMAC-SHA256 of secret, so end users can't forge or
+// edit one to page from a key they chose, e.g. in another tenant's partition
+func WithPaginationTokenSecret(secret []byte) PaginationTokenOption {
+	return func(config *paginationTokenConfig) {
+		config.secret = secret
+	}
+}
+
+// WithPaginationTokenEncryption encrypts tokens with AES-GCM, using a key derived from the secret,
+// instead of signing them, so end users can't read the key values either
+func WithPaginationTokenEncryption() PaginationTokenOption {
+	return func(config *paginationTokenConfig) {
+		config.encrypt = true
+	}
+}
+
+// WithPaginationTokenTTL makes tokens expire ttl after they are created
+func WithPaginationTokenTTL(ttl time.Duration) PaginationTokenOption {
+	return func(config *paginationTokenConfig) {
+		config.ttl = ttl
+	}
+}
+
+// WithPaginationTokenClock sets the clock used to stamp and check expiries
+func WithPaginationTokenClock(clock kit.ClockInterface) PaginationTokenOption {
+	return func(config *paginationTokenConfig) {
+		config.clock = clock
+	}
+}
+
+func newPaginationTokenConfig(options []PaginationTokenOption) (paginationTokenConfig, error) {
+	config := paginationTokenConfig{clock: kit.NewClock()}
+	for _, option := range options {
+		option(&config)
+	}
+	if len(config.secret) == 0 {
+		return paginationTokenConfig{}, errors.New("pagination token secret cannot be empty")
+	}
+	return config, nil
+}
+
+// NewPaginationToken turns a Query or Scan output's LastEvaluatedKey into a token that is safe to hand
+// to end users, returning nil for the last page. Resume from it with WithQueryExclusiveStartKey or
+// WithScanExclusiveStartKey and the same options, e.g.
+//
+//	token, err := dynamodbkit.NewPaginationToken(output.LastEvaluatedKey, dynamodbkit.WithPaginationTokenSecret(secret))
+func NewPaginationToken(lastEvaluatedKey *string, options ...PaginationTokenOption) (*string, error) {
+	if lastEvaluatedKey == nil {
+		return nil, nil
+	}
+
+	config, err := newPaginationTokenConfig(options)
+	if err != nil {
+		return nil, err
+	}
+
+	token := PaginationToken{}
+	if err := kit.DecodeCursor(*lastEvaluatedKey, &token.Position); err != nil {
+		return nil, kit.WrapError(err, "failed to decode LastEvaluatedKey")
+	}
+	if config.ttl > 0 {
+		token.ExpiresAt = config.clock.Now().Add(config.ttl).UTC()
+	}
+
+	cursor, err := kit.EncodeCursor(token)
+	if err != nil {
+		return nil, kit.WrapError(err, "failed to encode pagination token")
+	}
+
+	if !config.encrypt {
+		signed := kit.SignCursor(cursor, config.secret)
+		return &signed, nil
+	}
+
+	encrypted, err := encryptPaginationToken(cursor, config.secret)
+	if err != nil {
+		return nil, kit.WrapError(err, "failed to encrypt pagination token")
+	}
+	return &encrypted, nil
+}
+
+// decodePaginationToken verifies a token from NewPaginationToken and returns its ExclusiveStartKey
+func decodePaginationToken(token string, options []PaginationTokenOption) (map[string]types.AttributeValue, error) {
+	config, err := newPaginationTokenConfig(options)
+	if err != nil {
+		return nil, err
+	}
+
+	var cursor string
+	if config.encrypt {
+		cursor, err = decryptPaginationToken(token, config.secret)
+	} else {
+		cursor, err = kit.VerifyCursor(token, config.secret)
+	}
+	if err != nil {
+		return nil, kit.WrapError(err, "invalid pagination token")
+	}
+
+	var decoded PaginationToken
+	if err := kit.DecodeCursor(cursor, &decoded); err != nil {
+		return nil, kit.WrapError(err, "failed to decode pagination token")
+	}
+	if !decoded.ExpiresAt.IsZero() && !config.clock.Now().Before(decoded.ExpiresAt) {
+		return nil, ErrPaginationTokenExpired
+	}
+
+	key, err := attributevalue.MarshalMap(decoded.Position)
+	if err != nil {
+		return nil, kit.WrapError(err, "failed to marshal pagination token position")
+	}
+	return key, nil
+}
+
+func paginationTokenCipher(secret []byte) (cipher.AEAD, error) {
+	key := sha256.Sum256(secret)
+	block, err := aes.NewCipher(key[:])
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+func encryptPaginationToken(cursor string, secret []byte) (string, error) {
+	gcm, err := paginationTokenCipher(secret)
+	if err != nil {
+		return "", err
+	}
+
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+
+	sealed := gcm.Seal(nonce, nonce, []byte(cursor), nil)
+	return base64.RawURLEncoding.EncodeToString(sealed), nil
+}
+
+func decryptPaginationToken(token string, secret []byte) (string, error) {
+	gcm, err := paginationTokenCipher(secret)
+	if err != nil {
+		return "", err
+	}
+
+	sealed, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil || len(sealed) < gcm.NonceSize() {
+		return "", kit.ErrInvalidCursorSignature
+	}
+
+	opened, err := gcm.Open(nil, sealed[:gcm.NonceSize()], sealed[gcm.NonceSize():], nil)
+	if err != nil {
+		return "", kit.ErrInvalidCursorSignature
+	}
+	return string(opened), nil
+}
@@ -0,0 +1,104 @@
+package dynamodbkit
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/half-ogre/go-kit/kit"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestLastEvaluatedKey(t *testing.T) *string {
+	cursor, err := kit.EncodeCursor(map[string]any{"id": "theLastID"})
+	assert.NoError(t, err)
+	return &cursor
+}
+
+func TestNewPaginationToken(t *testing.T) {
+	theSecret := []byte("theSecret")
+	theKey := map[string]types.AttributeValue{"id": &types.AttributeValueMemberS{Value: "theLastID"}}
+
+	t.Run("round_trips_a_signed_token_through_a_query", func(t *testing.T) {
+		token, err := NewPaginationToken(newTestLastEvaluatedKey(t), WithPaginationTokenSecret(theSecret))
+		assert.NoError(t, err)
+		input := &dynamodb.QueryInput{}
+
+		err = WithQueryExclusiveStartKey(*token, WithPaginationTokenSecret(theSecret))(input)
+
+		assert.NoError(t, err)
+		assert.Equal(t, theKey, input.ExclusiveStartKey)
+	})
+
+	t.Run("round_trips_an_encrypted_token_through_a_scan", func(t *testing.T) {
+		options := []PaginationTokenOption{WithPaginationTokenSecret(theSecret), WithPaginationTokenEncryption()}
+		token, err := NewPaginationToken(newTestLastEvaluatedKey(t), options...)
+		assert.NoError(t, err)
+		input := &dynamodb.ScanInput{}
+
+		err = WithScanExclusiveStartKey(*token, options...)(input)
+
+		assert.NoError(t, err)
+		assert.Equal(t, theKey, input.ExclusiveStartKey)
+		assert.NotContains(t, *token, "theLastID")
+	})
+
+	t.Run("returns_nil_for_the_last_page", func(t *testing.T) {
+		token, err := NewPaginationToken(nil, WithPaginationTokenSecret(theSecret))
+
+		assert.NoError(t, err)
+		assert.Nil(t, token)
+	})
+
+	t.Run("returns_an_error_without_a_secret", func(t *testing.T) {
+		token, err := NewPaginationToken(newTestLastEvaluatedKey(t))
+
+		assert.Nil(t, token)
+		assert.EqualError(t, err, "pagination token secret cannot be empty")
+	})
+
+	t.Run("rejects_an_edited_token", func(t *testing.T) {
+		token, err := NewPaginationToken(newTestLastEvaluatedKey(t), WithPaginationTokenSecret(theSecret))
+		assert.NoError(t, err)
+		cursor, signature, _ := strings.Cut(*token, ".")
+		edited, err := kit.EncodeCursor(PaginationToken{Position: map[string]any{"id": "anotherID"}})
+		assert.NoError(t, err)
+		assert.NotEqual(t, cursor, edited)
+
+		err = WithQueryExclusiveStartKey(edited+"."+signature, WithPaginationTokenSecret(theSecret))(&dynamodb.QueryInput{})
+
+		assert.ErrorIs(t, err, kit.ErrInvalidCursorSignature)
+	})
+
+	t.Run("rejects_a_token_made_with_another_secret", func(t *testing.T) {
+		options := []PaginationTokenOption{WithPaginationTokenSecret([]byte("anotherSecret")), WithPaginationTokenEncryption()}
+		token, err := NewPaginationToken(newTestLastEvaluatedKey(t), options...)
+		assert.NoError(t, err)
+
+		err = WithScanExclusiveStartKey(*token, WithPaginationTokenSecret(theSecret), WithPaginationTokenEncryption())(&dynamodb.ScanInput{})
+
+		assert.ErrorIs(t, err, kit.ErrInvalidCursorSignature)
+	})
+
+	t.Run("rejects_an_expired_token", func(t *testing.T) {
+		now := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+		clock := kit.NewClock(kit.WithFake(func() time.Time { return now }))
+		options := []PaginationTokenOption{WithPaginationTokenSecret(theSecret), WithPaginationTokenTTL(time.Minute), WithPaginationTokenClock(clock)}
+		token, err := NewPaginationToken(newTestLastEvaluatedKey(t), options...)
+		assert.NoError(t, err)
+		assert.NoError(t, WithQueryExclusiveStartKey(*token, options...)(&dynamodb.QueryInput{}))
+		now = now.Add(time.Minute)
+
+		err = WithQueryExclusiveStartKey(*token, options...)(&dynamodb.QueryInput{})
+
+		assert.ErrorIs(t, err, ErrPaginationTokenExpired)
+	})
+
+	t.Run("rejects_a_raw_last_evaluated_key_when_a_secret_is_set", func(t *testing.T) {
+		err := WithQueryExclusiveStartKey(*newTestLastEvaluatedKey(t), WithPaginationTokenSecret(theSecret))(&dynamodb.QueryInput{})
+
+		assert.ErrorIs(t, err, kit.ErrInvalidCursorSignature)
+	})
+}
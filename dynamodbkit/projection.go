@@ -0,0 +1,105 @@
+package dynamodbkit
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+)
+
+// listIndexes matches the list indexes at the end of a document path element, e.g. the [0] of items[0]
+var listIndexes = regexp.MustCompile(`(\[\d+\])+$`)
+
+// ProjectionExpression returns a projection expression for attributes with every attribute name
+// replaced by a placeholder, and the expression attribute names for the placeholders, so attributes
+// named with reserved words such as name and timestamp can be projected. Attributes can be document
+// paths, e.g. address.city or items[0].sku.
+func ProjectionExpression(attributes ...string) (string, map[string]string, error) {
+	if len(attributes) == 0 {
+		return "", nil, errors.New("at least one attribute is required")
+	}
+
+	names := map[string]string{}
+	placeholders := map[string]string{}
+	paths := make([]string, 0, len(attributes))
+	for _, attribute := range attributes {
+		if attribute == "" {
+			return "", nil, errors.New("attribute cannot be empty")
+		}
+
+		elements := strings.Split(attribute, ".")
+		for i, element := range elements {
+			indexes := listIndexes.FindString(element)
+			name := strings.TrimSuffix(element, indexes)
+			if name == "" {
+				return "", nil, fmt.Errorf("attribute %s has an empty name", attribute)
+			}
+
+			placeholder, ok := placeholders[name]
+			if !ok {
+				placeholder = fmt.Sprintf("#projection%d", len(placeholders))
+				placeholders[name] = placeholder
+				names[placeholder] = name
+			}
+			elements[i] = placeholder + indexes
+		}
+		paths = append(paths, strings.Join(elements, "."))
+	}
+
+	return strings.Join(paths, ", "), names, nil
+}
+
+// WithQueryProjectedAttributes only returns attributes of each item, e.g.
+// WithQueryProjectedAttributes("id", "name", "timestamp"), aliasing their names as
+// ProjectionExpression does
+func WithQueryProjectedAttributes(attributes ...string) QueryOption {
+	return func(input *dynamodb.QueryInput) error {
+		expression, names, err := ProjectionExpression(attributes...)
+		if err != nil {
+			return err
+		}
+		input.ExpressionAttributeNames, err = mergeExpressionAttributeNames(input.ExpressionAttributeNames, names)
+		if err != nil {
+			return err
+		}
+		input.ProjectionExpression = aws.String(expression)
+		return nil
+	}
+}
+
+// WithScanProjectedAttributes only returns attributes of each item, aliasing their names as
+// ProjectionExpression does
+func WithScanProjectedAttributes(attributes ...string) ScanOption {
+	return func(input *dynamodb.ScanInput) error {
+		expression, names, err := ProjectionExpression(attributes...)
+		if err != nil {
+			return err
+		}
+		input.ExpressionAttributeNames, err = mergeExpressionAttributeNames(input.ExpressionAttributeNames, names)
+		if err != nil {
+			return err
+		}
+		input.ProjectionExpression = aws.String(expression)
+		return nil
+	}
+}
+
+// WithGetItemProjectedAttributes only returns attributes of the item, aliasing their names as
+// ProjectionExpression does
+func WithGetItemProjectedAttributes(attributes ...string) GetItemOption {
+	return func(input *dynamodb.GetItemInput) error {
+		expression, names, err := ProjectionExpression(attributes...)
+		if err != nil {
+			return err
+		}
+		input.ExpressionAttributeNames, err = mergeExpressionAttributeNames(input.ExpressionAttributeNames, names)
+		if err != nil {
+			return err
+		}
+		input.ProjectionExpression = aws.String(expression)
+		return nil
+	}
+}
@@ -0,0 +1,88 @@
+package dynamodbkit
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProjectionExpression(t *testing.T) {
+	t.Run("aliases_every_attribute", func(t *testing.T) {
+		expression, names, err := ProjectionExpression("id", "name", "timestamp")
+
+		require.NoError(t, err)
+		assert.Equal(t, "#projection0, #projection1, #projection2", expression)
+		assert.Equal(t, map[string]string{"#projection0": "id", "#projection1": "name", "#projection2": "timestamp"}, names)
+	})
+
+	t.Run("aliases_each_element_of_document_paths_and_keeps_list_indexes", func(t *testing.T) {
+		expression, names, err := ProjectionExpression("items[0].name", "address.name")
+
+		require.NoError(t, err)
+		assert.Equal(t, "#projection0[0].#projection1, #projection2.#projection1", expression)
+		assert.Equal(t, map[string]string{"#projection0": "items", "#projection1": "name", "#projection2": "address"}, names)
+	})
+
+	t.Run("returns_an_error_without_attributes", func(t *testing.T) {
+		_, _, err := ProjectionExpression()
+
+		assert.EqualError(t, err, "at least one attribute is required")
+	})
+
+	t.Run("returns_an_error_for_an_empty_path_element", func(t *testing.T) {
+		_, _, err := ProjectionExpression("address..city")
+
+		assert.EqualError(t, err, "attribute address..city has an empty name")
+	})
+}
+
+func TestWithQueryProjectedAttributes(t *testing.T) {
+	t.Run("sets_the_projection_alongside_the_key_condition_names", func(t *testing.T) {
+		var actualInput *dynamodb.QueryInput
+		fakeDB := &FakeDynamoDB{
+			QueryFake: func(ctx context.Context, params *dynamodb.QueryInput, optFns ...func(*dynamodb.Options)) (*dynamodb.QueryOutput, error) {
+				actualInput = params
+				return &dynamodb.QueryOutput{Items: []map[string]types.AttributeValue{}}, nil
+			},
+		}
+		setFake(func(ctx context.Context) (DynamoDB, error) { return fakeDB, nil })
+		t.Cleanup(func() { setFake(nil) })
+
+		_, err := Query[TestUser](context.Background(), "aTable", "id", "aUserID",
+			WithQuerySortKeyBeginsWith("timestamp", "2026"), WithQueryProjectedAttributes("id", "name", "timestamp"))
+
+		require.NoError(t, err)
+		assert.Equal(t, "#projection0, #projection1, #projection2", aws.ToString(actualInput.ProjectionExpression))
+		assert.Equal(t, "name", actualInput.ExpressionAttributeNames["#projection1"])
+		assert.Equal(t, "timestamp", actualInput.ExpressionAttributeNames["#sortKeyBeginsWith"])
+	})
+}
+
+func TestWithScanProjectedAttributes(t *testing.T) {
+	t.Run("sets_the_projection_and_names", func(t *testing.T) {
+		input := &dynamodb.ScanInput{}
+
+		err := WithScanProjectedAttributes("name")(input)
+
+		require.NoError(t, err)
+		assert.Equal(t, "#projection0", aws.ToString(input.ProjectionExpression))
+		assert.Equal(t, map[string]string{"#projection0": "name"}, input.ExpressionAttributeNames)
+	})
+}
+
+func TestWithGetItemProjectedAttributes(t *testing.T) {
+	t.Run("sets_the_projection_and_names", func(t *testing.T) {
+		input := &dynamodb.GetItemInput{}
+
+		err := WithGetItemProjectedAttributes("timestamp")(input)
+
+		require.NoError(t, err)
+		assert.Equal(t, "#projection0", aws.ToString(input.ProjectionExpression))
+		assert.Equal(t, map[string]string{"#projection0": "timestamp"}, input.ExpressionAttributeNames)
+	})
+}
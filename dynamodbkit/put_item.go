@@ -2,17 +2,22 @@ package dynamodbkit
 
 import (
 	"context"
+	"errors"
 	"fmt"
-	"log/slog"
+	"reflect"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
 	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
 	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
 	"github.com/half-ogre/go-kit/kit"
+	"github.com/half-ogre/go-kit/logkit/logfields"
 )
 
-func PutItem[T any](ctx context.Context, tableName string, item T, options ...PutItemOption) error {
+func PutItem[T any](ctx context.Context, tableName string, item T, options ...PutItemOption) (err error) {
+	op := newOperation(ctx, "PutItem", tableName)
+	defer op.wrap(&err)
+
 	i, err := attributevalue.MarshalMap(item)
 	if err != nil {
 		return err
@@ -32,24 +37,34 @@ func PutItem[T any](ctx context.Context, tableName string, item T, options ...Pu
 		}
 	}
 
-	// Apply global table name suffix if table name pointer wasn't changed by options
-	if putItemInput.TableName == originalTableNamePtr {
-		globalSuffix := getTableNameSuffix()
-		if globalSuffix != "" {
-			putItemInput.TableName = aws.String(fmt.Sprintf("%s%s", *putItemInput.TableName, globalSuffix))
-		}
-	}
+	// Apply the client's table name prefix, and its suffix if table name pointer wasn't changed by options
+	putItemInput.TableName = op.client.qualifyTableName(putItemInput.TableName, originalTableNamePtr)
+	op.table = *putItemInput.TableName
 
-	db, err := newDynamoDB(ctx)
+	db, err := op.dynamoDB(ctx)
 	if err != nil {
 		return kit.WrapError(err, "error creating DynamoDB client")
 	}
 
-	slog.Info("putting item into DynamoDB", "item", item, "table", tableName, "input", putItemInput)
+	logger.Info("putting item into DynamoDB", "item", item, logfields.Table(tableName), "input", putItemInput)
+
+	metrics := startOperation(op, "PutItem", putItemInput.TableName)
+	defer func() { metrics.finish(ctx, err) }()
+	putItemInput.ReturnConsumedCapacity = op.client.returnConsumedCapacity(metrics)
 
-	_, err = db.PutItem(ctx, putItemInput)
+	var output *dynamodb.PutItemOutput
+	if audit := op.client.audit; audit != nil {
+		err = putItemWithAudit(ctx, db, audit, putItemInput)
+	} else {
+		output, err = db.PutItem(ctx, putItemInput)
+	}
 	if err != nil {
-		return err
+		return markConditionFailed(err)
+	}
+	if output != nil {
+		metrics.addResponse(1, output.ConsumedCapacity)
+	} else {
+		metrics.addResponse(1)
 	}
 
 	return nil
@@ -64,13 +79,127 @@ func WithPutItemCondition(conditionExpression string) PutItemOption {
 	}
 }
 
-func WithPutItemExpressionAttributeValues(expressionAttributeValues map[string]types.AttributeValue) PutItemOption {
+// WithPutItemConditionExpression makes the put conditional on expr, e.g.
+// "#version = :version" with names {"#version": "version"} and values {":version": 3}. It's ANDed with
+// any condition from another option. When the condition isn't met, the error is ErrConditionFailed.
+func WithPutItemConditionExpression(expr string, names map[string]string, values map[string]any) PutItemOption {
 	return func(input *dynamodb.PutItemInput) error {
-		input.ExpressionAttributeValues = expressionAttributeValues
+		if expr == "" {
+			return errors.New("condition expression cannot be empty")
+		}
+
+		err := mergePutItemNames(input, names)
+		if err != nil {
+			return err
+		}
+
+		attributeValues, err := mergeConditionValues(input.ExpressionAttributeValues, values)
+		if err != nil {
+			return err
+		}
+		input.ExpressionAttributeValues = attributeValues
+
+		addPutItemCondition(input, expr)
 		return nil
 	}
 }
 
+// WithPutItemIfNotExists only puts the item when there isn't one with its key yet; pass a key attribute,
+// e.g. "id". When there is one, the error is ErrConditionFailed.
+func WithPutItemIfNotExists(attribute string) PutItemOption {
+	return WithPutItemConditionExpression("attribute_not_exists(#ifNotExists)", map[string]string{"#ifNotExists": attribute}, nil)
+}
+
+func addPutItemCondition(input *dynamodb.PutItemInput, expr string) {
+	input.ConditionExpression = andCondition(input.ConditionExpression, expr)
+}
+
+// andCondition returns expr ANDed with condition, or expr when there's no condition yet
+func andCondition(condition *string, expr string) *string {
+	if condition == nil {
+		return aws.String(expr)
+	}
+	return aws.String(fmt.Sprintf("(%s) AND (%s)", *condition, expr))
+}
+
+// WithPutItemExpressionAttributeValues adds values for the placeholders in the condition. They're
+// merged with the values of other options, in any order; a placeholder another option already set to
+// a different value is an error.
+func WithPutItemExpressionAttributeValues(expressionAttributeValues map[string]types.AttributeValue) PutItemOption {
+	return func(input *dynamodb.PutItemInput) error {
+		return mergePutItemValues(input, expressionAttributeValues)
+	}
+}
+
+// WithPutItemExpressionAttributeNames adds names for the placeholders in the condition, merged like
+// WithPutItemExpressionAttributeValues
+func WithPutItemExpressionAttributeNames(expressionAttributeNames map[string]string) PutItemOption {
+	return func(input *dynamodb.PutItemInput) error {
+		return mergePutItemNames(input, expressionAttributeNames)
+	}
+}
+
+func mergePutItemNames(input *dynamodb.PutItemInput, names map[string]string) error {
+	merged, err := mergeExpressionAttributeNames(input.ExpressionAttributeNames, names)
+	if err != nil {
+		return err
+	}
+	input.ExpressionAttributeNames = merged
+	return nil
+}
+
+func mergePutItemValues(input *dynamodb.PutItemInput, values map[string]types.AttributeValue) error {
+	merged, err := mergeExpressionAttributeValues(input.ExpressionAttributeValues, values)
+	if err != nil {
+		return err
+	}
+	input.ExpressionAttributeValues = merged
+	return nil
+}
+
+// mergeExpressionAttributeNames returns existing with names added; a placeholder already set to a
+// different attribute is an error
+func mergeExpressionAttributeNames(existing map[string]string, names map[string]string) (map[string]string, error) {
+	for name, attribute := range names {
+		if current, ok := existing[name]; ok && current != attribute {
+			return nil, fmt.Errorf("expression attribute name %s is already set to %s", name, current)
+		}
+		if existing == nil {
+			existing = map[string]string{}
+		}
+		existing[name] = attribute
+	}
+	return existing, nil
+}
+
+// mergeExpressionAttributeValues returns existing with values added; a placeholder already set to a
+// different value is an error
+func mergeExpressionAttributeValues(existing map[string]types.AttributeValue, values map[string]types.AttributeValue) (map[string]types.AttributeValue, error) {
+	for name, value := range values {
+		if current, ok := existing[name]; ok && !reflect.DeepEqual(current, value) {
+			return nil, fmt.Errorf("expression attribute value %s is already set to a different value", name)
+		}
+		if existing == nil {
+			existing = map[string]types.AttributeValue{}
+		}
+		existing[name] = value
+	}
+	return existing, nil
+}
+
+// mergeConditionValues marshals the values of a condition expression and merges them into existing
+func mergeConditionValues(existing map[string]types.AttributeValue, values map[string]any) (map[string]types.AttributeValue, error) {
+	attributeValues := make(map[string]types.AttributeValue, len(values))
+	for name, value := range values {
+		attributeValue, err := attributevalue.Marshal(value)
+		if err != nil {
+			return nil, kit.WrapError(err, "error marshalling condition value %s", name)
+		}
+		attributeValues[name] = attributeValue
+	}
+	return mergeExpressionAttributeValues(existing, attributeValues)
+}
+
 func WithPutItemTableNameSuffix(suffix string) PutItemOption {
 	return func(input *dynamodb.PutItemInput) error {
 		// Always create a new string to ensure pointer comparison detects change
@@ -21,7 +21,7 @@ func TestPutItem(t *testing.T) {
 
 		err := PutItem(context.Background(), "aTable", item, failingOption)
 
-		assert.EqualError(t, err, "error processing option: option processing failed")
+		assert.EqualError(t, err, "dynamodbkit.PutItem table=aTable: error processing option: option processing failed")
 	})
 
 	t.Run("returns_an_error_when_getting_a_new_dynamodb_connection_returns_an_error", func(t *testing.T) {
@@ -32,7 +32,7 @@ func TestPutItem(t *testing.T) {
 
 		err := PutItem(context.Background(), "aTable", item)
 
-		assert.EqualError(t, err, "error creating DynamoDB client: the fake error")
+		assert.EqualError(t, err, "dynamodbkit.PutItem table=aTable: error creating DynamoDB client: the fake error")
 	})
 
 	t.Run("passes_the_table_name_to_put_item", func(t *testing.T) {
@@ -92,7 +92,7 @@ func TestPutItem(t *testing.T) {
 
 		err := PutItem(context.Background(), "aTable", item)
 
-		assert.EqualError(t, err, "the fake error")
+		assert.EqualError(t, err, "dynamodbkit.PutItem table=aTable: the fake error")
 	})
 
 	t.Run("applies_put_item_options_correctly", func(t *testing.T) {
@@ -162,6 +162,116 @@ func TestWithPutItemCondition(t *testing.T) {
 	})
 }
 
+type failingMarshaler struct{}
+
+func (failingMarshaler) MarshalDynamoDBAttributeValue() (types.AttributeValue, error) {
+	return nil, errors.New("the marshal error")
+}
+
+func TestWithPutItemConditionExpression(t *testing.T) {
+	t.Run("sets_the_condition_names_and_values", func(t *testing.T) {
+		input := &dynamodb.PutItemInput{}
+		option := WithPutItemConditionExpression("#version = :version", map[string]string{"#version": "version"}, map[string]any{":version": 3})
+
+		err := option(input)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "#version = :version", *input.ConditionExpression)
+		assert.Equal(t, map[string]string{"#version": "version"}, input.ExpressionAttributeNames)
+		assert.Equal(t, map[string]types.AttributeValue{":version": &types.AttributeValueMemberN{Value: "3"}}, input.ExpressionAttributeValues)
+	})
+
+	t.Run("ands_the_condition_with_an_existing_one", func(t *testing.T) {
+		input := &dynamodb.PutItemInput{}
+
+		err := WithPutItemIfNotExists("id")(input)
+		assert.NoError(t, err)
+		err = WithPutItemConditionExpression("#status <> :status", map[string]string{"#status": "status"}, map[string]any{":status": "aStatus"})(input)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "(attribute_not_exists(#ifNotExists)) AND (#status <> :status)", *input.ConditionExpression)
+		assert.Equal(t, map[string]string{"#ifNotExists": "id", "#status": "status"}, input.ExpressionAttributeNames)
+	})
+
+	t.Run("returns_an_error_for_an_empty_expression", func(t *testing.T) {
+		err := WithPutItemConditionExpression("", nil, nil)(&dynamodb.PutItemInput{})
+
+		assert.EqualError(t, err, "condition expression cannot be empty")
+	})
+
+	t.Run("returns_an_error_when_a_value_cannot_be_marshalled", func(t *testing.T) {
+		err := WithPutItemConditionExpression("#a = :a", map[string]string{"#a": "a"}, map[string]any{":a": failingMarshaler{}})(&dynamodb.PutItemInput{})
+
+		assert.ErrorContains(t, err, "error marshalling condition value :a")
+	})
+}
+
+func TestWithPutItemIfNotExists(t *testing.T) {
+	t.Run("puts_only_when_the_attribute_does_not_exist", func(t *testing.T) {
+		input := &dynamodb.PutItemInput{}
+
+		err := WithPutItemIfNotExists("theKeyAttribute")(input)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "attribute_not_exists(#ifNotExists)", *input.ConditionExpression)
+		assert.Equal(t, map[string]string{"#ifNotExists": "theKeyAttribute"}, input.ExpressionAttributeNames)
+		assert.Nil(t, input.ExpressionAttributeValues)
+	})
+
+	t.Run("returns_err_condition_failed_when_the_item_exists", func(t *testing.T) {
+		fakeDB := &FakeDynamoDB{
+			PutItemFake: func(ctx context.Context, params *dynamodb.PutItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.PutItemOutput, error) {
+				return nil, &types.ConditionalCheckFailedException{Message: aws.String("the condition message")}
+			},
+		}
+		setFake(func(ctx context.Context) (DynamoDB, error) { return fakeDB, nil })
+		t.Cleanup(func() { setFake(nil) })
+
+		err := PutItem(context.Background(), "aTable", TestUser{ID: "anID"}, WithPutItemIfNotExists("id"))
+
+		assert.ErrorIs(t, err, ErrConditionFailed)
+		var conditionErr *types.ConditionalCheckFailedException
+		assert.ErrorAs(t, err, &conditionErr)
+	})
+
+	t.Run("returns_err_condition_failed_when_an_audited_put_fails_its_condition", func(t *testing.T) {
+		useTestAuditing(t)
+		fakeDB := &FakeDynamoDB{
+			DescribeTableFake: describeTableWithKeys("id"),
+			GetItemFake: func(ctx context.Context, params *dynamodb.GetItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.GetItemOutput, error) {
+				return &dynamodb.GetItemOutput{}, nil
+			},
+			TransactWriteItemsFake: func(ctx context.Context, params *dynamodb.TransactWriteItemsInput, optFns ...func(*dynamodb.Options)) (*dynamodb.TransactWriteItemsOutput, error) {
+				return nil, &types.TransactionCanceledException{CancellationReasons: []types.CancellationReason{
+					{Code: aws.String("ConditionalCheckFailed")},
+					{Code: aws.String("None")},
+				}}
+			},
+		}
+		setFake(func(ctx context.Context) (DynamoDB, error) { return fakeDB, nil })
+		t.Cleanup(func() { setFake(nil) })
+
+		err := PutItem(context.Background(), "anAuditedConditionTable", TestUser{ID: "anID"}, WithPutItemIfNotExists("id"))
+
+		assert.ErrorIs(t, err, ErrConditionFailed)
+	})
+
+	t.Run("does_not_return_err_condition_failed_for_other_errors", func(t *testing.T) {
+		fakeDB := &FakeDynamoDB{
+			PutItemFake: func(ctx context.Context, params *dynamodb.PutItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.PutItemOutput, error) {
+				return nil, errors.New("the fake error")
+			},
+		}
+		setFake(func(ctx context.Context) (DynamoDB, error) { return fakeDB, nil })
+		t.Cleanup(func() { setFake(nil) })
+
+		err := PutItem(context.Background(), "aTable", TestUser{ID: "anID"}, WithPutItemIfNotExists("id"))
+
+		assert.Error(t, err)
+		assert.NotErrorIs(t, err, ErrConditionFailed)
+	})
+}
+
 func TestWithPutItemExpressionAttributeValues(t *testing.T) {
 	t.Run("sets_expression_attribute_values_when_given_map", func(t *testing.T) {
 		input := &dynamodb.PutItemInput{}
@@ -179,16 +289,65 @@ func TestWithPutItemExpressionAttributeValues(t *testing.T) {
 		assert.Contains(t, input.ExpressionAttributeValues, ":age")
 	})
 
-	t.Run("sets_empty_map_when_given_empty_map", func(t *testing.T) {
+	t.Run("leaves_the_values_unset_when_given_an_empty_map", func(t *testing.T) {
 		input := &dynamodb.PutItemInput{}
-		values := map[string]types.AttributeValue{}
-		option := WithPutItemExpressionAttributeValues(values)
+		option := WithPutItemExpressionAttributeValues(map[string]types.AttributeValue{})
 
 		err := option(input)
 
 		assert.NoError(t, err)
-		assert.Equal(t, values, input.ExpressionAttributeValues)
-		assert.Len(t, input.ExpressionAttributeValues, 0)
+		assert.Nil(t, input.ExpressionAttributeValues)
+	})
+
+	t.Run("merges_with_the_values_of_other_options_in_either_order", func(t *testing.T) {
+		theValues := map[string]types.AttributeValue{":name": &types.AttributeValueMemberS{Value: "aName"}}
+		theCondition := WithPutItemConditionExpression("#version = :version", map[string]string{"#version": "version"}, map[string]any{":version": 3})
+		expected := map[string]types.AttributeValue{
+			":name":    &types.AttributeValueMemberS{Value: "aName"},
+			":version": &types.AttributeValueMemberN{Value: "3"},
+		}
+
+		valuesFirst := &dynamodb.PutItemInput{}
+		assert.NoError(t, WithPutItemExpressionAttributeValues(theValues)(valuesFirst))
+		assert.NoError(t, theCondition(valuesFirst))
+		conditionFirst := &dynamodb.PutItemInput{}
+		assert.NoError(t, theCondition(conditionFirst))
+		assert.NoError(t, WithPutItemExpressionAttributeValues(theValues)(conditionFirst))
+
+		assert.Equal(t, expected, valuesFirst.ExpressionAttributeValues)
+		assert.Equal(t, expected, conditionFirst.ExpressionAttributeValues)
+		assert.Equal(t, map[string]string{"#version": "version"}, valuesFirst.ExpressionAttributeNames)
+		assert.Equal(t, map[string]string{"#version": "version"}, conditionFirst.ExpressionAttributeNames)
+	})
+
+	t.Run("returns_an_error_when_another_option_set_the_placeholder_to_a_different_value", func(t *testing.T) {
+		input := &dynamodb.PutItemInput{}
+		assert.NoError(t, WithPutItemConditionExpression("#version = :version", map[string]string{"#version": "version"}, map[string]any{":version": 3})(input))
+
+		err := WithPutItemExpressionAttributeValues(map[string]types.AttributeValue{":version": &types.AttributeValueMemberN{Value: "4"}})(input)
+
+		assert.EqualError(t, err, "expression attribute value :version is already set to a different value")
+	})
+}
+
+func TestWithPutItemExpressionAttributeNames(t *testing.T) {
+	t.Run("merges_with_the_names_of_other_options", func(t *testing.T) {
+		input := &dynamodb.PutItemInput{}
+		assert.NoError(t, WithPutItemIfNotExists("id")(input))
+
+		err := WithPutItemExpressionAttributeNames(map[string]string{"#name": "name"})(input)
+
+		assert.NoError(t, err)
+		assert.Equal(t, map[string]string{"#ifNotExists": "id", "#name": "name"}, input.ExpressionAttributeNames)
+	})
+
+	t.Run("returns_an_error_when_another_option_set_the_placeholder_to_a_different_name", func(t *testing.T) {
+		input := &dynamodb.PutItemInput{}
+		assert.NoError(t, WithPutItemIfNotExists("id")(input))
+
+		err := WithPutItemExpressionAttributeNames(map[string]string{"#ifNotExists": "email"})(input)
+
+		assert.EqualError(t, err, "expression attribute name #ifNotExists is already set to id")
 	})
 }
 
@@ -2,33 +2,240 @@ package dynamodbkit
 
 import (
 	"context"
-	"encoding/base64"
-	"encoding/json"
+	"errors"
 	"fmt"
+	"iter"
+	"strings"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
 	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/expression"
 	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
 	"github.com/half-ogre/go-kit/kit"
 )
 
-func Query[TItem any, TPartitionKey string | int](ctx context.Context, tableName string, partitionKey string, partitionKeyValue TPartitionKey, options ...QueryOption) (*QueryOutput[TItem], error) {
+func Query[TItem any, TPartitionKey string | int](ctx context.Context, tableName string, partitionKey string, partitionKeyValue TPartitionKey, options ...QueryOption) (_ *QueryOutput[TItem], err error) {
+	op := newOperation(ctx, "Query", tableName)
+	defer op.wrap(&err)
+
+	db, queryInput, err := prepareQuery(ctx, op, partitionKey, partitionKeyValue, options)
+	if err != nil {
+		return nil, err
+	}
+
+	metrics := startOperation(op, "Query", queryInput.TableName)
+	queryInput.ReturnConsumedCapacity = op.client.returnConsumedCapacity(metrics)
+
+	page, err := queryPage[TItem](ctx, db, queryInput)
+	addPage(metrics, page)
+	metrics.finish(ctx, err)
+	if err != nil {
+		return nil, err
+	}
+
+	lastEvaluatedKey, err := encodeLastEvaluatedKey(page.lastEvaluatedKey)
+	if err != nil {
+		return nil, err
+	}
+
+	return &QueryOutput[TItem]{Items: page.items, LastEvaluatedKey: lastEvaluatedKey}, nil
+}
+
+// QueryAll queries every page of a partition, following LastEvaluatedKey, and returns all the items.
+// It takes QueryOptions and PageOptions. If it stops early, because of WithMaxPages, WithMaxItems, or
+// ctx being done between pages, the items read so far are returned with a LastEvaluatedKey to resume
+// from with WithQueryExclusiveStartKey; a done ctx is also returned as the error. When a page after the
+// first fails, e.g. because ctx is canceled mid-request, the items read before it are returned along
+// with the error and a LastEvaluatedKey that resumes at the failed page. With UseInstrumentation it
+// reports the pages as one QueryAll operation.
+func QueryAll[TItem any, TPartitionKey string | int](ctx context.Context, tableName string, partitionKey string, partitionKeyValue TPartitionKey, options ...QueryAllOption) (_ *QueryOutput[TItem], err error) {
+	op := newOperation(ctx, "QueryAll", tableName)
+	defer op.wrap(&err)
+
+	config := &queryAllConfig{}
+	for _, option := range options {
+		option.applyQueryAll(config)
+	}
+
+	db, queryInput, err := prepareQuery(ctx, op, partitionKey, partitionKeyValue, config.queryOptions)
+	if err != nil {
+		return nil, err
+	}
+
+	metrics := startOperation(op, "QueryAll", queryInput.TableName)
+	queryInput.ReturnConsumedCapacity = op.client.returnConsumedCapacity(metrics)
+
+	limit := queryInput.Limit
+	result := &QueryOutput[TItem]{Items: make([]TItem, 0)}
+	for pagesRead := 1; ; pagesRead++ {
+		queryInput.Limit = config.paging.pageLimit(limit, len(result.Items))
+		page, err := queryPage[TItem](ctx, db, queryInput)
+		addPage(metrics, page)
+		if err != nil {
+			metrics.finish(ctx, err)
+			if pagesRead == 1 {
+				return nil, err
+			}
+
+			// The items read so far are still good, so they're returned with a cursor to the failed page
+			var keyErr error
+			result.LastEvaluatedKey, keyErr = encodeLastEvaluatedKey(queryInput.ExclusiveStartKey)
+			if keyErr != nil {
+				return nil, keyErr
+			}
+			return result, kit.WrapError(err, "stopped querying at page %d", pagesRead)
+		}
+
+		result.Items = append(result.Items, page.items...)
+
+		if page.lastEvaluatedKey == nil {
+			metrics.finish(ctx, nil)
+			return result, nil
+		}
+		queryInput.ExclusiveStartKey = page.lastEvaluatedKey
+
+		stop, stopErr := config.paging.beforeNextPage(ctx, pagesRead, len(result.Items))
+		if stop {
+			if stopErr != nil {
+				stopErr = kit.WrapError(stopErr, "stopped querying after page %d", pagesRead)
+			}
+			metrics.finish(ctx, stopErr)
+			result.LastEvaluatedKey, err = encodeLastEvaluatedKey(page.lastEvaluatedKey)
+			if err != nil {
+				return nil, err
+			}
+			return result, stopErr
+		}
+	}
+}
+
+// QueryItems returns an iterator over every item in a partition, reading a page at a time as the loop
+// asks for more instead of holding every item like QueryAll. It takes QueryOptions and PageOptions, and
+// ends quietly at a page or item limit. An error, including ctx being done between pages, is yielded
+// once and ends the iteration.
+func QueryItems[TItem any, TPartitionKey string | int](ctx context.Context, tableName string, partitionKey string, partitionKeyValue TPartitionKey, options ...QueryAllOption) iter.Seq2[TItem, error] {
+	return func(yield func(TItem, error) bool) {
+		var zero TItem
+		op := newOperation(ctx, "QueryItems", tableName)
+		fail := func(err error) {
+			op.wrap(&err)
+			yield(zero, err)
+		}
+
+		config := &queryAllConfig{}
+		for _, option := range options {
+			option.applyQueryAll(config)
+		}
+
+		db, queryInput, err := prepareQuery(ctx, op, partitionKey, partitionKeyValue, config.queryOptions)
+		if err != nil {
+			fail(err)
+			return
+		}
+
+		metrics := startOperation(op, "QueryItems", queryInput.TableName)
+		queryInput.ReturnConsumedCapacity = op.client.returnConsumedCapacity(metrics)
+
+		limit := queryInput.Limit
+		itemsRead := 0
+		for pagesRead := 1; ; pagesRead++ {
+			queryInput.Limit = config.paging.pageLimit(limit, itemsRead)
+			page, err := queryPage[TItem](ctx, db, queryInput)
+			addPage(metrics, page)
+			if err != nil {
+				metrics.finish(ctx, err)
+				fail(err)
+				return
+			}
+
+			for _, item := range page.items {
+				if !yield(item, nil) {
+					metrics.finish(ctx, nil)
+					return
+				}
+			}
+			itemsRead += len(page.items)
+
+			if page.lastEvaluatedKey == nil {
+				metrics.finish(ctx, nil)
+				return
+			}
+			queryInput.ExclusiveStartKey = page.lastEvaluatedKey
+
+			stop, stopErr := config.paging.beforeNextPage(ctx, pagesRead, itemsRead)
+			if stop {
+				metrics.finish(ctx, stopErr)
+				if stopErr != nil {
+					fail(kit.WrapError(stopErr, "stopped querying after page %d", pagesRead))
+				}
+				return
+			}
+		}
+	}
+}
+
+// QueryCount returns how many items are in a partition, or match its options' key condition and filter,
+// with Select=COUNT so DynamoDB returns only the count of each page instead of the items. It follows
+// LastEvaluatedKey, adding up every page; WithQueryLimit sets how many items each page evaluates.
+func QueryCount[TPartitionKey string | int](ctx context.Context, tableName string, partitionKey string, partitionKeyValue TPartitionKey, options ...QueryOption) (_ int64, err error) {
+	op := newOperation(ctx, "QueryCount", tableName)
+	defer op.wrap(&err)
+
+	db, queryInput, err := prepareQuery(ctx, op, partitionKey, partitionKeyValue, options)
+	if err != nil {
+		return 0, err
+	}
+	if queryInput.ProjectionExpression != nil {
+		return 0, errors.New("projection expression cannot be used when counting")
+	}
+	queryInput.Select = types.SelectCount
+
+	metrics := startOperation(op, "QueryCount", queryInput.TableName)
+	defer func() { metrics.finish(ctx, err) }()
+	queryInput.ReturnConsumedCapacity = op.client.returnConsumedCapacity(metrics)
+
+	var count int64
+	for {
+		logQueryInput(ctx, queryInput)
+
+		output, err := db.Query(ctx, queryInput)
+		if err != nil {
+			return 0, kit.WrapError(err, "error counting")
+		}
+		metrics.addResponse(0, output.ConsumedCapacity)
+		count += int64(output.Count)
+
+		if output.LastEvaluatedKey == nil {
+			return count, nil
+		}
+		queryInput.ExclusiveStartKey = output.LastEvaluatedKey
+	}
+}
+
+func prepareQuery[TPartitionKey string | int](ctx context.Context, op *operation, partitionKey string, partitionKeyValue TPartitionKey, options []QueryOption) (DynamoDB, *dynamodb.QueryInput, error) {
 	if ctx == nil {
-		return nil, kit.WrapError(nil, "context cannot be nil")
+		return nil, nil, errors.New("context cannot be nil")
 	}
 
+	tableName := op.table
 	if tableName == "" {
-		return nil, kit.WrapError(nil, "table name cannot be empty")
+		return nil, nil, errors.New("table name cannot be empty")
 	}
 
 	if partitionKey == "" {
-		return nil, kit.WrapError(nil, "partition key cannot be empty")
+		return nil, nil, errors.New("partition key cannot be empty")
+	}
+
+	partitionKeyAttributeValue, err := getKeyAttributeValue(partitionKeyValue)
+	if err != nil {
+		return nil, nil, err
 	}
+	op.setKey(partitionKey, map[string]types.AttributeValue{partitionKey: partitionKeyAttributeValue})
 
-	db, err := newDynamoDB(ctx)
+	db, err := op.dynamoDB(ctx)
 	if err != nil {
-		return nil, kit.WrapError(err, "error creating DynamoDB client")
+		return nil, nil, kit.WrapError(err, "error creating DynamoDB client")
 	}
 
 	keyConditionExpr := expression.Key(partitionKey).Equal(expression.Value(partitionKeyValue))
@@ -37,7 +244,7 @@ func Query[TItem any, TPartitionKey string | int](ctx context.Context, tableName
 		Build()
 
 	if err != nil {
-		return nil, kit.WrapError(err, "error building expression")
+		return nil, nil, kit.WrapError(err, "error building expression")
 	}
 
 	queryInput := &dynamodb.QueryInput{
@@ -52,25 +259,30 @@ func Query[TItem any, TPartitionKey string | int](ctx context.Context, tableName
 	for _, option := range options {
 		err = option(queryInput)
 		if err != nil {
-			return nil, kit.WrapError(err, "error processing option")
+			return nil, nil, kit.WrapError(err, "error processing option")
 		}
 	}
 
-	// Apply global table name suffix if table name pointer wasn't changed by options
-	if queryInput.TableName == originalTableNamePtr {
-		globalSuffix := getTableNameSuffix()
-		if globalSuffix != "" {
-			queryInput.TableName = aws.String(fmt.Sprintf("%s%s", *queryInput.TableName, globalSuffix))
-		}
-	}
+	// Apply the client's table name prefix, and its suffix if table name pointer wasn't changed by options
+	queryInput.TableName = op.client.qualifyTableName(queryInput.TableName, originalTableNamePtr)
+	op.table = *queryInput.TableName
+	op.index = aws.ToString(queryInput.IndexName)
+
+	return db, queryInput, nil
+}
+
+func queryPage[TItem any](ctx context.Context, db DynamoDB, queryInput *dynamodb.QueryInput) (*page[TItem], error) {
+	logQueryInput(ctx, queryInput)
 
 	output, err := db.Query(ctx, queryInput)
 	if err != nil {
-		return nil, kit.WrapError(err, "error querying table %s", *queryInput.TableName)
+		return nil, kit.WrapError(err, "error querying")
 	}
 
-	result := &QueryOutput[TItem]{
-		Items: make([]TItem, 0),
+	result := &page[TItem]{
+		items:            make([]TItem, 0, len(output.Items)),
+		lastEvaluatedKey: output.LastEvaluatedKey,
+		consumedCapacity: output.ConsumedCapacity,
 	}
 
 	for _, i := range output.Items {
@@ -81,24 +293,7 @@ func Query[TItem any, TPartitionKey string | int](ctx context.Context, tableName
 			return nil, kit.WrapError(err, "error unmarshalling queried item")
 		}
 
-		result.Items = append(result.Items, item)
-	}
-
-	if output.LastEvaluatedKey != nil {
-		var lastEvaluatedKey any
-		err := attributevalue.UnmarshalMap(output.LastEvaluatedKey, &lastEvaluatedKey)
-		if err != nil {
-			return nil, kit.WrapError(err, "failed to unmarshal LastEvaluatedKey map %v", output.LastEvaluatedKey)
-		}
-
-		jsonBytes, err := json.Marshal(lastEvaluatedKey)
-		if err != nil {
-			return nil, kit.WrapError(err, "failed to marshal LastEvaluatedKey %v to JSON", output.LastEvaluatedKey)
-		}
-
-		encodedJson := base64.StdEncoding.EncodeToString(jsonBytes)
-
-		result.LastEvaluatedKey = &encodedJson
+		result.items = append(result.items, item)
 	}
 
 	return result, nil
@@ -109,24 +304,27 @@ type QueryOutput[TItem any] struct {
 	Items            []TItem
 }
 
+// Page returns the output as a kit.Page, with LastEvaluatedKey as its cursor
+func (o *QueryOutput[TItem]) Page() kit.Page[TItem] {
+	return kit.NewPage(o.Items, o.LastEvaluatedKey)
+}
+
 type QueryOption func(*dynamodb.QueryInput) error
 
-func WithQueryExclusiveStartKey(exclusiveStartKey string) QueryOption {
+// WithQueryExclusiveStartKey resumes from a LastEvaluatedKey. With pagination token options it instead
+// resumes from a token made by NewPaginationToken with the same options, rejecting one that is forged,
+// edited, or expired.
+func WithQueryExclusiveStartKey(exclusiveStartKey string, options ...PaginationTokenOption) QueryOption {
 	return func(input *dynamodb.QueryInput) error {
-		decodedJson, err := base64.StdEncoding.DecodeString(exclusiveStartKey)
-		if err != nil {
-			return kit.WrapError(err, "failed to decode exclusiveStartKey %s", exclusiveStartKey)
-		}
-
-		var v interface{}
-		err = json.Unmarshal(decodedJson, &v)
-		if err != nil {
-			return kit.WrapError(err, "failed to unmarshal exclusiveStartKey JSON %s", decodedJson)
+		var k map[string]types.AttributeValue
+		var err error
+		if len(options) > 0 {
+			k, err = decodePaginationToken(exclusiveStartKey, options)
+		} else {
+			k, err = decodeExclusiveStartKey(exclusiveStartKey)
 		}
-
-		k, err := attributevalue.MarshalMap(v)
 		if err != nil {
-			return kit.WrapError(err, "failed to unmarshal exclusiveStartKey JSON %s", decodedJson)
+			return err
 		}
 
 		input.ExclusiveStartKey = k
@@ -134,6 +332,9 @@ func WithQueryExclusiveStartKey(exclusiveStartKey string) QueryOption {
 	}
 }
 
+// WithQueryIndexName queries a global or local secondary index instead of the table. partitionKey, and
+// the key in any sort key condition, are then the index's keys. A page's LastEvaluatedKey includes the
+// index keys, so it resumes the same index query with WithQueryExclusiveStartKey.
 func WithQueryIndexName(indexName string) QueryOption {
 	return func(input *dynamodb.QueryInput) error {
 		input.IndexName = aws.String(indexName)
@@ -144,16 +345,18 @@ func WithQueryIndexName(indexName string) QueryOption {
 func WithQueryLimit(limit int64) QueryOption {
 	return func(input *dynamodb.QueryInput) error {
 		if limit < 0 {
-			return kit.WrapError(nil, "limit must be non-negative, got %d", limit)
+			return fmt.Errorf("limit must be non-negative, got %d", limit)
 		}
 		if limit > 2147483647 { // int32 max
-			return kit.WrapError(nil, "limit exceeds maximum allowed value, got %d", limit)
+			return fmt.Errorf("limit exceeds maximum allowed value, got %d", limit)
 		}
 		input.Limit = aws.Int32(int32(limit))
 		return nil
 	}
 }
 
+// WithQueryProjectionExpression only returns the attributes in projectionExpression, which must use
+// placeholders for reserved words; WithQueryProjectedAttributes adds them itself
 func WithQueryProjectionExpression(projectionExpression string) QueryOption {
 	return func(input *dynamodb.QueryInput) error {
 		input.ProjectionExpression = aws.String(projectionExpression)
@@ -161,6 +364,102 @@ func WithQueryProjectionExpression(projectionExpression string) QueryOption {
 	}
 }
 
+// WithQuerySortKeyBeginsWith adds begins_with(sortKey, prefix) to the key condition, e.g. to query one
+// geohash cell or all items with a "ORDER#" sort key prefix
+func WithQuerySortKeyBeginsWith(sortKey string, prefix string) QueryOption {
+	return func(input *dynamodb.QueryInput) error {
+		return addSortKeyCondition(input, sortKey, "#sortKeyBeginsWith", "begins_with(#sortKeyBeginsWith, :sortKeyBeginsWith)", map[string]types.AttributeValue{
+			":sortKeyBeginsWith": &types.AttributeValueMemberS{Value: prefix},
+		})
+	}
+}
+
+// WithQuerySortKeyEquals adds sortKey = value to the key condition
+func WithQuerySortKeyEquals[TSortKey string | int](sortKey string, value TSortKey) QueryOption {
+	return withQuerySortKeyComparison(sortKey, "=", value)
+}
+
+// WithQuerySortKeyLessThan adds sortKey < value to the key condition
+func WithQuerySortKeyLessThan[TSortKey string | int](sortKey string, value TSortKey) QueryOption {
+	return withQuerySortKeyComparison(sortKey, "<", value)
+}
+
+// WithQuerySortKeyLessThanOrEqual adds sortKey <= value to the key condition
+func WithQuerySortKeyLessThanOrEqual[TSortKey string | int](sortKey string, value TSortKey) QueryOption {
+	return withQuerySortKeyComparison(sortKey, "<=", value)
+}
+
+// WithQuerySortKeyGreaterThan adds sortKey > value to the key condition
+func WithQuerySortKeyGreaterThan[TSortKey string | int](sortKey string, value TSortKey) QueryOption {
+	return withQuerySortKeyComparison(sortKey, ">", value)
+}
+
+// WithQuerySortKeyGreaterThanOrEqual adds sortKey >= value to the key condition
+func WithQuerySortKeyGreaterThanOrEqual[TSortKey string | int](sortKey string, value TSortKey) QueryOption {
+	return withQuerySortKeyComparison(sortKey, ">=", value)
+}
+
+// WithQuerySortKeyBetween adds sortKey BETWEEN low AND high to the key condition; both ends are included
+func WithQuerySortKeyBetween[TSortKey string | int](sortKey string, low TSortKey, high TSortKey) QueryOption {
+	return func(input *dynamodb.QueryInput) error {
+		lowValue, err := getKeyAttributeValue(low)
+		if err != nil {
+			return err
+		}
+		highValue, err := getKeyAttributeValue(high)
+		if err != nil {
+			return err
+		}
+
+		return addSortKeyCondition(input, sortKey, "#sortKey", "#sortKey BETWEEN :sortKeyLow AND :sortKeyHigh", map[string]types.AttributeValue{
+			":sortKeyLow":  lowValue,
+			":sortKeyHigh": highValue,
+		})
+	}
+}
+
+func withQuerySortKeyComparison[TSortKey string | int](sortKey string, operator string, value TSortKey) QueryOption {
+	return func(input *dynamodb.QueryInput) error {
+		attributeValue, err := getKeyAttributeValue(value)
+		if err != nil {
+			return err
+		}
+
+		return addSortKeyCondition(input, sortKey, "#sortKey", "#sortKey "+operator+" :sortKey", map[string]types.AttributeValue{
+			":sortKey": attributeValue,
+		})
+	}
+}
+
+// addSortKeyCondition ANDs condition, which refers to the sort key as name, onto the partition key
+// condition. DynamoDB allows one condition on the sort key.
+func addSortKeyCondition(input *dynamodb.QueryInput, sortKey string, name string, condition string, values map[string]types.AttributeValue) error {
+	if sortKey == "" {
+		return errors.New("sort key cannot be empty")
+	}
+	if input.KeyConditionExpression == nil {
+		return errors.New("key condition expression cannot be empty")
+	}
+	for existing := range input.ExpressionAttributeNames {
+		if strings.HasPrefix(existing, "#sortKey") {
+			return errors.New("key condition already has a sort key condition")
+		}
+	}
+
+	if input.ExpressionAttributeNames == nil {
+		input.ExpressionAttributeNames = map[string]string{}
+	}
+	if input.ExpressionAttributeValues == nil {
+		input.ExpressionAttributeValues = map[string]types.AttributeValue{}
+	}
+	input.ExpressionAttributeNames[name] = sortKey
+	for placeholder, value := range values {
+		input.ExpressionAttributeValues[placeholder] = value
+	}
+	input.KeyConditionExpression = aws.String(*input.KeyConditionExpression + " AND " + condition)
+	return nil
+}
+
 func WithQueryTableNameSuffix(suffix string) QueryOption {
 	return func(input *dynamodb.QueryInput) error {
 		// Always create a new string to ensure pointer comparison detects change
@@ -3,12 +3,15 @@ package dynamodbkit
 import (
 	"context"
 	"errors"
+	"fmt"
 	"testing"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
 	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
 	"github.com/stretchr/testify/assert"
+
+	"github.com/half-ogre/go-kit/kit"
 )
 
 func TestQuery(t *testing.T) {
@@ -44,7 +47,7 @@ func TestQuery(t *testing.T) {
 		result, err := Query[TestUser](context.Background(), "aTable", "id", "aUserID")
 
 		assert.Nil(t, result)
-		assert.EqualError(t, err, "error creating DynamoDB client: the fake error")
+		assert.EqualError(t, err, "dynamodbkit.Query table=aTable id=aUserID: error creating DynamoDB client: the fake error")
 	})
 
 	t.Run("passes_the_table_name_to_query", func(t *testing.T) {
@@ -128,7 +131,7 @@ func TestQuery(t *testing.T) {
 		result, err := Query[TestUser](context.Background(), "aTable", "id", "aUserID")
 
 		assert.Nil(t, result)
-		assert.EqualError(t, err, "error querying table aTable: the fake error")
+		assert.EqualError(t, err, "dynamodbkit.Query table=aTable id=aUserID: error querying: the fake error")
 	})
 
 	t.Run("returns_empty_results_when_query_returns_no_items", func(t *testing.T) {
@@ -192,7 +195,7 @@ func TestQuery(t *testing.T) {
 		result, err := Query[TestUser](context.Background(), "aTable", "id", "aUserID")
 
 		assert.Nil(t, result)
-		assert.EqualError(t, err, "error unmarshalling queried item: unmarshal failed, cannot unmarshal list into Go value type string")
+		assert.EqualError(t, err, "dynamodbkit.Query table=aTable id=aUserID: error unmarshalling queried item: unmarshal failed, cannot unmarshal list into Go value type string")
 	})
 
 	t.Run("applies_query_options_correctly", func(t *testing.T) {
@@ -232,7 +235,7 @@ func TestQuery(t *testing.T) {
 		result, err := Query[TestUser](context.Background(), "aTable", "id", "aUserID", failingOption)
 
 		assert.Nil(t, result)
-		assert.EqualError(t, err, "error processing option: option processing failed")
+		assert.EqualError(t, err, "dynamodbkit.Query table=aTable id=aUserID: error processing option: option processing failed")
 	})
 
 	t.Run("succeeds_when_no_errors", func(t *testing.T) {
@@ -325,6 +328,140 @@ func TestQuery(t *testing.T) {
 	})
 }
 
+func TestQueryAll(t *testing.T) {
+	t.Run("follows_last_evaluated_key_until_the_last_page", func(t *testing.T) {
+		var actualStartKeys []map[string]types.AttributeValue
+		fakeDB := &FakeDynamoDB{
+			QueryFake: func(ctx context.Context, params *dynamodb.QueryInput, optFns ...func(*dynamodb.Options)) (*dynamodb.QueryOutput, error) {
+				actualStartKeys = append(actualStartKeys, params.ExclusiveStartKey)
+				if params.ExclusiveStartKey == nil {
+					return &dynamodb.QueryOutput{
+						Items:            []map[string]types.AttributeValue{mustMarshalMap(t, TestUser{ID: "theID", Name: "theFirstName"})},
+						LastEvaluatedKey: map[string]types.AttributeValue{"id": &types.AttributeValueMemberS{Value: "theID"}},
+					}, nil
+				}
+				return &dynamodb.QueryOutput{
+					Items: []map[string]types.AttributeValue{mustMarshalMap(t, TestUser{ID: "theID", Name: "theSecondName"})},
+				}, nil
+			},
+		}
+		setFake(func(ctx context.Context) (DynamoDB, error) { return fakeDB, nil })
+		t.Cleanup(func() { setFake(nil) })
+
+		result, err := QueryAll[TestUser](context.Background(), "aTable", "id", "theID")
+
+		assert.NoError(t, err)
+		assert.Equal(t, []TestUser{{ID: "theID", Name: "theFirstName"}, {ID: "theID", Name: "theSecondName"}}, result.Items)
+		assert.Nil(t, result.LastEvaluatedKey)
+		assert.Equal(t, []map[string]types.AttributeValue{nil, {"id": &types.AttributeValueMemberS{Value: "theID"}}}, actualStartKeys)
+	})
+
+	t.Run("returns_an_error_when_a_page_fails", func(t *testing.T) {
+		fakeDB := &FakeDynamoDB{
+			QueryFake: func(ctx context.Context, params *dynamodb.QueryInput, optFns ...func(*dynamodb.Options)) (*dynamodb.QueryOutput, error) {
+				return nil, errors.New("the query error")
+			},
+		}
+		setFake(func(ctx context.Context) (DynamoDB, error) { return fakeDB, nil })
+		t.Cleanup(func() { setFake(nil) })
+
+		result, err := QueryAll[TestUser](context.Background(), "theTableName", "id", "theID")
+
+		assert.Nil(t, result)
+		assert.EqualError(t, err, "dynamodbkit.QueryAll table=theTableName id=theID: error querying: the query error")
+	})
+
+	t.Run("returns_an_error_when_partition_key_is_empty", func(t *testing.T) {
+		result, err := QueryAll[TestUser](context.Background(), "aTable", "", "theID")
+
+		assert.Nil(t, result)
+		assert.ErrorContains(t, err, "partition key cannot be empty")
+	})
+}
+
+func TestQueryCount(t *testing.T) {
+	t.Run("adds_up_the_count_of_every_page", func(t *testing.T) {
+		var actualInputs []dynamodb.QueryInput
+		fakeDB := &FakeDynamoDB{
+			QueryFake: func(ctx context.Context, params *dynamodb.QueryInput, optFns ...func(*dynamodb.Options)) (*dynamodb.QueryOutput, error) {
+				actualInputs = append(actualInputs, *params)
+				if params.ExclusiveStartKey == nil {
+					return &dynamodb.QueryOutput{
+						Count:            3,
+						LastEvaluatedKey: map[string]types.AttributeValue{"id": &types.AttributeValueMemberS{Value: "theID"}},
+					}, nil
+				}
+				return &dynamodb.QueryOutput{Count: 2}, nil
+			},
+		}
+		setFake(func(ctx context.Context) (DynamoDB, error) { return fakeDB, nil })
+		t.Cleanup(func() { setFake(nil) })
+
+		count, err := QueryCount(context.Background(), "theTableName", "id", "theID", WithQuerySortKeyBeginsWith("sk", "ORDER#"))
+
+		assert.NoError(t, err)
+		assert.Equal(t, int64(5), count)
+		assert.Len(t, actualInputs, 2)
+		assert.Equal(t, types.SelectCount, actualInputs[0].Select)
+		assert.Equal(t, "theTableName", *actualInputs[0].TableName)
+		assert.Contains(t, *actualInputs[0].KeyConditionExpression, "begins_with")
+		assert.Equal(t, map[string]types.AttributeValue{"id": &types.AttributeValueMemberS{Value: "theID"}}, actualInputs[1].ExclusiveStartKey)
+	})
+
+	t.Run("reports_one_operation_for_every_page", func(t *testing.T) {
+		fakeDB := &FakeDynamoDB{
+			QueryFake: func(ctx context.Context, params *dynamodb.QueryInput, optFns ...func(*dynamodb.Options)) (*dynamodb.QueryOutput, error) {
+				output := &dynamodb.QueryOutput{Count: 1, ConsumedCapacity: &types.ConsumedCapacity{CapacityUnits: aws.Float64(0.5)}}
+				if params.ExclusiveStartKey == nil {
+					output.LastEvaluatedKey = map[string]types.AttributeValue{"id": &types.AttributeValueMemberS{Value: "theID"}}
+				}
+				return output, nil
+			},
+		}
+		setFake(func(ctx context.Context) (DynamoDB, error) { return fakeDB, nil })
+		var actualMetrics []OperationMetrics
+		UseInstrumentation(func(ctx context.Context, metrics OperationMetrics) {
+			actualMetrics = append(actualMetrics, metrics)
+		})
+		t.Cleanup(func() {
+			setFake(nil)
+			UseInstrumentation(nil)
+		})
+
+		_, err := QueryCount(context.Background(), "theTableName", "id", "theID")
+
+		assert.NoError(t, err)
+		assert.Len(t, actualMetrics, 1)
+		assert.Equal(t, "QueryCount", actualMetrics[0].Operation)
+		assert.Equal(t, 2, actualMetrics[0].Pages)
+		assert.Equal(t, 1.0, actualMetrics[0].ConsumedCapacity)
+	})
+
+	t.Run("returns_an_error_when_a_page_fails", func(t *testing.T) {
+		fakeDB := &FakeDynamoDB{
+			QueryFake: func(ctx context.Context, params *dynamodb.QueryInput, optFns ...func(*dynamodb.Options)) (*dynamodb.QueryOutput, error) {
+				return nil, errors.New("the query error")
+			},
+		}
+		setFake(func(ctx context.Context) (DynamoDB, error) { return fakeDB, nil })
+		t.Cleanup(func() { setFake(nil) })
+
+		count, err := QueryCount(context.Background(), "theTableName", "id", "theID")
+
+		assert.Zero(t, count)
+		assert.EqualError(t, err, "dynamodbkit.QueryCount table=theTableName id=theID: error counting: the query error")
+	})
+
+	t.Run("returns_an_error_with_a_projection_expression", func(t *testing.T) {
+		setFake(func(ctx context.Context) (DynamoDB, error) { return &FakeDynamoDB{}, nil })
+		t.Cleanup(func() { setFake(nil) })
+
+		_, err := QueryCount(context.Background(), "theTableName", "id", "theID", WithQueryProjectionExpression("id"))
+
+		assert.ErrorContains(t, err, "projection expression cannot be used when counting")
+	})
+}
+
 func TestWithQueryProjectionExpression(t *testing.T) {
 	t.Run("sets_projection_expression_when_given_string", func(t *testing.T) {
 		input := &dynamodb.QueryInput{}
@@ -383,7 +520,7 @@ func TestWithQueryExclusiveStartKey(t *testing.T) {
 		err := option(input)
 
 		assert.Error(t, err)
-		assert.Contains(t, err.Error(), "failed to unmarshal exclusiveStartKey JSON")
+		assert.Contains(t, err.Error(), "failed to unmarshal cursor JSON")
 	})
 }
 
@@ -468,6 +605,136 @@ func TestWithQueryTableNameSuffix(t *testing.T) {
 	})
 }
 
+func TestWithQuerySortKeyBeginsWith(t *testing.T) {
+	t.Run("adds_begins_with_to_the_key_condition", func(t *testing.T) {
+		input := &dynamodb.QueryInput{
+			KeyConditionExpression:    aws.String("#0 = :0"),
+			ExpressionAttributeNames:  map[string]string{"#0": "thePartitionKey"},
+			ExpressionAttributeValues: map[string]types.AttributeValue{":0": &types.AttributeValueMemberS{Value: "thePartitionKeyValue"}},
+		}
+		option := WithQuerySortKeyBeginsWith("theSortKey", "thePrefix")
+
+		err := option(input)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "#0 = :0 AND begins_with(#sortKeyBeginsWith, :sortKeyBeginsWith)", *input.KeyConditionExpression)
+		assert.Equal(t, "theSortKey", input.ExpressionAttributeNames["#sortKeyBeginsWith"])
+		assert.Equal(t, &types.AttributeValueMemberS{Value: "thePrefix"}, input.ExpressionAttributeValues[":sortKeyBeginsWith"])
+		assert.Equal(t, "thePartitionKey", input.ExpressionAttributeNames["#0"])
+	})
+
+	t.Run("returns_an_error_when_the_sort_key_is_empty", func(t *testing.T) {
+		input := &dynamodb.QueryInput{KeyConditionExpression: aws.String("#0 = :0")}
+		option := WithQuerySortKeyBeginsWith("", "thePrefix")
+
+		err := option(input)
+
+		assert.ErrorContains(t, err, "sort key cannot be empty")
+	})
+
+	t.Run("returns_an_error_without_a_key_condition", func(t *testing.T) {
+		input := &dynamodb.QueryInput{}
+		option := WithQuerySortKeyBeginsWith("theSortKey", "thePrefix")
+
+		err := option(input)
+
+		assert.ErrorContains(t, err, "key condition expression cannot be empty")
+	})
+}
+
+func newTestKeyConditionInput() *dynamodb.QueryInput {
+	return &dynamodb.QueryInput{
+		KeyConditionExpression:    aws.String("#0 = :0"),
+		ExpressionAttributeNames:  map[string]string{"#0": "aPartitionKey"},
+		ExpressionAttributeValues: map[string]types.AttributeValue{":0": &types.AttributeValueMemberS{Value: "aPartitionKeyValue"}},
+	}
+}
+
+func TestWithQuerySortKeyComparisons(t *testing.T) {
+	for _, test := range []struct {
+		name      string
+		option    QueryOption
+		condition string
+	}{
+		{"equals", WithQuerySortKeyEquals("theSortKey", "theValue"), "#0 = :0 AND #sortKey = :sortKey"},
+		{"less_than", WithQuerySortKeyLessThan("theSortKey", "theValue"), "#0 = :0 AND #sortKey < :sortKey"},
+		{"less_than_or_equal", WithQuerySortKeyLessThanOrEqual("theSortKey", "theValue"), "#0 = :0 AND #sortKey <= :sortKey"},
+		{"greater_than", WithQuerySortKeyGreaterThan("theSortKey", "theValue"), "#0 = :0 AND #sortKey > :sortKey"},
+		{"greater_than_or_equal", WithQuerySortKeyGreaterThanOrEqual("theSortKey", "theValue"), "#0 = :0 AND #sortKey >= :sortKey"},
+	} {
+		t.Run("adds_"+test.name+"_to_the_key_condition", func(t *testing.T) {
+			input := newTestKeyConditionInput()
+
+			err := test.option(input)
+
+			assert.NoError(t, err)
+			assert.Equal(t, test.condition, *input.KeyConditionExpression)
+			assert.Equal(t, "theSortKey", input.ExpressionAttributeNames["#sortKey"])
+			assert.Equal(t, &types.AttributeValueMemberS{Value: "theValue"}, input.ExpressionAttributeValues[":sortKey"])
+		})
+	}
+
+	t.Run("compares_a_number_sort_key", func(t *testing.T) {
+		input := newTestKeyConditionInput()
+
+		err := WithQuerySortKeyGreaterThan("theSortKey", 42)(input)
+
+		assert.NoError(t, err)
+		assert.Equal(t, &types.AttributeValueMemberN{Value: "42"}, input.ExpressionAttributeValues[":sortKey"])
+	})
+
+	t.Run("returns_an_error_when_the_sort_key_is_empty", func(t *testing.T) {
+		err := WithQuerySortKeyEquals("", "aValue")(newTestKeyConditionInput())
+
+		assert.EqualError(t, err, "sort key cannot be empty")
+	})
+
+	t.Run("returns_an_error_when_there_is_already_a_sort_key_condition", func(t *testing.T) {
+		input := newTestKeyConditionInput()
+		assert.NoError(t, WithQuerySortKeyBeginsWith("aSortKey", "aPrefix")(input))
+
+		err := WithQuerySortKeyLessThan("aSortKey", "aValue")(input)
+
+		assert.EqualError(t, err, "key condition already has a sort key condition")
+	})
+}
+
+func TestWithQuerySortKeyBetween(t *testing.T) {
+	t.Run("adds_between_to_the_key_condition", func(t *testing.T) {
+		input := newTestKeyConditionInput()
+
+		err := WithQuerySortKeyBetween("theSortKey", "2024-01-01", "2024-01-31")(input)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "#0 = :0 AND #sortKey BETWEEN :sortKeyLow AND :sortKeyHigh", *input.KeyConditionExpression)
+		assert.Equal(t, "theSortKey", input.ExpressionAttributeNames["#sortKey"])
+		assert.Equal(t, &types.AttributeValueMemberS{Value: "2024-01-01"}, input.ExpressionAttributeValues[":sortKeyLow"])
+		assert.Equal(t, &types.AttributeValueMemberS{Value: "2024-01-31"}, input.ExpressionAttributeValues[":sortKeyHigh"])
+	})
+
+	t.Run("is_applied_by_query", func(t *testing.T) {
+		var actualInput *dynamodb.QueryInput
+		fakeDB := &FakeDynamoDB{
+			QueryFake: func(ctx context.Context, params *dynamodb.QueryInput, optFns ...func(*dynamodb.Options)) (*dynamodb.QueryOutput, error) {
+				actualInput = params
+				return &dynamodb.QueryOutput{}, nil
+			},
+		}
+		setFake(func(ctx context.Context) (DynamoDB, error) { return fakeDB, nil })
+		t.Cleanup(func() { setFake(nil) })
+
+		_, err := Query[TestUserWithSort](context.Background(), "aTable", "user_id", "aUserID", WithQuerySortKeyBetween("timestamp", 100, 200))
+
+		assert.NoError(t, err)
+		assert.Equal(t, "#0 = :0 AND #sortKey BETWEEN :sortKeyLow AND :sortKeyHigh", *actualInput.KeyConditionExpression)
+		assert.Equal(t, map[string]types.AttributeValue{
+			":0":           &types.AttributeValueMemberS{Value: "aUserID"},
+			":sortKeyLow":  &types.AttributeValueMemberN{Value: "100"},
+			":sortKeyHigh": &types.AttributeValueMemberN{Value: "200"},
+		}, actualInput.ExpressionAttributeValues)
+	})
+}
+
 func TestWithQueryIndexName(t *testing.T) {
 	t.Run("sets_index_name_when_given_string_value", func(t *testing.T) {
 		input := &dynamodb.QueryInput{}
@@ -502,4 +769,223 @@ func TestWithQueryIndexName(t *testing.T) {
 		assert.NoError(t, err)
 		assert.Equal(t, "newIndexName", *input.IndexName)
 	})
+
+	t.Run("queries_the_index_with_the_projection_and_limit", func(t *testing.T) {
+		var actualInput *dynamodb.QueryInput
+		fakeDB := &FakeDynamoDB{
+			QueryFake: func(ctx context.Context, params *dynamodb.QueryInput, optFns ...func(*dynamodb.Options)) (*dynamodb.QueryOutput, error) {
+				actualInput = params
+				return &dynamodb.QueryOutput{Items: []map[string]types.AttributeValue{mustMarshalMap(t, TestUser{ID: "theID", Email: "theEmail"})}}, nil
+			},
+		}
+		setFake(func(ctx context.Context) (DynamoDB, error) { return fakeDB, nil })
+		t.Cleanup(func() { setFake(nil) })
+
+		result, err := Query[TestUser](context.Background(), "theTable", "email", "theEmail", WithQueryIndexName("theEmailIndex"), WithQueryProjectionExpression("id, email"), WithQueryLimit(5))
+
+		assert.NoError(t, err)
+		assert.Equal(t, []TestUser{{ID: "theID", Email: "theEmail"}}, result.Items)
+		assert.Equal(t, "theTable", *actualInput.TableName)
+		assert.Equal(t, "theEmailIndex", *actualInput.IndexName)
+		assert.Equal(t, "id, email", *actualInput.ProjectionExpression)
+		assert.Equal(t, int32(5), *actualInput.Limit)
+		assert.Equal(t, "email", actualInput.ExpressionAttributeNames["#0"])
+	})
+
+	t.Run("resumes_the_index_query_from_its_last_evaluated_key", func(t *testing.T) {
+		indexKey := map[string]types.AttributeValue{
+			"email":     &types.AttributeValueMemberS{Value: "theEmail"},
+			"timestamp": &types.AttributeValueMemberN{Value: "42"},
+			"id":        &types.AttributeValueMemberS{Value: "theID"},
+		}
+		var actualInputs []*dynamodb.QueryInput
+		fakeDB := &FakeDynamoDB{
+			QueryFake: func(ctx context.Context, params *dynamodb.QueryInput, optFns ...func(*dynamodb.Options)) (*dynamodb.QueryOutput, error) {
+				actualInputs = append(actualInputs, params)
+				if len(actualInputs) == 1 {
+					return &dynamodb.QueryOutput{LastEvaluatedKey: indexKey}, nil
+				}
+				return &dynamodb.QueryOutput{}, nil
+			},
+		}
+		setFake(func(ctx context.Context) (DynamoDB, error) { return fakeDB, nil })
+		t.Cleanup(func() { setFake(nil) })
+
+		first, err := Query[TestUser](context.Background(), "aTable", "email", "theEmail", WithQueryIndexName("anIndex"), WithQueryLimit(1))
+		assert.NoError(t, err)
+		assert.NotNil(t, first.LastEvaluatedKey)
+
+		second, err := Query[TestUser](context.Background(), "aTable", "email", "theEmail", WithQueryIndexName("anIndex"), WithQueryLimit(1), WithQueryExclusiveStartKey(*first.LastEvaluatedKey))
+
+		assert.NoError(t, err)
+		assert.Nil(t, second.LastEvaluatedKey)
+		assert.Equal(t, indexKey, actualInputs[1].ExclusiveStartKey)
+		assert.Equal(t, "anIndex", *actualInputs[1].IndexName)
+	})
+
+	t.Run("reads_every_page_of_the_index_with_query_all", func(t *testing.T) {
+		var actualIndexNames []string
+		fakeDB := &FakeDynamoDB{
+			QueryFake: func(ctx context.Context, params *dynamodb.QueryInput, optFns ...func(*dynamodb.Options)) (*dynamodb.QueryOutput, error) {
+				actualIndexNames = append(actualIndexNames, *params.IndexName)
+				output := &dynamodb.QueryOutput{Items: []map[string]types.AttributeValue{mustMarshalMap(t, TestUser{ID: fmt.Sprintf("theID%d", len(actualIndexNames))})}}
+				if len(actualIndexNames) == 1 {
+					output.LastEvaluatedKey = map[string]types.AttributeValue{"email": &types.AttributeValueMemberS{Value: "aKey"}}
+				}
+				return output, nil
+			},
+		}
+		setFake(func(ctx context.Context) (DynamoDB, error) { return fakeDB, nil })
+		t.Cleanup(func() { setFake(nil) })
+
+		result, err := QueryAll[TestUser](context.Background(), "aTable", "email", "anEmail", WithQueryIndexName("theIndex"))
+
+		assert.NoError(t, err)
+		assert.Equal(t, []TestUser{{ID: "theID1"}, {ID: "theID2"}}, result.Items)
+		assert.Equal(t, []string{"theIndex", "theIndex"}, actualIndexNames)
+	})
+
+	t.Run("names_the_index_in_the_error", func(t *testing.T) {
+		fakeDB := &FakeDynamoDB{
+			QueryFake: func(ctx context.Context, params *dynamodb.QueryInput, optFns ...func(*dynamodb.Options)) (*dynamodb.QueryOutput, error) {
+				return nil, errors.New("the fake error")
+			},
+		}
+		setFake(func(ctx context.Context) (DynamoDB, error) { return fakeDB, nil })
+		t.Cleanup(func() { setFake(nil) })
+
+		_, err := Query[TestUser](context.Background(), "theTable", "email", "theEmail", WithQueryIndexName("theIndex"))
+
+		assert.EqualError(t, err, "dynamodbkit.Query table=theTable index=theIndex email=theEmail: error querying: the fake error")
+	})
+}
+
+func TestQueryItems(t *testing.T) {
+	t.Run("yields_the_items_of_every_page", func(t *testing.T) {
+		calls := 0
+		fakeDB := &FakeDynamoDB{
+			QueryFake: func(ctx context.Context, params *dynamodb.QueryInput, optFns ...func(*dynamodb.Options)) (*dynamodb.QueryOutput, error) {
+				calls++
+				output := &dynamodb.QueryOutput{Items: []map[string]types.AttributeValue{mustMarshalMap(t, TestUser{ID: fmt.Sprintf("theID%d", calls)})}}
+				if calls == 1 {
+					output.LastEvaluatedKey = map[string]types.AttributeValue{"id": &types.AttributeValueMemberS{Value: "aKey"}}
+				}
+				return output, nil
+			},
+		}
+		setFake(func(ctx context.Context) (DynamoDB, error) { return fakeDB, nil })
+		t.Cleanup(func() { setFake(nil) })
+
+		var actualItems []TestUser
+		for item, err := range QueryItems[TestUser](context.Background(), "aTable", "id", "anID") {
+			assert.NoError(t, err)
+			actualItems = append(actualItems, item)
+		}
+
+		assert.Equal(t, []TestUser{{ID: "theID1"}, {ID: "theID2"}}, actualItems)
+	})
+
+	t.Run("stops_reading_pages_when_the_loop_breaks", func(t *testing.T) {
+		calls := 0
+		fakeDB := &FakeDynamoDB{
+			QueryFake: func(ctx context.Context, params *dynamodb.QueryInput, optFns ...func(*dynamodb.Options)) (*dynamodb.QueryOutput, error) {
+				calls++
+				return &dynamodb.QueryOutput{
+					Items:            []map[string]types.AttributeValue{mustMarshalMap(t, TestUser{ID: "theID"})},
+					LastEvaluatedKey: map[string]types.AttributeValue{"id": &types.AttributeValueMemberS{Value: "aKey"}},
+				}, nil
+			},
+		}
+		setFake(func(ctx context.Context) (DynamoDB, error) { return fakeDB, nil })
+		t.Cleanup(func() { setFake(nil) })
+
+		for range QueryItems[TestUser](context.Background(), "aTable", "id", "anID") {
+			break
+		}
+
+		assert.Equal(t, 1, calls)
+	})
+
+	t.Run("stops_at_max_items", func(t *testing.T) {
+		fakeDB := &FakeDynamoDB{
+			QueryFake: func(ctx context.Context, params *dynamodb.QueryInput, optFns ...func(*dynamodb.Options)) (*dynamodb.QueryOutput, error) {
+				return &dynamodb.QueryOutput{
+					Items:            []map[string]types.AttributeValue{mustMarshalMap(t, TestUser{ID: "theID"})},
+					LastEvaluatedKey: map[string]types.AttributeValue{"id": &types.AttributeValueMemberS{Value: "aKey"}},
+				}, nil
+			},
+		}
+		setFake(func(ctx context.Context) (DynamoDB, error) { return fakeDB, nil })
+		t.Cleanup(func() { setFake(nil) })
+
+		count := 0
+		for _, err := range QueryItems[TestUser](context.Background(), "aTable", "id", "anID", WithMaxItems(3)) {
+			assert.NoError(t, err)
+			count++
+		}
+
+		assert.Equal(t, 3, count)
+	})
+
+	t.Run("yields_the_error_when_a_page_fails", func(t *testing.T) {
+		fakeDB := &FakeDynamoDB{
+			QueryFake: func(ctx context.Context, params *dynamodb.QueryInput, optFns ...func(*dynamodb.Options)) (*dynamodb.QueryOutput, error) {
+				return nil, errors.New("the fake error")
+			},
+		}
+		setFake(func(ctx context.Context) (DynamoDB, error) { return fakeDB, nil })
+		t.Cleanup(func() { setFake(nil) })
+
+		var actualErrs []error
+		for _, err := range QueryItems[TestUser](context.Background(), "theTable", "id", "theID") {
+			actualErrs = append(actualErrs, err)
+		}
+
+		assert.Len(t, actualErrs, 1)
+		assert.EqualError(t, actualErrs[0], "dynamodbkit.QueryItems table=theTable id=theID: error querying: the fake error")
+	})
+
+	t.Run("yields_the_error_when_the_table_name_is_empty", func(t *testing.T) {
+		var actualErrs []error
+		for _, err := range QueryItems[TestUser](context.Background(), "", "id", "anID") {
+			actualErrs = append(actualErrs, err)
+		}
+
+		assert.Len(t, actualErrs, 1)
+		assert.ErrorContains(t, actualErrs[0], "table name cannot be empty")
+	})
+}
+
+func TestQueryOutputPage(t *testing.T) {
+	t.Run("returns_the_items_and_last_evaluated_key_as_a_kit_page", func(t *testing.T) {
+		fakeDB := &FakeDynamoDB{
+			QueryFake: func(ctx context.Context, params *dynamodb.QueryInput, optFns ...func(*dynamodb.Options)) (*dynamodb.QueryOutput, error) {
+				return &dynamodb.QueryOutput{
+					Items:            []map[string]types.AttributeValue{mustMarshalMap(t, TestUser{ID: "theID"})},
+					LastEvaluatedKey: map[string]types.AttributeValue{"id": &types.AttributeValueMemberS{Value: "theID"}},
+				}, nil
+			},
+		}
+		setFake(func(ctx context.Context) (DynamoDB, error) { return fakeDB, nil })
+		t.Cleanup(func() { setFake(nil) })
+		result, err := Query[TestUser](context.Background(), "aTable", "id", "theID")
+		assert.NoError(t, err)
+
+		page := result.Page()
+
+		assert.Equal(t, []TestUser{{ID: "theID"}}, page.Items)
+		assert.True(t, page.HasMore)
+		var position map[string]any
+		assert.NoError(t, kit.DecodeCursor(page.Cursor, &position))
+		assert.Equal(t, map[string]any{"id": "theID"}, position)
+	})
+
+	t.Run("returns_the_last_page_without_a_last_evaluated_key", func(t *testing.T) {
+		output := &QueryOutput[TestUser]{Items: []TestUser{{ID: "theID"}}}
+
+		page := output.Page()
+
+		assert.False(t, page.HasMore)
+		assert.Empty(t, page.Cursor)
+	})
 }
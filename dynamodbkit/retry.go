@@ -0,0 +1,210 @@
+package dynamodbkit
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/aws/smithy-go"
+
+	"github.com/half-ogre/go-kit/backoffkit"
+)
+
+// RetryPolicy is how operations retry DynamoDB calls that fail, on top of the AWS SDK's own retries.
+// A zero MaxAttempts leaves retrying to the SDK alone.
+type RetryPolicy struct {
+	// MaxAttempts is how many times a call is made before its error is returned, including the first
+	MaxAttempts int
+	// BaseDelay is the most the first retry waits; each retry after it may wait twice as long
+	BaseDelay time.Duration
+	// MaxDelay is the most any retry waits
+	MaxDelay time.Duration
+	// Backoff, when set, is how long each retry waits instead of BaseDelay and MaxDelay
+	Backoff backoffkit.Strategy
+	// Retryable reports whether a call's error is worth retrying; the default is IsThrottled
+	Retryable func(err error) bool
+}
+
+// DefaultRetryPolicy returns a policy retrying throttled calls up to 5 attempts, waiting up to 50ms
+// before the first retry and up to 5s before any
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{MaxAttempts: 5, BaseDelay: 50 * time.Millisecond, MaxDelay: 5 * time.Second}
+}
+
+// UseRetryPolicy sets how every operation retries failed calls, e.g. DefaultRetryPolicy() for a table
+// with provisioned capacity that's often throttled. Waits use exponential backoff with full jitter,
+// unless the policy sets another backoffkit strategy, so clients throttled together don't retry together, and the retries an operation made are in its
+// OperationMetrics. The SDK still retries a throttled request a few times quickly first, unless its
+// RetryMaxAttempts is set to 1.
+func UseRetryPolicy(policy RetryPolicy) {
+	updateDefaultClient(WithClientRetryPolicy(policy))
+}
+
+// WithClientRetryPolicy sets how every operation retries failed calls, as described on UseRetryPolicy
+func WithClientRetryPolicy(policy RetryPolicy) ClientOption {
+	return func(c *Client) {
+		c.retryPolicy = policy
+	}
+}
+
+// IsThrottled reports whether err is DynamoDB refusing a call for now: the table or index going over
+// its provisioned throughput, the account going over its request limit, or throttling
+func IsThrottled(err error) bool {
+	var provisioned *types.ProvisionedThroughputExceededException
+	var requestLimit *types.RequestLimitExceeded
+	if errors.As(err, &provisioned) || errors.As(err, &requestLimit) {
+		return true
+	}
+
+	var apiErr smithy.APIError
+	return errors.As(err, &apiErr) && apiErr.ErrorCode() == "ThrottlingException"
+}
+
+// sleep is replaced in tests
+var sleep = backoffkit.Sleep
+
+// retryingDynamoDB retries the calls of db as policy says, counting the retries for an operation's
+// metrics
+type retryingDynamoDB struct {
+	db      DynamoDB
+	policy  RetryPolicy
+	retries *atomic.Int64
+}
+
+// withRetries makes call until it succeeds, fails with an error the policy doesn't retry, or has been
+// made MaxAttempts times
+func withRetries[T any](ctx context.Context, r *retryingDynamoDB, method string, call func() (T, error)) (T, error) {
+	retryable := r.policy.Retryable
+	if retryable == nil {
+		retryable = IsThrottled
+	}
+	backoff := r.policy.Backoff
+	if backoff == nil {
+		backoff = backoffkit.FullJitter(backoffkit.Exponential(r.policy.BaseDelay, r.policy.MaxDelay))
+	}
+
+	var wait time.Duration
+	for attempt := 1; ; attempt++ {
+		result, err := call()
+		if err == nil || attempt >= r.policy.MaxAttempts || !retryable(err) {
+			return result, err
+		}
+
+		wait = backoff(attempt+1, wait)
+		r.retries.Add(1)
+		logger.InfoContext(ctx, "dynamodb call failed, retrying", "method", method, "attempt", attempt, "wait", wait, "error", err)
+		if err := sleep(ctx, wait); err != nil {
+			var zero T
+			return zero, err
+		}
+	}
+}
+
+func (r *retryingDynamoDB) Query(ctx context.Context, params *dynamodb.QueryInput, optFns ...func(*dynamodb.Options)) (*dynamodb.QueryOutput, error) {
+	return withRetries(ctx, r, "Query", func() (*dynamodb.QueryOutput, error) {
+		return r.db.Query(ctx, params, optFns...)
+	})
+}
+
+func (r *retryingDynamoDB) PutItem(ctx context.Context, params *dynamodb.PutItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.PutItemOutput, error) {
+	return withRetries(ctx, r, "PutItem", func() (*dynamodb.PutItemOutput, error) {
+		return r.db.PutItem(ctx, params, optFns...)
+	})
+}
+
+func (r *retryingDynamoDB) GetItem(ctx context.Context, params *dynamodb.GetItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.GetItemOutput, error) {
+	return withRetries(ctx, r, "GetItem", func() (*dynamodb.GetItemOutput, error) {
+		return r.db.GetItem(ctx, params, optFns...)
+	})
+}
+
+func (r *retryingDynamoDB) DeleteItem(ctx context.Context, params *dynamodb.DeleteItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.DeleteItemOutput, error) {
+	return withRetries(ctx, r, "DeleteItem", func() (*dynamodb.DeleteItemOutput, error) {
+		return r.db.DeleteItem(ctx, params, optFns...)
+	})
+}
+
+func (r *retryingDynamoDB) Scan(ctx context.Context, params *dynamodb.ScanInput, optFns ...func(*dynamodb.Options)) (*dynamodb.ScanOutput, error) {
+	return withRetries(ctx, r, "Scan", func() (*dynamodb.ScanOutput, error) {
+		return r.db.Scan(ctx, params, optFns...)
+	})
+}
+
+func (r *retryingDynamoDB) ListTables(ctx context.Context, params *dynamodb.ListTablesInput, optFns ...func(*dynamodb.Options)) (*dynamodb.ListTablesOutput, error) {
+	return withRetries(ctx, r, "ListTables", func() (*dynamodb.ListTablesOutput, error) {
+		return r.db.ListTables(ctx, params, optFns...)
+	})
+}
+
+func (r *retryingDynamoDB) UpdateItem(ctx context.Context, params *dynamodb.UpdateItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.UpdateItemOutput, error) {
+	updater, err := requireAPI[UpdateItemAPI](r.db, "UpdateItem")
+	if err != nil {
+		return nil, err
+	}
+	return withRetries(ctx, r, "UpdateItem", func() (*dynamodb.UpdateItemOutput, error) {
+		return updater.UpdateItem(ctx, params, optFns...)
+	})
+}
+
+func (r *retryingDynamoDB) BatchWriteItem(ctx context.Context, params *dynamodb.BatchWriteItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.BatchWriteItemOutput, error) {
+	writer, err := requireAPI[BatchWriteItemAPI](r.db, "BatchWriteItem")
+	if err != nil {
+		return nil, err
+	}
+	return withRetries(ctx, r, "BatchWriteItem", func() (*dynamodb.BatchWriteItemOutput, error) {
+		return writer.BatchWriteItem(ctx, params, optFns...)
+	})
+}
+
+func (r *retryingDynamoDB) TransactWriteItems(ctx context.Context, params *dynamodb.TransactWriteItemsInput, optFns ...func(*dynamodb.Options)) (*dynamodb.TransactWriteItemsOutput, error) {
+	writer, err := requireAPI[TransactWriteItemsAPI](r.db, "TransactWriteItems")
+	if err != nil {
+		return nil, err
+	}
+	return withRetries(ctx, r, "TransactWriteItems", func() (*dynamodb.TransactWriteItemsOutput, error) {
+		return writer.TransactWriteItems(ctx, params, optFns...)
+	})
+}
+
+func (r *retryingDynamoDB) ExecuteStatement(ctx context.Context, params *dynamodb.ExecuteStatementInput, optFns ...func(*dynamodb.Options)) (*dynamodb.ExecuteStatementOutput, error) {
+	executor, err := requireAPI[ExecuteStatementAPI](r.db, "ExecuteStatement")
+	if err != nil {
+		return nil, err
+	}
+	return withRetries(ctx, r, "ExecuteStatement", func() (*dynamodb.ExecuteStatementOutput, error) {
+		return executor.ExecuteStatement(ctx, params, optFns...)
+	})
+}
+
+func (r *retryingDynamoDB) CreateTable(ctx context.Context, params *dynamodb.CreateTableInput, optFns ...func(*dynamodb.Options)) (*dynamodb.CreateTableOutput, error) {
+	creator, err := requireAPI[CreateTableAPI](r.db, "CreateTable")
+	if err != nil {
+		return nil, err
+	}
+	return withRetries(ctx, r, "CreateTable", func() (*dynamodb.CreateTableOutput, error) {
+		return creator.CreateTable(ctx, params, optFns...)
+	})
+}
+
+func (r *retryingDynamoDB) DeleteTable(ctx context.Context, params *dynamodb.DeleteTableInput, optFns ...func(*dynamodb.Options)) (*dynamodb.DeleteTableOutput, error) {
+	deleter, err := requireAPI[DeleteTableAPI](r.db, "DeleteTable")
+	if err != nil {
+		return nil, err
+	}
+	return withRetries(ctx, r, "DeleteTable", func() (*dynamodb.DeleteTableOutput, error) {
+		return deleter.DeleteTable(ctx, params, optFns...)
+	})
+}
+
+func (r *retryingDynamoDB) DescribeTable(ctx context.Context, params *dynamodb.DescribeTableInput, optFns ...func(*dynamodb.Options)) (*dynamodb.DescribeTableOutput, error) {
+	describer, err := requireAPI[DescribeTableAPI](r.db, "DescribeTable")
+	if err != nil {
+		return nil, err
+	}
+	return withRetries(ctx, r, "DescribeTable", func() (*dynamodb.DescribeTableOutput, error) {
+		return describer.DescribeTable(ctx, params, optFns...)
+	})
+}
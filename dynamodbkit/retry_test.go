@@ -0,0 +1,176 @@
+package dynamodbkit
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/aws/smithy-go"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/half-ogre/go-kit/backoffkit"
+)
+
+// recordSleeps replaces sleep with one recording the waits without waiting
+func recordSleeps(t *testing.T) *[]time.Duration {
+	waits := []time.Duration{}
+	previous := sleep
+	sleep = func(ctx context.Context, d time.Duration) error {
+		waits = append(waits, d)
+		return nil
+	}
+	t.Cleanup(func() { sleep = previous })
+	return &waits
+}
+
+func TestWithClientRetryPolicy(t *testing.T) {
+	thePolicy := RetryPolicy{MaxAttempts: 3, BaseDelay: 10 * time.Millisecond, MaxDelay: 15 * time.Millisecond}
+
+	t.Run("retries_a_throttled_call_and_records_the_retries", func(t *testing.T) {
+		waits := recordSleeps(t)
+		attempts := 0
+		fakeDB := &FakeDynamoDB{
+			GetItemFake: func(ctx context.Context, params *dynamodb.GetItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.GetItemOutput, error) {
+				attempts++
+				if attempts < 3 {
+					return nil, &types.ProvisionedThroughputExceededException{Message: aws.String("the throttling")}
+				}
+				return &dynamodb.GetItemOutput{Item: mustMarshalMap(t, TestUser{ID: "theID"})}, nil
+			},
+		}
+		var records []OperationMetrics
+		client := NewClient(fakeDB, WithClientRetryPolicy(thePolicy), WithClientInstrumentation(func(ctx context.Context, metrics OperationMetrics) {
+			records = append(records, metrics)
+		}))
+
+		user, err := GetItem[TestUser](WithClient(context.Background(), client), "theTableName", "id", "theID")
+
+		assert.NoError(t, err)
+		assert.Equal(t, "theID", user.ID)
+		assert.Equal(t, 3, attempts)
+		assert.Len(t, *waits, 2)
+		assert.LessOrEqual(t, (*waits)[0], 10*time.Millisecond)
+		assert.LessOrEqual(t, (*waits)[1], 15*time.Millisecond)
+		assert.Equal(t, 2, records[0].Retries)
+	})
+
+	t.Run("returns_the_error_after_the_most_attempts", func(t *testing.T) {
+		recordSleeps(t)
+		attempts := 0
+		fakeDB := &FakeDynamoDB{
+			PutItemFake: func(ctx context.Context, params *dynamodb.PutItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.PutItemOutput, error) {
+				attempts++
+				return nil, &smithy.GenericAPIError{Code: "ThrottlingException", Message: "the throttling"}
+			},
+		}
+		client := NewClient(fakeDB, WithClientRetryPolicy(thePolicy))
+
+		err := PutItem(WithClient(context.Background(), client), "theTableName", TestUser{ID: "theID"})
+
+		assert.ErrorContains(t, err, "ThrottlingException")
+		assert.Equal(t, 3, attempts)
+	})
+
+	t.Run("does_not_retry_an_error_that_is_not_throttling", func(t *testing.T) {
+		waits := recordSleeps(t)
+		attempts := 0
+		fakeDB := &FakeDynamoDB{
+			DeleteItemFake: func(ctx context.Context, params *dynamodb.DeleteItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.DeleteItemOutput, error) {
+				attempts++
+				return nil, &types.ConditionalCheckFailedException{}
+			},
+		}
+		client := NewClient(fakeDB, WithClientRetryPolicy(thePolicy))
+
+		err := DeleteItem(WithClient(context.Background(), client), "theTableName", "id", "theID")
+
+		assert.ErrorIs(t, err, ErrConditionFailed)
+		assert.Equal(t, 1, attempts)
+		assert.Empty(t, *waits)
+	})
+
+	t.Run("retries_the_errors_the_policy_says", func(t *testing.T) {
+		recordSleeps(t)
+		attempts := 0
+		fakeDB := &FakeDynamoDB{
+			UpdateItemFake: func(ctx context.Context, params *dynamodb.UpdateItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.UpdateItemOutput, error) {
+				attempts++
+				if attempts == 1 {
+					return nil, errors.New("the transient error")
+				}
+				return &dynamodb.UpdateItemOutput{}, nil
+			},
+		}
+		policy := thePolicy
+		policy.Retryable = func(err error) bool { return err.Error() == "the transient error" }
+		client := NewClient(fakeDB, WithClientRetryPolicy(policy))
+
+		_, err := UpdateItem[TestUser](WithClient(context.Background(), client), "theTableName", "id", "theID", WithUpdateSet("name", "theName"))
+
+		assert.NoError(t, err)
+		assert.Equal(t, 2, attempts)
+	})
+
+	t.Run("waits_as_the_policy_backoff_says", func(t *testing.T) {
+		waits := recordSleeps(t)
+		fakeDB := &FakeDynamoDB{
+			GetItemFake: func(ctx context.Context, params *dynamodb.GetItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.GetItemOutput, error) {
+				return nil, &types.ProvisionedThroughputExceededException{}
+			},
+		}
+		policy := RetryPolicy{MaxAttempts: 4, Backoff: backoffkit.Fibonacci(time.Second, 0)}
+		client := NewClient(fakeDB, WithClientRetryPolicy(policy))
+
+		_, err := GetItem[TestUser](WithClient(context.Background(), client), "theTableName", "id", "theID")
+
+		assert.Error(t, err)
+		assert.Equal(t, []time.Duration{time.Second, time.Second, 2 * time.Second}, *waits)
+	})
+
+	t.Run("does_not_retry_without_a_policy", func(t *testing.T) {
+		attempts := 0
+		fakeDB := &FakeDynamoDB{
+			ScanFake: func(ctx context.Context, params *dynamodb.ScanInput, optFns ...func(*dynamodb.Options)) (*dynamodb.ScanOutput, error) {
+				attempts++
+				return nil, &types.RequestLimitExceeded{}
+			},
+		}
+
+		_, err := Scan[TestUser](WithClient(context.Background(), NewClient(fakeDB)), "theTableName")
+
+		assert.Error(t, err)
+		assert.Equal(t, 1, attempts)
+	})
+
+	t.Run("returns_the_context_error_when_done_while_waiting", func(t *testing.T) {
+		fakeDB := &FakeDynamoDB{
+			QueryFake: func(ctx context.Context, params *dynamodb.QueryInput, optFns ...func(*dynamodb.Options)) (*dynamodb.QueryOutput, error) {
+				return nil, &types.ProvisionedThroughputExceededException{}
+			},
+		}
+		client := NewClient(fakeDB, WithClientRetryPolicy(RetryPolicy{MaxAttempts: 3, BaseDelay: time.Hour, MaxDelay: time.Hour}))
+		ctx, cancel := context.WithCancel(WithClient(context.Background(), client))
+		cancel()
+
+		_, err := Query[TestUser](ctx, "theTableName", "id", "theID")
+
+		assert.ErrorIs(t, err, context.Canceled)
+	})
+}
+
+func TestIsThrottled(t *testing.T) {
+	t.Run("reports_throttling_errors", func(t *testing.T) {
+		assert.True(t, IsThrottled(&types.ProvisionedThroughputExceededException{}))
+		assert.True(t, IsThrottled(&types.RequestLimitExceeded{}))
+		assert.True(t, IsThrottled(&smithy.GenericAPIError{Code: "ThrottlingException"}))
+	})
+
+	t.Run("does_not_report_other_errors", func(t *testing.T) {
+		assert.False(t, IsThrottled(&types.ResourceNotFoundException{}))
+		assert.False(t, IsThrottled(errors.New("the error")))
+	})
+}
@@ -2,28 +2,190 @@ package dynamodbkit
 
 import (
 	"context"
-	"encoding/base64"
-	"encoding/json"
+	"errors"
 	"fmt"
+	"iter"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
 	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
 	"github.com/half-ogre/go-kit/kit"
 )
 
-func Scan[TItem any](ctx context.Context, tableName string, options ...ScanOption) (*ScanOutput[TItem], error) {
+func Scan[TItem any](ctx context.Context, tableName string, options ...ScanOption) (_ *ScanOutput[TItem], err error) {
+	op := newOperation(ctx, "Scan", tableName)
+	defer op.wrap(&err)
+
+	db, scanInput, err := prepareScan(ctx, op, options)
+	if err != nil {
+		return nil, err
+	}
+
+	metrics := startOperation(op, "Scan", scanInput.TableName)
+	scanInput.ReturnConsumedCapacity = op.client.returnConsumedCapacity(metrics)
+
+	page, err := scanPage[TItem](ctx, db, scanInput)
+	addPage(metrics, page)
+	metrics.finish(ctx, err)
+	if err != nil {
+		return nil, err
+	}
+
+	lastEvaluatedKey, err := encodeLastEvaluatedKey(page.lastEvaluatedKey)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ScanOutput[TItem]{Items: page.items, LastEvaluatedKey: lastEvaluatedKey}, nil
+}
+
+// ScanAll scans every page of a table, following LastEvaluatedKey, and returns all the items. It takes
+// ScanOptions and PageOptions. If it stops early, because of WithMaxPages, WithMaxItems, or ctx being
+// done between pages, the items read so far are returned with a LastEvaluatedKey to resume from with
+// WithScanExclusiveStartKey; a done ctx is also returned as the error. When a page after the first
+// fails, e.g. because ctx is canceled mid-request, the items read before it are returned along with the
+// error and a LastEvaluatedKey that resumes at the failed page. With UseInstrumentation it reports the
+// pages as one ScanAll operation.
+func ScanAll[TItem any](ctx context.Context, tableName string, options ...ScanAllOption) (_ *ScanOutput[TItem], err error) {
+	op := newOperation(ctx, "ScanAll", tableName)
+	defer op.wrap(&err)
+
+	config := &scanAllConfig{}
+	for _, option := range options {
+		option.applyScanAll(config)
+	}
+
+	db, scanInput, err := prepareScan(ctx, op, config.scanOptions)
+	if err != nil {
+		return nil, err
+	}
+
+	metrics := startOperation(op, "ScanAll", scanInput.TableName)
+	scanInput.ReturnConsumedCapacity = op.client.returnConsumedCapacity(metrics)
+
+	limit := scanInput.Limit
+	result := &ScanOutput[TItem]{Items: make([]TItem, 0)}
+	for pagesRead := 1; ; pagesRead++ {
+		scanInput.Limit = config.paging.pageLimit(limit, len(result.Items))
+		page, err := scanPage[TItem](ctx, db, scanInput)
+		addPage(metrics, page)
+		if err != nil {
+			metrics.finish(ctx, err)
+			if pagesRead == 1 {
+				return nil, err
+			}
+
+			// The items read so far are still good, so they're returned with a cursor to the failed page
+			var keyErr error
+			result.LastEvaluatedKey, keyErr = encodeLastEvaluatedKey(scanInput.ExclusiveStartKey)
+			if keyErr != nil {
+				return nil, keyErr
+			}
+			return result, kit.WrapError(err, "stopped scanning at page %d", pagesRead)
+		}
+
+		result.Items = append(result.Items, page.items...)
+
+		if page.lastEvaluatedKey == nil {
+			metrics.finish(ctx, nil)
+			return result, nil
+		}
+		scanInput.ExclusiveStartKey = page.lastEvaluatedKey
+
+		stop, stopErr := config.paging.beforeNextPage(ctx, pagesRead, len(result.Items))
+		if stop {
+			if stopErr != nil {
+				stopErr = kit.WrapError(stopErr, "stopped scanning after page %d", pagesRead)
+			}
+			metrics.finish(ctx, stopErr)
+			result.LastEvaluatedKey, err = encodeLastEvaluatedKey(page.lastEvaluatedKey)
+			if err != nil {
+				return nil, err
+			}
+			return result, stopErr
+		}
+	}
+}
+
+// ScanItems returns an iterator over every item in a table, reading a page at a time as the loop
+// asks for more instead of holding every item like ScanAll. It takes ScanOptions and PageOptions, and
+// ends quietly at a page or item limit. An error, including ctx being done between pages, is yielded
+// once and ends the iteration.
+func ScanItems[TItem any](ctx context.Context, tableName string, options ...ScanAllOption) iter.Seq2[TItem, error] {
+	return func(yield func(TItem, error) bool) {
+		var zero TItem
+		op := newOperation(ctx, "ScanItems", tableName)
+		fail := func(err error) {
+			op.wrap(&err)
+			yield(zero, err)
+		}
+
+		config := &scanAllConfig{}
+		for _, option := range options {
+			option.applyScanAll(config)
+		}
+
+		db, scanInput, err := prepareScan(ctx, op, config.scanOptions)
+		if err != nil {
+			fail(err)
+			return
+		}
+
+		metrics := startOperation(op, "ScanItems", scanInput.TableName)
+		scanInput.ReturnConsumedCapacity = op.client.returnConsumedCapacity(metrics)
+
+		limit := scanInput.Limit
+		itemsRead := 0
+		for pagesRead := 1; ; pagesRead++ {
+			scanInput.Limit = config.paging.pageLimit(limit, itemsRead)
+			page, err := scanPage[TItem](ctx, db, scanInput)
+			addPage(metrics, page)
+			if err != nil {
+				metrics.finish(ctx, err)
+				fail(err)
+				return
+			}
+
+			for _, item := range page.items {
+				if !yield(item, nil) {
+					metrics.finish(ctx, nil)
+					return
+				}
+			}
+			itemsRead += len(page.items)
+
+			if page.lastEvaluatedKey == nil {
+				metrics.finish(ctx, nil)
+				return
+			}
+			scanInput.ExclusiveStartKey = page.lastEvaluatedKey
+
+			stop, stopErr := config.paging.beforeNextPage(ctx, pagesRead, itemsRead)
+			if stop {
+				metrics.finish(ctx, stopErr)
+				if stopErr != nil {
+					fail(kit.WrapError(stopErr, "stopped scanning after page %d", pagesRead))
+				}
+				return
+			}
+		}
+	}
+}
+
+func prepareScan(ctx context.Context, op *operation, options []ScanOption) (DynamoDB, *dynamodb.ScanInput, error) {
 	if ctx == nil {
-		return nil, kit.WrapError(nil, "context cannot be nil")
+		return nil, nil, errors.New("context cannot be nil")
 	}
 
+	tableName := op.table
 	if tableName == "" {
-		return nil, kit.WrapError(nil, "table name cannot be empty")
+		return nil, nil, errors.New("table name cannot be empty")
 	}
 
-	db, err := newDynamoDB(ctx)
+	db, err := op.dynamoDB(ctx)
 	if err != nil {
-		return nil, kit.WrapError(err, "error creating DynamoDB client")
+		return nil, nil, kit.WrapError(err, "error creating DynamoDB client")
 	}
 
 	scanInput := &dynamodb.ScanInput{
@@ -35,25 +197,30 @@ func Scan[TItem any](ctx context.Context, tableName string, options ...ScanOptio
 	for _, option := range options {
 		err := option(scanInput)
 		if err != nil {
-			return nil, kit.WrapError(err, "error processing option")
+			return nil, nil, kit.WrapError(err, "error processing option")
 		}
 	}
 
-	// Apply global table name suffix if table name pointer wasn't changed by options
-	if scanInput.TableName == originalTableNamePtr {
-		globalSuffix := getTableNameSuffix()
-		if globalSuffix != "" {
-			scanInput.TableName = aws.String(fmt.Sprintf("%s%s", *scanInput.TableName, globalSuffix))
-		}
-	}
+	// Apply the client's table name prefix, and its suffix if table name pointer wasn't changed by options
+	scanInput.TableName = op.client.qualifyTableName(scanInput.TableName, originalTableNamePtr)
+	op.table = *scanInput.TableName
+	op.index = aws.ToString(scanInput.IndexName)
+
+	return db, scanInput, nil
+}
+
+func scanPage[TItem any](ctx context.Context, db DynamoDB, scanInput *dynamodb.ScanInput) (*page[TItem], error) {
+	logScanInput(ctx, scanInput)
 
 	output, err := db.Scan(ctx, scanInput)
 	if err != nil {
-		return nil, kit.WrapError(err, "error scanning table %s", *scanInput.TableName)
+		return nil, kit.WrapError(err, "error scanning")
 	}
 
-	result := &ScanOutput[TItem]{
-		Items: make([]TItem, 0),
+	result := &page[TItem]{
+		items:            make([]TItem, 0, len(output.Items)),
+		lastEvaluatedKey: output.LastEvaluatedKey,
+		consumedCapacity: output.ConsumedCapacity,
 	}
 
 	for _, i := range output.Items {
@@ -64,27 +231,53 @@ func Scan[TItem any](ctx context.Context, tableName string, options ...ScanOptio
 			return nil, kit.WrapError(err, "error unmarshalling scanned item")
 		}
 
-		result.Items = append(result.Items, item)
+		result.items = append(result.items, item)
 	}
 
-	if output.LastEvaluatedKey != nil {
-		var lastEvaluatedKey any
-		err := attributevalue.UnmarshalMap(output.LastEvaluatedKey, &lastEvaluatedKey)
-		if err != nil {
-			return nil, kit.WrapError(err, "failed to unmarshal LastEvaluatedKey map %v", output.LastEvaluatedKey)
-		}
+	return result, nil
+}
 
-		jsonBytes, err := json.Marshal(lastEvaluatedKey)
-		if err != nil {
-			return nil, kit.WrapError(err, "failed to marshal LastEvaluatedKey %v to JSON", output.LastEvaluatedKey)
-		}
+// page is one page of a query or scan, before its LastEvaluatedKey is encoded for callers
+type page[TItem any] struct {
+	items            []TItem
+	lastEvaluatedKey map[string]types.AttributeValue
+	consumedCapacity *types.ConsumedCapacity
+}
+
+// encodeLastEvaluatedKey encodes a LastEvaluatedKey as the opaque cursor accepted by
+// WithQueryExclusiveStartKey and WithScanExclusiveStartKey
+func encodeLastEvaluatedKey(key map[string]types.AttributeValue) (*string, error) {
+	if key == nil {
+		return nil, nil
+	}
 
-		encodedJson := base64.StdEncoding.EncodeToString(jsonBytes)
+	var lastEvaluatedKey any
+	err := attributevalue.UnmarshalMap(key, &lastEvaluatedKey)
+	if err != nil {
+		return nil, kit.WrapError(err, "failed to unmarshal LastEvaluatedKey map %v", key)
+	}
 
-		result.LastEvaluatedKey = &encodedJson
+	cursor, err := kit.EncodeCursor(lastEvaluatedKey)
+	if err != nil {
+		return nil, kit.WrapError(err, "failed to encode LastEvaluatedKey %v", key)
 	}
 
-	return result, nil
+	return &cursor, nil
+}
+
+// decodeExclusiveStartKey decodes a cursor from encodeLastEvaluatedKey
+func decodeExclusiveStartKey(exclusiveStartKey string) (map[string]types.AttributeValue, error) {
+	var position any
+	err := kit.DecodeCursor(exclusiveStartKey, &position)
+	if err != nil {
+		return nil, kit.WrapError(err, "failed to decode exclusiveStartKey")
+	}
+
+	key, err := attributevalue.MarshalMap(position)
+	if err != nil {
+		return nil, kit.WrapError(err, "failed to marshal exclusiveStartKey %s", exclusiveStartKey)
+	}
+	return key, nil
 }
 
 type ScanOutput[TItem any] struct {
@@ -92,24 +285,27 @@ type ScanOutput[TItem any] struct {
 	Items            []TItem
 }
 
+// Page returns the output as a kit.Page, with LastEvaluatedKey as its cursor
+func (o *ScanOutput[TItem]) Page() kit.Page[TItem] {
+	return kit.NewPage(o.Items, o.LastEvaluatedKey)
+}
+
 type ScanOption func(*dynamodb.ScanInput) error
 
-func WithScanExclusiveStartKey(exclusiveStartKey string) ScanOption {
+// WithScanExclusiveStartKey resumes from a LastEvaluatedKey. With pagination token options it instead
+// resumes from a token made by NewPaginationToken with the same options, rejecting one that is forged,
+// edited, or expired.
+func WithScanExclusiveStartKey(exclusiveStartKey string, options ...PaginationTokenOption) ScanOption {
 	return func(input *dynamodb.ScanInput) error {
-		decodedJson, err := base64.StdEncoding.DecodeString(exclusiveStartKey)
-		if err != nil {
-			return kit.WrapError(err, "failed to decode exclusiveStartKey %s", exclusiveStartKey)
-		}
-
-		var v interface{}
-		err = json.Unmarshal(decodedJson, &v)
-		if err != nil {
-			return kit.WrapError(err, "failed to unmarshal exclusiveStartKey JSON %s", decodedJson)
+		var k map[string]types.AttributeValue
+		var err error
+		if len(options) > 0 {
+			k, err = decodePaginationToken(exclusiveStartKey, options)
+		} else {
+			k, err = decodeExclusiveStartKey(exclusiveStartKey)
 		}
-
-		k, err := attributevalue.MarshalMap(v)
 		if err != nil {
-			return kit.WrapError(err, "failed to unmarshal exclusiveStartKey JSON %s", decodedJson)
+			return err
 		}
 
 		input.ExclusiveStartKey = k
@@ -127,10 +323,10 @@ func WithScanIndexName(indexName string) ScanOption {
 func WithScanLimit(limit int64) ScanOption {
 	return func(input *dynamodb.ScanInput) error {
 		if limit < 0 {
-			return kit.WrapError(nil, "limit must be non-negative, got %d", limit)
+			return fmt.Errorf("limit must be non-negative, got %d", limit)
 		}
 		if limit > 2147483647 { // int32 max
-			return kit.WrapError(nil, "limit exceeds maximum allowed value, got %d", limit)
+			return fmt.Errorf("limit exceeds maximum allowed value, got %d", limit)
 		}
 		input.Limit = aws.Int32(int32(limit))
 		return nil
@@ -5,6 +5,7 @@ import (
 	"encoding/base64"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"testing"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
@@ -12,6 +13,8 @@ import (
 	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
 	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
 	"github.com/stretchr/testify/assert"
+
+	"github.com/half-ogre/go-kit/kit"
 )
 
 func TestScan(t *testing.T) {
@@ -22,7 +25,7 @@ func TestScan(t *testing.T) {
 		result, err := Scan[TestUser](context.Background(), "aTable")
 
 		assert.Nil(t, result)
-		assert.EqualError(t, err, "error creating DynamoDB client: the fake error")
+		assert.EqualError(t, err, "dynamodbkit.Scan table=aTable: error creating DynamoDB client: the fake error")
 	})
 
 	t.Run("passes_the_table_name_to_scan", func(t *testing.T) {
@@ -77,7 +80,7 @@ func TestScan(t *testing.T) {
 		result, err := Scan[TestUser](context.Background(), "aTable")
 
 		assert.Nil(t, result)
-		assert.EqualError(t, err, "error scanning table aTable: the fake error")
+		assert.EqualError(t, err, "dynamodbkit.Scan table=aTable: error scanning: the fake error")
 	})
 
 	t.Run("returns_an_error_when_an_item_cannot_be_unmarshalled", func(t *testing.T) {
@@ -98,7 +101,7 @@ func TestScan(t *testing.T) {
 		result, err := Scan[TestUser](context.Background(), "aTable")
 
 		assert.Nil(t, result)
-		assert.EqualError(t, err, "error unmarshalling scanned item: unmarshal failed, cannot unmarshal list into Go value type string")
+		assert.EqualError(t, err, "dynamodbkit.Scan table=aTable: error unmarshalling scanned item: unmarshal failed, cannot unmarshal list into Go value type string")
 	})
 
 	t.Run("returns_a_last_evaluated_key_when_scan_returns_a_last_evaluated_key", func(t *testing.T) {
@@ -265,6 +268,50 @@ func TestScan(t *testing.T) {
 	})
 }
 
+func TestScanAll(t *testing.T) {
+	t.Run("follows_last_evaluated_key_until_the_last_page", func(t *testing.T) {
+		calls := 0
+		fakeDB := &FakeDynamoDB{
+			ScanFake: func(ctx context.Context, params *dynamodb.ScanInput, optFns ...func(*dynamodb.Options)) (*dynamodb.ScanOutput, error) {
+				calls++
+				if params.ExclusiveStartKey == nil {
+					return &dynamodb.ScanOutput{
+						Items:            []map[string]types.AttributeValue{mustMarshalMap(t, TestUser{ID: "theFirstID"})},
+						LastEvaluatedKey: map[string]types.AttributeValue{"id": &types.AttributeValueMemberS{Value: "theFirstID"}},
+					}, nil
+				}
+				return &dynamodb.ScanOutput{
+					Items: []map[string]types.AttributeValue{mustMarshalMap(t, TestUser{ID: "theSecondID"})},
+				}, nil
+			},
+		}
+		setFake(func(ctx context.Context) (DynamoDB, error) { return fakeDB, nil })
+		t.Cleanup(func() { setFake(nil) })
+
+		result, err := ScanAll[TestUser](context.Background(), "aTable")
+
+		assert.NoError(t, err)
+		assert.Equal(t, 2, calls)
+		assert.Equal(t, []TestUser{{ID: "theFirstID"}, {ID: "theSecondID"}}, result.Items)
+		assert.Nil(t, result.LastEvaluatedKey)
+	})
+
+	t.Run("returns_an_error_when_a_page_fails", func(t *testing.T) {
+		fakeDB := &FakeDynamoDB{
+			ScanFake: func(ctx context.Context, params *dynamodb.ScanInput, optFns ...func(*dynamodb.Options)) (*dynamodb.ScanOutput, error) {
+				return nil, errors.New("the scan error")
+			},
+		}
+		setFake(func(ctx context.Context) (DynamoDB, error) { return fakeDB, nil })
+		t.Cleanup(func() { setFake(nil) })
+
+		result, err := ScanAll[TestUser](context.Background(), "theTableName")
+
+		assert.Nil(t, result)
+		assert.EqualError(t, err, "dynamodbkit.ScanAll table=theTableName: error scanning: the scan error")
+	})
+}
+
 func TestWithScanExclusiveStartKey(t *testing.T) {
 	t.Run("returns_an_error_when_given_invalid_base64", func(t *testing.T) {
 		input := &dynamodb.ScanInput{}
@@ -287,7 +334,7 @@ func TestWithScanExclusiveStartKey(t *testing.T) {
 		err := option(input)
 
 		assert.Error(t, err)
-		assert.Contains(t, err.Error(), "failed to unmarshal exclusiveStartKey")
+		assert.Contains(t, err.Error(), "failed to unmarshal cursor JSON")
 		assert.Nil(t, input.ExclusiveStartKey)
 	})
 }
@@ -410,3 +457,70 @@ func TestWithScanIndexName(t *testing.T) {
 		assert.Equal(t, "newIndexName", *input.IndexName)
 	})
 }
+
+func TestScanItems(t *testing.T) {
+	t.Run("yields_the_items_of_every_page", func(t *testing.T) {
+		calls := 0
+		fakeDB := &FakeDynamoDB{
+			ScanFake: func(ctx context.Context, params *dynamodb.ScanInput, optFns ...func(*dynamodb.Options)) (*dynamodb.ScanOutput, error) {
+				calls++
+				output := &dynamodb.ScanOutput{Items: []map[string]types.AttributeValue{mustMarshalMap(t, TestUser{ID: fmt.Sprintf("theID%d", calls)})}}
+				if calls == 1 {
+					output.LastEvaluatedKey = map[string]types.AttributeValue{"id": &types.AttributeValueMemberS{Value: "aKey"}}
+				}
+				return output, nil
+			},
+		}
+		setFake(func(ctx context.Context) (DynamoDB, error) { return fakeDB, nil })
+		t.Cleanup(func() { setFake(nil) })
+
+		var actualItems []TestUser
+		for item, err := range ScanItems[TestUser](context.Background(), "aTable") {
+			assert.NoError(t, err)
+			actualItems = append(actualItems, item)
+		}
+
+		assert.Equal(t, []TestUser{{ID: "theID1"}, {ID: "theID2"}}, actualItems)
+	})
+
+	t.Run("yields_the_context_error_when_it_is_done_between_pages", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		t.Cleanup(cancel)
+		fakeDB := &FakeDynamoDB{
+			ScanFake: func(ctx context.Context, params *dynamodb.ScanInput, optFns ...func(*dynamodb.Options)) (*dynamodb.ScanOutput, error) {
+				cancel()
+				return &dynamodb.ScanOutput{
+					Items:            []map[string]types.AttributeValue{mustMarshalMap(t, TestUser{ID: "theID"})},
+					LastEvaluatedKey: map[string]types.AttributeValue{"id": &types.AttributeValueMemberS{Value: "aKey"}},
+				}, nil
+			},
+		}
+		setFake(func(ctx context.Context) (DynamoDB, error) { return fakeDB, nil })
+		t.Cleanup(func() { setFake(nil) })
+
+		var actualItems []TestUser
+		var actualErr error
+		for item, err := range ScanItems[TestUser](ctx, "aTable") {
+			if err != nil {
+				actualErr = err
+				continue
+			}
+			actualItems = append(actualItems, item)
+		}
+
+		assert.Equal(t, []TestUser{{ID: "theID"}}, actualItems)
+		assert.ErrorIs(t, actualErr, context.Canceled)
+		assert.ErrorContains(t, actualErr, "stopped scanning after page 1")
+	})
+}
+
+func TestScanOutputPage(t *testing.T) {
+	t.Run("returns_the_items_and_last_evaluated_key_as_a_kit_page", func(t *testing.T) {
+		lastEvaluatedKey := "theCursor"
+		output := &ScanOutput[TestUser]{Items: []TestUser{{ID: "theID"}}, LastEvaluatedKey: &lastEvaluatedKey}
+
+		page := output.Page()
+
+		assert.Equal(t, kit.Page[TestUser]{Items: []TestUser{{ID: "theID"}}, Cursor: "theCursor", HasMore: true}, page)
+	})
+}
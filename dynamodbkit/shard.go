@@ -0,0 +1,135 @@
+package dynamodbkit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"math/rand/v2"
+	"sort"
+	"sync"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/half-ogre/go-kit/kit"
+)
+
+// ShardedPartitionKey returns the partition key value for one shard of a write-sharded partition,
+// e.g. ShardedPartitionKey("user", 3) is "user#3"
+func ShardedPartitionKey(partitionKeyValue string, shard int) string {
+	return fmt.Sprintf("%s#%d", partitionKeyValue, shard)
+}
+
+// ShardedPartitionKeys returns the partition key values of all shardCount shards
+func ShardedPartitionKeys(partitionKeyValue string, shardCount int) ([]string, error) {
+	if err := validateShardCount(shardCount); err != nil {
+		return nil, err
+	}
+
+	keys := make([]string, shardCount)
+	for shard := range shardCount {
+		keys[shard] = ShardedPartitionKey(partitionKeyValue, shard)
+	}
+	return keys, nil
+}
+
+// ShardedPartitionKeyFor returns the shard partition key for an item, chosen by hashing shardBy (e.g. the
+// item's ID). The same shardBy always maps to the same shard, so the item can be read back with GetItem.
+func ShardedPartitionKeyFor(partitionKeyValue string, shardBy string, shardCount int) (string, error) {
+	if err := validateShardCount(shardCount); err != nil {
+		return "", err
+	}
+
+	hash := fnv.New32a()
+	_, _ = hash.Write([]byte(shardBy))
+	return ShardedPartitionKey(partitionKeyValue, int(hash.Sum32()%uint32(shardCount))), nil
+}
+
+// RandomShardedPartitionKey returns a random shard partition key, for write-heavy items that are only
+// ever read back with QueryShards
+func RandomShardedPartitionKey(partitionKeyValue string, shardCount int) (string, error) {
+	if err := validateShardCount(shardCount); err != nil {
+		return "", err
+	}
+
+	return ShardedPartitionKey(partitionKeyValue, rand.IntN(shardCount)), nil
+}
+
+func validateShardCount(shardCount int) error {
+	if shardCount <= 0 {
+		return fmt.Errorf("shard count must be greater than 0, got %d", shardCount)
+	}
+	return nil
+}
+
+// QueryShards queries all shardCount shards of a write-sharded partition concurrently, reading every page
+// of each, and merges the items into a single list ordered by less (typically comparing sort keys).
+// The options are applied to each shard's query; WithQueryExclusiveStartKey should not be used.
+func QueryShards[TItem any](ctx context.Context, tableName string, partitionKey string, partitionKeyValue string, shardCount int, less func(a, b TItem) bool, options ...QueryOption) (_ []TItem, err error) {
+	op := newOperation(ctx, "QueryShards", tableName)
+	op.setKey(partitionKey, map[string]types.AttributeValue{partitionKey: &types.AttributeValueMemberS{Value: partitionKeyValue}})
+	defer op.wrap(&err)
+
+	if ctx == nil {
+		return nil, errors.New("context cannot be nil")
+	}
+
+	shardKeys, err := ShardedPartitionKeys(partitionKeyValue, shardCount)
+	if err != nil {
+		return nil, err
+	}
+
+	if less == nil {
+		return nil, errors.New("less function cannot be nil")
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	queryAllOptions := make([]QueryAllOption, len(options))
+	for i, option := range options {
+		queryAllOptions[i] = option
+	}
+
+	shardItems := make([][]TItem, shardCount)
+	shardErrs := make([]error, shardCount)
+
+	var wg sync.WaitGroup
+	for shard, shardKey := range shardKeys {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			output, err := QueryAll[TItem](ctx, tableName, partitionKey, shardKey, queryAllOptions...)
+			if err != nil {
+				shardErrs[shard] = err
+				cancel()
+				return
+			}
+			shardItems[shard] = output.Items
+		}()
+	}
+	wg.Wait()
+
+	// A failing shard cancels the others, so return its error rather than their cancellations
+	failedShard := -1
+	for shard, err := range shardErrs {
+		if err == nil {
+			continue
+		}
+		if failedShard < 0 || (errors.Is(shardErrs[failedShard], context.Canceled) && !errors.Is(err, context.Canceled)) {
+			failedShard = shard
+		}
+	}
+	if failedShard >= 0 {
+		return nil, kit.WrapError(shardErrs[failedShard], "error querying shard %d", failedShard)
+	}
+
+	items := make([]TItem, 0)
+	for _, shard := range shardItems {
+		items = append(items, shard...)
+	}
+	sort.SliceStable(items, func(i, j int) bool {
+		return less(items[i], items[j])
+	})
+
+	return items, nil
+}
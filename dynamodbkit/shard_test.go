@@ -0,0 +1,183 @@
+package dynamodbkit
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestShardedPartitionKey(t *testing.T) {
+	t.Run("suffixes_the_shard_number", func(t *testing.T) {
+		assert.Equal(t, "theUser#3", ShardedPartitionKey("theUser", 3))
+	})
+
+	t.Run("lists_every_shard", func(t *testing.T) {
+		result, err := ShardedPartitionKeys("theUser", 3)
+
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"theUser#0", "theUser#1", "theUser#2"}, result)
+	})
+
+	t.Run("hashes_the_same_value_to_the_same_shard", func(t *testing.T) {
+		first, err := ShardedPartitionKeyFor("theUser", "theItemID", 10)
+		assert.NoError(t, err)
+		second, err := ShardedPartitionKeyFor("theUser", "theItemID", 10)
+		assert.NoError(t, err)
+
+		assert.Equal(t, first, second)
+		assert.True(t, strings.HasPrefix(first, "theUser#"))
+	})
+
+	t.Run("picks_a_random_shard_within_range", func(t *testing.T) {
+		keys, err := ShardedPartitionKeys("theUser", 4)
+		assert.NoError(t, err)
+
+		for range 100 {
+			key, err := RandomShardedPartitionKey("theUser", 4)
+
+			assert.NoError(t, err)
+			assert.Contains(t, keys, key)
+		}
+	})
+
+	for _, shardCount := range []int{0, -1} {
+		t.Run("returns_an_error_when_shard_count_is_not_positive", func(t *testing.T) {
+			keys, err := ShardedPartitionKeys("theUser", shardCount)
+			assert.Nil(t, keys)
+			assert.ErrorContains(t, err, "shard count must be greater than 0")
+
+			_, err = ShardedPartitionKeyFor("theUser", "theItemID", shardCount)
+			assert.ErrorContains(t, err, "shard count must be greater than 0")
+
+			_, err = RandomShardedPartitionKey("theUser", shardCount)
+			assert.ErrorContains(t, err, "shard count must be greater than 0")
+		})
+	}
+}
+
+func TestQueryShards(t *testing.T) {
+	byTimestamp := func(a, b TestUserWithSort) bool { return a.Timestamp < b.Timestamp }
+
+	t.Run("returns_an_error_when_shard_count_is_not_positive", func(t *testing.T) {
+		result, err := QueryShards(context.Background(), "aTable", "user_id", "aUser", 0, byTimestamp)
+
+		assert.Nil(t, result)
+		assert.ErrorContains(t, err, "shard count must be greater than 0, got 0")
+	})
+
+	t.Run("returns_an_error_when_less_is_nil", func(t *testing.T) {
+		result, err := QueryShards[TestUserWithSort](context.Background(), "aTable", "user_id", "aUser", 2, nil)
+
+		assert.Nil(t, result)
+		assert.ErrorContains(t, err, "less function cannot be nil")
+	})
+
+	t.Run("queries_every_shard_and_merges_by_sort_key", func(t *testing.T) {
+		shardItems := map[string][]TestUserWithSort{
+			"theUser#0": {{UserID: "theUser#0", Timestamp: "1"}, {UserID: "theUser#0", Timestamp: "4"}},
+			"theUser#1": {{UserID: "theUser#1", Timestamp: "2"}},
+			"theUser#2": {{UserID: "theUser#2", Timestamp: "3"}, {UserID: "theUser#2", Timestamp: "5"}},
+		}
+		var mu sync.Mutex
+		var actualKeys []string
+		fakeDB := &FakeDynamoDB{
+			QueryFake: func(ctx context.Context, params *dynamodb.QueryInput, optFns ...func(*dynamodb.Options)) (*dynamodb.QueryOutput, error) {
+				key := params.ExpressionAttributeValues[":0"].(*types.AttributeValueMemberS).Value
+				mu.Lock()
+				actualKeys = append(actualKeys, key)
+				mu.Unlock()
+				items := make([]map[string]types.AttributeValue, 0)
+				for _, item := range shardItems[key] {
+					items = append(items, mustMarshalMap(t, item))
+				}
+				return &dynamodb.QueryOutput{Items: items}, nil
+			},
+		}
+		setFake(func(ctx context.Context) (DynamoDB, error) { return fakeDB, nil })
+		t.Cleanup(func() { setFake(nil) })
+
+		result, err := QueryShards(context.Background(), "aTable", "user_id", "theUser", 3, byTimestamp)
+
+		assert.NoError(t, err)
+		assert.ElementsMatch(t, []string{"theUser#0", "theUser#1", "theUser#2"}, actualKeys)
+		timestamps := make([]string, 0, len(result))
+		for _, item := range result {
+			timestamps = append(timestamps, item.Timestamp)
+		}
+		assert.Equal(t, []string{"1", "2", "3", "4", "5"}, timestamps)
+	})
+
+	t.Run("reads_every_page_of_a_shard", func(t *testing.T) {
+		var mu sync.Mutex
+		calls := 0
+		fakeDB := &FakeDynamoDB{
+			QueryFake: func(ctx context.Context, params *dynamodb.QueryInput, optFns ...func(*dynamodb.Options)) (*dynamodb.QueryOutput, error) {
+				mu.Lock()
+				defer mu.Unlock()
+				calls++
+				if params.ExclusiveStartKey == nil {
+					return &dynamodb.QueryOutput{
+						Items:            []map[string]types.AttributeValue{mustMarshalMap(t, TestUserWithSort{Timestamp: "2"})},
+						LastEvaluatedKey: mustMarshalMap(t, map[string]string{"user_id": "theUser#0", "timestamp": "2"}),
+					}, nil
+				}
+				return &dynamodb.QueryOutput{
+					Items: []map[string]types.AttributeValue{mustMarshalMap(t, TestUserWithSort{Timestamp: "1"})},
+				}, nil
+			},
+		}
+		setFake(func(ctx context.Context) (DynamoDB, error) { return fakeDB, nil })
+		t.Cleanup(func() { setFake(nil) })
+
+		result, err := QueryShards(context.Background(), "aTable", "user_id", "theUser", 1, byTimestamp)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 2, calls)
+		assert.Len(t, result, 2)
+		assert.Equal(t, "1", result[0].Timestamp)
+	})
+
+	t.Run("returns_an_error_when_a_shard_query_fails", func(t *testing.T) {
+		fakeDB := &FakeDynamoDB{
+			QueryFake: func(ctx context.Context, params *dynamodb.QueryInput, optFns ...func(*dynamodb.Options)) (*dynamodb.QueryOutput, error) {
+				if params.ExpressionAttributeValues[":0"].(*types.AttributeValueMemberS).Value == "theUser#1" {
+					return nil, errors.New("the query error")
+				}
+				return &dynamodb.QueryOutput{Items: []map[string]types.AttributeValue{}}, nil
+			},
+		}
+		setFake(func(ctx context.Context) (DynamoDB, error) { return fakeDB, nil })
+		t.Cleanup(func() { setFake(nil) })
+
+		result, err := QueryShards(context.Background(), "aTable", "user_id", "theUser", 2, byTimestamp)
+
+		assert.Nil(t, result)
+		assert.EqualError(t, err, "error querying shard 1: dynamodbkit.QueryAll table=aTable user_id=theUser#1: error querying: the query error")
+	})
+
+	t.Run("returns_the_failing_shard_error_rather_than_the_cancellations_it_caused", func(t *testing.T) {
+		fakeDB := &FakeDynamoDB{
+			QueryFake: func(ctx context.Context, params *dynamodb.QueryInput, optFns ...func(*dynamodb.Options)) (*dynamodb.QueryOutput, error) {
+				if params.ExpressionAttributeValues[":0"].(*types.AttributeValueMemberS).Value == "theUser#1" {
+					return nil, errors.New("the query error")
+				}
+				<-ctx.Done()
+				return nil, ctx.Err()
+			},
+		}
+		setFake(func(ctx context.Context) (DynamoDB, error) { return fakeDB, nil })
+		t.Cleanup(func() { setFake(nil) })
+
+		result, err := QueryShards(context.Background(), "aTable", "user_id", "theUser", 3, byTimestamp)
+
+		assert.Nil(t, result)
+		assert.ErrorContains(t, err, "error querying shard 1")
+		assert.ErrorContains(t, err, "the query error")
+	})
+}
@@ -0,0 +1,281 @@
+package dynamodbkit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/half-ogre/go-kit/kit"
+)
+
+// CreateTableOption configures CreateTable
+type CreateTableOption func(*dynamodb.CreateTableInput) error
+
+// CreateTable creates a table, with the client's table name prefix and suffix, keyed by partitionKey of
+// partitionKeyType, e.g. for acceptance tests and local development. Tables are on-demand unless
+// WithCreateTableProvisionedThroughput is used. CreateTable returns once DynamoDB accepts the request,
+// while the table is still CREATING; use WaitUntilTableActive before using it.
+func CreateTable(ctx context.Context, tableName string, partitionKey string, partitionKeyType types.ScalarAttributeType, options ...CreateTableOption) (err error) {
+	op := newOperation(ctx, "CreateTable", tableName)
+	defer op.wrap(&err)
+
+	if tableName == "" {
+		return errors.New("table name cannot be empty")
+	}
+	if partitionKey == "" {
+		return errors.New("partition key cannot be empty")
+	}
+
+	db, err := op.dynamoDB(ctx)
+	if err != nil {
+		return kit.WrapError(err, "error creating DynamoDB client")
+	}
+
+	creator, err := requireAPI[CreateTableAPI](db, "CreateTable")
+	if err != nil {
+		return err
+	}
+
+	input := &dynamodb.CreateTableInput{
+		TableName:   aws.String(op.client.TableName(tableName)),
+		KeySchema:   []types.KeySchemaElement{{AttributeName: aws.String(partitionKey), KeyType: types.KeyTypeHash}},
+		BillingMode: types.BillingModePayPerRequest,
+	}
+	op.table = aws.ToString(input.TableName)
+	if err := addAttributeDefinition(input, partitionKey, partitionKeyType); err != nil {
+		return err
+	}
+
+	for _, option := range options {
+		if err := option(input); err != nil {
+			return kit.WrapError(err, "error applying create table option")
+		}
+	}
+
+	// Provisioned tables need throughput for each index too; default it to the table's
+	if input.BillingMode == types.BillingModeProvisioned {
+		for i := range input.GlobalSecondaryIndexes {
+			if input.GlobalSecondaryIndexes[i].ProvisionedThroughput == nil {
+				input.GlobalSecondaryIndexes[i].ProvisionedThroughput = input.ProvisionedThroughput
+			}
+		}
+	}
+
+	if _, err := creator.CreateTable(ctx, input); err != nil {
+		return kit.WrapError(err, "error creating table")
+	}
+
+	return nil
+}
+
+// WithCreateTableSortKey adds a sort key of sortKeyType to the table's key
+func WithCreateTableSortKey(sortKey string, sortKeyType types.ScalarAttributeType) CreateTableOption {
+	return func(input *dynamodb.CreateTableInput) error {
+		input.KeySchema = append(input.KeySchema, types.KeySchemaElement{AttributeName: aws.String(sortKey), KeyType: types.KeyTypeRange})
+		return addAttributeDefinition(input, sortKey, sortKeyType)
+	}
+}
+
+// WithCreateTableGlobalSecondaryIndex adds a global secondary index keyed by partitionKey, and by
+// sortKey if it isn't empty, projecting all attributes
+func WithCreateTableGlobalSecondaryIndex(indexName string, partitionKey string, partitionKeyType types.ScalarAttributeType, sortKey string, sortKeyType types.ScalarAttributeType) CreateTableOption {
+	return func(input *dynamodb.CreateTableInput) error {
+		index := types.GlobalSecondaryIndex{
+			IndexName:  aws.String(indexName),
+			KeySchema:  []types.KeySchemaElement{{AttributeName: aws.String(partitionKey), KeyType: types.KeyTypeHash}},
+			Projection: &types.Projection{ProjectionType: types.ProjectionTypeAll},
+		}
+		if err := addAttributeDefinition(input, partitionKey, partitionKeyType); err != nil {
+			return err
+		}
+
+		if sortKey != "" {
+			index.KeySchema = append(index.KeySchema, types.KeySchemaElement{AttributeName: aws.String(sortKey), KeyType: types.KeyTypeRange})
+			if err := addAttributeDefinition(input, sortKey, sortKeyType); err != nil {
+				return err
+			}
+		}
+
+		input.GlobalSecondaryIndexes = append(input.GlobalSecondaryIndexes, index)
+		return nil
+	}
+}
+
+// WithCreateTableProvisionedThroughput makes the table provisioned instead of on-demand, with
+// readCapacity and writeCapacity units for the table and each of its global secondary indexes
+func WithCreateTableProvisionedThroughput(readCapacity int64, writeCapacity int64) CreateTableOption {
+	return func(input *dynamodb.CreateTableInput) error {
+		input.BillingMode = types.BillingModeProvisioned
+		input.ProvisionedThroughput = &types.ProvisionedThroughput{
+			ReadCapacityUnits:  aws.Int64(readCapacity),
+			WriteCapacityUnits: aws.Int64(writeCapacity),
+		}
+		return nil
+	}
+}
+
+// WithCreateTableStream turns on the table's stream with viewType, e.g. NEW_AND_OLD_IMAGES
+func WithCreateTableStream(viewType types.StreamViewType) CreateTableOption {
+	return func(input *dynamodb.CreateTableInput) error {
+		input.StreamSpecification = &types.StreamSpecification{StreamEnabled: aws.Bool(true), StreamViewType: viewType}
+		return nil
+	}
+}
+
+// addAttributeDefinition defines a key attribute once, returning an error if it's defined with two types
+func addAttributeDefinition(input *dynamodb.CreateTableInput, name string, attributeType types.ScalarAttributeType) error {
+	for _, definition := range input.AttributeDefinitions {
+		if aws.ToString(definition.AttributeName) != name {
+			continue
+		}
+		if definition.AttributeType != attributeType {
+			return fmt.Errorf("key attribute %s is both %s and %s", name, definition.AttributeType, attributeType)
+		}
+		return nil
+	}
+
+	input.AttributeDefinitions = append(input.AttributeDefinitions, types.AttributeDefinition{AttributeName: aws.String(name), AttributeType: attributeType})
+	return nil
+}
+
+// DeleteTable deletes a table, with the client's table name prefix and suffix. Like CreateTable, it
+// returns while the table is still DELETING; use WaitUntilTableDeleted to wait for it to be gone.
+func DeleteTable(ctx context.Context, tableName string) (err error) {
+	op := newOperation(ctx, "DeleteTable", tableName)
+	defer op.wrap(&err)
+
+	if tableName == "" {
+		return errors.New("table name cannot be empty")
+	}
+
+	db, err := op.dynamoDB(ctx)
+	if err != nil {
+		return kit.WrapError(err, "error creating DynamoDB client")
+	}
+
+	deleter, err := requireAPI[DeleteTableAPI](db, "DeleteTable")
+	if err != nil {
+		return err
+	}
+
+	tableName = op.client.TableName(tableName)
+	op.table = tableName
+
+	if _, err := deleter.DeleteTable(ctx, &dynamodb.DeleteTableInput{TableName: aws.String(tableName)}); err != nil {
+		return kit.WrapError(err, "error deleting table")
+	}
+
+	return nil
+}
+
+// DescribeTable returns the description of a table, with the client's table name prefix and suffix
+func DescribeTable(ctx context.Context, tableName string) (_ *types.TableDescription, err error) {
+	op := newOperation(ctx, "DescribeTable", tableName)
+	defer op.wrap(&err)
+
+	if tableName == "" {
+		return nil, errors.New("table name cannot be empty")
+	}
+
+	db, err := op.dynamoDB(ctx)
+	if err != nil {
+		return nil, kit.WrapError(err, "error creating DynamoDB client")
+	}
+
+	tableName = op.client.TableName(tableName)
+	op.table = tableName
+
+	return describeTable(ctx, db, tableName)
+}
+
+// TableExists reports whether a table, with the client's table name prefix and suffix, exists in any
+// status
+func TableExists(ctx context.Context, tableName string) (bool, error) {
+	_, err := DescribeTable(ctx, tableName)
+	if isTableNotFound(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// WaitOption configures WaitUntilTableActive and WaitUntilTableDeleted
+type WaitOption func(*waitConfig)
+
+type waitConfig struct {
+	interval time.Duration
+}
+
+// WithWaitInterval sets how long to wait between checks of the table; the default is 1 second, which
+// suits DynamoDB Local, where tables are ready almost at once
+func WithWaitInterval(interval time.Duration) WaitOption {
+	return func(c *waitConfig) {
+		c.interval = interval
+	}
+}
+
+// WaitUntilTableActive waits until a table, with the client's table name prefix and suffix, is ACTIVE
+// and so are all its global secondary indexes. It waits until ctx is done, so give ctx a deadline.
+func WaitUntilTableActive(ctx context.Context, tableName string, options ...WaitOption) error {
+	return waitForTable(ctx, tableName, options, func(table *types.TableDescription, err error) (bool, error) {
+		if isTableNotFound(err) {
+			// A new table can take a moment to become visible
+			return false, nil
+		}
+		if err != nil {
+			return false, err
+		}
+		if table.TableStatus != types.TableStatusActive {
+			return false, nil
+		}
+		for _, index := range table.GlobalSecondaryIndexes {
+			if index.IndexStatus != types.IndexStatusActive {
+				return false, nil
+			}
+		}
+		return true, nil
+	})
+}
+
+// WaitUntilTableDeleted waits until a table, with the client's table name prefix and suffix, no longer
+// exists. It waits until ctx is done, so give ctx a deadline.
+func WaitUntilTableDeleted(ctx context.Context, tableName string, options ...WaitOption) error {
+	return waitForTable(ctx, tableName, options, func(table *types.TableDescription, err error) (bool, error) {
+		if isTableNotFound(err) {
+			return true, nil
+		}
+		return false, err
+	})
+}
+
+// waitForTable describes the table every interval until done reports true or an error
+func waitForTable(ctx context.Context, tableName string, options []WaitOption, done func(table *types.TableDescription, err error) (bool, error)) error {
+	config := waitConfig{interval: time.Second}
+	for _, option := range options {
+		option(&config)
+	}
+
+	for {
+		finished, err := done(DescribeTable(ctx, tableName))
+		if err != nil || finished {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return kit.WrapError(ctx.Err(), "stopped waiting for table %s", tableName)
+		case <-time.After(config.interval):
+		}
+	}
+}
+
+func isTableNotFound(err error) bool {
+	var notFound *types.ResourceNotFoundException
+	return errors.As(err, &notFound)
+}
@@ -0,0 +1,286 @@
+package dynamodbkit
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func setFakeDB(t *testing.T, fakeDB *FakeDynamoDB) {
+	setFake(func(ctx context.Context) (DynamoDB, error) { return fakeDB, nil })
+	t.Cleanup(func() { setFake(nil) })
+}
+
+func TestCreateTable(t *testing.T) {
+	t.Run("creates_an_on_demand_table_keyed_by_the_partition_key", func(t *testing.T) {
+		var actualInput *dynamodb.CreateTableInput
+		setFakeDB(t, &FakeDynamoDB{
+			CreateTableFake: func(ctx context.Context, params *dynamodb.CreateTableInput, optFns ...func(*dynamodb.Options)) (*dynamodb.CreateTableOutput, error) {
+				actualInput = params
+				return &dynamodb.CreateTableOutput{}, nil
+			},
+		})
+
+		err := CreateTable(context.Background(), "theTable", "id", types.ScalarAttributeTypeS)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "theTable", aws.ToString(actualInput.TableName))
+		assert.Equal(t, types.BillingModePayPerRequest, actualInput.BillingMode)
+		assert.Equal(t, []types.KeySchemaElement{{AttributeName: aws.String("id"), KeyType: types.KeyTypeHash}}, actualInput.KeySchema)
+		assert.Equal(t, []types.AttributeDefinition{{AttributeName: aws.String("id"), AttributeType: types.ScalarAttributeTypeS}}, actualInput.AttributeDefinitions)
+	})
+
+	t.Run("creates_the_table_with_the_global_suffix", func(t *testing.T) {
+		var actualTableName string
+		setFakeDB(t, &FakeDynamoDB{
+			CreateTableFake: func(ctx context.Context, params *dynamodb.CreateTableInput, optFns ...func(*dynamodb.Options)) (*dynamodb.CreateTableOutput, error) {
+				actualTableName = aws.ToString(params.TableName)
+				return &dynamodb.CreateTableOutput{}, nil
+			},
+		})
+		UseTableNameSuffix("-theSuffix")
+		t.Cleanup(func() { UseTableNameSuffix("") })
+
+		err := CreateTable(context.Background(), "theTable", "id", types.ScalarAttributeTypeS)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "theTable-theSuffix", actualTableName)
+	})
+
+	t.Run("adds_the_sort_key_and_global_secondary_indexes_with_shared_attribute_definitions", func(t *testing.T) {
+		var actualInput *dynamodb.CreateTableInput
+		setFakeDB(t, &FakeDynamoDB{
+			CreateTableFake: func(ctx context.Context, params *dynamodb.CreateTableInput, optFns ...func(*dynamodb.Options)) (*dynamodb.CreateTableOutput, error) {
+				actualInput = params
+				return &dynamodb.CreateTableOutput{}, nil
+			},
+		})
+
+		err := CreateTable(context.Background(), "theTable", "user_id", types.ScalarAttributeTypeS,
+			WithCreateTableSortKey("timestamp", types.ScalarAttributeTypeN),
+			WithCreateTableGlobalSecondaryIndex("theIndex", "email", types.ScalarAttributeTypeS, "timestamp", types.ScalarAttributeTypeN),
+			WithCreateTableGlobalSecondaryIndex("anotherIndex", "name", types.ScalarAttributeTypeS, "", ""))
+
+		assert.NoError(t, err)
+		assert.Equal(t, []types.KeySchemaElement{
+			{AttributeName: aws.String("user_id"), KeyType: types.KeyTypeHash},
+			{AttributeName: aws.String("timestamp"), KeyType: types.KeyTypeRange},
+		}, actualInput.KeySchema)
+		assert.Equal(t, []types.AttributeDefinition{
+			{AttributeName: aws.String("user_id"), AttributeType: types.ScalarAttributeTypeS},
+			{AttributeName: aws.String("timestamp"), AttributeType: types.ScalarAttributeTypeN},
+			{AttributeName: aws.String("email"), AttributeType: types.ScalarAttributeTypeS},
+			{AttributeName: aws.String("name"), AttributeType: types.ScalarAttributeTypeS},
+		}, actualInput.AttributeDefinitions)
+		assert.Len(t, actualInput.GlobalSecondaryIndexes, 2)
+		assert.Equal(t, "theIndex", aws.ToString(actualInput.GlobalSecondaryIndexes[0].IndexName))
+		assert.Len(t, actualInput.GlobalSecondaryIndexes[0].KeySchema, 2)
+		assert.Equal(t, types.ProjectionTypeAll, actualInput.GlobalSecondaryIndexes[0].Projection.ProjectionType)
+		assert.Equal(t, []types.KeySchemaElement{{AttributeName: aws.String("name"), KeyType: types.KeyTypeHash}}, actualInput.GlobalSecondaryIndexes[1].KeySchema)
+	})
+
+	t.Run("applies_provisioned_throughput_to_the_table_and_its_indexes", func(t *testing.T) {
+		var actualInput *dynamodb.CreateTableInput
+		setFakeDB(t, &FakeDynamoDB{
+			CreateTableFake: func(ctx context.Context, params *dynamodb.CreateTableInput, optFns ...func(*dynamodb.Options)) (*dynamodb.CreateTableOutput, error) {
+				actualInput = params
+				return &dynamodb.CreateTableOutput{}, nil
+			},
+		})
+
+		err := CreateTable(context.Background(), "theTable", "id", types.ScalarAttributeTypeS,
+			WithCreateTableGlobalSecondaryIndex("theIndex", "email", types.ScalarAttributeTypeS, "", ""),
+			WithCreateTableProvisionedThroughput(5, 10))
+
+		assert.NoError(t, err)
+		assert.Equal(t, types.BillingModeProvisioned, actualInput.BillingMode)
+		assert.Equal(t, int64(5), aws.ToInt64(actualInput.ProvisionedThroughput.ReadCapacityUnits))
+		assert.Equal(t, int64(10), aws.ToInt64(actualInput.ProvisionedThroughput.WriteCapacityUnits))
+		assert.Equal(t, actualInput.ProvisionedThroughput, actualInput.GlobalSecondaryIndexes[0].ProvisionedThroughput)
+	})
+
+	t.Run("turns_on_the_stream", func(t *testing.T) {
+		var actualInput *dynamodb.CreateTableInput
+		setFakeDB(t, &FakeDynamoDB{
+			CreateTableFake: func(ctx context.Context, params *dynamodb.CreateTableInput, optFns ...func(*dynamodb.Options)) (*dynamodb.CreateTableOutput, error) {
+				actualInput = params
+				return &dynamodb.CreateTableOutput{}, nil
+			},
+		})
+
+		err := CreateTable(context.Background(), "theTable", "id", types.ScalarAttributeTypeS, WithCreateTableStream(types.StreamViewTypeNewAndOldImages))
+
+		assert.NoError(t, err)
+		assert.True(t, aws.ToBool(actualInput.StreamSpecification.StreamEnabled))
+		assert.Equal(t, types.StreamViewTypeNewAndOldImages, actualInput.StreamSpecification.StreamViewType)
+	})
+
+	t.Run("returns_an_error_when_a_key_attribute_has_two_types", func(t *testing.T) {
+		setFakeDB(t, &FakeDynamoDB{})
+
+		err := CreateTable(context.Background(), "theTable", "id", types.ScalarAttributeTypeS,
+			WithCreateTableGlobalSecondaryIndex("theIndex", "id", types.ScalarAttributeTypeN, "", ""))
+
+		assert.EqualError(t, err, "dynamodbkit.CreateTable table=theTable: error applying create table option: key attribute id is both S and N")
+	})
+
+	t.Run("returns_an_error_when_the_partition_key_is_empty", func(t *testing.T) {
+		err := CreateTable(context.Background(), "theTable", "", types.ScalarAttributeTypeS)
+
+		assert.EqualError(t, err, "dynamodbkit.CreateTable table=theTable: partition key cannot be empty")
+	})
+
+	t.Run("returns_the_error_from_create_table", func(t *testing.T) {
+		setFakeDB(t, &FakeDynamoDB{
+			CreateTableFake: func(ctx context.Context, params *dynamodb.CreateTableInput, optFns ...func(*dynamodb.Options)) (*dynamodb.CreateTableOutput, error) {
+				return nil, errors.New("the create error")
+			},
+		})
+
+		err := CreateTable(context.Background(), "theTable", "id", types.ScalarAttributeTypeS)
+
+		assert.EqualError(t, err, "dynamodbkit.CreateTable table=theTable: error creating table: the create error")
+	})
+}
+
+func TestDeleteTable(t *testing.T) {
+	t.Run("deletes_the_table_with_the_global_suffix", func(t *testing.T) {
+		var actualTableName string
+		setFakeDB(t, &FakeDynamoDB{
+			DeleteTableFake: func(ctx context.Context, params *dynamodb.DeleteTableInput, optFns ...func(*dynamodb.Options)) (*dynamodb.DeleteTableOutput, error) {
+				actualTableName = aws.ToString(params.TableName)
+				return &dynamodb.DeleteTableOutput{}, nil
+			},
+		})
+		UseTableNameSuffix("-theSuffix")
+		t.Cleanup(func() { UseTableNameSuffix("") })
+
+		err := DeleteTable(context.Background(), "theTable")
+
+		assert.NoError(t, err)
+		assert.Equal(t, "theTable-theSuffix", actualTableName)
+	})
+
+	t.Run("returns_the_error_from_delete_table", func(t *testing.T) {
+		setFakeDB(t, &FakeDynamoDB{
+			DeleteTableFake: func(ctx context.Context, params *dynamodb.DeleteTableInput, optFns ...func(*dynamodb.Options)) (*dynamodb.DeleteTableOutput, error) {
+				return nil, errors.New("the delete error")
+			},
+		})
+
+		err := DeleteTable(context.Background(), "theTable")
+
+		assert.EqualError(t, err, "dynamodbkit.DeleteTable table=theTable: error deleting table: the delete error")
+	})
+}
+
+func TestTableExists(t *testing.T) {
+	t.Run("returns_true_for_a_table_in_any_status", func(t *testing.T) {
+		setFakeTableStatus(t, types.TableStatusCreating, nil)
+
+		exists, err := TableExists(context.Background(), "theTable")
+
+		assert.NoError(t, err)
+		assert.True(t, exists)
+	})
+
+	t.Run("returns_false_when_the_table_is_not_found", func(t *testing.T) {
+		setFakeDB(t, &FakeDynamoDB{
+			DescribeTableFake: func(ctx context.Context, params *dynamodb.DescribeTableInput, optFns ...func(*dynamodb.Options)) (*dynamodb.DescribeTableOutput, error) {
+				return nil, &types.ResourceNotFoundException{Message: aws.String("the table is not found")}
+			},
+		})
+
+		exists, err := TableExists(context.Background(), "theTable")
+
+		assert.NoError(t, err)
+		assert.False(t, exists)
+	})
+
+	t.Run("returns_other_errors_from_describe_table", func(t *testing.T) {
+		setFakeDB(t, &FakeDynamoDB{
+			DescribeTableFake: func(ctx context.Context, params *dynamodb.DescribeTableInput, optFns ...func(*dynamodb.Options)) (*dynamodb.DescribeTableOutput, error) {
+				return nil, errors.New("the describe error")
+			},
+		})
+
+		_, err := TableExists(context.Background(), "theTable")
+
+		assert.EqualError(t, err, "dynamodbkit.DescribeTable table=theTable: error describing table: the describe error")
+	})
+}
+
+func TestWaitUntilTableActive(t *testing.T) {
+	t.Run("waits_until_the_table_and_its_indexes_are_active", func(t *testing.T) {
+		descriptions := []*types.TableDescription{
+			nil,
+			{TableStatus: types.TableStatusCreating},
+			{TableStatus: types.TableStatusActive, GlobalSecondaryIndexes: []types.GlobalSecondaryIndexDescription{{IndexStatus: types.IndexStatusCreating}}},
+			{TableStatus: types.TableStatusActive, GlobalSecondaryIndexes: []types.GlobalSecondaryIndexDescription{{IndexStatus: types.IndexStatusActive}}},
+		}
+		calls := 0
+		setFakeDB(t, &FakeDynamoDB{
+			DescribeTableFake: func(ctx context.Context, params *dynamodb.DescribeTableInput, optFns ...func(*dynamodb.Options)) (*dynamodb.DescribeTableOutput, error) {
+				description := descriptions[calls]
+				calls++
+				if description == nil {
+					return nil, &types.ResourceNotFoundException{Message: aws.String("the table is not found")}
+				}
+				return &dynamodb.DescribeTableOutput{Table: description}, nil
+			},
+		})
+
+		err := WaitUntilTableActive(context.Background(), "theTable", WithWaitInterval(time.Millisecond))
+
+		assert.NoError(t, err)
+		assert.Equal(t, 4, calls)
+	})
+
+	t.Run("returns_an_error_when_the_context_is_done", func(t *testing.T) {
+		setFakeTableStatus(t, types.TableStatusCreating, nil)
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		err := WaitUntilTableActive(ctx, "theTable", WithWaitInterval(time.Hour))
+
+		assert.ErrorIs(t, err, context.Canceled)
+	})
+
+	t.Run("returns_other_errors_from_describe_table", func(t *testing.T) {
+		setFakeDB(t, &FakeDynamoDB{
+			DescribeTableFake: func(ctx context.Context, params *dynamodb.DescribeTableInput, optFns ...func(*dynamodb.Options)) (*dynamodb.DescribeTableOutput, error) {
+				return nil, errors.New("the describe error")
+			},
+		})
+
+		err := WaitUntilTableActive(context.Background(), "theTable")
+
+		assert.EqualError(t, err, "dynamodbkit.DescribeTable table=theTable: error describing table: the describe error")
+	})
+}
+
+func TestWaitUntilTableDeleted(t *testing.T) {
+	t.Run("waits_until_the_table_is_not_found", func(t *testing.T) {
+		calls := 0
+		setFakeDB(t, &FakeDynamoDB{
+			DescribeTableFake: func(ctx context.Context, params *dynamodb.DescribeTableInput, optFns ...func(*dynamodb.Options)) (*dynamodb.DescribeTableOutput, error) {
+				calls++
+				if calls < 3 {
+					return &dynamodb.DescribeTableOutput{Table: &types.TableDescription{TableStatus: types.TableStatusDeleting}}, nil
+				}
+				return nil, &types.ResourceNotFoundException{Message: aws.String("the table is not found")}
+			},
+		})
+
+		err := WaitUntilTableDeleted(context.Background(), "theTable", WithWaitInterval(time.Millisecond))
+
+		assert.NoError(t, err)
+		assert.Equal(t, 3, calls)
+	})
+}
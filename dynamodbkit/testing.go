@@ -19,12 +19,35 @@ func mustMarshalMap(t *testing.T, v any) map[string]types.AttributeValue {
 }
 
 type FakeDynamoDB struct {
-	DeleteItemFake func(ctx context.Context, params *dynamodb.DeleteItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.DeleteItemOutput, error)
-	GetItemFake    func(ctx context.Context, params *dynamodb.GetItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.GetItemOutput, error)
-	ListTablesFake func(ctx context.Context, params *dynamodb.ListTablesInput, optFns ...func(*dynamodb.Options)) (*dynamodb.ListTablesOutput, error)
-	PutItemFake    func(ctx context.Context, params *dynamodb.PutItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.PutItemOutput, error)
-	QueryFake      func(ctx context.Context, params *dynamodb.QueryInput, optFns ...func(*dynamodb.Options)) (*dynamodb.QueryOutput, error)
-	ScanFake       func(ctx context.Context, params *dynamodb.ScanInput, optFns ...func(*dynamodb.Options)) (*dynamodb.ScanOutput, error)
+	BatchWriteItemFake     func(ctx context.Context, params *dynamodb.BatchWriteItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.BatchWriteItemOutput, error)
+	CreateTableFake        func(ctx context.Context, params *dynamodb.CreateTableInput, optFns ...func(*dynamodb.Options)) (*dynamodb.CreateTableOutput, error)
+	DeleteItemFake         func(ctx context.Context, params *dynamodb.DeleteItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.DeleteItemOutput, error)
+	DeleteTableFake        func(ctx context.Context, params *dynamodb.DeleteTableInput, optFns ...func(*dynamodb.Options)) (*dynamodb.DeleteTableOutput, error)
+	DescribeTableFake      func(ctx context.Context, params *dynamodb.DescribeTableInput, optFns ...func(*dynamodb.Options)) (*dynamodb.DescribeTableOutput, error)
+	ExecuteStatementFake   func(ctx context.Context, params *dynamodb.ExecuteStatementInput, optFns ...func(*dynamodb.Options)) (*dynamodb.ExecuteStatementOutput, error)
+	GetItemFake            func(ctx context.Context, params *dynamodb.GetItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.GetItemOutput, error)
+	ListTablesFake         func(ctx context.Context, params *dynamodb.ListTablesInput, optFns ...func(*dynamodb.Options)) (*dynamodb.ListTablesOutput, error)
+	PutItemFake            func(ctx context.Context, params *dynamodb.PutItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.PutItemOutput, error)
+	QueryFake              func(ctx context.Context, params *dynamodb.QueryInput, optFns ...func(*dynamodb.Options)) (*dynamodb.QueryOutput, error)
+	ScanFake               func(ctx context.Context, params *dynamodb.ScanInput, optFns ...func(*dynamodb.Options)) (*dynamodb.ScanOutput, error)
+	TransactWriteItemsFake func(ctx context.Context, params *dynamodb.TransactWriteItemsInput, optFns ...func(*dynamodb.Options)) (*dynamodb.TransactWriteItemsOutput, error)
+	UpdateItemFake         func(ctx context.Context, params *dynamodb.UpdateItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.UpdateItemOutput, error)
+}
+
+func (f *FakeDynamoDB) BatchWriteItem(ctx context.Context, params *dynamodb.BatchWriteItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.BatchWriteItemOutput, error) {
+	if f.BatchWriteItemFake != nil {
+		return f.BatchWriteItemFake(ctx, params, optFns...)
+	} else {
+		panic("BatchWriteItem fake not implemented")
+	}
+}
+
+func (f *FakeDynamoDB) CreateTable(ctx context.Context, params *dynamodb.CreateTableInput, optFns ...func(*dynamodb.Options)) (*dynamodb.CreateTableOutput, error) {
+	if f.CreateTableFake != nil {
+		return f.CreateTableFake(ctx, params, optFns...)
+	} else {
+		panic("CreateTable fake not implemented")
+	}
 }
 
 func (f *FakeDynamoDB) DeleteItem(ctx context.Context, params *dynamodb.DeleteItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.DeleteItemOutput, error) {
@@ -35,6 +58,30 @@ func (f *FakeDynamoDB) DeleteItem(ctx context.Context, params *dynamodb.DeleteIt
 	}
 }
 
+func (f *FakeDynamoDB) DeleteTable(ctx context.Context, params *dynamodb.DeleteTableInput, optFns ...func(*dynamodb.Options)) (*dynamodb.DeleteTableOutput, error) {
+	if f.DeleteTableFake != nil {
+		return f.DeleteTableFake(ctx, params, optFns...)
+	} else {
+		panic("DeleteTable fake not implemented")
+	}
+}
+
+func (f *FakeDynamoDB) DescribeTable(ctx context.Context, params *dynamodb.DescribeTableInput, optFns ...func(*dynamodb.Options)) (*dynamodb.DescribeTableOutput, error) {
+	if f.DescribeTableFake != nil {
+		return f.DescribeTableFake(ctx, params, optFns...)
+	} else {
+		panic("DescribeTable fake not implemented")
+	}
+}
+
+func (f *FakeDynamoDB) ExecuteStatement(ctx context.Context, params *dynamodb.ExecuteStatementInput, optFns ...func(*dynamodb.Options)) (*dynamodb.ExecuteStatementOutput, error) {
+	if f.ExecuteStatementFake != nil {
+		return f.ExecuteStatementFake(ctx, params, optFns...)
+	} else {
+		panic("ExecuteStatement fake not implemented")
+	}
+}
+
 func (f *FakeDynamoDB) GetItem(ctx context.Context, params *dynamodb.GetItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.GetItemOutput, error) {
 	if f.GetItemFake != nil {
 		return f.GetItemFake(ctx, params, optFns...)
@@ -75,6 +122,22 @@ func (f *FakeDynamoDB) Scan(ctx context.Context, params *dynamodb.ScanInput, opt
 	}
 }
 
+func (f *FakeDynamoDB) TransactWriteItems(ctx context.Context, params *dynamodb.TransactWriteItemsInput, optFns ...func(*dynamodb.Options)) (*dynamodb.TransactWriteItemsOutput, error) {
+	if f.TransactWriteItemsFake != nil {
+		return f.TransactWriteItemsFake(ctx, params, optFns...)
+	} else {
+		panic("TransactWriteItems fake not implemented")
+	}
+}
+
+func (f *FakeDynamoDB) UpdateItem(ctx context.Context, params *dynamodb.UpdateItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.UpdateItemOutput, error) {
+	if f.UpdateItemFake != nil {
+		return f.UpdateItemFake(ctx, params, optFns...)
+	} else {
+		panic("UpdateItem fake not implemented")
+	}
+}
+
 // TestUser is a common test model used across test files
 type TestUser struct {
 	ID    string `dynamodbav:"id"`
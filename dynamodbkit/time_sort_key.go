@@ -0,0 +1,164 @@
+package dynamodbkit
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/half-ogre/go-kit/kit"
+)
+
+// TimePrecision controls how much of a time.Time is kept when it is encoded as a sort key.
+type TimePrecision int
+
+const (
+	TimePrecisionSecond TimePrecision = iota
+	TimePrecisionMillisecond
+	TimePrecisionMicrosecond
+	TimePrecisionNanosecond
+)
+
+// SortKeyFromTime encodes t as a UTC RFC3339 timestamp with a fixed number of fractional
+// digits for the given precision (e.g. 2024-01-02T03:04:05.000Z), so that encoded values
+// sort lexicographically in the same order as the times they represent.
+func SortKeyFromTime(t time.Time, precision TimePrecision) (string, error) {
+	layout, err := rfc3339Layout(precision)
+	if err != nil {
+		return "", err
+	}
+
+	t = t.UTC()
+	if t.Year() < 0 || t.Year() > 9999 {
+		return "", fmt.Errorf("time %v is outside the range of a four digit year", t)
+	}
+
+	return t.Truncate(precision.duration()).Format(layout), nil
+}
+
+// EpochSortKeyFromTime encodes t as a zero-padded Unix epoch in the unit of the given
+// precision (10 digits for seconds, 13 for milliseconds, 16 for microseconds, 19 for
+// nanoseconds), so that encoded values sort lexicographically.
+func EpochSortKeyFromTime(t time.Time, precision TimePrecision) (string, error) {
+	width, err := epochWidth(precision)
+	if err != nil {
+		return "", err
+	}
+
+	var epoch int64
+	switch precision {
+	case TimePrecisionSecond:
+		epoch = t.Unix()
+	case TimePrecisionMillisecond:
+		epoch = t.UnixMilli()
+	case TimePrecisionMicrosecond:
+		epoch = t.UnixMicro()
+	case TimePrecisionNanosecond:
+		epoch = t.UnixNano()
+	}
+
+	if epoch < 0 {
+		return "", fmt.Errorf("time %v is before the Unix epoch", t)
+	}
+
+	s := fmt.Sprintf("%0*d", width, epoch)
+	if len(s) > width {
+		return "", fmt.Errorf("time %v does not fit in a %d digit epoch", t, width)
+	}
+
+	return s, nil
+}
+
+// TimeFromSortKey decodes a sort key produced by SortKeyFromTime or EpochSortKeyFromTime.
+// Epoch sort keys are recognized by being all digits, with their precision inferred from
+// their width. Sort keys may carry a prefix (e.g. "ORDER#2024-01-02T03:04:05Z"); everything
+// up to and including the last '#' is ignored.
+func TimeFromSortKey(sortKey string) (time.Time, error) {
+	value := sortKey
+	if i := strings.LastIndex(value, "#"); i >= 0 {
+		value = value[i+1:]
+	}
+
+	if value == "" {
+		return time.Time{}, fmt.Errorf("sort key %s does not contain a time", sortKey)
+	}
+
+	if isAllDigits(value) {
+		epoch, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return time.Time{}, kit.WrapError(err, "failed to parse epoch sort key %s", sortKey)
+		}
+
+		switch len(value) {
+		case 10:
+			return time.Unix(epoch, 0).UTC(), nil
+		case 13:
+			return time.UnixMilli(epoch).UTC(), nil
+		case 16:
+			return time.UnixMicro(epoch).UTC(), nil
+		case 19:
+			return time.Unix(0, epoch).UTC(), nil
+		default:
+			return time.Time{}, fmt.Errorf("epoch sort key %s has unexpected width %d", sortKey, len(value))
+		}
+	}
+
+	t, err := time.Parse(time.RFC3339Nano, value)
+	if err != nil {
+		return time.Time{}, kit.WrapError(err, "failed to parse sort key %s as RFC3339", sortKey)
+	}
+
+	return t.UTC(), nil
+}
+
+func (p TimePrecision) duration() time.Duration {
+	switch p {
+	case TimePrecisionMillisecond:
+		return time.Millisecond
+	case TimePrecisionMicrosecond:
+		return time.Microsecond
+	case TimePrecisionNanosecond:
+		return time.Nanosecond
+	default:
+		return time.Second
+	}
+}
+
+func rfc3339Layout(precision TimePrecision) (string, error) {
+	switch precision {
+	case TimePrecisionSecond:
+		return "2006-01-02T15:04:05Z", nil
+	case TimePrecisionMillisecond:
+		return "2006-01-02T15:04:05.000Z", nil
+	case TimePrecisionMicrosecond:
+		return "2006-01-02T15:04:05.000000Z", nil
+	case TimePrecisionNanosecond:
+		return "2006-01-02T15:04:05.000000000Z", nil
+	default:
+		return "", fmt.Errorf("unknown time precision %d", precision)
+	}
+}
+
+func epochWidth(precision TimePrecision) (int, error) {
+	switch precision {
+	case TimePrecisionSecond:
+		return 10, nil
+	case TimePrecisionMillisecond:
+		return 13, nil
+	case TimePrecisionMicrosecond:
+		return 16, nil
+	case TimePrecisionNanosecond:
+		return 19, nil
+	default:
+		return 0, fmt.Errorf("unknown time precision %d", precision)
+	}
+}
+
+func isAllDigits(s string) bool {
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,208 @@
+package dynamodbkit
+
+import (
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSortKeyFromTime(t *testing.T) {
+	theTime := time.Date(2024, 1, 2, 3, 4, 5, 123456789, time.UTC)
+
+	t.Run("encodes_second_precision", func(t *testing.T) {
+		result, err := SortKeyFromTime(theTime, TimePrecisionSecond)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "2024-01-02T03:04:05Z", result)
+	})
+
+	t.Run("encodes_millisecond_precision", func(t *testing.T) {
+		result, err := SortKeyFromTime(theTime, TimePrecisionMillisecond)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "2024-01-02T03:04:05.123Z", result)
+	})
+
+	t.Run("encodes_microsecond_precision", func(t *testing.T) {
+		result, err := SortKeyFromTime(theTime, TimePrecisionMicrosecond)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "2024-01-02T03:04:05.123456Z", result)
+	})
+
+	t.Run("encodes_nanosecond_precision", func(t *testing.T) {
+		result, err := SortKeyFromTime(theTime, TimePrecisionNanosecond)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "2024-01-02T03:04:05.123456789Z", result)
+	})
+
+	t.Run("keeps_trailing_zeros_so_keys_have_a_fixed_width", func(t *testing.T) {
+		result, err := SortKeyFromTime(time.Date(2024, 1, 2, 3, 4, 5, 100000000, time.UTC), TimePrecisionMillisecond)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "2024-01-02T03:04:05.100Z", result)
+	})
+
+	t.Run("converts_to_utc", func(t *testing.T) {
+		theZone := time.FixedZone("aZone", -5*60*60)
+
+		result, err := SortKeyFromTime(time.Date(2024, 1, 1, 22, 0, 0, 0, theZone), TimePrecisionSecond)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "2024-01-02T03:00:00Z", result)
+	})
+
+	t.Run("encoded_keys_sort_in_time_order", func(t *testing.T) {
+		times := []time.Time{
+			time.Date(2024, 1, 2, 3, 4, 5, 900000000, time.UTC),
+			time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
+			time.Date(2023, 12, 31, 23, 59, 59, 0, time.UTC),
+			time.Date(2024, 1, 2, 3, 4, 5, 100000000, time.UTC),
+		}
+		keys := []string{}
+		for _, tm := range times {
+			key, err := SortKeyFromTime(tm, TimePrecisionMillisecond)
+			assert.NoError(t, err)
+			keys = append(keys, key)
+		}
+
+		sort.Strings(keys)
+
+		assert.Equal(t, []string{
+			"2023-12-31T23:59:59.000Z",
+			"2024-01-02T03:04:05.000Z",
+			"2024-01-02T03:04:05.100Z",
+			"2024-01-02T03:04:05.900Z",
+		}, keys)
+	})
+
+	t.Run("returns_an_error_for_an_unknown_precision", func(t *testing.T) {
+		result, err := SortKeyFromTime(theTime, TimePrecision(42))
+
+		assert.Empty(t, result)
+		assert.Contains(t, err.Error(), "unknown time precision 42")
+	})
+
+	t.Run("returns_an_error_when_the_year_has_more_than_four_digits", func(t *testing.T) {
+		result, err := SortKeyFromTime(time.Date(10000, 1, 1, 0, 0, 0, 0, time.UTC), TimePrecisionSecond)
+
+		assert.Empty(t, result)
+		assert.Contains(t, err.Error(), "outside the range of a four digit year")
+	})
+}
+
+func TestEpochSortKeyFromTime(t *testing.T) {
+	theTime := time.Date(2024, 1, 2, 3, 4, 5, 123456789, time.UTC)
+
+	t.Run("encodes_second_precision", func(t *testing.T) {
+		result, err := EpochSortKeyFromTime(theTime, TimePrecisionSecond)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "1704164645", result)
+	})
+
+	t.Run("encodes_millisecond_precision", func(t *testing.T) {
+		result, err := EpochSortKeyFromTime(theTime, TimePrecisionMillisecond)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "1704164645123", result)
+	})
+
+	t.Run("encodes_microsecond_precision", func(t *testing.T) {
+		result, err := EpochSortKeyFromTime(theTime, TimePrecisionMicrosecond)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "1704164645123456", result)
+	})
+
+	t.Run("encodes_nanosecond_precision", func(t *testing.T) {
+		result, err := EpochSortKeyFromTime(theTime, TimePrecisionNanosecond)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "1704164645123456789", result)
+	})
+
+	t.Run("zero_pads_early_times", func(t *testing.T) {
+		result, err := EpochSortKeyFromTime(time.Unix(42, 0), TimePrecisionSecond)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "0000000042", result)
+	})
+
+	t.Run("returns_an_error_for_times_before_the_epoch", func(t *testing.T) {
+		result, err := EpochSortKeyFromTime(time.Date(1969, 12, 31, 0, 0, 0, 0, time.UTC), TimePrecisionSecond)
+
+		assert.Empty(t, result)
+		assert.Contains(t, err.Error(), "before the Unix epoch")
+	})
+
+	t.Run("returns_an_error_when_the_epoch_does_not_fit_the_width", func(t *testing.T) {
+		result, err := EpochSortKeyFromTime(time.Date(2300, 1, 1, 0, 0, 0, 0, time.UTC), TimePrecisionSecond)
+
+		assert.Empty(t, result)
+		assert.Contains(t, err.Error(), "does not fit in a 10 digit epoch")
+	})
+
+	t.Run("returns_an_error_for_an_unknown_precision", func(t *testing.T) {
+		result, err := EpochSortKeyFromTime(theTime, TimePrecision(42))
+
+		assert.Empty(t, result)
+		assert.Contains(t, err.Error(), "unknown time precision 42")
+	})
+}
+
+func TestTimeFromSortKey(t *testing.T) {
+	theTime := time.Date(2024, 1, 2, 3, 4, 5, 123456789, time.UTC)
+
+	t.Run("round_trips_rfc3339_sort_keys", func(t *testing.T) {
+		for _, precision := range []TimePrecision{TimePrecisionSecond, TimePrecisionMillisecond, TimePrecisionMicrosecond, TimePrecisionNanosecond} {
+			sortKey, err := SortKeyFromTime(theTime, precision)
+			assert.NoError(t, err)
+
+			result, err := TimeFromSortKey(sortKey)
+
+			assert.NoError(t, err)
+			assert.Equal(t, theTime.Truncate(precision.duration()), result)
+		}
+	})
+
+	t.Run("round_trips_epoch_sort_keys", func(t *testing.T) {
+		for _, precision := range []TimePrecision{TimePrecisionSecond, TimePrecisionMillisecond, TimePrecisionMicrosecond, TimePrecisionNanosecond} {
+			sortKey, err := EpochSortKeyFromTime(theTime, precision)
+			assert.NoError(t, err)
+
+			result, err := TimeFromSortKey(sortKey)
+
+			assert.NoError(t, err)
+			assert.Equal(t, theTime.Truncate(precision.duration()), result)
+		}
+	})
+
+	t.Run("ignores_a_prefix_ending_in_a_hash", func(t *testing.T) {
+		result, err := TimeFromSortKey("ORDER#2024-01-02T03:04:05Z")
+
+		assert.NoError(t, err)
+		assert.Equal(t, time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC), result)
+	})
+
+	t.Run("returns_an_error_when_the_sort_key_has_no_time", func(t *testing.T) {
+		_, err := TimeFromSortKey("ORDER#")
+
+		assert.Contains(t, err.Error(), "does not contain a time")
+	})
+
+	t.Run("returns_an_error_for_an_epoch_with_an_unexpected_width", func(t *testing.T) {
+		_, err := TimeFromSortKey("12345")
+
+		assert.Contains(t, err.Error(), "unexpected width 5")
+	})
+
+	t.Run("returns_an_error_for_a_malformed_timestamp", func(t *testing.T) {
+		_, err := TimeFromSortKey("not-a-time")
+
+		assert.Contains(t, err.Error(), "failed to parse sort key not-a-time as RFC3339")
+	})
+}
@@ -0,0 +1,197 @@
+package dynamodbkit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/expression"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/half-ogre/go-kit/kit"
+)
+
+// UpdateItem applies the updates in options (WithUpdateSet, WithUpdateRemove, WithUpdateAdd) to an item
+// and returns it as it is after the update. It takes at least one update; like UpdateItem in the SDK it
+// creates the item if it doesn't exist, unless WithUpdateConditionExpression prevents it. The item is
+// nil when WithUpdateItemReturnValues asks for no values.
+func UpdateItem[TItem any, TPartitionKey string | int](ctx context.Context, tableName string, partitionKey string, partitionKeyValue TPartitionKey, options ...UpdateItemOption) (_ *TItem, err error) {
+	op := newOperation(ctx, "UpdateItem", tableName)
+	defer op.wrap(&err)
+
+	partitionKeyAttributeValue, err := getKeyAttributeValue(partitionKeyValue)
+	if err != nil {
+		return nil, err
+	}
+
+	config := &updateItemConfig{
+		input: &dynamodb.UpdateItemInput{
+			TableName: aws.String(tableName),
+			Key: map[string]types.AttributeValue{
+				partitionKey: partitionKeyAttributeValue,
+			},
+			ReturnValues: types.ReturnValueAllNew,
+		},
+	}
+
+	op.setKey(partitionKey, config.input.Key)
+	originalTableNamePtr := config.input.TableName
+
+	for _, option := range options {
+		err := option(config)
+		if err != nil {
+			return nil, kit.WrapError(err, "error processing option")
+		}
+	}
+
+	if !config.hasUpdate {
+		return nil, errors.New("update item requires at least one of WithUpdateSet, WithUpdateRemove, or WithUpdateAdd")
+	}
+
+	builder := expression.NewBuilder().WithUpdate(config.update)
+	if config.condition != nil {
+		builder = builder.WithCondition(*config.condition)
+	}
+	expr, err := builder.Build()
+	if err != nil {
+		return nil, kit.WrapError(err, "error building expression")
+	}
+
+	updateItemInput := config.input
+	updateItemInput.UpdateExpression = expr.Update()
+	updateItemInput.ConditionExpression = expr.Condition()
+	updateItemInput.ExpressionAttributeNames = expr.Names()
+	updateItemInput.ExpressionAttributeValues = expr.Values()
+
+	// Apply the client's table name prefix, and its suffix if table name pointer wasn't changed by options
+	updateItemInput.TableName = op.client.qualifyTableName(updateItemInput.TableName, originalTableNamePtr)
+	op.table = *updateItemInput.TableName
+
+	db, err := op.dynamoDB(ctx)
+	if err != nil {
+		return nil, kit.WrapError(err, "error creating DynamoDB client")
+	}
+
+	logger.Debug("updating DynamoDB item", "input", updateItemInput)
+
+	metrics := startOperation(op, "UpdateItem", updateItemInput.TableName)
+	defer func() { metrics.finish(ctx, err) }()
+	updateItemInput.ReturnConsumedCapacity = op.client.returnConsumedCapacity(metrics)
+
+	var attributes map[string]types.AttributeValue
+	var consumedCapacity *types.ConsumedCapacity
+	if audit := op.client.audit; audit != nil {
+		attributes, err = updateItemWithAudit(ctx, db, audit, updateItemInput)
+	} else {
+		var updater UpdateItemAPI
+		updater, err = requireAPI[UpdateItemAPI](db, "UpdateItem")
+		if err == nil {
+			var output *dynamodb.UpdateItemOutput
+			output, err = updater.UpdateItem(ctx, updateItemInput)
+			if output != nil {
+				attributes = output.Attributes
+				consumedCapacity = output.ConsumedCapacity
+			}
+		}
+	}
+	if err != nil {
+		return nil, kit.WrapError(markConditionFailed(err), "error updating item")
+	}
+	metrics.addResponse(1, consumedCapacity)
+
+	if len(attributes) == 0 {
+		return nil, nil
+	}
+
+	var item TItem
+	err = attributevalue.UnmarshalMap(attributes, &item)
+	if err != nil {
+		return nil, kit.WrapError(err, "failed to unmarshal updated item")
+	}
+
+	return &item, nil
+}
+
+type updateItemConfig struct {
+	input     *dynamodb.UpdateItemInput
+	update    expression.UpdateBuilder
+	hasUpdate bool
+	condition *expression.ConditionBuilder
+}
+
+type UpdateItemOption func(*updateItemConfig) error
+
+// WithUpdateSet sets attribute to value
+func WithUpdateSet(attribute string, value any) UpdateItemOption {
+	return func(config *updateItemConfig) error {
+		config.update = config.update.Set(expression.Name(attribute), expression.Value(value))
+		config.hasUpdate = true
+		return nil
+	}
+}
+
+// WithUpdateRemove removes attribute from the item
+func WithUpdateRemove(attribute string) UpdateItemOption {
+	return func(config *updateItemConfig) error {
+		config.update = config.update.Remove(expression.Name(attribute))
+		config.hasUpdate = true
+		return nil
+	}
+}
+
+// WithUpdateAdd adds value to attribute, which must be a number or a set; a missing attribute is
+// treated as zero or the empty set
+func WithUpdateAdd(attribute string, value any) UpdateItemOption {
+	return func(config *updateItemConfig) error {
+		config.update = config.update.Add(expression.Name(attribute), expression.Value(value))
+		config.hasUpdate = true
+		return nil
+	}
+}
+
+// WithUpdateConditionExpression makes the update conditional, e.g.
+// expression.AttributeExists(expression.Name("id")) to only update an existing item. When the condition
+// isn't met, the error is ErrConditionFailed.
+func WithUpdateConditionExpression(condition expression.ConditionBuilder) UpdateItemOption {
+	return func(config *updateItemConfig) error {
+		config.condition = &condition
+		return nil
+	}
+}
+
+func WithUpdateItemReturnValues(returnValues types.ReturnValue) UpdateItemOption {
+	return func(config *updateItemConfig) error {
+		config.input.ReturnValues = returnValues
+		return nil
+	}
+}
+
+func WithUpdateItemSortKey[TSortKey string | int](sortKey string, sortKeyValue TSortKey) UpdateItemOption {
+	return func(config *updateItemConfig) error {
+		sortKeyAttributeValue, err := getKeyAttributeValue(sortKeyValue)
+		if err != nil {
+			return err
+		}
+
+		config.input.Key[sortKey] = sortKeyAttributeValue
+
+		return nil
+	}
+}
+
+func WithUpdateItemTableNameSuffix(suffix string) UpdateItemOption {
+	return func(config *updateItemConfig) error {
+		input := config.input
+		// Always create a new string to ensure pointer comparison detects change
+		if suffix == "" {
+			// Create new string with same content to mark as modified
+			newTableName := *input.TableName
+			input.TableName = &newTableName
+		} else {
+			input.TableName = aws.String(fmt.Sprintf("%s%s", *input.TableName, suffix))
+		}
+		return nil
+	}
+}
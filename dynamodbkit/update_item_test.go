@@ -0,0 +1,182 @@
+package dynamodbkit
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/expression"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUpdateItem(t *testing.T) {
+	t.Run("returns_an_error_when_getting_a_new_dynamodb_connection_returns_an_error", func(t *testing.T) {
+		setFake(func(ctx context.Context) (DynamoDB, error) { return nil, errors.New("the fake error") })
+		t.Cleanup(func() { setFake(nil) })
+
+		_, err := UpdateItem[TestUser](context.Background(), "aTable", "id", "aUserID", WithUpdateSet("name", "aName"))
+
+		assert.EqualError(t, err, "dynamodbkit.UpdateItem table=aTable id=aUserID: error creating DynamoDB client: the fake error")
+	})
+
+	t.Run("returns_an_error_when_there_are_no_updates", func(t *testing.T) {
+		_, err := UpdateItem[TestUser](context.Background(), "aTable", "id", "aUserID")
+
+		assert.EqualError(t, err, "dynamodbkit.UpdateItem table=aTable id=aUserID: update item requires at least one of WithUpdateSet, WithUpdateRemove, or WithUpdateAdd")
+	})
+
+	t.Run("passes_the_table_name_and_key_to_update_item", func(t *testing.T) {
+		var actualInput *dynamodb.UpdateItemInput
+		fakeDB := &FakeDynamoDB{
+			UpdateItemFake: func(ctx context.Context, params *dynamodb.UpdateItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.UpdateItemOutput, error) {
+				actualInput = params
+				return &dynamodb.UpdateItemOutput{}, nil
+			},
+		}
+		setFake(func(ctx context.Context) (DynamoDB, error) { return fakeDB, nil })
+		t.Cleanup(func() { setFake(nil) })
+
+		_, err := UpdateItem[TestUser](context.Background(), "theTableName", "id", 12345, WithUpdateSet("name", "aName"))
+
+		assert.NoError(t, err)
+		assert.Equal(t, "theTableName", *actualInput.TableName)
+		assert.Equal(t, map[string]types.AttributeValue{"id": &types.AttributeValueMemberN{Value: "12345"}}, actualInput.Key)
+	})
+
+	t.Run("builds_the_update_expression_from_the_options", func(t *testing.T) {
+		var actualInput *dynamodb.UpdateItemInput
+		fakeDB := &FakeDynamoDB{
+			UpdateItemFake: func(ctx context.Context, params *dynamodb.UpdateItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.UpdateItemOutput, error) {
+				actualInput = params
+				return &dynamodb.UpdateItemOutput{}, nil
+			},
+		}
+		setFake(func(ctx context.Context) (DynamoDB, error) { return fakeDB, nil })
+		t.Cleanup(func() { setFake(nil) })
+
+		_, err := UpdateItem[TestUser](context.Background(), "aTable", "id", "aUserID",
+			WithUpdateSet("name", "theName"),
+			WithUpdateRemove("email"),
+			WithUpdateAdd("visits", 1))
+
+		assert.NoError(t, err)
+		assert.Equal(t, "ADD #0 :0\nREMOVE #1\nSET #2 = :1\n", *actualInput.UpdateExpression)
+		assert.Equal(t, map[string]string{"#0": "visits", "#1": "email", "#2": "name"}, actualInput.ExpressionAttributeNames)
+		assert.Equal(t, map[string]types.AttributeValue{
+			":0": &types.AttributeValueMemberN{Value: "1"},
+			":1": &types.AttributeValueMemberS{Value: "theName"},
+		}, actualInput.ExpressionAttributeValues)
+		assert.Nil(t, actualInput.ConditionExpression)
+	})
+
+	t.Run("passes_the_condition_expression_to_update_item", func(t *testing.T) {
+		var actualInput *dynamodb.UpdateItemInput
+		fakeDB := &FakeDynamoDB{
+			UpdateItemFake: func(ctx context.Context, params *dynamodb.UpdateItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.UpdateItemOutput, error) {
+				actualInput = params
+				return &dynamodb.UpdateItemOutput{}, nil
+			},
+		}
+		setFake(func(ctx context.Context) (DynamoDB, error) { return fakeDB, nil })
+		t.Cleanup(func() { setFake(nil) })
+
+		_, err := UpdateItem[TestUser](context.Background(), "aTable", "id", "aUserID",
+			WithUpdateSet("name", "theName"),
+			WithUpdateConditionExpression(expression.AttributeExists(expression.Name("id"))))
+
+		assert.NoError(t, err)
+		assert.Equal(t, "attribute_exists (#0)", *actualInput.ConditionExpression)
+		assert.Equal(t, "id", actualInput.ExpressionAttributeNames["#0"])
+	})
+
+	t.Run("returns_the_updated_item", func(t *testing.T) {
+		var actualInput *dynamodb.UpdateItemInput
+		fakeDB := &FakeDynamoDB{
+			UpdateItemFake: func(ctx context.Context, params *dynamodb.UpdateItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.UpdateItemOutput, error) {
+				actualInput = params
+				return &dynamodb.UpdateItemOutput{Attributes: mustMarshalMap(t, TestUser{ID: "theID", Name: "theName"})}, nil
+			},
+		}
+		setFake(func(ctx context.Context) (DynamoDB, error) { return fakeDB, nil })
+		t.Cleanup(func() { setFake(nil) })
+
+		item, err := UpdateItem[TestUser](context.Background(), "aTable", "id", "theID", WithUpdateSet("name", "theName"))
+
+		assert.NoError(t, err)
+		assert.Equal(t, types.ReturnValueAllNew, actualInput.ReturnValues)
+		assert.Equal(t, &TestUser{ID: "theID", Name: "theName"}, item)
+	})
+
+	t.Run("returns_nil_when_no_values_are_returned", func(t *testing.T) {
+		var actualInput *dynamodb.UpdateItemInput
+		fakeDB := &FakeDynamoDB{
+			UpdateItemFake: func(ctx context.Context, params *dynamodb.UpdateItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.UpdateItemOutput, error) {
+				actualInput = params
+				return &dynamodb.UpdateItemOutput{}, nil
+			},
+		}
+		setFake(func(ctx context.Context) (DynamoDB, error) { return fakeDB, nil })
+		t.Cleanup(func() { setFake(nil) })
+
+		item, err := UpdateItem[TestUser](context.Background(), "aTable", "id", "aUserID",
+			WithUpdateSet("name", "aName"),
+			WithUpdateItemReturnValues(types.ReturnValueNone))
+
+		assert.NoError(t, err)
+		assert.Equal(t, types.ReturnValueNone, actualInput.ReturnValues)
+		assert.Nil(t, item)
+	})
+
+	t.Run("applies_the_sort_key_and_table_name_suffix_options", func(t *testing.T) {
+		var actualInput *dynamodb.UpdateItemInput
+		fakeDB := &FakeDynamoDB{
+			UpdateItemFake: func(ctx context.Context, params *dynamodb.UpdateItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.UpdateItemOutput, error) {
+				actualInput = params
+				return &dynamodb.UpdateItemOutput{}, nil
+			},
+		}
+		setFake(func(ctx context.Context) (DynamoDB, error) { return fakeDB, nil })
+		t.Cleanup(func() { setFake(nil) })
+
+		_, err := UpdateItem[TestUserWithSort](context.Background(), "aTable", "user_id", "aUserID",
+			WithUpdateSet("name", "aName"),
+			WithUpdateItemSortKey("timestamp", "2023-01-01"),
+			WithUpdateItemTableNameSuffix("-theSuffix"))
+
+		assert.NoError(t, err)
+		assert.Equal(t, "aTable-theSuffix", *actualInput.TableName)
+		assert.Equal(t, &types.AttributeValueMemberS{Value: "2023-01-01"}, actualInput.Key["timestamp"])
+	})
+
+	t.Run("returns_err_condition_failed_when_the_condition_is_not_met", func(t *testing.T) {
+		fakeDB := &FakeDynamoDB{
+			UpdateItemFake: func(ctx context.Context, params *dynamodb.UpdateItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.UpdateItemOutput, error) {
+				return nil, &types.ConditionalCheckFailedException{}
+			},
+		}
+		setFake(func(ctx context.Context) (DynamoDB, error) { return fakeDB, nil })
+		t.Cleanup(func() { setFake(nil) })
+
+		_, err := UpdateItem[TestUser](context.Background(), "aTable", "id", "anID",
+			WithUpdateSet("name", "aName"),
+			WithUpdateConditionExpression(expression.AttributeExists(expression.Name("id"))))
+
+		assert.ErrorIs(t, err, ErrConditionFailed)
+	})
+
+	t.Run("returns_an_error_when_update_item_returns_an_error", func(t *testing.T) {
+		fakeDB := &FakeDynamoDB{
+			UpdateItemFake: func(ctx context.Context, params *dynamodb.UpdateItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.UpdateItemOutput, error) {
+				return nil, errors.New("the fake error")
+			},
+		}
+		setFake(func(ctx context.Context) (DynamoDB, error) { return fakeDB, nil })
+		t.Cleanup(func() { setFake(nil) })
+
+		_, err := UpdateItem[TestUser](context.Background(), "aTable", "id", "theID", WithUpdateSet("name", "aName"))
+
+		assert.EqualError(t, err, "dynamodbkit.UpdateItem table=aTable id=theID: error updating item: the fake error")
+	})
+}
@@ -0,0 +1,72 @@
+package echokit
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/half-ogre/go-kit/apikeykit"
+)
+
+func TestAPIKeyAuthenticator(t *testing.T) {
+	t.Run("authenticates_a_valid_key_with_its_scopes_as_permissions", func(t *testing.T) {
+		store := apikeykit.NewMemoryStore()
+		theKey, key, _ := apikeykit.Issue(context.Background(), store, "theprefix", apikeykit.WithOwner("theOwner"), apikeykit.WithName("theName"), apikeykit.WithScopes("orders:read"))
+		authenticator := NewAPIKeyAuthenticator(store, "theAudience")
+		c, _ := NewTestGetRequest(echo.New(), "/")
+		c.Request().Header.Set("X-API-Key", theKey)
+
+		err := authenticator.AuthenticateRequest(c)
+
+		assert.NoError(t, err)
+		isAuthenticated, _ := authenticator.IsAuthenticated(c)
+		assert.True(t, isAuthenticated)
+		user, err := authenticator.GetAuthenticatedUser(c)
+		assert.NoError(t, err)
+		assert.Equal(t, &AuthenticatedUser{Sub: "theOwner", Name: "theName", Permissions: map[string][]string{"theAudience": {"orders:read"}}}, user)
+		actualKey, ok := GetAPIKey(c)
+		assert.True(t, ok)
+		assert.Equal(t, key.ID, actualKey.ID)
+	})
+
+	t.Run("uses_the_key_id_as_sub_without_an_owner", func(t *testing.T) {
+		store := apikeykit.NewMemoryStore()
+		theKey, key, _ := apikeykit.Issue(context.Background(), store, "theprefix")
+		authenticator := NewAPIKeyAuthenticator(store, "theAudience")
+		c, _ := NewTestGetRequest(echo.New(), "/")
+		c.Request().Header.Set("Authorization", "Bearer "+theKey)
+
+		_ = authenticator.AuthenticateRequest(c)
+
+		user, _ := authenticator.GetAuthenticatedUser(c)
+		assert.Equal(t, "apikey:"+key.ID, user.Sub)
+	})
+
+	t.Run("returns_unauthorized_for_an_invalid_key", func(t *testing.T) {
+		authenticator := NewAPIKeyAuthenticator(apikeykit.NewMemoryStore(), "theAudience")
+		c, _ := NewTestGetRequest(echo.New(), "/")
+		c.Request().Header.Set("X-API-Key", "not a key")
+
+		err := authenticator.AuthenticateRequest(c)
+
+		assert.Equal(t, echo.NewHTTPError(http.StatusUnauthorized, "invalid API key"), err)
+		isAuthenticated, _ := authenticator.IsAuthenticated(c)
+		assert.False(t, isAuthenticated)
+	})
+
+	t.Run("does_not_authenticate_a_request_without_a_key", func(t *testing.T) {
+		authenticator := NewAPIKeyAuthenticator(apikeykit.NewMemoryStore(), "theAudience")
+		c, _ := NewTestGetRequest(echo.New(), "/")
+
+		err := authenticator.AuthenticateRequest(c)
+
+		assert.NoError(t, err)
+		isAuthenticated, _ := authenticator.IsAuthenticated(c)
+		assert.False(t, isAuthenticated)
+		_, ok := GetAPIKey(c)
+		assert.False(t, ok)
+	})
+}
@@ -0,0 +1,142 @@
+package echokit
+
+import (
+	"log/slog"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/half-ogre/go-kit/internal/audit"
+	"github.com/half-ogre/go-kit/kit"
+)
+
+const (
+	auditActionContextKey   = "github.com/half-ogre/go-kit/echokit/audit_action"
+	auditResourceContextKey = "github.com/half-ogre/go-kit/echokit/audit_resource"
+)
+
+// AuditRecord is the audit event written for each request; ginkit writes the same schema
+type AuditRecord = audit.Record
+
+// AuditSink receives audit records
+type AuditSink = audit.Sink
+
+// AuditSinkFunc adapts a function to the AuditSink interface
+type AuditSinkFunc = audit.SinkFunc
+
+// AuditOutcome is the result of an audited request
+type AuditOutcome = audit.Outcome
+
+const (
+	AuditOutcomeSuccess = audit.OutcomeSuccess
+	AuditOutcomeDenied  = audit.OutcomeDenied
+	AuditOutcomeFailure = audit.OutcomeFailure
+)
+
+// NewSlogAuditSink returns an AuditSink that logs each record as an "audit" message at INFO
+func NewSlogAuditSink(logger *slog.Logger) AuditSink {
+	return audit.NewSlogSink(logger)
+}
+
+type auditLoggerConfig struct {
+	actor func(c echo.Context) string
+	clock kit.ClockInterface
+}
+
+type AuditLoggerOption func(*auditLoggerConfig)
+
+// WithAuditActor sets how the actor is found. By default it is the Sub of the user authenticated by
+// the authentication middleware.
+func WithAuditActor(actor func(c echo.Context) string) AuditLoggerOption {
+	return func(config *auditLoggerConfig) {
+		config.actor = actor
+	}
+}
+
+// WithAuditClock sets the clock used to timestamp audit records
+func WithAuditClock(clock kit.ClockInterface) AuditLoggerOption {
+	return func(config *auditLoggerConfig) {
+		config.clock = clock
+	}
+}
+
+// AuditLogger returns a middleware that writes an audit record to sink for each request once it has
+// been handled. The action defaults to the method and route (e.g. "DELETE /users/:id") and the resource
+// to the request path; handlers can name them with SetAuditAction and SetAuditResource.
+func AuditLogger(sink AuditSink, options ...AuditLoggerOption) echo.MiddlewareFunc {
+	config := &auditLoggerConfig{
+		actor: authenticatedUserSub,
+		clock: kit.NewClock(),
+	}
+	for _, option := range options {
+		option(config)
+	}
+
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			err := next(c)
+			// Let the error handler write the response first so the recorded status is the one sent
+			if err != nil {
+				c.Error(err)
+			}
+
+			req := c.Request()
+			status := c.Response().Status
+
+			action, _ := c.Get(auditActionContextKey).(string)
+			if action == "" {
+				action = req.Method + " " + c.Path()
+			}
+			resource, _ := c.Get(auditResourceContextKey).(string)
+			if resource == "" {
+				resource = req.URL.Path
+			}
+			requestID := req.Header.Get(echo.HeaderXRequestID)
+			if requestID == "" {
+				requestID = c.Response().Header().Get(echo.HeaderXRequestID)
+			}
+
+			audit.Write(req.Context(), sink, AuditRecord{
+				Time:      config.clock.Now(),
+				Actor:     config.actor(c),
+				Action:    action,
+				Resource:  resource,
+				Outcome:   audit.OutcomeForStatus(status),
+				Status:    status,
+				Method:    req.Method,
+				RequestID: requestID,
+				RemoteIP:  c.RealIP(),
+			})
+
+			return nil
+		}
+	}
+}
+
+// SetAuditAction names the action recorded for the request, e.g. "user.delete"
+func SetAuditAction(c echo.Context, action string) {
+	c.Set(auditActionContextKey, action)
+}
+
+// SetAuditResource names the resource recorded for the request, e.g. "user/123"
+func SetAuditResource(c echo.Context, resource string) {
+	c.Set(auditResourceContextKey, resource)
+}
+
+func authenticatedUserSub(c echo.Context) string {
+	authenticator, err := GetAuthenticator(c)
+	if err != nil || authenticator == nil {
+		return ""
+	}
+
+	isAuthenticated, err := authenticator.IsAuthenticated(c)
+	if err != nil || !isAuthenticated {
+		return ""
+	}
+
+	user, err := authenticator.GetAuthenticatedUser(c)
+	if err != nil || user == nil {
+		return ""
+	}
+
+	return user.Sub
+}
@@ -0,0 +1,138 @@
+package echokit
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/half-ogre/go-kit/kit"
+)
+
+func newRecordingAuditSink() (AuditSink, *[]AuditRecord) {
+	records := []AuditRecord{}
+	sink := AuditSinkFunc(func(ctx context.Context, record AuditRecord) error {
+		records = append(records, record)
+		return nil
+	})
+	return sink, &records
+}
+
+func TestAuditLogger(t *testing.T) {
+	now := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	clock := kit.NewClock(kit.WithFake(func() time.Time { return now }))
+
+	t.Run("writes_a_record_for_a_successful_request", func(t *testing.T) {
+		e := echo.New()
+		c, _ := NewTestGetRequest(e, "/users/theID")
+		c.SetPath("/users/:id")
+		c.Request().Header.Set(echo.HeaderXRequestID, "theRequestID")
+		c.Request().RemoteAddr = "192.0.2.1:1234"
+		sink, records := newRecordingAuditSink()
+		handler := AuditLogger(sink, WithAuditClock(clock), WithAuditActor(func(c echo.Context) string { return "theActor" }))(func(c echo.Context) error {
+			return c.NoContent(http.StatusOK)
+		})
+
+		err := handler(c)
+
+		assert.NoError(t, err)
+		assert.Equal(t, []AuditRecord{{
+			Time:      now,
+			Actor:     "theActor",
+			Action:    "GET /users/:id",
+			Resource:  "/users/theID",
+			Outcome:   AuditOutcomeSuccess,
+			Status:    http.StatusOK,
+			Method:    http.MethodGet,
+			RequestID: "theRequestID",
+			RemoteIP:  "192.0.2.1",
+		}}, *records)
+	})
+
+	t.Run("uses_the_action_and_resource_set_by_the_handler", func(t *testing.T) {
+		e := echo.New()
+		c, _ := NewTestGetRequest(e, "/users/theID")
+		sink, records := newRecordingAuditSink()
+		handler := AuditLogger(sink, WithAuditClock(clock))(func(c echo.Context) error {
+			SetAuditAction(c, "theAction")
+			SetAuditResource(c, "theResource")
+			return c.NoContent(http.StatusOK)
+		})
+
+		err := handler(c)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "theAction", (*records)[0].Action)
+		assert.Equal(t, "theResource", (*records)[0].Resource)
+	})
+
+	t.Run("records_a_denied_outcome_from_a_handler_error", func(t *testing.T) {
+		e := echo.New()
+		c, rec := NewTestGetRequest(e, "/")
+		sink, records := newRecordingAuditSink()
+		handler := AuditLogger(sink, WithAuditClock(clock))(func(c echo.Context) error {
+			return echo.NewHTTPError(http.StatusForbidden)
+		})
+
+		err := handler(c)
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusForbidden, rec.Code)
+		assert.Equal(t, AuditOutcomeDenied, (*records)[0].Outcome)
+		assert.Equal(t, http.StatusForbidden, (*records)[0].Status)
+	})
+
+	t.Run("records_a_failure_outcome_from_an_unexpected_error", func(t *testing.T) {
+		e := echo.New()
+		c, _ := NewTestGetRequest(e, "/")
+		sink, records := newRecordingAuditSink()
+		handler := AuditLogger(sink, WithAuditClock(clock))(func(c echo.Context) error {
+			return errors.New("the error")
+		})
+
+		_ = handler(c)
+
+		assert.Equal(t, AuditOutcomeFailure, (*records)[0].Outcome)
+		assert.Equal(t, http.StatusInternalServerError, (*records)[0].Status)
+	})
+
+	t.Run("uses_the_authenticated_user_as_the_actor_by_default", func(t *testing.T) {
+		e := echo.New()
+		c, _ := NewTestGetRequest(e, "/")
+		c.Set(authenticatorContextKey, &FakeAuthenticator{
+			IsAuthenticatedFake: func(c echo.Context) (bool, error) {
+				return true, nil
+			},
+			GetAuthenticatedUserFake: func(c echo.Context) (*AuthenticatedUser, error) {
+				return &AuthenticatedUser{Sub: "theSub"}, nil
+			},
+		})
+		sink, records := newRecordingAuditSink()
+		handler := AuditLogger(sink, WithAuditClock(clock))(func(c echo.Context) error {
+			return c.NoContent(http.StatusOK)
+		})
+
+		err := handler(c)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "theSub", (*records)[0].Actor)
+	})
+
+	t.Run("leaves_the_actor_empty_without_an_authenticator", func(t *testing.T) {
+		e := echo.New()
+		c, _ := NewTestGetRequest(e, "/")
+		sink, records := newRecordingAuditSink()
+		handler := AuditLogger(sink, WithAuditClock(clock))(func(c echo.Context) error {
+			return c.NoContent(http.StatusOK)
+		})
+
+		err := handler(c)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "", (*records)[0].Actor)
+	})
+}
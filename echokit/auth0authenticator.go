@@ -2,12 +2,9 @@ package echokit
 
 import (
 	"context"
-	"crypto/rand"
-	"encoding/base64"
 	"encoding/json"
 	"errors"
 	"fmt"
-	"log/slog"
 	"net/http"
 	"net/url"
 	"time"
@@ -90,7 +87,7 @@ func (a *Auth0Authenticator) GetAuthenticatedUser(c echo.Context) (*Authenticate
 			return nil, kit.WrapError(err, "failed to unmarshal claims")
 		}
 
-		slog.Debug("claims", claims)
+		logger.Debug("claims", claims)
 
 		var permissions []string
 		if permissionsRaw, ok := claimsMap["permissions"]; ok {
@@ -186,7 +183,8 @@ func (a *Auth0Authenticator) HandleAuthenticationCallback(c echo.Context) (bool,
 		return false, kit.WrapError(err, "failed to get auth session")
 	}
 
-	if c.QueryParam("state") != session.Values["state"] {
+	sessionState, _ := session.Values["state"].(string)
+	if sessionState == "" || !kit.ConstantTimeEqual(c.QueryParam("state"), sessionState) {
 		return false, fmt.Errorf("query state %s did not match session state %s", c.QueryParam("state"), session.Values["state"])
 	}
 
@@ -245,7 +243,7 @@ func (a *Auth0Authenticator) GetAuthCodeURL(c echo.Context) (*url.URL, error) {
 		return nil, errors.New("failed to get auth session")
 	}
 
-	state, err := generateRandomState()
+	state, err := kit.RandomBase64URL(32)
 	if err != nil {
 		return nil, kit.WrapError(err, "error generating state")
 	}
@@ -311,15 +309,3 @@ func buildCallbackAuthCodeOption(c echo.Context, path string) (oauth2.AuthCodeOp
 	callbackUrl.Path = path
 	return oauth2.SetAuthURLParam("redirect_uri", callbackUrl.String()), nil
 }
-
-func generateRandomState() (string, error) {
-	b := make([]byte, 32)
-	_, err := rand.Read(b)
-	if err != nil {
-		return "", err
-	}
-
-	state := base64.StdEncoding.EncodeToString(b)
-
-	return state, nil
-}
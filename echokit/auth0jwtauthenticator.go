@@ -48,14 +48,28 @@ func NewAuth0JWTAuthenticator(config Auth0Config) (Authenticator, error) {
 		config: config,
 	}
 
+	jwtValidator, err := NewAuth0JWTValidator(config)
+	if err != nil {
+		return nil, err
+	}
+
+	jwtAuthenticator.jwtValidator = jwtValidator
+
+	return jwtAuthenticator, nil
+}
+
+// NewAuth0JWTValidator returns a validator for RS256 access tokens issued by config.Domain for
+// config.Audience, with Auth0CustomClaims as the custom claims. grpckit uses it to validate the same
+// tokens as NewAuth0JWTAuthenticator.
+func NewAuth0JWTValidator(config Auth0Config) (*validator.Validator, error) {
 	issuerURL, err := url.Parse("https://" + config.Domain + "/")
 	if err != nil {
 		log.Fatalf("Failed to parse the issuer url: %v", err)
 	}
 
-	provider := jwks.NewCachingProvider(issuerURL, 5*time.Minute)
+	provider := jwks.NewCachingProvider(issuerURL, 5*time.Minute, jwks.WithCustomClient(jwksClient))
 
-	jwtValidator, err := validator.New(
+	return validator.New(
 		provider.KeyFunc,
 		validator.RS256,
 		issuerURL.String(),
@@ -67,42 +81,22 @@ func NewAuth0JWTAuthenticator(config Auth0Config) (Authenticator, error) {
 		),
 		validator.WithAllowedClockSkew(time.Minute),
 	)
-	if err != nil {
-		return nil, err
-	}
-
-	jwtAuthenticator.jwtValidator = jwtValidator
-
-	return jwtAuthenticator, nil
 }
 
-func (a *Auth0JWTAuthenticator) AuthenticateRequest(c echo.Context) error {
-	authHeader := c.Request().Header.Get("Authorization")
-	if authHeader == "" {
-		return nil
-	}
-
-	authHeaderParts := strings.Fields(authHeader)
-	if len(authHeaderParts) != 2 || strings.ToLower(authHeaderParts[0]) != "bearer" {
-		return nil
-	}
-
-	validateResult, err := a.jwtValidator.ValidateToken(c.Request().Context(), authHeaderParts[1])
-	if err != nil {
-		return err
-	}
-
+// Auth0AuthenticatedUser maps the result of validating a token with NewAuth0JWTValidator to an
+// AuthenticatedUser whose permissions are granted for audience
+func Auth0AuthenticatedUser(validateResult any, audience string) (*AuthenticatedUser, error) {
 	validatedClaims, ok := validateResult.(*validator.ValidatedClaims)
 	if !ok {
-		return errors.New("failed to cast to ValidatedClaims")
+		return nil, errors.New("failed to cast to ValidatedClaims")
 	}
 
 	customClaims, ok := validatedClaims.CustomClaims.(*Auth0CustomClaims)
 	if !ok {
-		return errors.New("failed to cast custom claims")
+		return nil, errors.New("failed to cast custom claims")
 	}
 
-	authenticatedUser := AuthenticatedUser{
+	return &AuthenticatedUser{
 		Sub:               validatedClaims.RegisteredClaims.Subject,
 		Name:              customClaims.Name,
 		GivenName:         customClaims.GivenName,
@@ -114,10 +108,32 @@ func (a *Auth0JWTAuthenticator) AuthenticateRequest(c echo.Context) error {
 		EmailVerified:     customClaims.EmailVerified,
 		Picture:           customClaims.Picture,
 		UpdatedAt:         customClaims.UpdatedAt,
-		Permissions:       map[string][]string{a.config.Audience: customClaims.Permissions},
+		Permissions:       map[string][]string{audience: customClaims.Permissions},
+	}, nil
+}
+
+func (a *Auth0JWTAuthenticator) AuthenticateRequest(c echo.Context) error {
+	authHeader := c.Request().Header.Get("Authorization")
+	if authHeader == "" {
+		return nil
+	}
+
+	authHeaderParts := strings.Fields(authHeader)
+	if len(authHeaderParts) != 2 || strings.ToLower(authHeaderParts[0]) != "bearer" {
+		return nil
+	}
+
+	validateResult, err := a.jwtValidator.ValidateToken(c.Request().Context(), authHeaderParts[1])
+	if err != nil {
+		return err
+	}
+
+	authenticatedUser, err := Auth0AuthenticatedUser(validateResult, a.config.Audience)
+	if err != nil {
+		return err
 	}
 
-	c.Set(auth0JWTAuthenticatorContextKey, &authenticatedUser)
+	c.Set(auth0JWTAuthenticatorContextKey, authenticatedUser)
 
 	return nil
 }
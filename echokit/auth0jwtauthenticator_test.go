@@ -0,0 +1,39 @@
+package echokit
+
+import (
+	"testing"
+
+	"github.com/auth0/go-jwt-middleware/v2/validator"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAuth0AuthenticatedUser(t *testing.T) {
+	t.Run("maps_the_validated_claims_to_a_user", func(t *testing.T) {
+		theClaims := &validator.ValidatedClaims{
+			RegisteredClaims: validator.RegisteredClaims{Subject: "theSub"},
+			CustomClaims:     &Auth0CustomClaims{Name: "theName", Email: "theEmail", Permissions: []string{"thePermission"}},
+		}
+
+		result, err := Auth0AuthenticatedUser(theClaims, "theAudience")
+
+		assert.NoError(t, err)
+		assert.Equal(t, &AuthenticatedUser{
+			Sub:         "theSub",
+			Name:        "theName",
+			Email:       "theEmail",
+			Permissions: map[string][]string{"theAudience": {"thePermission"}},
+		}, result)
+	})
+
+	t.Run("returns_an_error_for_an_unexpected_result", func(t *testing.T) {
+		_, err := Auth0AuthenticatedUser("theResult", "theAudience")
+
+		assert.EqualError(t, err, "failed to cast to ValidatedClaims")
+	})
+
+	t.Run("returns_an_error_for_unexpected_custom_claims", func(t *testing.T) {
+		_, err := Auth0AuthenticatedUser(&validator.ValidatedClaims{}, "theAudience")
+
+		assert.EqualError(t, err, "failed to cast custom claims")
+	})
+}
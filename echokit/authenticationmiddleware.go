@@ -90,6 +90,12 @@ func WithAuthenticatedUserCallback(callback func(AuthenticatedUser) error) func(
 	}
 }
 
+// SetAuthenticator puts authenticator in the context as the authentication middleware does, for tests of
+// handlers behind RequireAuthenticated or RequirePermissions
+func SetAuthenticator(c echo.Context, authenticator Authenticator) {
+	c.Set(authenticatorContextKey, authenticator)
+}
+
 func GetAuthenticator(c echo.Context) (Authenticator, error) {
 	o := c.Get(authenticatorContextKey)
 	if o == nil {
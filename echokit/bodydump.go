@@ -0,0 +1,268 @@
+package echokit
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"mime"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/half-ogre/go-kit/logkit/logfields"
+	"github.com/labstack/echo/v4"
+)
+
+const redactedValue = "[REDACTED]"
+
+// BodyDumpConfig defines the configuration for the body dump middleware.
+type BodyDumpConfig struct {
+	// DebugPaths is a list of paths whose request and response bodies should be logged.
+	// When empty, bodies are logged for all paths.
+	DebugPaths []string
+
+	// MaxBodySize is the maximum number of bytes logged for each body. Defaults to 4096.
+	MaxBodySize int
+
+	// ContentTypes is a list of media types whose bodies are logged. An entry ending in "/"
+	// matches any subtype (e.g. "text/"). Defaults to JSON, form, and text bodies.
+	ContentTypes []string
+
+	// RedactFields is a list of JSON and form field names whose values are replaced with [REDACTED],
+	// matched case-insensitively at any depth. Defaults to common credential field names.
+	RedactFields []string
+}
+
+// DefaultBodyDumpConfig is the default configuration for BodyDump.
+var DefaultBodyDumpConfig = BodyDumpConfig{
+	MaxBodySize: 4096,
+	ContentTypes: []string{
+		echo.MIMEApplicationJSON,
+		echo.MIMEApplicationForm,
+		"text/",
+	},
+	RedactFields: []string{
+		"password",
+		"secret",
+		"token",
+		"access_token",
+		"refresh_token",
+		"id_token",
+		"client_secret",
+		"authorization",
+		"api_key",
+	},
+}
+
+// BodyDump returns a middleware that logs request and response bodies at DEBUG level, for
+// diagnosing integration issues in non-production environments. Bodies are size-capped,
+// filtered by content type, and have sensitive fields redacted before they are logged.
+// Nothing is captured when the default logger has DEBUG disabled.
+func BodyDump(config BodyDumpConfig) echo.MiddlewareFunc {
+	if config.MaxBodySize <= 0 {
+		config.MaxBodySize = DefaultBodyDumpConfig.MaxBodySize
+	}
+	if len(config.ContentTypes) == 0 {
+		config.ContentTypes = DefaultBodyDumpConfig.ContentTypes
+	}
+	if len(config.RedactFields) == 0 {
+		config.RedactFields = DefaultBodyDumpConfig.RedactFields
+	}
+
+	redactFields := make(map[string]bool, len(config.RedactFields))
+	for _, field := range config.RedactFields {
+		redactFields[strings.ToLower(field)] = true
+	}
+
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			req := c.Request()
+			if !logger.Enabled(req.Context(), slog.LevelDebug) || !isBodyDumpPath(config.DebugPaths, c.Path()) {
+				return next(c)
+			}
+
+			// Capture one byte past the max to tell a truncated body from one exactly at the max, and
+			// stream the rest to the handler rather than buffering a body of any size
+			var reqBody []byte
+			if req.Body != nil {
+				var err error
+				reqBody, err = io.ReadAll(io.LimitReader(req.Body, int64(config.MaxBodySize)+1))
+				if err != nil {
+					return err
+				}
+				req.Body = &bodyDumpRequestBody{Reader: io.MultiReader(bytes.NewReader(reqBody), req.Body), Closer: req.Body}
+			}
+
+			res := c.Response()
+			resCapture := &limitedBuffer{max: config.MaxBodySize}
+			writer := &bodyDumpResponseWriter{Writer: io.MultiWriter(res.Writer, resCapture), ResponseWriter: res.Writer}
+			res.Writer = writer
+			defer func() { res.Writer = writer.ResponseWriter }()
+
+			err := next(c)
+
+			logger.DebugContext(req.Context(), "body dump",
+				logfields.RequestID(req.Header.Get(echo.HeaderXRequestID)),
+				"method", req.Method,
+				"uri", req.RequestURI,
+				"status", res.Status,
+				"request_body", dumpBody(reqBody, len(reqBody) > config.MaxBodySize, req.Header.Get(echo.HeaderContentType), config, redactFields),
+				"response_body", dumpBody(resCapture.buf.Bytes(), resCapture.truncated, res.Header().Get(echo.HeaderContentType), config, redactFields),
+			)
+
+			return err
+		}
+	}
+}
+
+func isBodyDumpPath(debugPaths []string, path string) bool {
+	if len(debugPaths) == 0 {
+		return true
+	}
+	for _, debugPath := range debugPaths {
+		if path == debugPath {
+			return true
+		}
+	}
+	return false
+}
+
+// dumpBody renders a body for logging, returning a placeholder when it should not or cannot be logged
+func dumpBody(body []byte, truncated bool, contentType string, config BodyDumpConfig, redactFields map[string]bool) string {
+	if len(body) == 0 {
+		return ""
+	}
+
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil || !isBodyDumpContentType(config.ContentTypes, mediaType) {
+		return "[OMITTED: content type " + contentType + "]"
+	}
+
+	switch {
+	case mediaType == echo.MIMEApplicationJSON || strings.HasSuffix(mediaType, "+json"):
+		if truncated {
+			// A truncated JSON document can't be parsed, so it can't be redacted safely either
+			return "[OMITTED: JSON body exceeds max body size]"
+		}
+		var value any
+		if err := json.Unmarshal(body, &value); err != nil {
+			return "[OMITTED: invalid JSON body]"
+		}
+		redacted, err := json.Marshal(redactJSONValue(value, redactFields))
+		if err != nil {
+			return "[OMITTED: invalid JSON body]"
+		}
+		body = redacted
+	case mediaType == echo.MIMEApplicationForm:
+		if truncated {
+			return "[OMITTED: form body exceeds max body size]"
+		}
+		values, err := url.ParseQuery(string(body))
+		if err != nil {
+			return "[OMITTED: invalid form body]"
+		}
+		for key := range values {
+			if redactFields[strings.ToLower(key)] {
+				values[key] = []string{redactedValue}
+			}
+		}
+		body = []byte(values.Encode())
+	}
+
+	if len(body) > config.MaxBodySize {
+		return string(body[:config.MaxBodySize]) + "...[TRUNCATED]"
+	}
+	if truncated {
+		return string(body) + "...[TRUNCATED]"
+	}
+	return string(body)
+}
+
+func isBodyDumpContentType(contentTypes []string, mediaType string) bool {
+	for _, contentType := range contentTypes {
+		if strings.HasSuffix(contentType, "/") {
+			if strings.HasPrefix(mediaType, contentType) {
+				return true
+			}
+		} else if mediaType == contentType {
+			return true
+		}
+	}
+	return false
+}
+
+func redactJSONValue(value any, redactFields map[string]bool) any {
+	switch v := value.(type) {
+	case map[string]any:
+		for key, fieldValue := range v {
+			if redactFields[strings.ToLower(key)] {
+				v[key] = redactedValue
+			} else {
+				v[key] = redactJSONValue(fieldValue, redactFields)
+			}
+		}
+		return v
+	case []any:
+		for i, element := range v {
+			v[i] = redactJSONValue(element, redactFields)
+		}
+		return v
+	default:
+		return v
+	}
+}
+
+// limitedBuffer keeps the first max bytes written to it and discards the rest
+type limitedBuffer struct {
+	buf       bytes.Buffer
+	max       int
+	truncated bool
+}
+
+func (b *limitedBuffer) Write(p []byte) (int, error) {
+	remaining := b.max - b.buf.Len()
+	if remaining < len(p) {
+		b.truncated = true
+		if remaining > 0 {
+			b.buf.Write(p[:remaining])
+		}
+		return len(p), nil
+	}
+	return b.buf.Write(p)
+}
+
+// bodyDumpRequestBody replays the captured start of a request body before the unread rest
+type bodyDumpRequestBody struct {
+	io.Reader
+	io.Closer
+}
+
+type bodyDumpResponseWriter struct {
+	io.Writer
+	http.ResponseWriter
+}
+
+func (w *bodyDumpResponseWriter) WriteHeader(code int) {
+	w.ResponseWriter.WriteHeader(code)
+}
+
+func (w *bodyDumpResponseWriter) Write(b []byte) (int, error) {
+	return w.Writer.Write(b)
+}
+
+func (w *bodyDumpResponseWriter) Flush() {
+	if flusher, ok := w.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+func (w *bodyDumpResponseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	return http.NewResponseController(w.ResponseWriter).Hijack()
+}
+
+func (w *bodyDumpResponseWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
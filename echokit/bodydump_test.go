@@ -0,0 +1,201 @@
+package echokit
+
+import (
+	"bytes"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func setBodyDumpTestLogger(t *testing.T, level slog.Level) *bytes.Buffer {
+	var logBuf bytes.Buffer
+	previous := slog.Default()
+	slog.SetDefault(slog.New(slog.NewJSONHandler(&logBuf, &slog.HandlerOptions{Level: level})))
+	t.Cleanup(func() { slog.SetDefault(previous) })
+	return &logBuf
+}
+
+func TestBodyDump(t *testing.T) {
+	t.Run("logs_request_and_response_bodies_at_debug_level", func(t *testing.T) {
+		logBuf := setBodyDumpTestLogger(t, slog.LevelDebug)
+		e := echo.New()
+		e.Use(BodyDump(BodyDumpConfig{}))
+		var actualRequestBody string
+		e.POST("/things", func(c echo.Context) error {
+			body, _ := io.ReadAll(c.Request().Body)
+			actualRequestBody = string(body)
+			return c.String(http.StatusCreated, "theResponse")
+		})
+		req := httptest.NewRequest(http.MethodPost, "/things", strings.NewReader("theRequest"))
+		req.Header.Set(echo.HeaderContentType, echo.MIMETextPlain)
+		rec := httptest.NewRecorder()
+
+		e.ServeHTTP(rec, req)
+
+		assert.Equal(t, "theRequest", actualRequestBody)
+		assert.Equal(t, "theResponse", rec.Body.String())
+		logOutput := logBuf.String()
+		assert.Contains(t, logOutput, `"level":"DEBUG"`)
+		assert.Contains(t, logOutput, `"msg":"body dump"`)
+		assert.Contains(t, logOutput, `"request_body":"theRequest"`)
+		assert.Contains(t, logOutput, `"response_body":"theResponse"`)
+		assert.Contains(t, logOutput, `"status":201`)
+	})
+
+	t.Run("redacts_json_fields_at_any_depth", func(t *testing.T) {
+		logBuf := setBodyDumpTestLogger(t, slog.LevelDebug)
+		e := echo.New()
+		e.Use(BodyDump(BodyDumpConfig{}))
+		e.POST("/login", func(c echo.Context) error {
+			return c.JSON(http.StatusOK, map[string]any{"user": map[string]string{"Token": "theToken"}})
+		})
+		req := httptest.NewRequest(http.MethodPost, "/login", strings.NewReader(`{"name":"theName","password":"thePassword"}`))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+
+		e.ServeHTTP(rec, req)
+
+		logOutput := logBuf.String()
+		assert.NotContains(t, logOutput, "thePassword")
+		assert.NotContains(t, logOutput, "theToken")
+		assert.Contains(t, logOutput, "theName")
+		assert.Contains(t, logOutput, redactedValue)
+		assert.Contains(t, rec.Body.String(), "theToken")
+	})
+
+	t.Run("redacts_form_fields", func(t *testing.T) {
+		logBuf := setBodyDumpTestLogger(t, slog.LevelDebug)
+		e := echo.New()
+		e.Use(BodyDump(BodyDumpConfig{}))
+		e.POST("/login", func(c echo.Context) error {
+			return c.NoContent(http.StatusNoContent)
+		})
+		req := httptest.NewRequest(http.MethodPost, "/login", strings.NewReader("name=theName&password=thePassword"))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationForm)
+		rec := httptest.NewRecorder()
+
+		e.ServeHTTP(rec, req)
+
+		logOutput := logBuf.String()
+		assert.NotContains(t, logOutput, "thePassword")
+		assert.Contains(t, logOutput, "name=theName")
+	})
+
+	t.Run("omits_bodies_with_unlisted_content_types", func(t *testing.T) {
+		logBuf := setBodyDumpTestLogger(t, slog.LevelDebug)
+		e := echo.New()
+		e.Use(BodyDump(BodyDumpConfig{}))
+		e.GET("/image", func(c echo.Context) error {
+			return c.Blob(http.StatusOK, "image/png", []byte("theImageBytes"))
+		})
+		req := httptest.NewRequest(http.MethodGet, "/image", nil)
+		rec := httptest.NewRecorder()
+
+		e.ServeHTTP(rec, req)
+
+		logOutput := logBuf.String()
+		assert.NotContains(t, logOutput, "theImageBytes")
+		assert.Contains(t, logOutput, "[OMITTED: content type image/png]")
+	})
+
+	t.Run("truncates_bodies_over_the_max_size", func(t *testing.T) {
+		logBuf := setBodyDumpTestLogger(t, slog.LevelDebug)
+		e := echo.New()
+		e.Use(BodyDump(BodyDumpConfig{MaxBodySize: 5}))
+		e.GET("/text", func(c echo.Context) error {
+			return c.String(http.StatusOK, "abcdefghij")
+		})
+		req := httptest.NewRequest(http.MethodGet, "/text", nil)
+		rec := httptest.NewRecorder()
+
+		e.ServeHTTP(rec, req)
+
+		assert.Equal(t, "abcdefghij", rec.Body.String())
+		assert.Contains(t, logBuf.String(), `"response_body":"abcde...[TRUNCATED]"`)
+	})
+
+	t.Run("streams_request_bodies_over_the_max_size_to_the_handler", func(t *testing.T) {
+		logBuf := setBodyDumpTestLogger(t, slog.LevelDebug)
+		e := echo.New()
+		e.Use(BodyDump(BodyDumpConfig{MaxBodySize: 5}))
+		var actualRequestBody string
+		e.POST("/text", func(c echo.Context) error {
+			body, _ := io.ReadAll(c.Request().Body)
+			actualRequestBody = string(body)
+			return c.NoContent(http.StatusNoContent)
+		})
+		req := httptest.NewRequest(http.MethodPost, "/text", strings.NewReader("abcdefghij"))
+		req.Header.Set(echo.HeaderContentType, echo.MIMETextPlain)
+		rec := httptest.NewRecorder()
+
+		e.ServeHTTP(rec, req)
+
+		assert.Equal(t, "abcdefghij", actualRequestBody)
+		assert.Contains(t, logBuf.String(), `"request_body":"abcde...[TRUNCATED]"`)
+	})
+
+	t.Run("does_not_truncate_a_request_body_at_exactly_the_max_size", func(t *testing.T) {
+		logBuf := setBodyDumpTestLogger(t, slog.LevelDebug)
+		e := echo.New()
+		e.Use(BodyDump(BodyDumpConfig{MaxBodySize: 5}))
+		e.POST("/text", func(c echo.Context) error {
+			return c.NoContent(http.StatusNoContent)
+		})
+		req := httptest.NewRequest(http.MethodPost, "/text", strings.NewReader("abcde"))
+		req.Header.Set(echo.HeaderContentType, echo.MIMETextPlain)
+		rec := httptest.NewRecorder()
+
+		e.ServeHTTP(rec, req)
+
+		assert.Contains(t, logBuf.String(), `"request_body":"abcde"`)
+	})
+
+	t.Run("omits_json_bodies_over_the_max_size", func(t *testing.T) {
+		logBuf := setBodyDumpTestLogger(t, slog.LevelDebug)
+		e := echo.New()
+		e.Use(BodyDump(BodyDumpConfig{MaxBodySize: 5}))
+		e.GET("/json", func(c echo.Context) error {
+			return c.JSON(http.StatusOK, map[string]string{"password": "thePassword"})
+		})
+		req := httptest.NewRequest(http.MethodGet, "/json", nil)
+		rec := httptest.NewRecorder()
+
+		e.ServeHTTP(rec, req)
+
+		assert.NotContains(t, logBuf.String(), "thePassword")
+		assert.Contains(t, logBuf.String(), "[OMITTED: JSON body exceeds max body size]")
+	})
+
+	t.Run("only_logs_configured_debug_paths", func(t *testing.T) {
+		logBuf := setBodyDumpTestLogger(t, slog.LevelDebug)
+		e := echo.New()
+		e.Use(BodyDump(BodyDumpConfig{DebugPaths: []string{"/debugged"}}))
+		e.GET("/debugged", func(c echo.Context) error { return c.String(http.StatusOK, "theDebuggedBody") })
+		e.GET("/other", func(c echo.Context) error { return c.String(http.StatusOK, "theOtherBody") })
+
+		e.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/debugged", nil))
+		e.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/other", nil))
+
+		assert.Contains(t, logBuf.String(), "theDebuggedBody")
+		assert.NotContains(t, logBuf.String(), "theOtherBody")
+	})
+
+	t.Run("does_nothing_when_debug_logging_is_disabled", func(t *testing.T) {
+		logBuf := setBodyDumpTestLogger(t, slog.LevelInfo)
+		e := echo.New()
+		e.Use(BodyDump(BodyDumpConfig{}))
+		e.GET("/text", func(c echo.Context) error { return c.String(http.StatusOK, "theBody") })
+		rec := httptest.NewRecorder()
+
+		e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/text", nil))
+
+		assert.Equal(t, "theBody", rec.Body.String())
+		assert.Empty(t, logBuf.String())
+	})
+}
@@ -0,0 +1,103 @@
+package echokit
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func newCanaryTestServer(config CanaryConfig) *echo.Echo {
+	config.Handler = func(c echo.Context) error {
+		return c.String(http.StatusOK, fmt.Sprintf("canary %v", IsCanary(c)))
+	}
+	e := echo.New()
+	e.GET("/", func(c echo.Context) error {
+		return c.String(http.StatusOK, fmt.Sprintf("stable %v", IsCanary(c)))
+	}, Canary(config))
+	return e
+}
+
+func serveCanaryTestRequest(e *echo.Echo, userID string) string {
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("X-User-ID", userID)
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	return rec.Body.String()
+}
+
+func TestCanary(t *testing.T) {
+	t.Run("sends_every_request_to_the_canary_at_100_percent", func(t *testing.T) {
+		e := newCanaryTestServer(CanaryConfig{Percent: 100})
+
+		assert.Equal(t, "canary true", serveCanaryTestRequest(e, "aUser"))
+	})
+
+	t.Run("sends_no_requests_to_the_canary_at_0_percent", func(t *testing.T) {
+		e := newCanaryTestServer(CanaryConfig{Percent: 0})
+
+		assert.Equal(t, "stable false", serveCanaryTestRequest(e, "aUser"))
+	})
+
+	t.Run("makes_the_same_decision_for_the_same_key", func(t *testing.T) {
+		e := newCanaryTestServer(CanaryConfig{Percent: 50, Key: CanaryKeyFromHeader("X-User-ID")})
+
+		for i := range 20 {
+			userID := fmt.Sprintf("user%d", i)
+			assert.Equal(t, serveCanaryTestRequest(e, userID), serveCanaryTestRequest(e, userID))
+		}
+	})
+
+	t.Run("sends_about_the_configured_percentage_to_the_canary", func(t *testing.T) {
+		e := newCanaryTestServer(CanaryConfig{Percent: 25, Key: CanaryKeyFromHeader("X-User-ID")})
+
+		canaries := 0
+		for i := range 2000 {
+			if serveCanaryTestRequest(e, fmt.Sprintf("user%d", i)) == "canary true" {
+				canaries++
+			}
+		}
+
+		assert.InDelta(t, 500, canaries, 100)
+	})
+}
+
+func TestCanaryKeyFromCookie(t *testing.T) {
+	t.Run("returns_the_cookie_value", func(t *testing.T) {
+		e := echo.New()
+		c, _ := NewTestGetRequest(e, "/")
+		c.Request().AddCookie(&http.Cookie{Name: "session", Value: "theSession"})
+
+		assert.Equal(t, "theSession", CanaryKeyFromCookie("session")(c))
+	})
+
+	t.Run("falls_back_to_the_client_ip", func(t *testing.T) {
+		e := echo.New()
+		c, _ := NewTestGetRequest(e, "/")
+
+		assert.Equal(t, c.RealIP(), CanaryKeyFromCookie("session")(c))
+	})
+}
+
+func TestCanaryUpstream(t *testing.T) {
+	t.Run("proxies_to_the_target", func(t *testing.T) {
+		upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_, _ = w.Write([]byte("from upstream " + r.URL.Path))
+		}))
+		t.Cleanup(upstream.Close)
+		target, _ := url.Parse(upstream.URL)
+		e := echo.New()
+		e.GET("/users", func(c echo.Context) error {
+			return c.String(http.StatusOK, "stable")
+		}, Canary(CanaryConfig{Percent: 100, Handler: CanaryUpstream(target)}))
+		rec := httptest.NewRecorder()
+
+		e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/users", nil))
+
+		assert.Equal(t, "from upstream /users", rec.Body.String())
+	})
+}
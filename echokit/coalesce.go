@@ -0,0 +1,169 @@
+package echokit
+
+import (
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/labstack/echo/v4"
+)
+
+type coalesceConfig struct {
+	key         func(c echo.Context) string
+	varyHeaders []string
+	maxBodySize int
+
+	// onWait is called when a request starts waiting on another's response, so tests can release the
+	// first request once the rest are waiting
+	onWait func()
+}
+
+type CoalesceOption func(*coalesceConfig)
+
+// WithCoalesceKey sets how requests are matched. Requests with the same key share a response. By
+// default the key is the method, the request URI, and the values of the vary headers.
+func WithCoalesceKey(key func(c echo.Context) string) CoalesceOption {
+	return func(config *coalesceConfig) {
+		config.key = key
+	}
+}
+
+// WithCoalesceVaryHeaders sets the request headers whose values must match for requests to share a
+// response with the default key. The default is Authorization, Cookie, Accept, and Accept-Encoding, so
+// responses are never shared between users.
+func WithCoalesceVaryHeaders(headers ...string) CoalesceOption {
+	return func(config *coalesceConfig) {
+		config.varyHeaders = headers
+	}
+}
+
+// WithCoalesceMaxBodySize sets the largest response shared with waiting requests; when the response is
+// larger, they're handled on their own. The default is 1MB.
+func WithCoalesceMaxBodySize(maxBodySize int) CoalesceOption {
+	return func(config *coalesceConfig) {
+		config.maxBodySize = maxBodySize
+	}
+}
+
+func withCoalesceOnWait(onWait func()) CoalesceOption {
+	return func(config *coalesceConfig) {
+		config.onWait = onWait
+	}
+}
+
+type coalescedResponse struct {
+	done      chan struct{}
+	status    int
+	header    http.Header
+	body      []byte
+	committed bool
+	truncated bool
+}
+
+// Coalesce returns a middleware that handles concurrent identical GET and HEAD requests once and sends
+// the response to all of them, to protect expensive routes when a cache expires. Requests that arrive
+// after the response is sent are handled again. Add it to the routes that need it, e.g.
+//
+//	e.GET("/reports/:id", getReport, echokit.Coalesce())
+func Coalesce(options ...CoalesceOption) echo.MiddlewareFunc {
+	config := &coalesceConfig{
+		varyHeaders: []string{echo.HeaderAuthorization, echo.HeaderCookie, echo.HeaderAccept, echo.HeaderAcceptEncoding},
+		maxBodySize: 1 << 20,
+	}
+	for _, option := range options {
+		option(config)
+	}
+	if config.key == nil {
+		config.key = func(c echo.Context) string {
+			return defaultCoalesceKey(c, config.varyHeaders)
+		}
+	}
+
+	var mu sync.Mutex
+	inFlight := map[string]*coalescedResponse{}
+
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			req := c.Request()
+			if req.Method != http.MethodGet && req.Method != http.MethodHead {
+				return next(c)
+			}
+
+			key := config.key(c)
+
+			mu.Lock()
+			shared, waiting := inFlight[key]
+			if !waiting {
+				shared = &coalescedResponse{done: make(chan struct{})}
+				inFlight[key] = shared
+			}
+			mu.Unlock()
+
+			if waiting {
+				if config.onWait != nil {
+					config.onWait()
+				}
+				select {
+				case <-shared.done:
+				case <-req.Context().Done():
+					return req.Context().Err()
+				}
+
+				// Nothing can be replayed when the response was too large to capture or was never written
+				if shared.truncated || !shared.committed {
+					return next(c)
+				}
+				logger.DebugContext(req.Context(), "sending coalesced response", "method", req.Method, "uri", req.RequestURI)
+				return writeCoalescedResponse(c, shared)
+			}
+
+			defer func() {
+				mu.Lock()
+				delete(inFlight, key)
+				mu.Unlock()
+				close(shared.done)
+			}()
+
+			res := c.Response()
+			capture := &limitedBuffer{max: config.maxBodySize}
+			writer := &bodyDumpResponseWriter{Writer: io.MultiWriter(res.Writer, capture), ResponseWriter: res.Writer}
+			res.Writer = writer
+			defer func() { res.Writer = writer.ResponseWriter }()
+
+			// Handle errors here so the error response is captured and sent to waiting requests too
+			if err := next(c); err != nil {
+				c.Error(err)
+			}
+
+			shared.status = res.Status
+			shared.header = res.Header().Clone()
+			shared.body = capture.buf.Bytes()
+			shared.committed = res.Committed
+			shared.truncated = capture.truncated
+			return nil
+		}
+	}
+}
+
+func defaultCoalesceKey(c echo.Context, varyHeaders []string) string {
+	req := c.Request()
+	parts := []string{req.Method, req.URL.RequestURI()}
+	for _, header := range varyHeaders {
+		parts = append(parts, strings.Join(req.Header.Values(header), ","))
+	}
+	return strings.Join(parts, "\x00")
+}
+
+func writeCoalescedResponse(c echo.Context, shared *coalescedResponse) error {
+	res := c.Response()
+	for name, values := range shared.header {
+		// Keep headers already set for this request, e.g. its request ID
+		if _, ok := res.Header()[name]; !ok {
+			res.Header()[name] = values
+		}
+	}
+	res.WriteHeader(shared.status)
+	_, err := res.Write(shared.body)
+	return err
+}
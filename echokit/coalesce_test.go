@@ -0,0 +1,223 @@
+package echokit
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+// serveConcurrently sends requests to e while the first is being handled, releases it once each of the
+// rest has arrived at the handler or is waiting on the first's response, and returns the responses
+func serveConcurrently(e *echo.Echo, arrived chan struct{}, release chan struct{}, requests ...*http.Request) []*httptest.ResponseRecorder {
+	recs := make([]*httptest.ResponseRecorder, len(requests))
+	var wg sync.WaitGroup
+	for i, req := range requests {
+		recs[i] = httptest.NewRecorder()
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			e.ServeHTTP(recs[i], req)
+		}()
+		if i == 0 {
+			<-arrived
+		}
+	}
+	for range len(requests) - 1 {
+		<-arrived
+	}
+	close(release)
+	wg.Wait()
+	return recs
+}
+
+// newArrivals returns the channel handlers and waiting requests signal when they arrive, and the
+// option that makes waiting requests signal it
+func newArrivals() (chan struct{}, CoalesceOption) {
+	arrived := make(chan struct{}, 16)
+	return arrived, withCoalesceOnWait(func() { arrived <- struct{}{} })
+}
+
+func TestCoalesce(t *testing.T) {
+	t.Run("handles_concurrent_identical_gets_once", func(t *testing.T) {
+		var calls atomic.Int32
+		arrived, onWait := newArrivals()
+		release := make(chan struct{})
+		e := echo.New()
+		e.GET("/reports/:id", func(c echo.Context) error {
+			calls.Add(1)
+			arrived <- struct{}{}
+			<-release
+			c.Response().Header().Set("X-Report", "theReport")
+			return c.String(http.StatusOK, "theResponse")
+		}, Coalesce(onWait))
+
+		recs := serveConcurrently(e, arrived, release,
+			httptest.NewRequest(http.MethodGet, "/reports/1", nil),
+			httptest.NewRequest(http.MethodGet, "/reports/1", nil),
+			httptest.NewRequest(http.MethodGet, "/reports/1", nil))
+
+		assert.Equal(t, int32(1), calls.Load())
+		for _, rec := range recs {
+			assert.Equal(t, http.StatusOK, rec.Code)
+			assert.Equal(t, "theResponse", rec.Body.String())
+			assert.Equal(t, "theReport", rec.Header().Get("X-Report"))
+		}
+	})
+
+	t.Run("handles_requests_with_different_keys_separately", func(t *testing.T) {
+		var calls atomic.Int32
+		arrived, onWait := newArrivals()
+		release := make(chan struct{})
+		e := echo.New()
+		e.GET("/reports/:id", func(c echo.Context) error {
+			calls.Add(1)
+			arrived <- struct{}{}
+			<-release
+			return c.String(http.StatusOK, c.Param("id"))
+		}, Coalesce(onWait))
+		otherUser := httptest.NewRequest(http.MethodGet, "/reports/1", nil)
+		otherUser.Header.Set(echo.HeaderAuthorization, "Bearer theOtherToken")
+
+		recs := serveConcurrently(e, arrived, release,
+			httptest.NewRequest(http.MethodGet, "/reports/1", nil),
+			httptest.NewRequest(http.MethodGet, "/reports/2", nil),
+			otherUser)
+
+		assert.Equal(t, int32(3), calls.Load())
+		assert.Equal(t, "1", recs[0].Body.String())
+		assert.Equal(t, "2", recs[1].Body.String())
+		assert.Equal(t, "1", recs[2].Body.String())
+	})
+
+	t.Run("sends_the_error_response_to_waiting_requests", func(t *testing.T) {
+		var calls atomic.Int32
+		arrived, onWait := newArrivals()
+		release := make(chan struct{})
+		e := echo.New()
+		e.GET("/reports/:id", func(c echo.Context) error {
+			calls.Add(1)
+			arrived <- struct{}{}
+			<-release
+			return echo.NewHTTPError(http.StatusServiceUnavailable, "theMessage")
+		}, Coalesce(onWait))
+
+		recs := serveConcurrently(e, arrived, release,
+			httptest.NewRequest(http.MethodGet, "/reports/1", nil),
+			httptest.NewRequest(http.MethodGet, "/reports/1", nil))
+
+		assert.Equal(t, int32(1), calls.Load())
+		for _, rec := range recs {
+			assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+			assert.Contains(t, rec.Body.String(), "theMessage")
+		}
+	})
+
+	t.Run("handles_waiting_requests_on_their_own_when_the_response_is_too_large", func(t *testing.T) {
+		var calls atomic.Int32
+		arrived, onWait := newArrivals()
+		release := make(chan struct{})
+		e := echo.New()
+		e.GET("/reports/:id", func(c echo.Context) error {
+			calls.Add(1)
+			arrived <- struct{}{}
+			<-release
+			return c.String(http.StatusOK, "theLargeResponse")
+		}, Coalesce(WithCoalesceMaxBodySize(4), onWait))
+
+		recs := serveConcurrently(e, arrived, release,
+			httptest.NewRequest(http.MethodGet, "/reports/1", nil),
+			httptest.NewRequest(http.MethodGet, "/reports/1", nil))
+
+		assert.Equal(t, int32(2), calls.Load())
+		for _, rec := range recs {
+			assert.Equal(t, "theLargeResponse", rec.Body.String())
+		}
+	})
+
+	t.Run("handles_waiting_requests_on_their_own_when_no_response_was_written", func(t *testing.T) {
+		var calls atomic.Int32
+		arrived, onWait := newArrivals()
+		release := make(chan struct{})
+		e := echo.New()
+		e.GET("/reports/:id", func(c echo.Context) error {
+			if calls.Add(1) == 1 {
+				arrived <- struct{}{}
+				<-release
+				return nil
+			}
+			return c.String(http.StatusOK, "theResponse")
+		}, Coalesce(onWait))
+
+		recs := serveConcurrently(e, arrived, release,
+			httptest.NewRequest(http.MethodGet, "/reports/1", nil),
+			httptest.NewRequest(http.MethodGet, "/reports/1", nil))
+
+		assert.Equal(t, int32(2), calls.Load())
+		assert.Equal(t, "theResponse", recs[1].Body.String())
+	})
+
+	t.Run("uses_the_key_function", func(t *testing.T) {
+		var calls atomic.Int32
+		arrived, onWait := newArrivals()
+		release := make(chan struct{})
+		e := echo.New()
+		e.GET("/reports/:id", func(c echo.Context) error {
+			calls.Add(1)
+			arrived <- struct{}{}
+			<-release
+			return c.String(http.StatusOK, "theResponse")
+		}, Coalesce(WithCoalesceKey(func(c echo.Context) string { return c.Path() }), onWait))
+
+		serveConcurrently(e, arrived, release,
+			httptest.NewRequest(http.MethodGet, "/reports/1", nil),
+			httptest.NewRequest(http.MethodGet, "/reports/2", nil))
+
+		assert.Equal(t, int32(1), calls.Load())
+	})
+
+	t.Run("does_not_coalesce_other_methods", func(t *testing.T) {
+		var calls atomic.Int32
+		e := echo.New()
+		e.POST("/reports", func(c echo.Context) error {
+			calls.Add(1)
+			return c.NoContent(http.StatusCreated)
+		}, Coalesce())
+
+		for range 2 {
+			rec := httptest.NewRecorder()
+			e.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/reports", nil))
+			assert.Equal(t, http.StatusCreated, rec.Code)
+		}
+
+		assert.Equal(t, int32(2), calls.Load())
+	})
+
+	t.Run("stops_waiting_when_the_request_is_canceled", func(t *testing.T) {
+		entered, release := make(chan struct{}), make(chan struct{})
+		defer close(release)
+		e := echo.New()
+		var once sync.Once
+		e.GET("/reports/:id", func(c echo.Context) error {
+			once.Do(func() { close(entered) })
+			<-release
+			return c.String(http.StatusOK, "theResponse")
+		}, Coalesce())
+		go e.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/reports/1", nil))
+		<-entered
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		var actualErr error
+		e.HTTPErrorHandler = func(err error, c echo.Context) { actualErr = err }
+
+		e.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/reports/1", nil).WithContext(ctx))
+
+		assert.True(t, errors.Is(actualErr, context.Canceled))
+	})
+}
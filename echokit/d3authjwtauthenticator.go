@@ -3,7 +3,6 @@ package echokit
 import (
 	"context"
 	"errors"
-	"log/slog"
 	"net/http"
 	"net/url"
 	"strings"
@@ -57,7 +56,7 @@ func NewD3AuthJWTAuthenticator(config D3AuthConfig) (Authenticator, error) {
 		return nil, kit.WrapError(err, "failed to parse d3-auth base URL")
 	}
 
-	provider := jwks.NewCachingProvider(jwksURL, 5*time.Minute)
+	provider := jwks.NewCachingProvider(jwksURL, 5*time.Minute, jwks.WithCustomClient(jwksClient))
 
 	jwtValidator, err := validator.New(
 		provider.KeyFunc,
@@ -97,7 +96,7 @@ func (a *D3AuthJWTAuthenticator) AuthenticateRequest(c echo.Context) error {
 		// The user presented a d3-auth JWT, so they are authenticated — but
 		// validation failed (wrong audience, expired, etc.), meaning they are
 		// not authorized for this API. Return 403, not 500.
-		slog.Debug("d3auth_jwt_validation_failed", "error", err.Error())
+		logger.Debug("d3auth_jwt_validation_failed", "error", err.Error())
 		return echo.NewHTTPError(http.StatusForbidden, "You do not have access to this application")
 	}
 
@@ -0,0 +1,167 @@
+package echokit
+
+import (
+	"log/slog"
+	"net/http"
+
+	"github.com/half-ogre/go-kit/internal/debugroutes"
+	"github.com/half-ogre/go-kit/kit"
+	"github.com/half-ogre/go-kit/versionkit"
+	"github.com/labstack/echo/v4"
+)
+
+type debugRoutesConfig struct {
+	audience   string
+	permission string
+	allowedIPs []string
+	config     any
+	levelVar   *slog.LevelVar
+	buildInfo  *versionkit.BuildInfo
+}
+
+type DebugRoutesOption func(*debugRoutesConfig)
+
+// WithDebugPermission requires callers to hold permission for audience (see RequirePermission). The
+// authentication middleware must run before the debug routes.
+func WithDebugPermission(audience string, permission string) DebugRoutesOption {
+	return func(config *debugRoutesConfig) {
+		config.audience = audience
+		config.permission = permission
+	}
+}
+
+// WithDebugAllowedIPs limits the debug routes to callers whose c.RealIP() is one of ipsOrCIDRs. Set
+// e.IPExtractor when running behind a proxy so forwarded headers cannot be spoofed.
+func WithDebugAllowedIPs(ipsOrCIDRs ...string) DebugRoutesOption {
+	return func(config *debugRoutesConfig) {
+		config.allowedIPs = append(config.allowedIPs, ipsOrCIDRs...)
+	}
+}
+
+// WithDebugConfig sets the value served, with sensitive keys redacted, at /debug/config
+func WithDebugConfig(config any) DebugRoutesOption {
+	return func(c *debugRoutesConfig) {
+		c.config = config
+	}
+}
+
+// WithDebugLevelVar sets the default logger's level (as returned by logkit.SetDefaultLogger) so
+// /debug/loglevel changes it along with the logkit module levels
+func WithDebugLevelVar(levelVar *slog.LevelVar) DebugRoutesOption {
+	return func(config *debugRoutesConfig) {
+		config.levelVar = levelVar
+	}
+}
+
+// WithDebugBuildInfo sets the build info served at /debug/build, e.g. one stamped with
+// versionkit.LDFlags. It defaults to versionkit.GetBuildInfo.
+func WithDebugBuildInfo(buildInfo *versionkit.BuildInfo) DebugRoutesOption {
+	return func(config *debugRoutesConfig) {
+		config.buildInfo = buildInfo
+	}
+}
+
+// RegisterDebugRoutes registers debug endpoints under /debug:
+//   - GET /debug/routes lists the registered routes and their handlers
+//   - GET /debug/config returns the WithDebugConfig value with sensitive keys redacted
+//   - GET and PUT /debug/loglevel read and set the logkit level spec, e.g. {"level":"info,dynamodbkit=debug"}
+//   - GET /debug/build returns the version, commit, and build date
+//   - /debug/pprof/ serves net/http/pprof
+//
+// Without WithDebugPermission or WithDebugAllowedIPs only loopback callers are allowed, judged by the
+// connection's remote address rather than forwarded headers.
+func RegisterDebugRoutes(e *echo.Echo, options ...DebugRoutesOption) error {
+	config := &debugRoutesConfig{}
+	for _, option := range options {
+		option(config)
+	}
+
+	guards, err := debugGuards(config)
+	if err != nil {
+		return kit.WrapError(err, "failed to register debug routes")
+	}
+
+	g := e.Group(debugroutes.Prefix, guards...)
+
+	g.GET("/routes", func(c echo.Context) error {
+		routes := []debugroutes.Route{}
+		for _, route := range e.Routes() {
+			routes = append(routes, debugroutes.Route{Method: route.Method, Path: route.Path, Handler: route.Name})
+		}
+		debugroutes.SortRoutes(routes)
+		return c.JSON(http.StatusOK, debugroutes.RoutesResponse{Routes: routes})
+	})
+
+	g.GET("/config", func(c echo.Context) error {
+		redacted, err := debugroutes.Redact(config.config)
+		if err != nil {
+			return kit.WrapError(err, "failed to redact debug config")
+		}
+		return c.JSON(http.StatusOK, redacted)
+	})
+
+	g.GET("/loglevel", func(c echo.Context) error {
+		return c.JSON(http.StatusOK, debugroutes.LogLevelRequest{Level: debugroutes.CurrentLogLevel(config.levelVar)})
+	})
+
+	g.PUT("/loglevel", func(c echo.Context) error {
+		var request debugroutes.LogLevelRequest
+		if err := c.Bind(&request); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "invalid log level request")
+		}
+
+		level, err := debugroutes.SetLogLevel(config.levelVar, request.Level)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+		}
+
+		logger.Info("log level changed", "level", level)
+		return c.JSON(http.StatusOK, debugroutes.LogLevelRequest{Level: level})
+	})
+
+	g.GET("/build", func(c echo.Context) error {
+		return c.JSON(http.StatusOK, debugroutes.Build(config.buildInfo))
+	})
+
+	g.Any("/pprof/*", echo.WrapHandler(debugroutes.PprofHandler()))
+
+	return nil
+}
+
+func debugGuards(config *debugRoutesConfig) ([]echo.MiddlewareFunc, error) {
+	guards := []echo.MiddlewareFunc{}
+
+	// The loopback default checks the connection's peer address: c.RealIP() trusts X-Forwarded-For
+	// and X-Real-IP unless e.IPExtractor is set, so a remote caller could claim to be 127.0.0.1.
+	networks := debugroutes.LoopbackNetworks
+	clientIP := func(c echo.Context) string {
+		return debugroutes.RemoteIP(c.Request().RemoteAddr)
+	}
+	if len(config.allowedIPs) > 0 {
+		parsed, err := debugroutes.ParseNetworks(config.allowedIPs)
+		if err != nil {
+			return nil, err
+		}
+		networks = parsed
+		clientIP = func(c echo.Context) string {
+			return c.RealIP()
+		}
+	}
+
+	if len(config.allowedIPs) > 0 || config.permission == "" {
+		guards = append(guards, func(next echo.HandlerFunc) echo.HandlerFunc {
+			return func(c echo.Context) error {
+				if !debugroutes.IPAllowed(clientIP(c), networks) {
+					return echo.NewHTTPError(http.StatusForbidden)
+				}
+				return next(c)
+			}
+		})
+	}
+
+	if config.permission != "" {
+		guards = append(guards, RequirePermission(config.audience, config.permission))
+	}
+
+	return guards, nil
+}
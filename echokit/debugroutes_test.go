@@ -0,0 +1,211 @@
+package echokit
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/half-ogre/go-kit/versionkit"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func newDebugRequest(method string, path string, body string) *http.Request {
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	req.RemoteAddr = "127.0.0.1:4321"
+	if body != "" {
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	}
+	return req
+}
+
+func TestRegisterDebugRoutes(t *testing.T) {
+	t.Run("lists_the_registered_routes", func(t *testing.T) {
+		e := echo.New()
+		e.GET("/users/:id", func(c echo.Context) error { return nil })
+		err := RegisterDebugRoutes(e)
+		assert.NoError(t, err)
+		rec := httptest.NewRecorder()
+
+		e.ServeHTTP(rec, newDebugRequest(http.MethodGet, "/debug/routes", ""))
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		var body struct {
+			Routes []struct {
+				Method string `json:"method"`
+				Path   string `json:"path"`
+			} `json:"routes"`
+		}
+		assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+		assert.Contains(t, body.Routes, struct {
+			Method string `json:"method"`
+			Path   string `json:"path"`
+		}{Method: http.MethodGet, Path: "/users/:id"})
+	})
+
+	t.Run("serves_the_config_with_sensitive_keys_redacted", func(t *testing.T) {
+		e := echo.New()
+		theConfig := map[string]any{
+			"region":   "theRegion",
+			"database": map[string]any{"host": "theHost", "password": "thePassword"},
+			"apiKey":   "theAPIKey",
+		}
+		err := RegisterDebugRoutes(e, WithDebugConfig(theConfig))
+		assert.NoError(t, err)
+		rec := httptest.NewRecorder()
+
+		e.ServeHTTP(rec, newDebugRequest(http.MethodGet, "/debug/config", ""))
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.JSONEq(t, `{"region":"theRegion","database":{"host":"theHost","password":"[REDACTED]"},"apiKey":"[REDACTED]"}`, rec.Body.String())
+	})
+
+	t.Run("sets_and_reads_the_log_level", func(t *testing.T) {
+		e := echo.New()
+		levelVar := new(slog.LevelVar)
+		err := RegisterDebugRoutes(e, WithDebugLevelVar(levelVar))
+		assert.NoError(t, err)
+		putRec := httptest.NewRecorder()
+		getRec := httptest.NewRecorder()
+
+		e.ServeHTTP(putRec, newDebugRequest(http.MethodPut, "/debug/loglevel", `{"level":"warn,dynamodbkit=debug"}`))
+		e.ServeHTTP(getRec, newDebugRequest(http.MethodGet, "/debug/loglevel", ""))
+
+		assert.Equal(t, http.StatusOK, putRec.Code)
+		assert.JSONEq(t, `{"level":"warn,dynamodbkit=debug"}`, putRec.Body.String())
+		assert.JSONEq(t, `{"level":"warn,dynamodbkit=debug"}`, getRec.Body.String())
+		assert.Equal(t, slog.LevelWarn, levelVar.Level())
+	})
+
+	t.Run("rejects_an_invalid_log_level", func(t *testing.T) {
+		e := echo.New()
+		err := RegisterDebugRoutes(e)
+		assert.NoError(t, err)
+		rec := httptest.NewRecorder()
+
+		e.ServeHTTP(rec, newDebugRequest(http.MethodPut, "/debug/loglevel", `{"level":"loud"}`))
+
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+
+	t.Run("serves_the_build_info", func(t *testing.T) {
+		e := echo.New()
+		err := RegisterDebugRoutes(e, WithDebugBuildInfo(&versionkit.BuildInfo{Version: "1.2.3", GitCommit: "abc1234", BuildDate: "2026-01-02"}))
+		assert.NoError(t, err)
+		rec := httptest.NewRecorder()
+
+		e.ServeHTTP(rec, newDebugRequest(http.MethodGet, "/debug/build", ""))
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Contains(t, rec.Body.String(), `"version":"1.2.3","git_commit":"abc1234","build_date":"2026-01-02"`)
+	})
+
+	t.Run("serves_pprof", func(t *testing.T) {
+		e := echo.New()
+		err := RegisterDebugRoutes(e)
+		assert.NoError(t, err)
+		rec := httptest.NewRecorder()
+
+		e.ServeHTTP(rec, newDebugRequest(http.MethodGet, "/debug/pprof/goroutine?debug=1", ""))
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Contains(t, rec.Body.String(), "goroutine profile")
+	})
+
+	t.Run("forbids_non_loopback_callers_by_default", func(t *testing.T) {
+		e := echo.New()
+		err := RegisterDebugRoutes(e)
+		assert.NoError(t, err)
+		req := newDebugRequest(http.MethodGet, "/debug/routes", "")
+		req.RemoteAddr = "203.0.113.7:4321"
+		rec := httptest.NewRecorder()
+
+		e.ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusForbidden, rec.Code)
+	})
+
+	t.Run("forbids_a_remote_caller_spoofing_a_loopback_forwarded_header", func(t *testing.T) {
+		e := echo.New()
+		err := RegisterDebugRoutes(e)
+		assert.NoError(t, err)
+		req := newDebugRequest(http.MethodGet, "/debug/routes", "")
+		req.RemoteAddr = "203.0.113.7:4321"
+		req.Header.Set(echo.HeaderXForwardedFor, "127.0.0.1")
+		req.Header.Set(echo.HeaderXRealIP, "127.0.0.1")
+		rec := httptest.NewRecorder()
+
+		e.ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusForbidden, rec.Code)
+	})
+
+	t.Run("allows_callers_in_the_allowed_ips", func(t *testing.T) {
+		e := echo.New()
+		err := RegisterDebugRoutes(e, WithDebugAllowedIPs("203.0.113.0/24"))
+		assert.NoError(t, err)
+		req := newDebugRequest(http.MethodGet, "/debug/routes", "")
+		req.RemoteAddr = "203.0.113.7:4321"
+		rec := httptest.NewRecorder()
+
+		e.ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+	})
+
+	t.Run("returns_an_error_for_an_invalid_allowed_ip", func(t *testing.T) {
+		e := echo.New()
+
+		err := RegisterDebugRoutes(e, WithDebugAllowedIPs("notAnIP"))
+
+		assert.EqualError(t, err, `failed to register debug routes: invalid IP "notAnIP"`)
+	})
+
+	t.Run("allows_a_remote_caller_with_the_permission", func(t *testing.T) {
+		e := echo.New()
+		theUser := &AuthenticatedUser{Sub: "theSub", Permissions: map[string][]string{"theAudience": {"debug:read"}}}
+		e.Use(func(next echo.HandlerFunc) echo.HandlerFunc {
+			return func(c echo.Context) error {
+				SetAuthenticator(c, &FakeAuthenticator{
+					IsAuthenticatedFake:      func(c echo.Context) (bool, error) { return true, nil },
+					GetAuthenticatedUserFake: func(c echo.Context) (*AuthenticatedUser, error) { return theUser, nil },
+				})
+				return next(c)
+			}
+		})
+		err := RegisterDebugRoutes(e, WithDebugPermission("theAudience", "debug:read"))
+		assert.NoError(t, err)
+		req := newDebugRequest(http.MethodGet, "/debug/routes", "")
+		req.RemoteAddr = "203.0.113.7:4321"
+		rec := httptest.NewRecorder()
+
+		e.ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+	})
+
+	t.Run("denies_a_caller_without_the_permission", func(t *testing.T) {
+		e := echo.New()
+		theUser := &AuthenticatedUser{Sub: "theSub", Permissions: map[string][]string{"theAudience": {"users:read"}}}
+		e.Use(func(next echo.HandlerFunc) echo.HandlerFunc {
+			return func(c echo.Context) error {
+				SetAuthenticator(c, &FakeAuthenticator{
+					IsAuthenticatedFake:        func(c echo.Context) (bool, error) { return true, nil },
+					GetAuthenticatedUserFake:   func(c echo.Context) (*AuthenticatedUser, error) { return theUser, nil },
+					HandleNotAuthenticatedFake: func(c echo.Context) error { return c.NoContent(http.StatusUnauthorized) },
+				})
+				return next(c)
+			}
+		})
+		err := RegisterDebugRoutes(e, WithDebugPermission("theAudience", "debug:read"))
+		assert.NoError(t, err)
+		rec := httptest.NewRecorder()
+
+		e.ServeHTTP(rec, newDebugRequest(http.MethodGet, "/debug/routes", ""))
+
+		assert.Equal(t, http.StatusUnauthorized, rec.Code)
+	})
+}
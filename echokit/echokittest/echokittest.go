@@ -0,0 +1,47 @@
+// Package echokittest helps test handlers that use echokit's authentication and permission middleware
+package echokittest
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/half-ogre/go-kit/echokit"
+)
+
+// NewFakeAuthenticator returns a FakeAuthenticator for a static user. A nil user is not authenticated,
+// and HandleNotAuthenticated returns a 401 HTTPError.
+func NewFakeAuthenticator(user *echokit.AuthenticatedUser) *echokit.FakeAuthenticator {
+	return &echokit.FakeAuthenticator{
+		AuthenticateRequestFake: func(c echo.Context) error {
+			return nil
+		},
+		GetAuthenticatedUserFake: func(c echo.Context) (*echokit.AuthenticatedUser, error) {
+			if user == nil {
+				return nil, errors.New("user is not authenticated")
+			}
+			return user, nil
+		},
+		IsAuthenticatedFake: func(c echo.Context) (bool, error) {
+			return user != nil, nil
+		},
+		HandleNotAuthenticatedFake: func(c echo.Context) error {
+			return echo.NewHTTPError(http.StatusUnauthorized)
+		},
+	}
+}
+
+// WithAuthenticatedUser makes user the authenticated user of c, as if the authentication middleware had
+// run, and returns c
+func WithAuthenticatedUser(c echo.Context, user echokit.AuthenticatedUser) echo.Context {
+	echokit.SetAuthenticator(c, NewFakeAuthenticator(&user))
+	return c
+}
+
+// WithoutAuthenticatedUser makes c unauthenticated, as if the authentication middleware found no user,
+// and returns c
+func WithoutAuthenticatedUser(c echo.Context) echo.Context {
+	echokit.SetAuthenticator(c, NewFakeAuthenticator(nil))
+	return c
+}
@@ -0,0 +1,75 @@
+package echokittest
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/half-ogre/go-kit/echokit"
+)
+
+func TestWithAuthenticatedUser(t *testing.T) {
+	t.Run("lets_a_user_with_the_permission_through_require_permission", func(t *testing.T) {
+		e := echo.New()
+		c, rec := echokit.NewTestGetRequest(e, "/")
+		WithAuthenticatedUser(c, echokit.AuthenticatedUser{
+			Sub:         "theSub",
+			Permissions: map[string][]string{"theAudience": {"thePermission"}},
+		})
+		handler := echokit.RequirePermission("theAudience", "thePermission")(func(c echo.Context) error {
+			return c.String(http.StatusOK, "success")
+		})
+
+		err := handler(c)
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusOK, rec.Code)
+	})
+
+	t.Run("rejects_a_user_without_the_permission", func(t *testing.T) {
+		e := echo.New()
+		c, _ := echokit.NewTestGetRequest(e, "/")
+		WithAuthenticatedUser(c, echokit.AuthenticatedUser{Sub: "theSub"})
+		handler := echokit.RequirePermission("theAudience", "thePermission")(func(c echo.Context) error {
+			return c.String(http.StatusOK, "success")
+		})
+
+		err := handler(c)
+
+		var httpErr *echo.HTTPError
+		assert.ErrorAs(t, err, &httpErr)
+		assert.Equal(t, http.StatusUnauthorized, httpErr.Code)
+	})
+
+	t.Run("makes_the_user_available_to_the_handler", func(t *testing.T) {
+		e := echo.New()
+		c, _ := echokit.NewTestGetRequest(e, "/")
+		WithAuthenticatedUser(c, echokit.AuthenticatedUser{Sub: "theSub"})
+
+		authenticator, err := echokit.GetAuthenticator(c)
+		assert.NoError(t, err)
+		user, err := authenticator.GetAuthenticatedUser(c)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "theSub", user.Sub)
+	})
+}
+
+func TestWithoutAuthenticatedUser(t *testing.T) {
+	t.Run("is_not_authenticated", func(t *testing.T) {
+		e := echo.New()
+		c, _ := echokit.NewTestGetRequest(e, "/")
+		WithoutAuthenticatedUser(c)
+
+		authenticator, err := echokit.GetAuthenticator(c)
+		assert.NoError(t, err)
+		isAuthenticated, err := authenticator.IsAuthenticated(c)
+
+		assert.NoError(t, err)
+		assert.False(t, isAuthenticated)
+		_, err = authenticator.GetAuthenticatedUser(c)
+		assert.EqualError(t, err, "user is not authenticated")
+	})
+}
@@ -0,0 +1,150 @@
+package echokit
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+
+	"github.com/half-ogre/go-kit/kit"
+	"github.com/labstack/echo/v4"
+)
+
+const (
+	enqueuerContextKey      = "github.com/half-ogre/go-kit/echokit/enqueuer"
+	enqueueFailedContextKey = "github.com/half-ogre/go-kit/echokit/enqueue_failed"
+)
+
+// Job metadata keys set by Enqueue from the request
+const (
+	JobMetadataRequestID   = "request_id"
+	JobMetadataTraceID     = "amzn_trace_id"
+	JobMetadataTraceparent = "traceparent"
+)
+
+// Job is background work submitted from a handler
+type Job struct {
+	Type     string
+	Payload  any
+	Metadata map[string]string
+}
+
+// Enqueuer submits jobs to a queue backend, such as SQS
+type Enqueuer interface {
+	Enqueue(ctx context.Context, job Job) error
+}
+
+// EnqueuerFunc adapts a function to the Enqueuer interface
+type EnqueuerFunc func(ctx context.Context, job Job) error
+
+func (f EnqueuerFunc) Enqueue(ctx context.Context, job Job) error {
+	return f(ctx, job)
+}
+
+// EnqueueMiddleware makes enqueuer available to Enqueue. If any Enqueue during the request fails and the
+// handler doesn't return an error itself, the request fails with a 500, so handlers can fire and forget
+// without silently dropping work. If the response was already written the failure is only logged.
+func EnqueueMiddleware(enqueuer Enqueuer) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			failed := &enqueueFailure{}
+			c.Set(enqueuerContextKey, enqueuer)
+			c.Set(enqueueFailedContextKey, failed)
+
+			err := next(c)
+			if err != nil {
+				return err
+			}
+
+			enqueueErr := failed.get()
+			if enqueueErr == nil {
+				return nil
+			}
+
+			if c.Response().Committed {
+				logger.ErrorContext(c.Request().Context(), "enqueue failed after response was written",
+					"uri", c.Request().RequestURI,
+					"method", c.Request().Method,
+					"error", enqueueErr.Error(),
+				)
+				return nil
+			}
+
+			return echo.NewHTTPError(http.StatusInternalServerError).SetInternal(enqueueErr)
+		}
+	}
+}
+
+// Enqueue submits job with the request's ID and trace context added to its metadata, using the
+// Enqueuer set by EnqueueMiddleware. Handlers may ignore the returned error; EnqueueMiddleware fails
+// the request instead.
+func Enqueue(c echo.Context, job Job) error {
+	err := enqueue(c, job)
+	if err != nil {
+		if failed, ok := c.Get(enqueueFailedContextKey).(*enqueueFailure); ok {
+			failed.set(err)
+		}
+	}
+	return err
+}
+
+func enqueue(c echo.Context, job Job) error {
+	enqueuer, ok := c.Get(enqueuerContextKey).(Enqueuer)
+	if !ok {
+		return errors.New("no enqueuer in context; use EnqueueMiddleware")
+	}
+
+	metadata := make(map[string]string, len(job.Metadata)+3)
+	for key, value := range requestJobMetadata(c) {
+		if value != "" {
+			metadata[key] = value
+		}
+	}
+	// Metadata set by the handler wins over what was captured from the request
+	for key, value := range job.Metadata {
+		metadata[key] = value
+	}
+	job.Metadata = metadata
+
+	err := enqueuer.Enqueue(c.Request().Context(), job)
+	if err != nil {
+		return kit.WrapError(err, "error enqueuing %s job", job.Type)
+	}
+
+	return nil
+}
+
+func requestJobMetadata(c echo.Context) map[string]string {
+	req := c.Request()
+	requestID := req.Header.Get(echo.HeaderXRequestID)
+	if requestID == "" {
+		// echo's RequestID middleware sets the generated ID on the response
+		requestID = c.Response().Header().Get(echo.HeaderXRequestID)
+	}
+
+	return map[string]string{
+		JobMetadataRequestID:   requestID,
+		JobMetadataTraceID:     req.Header.Get("X-Amzn-Trace-Id"),
+		JobMetadataTraceparent: req.Header.Get("Traceparent"),
+	}
+}
+
+// enqueueFailure holds the first enqueue error of a request; handlers may enqueue from goroutines
+type enqueueFailure struct {
+	mu  sync.Mutex
+	err error
+}
+
+func (f *enqueueFailure) set(err error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.err == nil {
+		f.err = err
+	}
+}
+
+func (f *enqueueFailure) get() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.err
+}
@@ -0,0 +1,126 @@
+package echokit
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEnqueue(t *testing.T) {
+	t.Run("submits_the_job_with_request_metadata", func(t *testing.T) {
+		e := echo.New()
+		c, _ := NewTestGetRequest(e, "/")
+		c.Request().Header.Set(echo.HeaderXRequestID, "theRequestID")
+		c.Request().Header.Set("X-Amzn-Trace-Id", "theTraceID")
+		c.Request().Header.Set("Traceparent", "theTraceparent")
+		var actualJob Job
+		enqueuer := EnqueuerFunc(func(ctx context.Context, job Job) error {
+			actualJob = job
+			return nil
+		})
+		handler := EnqueueMiddleware(enqueuer)(func(c echo.Context) error {
+			return Enqueue(c, Job{Type: "theType", Payload: "thePayload", Metadata: map[string]string{"theKey": "theValue"}})
+		})
+
+		err := handler(c)
+
+		assert.NoError(t, err)
+		assert.Equal(t, Job{
+			Type:    "theType",
+			Payload: "thePayload",
+			Metadata: map[string]string{
+				JobMetadataRequestID:   "theRequestID",
+				JobMetadataTraceID:     "theTraceID",
+				JobMetadataTraceparent: "theTraceparent",
+				"theKey":               "theValue",
+			},
+		}, actualJob)
+	})
+
+	t.Run("uses_the_generated_request_id_from_the_response", func(t *testing.T) {
+		e := echo.New()
+		c, _ := NewTestGetRequest(e, "/")
+		c.Response().Header().Set(echo.HeaderXRequestID, "theGeneratedID")
+		var actualJob Job
+		enqueuer := EnqueuerFunc(func(ctx context.Context, job Job) error {
+			actualJob = job
+			return nil
+		})
+		handler := EnqueueMiddleware(enqueuer)(func(c echo.Context) error {
+			return Enqueue(c, Job{Type: "theType"})
+		})
+
+		err := handler(c)
+
+		assert.NoError(t, err)
+		assert.Equal(t, map[string]string{JobMetadataRequestID: "theGeneratedID"}, actualJob.Metadata)
+	})
+
+	t.Run("returns_an_error_without_the_middleware", func(t *testing.T) {
+		e := echo.New()
+		c, _ := NewTestGetRequest(e, "/")
+
+		err := Enqueue(c, Job{Type: "theType"})
+
+		assert.EqualError(t, err, "no enqueuer in context; use EnqueueMiddleware")
+	})
+}
+
+func TestEnqueueMiddleware(t *testing.T) {
+	t.Run("fails_the_request_when_an_ignored_enqueue_fails", func(t *testing.T) {
+		e := echo.New()
+		c, _ := NewTestGetRequest(e, "/")
+		enqueuer := EnqueuerFunc(func(ctx context.Context, job Job) error {
+			return errors.New("the enqueue error")
+		})
+		handler := EnqueueMiddleware(enqueuer)(func(c echo.Context) error {
+			_ = Enqueue(c, Job{Type: "theType"})
+			return nil
+		})
+
+		err := handler(c)
+
+		var httpErr *echo.HTTPError
+		assert.ErrorAs(t, err, &httpErr)
+		assert.Equal(t, http.StatusInternalServerError, httpErr.Code)
+		assert.EqualError(t, httpErr.Internal, "error enqueuing theType job: the enqueue error")
+	})
+
+	t.Run("returns_the_handler_error_when_there_is_one", func(t *testing.T) {
+		e := echo.New()
+		c, _ := NewTestGetRequest(e, "/")
+		enqueuer := EnqueuerFunc(func(ctx context.Context, job Job) error {
+			return errors.New("the enqueue error")
+		})
+		handler := EnqueueMiddleware(enqueuer)(func(c echo.Context) error {
+			_ = Enqueue(c, Job{Type: "theType"})
+			return errors.New("the handler error")
+		})
+
+		err := handler(c)
+
+		assert.EqualError(t, err, "the handler error")
+	})
+
+	t.Run("does_not_fail_a_request_whose_response_was_written", func(t *testing.T) {
+		e := echo.New()
+		c, rec := NewTestGetRequest(e, "/")
+		enqueuer := EnqueuerFunc(func(ctx context.Context, job Job) error {
+			return errors.New("the enqueue error")
+		})
+		handler := EnqueueMiddleware(enqueuer)(func(c echo.Context) error {
+			err := c.NoContent(http.StatusAccepted)
+			_ = Enqueue(c, Job{Type: "theType"})
+			return err
+		})
+
+		err := handler(c)
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusAccepted, rec.Code)
+	})
+}
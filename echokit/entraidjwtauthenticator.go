@@ -4,7 +4,6 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"log/slog"
 	"net/http"
 	"net/url"
 	"strings"
@@ -53,7 +52,7 @@ func NewEntraIDJWTAuthenticator(tenantID, audience string) (Authenticator, error
 		return nil, fmt.Errorf("failed to parse authority URL: %w", err)
 	}
 
-	provider := jwks.NewCachingProvider(authorityURL, 5*time.Minute)
+	provider := jwks.NewCachingProvider(authorityURL, 5*time.Minute, jwks.WithCustomClient(jwksClient))
 
 	jwtValidator, err := validator.New(
 		provider.KeyFunc,
@@ -91,7 +90,7 @@ func (a *EntraIDJWTAuthenticator) AuthenticateRequest(c echo.Context) error {
 
 	validateResult, err := a.jwtValidator.ValidateToken(c.Request().Context(), authHeaderParts[1])
 	if err != nil {
-		slog.Debug("Entra ID JWT validation failed", "error", err)
+		logger.Debug("Entra ID JWT validation failed", "error", err)
 		return err
 	}
 
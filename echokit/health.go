@@ -0,0 +1,18 @@
+package echokit
+
+import (
+	"github.com/labstack/echo/v4"
+
+	"github.com/half-ogre/go-kit/healthkit"
+)
+
+// RegisterHealthRoutes registers checker's health endpoints:
+//   - GET /livez serves the liveness report, which is up while the process can serve requests
+//   - GET /readyz runs the checks and serves the readiness report, with a 503 when it's down
+//
+// Register the server's Shutdown with a healthkit.Shutdown so /readyz reports down while requests
+// drain.
+func RegisterHealthRoutes(e *echo.Echo, checker *healthkit.Checker) {
+	e.GET("/livez", echo.WrapHandler(checker.LivenessHandler()))
+	e.GET("/readyz", echo.WrapHandler(checker.ReadinessHandler()))
+}
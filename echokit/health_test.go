@@ -0,0 +1,44 @@
+package echokit
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/half-ogre/go-kit/healthkit"
+)
+
+func TestRegisterHealthRoutes(t *testing.T) {
+	newChecker := func() *healthkit.Checker {
+		checker := healthkit.NewChecker()
+		checker.Register(healthkit.NewCheck("theCheck", func(ctx context.Context) error { return errors.New("the check error") }))
+		return checker
+	}
+
+	t.Run("serves_liveness_at_livez", func(t *testing.T) {
+		e := echo.New()
+		RegisterHealthRoutes(e, newChecker())
+		rec := httptest.NewRecorder()
+
+		e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/livez", nil))
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.JSONEq(t, `{"status":"up","checks":[]}`, rec.Body.String())
+	})
+
+	t.Run("serves_readiness_at_readyz", func(t *testing.T) {
+		e := echo.New()
+		RegisterHealthRoutes(e, newChecker())
+		rec := httptest.NewRecorder()
+
+		e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+
+		assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+		assert.Contains(t, rec.Body.String(), `"error":"the check error"`)
+	})
+}
@@ -0,0 +1,9 @@
+package echokit
+
+import (
+	"github.com/half-ogre/go-kit/httpcachekit"
+)
+
+// jwksClient fetches the OIDC discovery documents and JWKS of the JWT authenticators, so refreshing the
+// keys revalidates them with the issuer rather than downloading them again
+var jwksClient = httpcachekit.NewClient(httpcachekit.NewMemoryStore(100))
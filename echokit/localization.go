@@ -0,0 +1,26 @@
+package echokit
+
+import (
+	"github.com/labstack/echo/v4"
+
+	"github.com/half-ogre/go-kit/i18nkit"
+)
+
+// Localization returns a middleware that puts an i18nkit localizer for the request's preferred locale
+// in the request's context, for GetLocalizer and a Renderer WithRendererCatalog, and sets the
+// Content-Language header to the locale
+func Localization(catalog *i18nkit.Catalog) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			localizer := catalog.RequestLocalizer(c.Request())
+			i18nkit.SetResponseHeaders(c.Response().Header(), localizer)
+			c.SetRequest(c.Request().WithContext(i18nkit.WithLocalizer(c.Request().Context(), localizer)))
+			return next(c)
+		}
+	}
+}
+
+// GetLocalizer returns the localizer Localization put in the request's context, if any
+func GetLocalizer(c echo.Context) (*i18nkit.Localizer, bool) {
+	return i18nkit.FromContext(c.Request().Context())
+}
@@ -0,0 +1,42 @@
+package echokit
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/half-ogre/go-kit/i18nkit"
+)
+
+func TestLocalization(t *testing.T) {
+	catalog := i18nkit.NewCatalog("en")
+	catalog.Add("de", "greeting", i18nkit.Text("Hallo"))
+
+	t.Run("puts_a_localizer_for_the_preferred_locale_in_the_request_context", func(t *testing.T) {
+		c, rec := NewTestGetRequest(echo.New(), "/?lang=de")
+		var locale string
+		handler := Localization(catalog)(func(c echo.Context) error {
+			localizer, ok := GetLocalizer(c)
+			assert.True(t, ok)
+			locale = localizer.Locale()
+			return c.NoContent(http.StatusNoContent)
+		})
+
+		err := handler(c)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "de", locale)
+		assert.Equal(t, "de", rec.Header().Get("Content-Language"))
+		assert.Equal(t, "Accept-Language", rec.Header().Get("Vary"))
+	})
+
+	t.Run("has_no_localizer_without_the_middleware", func(t *testing.T) {
+		c, _ := NewTestGetRequest(echo.New(), "/")
+
+		_, ok := GetLocalizer(c)
+
+		assert.False(t, ok)
+	})
+}
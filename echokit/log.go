@@ -0,0 +1,7 @@
+package echokit
+
+import "github.com/half-ogre/go-kit/logkit"
+
+// logger is the logger for the package's own records, so operators can turn them up or down with
+// logkit.SetLevelSpec, e.g. LOG_LEVEL=info,echokit=debug
+var logger = logkit.Logger("echokit")
@@ -0,0 +1,56 @@
+package echokit
+
+import (
+	"errors"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/openapi3filter"
+	"github.com/labstack/echo/v4"
+
+	"github.com/half-ogre/go-kit/internal/openapi"
+)
+
+// OpenAPIValidatorOption configures the OpenAPI validation middleware
+type OpenAPIValidatorOption = openapi.ValidatorOption
+
+// LoadOpenAPISpec loads and validates the OpenAPI spec at path, which may be JSON or YAML
+func LoadOpenAPISpec(path string) (*openapi3.T, error) {
+	return openapi.LoadSpec(path)
+}
+
+// WithOpenAPIUnknownRoutesAllowed lets requests that match no operation in the spec, such as health
+// checks, through instead of rejecting them with 404 or 405
+func WithOpenAPIUnknownRoutesAllowed() OpenAPIValidatorOption {
+	return openapi.WithUnknownRoutesAllowed()
+}
+
+// WithOpenAPIAuthenticationFunc validates the spec's security requirements with fn; by default they
+// are ignored, leaving authentication to the authentication middleware
+func WithOpenAPIAuthenticationFunc(fn openapi3filter.AuthenticationFunc) OpenAPIValidatorOption {
+	return openapi.WithAuthenticationFunc(fn)
+}
+
+// OpenAPIValidator returns a middleware that validates each request's path, query, headers, and body
+// against spec before calling the handler. Requests that break the contract are rejected with an
+// echo.HTTPError: 400 for invalid input, 404 or 405 for unknown operations.
+func OpenAPIValidator(spec *openapi3.T, options ...OpenAPIValidatorOption) (echo.MiddlewareFunc, error) {
+	validator, err := openapi.NewValidator(spec, options...)
+	if err != nil {
+		return nil, err
+	}
+
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			err := validator.ValidateRequest(c.Request())
+			var validationErr *openapi.ValidationError
+			if errors.As(err, &validationErr) {
+				return echo.NewHTTPError(validationErr.Status, validationErr.Message).SetInternal(validationErr.Err)
+			}
+			if err != nil {
+				return err
+			}
+
+			return next(c)
+		}
+	}, nil
+}
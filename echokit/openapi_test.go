@@ -0,0 +1,100 @@
+package echokit
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+const openAPITestSpec = `{
+  "openapi": "3.0.3",
+  "info": {"title": "Users", "version": "1.0.0"},
+  "paths": {
+    "/users": {
+      "post": {
+        "requestBody": {
+          "required": true,
+          "content": {"application/json": {"schema": {"type": "object", "required": ["name"], "properties": {"name": {"type": "string"}}}}}
+        },
+        "responses": {"201": {"description": "created"}}
+      }
+    }
+  }
+}`
+
+func newOpenAPITestServer(t *testing.T, options ...OpenAPIValidatorOption) (*echo.Echo, *bool) {
+	spec, err := openapi3.NewLoader().LoadFromData([]byte(openAPITestSpec))
+	assert.NoError(t, err)
+	middleware, err := OpenAPIValidator(spec, options...)
+	assert.NoError(t, err)
+
+	handlerCalled := false
+	e := echo.New()
+	e.Use(middleware)
+	e.POST("/users", func(c echo.Context) error {
+		handlerCalled = true
+		var body map[string]string
+		if err := c.Bind(&body); err != nil {
+			return err
+		}
+		return c.String(http.StatusCreated, body["name"])
+	})
+	e.GET("/health", func(c echo.Context) error {
+		handlerCalled = true
+		return c.NoContent(http.StatusOK)
+	})
+	return e, &handlerCalled
+}
+
+func TestOpenAPIValidator(t *testing.T) {
+	t.Run("calls_the_handler_with_the_body_when_the_request_is_valid", func(t *testing.T) {
+		e, handlerCalled := newOpenAPITestServer(t)
+		req := httptest.NewRequest(http.MethodPost, "/users", strings.NewReader(`{"name":"theName"}`))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+
+		e.ServeHTTP(rec, req)
+
+		assert.True(t, *handlerCalled)
+		assert.Equal(t, http.StatusCreated, rec.Code)
+		assert.Equal(t, "theName", rec.Body.String())
+	})
+
+	t.Run("rejects_an_invalid_request_with_bad_request", func(t *testing.T) {
+		e, handlerCalled := newOpenAPITestServer(t)
+		req := httptest.NewRequest(http.MethodPost, "/users", strings.NewReader(`{}`))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+
+		e.ServeHTTP(rec, req)
+
+		assert.False(t, *handlerCalled)
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		assert.Contains(t, rec.Body.String(), `"message":"invalid request body:`)
+	})
+
+	t.Run("rejects_an_unknown_route_with_not_found", func(t *testing.T) {
+		e, handlerCalled := newOpenAPITestServer(t)
+		rec := httptest.NewRecorder()
+
+		e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/health", nil))
+
+		assert.False(t, *handlerCalled)
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+	})
+
+	t.Run("lets_unknown_routes_through_when_allowed", func(t *testing.T) {
+		e, handlerCalled := newOpenAPITestServer(t, WithOpenAPIUnknownRoutesAllowed())
+		rec := httptest.NewRecorder()
+
+		e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/health", nil))
+
+		assert.True(t, *handlerCalled)
+		assert.Equal(t, http.StatusOK, rec.Code)
+	})
+}
@@ -9,6 +9,7 @@ import (
 	"path/filepath"
 	"strings"
 
+	"github.com/half-ogre/go-kit/i18nkit"
 	"github.com/half-ogre/go-kit/kit"
 	"github.com/labstack/echo/v4"
 )
@@ -16,17 +17,34 @@ import (
 type LayoutModelFunc func(c echo.Context, path string, tmpl *template.Template, data interface{}) (interface{}, error)
 
 type Renderer struct {
+	catalog           *i18nkit.Catalog
 	layoutModelFunc   LayoutModelFunc
 	templates         map[string]*template.Template
 	templateFilesPath string
 }
 
-func NewRenderer(templateFilesPath string, layoutModelFunc LayoutModelFunc) *Renderer {
-	return &Renderer{
+// RendererOption configures a Renderer
+type RendererOption func(*Renderer)
+
+// WithRendererCatalog gives templates the i18nkit template functions, e.g. {{ t "greeting" }},
+// translating into the locale of the localizer the Localization middleware put in the request's
+// context, or else the locale the request prefers
+func WithRendererCatalog(catalog *i18nkit.Catalog) RendererOption {
+	return func(r *Renderer) {
+		r.catalog = catalog
+	}
+}
+
+func NewRenderer(templateFilesPath string, layoutModelFunc LayoutModelFunc, options ...RendererOption) *Renderer {
+	r := &Renderer{
 		layoutModelFunc:   layoutModelFunc,
 		templates:         map[string]*template.Template{},
 		templateFilesPath: templateFilesPath,
 	}
+	for _, option := range options {
+		option(r)
+	}
+	return r
 }
 
 func (r *Renderer) Render(w io.Writer, path string, data interface{}, c echo.Context) error {
@@ -53,7 +71,11 @@ func (r *Renderer) Render(w io.Writer, path string, data interface{}, c echo.Con
 			templates = append([]string{layout}, templates...)
 		}
 
-		tmpl, err = template.ParseFiles(templates...)
+		tmpl = template.New(filepath.Base(templates[0]))
+		if r.catalog != nil {
+			tmpl = tmpl.Funcs(i18nkit.FuncMap(r.catalog.Localizer()))
+		}
+		tmpl, err = tmpl.ParseFiles(templates...)
 		if err != nil {
 			return kit.WrapError(err, "error parsing template files")
 		}
@@ -63,6 +85,19 @@ func (r *Renderer) Render(w io.Writer, path string, data interface{}, c echo.Con
 		}
 	}
 
+	if r.catalog != nil {
+		localizer, ok := i18nkit.FromContext(c.Request().Context())
+		if !ok {
+			localizer = r.catalog.RequestLocalizer(c.Request())
+		}
+
+		localized, err := tmpl.Clone()
+		if err != nil {
+			return kit.WrapError(err, "error cloning template %s", path)
+		}
+		tmpl = localized.Funcs(i18nkit.FuncMap(localizer))
+	}
+
 	layoutModel, err := r.layoutModelFunc(c, path, tmpl, data)
 	if err != nil {
 		return kit.WrapError(err, "error getting layout model")
@@ -12,6 +12,8 @@ import (
 	"github.com/labstack/echo/v4"
 	"github.com/labstack/echo/v4/middleware"
 	"github.com/stretchr/testify/assert"
+
+	"github.com/half-ogre/go-kit/i18nkit"
 )
 
 func TestNewRenderer(t *testing.T) {
@@ -285,3 +287,48 @@ func TestRenderer_RenderWithPartials(t *testing.T) {
 	result := strings.TrimSpace(buf.String())
 	assert.Equal(t, "<html><body><header>Site Header</header><h1>Partials Test</h1><footer>Site Footer</footer></body></html>", result)
 }
+
+func TestRenderer_RenderWithCatalog(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "renderer_test_*")
+	assert.NoError(t, err)
+	t.Cleanup(func() { os.RemoveAll(tmpDir) })
+
+	layoutContent := `{{ define "layout" }}<html lang="{{ locale }}">{{ template "content" . }}</html>{{ end }}`
+	templateContent := `{{ define "content" }}<p>{{ t "greeting" "name" .Name }}</p>{{ end }}`
+	err = os.WriteFile(filepath.Join(tmpDir, "_layout.html"), []byte(layoutContent), 0644)
+	assert.NoError(t, err)
+	err = os.WriteFile(filepath.Join(tmpDir, "localized.html"), []byte(templateContent), 0644)
+	assert.NoError(t, err)
+
+	catalog := i18nkit.NewCatalog("en")
+	catalog.Add("en", "greeting", i18nkit.Text("Hello, {name}!"))
+	catalog.Add("fr", "greeting", i18nkit.Text("Bonjour, {name} !"))
+	layoutModelFunc := func(c echo.Context, path string, tmpl *template.Template, data interface{}) (interface{}, error) {
+		return data, nil
+	}
+	renderer := NewRenderer(tmpDir, layoutModelFunc, WithRendererCatalog(catalog))
+	theData := map[string]string{"Name": "Ada"}
+
+	t.Run("renders_in_the_locale_the_request_prefers", func(t *testing.T) {
+		c, _ := NewTestGetRequest(echo.New(), "/")
+		c.Request().Header.Set("Accept-Language", "fr-CA,fr;q=0.9")
+
+		var buf bytes.Buffer
+		err := renderer.Render(&buf, "localized", theData, c)
+
+		assert.NoError(t, err)
+		assert.Equal(t, `<html lang="fr"><p>Bonjour, Ada !</p></html>`, strings.TrimSpace(buf.String()))
+	})
+
+	t.Run("renders_in_the_locale_of_the_localizer_in_the_request_context", func(t *testing.T) {
+		c, _ := NewTestGetRequest(echo.New(), "/")
+		c.Request().Header.Set("Accept-Language", "fr")
+		c.SetRequest(c.Request().WithContext(i18nkit.WithLocalizer(c.Request().Context(), catalog.Localizer("en"))))
+
+		var buf bytes.Buffer
+		err := renderer.Render(&buf, "localized", theData, c)
+
+		assert.NoError(t, err)
+		assert.Equal(t, `<html lang="en"><p>Hello, Ada!</p></html>`, strings.TrimSpace(buf.String()))
+	})
+}
@@ -1,9 +1,12 @@
 package echokit
 
 import (
+	"errors"
 	"log/slog"
 	"time"
 
+	"github.com/half-ogre/go-kit/logkit"
+	"github.com/half-ogre/go-kit/logkit/logfields"
 	"github.com/labstack/echo/v4"
 )
 
@@ -46,9 +49,9 @@ func RequestLoggerWithConfig(config RequestLoggerConfig) echo.MiddlewareFunc {
 				errMsg = err.Error()
 			}
 
-			slog.Log(c.Request().Context(), logLevel, "request",
-				"id", req.Header.Get(echo.HeaderXRequestID),
-				"amzn_trace_id", req.Header.Get("X-Amzn-Trace-Id"),
+			attrs := []any{
+				logfields.RequestID(req.Header.Get(echo.HeaderXRequestID)),
+				logfields.TraceID(req.Header.Get("X-Amzn-Trace-Id")),
 				"remote_ip", c.RealIP(),
 				"x_forwarded_for", req.Header.Get("X-Forwarded-For"),
 				"x_forwarded_proto", req.Header.Get("X-Forwarded-Proto"),
@@ -58,25 +61,39 @@ func RequestLoggerWithConfig(config RequestLoggerConfig) echo.MiddlewareFunc {
 				"user_agent", req.UserAgent(),
 				"status", res.Status,
 				"error", errMsg,
-				"latency", latency.Nanoseconds(),
-				"latency_human", latency.String(),
+				logfields.Duration(latency),
+				"duration_human", latency.String(),
 				"bytes_in", req.Header.Get(echo.HeaderContentLength),
 				"bytes_out", res.Size,
-			)
+				"route", RouteName(c),
+			}
+			if labels := RouteLabels(c); len(labels) > 0 {
+				attrs = append(attrs, "route_labels", labels)
+			}
+
+			slog.Log(c.Request().Context(), logLevel, "request", attrs...)
 
 			return err
 		}
 	}
 }
 
-// PanicLogger logs panics at ERROR level with error message, stack trace, URI, and method.
-// This function is meant to be used as the LogErrorFunc in echomiddleware.RecoverConfig.
+// PanicLogger logs panics at ERROR level with error message, stack trace, URI, method, and request ID,
+// the same fields as ginkit.Recovery. A panic re-panicked by logkit.CaptureAndRepanic is logged with
+// the stack from where it happened. This function is meant to be used as the LogErrorFunc in
+// echomiddleware.RecoverConfig.
 func PanicLogger(c echo.Context, err error, stack []byte) error {
-	slog.Error("panic recovered",
-		"error", err.Error(),
-		"stack", string(stack),
-		"uri", c.Request().RequestURI,
-		"method", c.Request().Method,
-	)
+	var panicErr *logkit.PanicError
+	if !errors.As(err, &panicErr) {
+		panicErr = &logkit.PanicError{Value: err, Stack: stack}
+	}
+
+	req := c.Request()
+	requestID := req.Header.Get(echo.HeaderXRequestID)
+	if requestID == "" {
+		requestID = c.Response().Header().Get(echo.HeaderXRequestID)
+	}
+
+	logkit.LogPanic(req.Context(), panicErr, logkit.PanicRequestAttrs(req.Method, req.RequestURI, requestID)...)
 	return err
 }
@@ -11,6 +11,8 @@ import (
 	"github.com/labstack/echo/v4"
 	echomiddleware "github.com/labstack/echo/v4/middleware"
 	"github.com/stretchr/testify/assert"
+
+	"github.com/half-ogre/go-kit/logkit"
 )
 
 func TestRequestLogger(t *testing.T) {
@@ -158,7 +160,7 @@ func TestRequestLogger(t *testing.T) {
 		e.ServeHTTP(rec, req)
 
 		logOutput := logBuf.String()
-		assert.Contains(t, logOutput, `"id":`)
+		assert.Contains(t, logOutput, `"request_id":`)
 	})
 
 	t.Run("logs_remote_ip", func(t *testing.T) {
@@ -185,7 +187,7 @@ func TestRequestLogger(t *testing.T) {
 		assert.Contains(t, logOutput, `"remote_ip":"192.168.1.100"`)
 	})
 
-	t.Run("logs_amzn_trace_id_when_present", func(t *testing.T) {
+	t.Run("logs_amzn_trace_id_as_the_trace_id_when_present", func(t *testing.T) {
 		var logBuf bytes.Buffer
 		testLogger := slog.New(slog.NewJSONHandler(&logBuf, nil))
 		slog.SetDefault(testLogger)
@@ -206,10 +208,10 @@ func TestRequestLogger(t *testing.T) {
 		e.ServeHTTP(rec, req)
 
 		logOutput := logBuf.String()
-		assert.Contains(t, logOutput, `"amzn_trace_id":"Root=1-67890abc-12345678901234567890abcd"`)
+		assert.Contains(t, logOutput, `"trace_id":"Root=1-67890abc-12345678901234567890abcd"`)
 	})
 
-	t.Run("logs_empty_amzn_trace_id_when_not_present", func(t *testing.T) {
+	t.Run("logs_empty_trace_id_when_not_present", func(t *testing.T) {
 		var logBuf bytes.Buffer
 		testLogger := slog.New(slog.NewJSONHandler(&logBuf, nil))
 		slog.SetDefault(testLogger)
@@ -229,7 +231,7 @@ func TestRequestLogger(t *testing.T) {
 		e.ServeHTTP(rec, req)
 
 		logOutput := logBuf.String()
-		assert.Contains(t, logOutput, `"amzn_trace_id":""`)
+		assert.Contains(t, logOutput, `"trace_id":""`)
 	})
 
 	t.Run("logs_x_forwarded_for_when_present", func(t *testing.T) {
@@ -326,7 +328,7 @@ func TestRequestLogger(t *testing.T) {
 		assert.Contains(t, logOutput, `"x_forwarded_proto":""`)
 	})
 
-	t.Run("logs_latency_in_nanoseconds_and_human_readable", func(t *testing.T) {
+	t.Run("logs_duration_in_nanoseconds_and_human_readable", func(t *testing.T) {
 		var logBuf bytes.Buffer
 		testLogger := slog.New(slog.NewJSONHandler(&logBuf, nil))
 		slog.SetDefault(testLogger)
@@ -346,8 +348,8 @@ func TestRequestLogger(t *testing.T) {
 		e.ServeHTTP(rec, req)
 
 		logOutput := logBuf.String()
-		assert.Contains(t, logOutput, `"latency":`)
-		assert.Contains(t, logOutput, `"latency_human":`)
+		assert.Contains(t, logOutput, `"duration":`)
+		assert.Contains(t, logOutput, `"duration_human":`)
 	})
 
 	t.Run("logs_http_error_in_error_field", func(t *testing.T) {
@@ -502,3 +504,49 @@ func TestRequestLogger(t *testing.T) {
 		assert.Contains(t, logOutput, `"error":"the panic message"`)
 	})
 }
+
+func panickingHandler(c echo.Context) error {
+	panic("thePanic")
+}
+
+func TestPanicLogger(t *testing.T) {
+	t.Run("logs_the_panic_with_request_fields", func(t *testing.T) {
+		var logBuf bytes.Buffer
+		previous := slog.Default()
+		slog.SetDefault(slog.New(slog.NewJSONHandler(&logBuf, nil)))
+		t.Cleanup(func() { slog.SetDefault(previous) })
+		e := echo.New()
+		e.Use(echomiddleware.RecoverWithConfig(echomiddleware.RecoverConfig{LogErrorFunc: PanicLogger}))
+		e.GET("/test", panickingHandler)
+		req := httptest.NewRequest(http.MethodGet, "/test?id=1", nil)
+		req.Header.Set(echo.HeaderXRequestID, "theRequestID")
+
+		e.ServeHTTP(httptest.NewRecorder(), req)
+
+		logOutput := logBuf.String()
+		assert.Contains(t, logOutput, `"msg":"panic recovered"`)
+		assert.Contains(t, logOutput, `"error":"thePanic"`)
+		assert.Contains(t, logOutput, `"method":"GET"`)
+		assert.Contains(t, logOutput, `"uri":"/test?id=1"`)
+		assert.Contains(t, logOutput, `"request_id":"theRequestID"`)
+		assert.Contains(t, logOutput, `"stack":`)
+	})
+
+	t.Run("logs_the_stack_of_a_captured_panic", func(t *testing.T) {
+		var logBuf bytes.Buffer
+		previous := slog.Default()
+		slog.SetDefault(slog.New(slog.NewJSONHandler(&logBuf, nil)))
+		t.Cleanup(func() { slog.SetDefault(previous) })
+		e := echo.New()
+		e.Use(echomiddleware.RecoverWithConfig(echomiddleware.RecoverConfig{LogErrorFunc: PanicLogger}))
+		e.GET("/test", func(c echo.Context) error {
+			defer logkit.CaptureAndRepanic()
+			return panickingHandler(c)
+		})
+
+		e.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/test", nil))
+
+		assert.Contains(t, logBuf.String(), `"error":"thePanic"`)
+		assert.Contains(t, logBuf.String(), "panickingHandler")
+	})
+}
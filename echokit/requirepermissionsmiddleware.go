@@ -2,10 +2,10 @@ package echokit
 
 import (
 	"errors"
-	"log/slog"
 	"slices"
 
 	"github.com/half-ogre/go-kit/kit"
+	"github.com/half-ogre/go-kit/logkit/logfields"
 	"github.com/labstack/echo/v4"
 )
 
@@ -34,7 +34,7 @@ func RequirePermissions(audience string, permissions []string, orPermissions ...
 					return kit.WrapError(err, "error getting authenticated user")
 				}
 
-				slog.Debug("checking user permissions", "user", authenticatedUser)
+				logger.Debug("checking user permissions", logfields.UserSub(authenticatedUser.Sub))
 
 				userPerms := authenticatedUser.Permissions[audience]
 				hasPermissions := checkPermissions(userPerms, permissions)
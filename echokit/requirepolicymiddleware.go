@@ -0,0 +1,89 @@
+package echokit
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/half-ogre/go-kit/authzkit"
+	"github.com/half-ogre/go-kit/kit"
+	"github.com/half-ogre/go-kit/logkit/logfields"
+)
+
+const authorizationDecisionContextKey = "github.com/half-ogre/go-kit/echokit/authorization_decision"
+
+// PolicyResourceFunc returns the resource a request acts on, e.g. loaded by the ID in its path so a
+// policy can check who owns it
+type PolicyResourceFunc func(c echo.Context) (authzkit.Resource, error)
+
+// PrincipalForUser returns the authzkit principal for user, with the user's permissions for audience
+func PrincipalForUser(user *AuthenticatedUser, audience string) authzkit.Principal {
+	return authzkit.Principal{
+		ID:          user.Sub,
+		Permissions: user.Permissions[audience],
+		Attributes: map[string]any{
+			"email":          user.Email,
+			"email_verified": user.EmailVerified,
+			"name":           user.Name,
+		},
+	}
+}
+
+// RequirePolicy returns a middleware that asks engine whether the authenticated user may perform action
+// on the resource returned by resource, responding 403 Forbidden if not. Unauthenticated requests are
+// handled by the authenticator like RequirePermissions does. The decision is available to handlers
+// with GetAuthorizationDecision.
+func RequirePolicy(engine *authzkit.Engine, audience, action string, resource PolicyResourceFunc) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			authenticator, err := GetAuthenticator(c)
+			if err != nil {
+				return kit.WrapError(err, "error getting authenticator")
+			}
+
+			if authenticator == nil {
+				return errors.New("authenticator not found in context")
+			}
+
+			isAuthenticated, err := authenticator.IsAuthenticated(c)
+			if err != nil {
+				return kit.WrapError(err, "error checking authentication")
+			}
+
+			if !isAuthenticated {
+				return authenticator.HandleNotAuthenticated(c)
+			}
+
+			authenticatedUser, err := authenticator.GetAuthenticatedUser(c)
+			if err != nil {
+				return kit.WrapError(err, "error getting authenticated user")
+			}
+
+			theResource, err := resource(c)
+			if err != nil {
+				return kit.WrapError(err, "error getting resource for authorization")
+			}
+
+			decision := engine.Evaluate(c.Request().Context(), authzkit.Request{
+				Principal: PrincipalForUser(authenticatedUser, audience),
+				Action:    action,
+				Resource:  theResource,
+			})
+			c.Set(authorizationDecisionContextKey, decision)
+
+			if !decision.Allowed {
+				logger.Debug("request denied by policy", logfields.UserSub(authenticatedUser.Sub), "reason", decision.Reason)
+				return echo.NewHTTPError(http.StatusForbidden)
+			}
+
+			return next(c)
+		}
+	}
+}
+
+// GetAuthorizationDecision returns the decision RequirePolicy made for the request, if any
+func GetAuthorizationDecision(c echo.Context) (authzkit.Decision, bool) {
+	decision, ok := c.Get(authorizationDecisionContextKey).(authzkit.Decision)
+	return decision, ok
+}
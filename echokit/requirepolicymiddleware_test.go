@@ -0,0 +1,131 @@
+package echokit
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/half-ogre/go-kit/authzkit"
+)
+
+func newTestPolicyEngine(t *testing.T) *authzkit.Engine {
+	engine, err := authzkit.NewEngine([]authzkit.Policy{
+		{ID: "owners", Effect: authzkit.EffectAllow, Actions: []string{"edit"}, Resources: []string{"document"}, Condition: authzkit.OwnerIs("owner")},
+	})
+	assert.NoError(t, err)
+	return engine
+}
+
+func newTestPolicyAuthenticator(user *AuthenticatedUser) *FakeAuthenticator {
+	return &FakeAuthenticator{
+		IsAuthenticatedFake: func(c echo.Context) (bool, error) {
+			return true, nil
+		},
+		GetAuthenticatedUserFake: func(c echo.Context) (*AuthenticatedUser, error) {
+			return user, nil
+		},
+	}
+}
+
+func theDocumentOwnedBy(owner string) PolicyResourceFunc {
+	return func(c echo.Context) (authzkit.Resource, error) {
+		return authzkit.Resource{Type: "document", ID: "theDocument", Attributes: map[string]any{"owner": owner}}, nil
+	}
+}
+
+func TestRequirePolicy(t *testing.T) {
+	t.Run("calls_the_next_handler_when_the_policy_allows", func(t *testing.T) {
+		e := echo.New()
+		c, rec := NewTestGetRequest(e, "/")
+		c.Set(authenticatorContextKey, newTestPolicyAuthenticator(&AuthenticatedUser{Sub: "theUser"}))
+
+		middleware := RequirePolicy(newTestPolicyEngine(t), "theAudience", "edit", theDocumentOwnedBy("theUser"))
+		handler := middleware(func(c echo.Context) error {
+			return c.String(http.StatusOK, "success")
+		})
+
+		err := handler(c)
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusOK, rec.Code)
+		decision, ok := GetAuthorizationDecision(c)
+		assert.True(t, ok)
+		assert.Equal(t, "owners", decision.PolicyID)
+	})
+
+	t.Run("returns_forbidden_when_the_policy_denies", func(t *testing.T) {
+		e := echo.New()
+		c, _ := NewTestGetRequest(e, "/")
+		c.Set(authenticatorContextKey, newTestPolicyAuthenticator(&AuthenticatedUser{Sub: "anotherUser"}))
+		nextCalled := false
+
+		middleware := RequirePolicy(newTestPolicyEngine(t), "theAudience", "edit", theDocumentOwnedBy("theUser"))
+		handler := middleware(func(c echo.Context) error {
+			nextCalled = true
+			return nil
+		})
+
+		err := handler(c)
+
+		assert.Equal(t, echo.NewHTTPError(http.StatusForbidden), err)
+		assert.False(t, nextCalled)
+		decision, ok := GetAuthorizationDecision(c)
+		assert.True(t, ok)
+		assert.False(t, decision.Allowed)
+	})
+
+	t.Run("calls_HandleNotAuthenticated_when_user_is_not_authenticated", func(t *testing.T) {
+		handleNotAuthenticatedCalled := false
+		e := echo.New()
+		c, _ := NewTestGetRequest(e, "/")
+		c.Set(authenticatorContextKey, &FakeAuthenticator{
+			IsAuthenticatedFake: func(c echo.Context) (bool, error) {
+				return false, nil
+			},
+			HandleNotAuthenticatedFake: func(c echo.Context) error {
+				handleNotAuthenticatedCalled = true
+				return c.NoContent(http.StatusUnauthorized)
+			},
+		})
+
+		middleware := RequirePolicy(newTestPolicyEngine(t), "theAudience", "edit", theDocumentOwnedBy("theUser"))
+		handler := middleware(func(c echo.Context) error {
+			return nil
+		})
+
+		err := handler(c)
+
+		assert.NoError(t, err)
+		assert.True(t, handleNotAuthenticatedCalled)
+	})
+
+	t.Run("returns_an_error_when_the_resource_cannot_be_loaded", func(t *testing.T) {
+		e := echo.New()
+		c, _ := NewTestGetRequest(e, "/")
+		c.Set(authenticatorContextKey, newTestPolicyAuthenticator(&AuthenticatedUser{Sub: "theUser"}))
+
+		middleware := RequirePolicy(newTestPolicyEngine(t), "theAudience", "edit", func(c echo.Context) (authzkit.Resource, error) {
+			return authzkit.Resource{}, assert.AnError
+		})
+		handler := middleware(func(c echo.Context) error {
+			return nil
+		})
+
+		err := handler(c)
+
+		assert.ErrorIs(t, err, assert.AnError)
+		assert.Contains(t, err.Error(), "error getting resource for authorization")
+	})
+}
+
+func TestPrincipalForUser(t *testing.T) {
+	user := &AuthenticatedUser{Sub: "theUser", Email: "theEmail", Permissions: map[string][]string{"theAudience": {"thePermission"}}}
+
+	principal := PrincipalForUser(user, "theAudience")
+
+	assert.Equal(t, "theUser", principal.ID)
+	assert.Equal(t, []string{"thePermission"}, principal.Permissions)
+	assert.Equal(t, "theEmail", principal.Attributes["email"])
+}
@@ -0,0 +1,84 @@
+package echokit
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/half-ogre/go-kit/internal/respond"
+	"github.com/half-ogre/go-kit/kit"
+)
+
+const responseTemplateContextKey = "github.com/half-ogre/go-kit/echokit/response_template"
+
+// ResponseEncoder writes model as the response with status in the encoder's media type
+type ResponseEncoder = respond.Encoder
+
+// CSVMarshaler is implemented by models that write themselves as CSV records, header first
+type CSVMarshaler = respond.CSVMarshaler
+
+// RegisterResponseEncoder sets the encoder Respond uses for mediaType, e.g. "application/xml",
+// replacing any encoder already registered for it. New media types are offered after the existing
+// ones when the Accept header doesn't prefer one. Encoders are shared with ginkit.Respond.
+func RegisterResponseEncoder(mediaType string, encoder ResponseEncoder) {
+	respond.Register(mediaType, encoder)
+}
+
+// RespondOption configures Respond
+type RespondOption func(*respondConfig)
+
+type respondConfig struct {
+	template string
+}
+
+// WithResponseTemplate sets the Renderer template for an HTML response, overriding WithRouteTemplate
+func WithResponseTemplate(path string) RespondOption {
+	return func(c *respondConfig) {
+		c.template = path
+	}
+}
+
+// WithRouteTemplate is route middleware that sets the Renderer template Respond uses for an HTML
+// response, e.g. e.GET("/users/:id", getUser, echokit.WithRouteTemplate("users/show"))
+func WithRouteTemplate(path string) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			c.Set(responseTemplateContextKey, path)
+			return next(c)
+		}
+	}
+}
+
+// Respond writes model with status in the media type the request's Accept header prefers: JSON, HTML
+// rendered with the echo Renderer when the route has a template, CSV, or one added with
+// RegisterResponseEncoder. A request without an Accept header gets JSON. When none of the accepted
+// media types can be written, Respond returns a 406 Not Acceptable error.
+func Respond(c echo.Context, status int, model any, options ...RespondOption) error {
+	config := respondConfig{}
+	for _, option := range options {
+		option(&config)
+	}
+	template := config.template
+	if template == "" {
+		template, _ = c.Get(responseTemplateContextKey).(string)
+	}
+
+	c.Response().Header().Add(echo.HeaderVary, echo.HeaderAccept)
+
+	canRenderHTML := template != "" && c.Echo().Renderer != nil
+	mediaType, encode, ok := respond.Negotiate(c.Request().Header.Values(echo.HeaderAccept), canRenderHTML)
+	if !ok {
+		return echo.NewHTTPError(http.StatusNotAcceptable, "none of the accepted media types can be returned")
+	}
+
+	var err error
+	if encode == nil {
+		err = c.Render(status, template, model)
+	} else {
+		err = encode(c.Response(), status, model)
+	}
+	if err != nil {
+		return kit.WrapError(err, "error writing %s response", mediaType)
+	}
+	return nil
+}
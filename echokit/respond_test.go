@@ -0,0 +1,141 @@
+package echokit
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/half-ogre/go-kit/internal/respond"
+)
+
+type testRespondUser struct {
+	ID     string `json:"id" csv:"id"`
+	Name   string `json:"name" csv:"name"`
+	Secret string `json:"-" csv:"-"`
+}
+
+type fakeTemplateRenderer struct{}
+
+func (fakeTemplateRenderer) Render(w io.Writer, name string, data any, c echo.Context) error {
+	_, err := fmt.Fprintf(w, "<p>%s: %v</p>", name, data)
+	return err
+}
+
+func serveRespond(e *echo.Echo, accept string, middleware ...echo.MiddlewareFunc) *httptest.ResponseRecorder {
+	e.GET("/users", func(c echo.Context) error {
+		return Respond(c, http.StatusOK, []testRespondUser{{ID: "theID", Name: "theName", Secret: "theSecret"}})
+	}, middleware...)
+
+	req := httptest.NewRequest(http.MethodGet, "/users", nil)
+	if accept != "" {
+		req.Header.Set(echo.HeaderAccept, accept)
+	}
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestRespond(t *testing.T) {
+	t.Run("responds_with_json_when_there_is_no_accept_header", func(t *testing.T) {
+		rec := serveRespond(echo.New(), "")
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, echo.MIMEApplicationJSON, rec.Header().Get(echo.HeaderContentType))
+		assert.JSONEq(t, `[{"id":"theID","name":"theName"}]`, rec.Body.String())
+		assert.Equal(t, echo.HeaderAccept, rec.Header().Get(echo.HeaderVary))
+	})
+
+	t.Run("renders_html_with_the_route_template", func(t *testing.T) {
+		e := echo.New()
+		e.Renderer = fakeTemplateRenderer{}
+
+		rec := serveRespond(e, "text/html,application/xhtml+xml,*/*;q=0.8", WithRouteTemplate("users/index"))
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, echo.MIMETextHTMLCharsetUTF8, rec.Header().Get(echo.HeaderContentType))
+		assert.Equal(t, "<p>users/index: [{theID theName theSecret}]</p>", rec.Body.String())
+	})
+
+	t.Run("uses_the_response_template_option_over_the_route_template", func(t *testing.T) {
+		e := echo.New()
+		e.Renderer = fakeTemplateRenderer{}
+		e.GET("/users", func(c echo.Context) error {
+			return Respond(c, http.StatusOK, "theModel", WithResponseTemplate("users/other"))
+		}, WithRouteTemplate("users/index"))
+		req := httptest.NewRequest(http.MethodGet, "/users", nil)
+		req.Header.Set(echo.HeaderAccept, "text/html")
+		rec := httptest.NewRecorder()
+
+		e.ServeHTTP(rec, req)
+
+		assert.Equal(t, "<p>users/other: theModel</p>", rec.Body.String())
+	})
+
+	t.Run("falls_back_to_json_for_browsers_when_the_route_has_no_template", func(t *testing.T) {
+		e := echo.New()
+		e.Renderer = fakeTemplateRenderer{}
+
+		rec := serveRespond(e, "text/html,*/*;q=0.8")
+
+		assert.Equal(t, echo.MIMEApplicationJSON, rec.Header().Get(echo.HeaderContentType))
+	})
+
+	t.Run("responds_with_csv_for_a_slice_of_structs", func(t *testing.T) {
+		rec := serveRespond(echo.New(), "text/csv")
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "text/csv; charset=utf-8", rec.Header().Get(echo.HeaderContentType))
+		assert.Equal(t, "id,name\ntheID,theName\n", rec.Body.String())
+	})
+
+	t.Run("picks_the_media_type_with_the_highest_quality", func(t *testing.T) {
+		rec := serveRespond(echo.New(), "application/json;q=0.5, text/csv;q=0.9")
+
+		assert.Equal(t, "text/csv; charset=utf-8", rec.Header().Get(echo.HeaderContentType))
+	})
+
+	t.Run("matches_a_subtype_wildcard", func(t *testing.T) {
+		rec := serveRespond(echo.New(), "text/*")
+
+		assert.Equal(t, "text/csv; charset=utf-8", rec.Header().Get(echo.HeaderContentType))
+	})
+
+	t.Run("returns_not_acceptable_when_no_accepted_media_type_can_be_written", func(t *testing.T) {
+		rec := serveRespond(echo.New(), "application/xml, application/json;q=0")
+
+		assert.Equal(t, http.StatusNotAcceptable, rec.Code)
+	})
+
+	t.Run("uses_a_registered_encoder", func(t *testing.T) {
+		RegisterResponseEncoder("application/xml", func(w http.ResponseWriter, status int, model any) error {
+			w.Header().Set(echo.HeaderContentType, echo.MIMEApplicationXML)
+			w.WriteHeader(status)
+			_, err := fmt.Fprintf(w, "<count>%d</count>", len(model.([]testRespondUser)))
+			return err
+		})
+		t.Cleanup(respond.ResetEncoders)
+
+		rec := serveRespond(echo.New(), "application/xml")
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Contains(t, rec.Body.String(), "<count>1</count>")
+	})
+
+	t.Run("replaces_the_encoder_for_a_registered_media_type", func(t *testing.T) {
+		RegisterResponseEncoder(echo.MIMEApplicationJSON, func(w http.ResponseWriter, status int, model any) error {
+			w.WriteHeader(status)
+			_, err := io.WriteString(w, "theJSON")
+			return err
+		})
+		t.Cleanup(respond.ResetEncoders)
+
+		rec := serveRespond(echo.New(), "application/json")
+
+		assert.Equal(t, "theJSON", rec.Body.String())
+	})
+}
@@ -0,0 +1,54 @@
+package echokit
+
+import (
+	"maps"
+
+	"github.com/labstack/echo/v4"
+)
+
+const (
+	routeNameContextKey   = "github.com/half-ogre/go-kit/echokit/route_name"
+	routeLabelsContextKey = "github.com/half-ogre/go-kit/echokit/route_labels"
+)
+
+// WithRouteName is route middleware that gives a route a logical name, e.g.
+// e.GET("/users/:id", getUser, echokit.WithRouteName("get_user")), which RequestLogger logs as "route"
+// so dashboards can group requests by name instead of path template
+func WithRouteName(name string) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			c.Set(routeNameContextKey, name)
+			return next(c)
+		}
+	}
+}
+
+// WithRouteLabels is route middleware that attaches labels to a route, e.g. {"team": "accounts"},
+// which RequestLogger logs under "route_labels". Labels from more than one WithRouteLabels are merged.
+func WithRouteLabels(labels map[string]string) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			merged := maps.Clone(RouteLabels(c))
+			if merged == nil {
+				merged = make(map[string]string, len(labels))
+			}
+			maps.Copy(merged, labels)
+			c.Set(routeLabelsContextKey, merged)
+			return next(c)
+		}
+	}
+}
+
+// RouteName returns the name set with WithRouteName, or the route's path template if it has none
+func RouteName(c echo.Context) string {
+	if name, ok := c.Get(routeNameContextKey).(string); ok {
+		return name
+	}
+	return c.Path()
+}
+
+// RouteLabels returns the labels set with WithRouteLabels, or nil if there are none
+func RouteLabels(c echo.Context) map[string]string {
+	labels, _ := c.Get(routeLabelsContextKey).(map[string]string)
+	return labels
+}
@@ -0,0 +1,82 @@
+package echokit
+
+import (
+	"bytes"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRouteName(t *testing.T) {
+	t.Run("returns_the_name_set_on_the_route", func(t *testing.T) {
+		e := echo.New()
+		var actualName string
+		e.GET("/users/:id", func(c echo.Context) error {
+			actualName = RouteName(c)
+			return nil
+		}, WithRouteName("get_user"))
+
+		e.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/users/123", nil))
+
+		assert.Equal(t, "get_user", actualName)
+	})
+
+	t.Run("falls_back_to_the_path_template", func(t *testing.T) {
+		e := echo.New()
+		var actualName string
+		e.GET("/users/:id", func(c echo.Context) error {
+			actualName = RouteName(c)
+			return nil
+		})
+
+		e.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/users/123", nil))
+
+		assert.Equal(t, "/users/:id", actualName)
+	})
+}
+
+func TestRouteLabels(t *testing.T) {
+	t.Run("merges_labels_from_groups_and_routes", func(t *testing.T) {
+		e := echo.New()
+		group := e.Group("/users", WithRouteLabels(map[string]string{"team": "accounts", "tier": "1"}))
+		var actualLabels map[string]string
+		group.GET("/:id", func(c echo.Context) error {
+			actualLabels = RouteLabels(c)
+			return nil
+		}, WithRouteLabels(map[string]string{"tier": "2"}))
+
+		e.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/users/123", nil))
+
+		assert.Equal(t, map[string]string{"team": "accounts", "tier": "2"}, actualLabels)
+	})
+
+	t.Run("returns_nil_when_there_are_no_labels", func(t *testing.T) {
+		e := echo.New()
+		c, _ := NewTestGetRequest(e, "/")
+
+		assert.Nil(t, RouteLabels(c))
+	})
+}
+
+func TestRequestLoggerRouteName(t *testing.T) {
+	t.Run("logs_the_route_name_and_labels", func(t *testing.T) {
+		var logBuf bytes.Buffer
+		slog.SetDefault(slog.New(slog.NewJSONHandler(&logBuf, nil)))
+		t.Cleanup(func() { slog.SetDefault(slog.Default()) })
+
+		e := echo.New()
+		e.Use(RequestLogger())
+		e.GET("/users/:id", func(c echo.Context) error {
+			return c.NoContent(http.StatusOK)
+		}, WithRouteName("get_user"), WithRouteLabels(map[string]string{"team": "accounts"}))
+
+		e.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/users/123", nil))
+
+		assert.Contains(t, logBuf.String(), `"route":"get_user"`)
+		assert.Contains(t, logBuf.String(), `"route_labels":{"team":"accounts"}`)
+	})
+}
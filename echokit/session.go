@@ -6,11 +6,13 @@ import (
 	"github.com/gorilla/context"
 	"github.com/gorilla/sessions"
 	"github.com/half-ogre/go-kit/kit"
+	"github.com/half-ogre/go-kit/sesskit"
 	"github.com/labstack/echo/v4"
 )
 
 const CONTEXT_KEY_SESSION_STORE = "fx-session-store"
 
+// DeleteSession deletes the session named name, as sesskit.Delete does
 func DeleteSession(name string, c echo.Context) error {
 	v := c.Get(CONTEXT_KEY_SESSION_STORE)
 
@@ -23,20 +25,7 @@ func DeleteSession(name string, c echo.Context) error {
 		return fmt.Errorf("failed to cast %+v to session store", v)
 	}
 
-	s, err := sessionStore.Get(c.Request(), name)
-	if err != nil {
-		return kit.WrapError(err, "error getting session")
-	}
-
-	s.Values = make(map[interface{}]interface{})
-	s.Options.MaxAge = -1
-
-	err = s.Save(c.Request(), c.Response().Writer)
-	if err != nil {
-		return kit.WrapError(err, "failed to delete session")
-	}
-
-	return nil
+	return sesskit.Delete(c.Request(), c.Response().Writer, sessionStore, name)
 }
 
 func GetSession(name string, c echo.Context) (*sessions.Session, error) {
@@ -59,6 +48,8 @@ func GetSession(name string, c echo.Context) (*sessions.Session, error) {
 	return s, nil
 }
 
+// NewSessionMiddleware makes sessionStore available to GetSession and DeleteSession. A sesskit.Store
+// shares its sessions with ginkit.Sessions.
 func NewSessionMiddleware(sessionStore sessions.Store) echo.MiddlewareFunc {
 	if sessionStore == nil {
 		panic("session store must not be nil")
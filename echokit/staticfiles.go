@@ -2,11 +2,8 @@ package echokit
 
 import (
 	"bytes"
-	"context"
 	"crypto/md5"
 	"fmt"
-	"io/fs"
-	"log/slog"
 	"mime"
 	"net/http"
 	"os"
@@ -14,10 +11,10 @@ import (
 	"regexp"
 	"strings"
 	"sync"
-	"time"
 
-	"github.com/fsnotify/fsnotify"
 	"github.com/labstack/echo/v4"
+
+	"github.com/half-ogre/go-kit/filekit"
 )
 
 var reloadScript = []byte(`<script>new EventSource('/api/dev/reload').onmessage = () => location.reload()</script></body>`)
@@ -48,11 +45,10 @@ type StaticFilesMiddleware struct {
 	files map[string]*staticEntry
 	spa   *staticEntry
 
-	watcher   *fsnotify.Watcher
+	watcher   *filekit.Watcher
 	notifyMu  sync.Mutex
 	notify    chan struct{}
 	version   int
-	cancelCtx context.CancelFunc
 	done      chan struct{} // closed on Close() to unblock SSE handlers
 	closeOnce sync.Once
 }
@@ -97,9 +93,6 @@ func NewStaticFilesMiddleware(root string, devMode bool, opts ...StaticFilesOpti
 func (m *StaticFilesMiddleware) Close() {
 	m.closeOnce.Do(func() {
 		close(m.done)
-		if m.cancelCtx != nil {
-			m.cancelCtx()
-		}
 		if m.watcher != nil {
 			m.watcher.Close()
 		}
@@ -371,7 +364,7 @@ func (m *StaticFilesMiddleware) build() error {
 					cycle = append(cycle, child)
 				}
 			}
-			slog.Warn("static file has circular dependency — fingerprinted imports may be incomplete",
+			logger.Warn("static file has circular dependency — fingerprinted imports may be incomplete",
 				"file", path, "unresolved_deps", cycle)
 		}
 	}
@@ -469,101 +462,20 @@ func (m *StaticFilesMiddleware) build() error {
 	return nil
 }
 
-// startWatcher creates an fsnotify watcher for live reload in dev mode.
+// startWatcher starts watching the root directory for live reload in dev mode.
 func (m *StaticFilesMiddleware) startWatcher() {
-	fsw, err := fsnotify.NewWatcher()
-	if err != nil {
-		slog.Error("Failed to create live reload watcher", "error", err)
-		return
-	}
-
-	var watchDirs func(dir string) error
-	watchDirs = func(dir string) error {
-		return filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
-			if err != nil {
-				return err
-			}
-			if d.IsDir() {
-				if d.Name() != "." && strings.HasPrefix(d.Name(), ".") {
-					return filepath.SkipDir
-				}
-				return fsw.Add(path)
-			}
-			if d.Type()&os.ModeSymlink != 0 {
-				resolved, err := filepath.EvalSymlinks(path)
-				if err != nil {
-					return nil
-				}
-				info, err := os.Stat(resolved)
-				if err != nil {
-					return nil
-				}
-				if info.IsDir() {
-					return watchDirs(resolved)
-				}
-			}
-			return nil
-		})
-	}
-	err = watchDirs(m.root)
+	w, err := filekit.NewWatcher(m.root, func([]string) {
+		m.broadcastReload()
+	}, filekit.WithWatchErrorHandler(func(err error) {
+		logger.Error("File watcher error", "error", err)
+	}))
 	if err != nil {
-		slog.Error("Failed to walk directory for live reload", "error", err)
-		fsw.Close()
+		logger.Error("Failed to create live reload watcher", "error", err)
 		return
 	}
 
-	m.watcher = fsw
-
-	ctx, cancel := context.WithCancel(context.Background())
-	m.cancelCtx = cancel
-
-	go m.runWatcher(ctx)
-	slog.Info("Live reload watcher started", "directory", m.root)
-}
-
-// runWatcher processes file system events and broadcasts reload signals.
-func (m *StaticFilesMiddleware) runWatcher(ctx context.Context) {
-	var debounce *time.Timer
-
-	for {
-		select {
-		case <-ctx.Done():
-			if debounce != nil {
-				debounce.Stop()
-			}
-			return
-
-		case event, ok := <-m.watcher.Events:
-			if !ok {
-				return
-			}
-
-			if event.Has(fsnotify.Create) {
-				filepath.WalkDir(event.Name, func(path string, d fs.DirEntry, err error) error {
-					if err != nil {
-						return nil
-					}
-					if d.IsDir() {
-						m.watcher.Add(path)
-					}
-					return nil
-				})
-			}
-
-			if debounce != nil {
-				debounce.Stop()
-			}
-			debounce = time.AfterFunc(100*time.Millisecond, func() {
-				m.broadcastReload()
-			})
-
-		case err, ok := <-m.watcher.Errors:
-			if !ok {
-				return
-			}
-			slog.Error("File watcher error", "error", err)
-		}
-	}
+	m.watcher = w
+	logger.Info("Live reload watcher started", "directory", m.root)
 }
 
 func (m *StaticFilesMiddleware) broadcastReload() {
@@ -577,7 +489,7 @@ func (m *StaticFilesMiddleware) broadcastReload() {
 	m.version++
 	close(m.notify)
 	m.notify = make(chan struct{})
-	slog.Debug("Live reload triggered", "version", m.version)
+	logger.Debug("Live reload triggered", "version", m.version)
 }
 
 func (m *StaticFilesMiddleware) waitForReload() <-chan struct{} {
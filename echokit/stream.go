@@ -0,0 +1,53 @@
+package echokit
+
+import (
+	"iter"
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/half-ogre/go-kit/internal/respond"
+)
+
+// MIMEApplicationNDJSON is the media type of newline-delimited JSON
+const MIMEApplicationNDJSON = respond.MIMEApplicationNDJSON
+
+// StreamOption configures StreamNDJSON and StreamCSV
+type StreamOption = respond.StreamOption
+
+// WithStreamFlushEvery flushes the response after every n records instead of after each one
+func WithStreamFlushEvery(n int) StreamOption {
+	return respond.WithFlushEvery(n)
+}
+
+// WithStreamWriteTimeout gives each flush d to reach the client, extending the server's WriteTimeout so
+// a long export isn't cut off while a client that stops reading still is
+func WithStreamWriteTimeout(d time.Duration) StreamOption {
+	return respond.WithWriteTimeout(d)
+}
+
+// StreamNDJSON writes the items from seq as newline-delimited JSON with status, flushing as it goes
+// so the client gets them before the whole result is read, e.g. from dynamodbkit.QueryItems.
+// Streaming stops when the client disconnects or the request's context is otherwise done. The status
+// is sent with the first item, so an error from seq after that truncates the response; it's returned
+// for the RequestLogger to log.
+func StreamNDJSON[T any](c echo.Context, status int, seq iter.Seq2[T, error], options ...StreamOption) error {
+	startStream(c, status, MIMEApplicationNDJSON)
+	return respond.Stream(c.Request().Context(), http.NewResponseController(c.Response().Writer), seq, respond.NDJSONWriter[T](c.Response()), options...)
+}
+
+// StreamCSV writes header and then the records from seq as CSV with status, flushing as it goes, as
+// StreamNDJSON does for JSON. A nil header writes no header row.
+func StreamCSV(c echo.Context, status int, header []string, seq iter.Seq2[[]string, error], options ...StreamOption) error {
+	startStream(c, status, respond.MIMETextCSV+"; charset=utf-8")
+	return respond.Stream(c.Request().Context(), http.NewResponseController(c.Response().Writer), respond.WithCSVHeader(header, seq), respond.CSVWriter(c.Response()), options...)
+}
+
+// startStream writes the status and headers; records are written through c.Response() so the
+// RequestLogger's bytes_out counts them
+func startStream(c echo.Context, status int, contentType string) {
+	c.Response().Header().Set(echo.HeaderContentType, contentType)
+	c.Response().Header().Set(echo.HeaderXContentTypeOptions, "nosniff")
+	c.Response().WriteHeader(status)
+}
@@ -0,0 +1,150 @@
+package echokit
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"iter"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+type flushCountingRecorder struct {
+	*httptest.ResponseRecorder
+	flushes int
+}
+
+func (r *flushCountingRecorder) Flush() {
+	r.flushes++
+	r.ResponseRecorder.Flush()
+}
+
+func seqOf[T any](items ...T) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		for _, item := range items {
+			if !yield(item, nil) {
+				return
+			}
+		}
+	}
+}
+
+func serveStream(t *testing.T, handler echo.HandlerFunc, middleware ...echo.MiddlewareFunc) *flushCountingRecorder {
+	t.Helper()
+	e := echo.New()
+	e.GET("/export", handler, middleware...)
+	rec := &flushCountingRecorder{ResponseRecorder: httptest.NewRecorder()}
+	e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/export", nil))
+	return rec
+}
+
+func TestStreamNDJSON(t *testing.T) {
+	t.Run("writes_each_item_as_a_json_line_and_flushes", func(t *testing.T) {
+		rec := serveStream(t, func(c echo.Context) error {
+			return StreamNDJSON(c, http.StatusOK, seqOf(testRespondUser{ID: "theID1"}, testRespondUser{ID: "theID2"}))
+		})
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, MIMEApplicationNDJSON, rec.Header().Get(echo.HeaderContentType))
+		assert.Equal(t, "{\"id\":\"theID1\",\"name\":\"\"}\n{\"id\":\"theID2\",\"name\":\"\"}\n", rec.Body.String())
+		assert.Equal(t, 4, rec.flushes)
+	})
+
+	t.Run("flushes_after_every_n_records", func(t *testing.T) {
+		rec := serveStream(t, func(c echo.Context) error {
+			return StreamNDJSON(c, http.StatusOK, seqOf(1, 2, 3, 4, 5), WithStreamFlushEvery(2))
+		})
+
+		assert.Equal(t, "1\n2\n3\n4\n5\n", rec.Body.String())
+		assert.Equal(t, 4, rec.flushes)
+	})
+
+	t.Run("stops_when_the_client_disconnects", func(t *testing.T) {
+		var actualErr error
+		e := echo.New()
+		ctx, cancel := context.WithCancel(context.Background())
+		t.Cleanup(cancel)
+		e.GET("/export", func(c echo.Context) error {
+			items := func(yield func(int, error) bool) {
+				for i := 1; ; i++ {
+					if i == 3 {
+						cancel()
+					}
+					if !yield(i, nil) {
+						return
+					}
+				}
+			}
+			actualErr = StreamNDJSON(c, http.StatusOK, items)
+			return actualErr
+		})
+		rec := httptest.NewRecorder()
+
+		e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/export", nil).WithContext(ctx))
+
+		assert.Equal(t, "1\n2\n", rec.Body.String())
+		assert.ErrorIs(t, actualErr, context.Canceled)
+		assert.EqualError(t, actualErr, "stopped streaming response after 2 records: context canceled")
+	})
+
+	t.Run("returns_the_error_from_the_sequence_after_the_items_before_it", func(t *testing.T) {
+		var actualErr error
+		rec := serveStream(t, func(c echo.Context) error {
+			items := func(yield func(int, error) bool) {
+				if !yield(1, nil) {
+					return
+				}
+				yield(0, errors.New("the sequence error"))
+			}
+			actualErr = StreamNDJSON(c, http.StatusOK, items)
+			return actualErr
+		})
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "1\n", rec.Body.String())
+		assert.EqualError(t, actualErr, "error streaming response after 1 records: the sequence error")
+	})
+
+	t.Run("reports_the_bytes_streamed_to_the_request_logger", func(t *testing.T) {
+		var logBuf bytes.Buffer
+		previous := slog.Default()
+		slog.SetDefault(slog.New(slog.NewJSONHandler(&logBuf, nil)))
+		t.Cleanup(func() { slog.SetDefault(previous) })
+
+		rec := serveStream(t, func(c echo.Context) error {
+			return StreamNDJSON(c, http.StatusOK, seqOf(slices.Repeat([]string{"aValue"}, 100)...))
+		}, RequestLogger())
+
+		var entry map[string]any
+		assert.NoError(t, json.Unmarshal(logBuf.Bytes(), &entry))
+		assert.Equal(t, float64(rec.Body.Len()), entry["bytes_out"])
+		assert.Equal(t, float64(http.StatusOK), entry["status"])
+	})
+}
+
+func TestStreamCSV(t *testing.T) {
+	t.Run("writes_the_header_and_records", func(t *testing.T) {
+		rec := serveStream(t, func(c echo.Context) error {
+			return StreamCSV(c, http.StatusOK, []string{"id", "name"}, seqOf([]string{"theID", "theName"}, []string{"anID", "a, name"}))
+		})
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "text/csv; charset=utf-8", rec.Header().Get(echo.HeaderContentType))
+		assert.Equal(t, "id,name\ntheID,theName\nanID,\"a, name\"\n", rec.Body.String())
+	})
+
+	t.Run("writes_no_header_row_for_a_nil_header", func(t *testing.T) {
+		rec := serveStream(t, func(c echo.Context) error {
+			return StreamCSV(c, http.StatusOK, nil, seqOf([]string{"theValue"}))
+		})
+
+		assert.Equal(t, "theValue\n", rec.Body.String())
+	})
+}
@@ -1,9 +1,11 @@
 package echokit
 
 import (
-	"time"
+	"reflect"
 
 	"github.com/go-playground/validator/v10"
+
+	"github.com/half-ogre/go-kit/validatekit"
 )
 
 // CustomValidator wraps the validator for Echo
@@ -11,21 +13,22 @@ type CustomValidator struct {
 	validator *validator.Validate
 }
 
-// Validate implements Echo's Validator interface
+// Validate implements Echo's Validator interface. A pointer to a struct is sanitized by its sanitize
+// tags with validatekit.Sanitize before it's validated.
 func (cv *CustomValidator) Validate(i interface{}) error {
+	if value := reflect.ValueOf(i); value.Kind() == reflect.Pointer && !value.IsNil() && value.Elem().Kind() == reflect.Struct {
+		if err := validatekit.Sanitize(i); err != nil {
+			return err
+		}
+	}
 	return cv.validator.Struct(i)
 }
 
-// NewValidator creates a new validator instance
+// NewValidator creates a new validator instance with the validatekit tags, e.g. isodate for YYYY-MM-DD
+// dates, slug, and ulid
 func NewValidator() *CustomValidator {
 	v := validator.New()
-
-	// Register custom isodate validator for YYYY-MM-DD format
-	v.RegisterValidation("isodate", func(fl validator.FieldLevel) bool {
-		dateStr := fl.Field().String()
-		_, err := time.Parse("2006-01-02", dateStr)
-		return err == nil
-	})
+	_ = validatekit.Register(v)
 
 	return &CustomValidator{
 		validator: v,
@@ -221,3 +221,29 @@ func TestCustomISODateValidator(t *testing.T) {
 		assert.Error(t, err)
 	})
 }
+
+func TestValidatekitTags(t *testing.T) {
+	validator := NewValidator()
+
+	t.Run("validates_slug_and_ulid", func(t *testing.T) {
+		type TestStruct struct {
+			ID   string `validate:"ulid"`
+			Slug string `validate:"slug"`
+		}
+
+		assert.NoError(t, validator.Validate(TestStruct{ID: "01ARZ3NDEKTSV4RRFFQ69G5FAV", Slug: "the-slug"}))
+		assert.Error(t, validator.Validate(TestStruct{ID: "01ARZ3NDEKTSV4RRFFQ69G5FAV", Slug: "The Slug"}))
+	})
+
+	t.Run("sanitizes_a_pointer_to_a_struct_before_validating", func(t *testing.T) {
+		type TestStruct struct {
+			Slug string `validate:"slug" sanitize:"trim,lower"`
+		}
+		data := TestStruct{Slug: " The-Slug "}
+
+		err := validator.Validate(&data)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "the-slug", data.Slug)
+	})
+}
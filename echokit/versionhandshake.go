@@ -0,0 +1,25 @@
+package echokit
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/half-ogre/go-kit/versionkit"
+)
+
+// VersionHandshake returns a middleware that advertises the service's build in the response headers
+// and rejects requests from peers that fail handshake's check with 412 Precondition Failed
+func VersionHandshake(handshake *versionkit.Handshake) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			handshake.SetHeaders(c.Response().Header())
+
+			if err := handshake.Check(c.Request().Header); err != nil {
+				return echo.NewHTTPError(http.StatusPreconditionFailed, err.Error())
+			}
+
+			return next(c)
+		}
+	}
+}
@@ -0,0 +1,46 @@
+package echokit
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/half-ogre/go-kit/versionkit"
+)
+
+func TestVersionHandshake(t *testing.T) {
+	handshake, _ := versionkit.NewHandshake(&versionkit.BuildInfo{Version: "v1.0.0", GitCommit: "theCommit"}, versionkit.WithMinimumPeerVersion("v2.0.0"))
+
+	t.Run("advertises_the_build_and_accepts_a_compatible_peer", func(t *testing.T) {
+		e := echo.New()
+		e.Use(VersionHandshake(handshake))
+		e.GET("/things", func(c echo.Context) error { return c.String(http.StatusOK, "theResponse") })
+		req := httptest.NewRequest(http.MethodGet, "/things", nil)
+		req.Header.Set(versionkit.HeaderServiceVersion, "v2.1.0")
+		rec := httptest.NewRecorder()
+
+		e.ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "v1.0.0", rec.Header().Get(versionkit.HeaderServiceVersion))
+		assert.Equal(t, "theCommit", rec.Header().Get(versionkit.HeaderServiceCommit))
+	})
+
+	t.Run("rejects_an_old_peer", func(t *testing.T) {
+		e := echo.New()
+		e.Use(VersionHandshake(handshake))
+		e.GET("/things", func(c echo.Context) error { return c.String(http.StatusOK, "theResponse") })
+		req := httptest.NewRequest(http.MethodGet, "/things", nil)
+		req.Header.Set(versionkit.HeaderServiceVersion, "v1.9.0")
+		rec := httptest.NewRecorder()
+
+		e.ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusPreconditionFailed, rec.Code)
+		assert.Contains(t, rec.Body.String(), "peer version v1.9.0 is older than the minimum v2.0.0")
+		assert.Equal(t, "v1.0.0", rec.Header().Get(versionkit.HeaderServiceVersion))
+	})
+}
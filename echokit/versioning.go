@@ -0,0 +1,144 @@
+package echokit
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/half-ogre/go-kit/internal/apiversion"
+)
+
+const apiVersionContextKey = "github.com/half-ogre/go-kit/echokit/api_version"
+
+// APIVersions registers versioned route groups. By default the version is the path segment after the
+// prefix, e.g. /v2/users; with WithAcceptHeaderVersioning it's taken from the Accept header instead.
+type APIVersions struct {
+	e          *echo.Echo
+	negotiator apiversion.Negotiator
+	byAccept   bool
+}
+
+type APIVersionsOption func(*APIVersions)
+
+// WithAcceptHeaderVersioning picks the version from a vendor media type in the Accept header, e.g.
+// "application/vnd.acme.v2+json" for vendor "acme", using defaultVersion when there isn't one. Requests
+// are routed to the version's group by inserting the version into their path, so paths that already
+// start with a version still work.
+func WithAcceptHeaderVersioning(vendor string, defaultVersion string) APIVersionsOption {
+	return func(a *APIVersions) {
+		a.negotiator.Vendor = vendor
+		a.negotiator.DefaultVersion = defaultVersion
+		a.byAccept = true
+	}
+}
+
+// WithAPIVersionsPrefix serves the versions under prefix, e.g. "/api" for /api/v2/users. With Accept
+// header versioning only requests under the prefix are versioned, leaving routes like /healthz alone.
+func WithAPIVersionsPrefix(prefix string) APIVersionsOption {
+	return func(a *APIVersions) {
+		a.negotiator.Prefix = strings.TrimSuffix(prefix, "/")
+	}
+}
+
+// NewAPIVersions returns an APIVersions that registers routes on e
+func NewAPIVersions(e *echo.Echo, options ...APIVersionsOption) *APIVersions {
+	a := &APIVersions{e: e, negotiator: apiversion.Negotiator{Versions: map[string]bool{}}}
+	for _, option := range options {
+		option(a)
+	}
+
+	if a.byAccept {
+		e.Pre(a.routeByAcceptHeader)
+	}
+
+	return a
+}
+
+type versionConfig struct {
+	deprecation apiversion.Deprecation
+	middlewares []echo.MiddlewareFunc
+}
+
+type VersionOption func(*versionConfig)
+
+// WithVersionDeprecated marks the version deprecated as of at, sending a Deprecation header (RFC 9745)
+// with every response
+func WithVersionDeprecated(at time.Time) VersionOption {
+	return func(config *versionConfig) {
+		config.deprecation.Deprecated = at
+	}
+}
+
+// WithVersionSunset sends a Sunset header (RFC 8594) with every response saying when the version will
+// stop working
+func WithVersionSunset(at time.Time) VersionOption {
+	return func(config *versionConfig) {
+		config.deprecation.Sunset = at
+	}
+}
+
+// WithVersionDeprecationLink sends a Link header pointing to docs on moving off the version
+func WithVersionDeprecationLink(link string) VersionOption {
+	return func(config *versionConfig) {
+		config.deprecation.Link = link
+	}
+}
+
+// WithVersionMiddleware adds middleware that runs only for the version's routes
+func WithVersionMiddleware(middlewares ...echo.MiddlewareFunc) VersionOption {
+	return func(config *versionConfig) {
+		config.middlewares = append(config.middlewares, middlewares...)
+	}
+}
+
+// Version returns the route group for version, e.g. "v2", whose routes are served under /v2 after the
+// prefix
+func (a *APIVersions) Version(version string, options ...VersionOption) *echo.Group {
+	config := &versionConfig{}
+	for _, option := range options {
+		option(config)
+	}
+
+	a.negotiator.Versions[version] = true
+
+	middlewares := []echo.MiddlewareFunc{versionMiddleware(version, config.deprecation, a.byAccept)}
+	return a.e.Group(a.negotiator.Prefix+"/"+version, append(middlewares, config.middlewares...)...)
+}
+
+// RequestAPIVersion returns the API version of the route handling the request, or "" if it isn't in a
+// versioned group
+func RequestAPIVersion(c echo.Context) string {
+	version, _ := c.Get(apiVersionContextKey).(string)
+	return version
+}
+
+func versionMiddleware(version string, deprecation apiversion.Deprecation, varyAccept bool) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			c.Set(apiVersionContextKey, version)
+
+			header := c.Response().Header()
+			if varyAccept {
+				header.Add(echo.HeaderVary, echo.HeaderAccept)
+			}
+			deprecation.SetHeaders(header)
+
+			return next(c)
+		}
+	}
+}
+
+// routeByAcceptHeader inserts the version from the Accept header into the request path after the prefix
+// so the router finds the version's group. Requests for a version that isn't registered get 406 Not
+// Acceptable.
+func (a *APIVersions) routeByAcceptHeader(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		req := c.Request()
+		if err := a.negotiator.Route(req.URL, req.Header.Values(echo.HeaderAccept)); err != nil {
+			return echo.NewHTTPError(http.StatusNotAcceptable, err.Error())
+		}
+		return next(c)
+	}
+}
@@ -0,0 +1,129 @@
+package echokit
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func serveVersioned(e *echo.Echo, path string, accept string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, path, nil)
+	if accept != "" {
+		req.Header.Set(echo.HeaderAccept, accept)
+	}
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	return rec
+}
+
+func versionHandler(c echo.Context) error {
+	return c.String(http.StatusOK, RequestAPIVersion(c))
+}
+
+func TestAPIVersions(t *testing.T) {
+	t.Run("routes_by_path_prefix", func(t *testing.T) {
+		e := echo.New()
+		versions := NewAPIVersions(e)
+		versions.Version("v1").GET("/users", versionHandler)
+		versions.Version("v2").GET("/users", versionHandler)
+
+		assert.Equal(t, "v1", serveVersioned(e, "/v1/users", "").Body.String())
+		assert.Equal(t, "v2", serveVersioned(e, "/v2/users", "").Body.String())
+		assert.Equal(t, http.StatusNotFound, serveVersioned(e, "/users", "").Code)
+	})
+
+	t.Run("routes_under_the_prefix", func(t *testing.T) {
+		e := echo.New()
+		NewAPIVersions(e, WithAPIVersionsPrefix("/api/")).Version("v1").GET("/users", versionHandler)
+
+		assert.Equal(t, "v1", serveVersioned(e, "/api/v1/users", "").Body.String())
+	})
+
+	t.Run("routes_by_accept_header", func(t *testing.T) {
+		e := echo.New()
+		versions := NewAPIVersions(e, WithAcceptHeaderVersioning("acme", "v1"))
+		versions.Version("v1").GET("/users", versionHandler)
+		versions.Version("v2").GET("/users", versionHandler)
+
+		rec := serveVersioned(e, "/users", "text/html, application/vnd.acme.v2+json; q=0.9")
+
+		assert.Equal(t, "v2", rec.Body.String())
+		assert.Equal(t, echo.HeaderAccept, rec.Header().Get(echo.HeaderVary))
+		assert.Equal(t, "v1", serveVersioned(e, "/users", "application/json").Body.String())
+		assert.Equal(t, "v1", serveVersioned(e, "/v1/users", "application/vnd.acme.v1+json").Body.String())
+	})
+
+	t.Run("returns_not_acceptable_for_an_unknown_version", func(t *testing.T) {
+		e := echo.New()
+		NewAPIVersions(e, WithAcceptHeaderVersioning("acme", "v1")).Version("v1").GET("/users", versionHandler)
+
+		rec := serveVersioned(e, "/users", "application/vnd.acme.v9+json")
+
+		assert.Equal(t, http.StatusNotAcceptable, rec.Code)
+		assert.Contains(t, rec.Body.String(), `unsupported API version \"v9\"`)
+	})
+
+	t.Run("leaves_requests_outside_the_prefix_alone", func(t *testing.T) {
+		e := echo.New()
+		NewAPIVersions(e, WithAcceptHeaderVersioning("acme", "v1"), WithAPIVersionsPrefix("/api")).Version("v1").GET("/users", versionHandler)
+		e.GET("/healthz", func(c echo.Context) error { return c.String(http.StatusOK, "theHealth") })
+		e.GET("/apis", func(c echo.Context) error { return c.String(http.StatusOK, "theAPIs") })
+
+		assert.Equal(t, "v1", serveVersioned(e, "/api/users", "").Body.String())
+		assert.Equal(t, "theHealth", serveVersioned(e, "/healthz", "").Body.String())
+		assert.Equal(t, "theAPIs", serveVersioned(e, "/apis", "").Body.String())
+	})
+
+	t.Run("sends_deprecation_headers", func(t *testing.T) {
+		e := echo.New()
+		NewAPIVersions(e).Version("v1",
+			WithVersionDeprecated(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)),
+			WithVersionSunset(time.Date(2024, 7, 1, 0, 0, 0, 0, time.UTC)),
+			WithVersionDeprecationLink("https://example.com/migrate"),
+		).GET("/users", versionHandler)
+
+		rec := serveVersioned(e, "/v1/users", "")
+
+		assert.Equal(t, "@1704067200", rec.Header().Get("Deprecation"))
+		assert.Equal(t, "Mon, 01 Jul 2024 00:00:00 GMT", rec.Header().Get("Sunset"))
+		assert.Equal(t, `<https://example.com/migrate>; rel="deprecation"`, rec.Header().Get("Link"))
+	})
+
+	t.Run("does_not_send_deprecation_headers_for_current_versions", func(t *testing.T) {
+		e := echo.New()
+		NewAPIVersions(e).Version("v2").GET("/users", versionHandler)
+
+		rec := serveVersioned(e, "/v2/users", "")
+
+		assert.Empty(t, rec.Header().Get("Deprecation"))
+		assert.Empty(t, rec.Header().Get("Sunset"))
+	})
+
+	t.Run("runs_version_middleware_only_for_its_version", func(t *testing.T) {
+		e := echo.New()
+		versions := NewAPIVersions(e)
+		versions.Version("v1", WithVersionMiddleware(func(next echo.HandlerFunc) echo.HandlerFunc {
+			return func(c echo.Context) error {
+				c.Response().Header().Set("X-Legacy", "true")
+				return next(c)
+			}
+		})).GET("/users", versionHandler)
+		versions.Version("v2").GET("/users", versionHandler)
+
+		assert.Equal(t, "true", serveVersioned(e, "/v1/users", "").Header().Get("X-Legacy"))
+		assert.Empty(t, serveVersioned(e, "/v2/users", "").Header().Get("X-Legacy"))
+	})
+}
+
+func TestRequestAPIVersion(t *testing.T) {
+	t.Run("returns_empty_outside_a_versioned_group", func(t *testing.T) {
+		e := echo.New()
+		c := e.NewContext(httptest.NewRequest(http.MethodGet, "/", nil), httptest.NewRecorder())
+
+		assert.Equal(t, "", RequestAPIVersion(c))
+	})
+}
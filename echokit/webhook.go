@@ -0,0 +1,118 @@
+package echokit
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/half-ogre/go-kit/internal/webhook"
+	"github.com/half-ogre/go-kit/kit"
+)
+
+// DefaultWebhookTolerance is how far a signed webhook timestamp may be from the current time before
+// the delivery is rejected as a possible replay.
+const DefaultWebhookTolerance = webhook.DefaultTolerance
+
+var (
+	// ErrWebhookSignatureMissing is returned by a WebhookVerifier when the request has no signature
+	ErrWebhookSignatureMissing = webhook.ErrSignatureMissing
+	// ErrWebhookSignatureInvalid is returned by a WebhookVerifier when no signature matches the body
+	ErrWebhookSignatureInvalid = webhook.ErrSignatureInvalid
+	// ErrWebhookTimestampOutOfTolerance is returned by a WebhookVerifier when the signed timestamp is too old or too far in the future
+	ErrWebhookTimestampOutOfTolerance = webhook.ErrTimestampOutOfTolerance
+)
+
+// WebhookSecretProvider returns the secret used to verify webhook signatures. It is called for every
+// delivery so that secrets can be rotated without restarting the service.
+type WebhookSecretProvider = webhook.SecretProvider
+
+// WebhookVerifier verifies the signature of a webhook delivery
+type WebhookVerifier = webhook.Verifier
+
+// WebhookVerifierOption configures a webhook verifier
+type WebhookVerifierOption = webhook.VerifierOption
+
+// WebhookHandlerOption configures a WebhookHandler
+type WebhookHandlerOption = webhook.HandlerOption
+
+// DefaultWebhookMaxBodySize is the largest webhook body read before the delivery is rejected
+const DefaultWebhookMaxBodySize = webhook.DefaultMaxBodySize
+
+// StaticWebhookSecret returns a WebhookSecretProvider that always returns secret
+func StaticWebhookSecret(secret string) WebhookSecretProvider {
+	return webhook.StaticSecret(secret)
+}
+
+// WebhookHandler returns a handler that reads the request body, verifies its signature with verifier
+// using the secret from secretProvider, and only then calls handler. The body is restored so handler
+// can read or bind it as usual. Deliveries that fail verification are rejected with 401 Unauthorized,
+// and bodies larger than the max body size with 413 Request Entity Too Large.
+func WebhookHandler(secretProvider WebhookSecretProvider, verifier WebhookVerifier, handler echo.HandlerFunc, options ...WebhookHandlerOption) echo.HandlerFunc {
+	config := webhook.NewHandlerConfig(options)
+	return func(c echo.Context) error {
+		req := c.Request()
+
+		err := webhook.ReadAndVerify(req, config.MaxBodySize, secretProvider, verifier)
+		var verificationErr *webhook.VerificationError
+		if errors.As(err, &verificationErr) {
+			logger.WarnContext(req.Context(), "webhook verification failed", "error", err.Error(), "uri", req.RequestURI)
+			return echo.NewHTTPError(http.StatusUnauthorized, "invalid webhook signature")
+		}
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			return echo.NewHTTPError(http.StatusRequestEntityTooLarge, "webhook body too large")
+		}
+		if err != nil {
+			return err
+		}
+
+		return handler(c)
+	}
+}
+
+// WithWebhookMaxBodySize sets the largest body, in bytes, read before the delivery is rejected with
+// 413 Request Entity Too Large. It defaults to DefaultWebhookMaxBodySize.
+func WithWebhookMaxBodySize(maxBodySize int64) WebhookHandlerOption {
+	return webhook.WithMaxBodySize(maxBodySize)
+}
+
+// WithWebhookClock sets the clock used to check signed timestamps
+func WithWebhookClock(clock kit.ClockInterface) WebhookVerifierOption {
+	return webhook.WithClock(clock)
+}
+
+// WithWebhookTolerance sets how far a signed timestamp may be from the current time
+func WithWebhookTolerance(tolerance time.Duration) WebhookVerifierOption {
+	return webhook.WithTolerance(tolerance)
+}
+
+// WithWebhookTimestampHeader sets the header holding the Unix timestamp that is signed along with the body.
+// Only used by the generic HMAC verifier.
+func WithWebhookTimestampHeader(header string) WebhookVerifierOption {
+	return webhook.WithTimestampHeader(header)
+}
+
+// WithWebhookSignaturePrefix sets a prefix (e.g. "sha256=") that is stripped from the signature header.
+// Only used by the generic HMAC verifier.
+func WithWebhookSignaturePrefix(prefix string) WebhookVerifierOption {
+	return webhook.WithSignaturePrefix(prefix)
+}
+
+// NewGitHubWebhookVerifier returns a verifier for GitHub's X-Hub-Signature-256 header
+func NewGitHubWebhookVerifier() WebhookVerifier {
+	return webhook.NewGitHubVerifier()
+}
+
+// NewStripeWebhookVerifier returns a verifier for Stripe's Stripe-Signature header, rejecting
+// deliveries whose signed timestamp is outside the tolerance
+func NewStripeWebhookVerifier(options ...WebhookVerifierOption) WebhookVerifier {
+	return webhook.NewStripeVerifier(options...)
+}
+
+// NewHMACWebhookVerifier returns a verifier for providers that put a hex-encoded HMAC-SHA256 of the body
+// in signatureHeader, optionally signed together with a timestamp header
+func NewHMACWebhookVerifier(signatureHeader string, options ...WebhookVerifierOption) WebhookVerifier {
+	return webhook.NewHMACVerifier(signatureHeader, options...)
+}
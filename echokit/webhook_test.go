@@ -0,0 +1,107 @@
+package echokit
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func signWebhookTestPayload(secret string, payload string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(payload))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func newWebhookTestRequest(body string, headers map[string]string) *http.Request {
+	req := httptest.NewRequest(http.MethodPost, "/webhook", strings.NewReader(body))
+	for key, value := range headers {
+		req.Header.Set(key, value)
+	}
+	return req
+}
+
+func TestWebhookHandler(t *testing.T) {
+	t.Run("calls_the_handler_with_the_body_when_the_signature_is_valid", func(t *testing.T) {
+		e := echo.New()
+		req := newWebhookTestRequest(`{"action":"opened"}`, map[string]string{
+			"X-Hub-Signature-256": "sha256=" + signWebhookTestPayload("theSecret", `{"action":"opened"}`),
+		})
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		var actualBody string
+		handler := WebhookHandler(StaticWebhookSecret("theSecret"), NewGitHubWebhookVerifier(), func(c echo.Context) error {
+			body, _ := io.ReadAll(c.Request().Body)
+			actualBody = string(body)
+			return c.NoContent(http.StatusNoContent)
+		})
+
+		err := handler(c)
+
+		assert.NoError(t, err)
+		assert.Equal(t, `{"action":"opened"}`, actualBody)
+		assert.Equal(t, http.StatusNoContent, rec.Code)
+	})
+
+	t.Run("returns_unauthorized_when_the_signature_is_invalid", func(t *testing.T) {
+		e := echo.New()
+		req := newWebhookTestRequest(`{"action":"opened"}`, map[string]string{
+			"X-Hub-Signature-256": "sha256=" + signWebhookTestPayload("aDifferentSecret", `{"action":"opened"}`),
+		})
+		c := e.NewContext(req, httptest.NewRecorder())
+		handlerCalled := false
+		handler := WebhookHandler(StaticWebhookSecret("theSecret"), NewGitHubWebhookVerifier(), func(c echo.Context) error {
+			handlerCalled = true
+			return nil
+		})
+
+		err := handler(c)
+
+		var httpErr *echo.HTTPError
+		assert.True(t, errors.As(err, &httpErr))
+		assert.Equal(t, http.StatusUnauthorized, httpErr.Code)
+		assert.False(t, handlerCalled)
+	})
+
+	t.Run("returns_request_entity_too_large_when_the_body_is_too_large", func(t *testing.T) {
+		e := echo.New()
+		req := newWebhookTestRequest("aBodyThatIsTooLarge", map[string]string{
+			"X-Hub-Signature-256": "sha256=" + signWebhookTestPayload("theSecret", "aBodyThatIsTooLarge"),
+		})
+		c := e.NewContext(req, httptest.NewRecorder())
+		handlerCalled := false
+		handler := WebhookHandler(StaticWebhookSecret("theSecret"), NewGitHubWebhookVerifier(), func(c echo.Context) error {
+			handlerCalled = true
+			return nil
+		}, WithWebhookMaxBodySize(5))
+
+		err := handler(c)
+
+		var httpErr *echo.HTTPError
+		assert.True(t, errors.As(err, &httpErr))
+		assert.Equal(t, http.StatusRequestEntityTooLarge, httpErr.Code)
+		assert.False(t, handlerCalled)
+	})
+
+	t.Run("returns_an_error_when_the_secret_provider_fails", func(t *testing.T) {
+		e := echo.New()
+		c := e.NewContext(newWebhookTestRequest("aBody", nil), httptest.NewRecorder())
+		secretProvider := func(ctx context.Context) ([]byte, error) {
+			return nil, errors.New("the secret error")
+		}
+		handler := WebhookHandler(secretProvider, NewGitHubWebhookVerifier(), func(c echo.Context) error { return nil })
+
+		err := handler(c)
+
+		assert.EqualError(t, err, "failed to get webhook secret: the secret error")
+	})
+}
@@ -11,13 +11,20 @@ import (
 	"unicode"
 )
 
+// LoadEnv sets variables from .env files, or from directories read with ReadEnvDir, that aren't already
+// set. Variables already in the environment win, then earlier paths win over later ones.
 func LoadEnv(paths ...string) error {
 	if paths == nil {
 		paths = []string{"./.env"}
 	}
 
 	for _, path := range paths {
-		err := loadEnvFile(path)
+		info, err := os.Stat(path)
+		if err == nil && info.IsDir() {
+			err = loadEnvDir(path)
+		} else {
+			err = loadEnvFile(path)
+		}
 		if err != nil {
 			return err
 		}
@@ -32,14 +39,17 @@ func loadEnvFile(path string) error {
 		return err
 	}
 
-	for key, value := range envFromFile {
+	setUnsetEnv(envFromFile)
+	return nil
+}
+
+func setUnsetEnv(env map[string]string) {
+	for key, value := range env {
 		_, exists := os.LookupEnv(key)
 		if !exists {
 			_ = os.Setenv(key, value)
 		}
 	}
-
-	return nil
 }
 
 func ReadEnvFile(path string) (map[string]string, error) {
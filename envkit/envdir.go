@@ -0,0 +1,57 @@
+package envkit
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/half-ogre/go-kit/kit"
+)
+
+// ReadEnvDir reads a directory where each file is a variable, named by the file name and set to the
+// file's contents, as with Kubernetes secret, config map, and downward API volume mounts. Hidden
+// entries, such as the ..data links Kubernetes uses for atomic updates, and subdirectories are
+// skipped. A single trailing newline is trimmed from each value.
+func ReadEnvDir(path string) (map[string]string, error) {
+	entries, err := os.ReadDir(path)
+	if err != nil {
+		return nil, err
+	}
+
+	env := make(map[string]string, len(entries))
+	for _, entry := range entries {
+		name := entry.Name()
+		if strings.HasPrefix(name, ".") {
+			continue
+		}
+
+		filePath := filepath.Join(path, name)
+		// Stat rather than entry.Info so the symlinks Kubernetes mounts are followed
+		info, err := os.Stat(filePath)
+		if err != nil {
+			return nil, kit.WrapError(err, "failed to stat %s", filePath)
+		}
+		if info.IsDir() {
+			continue
+		}
+
+		value, err := os.ReadFile(filePath)
+		if err != nil {
+			return nil, kit.WrapError(err, "failed to read %s", filePath)
+		}
+
+		env[name] = strings.TrimSuffix(strings.TrimSuffix(string(value), "\n"), "\r")
+	}
+
+	return env, nil
+}
+
+func loadEnvDir(path string) error {
+	envFromDir, err := ReadEnvDir(path)
+	if err != nil {
+		return err
+	}
+
+	setUnsetEnv(envFromDir)
+	return nil
+}
@@ -0,0 +1,99 @@
+package envkit
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func writeEnvDirFile(t *testing.T, dir string, name string, content string) {
+	err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o600)
+	assert.NoError(t, err)
+}
+
+func TestReadEnvDir(t *testing.T) {
+	t.Run("reads_each_file_as_a_variable", func(t *testing.T) {
+		dir := t.TempDir()
+		writeEnvDirFile(t, dir, "DB_PASSWORD", "thePassword\n")
+		writeEnvDirFile(t, dir, "labels", "app=\"theApp\"\ntier=\"theTier\"")
+
+		result, err := ReadEnvDir(dir)
+
+		assert.NoError(t, err)
+		assert.Equal(t, map[string]string{
+			"DB_PASSWORD": "thePassword",
+			"labels":      "app=\"theApp\"\ntier=\"theTier\"",
+		}, result)
+	})
+
+	t.Run("follows_kubernetes_symlinks_and_skips_hidden_entries", func(t *testing.T) {
+		dir := t.TempDir()
+		dataDir := filepath.Join(dir, "..2025_01_02_03_04_05.000000001")
+		assert.NoError(t, os.Mkdir(dataDir, 0o700))
+		writeEnvDirFile(t, dataDir, "API_KEY", "theKey")
+		assert.NoError(t, os.Symlink(filepath.Base(dataDir), filepath.Join(dir, "..data")))
+		assert.NoError(t, os.Symlink(filepath.Join("..data", "API_KEY"), filepath.Join(dir, "API_KEY")))
+
+		result, err := ReadEnvDir(dir)
+
+		assert.NoError(t, err)
+		assert.Equal(t, map[string]string{"API_KEY": "theKey"}, result)
+	})
+
+	t.Run("skips_subdirectories", func(t *testing.T) {
+		dir := t.TempDir()
+		assert.NoError(t, os.Mkdir(filepath.Join(dir, "nested"), 0o700))
+
+		result, err := ReadEnvDir(dir)
+
+		assert.NoError(t, err)
+		assert.Empty(t, result)
+	})
+
+	t.Run("returns_an_error_when_the_directory_does_not_exist", func(t *testing.T) {
+		_, err := ReadEnvDir("/non/existent/dir")
+
+		assert.Error(t, err)
+	})
+}
+
+func TestLoadEnvWithDirectory(t *testing.T) {
+	t.Run("sets_variables_from_the_directory", func(t *testing.T) {
+		dir := t.TempDir()
+		writeEnvDirFile(t, dir, "TEST_ENVDIR_KEY", "theDirValue\n")
+		os.Unsetenv("TEST_ENVDIR_KEY")
+		t.Cleanup(func() { os.Unsetenv("TEST_ENVDIR_KEY") })
+
+		err := LoadEnv(dir)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "theDirValue", os.Getenv("TEST_ENVDIR_KEY"))
+	})
+
+	t.Run("gives_earlier_paths_precedence", func(t *testing.T) {
+		dir := t.TempDir()
+		writeEnvDirFile(t, dir, "TEST_ENVDIR_KEY", "theDirValue")
+		envFile := filepath.Join(t.TempDir(), ".env")
+		assert.NoError(t, os.WriteFile(envFile, []byte("TEST_ENVDIR_KEY=theFileValue"), 0o600))
+		os.Unsetenv("TEST_ENVDIR_KEY")
+		t.Cleanup(func() { os.Unsetenv("TEST_ENVDIR_KEY") })
+
+		err := LoadEnv(dir, envFile)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "theDirValue", os.Getenv("TEST_ENVDIR_KEY"))
+	})
+
+	t.Run("does_not_overwrite_existing_variables", func(t *testing.T) {
+		dir := t.TempDir()
+		writeEnvDirFile(t, dir, "TEST_ENVDIR_KEY", "theDirValue")
+		t.Setenv("TEST_ENVDIR_KEY", "theExistingValue")
+
+		err := LoadEnv(dir)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "theExistingValue", os.Getenv("TEST_ENVDIR_KEY"))
+	})
+}
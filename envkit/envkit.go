@@ -1,11 +1,16 @@
 package envkit
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
 	"strconv"
 
+	"gopkg.in/yaml.v3"
+
 	"github.com/half-ogre/go-kit/kit"
+	"github.com/half-ogre/go-kit/validatekit"
 )
 
 func GetenvBoolWithDefault(key string, defaultValue bool) (bool, error) {
@@ -57,3 +62,67 @@ func MustGetenv(key string) string {
 
 	return value
 }
+
+// GetenvValidated returns the environment value of key, or defaultValue when it's unset, after
+// applying rules to it, e.g. GetenvValidated("API_URL", "", validatekit.Trim, validatekit.Required,
+// validatekit.URL), so bad config fails at startup instead of on first use
+func GetenvValidated(key string, defaultValue string, rules ...validatekit.Rule) (string, error) {
+	value, err := validatekit.Apply(GetenvWithDefault(key, defaultValue), rules...)
+	if err != nil {
+		return "", kit.WrapError(err, "invalid %s", key)
+	}
+
+	return value, nil
+}
+
+// GetenvJSONWithDefault decodes a JSON environment value (e.g. FEATURE_FLAGS={"a":true}) into T,
+// which may be a struct, slice, or map such as map[string]string
+func GetenvJSONWithDefault[T any](key string, defaultValue T) (T, error) {
+	value := os.Getenv(key)
+
+	if value == "" {
+		return defaultValue, nil
+	}
+
+	var result T
+	if err := json.Unmarshal([]byte(value), &result); err != nil {
+		var zero T
+		var syntaxErr *json.SyntaxError
+		var typeErr *json.UnmarshalTypeError
+		switch {
+		case errors.As(err, &syntaxErr):
+			return zero, kit.WrapError(err, "failed to parse %s as JSON: malformed at offset %d", key, syntaxErr.Offset)
+		case errors.As(err, &typeErr):
+			return zero, kit.WrapError(err, "failed to parse %s as JSON: %s has the wrong type for %T", key, describeJSONField(typeErr.Field), result)
+		default:
+			return zero, kit.WrapError(err, "failed to parse %s as JSON", key)
+		}
+	}
+
+	return result, nil
+}
+
+// GetenvYAMLWithDefault decodes a YAML environment value into T, which may be a struct, slice, or map.
+// Since JSON is valid YAML, this also accepts JSON values.
+func GetenvYAMLWithDefault[T any](key string, defaultValue T) (T, error) {
+	value := os.Getenv(key)
+
+	if value == "" {
+		return defaultValue, nil
+	}
+
+	var result T
+	if err := yaml.Unmarshal([]byte(value), &result); err != nil {
+		var zero T
+		return zero, kit.WrapError(err, "failed to parse %s as YAML", key)
+	}
+
+	return result, nil
+}
+
+func describeJSONField(field string) string {
+	if field == "" {
+		return "the value"
+	}
+	return fmt.Sprintf("field %q", field)
+}
@@ -5,6 +5,8 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+
+	"github.com/half-ogre/go-kit/validatekit"
 )
 
 func TestGetenvBoolWithDefault(t *testing.T) {
@@ -215,6 +217,49 @@ func TestGetenvWithDefault(t *testing.T) {
 	})
 }
 
+func TestGetenvValidated(t *testing.T) {
+	key := "TEST_VALIDATED_ENV_VAR"
+
+	t.Run("returns_the_sanitized_value", func(t *testing.T) {
+		os.Setenv(key, "  https://api.example.com  ")
+		t.Cleanup(func() { os.Unsetenv(key) })
+
+		result, err := GetenvValidated(key, "", validatekit.Trim, validatekit.URL)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "https://api.example.com", result)
+	})
+
+	t.Run("validates_the_default_when_not_set", func(t *testing.T) {
+		os.Unsetenv(key)
+
+		result, err := GetenvValidated(key, "the-default-slug", validatekit.Slug)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "the-default-slug", result)
+	})
+
+	t.Run("returns_an_error_for_an_invalid_value", func(t *testing.T) {
+		os.Setenv(key, "not a url")
+		t.Cleanup(func() { os.Unsetenv(key) })
+
+		result, err := GetenvValidated(key, "", validatekit.URL)
+
+		assert.Empty(t, result)
+		assert.EqualError(t, err, "invalid TEST_VALIDATED_ENV_VAR: must be an http or https URL")
+	})
+
+	t.Run("returns_an_error_when_required_and_not_set", func(t *testing.T) {
+		os.Unsetenv(key)
+
+		_, err := GetenvValidated(key, "", validatekit.Required)
+
+		var validationErr *validatekit.Error
+		assert.ErrorAs(t, err, &validationErr)
+		assert.Equal(t, "required", validationErr.Rule)
+	})
+}
+
 func TestMustGetenv(t *testing.T) {
 	t.Run("environment_variable_set_returns_value", func(t *testing.T) {
 		key := "TEST_MUST_ENV_VAR"
@@ -247,3 +292,116 @@ func TestMustGetenv(t *testing.T) {
 		})
 	})
 }
+
+type testFeatureConfig struct {
+	Name  string          `json:"name" yaml:"name"`
+	Flags map[string]bool `json:"flags" yaml:"flags"`
+}
+
+func TestGetenvJSONWithDefault(t *testing.T) {
+	key := "TEST_JSON_ENV_VAR"
+
+	t.Run("environment_variable_not_set_returns_default", func(t *testing.T) {
+		theDefaultValue := map[string]string{"theKey": "theDefault"}
+		os.Unsetenv(key)
+		t.Cleanup(func() { os.Unsetenv(key) })
+
+		result, err := GetenvJSONWithDefault(key, theDefaultValue)
+
+		assert.NoError(t, err)
+		assert.Equal(t, theDefaultValue, result)
+	})
+
+	t.Run("decodes_an_object_into_a_map", func(t *testing.T) {
+		os.Setenv(key, `{"theKey":"theValue"}`)
+		t.Cleanup(func() { os.Unsetenv(key) })
+
+		result, err := GetenvJSONWithDefault[map[string]string](key, nil)
+
+		assert.NoError(t, err)
+		assert.Equal(t, map[string]string{"theKey": "theValue"}, result)
+	})
+
+	t.Run("decodes_an_object_into_a_struct", func(t *testing.T) {
+		os.Setenv(key, `{"name":"theName","flags":{"a":true}}`)
+		t.Cleanup(func() { os.Unsetenv(key) })
+
+		result, err := GetenvJSONWithDefault(key, testFeatureConfig{})
+
+		assert.NoError(t, err)
+		assert.Equal(t, testFeatureConfig{Name: "theName", Flags: map[string]bool{"a": true}}, result)
+	})
+
+	t.Run("decodes_an_array_into_a_slice", func(t *testing.T) {
+		os.Setenv(key, `["a","b"]`)
+		t.Cleanup(func() { os.Unsetenv(key) })
+
+		result, err := GetenvJSONWithDefault[[]string](key, nil)
+
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"a", "b"}, result)
+	})
+
+	t.Run("malformed_json_returns_an_error_with_the_offset", func(t *testing.T) {
+		os.Setenv(key, `{"a":tru}`)
+		t.Cleanup(func() { os.Unsetenv(key) })
+
+		result, err := GetenvJSONWithDefault[map[string]bool](key, map[string]bool{"b": true})
+
+		assert.Nil(t, result)
+		assert.ErrorContains(t, err, "failed to parse TEST_JSON_ENV_VAR as JSON: malformed at offset 9")
+	})
+
+	t.Run("mistyped_json_returns_an_error_naming_the_field", func(t *testing.T) {
+		os.Setenv(key, `{"name":42}`)
+		t.Cleanup(func() { os.Unsetenv(key) })
+
+		_, err := GetenvJSONWithDefault(key, testFeatureConfig{})
+
+		assert.ErrorContains(t, err, `failed to parse TEST_JSON_ENV_VAR as JSON: field "name" has the wrong type for envkit.testFeatureConfig`)
+	})
+}
+
+func TestGetenvYAMLWithDefault(t *testing.T) {
+	key := "TEST_YAML_ENV_VAR"
+
+	t.Run("environment_variable_not_set_returns_default", func(t *testing.T) {
+		theDefaultValue := testFeatureConfig{Name: "theDefault"}
+		os.Unsetenv(key)
+		t.Cleanup(func() { os.Unsetenv(key) })
+
+		result, err := GetenvYAMLWithDefault(key, theDefaultValue)
+
+		assert.NoError(t, err)
+		assert.Equal(t, theDefaultValue, result)
+	})
+
+	t.Run("decodes_yaml_into_a_struct", func(t *testing.T) {
+		os.Setenv(key, "name: theName\nflags:\n  a: true\n")
+		t.Cleanup(func() { os.Unsetenv(key) })
+
+		result, err := GetenvYAMLWithDefault(key, testFeatureConfig{})
+
+		assert.NoError(t, err)
+		assert.Equal(t, testFeatureConfig{Name: "theName", Flags: map[string]bool{"a": true}}, result)
+	})
+
+	t.Run("decodes_json_as_yaml", func(t *testing.T) {
+		os.Setenv(key, `{"theKey": "theValue"}`)
+		t.Cleanup(func() { os.Unsetenv(key) })
+
+		result, err := GetenvYAMLWithDefault[map[string]string](key, nil)
+
+		assert.NoError(t, err)
+		assert.Equal(t, map[string]string{"theKey": "theValue"}, result)
+	})
+
+	t.Run("malformed_yaml_returns_an_error", func(t *testing.T) {
+		os.Setenv(key, "name: [unclosed")
+		t.Cleanup(func() { os.Unsetenv(key) })
+
+		_, err := GetenvYAMLWithDefault(key, testFeatureConfig{})
+
+		assert.ErrorContains(t, err, "failed to parse TEST_YAML_ENV_VAR as YAML")
+	})
+}
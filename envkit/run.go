@@ -0,0 +1,65 @@
+package envkit
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"sort"
+	"strings"
+
+	"github.com/half-ogre/go-kit/kit"
+)
+
+// RunWithEnv loads the .env file at path and runs argv as a child process with the merged environment,
+// connected to the current stdin, stdout, and stderr. As with LoadEnv, variables already set in the
+// environment take precedence over the file. If the child exits non-zero the returned error is an
+// *exec.ExitError carrying its exit code.
+func RunWithEnv(path string, argv []string) error {
+	return RunWithEnvContext(context.Background(), path, argv)
+}
+
+// RunWithEnvContext is RunWithEnv with a context that kills the child process when done
+func RunWithEnvContext(ctx context.Context, path string, argv []string) error {
+	if len(argv) == 0 {
+		return fmt.Errorf("no command to run")
+	}
+
+	envFromFile, err := ReadEnvFile(path)
+	if err != nil {
+		return kit.WrapError(err, "failed to read env file %s", path)
+	}
+
+	cmd := exec.CommandContext(ctx, argv[0], argv[1:]...)
+	cmd.Env = mergeEnv(os.Environ(), envFromFile)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+
+	return cmd.Run()
+}
+
+// mergeEnv appends the variables from envFromFile that are not already set in environ
+func mergeEnv(environ []string, envFromFile map[string]string) []string {
+	existing := make(map[string]bool, len(environ))
+	for _, entry := range environ {
+		if key, _, found := strings.Cut(entry, "="); found {
+			existing[key] = true
+		}
+	}
+
+	keys := make([]string, 0, len(envFromFile))
+	for key := range envFromFile {
+		if !existing[key] {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	merged := append([]string{}, environ...)
+	for _, key := range keys {
+		merged = append(merged, key+"="+envFromFile[key])
+	}
+
+	return merged
+}
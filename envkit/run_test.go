@@ -0,0 +1,69 @@
+package envkit
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRunWithEnv(t *testing.T) {
+	t.Run("runs_the_command_with_variables_from_the_file", func(t *testing.T) {
+		dir := t.TempDir()
+		envPath := filepath.Join(dir, ".env")
+		outPath := filepath.Join(dir, "out")
+		assert.NoError(t, os.WriteFile(envPath, []byte("THE_RUN_WITH_ENV_KEY=theValue\n"), 0o600))
+
+		err := RunWithEnv(envPath, []string{"sh", "-c", `printf %s "$THE_RUN_WITH_ENV_KEY" > "$0"`, outPath})
+
+		assert.NoError(t, err)
+		out, _ := os.ReadFile(outPath)
+		assert.Equal(t, "theValue", string(out))
+	})
+
+	t.Run("returns_the_exit_error_when_the_command_fails", func(t *testing.T) {
+		envPath := filepath.Join(t.TempDir(), ".env")
+		assert.NoError(t, os.WriteFile(envPath, []byte(""), 0o600))
+
+		err := RunWithEnv(envPath, []string{"sh", "-c", "exit 3"})
+
+		var exitErr *exec.ExitError
+		assert.True(t, errors.As(err, &exitErr))
+		assert.Equal(t, 3, exitErr.ExitCode())
+	})
+
+	t.Run("returns_an_error_when_the_file_does_not_exist", func(t *testing.T) {
+		err := RunWithEnv(filepath.Join(t.TempDir(), "missing.env"), []string{"true"})
+
+		assert.ErrorContains(t, err, "failed to read env file")
+	})
+
+	t.Run("returns_an_error_when_there_is_no_command", func(t *testing.T) {
+		err := RunWithEnv(".env", nil)
+
+		assert.EqualError(t, err, "no command to run")
+	})
+}
+
+func TestMergeEnv(t *testing.T) {
+	t.Run("appends_file_variables_in_key_order", func(t *testing.T) {
+		result := mergeEnv([]string{"A=1"}, map[string]string{"C": "3", "B": "2"})
+
+		assert.Equal(t, []string{"A=1", "B=2", "C=3"}, result)
+	})
+
+	t.Run("keeps_existing_variables_over_file_variables", func(t *testing.T) {
+		result := mergeEnv([]string{"A=theExistingValue"}, map[string]string{"A": "theFileValue"})
+
+		assert.Equal(t, []string{"A=theExistingValue"}, result)
+	})
+
+	t.Run("treats_variables_set_to_empty_as_existing", func(t *testing.T) {
+		result := mergeEnv([]string{"A="}, map[string]string{"A": "theFileValue"})
+
+		assert.Equal(t, []string{"A="}, result)
+	})
+}
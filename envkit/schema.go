@@ -0,0 +1,162 @@
+package envkit
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// EnvVar documents one environment variable of a config struct
+type EnvVar struct {
+	Name        string `json:"name"`
+	Type        string `json:"type"`
+	Default     string `json:"default,omitempty"`
+	Required    bool   `json:"required"`
+	Description string `json:"description,omitempty"`
+}
+
+// DescribeEnv returns the environment variables of config, a struct or pointer to one, from its field
+// tags, e.g.
+//
+//	type Config struct {
+//		Port     int           `env:"PORT" envDefault:"8080" description:"Port to listen on"`
+//		Timeout  time.Duration `env:"TIMEOUT" envDefault:"30s"`
+//		Database struct {
+//			URL string `env:"URL,required" description:"Postgres connection string"`
+//		} `envPrefix:"DATABASE_"`
+//	}
+//
+// Fields without an env tag are skipped, except structs, whose fields are described with the
+// envPrefix tag prepended to their names. Call it from a go:generate program with WriteEnvMarkdown or
+// WriteEnvJSON to keep a service's documented environment in step with its config.
+func DescribeEnv(config any) ([]EnvVar, error) {
+	t := reflect.TypeOf(config)
+	for t != nil && t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t == nil || t.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("config must be a struct or pointer to a struct, got %T", config)
+	}
+
+	vars := []EnvVar{}
+	seen := map[string]string{}
+	if err := describeEnvFields(t, "", "", &vars, seen); err != nil {
+		return nil, err
+	}
+	return vars, nil
+}
+
+var (
+	durationType = reflect.TypeOf(time.Duration(0))
+	timeType     = reflect.TypeOf(time.Time{})
+)
+
+func describeEnvFields(t reflect.Type, prefix string, path string, vars *[]EnvVar, seen map[string]string) error {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		fieldPath := path + field.Name
+
+		tag, tagged := field.Tag.Lookup("env")
+		if !tagged || tag == "-" {
+			fieldType := field.Type
+			for fieldType.Kind() == reflect.Pointer {
+				fieldType = fieldType.Elem()
+			}
+			if !tagged && fieldType.Kind() == reflect.Struct && fieldType != timeType {
+				if err := describeEnvFields(fieldType, prefix+field.Tag.Get("envPrefix"), fieldPath+".", vars, seen); err != nil {
+					return err
+				}
+			}
+			continue
+		}
+
+		name, options, _ := strings.Cut(tag, ",")
+		if name == "" {
+			return fmt.Errorf("field %s has an empty env name", fieldPath)
+		}
+		name = prefix + name
+		if other, ok := seen[name]; ok {
+			return fmt.Errorf("fields %s and %s both use environment variable %s", other, fieldPath, name)
+		}
+		seen[name] = fieldPath
+
+		required := false
+		for _, option := range strings.Split(options, ",") {
+			switch option {
+			case "":
+			case "required":
+				required = true
+			default:
+				return fmt.Errorf("field %s has unknown env tag option %q", fieldPath, option)
+			}
+		}
+
+		*vars = append(*vars, EnvVar{
+			Name:        name,
+			Type:        describeEnvType(field.Type),
+			Default:     field.Tag.Get("envDefault"),
+			Required:    required,
+			Description: field.Tag.Get("description"),
+		})
+	}
+	return nil
+}
+
+func describeEnvType(t reflect.Type) string {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	switch t {
+	case durationType:
+		return "duration"
+	case timeType:
+		return "time"
+	}
+	switch t.Kind() {
+	case reflect.Slice:
+		return "list of " + describeEnvType(t.Elem())
+	case reflect.Map, reflect.Struct:
+		return "JSON"
+	default:
+		return t.Kind().String()
+	}
+}
+
+// WriteEnvMarkdown writes vars as a Markdown table
+func WriteEnvMarkdown(w io.Writer, vars []EnvVar) error {
+	var b strings.Builder
+	b.WriteString("| Name | Type | Default | Required | Description |\n")
+	b.WriteString("| --- | --- | --- | --- | --- |\n")
+	for _, v := range vars {
+		defaultValue := ""
+		if v.Default != "" {
+			defaultValue = "`" + escapeMarkdownCell(v.Default) + "`"
+		}
+		required := "no"
+		if v.Required {
+			required = "yes"
+		}
+		fmt.Fprintf(&b, "| `%s` | %s | %s | %s | %s |\n", v.Name, v.Type, defaultValue, required, escapeMarkdownCell(v.Description))
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// WriteEnvJSON writes vars as an indented JSON array
+func WriteEnvJSON(w io.Writer, vars []EnvVar) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(vars)
+}
+
+func escapeMarkdownCell(s string) string {
+	s = strings.ReplaceAll(s, "|", `\|`)
+	return strings.ReplaceAll(s, "\n", " ")
+}
@@ -0,0 +1,98 @@
+package envkit
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type testDatabaseConfig struct {
+	URL      string `env:"URL,required" description:"Postgres connection string"`
+	MaxConns int    `env:"MAX_CONNS" envDefault:"10"`
+}
+
+type testConfig struct {
+	Port     int                `env:"PORT" envDefault:"8080" description:"Port to listen on"`
+	Timeout  time.Duration      `env:"TIMEOUT" envDefault:"30s"`
+	Debug    *bool              `env:"DEBUG"`
+	Origins  []string           `env:"ALLOWED_ORIGINS" description:"Origins allowed by CORS | comma-separated"`
+	Flags    map[string]bool    `env:"FEATURE_FLAGS"`
+	Started  time.Time          `env:"STARTED_AT"`
+	Database testDatabaseConfig `envPrefix:"DATABASE_"`
+	Ignored  string             `env:"-"`
+	Untagged string
+	internal string `env:"INTERNAL"`
+}
+
+func TestDescribeEnv(t *testing.T) {
+	t.Run("describes_the_tagged_fields", func(t *testing.T) {
+		vars, err := DescribeEnv(&testConfig{})
+
+		assert.NoError(t, err)
+		assert.Equal(t, []EnvVar{
+			{Name: "PORT", Type: "int", Default: "8080", Description: "Port to listen on"},
+			{Name: "TIMEOUT", Type: "duration", Default: "30s"},
+			{Name: "DEBUG", Type: "bool"},
+			{Name: "ALLOWED_ORIGINS", Type: "list of string", Description: "Origins allowed by CORS | comma-separated"},
+			{Name: "FEATURE_FLAGS", Type: "JSON"},
+			{Name: "STARTED_AT", Type: "time"},
+			{Name: "DATABASE_URL", Type: "string", Required: true, Description: "Postgres connection string"},
+			{Name: "DATABASE_MAX_CONNS", Type: "int", Default: "10"},
+		}, vars)
+	})
+
+	t.Run("returns_an_error_for_a_non_struct", func(t *testing.T) {
+		_, err := DescribeEnv("theConfig")
+
+		assert.EqualError(t, err, "config must be a struct or pointer to a struct, got string")
+	})
+
+	t.Run("returns_an_error_for_a_duplicate_name", func(t *testing.T) {
+		_, err := DescribeEnv(struct {
+			A string `env:"NAME"`
+			B string `env:"NAME"`
+		}{})
+
+		assert.EqualError(t, err, "fields A and B both use environment variable NAME")
+	})
+
+	t.Run("returns_an_error_for_an_unknown_option", func(t *testing.T) {
+		_, err := DescribeEnv(struct {
+			Nested struct {
+				A string `env:"NAME,optional"`
+			}
+		}{})
+
+		assert.EqualError(t, err, `field Nested.A has unknown env tag option "optional"`)
+	})
+}
+
+func TestWriteEnvMarkdown(t *testing.T) {
+	t.Run("writes_a_table", func(t *testing.T) {
+		var buf bytes.Buffer
+
+		err := WriteEnvMarkdown(&buf, []EnvVar{
+			{Name: "PORT", Type: "int", Default: "8080", Description: "Port to listen on"},
+			{Name: "DATABASE_URL", Type: "string", Required: true, Description: "a | b"},
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, "| Name | Type | Default | Required | Description |\n"+
+			"| --- | --- | --- | --- | --- |\n"+
+			"| `PORT` | int | `8080` | no | Port to listen on |\n"+
+			"| `DATABASE_URL` | string |  | yes | a \\| b |\n", buf.String())
+	})
+}
+
+func TestWriteEnvJSON(t *testing.T) {
+	t.Run("writes_an_array", func(t *testing.T) {
+		var buf bytes.Buffer
+
+		err := WriteEnvJSON(&buf, []EnvVar{{Name: "PORT", Type: "int", Default: "8080"}})
+
+		assert.NoError(t, err)
+		assert.JSONEq(t, `[{"name":"PORT","type":"int","default":"8080","required":false}]`, buf.String())
+	})
+}
@@ -0,0 +1,26 @@
+package envkit
+
+import (
+	"os"
+
+	"github.com/half-ogre/go-kit/filekit"
+)
+
+// WatchEnv watches a .env file, or a directory read with ReadEnvDir, and calls onChange with its
+// variables each time it changes, or with the error reading it, e.g. so a dev server picks up edited
+// settings without restarting. It doesn't set the variables in the environment. Close the returned
+// watcher to stop watching.
+func WatchEnv(path string, onChange func(env map[string]string, err error), options ...filekit.WatchOption) (*filekit.Watcher, error) {
+	return filekit.NewWatcher(path, func([]string) {
+		onChange(readEnv(path))
+	}, options...)
+}
+
+// readEnv reads the variables from a .env file or a directory read with ReadEnvDir
+func readEnv(path string) (map[string]string, error) {
+	info, err := os.Stat(path)
+	if err == nil && info.IsDir() {
+		return ReadEnvDir(path)
+	}
+	return ReadEnvFile(path)
+}
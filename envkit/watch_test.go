@@ -0,0 +1,63 @@
+package envkit
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/half-ogre/go-kit/filekit"
+)
+
+func TestWatchEnv(t *testing.T) {
+	t.Run("calls_back_with_the_variables_when_the_file_changes", func(t *testing.T) {
+		thePath := filepath.Join(t.TempDir(), ".env")
+		os.WriteFile(thePath, []byte("A=1\n"), 0o644)
+		changes := make(chan map[string]string, 10)
+		w, err := WatchEnv(thePath, func(env map[string]string, err error) {
+			assert.NoError(t, err)
+			changes <- env
+		}, filekit.WithWatchDebounce(20*time.Millisecond))
+		require.NoError(t, err)
+		defer w.Close()
+
+		filekit.WriteFileAtomic(thePath, []byte("A=2\nB=3\n"), 0o644)
+
+		select {
+		case env := <-changes:
+			assert.Equal(t, map[string]string{"A": "2", "B": "3"}, env)
+		case <-time.After(2 * time.Second):
+			t.Fatal("timed out waiting for a change")
+		}
+	})
+
+	t.Run("calls_back_with_the_variables_when_a_directory_changes", func(t *testing.T) {
+		theDir := t.TempDir()
+		os.WriteFile(filepath.Join(theDir, "A"), []byte("1"), 0o644)
+		changes := make(chan map[string]string, 10)
+		w, err := WatchEnv(theDir, func(env map[string]string, err error) {
+			assert.NoError(t, err)
+			changes <- env
+		}, filekit.WithWatchDebounce(20*time.Millisecond))
+		require.NoError(t, err)
+		defer w.Close()
+
+		os.WriteFile(filepath.Join(theDir, "B"), []byte("2"), 0o644)
+
+		select {
+		case env := <-changes:
+			assert.Equal(t, map[string]string{"A": "1", "B": "2"}, env)
+		case <-time.After(2 * time.Second):
+			t.Fatal("timed out waiting for a change")
+		}
+	})
+
+	t.Run("returns_an_error_when_the_path_does_not_exist", func(t *testing.T) {
+		_, err := WatchEnv(filepath.Join(t.TempDir(), ".env"), func(map[string]string, error) {})
+
+		assert.ErrorIs(t, err, os.ErrNotExist)
+	})
+}
@@ -0,0 +1,111 @@
+package eventbridgekit
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/half-ogre/go-kit/kit"
+)
+
+// Event is an event as EventBridge delivers it to targets, e.g. a Lambda function or an SQS queue
+type Event struct {
+	Version    string          `json:"version"`
+	ID         string          `json:"id"`
+	DetailType string          `json:"detail-type"`
+	Source     string          `json:"source"`
+	Account    string          `json:"account"`
+	Time       time.Time       `json:"time"`
+	Region     string          `json:"region"`
+	Resources  []string        `json:"resources"`
+	Detail     json.RawMessage `json:"detail"`
+}
+
+// UnmarshalDetail unmarshals the Envelope in an event's detail. It returns ErrDetailTypeMismatch if the
+// event's detail-type isn't DetailType[T]. Check the envelope's Version before using its Data when T's
+// schema has changed incompatibly.
+func UnmarshalDetail[T any](event *Event) (*Envelope[T], error) {
+	if detailType := DetailType[T](); event.DetailType != detailType {
+		return nil, fmt.Errorf("%w: event is %s, not %s", ErrDetailTypeMismatch, event.DetailType, detailType)
+	}
+
+	var envelope Envelope[T]
+	if err := json.Unmarshal(event.Detail, &envelope); err != nil {
+		return nil, kit.WrapError(err, "error unmarshalling %s event %s", event.DetailType, event.ID)
+	}
+	return &envelope, nil
+}
+
+// Handler handles an event of type T, with the envelope unmarshaled from its detail
+type Handler[T any] func(ctx context.Context, event *Event, envelope *Envelope[T]) error
+
+// Router routes events to handlers by detail-type
+type Router struct {
+	handlers map[string]func(ctx context.Context, event *Event) error
+}
+
+// NewRouter returns a router without handlers
+func NewRouter() *Router {
+	return &Router{handlers: map[string]func(ctx context.Context, event *Event) error{}}
+}
+
+// Handle routes events with detail-type DetailType[T] to handler, replacing any handler already routed
+// that detail-type
+func Handle[T any](router *Router, handler Handler[T]) {
+	router.handlers[DetailType[T]()] = func(ctx context.Context, event *Event) error {
+		envelope, err := UnmarshalDetail[T](event)
+		if err != nil {
+			return err
+		}
+		return handler(ctx, event, envelope)
+	}
+}
+
+// Route unmarshals an event as EventBridge delivers it and passes it to the handler for its
+// detail-type, returning the handler's error. It returns ErrUnhandledDetailType when there's no handler.
+func (r *Router) Route(ctx context.Context, data []byte) error {
+	var event Event
+	if err := json.Unmarshal(data, &event); err != nil {
+		return kit.WrapError(err, "error unmarshalling event")
+	}
+	return r.RouteEvent(ctx, &event)
+}
+
+// RouteEvent passes an event to the handler for its detail-type, as Route does
+func (r *Router) RouteEvent(ctx context.Context, event *Event) error {
+	handler, ok := r.handlers[event.DetailType]
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrUnhandledDetailType, event.DetailType)
+	}
+	return handler(ctx, event)
+}
+
+// Pattern is the event pattern of a rule matching events by source and detail-type
+type Pattern struct {
+	Source     []string `json:"source,omitempty"`
+	DetailType []string `json:"detail-type,omitempty"`
+}
+
+// PatternFor returns the pattern matching events of type T from sources, e.g. for a rule's
+// EventPattern in deployment code
+func PatternFor[T any](sources ...string) Pattern {
+	return Pattern{Source: sources, DetailType: []string{DetailType[T]()}}
+}
+
+// JSON returns the pattern as the JSON a rule's EventPattern takes
+func (p Pattern) JSON() string {
+	data, _ := json.Marshal(p)
+	return string(data)
+}
+
+// Matches reports whether the pattern matches an event, e.g. to check rules in tests
+func (p Pattern) Matches(event *Event) bool {
+	return matchesAny(p.Source, event.Source) && matchesAny(p.DetailType, event.DetailType)
+}
+
+// matchesAny reports whether value is one of values, which match anything when empty
+func matchesAny(values []string, value string) bool {
+	return len(values) == 0 || slices.Contains(values, value)
+}
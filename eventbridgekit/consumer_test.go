@@ -0,0 +1,108 @@
+package eventbridgekit
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const theOrderPlacedEvent = `{
+	"version": "0",
+	"id": "theEventID",
+	"detail-type": "OrderPlaced",
+	"source": "theSource",
+	"account": "123456789012",
+	"time": "2024-01-02T03:04:05Z",
+	"region": "us-east-1",
+	"resources": [],
+	"detail": {"version": 2, "data": {"order_id": "theOrderID"}}
+}`
+
+func TestUnmarshalDetail(t *testing.T) {
+	t.Run("unmarshals_the_envelope", func(t *testing.T) {
+		event := &Event{ID: "theEventID", DetailType: "OrderPlaced", Detail: []byte(`{"version":2,"data":{"order_id":"theOrderID"}}`)}
+
+		envelope, err := UnmarshalDetail[OrderPlaced](event)
+
+		assert.NoError(t, err)
+		assert.Equal(t, &Envelope[OrderPlaced]{Version: 2, Data: OrderPlaced{OrderID: "theOrderID"}}, envelope)
+	})
+
+	t.Run("returns_an_error_for_another_detail_type", func(t *testing.T) {
+		event := &Event{DetailType: "Order Shipped", Detail: []byte(`{}`)}
+
+		_, err := UnmarshalDetail[OrderPlaced](event)
+
+		assert.ErrorIs(t, err, ErrDetailTypeMismatch)
+		assert.EqualError(t, err, "detail-type mismatch: event is Order Shipped, not OrderPlaced")
+	})
+}
+
+func TestRouter(t *testing.T) {
+	t.Run("routes_an_event_to_the_handler_for_its_detail_type", func(t *testing.T) {
+		router := NewRouter()
+		var actualEvent *Event
+		var actualEnvelope *Envelope[OrderPlaced]
+		Handle(router, func(ctx context.Context, event *Event, envelope *Envelope[OrderPlaced]) error {
+			actualEvent, actualEnvelope = event, envelope
+			return nil
+		})
+		Handle(router, func(ctx context.Context, event *Event, envelope *Envelope[renamedEvent]) error {
+			t.Fatal("the other handler should not be called")
+			return nil
+		})
+
+		err := router.Route(context.Background(), []byte(theOrderPlacedEvent))
+
+		assert.NoError(t, err)
+		assert.Equal(t, "theEventID", actualEvent.ID)
+		assert.Equal(t, "theSource", actualEvent.Source)
+		assert.Equal(t, 2, actualEnvelope.Version)
+		assert.Equal(t, "theOrderID", actualEnvelope.Data.OrderID)
+	})
+
+	t.Run("returns_the_handler_error", func(t *testing.T) {
+		router := NewRouter()
+		Handle(router, func(ctx context.Context, event *Event, envelope *Envelope[OrderPlaced]) error {
+			return errors.New("the handler error")
+		})
+
+		err := router.Route(context.Background(), []byte(theOrderPlacedEvent))
+
+		assert.EqualError(t, err, "the handler error")
+	})
+
+	t.Run("returns_an_error_for_a_detail_type_without_a_handler", func(t *testing.T) {
+		router := NewRouter()
+
+		err := router.RouteEvent(context.Background(), &Event{DetailType: "Order Shipped"})
+
+		assert.ErrorIs(t, err, ErrUnhandledDetailType)
+	})
+
+	t.Run("returns_an_error_for_an_invalid_event", func(t *testing.T) {
+		err := NewRouter().Route(context.Background(), []byte("not json"))
+
+		assert.ErrorContains(t, err, "error unmarshalling event")
+	})
+}
+
+func TestPattern(t *testing.T) {
+	t.Run("matches_events_of_the_type_from_the_sources", func(t *testing.T) {
+		pattern := PatternFor[OrderPlaced]("theSource", "anotherSource")
+
+		assert.Equal(t, `{"source":["theSource","anotherSource"],"detail-type":["OrderPlaced"]}`, pattern.JSON())
+		assert.True(t, pattern.Matches(&Event{Source: "anotherSource", DetailType: "OrderPlaced"}))
+		assert.False(t, pattern.Matches(&Event{Source: "theSource", DetailType: "Order Shipped"}))
+		assert.False(t, pattern.Matches(&Event{Source: "aThirdSource", DetailType: "OrderPlaced"}))
+	})
+
+	t.Run("matches_any_source_without_sources", func(t *testing.T) {
+		pattern := PatternFor[OrderPlaced]()
+
+		assert.Equal(t, `{"detail-type":["OrderPlaced"]}`, pattern.JSON())
+		assert.True(t, pattern.Matches(&Event{Source: "theSource", DetailType: "OrderPlaced"}))
+	})
+}
@@ -0,0 +1,142 @@
+// Package eventbridgekit publishes and consumes typed Amazon EventBridge events. Each Go type is one
+// detail-type, by convention the type's name, and its detail is an Envelope holding the event and the
+// version of its schema, so consumers can tell old events from new ones as the type changes. A
+// Publisher puts events in batches, and a Router unmarshals received events by detail-type for their
+// handlers.
+//
+// The AWS SDK's EventBridge client isn't a dependency of this module, so publishers use the small
+// EventBridge interface instead. Adapting *eventbridge.Client to it takes a few lines: map each Entry
+// to a types.PutEventsRequestEntry and each of the output's Entries to an EntryResult.
+package eventbridgekit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/half-ogre/go-kit/logkit"
+)
+
+var logger = logkit.Logger("eventbridgekit")
+
+// The limits of one PutEvents request
+const (
+	MaxBatchEntries = 10
+	MaxBatchBytes   = 256 * 1024
+)
+
+// ErrDetailTypeMismatch is returned when unmarshaling an event into a type for another detail-type
+var ErrDetailTypeMismatch = errors.New("detail-type mismatch")
+
+// ErrUnhandledDetailType is returned by Router.Route for an event whose detail-type has no handler
+var ErrUnhandledDetailType = errors.New("unhandled detail-type")
+
+// EventBridge is the part of the EventBridge API publishers use
+type EventBridge interface {
+	// PutEvents puts entries and returns a result for each, in order
+	PutEvents(ctx context.Context, entries []Entry) ([]EntryResult, error)
+}
+
+// Entry is one event to put
+type Entry struct {
+	EventBusName string
+	Source       string
+	DetailType   string
+	Detail       string
+	Resources    []string
+	// Time is the event's time; EventBridge uses the time it's put when it's zero
+	Time time.Time
+}
+
+// size is the entry's size as EventBridge counts it against MaxBatchBytes
+func (e Entry) size() int {
+	size := len(e.Source) + len(e.DetailType) + len(e.Detail)
+	for _, resource := range e.Resources {
+		size += len(resource)
+	}
+	if !e.Time.IsZero() {
+		size += 14
+	}
+	return size
+}
+
+// EntryResult is the result of putting one entry: its event ID, or an error code if it failed
+type EntryResult struct {
+	EventID      string
+	ErrorCode    string
+	ErrorMessage string
+}
+
+// EntryFailure is an entry that still failed after retries
+type EntryFailure struct {
+	// Index is the entry's position in the entries or events that were put
+	Index        int
+	ErrorCode    string
+	ErrorMessage string
+}
+
+// PutEventsError is returned when entries still fail after retries
+type PutEventsError struct {
+	Failures []EntryFailure
+}
+
+func (e *PutEventsError) Error() string {
+	reasons := make([]string, 0, min(len(e.Failures), 3))
+	for _, failure := range e.Failures[:min(len(e.Failures), 3)] {
+		reasons = append(reasons, fmt.Sprintf("%d: %s: %s", failure.Index, failure.ErrorCode, failure.ErrorMessage))
+	}
+	return fmt.Sprintf("%d events failed: %s", len(e.Failures), strings.Join(reasons, "; "))
+}
+
+// DetailTyper is implemented by event types whose detail-type isn't their type's name
+type DetailTyper interface {
+	DetailType() string
+}
+
+// SchemaVersioner is implemented by event types whose schema has changed since version 1, returning
+// the version events of the type are published with
+type SchemaVersioner interface {
+	SchemaVersion() int
+}
+
+// DetailType returns the detail-type of events of type T: the result of its DetailType method if it
+// implements DetailTyper, or else its type name, e.g. "OrderPlaced"
+func DetailType[T any]() string {
+	if detailTyper, ok := zeroOf[T]().(DetailTyper); ok {
+		return detailTyper.DetailType()
+	}
+	t := reflect.TypeFor[T]()
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	return t.Name()
+}
+
+// SchemaVersion returns the version events of type T are published with: the result of its
+// SchemaVersion method if it implements SchemaVersioner, or else 1
+func SchemaVersion[T any]() int {
+	if versioner, ok := zeroOf[T]().(SchemaVersioner); ok {
+		return versioner.SchemaVersion()
+	}
+	return 1
+}
+
+// zeroOf returns T's zero value, or a pointer to a new value if T is a pointer type, so its methods
+// can be called without dereferencing nil
+func zeroOf[T any]() any {
+	t := reflect.TypeFor[T]()
+	if t.Kind() == reflect.Pointer {
+		return reflect.New(t.Elem()).Interface()
+	}
+	var zero T
+	return zero
+}
+
+// Envelope is the detail of a published event: the event and the version of its schema
+type Envelope[T any] struct {
+	Version int `json:"version"`
+	Data    T   `json:"data"`
+}
@@ -0,0 +1,44 @@
+package eventbridgekit
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type OrderPlaced struct {
+	OrderID string `json:"order_id"`
+}
+
+type renamedEvent struct{}
+
+func (renamedEvent) DetailType() string { return "Order Shipped" }
+
+type versionedEvent struct{}
+
+func (*versionedEvent) SchemaVersion() int { return 3 }
+
+func TestDetailType(t *testing.T) {
+	t.Run("returns_the_type_name", func(t *testing.T) {
+		assert.Equal(t, "OrderPlaced", DetailType[OrderPlaced]())
+	})
+
+	t.Run("returns_the_type_name_for_a_pointer_type", func(t *testing.T) {
+		assert.Equal(t, "OrderPlaced", DetailType[*OrderPlaced]())
+	})
+
+	t.Run("returns_the_detail_type_method_result", func(t *testing.T) {
+		assert.Equal(t, "Order Shipped", DetailType[renamedEvent]())
+		assert.Equal(t, "Order Shipped", DetailType[*renamedEvent]())
+	})
+}
+
+func TestSchemaVersion(t *testing.T) {
+	t.Run("returns_1_by_default", func(t *testing.T) {
+		assert.Equal(t, 1, SchemaVersion[OrderPlaced]())
+	})
+
+	t.Run("returns_the_schema_version_method_result", func(t *testing.T) {
+		assert.Equal(t, 3, SchemaVersion[*versionedEvent]())
+	})
+}
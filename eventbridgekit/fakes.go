@@ -0,0 +1,18 @@
+package eventbridgekit
+
+import (
+	"context"
+)
+
+// FakeEventBridge is an EventBridge for tests
+type FakeEventBridge struct {
+	PutEventsFake func(ctx context.Context, entries []Entry) ([]EntryResult, error)
+}
+
+func (f *FakeEventBridge) PutEvents(ctx context.Context, entries []Entry) ([]EntryResult, error) {
+	if f.PutEventsFake != nil {
+		return f.PutEventsFake(ctx, entries)
+	} else {
+		panic("PutEvents fake not implemented")
+	}
+}
@@ -0,0 +1,198 @@
+package eventbridgekit
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/half-ogre/go-kit/kit"
+)
+
+// PublisherOption configures NewPublisher
+type PublisherOption func(*Publisher)
+
+// WithEventBusName sets the event bus events are put on; the default is the account's default bus
+func WithEventBusName(eventBusName string) PublisherOption {
+	return func(p *Publisher) {
+		p.eventBusName = eventBusName
+	}
+}
+
+// WithPublisherRetries sets how many times entries that fail, e.g. with a ThrottlingException, are put
+// again and the initial backoff between attempts, which doubles after each retry; the default is 2
+// retries starting at 100ms. Failed requests aren't retried here: the AWS SDK client already retries
+// them.
+func WithPublisherRetries(maxRetries int, backoff time.Duration) PublisherOption {
+	return func(p *Publisher) {
+		p.maxRetries = maxRetries
+		p.backoff = backoff
+	}
+}
+
+// Publisher puts events from one source, e.g. "com.example.orders", on an event bus
+type Publisher struct {
+	eventBridge  EventBridge
+	source       string
+	eventBusName string
+	maxRetries   int
+	backoff      time.Duration
+}
+
+// NewPublisher returns a publisher putting events from source with eventBridge
+func NewPublisher(eventBridge EventBridge, source string, options ...PublisherOption) *Publisher {
+	p := &Publisher{
+		eventBridge: eventBridge,
+		source:      source,
+		maxRetries:  2,
+		backoff:     100 * time.Millisecond,
+	}
+	for _, option := range options {
+		option(p)
+	}
+	return p
+}
+
+// PutEventOption configures the entries PutEvent and PutEvents put
+type PutEventOption func(*Entry)
+
+// WithResources sets the ARNs of the resources the event is about
+func WithResources(resources ...string) PutEventOption {
+	return func(e *Entry) {
+		e.Resources = resources
+	}
+}
+
+// WithTime sets the event's time, e.g. when what it describes happened; the default is when it's put
+func WithTime(t time.Time) PutEventOption {
+	return func(e *Entry) {
+		e.Time = t
+	}
+}
+
+// NewEntry returns the entry for event: its detail-type is DetailType[T] and its detail an Envelope
+// holding event with SchemaVersion[T]
+func NewEntry[T any](publisher *Publisher, event T, options ...PutEventOption) (Entry, error) {
+	detail, err := json.Marshal(Envelope[T]{Version: SchemaVersion[T](), Data: event})
+	if err != nil {
+		return Entry{}, kit.WrapError(err, "error marshalling %s event", DetailType[T]())
+	}
+
+	entry := Entry{
+		EventBusName: publisher.eventBusName,
+		Source:       publisher.source,
+		DetailType:   DetailType[T](),
+		Detail:       string(detail),
+	}
+	for _, option := range options {
+		option(&entry)
+	}
+	return entry, nil
+}
+
+// PutEvent puts event, as NewEntry makes it, and returns its event ID
+func PutEvent[T any](ctx context.Context, publisher *Publisher, event T, options ...PutEventOption) (string, error) {
+	entry, err := NewEntry(publisher, event, options...)
+	if err != nil {
+		return "", err
+	}
+
+	eventIDs, err := publisher.PutEntries(ctx, []Entry{entry})
+	if err != nil {
+		return "", err
+	}
+	return eventIDs[0], nil
+}
+
+// PutEvents puts events, as NewEntry makes them, in as few requests as the batch limits allow, and
+// returns their event IDs in order. See PutEntries for how failures are returned.
+func PutEvents[T any](ctx context.Context, publisher *Publisher, events []T, options ...PutEventOption) ([]string, error) {
+	entries := make([]Entry, 0, len(events))
+	for _, event := range events {
+		entry, err := NewEntry(publisher, event, options...)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, entry)
+	}
+	return publisher.PutEntries(ctx, entries)
+}
+
+// PutEntries puts entries, e.g. of different event types from NewEntry, in batches of up to
+// MaxBatchEntries and MaxBatchBytes, retrying entries that fail. It returns the event IDs in order,
+// with "" for entries that still failed, and a *PutEventsError naming them. A failed request is
+// returned as is, and the batches after it aren't put.
+func (p *Publisher) PutEntries(ctx context.Context, entries []Entry) ([]string, error) {
+	eventIDs := make([]string, len(entries))
+	var failures []EntryFailure
+
+	start := 0
+	for start < len(entries) {
+		end, size := start, 0
+		for end < len(entries) && end-start < MaxBatchEntries && (end == start || size+entries[end].size() <= MaxBatchBytes) {
+			size += entries[end].size()
+			end++
+		}
+
+		batchFailures, err := p.putBatch(ctx, entries, start, end, eventIDs)
+		if err != nil {
+			return eventIDs, err
+		}
+		failures = append(failures, batchFailures...)
+		start = end
+	}
+
+	if len(failures) > 0 {
+		return eventIDs, &PutEventsError{Failures: failures}
+	}
+	return eventIDs, nil
+}
+
+// putBatch puts entries[start:end], retrying the entries that fail, and sets their event IDs
+func (p *Publisher) putBatch(ctx context.Context, entries []Entry, start int, end int, eventIDs []string) ([]EntryFailure, error) {
+	pending := make([]int, 0, end-start)
+	for i := start; i < end; i++ {
+		pending = append(pending, i)
+	}
+
+	backoff := p.backoff
+	for attempt := 0; ; attempt++ {
+		batch := make([]Entry, len(pending))
+		for i, index := range pending {
+			batch[i] = entries[index]
+		}
+
+		results, err := p.eventBridge.PutEvents(ctx, batch)
+		if err != nil {
+			return nil, kit.WrapError(err, "error putting %d events", len(batch))
+		}
+
+		var failed []int
+		var failures []EntryFailure
+		for i, index := range pending {
+			if i >= len(results) || results[i].ErrorCode != "" {
+				failed = append(failed, index)
+				failure := EntryFailure{Index: index, ErrorCode: "MissingResult"}
+				if i < len(results) {
+					failure.ErrorCode, failure.ErrorMessage = results[i].ErrorCode, results[i].ErrorMessage
+				}
+				failures = append(failures, failure)
+				continue
+			}
+			eventIDs[index] = results[i].EventID
+		}
+
+		if len(failed) == 0 || attempt >= p.maxRetries {
+			return failures, nil
+		}
+
+		logger.WarnContext(ctx, "retrying failed eventbridge entries", "entries", len(failed), "error_code", failures[0].ErrorCode, "attempt", attempt+1)
+
+		select {
+		case <-ctx.Done():
+			return failures, nil
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+		pending = failed
+	}
+}
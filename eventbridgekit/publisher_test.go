@@ -0,0 +1,134 @@
+package eventbridgekit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// acceptingEventBridge returns a fake recording the batches it's sent, accepting every entry
+func acceptingEventBridge(batches *[][]Entry) *FakeEventBridge {
+	return &FakeEventBridge{
+		PutEventsFake: func(ctx context.Context, entries []Entry) ([]EntryResult, error) {
+			*batches = append(*batches, entries)
+			results := make([]EntryResult, len(entries))
+			for i := range entries {
+				results[i] = EntryResult{EventID: fmt.Sprintf("event-%d-%d", len(*batches), i)}
+			}
+			return results, nil
+		},
+	}
+}
+
+func TestPutEvent(t *testing.T) {
+	t.Run("puts_the_event_in_a_versioned_envelope", func(t *testing.T) {
+		var batches [][]Entry
+		publisher := NewPublisher(acceptingEventBridge(&batches), "theSource", WithEventBusName("theBus"))
+		theTime := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+
+		eventID, err := PutEvent(context.Background(), publisher, OrderPlaced{OrderID: "theOrderID"}, WithResources("theResource"), WithTime(theTime))
+
+		assert.NoError(t, err)
+		assert.Equal(t, "event-1-0", eventID)
+		assert.Equal(t, [][]Entry{{{
+			EventBusName: "theBus",
+			Source:       "theSource",
+			DetailType:   "OrderPlaced",
+			Detail:       `{"version":1,"data":{"order_id":"theOrderID"}}`,
+			Resources:    []string{"theResource"},
+			Time:         theTime,
+		}}}, batches)
+	})
+
+	t.Run("returns_the_error_from_put_events", func(t *testing.T) {
+		publisher := NewPublisher(&FakeEventBridge{
+			PutEventsFake: func(ctx context.Context, entries []Entry) ([]EntryResult, error) {
+				return nil, errors.New("the put error")
+			},
+		}, "theSource")
+
+		_, err := PutEvent(context.Background(), publisher, OrderPlaced{})
+
+		assert.EqualError(t, err, "error putting 1 events: the put error")
+	})
+}
+
+func TestPutEvents(t *testing.T) {
+	t.Run("puts_events_in_batches_of_the_most_entries", func(t *testing.T) {
+		var batches [][]Entry
+		publisher := NewPublisher(acceptingEventBridge(&batches), "theSource")
+		events := make([]OrderPlaced, 23)
+
+		eventIDs, err := PutEvents(context.Background(), publisher, events)
+
+		assert.NoError(t, err)
+		assert.Len(t, eventIDs, 23)
+		assert.Equal(t, "event-3-2", eventIDs[22])
+		assert.Len(t, batches, 3)
+		assert.Len(t, batches[0], 10)
+		assert.Len(t, batches[2], 3)
+	})
+
+	t.Run("puts_events_in_batches_under_the_most_bytes", func(t *testing.T) {
+		var batches [][]Entry
+		publisher := NewPublisher(acceptingEventBridge(&batches), "theSource")
+		large := strings.Repeat("a", MaxBatchBytes/2)
+		events := []OrderPlaced{{OrderID: large}, {OrderID: large}, {OrderID: "small"}}
+
+		_, err := PutEvents(context.Background(), publisher, events)
+
+		assert.NoError(t, err)
+		assert.Len(t, batches, 2)
+		assert.Len(t, batches[0], 1)
+		assert.Len(t, batches[1], 2)
+	})
+
+	t.Run("retries_failed_entries", func(t *testing.T) {
+		var sent [][]Entry
+		publisher := NewPublisher(&FakeEventBridge{
+			PutEventsFake: func(ctx context.Context, entries []Entry) ([]EntryResult, error) {
+				sent = append(sent, entries)
+				if len(sent) == 1 {
+					return []EntryResult{{EventID: "theEventID"}, {ErrorCode: "ThrottlingException", ErrorMessage: "Rate exceeded"}}, nil
+				}
+				return []EntryResult{{EventID: "anotherEventID"}}, nil
+			},
+		}, "theSource", WithPublisherRetries(2, time.Millisecond))
+
+		eventIDs, err := PutEvents(context.Background(), publisher, []OrderPlaced{{OrderID: "a"}, {OrderID: "b"}})
+
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"theEventID", "anotherEventID"}, eventIDs)
+		assert.Len(t, sent, 2)
+		assert.Equal(t, `{"version":1,"data":{"order_id":"b"}}`, sent[1][0].Detail)
+	})
+
+	t.Run("returns_a_put_events_error_for_entries_that_still_fail", func(t *testing.T) {
+		attempts := 0
+		publisher := NewPublisher(&FakeEventBridge{
+			PutEventsFake: func(ctx context.Context, entries []Entry) ([]EntryResult, error) {
+				attempts++
+				results := make([]EntryResult, len(entries))
+				results[len(entries)-1] = EntryResult{ErrorCode: "InternalFailure", ErrorMessage: "the failure"}
+				if len(entries) > 1 {
+					results[0] = EntryResult{EventID: "theEventID"}
+				}
+				return results, nil
+			},
+		}, "theSource", WithPublisherRetries(1, time.Millisecond))
+
+		eventIDs, err := PutEvents(context.Background(), publisher, []OrderPlaced{{OrderID: "a"}, {OrderID: "b"}})
+
+		var putErr *PutEventsError
+		assert.ErrorAs(t, err, &putErr)
+		assert.Equal(t, []EntryFailure{{Index: 1, ErrorCode: "InternalFailure", ErrorMessage: "the failure"}}, putErr.Failures)
+		assert.EqualError(t, err, "1 events failed: 1: InternalFailure: the failure")
+		assert.Equal(t, []string{"theEventID", ""}, eventIDs)
+		assert.Equal(t, 2, attempts)
+	})
+}
@@ -0,0 +1,53 @@
+package filekit
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/half-ogre/go-kit/kit"
+)
+
+// ErrChecksumMismatch is returned by VerifyChecksum when a file's checksum isn't the one expected
+var ErrChecksumMismatch = errors.New("checksum mismatch")
+
+// Checksum returns the hex encoded SHA-256 checksum of r's contents
+func Checksum(r io.Reader) (string, error) {
+	hash := sha256.New()
+	_, err := io.Copy(hash, r)
+	if err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// ChecksumFile returns the hex encoded SHA-256 checksum of the file at path
+func ChecksumFile(path string) (string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", kit.WrapError(err, "error opening %s", path)
+	}
+	defer file.Close()
+
+	sum, err := Checksum(file)
+	if err != nil {
+		return "", kit.WrapError(err, "error reading %s", path)
+	}
+	return sum, nil
+}
+
+// VerifyChecksum returns an error wrapping ErrChecksumMismatch unless the hex encoded SHA-256 checksum
+// of the file at path is expected, in either case
+func VerifyChecksum(path string, expected string) error {
+	sum, err := ChecksumFile(path)
+	if err != nil {
+		return err
+	}
+	if !strings.EqualFold(sum, expected) {
+		return kit.WrapError(ErrChecksumMismatch, "%s has checksum %s, expected %s", path, sum, expected)
+	}
+	return nil
+}
@@ -0,0 +1,57 @@
+package filekit
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const helloSHA256 = "2cf24dba5fb0a30e26e83b2ac5b9e29e1b161e5c1fa7425e73043362938b9824"
+
+func TestChecksum(t *testing.T) {
+	t.Run("returns_the_sha256_of_the_contents", func(t *testing.T) {
+		sum, err := Checksum(strings.NewReader("hello"))
+
+		require.NoError(t, err)
+		assert.Equal(t, helloSHA256, sum)
+	})
+}
+
+func TestChecksumFile(t *testing.T) {
+	t.Run("returns_the_sha256_of_the_file", func(t *testing.T) {
+		thePath := filepath.Join(t.TempDir(), "file")
+		os.WriteFile(thePath, []byte("hello"), 0o644)
+
+		sum, err := ChecksumFile(thePath)
+
+		require.NoError(t, err)
+		assert.Equal(t, helloSHA256, sum)
+	})
+
+	t.Run("returns_an_error_when_the_file_does_not_exist", func(t *testing.T) {
+		_, err := ChecksumFile(filepath.Join(t.TempDir(), "missing"))
+
+		assert.ErrorIs(t, err, os.ErrNotExist)
+	})
+}
+
+func TestVerifyChecksum(t *testing.T) {
+	thePath := filepath.Join(t.TempDir(), "file")
+	os.WriteFile(thePath, []byte("hello"), 0o644)
+
+	t.Run("returns_nil_when_the_checksum_matches_in_any_case", func(t *testing.T) {
+		err := VerifyChecksum(thePath, strings.ToUpper(helloSHA256))
+
+		assert.NoError(t, err)
+	})
+
+	t.Run("returns_err_checksum_mismatch_when_it_does_not_match", func(t *testing.T) {
+		err := VerifyChecksum(thePath, "0000")
+
+		assert.ErrorIs(t, err, ErrChecksumMismatch)
+	})
+}
@@ -0,0 +1,88 @@
+// Package filekit has the file primitives the kit would otherwise write over and over: atomic writes
+// that rename a finished temporary file into place, advisory file locks, SHA-256 checksums, temporary
+// directories that clean up after themselves, and a debounced, recursive directory watcher. logkit
+// writes its overflow batches with WriteFileAtomic, envkit's watch mode and the echokit live reload
+// are built on Watcher.
+//
+//	err := filekit.WriteFileAtomic("schema.sql", dump, 0o644)
+package filekit
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/half-ogre/go-kit/kit"
+)
+
+// WriteFileAtomic writes data to path as os.WriteFile does, except that readers see either the old
+// contents or all of data, never part of it, and a crash doesn't leave a partly written file behind
+func WriteFileAtomic(path string, data []byte, perm os.FileMode) error {
+	return WriteAtomic(path, perm, func(w io.Writer) error {
+		_, err := io.Copy(w, bytes.NewReader(data))
+		return err
+	})
+}
+
+// WriteAtomic writes what write writes to path atomically, as WriteFileAtomic does, for contents
+// written as a stream, e.g. a schema dump. It writes a temporary file in path's directory, syncs it to
+// disk, and renames it over path; when write returns an error path is left as it was.
+func WriteAtomic(path string, perm os.FileMode, write func(w io.Writer) error) error {
+	dir := filepath.Dir(path)
+	file, err := os.CreateTemp(dir, "."+filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return kit.WrapError(err, "error creating temporary file for %s", path)
+	}
+	defer os.Remove(file.Name())
+
+	err = write(file)
+	if err == nil {
+		err = file.Chmod(perm)
+	}
+	if err == nil {
+		err = file.Sync()
+	}
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return kit.WrapError(err, "error writing %s", path)
+	}
+
+	err = os.Rename(file.Name(), path)
+	if err != nil {
+		return kit.WrapError(err, "error renaming temporary file to %s", path)
+	}
+
+	syncDir(dir)
+	return nil
+}
+
+// syncDir syncs dir so a rename in it survives a crash; it's best effort, as not every platform can
+// sync a directory
+func syncDir(dir string) {
+	d, err := os.Open(dir)
+	if err != nil {
+		return
+	}
+	defer d.Close()
+	d.Sync()
+}
+
+// WithTempDir creates a temporary directory with a name starting with pattern, as os.MkdirTemp does,
+// calls fn with it, and removes it and everything in it when fn returns, returning fn's error
+func WithTempDir(pattern string, fn func(dir string) error) (err error) {
+	dir, err := os.MkdirTemp("", pattern)
+	if err != nil {
+		return kit.WrapError(err, "error creating temporary directory")
+	}
+	defer func() {
+		removeErr := os.RemoveAll(dir)
+		if err == nil && removeErr != nil {
+			err = kit.WrapError(removeErr, "error removing temporary directory %s", dir)
+		}
+	}()
+
+	return fn(dir)
+}
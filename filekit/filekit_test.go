@@ -0,0 +1,100 @@
+package filekit
+
+import (
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteFileAtomic(t *testing.T) {
+	t.Run("writes_the_file", func(t *testing.T) {
+		thePath := filepath.Join(t.TempDir(), "schema.sql")
+
+		err := WriteFileAtomic(thePath, []byte("create table users;"), 0o640)
+
+		require.NoError(t, err)
+		contents, _ := os.ReadFile(thePath)
+		assert.Equal(t, "create table users;", string(contents))
+		info, _ := os.Stat(thePath)
+		assert.Equal(t, os.FileMode(0o640), info.Mode().Perm())
+	})
+
+	t.Run("replaces_an_existing_file", func(t *testing.T) {
+		thePath := filepath.Join(t.TempDir(), "schema.sql")
+		os.WriteFile(thePath, []byte("old"), 0o644)
+
+		err := WriteFileAtomic(thePath, []byte("new"), 0o644)
+
+		require.NoError(t, err)
+		contents, _ := os.ReadFile(thePath)
+		assert.Equal(t, "new", string(contents))
+	})
+
+	t.Run("returns_an_error_when_the_directory_does_not_exist", func(t *testing.T) {
+		err := WriteFileAtomic(filepath.Join(t.TempDir(), "missing", "schema.sql"), []byte("x"), 0o644)
+
+		assert.ErrorContains(t, err, "error creating temporary file")
+	})
+}
+
+func TestWriteAtomic(t *testing.T) {
+	t.Run("leaves_the_file_and_no_temporary_file_when_write_fails", func(t *testing.T) {
+		theDir := t.TempDir()
+		thePath := filepath.Join(theDir, "schema.sql")
+		os.WriteFile(thePath, []byte("old"), 0o644)
+		theErr := errors.New("the error")
+
+		err := WriteAtomic(thePath, 0o644, func(w io.Writer) error {
+			io.WriteString(w, "part")
+			return theErr
+		})
+
+		assert.ErrorIs(t, err, theErr)
+		contents, _ := os.ReadFile(thePath)
+		assert.Equal(t, "old", string(contents))
+		entries, _ := os.ReadDir(theDir)
+		assert.Len(t, entries, 1)
+	})
+
+	t.Run("writes_what_write_writes", func(t *testing.T) {
+		thePath := filepath.Join(t.TempDir(), "dump.sql")
+
+		err := WriteAtomic(thePath, 0o644, func(w io.Writer) error {
+			io.WriteString(w, "one;")
+			io.WriteString(w, "two;")
+			return nil
+		})
+
+		require.NoError(t, err)
+		contents, _ := os.ReadFile(thePath)
+		assert.Equal(t, "one;two;", string(contents))
+	})
+}
+
+func TestWithTempDir(t *testing.T) {
+	t.Run("removes_the_directory_after_fn_returns", func(t *testing.T) {
+		var theDir string
+
+		err := WithTempDir("filekit-test-*", func(dir string) error {
+			theDir = dir
+			return os.WriteFile(filepath.Join(dir, "file"), []byte("x"), 0o644)
+		})
+
+		require.NoError(t, err)
+		_, err = os.Stat(theDir)
+		assert.True(t, os.IsNotExist(err))
+	})
+
+	t.Run("returns_the_error_from_fn", func(t *testing.T) {
+		theErr := errors.New("the error")
+
+		err := WithTempDir("filekit-test-*", func(string) error { return theErr })
+
+		assert.ErrorIs(t, err, theErr)
+	})
+}
@@ -0,0 +1,66 @@
+package filekit
+
+import (
+	"context"
+	"errors"
+	"os"
+	"time"
+
+	"github.com/half-ogre/go-kit/backoffkit"
+	"github.com/half-ogre/go-kit/kit"
+)
+
+// ErrLocked is returned by TryLock when another process or Lock holds the lock
+var ErrLocked = errors.New("file is locked")
+
+// lockBackoff is how long Lock waits between tries
+var lockBackoff = backoffkit.FullJitter(backoffkit.Exponential(10*time.Millisecond, 500*time.Millisecond))
+
+// Lock is an exclusive advisory lock on a file, e.g. so two runs of a CLI don't write the same output
+// at once. Advisory locks only keep out processes that lock the file too. Locks aren't supported on
+// every platform; where they aren't TryLock and Lock return errors.ErrUnsupported.
+type Lock struct {
+	file *os.File
+}
+
+// TryLock takes the lock on the file at path, creating it if needed, or returns ErrLocked at once if
+// it's held
+func TryLock(path string) (*Lock, error) {
+	file, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0o644)
+	if err != nil {
+		return nil, kit.WrapError(err, "error opening lock file %s", path)
+	}
+
+	err = tryLockFile(file)
+	if err != nil {
+		file.Close()
+		if errors.Is(err, ErrLocked) {
+			return nil, ErrLocked
+		}
+		return nil, kit.WrapError(err, "error locking %s", path)
+	}
+
+	return &Lock{file: file}, nil
+}
+
+// AcquireLock takes the lock on the file at path, creating it if needed, waiting until it's released if
+// it's held, or returns ctx's error if ctx is done first
+func AcquireLock(ctx context.Context, path string) (*Lock, error) {
+	for range backoffkit.Attempts(ctx, lockBackoff, 0) {
+		lock, err := TryLock(path)
+		if !errors.Is(err, ErrLocked) {
+			return lock, err
+		}
+	}
+	return nil, kit.WrapError(ctx.Err(), "error waiting for lock on %s", path)
+}
+
+// Unlock releases the lock; it leaves the file in place, as removing it would race with other
+// processes opening it
+func (l *Lock) Unlock() error {
+	err := unlockFile(l.file)
+	if closeErr := l.file.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
@@ -0,0 +1,16 @@
+//go:build !(linux || darwin || freebsd)
+
+package filekit
+
+import (
+	"errors"
+	"os"
+)
+
+func tryLockFile(*os.File) error {
+	return errors.ErrUnsupported
+}
+
+func unlockFile(*os.File) error {
+	return errors.ErrUnsupported
+}
@@ -0,0 +1,62 @@
+//go:build linux || darwin || freebsd
+
+package filekit
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTryLock(t *testing.T) {
+	t.Run("returns_err_locked_when_the_lock_is_held", func(t *testing.T) {
+		thePath := filepath.Join(t.TempDir(), "gokit.lock")
+		lock, err := TryLock(thePath)
+		require.NoError(t, err)
+		defer lock.Unlock()
+
+		_, err = TryLock(thePath)
+
+		assert.ErrorIs(t, err, ErrLocked)
+	})
+
+	t.Run("takes_the_lock_after_it_is_released", func(t *testing.T) {
+		thePath := filepath.Join(t.TempDir(), "gokit.lock")
+		lock, _ := TryLock(thePath)
+		require.NoError(t, lock.Unlock())
+
+		lock, err := TryLock(thePath)
+
+		require.NoError(t, err)
+		assert.NoError(t, lock.Unlock())
+	})
+}
+
+func TestAcquireLock(t *testing.T) {
+	t.Run("waits_until_the_lock_is_released", func(t *testing.T) {
+		thePath := filepath.Join(t.TempDir(), "gokit.lock")
+		held, _ := TryLock(thePath)
+		time.AfterFunc(50*time.Millisecond, func() { held.Unlock() })
+
+		lock, err := AcquireLock(context.Background(), thePath)
+
+		require.NoError(t, err)
+		assert.NoError(t, lock.Unlock())
+	})
+
+	t.Run("returns_the_context_error_when_done_while_waiting", func(t *testing.T) {
+		thePath := filepath.Join(t.TempDir(), "gokit.lock")
+		held, _ := TryLock(thePath)
+		defer held.Unlock()
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+
+		_, err := AcquireLock(ctx, thePath)
+
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+	})
+}
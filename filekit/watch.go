@@ -0,0 +1,197 @@
+package filekit
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+
+	"github.com/half-ogre/go-kit/kit"
+)
+
+// WatchOption configures a Watcher
+type WatchOption func(*watchConfig)
+
+type watchConfig struct {
+	debounce time.Duration
+	onError  func(err error)
+}
+
+// WithWatchDebounce sets how long a Watcher waits after a change for more before calling back with all
+// of them, so saving many files at once is one callback; the default is 100ms
+func WithWatchDebounce(debounce time.Duration) WatchOption {
+	return func(c *watchConfig) {
+		c.debounce = debounce
+	}
+}
+
+// WithWatchErrorHandler sets a function a Watcher calls with the errors it gets while watching, e.g. to
+// log them; they're dropped by default
+func WithWatchErrorHandler(onError func(err error)) WatchOption {
+	return func(c *watchConfig) {
+		c.onError = onError
+	}
+}
+
+// Watcher watches a file, or a directory and every directory under it, and calls back with the paths
+// that changed
+type Watcher struct {
+	fsw       *fsnotify.Watcher
+	file      string
+	onChange  func(paths []string)
+	config    watchConfig
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+// NewWatcher starts watching path and calls onChange with the sorted paths that changed once no more
+// have changed for the debounce period. When path is a directory, directories under it are watched
+// too, including ones created later and ones symlinked to, except hidden directories. When path is a
+// file, its directory is watched for it, so replacing the file, as editors and WriteFileAtomic do, is a
+// change too. onChange is called on the watcher's goroutine, one call at a time.
+func NewWatcher(path string, onChange func(paths []string), options ...WatchOption) (*Watcher, error) {
+	config := watchConfig{
+		debounce: 100 * time.Millisecond,
+		onError:  func(error) {},
+	}
+	for _, option := range options {
+		option(&config)
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, kit.WrapError(err, "error watching %s", path)
+	}
+
+	fsw, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, kit.WrapError(err, "error creating watcher")
+	}
+
+	w := &Watcher{
+		fsw:      fsw,
+		onChange: onChange,
+		config:   config,
+		done:     make(chan struct{}),
+	}
+
+	if info.IsDir() {
+		err = w.addDirs(path)
+	} else {
+		w.file = filepath.Clean(path)
+		err = fsw.Add(filepath.Dir(w.file))
+	}
+	if err != nil {
+		fsw.Close()
+		return nil, kit.WrapError(err, "error watching %s", path)
+	}
+
+	go w.run()
+	return w, nil
+}
+
+// Close stops watching; onChange isn't called after it returns unless it's running. It's safe to call
+// more than once.
+func (w *Watcher) Close() error {
+	var err error
+	w.closeOnce.Do(func() {
+		close(w.done)
+		err = w.fsw.Close()
+	})
+	return err
+}
+
+// addDirs watches dir and the directories under it, following symlinks to directories and skipping
+// hidden ones
+func (w *Watcher) addDirs(dir string) error {
+	return filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if path != dir && strings.HasPrefix(d.Name(), ".") {
+				return filepath.SkipDir
+			}
+			return w.fsw.Add(path)
+		}
+		if d.Type()&os.ModeSymlink != 0 {
+			resolved, err := filepath.EvalSymlinks(path)
+			if err != nil {
+				return nil
+			}
+			info, err := os.Stat(resolved)
+			if err != nil {
+				return nil
+			}
+			if info.IsDir() {
+				return w.addDirs(resolved)
+			}
+		}
+		return nil
+	})
+}
+
+func (w *Watcher) run() {
+	changed := map[string]struct{}{}
+	var timer *time.Timer
+	var fire <-chan time.Time
+
+	for {
+		select {
+		case <-w.done:
+			if timer != nil {
+				timer.Stop()
+			}
+			return
+
+		case event, ok := <-w.fsw.Events:
+			if !ok {
+				return
+			}
+			if w.file != "" && filepath.Clean(event.Name) != w.file {
+				continue
+			}
+			if w.file == "" && event.Has(fsnotify.Create) {
+				if info, err := os.Stat(event.Name); err == nil && info.IsDir() {
+					if err := w.addDirs(event.Name); err != nil {
+						w.config.onError(err)
+					}
+				}
+			}
+
+			changed[event.Name] = struct{}{}
+			if timer == nil {
+				timer = time.NewTimer(w.config.debounce)
+			} else {
+				timer.Reset(w.config.debounce)
+			}
+			fire = timer.C
+
+		case <-fire:
+			fire = nil
+			select {
+			case <-w.done:
+				return
+			default:
+			}
+			paths := make([]string, 0, len(changed))
+			for path := range changed {
+				paths = append(paths, path)
+			}
+			slices.Sort(paths)
+			clear(changed)
+			w.onChange(paths)
+
+		case err, ok := <-w.fsw.Errors:
+			if !ok {
+				return
+			}
+			w.config.onError(err)
+		}
+	}
+}
@@ -0,0 +1,86 @@
+package filekit
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// watch starts a watcher on path and returns a channel of the paths it calls back with
+func watch(t *testing.T, path string) <-chan []string {
+	changes := make(chan []string, 10)
+	w, err := NewWatcher(path, func(paths []string) { changes <- paths }, WithWatchDebounce(20*time.Millisecond))
+	require.NoError(t, err)
+	t.Cleanup(func() { w.Close() })
+	return changes
+}
+
+func nextChange(t *testing.T, changes <-chan []string) []string {
+	select {
+	case paths := <-changes:
+		return paths
+	case <-time.After(2 * time.Second):
+		t.Fatal("timed out waiting for a change")
+		return nil
+	}
+}
+
+func TestWatcher(t *testing.T) {
+	t.Run("calls_back_once_with_the_changed_files", func(t *testing.T) {
+		theDir := t.TempDir()
+		changes := watch(t, theDir)
+
+		os.WriteFile(filepath.Join(theDir, "a.txt"), []byte("a"), 0o644)
+		os.WriteFile(filepath.Join(theDir, "b.txt"), []byte("b"), 0o644)
+
+		assert.Equal(t, []string{filepath.Join(theDir, "a.txt"), filepath.Join(theDir, "b.txt")}, nextChange(t, changes))
+	})
+
+	t.Run("watches_directories_created_after_it_starts", func(t *testing.T) {
+		theDir := t.TempDir()
+		changes := watch(t, theDir)
+		os.Mkdir(filepath.Join(theDir, "sub"), 0o755)
+		nextChange(t, changes)
+
+		os.WriteFile(filepath.Join(theDir, "sub", "c.txt"), []byte("c"), 0o644)
+
+		assert.Contains(t, nextChange(t, changes), filepath.Join(theDir, "sub", "c.txt"))
+	})
+
+	t.Run("sees_a_watched_file_replaced_atomically", func(t *testing.T) {
+		theDir := t.TempDir()
+		thePath := filepath.Join(theDir, ".env")
+		os.WriteFile(thePath, []byte("A=1"), 0o644)
+		changes := watch(t, thePath)
+
+		os.WriteFile(filepath.Join(theDir, "other"), []byte("x"), 0o644)
+		WriteFileAtomic(thePath, []byte("A=2"), 0o644)
+
+		assert.Equal(t, []string{thePath}, nextChange(t, changes))
+	})
+
+	t.Run("returns_an_error_when_the_path_does_not_exist", func(t *testing.T) {
+		_, err := NewWatcher(filepath.Join(t.TempDir(), "missing"), func([]string) {})
+
+		assert.ErrorIs(t, err, os.ErrNotExist)
+	})
+
+	t.Run("does_not_call_back_after_close", func(t *testing.T) {
+		theDir := t.TempDir()
+		called := make(chan struct{}, 1)
+		w, _ := NewWatcher(theDir, func([]string) { called <- struct{}{} }, WithWatchDebounce(20*time.Millisecond))
+
+		os.WriteFile(filepath.Join(theDir, "a.txt"), []byte("a"), 0o644)
+		w.Close()
+
+		select {
+		case <-called:
+			t.Fatal("called back after close")
+		case <-time.After(100 * time.Millisecond):
+		}
+	})
+}
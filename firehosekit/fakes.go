@@ -0,0 +1,18 @@
+package firehosekit
+
+import (
+	"context"
+)
+
+// FakeFirehose is a Firehose for tests
+type FakeFirehose struct {
+	PutRecordBatchFake func(ctx context.Context, deliveryStreamName string, records [][]byte) ([]RecordResult, error)
+}
+
+func (f *FakeFirehose) PutRecordBatch(ctx context.Context, deliveryStreamName string, records [][]byte) ([]RecordResult, error) {
+	if f.PutRecordBatchFake != nil {
+		return f.PutRecordBatchFake(ctx, deliveryStreamName, records)
+	} else {
+		panic("PutRecordBatch fake not implemented")
+	}
+}
@@ -0,0 +1,55 @@
+// Package firehosekit delivers records to Amazon Data Firehose. A Producer buffers records, e.g.
+// analytics events from echokit or ginkit middleware, and sends them in batches with PutRecordBatch
+// when a batch is full or every flush interval, retrying the records Firehose rejects.
+//
+// The AWS SDK's Firehose client isn't a dependency of this module, so producers use the small
+// Firehose interface instead. Adapting *firehose.Client to it takes a few lines: wrap each record in a
+// types.Record and return a RecordResult for each of the output's RequestResponses.
+package firehosekit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/half-ogre/go-kit/logkit"
+)
+
+var logger = logkit.Logger("firehosekit")
+
+// The limits of one PutRecordBatch request
+const (
+	MaxBatchRecords = 500
+	MaxBatchBytes   = 4 * 1024 * 1024
+	MaxRecordBytes  = 1000 * 1024
+)
+
+// ErrRecordTooLarge is returned by Put when a record, after compression, is over MaxRecordBytes
+var ErrRecordTooLarge = errors.New("record is too large")
+
+// ErrProducerClosed is returned by Put and Flush after Close
+var ErrProducerClosed = errors.New("producer is closed")
+
+// Firehose is the part of the Firehose API producers use
+type Firehose interface {
+	// PutRecordBatch sends records to the delivery stream and returns a result for each, in order
+	PutRecordBatch(ctx context.Context, deliveryStreamName string, records [][]byte) ([]RecordResult, error)
+}
+
+// RecordResult is the result of putting one record; ErrorCode is empty if the record was accepted
+type RecordResult struct {
+	ErrorCode    string
+	ErrorMessage string
+}
+
+// BatchError is returned when records are still rejected after retries. Records holds them, as sent,
+// e.g. to write to a dead-letter store.
+type BatchError struct {
+	Records      [][]byte
+	ErrorCode    string
+	ErrorMessage string
+}
+
+func (e *BatchError) Error() string {
+	return fmt.Sprintf("%d records rejected: %s: %s", len(e.Records), e.ErrorCode, e.ErrorMessage)
+}
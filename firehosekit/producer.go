@@ -0,0 +1,341 @@
+package firehosekit
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/half-ogre/go-kit/kit"
+)
+
+// Compression is how a producer compresses each record
+type Compression int
+
+const (
+	CompressionNone Compression = iota
+	// CompressionGzip gzips each record. Firehose concatenates records into its destination objects,
+	// and concatenated gzip members are a valid gzip file, so S3 objects read as one gzip file.
+	CompressionGzip
+)
+
+// ProducerOption configures NewProducer
+type ProducerOption func(*producerConfig)
+
+type producerConfig struct {
+	maxRecords    int
+	maxBytes      int
+	flushInterval time.Duration
+	compression   Compression
+	maxRetries    int
+	backoff       time.Duration
+	errorHandler  func(err error)
+}
+
+// WithMaxBatchRecords sets how many records a batch holds before it's sent; the default and most is
+// MaxBatchRecords
+func WithMaxBatchRecords(maxRecords int) ProducerOption {
+	return func(c *producerConfig) {
+		c.maxRecords = min(max(maxRecords, 1), MaxBatchRecords)
+	}
+}
+
+// WithMaxBatchBytes sets how many bytes of records a batch holds before it's sent; the default and most
+// is MaxBatchBytes
+func WithMaxBatchBytes(maxBytes int) ProducerOption {
+	return func(c *producerConfig) {
+		c.maxBytes = min(max(maxBytes, 1), MaxBatchBytes)
+	}
+}
+
+// WithFlushInterval sets how often buffered records are sent even if the batch isn't full; the default
+// is 1s
+func WithFlushInterval(interval time.Duration) ProducerOption {
+	return func(c *producerConfig) {
+		c.flushInterval = interval
+	}
+}
+
+// WithCompression sets how each record is compressed; the default is CompressionNone
+func WithCompression(compression Compression) ProducerOption {
+	return func(c *producerConfig) {
+		c.compression = compression
+	}
+}
+
+// WithRetries sets how many times records Firehose rejects, e.g. when the stream is throttled, are sent
+// again and the initial backoff between attempts, which doubles after each retry; the default is 3
+// retries starting at 100ms. Failed requests aren't retried here: the AWS SDK client already retries
+// them.
+func WithRetries(maxRetries int, backoff time.Duration) ProducerOption {
+	return func(c *producerConfig) {
+		c.maxRetries = maxRetries
+		c.backoff = backoff
+	}
+}
+
+// WithErrorHandler sets what's done with errors sending batches that nobody waits for, i.e. those
+// sent when full or at the flush interval; the default logs them
+func WithErrorHandler(handler func(err error)) ProducerOption {
+	return func(c *producerConfig) {
+		c.errorHandler = handler
+	}
+}
+
+// Producer buffers records and sends them to a Firehose delivery stream in batches. A batch is sent
+// when it reaches the maximum records or bytes, or at the flush interval, one batch at a time. It's
+// safe for concurrent use; call Close at shutdown to send what's buffered.
+type Producer struct {
+	firehose   Firehose
+	streamName string
+	config     producerConfig
+
+	mu          sync.Mutex
+	buffer      [][]byte
+	bufferBytes int
+	closed      bool
+	enqueuing   sync.WaitGroup
+
+	batches chan batch
+	closing chan struct{}
+	stopped chan struct{}
+}
+
+// batch is records for the sending goroutine; sent, if not nil, receives the result
+type batch struct {
+	records [][]byte
+	sent    chan error
+}
+
+// NewProducer returns a producer sending records to the delivery stream named streamName
+func NewProducer(firehose Firehose, streamName string, options ...ProducerOption) *Producer {
+	config := producerConfig{
+		maxRecords:    MaxBatchRecords,
+		maxBytes:      MaxBatchBytes,
+		flushInterval: time.Second,
+		maxRetries:    3,
+		backoff:       100 * time.Millisecond,
+		errorHandler: func(err error) {
+			logger.Error("error sending firehose records", "delivery_stream", streamName, "error", err)
+		},
+	}
+	for _, option := range options {
+		option(&config)
+	}
+
+	p := &Producer{
+		firehose:   firehose,
+		streamName: streamName,
+		config:     config,
+		batches:    make(chan batch),
+		closing:    make(chan struct{}),
+		stopped:    make(chan struct{}),
+	}
+	go p.run()
+	return p
+}
+
+// Put buffers a record, compressing it first if the producer compresses records. If the buffer is full
+// it's handed to be sent, which waits, until ctx is done, for the batch before it to be sent.
+func (p *Producer) Put(ctx context.Context, data []byte) error {
+	record, err := p.compress(data)
+	if err != nil {
+		return err
+	}
+	if len(record) > MaxRecordBytes {
+		return ErrRecordTooLarge
+	}
+
+	p.mu.Lock()
+	if p.closed {
+		p.mu.Unlock()
+		return ErrProducerClosed
+	}
+	var full [][][]byte
+	if len(p.buffer) > 0 && p.bufferBytes+len(record) > p.config.maxBytes {
+		full = append(full, p.takeBuffer())
+	}
+	p.buffer = append(p.buffer, record)
+	p.bufferBytes += len(record)
+	if len(p.buffer) >= p.config.maxRecords || p.bufferBytes >= p.config.maxBytes {
+		full = append(full, p.takeBuffer())
+	}
+	if len(full) == 0 {
+		p.mu.Unlock()
+		return nil
+	}
+	p.enqueuing.Add(1)
+	p.mu.Unlock()
+	defer p.enqueuing.Done()
+
+	for _, records := range full {
+		select {
+		case p.batches <- batch{records: records}:
+		case <-ctx.Done():
+			return kit.WrapError(ctx.Err(), "error handing %d records to be sent", len(records))
+		}
+	}
+	return nil
+}
+
+// PutJSON marshals v as JSON and puts it as a record ending in a newline, so the records Firehose
+// writes to its destination are newline-delimited JSON
+func (p *Producer) PutJSON(ctx context.Context, v any) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return kit.WrapError(err, "error marshaling record")
+	}
+	return p.Put(ctx, append(data, '\n'))
+}
+
+// Flush sends the buffered records, after any batches already handed to be sent, and returns the
+// error sending them, e.g. a *BatchError
+func (p *Producer) Flush(ctx context.Context) error {
+	p.mu.Lock()
+	if p.closed {
+		p.mu.Unlock()
+		return ErrProducerClosed
+	}
+	records := p.takeBuffer()
+	p.mu.Unlock()
+
+	return p.sendAndWait(ctx, records)
+}
+
+// Close stops buffering records, sends the buffered records, and stops the producer. It returns the
+// error sending the buffered records, or ctx's error if ctx is done first, in which case records may
+// be lost.
+func (p *Producer) Close(ctx context.Context) error {
+	p.mu.Lock()
+	if p.closed {
+		p.mu.Unlock()
+		return nil
+	}
+	p.closed = true
+	records := p.takeBuffer()
+	p.mu.Unlock()
+
+	// Puts that took a full buffer before the producer closed still hand it to be sent
+	p.enqueuing.Wait()
+	err := p.sendAndWait(ctx, records)
+	close(p.closing)
+
+	select {
+	case <-p.stopped:
+	case <-ctx.Done():
+		if err == nil {
+			err = ctx.Err()
+		}
+	}
+	return err
+}
+
+// sendAndWait hands records to be sent and waits for them to be; with no records it still waits for
+// the batches handed before it
+func (p *Producer) sendAndWait(ctx context.Context, records [][]byte) error {
+	sent := make(chan error, 1)
+	select {
+	case p.batches <- batch{records: records, sent: sent}:
+	case <-ctx.Done():
+		return kit.WrapError(ctx.Err(), "error handing %d records to be sent", len(records))
+	}
+
+	select {
+	case err := <-sent:
+		return err
+	case <-ctx.Done():
+		return kit.WrapError(ctx.Err(), "error waiting for %d records to be sent", len(records))
+	}
+}
+
+// run sends batches one at a time, and the buffer at each flush interval, until the producer closes
+func (p *Producer) run() {
+	defer close(p.stopped)
+
+	ticker := time.NewTicker(p.config.flushInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-p.closing:
+			return
+		case b := <-p.batches:
+			err := p.send(context.Background(), b.records)
+			if b.sent != nil {
+				b.sent <- err
+			} else if err != nil {
+				p.config.errorHandler(err)
+			}
+		case <-ticker.C:
+			p.mu.Lock()
+			records := p.takeBuffer()
+			p.mu.Unlock()
+			if err := p.send(context.Background(), records); err != nil {
+				p.config.errorHandler(err)
+			}
+		}
+	}
+}
+
+// send puts records, retrying those Firehose rejects with backoff
+func (p *Producer) send(ctx context.Context, records [][]byte) error {
+	if len(records) == 0 {
+		return nil
+	}
+
+	pending := records
+	backoff := p.config.backoff
+	for attempt := 0; ; attempt++ {
+		results, err := p.firehose.PutRecordBatch(ctx, p.streamName, pending)
+		if err != nil {
+			return kit.WrapError(err, "error putting %d records to %s", len(pending), p.streamName)
+		}
+
+		var rejected [][]byte
+		var lastResult RecordResult
+		for i, result := range results {
+			if i < len(pending) && result.ErrorCode != "" {
+				rejected = append(rejected, pending[i])
+				lastResult = result
+			}
+		}
+		if len(rejected) == 0 {
+			return nil
+		}
+		if attempt >= p.config.maxRetries {
+			return &BatchError{Records: rejected, ErrorCode: lastResult.ErrorCode, ErrorMessage: lastResult.ErrorMessage}
+		}
+
+		logger.WarnContext(ctx, "retrying rejected firehose records", "delivery_stream", p.streamName, "records", len(rejected), "error_code", lastResult.ErrorCode, "attempt", attempt+1)
+
+		time.Sleep(backoff)
+		backoff *= 2
+		pending = rejected
+	}
+}
+
+// takeBuffer returns the buffered records and empties the buffer; p.mu must be held
+func (p *Producer) takeBuffer() [][]byte {
+	records := p.buffer
+	p.buffer = nil
+	p.bufferBytes = 0
+	return records
+}
+
+func (p *Producer) compress(data []byte) ([]byte, error) {
+	if p.config.compression != CompressionGzip {
+		return data, nil
+	}
+
+	var compressed bytes.Buffer
+	writer := gzip.NewWriter(&compressed)
+	if _, err := writer.Write(data); err != nil {
+		return nil, kit.WrapError(err, "error compressing record")
+	}
+	if err := writer.Close(); err != nil {
+		return nil, kit.WrapError(err, "error compressing record")
+	}
+	return compressed.Bytes(), nil
+}
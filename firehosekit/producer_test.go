@@ -0,0 +1,221 @@
+package firehosekit
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"errors"
+	"io"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// recordingFirehose returns a fake recording the batches it's sent, accepting every record
+func recordingFirehose() (*FakeFirehose, func() [][][]byte) {
+	var mu sync.Mutex
+	var batches [][][]byte
+	fake := &FakeFirehose{
+		PutRecordBatchFake: func(ctx context.Context, deliveryStreamName string, records [][]byte) ([]RecordResult, error) {
+			mu.Lock()
+			defer mu.Unlock()
+			batches = append(batches, records)
+			return make([]RecordResult, len(records)), nil
+		},
+	}
+	return fake, func() [][][]byte {
+		mu.Lock()
+		defer mu.Unlock()
+		return batches
+	}
+}
+
+func TestProducer(t *testing.T) {
+	t.Run("sends_buffered_records_on_flush", func(t *testing.T) {
+		var actualStreamName string
+		var actualRecords [][]byte
+		fake := &FakeFirehose{
+			PutRecordBatchFake: func(ctx context.Context, deliveryStreamName string, records [][]byte) ([]RecordResult, error) {
+				actualStreamName = deliveryStreamName
+				actualRecords = records
+				return make([]RecordResult, len(records)), nil
+			},
+		}
+		producer := NewProducer(fake, "theStream", WithFlushInterval(time.Hour))
+		defer producer.Close(context.Background())
+
+		assert.NoError(t, producer.Put(context.Background(), []byte("aRecord")))
+		assert.NoError(t, producer.Put(context.Background(), []byte("anotherRecord")))
+		err := producer.Flush(context.Background())
+
+		assert.NoError(t, err)
+		assert.Equal(t, "theStream", actualStreamName)
+		assert.Equal(t, [][]byte{[]byte("aRecord"), []byte("anotherRecord")}, actualRecords)
+	})
+
+	t.Run("sends_a_batch_when_it_reaches_the_maximum_records", func(t *testing.T) {
+		fake, batches := recordingFirehose()
+		producer := NewProducer(fake, "theStream", WithMaxBatchRecords(2), WithFlushInterval(time.Hour))
+
+		for _, record := range []string{"a", "b", "c"} {
+			assert.NoError(t, producer.Put(context.Background(), []byte(record)))
+		}
+		assert.NoError(t, producer.Close(context.Background()))
+
+		assert.Equal(t, [][][]byte{{[]byte("a"), []byte("b")}, {[]byte("c")}}, batches())
+	})
+
+	t.Run("sends_a_batch_before_it_would_exceed_the_maximum_bytes", func(t *testing.T) {
+		fake, batches := recordingFirehose()
+		producer := NewProducer(fake, "theStream", WithMaxBatchBytes(5), WithFlushInterval(time.Hour))
+
+		for _, record := range []string{"aaa", "bb", "cc"} {
+			assert.NoError(t, producer.Put(context.Background(), []byte(record)))
+		}
+		assert.NoError(t, producer.Close(context.Background()))
+
+		assert.Equal(t, [][][]byte{{[]byte("aaa"), []byte("bb")}, {[]byte("cc")}}, batches())
+	})
+
+	t.Run("sends_buffered_records_at_the_flush_interval", func(t *testing.T) {
+		fake, batches := recordingFirehose()
+		producer := NewProducer(fake, "theStream", WithFlushInterval(10*time.Millisecond))
+		defer producer.Close(context.Background())
+
+		assert.NoError(t, producer.Put(context.Background(), []byte("aRecord")))
+
+		assert.Eventually(t, func() bool { return len(batches()) == 1 }, time.Second, 5*time.Millisecond)
+		assert.Equal(t, [][]byte{[]byte("aRecord")}, batches()[0])
+	})
+
+	t.Run("puts_json_records_ending_in_a_newline", func(t *testing.T) {
+		fake, batches := recordingFirehose()
+		producer := NewProducer(fake, "theStream", WithFlushInterval(time.Hour))
+
+		err := producer.PutJSON(context.Background(), map[string]string{"event": "theEvent"})
+		assert.NoError(t, producer.Close(context.Background()))
+
+		assert.NoError(t, err)
+		assert.Equal(t, [][][]byte{{[]byte("{\"event\":\"theEvent\"}\n")}}, batches())
+	})
+
+	t.Run("gzips_each_record", func(t *testing.T) {
+		fake, batches := recordingFirehose()
+		producer := NewProducer(fake, "theStream", WithCompression(CompressionGzip), WithFlushInterval(time.Hour))
+
+		assert.NoError(t, producer.Put(context.Background(), []byte("aRecord")))
+		assert.NoError(t, producer.Put(context.Background(), []byte("anotherRecord")))
+		assert.NoError(t, producer.Close(context.Background()))
+
+		// Concatenated records read as one gzip stream
+		reader, err := gzip.NewReader(bytes.NewReader(bytes.Join(batches()[0], nil)))
+		assert.NoError(t, err)
+		actual, err := io.ReadAll(reader)
+		assert.NoError(t, err)
+		assert.Equal(t, "aRecordanotherRecord", string(actual))
+	})
+
+	t.Run("retries_rejected_records", func(t *testing.T) {
+		var sent [][][]byte
+		fake := &FakeFirehose{
+			PutRecordBatchFake: func(ctx context.Context, deliveryStreamName string, records [][]byte) ([]RecordResult, error) {
+				sent = append(sent, records)
+				results := make([]RecordResult, len(records))
+				if len(sent) == 1 {
+					results[1] = RecordResult{ErrorCode: "ServiceUnavailableException", ErrorMessage: "Slow down."}
+				}
+				return results, nil
+			},
+		}
+		producer := NewProducer(fake, "theStream", WithRetries(3, time.Millisecond), WithFlushInterval(time.Hour))
+		defer producer.Close(context.Background())
+
+		assert.NoError(t, producer.Put(context.Background(), []byte("a")))
+		assert.NoError(t, producer.Put(context.Background(), []byte("b")))
+		err := producer.Flush(context.Background())
+
+		assert.NoError(t, err)
+		assert.Equal(t, [][][]byte{{[]byte("a"), []byte("b")}, {[]byte("b")}}, sent)
+	})
+
+	t.Run("returns_a_batch_error_when_records_are_still_rejected_after_retries", func(t *testing.T) {
+		attempts := 0
+		fake := &FakeFirehose{
+			PutRecordBatchFake: func(ctx context.Context, deliveryStreamName string, records [][]byte) ([]RecordResult, error) {
+				attempts++
+				return []RecordResult{{ErrorCode: "ServiceUnavailableException", ErrorMessage: "Slow down."}}, nil
+			},
+		}
+		producer := NewProducer(fake, "theStream", WithRetries(2, time.Millisecond), WithFlushInterval(time.Hour))
+		defer producer.Close(context.Background())
+
+		assert.NoError(t, producer.Put(context.Background(), []byte("a")))
+		err := producer.Flush(context.Background())
+
+		var batchErr *BatchError
+		assert.ErrorAs(t, err, &batchErr)
+		assert.Equal(t, [][]byte{[]byte("a")}, batchErr.Records)
+		assert.EqualError(t, err, "1 records rejected: ServiceUnavailableException: Slow down.")
+		assert.Equal(t, 3, attempts)
+	})
+
+	t.Run("returns_the_error_from_put_record_batch", func(t *testing.T) {
+		fake := &FakeFirehose{
+			PutRecordBatchFake: func(ctx context.Context, deliveryStreamName string, records [][]byte) ([]RecordResult, error) {
+				return nil, errors.New("the put error")
+			},
+		}
+		producer := NewProducer(fake, "theStream", WithFlushInterval(time.Hour))
+		defer producer.Close(context.Background())
+
+		assert.NoError(t, producer.Put(context.Background(), []byte("a")))
+		err := producer.Flush(context.Background())
+
+		assert.EqualError(t, err, "error putting 1 records to theStream: the put error")
+	})
+
+	t.Run("passes_errors_sending_full_batches_to_the_error_handler", func(t *testing.T) {
+		fake := &FakeFirehose{
+			PutRecordBatchFake: func(ctx context.Context, deliveryStreamName string, records [][]byte) ([]RecordResult, error) {
+				return nil, errors.New("the put error")
+			},
+		}
+		handled := make(chan error, 1)
+		producer := NewProducer(fake, "theStream", WithMaxBatchRecords(1), WithFlushInterval(time.Hour), WithErrorHandler(func(err error) { handled <- err }))
+		defer producer.Close(context.Background())
+
+		assert.NoError(t, producer.Put(context.Background(), []byte("a")))
+
+		select {
+		case err := <-handled:
+			assert.EqualError(t, err, "error putting 1 records to theStream: the put error")
+		case <-time.After(time.Second):
+			t.Fatal("the error handler wasn't called")
+		}
+	})
+
+	t.Run("returns_an_error_for_a_record_that_is_too_large", func(t *testing.T) {
+		producer := NewProducer(&FakeFirehose{}, "theStream")
+		defer producer.Close(context.Background())
+
+		err := producer.Put(context.Background(), make([]byte, MaxRecordBytes+1))
+
+		assert.ErrorIs(t, err, ErrRecordTooLarge)
+	})
+
+	t.Run("sends_buffered_records_on_close_and_rejects_records_after", func(t *testing.T) {
+		fake, batches := recordingFirehose()
+		producer := NewProducer(fake, "theStream", WithFlushInterval(time.Hour))
+
+		assert.NoError(t, producer.Put(context.Background(), []byte("a")))
+		err := producer.Close(context.Background())
+
+		assert.NoError(t, err)
+		assert.Equal(t, [][][]byte{{[]byte("a")}}, batches())
+		assert.ErrorIs(t, producer.Put(context.Background(), []byte("b")), ErrProducerClosed)
+		assert.ErrorIs(t, producer.Flush(context.Background()), ErrProducerClosed)
+		assert.NoError(t, producer.Close(context.Background()))
+	})
+}
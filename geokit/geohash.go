@@ -0,0 +1,185 @@
+package geokit
+
+import (
+	"fmt"
+	"strings"
+)
+
+const geohashAlphabet = "0123456789bcdefghjkmnpqrstuvwxyz"
+
+// MaxGeohashPrecision is the longest geohash Encode produces, about 3.7cm by 1.9cm
+const MaxGeohashPrecision = 12
+
+// EncodeGeohash returns the geohash of p with precision characters, between 1 and MaxGeohashPrecision
+func EncodeGeohash(p Point, precision int) (string, error) {
+	if precision < 1 || precision > MaxGeohashPrecision {
+		return "", fmt.Errorf("geohash precision must be between 1 and %d, got %d", MaxGeohashPrecision, precision)
+	}
+	if err := p.Validate(); err != nil {
+		return "", err
+	}
+
+	minLat, maxLat := -90.0, 90.0
+	minLon, maxLon := -180.0, 180.0
+	evenBit := true
+
+	var hash strings.Builder
+	for hash.Len() < precision {
+		index := 0
+		for bit := 4; bit >= 0; bit-- {
+			if evenBit {
+				mid := (minLon + maxLon) / 2
+				if p.Lon >= mid {
+					index |= 1 << bit
+					minLon = mid
+				} else {
+					maxLon = mid
+				}
+			} else {
+				mid := (minLat + maxLat) / 2
+				if p.Lat >= mid {
+					index |= 1 << bit
+					minLat = mid
+				} else {
+					maxLat = mid
+				}
+			}
+			evenBit = !evenBit
+		}
+		hash.WriteByte(geohashAlphabet[index])
+	}
+
+	return hash.String(), nil
+}
+
+// DecodeGeohash returns the box covered by hash. Geohashes are case-insensitive.
+func DecodeGeohash(hash string) (Box, error) {
+	if hash == "" {
+		return Box{}, fmt.Errorf("geohash cannot be empty")
+	}
+
+	box := Box{MinLat: -90, MaxLat: 90, MinLon: -180, MaxLon: 180}
+	evenBit := true
+
+	for _, c := range strings.ToLower(hash) {
+		index := strings.IndexRune(geohashAlphabet, c)
+		if index < 0 {
+			return Box{}, fmt.Errorf("invalid geohash %q", hash)
+		}
+
+		for bit := 4; bit >= 0; bit-- {
+			set := index&(1<<bit) != 0
+			if evenBit {
+				mid := (box.MinLon + box.MaxLon) / 2
+				if set {
+					box.MinLon = mid
+				} else {
+					box.MaxLon = mid
+				}
+			} else {
+				mid := (box.MinLat + box.MaxLat) / 2
+				if set {
+					box.MinLat = mid
+				} else {
+					box.MaxLat = mid
+				}
+			}
+			evenBit = !evenBit
+		}
+	}
+
+	return box, nil
+}
+
+// DecodeGeohashCenter returns the center of the box covered by hash
+func DecodeGeohashCenter(hash string) (Point, error) {
+	box, err := DecodeGeohash(hash)
+	if err != nil {
+		return Point{}, err
+	}
+	return Point{Lat: (box.MinLat + box.MaxLat) / 2, Lon: (box.MinLon + box.MaxLon) / 2}, nil
+}
+
+// CoverBox returns the geohash prefixes whose cells together cover box, at the finest precision that
+// needs no more than maxCells prefixes. Query each prefix (e.g. with begins_with on a geohash sort key)
+// and filter the results with Box.Contains, since the cells extend past the box.
+func CoverBox(box Box, maxCells int) ([]string, error) {
+	if maxCells < 1 {
+		return nil, fmt.Errorf("max cells must be greater than 0, got %d", maxCells)
+	}
+	if err := (Point{Lat: box.MinLat, Lon: box.MinLon}).Validate(); err != nil {
+		return nil, err
+	}
+	if err := (Point{Lat: box.MaxLat, Lon: box.MaxLon}).Validate(); err != nil {
+		return nil, err
+	}
+	if box.MinLat > box.MaxLat {
+		return nil, fmt.Errorf("min latitude %v is greater than max latitude %v", box.MinLat, box.MaxLat)
+	}
+
+	// Precision 1 can always be covered, by at most all 32 cells
+	best, _ := coverAtPrecision(box, 1, len(geohashAlphabet))
+	for precision := 2; precision <= MaxGeohashPrecision; precision++ {
+		cells, ok := coverAtPrecision(box, precision, maxCells)
+		if !ok {
+			break
+		}
+		best = cells
+	}
+
+	return best, nil
+}
+
+// coverAtPrecision returns the cells at precision that cover box, or false if there are more than maxCells
+func coverAtPrecision(box Box, precision int, maxCells int) ([]string, bool) {
+	if box.CrossesAntimeridian() {
+		west, ok := coverAtPrecision(Box{MinLat: box.MinLat, MaxLat: box.MaxLat, MinLon: box.MinLon, MaxLon: 180}, precision, maxCells)
+		if !ok {
+			return nil, false
+		}
+		east, ok := coverAtPrecision(Box{MinLat: box.MinLat, MaxLat: box.MaxLat, MinLon: -180, MaxLon: box.MaxLon}, precision, maxCells-len(west))
+		if !ok {
+			return nil, false
+		}
+		return append(west, east...), true
+	}
+
+	latStep, lonStep := cellSize(precision)
+	latCells := int(cellIndex(box.MaxLat, -90, latStep, 90)-cellIndex(box.MinLat, -90, latStep, 90)) + 1
+	lonCells := int(cellIndex(box.MaxLon, -180, lonStep, 180)-cellIndex(box.MinLon, -180, lonStep, 180)) + 1
+	if latCells*lonCells > maxCells {
+		return nil, false
+	}
+
+	cells := make([]string, 0, latCells*lonCells)
+	for i := 0; i < latCells; i++ {
+		lat := -90 + (float64(cellIndex(box.MinLat, -90, latStep, 90)+i)+0.5)*latStep
+		for j := 0; j < lonCells; j++ {
+			lon := -180 + (float64(cellIndex(box.MinLon, -180, lonStep, 180)+j)+0.5)*lonStep
+			// The cell centers are always in range, so encoding cannot fail
+			hash, _ := EncodeGeohash(Point{Lat: lat, Lon: lon}, precision)
+			cells = append(cells, hash)
+		}
+	}
+
+	return cells, true
+}
+
+// cellSize returns the latitude and longitude span of a cell at precision
+func cellSize(precision int) (float64, float64) {
+	bits := precision * 5
+	lonBits := (bits + 1) / 2
+	latBits := bits / 2
+	return 180 / float64(uint64(1)<<latBits), 360 / float64(uint64(1)<<lonBits)
+}
+
+// cellIndex returns which cell of size step, counting from origin, contains value; a value on the
+// upper edge belongs to the last cell
+func cellIndex(value float64, origin float64, step float64, max float64) int {
+	index := int((value - origin) / step)
+	last := int((max-origin)/step) - 1
+	if index > last {
+		return last
+	}
+	return index
+}
@@ -0,0 +1,138 @@
+package geokit
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEncodeGeohash(t *testing.T) {
+	t.Run("encodes_known_points", func(t *testing.T) {
+		hash, err := EncodeGeohash(Point{Lat: 57.64911, Lon: 10.40744}, 11)
+		assert.NoError(t, err)
+		assert.Equal(t, "u4pruydqqvj", hash)
+
+		hash, err = EncodeGeohash(Point{Lat: 42.6, Lon: -5.6}, 5)
+		assert.NoError(t, err)
+		assert.Equal(t, "ezs42", hash)
+	})
+
+	t.Run("returns_a_prefix_for_a_lower_precision", func(t *testing.T) {
+		hash, err := EncodeGeohash(Point{Lat: 57.64911, Lon: 10.40744}, 4)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "u4pr", hash)
+	})
+
+	t.Run("returns_an_error_for_an_invalid_precision", func(t *testing.T) {
+		_, err := EncodeGeohash(theParis, 13)
+
+		assert.EqualError(t, err, "geohash precision must be between 1 and 12, got 13")
+	})
+
+	t.Run("returns_an_error_for_an_invalid_point", func(t *testing.T) {
+		_, err := EncodeGeohash(Point{Lat: 100}, 5)
+
+		assert.EqualError(t, err, "latitude 100 is out of range")
+	})
+}
+
+func TestDecodeGeohash(t *testing.T) {
+	t.Run("decodes_the_cell_of_a_known_hash", func(t *testing.T) {
+		box, err := DecodeGeohash("ezs42")
+
+		assert.NoError(t, err)
+		assert.InDelta(t, 42.583, box.MinLat, 0.001)
+		assert.InDelta(t, 42.627, box.MaxLat, 0.001)
+		assert.InDelta(t, -5.625, box.MinLon, 0.001)
+		assert.InDelta(t, -5.581, box.MaxLon, 0.001)
+	})
+
+	t.Run("is_case_insensitive", func(t *testing.T) {
+		lower, err := DecodeGeohash("ezs42")
+		assert.NoError(t, err)
+
+		upper, err := DecodeGeohash("EZS42")
+
+		assert.NoError(t, err)
+		assert.Equal(t, lower, upper)
+	})
+
+	t.Run("returns_an_error_for_an_invalid_character", func(t *testing.T) {
+		_, err := DecodeGeohash("ezs4a")
+
+		assert.EqualError(t, err, `invalid geohash "ezs4a"`)
+	})
+
+	t.Run("returns_an_error_for_an_empty_hash", func(t *testing.T) {
+		_, err := DecodeGeohash("")
+
+		assert.EqualError(t, err, "geohash cannot be empty")
+	})
+}
+
+func TestDecodeGeohashCenter(t *testing.T) {
+	t.Run("round_trips_a_point_within_the_cell_size", func(t *testing.T) {
+		hash, err := EncodeGeohash(theParis, 9)
+		assert.NoError(t, err)
+
+		center, err := DecodeGeohashCenter(hash)
+
+		assert.NoError(t, err)
+		assert.InDelta(t, theParis.Lat, center.Lat, 0.0001)
+		assert.InDelta(t, theParis.Lon, center.Lon, 0.0001)
+	})
+}
+
+func TestCoverBox(t *testing.T) {
+	t.Run("covers_the_box_with_no_more_than_max_cells", func(t *testing.T) {
+		box := BoundingBox(theParis, 2_000)
+
+		prefixes, err := CoverBox(box, 9)
+
+		assert.NoError(t, err)
+		assert.NotEmpty(t, prefixes)
+		assert.LessOrEqual(t, len(prefixes), 9)
+		for _, corner := range []Point{{box.MinLat, box.MinLon}, {box.MinLat, box.MaxLon}, {box.MaxLat, box.MinLon}, {box.MaxLat, box.MaxLon}, theParis} {
+			hash, err := EncodeGeohash(corner, len(prefixes[0]))
+			assert.NoError(t, err)
+			assert.Contains(t, prefixes, hash)
+		}
+	})
+
+	t.Run("uses_a_single_cell_for_a_box_inside_one_cell", func(t *testing.T) {
+		cell, err := DecodeGeohash("u09tvw")
+		assert.NoError(t, err)
+		box := Box{MinLat: cell.MinLat + 0.0001, MinLon: cell.MinLon + 0.0001, MaxLat: cell.MinLat + 0.0002, MaxLon: cell.MinLon + 0.0002}
+
+		prefixes, err := CoverBox(box, 1)
+
+		assert.NoError(t, err)
+		assert.Len(t, prefixes, 1)
+		assert.Contains(t, prefixes[0], "u09tvw")
+	})
+
+	t.Run("covers_both_sides_of_the_antimeridian", func(t *testing.T) {
+		box := Box{MinLat: -1, MinLon: 179, MaxLat: 1, MaxLon: -179}
+
+		prefixes, err := CoverBox(box, 16)
+
+		assert.NoError(t, err)
+		west, _ := EncodeGeohash(Point{Lat: 0, Lon: 179.5}, len(prefixes[0]))
+		east, _ := EncodeGeohash(Point{Lat: 0, Lon: -179.5}, len(prefixes[0]))
+		assert.Contains(t, prefixes, west)
+		assert.Contains(t, prefixes, east)
+	})
+
+	t.Run("returns_an_error_for_an_invalid_max_cells", func(t *testing.T) {
+		_, err := CoverBox(Box{}, 0)
+
+		assert.EqualError(t, err, "max cells must be greater than 0, got 0")
+	})
+
+	t.Run("returns_an_error_for_an_inverted_latitude_range", func(t *testing.T) {
+		_, err := CoverBox(Box{MinLat: 10, MaxLat: 5}, 4)
+
+		assert.EqualError(t, err, "min latitude 10 is greater than max latitude 5")
+	})
+}
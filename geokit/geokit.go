@@ -0,0 +1,99 @@
+// Package geokit provides geohash encoding, bounding-box and distance math, and helpers for
+// location-scoped lookups in DynamoDB (geohash sort-key prefixes) and Postgres (plain SQL, no PostGIS).
+package geokit
+
+import (
+	"fmt"
+	"math"
+)
+
+// earthRadiusMeters is the mean radius of the Earth
+const earthRadiusMeters = 6371008.8
+
+// Point is a latitude and longitude in degrees
+type Point struct {
+	Lat float64 `json:"lat"`
+	Lon float64 `json:"lon"`
+}
+
+// Validate returns an error if the latitude is outside [-90, 90] or the longitude outside [-180, 180]
+func (p Point) Validate() error {
+	if math.IsNaN(p.Lat) || p.Lat < -90 || p.Lat > 90 {
+		return fmt.Errorf("latitude %v is out of range", p.Lat)
+	}
+	if math.IsNaN(p.Lon) || p.Lon < -180 || p.Lon > 180 {
+		return fmt.Errorf("longitude %v is out of range", p.Lon)
+	}
+	return nil
+}
+
+// Box is a latitude/longitude bounding box. A box whose MinLon is greater than its MaxLon crosses the
+// antimeridian.
+type Box struct {
+	MinLat float64 `json:"min_lat"`
+	MinLon float64 `json:"min_lon"`
+	MaxLat float64 `json:"max_lat"`
+	MaxLon float64 `json:"max_lon"`
+}
+
+// CrossesAntimeridian reports whether the box wraps from 180 to -180 longitude
+func (b Box) CrossesAntimeridian() bool {
+	return b.MinLon > b.MaxLon
+}
+
+// Contains reports whether p is inside the box, edges included
+func (b Box) Contains(p Point) bool {
+	if p.Lat < b.MinLat || p.Lat > b.MaxLat {
+		return false
+	}
+	if b.CrossesAntimeridian() {
+		return p.Lon >= b.MinLon || p.Lon <= b.MaxLon
+	}
+	return p.Lon >= b.MinLon && p.Lon <= b.MaxLon
+}
+
+// Distance returns the great-circle distance between a and b in meters
+func Distance(a Point, b Point) float64 {
+	lat1 := a.Lat * math.Pi / 180
+	lat2 := b.Lat * math.Pi / 180
+	dLat := (b.Lat - a.Lat) * math.Pi / 180
+	dLon := (b.Lon - a.Lon) * math.Pi / 180
+
+	h := math.Sin(dLat/2)*math.Sin(dLat/2) + math.Cos(lat1)*math.Cos(lat2)*math.Sin(dLon/2)*math.Sin(dLon/2)
+	return 2 * earthRadiusMeters * math.Asin(math.Min(1, math.Sqrt(h)))
+}
+
+// BoundingBox returns the smallest box containing every point within radiusMeters of center. Use it to
+// narrow a radius search, then filter the results with Distance.
+func BoundingBox(center Point, radiusMeters float64) Box {
+	dLat := radiusMeters / earthRadiusMeters * 180 / math.Pi
+
+	box := Box{MinLat: center.Lat - dLat, MaxLat: center.Lat + dLat}
+	if box.MinLat <= -90 || box.MaxLat >= 90 {
+		// The circle covers a pole, so it spans every longitude
+		box.MinLat = math.Max(box.MinLat, -90)
+		box.MaxLat = math.Min(box.MaxLat, 90)
+		box.MinLon = -180
+		box.MaxLon = 180
+		return box
+	}
+
+	dLon := math.Asin(math.Min(1, math.Sin(radiusMeters/earthRadiusMeters)/math.Cos(center.Lat*math.Pi/180))) * 180 / math.Pi
+	box.MinLon = normalizeLon(center.Lon - dLon)
+	box.MaxLon = normalizeLon(center.Lon + dLon)
+	if dLon >= 180 {
+		box.MinLon = -180
+		box.MaxLon = 180
+	}
+	return box
+}
+
+func normalizeLon(lon float64) float64 {
+	for lon < -180 {
+		lon += 360
+	}
+	for lon > 180 {
+		lon -= 360
+	}
+	return lon
+}
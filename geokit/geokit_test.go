@@ -0,0 +1,91 @@
+package geokit
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	theParis  = Point{Lat: 48.8566, Lon: 2.3522}
+	theLondon = Point{Lat: 51.5074, Lon: -0.1278}
+)
+
+func TestPointValidate(t *testing.T) {
+	t.Run("accepts_a_point_in_range", func(t *testing.T) {
+		assert.NoError(t, theParis.Validate())
+	})
+
+	t.Run("returns_an_error_for_an_out_of_range_latitude", func(t *testing.T) {
+		assert.EqualError(t, Point{Lat: 91}.Validate(), "latitude 91 is out of range")
+	})
+
+	t.Run("returns_an_error_for_an_out_of_range_longitude", func(t *testing.T) {
+		assert.EqualError(t, Point{Lon: -181}.Validate(), "longitude -181 is out of range")
+	})
+
+	t.Run("returns_an_error_for_nan", func(t *testing.T) {
+		assert.Error(t, Point{Lat: math.NaN()}.Validate())
+	})
+}
+
+func TestBoxContains(t *testing.T) {
+	t.Run("contains_points_inside_and_on_the_edge", func(t *testing.T) {
+		box := Box{MinLat: 10, MinLon: 20, MaxLat: 11, MaxLon: 21}
+
+		assert.True(t, box.Contains(Point{Lat: 10.5, Lon: 20.5}))
+		assert.True(t, box.Contains(Point{Lat: 10, Lon: 21}))
+		assert.False(t, box.Contains(Point{Lat: 11.5, Lon: 20.5}))
+		assert.False(t, box.Contains(Point{Lat: 10.5, Lon: 19.5}))
+	})
+
+	t.Run("contains_points_on_both_sides_of_the_antimeridian", func(t *testing.T) {
+		box := Box{MinLat: -10, MinLon: 170, MaxLat: 10, MaxLon: -170}
+
+		assert.True(t, box.CrossesAntimeridian())
+		assert.True(t, box.Contains(Point{Lat: 0, Lon: 175}))
+		assert.True(t, box.Contains(Point{Lat: 0, Lon: -175}))
+		assert.False(t, box.Contains(Point{Lat: 0, Lon: 0}))
+	})
+}
+
+func TestDistance(t *testing.T) {
+	t.Run("returns_the_great_circle_distance", func(t *testing.T) {
+		assert.InDelta(t, 343_500, Distance(theParis, theLondon), 1_000)
+	})
+
+	t.Run("returns_zero_for_the_same_point", func(t *testing.T) {
+		assert.Equal(t, 0.0, Distance(theParis, theParis))
+	})
+
+	t.Run("measures_across_the_antimeridian", func(t *testing.T) {
+		assert.InDelta(t, 222_390, Distance(Point{Lat: 0, Lon: 179}, Point{Lat: 0, Lon: -179}), 100)
+	})
+}
+
+func TestBoundingBox(t *testing.T) {
+	t.Run("contains_the_circle", func(t *testing.T) {
+		box := BoundingBox(theParis, 10_000)
+
+		assert.True(t, box.Contains(theParis))
+		assert.InDelta(t, 10_000, Distance(theParis, Point{Lat: box.MaxLat, Lon: theParis.Lon}), 1)
+		assert.InDelta(t, 10_000, Distance(theParis, Point{Lat: box.MinLat, Lon: theParis.Lon}), 1)
+		assert.Less(t, box.MinLon, theParis.Lon)
+		assert.Greater(t, box.MaxLon, theParis.Lon)
+		assert.False(t, box.Contains(theLondon))
+	})
+
+	t.Run("wraps_across_the_antimeridian", func(t *testing.T) {
+		box := BoundingBox(Point{Lat: 0, Lon: 179.95}, 20_000)
+
+		assert.True(t, box.CrossesAntimeridian())
+		assert.True(t, box.Contains(Point{Lat: 0, Lon: -179.95}))
+	})
+
+	t.Run("spans_every_longitude_around_a_pole", func(t *testing.T) {
+		box := BoundingBox(Point{Lat: 89.99, Lon: 0}, 10_000)
+
+		assert.Equal(t, Box{MinLat: box.MinLat, MinLon: -180, MaxLat: 90, MaxLon: 180}, box)
+	})
+}
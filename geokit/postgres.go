@@ -0,0 +1,53 @@
+package geokit
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// BoxCondition returns a SQL condition matching rows whose latitude and longitude columns are inside
+// box, and its arguments, numbered from firstArg (e.g. 1 for $1). With a B-tree index on the columns it
+// needs no PostGIS. Column names may be qualified, e.g. "places.lat".
+func BoxCondition(latColumn string, lonColumn string, box Box, firstArg int) (string, []any) {
+	lat := quoteColumn(latColumn)
+	lon := quoteColumn(lonColumn)
+
+	lonOperator := "AND"
+	if box.CrossesAntimeridian() {
+		lonOperator = "OR"
+	}
+
+	condition := fmt.Sprintf("%s BETWEEN $%d AND $%d AND (%s >= $%d %s %s <= $%d)",
+		lat, firstArg, firstArg+1, lon, firstArg+2, lonOperator, lon, firstArg+3)
+	return condition, []any{box.MinLat, box.MaxLat, box.MinLon, box.MaxLon}
+}
+
+// DistanceExpression returns a SQL expression for the great-circle distance in meters between the
+// row's latitude and longitude columns and the point in arguments $latArg and $lonArg
+func DistanceExpression(latColumn string, lonColumn string, latArg int, lonArg int) string {
+	lat := quoteColumn(latColumn)
+	lon := quoteColumn(lonColumn)
+
+	return fmt.Sprintf(
+		"(2 * %.1f * asin(least(1, sqrt(power(sin(radians(%s - $%d::float8) / 2), 2) + cos(radians($%d::float8)) * cos(radians(%s)) * power(sin(radians(%s - $%d::float8) / 2), 2)))))",
+		earthRadiusMeters, lat, latArg, latArg, lat, lon, lonArg)
+}
+
+// RadiusCondition returns a SQL condition matching rows within radiusMeters of center, and its
+// arguments numbered from firstArg. The bounding box condition lets an index narrow the rows before
+// the distance is computed.
+func RadiusCondition(latColumn string, lonColumn string, center Point, radiusMeters float64, firstArg int) (string, []any) {
+	boxCondition, args := BoxCondition(latColumn, lonColumn, BoundingBox(center, radiusMeters), firstArg)
+
+	latArg := firstArg + len(args)
+	distance := DistanceExpression(latColumn, lonColumn, latArg, latArg+1)
+	args = append(args, center.Lat, center.Lon, radiusMeters)
+
+	return fmt.Sprintf("%s AND %s <= $%d", boxCondition, distance, latArg+2), args
+}
+
+func quoteColumn(column string) string {
+	return pgx.Identifier(strings.Split(column, ".")).Sanitize()
+}
@@ -0,0 +1,44 @@
+package geokit
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBoxCondition(t *testing.T) {
+	t.Run("returns_a_between_condition_and_its_arguments", func(t *testing.T) {
+		condition, args := BoxCondition("lat", "places.lon", Box{MinLat: 1, MinLon: 2, MaxLat: 3, MaxLon: 4}, 2)
+
+		assert.Equal(t, `"lat" BETWEEN $2 AND $3 AND ("places"."lon" >= $4 AND "places"."lon" <= $5)`, condition)
+		assert.Equal(t, []any{1.0, 3.0, 2.0, 4.0}, args)
+	})
+
+	t.Run("matches_either_side_of_the_antimeridian", func(t *testing.T) {
+		condition, _ := BoxCondition("lat", "lon", Box{MinLat: -1, MinLon: 179, MaxLat: 1, MaxLon: -179}, 1)
+
+		assert.Equal(t, `"lat" BETWEEN $1 AND $2 AND ("lon" >= $3 OR "lon" <= $4)`, condition)
+	})
+}
+
+func TestDistanceExpression(t *testing.T) {
+	t.Run("uses_the_given_arguments", func(t *testing.T) {
+		expression := DistanceExpression("lat", "lon", 5, 6)
+
+		assert.Contains(t, expression, `radians("lat" - $5::float8)`)
+		assert.Contains(t, expression, `radians("lon" - $6::float8)`)
+		assert.Contains(t, expression, "2 * 6371008.8 * asin")
+	})
+}
+
+func TestRadiusCondition(t *testing.T) {
+	t.Run("combines_the_bounding_box_and_distance", func(t *testing.T) {
+		condition, args := RadiusCondition("lat", "lon", theParis, 1_000, 1)
+
+		assert.Contains(t, condition, `"lat" BETWEEN $1 AND $2`)
+		assert.Contains(t, condition, `radians("lat" - $5::float8)`)
+		assert.Contains(t, condition, "<= $7")
+		assert.Len(t, args, 7)
+		assert.Equal(t, []any{theParis.Lat, theParis.Lon, 1_000.0}, args[4:])
+	})
+}
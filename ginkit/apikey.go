@@ -0,0 +1,69 @@
+package ginkit
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/half-ogre/go-kit/apikeykit"
+	"github.com/half-ogre/go-kit/authzkit"
+)
+
+const apiKeyContextKey = "github.com/half-ogre/go-kit/ginkit/api_key"
+
+// APIKeyAuthentication returns a middleware that verifies an apikeykit key in the X-API-Key header or
+// an Authorization bearer token against store. It aborts with 401 Unauthorized for a key that isn't
+// valid, and lets requests without a key through, for RequirePolicy with APIKeyPrincipal to reject.
+func APIKeyAuthentication(store apikeykit.Store) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := apikeykit.FromRequest(c.Request)
+		if key == "" {
+			c.Next()
+			return
+		}
+
+		verified, err := apikeykit.Verify(c.Request.Context(), store, key)
+		if errors.Is(err, apikeykit.ErrInvalidKey) || errors.Is(err, apikeykit.ErrKeyExpired) || errors.Is(err, apikeykit.ErrKeyRevoked) {
+			c.AbortWithStatus(http.StatusUnauthorized)
+			return
+		}
+		if err != nil {
+			_ = c.AbortWithError(http.StatusInternalServerError, err)
+			return
+		}
+
+		c.Set(apiKeyContextKey, verified)
+		c.Next()
+	}
+}
+
+// GetAPIKey returns the key APIKeyAuthentication verified for the request, if any
+func GetAPIKey(c *gin.Context) (*apikeykit.Key, bool) {
+	value, ok := c.Get(apiKeyContextKey)
+	if !ok {
+		return nil, false
+	}
+	key, ok := value.(*apikeykit.Key)
+	return key, ok
+}
+
+// APIKeyPrincipal is a PolicyPrincipalFunc for requests authenticated by APIKeyAuthentication: the
+// principal's ID is the key's owner, or "apikey:<id>" without one, its permissions are the key's
+// scopes, and its api_key_id attribute is the key's ID
+func APIKeyPrincipal(c *gin.Context) (authzkit.Principal, bool) {
+	key, ok := GetAPIKey(c)
+	if !ok {
+		return authzkit.Principal{}, false
+	}
+
+	id := key.Owner
+	if id == "" {
+		id = "apikey:" + key.ID
+	}
+	return authzkit.Principal{
+		ID:          id,
+		Permissions: key.Scopes,
+		Attributes:  map[string]any{"api_key_id": key.ID},
+	}, true
+}
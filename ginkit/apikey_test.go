@@ -0,0 +1,68 @@
+package ginkit
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/half-ogre/go-kit/apikeykit"
+	"github.com/half-ogre/go-kit/authzkit"
+)
+
+func TestAPIKeyAuthentication(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	newRouter := func(store apikeykit.Store, principals *[]authzkit.Principal) *gin.Engine {
+		router := gin.New()
+		router.GET("/", APIKeyAuthentication(store), func(c *gin.Context) {
+			principal, ok := APIKeyPrincipal(c)
+			if ok {
+				*principals = append(*principals, principal)
+			}
+			c.Status(http.StatusNoContent)
+		})
+		return router
+	}
+
+	t.Run("sets_the_principal_for_a_valid_key", func(t *testing.T) {
+		store := apikeykit.NewMemoryStore()
+		theKey, key, _ := apikeykit.Issue(context.Background(), store, "theprefix", apikeykit.WithOwner("theOwner"), apikeykit.WithScopes("orders:read"))
+		principals := []authzkit.Principal{}
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set("X-API-Key", theKey)
+		w := httptest.NewRecorder()
+
+		newRouter(store, &principals).ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusNoContent, w.Code)
+		assert.Equal(t, []authzkit.Principal{{ID: "theOwner", Permissions: []string{"orders:read"}, Attributes: map[string]any{"api_key_id": key.ID}}}, principals)
+	})
+
+	t.Run("aborts_with_unauthorized_for_a_revoked_key", func(t *testing.T) {
+		store := apikeykit.NewMemoryStore()
+		theKey, key, _ := apikeykit.Issue(context.Background(), store, "theprefix")
+		_ = apikeykit.Revoke(context.Background(), store, key.ID)
+		principals := []authzkit.Principal{}
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set("Authorization", "Bearer "+theKey)
+		w := httptest.NewRecorder()
+
+		newRouter(store, &principals).ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+		assert.Empty(t, principals)
+	})
+
+	t.Run("lets_a_request_without_a_key_through_without_a_principal", func(t *testing.T) {
+		principals := []authzkit.Principal{}
+		w := httptest.NewRecorder()
+
+		newRouter(apikeykit.NewMemoryStore(), &principals).ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+
+		assert.Equal(t, http.StatusNoContent, w.Code)
+		assert.Empty(t, principals)
+	})
+}
@@ -0,0 +1,114 @@
+package ginkit
+
+import (
+	"log/slog"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/half-ogre/go-kit/internal/audit"
+	"github.com/half-ogre/go-kit/kit"
+)
+
+const (
+	auditActionContextKey   = "github.com/half-ogre/go-kit/ginkit/audit_action"
+	auditResourceContextKey = "github.com/half-ogre/go-kit/ginkit/audit_resource"
+)
+
+// AuditRecord is the audit event written for each request; echokit writes the same schema
+type AuditRecord = audit.Record
+
+// AuditSink receives audit records
+type AuditSink = audit.Sink
+
+// AuditSinkFunc adapts a function to the AuditSink interface
+type AuditSinkFunc = audit.SinkFunc
+
+// AuditOutcome is the result of an audited request
+type AuditOutcome = audit.Outcome
+
+const (
+	AuditOutcomeSuccess = audit.OutcomeSuccess
+	AuditOutcomeDenied  = audit.OutcomeDenied
+	AuditOutcomeFailure = audit.OutcomeFailure
+)
+
+// NewSlogAuditSink returns an AuditSink that logs each record as an "audit" message at INFO
+func NewSlogAuditSink(logger *slog.Logger) AuditSink {
+	return audit.NewSlogSink(logger)
+}
+
+type auditLoggerConfig struct {
+	actor func(c *gin.Context) string
+	clock kit.ClockInterface
+}
+
+type AuditLoggerOption func(*auditLoggerConfig)
+
+// WithAuditActor sets how the actor is found, e.g. from the claims set by an auth middleware
+func WithAuditActor(actor func(c *gin.Context) string) AuditLoggerOption {
+	return func(config *auditLoggerConfig) {
+		config.actor = actor
+	}
+}
+
+// WithAuditClock sets the clock used to timestamp audit records
+func WithAuditClock(clock kit.ClockInterface) AuditLoggerOption {
+	return func(config *auditLoggerConfig) {
+		config.clock = clock
+	}
+}
+
+// AuditLogger returns a middleware that writes an audit record to sink for each request once it has
+// been handled. The action defaults to the method and route (e.g. "DELETE /users/:id") and the resource
+// to the request path; handlers can name them with SetAuditAction and SetAuditResource.
+func AuditLogger(sink AuditSink, options ...AuditLoggerOption) gin.HandlerFunc {
+	config := &auditLoggerConfig{
+		actor: func(c *gin.Context) string { return "" },
+		clock: kit.NewClock(),
+	}
+	for _, option := range options {
+		option(config)
+	}
+
+	return func(c *gin.Context) {
+		c.Next()
+
+		req := c.Request
+		status := c.Writer.Status()
+
+		action := c.GetString(auditActionContextKey)
+		if action == "" {
+			action = req.Method + " " + c.FullPath()
+		}
+		resource := c.GetString(auditResourceContextKey)
+		if resource == "" {
+			resource = req.URL.Path
+		}
+		requestID := req.Header.Get("X-Request-ID")
+		if requestID == "" {
+			requestID = c.Writer.Header().Get("X-Request-ID")
+		}
+
+		audit.Write(req.Context(), sink, AuditRecord{
+			Time:      config.clock.Now(),
+			Actor:     config.actor(c),
+			Action:    action,
+			Resource:  resource,
+			Outcome:   audit.OutcomeForStatus(status),
+			Status:    status,
+			Method:    req.Method,
+			RequestID: requestID,
+			RemoteIP:  c.ClientIP(),
+		})
+	}
+}
+
+// SetAuditAction names the action recorded for the request, e.g. "user.delete"
+func SetAuditAction(c *gin.Context, action string) {
+	c.Set(auditActionContextKey, action)
+}
+
+// SetAuditResource names the resource recorded for the request, e.g. "user/123"
+func SetAuditResource(c *gin.Context, resource string) {
+	c.Set(auditResourceContextKey, resource)
+}
@@ -0,0 +1,102 @@
+package ginkit
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/half-ogre/go-kit/kit"
+)
+
+func newRecordingAuditSink() (AuditSink, *[]AuditRecord) {
+	records := []AuditRecord{}
+	sink := AuditSinkFunc(func(ctx context.Context, record AuditRecord) error {
+		records = append(records, record)
+		return nil
+	})
+	return sink, &records
+}
+
+func TestAuditLogger(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	now := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	clock := kit.NewClock(kit.WithFake(func() time.Time { return now }))
+
+	t.Run("writes_a_record_for_a_successful_request", func(t *testing.T) {
+		sink, records := newRecordingAuditSink()
+		router := gin.New()
+		router.Use(AuditLogger(sink, WithAuditClock(clock), WithAuditActor(func(c *gin.Context) string { return "theActor" })))
+		router.GET("/users/:id", func(c *gin.Context) {
+			c.Status(http.StatusOK)
+		})
+		req := httptest.NewRequest(http.MethodGet, "/users/theID", nil)
+		req.Header.Set("X-Request-ID", "theRequestID")
+		req.RemoteAddr = "192.0.2.1:1234"
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, []AuditRecord{{
+			Time:      now,
+			Actor:     "theActor",
+			Action:    "GET /users/:id",
+			Resource:  "/users/theID",
+			Outcome:   AuditOutcomeSuccess,
+			Status:    http.StatusOK,
+			Method:    http.MethodGet,
+			RequestID: "theRequestID",
+			RemoteIP:  "192.0.2.1",
+		}}, *records)
+	})
+
+	t.Run("uses_the_action_and_resource_set_by_the_handler", func(t *testing.T) {
+		sink, records := newRecordingAuditSink()
+		router := gin.New()
+		router.Use(AuditLogger(sink, WithAuditClock(clock)))
+		router.DELETE("/users/:id", func(c *gin.Context) {
+			SetAuditAction(c, "theAction")
+			SetAuditResource(c, "theResource")
+			c.Status(http.StatusNoContent)
+		})
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodDelete, "/users/theID", nil))
+
+		assert.Equal(t, "theAction", (*records)[0].Action)
+		assert.Equal(t, "theResource", (*records)[0].Resource)
+	})
+
+	t.Run("records_a_denied_outcome_for_a_forbidden_request", func(t *testing.T) {
+		sink, records := newRecordingAuditSink()
+		router := gin.New()
+		router.Use(AuditLogger(sink, WithAuditClock(clock)))
+		router.GET("/", func(c *gin.Context) {
+			c.AbortWithStatus(http.StatusForbidden)
+		})
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+
+		assert.Equal(t, AuditOutcomeDenied, (*records)[0].Outcome)
+		assert.Equal(t, http.StatusForbidden, (*records)[0].Status)
+	})
+
+	t.Run("records_a_failure_outcome_for_a_server_error", func(t *testing.T) {
+		sink, records := newRecordingAuditSink()
+		router := gin.New()
+		router.Use(AuditLogger(sink, WithAuditClock(clock)))
+		router.GET("/", func(c *gin.Context) {
+			c.Status(http.StatusInternalServerError)
+		})
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+
+		assert.Equal(t, AuditOutcomeFailure, (*records)[0].Outcome)
+	})
+}
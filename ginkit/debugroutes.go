@@ -0,0 +1,178 @@
+package ginkit
+
+import (
+	"log/slog"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/half-ogre/go-kit/internal/debugroutes"
+	"github.com/half-ogre/go-kit/kit"
+	"github.com/half-ogre/go-kit/versionkit"
+)
+
+type debugRoutesConfig struct {
+	permission func(c *gin.Context) bool
+	allowedIPs []string
+	config     any
+	levelVar   *slog.LevelVar
+	buildInfo  *versionkit.BuildInfo
+}
+
+type DebugRoutesOption func(*debugRoutesConfig)
+
+// WithDebugPermission requires permission to return true for the request, e.g. by checking the claims
+// set by an auth middleware that runs before the debug routes; otherwise the request gets 403 Forbidden
+func WithDebugPermission(permission func(c *gin.Context) bool) DebugRoutesOption {
+	return func(config *debugRoutesConfig) {
+		config.permission = permission
+	}
+}
+
+// WithDebugAllowedIPs limits the debug routes to callers whose c.ClientIP() is one of ipsOrCIDRs. Call
+// SetTrustedProxies on the engine so forwarded headers cannot be spoofed.
+func WithDebugAllowedIPs(ipsOrCIDRs ...string) DebugRoutesOption {
+	return func(config *debugRoutesConfig) {
+		config.allowedIPs = append(config.allowedIPs, ipsOrCIDRs...)
+	}
+}
+
+// WithDebugConfig sets the value served, with sensitive keys redacted, at /debug/config
+func WithDebugConfig(config any) DebugRoutesOption {
+	return func(c *debugRoutesConfig) {
+		c.config = config
+	}
+}
+
+// WithDebugLevelVar sets the default logger's level (as returned by logkit.SetDefaultLogger) so
+// /debug/loglevel changes it along with the logkit module levels
+func WithDebugLevelVar(levelVar *slog.LevelVar) DebugRoutesOption {
+	return func(config *debugRoutesConfig) {
+		config.levelVar = levelVar
+	}
+}
+
+// WithDebugBuildInfo sets the build info served at /debug/build, e.g. one stamped with
+// versionkit.LDFlags. It defaults to versionkit.GetBuildInfo.
+func WithDebugBuildInfo(buildInfo *versionkit.BuildInfo) DebugRoutesOption {
+	return func(config *debugRoutesConfig) {
+		config.buildInfo = buildInfo
+	}
+}
+
+// RegisterDebugRoutes registers the same debug endpoints as echokit.RegisterDebugRoutes under /debug:
+//   - GET /debug/routes lists the registered routes and the engine's global middleware
+//   - GET /debug/config returns the WithDebugConfig value with sensitive keys redacted
+//   - GET and PUT /debug/loglevel read and set the logkit level spec, e.g. {"level":"info,dynamodbkit=debug"}
+//   - GET /debug/build returns the version, commit, and build date
+//   - /debug/pprof/ serves net/http/pprof
+//
+// Without WithDebugPermission or WithDebugAllowedIPs only loopback callers are allowed, judged by the
+// connection's remote address rather than forwarded headers.
+func RegisterDebugRoutes(r *gin.Engine, options ...DebugRoutesOption) error {
+	config := &debugRoutesConfig{}
+	for _, option := range options {
+		option(config)
+	}
+
+	guards, err := debugGuards(config)
+	if err != nil {
+		return kit.WrapError(err, "failed to register debug routes")
+	}
+
+	g := r.Group(debugroutes.Prefix, guards...)
+
+	g.GET("/routes", func(c *gin.Context) {
+		routes := []debugroutes.Route{}
+		for _, route := range r.Routes() {
+			routes = append(routes, debugroutes.Route{Method: route.Method, Path: route.Path, Handler: route.Handler})
+		}
+		debugroutes.SortRoutes(routes)
+
+		middleware := []string{}
+		for _, handler := range r.Handlers {
+			middleware = append(middleware, debugroutes.FuncName(handler))
+		}
+
+		c.JSON(http.StatusOK, debugroutes.RoutesResponse{Routes: routes, Middleware: middleware})
+	})
+
+	g.GET("/config", func(c *gin.Context) {
+		redacted, err := debugroutes.Redact(config.config)
+		if err != nil {
+			_ = c.Error(kit.WrapError(err, "failed to redact debug config"))
+			c.AbortWithStatus(http.StatusInternalServerError)
+			return
+		}
+		c.JSON(http.StatusOK, redacted)
+	})
+
+	g.GET("/loglevel", func(c *gin.Context) {
+		c.JSON(http.StatusOK, debugroutes.LogLevelRequest{Level: debugroutes.CurrentLogLevel(config.levelVar)})
+	})
+
+	g.PUT("/loglevel", func(c *gin.Context) {
+		var request debugroutes.LogLevelRequest
+		if err := c.ShouldBindJSON(&request); err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"message": "invalid log level request"})
+			return
+		}
+
+		level, err := debugroutes.SetLogLevel(config.levelVar, request.Level)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+			return
+		}
+
+		slog.Info("log level changed", "level", level)
+		c.JSON(http.StatusOK, debugroutes.LogLevelRequest{Level: level})
+	})
+
+	g.GET("/build", func(c *gin.Context) {
+		c.JSON(http.StatusOK, debugroutes.Build(config.buildInfo))
+	})
+
+	g.Any("/pprof/*profile", gin.WrapH(debugroutes.PprofHandler()))
+
+	return nil
+}
+
+func debugGuards(config *debugRoutesConfig) ([]gin.HandlerFunc, error) {
+	guards := []gin.HandlerFunc{}
+
+	// The loopback default checks the connection's peer address: c.ClientIP() trusts forwarded
+	// headers from every proxy unless SetTrustedProxies is called, so a remote caller could claim to
+	// be 127.0.0.1.
+	networks := debugroutes.LoopbackNetworks
+	clientIP := (*gin.Context).RemoteIP
+	if len(config.allowedIPs) > 0 {
+		parsed, err := debugroutes.ParseNetworks(config.allowedIPs)
+		if err != nil {
+			return nil, err
+		}
+		networks = parsed
+		clientIP = (*gin.Context).ClientIP
+	}
+
+	if len(config.allowedIPs) > 0 || config.permission == nil {
+		guards = append(guards, func(c *gin.Context) {
+			if !debugroutes.IPAllowed(clientIP(c), networks) {
+				c.AbortWithStatus(http.StatusForbidden)
+				return
+			}
+			c.Next()
+		})
+	}
+
+	if config.permission != nil {
+		guards = append(guards, func(c *gin.Context) {
+			if !config.permission(c) {
+				c.AbortWithStatus(http.StatusForbidden)
+				return
+			}
+			c.Next()
+		})
+	}
+
+	return guards, nil
+}
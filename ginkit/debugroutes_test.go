@@ -0,0 +1,199 @@
+package ginkit
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/half-ogre/go-kit/versionkit"
+)
+
+func newDebugRequest(method string, path string, body string) *http.Request {
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	req.RemoteAddr = "127.0.0.1:4321"
+	if body != "" {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	return req
+}
+
+func TestRegisterDebugRoutes(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	t.Run("lists_the_registered_routes_and_middleware", func(t *testing.T) {
+		router := gin.New()
+		router.Use(gin.Recovery())
+		router.GET("/users/:id", func(c *gin.Context) {})
+		err := RegisterDebugRoutes(router)
+		assert.NoError(t, err)
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, newDebugRequest(http.MethodGet, "/debug/routes", ""))
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		var body struct {
+			Routes []struct {
+				Method string `json:"method"`
+				Path   string `json:"path"`
+			} `json:"routes"`
+			Middleware []string `json:"middleware"`
+		}
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+		assert.Contains(t, body.Routes, struct {
+			Method string `json:"method"`
+			Path   string `json:"path"`
+		}{Method: http.MethodGet, Path: "/users/:id"})
+		assert.Len(t, body.Middleware, 1)
+		assert.Contains(t, body.Middleware[0], "github.com/gin-gonic/gin.CustomRecoveryWithWriter")
+	})
+
+	t.Run("serves_the_config_with_sensitive_keys_redacted", func(t *testing.T) {
+		router := gin.New()
+		theConfig := map[string]any{
+			"region":   "theRegion",
+			"database": map[string]any{"host": "theHost", "password": "thePassword"},
+		}
+		err := RegisterDebugRoutes(router, WithDebugConfig(theConfig))
+		assert.NoError(t, err)
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, newDebugRequest(http.MethodGet, "/debug/config", ""))
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.JSONEq(t, `{"region":"theRegion","database":{"host":"theHost","password":"[REDACTED]"}}`, w.Body.String())
+	})
+
+	t.Run("sets_and_reads_the_log_level", func(t *testing.T) {
+		router := gin.New()
+		levelVar := new(slog.LevelVar)
+		err := RegisterDebugRoutes(router, WithDebugLevelVar(levelVar))
+		assert.NoError(t, err)
+		putW := httptest.NewRecorder()
+		getW := httptest.NewRecorder()
+
+		router.ServeHTTP(putW, newDebugRequest(http.MethodPut, "/debug/loglevel", `{"level":"error,pgkit=debug"}`))
+		router.ServeHTTP(getW, newDebugRequest(http.MethodGet, "/debug/loglevel", ""))
+
+		assert.Equal(t, http.StatusOK, putW.Code)
+		assert.JSONEq(t, `{"level":"error,pgkit=debug"}`, putW.Body.String())
+		assert.JSONEq(t, `{"level":"error,pgkit=debug"}`, getW.Body.String())
+		assert.Equal(t, slog.LevelError, levelVar.Level())
+	})
+
+	t.Run("rejects_an_invalid_log_level", func(t *testing.T) {
+		router := gin.New()
+		err := RegisterDebugRoutes(router)
+		assert.NoError(t, err)
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, newDebugRequest(http.MethodPut, "/debug/loglevel", `{"level":"loud"}`))
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("serves_the_build_info", func(t *testing.T) {
+		router := gin.New()
+		err := RegisterDebugRoutes(router, WithDebugBuildInfo(&versionkit.BuildInfo{Version: "1.2.3", GitCommit: "abc1234", BuildDate: "2026-01-02"}))
+		assert.NoError(t, err)
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, newDebugRequest(http.MethodGet, "/debug/build", ""))
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), `"version":"1.2.3","git_commit":"abc1234","build_date":"2026-01-02"`)
+	})
+
+	t.Run("serves_pprof", func(t *testing.T) {
+		router := gin.New()
+		err := RegisterDebugRoutes(router)
+		assert.NoError(t, err)
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, newDebugRequest(http.MethodGet, "/debug/pprof/goroutine?debug=1", ""))
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), "goroutine profile")
+	})
+
+	t.Run("forbids_non_loopback_callers_by_default", func(t *testing.T) {
+		router := gin.New()
+		err := RegisterDebugRoutes(router)
+		assert.NoError(t, err)
+		req := newDebugRequest(http.MethodGet, "/debug/routes", "")
+		req.RemoteAddr = "203.0.113.7:4321"
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusForbidden, w.Code)
+	})
+
+	t.Run("forbids_a_remote_caller_spoofing_a_loopback_forwarded_header", func(t *testing.T) {
+		router := gin.New()
+		err := RegisterDebugRoutes(router)
+		assert.NoError(t, err)
+		req := newDebugRequest(http.MethodGet, "/debug/routes", "")
+		req.RemoteAddr = "203.0.113.7:4321"
+		req.Header.Set("X-Forwarded-For", "127.0.0.1")
+		req.Header.Set("X-Real-IP", "127.0.0.1")
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusForbidden, w.Code)
+	})
+
+	t.Run("allows_callers_in_the_allowed_ips", func(t *testing.T) {
+		router := gin.New()
+		err := RegisterDebugRoutes(router, WithDebugAllowedIPs("203.0.113.0/24"))
+		assert.NoError(t, err)
+		req := newDebugRequest(http.MethodGet, "/debug/routes", "")
+		req.RemoteAddr = "203.0.113.7:4321"
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+
+	t.Run("returns_an_error_for_an_invalid_allowed_ip", func(t *testing.T) {
+		router := gin.New()
+
+		err := RegisterDebugRoutes(router, WithDebugAllowedIPs("10.0.0.0/99"))
+
+		assert.EqualError(t, err, `failed to register debug routes: invalid CIDR "10.0.0.0/99"`)
+	})
+
+	t.Run("allows_a_remote_caller_with_the_permission", func(t *testing.T) {
+		router := gin.New()
+		err := RegisterDebugRoutes(router, WithDebugPermission(func(c *gin.Context) bool {
+			return c.GetHeader("X-Debug-Permission") == "thePermission"
+		}))
+		assert.NoError(t, err)
+		req := newDebugRequest(http.MethodGet, "/debug/routes", "")
+		req.RemoteAddr = "203.0.113.7:4321"
+		req.Header.Set("X-Debug-Permission", "thePermission")
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+
+	t.Run("forbids_a_caller_without_the_permission", func(t *testing.T) {
+		router := gin.New()
+		err := RegisterDebugRoutes(router, WithDebugPermission(func(c *gin.Context) bool { return false }))
+		assert.NoError(t, err)
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, newDebugRequest(http.MethodGet, "/debug/routes", ""))
+
+		assert.Equal(t, http.StatusForbidden, w.Code)
+	})
+}
@@ -0,0 +1,18 @@
+package ginkit
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"github.com/half-ogre/go-kit/healthkit"
+)
+
+// RegisterHealthRoutes registers the same health endpoints as echokit.RegisterHealthRoutes:
+//   - GET /livez serves the liveness report, which is up while the process can serve requests
+//   - GET /readyz runs the checks and serves the readiness report, with a 503 when it's down
+//
+// Register the server's Shutdown with a healthkit.Shutdown so /readyz reports down while requests
+// drain.
+func RegisterHealthRoutes(r *gin.Engine, checker *healthkit.Checker) {
+	r.GET("/livez", gin.WrapH(checker.LivenessHandler()))
+	r.GET("/readyz", gin.WrapH(checker.ReadinessHandler()))
+}
@@ -0,0 +1,46 @@
+package ginkit
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/half-ogre/go-kit/healthkit"
+)
+
+func TestRegisterHealthRoutes(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	newChecker := func() *healthkit.Checker {
+		checker := healthkit.NewChecker()
+		checker.Register(healthkit.NewCheck("theCheck", func(ctx context.Context) error { return errors.New("the check error") }))
+		return checker
+	}
+
+	t.Run("serves_liveness_at_livez", func(t *testing.T) {
+		router := gin.New()
+		RegisterHealthRoutes(router, newChecker())
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/livez", nil))
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.JSONEq(t, `{"status":"up","checks":[]}`, w.Body.String())
+	})
+
+	t.Run("serves_readiness_at_readyz", func(t *testing.T) {
+		router := gin.New()
+		RegisterHealthRoutes(router, newChecker())
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+
+		assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+		assert.Contains(t, w.Body.String(), `"error":"the check error"`)
+	})
+}
@@ -0,0 +1,50 @@
+package ginkit
+
+import (
+	"html/template"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/render"
+
+	"github.com/half-ogre/go-kit/i18nkit"
+	"github.com/half-ogre/go-kit/kit"
+)
+
+// Localization returns a middleware that puts an i18nkit localizer for the request's preferred locale
+// in the request's context, for GetLocalizer and LocalizedHTML, and sets the Content-Language header
+// to the locale
+func Localization(catalog *i18nkit.Catalog) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		localizer := catalog.RequestLocalizer(c.Request)
+		i18nkit.SetResponseHeaders(c.Writer.Header(), localizer)
+		c.Request = c.Request.WithContext(i18nkit.WithLocalizer(c.Request.Context(), localizer))
+		c.Next()
+	}
+}
+
+// GetLocalizer returns the localizer Localization put in the request's context, if any
+func GetLocalizer(c *gin.Context) (*i18nkit.Localizer, bool) {
+	return i18nkit.FromContext(c.Request.Context())
+}
+
+// LocalizedHTML renders the template name in tmpl with status, its i18nkit template functions bound
+// to the request's localizer. Gin's HTML renderer can't see the request, so tmpl is parsed with the
+// functions and not set on the engine, e.g.
+//
+//	tmpl := template.Must(template.New("").Funcs(i18nkit.FuncMap(catalog.Localizer())).ParseFS(templates, "*.html"))
+//
+// and is cloned for each request, so it must not be executed directly. Without a localizer in the
+// request's context tmpl is rendered with the functions it was parsed with.
+func LocalizedHTML(c *gin.Context, status int, tmpl *template.Template, name string, data any) {
+	if localizer, ok := GetLocalizer(c); ok {
+		localized, err := tmpl.Clone()
+		if err != nil {
+			_ = c.AbortWithError(http.StatusInternalServerError, kit.WrapError(err, "error cloning template %s", name))
+			return
+		}
+		tmpl = localized.Funcs(i18nkit.FuncMap(localizer))
+	}
+
+	c.Render(status, render.HTML{Template: tmpl, Name: name, Data: data})
+}
@@ -0,0 +1,68 @@
+package ginkit
+
+import (
+	"html/template"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/half-ogre/go-kit/i18nkit"
+)
+
+func TestLocalization(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	catalog := i18nkit.NewCatalog("en")
+	catalog.Add("en", "files", i18nkit.Message{i18nkit.PluralOne: "{count} file", i18nkit.PluralOther: "{count} files"})
+	catalog.Add("pl", "files", i18nkit.Message{i18nkit.PluralOne: "{count} plik", i18nkit.PluralFew: "{count} pliki", i18nkit.PluralOther: "{count} plików"})
+	tmpl := template.Must(template.New("").Funcs(i18nkit.FuncMap(catalog.Localizer())).Parse(`{{ define "files" }}{{ locale }}: {{ tn "files" .Count }}{{ end }}`))
+	newRouter := func(middleware ...gin.HandlerFunc) *gin.Engine {
+		router := gin.New()
+		router.GET("/", append(middleware, func(c *gin.Context) {
+			LocalizedHTML(c, http.StatusOK, tmpl, "files", gin.H{"Count": 3})
+		})...)
+		return router
+	}
+
+	t.Run("renders_in_the_locale_the_request_prefers", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set("Accept-Language", "pl-PL, en;q=0.5")
+		w := httptest.NewRecorder()
+
+		newRouter(Localization(catalog)).ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "pl: 3 pliki", w.Body.String())
+		assert.Equal(t, "pl", w.Header().Get("Content-Language"))
+	})
+
+	t.Run("renders_with_the_parsed_functions_without_the_middleware", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set("Accept-Language", "pl")
+		w := httptest.NewRecorder()
+
+		newRouter().ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "en: 3 files", w.Body.String())
+	})
+
+	t.Run("get_localizer_returns_the_localizer_for_the_lang_cookie", func(t *testing.T) {
+		var locale string
+		router := gin.New()
+		router.GET("/", Localization(catalog), func(c *gin.Context) {
+			localizer, _ := GetLocalizer(c)
+			locale = localizer.Locale()
+			c.Status(http.StatusNoContent)
+		})
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.AddCookie(&http.Cookie{Name: "lang", Value: "pl"})
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, "pl", locale)
+	})
+}
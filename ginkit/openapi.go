@@ -0,0 +1,59 @@
+package ginkit
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/openapi3filter"
+	"github.com/gin-gonic/gin"
+
+	"github.com/half-ogre/go-kit/internal/openapi"
+)
+
+// OpenAPIValidatorOption configures the OpenAPI validation middleware
+type OpenAPIValidatorOption = openapi.ValidatorOption
+
+// LoadOpenAPISpec loads and validates the OpenAPI spec at path, which may be JSON or YAML
+func LoadOpenAPISpec(path string) (*openapi3.T, error) {
+	return openapi.LoadSpec(path)
+}
+
+// WithOpenAPIUnknownRoutesAllowed lets requests that match no operation in the spec, such as health
+// checks, through instead of rejecting them with 404 or 405
+func WithOpenAPIUnknownRoutesAllowed() OpenAPIValidatorOption {
+	return openapi.WithUnknownRoutesAllowed()
+}
+
+// WithOpenAPIAuthenticationFunc validates the spec's security requirements with fn; by default they
+// are ignored, leaving authentication to the authentication middleware
+func WithOpenAPIAuthenticationFunc(fn openapi3filter.AuthenticationFunc) OpenAPIValidatorOption {
+	return openapi.WithAuthenticationFunc(fn)
+}
+
+// OpenAPIValidator returns a middleware that validates each request's path, query, headers, and body
+// against spec before calling the handler. Requests that break the contract are aborted with the same
+// status and {"message": "..."} body as echokit.OpenAPIValidator: 400 for invalid input, 404 or 405
+// for unknown operations.
+func OpenAPIValidator(spec *openapi3.T, options ...OpenAPIValidatorOption) (gin.HandlerFunc, error) {
+	validator, err := openapi.NewValidator(spec, options...)
+	if err != nil {
+		return nil, err
+	}
+
+	return func(c *gin.Context) {
+		err := validator.ValidateRequest(c.Request)
+		var validationErr *openapi.ValidationError
+		if errors.As(err, &validationErr) {
+			_ = c.Error(validationErr.Err)
+			c.AbortWithStatusJSON(validationErr.Status, gin.H{"message": validationErr.Message})
+			return
+		}
+		if err != nil {
+			_ = c.AbortWithError(http.StatusInternalServerError, err)
+			return
+		}
+
+		c.Next()
+	}, nil
+}
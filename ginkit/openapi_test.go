@@ -0,0 +1,101 @@
+package ginkit
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+const openAPITestSpec = `{
+  "openapi": "3.0.3",
+  "info": {"title": "Users", "version": "1.0.0"},
+  "paths": {
+    "/users": {
+      "post": {
+        "requestBody": {
+          "required": true,
+          "content": {"application/json": {"schema": {"type": "object", "required": ["name"], "properties": {"name": {"type": "string"}}}}}
+        },
+        "responses": {"201": {"description": "created"}}
+      }
+    }
+  }
+}`
+
+func newOpenAPITestRouter(t *testing.T, options ...OpenAPIValidatorOption) (*gin.Engine, *bool) {
+	spec, err := openapi3.NewLoader().LoadFromData([]byte(openAPITestSpec))
+	assert.NoError(t, err)
+	middleware, err := OpenAPIValidator(spec, options...)
+	assert.NoError(t, err)
+
+	handlerCalled := false
+	router := gin.New()
+	router.Use(middleware)
+	router.POST("/users", func(c *gin.Context) {
+		handlerCalled = true
+		var body map[string]string
+		_ = c.ShouldBindJSON(&body)
+		c.String(http.StatusCreated, body["name"])
+	})
+	router.GET("/health", func(c *gin.Context) {
+		handlerCalled = true
+		c.Status(http.StatusOK)
+	})
+	return router, &handlerCalled
+}
+
+func TestOpenAPIValidator(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	t.Run("calls_the_handler_with_the_body_when_the_request_is_valid", func(t *testing.T) {
+		router, handlerCalled := newOpenAPITestRouter(t)
+		req := httptest.NewRequest(http.MethodPost, "/users", strings.NewReader(`{"name":"theName"}`))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+
+		assert.True(t, *handlerCalled)
+		assert.Equal(t, http.StatusCreated, w.Code)
+		assert.Equal(t, "theName", w.Body.String())
+	})
+
+	t.Run("aborts_an_invalid_request_with_bad_request", func(t *testing.T) {
+		router, handlerCalled := newOpenAPITestRouter(t)
+		req := httptest.NewRequest(http.MethodPost, "/users", strings.NewReader(`{}`))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+
+		assert.False(t, *handlerCalled)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), `"message":"invalid request body:`)
+	})
+
+	t.Run("aborts_an_unknown_route_with_not_found", func(t *testing.T) {
+		router, handlerCalled := newOpenAPITestRouter(t)
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/health", nil))
+
+		assert.False(t, *handlerCalled)
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		assert.JSONEq(t, `{"message":"no matching operation was found"}`, w.Body.String())
+	})
+
+	t.Run("lets_unknown_routes_through_when_allowed", func(t *testing.T) {
+		router, handlerCalled := newOpenAPITestRouter(t, WithOpenAPIUnknownRoutesAllowed())
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/health", nil))
+
+		assert.True(t, *handlerCalled)
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+}
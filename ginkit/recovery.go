@@ -0,0 +1,48 @@
+package ginkit
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/half-ogre/go-kit/logkit"
+)
+
+// Recovery returns a middleware that recovers panics in later handlers, logs them at ERROR level with
+// the same fields as echokit.PanicLogger (error, stack, method, URI, and request ID), and responds 500
+// Internal Server Error. Use it in place of gin.Recovery. A panic re-panicked by
+// logkit.CaptureAndRepanic is logged with the stack from where it happened.
+func Recovery() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		defer func() {
+			recovered := recover()
+			if recovered == nil {
+				return
+			}
+			// net/http uses ErrAbortHandler to abort a response without logging, so let it through
+			if err, ok := recovered.(error); ok && errors.Is(err, http.ErrAbortHandler) {
+				panic(recovered)
+			}
+
+			panicErr := logkit.CapturePanic(recovered)
+
+			req := c.Request
+			requestID := req.Header.Get("X-Request-ID")
+			if requestID == "" {
+				requestID = c.Writer.Header().Get("X-Request-ID")
+			}
+
+			logkit.LogPanic(req.Context(), panicErr, logkit.PanicRequestAttrs(req.Method, req.RequestURI, requestID)...)
+
+			_ = c.Error(panicErr)
+			if c.Writer.Written() {
+				c.Abort()
+				return
+			}
+			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"message": http.StatusText(http.StatusInternalServerError)})
+		}()
+
+		c.Next()
+	}
+}
@@ -0,0 +1,112 @@
+package ginkit
+
+import (
+	"bytes"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/half-ogre/go-kit/logkit"
+)
+
+func setRecoveryTestLogger(t *testing.T) *bytes.Buffer {
+	var logBuf bytes.Buffer
+	previous := slog.Default()
+	slog.SetDefault(slog.New(slog.NewJSONHandler(&logBuf, nil)))
+	t.Cleanup(func() { slog.SetDefault(previous) })
+	return &logBuf
+}
+
+func panickingHandler(c *gin.Context) {
+	panic("thePanic")
+}
+
+func TestRecovery(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	t.Run("logs_the_panic_and_responds_with_an_internal_server_error", func(t *testing.T) {
+		logBuf := setRecoveryTestLogger(t)
+		router := gin.New()
+		router.Use(Recovery())
+		router.GET("/things", panickingHandler)
+		req := httptest.NewRequest(http.MethodGet, "/things?id=1", nil)
+		req.Header.Set("X-Request-ID", "theRequestID")
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+		assert.JSONEq(t, `{"message":"Internal Server Error"}`, w.Body.String())
+		logOutput := logBuf.String()
+		assert.Contains(t, logOutput, `"level":"ERROR"`)
+		assert.Contains(t, logOutput, `"msg":"panic recovered"`)
+		assert.Contains(t, logOutput, `"error":"thePanic"`)
+		assert.Contains(t, logOutput, `"method":"GET"`)
+		assert.Contains(t, logOutput, `"uri":"/things?id=1"`)
+		assert.Contains(t, logOutput, `"request_id":"theRequestID"`)
+		assert.Contains(t, logOutput, "panickingHandler")
+	})
+
+	t.Run("logs_the_stack_of_a_captured_panic", func(t *testing.T) {
+		logBuf := setRecoveryTestLogger(t)
+		router := gin.New()
+		router.Use(Recovery())
+		router.GET("/things", func(c *gin.Context) {
+			defer logkit.CaptureAndRepanic()
+			panickingHandler(c)
+		})
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/things", nil))
+
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+		assert.Contains(t, logBuf.String(), `"error":"thePanic"`)
+		assert.Contains(t, logBuf.String(), "panickingHandler")
+	})
+
+	t.Run("keeps_a_response_already_written", func(t *testing.T) {
+		setRecoveryTestLogger(t)
+		router := gin.New()
+		router.Use(Recovery())
+		router.GET("/things", func(c *gin.Context) {
+			c.String(http.StatusAccepted, "theResponse")
+			panic("thePanic")
+		})
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/things", nil))
+
+		assert.Equal(t, http.StatusAccepted, w.Code)
+		assert.Equal(t, "theResponse", w.Body.String())
+	})
+
+	t.Run("lets_an_aborted_handler_panic_through", func(t *testing.T) {
+		router := gin.New()
+		router.Use(Recovery())
+		router.GET("/things", func(c *gin.Context) {
+			panic(http.ErrAbortHandler)
+		})
+
+		assert.PanicsWithValue(t, http.ErrAbortHandler, func() {
+			router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/things", nil))
+		})
+	})
+
+	t.Run("does_nothing_without_a_panic", func(t *testing.T) {
+		router := gin.New()
+		router.Use(Recovery())
+		router.GET("/things", func(c *gin.Context) {
+			c.String(http.StatusOK, "theResponse")
+		})
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/things", nil))
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "theResponse", w.Body.String())
+	})
+}
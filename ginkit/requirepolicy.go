@@ -0,0 +1,63 @@
+package ginkit
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/half-ogre/go-kit/authzkit"
+)
+
+const authorizationDecisionContextKey = "github.com/half-ogre/go-kit/ginkit/authorization_decision"
+
+// PolicyPrincipalFunc returns who is making a request, e.g. from the claims set by an auth middleware,
+// and false if nobody is authenticated
+type PolicyPrincipalFunc func(c *gin.Context) (authzkit.Principal, bool)
+
+// PolicyResourceFunc returns the resource a request acts on, e.g. loaded by the ID in its path so a
+// policy can check who owns it
+type PolicyResourceFunc func(c *gin.Context) (authzkit.Resource, error)
+
+// RequirePolicy returns a middleware that asks engine whether the principal may perform action on the
+// resource returned by resource. It aborts with 401 Unauthorized when there is no principal and 403
+// Forbidden when the policy denies the request. The decision is available to handlers with
+// GetAuthorizationDecision.
+func RequirePolicy(engine *authzkit.Engine, principal PolicyPrincipalFunc, action string, resource PolicyResourceFunc) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		thePrincipal, ok := principal(c)
+		if !ok {
+			c.AbortWithStatus(http.StatusUnauthorized)
+			return
+		}
+
+		theResource, err := resource(c)
+		if err != nil {
+			_ = c.AbortWithError(http.StatusInternalServerError, err)
+			return
+		}
+
+		decision := engine.Evaluate(c.Request.Context(), authzkit.Request{
+			Principal: thePrincipal,
+			Action:    action,
+			Resource:  theResource,
+		})
+		c.Set(authorizationDecisionContextKey, decision)
+
+		if !decision.Allowed {
+			c.AbortWithStatus(http.StatusForbidden)
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// GetAuthorizationDecision returns the decision RequirePolicy made for the request, if any
+func GetAuthorizationDecision(c *gin.Context) (authzkit.Decision, bool) {
+	decision, ok := c.Get(authorizationDecisionContextKey)
+	if !ok {
+		return authzkit.Decision{}, false
+	}
+	d, ok := decision.(authzkit.Decision)
+	return d, ok
+}
@@ -0,0 +1,88 @@
+package ginkit
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/half-ogre/go-kit/authzkit"
+)
+
+func TestRequirePolicy(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	engine, err := authzkit.NewEngine([]authzkit.Policy{
+		{ID: "owners", Effect: authzkit.EffectAllow, Actions: []string{"edit"}, Resources: []string{"document"}, Condition: authzkit.MustParseCondition("resource.owner == principal.id")},
+	})
+	assert.NoError(t, err)
+	principalFromHeader := func(c *gin.Context) (authzkit.Principal, bool) {
+		sub := c.GetHeader("X-User")
+		return authzkit.Principal{ID: sub}, sub != ""
+	}
+	theDocument := func(c *gin.Context) (authzkit.Resource, error) {
+		return authzkit.Resource{Type: "document", ID: c.Param("id"), Attributes: map[string]any{"owner": "theUser"}}, nil
+	}
+
+	newRouter := func(resource PolicyResourceFunc, decisions *[]authzkit.Decision) *gin.Engine {
+		router := gin.New()
+		router.PUT("/documents/:id", RequirePolicy(engine, principalFromHeader, "edit", resource), func(c *gin.Context) {
+			decision, _ := GetAuthorizationDecision(c)
+			*decisions = append(*decisions, decision)
+			c.Status(http.StatusNoContent)
+		})
+		return router
+	}
+
+	t.Run("calls_the_next_handler_when_the_policy_allows", func(t *testing.T) {
+		decisions := []authzkit.Decision{}
+		req := httptest.NewRequest(http.MethodPut, "/documents/theDocument", nil)
+		req.Header.Set("X-User", "theUser")
+		w := httptest.NewRecorder()
+
+		newRouter(theDocument, &decisions).ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusNoContent, w.Code)
+		assert.Len(t, decisions, 1)
+		assert.Equal(t, "document:theDocument", decisions[0].Resource)
+		assert.Equal(t, "owners", decisions[0].PolicyID)
+	})
+
+	t.Run("returns_forbidden_when_the_policy_denies", func(t *testing.T) {
+		decisions := []authzkit.Decision{}
+		req := httptest.NewRequest(http.MethodPut, "/documents/theDocument", nil)
+		req.Header.Set("X-User", "anotherUser")
+		w := httptest.NewRecorder()
+
+		newRouter(theDocument, &decisions).ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusForbidden, w.Code)
+		assert.Empty(t, decisions)
+	})
+
+	t.Run("returns_unauthorized_without_a_principal", func(t *testing.T) {
+		decisions := []authzkit.Decision{}
+		req := httptest.NewRequest(http.MethodPut, "/documents/theDocument", nil)
+		w := httptest.NewRecorder()
+
+		newRouter(theDocument, &decisions).ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+		assert.Empty(t, decisions)
+	})
+
+	t.Run("returns_an_internal_server_error_when_the_resource_cannot_be_loaded", func(t *testing.T) {
+		decisions := []authzkit.Decision{}
+		req := httptest.NewRequest(http.MethodPut, "/documents/theDocument", nil)
+		req.Header.Set("X-User", "theUser")
+		w := httptest.NewRecorder()
+
+		newRouter(func(c *gin.Context) (authzkit.Resource, error) {
+			return authzkit.Resource{}, assert.AnError
+		}, &decisions).ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+		assert.Empty(t, decisions)
+	})
+}
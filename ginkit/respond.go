@@ -0,0 +1,86 @@
+package ginkit
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/half-ogre/go-kit/internal/respond"
+	"github.com/half-ogre/go-kit/kit"
+)
+
+const responseTemplateContextKey = "github.com/half-ogre/go-kit/ginkit/response_template"
+
+// ResponseEncoder writes model as the response with status in the encoder's media type
+type ResponseEncoder = respond.Encoder
+
+// CSVMarshaler is implemented by models that write themselves as CSV records, header first
+type CSVMarshaler = respond.CSVMarshaler
+
+// RegisterResponseEncoder sets the encoder Respond uses for mediaType, e.g. "application/xml",
+// replacing any encoder already registered for it. New media types are offered after the existing
+// ones when the Accept header doesn't prefer one. Encoders are shared with echokit.Respond.
+func RegisterResponseEncoder(mediaType string, encoder ResponseEncoder) {
+	respond.Register(mediaType, encoder)
+}
+
+// RespondOption configures Respond
+type RespondOption func(*respondConfig)
+
+type respondConfig struct {
+	template string
+}
+
+// WithResponseTemplate sets the HTML template for an HTML response, overriding WithRouteTemplate
+func WithResponseTemplate(name string) RespondOption {
+	return func(c *respondConfig) {
+		c.template = name
+	}
+}
+
+// WithRouteTemplate is route middleware that sets the HTML template Respond uses for an HTML
+// response, e.g. router.GET("/users/:id", ginkit.WithRouteTemplate("users/show.html"), getUser)
+func WithRouteTemplate(name string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Set(responseTemplateContextKey, name)
+		c.Next()
+	}
+}
+
+// Respond writes model with status in the media type the request's Accept header prefers: JSON, HTML
+// rendered with the engine's templates when the route has a template, CSV, or one added with
+// RegisterResponseEncoder. A request without an Accept header gets JSON. When none of the accepted
+// media types can be written, the request is aborted with 406 Not Acceptable. An error writing the
+// response is added to the context's errors, aborting with 500 Internal Server Error if nothing was
+// written yet.
+func Respond(c *gin.Context, status int, model any, options ...RespondOption) {
+	config := respondConfig{}
+	for _, option := range options {
+		option(&config)
+	}
+	template := config.template
+	if template == "" {
+		template = c.GetString(responseTemplateContextKey)
+	}
+
+	c.Writer.Header().Add("Vary", "Accept")
+
+	mediaType, encode, ok := respond.Negotiate(c.Request.Header.Values("Accept"), template != "")
+	if !ok {
+		c.AbortWithStatusJSON(http.StatusNotAcceptable, gin.H{"error": "none of the accepted media types can be returned"})
+		return
+	}
+
+	if encode == nil {
+		c.HTML(status, template, model)
+		return
+	}
+	if err := encode(c.Writer, status, model); err != nil {
+		err = kit.WrapError(err, "error writing %s response", mediaType)
+		if c.Writer.Written() {
+			_ = c.Error(err)
+			return
+		}
+		_ = c.AbortWithError(http.StatusInternalServerError, err)
+	}
+}
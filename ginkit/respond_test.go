@@ -0,0 +1,137 @@
+package ginkit
+
+import (
+	"fmt"
+	"html/template"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/half-ogre/go-kit/internal/respond"
+)
+
+type testRespondUser struct {
+	ID     string `json:"id" csv:"id"`
+	Name   string `json:"name" csv:"name"`
+	Secret string `json:"-" csv:"-"`
+}
+
+func serveRespond(router *gin.Engine, accept string, handlers ...gin.HandlerFunc) *httptest.ResponseRecorder {
+	if len(handlers) == 0 {
+		handlers = []gin.HandlerFunc{func(c *gin.Context) {
+			Respond(c, http.StatusOK, []testRespondUser{{ID: "theID", Name: "theName", Secret: "theSecret"}})
+		}}
+	}
+	router.GET("/users", handlers...)
+
+	req := httptest.NewRequest(http.MethodGet, "/users", nil)
+	if accept != "" {
+		req.Header.Set("Accept", accept)
+	}
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	return rec
+}
+
+func newTemplateRouter() *gin.Engine {
+	router := gin.New()
+	router.SetHTMLTemplate(template.Must(template.New("users/index.html").Parse(`<p>{{range .}}{{.Name}}{{end}}</p>`)))
+	return router
+}
+
+func TestRespond(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	t.Run("responds_with_json_when_there_is_no_accept_header", func(t *testing.T) {
+		rec := serveRespond(gin.New(), "")
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+		assert.Equal(t, "Accept", rec.Header().Get("Vary"))
+		assert.JSONEq(t, `[{"id":"theID","name":"theName"}]`, rec.Body.String())
+	})
+
+	t.Run("renders_html_with_the_route_template", func(t *testing.T) {
+		rec := serveRespond(newTemplateRouter(), "text/html,application/xhtml+xml,*/*;q=0.8", WithRouteTemplate("users/index.html"), func(c *gin.Context) {
+			Respond(c, http.StatusOK, []testRespondUser{{Name: "theName"}})
+		})
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "text/html; charset=utf-8", rec.Header().Get("Content-Type"))
+		assert.Equal(t, "<p>theName</p>", rec.Body.String())
+	})
+
+	t.Run("uses_the_response_template_option_over_the_route_template", func(t *testing.T) {
+		rec := serveRespond(newTemplateRouter(), "text/html", WithRouteTemplate("aTemplate"), func(c *gin.Context) {
+			Respond(c, http.StatusOK, []testRespondUser{{Name: "theName"}}, WithResponseTemplate("users/index.html"))
+		})
+
+		assert.Equal(t, "<p>theName</p>", rec.Body.String())
+	})
+
+	t.Run("falls_back_to_json_for_browsers_when_the_route_has_no_template", func(t *testing.T) {
+		rec := serveRespond(gin.New(), "text/html,application/xhtml+xml,*/*;q=0.8")
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+	})
+
+	t.Run("responds_with_csv_for_a_slice_of_structs", func(t *testing.T) {
+		rec := serveRespond(gin.New(), "text/csv")
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "text/csv; charset=utf-8", rec.Header().Get("Content-Type"))
+		assert.Equal(t, "id,name\ntheID,theName\n", rec.Body.String())
+	})
+
+	t.Run("aborts_with_not_acceptable_when_no_accepted_media_type_can_be_written", func(t *testing.T) {
+		rec := serveRespond(gin.New(), "application/xml, application/json;q=0")
+
+		assert.Equal(t, http.StatusNotAcceptable, rec.Code)
+		assert.JSONEq(t, `{"error":"none of the accepted media types can be returned"}`, rec.Body.String())
+	})
+
+	t.Run("aborts_with_internal_server_error_when_the_model_cannot_be_encoded", func(t *testing.T) {
+		var actualErrors []*gin.Error
+		rec := serveRespond(gin.New(), "text/csv", func(c *gin.Context) {
+			Respond(c, http.StatusOK, "aModel")
+			actualErrors = c.Errors
+		})
+
+		assert.Equal(t, http.StatusInternalServerError, rec.Code)
+		assert.Len(t, actualErrors, 1)
+		assert.EqualError(t, actualErrors[0].Err, "error writing text/csv response: can't write string as CSV; use a slice of structs, [][]string, or a CSVMarshaler")
+	})
+
+	t.Run("uses_an_encoder_from_the_registry_shared_with_echokit", func(t *testing.T) {
+		t.Cleanup(respond.ResetEncoders)
+		respond.Register("application/xml", func(w http.ResponseWriter, status int, model any) error {
+			w.Header().Set("Content-Type", "application/xml")
+			w.WriteHeader(status)
+			_, err := fmt.Fprintf(w, "<count>%d</count>", len(model.([]testRespondUser)))
+			return err
+		})
+
+		rec := serveRespond(gin.New(), "application/xml")
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "<count>1</count>", rec.Body.String())
+	})
+
+	t.Run("replaces_the_encoder_for_a_registered_media_type", func(t *testing.T) {
+		t.Cleanup(respond.ResetEncoders)
+		RegisterResponseEncoder("application/json", func(w http.ResponseWriter, status int, model any) error {
+			w.WriteHeader(status)
+			_, err := io.WriteString(w, "theJSON")
+			return err
+		})
+
+		rec := serveRespond(gin.New(), "application/json")
+
+		assert.Equal(t, "theJSON", rec.Body.String())
+	})
+}
@@ -0,0 +1,63 @@
+package ginkit
+
+import (
+	"errors"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/sessions"
+
+	"github.com/half-ogre/go-kit/kit"
+	"github.com/half-ogre/go-kit/sesskit"
+)
+
+const sessionStoreContextKey = "github.com/half-ogre/go-kit/ginkit/session_store"
+
+// Sessions returns a middleware that makes store available to GetSession and DeleteSession. With a
+// sesskit.Store configured as it is for echokit.NewSessionMiddleware, both frameworks read and write
+// the same sessions.
+func Sessions(store sessions.Store) gin.HandlerFunc {
+	if store == nil {
+		panic("session store must not be nil")
+	}
+
+	return func(c *gin.Context) {
+		c.Set(sessionStoreContextKey, store)
+		c.Next()
+	}
+}
+
+// GetSession returns the session named name from the store Sessions made available; save changes to
+// it with its Save method before writing the response body
+func GetSession(c *gin.Context, name string) (*sessions.Session, error) {
+	store, err := getSessionStore(c)
+	if err != nil {
+		return nil, err
+	}
+
+	session, err := store.Get(c.Request, name)
+	if err != nil {
+		return nil, kit.WrapError(err, "error getting session")
+	}
+	return session, nil
+}
+
+// DeleteSession deletes the session named name, as sesskit.Delete does
+func DeleteSession(c *gin.Context, name string) error {
+	store, err := getSessionStore(c)
+	if err != nil {
+		return err
+	}
+	return sesskit.Delete(c.Request, c.Writer, store, name)
+}
+
+func getSessionStore(c *gin.Context) (sessions.Store, error) {
+	value, ok := c.Get(sessionStoreContextKey)
+	if !ok {
+		return nil, errors.New("no session store; use the Sessions middleware")
+	}
+	store, ok := value.(sessions.Store)
+	if !ok {
+		return nil, errors.New("session store has the wrong type")
+	}
+	return store, nil
+}
@@ -0,0 +1,81 @@
+package ginkit
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/half-ogre/go-kit/echokit"
+	"github.com/half-ogre/go-kit/sesskit"
+)
+
+func newTestSessionStore(t *testing.T) *sesskit.Store {
+	cipher, err := sesskit.NewAESCipher(bytes.Repeat([]byte{1}, 32))
+	require.NoError(t, err)
+	store, err := sesskit.NewStore(sesskit.WithCipher(cipher))
+	require.NoError(t, err)
+	return store
+}
+
+func TestSessions(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	t.Run("reads_a_session_written_by_echokit", func(t *testing.T) {
+		store := newTestSessionStore(t)
+		e := echo.New()
+		e.Use(echokit.NewSessionMiddleware(store))
+		e.GET("/", func(c echo.Context) error {
+			session, err := echokit.GetSession("theSession", c)
+			if err != nil {
+				return err
+			}
+			session.Values["user"] = "theUser"
+			return session.Save(c.Request(), c.Response().Writer)
+		})
+		echoResponse := httptest.NewRecorder()
+		e.ServeHTTP(echoResponse, httptest.NewRequest(http.MethodGet, "/", nil))
+		var actualUser any
+		router := gin.New()
+		router.GET("/", Sessions(store), func(c *gin.Context) {
+			session, err := GetSession(c, "theSession")
+			require.NoError(t, err)
+			actualUser = session.Values["user"]
+			c.Status(http.StatusNoContent)
+		})
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.AddCookie(echoResponse.Result().Cookies()[0])
+
+		router.ServeHTTP(httptest.NewRecorder(), req)
+
+		assert.Equal(t, "theUser", actualUser)
+	})
+
+	t.Run("deletes_a_session", func(t *testing.T) {
+		store := newTestSessionStore(t)
+		router := gin.New()
+		router.GET("/", Sessions(store), func(c *gin.Context) {
+			require.NoError(t, DeleteSession(c, "theSession"))
+			c.Status(http.StatusNoContent)
+		})
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+
+		assert.Equal(t, -1, w.Result().Cookies()[0].MaxAge)
+	})
+
+	t.Run("returns_an_error_without_the_middleware", func(t *testing.T) {
+		c, _ := gin.CreateTestContext(httptest.NewRecorder())
+		c.Request = httptest.NewRequest(http.MethodGet, "/", nil)
+
+		_, err := GetSession(c, "theSession")
+
+		assert.EqualError(t, err, "no session store; use the Sessions middleware")
+	})
+}
@@ -5,6 +5,8 @@ import (
 	"time"
 
 	"github.com/gin-gonic/gin"
+
+	"github.com/half-ogre/go-kit/logkit/logfields"
 )
 
 type SlogRequestLoggerOption func(*SlogRequestLoggerConfig)
@@ -61,7 +63,7 @@ func SlogRequestLogger(options ...SlogRequestLoggerOption) gin.HandlerFunc {
 			"method", method,
 			"path", path,
 			"status", statusCode,
-			"latency", latency,
+			logfields.Duration(latency),
 			"client_ip", clientIP,
 			"body_size", bodySize,
 		)
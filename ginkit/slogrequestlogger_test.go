@@ -91,7 +91,7 @@ func TestSlogRequestLogger(t *testing.T) {
 		assert.Contains(t, logString, "method=GET")
 		assert.Contains(t, logString, "path=/test")
 		assert.Contains(t, logString, "status=200")
-		assert.Contains(t, logString, "latency=")
+		assert.Contains(t, logString, "duration=")
 		assert.Contains(t, logString, "client_ip=")
 		assert.Contains(t, logString, "body_size=-1")
 	})
@@ -247,8 +247,8 @@ func TestSlogRequestLogger(t *testing.T) {
 		router.ServeHTTP(w, req)
 
 		logString := logOutput.String()
-		assert.Contains(t, logString, "latency=")
-		// Check that latency is greater than the sleep time
+		assert.Contains(t, logString, "duration=")
+		// Check that duration is greater than the sleep time
 		assert.Contains(t, logString, "ms") // Should contain milliseconds in the duration
 	})
 
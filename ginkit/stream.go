@@ -0,0 +1,69 @@
+package ginkit
+
+import (
+	"iter"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/half-ogre/go-kit/internal/respond"
+)
+
+// MIMEApplicationNDJSON is the media type of newline-delimited JSON
+const MIMEApplicationNDJSON = respond.MIMEApplicationNDJSON
+
+// StreamOption configures StreamNDJSON and StreamCSV
+type StreamOption = respond.StreamOption
+
+// WithStreamFlushEvery flushes the response after every n records instead of after each one
+func WithStreamFlushEvery(n int) StreamOption {
+	return respond.WithFlushEvery(n)
+}
+
+// WithStreamWriteTimeout gives each flush d to reach the client, extending the server's WriteTimeout so
+// a long export isn't cut off while a client that stops reading still is
+func WithStreamWriteTimeout(d time.Duration) StreamOption {
+	return respond.WithWriteTimeout(d)
+}
+
+// StreamNDJSON writes the items from seq as newline-delimited JSON with status, flushing as it goes
+// so the client gets them before the whole result is read, e.g. from dynamodbkit.QueryItems.
+// Streaming stops when the client disconnects or the request's context is otherwise done. The status
+// is sent before the first item, so an error from seq truncates the response; it's added to the
+// context's errors and returned.
+func StreamNDJSON[T any](c *gin.Context, status int, seq iter.Seq2[T, error], options ...StreamOption) error {
+	startStream(c, status, MIMEApplicationNDJSON)
+	return recordStreamError(c, respond.Stream(c.Request.Context(), streamController(c), seq, respond.NDJSONWriter[T](c.Writer), options...))
+}
+
+// StreamCSV writes header and then the records from seq as CSV with status, flushing as it goes, as
+// StreamNDJSON does for JSON. A nil header writes no header row.
+func StreamCSV(c *gin.Context, status int, header []string, seq iter.Seq2[[]string, error], options ...StreamOption) error {
+	startStream(c, status, respond.MIMETextCSV+"; charset=utf-8")
+	return recordStreamError(c, respond.Stream(c.Request.Context(), streamController(c), respond.WithCSVHeader(header, seq), respond.CSVWriter(c.Writer), options...))
+}
+
+// startStream writes the status and headers; records are written through c.Writer so its Size
+// counts them
+func startStream(c *gin.Context, status int, contentType string) {
+	c.Header("Content-Type", contentType)
+	c.Header("X-Content-Type-Options", "nosniff")
+	c.Status(status)
+	c.Writer.WriteHeaderNow()
+}
+
+// streamController flushes the writer under gin's, as gin's Flush panics when that writer can't
+func streamController(c *gin.Context) *http.ResponseController {
+	if unwrapper, ok := c.Writer.(interface{ Unwrap() http.ResponseWriter }); ok {
+		return http.NewResponseController(unwrapper.Unwrap())
+	}
+	return http.NewResponseController(c.Writer)
+}
+
+func recordStreamError(c *gin.Context, err error) error {
+	if err != nil {
+		_ = c.Error(err)
+	}
+	return err
+}
@@ -0,0 +1,156 @@
+package ginkit
+
+import (
+	"context"
+	"errors"
+	"iter"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+type flushCountingRecorder struct {
+	*httptest.ResponseRecorder
+	flushes int
+}
+
+func (r *flushCountingRecorder) Flush() {
+	r.flushes++
+	r.ResponseRecorder.Flush()
+}
+
+func seqOf[T any](items ...T) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		for _, item := range items {
+			if !yield(item, nil) {
+				return
+			}
+		}
+	}
+}
+
+func serveStream(t *testing.T, w http.ResponseWriter, req *http.Request, handlers ...gin.HandlerFunc) {
+	t.Helper()
+	router := gin.New()
+	router.GET("/export", handlers...)
+	router.ServeHTTP(w, req)
+}
+
+func TestStreamNDJSON(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	t.Run("writes_each_item_as_a_json_line_and_flushes", func(t *testing.T) {
+		rec := &flushCountingRecorder{ResponseRecorder: httptest.NewRecorder()}
+
+		serveStream(t, rec, httptest.NewRequest(http.MethodGet, "/export", nil), func(c *gin.Context) {
+			_ = StreamNDJSON(c, http.StatusOK, seqOf(testRespondUser{ID: "theID1"}, testRespondUser{ID: "theID2"}))
+		})
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, MIMEApplicationNDJSON, rec.Header().Get("Content-Type"))
+		assert.Equal(t, "nosniff", rec.Header().Get("X-Content-Type-Options"))
+		assert.Equal(t, "{\"id\":\"theID1\",\"name\":\"\"}\n{\"id\":\"theID2\",\"name\":\"\"}\n", rec.Body.String())
+		assert.Equal(t, 4, rec.flushes)
+	})
+
+	t.Run("flushes_after_every_n_records", func(t *testing.T) {
+		rec := &flushCountingRecorder{ResponseRecorder: httptest.NewRecorder()}
+
+		serveStream(t, rec, httptest.NewRequest(http.MethodGet, "/export", nil), func(c *gin.Context) {
+			_ = StreamNDJSON(c, http.StatusOK, seqOf(1, 2, 3, 4, 5), WithStreamFlushEvery(2))
+		})
+
+		assert.Equal(t, "1\n2\n3\n4\n5\n", rec.Body.String())
+		assert.Equal(t, 4, rec.flushes)
+	})
+
+	t.Run("streams_to_a_writer_that_cannot_flush", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		w := struct{ http.ResponseWriter }{rec}
+
+		serveStream(t, w, httptest.NewRequest(http.MethodGet, "/export", nil), func(c *gin.Context) {
+			_ = StreamNDJSON(c, http.StatusOK, seqOf(1, 2))
+		})
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "1\n2\n", rec.Body.String())
+	})
+
+	t.Run("stops_when_the_client_disconnects", func(t *testing.T) {
+		var actualErr error
+		ctx, cancel := context.WithCancel(context.Background())
+		t.Cleanup(cancel)
+		rec := httptest.NewRecorder()
+
+		serveStream(t, rec, httptest.NewRequest(http.MethodGet, "/export", nil).WithContext(ctx), func(c *gin.Context) {
+			items := func(yield func(int, error) bool) {
+				for i := 1; ; i++ {
+					if i == 3 {
+						cancel()
+					}
+					if !yield(i, nil) {
+						return
+					}
+				}
+			}
+			actualErr = StreamNDJSON(c, http.StatusOK, items)
+		})
+
+		assert.Equal(t, "1\n2\n", rec.Body.String())
+		assert.ErrorIs(t, actualErr, context.Canceled)
+		assert.EqualError(t, actualErr, "stopped streaming response after 2 records: context canceled")
+	})
+
+	t.Run("adds_the_error_from_the_sequence_to_the_context_errors", func(t *testing.T) {
+		var actualErrors []*gin.Error
+		rec := httptest.NewRecorder()
+
+		serveStream(t, rec, httptest.NewRequest(http.MethodGet, "/export", nil), func(c *gin.Context) {
+			items := func(yield func(int, error) bool) {
+				if !yield(1, nil) {
+					return
+				}
+				yield(0, errors.New("the sequence error"))
+			}
+			_ = StreamNDJSON(c, http.StatusOK, items)
+			actualErrors = c.Errors
+		})
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "1\n", rec.Body.String())
+		assert.Len(t, actualErrors, 1)
+		assert.EqualError(t, actualErrors[0].Err, "error streaming response after 1 records: the sequence error")
+	})
+}
+
+func TestStreamCSV(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	t.Run("writes_the_header_and_records", func(t *testing.T) {
+		var actualSize int
+		rec := httptest.NewRecorder()
+
+		serveStream(t, rec, httptest.NewRequest(http.MethodGet, "/export", nil), func(c *gin.Context) {
+			_ = StreamCSV(c, http.StatusCreated, []string{"id", "name"}, seqOf([]string{"theID", "theName"}, []string{"anID", "a, name"}))
+			actualSize = c.Writer.Size()
+		})
+
+		assert.Equal(t, http.StatusCreated, rec.Code)
+		assert.Equal(t, "text/csv; charset=utf-8", rec.Header().Get("Content-Type"))
+		assert.Equal(t, "id,name\ntheID,theName\nanID,\"a, name\"\n", rec.Body.String())
+		assert.Equal(t, rec.Body.Len(), actualSize)
+	})
+
+	t.Run("writes_no_header_row_for_a_nil_header", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+
+		serveStream(t, rec, httptest.NewRequest(http.MethodGet, "/export", nil), func(c *gin.Context) {
+			_ = StreamCSV(c, http.StatusOK, nil, seqOf([]string{"theValue"}))
+		})
+
+		assert.Equal(t, "theValue\n", rec.Body.String())
+	})
+}
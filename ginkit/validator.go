@@ -0,0 +1,61 @@
+package ginkit
+
+import (
+	"reflect"
+
+	"github.com/go-playground/validator/v10"
+
+	"github.com/half-ogre/go-kit/validatekit"
+)
+
+// Validator is a gin binding.StructValidator that sanitizes structs by their sanitize tags with
+// validatekit.Sanitize and then validates their binding tags, which include the validatekit tags, e.g.
+// `binding:"required,slug"`. Set it with binding.Validator = ginkit.NewValidator().
+type Validator struct {
+	validate *validator.Validate
+}
+
+// NewValidator creates a Validator
+func NewValidator() *Validator {
+	v := validator.New()
+	v.SetTagName("binding")
+	_ = validatekit.Register(v)
+
+	return &Validator{validate: v}
+}
+
+// ValidateStruct sanitizes and validates obj, a struct, a pointer to one, or a slice of them; a struct
+// is only sanitized through a pointer
+func (v *Validator) ValidateStruct(obj any) error {
+	if obj == nil {
+		return nil
+	}
+
+	value := reflect.ValueOf(obj)
+	switch value.Kind() {
+	case reflect.Pointer:
+		if value.IsNil() {
+			return nil
+		}
+		if value.Elem().Kind() == reflect.Struct {
+			if err := validatekit.Sanitize(obj); err != nil {
+				return err
+			}
+		}
+		return v.ValidateStruct(value.Elem().Interface())
+	case reflect.Struct:
+		return v.validate.Struct(obj)
+	case reflect.Slice, reflect.Array:
+		for i := range value.Len() {
+			if err := v.ValidateStruct(value.Index(i).Interface()); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// Engine returns the underlying go-playground validator, e.g. to register more tags
+func (v *Validator) Engine() any {
+	return v.validate
+}
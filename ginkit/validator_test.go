@@ -0,0 +1,67 @@
+package ginkit
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidator(t *testing.T) {
+	type Post struct {
+		Slug  string `json:"slug" binding:"required,slug" sanitize:"trim,lower"`
+		Email string `json:"email" binding:"omitempty,email" sanitize:"trim"`
+	}
+	validator := NewValidator()
+
+	t.Run("sanitizes_and_validates_a_pointer_to_a_struct", func(t *testing.T) {
+		post := Post{Slug: " The-Post ", Email: " ada@example.com "}
+
+		err := validator.ValidateStruct(&post)
+
+		assert.NoError(t, err)
+		assert.Equal(t, Post{Slug: "the-post", Email: "ada@example.com"}, post)
+	})
+
+	t.Run("returns_an_error_for_an_invalid_struct", func(t *testing.T) {
+		err := validator.ValidateStruct(&Post{Slug: "the post"})
+
+		assert.ErrorContains(t, err, "'slug' tag")
+	})
+
+	t.Run("validates_each_struct_of_a_slice", func(t *testing.T) {
+		err := validator.ValidateStruct([]Post{{Slug: "the-post"}, {Slug: ""}})
+
+		assert.ErrorContains(t, err, "'required' tag")
+	})
+
+	t.Run("binds_requests_as_the_binding_validator", func(t *testing.T) {
+		gin.SetMode(gin.TestMode)
+		defaultValidator := binding.Validator
+		binding.Validator = validator
+		t.Cleanup(func() { binding.Validator = defaultValidator })
+		router := gin.New()
+		var bound Post
+		router.POST("/", func(c *gin.Context) {
+			var post Post
+			if err := c.ShouldBindJSON(&post); err != nil {
+				c.Status(http.StatusBadRequest)
+				return
+			}
+			bound = post
+			c.Status(http.StatusNoContent)
+		})
+		req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"slug": " My-Post "}`))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusNoContent, w.Code)
+		assert.Equal(t, "my-post", bound.Slug)
+	})
+}
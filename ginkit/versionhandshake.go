@@ -0,0 +1,24 @@
+package ginkit
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/half-ogre/go-kit/versionkit"
+)
+
+// VersionHandshake returns a middleware that advertises the service's build in the response headers
+// and rejects requests from peers that fail handshake's check with 412 Precondition Failed
+func VersionHandshake(handshake *versionkit.Handshake) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		handshake.SetHeaders(c.Writer.Header())
+
+		if err := handshake.Check(c.Request.Header); err != nil {
+			c.AbortWithStatusJSON(http.StatusPreconditionFailed, gin.H{"message": err.Error()})
+			return
+		}
+
+		c.Next()
+	}
+}
@@ -0,0 +1,46 @@
+package ginkit
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/half-ogre/go-kit/versionkit"
+)
+
+func TestVersionHandshake(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	handshake, _ := versionkit.NewHandshake(&versionkit.BuildInfo{Version: "v1.0.0", GitCommit: "theCommit"}, versionkit.WithMinimumPeerVersion("v2.0.0"))
+
+	t.Run("advertises_the_build_and_accepts_a_compatible_peer", func(t *testing.T) {
+		router := gin.New()
+		router.Use(VersionHandshake(handshake))
+		router.GET("/things", func(c *gin.Context) { c.String(http.StatusOK, "theResponse") })
+		req := httptest.NewRequest(http.MethodGet, "/things", nil)
+		req.Header.Set(versionkit.HeaderServiceVersion, "v2.1.0")
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "v1.0.0", w.Header().Get(versionkit.HeaderServiceVersion))
+		assert.Equal(t, "theCommit", w.Header().Get(versionkit.HeaderServiceCommit))
+	})
+
+	t.Run("rejects_an_old_peer", func(t *testing.T) {
+		router := gin.New()
+		router.Use(VersionHandshake(handshake))
+		router.GET("/things", func(c *gin.Context) { c.String(http.StatusOK, "theResponse") })
+		req := httptest.NewRequest(http.MethodGet, "/things", nil)
+		req.Header.Set(versionkit.HeaderServiceVersion, "v1.9.0")
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusPreconditionFailed, w.Code)
+		assert.JSONEq(t, `{"message":"peer version v1.9.0 is older than the minimum v2.0.0"}`, w.Body.String())
+	})
+}
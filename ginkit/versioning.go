@@ -0,0 +1,144 @@
+package ginkit
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/half-ogre/go-kit/internal/apiversion"
+)
+
+const apiVersionContextKey = "github.com/half-ogre/go-kit/ginkit/api_version"
+
+// APIVersions registers versioned route groups the same way as echokit.APIVersions. By default the
+// version is the path segment after the prefix, e.g. /v2/users; with WithAcceptHeaderVersioning it's
+// taken from the Accept header instead.
+type APIVersions struct {
+	r          *gin.Engine
+	negotiator apiversion.Negotiator
+	byAccept   bool
+}
+
+type APIVersionsOption func(*APIVersions)
+
+// WithAcceptHeaderVersioning picks the version from a vendor media type in the Accept header, e.g.
+// "application/vnd.acme.v2+json" for vendor "acme", using defaultVersion when there isn't one. Gin
+// matches routes before any middleware runs, so serve requests with Handler to have them routed to the
+// version's group.
+func WithAcceptHeaderVersioning(vendor string, defaultVersion string) APIVersionsOption {
+	return func(a *APIVersions) {
+		a.negotiator.Vendor = vendor
+		a.negotiator.DefaultVersion = defaultVersion
+		a.byAccept = true
+	}
+}
+
+// WithAPIVersionsPrefix serves the versions under prefix, e.g. "/api" for /api/v2/users. With Accept
+// header versioning only requests under the prefix are versioned, leaving routes like /healthz alone.
+func WithAPIVersionsPrefix(prefix string) APIVersionsOption {
+	return func(a *APIVersions) {
+		a.negotiator.Prefix = strings.TrimSuffix(prefix, "/")
+	}
+}
+
+// NewAPIVersions returns an APIVersions that registers routes on r
+func NewAPIVersions(r *gin.Engine, options ...APIVersionsOption) *APIVersions {
+	a := &APIVersions{r: r, negotiator: apiversion.Negotiator{Versions: map[string]bool{}}}
+	for _, option := range options {
+		option(a)
+	}
+	return a
+}
+
+type versionConfig struct {
+	deprecation apiversion.Deprecation
+	middlewares []gin.HandlerFunc
+}
+
+type VersionOption func(*versionConfig)
+
+// WithVersionDeprecated marks the version deprecated as of at, sending a Deprecation header (RFC 9745)
+// with every response
+func WithVersionDeprecated(at time.Time) VersionOption {
+	return func(config *versionConfig) {
+		config.deprecation.Deprecated = at
+	}
+}
+
+// WithVersionSunset sends a Sunset header (RFC 8594) with every response saying when the version will
+// stop working
+func WithVersionSunset(at time.Time) VersionOption {
+	return func(config *versionConfig) {
+		config.deprecation.Sunset = at
+	}
+}
+
+// WithVersionDeprecationLink sends a Link header pointing to docs on moving off the version
+func WithVersionDeprecationLink(link string) VersionOption {
+	return func(config *versionConfig) {
+		config.deprecation.Link = link
+	}
+}
+
+// WithVersionMiddleware adds middleware that runs only for the version's routes
+func WithVersionMiddleware(middlewares ...gin.HandlerFunc) VersionOption {
+	return func(config *versionConfig) {
+		config.middlewares = append(config.middlewares, middlewares...)
+	}
+}
+
+// Version returns the route group for version, e.g. "v2", whose routes are served under /v2 after the
+// prefix
+func (a *APIVersions) Version(version string, options ...VersionOption) *gin.RouterGroup {
+	config := &versionConfig{}
+	for _, option := range options {
+		option(config)
+	}
+
+	a.negotiator.Versions[version] = true
+
+	middlewares := []gin.HandlerFunc{versionMiddleware(version, config.deprecation, a.byAccept)}
+	return a.r.Group(a.negotiator.Prefix+"/"+version, append(middlewares, config.middlewares...)...)
+}
+
+// Handler returns the engine wrapped to route requests by their Accept header when
+// WithAcceptHeaderVersioning is set. Requests for a version that isn't registered get 406 Not
+// Acceptable.
+func (a *APIVersions) Handler() http.Handler {
+	if !a.byAccept {
+		return a.r
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if err := a.negotiator.Route(req.URL, req.Header.Values("Accept")); err != nil {
+			w.Header().Set("Content-Type", "application/json; charset=utf-8")
+			w.WriteHeader(http.StatusNotAcceptable)
+			_ = json.NewEncoder(w).Encode(gin.H{"message": err.Error()})
+			return
+		}
+		a.r.ServeHTTP(w, req)
+	})
+}
+
+// RequestAPIVersion returns the API version of the route handling the request, or "" if it isn't in a
+// versioned group
+func RequestAPIVersion(c *gin.Context) string {
+	return c.GetString(apiVersionContextKey)
+}
+
+func versionMiddleware(version string, deprecation apiversion.Deprecation, varyAccept bool) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Set(apiVersionContextKey, version)
+
+		header := c.Writer.Header()
+		if varyAccept {
+			header.Add("Vary", "Accept")
+		}
+		deprecation.SetHeaders(header)
+
+		c.Next()
+	}
+}
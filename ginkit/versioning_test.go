@@ -0,0 +1,110 @@
+package ginkit
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func serveVersioned(handler http.Handler, path string, accept string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, path, nil)
+	if accept != "" {
+		req.Header.Set("Accept", accept)
+	}
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+	return w
+}
+
+func versionHandler(c *gin.Context) {
+	c.String(http.StatusOK, RequestAPIVersion(c))
+}
+
+func TestAPIVersions(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	t.Run("routes_by_path_prefix", func(t *testing.T) {
+		router := gin.New()
+		versions := NewAPIVersions(router)
+		versions.Version("v1").GET("/users", versionHandler)
+		versions.Version("v2").GET("/users", versionHandler)
+
+		assert.Equal(t, "v1", serveVersioned(versions.Handler(), "/v1/users", "").Body.String())
+		assert.Equal(t, "v2", serveVersioned(router, "/v2/users", "").Body.String())
+		assert.Equal(t, http.StatusNotFound, serveVersioned(router, "/users", "").Code)
+	})
+
+	t.Run("routes_under_the_prefix", func(t *testing.T) {
+		router := gin.New()
+		NewAPIVersions(router, WithAPIVersionsPrefix("/api/")).Version("v1").GET("/users", versionHandler)
+
+		assert.Equal(t, "v1", serveVersioned(router, "/api/v1/users", "").Body.String())
+	})
+
+	t.Run("routes_by_accept_header", func(t *testing.T) {
+		router := gin.New()
+		versions := NewAPIVersions(router, WithAcceptHeaderVersioning("acme", "v1"))
+		versions.Version("v1").GET("/users", versionHandler)
+		versions.Version("v2").GET("/users", versionHandler)
+
+		w := serveVersioned(versions.Handler(), "/users", "text/html, application/vnd.acme.v2+json; q=0.9")
+
+		assert.Equal(t, "v2", w.Body.String())
+		assert.Equal(t, "Accept", w.Header().Get("Vary"))
+		assert.Equal(t, "v1", serveVersioned(versions.Handler(), "/users", "application/json").Body.String())
+		assert.Equal(t, "v1", serveVersioned(versions.Handler(), "/v1/users", "application/vnd.acme.v1+json").Body.String())
+	})
+
+	t.Run("returns_not_acceptable_for_an_unknown_version", func(t *testing.T) {
+		router := gin.New()
+		versions := NewAPIVersions(router, WithAcceptHeaderVersioning("acme", "v1"))
+		versions.Version("v1").GET("/users", versionHandler)
+
+		w := serveVersioned(versions.Handler(), "/users", "application/vnd.acme.v9+json")
+
+		assert.Equal(t, http.StatusNotAcceptable, w.Code)
+		assert.JSONEq(t, `{"message":"unsupported API version \"v9\""}`, w.Body.String())
+	})
+
+	t.Run("leaves_requests_outside_the_prefix_alone", func(t *testing.T) {
+		router := gin.New()
+		versions := NewAPIVersions(router, WithAcceptHeaderVersioning("acme", "v1"), WithAPIVersionsPrefix("/api"))
+		versions.Version("v1").GET("/users", versionHandler)
+		router.GET("/healthz", func(c *gin.Context) { c.String(http.StatusOK, "theHealth") })
+
+		assert.Equal(t, "v1", serveVersioned(versions.Handler(), "/api/users", "").Body.String())
+		assert.Equal(t, "theHealth", serveVersioned(versions.Handler(), "/healthz", "").Body.String())
+	})
+
+	t.Run("sends_deprecation_headers", func(t *testing.T) {
+		router := gin.New()
+		NewAPIVersions(router).Version("v1",
+			WithVersionDeprecated(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)),
+			WithVersionSunset(time.Date(2024, 7, 1, 0, 0, 0, 0, time.UTC)),
+			WithVersionDeprecationLink("https://example.com/migrate"),
+		).GET("/users", versionHandler)
+
+		w := serveVersioned(router, "/v1/users", "")
+
+		assert.Equal(t, "@1704067200", w.Header().Get("Deprecation"))
+		assert.Equal(t, "Mon, 01 Jul 2024 00:00:00 GMT", w.Header().Get("Sunset"))
+		assert.Equal(t, `<https://example.com/migrate>; rel="deprecation"`, w.Header().Get("Link"))
+	})
+
+	t.Run("runs_version_middleware_only_for_its_version", func(t *testing.T) {
+		router := gin.New()
+		versions := NewAPIVersions(router)
+		versions.Version("v1", WithVersionMiddleware(func(c *gin.Context) {
+			c.Header("X-Legacy", "true")
+			c.Next()
+		})).GET("/users", versionHandler)
+		versions.Version("v2").GET("/users", versionHandler)
+
+		assert.Equal(t, "true", serveVersioned(router, "/v1/users", "").Header().Get("X-Legacy"))
+		assert.Empty(t, serveVersioned(router, "/v2/users", "").Header().Get("X-Legacy"))
+	})
+}
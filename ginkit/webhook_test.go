@@ -0,0 +1,96 @@
+package ginkit
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func signWebhookTestPayload(secret string, payload string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(payload))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func TestWebhookHandler(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	t.Run("calls_the_handler_with_the_body_when_the_signature_is_valid", func(t *testing.T) {
+		var actualBody string
+		router := gin.New()
+		router.POST("/webhook", WebhookHandler(StaticWebhookSecret("theSecret"), NewGitHubWebhookVerifier(), func(c *gin.Context) {
+			body, _ := io.ReadAll(c.Request.Body)
+			actualBody = string(body)
+			c.Status(http.StatusNoContent)
+		}))
+		req := httptest.NewRequest(http.MethodPost, "/webhook", strings.NewReader(`{"action":"opened"}`))
+		req.Header.Set("X-Hub-Signature-256", "sha256="+signWebhookTestPayload("theSecret", `{"action":"opened"}`))
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusNoContent, w.Code)
+		assert.Equal(t, `{"action":"opened"}`, actualBody)
+	})
+
+	t.Run("aborts_with_unauthorized_when_the_signature_is_invalid", func(t *testing.T) {
+		handlerCalled := false
+		router := gin.New()
+		router.POST("/webhook", WebhookHandler(StaticWebhookSecret("theSecret"), NewGitHubWebhookVerifier(), func(c *gin.Context) {
+			handlerCalled = true
+		}))
+		req := httptest.NewRequest(http.MethodPost, "/webhook", strings.NewReader("aBody"))
+		req.Header.Set("X-Hub-Signature-256", "sha256="+signWebhookTestPayload("aDifferentSecret", "aBody"))
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+		assert.JSONEq(t, `{"error":"invalid webhook signature"}`, w.Body.String())
+		assert.False(t, handlerCalled)
+	})
+
+	t.Run("aborts_with_request_entity_too_large_when_the_body_is_too_large", func(t *testing.T) {
+		handlerCalled := false
+		router := gin.New()
+		router.POST("/webhook", WebhookHandler(StaticWebhookSecret("theSecret"), NewGitHubWebhookVerifier(), func(c *gin.Context) {
+			handlerCalled = true
+		}, WithWebhookMaxBodySize(5)))
+		req := httptest.NewRequest(http.MethodPost, "/webhook", strings.NewReader("aBodyThatIsTooLarge"))
+		req.Header.Set("X-Hub-Signature-256", "sha256="+signWebhookTestPayload("theSecret", "aBodyThatIsTooLarge"))
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusRequestEntityTooLarge, w.Code)
+		assert.JSONEq(t, `{"error":"webhook body too large"}`, w.Body.String())
+		assert.False(t, handlerCalled)
+	})
+
+	t.Run("aborts_with_internal_server_error_when_the_secret_provider_fails", func(t *testing.T) {
+		handlerCalled := false
+		secretProvider := func(ctx context.Context) ([]byte, error) {
+			return nil, errors.New("the secret error")
+		}
+		router := gin.New()
+		router.POST("/webhook", WebhookHandler(secretProvider, NewGitHubWebhookVerifier(), func(c *gin.Context) {
+			handlerCalled = true
+		}))
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/webhook", strings.NewReader("aBody")))
+
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+		assert.False(t, handlerCalled)
+	})
+}
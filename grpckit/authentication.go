@@ -0,0 +1,97 @@
+package grpckit
+
+import (
+	"context"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/half-ogre/go-kit/echokit"
+)
+
+// AuthenticatedUser is the same user type the echokit authenticators produce, so permission checks
+// read the same claims over HTTP and gRPC
+type AuthenticatedUser = echokit.AuthenticatedUser
+
+const authenticatedUserContextKey contextKey = "github.com/half-ogre/go-kit/grpckit/authenticated_user"
+
+// Authenticator validates a bearer token and returns the user it was issued to
+type Authenticator interface {
+	Authenticate(ctx context.Context, token string) (*AuthenticatedUser, error)
+}
+
+// AuthenticatorFunc adapts a function to the Authenticator interface
+type AuthenticatorFunc func(ctx context.Context, token string) (*AuthenticatedUser, error)
+
+func (f AuthenticatorFunc) Authenticate(ctx context.Context, token string) (*AuthenticatedUser, error) {
+	return f(ctx, token)
+}
+
+// NewAuth0JWTAuthenticator returns an Authenticator that validates Auth0 access tokens the same way
+// as echokit.NewAuth0JWTAuthenticator, from the same config
+func NewAuth0JWTAuthenticator(config echokit.Auth0Config) (Authenticator, error) {
+	jwtValidator, err := echokit.NewAuth0JWTValidator(config)
+	if err != nil {
+		return nil, err
+	}
+
+	return AuthenticatorFunc(func(ctx context.Context, token string) (*AuthenticatedUser, error) {
+		validateResult, err := jwtValidator.ValidateToken(ctx, token)
+		if err != nil {
+			return nil, err
+		}
+		return echokit.Auth0AuthenticatedUser(validateResult, config.Audience)
+	}), nil
+}
+
+// GetAuthenticatedUser returns the user set by the authentication interceptors, or false if the call
+// had no bearer token
+func GetAuthenticatedUser(ctx context.Context) (*AuthenticatedUser, bool) {
+	user, ok := ctx.Value(authenticatedUserContextKey).(*AuthenticatedUser)
+	return user, ok && user != nil
+}
+
+// WithAuthenticatedUser returns a copy of ctx carrying user, e.g. to call handlers in tests
+func WithAuthenticatedUser(ctx context.Context, user *AuthenticatedUser) context.Context {
+	return context.WithValue(ctx, authenticatedUserContextKey, user)
+}
+
+// UnaryAuthentication returns an interceptor that authenticates the bearer token in the authorization
+// metadata. Like the echokit authentication middleware, calls without a token continue unauthenticated
+// (use UnaryRequirePermissions to reject them); calls with an invalid token get codes.Unauthenticated.
+func UnaryAuthentication(authenticator Authenticator) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		ctx, err := authenticatedContext(ctx, authenticator)
+		if err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+// StreamAuthentication is the stream equivalent of UnaryAuthentication
+func StreamAuthentication(authenticator Authenticator) grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx, err := authenticatedContext(ss.Context(), authenticator)
+		if err != nil {
+			return err
+		}
+		return handler(srv, withStreamContext(ss, ctx))
+	}
+}
+
+func authenticatedContext(ctx context.Context, authenticator Authenticator) (context.Context, error) {
+	authorization := strings.Fields(incomingMetadata(ctx, "authorization"))
+	if len(authorization) != 2 || strings.ToLower(authorization[0]) != "bearer" {
+		return ctx, nil
+	}
+
+	user, err := authenticator.Authenticate(ctx, authorization[1])
+	if err != nil {
+		return nil, status.Error(codes.Unauthenticated, "invalid token")
+	}
+
+	return WithAuthenticatedUser(ctx, user), nil
+}
@@ -0,0 +1,72 @@
+package grpckit
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func newFakeAuthenticator(user *AuthenticatedUser) Authenticator {
+	return AuthenticatorFunc(func(ctx context.Context, token string) (*AuthenticatedUser, error) {
+		if token != "theToken" {
+			return nil, assert.AnError
+		}
+		return user, nil
+	})
+}
+
+func TestUnaryAuthentication(t *testing.T) {
+	theUser := &AuthenticatedUser{Sub: "theSub"}
+
+	t.Run("adds_the_user_for_a_valid_bearer_token", func(t *testing.T) {
+		var user *AuthenticatedUser
+
+		_, err := UnaryAuthentication(newFakeAuthenticator(theUser))(incomingContext("authorization", "Bearer theToken"), nil, theUnaryInfo, func(ctx context.Context, req any) (any, error) {
+			user, _ = GetAuthenticatedUser(ctx)
+			return nil, nil
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, theUser, user)
+	})
+
+	t.Run("continues_unauthenticated_without_a_bearer_token", func(t *testing.T) {
+		authenticated := true
+
+		_, err := UnaryAuthentication(newFakeAuthenticator(theUser))(incomingContext("authorization", "Basic theCredentials"), nil, theUnaryInfo, func(ctx context.Context, req any) (any, error) {
+			_, authenticated = GetAuthenticatedUser(ctx)
+			return nil, nil
+		})
+
+		assert.NoError(t, err)
+		assert.False(t, authenticated)
+	})
+
+	t.Run("returns_unauthenticated_for_an_invalid_token", func(t *testing.T) {
+		_, err := UnaryAuthentication(newFakeAuthenticator(theUser))(incomingContext("authorization", "Bearer theWrongToken"), nil, theUnaryInfo, func(ctx context.Context, req any) (any, error) {
+			t.Fatal("handler should not be called")
+			return nil, nil
+		})
+
+		assert.Equal(t, codes.Unauthenticated, status.Code(err))
+	})
+}
+
+func TestStreamAuthentication(t *testing.T) {
+	t.Run("adds_the_user_to_the_stream_context", func(t *testing.T) {
+		theUser := &AuthenticatedUser{Sub: "theSub"}
+		var user *AuthenticatedUser
+
+		err := StreamAuthentication(newFakeAuthenticator(theUser))(nil, newFakeServerStream(incomingContext("authorization", "Bearer theToken")), theStreamInfo, func(srv any, ss grpc.ServerStream) error {
+			user, _ = GetAuthenticatedUser(ss.Context())
+			return nil
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, theUser, user)
+	})
+}
@@ -0,0 +1,47 @@
+// Package grpckit provides gRPC server interceptors that match the echokit and ginkit middleware:
+// request IDs, trace propagation, slog request logging, panic recovery, JWT authentication, and
+// permission checks. Chain them with grpc.ChainUnaryInterceptor and grpc.ChainStreamInterceptor, e.g.
+//
+//	grpc.NewServer(
+//		grpc.ChainUnaryInterceptor(
+//			grpckit.UnaryRecovery(),
+//			grpckit.UnaryRequestID(),
+//			grpckit.UnaryTracing(),
+//			grpckit.UnaryRequestLogger(),
+//			grpckit.UnaryAuthentication(authenticator),
+//			grpckit.UnaryRequirePermissions(audience, permissions),
+//		),
+//	)
+package grpckit
+
+import (
+	"context"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+type contextKey string
+
+// wrappedServerStream replaces a stream's context so stream interceptors can add values to it
+type wrappedServerStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *wrappedServerStream) Context() context.Context {
+	return s.ctx
+}
+
+func withStreamContext(ss grpc.ServerStream, ctx context.Context) grpc.ServerStream {
+	return &wrappedServerStream{ServerStream: ss, ctx: ctx}
+}
+
+// incomingMetadata returns the first value of key in ctx's incoming metadata
+func incomingMetadata(ctx context.Context, key string) string {
+	values := metadata.ValueFromIncomingContext(ctx, key)
+	if len(values) == 0 {
+		return ""
+	}
+	return values[0]
+}
@@ -0,0 +1,34 @@
+package grpckit
+
+import (
+	"context"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+type fakeServerStream struct {
+	grpc.ServerStream
+	ctx    context.Context
+	header metadata.MD
+}
+
+func newFakeServerStream(ctx context.Context) *fakeServerStream {
+	return &fakeServerStream{ctx: ctx, header: metadata.MD{}}
+}
+
+func (s *fakeServerStream) Context() context.Context {
+	return s.ctx
+}
+
+func (s *fakeServerStream) SetHeader(md metadata.MD) error {
+	s.header = metadata.Join(s.header, md)
+	return nil
+}
+
+func incomingContext(pairs ...string) context.Context {
+	return metadata.NewIncomingContext(context.Background(), metadata.Pairs(pairs...))
+}
+
+var theUnaryInfo = &grpc.UnaryServerInfo{FullMethod: "/users.v1.Users/GetUser"}
+var theStreamInfo = &grpc.StreamServerInfo{FullMethod: "/users.v1.Users/ListUsers"}
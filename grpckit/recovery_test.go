@@ -0,0 +1,48 @@
+package grpckit
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestUnaryRecovery(t *testing.T) {
+	t.Run("returns_an_internal_error_for_a_panic", func(t *testing.T) {
+		logBuf := setTestLogger(t)
+
+		_, err := UnaryRecovery()(context.Background(), nil, theUnaryInfo, func(ctx context.Context, req any) (any, error) {
+			panic("thePanic")
+		})
+
+		assert.Equal(t, codes.Internal, status.Code(err))
+		assert.Contains(t, logBuf.String(), `"msg":"panic recovered"`)
+		assert.Contains(t, logBuf.String(), `"error":"thePanic"`)
+		assert.Contains(t, logBuf.String(), `"method":"/users.v1.Users/GetUser"`)
+		assert.Contains(t, logBuf.String(), `"stack":`)
+	})
+
+	t.Run("returns_the_handler_result_without_a_panic", func(t *testing.T) {
+		resp, err := UnaryRecovery()(context.Background(), nil, theUnaryInfo, func(ctx context.Context, req any) (any, error) {
+			return "theResponse", nil
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, "theResponse", resp)
+	})
+}
+
+func TestStreamRecovery(t *testing.T) {
+	t.Run("returns_an_internal_error_for_a_panic", func(t *testing.T) {
+		setTestLogger(t)
+
+		err := StreamRecovery()(nil, newFakeServerStream(context.Background()), theStreamInfo, func(srv any, ss grpc.ServerStream) error {
+			panic(assert.AnError)
+		})
+
+		assert.Equal(t, codes.Internal, status.Code(err))
+	})
+}
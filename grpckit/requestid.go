@@ -0,0 +1,65 @@
+package grpckit
+
+import (
+	"context"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+
+	"github.com/half-ogre/go-kit/kit"
+)
+
+// RequestIDMetadataKey is the metadata key for request IDs, the gRPC equivalent of X-Request-ID
+const RequestIDMetadataKey = "x-request-id"
+
+const requestIDContextKey contextKey = "github.com/half-ogre/go-kit/grpckit/request_id"
+
+// GetRequestID returns the request ID set by the request ID interceptors, or an empty string
+func GetRequestID(ctx context.Context) string {
+	requestID, _ := ctx.Value(requestIDContextKey).(string)
+	return requestID
+}
+
+// UnaryRequestID returns an interceptor that takes the request ID from the x-request-id metadata,
+// generating one when it is missing, adds it to the context, and sends it back as a response header
+func UnaryRequestID() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		ctx, requestID, err := requestIDContext(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		// SetHeader only fails outside a real server transport, e.g. when interceptors are called directly
+		_ = grpc.SetHeader(ctx, metadata.Pairs(RequestIDMetadataKey, requestID))
+
+		return handler(ctx, req)
+	}
+}
+
+// StreamRequestID is the stream equivalent of UnaryRequestID
+func StreamRequestID() grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx, requestID, err := requestIDContext(ss.Context())
+		if err != nil {
+			return err
+		}
+
+		if err := ss.SetHeader(metadata.Pairs(RequestIDMetadataKey, requestID)); err != nil {
+			return kit.WrapError(err, "failed to set request ID header")
+		}
+
+		return handler(srv, withStreamContext(ss, ctx))
+	}
+}
+
+func requestIDContext(ctx context.Context) (context.Context, string, error) {
+	requestID := incomingMetadata(ctx, RequestIDMetadataKey)
+	if requestID == "" {
+		generated, err := kit.RandomToken(32)
+		if err != nil {
+			return nil, "", kit.WrapError(err, "failed to generate request ID")
+		}
+		requestID = generated
+	}
+	return context.WithValue(ctx, requestIDContextKey, requestID), requestID, nil
+}
@@ -0,0 +1,57 @@
+package grpckit
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+)
+
+func TestUnaryRequestID(t *testing.T) {
+	t.Run("uses_the_request_id_from_the_metadata", func(t *testing.T) {
+		var requestID string
+
+		_, err := UnaryRequestID()(incomingContext(RequestIDMetadataKey, "theRequestID"), nil, theUnaryInfo, func(ctx context.Context, req any) (any, error) {
+			requestID = GetRequestID(ctx)
+			return nil, nil
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, "theRequestID", requestID)
+	})
+
+	t.Run("generates_a_request_id_when_the_metadata_has_none", func(t *testing.T) {
+		var requestID string
+
+		_, err := UnaryRequestID()(context.Background(), nil, theUnaryInfo, func(ctx context.Context, req any) (any, error) {
+			requestID = GetRequestID(ctx)
+			return nil, nil
+		})
+
+		assert.NoError(t, err)
+		assert.Len(t, requestID, 32)
+	})
+}
+
+func TestStreamRequestID(t *testing.T) {
+	t.Run("adds_the_request_id_to_the_stream_context_and_header", func(t *testing.T) {
+		stream := newFakeServerStream(incomingContext(RequestIDMetadataKey, "theRequestID"))
+		var requestID string
+
+		err := StreamRequestID()(nil, stream, theStreamInfo, func(srv any, ss grpc.ServerStream) error {
+			requestID = GetRequestID(ss.Context())
+			return nil
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, "theRequestID", requestID)
+		assert.Equal(t, []string{"theRequestID"}, stream.header.Get(RequestIDMetadataKey))
+	})
+}
+
+func TestGetRequestID(t *testing.T) {
+	t.Run("returns_an_empty_string_without_a_request_id", func(t *testing.T) {
+		assert.Equal(t, "", GetRequestID(context.Background()))
+	})
+}
@@ -0,0 +1,87 @@
+package grpckit
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+
+	"github.com/half-ogre/go-kit/logkit/logfields"
+)
+
+// RequestLoggerConfig defines the configuration for the request logger interceptors
+type RequestLoggerConfig struct {
+	// DebugMethods is a list of full method names (e.g. "/grpc.health.v1.Health/Check") that should be
+	// logged at DEBUG level instead of INFO. All other methods will be logged at INFO level.
+	DebugMethods []string
+}
+
+// UnaryRequestLogger returns an interceptor that logs each call with the same fields as the echokit
+// request logger. All calls are logged at INFO level by default.
+func UnaryRequestLogger() grpc.UnaryServerInterceptor {
+	return UnaryRequestLoggerWithConfig(RequestLoggerConfig{})
+}
+
+// UnaryRequestLoggerWithConfig returns an interceptor that logs each call, with the methods in
+// config.DebugMethods at DEBUG level and all others at INFO level
+func UnaryRequestLoggerWithConfig(config RequestLoggerConfig) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		start := time.Now()
+		resp, err := handler(ctx, req)
+		logRequest(ctx, config, info.FullMethod, "unary", start, err)
+		return resp, err
+	}
+}
+
+// StreamRequestLogger is the stream equivalent of UnaryRequestLogger
+func StreamRequestLogger() grpc.StreamServerInterceptor {
+	return StreamRequestLoggerWithConfig(RequestLoggerConfig{})
+}
+
+// StreamRequestLoggerWithConfig is the stream equivalent of UnaryRequestLoggerWithConfig
+func StreamRequestLoggerWithConfig(config RequestLoggerConfig) grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		start := time.Now()
+		err := handler(srv, ss)
+		logRequest(ss.Context(), config, info.FullMethod, "stream", start, err)
+		return err
+	}
+}
+
+func logRequest(ctx context.Context, config RequestLoggerConfig, method string, kind string, start time.Time, err error) {
+	latency := time.Since(start)
+
+	logLevel := slog.LevelInfo
+	for _, debugMethod := range config.DebugMethods {
+		if method == debugMethod {
+			logLevel = slog.LevelDebug
+			break
+		}
+	}
+
+	errMsg := ""
+	if err != nil {
+		errMsg = err.Error()
+	}
+
+	peerAddress := ""
+	if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+		peerAddress = p.Addr.String()
+	}
+
+	slog.Log(ctx, logLevel, "request",
+		logfields.RequestID(GetRequestID(ctx)),
+		logfields.TraceID(GetTraceContext(ctx).TraceID()),
+		"peer", peerAddress,
+		"method", method,
+		"kind", kind,
+		"user_agent", incomingMetadata(ctx, "user-agent"),
+		"code", status.Code(err).String(),
+		"error", errMsg,
+		logfields.Duration(latency),
+		"duration_human", latency.String(),
+	)
+}
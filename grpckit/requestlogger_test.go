@@ -0,0 +1,81 @@
+package grpckit
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func setTestLogger(t *testing.T) *bytes.Buffer {
+	var logBuf bytes.Buffer
+	previous := slog.Default()
+	slog.SetDefault(slog.New(slog.NewJSONHandler(&logBuf, &slog.HandlerOptions{Level: slog.LevelDebug})))
+	t.Cleanup(func() { slog.SetDefault(previous) })
+	return &logBuf
+}
+
+func TestUnaryRequestLogger(t *testing.T) {
+	t.Run("logs_a_successful_call_at_info_level", func(t *testing.T) {
+		logBuf := setTestLogger(t)
+		ctx := context.WithValue(context.Background(), requestIDContextKey, "theRequestID")
+		ctx = context.WithValue(ctx, traceContextKey, TraceContext{AmznTraceID: "theAmznTraceID"})
+
+		_, err := UnaryRequestLogger()(ctx, nil, theUnaryInfo, func(ctx context.Context, req any) (any, error) {
+			return "theResponse", nil
+		})
+
+		assert.NoError(t, err)
+		logOutput := logBuf.String()
+		assert.Contains(t, logOutput, `"level":"INFO"`)
+		assert.Contains(t, logOutput, `"msg":"request"`)
+		assert.Contains(t, logOutput, `"request_id":"theRequestID"`)
+		assert.Contains(t, logOutput, `"trace_id":"theAmznTraceID"`)
+		assert.Contains(t, logOutput, `"method":"/users.v1.Users/GetUser"`)
+		assert.Contains(t, logOutput, `"kind":"unary"`)
+		assert.Contains(t, logOutput, `"code":"OK"`)
+	})
+
+	t.Run("logs_the_status_code_and_error_of_a_failed_call", func(t *testing.T) {
+		logBuf := setTestLogger(t)
+
+		_, err := UnaryRequestLogger()(context.Background(), nil, theUnaryInfo, func(ctx context.Context, req any) (any, error) {
+			return nil, status.Error(codes.NotFound, "theError")
+		})
+
+		assert.Error(t, err)
+		assert.Contains(t, logBuf.String(), `"code":"NotFound"`)
+		assert.Contains(t, logBuf.String(), `"error":"rpc error: code = NotFound desc = theError"`)
+	})
+
+	t.Run("logs_debug_methods_at_debug_level", func(t *testing.T) {
+		logBuf := setTestLogger(t)
+		config := RequestLoggerConfig{DebugMethods: []string{"/users.v1.Users/GetUser"}}
+
+		_, err := UnaryRequestLoggerWithConfig(config)(context.Background(), nil, theUnaryInfo, func(ctx context.Context, req any) (any, error) {
+			return nil, nil
+		})
+
+		assert.NoError(t, err)
+		assert.Contains(t, logBuf.String(), `"level":"DEBUG"`)
+	})
+}
+
+func TestStreamRequestLogger(t *testing.T) {
+	t.Run("logs_a_stream_call", func(t *testing.T) {
+		logBuf := setTestLogger(t)
+
+		err := StreamRequestLogger()(nil, newFakeServerStream(context.Background()), theStreamInfo, func(srv any, ss grpc.ServerStream) error {
+			return nil
+		})
+
+		assert.NoError(t, err)
+		assert.Contains(t, logBuf.String(), `"method":"/users.v1.Users/ListUsers"`)
+		assert.Contains(t, logBuf.String(), `"kind":"stream"`)
+	})
+}
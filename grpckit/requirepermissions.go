@@ -0,0 +1,59 @@
+package grpckit
+
+import (
+	"context"
+	"log/slog"
+	"slices"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/half-ogre/go-kit/logkit/logfields"
+)
+
+// UnaryRequirePermissions returns an interceptor that checks the authenticated user holds, for
+// audience, every permission listed for the call's full method name (e.g. "/users.v1.Users/DeleteUser")
+// in methodPermissions. Unauthenticated calls get codes.Unauthenticated and users without the
+// permissions get codes.PermissionDenied. Methods not in methodPermissions are not checked.
+func UnaryRequirePermissions(audience string, methodPermissions map[string][]string) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		if err := checkMethodPermissions(ctx, audience, methodPermissions, info.FullMethod); err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+// StreamRequirePermissions is the stream equivalent of UnaryRequirePermissions
+func StreamRequirePermissions(audience string, methodPermissions map[string][]string) grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if err := checkMethodPermissions(ss.Context(), audience, methodPermissions, info.FullMethod); err != nil {
+			return err
+		}
+		return handler(srv, ss)
+	}
+}
+
+func checkMethodPermissions(ctx context.Context, audience string, methodPermissions map[string][]string, method string) error {
+	permissions, ok := methodPermissions[method]
+	if !ok {
+		return nil
+	}
+
+	user, ok := GetAuthenticatedUser(ctx)
+	if !ok {
+		return status.Error(codes.Unauthenticated, "not authenticated")
+	}
+
+	slog.DebugContext(ctx, "checking user permissions", logfields.UserSub(user.Sub), "method", method)
+
+	userPermissions := user.Permissions[audience]
+	for _, permission := range permissions {
+		if !slices.Contains(userPermissions, permission) {
+			return status.Error(codes.PermissionDenied, "permission denied")
+		}
+	}
+
+	return nil
+}
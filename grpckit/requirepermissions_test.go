@@ -0,0 +1,58 @@
+package grpckit
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestUnaryRequirePermissions(t *testing.T) {
+	thePermissions := map[string][]string{"/users.v1.Users/GetUser": {"users:read"}}
+	okHandler := func(ctx context.Context, req any) (any, error) { return "theResponse", nil }
+
+	t.Run("calls_the_handler_when_the_user_has_the_permissions", func(t *testing.T) {
+		ctx := WithAuthenticatedUser(context.Background(), &AuthenticatedUser{Sub: "theSub", Permissions: map[string][]string{"theAudience": {"users:read"}}})
+
+		resp, err := UnaryRequirePermissions("theAudience", thePermissions)(ctx, nil, theUnaryInfo, okHandler)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "theResponse", resp)
+	})
+
+	t.Run("returns_permission_denied_when_the_user_lacks_a_permission", func(t *testing.T) {
+		ctx := WithAuthenticatedUser(context.Background(), &AuthenticatedUser{Sub: "theSub", Permissions: map[string][]string{"theOtherAudience": {"users:read"}}})
+
+		_, err := UnaryRequirePermissions("theAudience", thePermissions)(ctx, nil, theUnaryInfo, okHandler)
+
+		assert.Equal(t, codes.PermissionDenied, status.Code(err))
+	})
+
+	t.Run("returns_unauthenticated_without_a_user", func(t *testing.T) {
+		_, err := UnaryRequirePermissions("theAudience", thePermissions)(context.Background(), nil, theUnaryInfo, okHandler)
+
+		assert.Equal(t, codes.Unauthenticated, status.Code(err))
+	})
+
+	t.Run("does_not_check_methods_without_permissions", func(t *testing.T) {
+		_, err := UnaryRequirePermissions("theAudience", map[string][]string{})(context.Background(), nil, theUnaryInfo, okHandler)
+
+		assert.NoError(t, err)
+	})
+}
+
+func TestStreamRequirePermissions(t *testing.T) {
+	t.Run("returns_permission_denied_when_the_user_lacks_a_permission", func(t *testing.T) {
+		ctx := WithAuthenticatedUser(context.Background(), &AuthenticatedUser{Sub: "theSub"})
+		thePermissions := map[string][]string{"/users.v1.Users/ListUsers": {"users:list"}}
+
+		err := StreamRequirePermissions("theAudience", thePermissions)(nil, newFakeServerStream(ctx), theStreamInfo, func(srv any, ss grpc.ServerStream) error {
+			return nil
+		})
+
+		assert.Equal(t, codes.PermissionDenied, status.Code(err))
+	})
+}
@@ -0,0 +1,98 @@
+package grpckit
+
+import (
+	"context"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+const (
+	// TraceparentMetadataKey is the W3C trace context key, the gRPC equivalent of the Traceparent header
+	TraceparentMetadataKey = "traceparent"
+	// AmznTraceIDMetadataKey is the AWS X-Ray trace key, the gRPC equivalent of X-Amzn-Trace-Id
+	AmznTraceIDMetadataKey = "x-amzn-trace-id"
+)
+
+const traceContextKey contextKey = "github.com/half-ogre/go-kit/grpckit/trace"
+
+// TraceContext is the trace information received with a call
+type TraceContext struct {
+	Traceparent string
+	AmznTraceID string
+}
+
+// TraceID returns the X-Ray trace ID if there is one, otherwise the trace ID from the traceparent
+func (t TraceContext) TraceID() string {
+	if t.AmznTraceID != "" {
+		return t.AmznTraceID
+	}
+	// traceparent is version-traceid-parentid-flags
+	parts := strings.Split(t.Traceparent, "-")
+	if len(parts) == 4 {
+		return parts[1]
+	}
+	return ""
+}
+
+// GetTraceContext returns the trace context set by the tracing interceptors
+func GetTraceContext(ctx context.Context) TraceContext {
+	trace, _ := ctx.Value(traceContextKey).(TraceContext)
+	return trace
+}
+
+// UnaryTracing returns an interceptor that adds the traceparent and x-amzn-trace-id metadata to the
+// context, where the request logger and the client tracing interceptors find them
+func UnaryTracing() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		return handler(traceContext(ctx), req)
+	}
+}
+
+// StreamTracing is the stream equivalent of UnaryTracing
+func StreamTracing() grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		return handler(srv, withStreamContext(ss, traceContext(ss.Context())))
+	}
+}
+
+// UnaryClientTracing returns a client interceptor that forwards the request ID and trace context of
+// the call being handled to outgoing calls, so a chain of services shares them
+func UnaryClientTracing() grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		return invoker(outgoingTraceContext(ctx), method, req, reply, cc, opts...)
+	}
+}
+
+// StreamClientTracing is the stream equivalent of UnaryClientTracing
+func StreamClientTracing() grpc.StreamClientInterceptor {
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		return streamer(outgoingTraceContext(ctx), desc, cc, method, opts...)
+	}
+}
+
+func traceContext(ctx context.Context) context.Context {
+	return context.WithValue(ctx, traceContextKey, TraceContext{
+		Traceparent: incomingMetadata(ctx, TraceparentMetadataKey),
+		AmznTraceID: incomingMetadata(ctx, AmznTraceIDMetadataKey),
+	})
+}
+
+func outgoingTraceContext(ctx context.Context) context.Context {
+	pairs := []string{}
+	if requestID := GetRequestID(ctx); requestID != "" {
+		pairs = append(pairs, RequestIDMetadataKey, requestID)
+	}
+	trace := GetTraceContext(ctx)
+	if trace.Traceparent != "" {
+		pairs = append(pairs, TraceparentMetadataKey, trace.Traceparent)
+	}
+	if trace.AmznTraceID != "" {
+		pairs = append(pairs, AmznTraceIDMetadataKey, trace.AmznTraceID)
+	}
+	if len(pairs) == 0 {
+		return ctx
+	}
+	return metadata.AppendToOutgoingContext(ctx, pairs...)
+}
@@ -0,0 +1,84 @@
+package grpckit
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+const theTraceparent = "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+
+func TestUnaryTracing(t *testing.T) {
+	t.Run("adds_the_trace_metadata_to_the_context", func(t *testing.T) {
+		var trace TraceContext
+
+		_, err := UnaryTracing()(incomingContext(TraceparentMetadataKey, theTraceparent, AmznTraceIDMetadataKey, "theAmznTraceID"), nil, theUnaryInfo, func(ctx context.Context, req any) (any, error) {
+			trace = GetTraceContext(ctx)
+			return nil, nil
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, TraceContext{Traceparent: theTraceparent, AmznTraceID: "theAmznTraceID"}, trace)
+	})
+}
+
+func TestStreamTracing(t *testing.T) {
+	t.Run("adds_the_trace_metadata_to_the_stream_context", func(t *testing.T) {
+		var trace TraceContext
+
+		err := StreamTracing()(nil, newFakeServerStream(incomingContext(TraceparentMetadataKey, theTraceparent)), theStreamInfo, func(srv any, ss grpc.ServerStream) error {
+			trace = GetTraceContext(ss.Context())
+			return nil
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, theTraceparent, trace.Traceparent)
+	})
+}
+
+func TestTraceContextTraceID(t *testing.T) {
+	t.Run("prefers_the_amzn_trace_id", func(t *testing.T) {
+		assert.Equal(t, "theAmznTraceID", TraceContext{Traceparent: theTraceparent, AmznTraceID: "theAmznTraceID"}.TraceID())
+	})
+
+	t.Run("uses_the_trace_id_from_the_traceparent", func(t *testing.T) {
+		assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", TraceContext{Traceparent: theTraceparent}.TraceID())
+	})
+
+	t.Run("returns_an_empty_string_for_a_malformed_traceparent", func(t *testing.T) {
+		assert.Equal(t, "", TraceContext{Traceparent: "theTraceparent"}.TraceID())
+	})
+}
+
+func TestUnaryClientTracing(t *testing.T) {
+	t.Run("forwards_the_request_id_and_trace_context", func(t *testing.T) {
+		ctx := context.WithValue(context.Background(), requestIDContextKey, "theRequestID")
+		ctx = context.WithValue(ctx, traceContextKey, TraceContext{Traceparent: theTraceparent, AmznTraceID: "theAmznTraceID"})
+		var outgoing metadata.MD
+
+		err := UnaryClientTracing()(ctx, "/users.v1.Users/GetUser", nil, nil, nil, func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+			outgoing, _ = metadata.FromOutgoingContext(ctx)
+			return nil
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"theRequestID"}, outgoing.Get(RequestIDMetadataKey))
+		assert.Equal(t, []string{theTraceparent}, outgoing.Get(TraceparentMetadataKey))
+		assert.Equal(t, []string{"theAmznTraceID"}, outgoing.Get(AmznTraceIDMetadataKey))
+	})
+
+	t.Run("leaves_the_context_alone_without_a_trace", func(t *testing.T) {
+		var hasOutgoing bool
+
+		err := UnaryClientTracing()(context.Background(), "/users.v1.Users/GetUser", nil, nil, nil, func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+			_, hasOutgoing = metadata.FromOutgoingContext(ctx)
+			return nil
+		})
+
+		assert.NoError(t, err)
+		assert.False(t, hasOutgoing)
+	})
+}
@@ -0,0 +1,91 @@
+package healthkit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"runtime"
+
+	"github.com/half-ogre/go-kit/kit"
+)
+
+// HTTPCheckOption configures an HTTPCheck
+type HTTPCheckOption func(*httpCheckConfig)
+
+type httpCheckConfig struct {
+	client *http.Client
+}
+
+// WithHTTPCheckClient sets the client an HTTPCheck uses; the default is http.DefaultClient
+func WithHTTPCheckClient(client *http.Client) HTTPCheckOption {
+	return func(c *httpCheckConfig) {
+		c.client = client
+	}
+}
+
+// HTTPCheck returns a check named name that fails unless a GET of url responds with a 2xx status
+func HTTPCheck(name string, url string, options ...HTTPCheckOption) HealthCheck {
+	config := httpCheckConfig{client: http.DefaultClient}
+	for _, option := range options {
+		option(&config)
+	}
+
+	return NewCheck(name, func(ctx context.Context) error {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+		if err != nil {
+			return kit.WrapError(err, "failed to create request for %s", url)
+		}
+
+		res, err := config.client.Do(req)
+		if err != nil {
+			return kit.WrapError(err, "failed to get %s", url)
+		}
+		defer res.Body.Close()
+
+		if res.StatusCode < 200 || res.StatusCode > 299 {
+			return fmt.Errorf("unexpected status %d from %s", res.StatusCode, url)
+		}
+		return nil
+	})
+}
+
+// GoroutineCheck returns a check named "goroutines" that fails when more than max goroutines are
+// running, e.g. because of a leak
+func GoroutineCheck(max int) HealthCheck {
+	return NewCheck("goroutines", func(ctx context.Context) error {
+		if count := runtime.NumGoroutine(); count > max {
+			return fmt.Errorf("%d goroutines exceeds the maximum of %d", count, max)
+		}
+		return nil
+	})
+}
+
+// MemoryCheck returns a check named "memory" that fails when the heap in use exceeds maxHeapBytes
+func MemoryCheck(maxHeapBytes uint64) HealthCheck {
+	return NewCheck("memory", func(ctx context.Context) error {
+		var stats runtime.MemStats
+		runtime.ReadMemStats(&stats)
+		if stats.HeapInuse > maxHeapBytes {
+			return fmt.Errorf("heap in use of %d bytes exceeds the maximum of %d", stats.HeapInuse, maxHeapBytes)
+		}
+		return nil
+	})
+}
+
+// DiskSpaceCheck returns a check named "disk:<path>" that fails when the filesystem holding path has
+// fewer than minFreeBytes available
+func DiskSpaceCheck(path string, minFreeBytes uint64) HealthCheck {
+	return NewCheck("disk:"+path, func(ctx context.Context) error {
+		free, err := freeBytes(path)
+		if err != nil {
+			return err
+		}
+		if free < minFreeBytes {
+			return fmt.Errorf("%d bytes free is below the minimum of %d", free, minFreeBytes)
+		}
+		return nil
+	})
+}
+
+var errDiskSpaceUnsupported = errors.New("disk space checks aren't supported on this platform")
@@ -0,0 +1,75 @@
+package healthkit
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHTTPCheck(t *testing.T) {
+	t.Run("passes_for_a_2xx_response", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusNoContent)
+		}))
+		t.Cleanup(server.Close)
+
+		check := HTTPCheck("theDependency", server.URL, WithHTTPCheckClient(server.Client()))
+
+		assert.Equal(t, "theDependency", check.Name())
+		assert.NoError(t, check.Check(context.Background()))
+	})
+
+	t.Run("fails_for_a_non_2xx_response", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusBadGateway)
+		}))
+		t.Cleanup(server.Close)
+
+		err := HTTPCheck("theDependency", server.URL).Check(context.Background())
+
+		assert.EqualError(t, err, "unexpected status 502 from "+server.URL)
+	})
+}
+
+func TestGoroutineCheck(t *testing.T) {
+	t.Run("passes_under_the_maximum", func(t *testing.T) {
+		assert.NoError(t, GoroutineCheck(1_000_000).Check(context.Background()))
+	})
+
+	t.Run("fails_over_the_maximum", func(t *testing.T) {
+		assert.Error(t, GoroutineCheck(0).Check(context.Background()))
+	})
+}
+
+func TestMemoryCheck(t *testing.T) {
+	t.Run("passes_under_the_maximum", func(t *testing.T) {
+		assert.NoError(t, MemoryCheck(1<<62).Check(context.Background()))
+	})
+
+	t.Run("fails_over_the_maximum", func(t *testing.T) {
+		assert.Error(t, MemoryCheck(1).Check(context.Background()))
+	})
+}
+
+func TestDiskSpaceCheck(t *testing.T) {
+	t.Run("passes_with_enough_free_space", func(t *testing.T) {
+		check := DiskSpaceCheck(t.TempDir(), 1)
+
+		assert.NoError(t, check.Check(context.Background()))
+	})
+
+	t.Run("fails_without_enough_free_space", func(t *testing.T) {
+		check := DiskSpaceCheck(t.TempDir(), 1<<62)
+
+		assert.ErrorContains(t, check.Check(context.Background()), "is below the minimum")
+	})
+
+	t.Run("returns_an_error_for_a_missing_path", func(t *testing.T) {
+		check := DiskSpaceCheck("/does/not/exist", 1)
+
+		assert.ErrorContains(t, check.Check(context.Background()), "failed to stat filesystem for /does/not/exist")
+	})
+}
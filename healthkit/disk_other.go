@@ -0,0 +1,7 @@
+//go:build !(linux || darwin || freebsd)
+
+package healthkit
+
+func freeBytes(path string) (uint64, error) {
+	return 0, errDiskSpaceUnsupported
+}
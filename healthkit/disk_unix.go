@@ -0,0 +1,17 @@
+//go:build linux || darwin || freebsd
+
+package healthkit
+
+import (
+	"syscall"
+
+	"github.com/half-ogre/go-kit/kit"
+)
+
+func freeBytes(path string) (uint64, error) {
+	var stat syscall.Statfs_t
+	if err := syscall.Statfs(path, &stat); err != nil {
+		return 0, kit.WrapError(err, "failed to stat filesystem for %s", path)
+	}
+	return uint64(stat.Bavail) * uint64(stat.Bsize), nil
+}
@@ -0,0 +1,241 @@
+// Package healthkit runs health checks for liveness and readiness endpoints. The built-in checks
+// cover HTTP dependencies, disk space, goroutines, and memory; pgkit.HealthCheck and
+// dynamodbkit.TableHealthCheck cover Postgres and DynamoDB without healthkit depending on either.
+// echokit and ginkit register the endpoints with RegisterHealthRoutes, and Shutdown fails readiness
+// before stopping servers and closing dependencies.
+package healthkit
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Status is the health of a check or of a whole report
+type Status string
+
+const (
+	StatusUp       Status = "up"
+	StatusDegraded Status = "degraded"
+	StatusDown     Status = "down"
+)
+
+// HealthCheck checks one dependency, returning an error when it's unhealthy
+type HealthCheck interface {
+	Name() string
+	Check(ctx context.Context) error
+}
+
+type checkFunc struct {
+	name string
+	fn   func(ctx context.Context) error
+}
+
+func (c *checkFunc) Name() string {
+	return c.name
+}
+
+func (c *checkFunc) Check(ctx context.Context) error {
+	return c.fn(ctx)
+}
+
+// NewCheck returns a HealthCheck named name that calls fn
+func NewCheck(name string, fn func(ctx context.Context) error) HealthCheck {
+	return &checkFunc{name: name, fn: fn}
+}
+
+// CheckerOption configures a Checker
+type CheckerOption func(*checkerConfig)
+
+type checkerConfig struct {
+	timeout time.Duration
+}
+
+// WithTimeout sets how long each check may run before it's reported down; the default is 5 seconds
+func WithTimeout(timeout time.Duration) CheckerOption {
+	return func(c *checkerConfig) {
+		c.timeout = timeout
+	}
+}
+
+// CheckOption configures a check registered with a Checker
+type CheckOption func(*registeredCheck)
+
+// WithNonCritical makes a failure of the check degrade the report rather than take it down, e.g. for
+// a cache the service can run without
+func WithNonCritical() CheckOption {
+	return func(c *registeredCheck) {
+		c.nonCritical = true
+	}
+}
+
+type registeredCheck struct {
+	check       HealthCheck
+	nonCritical bool
+}
+
+// Checker runs the registered health checks
+type Checker struct {
+	config       checkerConfig
+	mu           sync.RWMutex
+	checks       []registeredCheck
+	shuttingDown atomic.Bool
+}
+
+// NewChecker creates a Checker with no checks
+func NewChecker(options ...CheckerOption) *Checker {
+	config := checkerConfig{timeout: 5 * time.Second}
+	for _, option := range options {
+		option(&config)
+	}
+	return &Checker{config: config}
+}
+
+// Register adds check to the checks run by Check
+func (c *Checker) Register(check HealthCheck, options ...CheckOption) {
+	registered := registeredCheck{check: check}
+	for _, option := range options {
+		option(&registered)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.checks = append(c.checks, registered)
+}
+
+// SetShuttingDown makes readiness report down without running the checks, so a load balancer stops
+// sending traffic while in-flight requests drain. Liveness is unaffected.
+func (c *Checker) SetShuttingDown() {
+	c.shuttingDown.Store(true)
+}
+
+// ShuttingDown reports whether SetShuttingDown has been called
+func (c *Checker) ShuttingDown() bool {
+	return c.shuttingDown.Load()
+}
+
+// CheckResult is the outcome of one check
+type CheckResult struct {
+	Name       string `json:"name"`
+	Status     Status `json:"status"`
+	Error      string `json:"error,omitempty"`
+	DurationMS int64  `json:"duration_ms"`
+}
+
+// Report is the outcome of all the checks: down if any critical check failed, degraded if only
+// non-critical checks failed, and up otherwise
+type Report struct {
+	Status Status        `json:"status"`
+	Checks []CheckResult `json:"checks"`
+}
+
+// HTTPStatus returns 503 for a report that's down and 200 otherwise, so a degraded service still
+// takes traffic
+func (r Report) HTTPStatus() int {
+	if r.Status == StatusDown {
+		return http.StatusServiceUnavailable
+	}
+	return http.StatusOK
+}
+
+// Check runs the registered checks concurrently, each with the checker's timeout, and returns the
+// report with the results in registration order
+func (c *Checker) Check(ctx context.Context) Report {
+	c.mu.RLock()
+	checks := append([]registeredCheck(nil), c.checks...)
+	c.mu.RUnlock()
+
+	results := make([]CheckResult, len(checks))
+	var wg sync.WaitGroup
+	for i, registered := range checks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i] = c.run(ctx, registered)
+		}()
+	}
+	wg.Wait()
+
+	report := Report{Status: StatusUp, Checks: results}
+	for _, result := range results {
+		switch result.Status {
+		case StatusDown:
+			report.Status = StatusDown
+		case StatusDegraded:
+			if report.Status == StatusUp {
+				report.Status = StatusDegraded
+			}
+		}
+	}
+	return report
+}
+
+func (c *Checker) run(ctx context.Context, registered registeredCheck) CheckResult {
+	if c.config.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.config.timeout)
+		defer cancel()
+	}
+
+	started := time.Now()
+	err := runCheck(ctx, registered.check)
+	result := CheckResult{
+		Name:       registered.check.Name(),
+		Status:     StatusUp,
+		DurationMS: time.Since(started).Milliseconds(),
+	}
+	if err != nil {
+		result.Error = err.Error()
+		result.Status = StatusDown
+		if registered.nonCritical {
+			result.Status = StatusDegraded
+		}
+	}
+	return result
+}
+
+// runCheck returns when check does or ctx is done, whichever is first, so a check that ignores its
+// context can't hang the report
+func runCheck(ctx context.Context, check HealthCheck) error {
+	done := make(chan error, 1)
+	go func() {
+		done <- check.Check(ctx)
+	}()
+
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// ReadinessHandler serves the report as JSON, with a 503 when it's down or the checker is shutting
+// down
+func (c *Checker) ReadinessHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		report := Report{Status: StatusDown, Checks: []CheckResult{}}
+		if !c.ShuttingDown() {
+			report = c.Check(r.Context())
+		}
+		writeReport(w, report)
+	})
+}
+
+// LivenessHandler serves an up report without running any checks, as a dependency being down isn't a
+// reason to restart the process
+func (c *Checker) LivenessHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		writeReport(w, Report{Status: StatusUp, Checks: []CheckResult{}})
+	})
+}
+
+func writeReport(w http.ResponseWriter, report Report) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(report.HTTPStatus())
+	_ = json.NewEncoder(w).Encode(report)
+}
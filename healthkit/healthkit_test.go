@@ -0,0 +1,144 @@
+package healthkit
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func passingCheck(name string) HealthCheck {
+	return NewCheck(name, func(ctx context.Context) error { return nil })
+}
+
+func failingCheck(name string) HealthCheck {
+	return NewCheck(name, func(ctx context.Context) error { return errors.New("the check error") })
+}
+
+func TestCheckerCheck(t *testing.T) {
+	t.Run("reports_up_when_every_check_passes", func(t *testing.T) {
+		checker := NewChecker()
+		checker.Register(passingCheck("first"))
+		checker.Register(passingCheck("second"))
+
+		report := checker.Check(context.Background())
+
+		assert.Equal(t, StatusUp, report.Status)
+		assert.Len(t, report.Checks, 2)
+		assert.Equal(t, "first", report.Checks[0].Name)
+		assert.Equal(t, StatusUp, report.Checks[0].Status)
+		assert.Equal(t, "second", report.Checks[1].Name)
+		assert.Equal(t, http.StatusOK, report.HTTPStatus())
+	})
+
+	t.Run("reports_down_when_a_critical_check_fails", func(t *testing.T) {
+		checker := NewChecker()
+		checker.Register(passingCheck("first"))
+		checker.Register(failingCheck("second"))
+
+		report := checker.Check(context.Background())
+
+		assert.Equal(t, StatusDown, report.Status)
+		assert.Equal(t, StatusDown, report.Checks[1].Status)
+		assert.Equal(t, "the check error", report.Checks[1].Error)
+		assert.Equal(t, http.StatusServiceUnavailable, report.HTTPStatus())
+	})
+
+	t.Run("reports_degraded_when_only_a_non_critical_check_fails", func(t *testing.T) {
+		checker := NewChecker()
+		checker.Register(passingCheck("first"))
+		checker.Register(failingCheck("second"), WithNonCritical())
+
+		report := checker.Check(context.Background())
+
+		assert.Equal(t, StatusDegraded, report.Status)
+		assert.Equal(t, StatusDegraded, report.Checks[1].Status)
+		assert.Equal(t, http.StatusOK, report.HTTPStatus())
+	})
+
+	t.Run("reports_down_over_degraded", func(t *testing.T) {
+		checker := NewChecker()
+		checker.Register(failingCheck("first"), WithNonCritical())
+		checker.Register(failingCheck("second"))
+
+		report := checker.Check(context.Background())
+
+		assert.Equal(t, StatusDown, report.Status)
+	})
+
+	t.Run("reports_a_check_that_ignores_its_context_down_after_the_timeout", func(t *testing.T) {
+		release := make(chan struct{})
+		t.Cleanup(func() { close(release) })
+		checker := NewChecker(WithTimeout(10 * time.Millisecond))
+		checker.Register(NewCheck("theCheck", func(ctx context.Context) error {
+			<-release
+			return nil
+		}))
+
+		report := checker.Check(context.Background())
+
+		assert.Equal(t, StatusDown, report.Status)
+		assert.Equal(t, context.DeadlineExceeded.Error(), report.Checks[0].Error)
+	})
+
+	t.Run("reports_up_with_no_checks", func(t *testing.T) {
+		report := NewChecker().Check(context.Background())
+
+		assert.Equal(t, StatusUp, report.Status)
+		assert.Empty(t, report.Checks)
+	})
+}
+
+func TestCheckerReadinessHandler(t *testing.T) {
+	t.Run("serves_the_report_as_json", func(t *testing.T) {
+		checker := NewChecker()
+		checker.Register(failingCheck("theCheck"))
+		rec := httptest.NewRecorder()
+
+		checker.ReadinessHandler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+
+		assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+		assert.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+		assert.Equal(t, "no-store", rec.Header().Get("Cache-Control"))
+		var report Report
+		assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &report))
+		assert.Equal(t, StatusDown, report.Status)
+		assert.Equal(t, "theCheck", report.Checks[0].Name)
+	})
+
+	t.Run("reports_down_without_running_the_checks_when_shutting_down", func(t *testing.T) {
+		checker := NewChecker()
+		ran := false
+		checker.Register(NewCheck("theCheck", func(ctx context.Context) error {
+			ran = true
+			return nil
+		}))
+		checker.SetShuttingDown()
+		rec := httptest.NewRecorder()
+
+		checker.ReadinessHandler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+
+		assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+		assert.False(t, ran)
+		assert.True(t, checker.ShuttingDown())
+	})
+}
+
+func TestCheckerLivenessHandler(t *testing.T) {
+	t.Run("serves_up_without_running_the_checks", func(t *testing.T) {
+		checker := NewChecker()
+		checker.Register(failingCheck("theCheck"))
+		checker.SetShuttingDown()
+		rec := httptest.NewRecorder()
+
+		checker.LivenessHandler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/livez", nil))
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.JSONEq(t, `{"status":"up","checks":[]}`, rec.Body.String())
+	})
+}
@@ -0,0 +1,102 @@
+package healthkit
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/half-ogre/go-kit/kit"
+)
+
+// ShutdownOption configures a Shutdown
+type ShutdownOption func(*shutdownConfig)
+
+type shutdownConfig struct {
+	drainDelay time.Duration
+	timeout    time.Duration
+}
+
+// WithDrainDelay sets how long Shutdown waits after readiness starts failing before it runs the
+// hooks, giving load balancers time to stop sending traffic; the default is 5 seconds
+func WithDrainDelay(delay time.Duration) ShutdownOption {
+	return func(c *shutdownConfig) {
+		c.drainDelay = delay
+	}
+}
+
+// WithShutdownTimeout sets how long the hooks have to finish once they start; the default is 30
+// seconds
+func WithShutdownTimeout(timeout time.Duration) ShutdownOption {
+	return func(c *shutdownConfig) {
+		c.timeout = timeout
+	}
+}
+
+type shutdownHook struct {
+	name string
+	fn   func(ctx context.Context) error
+}
+
+// Shutdown orchestrates a graceful shutdown: it sets the checker shutting down so readiness fails,
+// waits out the drain delay, and then runs the hooks in reverse registration order, like defer, so a
+// server registered after its dependencies stops before they're closed
+type Shutdown struct {
+	checker *Checker
+	config  shutdownConfig
+	hooks   []shutdownHook
+}
+
+// NewShutdown creates a Shutdown for checker with no hooks
+func NewShutdown(checker *Checker, options ...ShutdownOption) *Shutdown {
+	config := shutdownConfig{drainDelay: 5 * time.Second, timeout: 30 * time.Second}
+	for _, option := range options {
+		option(&config)
+	}
+	return &Shutdown{checker: checker, config: config}
+}
+
+// OnShutdown registers fn to run during shutdown, e.g. an echo.Echo's or http.Server's Shutdown or a
+// pgkit.DB's Close
+func (s *Shutdown) OnShutdown(name string, fn func(ctx context.Context) error) {
+	s.hooks = append(s.hooks, shutdownHook{name: name, fn: fn})
+}
+
+// Run shuts down immediately, returning the hooks' errors joined. Every hook runs even when an
+// earlier one fails.
+func (s *Shutdown) Run(ctx context.Context) error {
+	s.checker.SetShuttingDown()
+
+	if s.config.drainDelay > 0 {
+		slog.InfoContext(ctx, "draining before shutdown", "delay", s.config.drainDelay)
+		select {
+		case <-time.After(s.config.drainDelay):
+		case <-ctx.Done():
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), s.config.timeout)
+	defer cancel()
+
+	var errs []error
+	for i := len(s.hooks) - 1; i >= 0; i-- {
+		hook := s.hooks[i]
+		if err := hook.fn(ctx); err != nil {
+			slog.ErrorContext(ctx, "shutdown hook failed", "hook", hook.name, "error", err)
+			errs = append(errs, kit.WrapError(err, "failed to shut down %s", hook.name))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Wait blocks until the process receives SIGINT or SIGTERM, or ctx is done, and then calls Run
+func (s *Shutdown) Wait(ctx context.Context) error {
+	signalCtx, stop := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	<-signalCtx.Done()
+	return s.Run(ctx)
+}
@@ -0,0 +1,91 @@
+package healthkit
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestShutdownRun(t *testing.T) {
+	t.Run("fails_readiness_before_running_the_hooks", func(t *testing.T) {
+		checker := NewChecker()
+		shutdown := NewShutdown(checker, WithDrainDelay(0))
+		var shuttingDownInHook bool
+		shutdown.OnShutdown("theServer", func(ctx context.Context) error {
+			shuttingDownInHook = checker.ShuttingDown()
+			return nil
+		})
+
+		err := shutdown.Run(context.Background())
+
+		assert.NoError(t, err)
+		assert.True(t, shuttingDownInHook)
+	})
+
+	t.Run("runs_the_hooks_in_reverse_registration_order", func(t *testing.T) {
+		shutdown := NewShutdown(NewChecker(), WithDrainDelay(0))
+		var order []string
+		for _, name := range []string{"theDatabase", "theCache", "theServer"} {
+			shutdown.OnShutdown(name, func(ctx context.Context) error {
+				order = append(order, name)
+				return nil
+			})
+		}
+
+		err := shutdown.Run(context.Background())
+
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"theServer", "theCache", "theDatabase"}, order)
+	})
+
+	t.Run("runs_every_hook_and_joins_their_errors", func(t *testing.T) {
+		shutdown := NewShutdown(NewChecker(), WithDrainDelay(0))
+		ran := false
+		shutdown.OnShutdown("theDatabase", func(ctx context.Context) error {
+			ran = true
+			return nil
+		})
+		shutdown.OnShutdown("theServer", func(ctx context.Context) error { return errors.New("the shutdown error") })
+
+		err := shutdown.Run(context.Background())
+
+		assert.EqualError(t, err, "failed to shut down theServer: the shutdown error")
+		assert.True(t, ran)
+	})
+
+	t.Run("gives_the_hooks_the_shutdown_timeout_even_when_ctx_is_done", func(t *testing.T) {
+		shutdown := NewShutdown(NewChecker(), WithDrainDelay(time.Hour), WithShutdownTimeout(time.Minute))
+		var hookCtxErr error
+		var hasDeadline bool
+		shutdown.OnShutdown("theServer", func(ctx context.Context) error {
+			hookCtxErr = ctx.Err()
+			_, hasDeadline = ctx.Deadline()
+			return nil
+		})
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		err := shutdown.Run(ctx)
+
+		assert.NoError(t, err)
+		assert.NoError(t, hookCtxErr)
+		assert.True(t, hasDeadline)
+	})
+}
+
+func TestShutdownWait(t *testing.T) {
+	t.Run("runs_the_shutdown_when_ctx_is_done", func(t *testing.T) {
+		checker := NewChecker()
+		shutdown := NewShutdown(checker, WithDrainDelay(0))
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		err := shutdown.Wait(ctx)
+
+		assert.NoError(t, err)
+		assert.True(t, checker.ShuttingDown())
+	})
+}
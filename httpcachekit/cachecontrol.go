@@ -0,0 +1,78 @@
+package httpcachekit
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+// CacheControl is the directives of a Cache-Control header, by lowercase name, with their values
+// unquoted; directives without a value have an empty one
+type CacheControl map[string]string
+
+// ParseCacheControl parses the directives of a Cache-Control header, e.g.
+// `max-age=300, must-revalidate` or `no-cache="Set-Cookie"`
+func ParseCacheControl(header string) CacheControl {
+	cc := CacheControl{}
+	for _, directive := range splitDirectives(header) {
+		name, value, _ := strings.Cut(directive, "=")
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" {
+			continue
+		}
+		value = strings.TrimSpace(value)
+		if unquoted, err := strconv.Unquote(value); err == nil && strings.HasPrefix(value, `"`) {
+			value = unquoted
+		}
+		cc[name] = value
+	}
+	return cc
+}
+
+// splitDirectives splits a Cache-Control header on the commas that aren't in quoted values
+func splitDirectives(header string) []string {
+	var directives []string
+	quoted := false
+	start := 0
+	for i := 0; i < len(header); i++ {
+		switch header[i] {
+		case '\\':
+			if quoted {
+				i++
+			}
+		case '"':
+			quoted = !quoted
+		case ',':
+			if !quoted {
+				directives = append(directives, header[start:i])
+				start = i + 1
+			}
+		}
+	}
+	return append(directives, header[start:])
+}
+
+// Has returns whether the directive is present
+func (cc CacheControl) Has(directive string) bool {
+	_, ok := cc[directive]
+	return ok
+}
+
+// Seconds returns the value of a delta-seconds directive, e.g. max-age, and whether it's present with a
+// valid value
+func (cc CacheControl) Seconds(directive string) (time.Duration, bool) {
+	value, ok := cc[directive]
+	if !ok {
+		return 0, false
+	}
+	seconds, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || seconds < 0 {
+		return 0, false
+	}
+	if seconds > int64(maxDuration/time.Second) {
+		return maxDuration, true
+	}
+	return time.Duration(seconds) * time.Second, true
+}
+
+const maxDuration = time.Duration(1<<63 - 1)
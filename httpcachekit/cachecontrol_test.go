@@ -0,0 +1,49 @@
+package httpcachekit
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseCacheControl(t *testing.T) {
+	t.Run("parses_directives_with_and_without_values", func(t *testing.T) {
+		cc := ParseCacheControl("Max-Age=300, must-revalidate")
+
+		assert.Equal(t, CacheControl{"max-age": "300", "must-revalidate": ""}, cc)
+	})
+
+	t.Run("unquotes_values_and_keeps_commas_in_quotes", func(t *testing.T) {
+		cc := ParseCacheControl(`no-cache="Set-Cookie, X-Token", private`)
+
+		assert.Equal(t, CacheControl{"no-cache": "Set-Cookie, X-Token", "private": ""}, cc)
+	})
+
+	t.Run("skips_empty_directives", func(t *testing.T) {
+		cc := ParseCacheControl(" , no-store,")
+
+		assert.Equal(t, CacheControl{"no-store": ""}, cc)
+	})
+}
+
+func TestCacheControlSeconds(t *testing.T) {
+	t.Run("returns_the_duration_of_a_delta_seconds_directive", func(t *testing.T) {
+		seconds, ok := ParseCacheControl("max-age=60").Seconds("max-age")
+
+		assert.True(t, ok)
+		assert.Equal(t, time.Minute, seconds)
+	})
+
+	t.Run("returns_false_when_the_value_is_invalid", func(t *testing.T) {
+		_, ok := ParseCacheControl("max-age=soon").Seconds("max-age")
+
+		assert.False(t, ok)
+	})
+
+	t.Run("returns_false_when_the_directive_is_missing", func(t *testing.T) {
+		_, ok := ParseCacheControl("no-cache").Seconds("max-age")
+
+		assert.False(t, ok)
+	})
+}
@@ -0,0 +1,205 @@
+package httpcachekit
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// heuristicallyCacheable are the status codes that can be stored without explicit freshness, RFC 9111
+// section 4.2.2
+var heuristicallyCacheable = map[int]bool{
+	http.StatusOK:                   true,
+	http.StatusNonAuthoritativeInfo: true,
+	http.StatusNoContent:            true,
+	http.StatusMultipleChoices:      true,
+	http.StatusMovedPermanently:     true,
+	http.StatusPermanentRedirect:    true,
+	http.StatusNotFound:             true,
+	http.StatusMethodNotAllowed:     true,
+	http.StatusGone:                 true,
+	http.StatusRequestURITooLong:    true,
+	http.StatusNotImplemented:       true,
+}
+
+// maxHeuristicFreshness caps the freshness guessed from Last-Modified
+const maxHeuristicFreshness = 24 * time.Hour
+
+// entry is a stored response
+type entry struct {
+	StatusCode   int         `json:"status"`
+	Header       http.Header `json:"header"`
+	Body         []byte      `json:"body"`
+	Vary         http.Header `json:"vary,omitempty"`
+	RequestTime  time.Time   `json:"requestTime"`
+	ResponseTime time.Time   `json:"responseTime"`
+}
+
+// newEntry returns the entry storing resp with body, received for req
+func newEntry(req *http.Request, resp *http.Response, body []byte, requestTime time.Time, responseTime time.Time) *entry {
+	e := &entry{
+		StatusCode:   resp.StatusCode,
+		Header:       resp.Header.Clone(),
+		Body:         body,
+		RequestTime:  requestTime,
+		ResponseTime: responseTime,
+	}
+	for _, name := range varyNames(resp.Header) {
+		if e.Vary == nil {
+			e.Vary = http.Header{}
+		}
+		e.Vary[name] = req.Header.Values(name)
+	}
+	return e
+}
+
+// varyNames returns the canonical names of the request headers the response varies by
+func varyNames(header http.Header) []string {
+	var names []string
+	for _, value := range header.Values("Vary") {
+		for _, name := range strings.Split(value, ",") {
+			if name = strings.TrimSpace(name); name != "" {
+				names = append(names, http.CanonicalHeaderKey(name))
+			}
+		}
+	}
+	return names
+}
+
+// matches returns whether the entry can answer req, i.e. req has the same values for the headers the
+// response varies by
+func (e *entry) matches(req *http.Request) bool {
+	for _, name := range varyNames(e.Header) {
+		if name == "*" {
+			return false
+		}
+		if strings.Join(req.Header.Values(name), ",") != strings.Join(e.Vary[name], ",") {
+			return false
+		}
+	}
+	return true
+}
+
+// age returns the current age of the response, RFC 9111 section 4.2.3
+func (e *entry) age(now time.Time) time.Duration {
+	apparentAge := time.Duration(0)
+	if date, err := http.ParseTime(e.Header.Get("Date")); err == nil {
+		apparentAge = max(0, e.ResponseTime.Sub(date))
+	}
+
+	ageValue := time.Duration(0)
+	if seconds, err := strconv.ParseInt(e.Header.Get("Age"), 10, 64); err == nil && seconds > 0 {
+		ageValue = time.Duration(seconds) * time.Second
+	}
+	correctedAgeValue := ageValue + e.ResponseTime.Sub(e.RequestTime)
+
+	return max(apparentAge, correctedAgeValue) + max(0, now.Sub(e.ResponseTime))
+}
+
+// freshnessLifetime returns how long the response is fresh for, RFC 9111 section 4.2.1
+func (e *entry) freshnessLifetime() time.Duration {
+	cc := ParseCacheControl(strings.Join(e.Header.Values("Cache-Control"), ","))
+	if maxAge, ok := cc.Seconds("max-age"); ok {
+		return maxAge
+	}
+
+	date, err := http.ParseTime(e.Header.Get("Date"))
+	if err != nil {
+		date = e.ResponseTime
+	}
+
+	if expiresHeader := e.Header.Get("Expires"); expiresHeader != "" {
+		expires, err := http.ParseTime(expiresHeader)
+		if err != nil {
+			return 0
+		}
+		return max(0, expires.Sub(date))
+	}
+
+	if heuristicallyCacheable[e.StatusCode] {
+		if lastModified, err := http.ParseTime(e.Header.Get("Last-Modified")); err == nil {
+			return min(max(0, date.Sub(lastModified)/10), maxHeuristicFreshness)
+		}
+	}
+
+	return 0
+}
+
+// canServe returns whether the entry can be used without revalidating it, at age, given the request's
+// cache directives
+func (e *entry) canServe(reqCC CacheControl, age time.Duration) bool {
+	cc := ParseCacheControl(strings.Join(e.Header.Values("Cache-Control"), ","))
+	if cc.Has("no-cache") || reqCC.Has("no-cache") || e.Header.Get("Pragma") == "no-cache" && !cc.Has("max-age") {
+		return false
+	}
+
+	lifetime := e.freshnessLifetime()
+	if maxAge, ok := reqCC.Seconds("max-age"); ok {
+		if age > maxAge {
+			return false
+		}
+	}
+	if minFresh, ok := reqCC.Seconds("min-fresh"); ok {
+		age += minFresh
+	}
+	if age < lifetime {
+		return true
+	}
+
+	if cc.Has("must-revalidate") || !reqCC.Has("max-stale") {
+		return false
+	}
+	maxStale, ok := reqCC.Seconds("max-stale")
+	return !ok || age-lifetime < maxStale
+}
+
+// hasValidators returns whether the response can be revalidated
+func (e *entry) hasValidators() bool {
+	return e.Header.Get("ETag") != "" || e.Header.Get("Last-Modified") != ""
+}
+
+// revalidationRequest returns a copy of req asking whether the stored response is still current
+func (e *entry) revalidationRequest(req *http.Request) *http.Request {
+	conditional := req.Clone(req.Context())
+	if etag := e.Header.Get("ETag"); etag != "" {
+		conditional.Header.Set("If-None-Match", etag)
+	}
+	if lastModified := e.Header.Get("Last-Modified"); lastModified != "" {
+		conditional.Header.Set("If-Modified-Since", lastModified)
+	}
+	return conditional
+}
+
+// update freshens the entry with the headers of a 304 Not Modified response, RFC 9111 section 4.3.4
+func (e *entry) update(notModified *http.Response, requestTime time.Time, responseTime time.Time) {
+	for name, values := range notModified.Header {
+		if name == "Content-Length" {
+			continue
+		}
+		e.Header[name] = values
+	}
+	e.RequestTime = requestTime
+	e.ResponseTime = responseTime
+}
+
+// response returns the stored response for req, at age
+func (e *entry) response(req *http.Request, age time.Duration) *http.Response {
+	header := e.Header.Clone()
+	header.Set("Age", strconv.FormatInt(int64(age/time.Second), 10))
+	header.Set(FromCacheHeader, "1")
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", e.StatusCode, http.StatusText(e.StatusCode)),
+		StatusCode:    e.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader(e.Body)),
+		ContentLength: int64(len(e.Body)),
+		Request:       req,
+	}
+}
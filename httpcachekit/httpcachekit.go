@@ -0,0 +1,239 @@
+// Package httpcachekit is a client-side HTTP cache: an http.RoundTripper that keeps responses as RFC
+// 9111 allows a private cache to and answers repeated requests from them while they're fresh, then
+// revalidates them with If-None-Match and If-Modified-Since, so a 304 Not Modified refreshes them
+// without downloading them again. It suits the documents a service fetches over and over, e.g. JWKS and
+// OIDC discovery documents, which the echokit JWT authenticators fetch through it, and third-party API
+// responses. Responses are kept in memory with MemoryStore or on disk with DiskStore.
+//
+//	client := httpcachekit.NewClient(httpcachekit.NewMemoryStore(1000))
+//	resp, err := client.Get("https://example.com/.well-known/openid-configuration")
+package httpcachekit
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/half-ogre/go-kit/kit"
+	"github.com/half-ogre/go-kit/logkit"
+)
+
+var logger = logkit.Logger("httpcachekit")
+
+// FromCacheHeader is set to 1 on responses answered from the cache, including ones revalidated with a
+// 304 Not Modified
+const FromCacheHeader = "X-From-Cache"
+
+// FromCache returns whether resp was answered from the cache
+func FromCache(resp *http.Response) bool {
+	return resp.Header.Get(FromCacheHeader) == "1"
+}
+
+// TransportOption configures a Transport
+type TransportOption func(*Transport)
+
+// WithTransportBase sets the RoundTripper that makes the requests the cache can't answer; the default
+// is http.DefaultTransport
+func WithTransportBase(base http.RoundTripper) TransportOption {
+	return func(t *Transport) {
+		t.base = base
+	}
+}
+
+// WithTransportClock sets the clock ages and freshness are measured with
+func WithTransportClock(clock kit.ClockInterface) TransportOption {
+	return func(t *Transport) {
+		t.clock = clock
+	}
+}
+
+// WithMaxBodySize sets the largest response body that's stored; larger responses are passed through
+// without being stored. The default is 10 MiB.
+func WithMaxBodySize(maxBodySize int64) TransportOption {
+	return func(t *Transport) {
+		t.maxBodySize = maxBodySize
+	}
+}
+
+// Transport is an http.RoundTripper caching GET responses in a Store. Successful requests with other
+// methods, e.g. POST and DELETE, invalidate the stored response for their URL. Requests with their own
+// conditional or Range headers, and requests or responses with no-store, aren't cached. Store errors
+// are logged rather than returned, falling back to the network.
+type Transport struct {
+	store       Store
+	base        http.RoundTripper
+	clock       kit.ClockInterface
+	maxBodySize int64
+}
+
+// NewTransport returns a Transport keeping responses in store
+func NewTransport(store Store, options ...TransportOption) *Transport {
+	t := &Transport{
+		store:       store,
+		base:        http.DefaultTransport,
+		clock:       kit.NewClock(),
+		maxBodySize: 10 << 20,
+	}
+	for _, option := range options {
+		option(t)
+	}
+	return t
+}
+
+// NewClient returns an http.Client with a Transport keeping responses in store
+func NewClient(store Store, options ...TransportOption) *http.Client {
+	return &http.Client{Transport: NewTransport(store, options...)}
+}
+
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+	key := cacheKey(req)
+
+	if req.Method != http.MethodGet && req.Method != http.MethodHead {
+		resp, err := t.base.RoundTrip(req)
+		if err == nil && resp.StatusCode < 400 {
+			t.delete(ctx, key)
+		}
+		return resp, err
+	}
+
+	reqCC := ParseCacheControl(strings.Join(req.Header.Values("Cache-Control"), ","))
+	if req.Method != http.MethodGet || reqCC.Has("no-store") || hasConditions(req) {
+		return t.base.RoundTrip(req)
+	}
+
+	stored := t.load(ctx, key)
+	if stored != nil && !stored.matches(req) {
+		stored = nil
+	}
+
+	outgoing := req
+	if stored != nil {
+		age := stored.age(t.clock.Now())
+		if stored.canServe(reqCC, age) {
+			return stored.response(req, age), nil
+		}
+		if stored.hasValidators() {
+			outgoing = stored.revalidationRequest(req)
+		}
+	}
+
+	requestTime := t.clock.Now()
+	resp, err := t.base.RoundTrip(outgoing)
+	if err != nil {
+		return nil, err
+	}
+	responseTime := t.clock.Now()
+
+	if stored != nil && outgoing != req && resp.StatusCode == http.StatusNotModified {
+		io.Copy(io.Discard, resp.Body)
+		resp.Body.Close()
+
+		stored.update(resp, requestTime, responseTime)
+		t.save(ctx, key, stored)
+		return stored.response(req, stored.age(responseTime)), nil
+	}
+
+	if !storable(resp) {
+		if stored != nil {
+			t.delete(ctx, key)
+		}
+		return resp, nil
+	}
+
+	body, complete, err := readBody(resp.Body, t.maxBodySize)
+	if err != nil {
+		resp.Body.Close()
+		return nil, kit.WrapError(err, "error reading response body")
+	}
+	if !complete {
+		resp.Body = struct {
+			io.Reader
+			io.Closer
+		}{io.MultiReader(bytes.NewReader(body), resp.Body), resp.Body}
+		return resp, nil
+	}
+	resp.Body.Close()
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+
+	t.save(ctx, key, newEntry(req, resp, body, requestTime, responseTime))
+	return resp, nil
+}
+
+// cacheKey returns the key requests for the same resource share
+func cacheKey(req *http.Request) string {
+	return req.URL.String()
+}
+
+// hasConditions returns whether req makes its own conditional or partial request, which the cache
+// passes through rather than answers
+func hasConditions(req *http.Request) bool {
+	for _, name := range []string{"If-None-Match", "If-Modified-Since", "If-Match", "If-Unmodified-Since", "If-Range", "Range"} {
+		if req.Header.Get(name) != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// storable returns whether resp may be stored, RFC 9111 section 3
+func storable(resp *http.Response) bool {
+	cc := ParseCacheControl(strings.Join(resp.Header.Values("Cache-Control"), ","))
+	if cc.Has("no-store") {
+		return false
+	}
+	for _, name := range varyNames(resp.Header) {
+		if name == "*" {
+			return false
+		}
+	}
+	return heuristicallyCacheable[resp.StatusCode] || cc.Has("max-age") || cc.Has("public") || resp.Header.Get("Expires") != ""
+}
+
+// readBody reads body up to limit, returning whether it read all of it
+func readBody(body io.Reader, limit int64) ([]byte, bool, error) {
+	read, err := io.ReadAll(io.LimitReader(body, limit+1))
+	if err != nil {
+		return nil, false, err
+	}
+	return read, int64(len(read)) <= limit, nil
+}
+
+func (t *Transport) load(ctx context.Context, key string) *entry {
+	value, ok, err := t.store.Get(ctx, key)
+	if err != nil {
+		logger.WarnContext(ctx, "failed to get cached response", "key", key, "error", err)
+		return nil
+	}
+	if !ok {
+		return nil
+	}
+
+	var e entry
+	err = json.Unmarshal(value, &e)
+	if err != nil {
+		logger.WarnContext(ctx, "failed to decode cached response", "key", key, "error", err)
+		return nil
+	}
+	return &e
+}
+
+func (t *Transport) save(ctx context.Context, key string, e *entry) {
+	value, err := json.Marshal(e)
+	if err == nil {
+		err = t.store.Set(ctx, key, value)
+	}
+	if err != nil {
+		logger.WarnContext(ctx, "failed to cache response", "key", key, "error", err)
+	}
+}
+
+func (t *Transport) delete(ctx context.Context, key string) {
+	err := t.store.Delete(ctx, key)
+	if err != nil {
+		logger.WarnContext(ctx, "failed to invalidate cached response", "key", key, "error", err)
+	}
+}
@@ -0,0 +1,255 @@
+package httpcachekit
+
+import (
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/half-ogre/go-kit/kit"
+)
+
+// fakeOrigin is a RoundTripper answering with respond, recording the requests it gets
+type fakeOrigin struct {
+	respond  func(req *http.Request) *http.Response
+	requests []*http.Request
+}
+
+func (o *fakeOrigin) RoundTrip(req *http.Request) (*http.Response, error) {
+	o.requests = append(o.requests, req)
+	return o.respond(req), nil
+}
+
+func newResponse(status int, header http.Header, body string) *http.Response {
+	if header == nil {
+		header = http.Header{}
+	}
+	return &http.Response{StatusCode: status, Header: header, Body: io.NopCloser(strings.NewReader(body))}
+}
+
+// newTestClient returns a client caching in memory, with a clock that's now unless it's moved
+func newTestClient(origin *fakeOrigin, now *time.Time) *http.Client {
+	return NewClient(NewMemoryStore(0),
+		WithTransportBase(origin),
+		WithTransportClock(kit.NewClock(kit.WithFake(func() time.Time { return *now }))))
+}
+
+func get(t *testing.T, client *http.Client, url string, header ...string) (*http.Response, string) {
+	req, _ := http.NewRequest(http.MethodGet, url, nil)
+	for i := 0; i+1 < len(header); i += 2 {
+		req.Header.Set(header[i], header[i+1])
+	}
+	resp, err := client.Do(req)
+	require.NoError(t, err)
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	return resp, string(body)
+}
+
+func TestTransport(t *testing.T) {
+	theURL := "https://example.com/.well-known/jwks.json"
+
+	t.Run("answers_from_the_cache_while_fresh", func(t *testing.T) {
+		now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+		origin := &fakeOrigin{respond: func(*http.Request) *http.Response {
+			return newResponse(http.StatusOK, http.Header{"Cache-Control": {"max-age=300"}}, "theKeys")
+		}}
+		client := newTestClient(origin, &now)
+		get(t, client, theURL)
+		now = now.Add(time.Minute)
+
+		resp, body := get(t, client, theURL)
+
+		assert.Len(t, origin.requests, 1)
+		assert.Equal(t, "theKeys", body)
+		assert.True(t, FromCache(resp))
+		assert.Equal(t, "60", resp.Header.Get("Age"))
+	})
+
+	t.Run("goes_to_the_origin_once_stale", func(t *testing.T) {
+		now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+		origin := &fakeOrigin{respond: func(*http.Request) *http.Response {
+			return newResponse(http.StatusOK, http.Header{"Cache-Control": {"max-age=300"}}, "theKeys")
+		}}
+		client := newTestClient(origin, &now)
+		get(t, client, theURL)
+		now = now.Add(301 * time.Second)
+
+		resp, _ := get(t, client, theURL)
+
+		assert.Len(t, origin.requests, 2)
+		assert.False(t, FromCache(resp))
+	})
+
+	t.Run("revalidates_with_the_etag_and_serves_the_stored_body_on_not_modified", func(t *testing.T) {
+		now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+		origin := &fakeOrigin{respond: func(req *http.Request) *http.Response {
+			if req.Header.Get("If-None-Match") == `"v1"` {
+				return newResponse(http.StatusNotModified, http.Header{"Cache-Control": {"max-age=60"}}, "")
+			}
+			return newResponse(http.StatusOK, http.Header{"Etag": {`"v1"`}, "Cache-Control": {"no-cache"}}, "theKeys")
+		}}
+		client := newTestClient(origin, &now)
+		get(t, client, theURL)
+
+		resp, body := get(t, client, theURL)
+
+		assert.Len(t, origin.requests, 2)
+		assert.Equal(t, `"v1"`, origin.requests[1].Header.Get("If-None-Match"))
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, "theKeys", body)
+		assert.True(t, FromCache(resp))
+		assert.Equal(t, "max-age=60", resp.Header.Get("Cache-Control"))
+	})
+
+	t.Run("revalidates_with_last_modified", func(t *testing.T) {
+		now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+		lastModified := now.Add(-time.Hour).Format(http.TimeFormat)
+		origin := &fakeOrigin{respond: func(req *http.Request) *http.Response {
+			return newResponse(http.StatusOK, http.Header{"Last-Modified": {lastModified}, "Date": {now.Format(http.TimeFormat)}}, "theDocument")
+		}}
+		client := newTestClient(origin, &now)
+		get(t, client, theURL)
+		// Fresh for a tenth of the hour since it was modified
+		now = now.Add(7 * time.Minute)
+
+		get(t, client, theURL)
+
+		assert.Len(t, origin.requests, 2)
+		assert.Equal(t, lastModified, origin.requests[1].Header.Get("If-Modified-Since"))
+	})
+
+	t.Run("uses_expires_when_there_is_no_max_age", func(t *testing.T) {
+		now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+		origin := &fakeOrigin{respond: func(*http.Request) *http.Response {
+			return newResponse(http.StatusOK, http.Header{
+				"Date":    {now.Format(http.TimeFormat)},
+				"Expires": {now.Add(time.Hour).Format(http.TimeFormat)},
+			}, "theDocument")
+		}}
+		client := newTestClient(origin, &now)
+		get(t, client, theURL)
+		now = now.Add(59 * time.Minute)
+
+		get(t, client, theURL)
+
+		assert.Len(t, origin.requests, 1)
+	})
+
+	t.Run("does_not_store_no_store_responses", func(t *testing.T) {
+		now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+		origin := &fakeOrigin{respond: func(*http.Request) *http.Response {
+			return newResponse(http.StatusOK, http.Header{"Cache-Control": {"no-store, max-age=300"}}, "theSecret")
+		}}
+		client := newTestClient(origin, &now)
+		get(t, client, theURL)
+
+		get(t, client, theURL)
+
+		assert.Len(t, origin.requests, 2)
+	})
+
+	t.Run("goes_to_the_origin_when_the_request_has_no_cache", func(t *testing.T) {
+		now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+		origin := &fakeOrigin{respond: func(*http.Request) *http.Response {
+			return newResponse(http.StatusOK, http.Header{"Cache-Control": {"max-age=300"}}, "theKeys")
+		}}
+		client := newTestClient(origin, &now)
+		get(t, client, theURL)
+
+		get(t, client, theURL, "Cache-Control", "no-cache")
+
+		assert.Len(t, origin.requests, 2)
+	})
+
+	t.Run("honors_the_request_max_age", func(t *testing.T) {
+		now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+		origin := &fakeOrigin{respond: func(*http.Request) *http.Response {
+			return newResponse(http.StatusOK, http.Header{"Cache-Control": {"max-age=300"}}, "theKeys")
+		}}
+		client := newTestClient(origin, &now)
+		get(t, client, theURL)
+		now = now.Add(2 * time.Minute)
+
+		get(t, client, theURL, "Cache-Control", "max-age=60")
+
+		assert.Len(t, origin.requests, 2)
+	})
+
+	t.Run("serves_stale_responses_within_the_request_max_stale", func(t *testing.T) {
+		now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+		origin := &fakeOrigin{respond: func(*http.Request) *http.Response {
+			return newResponse(http.StatusOK, http.Header{"Cache-Control": {"max-age=60"}}, "theKeys")
+		}}
+		client := newTestClient(origin, &now)
+		get(t, client, theURL)
+		now = now.Add(90 * time.Second)
+
+		get(t, client, theURL, "Cache-Control", "max-stale=60")
+
+		assert.Len(t, origin.requests, 1)
+	})
+
+	t.Run("keeps_separate_responses_for_the_headers_they_vary_by", func(t *testing.T) {
+		now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+		origin := &fakeOrigin{respond: func(req *http.Request) *http.Response {
+			return newResponse(http.StatusOK, http.Header{"Cache-Control": {"max-age=300"}, "Vary": {"Accept-Language"}}, req.Header.Get("Accept-Language"))
+		}}
+		client := newTestClient(origin, &now)
+		get(t, client, theURL, "Accept-Language", "en")
+
+		_, body := get(t, client, theURL, "Accept-Language", "fr")
+
+		assert.Len(t, origin.requests, 2)
+		assert.Equal(t, "fr", body)
+	})
+
+	t.Run("invalidates_the_stored_response_after_an_unsafe_request", func(t *testing.T) {
+		now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+		origin := &fakeOrigin{respond: func(*http.Request) *http.Response {
+			return newResponse(http.StatusOK, http.Header{"Cache-Control": {"max-age=300"}}, "theItem")
+		}}
+		client := newTestClient(origin, &now)
+		get(t, client, theURL)
+		req, _ := http.NewRequest(http.MethodDelete, theURL, nil)
+		resp, err := client.Do(req)
+		require.NoError(t, err)
+		resp.Body.Close()
+
+		get(t, client, theURL)
+
+		assert.Len(t, origin.requests, 3)
+	})
+
+	t.Run("passes_through_requests_with_their_own_conditions", func(t *testing.T) {
+		now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+		origin := &fakeOrigin{respond: func(*http.Request) *http.Response {
+			return newResponse(http.StatusNotModified, nil, "")
+		}}
+		client := newTestClient(origin, &now)
+
+		resp, _ := get(t, client, theURL, "If-None-Match", `"theETag"`)
+
+		assert.Equal(t, http.StatusNotModified, resp.StatusCode)
+		assert.False(t, FromCache(resp))
+	})
+
+	t.Run("does_not_store_bodies_larger_than_the_maximum", func(t *testing.T) {
+		now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+		origin := &fakeOrigin{respond: func(*http.Request) *http.Response {
+			return newResponse(http.StatusOK, http.Header{"Cache-Control": {"max-age=300"}}, "theLargeBody")
+		}}
+		client := NewClient(NewMemoryStore(0), WithTransportBase(origin), WithMaxBodySize(4),
+			WithTransportClock(kit.NewClock(kit.WithFake(func() time.Time { return now }))))
+		_, body := get(t, client, theURL)
+
+		get(t, client, theURL)
+
+		assert.Equal(t, "theLargeBody", body)
+		assert.Len(t, origin.requests, 2)
+	})
+}
@@ -0,0 +1,135 @@
+package httpcachekit
+
+import (
+	"container/list"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/half-ogre/go-kit/filekit"
+	"github.com/half-ogre/go-kit/kit"
+)
+
+// Store is where a Transport keeps responses. Values are opaque bytes so the store can be in process
+// (see MemoryStore), on disk (see DiskStore), or shared, such as Redis.
+type Store interface {
+	Get(ctx context.Context, key string) ([]byte, bool, error)
+	Set(ctx context.Context, key string, value []byte) error
+	Delete(ctx context.Context, key string) error
+}
+
+// MemoryStore is an in-process Store that keeps up to a maximum number of responses, dropping the
+// least recently used
+type MemoryStore struct {
+	mu         sync.Mutex
+	maxEntries int
+	entries    map[string]*list.Element
+	recent     *list.List
+}
+
+type memoryStoreEntry struct {
+	key   string
+	value []byte
+}
+
+// NewMemoryStore returns an empty MemoryStore keeping up to maxEntries responses, or any number when
+// maxEntries is 0 or less
+func NewMemoryStore(maxEntries int) *MemoryStore {
+	return &MemoryStore{
+		maxEntries: maxEntries,
+		entries:    map[string]*list.Element{},
+		recent:     list.New(),
+	}
+}
+
+func (s *MemoryStore) Get(ctx context.Context, key string) ([]byte, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	element, ok := s.entries[key]
+	if !ok {
+		return nil, false, nil
+	}
+	s.recent.MoveToFront(element)
+	return element.Value.(*memoryStoreEntry).value, true, nil
+}
+
+func (s *MemoryStore) Set(ctx context.Context, key string, value []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if element, ok := s.entries[key]; ok {
+		element.Value.(*memoryStoreEntry).value = value
+		s.recent.MoveToFront(element)
+		return nil
+	}
+
+	s.entries[key] = s.recent.PushFront(&memoryStoreEntry{key: key, value: value})
+	if s.maxEntries > 0 && s.recent.Len() > s.maxEntries {
+		oldest := s.recent.Back()
+		s.recent.Remove(oldest)
+		delete(s.entries, oldest.Value.(*memoryStoreEntry).key)
+	}
+	return nil
+}
+
+func (s *MemoryStore) Delete(ctx context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if element, ok := s.entries[key]; ok {
+		s.recent.Remove(element)
+		delete(s.entries, key)
+	}
+	return nil
+}
+
+// DiskStore is a Store keeping each response in a file in a directory, so they outlive the process,
+// e.g. for a CLI that calls the same API on every run. Files are written atomically, so processes can
+// share the directory.
+type DiskStore struct {
+	dir string
+}
+
+// NewDiskStore returns a DiskStore keeping responses in dir, which is created when the first response
+// is stored
+func NewDiskStore(dir string) *DiskStore {
+	return &DiskStore{dir: dir}
+}
+
+func (s *DiskStore) Get(ctx context.Context, key string) ([]byte, bool, error) {
+	value, err := os.ReadFile(s.path(key))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, kit.WrapError(err, "error reading cached response")
+	}
+	return value, true, nil
+}
+
+func (s *DiskStore) Set(ctx context.Context, key string, value []byte) error {
+	err := os.MkdirAll(s.dir, 0o700)
+	if err != nil {
+		return kit.WrapError(err, "error creating cache directory %s", s.dir)
+	}
+	return filekit.WriteFileAtomic(s.path(key), value, 0o600)
+}
+
+func (s *DiskStore) Delete(ctx context.Context, key string) error {
+	err := os.Remove(s.path(key))
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return kit.WrapError(err, "error removing cached response")
+	}
+	return nil
+}
+
+// path returns the file for key, named by its hash so any URL makes a valid file name
+func (s *DiskStore) path(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return filepath.Join(s.dir, hex.EncodeToString(sum[:]))
+}
@@ -0,0 +1,80 @@
+package httpcachekit
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemoryStore(t *testing.T) {
+	t.Run("gets_what_was_set", func(t *testing.T) {
+		store := NewMemoryStore(0)
+		store.Set(context.Background(), "theKey", []byte("theValue"))
+
+		value, ok, err := store.Get(context.Background(), "theKey")
+
+		require.NoError(t, err)
+		assert.True(t, ok)
+		assert.Equal(t, []byte("theValue"), value)
+	})
+
+	t.Run("drops_the_least_recently_used_entry_when_full", func(t *testing.T) {
+		store := NewMemoryStore(2)
+		store.Set(context.Background(), "a", []byte("a"))
+		store.Set(context.Background(), "b", []byte("b"))
+		store.Get(context.Background(), "a")
+
+		store.Set(context.Background(), "c", []byte("c"))
+
+		_, aOK, _ := store.Get(context.Background(), "a")
+		_, bOK, _ := store.Get(context.Background(), "b")
+		_, cOK, _ := store.Get(context.Background(), "c")
+		assert.True(t, aOK)
+		assert.False(t, bOK)
+		assert.True(t, cOK)
+	})
+
+	t.Run("deletes_an_entry", func(t *testing.T) {
+		store := NewMemoryStore(0)
+		store.Set(context.Background(), "theKey", []byte("theValue"))
+
+		err := store.Delete(context.Background(), "theKey")
+
+		require.NoError(t, err)
+		_, ok, _ := store.Get(context.Background(), "theKey")
+		assert.False(t, ok)
+	})
+}
+
+func TestDiskStore(t *testing.T) {
+	t.Run("gets_what_was_set_in_a_directory_it_creates", func(t *testing.T) {
+		store := NewDiskStore(t.TempDir() + "/cache")
+		err := store.Set(context.Background(), "https://example.com/jwks.json", []byte("theValue"))
+		require.NoError(t, err)
+
+		value, ok, err := NewDiskStore(store.dir).Get(context.Background(), "https://example.com/jwks.json")
+
+		require.NoError(t, err)
+		assert.True(t, ok)
+		assert.Equal(t, []byte("theValue"), value)
+	})
+
+	t.Run("returns_false_for_a_missing_key", func(t *testing.T) {
+		_, ok, err := NewDiskStore(t.TempDir()).Get(context.Background(), "theKey")
+
+		assert.NoError(t, err)
+		assert.False(t, ok)
+	})
+
+	t.Run("deletes_an_entry_and_ignores_a_missing_one", func(t *testing.T) {
+		store := NewDiskStore(t.TempDir())
+		store.Set(context.Background(), "theKey", []byte("theValue"))
+
+		assert.NoError(t, store.Delete(context.Background(), "theKey"))
+		assert.NoError(t, store.Delete(context.Background(), "theKey"))
+		_, ok, _ := store.Get(context.Background(), "theKey")
+		assert.False(t, ok)
+	})
+}
@@ -0,0 +1,215 @@
+// Package i18nkit translates messages from catalogs shared by every framework the kit supports. A
+// Catalog is loaded from JSON or YAML files, e.g. in an embed.FS; a Localizer translates its messages
+// for the locale best matching what a request prefers, picking plural forms by each language's rules;
+// and FuncMap binds a Localizer into template functions, which the echokit Renderer and ginkit
+// LocalizedHTML use to render templates in the request's locale.
+package i18nkit
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"path"
+	"slices"
+	"strings"
+	"sync"
+
+	"golang.org/x/text/language"
+	"gopkg.in/yaml.v3"
+
+	"github.com/half-ogre/go-kit/kit"
+	"github.com/half-ogre/go-kit/logkit"
+)
+
+var logger = logkit.Logger("i18nkit")
+
+// Message is a message's text for each plural category. A message that doesn't vary by count has
+// only PluralOther.
+type Message map[PluralCategory]string
+
+// Text returns a message that doesn't vary by count
+func Text(text string) Message {
+	return Message{PluralOther: text}
+}
+
+// form returns the text for category, or for PluralOther if the message has no text for it
+func (m Message) form(category PluralCategory) string {
+	if text, ok := m[category]; ok {
+		return text
+	}
+	return m[PluralOther]
+}
+
+// Catalog is messages by locale and key, with a default locale for messages missing from the others
+type Catalog struct {
+	mu            sync.RWMutex
+	defaultLocale string
+	messages      map[string]map[string]Message
+	// locales are the locales with messages, the default first, for matching
+	locales []string
+	matcher language.Matcher
+}
+
+// NewCatalog returns an empty catalog whose messages fall back to defaultLocale, e.g. "en"
+func NewCatalog(defaultLocale string) *Catalog {
+	c := &Catalog{messages: map[string]map[string]Message{}}
+	c.defaultLocale = c.addLocale(defaultLocale)
+	return c
+}
+
+// LoadFS returns a catalog of the message files in the root of fsys, e.g. an embed.FS, named for their
+// locale: en.json, fr-CA.yaml, and so on. A file maps keys to messages, each either text or an object
+// of text by plural category:
+//
+//	{
+//		"greeting": "Hello, {name}!",
+//		"files": {"one": "{count} file", "other": "{count} files"}
+//	}
+func LoadFS(fsys fs.FS, defaultLocale string) (*Catalog, error) {
+	entries, err := fs.ReadDir(fsys, ".")
+	if err != nil {
+		return nil, kit.WrapError(err, "error reading message files")
+	}
+
+	c := NewCatalog(defaultLocale)
+	loaded := false
+	for _, entry := range entries {
+		ext := path.Ext(entry.Name())
+		if entry.IsDir() || (ext != ".json" && ext != ".yaml" && ext != ".yml") {
+			continue
+		}
+
+		locale := strings.TrimSuffix(entry.Name(), ext)
+		if _, err := language.Parse(strings.ReplaceAll(locale, "_", "-")); err != nil {
+			return nil, fmt.Errorf("message file %s isn't named for a locale: %w", entry.Name(), err)
+		}
+
+		data, err := fs.ReadFile(fsys, entry.Name())
+		if err != nil {
+			return nil, kit.WrapError(err, "error reading message file %s", entry.Name())
+		}
+		var raw map[string]any
+		if ext == ".json" {
+			err = json.Unmarshal(data, &raw)
+		} else {
+			err = yaml.Unmarshal(data, &raw)
+		}
+		if err != nil {
+			return nil, kit.WrapError(err, "error parsing message file %s", entry.Name())
+		}
+
+		messages, err := parseMessages(raw)
+		if err != nil {
+			return nil, kit.WrapError(err, "error parsing message file %s", entry.Name())
+		}
+		c.AddMessages(locale, messages)
+		loaded = loaded || canonicalLocale(locale) == c.defaultLocale
+	}
+
+	if !loaded {
+		return nil, fmt.Errorf("no message file for the default locale %s", defaultLocale)
+	}
+	return c, nil
+}
+
+// parseMessages converts the decoded contents of a message file to messages
+func parseMessages(raw map[string]any) (map[string]Message, error) {
+	messages := make(map[string]Message, len(raw))
+	for key, value := range raw {
+		switch value := value.(type) {
+		case string:
+			messages[key] = Text(value)
+		case map[string]any:
+			message := Message{}
+			for category, text := range value {
+				if !PluralCategory(category).valid() {
+					return nil, fmt.Errorf("message %s has unknown plural category %q", key, category)
+				}
+				s, ok := text.(string)
+				if !ok {
+					return nil, fmt.Errorf("message %s has a %s form that isn't text", key, category)
+				}
+				message[PluralCategory(category)] = s
+			}
+			if _, ok := message[PluralOther]; !ok {
+				return nil, fmt.Errorf("message %s has no %q form", key, PluralOther)
+			}
+			messages[key] = message
+		default:
+			return nil, fmt.Errorf("message %s isn't text or plural forms", key)
+		}
+	}
+	return messages, nil
+}
+
+// Add adds message to the catalog as key in locale, replacing any message it has for them
+func (c *Catalog) Add(locale string, key string, message Message) {
+	c.AddMessages(locale, map[string]Message{key: message})
+}
+
+// AddMessages adds messages by key to the catalog in locale, replacing any it has for the same keys
+func (c *Catalog) AddMessages(locale string, messages map[string]Message) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	locale = c.addLocale(locale)
+	for key, message := range messages {
+		c.messages[locale][key] = message
+	}
+}
+
+// addLocale adds locale to the catalog if it's new and returns its canonical form; c.mu must be held
+// by the caller unless c is new
+func (c *Catalog) addLocale(locale string) string {
+	locale = canonicalLocale(locale)
+	if _, ok := c.messages[locale]; ok {
+		return locale
+	}
+
+	c.messages[locale] = map[string]Message{}
+	c.locales = append(c.locales, locale)
+	tags := make([]language.Tag, len(c.locales))
+	for i, l := range c.locales {
+		tags[i] = language.Make(l)
+	}
+	c.matcher = language.NewMatcher(tags)
+	return locale
+}
+
+// DefaultLocale returns the locale messages fall back to
+func (c *Catalog) DefaultLocale() string {
+	return c.defaultLocale
+}
+
+// Locales returns the locales with messages, the default first
+func (c *Catalog) Locales() []string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return slices.Clone(c.locales)
+}
+
+// lookup returns the message for key in locale and whether there is one
+func (c *Catalog) lookup(locale string, key string) (Message, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	message, ok := c.messages[locale][key]
+	return message, ok
+}
+
+// hasLocale reports whether the catalog has messages in locale
+func (c *Catalog) hasLocale(locale string) bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	_, ok := c.messages[locale]
+	return ok
+}
+
+// canonicalLocale returns locale in its canonical form, e.g. "fr-CA" for "fr_ca", or locale itself if
+// it isn't valid
+func canonicalLocale(locale string) string {
+	tag, err := language.Parse(strings.ReplaceAll(locale, "_", "-"))
+	if err != nil {
+		return locale
+	}
+	return tag.String()
+}
@@ -0,0 +1,101 @@
+package i18nkit
+
+import (
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoadFS(t *testing.T) {
+	t.Run("loads_json_and_yaml_message_files_named_for_their_locale", func(t *testing.T) {
+		fsys := fstest.MapFS{
+			"en.json":    {Data: []byte(`{"greeting": "Hello, {name}!", "files": {"one": "{count} file", "other": "{count} files"}}`)},
+			"fr_ca.yaml": {Data: []byte("greeting: \"Bonjour, {name}!\"\n")},
+			"README.md":  {Data: []byte("not messages")},
+		}
+
+		catalog, err := LoadFS(fsys, "en")
+
+		assert.NoError(t, err)
+		assert.Equal(t, "en", catalog.DefaultLocale())
+		assert.Equal(t, []string{"en", "fr-CA"}, catalog.Locales())
+		message, ok := catalog.lookup("en", "files")
+		assert.True(t, ok)
+		assert.Equal(t, Message{PluralOne: "{count} file", PluralOther: "{count} files"}, message)
+		message, ok = catalog.lookup("fr-CA", "greeting")
+		assert.True(t, ok)
+		assert.Equal(t, Text("Bonjour, {name}!"), message)
+	})
+
+	t.Run("returns_an_error_without_a_file_for_the_default_locale", func(t *testing.T) {
+		fsys := fstest.MapFS{"fr.json": {Data: []byte(`{"greeting": "Bonjour"}`)}}
+
+		_, err := LoadFS(fsys, "en")
+
+		assert.EqualError(t, err, "no message file for the default locale en")
+	})
+
+	t.Run("returns_an_error_for_a_file_not_named_for_a_locale", func(t *testing.T) {
+		fsys := fstest.MapFS{"en.json": {Data: []byte(`{}`)}, "messages.json": {Data: []byte(`{}`)}}
+
+		_, err := LoadFS(fsys, "en")
+
+		assert.ErrorContains(t, err, "message file messages.json isn't named for a locale")
+	})
+
+	t.Run("returns_an_error_for_an_unknown_plural_category", func(t *testing.T) {
+		fsys := fstest.MapFS{"en.json": {Data: []byte(`{"files": {"single": "a file", "other": "files"}}`)}}
+
+		_, err := LoadFS(fsys, "en")
+
+		assert.ErrorContains(t, err, `message files has unknown plural category "single"`)
+	})
+
+	t.Run("returns_an_error_for_plural_forms_without_other", func(t *testing.T) {
+		fsys := fstest.MapFS{"en.json": {Data: []byte(`{"files": {"one": "a file"}}`)}}
+
+		_, err := LoadFS(fsys, "en")
+
+		assert.ErrorContains(t, err, `message files has no "other" form`)
+	})
+
+	t.Run("returns_an_error_for_a_message_that_isnt_text", func(t *testing.T) {
+		fsys := fstest.MapFS{"en.json": {Data: []byte(`{"count": 3}`)}}
+
+		_, err := LoadFS(fsys, "en")
+
+		assert.ErrorContains(t, err, "message count isn't text or plural forms")
+	})
+
+	t.Run("returns_an_error_for_a_file_that_doesnt_parse", func(t *testing.T) {
+		fsys := fstest.MapFS{"en.json": {Data: []byte(`{`)}}
+
+		_, err := LoadFS(fsys, "en")
+
+		assert.ErrorContains(t, err, "error parsing message file en.json")
+	})
+}
+
+func TestCatalog_Add(t *testing.T) {
+	t.Run("adds_messages_under_the_canonical_locale", func(t *testing.T) {
+		catalog := NewCatalog("en-us")
+
+		catalog.Add("pt_br", "greeting", Text("Olá"))
+
+		assert.Equal(t, []string{"en-US", "pt-BR"}, catalog.Locales())
+		message, ok := catalog.lookup("pt-BR", "greeting")
+		assert.True(t, ok)
+		assert.Equal(t, Text("Olá"), message)
+	})
+
+	t.Run("replaces_a_message_for_the_same_key", func(t *testing.T) {
+		catalog := NewCatalog("en")
+		catalog.Add("en", "greeting", Text("Hello"))
+
+		catalog.AddMessages("en", map[string]Message{"greeting": Text("Hi")})
+
+		message, _ := catalog.lookup("en", "greeting")
+		assert.Equal(t, Text("Hi"), message)
+	})
+}
@@ -0,0 +1,143 @@
+package i18nkit
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"golang.org/x/text/language"
+)
+
+// Localizer translates a catalog's messages into a locale, falling back to the locale's base language,
+// e.g. "fr" for "fr-CA", and then to the catalog's default locale
+type Localizer struct {
+	catalog *Catalog
+	locale  string
+	// chain is the locales messages are looked up in, in order
+	chain []string
+}
+
+// Localizer returns a localizer for the catalog's locale best matching preferences, each a locale or
+// an Accept-Language header value, in order of preference. Without a match it's for the default
+// locale.
+func (c *Catalog) Localizer(preferences ...string) *Localizer {
+	locale := c.Match(preferences...)
+
+	chain := []string{locale}
+	if base := baseLanguage(locale); base != locale && c.hasLocale(base) {
+		chain = append(chain, base)
+	}
+	if locale != c.defaultLocale {
+		chain = append(chain, c.defaultLocale)
+	}
+	return &Localizer{catalog: c, locale: locale, chain: chain}
+}
+
+// Match returns the catalog's locale best matching preferences, each a locale or an Accept-Language
+// header value, in order of preference, or the default locale if none of them match
+func (c *Catalog) Match(preferences ...string) string {
+	var tags []language.Tag
+	for _, preference := range preferences {
+		parsed, _, err := language.ParseAcceptLanguage(strings.ReplaceAll(preference, "_", "-"))
+		if err != nil {
+			logger.Debug("ignoring locale preference that isn't valid", "preference", preference, "error", err)
+			continue
+		}
+		tags = append(tags, parsed...)
+	}
+	if len(tags) == 0 {
+		return c.defaultLocale
+	}
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	_, index, confidence := c.matcher.Match(tags...)
+	if confidence == language.No {
+		return c.defaultLocale
+	}
+	return c.locales[index]
+}
+
+// Locale returns the locale the localizer translates into
+func (l *Localizer) Locale() string {
+	return l.locale
+}
+
+// T returns the message for key with its placeholders replaced by args, which are alternating names
+// and values: T("greeting", "name", user.Name) replaces "{name}" in "Hello, {name}!". A key without a
+// message in any of the localizer's locales is returned as is, so missing translations are visible.
+func (l *Localizer) T(key string, args ...any) string {
+	message, locale, ok := l.lookup(key)
+	if !ok {
+		return key
+	}
+	return format(message.form(Plural(locale, 1)), args)
+}
+
+// N returns the form of the message for key that count takes in the localizer's locale, with "{count}"
+// replaced by count and its other placeholders replaced by args as T does
+func (l *Localizer) N(key string, count int, args ...any) string {
+	message, locale, ok := l.lookup(key)
+	if !ok {
+		return key
+	}
+	return format(message.form(Plural(locale, count)), append([]any{"count", count}, args...))
+}
+
+// Has reports whether there's a message for key in any of the localizer's locales
+func (l *Localizer) Has(key string) bool {
+	_, _, ok := l.lookup(key)
+	return ok
+}
+
+// lookup returns the message for key and the locale it's in, the first in the localizer's chain that
+// has it, so its plural forms are picked by that locale's rule
+func (l *Localizer) lookup(key string) (Message, string, bool) {
+	for _, locale := range l.chain {
+		if message, ok := l.catalog.lookup(locale, key); ok {
+			return message, locale, true
+		}
+	}
+
+	logger.Debug("no message for key", "key", key, "locale", l.locale)
+	return nil, "", false
+}
+
+// format replaces the "{name}" placeholders in text with the values in args, alternating names and
+// values
+func format(text string, args []any) string {
+	if len(args) == 0 || !strings.Contains(text, "{") {
+		return text
+	}
+
+	replacements := make([]string, 0, len(args))
+	for i := 0; i+1 < len(args); i += 2 {
+		replacements = append(replacements, "{"+fmt.Sprint(args[i])+"}", formatValue(args[i+1]))
+	}
+	return strings.NewReplacer(replacements...).Replace(text)
+}
+
+func formatValue(value any) string {
+	switch value := value.(type) {
+	case string:
+		return value
+	case int:
+		return strconv.Itoa(value)
+	default:
+		return fmt.Sprint(value)
+	}
+}
+
+type localizerKey struct{}
+
+// WithLocalizer returns a copy of ctx carrying localizer
+func WithLocalizer(ctx context.Context, localizer *Localizer) context.Context {
+	return context.WithValue(ctx, localizerKey{}, localizer)
+}
+
+// FromContext returns the localizer carried by ctx, and false if there isn't one
+func FromContext(ctx context.Context) (*Localizer, bool) {
+	localizer, ok := ctx.Value(localizerKey{}).(*Localizer)
+	return localizer, ok && localizer != nil
+}
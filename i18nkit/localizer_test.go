@@ -0,0 +1,132 @@
+package i18nkit
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestCatalog() *Catalog {
+	catalog := NewCatalog("en")
+	catalog.AddMessages("en", map[string]Message{
+		"greeting": Text("Hello, {name}!"),
+		"farewell": Text("Goodbye"),
+		"files":    {PluralOne: "{count} file in {folder}", PluralOther: "{count} files in {folder}"},
+		"items":    {PluralOne: "one item", PluralOther: "{count} items"},
+	})
+	catalog.AddMessages("fr", map[string]Message{
+		"greeting": Text("Bonjour, {name} !"),
+		"files":    {PluralOne: "{count} fichier dans {folder}", PluralOther: "{count} fichiers dans {folder}"},
+	})
+	catalog.AddMessages("fr-CA", map[string]Message{
+		"farewell": Text("Bonjour"),
+	})
+	return catalog
+}
+
+func TestCatalog_Match(t *testing.T) {
+	catalog := newTestCatalog()
+
+	tests := []struct {
+		name        string
+		preferences []string
+		expected    string
+	}{
+		{"exact_locale", []string{"fr-CA"}, "fr-CA"},
+		{"base_language_for_an_unsupported_region", []string{"fr-BE"}, "fr"},
+		{"first_supported_preference_of_an_accept_language_header", []string{"de-DE,fr;q=0.8,en;q=0.5"}, "fr"},
+		{"first_supported_preference_of_several", []string{"de", "fr"}, "fr"},
+		{"default_without_preferences", nil, "en"},
+		{"default_without_a_supported_preference", []string{"de-DE"}, "en"},
+		{"default_for_an_invalid_preference", []string{"not a locale!"}, "en"},
+		{"underscored_locale", []string{"fr_CA"}, "fr-CA"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.expected, catalog.Match(test.preferences...))
+		})
+	}
+}
+
+func TestLocalizer_T(t *testing.T) {
+	catalog := newTestCatalog()
+
+	t.Run("returns_the_message_with_placeholders_replaced", func(t *testing.T) {
+		localizer := catalog.Localizer("fr")
+
+		assert.Equal(t, "Bonjour, Ada !", localizer.T("greeting", "name", "Ada"))
+	})
+
+	t.Run("falls_back_to_the_base_language", func(t *testing.T) {
+		localizer := catalog.Localizer("fr-CA")
+
+		assert.Equal(t, "fr-CA", localizer.Locale())
+		assert.Equal(t, "Bonjour, Ada !", localizer.T("greeting", "name", "Ada"))
+		assert.Equal(t, "Bonjour", localizer.T("farewell"))
+	})
+
+	t.Run("falls_back_to_the_default_locale", func(t *testing.T) {
+		localizer := catalog.Localizer("fr")
+
+		assert.Equal(t, "Goodbye", localizer.T("farewell"))
+	})
+
+	t.Run("returns_the_key_without_a_message", func(t *testing.T) {
+		localizer := catalog.Localizer("fr")
+
+		assert.Equal(t, "missing.key", localizer.T("missing.key"))
+		assert.False(t, localizer.Has("missing.key"))
+		assert.True(t, localizer.Has("farewell"))
+	})
+
+	t.Run("leaves_placeholders_without_args", func(t *testing.T) {
+		localizer := catalog.Localizer("en")
+
+		assert.Equal(t, "Hello, {name}!", localizer.T("greeting"))
+	})
+}
+
+func TestLocalizer_N(t *testing.T) {
+	catalog := newTestCatalog()
+
+	t.Run("returns_the_form_for_the_count", func(t *testing.T) {
+		localizer := catalog.Localizer("en")
+
+		assert.Equal(t, "1 file in docs", localizer.N("files", 1, "folder", "docs"))
+		assert.Equal(t, "2 files in docs", localizer.N("files", 2, "folder", "docs"))
+		assert.Equal(t, "one item", localizer.N("items", 1))
+		assert.Equal(t, "0 items", localizer.N("items", 0))
+	})
+
+	t.Run("uses_the_plural_rule_of_the_locale", func(t *testing.T) {
+		localizer := catalog.Localizer("fr")
+
+		assert.Equal(t, "0 fichier dans docs", localizer.N("files", 0, "folder", "docs"))
+	})
+
+	t.Run("uses_the_plural_rule_of_the_locale_the_message_falls_back_to", func(t *testing.T) {
+		localizer := catalog.Localizer("fr")
+
+		assert.Equal(t, "0 items", localizer.N("items", 0))
+	})
+}
+
+func TestFromContext(t *testing.T) {
+	t.Run("returns_the_localizer_carried_by_the_context", func(t *testing.T) {
+		theLocalizer := newTestCatalog().Localizer("fr")
+		ctx := WithLocalizer(context.Background(), theLocalizer)
+
+		localizer, ok := FromContext(ctx)
+
+		assert.True(t, ok)
+		assert.Same(t, theLocalizer, localizer)
+	})
+
+	t.Run("returns_false_without_a_localizer", func(t *testing.T) {
+		_, ok := FromContext(context.Background())
+
+		assert.False(t, ok)
+	})
+}
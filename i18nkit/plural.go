@@ -0,0 +1,161 @@
+package i18nkit
+
+import (
+	"sync"
+
+	"golang.org/x/text/language"
+)
+
+// PluralCategory is a CLDR plural category, naming which form of a message a count takes
+type PluralCategory string
+
+const (
+	PluralZero  PluralCategory = "zero"
+	PluralOne   PluralCategory = "one"
+	PluralTwo   PluralCategory = "two"
+	PluralFew   PluralCategory = "few"
+	PluralMany  PluralCategory = "many"
+	PluralOther PluralCategory = "other"
+)
+
+func (c PluralCategory) valid() bool {
+	switch c {
+	case PluralZero, PluralOne, PluralTwo, PluralFew, PluralMany, PluralOther:
+		return true
+	}
+	return false
+}
+
+// PluralRule returns the plural category of a count in a language
+type PluralRule func(n int) PluralCategory
+
+var (
+	pluralRulesMu sync.RWMutex
+	// pluralRules are the CLDR cardinal rules for whole numbers, by base language; languages without one
+	// use oneOtherRule
+	pluralRules = map[string]PluralRule{
+		"ar": arabicRule,
+		"be": eastSlavicRule,
+		"cs": czechRule,
+		"fr": zeroOneOtherRule,
+		"id": otherRule,
+		"ja": otherRule,
+		"km": otherRule,
+		"ko": otherRule,
+		"lo": otherRule,
+		"ms": otherRule,
+		"my": otherRule,
+		"pl": polishRule,
+		"pt": zeroOneOtherRule,
+		"ru": eastSlavicRule,
+		"sk": czechRule,
+		"th": otherRule,
+		"uk": eastSlavicRule,
+		"vi": otherRule,
+		"zh": otherRule,
+	}
+)
+
+// RegisterPluralRule sets the plural rule of locale's base language, e.g. for a language whose rule
+// isn't built in, replacing any rule it has
+func RegisterPluralRule(locale string, rule PluralRule) {
+	pluralRulesMu.Lock()
+	defer pluralRulesMu.Unlock()
+	pluralRules[baseLanguage(locale)] = rule
+}
+
+// Plural returns the plural category of n in locale
+func Plural(locale string, n int) PluralCategory {
+	pluralRulesMu.RLock()
+	rule, ok := pluralRules[baseLanguage(locale)]
+	pluralRulesMu.RUnlock()
+	if !ok {
+		rule = oneOtherRule
+	}
+
+	if n < 0 {
+		n = -n
+	}
+	return rule(n)
+}
+
+// baseLanguage returns the language of locale without its script or region, e.g. "pt" for "pt-BR"
+func baseLanguage(locale string) string {
+	base, _ := language.Make(canonicalLocale(locale)).Base()
+	return base.String()
+}
+
+// oneOtherRule is the rule of English, German, Spanish, and most European languages
+func oneOtherRule(n int) PluralCategory {
+	if n == 1 {
+		return PluralOne
+	}
+	return PluralOther
+}
+
+// zeroOneOtherRule is the rule of French and Portuguese, where zero is singular too
+func zeroOneOtherRule(n int) PluralCategory {
+	if n == 0 || n == 1 {
+		return PluralOne
+	}
+	return PluralOther
+}
+
+// otherRule is the rule of languages without plural forms, e.g. Japanese and Chinese
+func otherRule(n int) PluralCategory {
+	return PluralOther
+}
+
+// eastSlavicRule is the rule of Russian, Ukrainian, and Belarusian
+func eastSlavicRule(n int) PluralCategory {
+	switch {
+	case n%10 == 1 && n%100 != 11:
+		return PluralOne
+	case n%10 >= 2 && n%10 <= 4 && (n%100 < 12 || n%100 > 14):
+		return PluralFew
+	default:
+		return PluralMany
+	}
+}
+
+// polishRule is the rule of Polish
+func polishRule(n int) PluralCategory {
+	switch {
+	case n == 1:
+		return PluralOne
+	case n%10 >= 2 && n%10 <= 4 && (n%100 < 12 || n%100 > 14):
+		return PluralFew
+	default:
+		return PluralMany
+	}
+}
+
+// czechRule is the rule of Czech and Slovak
+func czechRule(n int) PluralCategory {
+	switch {
+	case n == 1:
+		return PluralOne
+	case n >= 2 && n <= 4:
+		return PluralFew
+	default:
+		return PluralOther
+	}
+}
+
+// arabicRule is the rule of Arabic
+func arabicRule(n int) PluralCategory {
+	switch {
+	case n == 0:
+		return PluralZero
+	case n == 1:
+		return PluralOne
+	case n == 2:
+		return PluralTwo
+	case n%100 >= 3 && n%100 <= 10:
+		return PluralFew
+	case n%100 >= 11:
+		return PluralMany
+	default:
+		return PluralOther
+	}
+}
@@ -0,0 +1,73 @@
+package i18nkit
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPlural(t *testing.T) {
+	tests := []struct {
+		locale   string
+		n        int
+		expected PluralCategory
+	}{
+		{"en", 1, PluralOne},
+		{"en", 0, PluralOther},
+		{"en-GB", 2, PluralOther},
+		{"en", -1, PluralOne},
+		{"fr", 0, PluralOne},
+		{"fr-CA", 1, PluralOne},
+		{"fr", 2, PluralOther},
+		{"pt-BR", 0, PluralOne},
+		{"ja", 1, PluralOther},
+		{"zh-Hant", 5, PluralOther},
+		{"ru", 1, PluralOne},
+		{"ru", 21, PluralOne},
+		{"ru", 11, PluralMany},
+		{"ru", 3, PluralFew},
+		{"ru", 13, PluralMany},
+		{"ru", 24, PluralFew},
+		{"ru", 5, PluralMany},
+		{"uk", 101, PluralOne},
+		{"pl", 1, PluralOne},
+		{"pl", 21, PluralMany},
+		{"pl", 22, PluralFew},
+		{"pl", 12, PluralMany},
+		{"cs", 3, PluralFew},
+		{"cs", 5, PluralOther},
+		{"ar", 0, PluralZero},
+		{"ar", 1, PluralOne},
+		{"ar", 2, PluralTwo},
+		{"ar", 103, PluralFew},
+		{"ar", 111, PluralMany},
+		{"ar", 100, PluralOther},
+	}
+
+	for _, test := range tests {
+		t.Run(fmt.Sprintf("%s_%d_is_%s", test.locale, test.n, test.expected), func(t *testing.T) {
+			assert.Equal(t, test.expected, Plural(test.locale, test.n))
+		})
+	}
+}
+
+func TestRegisterPluralRule(t *testing.T) {
+	t.Run("sets_the_rule_of_the_locales_base_language", func(t *testing.T) {
+		t.Cleanup(func() {
+			pluralRulesMu.Lock()
+			delete(pluralRules, "cy")
+			pluralRulesMu.Unlock()
+		})
+
+		RegisterPluralRule("cy-GB", func(n int) PluralCategory {
+			if n == 2 {
+				return PluralTwo
+			}
+			return PluralOther
+		})
+
+		assert.Equal(t, PluralTwo, Plural("cy", 2))
+		assert.Equal(t, PluralOther, Plural("cy", 1))
+	})
+}
@@ -0,0 +1,49 @@
+package i18nkit
+
+import (
+	"net/http"
+)
+
+// LocaleParameter is the query parameter, and LocaleCookie the cookie, a request can choose its locale
+// with, overriding its Accept-Language header
+const (
+	LocaleParameter = "lang"
+	LocaleCookie    = "lang"
+)
+
+// Preferences returns the locales a request prefers, in order: its lang query parameter, its lang
+// cookie, and its Accept-Language header
+func Preferences(r *http.Request) []string {
+	var preferences []string
+	if locale := r.URL.Query().Get(LocaleParameter); locale != "" {
+		preferences = append(preferences, locale)
+	}
+	if cookie, err := r.Cookie(LocaleCookie); err == nil && cookie.Value != "" {
+		preferences = append(preferences, cookie.Value)
+	}
+	return append(preferences, r.Header.Values("Accept-Language")...)
+}
+
+// RequestLocalizer returns a localizer for the catalog's locale best matching the Preferences of r
+func (c *Catalog) RequestLocalizer(r *http.Request) *Localizer {
+	return c.Localizer(Preferences(r)...)
+}
+
+// Middleware returns a middleware that puts a localizer for the request's preferred locale in its
+// context, for FromContext, and sets the Content-Language header to the locale
+func Middleware(catalog *Catalog) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			localizer := catalog.RequestLocalizer(r)
+			SetResponseHeaders(w.Header(), localizer)
+			next.ServeHTTP(w, r.WithContext(WithLocalizer(r.Context(), localizer)))
+		})
+	}
+}
+
+// SetResponseHeaders sets the Content-Language header to the localizer's locale and adds
+// Accept-Language to the Vary header, since the response depends on it
+func SetResponseHeaders(header http.Header, localizer *Localizer) {
+	header.Set("Content-Language", localizer.Locale())
+	header.Add("Vary", "Accept-Language")
+}
@@ -0,0 +1,45 @@
+package i18nkit
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPreferences(t *testing.T) {
+	t.Run("returns_the_query_parameter_cookie_and_accept_language_in_order", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/?lang=de", nil)
+		req.AddCookie(&http.Cookie{Name: "lang", Value: "fr"})
+		req.Header.Set("Accept-Language", "en-US,en;q=0.9")
+
+		assert.Equal(t, []string{"de", "fr", "en-US,en;q=0.9"}, Preferences(req))
+	})
+
+	t.Run("returns_nothing_for_a_request_without_preferences", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+
+		assert.Empty(t, Preferences(req))
+	})
+}
+
+func TestMiddleware(t *testing.T) {
+	t.Run("puts_a_localizer_for_the_preferred_locale_in_the_request_context", func(t *testing.T) {
+		var locale string
+		handler := Middleware(newTestCatalog())(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			localizer, ok := FromContext(r.Context())
+			assert.True(t, ok)
+			locale = localizer.Locale()
+		}))
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set("Accept-Language", "fr-CH, fr;q=0.9")
+		w := httptest.NewRecorder()
+
+		handler.ServeHTTP(w, req)
+
+		assert.Equal(t, "fr", locale)
+		assert.Equal(t, "fr", w.Header().Get("Content-Language"))
+		assert.Equal(t, []string{"Accept-Language"}, w.Header().Values("Vary"))
+	})
+}
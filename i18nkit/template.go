@@ -0,0 +1,22 @@
+package i18nkit
+
+import (
+	"html/template"
+)
+
+// FuncMap returns template functions translating with localizer:
+//
+//	{{ t "greeting" "name" .User.Name }}  the message for a key, as Localizer.T
+//	{{ tn "files" .Count }}               the message's form for a count, as Localizer.N
+//	{{ locale }}                          the localizer's locale, e.g. for <html lang="...">
+//
+// Templates are parsed with the functions bound to any localizer, e.g. the catalog's default, and
+// cloned and rebound to a request's localizer to render it, as the echokit Renderer and ginkit
+// LocalizedHTML do.
+func FuncMap(localizer *Localizer) template.FuncMap {
+	return template.FuncMap{
+		"t":      localizer.T,
+		"tn":     localizer.N,
+		"locale": localizer.Locale,
+	}
+}
@@ -0,0 +1,22 @@
+package i18nkit
+
+import (
+	"bytes"
+	"html/template"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFuncMap(t *testing.T) {
+	t.Run("translates_with_the_localizer", func(t *testing.T) {
+		tmpl := template.Must(template.New("page").Funcs(FuncMap(newTestCatalog().Localizer("fr"))).Parse(
+			`<html lang="{{ locale }}">{{ t "greeting" "name" .Name }} {{ tn "files" .Count "folder" "docs" }}</html>`))
+
+		var buf bytes.Buffer
+		err := tmpl.Execute(&buf, map[string]any{"Name": "<Ada>", "Count": 2})
+
+		assert.NoError(t, err)
+		assert.Equal(t, `<html lang="fr">Bonjour, &lt;Ada&gt; ! 2 fichiers dans docs</html>`, buf.String())
+	})
+}
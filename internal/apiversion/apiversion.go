@@ -0,0 +1,109 @@
+// Package apiversion implements the API version negotiation and deprecation headers used by the echokit
+// and ginkit versioned route groups, so services version their APIs the same way on either framework.
+package apiversion
+
+import (
+	"fmt"
+	"mime"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Deprecation is the deprecation status of a version, sent as headers with each of its responses
+type Deprecation struct {
+	// Deprecated is when the version was deprecated; zero if it isn't
+	Deprecated time.Time
+	// Sunset is when the version will stop working; zero if it isn't scheduled to
+	Sunset time.Time
+	// Link points to docs on moving off the version
+	Link string
+}
+
+// SetHeaders sets the Deprecation (RFC 9745), Sunset (RFC 8594), and deprecation Link headers
+func (d Deprecation) SetHeaders(header http.Header) {
+	if !d.Deprecated.IsZero() {
+		header.Set("Deprecation", fmt.Sprintf("@%d", d.Deprecated.Unix()))
+	}
+	if !d.Sunset.IsZero() {
+		header.Set("Sunset", d.Sunset.UTC().Format(http.TimeFormat))
+	}
+	if d.Link != "" {
+		header.Add("Link", fmt.Sprintf(`<%s>; rel="deprecation"`, d.Link))
+	}
+}
+
+// Negotiator routes requests to versioned route groups by the vendor media type in their Accept header
+type Negotiator struct {
+	// Prefix is the path the versions are served under, e.g. "/api"; requests outside it aren't routed
+	Prefix string
+	// Vendor is the vendor in the media types, e.g. "acme" for "application/vnd.acme.v2+json"
+	Vendor string
+	// DefaultVersion is used for requests without a vendor media type
+	DefaultVersion string
+	// Versions are the registered versions
+	Versions map[string]bool
+}
+
+// UnsupportedVersionError is returned by Route for a version that isn't registered; respond with 406
+// Not Acceptable
+type UnsupportedVersionError struct {
+	Version string
+}
+
+func (e *UnsupportedVersionError) Error() string {
+	return fmt.Sprintf("unsupported API version %q", e.Version)
+}
+
+// Route inserts the version from accept into u's path after the prefix, e.g. /api/users becomes
+// /api/v2/users, so the router finds the version's group. Paths outside the prefix and paths that
+// already start with the version are left alone.
+func (n *Negotiator) Route(u *url.URL, accept []string) error {
+	path, underPrefix := strings.CutPrefix(u.Path, n.Prefix)
+	if !underPrefix || (path != "" && !strings.HasPrefix(path, "/")) {
+		return nil
+	}
+
+	version := AcceptedVersion(accept, n.Vendor)
+	if version == "" {
+		version = n.DefaultVersion
+	}
+	if !n.Versions[version] {
+		return &UnsupportedVersionError{Version: version}
+	}
+
+	versionPath := "/" + version
+	if path == versionPath || strings.HasPrefix(path, versionPath+"/") {
+		return nil
+	}
+
+	u.Path = n.Prefix + versionPath + path
+	if u.RawPath != "" {
+		u.RawPath = n.Prefix + versionPath + strings.TrimPrefix(u.RawPath, n.Prefix)
+	}
+	return nil
+}
+
+// AcceptedVersion returns the version in the first of vendor's media types in accept, e.g. "v2" from
+// "application/vnd.acme.v2+json", or "" if there isn't one
+func AcceptedVersion(accept []string, vendor string) string {
+	prefix := "application/vnd." + vendor + "."
+	for _, value := range accept {
+		for _, mediaRange := range strings.Split(value, ",") {
+			mediaType, _, err := mime.ParseMediaType(strings.TrimSpace(mediaRange))
+			if err != nil {
+				continue
+			}
+			version, found := strings.CutPrefix(mediaType, prefix)
+			if !found {
+				continue
+			}
+			version, _, _ = strings.Cut(version, "+")
+			if version != "" {
+				return version
+			}
+		}
+	}
+	return ""
+}
@@ -0,0 +1,88 @@
+package apiversion
+
+import (
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDeprecationSetHeaders(t *testing.T) {
+	t.Run("sets_the_headers", func(t *testing.T) {
+		header := http.Header{}
+
+		Deprecation{
+			Deprecated: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+			Sunset:     time.Date(2024, 7, 1, 0, 0, 0, 0, time.UTC),
+			Link:       "https://example.com/migrate",
+		}.SetHeaders(header)
+
+		assert.Equal(t, "@1704067200", header.Get("Deprecation"))
+		assert.Equal(t, "Mon, 01 Jul 2024 00:00:00 GMT", header.Get("Sunset"))
+		assert.Equal(t, `<https://example.com/migrate>; rel="deprecation"`, header.Get("Link"))
+	})
+
+	t.Run("sets_nothing_for_a_current_version", func(t *testing.T) {
+		header := http.Header{}
+
+		Deprecation{}.SetHeaders(header)
+
+		assert.Empty(t, header)
+	})
+}
+
+func TestNegotiatorRoute(t *testing.T) {
+	negotiator := &Negotiator{Prefix: "/api", Vendor: "acme", DefaultVersion: "v1", Versions: map[string]bool{"v1": true, "v2": true}}
+
+	t.Run("inserts_the_accepted_version", func(t *testing.T) {
+		u, _ := url.Parse("/api/users/a%2Fb")
+
+		err := negotiator.Route(u, []string{"application/vnd.acme.v2+json"})
+
+		assert.NoError(t, err)
+		assert.Equal(t, "/api/v2/users/a/b", u.Path)
+		assert.Equal(t, "/api/v2/users/a%2Fb", u.RawPath)
+	})
+
+	t.Run("uses_the_default_version", func(t *testing.T) {
+		u, _ := url.Parse("/api/users")
+
+		err := negotiator.Route(u, nil)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "/api/v1/users", u.Path)
+	})
+
+	t.Run("leaves_versioned_paths_and_paths_outside_the_prefix_alone", func(t *testing.T) {
+		for _, path := range []string{"/api/v2/users", "/apis", "/healthz"} {
+			u, _ := url.Parse(path)
+
+			err := negotiator.Route(u, []string{"application/vnd.acme.v2+json"})
+
+			assert.NoError(t, err)
+			assert.Equal(t, path, u.Path)
+		}
+	})
+
+	t.Run("returns_an_error_for_an_unsupported_version", func(t *testing.T) {
+		u, _ := url.Parse("/api/users")
+
+		err := negotiator.Route(u, []string{"application/vnd.acme.v9+json"})
+
+		assert.EqualError(t, err, `unsupported API version "v9"`)
+	})
+}
+
+func TestAcceptedVersion(t *testing.T) {
+	t.Run("returns_the_first_vendor_version", func(t *testing.T) {
+		assert.Equal(t, "v2", AcceptedVersion([]string{"text/html, application/vnd.acme.v2+json; q=0.9", "application/vnd.acme.v3+json"}, "acme"))
+		assert.Equal(t, "v3", AcceptedVersion([]string{"application/vnd.acme.v3"}, "acme"))
+	})
+
+	t.Run("returns_empty_without_a_vendor_media_type", func(t *testing.T) {
+		assert.Equal(t, "", AcceptedVersion([]string{"application/json", "application/vnd.other.v2+json"}, "acme"))
+		assert.Equal(t, "", AcceptedVersion(nil, "acme"))
+	})
+}
@@ -0,0 +1,91 @@
+// Package audit defines the audit record written by the echokit and ginkit audit middleware, so
+// security teams get the same events regardless of framework.
+package audit
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/half-ogre/go-kit/logkit/logfields"
+)
+
+// Outcome is the result of an audited request
+type Outcome string
+
+const (
+	// OutcomeSuccess is a request that completed with a 1xx, 2xx, or 3xx status
+	OutcomeSuccess Outcome = "success"
+	// OutcomeDenied is a request rejected with 401 Unauthorized or 403 Forbidden
+	OutcomeDenied Outcome = "denied"
+	// OutcomeFailure is any other request that completed with a 4xx or 5xx status
+	OutcomeFailure Outcome = "failure"
+)
+
+// OutcomeForStatus returns the outcome of a request that completed with status
+func OutcomeForStatus(status int) Outcome {
+	switch {
+	case status == http.StatusUnauthorized || status == http.StatusForbidden:
+		return OutcomeDenied
+	case status >= 400:
+		return OutcomeFailure
+	default:
+		return OutcomeSuccess
+	}
+}
+
+// Record is an audit event for one request
+type Record struct {
+	Time      time.Time `json:"time"`
+	Actor     string    `json:"actor"`
+	Action    string    `json:"action"`
+	Resource  string    `json:"resource"`
+	Outcome   Outcome   `json:"outcome"`
+	Status    int       `json:"status"`
+	Method    string    `json:"method"`
+	RequestID string    `json:"request_id,omitempty"`
+	RemoteIP  string    `json:"remote_ip,omitempty"`
+}
+
+// Sink receives audit records, e.g. to ship them to a SIEM or an append-only store
+type Sink interface {
+	Write(ctx context.Context, record Record) error
+}
+
+// SinkFunc adapts a function to the Sink interface
+type SinkFunc func(ctx context.Context, record Record) error
+
+func (f SinkFunc) Write(ctx context.Context, record Record) error {
+	return f(ctx, record)
+}
+
+// NewSlogSink returns a Sink that logs each record as an "audit" message at INFO
+func NewSlogSink(logger *slog.Logger) Sink {
+	return SinkFunc(func(ctx context.Context, record Record) error {
+		logger.InfoContext(ctx, "audit",
+			"time", record.Time,
+			"actor", record.Actor,
+			"action", record.Action,
+			"resource", record.Resource,
+			"outcome", string(record.Outcome),
+			"status", record.Status,
+			"method", record.Method,
+			logfields.RequestID(record.RequestID),
+			"remote_ip", record.RemoteIP,
+		)
+		return nil
+	})
+}
+
+// Write sends record to sink, logging rather than returning a failure so auditing never fails a
+// request that has already been handled
+func Write(ctx context.Context, sink Sink, record Record) {
+	if err := sink.Write(ctx, record); err != nil {
+		slog.ErrorContext(ctx, "failed to write audit record",
+			"error", err.Error(),
+			"action", record.Action,
+			"resource", record.Resource,
+		)
+	}
+}
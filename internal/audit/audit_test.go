@@ -0,0 +1,64 @@
+package audit
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOutcomeForStatus(t *testing.T) {
+	t.Run("maps_statuses_to_outcomes", func(t *testing.T) {
+		assert.Equal(t, OutcomeSuccess, OutcomeForStatus(http.StatusOK))
+		assert.Equal(t, OutcomeSuccess, OutcomeForStatus(http.StatusFound))
+		assert.Equal(t, OutcomeDenied, OutcomeForStatus(http.StatusUnauthorized))
+		assert.Equal(t, OutcomeDenied, OutcomeForStatus(http.StatusForbidden))
+		assert.Equal(t, OutcomeFailure, OutcomeForStatus(http.StatusNotFound))
+		assert.Equal(t, OutcomeFailure, OutcomeForStatus(http.StatusInternalServerError))
+	})
+}
+
+func TestNewSlogSink(t *testing.T) {
+	t.Run("logs_the_record", func(t *testing.T) {
+		var logBuf bytes.Buffer
+		sink := NewSlogSink(slog.New(slog.NewJSONHandler(&logBuf, nil)))
+
+		err := sink.Write(context.Background(), Record{
+			Time:     time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC),
+			Actor:    "theActor",
+			Action:   "theAction",
+			Resource: "theResource",
+			Outcome:  OutcomeDenied,
+			Status:   http.StatusForbidden,
+		})
+
+		assert.NoError(t, err)
+		assert.Contains(t, logBuf.String(), `"msg":"audit"`)
+		assert.Contains(t, logBuf.String(), `"actor":"theActor"`)
+		assert.Contains(t, logBuf.String(), `"action":"theAction"`)
+		assert.Contains(t, logBuf.String(), `"resource":"theResource"`)
+		assert.Contains(t, logBuf.String(), `"outcome":"denied"`)
+		assert.Contains(t, logBuf.String(), `"status":403`)
+	})
+}
+
+func TestWrite(t *testing.T) {
+	t.Run("logs_a_sink_failure", func(t *testing.T) {
+		var logBuf bytes.Buffer
+		slog.SetDefault(slog.New(slog.NewJSONHandler(&logBuf, nil)))
+		t.Cleanup(func() { slog.SetDefault(slog.Default()) })
+		sink := SinkFunc(func(ctx context.Context, record Record) error {
+			return errors.New("the sink error")
+		})
+
+		Write(context.Background(), sink, Record{Action: "theAction"})
+
+		assert.Contains(t, logBuf.String(), `"msg":"failed to write audit record"`)
+		assert.Contains(t, logBuf.String(), `"error":"the sink error"`)
+	})
+}
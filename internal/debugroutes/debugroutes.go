@@ -0,0 +1,245 @@
+// Package debugroutes implements the debug endpoints registered by echokit and ginkit: route listing,
+// redacted config, runtime log levels, build info, and pprof.
+package debugroutes
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"net/http/pprof"
+	"reflect"
+	"runtime"
+	"sort"
+	"strings"
+
+	"github.com/half-ogre/go-kit/logkit"
+	"github.com/half-ogre/go-kit/versionkit"
+)
+
+// Prefix is the path the debug routes are registered under. pprof's index only serves profiles
+// below /debug/pprof/, so it is not configurable.
+const Prefix = "/debug"
+
+const redactedValue = "[REDACTED]"
+
+// RedactKeys are the substrings that mark a config key as sensitive, matched case-insensitively
+var RedactKeys = []string{
+	"password",
+	"secret",
+	"token",
+	"credential",
+	"apikey",
+	"api_key",
+	"private_key",
+	"authorization",
+	"dsn",
+}
+
+// Route is one registered route in the /debug/routes response
+type Route struct {
+	Method  string `json:"method"`
+	Path    string `json:"path"`
+	Handler string `json:"handler,omitempty"`
+}
+
+// RoutesResponse is the /debug/routes response body
+type RoutesResponse struct {
+	Routes     []Route  `json:"routes"`
+	Middleware []string `json:"middleware,omitempty"`
+}
+
+// SortRoutes orders routes by path, then method
+func SortRoutes(routes []Route) {
+	sort.Slice(routes, func(i, j int) bool {
+		if routes[i].Path != routes[j].Path {
+			return routes[i].Path < routes[j].Path
+		}
+		return routes[i].Method < routes[j].Method
+	})
+}
+
+// FuncName returns the name of the function f, e.g. "github.com/gin-gonic/gin.LoggerWithConfig.func1"
+func FuncName(f any) string {
+	value := reflect.ValueOf(f)
+	if value.Kind() != reflect.Func {
+		return ""
+	}
+	if fn := runtime.FuncForPC(value.Pointer()); fn != nil {
+		return fn.Name()
+	}
+	return ""
+}
+
+// Redact returns config as generic JSON with the values of sensitive keys replaced with [REDACTED]
+func Redact(config any) (any, error) {
+	if config == nil {
+		return map[string]any{}, nil
+	}
+
+	data, err := json.Marshal(config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal config: %w", err)
+	}
+
+	var value any
+	if err := json.Unmarshal(data, &value); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal config: %w", err)
+	}
+
+	return redactValue(value), nil
+}
+
+func redactValue(value any) any {
+	switch v := value.(type) {
+	case map[string]any:
+		for key, fieldValue := range v {
+			if isSensitiveKey(key) {
+				v[key] = redactedValue
+			} else {
+				v[key] = redactValue(fieldValue)
+			}
+		}
+		return v
+	case []any:
+		for i, element := range v {
+			v[i] = redactValue(element)
+		}
+		return v
+	default:
+		return v
+	}
+}
+
+func isSensitiveKey(key string) bool {
+	key = strings.ToLower(key)
+	for _, sensitive := range RedactKeys {
+		if strings.Contains(key, sensitive) {
+			return true
+		}
+	}
+	return false
+}
+
+// LogLevelRequest is the /debug/loglevel PUT body and response body, e.g. {"level":"info,dynamodbkit=debug"}
+type LogLevelRequest struct {
+	Level string `json:"level"`
+}
+
+// CurrentLogLevel returns the level spec in effect: the logkit level spec if one has been set,
+// otherwise levelVar's level, otherwise an empty string
+func CurrentLogLevel(levelVar *slog.LevelVar) string {
+	if spec, ok := logkit.GetLevelSpec(); ok {
+		return spec.String()
+	}
+	if levelVar != nil {
+		return strings.ToLower(levelVar.Level().String())
+	}
+	return ""
+}
+
+// SetLogLevel parses spec with logkit.ParseLevelSpec, applies it to module loggers, and sets levelVar
+// (when not nil) to the spec's default level. It returns the spec now in effect.
+func SetLogLevel(levelVar *slog.LevelVar, spec string) (string, error) {
+	levelSpec, err := logkit.ParseLevelSpec(spec)
+	if err != nil {
+		return "", err
+	}
+
+	logkit.SetLevelSpec(levelSpec)
+	if levelVar != nil {
+		levelVar.Set(levelSpec.Default)
+	}
+
+	return levelSpec.String(), nil
+}
+
+// BuildResponse is the /debug/build response body
+type BuildResponse struct {
+	Version   string `json:"version"`
+	GitCommit string `json:"git_commit"`
+	BuildDate string `json:"build_date"`
+	GoVersion string `json:"go_version"`
+}
+
+// Build returns the /debug/build response for info, or for versionkit.GetBuildInfo when info is nil
+func Build(info *versionkit.BuildInfo) BuildResponse {
+	if info == nil {
+		info = versionkit.GetBuildInfo()
+	}
+	return BuildResponse{
+		Version:   info.GetBuildVersion(),
+		GitCommit: info.GetBuildCommit(),
+		BuildDate: info.GetBuildDate(),
+		GoVersion: runtime.Version(),
+	}
+}
+
+// LoopbackNetworks are the networks allowed when no permission or allowed IPs are configured
+var LoopbackNetworks = []*net.IPNet{
+	{IP: net.IPv4(127, 0, 0, 0), Mask: net.CIDRMask(8, 32)},
+	{IP: net.IPv6loopback, Mask: net.CIDRMask(128, 128)},
+}
+
+// ParseNetworks parses IPs and CIDRs (e.g. "10.0.0.0/8", "192.168.1.10") into networks
+func ParseNetworks(ipsOrCIDRs []string) ([]*net.IPNet, error) {
+	networks := make([]*net.IPNet, 0, len(ipsOrCIDRs))
+	for _, value := range ipsOrCIDRs {
+		if strings.Contains(value, "/") {
+			_, network, err := net.ParseCIDR(value)
+			if err != nil {
+				return nil, fmt.Errorf("invalid CIDR %q", value)
+			}
+			networks = append(networks, network)
+			continue
+		}
+
+		ip := net.ParseIP(value)
+		if ip == nil {
+			return nil, fmt.Errorf("invalid IP %q", value)
+		}
+		bits := 128
+		if ip.To4() != nil {
+			ip = ip.To4()
+			bits = 32
+		}
+		networks = append(networks, &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)})
+	}
+	return networks, nil
+}
+
+// RemoteIP returns the host of remoteAddr (an http.Request's RemoteAddr), the connection's peer
+// address, which unlike forwarded headers cannot be set by the caller
+func RemoteIP(remoteAddr string) string {
+	host, _, err := net.SplitHostPort(remoteAddr)
+	if err != nil {
+		return remoteAddr
+	}
+	return host
+}
+
+// IPAllowed reports whether ip is in any of networks
+func IPAllowed(ip string, networks []*net.IPNet) bool {
+	parsed := net.ParseIP(ip)
+	if parsed == nil {
+		return false
+	}
+	for _, network := range networks {
+		if network.Contains(parsed) {
+			return true
+		}
+	}
+	return false
+}
+
+// PprofHandler serves the net/http/pprof endpoints under /debug/pprof/
+func PprofHandler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc(Prefix+"/pprof/", pprof.Index)
+	mux.HandleFunc(Prefix+"/pprof/cmdline", pprof.Cmdline)
+	mux.HandleFunc(Prefix+"/pprof/profile", pprof.Profile)
+	mux.HandleFunc(Prefix+"/pprof/symbol", pprof.Symbol)
+	mux.HandleFunc(Prefix+"/pprof/trace", pprof.Trace)
+	return mux
+}
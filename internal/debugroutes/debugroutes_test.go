@@ -0,0 +1,115 @@
+package debugroutes
+
+import (
+	"runtime"
+	"testing"
+
+	"github.com/half-ogre/go-kit/versionkit"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFuncName(t *testing.T) {
+	t.Run("returns_the_name_of_a_function", func(t *testing.T) {
+		assert.Equal(t, "github.com/half-ogre/go-kit/internal/debugroutes.SortRoutes", FuncName(SortRoutes))
+	})
+
+	t.Run("returns_an_empty_name_for_a_non_function", func(t *testing.T) {
+		assert.Equal(t, "", FuncName("theString"))
+	})
+}
+
+func TestBuild(t *testing.T) {
+	t.Run("uses_the_build_info", func(t *testing.T) {
+		result := Build(&versionkit.BuildInfo{Version: "1.2.3", GitCommit: "abc1234", BuildDate: "2026-01-02"})
+
+		assert.Equal(t, BuildResponse{Version: "1.2.3", GitCommit: "abc1234", BuildDate: "2026-01-02", GoVersion: runtime.Version()}, result)
+	})
+
+	t.Run("falls_back_for_missing_values", func(t *testing.T) {
+		result := Build(&versionkit.BuildInfo{})
+
+		assert.Equal(t, "dev", result.Version)
+		assert.Equal(t, "unknown", result.GitCommit)
+		assert.Equal(t, "unknown", result.BuildDate)
+	})
+}
+
+func TestRedact(t *testing.T) {
+	t.Run("redacts_sensitive_keys_in_nested_structs", func(t *testing.T) {
+		type database struct {
+			Host        string
+			DatabaseDSN string
+		}
+		theConfig := struct {
+			Region       string
+			ClientSecret string
+			Databases    []database
+		}{
+			Region:       "theRegion",
+			ClientSecret: "theSecret",
+			Databases:    []database{{Host: "theHost", DatabaseDSN: "theDSN"}},
+		}
+
+		result, err := Redact(theConfig)
+
+		assert.NoError(t, err)
+		assert.Equal(t, map[string]any{
+			"Region":       "theRegion",
+			"ClientSecret": "[REDACTED]",
+			"Databases":    []any{map[string]any{"Host": "theHost", "DatabaseDSN": "[REDACTED]"}},
+		}, result)
+	})
+
+	t.Run("returns_an_empty_object_for_a_nil_config", func(t *testing.T) {
+		result, err := Redact(nil)
+
+		assert.NoError(t, err)
+		assert.Equal(t, map[string]any{}, result)
+	})
+
+	t.Run("returns_an_error_for_a_config_that_cannot_be_marshaled", func(t *testing.T) {
+		_, err := Redact(func() {})
+
+		assert.ErrorContains(t, err, "failed to marshal config")
+	})
+}
+
+func TestParseNetworks(t *testing.T) {
+	t.Run("parses_ips_and_cidrs", func(t *testing.T) {
+		networks, err := ParseNetworks([]string{"10.0.0.0/8", "192.168.1.10", "::1"})
+
+		assert.NoError(t, err)
+		assert.True(t, IPAllowed("10.1.2.3", networks))
+		assert.True(t, IPAllowed("192.168.1.10", networks))
+		assert.True(t, IPAllowed("::1", networks))
+		assert.False(t, IPAllowed("192.168.1.11", networks))
+	})
+
+	t.Run("returns_an_error_for_an_invalid_cidr", func(t *testing.T) {
+		_, err := ParseNetworks([]string{"10.0.0.0/99"})
+
+		assert.EqualError(t, err, `invalid CIDR "10.0.0.0/99"`)
+	})
+}
+
+func TestIPAllowed(t *testing.T) {
+	t.Run("allows_loopback_addresses", func(t *testing.T) {
+		assert.True(t, IPAllowed("127.0.0.1", LoopbackNetworks))
+		assert.True(t, IPAllowed("::1", LoopbackNetworks))
+	})
+
+	t.Run("rejects_an_unparseable_ip", func(t *testing.T) {
+		assert.False(t, IPAllowed("notAnIP", LoopbackNetworks))
+	})
+}
+
+func TestRemoteIP(t *testing.T) {
+	t.Run("returns_the_host_of_the_remote_address", func(t *testing.T) {
+		assert.Equal(t, "203.0.113.7", RemoteIP("203.0.113.7:4321"))
+		assert.Equal(t, "::1", RemoteIP("[::1]:4321"))
+	})
+
+	t.Run("returns_the_remote_address_when_it_has_no_port", func(t *testing.T) {
+		assert.Equal(t, "127.0.0.1", RemoteIP("127.0.0.1"))
+	})
+}
@@ -0,0 +1,202 @@
+// Package openapi validates requests against an OpenAPI 3 spec. It holds the spec loading and error
+// mapping shared by echokit and ginkit so both frameworks enforce a contract identically.
+package openapi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/openapi3filter"
+	"github.com/getkin/kin-openapi/routers"
+	"github.com/getkin/kin-openapi/routers/gorillamux"
+
+	"github.com/half-ogre/go-kit/kit"
+)
+
+// LoadSpec loads and validates the OpenAPI spec at path, which may be JSON or YAML and may reference
+// other files relative to it
+func LoadSpec(path string) (*openapi3.T, error) {
+	loader := openapi3.NewLoader()
+	loader.IsExternalRefsAllowed = true
+
+	spec, err := loader.LoadFromFile(path)
+	if err != nil {
+		return nil, kit.WrapError(err, "failed to load OpenAPI spec %s", path)
+	}
+
+	err = spec.Validate(loader.Context)
+	if err != nil {
+		return nil, kit.WrapError(err, "invalid OpenAPI spec %s", path)
+	}
+
+	return spec, nil
+}
+
+// ValidationError is returned by Validator.ValidateRequest when a request breaks the contract. Status and
+// Message are what the frameworks respond with.
+type ValidationError struct {
+	Status  int
+	Message string
+	Err     error
+}
+
+func (e *ValidationError) Error() string {
+	return e.Message
+}
+
+func (e *ValidationError) Unwrap() error {
+	return e.Err
+}
+
+// Validator validates requests against an OpenAPI spec
+type Validator struct {
+	router             routers.Router
+	allowUnknownRoutes bool
+	filterOptions      *openapi3filter.Options
+}
+
+// ValidatorOption configures a Validator
+type ValidatorOption func(*Validator)
+
+// WithUnknownRoutesAllowed lets requests that match no operation in the spec, such as health checks,
+// through instead of rejecting them with 404 or 405
+func WithUnknownRoutesAllowed() ValidatorOption {
+	return func(v *Validator) {
+		v.allowUnknownRoutes = true
+	}
+}
+
+// WithAuthenticationFunc validates the spec's security requirements with fn. Without it security
+// requirements are ignored, leaving authentication to the authenticator middleware.
+func WithAuthenticationFunc(fn openapi3filter.AuthenticationFunc) ValidatorOption {
+	return func(v *Validator) {
+		v.filterOptions.AuthenticationFunc = fn
+	}
+}
+
+// NewValidator returns a Validator for spec. Operations are matched on the path of each server URL
+// only, so the same spec works on any host.
+func NewValidator(spec *openapi3.T, options ...ValidatorOption) (*Validator, error) {
+	if spec == nil {
+		return nil, fmt.Errorf("OpenAPI spec cannot be nil")
+	}
+
+	routerSpec := *spec
+	routerSpec.Servers = make(openapi3.Servers, 0, len(spec.Servers))
+	for _, server := range spec.Servers {
+		routerSpec.Servers = append(routerSpec.Servers, &openapi3.Server{URL: serverPath(server.URL), Variables: server.Variables})
+	}
+
+	router, err := gorillamux.NewRouter(&routerSpec)
+	if err != nil {
+		return nil, kit.WrapError(err, "failed to create OpenAPI router")
+	}
+
+	v := &Validator{
+		router: router,
+		filterOptions: &openapi3filter.Options{
+			AuthenticationFunc: openapi3filter.NoopAuthenticationFunc,
+		},
+	}
+	for _, option := range options {
+		option(v)
+	}
+
+	return v, nil
+}
+
+// serverPath drops the scheme and host from a server URL, keeping any variables in the path
+func serverPath(serverURL string) string {
+	if i := strings.Index(serverURL, "://"); i >= 0 {
+		serverURL = serverURL[i+3:]
+		if j := strings.Index(serverURL, "/"); j >= 0 {
+			serverURL = serverURL[j:]
+		} else {
+			serverURL = ""
+		}
+	}
+	if serverURL == "" {
+		return "/"
+	}
+	return serverURL
+}
+
+// ValidateRequest validates req's path, query, headers, and body against the matching operation,
+// returning a *ValidationError if it doesn't conform. The body is restored so handlers can still read it.
+func (v *Validator) ValidateRequest(req *http.Request) error {
+	// The router matches on the full URL, so give it the request's path without the host
+	routeReq := req.Clone(req.Context())
+	routeReq.URL = &url.URL{Path: req.URL.Path, RawPath: req.URL.RawPath, RawQuery: req.URL.RawQuery}
+	routeReq.Host = ""
+
+	route, pathParams, err := v.router.FindRoute(routeReq)
+	if err != nil {
+		if v.allowUnknownRoutes {
+			return nil
+		}
+		return routeError(err)
+	}
+
+	input := &openapi3filter.RequestValidationInput{
+		Request:    req,
+		PathParams: pathParams,
+		Route:      route,
+		Options:    v.filterOptions,
+	}
+
+	err = openapi3filter.ValidateRequest(context.WithoutCancel(req.Context()), input)
+	if err != nil {
+		return validationError(err)
+	}
+
+	return nil
+}
+
+func routeError(err error) *ValidationError {
+	var routeErr *routers.RouteError
+	if errors.As(err, &routeErr) && errors.Is(routeErr, routers.ErrMethodNotAllowed) {
+		return &ValidationError{Status: http.StatusMethodNotAllowed, Message: "method not allowed", Err: err}
+	}
+	return &ValidationError{Status: http.StatusNotFound, Message: "no matching operation was found", Err: err}
+}
+
+func validationError(err error) *ValidationError {
+	var requestErr *openapi3filter.RequestError
+	if errors.As(err, &requestErr) {
+		return &ValidationError{Status: http.StatusBadRequest, Message: requestErrorMessage(requestErr), Err: err}
+	}
+
+	var securityErr *openapi3filter.SecurityRequirementsError
+	if errors.As(err, &securityErr) {
+		return &ValidationError{Status: http.StatusUnauthorized, Message: "security requirements not met", Err: err}
+	}
+
+	return &ValidationError{Status: http.StatusBadRequest, Message: "invalid request", Err: err}
+}
+
+func requestErrorMessage(err *openapi3filter.RequestError) string {
+	reason := err.Reason
+	var schemaErr *openapi3.SchemaError
+	if errors.As(err.Err, &schemaErr) {
+		reason = schemaErr.Reason
+		if pointer := schemaErr.JSONPointer(); len(pointer) > 0 {
+			reason = fmt.Sprintf("%s: %s", "/"+strings.Join(pointer, "/"), reason)
+		}
+	} else if reason == "" && err.Err != nil {
+		reason = err.Err.Error()
+	}
+
+	switch {
+	case err.Parameter != nil:
+		return fmt.Sprintf("invalid %s parameter %s: %s", err.Parameter.In, err.Parameter.Name, reason)
+	case err.RequestBody != nil:
+		return fmt.Sprintf("invalid request body: %s", reason)
+	default:
+		return reason
+	}
+}
@@ -0,0 +1,188 @@
+package openapi
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/stretchr/testify/assert"
+)
+
+const testSpec = `openapi: 3.0.3
+info:
+  title: Users
+  version: 1.0.0
+servers:
+  - url: https://api.example.com/v1
+paths:
+  /users/{id}:
+    get:
+      parameters:
+        - name: id
+          in: path
+          required: true
+          schema:
+            type: integer
+        - name: fields
+          in: query
+          schema:
+            type: string
+            enum: [name, email]
+      responses:
+        "200":
+          description: the user
+  /users:
+    post:
+      requestBody:
+        required: true
+        content:
+          application/json:
+            schema:
+              type: object
+              required: [name]
+              properties:
+                name:
+                  type: string
+                age:
+                  type: integer
+      responses:
+        "201":
+          description: created
+`
+
+func newTestValidator(t *testing.T, options ...ValidatorOption) *Validator {
+	spec, err := openapi3.NewLoader().LoadFromData([]byte(testSpec))
+	assert.NoError(t, err)
+	validator, err := NewValidator(spec, options...)
+	assert.NoError(t, err)
+	return validator
+}
+
+func newTestJSONRequest(method string, target string, body string) *http.Request {
+	req := httptest.NewRequest(method, target, strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	return req
+}
+
+func TestLoadSpec(t *testing.T) {
+	t.Run("loads_a_yaml_spec", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "openapi.yaml")
+		assert.NoError(t, os.WriteFile(path, []byte(testSpec), 0o600))
+
+		spec, err := LoadSpec(path)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "Users", spec.Info.Title)
+	})
+
+	t.Run("returns_an_error_for_an_invalid_spec", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "openapi.yaml")
+		assert.NoError(t, os.WriteFile(path, []byte("openapi: 3.0.3\npaths: {}\n"), 0o600))
+
+		_, err := LoadSpec(path)
+
+		assert.ErrorContains(t, err, "invalid OpenAPI spec "+path)
+	})
+}
+
+func TestValidateRequest(t *testing.T) {
+	t.Run("accepts_a_conforming_request_on_any_host", func(t *testing.T) {
+		validator := newTestValidator(t)
+		req := httptest.NewRequest(http.MethodGet, "http://localhost:8080/v1/users/42?fields=name", nil)
+
+		err := validator.ValidateRequest(req)
+
+		assert.NoError(t, err)
+	})
+
+	t.Run("rejects_an_invalid_path_parameter", func(t *testing.T) {
+		validator := newTestValidator(t)
+		req := httptest.NewRequest(http.MethodGet, "/v1/users/abc", nil)
+
+		err := validator.ValidateRequest(req)
+
+		var validationErr *ValidationError
+		assert.ErrorAs(t, err, &validationErr)
+		assert.Equal(t, http.StatusBadRequest, validationErr.Status)
+		assert.Contains(t, validationErr.Message, "invalid path parameter id")
+	})
+
+	t.Run("rejects_an_invalid_query_parameter", func(t *testing.T) {
+		validator := newTestValidator(t)
+		req := httptest.NewRequest(http.MethodGet, "/v1/users/42?fields=password", nil)
+
+		err := validator.ValidateRequest(req)
+
+		var validationErr *ValidationError
+		assert.ErrorAs(t, err, &validationErr)
+		assert.Equal(t, http.StatusBadRequest, validationErr.Status)
+		assert.Contains(t, validationErr.Message, "invalid query parameter fields")
+	})
+
+	t.Run("rejects_an_invalid_body_and_names_the_field", func(t *testing.T) {
+		validator := newTestValidator(t)
+		req := newTestJSONRequest(http.MethodPost, "/v1/users", `{"name":"theName","age":"old"}`)
+
+		err := validator.ValidateRequest(req)
+
+		var validationErr *ValidationError
+		assert.ErrorAs(t, err, &validationErr)
+		assert.Equal(t, http.StatusBadRequest, validationErr.Status)
+		assert.Contains(t, validationErr.Message, "invalid request body: /age:")
+	})
+
+	t.Run("restores_the_body_after_validating_it", func(t *testing.T) {
+		validator := newTestValidator(t)
+		req := newTestJSONRequest(http.MethodPost, "/v1/users", `{"name":"theName"}`)
+
+		err := validator.ValidateRequest(req)
+
+		assert.NoError(t, err)
+		body, _ := io.ReadAll(req.Body)
+		assert.Equal(t, `{"name":"theName"}`, string(body))
+	})
+
+	t.Run("rejects_an_unknown_path_with_not_found", func(t *testing.T) {
+		validator := newTestValidator(t)
+		req := httptest.NewRequest(http.MethodGet, "/v1/health", nil)
+
+		err := validator.ValidateRequest(req)
+
+		var validationErr *ValidationError
+		assert.ErrorAs(t, err, &validationErr)
+		assert.Equal(t, http.StatusNotFound, validationErr.Status)
+	})
+
+	t.Run("rejects_an_unknown_method_with_method_not_allowed", func(t *testing.T) {
+		validator := newTestValidator(t)
+		req := httptest.NewRequest(http.MethodDelete, "/v1/users/42", nil)
+
+		err := validator.ValidateRequest(req)
+
+		var validationErr *ValidationError
+		assert.ErrorAs(t, err, &validationErr)
+		assert.Equal(t, http.StatusMethodNotAllowed, validationErr.Status)
+	})
+
+	t.Run("lets_unknown_routes_through_when_allowed", func(t *testing.T) {
+		validator := newTestValidator(t, WithUnknownRoutesAllowed())
+		req := httptest.NewRequest(http.MethodGet, "/v1/health", nil)
+
+		err := validator.ValidateRequest(req)
+
+		assert.NoError(t, err)
+	})
+}
+
+func TestNewValidator(t *testing.T) {
+	t.Run("returns_an_error_when_spec_is_nil", func(t *testing.T) {
+		_, err := NewValidator(nil)
+
+		assert.EqualError(t, err, "OpenAPI spec cannot be nil")
+	})
+}
@@ -0,0 +1,233 @@
+// Package respond picks and writes response media types from the Accept header and streams NDJSON and
+// CSV responses. It holds the encoder registry shared by echokit and ginkit, so an encoder registered
+// with either is used by both.
+package respond
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"mime"
+	"net/http"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/half-ogre/go-kit/kit"
+)
+
+// Media types with default encoders
+const (
+	MIMEApplicationJSON = "application/json"
+	MIMETextHTML        = "text/html"
+	MIMETextCSV         = "text/csv"
+)
+
+// Encoder writes model as the response with status in the encoder's media type
+type Encoder func(w http.ResponseWriter, status int, model any) error
+
+type registeredEncoder struct {
+	mediaType string
+	encode    Encoder
+}
+
+var (
+	encodersMu sync.RWMutex
+	encoders   = defaultEncoders()
+)
+
+// defaultEncoders returns JSON, HTML, and CSV. HTML has no encoder, as the framework renders it with
+// its templates, unless one is registered.
+func defaultEncoders() []registeredEncoder {
+	return []registeredEncoder{
+		{mediaType: MIMEApplicationJSON, encode: EncodeJSON},
+		{mediaType: MIMETextHTML},
+		{mediaType: MIMETextCSV, encode: EncodeCSV},
+	}
+}
+
+// Register sets the encoder for mediaType, replacing any already registered for it. New media types
+// are offered after the existing ones when the Accept header doesn't prefer one.
+func Register(mediaType string, encoder Encoder) {
+	encodersMu.Lock()
+	defer encodersMu.Unlock()
+
+	for i := range encoders {
+		if encoders[i].mediaType == mediaType {
+			encoders[i].encode = encoder
+			return
+		}
+	}
+	encoders = append(encoders, registeredEncoder{mediaType: mediaType, encode: encoder})
+}
+
+// ResetEncoders restores the default encoders, for tests
+func ResetEncoders() {
+	encodersMu.Lock()
+	defer encodersMu.Unlock()
+	encoders = defaultEncoders()
+}
+
+// Negotiate returns the media type and encoder for the most preferred media range in accept, taking
+// encoders in registration order for wildcards and ties. A request without an Accept header gets the
+// first, JSON. canRenderHTML says whether the framework can render HTML for the request; when it
+// picks HTML with no registered encoder, the returned encoder is nil and the framework renders it.
+func Negotiate(accept []string, canRenderHTML bool) (string, Encoder, bool) {
+	encodersMu.RLock()
+	defer encodersMu.RUnlock()
+
+	mediaRanges := ParseAccept(accept)
+	if len(mediaRanges) == 0 {
+		mediaRanges = []MediaRange{{MediaType: "*/*", Quality: 1}}
+	}
+
+	for _, mediaRange := range mediaRanges {
+		for _, encoder := range encoders {
+			if encoder.encode == nil && !canRenderHTML {
+				continue
+			}
+			if mediaRange.Matches(encoder.mediaType) {
+				return encoder.mediaType, encoder.encode, true
+			}
+		}
+	}
+	return "", nil, false
+}
+
+// MediaRange is a media range from an Accept header, e.g. "text/*", with its quality
+type MediaRange struct {
+	MediaType string
+	Quality   float64
+}
+
+// Matches reports whether mediaType is in the range
+func (r MediaRange) Matches(mediaType string) bool {
+	if r.MediaType == "*/*" || r.MediaType == mediaType {
+		return true
+	}
+	rangeType, rangeSubtype, _ := strings.Cut(r.MediaType, "/")
+	typ, _, _ := strings.Cut(mediaType, "/")
+	return rangeSubtype == "*" && rangeType == typ
+}
+
+// ParseAccept returns the media ranges in accept ordered by quality, leaving out any that can't be
+// parsed or have a quality of 0, which aren't acceptable
+func ParseAccept(accept []string) []MediaRange {
+	var mediaRanges []MediaRange
+	for _, value := range accept {
+		for _, part := range strings.Split(value, ",") {
+			mediaType, params, err := mime.ParseMediaType(strings.TrimSpace(part))
+			if err != nil {
+				continue
+			}
+
+			quality := 1.0
+			if q, ok := params["q"]; ok {
+				if quality, err = strconv.ParseFloat(q, 64); err != nil {
+					continue
+				}
+			}
+			if quality <= 0 {
+				continue
+			}
+
+			mediaRanges = append(mediaRanges, MediaRange{MediaType: mediaType, Quality: quality})
+		}
+	}
+
+	sort.SliceStable(mediaRanges, func(i, j int) bool {
+		return mediaRanges[i].Quality > mediaRanges[j].Quality
+	})
+	return mediaRanges
+}
+
+// EncodeJSON writes model as JSON
+func EncodeJSON(w http.ResponseWriter, status int, model any) error {
+	w.Header().Set("Content-Type", MIMEApplicationJSON)
+	w.WriteHeader(status)
+	return json.NewEncoder(w).Encode(model)
+}
+
+// EncodeCSV writes model as CSV; see CSVRecords for the models it can write
+func EncodeCSV(w http.ResponseWriter, status int, model any) error {
+	records, err := CSVRecords(model)
+	if err != nil {
+		return err
+	}
+
+	w.Header().Set("Content-Type", MIMETextCSV+"; charset=utf-8")
+	w.WriteHeader(status)
+
+	writer := csv.NewWriter(w)
+	if err := writer.WriteAll(records); err != nil {
+		return kit.WrapError(err, "error writing CSV")
+	}
+	return nil
+}
+
+// CSVMarshaler is implemented by models that write themselves as CSV records, header first
+type CSVMarshaler interface {
+	MarshalCSV() ([][]string, error)
+}
+
+// CSVRecords returns model as CSV records. Besides a CSVMarshaler or [][]string, model can be a slice
+// of structs, written with a header row of the fields' csv tags, or their names without one. A field
+// tagged csv:"-" is left out.
+func CSVRecords(model any) ([][]string, error) {
+	switch m := model.(type) {
+	case CSVMarshaler:
+		return m.MarshalCSV()
+	case [][]string:
+		return m, nil
+	}
+
+	value := reflect.ValueOf(model)
+	if value.Kind() != reflect.Slice {
+		return nil, fmt.Errorf("can't write %T as CSV; use a slice of structs, [][]string, or a CSVMarshaler", model)
+	}
+	elemType := value.Type().Elem()
+	for elemType.Kind() == reflect.Pointer {
+		elemType = elemType.Elem()
+	}
+	if elemType.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("can't write %T as CSV; use a slice of structs, [][]string, or a CSVMarshaler", model)
+	}
+
+	var header []string
+	var fields []int
+	for i := 0; i < elemType.NumField(); i++ {
+		field := elemType.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		name := field.Tag.Get("csv")
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		header = append(header, name)
+		fields = append(fields, i)
+	}
+
+	records := make([][]string, 0, value.Len()+1)
+	records = append(records, header)
+	for i := 0; i < value.Len(); i++ {
+		elem := value.Index(i)
+		for elem.Kind() == reflect.Pointer {
+			elem = elem.Elem()
+		}
+
+		record := make([]string, len(fields))
+		if elem.IsValid() {
+			for j, field := range fields {
+				record[j] = fmt.Sprint(elem.Field(field).Interface())
+			}
+		}
+		records = append(records, record)
+	}
+	return records, nil
+}
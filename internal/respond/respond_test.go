@@ -0,0 +1,143 @@
+package respond
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNegotiate(t *testing.T) {
+	t.Run("picks_json_without_an_accept_header", func(t *testing.T) {
+		mediaType, encode, ok := Negotiate(nil, true)
+
+		assert.True(t, ok)
+		assert.Equal(t, MIMEApplicationJSON, mediaType)
+		assert.NotNil(t, encode)
+	})
+
+	t.Run("returns_a_nil_encoder_for_html_the_framework_renders", func(t *testing.T) {
+		mediaType, encode, ok := Negotiate([]string{"text/html,application/json;q=0.9"}, true)
+
+		assert.True(t, ok)
+		assert.Equal(t, MIMETextHTML, mediaType)
+		assert.Nil(t, encode)
+	})
+
+	t.Run("skips_html_when_the_framework_cannot_render_it", func(t *testing.T) {
+		mediaType, _, ok := Negotiate([]string{"text/html,*/*;q=0.8"}, false)
+
+		assert.True(t, ok)
+		assert.Equal(t, MIMEApplicationJSON, mediaType)
+	})
+
+	t.Run("matches_a_subtype_wildcard", func(t *testing.T) {
+		mediaType, _, ok := Negotiate([]string{"text/*"}, false)
+
+		assert.True(t, ok)
+		assert.Equal(t, MIMETextCSV, mediaType)
+	})
+
+	t.Run("returns_false_when_no_accepted_media_type_has_an_encoder", func(t *testing.T) {
+		_, _, ok := Negotiate([]string{"application/xml", "application/json;q=0"}, true)
+
+		assert.False(t, ok)
+	})
+
+	t.Run("uses_a_registered_encoder", func(t *testing.T) {
+		t.Cleanup(ResetEncoders)
+		Register("application/xml", EncodeJSON)
+
+		mediaType, encode, ok := Negotiate([]string{"application/xml"}, false)
+
+		assert.True(t, ok)
+		assert.Equal(t, "application/xml", mediaType)
+		assert.NotNil(t, encode)
+	})
+
+	t.Run("renders_html_with_a_registered_encoder_when_the_framework_cannot", func(t *testing.T) {
+		t.Cleanup(ResetEncoders)
+		Register(MIMETextHTML, EncodeJSON)
+
+		mediaType, encode, ok := Negotiate([]string{"text/html"}, false)
+
+		assert.True(t, ok)
+		assert.Equal(t, MIMETextHTML, mediaType)
+		assert.NotNil(t, encode)
+	})
+}
+
+func TestParseAccept(t *testing.T) {
+	t.Run("orders_media_ranges_by_quality_keeping_header_order_for_ties", func(t *testing.T) {
+		mediaRanges := ParseAccept([]string{"text/csv;q=0.5, application/json", "text/html"})
+
+		assert.Equal(t, []MediaRange{
+			{MediaType: "application/json", Quality: 1},
+			{MediaType: "text/html", Quality: 1},
+			{MediaType: "text/csv", Quality: 0.5},
+		}, mediaRanges)
+	})
+
+	t.Run("leaves_out_unacceptable_and_invalid_media_ranges", func(t *testing.T) {
+		mediaRanges := ParseAccept([]string{"application/json;q=0, text/csv;q=aQuality, /, text/html"})
+
+		assert.Equal(t, []MediaRange{{MediaType: "text/html", Quality: 1}}, mediaRanges)
+	})
+}
+
+type testCSVMarshaler struct{}
+
+func (testCSVMarshaler) MarshalCSV() ([][]string, error) {
+	return [][]string{{"theHeader"}, {"theValue"}}, nil
+}
+
+func TestEncodeCSV(t *testing.T) {
+	t.Run("writes_the_records_with_the_status", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+
+		err := EncodeCSV(rec, http.StatusCreated, testCSVMarshaler{})
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusCreated, rec.Code)
+		assert.Equal(t, "text/csv; charset=utf-8", rec.Header().Get("Content-Type"))
+		assert.Equal(t, "theHeader\ntheValue\n", rec.Body.String())
+	})
+
+	t.Run("writes_nothing_for_a_model_it_cannot_write", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+
+		err := EncodeCSV(rec, http.StatusOK, "aModel")
+
+		assert.Error(t, err)
+		assert.Empty(t, rec.Header().Get("Content-Type"))
+		assert.Zero(t, rec.Body.Len())
+	})
+}
+
+func TestCSVRecords(t *testing.T) {
+	t.Run("uses_a_csv_marshaler", func(t *testing.T) {
+		records, err := CSVRecords(testCSVMarshaler{})
+
+		assert.NoError(t, err)
+		assert.Equal(t, [][]string{{"theHeader"}, {"theValue"}}, records)
+	})
+
+	t.Run("uses_field_names_without_csv_tags_and_follows_pointers", func(t *testing.T) {
+		type row struct {
+			Name  string
+			Count int
+		}
+
+		records, err := CSVRecords([]*row{{Name: "aName", Count: 3}})
+
+		assert.NoError(t, err)
+		assert.Equal(t, [][]string{{"Name", "Count"}, {"aName", "3"}}, records)
+	})
+
+	t.Run("returns_an_error_for_a_model_that_is_not_a_slice_of_structs", func(t *testing.T) {
+		_, err := CSVRecords(map[string]string{"a": "b"})
+
+		assert.EqualError(t, err, "can't write map[string]string as CSV; use a slice of structs, [][]string, or a CSVMarshaler")
+	})
+}
@@ -0,0 +1,123 @@
+package respond
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"io"
+	"iter"
+	"net/http"
+	"time"
+
+	"github.com/half-ogre/go-kit/kit"
+)
+
+// MIMEApplicationNDJSON is the media type of newline-delimited JSON
+const MIMEApplicationNDJSON = "application/x-ndjson"
+
+// StreamOption configures Stream
+type StreamOption func(*streamConfig)
+
+type streamConfig struct {
+	flushEvery   int
+	writeTimeout time.Duration
+}
+
+// WithFlushEvery flushes the response after every n records instead of after each one
+func WithFlushEvery(n int) StreamOption {
+	return func(c *streamConfig) {
+		c.flushEvery = n
+	}
+}
+
+// WithWriteTimeout gives each flush d to reach the client, extending the server's WriteTimeout so a
+// long export isn't cut off while a client that stops reading still is
+func WithWriteTimeout(d time.Duration) StreamOption {
+	return func(c *streamConfig) {
+		c.writeTimeout = d
+	}
+}
+
+// Stream writes the records from seq with write, flushing through controller as it goes, until seq
+// ends, returns an error, or ctx is done, e.g. because the client disconnected. The status and headers
+// must already be written, so an error after that truncates the response.
+func Stream[T any](ctx context.Context, controller *http.ResponseController, seq iter.Seq2[T, error], write func(T) error, options ...StreamOption) error {
+	config := streamConfig{flushEvery: 1}
+	for _, option := range options {
+		option(&config)
+	}
+	config.flushEvery = max(config.flushEvery, 1)
+
+	flush := func() error {
+		if config.writeTimeout > 0 {
+			if err := controller.SetWriteDeadline(time.Now().Add(config.writeTimeout)); err != nil && !errors.Is(err, http.ErrNotSupported) {
+				return kit.WrapError(err, "error setting write deadline")
+			}
+		}
+		if err := controller.Flush(); err != nil && !errors.Is(err, http.ErrNotSupported) {
+			return kit.WrapError(err, "error flushing response")
+		}
+		return nil
+	}
+
+	if err := flush(); err != nil {
+		return err
+	}
+
+	written := 0
+	for record, err := range seq {
+		if err != nil {
+			return kit.WrapError(err, "error streaming response after %d records", written)
+		}
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return kit.WrapError(ctxErr, "stopped streaming response after %d records", written)
+		}
+
+		if err := write(record); err != nil {
+			return kit.WrapError(err, "error writing record %d", written)
+		}
+		written++
+
+		if written%config.flushEvery == 0 {
+			if err := flush(); err != nil {
+				return err
+			}
+		}
+	}
+
+	return flush()
+}
+
+// NDJSONWriter returns a Stream write func that writes each record to w as a line of JSON
+func NDJSONWriter[T any](w io.Writer) func(T) error {
+	encoder := json.NewEncoder(w)
+	return func(record T) error {
+		return encoder.Encode(record)
+	}
+}
+
+// CSVWriter returns a Stream write func that writes each record to w as a CSV row
+func CSVWriter(w io.Writer) func([]string) error {
+	writer := csv.NewWriter(w)
+	return func(record []string) error {
+		if err := writer.Write(record); err != nil {
+			return err
+		}
+		writer.Flush()
+		return writer.Error()
+	}
+}
+
+// WithCSVHeader returns seq with header first, or seq itself if header is nil
+func WithCSVHeader(header []string, seq iter.Seq2[[]string, error]) iter.Seq2[[]string, error] {
+	if header == nil {
+		return seq
+	}
+	return func(yield func([]string, error) bool) {
+		if !yield(header, nil) {
+			return
+		}
+		seq(yield)
+	}
+}
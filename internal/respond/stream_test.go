@@ -0,0 +1,127 @@
+package respond
+
+import (
+	"context"
+	"errors"
+	"iter"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type flushCountingRecorder struct {
+	*httptest.ResponseRecorder
+	flushes int
+}
+
+func (r *flushCountingRecorder) Flush() {
+	r.flushes++
+	r.ResponseRecorder.Flush()
+}
+
+func seqOf[T any](items ...T) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		for _, item := range items {
+			if !yield(item, nil) {
+				return
+			}
+		}
+	}
+}
+
+func TestStream(t *testing.T) {
+	t.Run("writes_each_record_and_flushes_before_after_and_at_the_end", func(t *testing.T) {
+		rec := &flushCountingRecorder{ResponseRecorder: httptest.NewRecorder()}
+
+		err := Stream(context.Background(), http.NewResponseController(rec), seqOf("theFirst", "theSecond"), NDJSONWriter[string](rec))
+
+		assert.NoError(t, err)
+		assert.Equal(t, "\"theFirst\"\n\"theSecond\"\n", rec.Body.String())
+		assert.Equal(t, 4, rec.flushes)
+	})
+
+	t.Run("flushes_after_every_n_records", func(t *testing.T) {
+		rec := &flushCountingRecorder{ResponseRecorder: httptest.NewRecorder()}
+
+		err := Stream(context.Background(), http.NewResponseController(rec), seqOf(1, 2, 3, 4, 5), NDJSONWriter[int](rec), WithFlushEvery(2))
+
+		assert.NoError(t, err)
+		assert.Equal(t, 4, rec.flushes)
+	})
+
+	t.Run("treats_a_flush_every_below_one_as_one", func(t *testing.T) {
+		rec := &flushCountingRecorder{ResponseRecorder: httptest.NewRecorder()}
+
+		err := Stream(context.Background(), http.NewResponseController(rec), seqOf(1, 2), NDJSONWriter[int](rec), WithFlushEvery(0))
+
+		assert.NoError(t, err)
+		assert.Equal(t, 4, rec.flushes)
+	})
+
+	t.Run("streams_to_a_writer_that_cannot_flush_or_set_deadlines", func(t *testing.T) {
+		var body strings.Builder
+		w := struct{ http.ResponseWriter }{httptest.NewRecorder()}
+
+		err := Stream(context.Background(), http.NewResponseController(w), seqOf(1), NDJSONWriter[int](&body), WithWriteTimeout(1))
+
+		assert.NoError(t, err)
+		assert.Equal(t, "1\n", body.String())
+	})
+
+	t.Run("stops_when_the_context_is_done", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		ctx, cancel := context.WithCancel(context.Background())
+		t.Cleanup(cancel)
+		items := func(yield func(int, error) bool) {
+			for i := 1; ; i++ {
+				if i == 2 {
+					cancel()
+				}
+				if !yield(i, nil) {
+					return
+				}
+			}
+		}
+
+		err := Stream(ctx, http.NewResponseController(rec), items, NDJSONWriter[int](rec))
+
+		assert.Equal(t, "1\n", rec.Body.String())
+		assert.EqualError(t, err, "stopped streaming response after 1 records: context canceled")
+	})
+
+	t.Run("returns_the_error_from_the_sequence", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		items := func(yield func(int, error) bool) {
+			yield(0, errors.New("the sequence error"))
+		}
+
+		err := Stream(context.Background(), http.NewResponseController(rec), items, NDJSONWriter[int](rec))
+
+		assert.EqualError(t, err, "error streaming response after 0 records: the sequence error")
+	})
+
+	t.Run("returns_the_error_from_write", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		write := func(int) error { return errors.New("the write error") }
+
+		err := Stream(context.Background(), http.NewResponseController(rec), seqOf(1), write)
+
+		assert.EqualError(t, err, "error writing record 0: the write error")
+	})
+}
+
+func TestCSVWriter(t *testing.T) {
+	t.Run("writes_the_header_and_then_the_records", func(t *testing.T) {
+		var body strings.Builder
+		write := CSVWriter(&body)
+
+		for record := range WithCSVHeader([]string{"id", "name"}, seqOf([]string{"theID", "a, name"})) {
+			assert.NoError(t, write(record))
+		}
+
+		assert.Equal(t, "id,name\ntheID,\"a, name\"\n", body.String())
+	})
+}
@@ -0,0 +1,296 @@
+// Package webhook verifies signed webhook deliveries. It holds the verifier implementations shared by
+// echokit and ginkit so both frameworks validate payloads identically.
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/half-ogre/go-kit/kit"
+)
+
+// DefaultTolerance is how far a signed webhook timestamp may be from the current time before
+// the delivery is rejected as a possible replay.
+const DefaultTolerance = 5 * time.Minute
+
+// DefaultMaxBodySize is the largest webhook body read before the delivery is rejected, so a caller
+// cannot make the handler buffer an unbounded body before its signature is checked.
+const DefaultMaxBodySize = 1 << 20
+
+var (
+	// ErrSignatureMissing is returned by a Verifier when the request has no signature
+	ErrSignatureMissing = errors.New("webhook signature missing")
+	// ErrSignatureInvalid is returned by a Verifier when no signature matches the body
+	ErrSignatureInvalid = errors.New("webhook signature invalid")
+	// ErrTimestampOutOfTolerance is returned by a Verifier when the signed timestamp is too old or too far in the future
+	ErrTimestampOutOfTolerance = errors.New("webhook timestamp outside tolerance")
+)
+
+// SecretProvider returns the secret used to verify webhook signatures. It is called for every
+// delivery so that secrets can be rotated without restarting the service.
+type SecretProvider func(ctx context.Context) ([]byte, error)
+
+// StaticSecret returns a SecretProvider that always returns secret
+func StaticSecret(secret string) SecretProvider {
+	return func(ctx context.Context) ([]byte, error) {
+		return []byte(secret), nil
+	}
+}
+
+// Verifier verifies the signature of a webhook delivery
+type Verifier interface {
+	Verify(req *http.Request, body []byte, secret []byte) error
+}
+
+// VerificationError is returned by ReadAndVerify when the verifier rejects a delivery, as opposed to
+// failing to read the body or get the secret
+type VerificationError struct {
+	Err error
+}
+
+func (e *VerificationError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *VerificationError) Unwrap() error {
+	return e.Err
+}
+
+// HandlerOption configures a webhook handler
+type HandlerOption func(*HandlerConfig)
+
+// HandlerConfig is the configuration of a webhook handler
+type HandlerConfig struct {
+	MaxBodySize int64
+}
+
+// NewHandlerConfig returns the handler configuration with options applied to the defaults
+func NewHandlerConfig(options []HandlerOption) HandlerConfig {
+	config := HandlerConfig{MaxBodySize: DefaultMaxBodySize}
+	for _, option := range options {
+		option(&config)
+	}
+	return config
+}
+
+// WithMaxBodySize sets the largest body, in bytes, read before the delivery is rejected
+func WithMaxBodySize(maxBodySize int64) HandlerOption {
+	return func(config *HandlerConfig) {
+		config.MaxBodySize = maxBodySize
+	}
+}
+
+// ReadAndVerify reads up to maxBodySize bytes of the request body, verifies its signature with
+// verifier using the secret from secretProvider, and restores the body so it can be read again by the
+// handler. A larger body returns an error wrapping *http.MaxBytesError.
+func ReadAndVerify(req *http.Request, maxBodySize int64, secretProvider SecretProvider, verifier Verifier) error {
+	body, err := io.ReadAll(http.MaxBytesReader(nil, req.Body, maxBodySize))
+	if err != nil {
+		return kit.WrapError(err, "failed to read webhook body")
+	}
+	req.Body = io.NopCloser(bytes.NewReader(body))
+
+	secret, err := secretProvider(req.Context())
+	if err != nil {
+		return kit.WrapError(err, "failed to get webhook secret")
+	}
+
+	if err := verifier.Verify(req, body, secret); err != nil {
+		return &VerificationError{Err: err}
+	}
+
+	return nil
+}
+
+// VerifierOption configures a webhook verifier
+type VerifierOption func(*verifierOptions)
+
+type verifierOptions struct {
+	clock           kit.ClockInterface
+	tolerance       time.Duration
+	timestampHeader string
+	signaturePrefix string
+}
+
+func newVerifierOptions(options []VerifierOption) verifierOptions {
+	opts := verifierOptions{
+		clock:     kit.NewClock(),
+		tolerance: DefaultTolerance,
+	}
+	for _, option := range options {
+		option(&opts)
+	}
+	return opts
+}
+
+// WithClock sets the clock used to check signed timestamps
+func WithClock(clock kit.ClockInterface) VerifierOption {
+	return func(o *verifierOptions) {
+		o.clock = clock
+	}
+}
+
+// WithTolerance sets how far a signed timestamp may be from the current time
+func WithTolerance(tolerance time.Duration) VerifierOption {
+	return func(o *verifierOptions) {
+		o.tolerance = tolerance
+	}
+}
+
+// WithTimestampHeader sets the header holding the Unix timestamp that is signed along with the body.
+// Only used by the generic HMAC verifier.
+func WithTimestampHeader(header string) VerifierOption {
+	return func(o *verifierOptions) {
+		o.timestampHeader = header
+	}
+}
+
+// WithSignaturePrefix sets a prefix (e.g. "sha256=") that is stripped from the signature header.
+// Only used by the generic HMAC verifier.
+func WithSignaturePrefix(prefix string) VerifierOption {
+	return func(o *verifierOptions) {
+		o.signaturePrefix = prefix
+	}
+}
+
+// NewGitHubVerifier returns a verifier for GitHub's X-Hub-Signature-256 header.
+// GitHub does not sign a timestamp, so there is no replay protection beyond the signature itself.
+func NewGitHubVerifier() Verifier {
+	return NewHMACVerifier("X-Hub-Signature-256", WithSignaturePrefix("sha256="))
+}
+
+type stripeVerifier struct {
+	opts verifierOptions
+}
+
+// NewStripeVerifier returns a verifier for Stripe's Stripe-Signature header, rejecting
+// deliveries whose signed timestamp is outside the tolerance
+func NewStripeVerifier(options ...VerifierOption) Verifier {
+	return &stripeVerifier{opts: newVerifierOptions(options)}
+}
+
+func (v *stripeVerifier) Verify(req *http.Request, body []byte, secret []byte) error {
+	header := req.Header.Get("Stripe-Signature")
+	if header == "" {
+		return ErrSignatureMissing
+	}
+
+	// The header looks like t=1492774577,v1=5257a869...,v1=...; there may be several v1 signatures while a secret is rolled
+	var timestamp string
+	var signatures []string
+	for _, part := range strings.Split(header, ",") {
+		key, value, found := strings.Cut(strings.TrimSpace(part), "=")
+		if !found {
+			continue
+		}
+		switch key {
+		case "t":
+			timestamp = value
+		case "v1":
+			signatures = append(signatures, value)
+		}
+	}
+	if timestamp == "" || len(signatures) == 0 {
+		return ErrSignatureMissing
+	}
+
+	if err := checkTimestamp(timestamp, v.opts); err != nil {
+		return err
+	}
+
+	expected := computeHMAC(secret, []byte(timestamp), []byte("."), body)
+	for _, signature := range signatures {
+		if hmacEqualHex(expected, signature) {
+			return nil
+		}
+	}
+
+	return ErrSignatureInvalid
+}
+
+type hmacVerifier struct {
+	signatureHeader string
+	opts            verifierOptions
+}
+
+// NewHMACVerifier returns a verifier for providers that put a hex-encoded HMAC-SHA256 of the body in
+// signatureHeader. When a timestamp header is configured, the signed payload is "<timestamp>.<body>" and
+// deliveries outside the tolerance are rejected.
+func NewHMACVerifier(signatureHeader string, options ...VerifierOption) Verifier {
+	return &hmacVerifier{signatureHeader: signatureHeader, opts: newVerifierOptions(options)}
+}
+
+func (v *hmacVerifier) Verify(req *http.Request, body []byte, secret []byte) error {
+	signature := req.Header.Get(v.signatureHeader)
+	if signature == "" {
+		return ErrSignatureMissing
+	}
+
+	if v.opts.signaturePrefix != "" {
+		var found bool
+		signature, found = strings.CutPrefix(signature, v.opts.signaturePrefix)
+		if !found {
+			return ErrSignatureInvalid
+		}
+	}
+
+	var expected []byte
+	if v.opts.timestampHeader != "" {
+		timestamp := req.Header.Get(v.opts.timestampHeader)
+		if timestamp == "" {
+			return ErrSignatureMissing
+		}
+		if err := checkTimestamp(timestamp, v.opts); err != nil {
+			return err
+		}
+		expected = computeHMAC(secret, []byte(timestamp), []byte("."), body)
+	} else {
+		expected = computeHMAC(secret, body)
+	}
+
+	if !hmacEqualHex(expected, signature) {
+		return ErrSignatureInvalid
+	}
+
+	return nil
+}
+
+func checkTimestamp(timestamp string, opts verifierOptions) error {
+	seconds, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return fmt.Errorf("%w: timestamp %q is not a Unix time", ErrSignatureInvalid, timestamp)
+	}
+
+	age := opts.clock.Now().Sub(time.Unix(seconds, 0))
+	if age > opts.tolerance || age < -opts.tolerance {
+		return ErrTimestampOutOfTolerance
+	}
+
+	return nil
+}
+
+func computeHMAC(secret []byte, parts ...[]byte) []byte {
+	mac := hmac.New(sha256.New, secret)
+	for _, part := range parts {
+		mac.Write(part)
+	}
+	return mac.Sum(nil)
+}
+
+func hmacEqualHex(expected []byte, signature string) bool {
+	actual, err := hex.DecodeString(signature)
+	if err != nil {
+		return false
+	}
+	return hmac.Equal(expected, actual)
+}
@@ -0,0 +1,217 @@
+package webhook
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/half-ogre/go-kit/kit"
+)
+
+func signTestPayload(secret string, payload string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(payload))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func newTestRequest(body string, headers map[string]string) *http.Request {
+	req := httptest.NewRequest(http.MethodPost, "/webhook", strings.NewReader(body))
+	for key, value := range headers {
+		req.Header.Set(key, value)
+	}
+	return req
+}
+
+func TestReadAndVerify(t *testing.T) {
+	t.Run("restores_the_body_after_verifying", func(t *testing.T) {
+		req := newTestRequest("aBody", map[string]string{"X-Signature": signTestPayload("theSecret", "aBody")})
+
+		err := ReadAndVerify(req, DefaultMaxBodySize, StaticSecret("theSecret"), NewHMACVerifier("X-Signature"))
+
+		assert.NoError(t, err)
+		body, _ := io.ReadAll(req.Body)
+		assert.Equal(t, "aBody", string(body))
+	})
+
+	t.Run("returns_a_verification_error_when_the_verifier_rejects_the_delivery", func(t *testing.T) {
+		req := newTestRequest("aBody", map[string]string{"X-Signature": signTestPayload("aDifferentSecret", "aBody")})
+
+		err := ReadAndVerify(req, DefaultMaxBodySize, StaticSecret("theSecret"), NewHMACVerifier("X-Signature"))
+
+		var verificationErr *VerificationError
+		assert.ErrorAs(t, err, &verificationErr)
+		assert.ErrorIs(t, err, ErrSignatureInvalid)
+	})
+
+	t.Run("returns_a_max_bytes_error_when_the_body_is_too_large", func(t *testing.T) {
+		req := newTestRequest("aBodyThatIsTooLarge", map[string]string{"X-Signature": signTestPayload("theSecret", "aBodyThatIsTooLarge")})
+
+		err := ReadAndVerify(req, 5, StaticSecret("theSecret"), NewHMACVerifier("X-Signature"))
+
+		var maxBytesErr *http.MaxBytesError
+		assert.ErrorAs(t, err, &maxBytesErr)
+		assert.EqualError(t, err, "failed to read webhook body: http: request body too large")
+	})
+
+	t.Run("returns_an_error_when_the_secret_provider_fails", func(t *testing.T) {
+		secretProvider := func(ctx context.Context) ([]byte, error) {
+			return nil, errors.New("the secret error")
+		}
+
+		err := ReadAndVerify(newTestRequest("aBody", nil), DefaultMaxBodySize, secretProvider, NewHMACVerifier("X-Signature"))
+
+		var verificationErr *VerificationError
+		assert.False(t, errors.As(err, &verificationErr))
+		assert.EqualError(t, err, "failed to get webhook secret: the secret error")
+	})
+}
+
+func TestNewHandlerConfig(t *testing.T) {
+	t.Run("defaults_the_max_body_size", func(t *testing.T) {
+		config := NewHandlerConfig(nil)
+
+		assert.Equal(t, int64(DefaultMaxBodySize), config.MaxBodySize)
+	})
+
+	t.Run("sets_the_max_body_size", func(t *testing.T) {
+		config := NewHandlerConfig([]HandlerOption{WithMaxBodySize(42)})
+
+		assert.Equal(t, int64(42), config.MaxBodySize)
+	})
+}
+
+func TestGitHubVerifier(t *testing.T) {
+	t.Run("returns_missing_when_there_is_no_signature_header", func(t *testing.T) {
+		err := NewGitHubVerifier().Verify(newTestRequest("aBody", nil), []byte("aBody"), []byte("theSecret"))
+
+		assert.ErrorIs(t, err, ErrSignatureMissing)
+	})
+
+	t.Run("returns_invalid_when_the_prefix_is_missing", func(t *testing.T) {
+		req := newTestRequest("aBody", map[string]string{
+			"X-Hub-Signature-256": signTestPayload("theSecret", "aBody"),
+		})
+
+		err := NewGitHubVerifier().Verify(req, []byte("aBody"), []byte("theSecret"))
+
+		assert.ErrorIs(t, err, ErrSignatureInvalid)
+	})
+}
+
+func TestStripeVerifier(t *testing.T) {
+	theNow := time.Unix(1700000000, 0)
+	clock := kit.NewClock(kit.WithFake(func() time.Time { return theNow }))
+
+	t.Run("accepts_a_valid_signature_within_tolerance", func(t *testing.T) {
+		timestamp := fmt.Sprint(theNow.Add(-time.Minute).Unix())
+		req := newTestRequest("aBody", map[string]string{
+			"Stripe-Signature": "t=" + timestamp + ",v1=" + signTestPayload("theSecret", timestamp+".aBody"),
+		})
+
+		err := NewStripeVerifier(WithClock(clock)).Verify(req, []byte("aBody"), []byte("theSecret"))
+
+		assert.NoError(t, err)
+	})
+
+	t.Run("accepts_any_matching_v1_signature", func(t *testing.T) {
+		timestamp := fmt.Sprint(theNow.Unix())
+		req := newTestRequest("aBody", map[string]string{
+			"Stripe-Signature": "t=" + timestamp + ",v1=" + signTestPayload("anOldSecret", timestamp+".aBody") +
+				",v1=" + signTestPayload("theSecret", timestamp+".aBody"),
+		})
+
+		err := NewStripeVerifier(WithClock(clock)).Verify(req, []byte("aBody"), []byte("theSecret"))
+
+		assert.NoError(t, err)
+	})
+
+	t.Run("rejects_a_timestamp_outside_tolerance", func(t *testing.T) {
+		timestamp := fmt.Sprint(theNow.Add(-10 * time.Minute).Unix())
+		req := newTestRequest("aBody", map[string]string{
+			"Stripe-Signature": "t=" + timestamp + ",v1=" + signTestPayload("theSecret", timestamp+".aBody"),
+		})
+
+		err := NewStripeVerifier(WithClock(clock)).Verify(req, []byte("aBody"), []byte("theSecret"))
+
+		assert.ErrorIs(t, err, ErrTimestampOutOfTolerance)
+	})
+
+	t.Run("rejects_a_tampered_body", func(t *testing.T) {
+		timestamp := fmt.Sprint(theNow.Unix())
+		req := newTestRequest("aTamperedBody", map[string]string{
+			"Stripe-Signature": "t=" + timestamp + ",v1=" + signTestPayload("theSecret", timestamp+".aBody"),
+		})
+
+		err := NewStripeVerifier(WithClock(clock)).Verify(req, []byte("aTamperedBody"), []byte("theSecret"))
+
+		assert.ErrorIs(t, err, ErrSignatureInvalid)
+	})
+
+	t.Run("returns_missing_when_the_header_has_no_signature", func(t *testing.T) {
+		req := newTestRequest("aBody", map[string]string{"Stripe-Signature": "t=1700000000"})
+
+		err := NewStripeVerifier(WithClock(clock)).Verify(req, []byte("aBody"), []byte("theSecret"))
+
+		assert.ErrorIs(t, err, ErrSignatureMissing)
+	})
+}
+
+func TestHMACVerifier(t *testing.T) {
+	theNow := time.Unix(1700000000, 0)
+	clock := kit.NewClock(kit.WithFake(func() time.Time { return theNow }))
+
+	t.Run("verifies_a_signature_of_the_body", func(t *testing.T) {
+		req := newTestRequest("aBody", map[string]string{
+			"X-Signature": signTestPayload("theSecret", "aBody"),
+		})
+
+		err := NewHMACVerifier("X-Signature").Verify(req, []byte("aBody"), []byte("theSecret"))
+
+		assert.NoError(t, err)
+	})
+
+	t.Run("verifies_a_signature_of_the_timestamp_and_body", func(t *testing.T) {
+		timestamp := fmt.Sprint(theNow.Unix())
+		req := newTestRequest("aBody", map[string]string{
+			"X-Signature": signTestPayload("theSecret", timestamp+".aBody"),
+			"X-Timestamp": timestamp,
+		})
+		verifier := NewHMACVerifier("X-Signature", WithTimestampHeader("X-Timestamp"), WithClock(clock))
+
+		err := verifier.Verify(req, []byte("aBody"), []byte("theSecret"))
+
+		assert.NoError(t, err)
+	})
+
+	t.Run("rejects_a_replayed_delivery", func(t *testing.T) {
+		timestamp := fmt.Sprint(theNow.Add(-time.Hour).Unix())
+		req := newTestRequest("aBody", map[string]string{
+			"X-Signature": signTestPayload("theSecret", timestamp+".aBody"),
+			"X-Timestamp": timestamp,
+		})
+		verifier := NewHMACVerifier("X-Signature", WithTimestampHeader("X-Timestamp"), WithClock(clock))
+
+		err := verifier.Verify(req, []byte("aBody"), []byte("theSecret"))
+
+		assert.ErrorIs(t, err, ErrTimestampOutOfTolerance)
+	})
+
+	t.Run("rejects_a_non_hex_signature", func(t *testing.T) {
+		req := newTestRequest("aBody", map[string]string{"X-Signature": "not hex"})
+
+		err := NewHMACVerifier("X-Signature").Verify(req, []byte("aBody"), []byte("theSecret"))
+
+		assert.ErrorIs(t, err, ErrSignatureInvalid)
+	})
+}
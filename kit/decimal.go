@@ -0,0 +1,266 @@
+package kit
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// MaxDecimalScale bounds the digits after the point of a parsed decimal, and of a product, so
+// untrusted input such as "1e-999999999" cannot allocate gigabytes of digits
+const MaxDecimalScale = 1000
+
+// Decimal is an exact fixed-point decimal number, for amounts that must not pass through float64.
+// The zero value is 0. Decimals are immutable; arithmetic returns new values and never overflows.
+//
+// Decimal encodes as a JSON string ("12.34") and a Postgres numeric, so it can be used in structs
+// handled by the generic JSON and pgx marshallers. dynamodbkit.Decimal stores it as a DynamoDB number.
+type Decimal struct {
+	coef  *big.Int // nil means zero
+	scale int32    // number of digits after the decimal point
+}
+
+// NewDecimal returns coef × 10^-scale, e.g. NewDecimal(1234, 2) is 12.34
+func NewDecimal(coef int64, scale int32) Decimal {
+	if scale < 0 {
+		return Decimal{coef: new(big.Int).Mul(big.NewInt(coef), pow10(-scale))}
+	}
+	return Decimal{coef: big.NewInt(coef), scale: scale}
+}
+
+// ParseDecimal parses a decimal string such as "-12.34" or "1.5e3". It returns an error when the
+// value's scale, positive or negative, is beyond MaxDecimalScale.
+func ParseDecimal(s string) (Decimal, error) {
+	original := s
+	if s == "" {
+		return Decimal{}, fmt.Errorf("invalid decimal %q", original)
+	}
+
+	var exponent int64
+	if i := strings.IndexAny(s, "eE"); i >= 0 {
+		var err error
+		exponent, err = strconv.ParseInt(s[i+1:], 10, 64)
+		if err != nil {
+			return Decimal{}, fmt.Errorf("invalid decimal %q", original)
+		}
+		s = s[:i]
+	}
+
+	sign := ""
+	if s != "" && (s[0] == '-' || s[0] == '+') {
+		sign, s = s[:1], s[1:]
+	}
+
+	intPart, fracPart, _ := strings.Cut(s, ".")
+	digits := intPart + fracPart
+	if digits == "" || strings.IndexFunc(digits, func(r rune) bool { return r < '0' || r > '9' }) >= 0 {
+		return Decimal{}, fmt.Errorf("invalid decimal %q", original)
+	}
+
+	coef, ok := new(big.Int).SetString(sign+digits, 10)
+	if !ok {
+		return Decimal{}, fmt.Errorf("invalid decimal %q", original)
+	}
+
+	scale := int64(len(fracPart)) - exponent
+	if scale > MaxDecimalScale || scale < -MaxDecimalScale {
+		return Decimal{}, fmt.Errorf("decimal %q exceeds the maximum scale of %d", original, MaxDecimalScale)
+	}
+	if scale < 0 {
+		coef.Mul(coef, pow10(int32(-scale)))
+		scale = 0
+	}
+
+	return Decimal{coef: coef, scale: int32(scale)}, nil
+}
+
+// MustParseDecimal is ParseDecimal that panics on error, for constants
+func MustParseDecimal(s string) Decimal {
+	d, err := ParseDecimal(s)
+	if err != nil {
+		panic(err)
+	}
+	return d
+}
+
+func pow10(n int32) *big.Int {
+	return new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(n)), nil)
+}
+
+func (d Decimal) bigCoef() *big.Int {
+	if d.coef == nil {
+		return new(big.Int)
+	}
+	return d.coef
+}
+
+// rescale returns the coefficient of d at a larger scale
+func (d Decimal) rescale(scale int32) *big.Int {
+	if scale == d.scale {
+		return d.bigCoef()
+	}
+	return new(big.Int).Mul(d.bigCoef(), pow10(scale-d.scale))
+}
+
+// Scale returns the number of digits after the decimal point
+func (d Decimal) Scale() int32 {
+	return d.scale
+}
+
+// Add returns d + other
+func (d Decimal) Add(other Decimal) Decimal {
+	scale := max(d.scale, other.scale)
+	return Decimal{coef: new(big.Int).Add(d.rescale(scale), other.rescale(scale)), scale: scale}
+}
+
+// Sub returns d - other
+func (d Decimal) Sub(other Decimal) Decimal {
+	scale := max(d.scale, other.scale)
+	return Decimal{coef: new(big.Int).Sub(d.rescale(scale), other.rescale(scale)), scale: scale}
+}
+
+// Mul returns d × other, exactly unless the product's scale is beyond MaxDecimalScale, in which case
+// it is rounded to MaxDecimalScale; use Round to bring the result back to a currency's scale
+func (d Decimal) Mul(other Decimal) Decimal {
+	coef := new(big.Int).Mul(d.bigCoef(), other.bigCoef())
+	// The sum can overflow int32 when a scale comes from NewDecimal rather than ParseDecimal
+	scale := int64(d.scale) + int64(other.scale)
+	if scale > MaxDecimalScale {
+		return Decimal{coef: roundCoef(coef, scale-MaxDecimalScale), scale: MaxDecimalScale}
+	}
+	return Decimal{coef: coef, scale: int32(scale)}
+}
+
+// Neg returns -d
+func (d Decimal) Neg() Decimal {
+	return Decimal{coef: new(big.Int).Neg(d.bigCoef()), scale: d.scale}
+}
+
+// Round returns d rounded to scale digits after the decimal point, with halves rounded away from zero
+func (d Decimal) Round(scale int32) Decimal {
+	if scale >= d.scale {
+		return Decimal{coef: d.rescale(scale), scale: scale}
+	}
+	return Decimal{coef: roundCoef(d.bigCoef(), int64(d.scale)-int64(scale)), scale: scale}
+}
+
+// roundCoef returns coef ÷ 10^digits, with halves rounded away from zero
+func roundCoef(coef *big.Int, digits int64) *big.Int {
+	// |coef| < 2^BitLen, so it is less than half of 10^digits and rounds to zero without computing
+	// a divisor that could be gigabytes for a huge scale
+	if digits > int64(coef.BitLen()) {
+		return new(big.Int)
+	}
+
+	divisor := new(big.Int).Exp(big.NewInt(10), big.NewInt(digits), nil)
+	quotient, remainder := new(big.Int).QuoRem(coef, divisor, new(big.Int))
+	// Round away from zero when the remainder is at least half the divisor
+	if new(big.Int).Mul(new(big.Int).Abs(remainder), big.NewInt(2)).Cmp(divisor) >= 0 {
+		quotient.Add(quotient, big.NewInt(int64(coef.Sign())))
+	}
+	return quotient
+}
+
+// Cmp compares d and other, returning -1, 0, or +1
+func (d Decimal) Cmp(other Decimal) int {
+	scale := max(d.scale, other.scale)
+	return d.rescale(scale).Cmp(other.rescale(scale))
+}
+
+// Equal reports whether d and other are numerically equal, regardless of scale
+func (d Decimal) Equal(other Decimal) bool {
+	return d.Cmp(other) == 0
+}
+
+// Sign returns -1, 0, or +1 depending on the sign of d
+func (d Decimal) Sign() int {
+	return d.bigCoef().Sign()
+}
+
+// IsZero reports whether d is zero
+func (d Decimal) IsZero() bool {
+	return d.Sign() == 0
+}
+
+// String returns d in plain decimal notation with exactly Scale digits after the point
+func (d Decimal) String() string {
+	digits := new(big.Int).Abs(d.bigCoef()).String()
+	sign := ""
+	if d.Sign() < 0 {
+		sign = "-"
+	}
+	if d.scale == 0 {
+		return sign + digits
+	}
+
+	if len(digits) <= int(d.scale) {
+		digits = strings.Repeat("0", int(d.scale)-len(digits)+1) + digits
+	}
+	point := len(digits) - int(d.scale)
+	return sign + digits[:point] + "." + digits[point:]
+}
+
+// MarshalJSON encodes d as a JSON string so it survives decoders that use float64
+func (d Decimal) MarshalJSON() ([]byte, error) {
+	return json.Marshal(d.String())
+}
+
+// UnmarshalJSON accepts a JSON string or number
+func (d *Decimal) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		return nil
+	}
+	s := strings.Trim(string(data), `"`)
+	parsed, err := ParseDecimal(s)
+	if err != nil {
+		return err
+	}
+	*d = parsed
+	return nil
+}
+
+// MarshalText implements encoding.TextMarshaler
+func (d Decimal) MarshalText() ([]byte, error) {
+	return []byte(d.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler
+func (d *Decimal) UnmarshalText(text []byte) error {
+	parsed, err := ParseDecimal(string(text))
+	if err != nil {
+		return err
+	}
+	*d = parsed
+	return nil
+}
+
+// Value implements driver.Valuer, sending d as text so Postgres stores it in a numeric column exactly
+func (d Decimal) Value() (driver.Value, error) {
+	return d.String(), nil
+}
+
+// Scan implements sql.Scanner for numeric, text, and integer columns
+func (d *Decimal) Scan(src any) error {
+	var s string
+	switch v := src.(type) {
+	case string:
+		s = v
+	case []byte:
+		s = string(v)
+	case int64:
+		*d = NewDecimal(v, 0)
+		return nil
+	default:
+		return fmt.Errorf("cannot scan %T into Decimal", src)
+	}
+
+	parsed, err := ParseDecimal(s)
+	if err != nil {
+		return err
+	}
+	*d = parsed
+	return nil
+}
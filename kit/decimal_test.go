@@ -0,0 +1,183 @@
+package kit
+
+import (
+	"encoding/json"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseDecimal(t *testing.T) {
+	cases := map[string]string{
+		"12.34":   "12.34",
+		"-0.5":    "-0.5",
+		"+7":      "7",
+		"0.001":   "0.001",
+		".5":      "0.5",
+		"1.5e3":   "1500",
+		"1.25E-1": "0.125",
+		"100":     "100",
+	}
+	for input, expected := range cases {
+		t.Run("parses_"+input, func(t *testing.T) {
+			result, err := ParseDecimal(input)
+
+			assert.NoError(t, err)
+			assert.Equal(t, expected, result.String())
+		})
+	}
+
+	for _, input := range []string{"", "abc", "1.2.3", "-", "1e", "1,000"} {
+		t.Run("rejects_"+input, func(t *testing.T) {
+			_, err := ParseDecimal(input)
+
+			assert.Error(t, err)
+		})
+	}
+
+	for _, input := range []string{"1e-2147483648", "1e999999999", "1e-1001", "1e1001", "0." + strings.Repeat("1", 1001)} {
+		t.Run("rejects_a_scale_beyond_the_maximum", func(t *testing.T) {
+			_, err := ParseDecimal(input)
+
+			assert.ErrorContains(t, err, "exceeds the maximum scale of 1000")
+		})
+	}
+
+	t.Run("parses_the_maximum_scale", func(t *testing.T) {
+		result, err := ParseDecimal("1e-1000")
+
+		assert.NoError(t, err)
+		assert.Equal(t, int32(MaxDecimalScale), result.Scale())
+	})
+}
+
+func TestDecimalArithmetic(t *testing.T) {
+	t.Run("adds_without_float_error", func(t *testing.T) {
+		result := MustParseDecimal("0.1").Add(MustParseDecimal("0.2"))
+
+		assert.Equal(t, "0.3", result.String())
+	})
+
+	t.Run("aligns_scales_when_adding", func(t *testing.T) {
+		result := MustParseDecimal("1.5").Add(MustParseDecimal("2.25"))
+
+		assert.Equal(t, "3.75", result.String())
+	})
+
+	t.Run("subtracts", func(t *testing.T) {
+		result := MustParseDecimal("1").Sub(MustParseDecimal("1.01"))
+
+		assert.Equal(t, "-0.01", result.String())
+	})
+
+	t.Run("multiplies_exactly", func(t *testing.T) {
+		result := MustParseDecimal("19.99").Mul(MustParseDecimal("0.0825"))
+
+		assert.Equal(t, "1.649175", result.String())
+	})
+
+	t.Run("does_not_overflow_int64", func(t *testing.T) {
+		big := NewDecimal(9223372036854775807, 0)
+
+		result := big.Add(NewDecimal(1, 0))
+
+		assert.Equal(t, "9223372036854775808", result.String())
+	})
+
+	t.Run("rounds_a_product_beyond_the_maximum_scale", func(t *testing.T) {
+		aSmallDecimal := MustParseDecimal("1e-1000")
+
+		result := aSmallDecimal.Mul(MustParseDecimal("0.5"))
+
+		assert.Equal(t, int32(MaxDecimalScale), result.Scale())
+		assert.True(t, result.Equal(aSmallDecimal))
+	})
+
+	t.Run("does_not_overflow_the_scale_when_multiplying", func(t *testing.T) {
+		aHugeScale := NewDecimal(1, math.MaxInt32)
+
+		result := aHugeScale.Mul(aHugeScale)
+
+		assert.Equal(t, int32(MaxDecimalScale), result.Scale())
+		assert.True(t, result.IsZero())
+	})
+
+	t.Run("rounds_half_away_from_zero", func(t *testing.T) {
+		assert.Equal(t, "1.65", MustParseDecimal("1.645").Round(2).String())
+		assert.Equal(t, "-1.65", MustParseDecimal("-1.645").Round(2).String())
+		assert.Equal(t, "1.64", MustParseDecimal("1.6449").Round(2).String())
+		assert.Equal(t, "2.500", MustParseDecimal("2.5").Round(3).String())
+	})
+
+	t.Run("compares_regardless_of_scale", func(t *testing.T) {
+		assert.True(t, MustParseDecimal("1.50").Equal(MustParseDecimal("1.5")))
+		assert.Equal(t, -1, MustParseDecimal("1.49").Cmp(MustParseDecimal("1.5")))
+		assert.True(t, Decimal{}.IsZero())
+		assert.Equal(t, "0", Decimal{}.String())
+	})
+
+	t.Run("negative_scale_multiplies", func(t *testing.T) {
+		assert.Equal(t, "1200", NewDecimal(12, -2).String())
+	})
+}
+
+func TestDecimalEncoding(t *testing.T) {
+	t.Run("marshals_json_as_a_string", func(t *testing.T) {
+		result, err := json.Marshal(struct {
+			Amount Decimal `json:"amount"`
+		}{Amount: MustParseDecimal("12.30")})
+
+		assert.NoError(t, err)
+		assert.Equal(t, `{"amount":"12.30"}`, string(result))
+	})
+
+	t.Run("unmarshalling_json_with_a_huge_exponent_returns_an_error", func(t *testing.T) {
+		var result Decimal
+
+		err := json.Unmarshal([]byte(`{"amount":1e999999999}`), &struct {
+			Amount *Decimal `json:"amount"`
+		}{Amount: &result})
+
+		assert.ErrorContains(t, err, "exceeds the maximum scale of 1000")
+	})
+
+	t.Run("unmarshals_json_strings_and_numbers", func(t *testing.T) {
+		var result struct {
+			A Decimal `json:"a"`
+			B Decimal `json:"b"`
+		}
+
+		err := json.Unmarshal([]byte(`{"a":"12.34","b":0.1}`), &result)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "12.34", result.A.String())
+		assert.Equal(t, "0.1", result.B.String())
+	})
+
+	t.Run("values_as_text_for_postgres", func(t *testing.T) {
+		result, err := MustParseDecimal("12.34").Value()
+
+		assert.NoError(t, err)
+		assert.Equal(t, "12.34", result)
+	})
+
+	t.Run("scans_numeric_text_and_integers", func(t *testing.T) {
+		var fromBytes, fromInt Decimal
+
+		assert.NoError(t, fromBytes.Scan([]byte("12.34")))
+		assert.NoError(t, fromInt.Scan(int64(42)))
+
+		assert.Equal(t, "12.34", fromBytes.String())
+		assert.Equal(t, "42", fromInt.String())
+	})
+
+	t.Run("scanning_floats_returns_an_error", func(t *testing.T) {
+		var result Decimal
+
+		err := result.Scan(1.5)
+
+		assert.EqualError(t, err, "cannot scan float64 into Decimal")
+	})
+}
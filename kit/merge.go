@@ -0,0 +1,197 @@
+package kit
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+)
+
+// Merge deep-merges src into dst, which must be a pointer to a value of src's type. It is meant for
+// layering config structs: non-zero fields in src overwrite dst, nested structs and pointers to
+// structs are merged field by field, maps are merged key by key, and non-empty slices replace dst's.
+// Zero-valued fields in src leave dst unchanged, so a layer cannot reset a field to its zero value.
+func Merge(dst any, src any) error {
+	dstValue := reflect.ValueOf(dst)
+	if dstValue.Kind() != reflect.Pointer || dstValue.IsNil() {
+		return fmt.Errorf("merge destination must be a non-nil pointer, got %T", dst)
+	}
+
+	srcValue := reflect.ValueOf(src)
+	if !srcValue.IsValid() {
+		return fmt.Errorf("cannot merge %T into %T", src, dst)
+	}
+	if srcValue.Kind() == reflect.Pointer && srcValue.Type() == dstValue.Type() {
+		if srcValue.IsNil() {
+			return nil
+		}
+		srcValue = srcValue.Elem()
+	}
+	if srcValue.Type() != dstValue.Elem().Type() {
+		return fmt.Errorf("cannot merge %T into %T", src, dst)
+	}
+
+	mergeValue(dstValue.Elem(), srcValue)
+	return nil
+}
+
+func mergeValue(dst, src reflect.Value) {
+	switch src.Kind() {
+	case reflect.Struct:
+		if !hasExportedFields(src.Type()) {
+			if !src.IsZero() {
+				dst.Set(src)
+			}
+			return
+		}
+		for i := 0; i < src.NumField(); i++ {
+			if src.Type().Field(i).IsExported() {
+				mergeValue(dst.Field(i), src.Field(i))
+			}
+		}
+	case reflect.Pointer:
+		if src.IsNil() {
+			return
+		}
+		if src.Elem().Kind() == reflect.Struct && hasExportedFields(src.Elem().Type()) {
+			if dst.IsNil() {
+				dst.Set(reflect.New(src.Elem().Type()))
+			}
+			mergeValue(dst.Elem(), src.Elem())
+			return
+		}
+		dst.Set(src)
+	case reflect.Map:
+		if src.Len() == 0 {
+			return
+		}
+		if dst.IsNil() {
+			dst.Set(reflect.MakeMapWithSize(src.Type(), src.Len()))
+		}
+		iter := src.MapRange()
+		for iter.Next() {
+			dst.SetMapIndex(iter.Key(), iter.Value())
+		}
+	case reflect.Slice:
+		if src.Len() > 0 {
+			dst.Set(src)
+		}
+	default:
+		if !src.IsZero() {
+			dst.Set(src)
+		}
+	}
+}
+
+// Change is a single difference found by Diff. Old or New is nil when a map key or slice element
+// was added or removed.
+type Change struct {
+	Path string
+	Old  any
+	New  any
+}
+
+// Diff compares a and b, which must have the same type, and returns the changed fields as paths such as
+// "Address.City", "Tags[env]", or "Items[2].Name", in field order. Only exported fields are compared;
+// structs without exported fields, like time.Time, are compared as a whole.
+func Diff(a any, b any) ([]Change, error) {
+	aValue := reflect.ValueOf(a)
+	bValue := reflect.ValueOf(b)
+	if !aValue.IsValid() || !bValue.IsValid() || aValue.Type() != bValue.Type() {
+		return nil, fmt.Errorf("cannot diff %T and %T", a, b)
+	}
+
+	var changes []Change
+	diffValue("", aValue, bValue, &changes)
+	return changes, nil
+}
+
+func diffValue(path string, a, b reflect.Value, changes *[]Change) {
+	switch a.Kind() {
+	case reflect.Struct:
+		if !hasExportedFields(a.Type()) {
+			break
+		}
+		for i := 0; i < a.NumField(); i++ {
+			field := a.Type().Field(i)
+			if field.IsExported() {
+				diffValue(joinPath(path, field.Name), a.Field(i), b.Field(i), changes)
+			}
+		}
+		return
+	case reflect.Pointer, reflect.Interface:
+		if a.IsNil() || b.IsNil() {
+			break
+		}
+		if a.Kind() == reflect.Interface && a.Elem().Type() != b.Elem().Type() {
+			break
+		}
+		diffValue(path, a.Elem(), b.Elem(), changes)
+		return
+	case reflect.Map:
+		keys := mapKeyUnion(a, b)
+		for _, key := range keys {
+			keyPath := fmt.Sprintf("%s[%v]", path, key.Interface())
+			aElem, bElem := a.MapIndex(key), b.MapIndex(key)
+			switch {
+			case !aElem.IsValid():
+				*changes = append(*changes, Change{Path: keyPath, New: bElem.Interface()})
+			case !bElem.IsValid():
+				*changes = append(*changes, Change{Path: keyPath, Old: aElem.Interface()})
+			default:
+				diffValue(keyPath, aElem, bElem, changes)
+			}
+		}
+		return
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < max(a.Len(), b.Len()); i++ {
+			elemPath := fmt.Sprintf("%s[%d]", path, i)
+			switch {
+			case i >= a.Len():
+				*changes = append(*changes, Change{Path: elemPath, New: b.Index(i).Interface()})
+			case i >= b.Len():
+				*changes = append(*changes, Change{Path: elemPath, Old: a.Index(i).Interface()})
+			default:
+				diffValue(elemPath, a.Index(i), b.Index(i), changes)
+			}
+		}
+		return
+	}
+
+	if !reflect.DeepEqual(a.Interface(), b.Interface()) {
+		*changes = append(*changes, Change{Path: path, Old: a.Interface(), New: b.Interface()})
+	}
+}
+
+func joinPath(path, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
+
+// mapKeyUnion returns the keys of both maps, sorted so changes are reported in a stable order
+func mapKeyUnion(a, b reflect.Value) []reflect.Value {
+	seen := make(map[any]bool)
+	var keys []reflect.Value
+	for _, m := range []reflect.Value{a, b} {
+		for _, key := range m.MapKeys() {
+			if !seen[key.Interface()] {
+				seen[key.Interface()] = true
+				keys = append(keys, key)
+			}
+		}
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		return fmt.Sprint(keys[i].Interface()) < fmt.Sprint(keys[j].Interface())
+	})
+	return keys
+}
+
+func hasExportedFields(t reflect.Type) bool {
+	for i := 0; i < t.NumField(); i++ {
+		if t.Field(i).IsExported() {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,181 @@
+package kit
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type testServerConfig struct {
+	Host    string
+	Port    int
+	Timeout time.Duration
+}
+
+type testConfig struct {
+	Name      string
+	Debug     bool
+	Server    testServerConfig
+	Database  *testServerConfig
+	Tags      map[string]string
+	Origins   []string
+	StartedAt time.Time
+	secret    string
+}
+
+func TestMerge(t *testing.T) {
+	t.Run("overwrites_with_non_zero_fields", func(t *testing.T) {
+		dst := testConfig{Name: "theBase", Server: testServerConfig{Host: "localhost", Port: 8080}}
+		src := testConfig{Server: testServerConfig{Port: 9090}, Debug: true}
+
+		err := Merge(&dst, src)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "theBase", dst.Name)
+		assert.True(t, dst.Debug)
+		assert.Equal(t, testServerConfig{Host: "localhost", Port: 9090}, dst.Server)
+	})
+
+	t.Run("merges_pointers_to_structs", func(t *testing.T) {
+		dst := testConfig{Database: &testServerConfig{Host: "theHost", Port: 5432}}
+		src := &testConfig{Database: &testServerConfig{Timeout: time.Second}}
+
+		err := Merge(&dst, src)
+
+		assert.NoError(t, err)
+		assert.Equal(t, &testServerConfig{Host: "theHost", Port: 5432, Timeout: time.Second}, dst.Database)
+	})
+
+	t.Run("allocates_nil_destination_pointers", func(t *testing.T) {
+		dst := testConfig{}
+		src := testConfig{Database: &testServerConfig{Port: 5432}}
+
+		err := Merge(&dst, src)
+
+		assert.NoError(t, err)
+		assert.Equal(t, &testServerConfig{Port: 5432}, dst.Database)
+		assert.NotSame(t, src.Database, dst.Database)
+	})
+
+	t.Run("merges_maps_by_key", func(t *testing.T) {
+		dst := testConfig{Tags: map[string]string{"env": "dev", "team": "theTeam"}}
+		src := testConfig{Tags: map[string]string{"env": "prod"}}
+
+		err := Merge(&dst, src)
+
+		assert.NoError(t, err)
+		assert.Equal(t, map[string]string{"env": "prod", "team": "theTeam"}, dst.Tags)
+	})
+
+	t.Run("replaces_slices", func(t *testing.T) {
+		dst := testConfig{Origins: []string{"a", "b"}}
+		src := testConfig{Origins: []string{"c"}}
+
+		err := Merge(&dst, src)
+
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"c"}, dst.Origins)
+	})
+
+	t.Run("treats_structs_without_exported_fields_as_values", func(t *testing.T) {
+		theTime := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+		dst := testConfig{}
+
+		err := Merge(&dst, testConfig{StartedAt: theTime})
+
+		assert.NoError(t, err)
+		assert.Equal(t, theTime, dst.StartedAt)
+	})
+
+	t.Run("ignores_unexported_fields", func(t *testing.T) {
+		dst := testConfig{secret: "theSecret"}
+
+		err := Merge(&dst, testConfig{secret: "anotherSecret"})
+
+		assert.NoError(t, err)
+		assert.Equal(t, "theSecret", dst.secret)
+	})
+
+	t.Run("returns_an_error_when_dst_is_not_a_pointer", func(t *testing.T) {
+		err := Merge(testConfig{}, testConfig{})
+
+		assert.EqualError(t, err, "merge destination must be a non-nil pointer, got kit.testConfig")
+	})
+
+	t.Run("returns_an_error_when_types_differ", func(t *testing.T) {
+		err := Merge(&testConfig{}, testServerConfig{})
+
+		assert.EqualError(t, err, "cannot merge kit.testServerConfig into *kit.testConfig")
+	})
+}
+
+func TestDiff(t *testing.T) {
+	t.Run("returns_no_changes_for_equal_values", func(t *testing.T) {
+		a := testConfig{Name: "theName", Tags: map[string]string{"a": "b"}}
+
+		result, err := Diff(a, a)
+
+		assert.NoError(t, err)
+		assert.Empty(t, result)
+	})
+
+	t.Run("reports_changed_fields_by_path", func(t *testing.T) {
+		a := testConfig{Name: "theName", Server: testServerConfig{Host: "aHost", Port: 80}}
+		b := testConfig{Name: "theName", Server: testServerConfig{Host: "anotherHost", Port: 80}, Debug: true}
+
+		result, err := Diff(a, b)
+
+		assert.NoError(t, err)
+		assert.Equal(t, []Change{
+			{Path: "Debug", Old: false, New: true},
+			{Path: "Server.Host", Old: "aHost", New: "anotherHost"},
+		}, result)
+	})
+
+	t.Run("reports_map_keys_added_removed_and_changed", func(t *testing.T) {
+		a := testConfig{Tags: map[string]string{"env": "dev", "old": "x"}}
+		b := testConfig{Tags: map[string]string{"env": "prod", "new": "y"}}
+
+		result, err := Diff(a, b)
+
+		assert.NoError(t, err)
+		assert.Equal(t, []Change{
+			{Path: "Tags[env]", Old: "dev", New: "prod"},
+			{Path: "Tags[new]", New: "y"},
+			{Path: "Tags[old]", Old: "x"},
+		}, result)
+	})
+
+	t.Run("reports_slice_elements", func(t *testing.T) {
+		a := testConfig{Origins: []string{"a", "b"}}
+		b := testConfig{Origins: []string{"a", "c", "d"}}
+
+		result, err := Diff(a, b)
+
+		assert.NoError(t, err)
+		assert.Equal(t, []Change{
+			{Path: "Origins[1]", Old: "b", New: "c"},
+			{Path: "Origins[2]", New: "d"},
+		}, result)
+	})
+
+	t.Run("follows_pointers_and_reports_nil_changes", func(t *testing.T) {
+		theDatabase := &testServerConfig{Port: 5432}
+		a := testConfig{Database: &testServerConfig{Port: 5432}}
+
+		changed, err := Diff(a, testConfig{Database: &testServerConfig{Port: 5433}})
+		assert.NoError(t, err)
+		removed, err := Diff(testConfig{Database: theDatabase}, testConfig{})
+		assert.NoError(t, err)
+
+		assert.Equal(t, []Change{{Path: "Database.Port", Old: 5432, New: 5433}}, changed)
+		assert.Equal(t, []Change{{Path: "Database", Old: theDatabase, New: (*testServerConfig)(nil)}}, removed)
+	})
+
+	t.Run("returns_an_error_when_types_differ", func(t *testing.T) {
+		_, err := Diff(testConfig{}, testServerConfig{})
+
+		assert.EqualError(t, err, "cannot diff kit.testConfig and kit.testServerConfig")
+	})
+}
@@ -0,0 +1,50 @@
+package kit
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrCurrencyMismatch is returned when combining Money values in different currencies
+var ErrCurrencyMismatch = errors.New("currency mismatch")
+
+// Money is a Decimal amount in an ISO 4217 currency
+type Money struct {
+	Amount   Decimal `json:"amount"`
+	Currency string  `json:"currency"`
+}
+
+// NewMoney parses amount as a Decimal in currency
+func NewMoney(amount string, currency string) (Money, error) {
+	d, err := ParseDecimal(amount)
+	if err != nil {
+		return Money{}, err
+	}
+	return Money{Amount: d, Currency: currency}, nil
+}
+
+// Add returns m + other, or ErrCurrencyMismatch if their currencies differ
+func (m Money) Add(other Money) (Money, error) {
+	if m.Currency != other.Currency {
+		return Money{}, fmt.Errorf("%w: cannot add %s to %s", ErrCurrencyMismatch, other.Currency, m.Currency)
+	}
+	return Money{Amount: m.Amount.Add(other.Amount), Currency: m.Currency}, nil
+}
+
+// Sub returns m - other, or ErrCurrencyMismatch if their currencies differ
+func (m Money) Sub(other Money) (Money, error) {
+	if m.Currency != other.Currency {
+		return Money{}, fmt.Errorf("%w: cannot subtract %s from %s", ErrCurrencyMismatch, other.Currency, m.Currency)
+	}
+	return Money{Amount: m.Amount.Sub(other.Amount), Currency: m.Currency}, nil
+}
+
+// Mul returns m multiplied by factor (e.g. a quantity or tax rate), rounded to scale digits
+func (m Money) Mul(factor Decimal, scale int32) Money {
+	return Money{Amount: m.Amount.Mul(factor).Round(scale), Currency: m.Currency}
+}
+
+// String returns the amount followed by the currency, e.g. "12.34 USD"
+func (m Money) String() string {
+	return m.Amount.String() + " " + m.Currency
+}
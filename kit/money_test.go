@@ -0,0 +1,68 @@
+package kit
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMoney(t *testing.T) {
+	t.Run("adds_amounts_in_the_same_currency", func(t *testing.T) {
+		a, _ := NewMoney("10.10", "USD")
+		b, _ := NewMoney("0.95", "USD")
+
+		result, err := a.Add(b)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "11.05 USD", result.String())
+	})
+
+	t.Run("returns_an_error_when_adding_different_currencies", func(t *testing.T) {
+		a, _ := NewMoney("10", "USD")
+		b, _ := NewMoney("10", "EUR")
+
+		_, err := a.Add(b)
+
+		assert.ErrorIs(t, err, ErrCurrencyMismatch)
+		assert.EqualError(t, err, "currency mismatch: cannot add EUR to USD")
+	})
+
+	t.Run("subtracts_amounts_in_the_same_currency", func(t *testing.T) {
+		a, _ := NewMoney("10", "USD")
+		b, _ := NewMoney("0.01", "USD")
+
+		result, err := a.Sub(b)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "9.99 USD", result.String())
+	})
+
+	t.Run("multiplies_and_rounds", func(t *testing.T) {
+		price, _ := NewMoney("19.99", "USD")
+
+		result := price.Mul(MustParseDecimal("0.0825"), 2)
+
+		assert.Equal(t, "1.65 USD", result.String())
+	})
+
+	t.Run("returns_an_error_for_an_invalid_amount", func(t *testing.T) {
+		_, err := NewMoney("ten", "USD")
+
+		assert.EqualError(t, err, `invalid decimal "ten"`)
+	})
+
+	t.Run("round_trips_json", func(t *testing.T) {
+		theMoney, _ := NewMoney("12.34", "USD")
+
+		encoded, err := json.Marshal(theMoney)
+		assert.NoError(t, err)
+		var result Money
+		err = json.Unmarshal(encoded, &result)
+
+		assert.NoError(t, err)
+		assert.Equal(t, `{"amount":"12.34","currency":"USD"}`, string(encoded))
+		assert.True(t, theMoney.Amount.Equal(result.Amount))
+		assert.Equal(t, "USD", result.Currency)
+	})
+}
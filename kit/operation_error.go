@@ -0,0 +1,78 @@
+package kit
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// RedactedErrorValue replaces the values of sensitive fields in error messages
+const RedactedErrorValue = "[REDACTED]"
+
+// ErrorField identifies what an operation was working on, e.g. a table or an item's key
+type ErrorField struct {
+	Key   string
+	Value any
+	// Sensitive fields, e.g. keys holding an email address, show as RedactedErrorValue in the message;
+	// Value still has the real value
+	Sensitive bool
+}
+
+// OperationError is an error from an operation, with fields saying what it was working on, e.g.
+//
+//	dynamodbkit.GetItem table=users id=123: error getting item: operation error DynamoDB: GetItem, ...
+//
+// Use errors.As to get one from an error chain and errors.Is or errors.As to classify its cause.
+type OperationError struct {
+	// Op is the operation, named for its package, e.g. "dynamodbkit.GetItem"
+	Op     string
+	Fields []ErrorField
+	Err    error
+}
+
+// NewOperationError returns err as an *OperationError for op with fields. It returns nil when err is
+// nil, and err as is when it already has an *OperationError in its chain, so an operation built on
+// another keeps the innermost, most specific one.
+func NewOperationError(op string, err error, fields ...ErrorField) error {
+	if err == nil {
+		return nil
+	}
+
+	var operationErr *OperationError
+	if errors.As(err, &operationErr) {
+		return err
+	}
+
+	return &OperationError{Op: op, Fields: fields, Err: err}
+}
+
+func (e *OperationError) Error() string {
+	var b strings.Builder
+	b.WriteString(e.Op)
+	for _, field := range e.Fields {
+		value := any(RedactedErrorValue)
+		if !field.Sensitive {
+			value = field.Value
+		}
+		fmt.Fprintf(&b, " %s=%v", field.Key, value)
+	}
+	if e.Err != nil {
+		b.WriteString(": ")
+		b.WriteString(e.Err.Error())
+	}
+	return b.String()
+}
+
+func (e *OperationError) Unwrap() error {
+	return e.Err
+}
+
+// Field returns the value of the field named key
+func (e *OperationError) Field(key string) (any, bool) {
+	for _, field := range e.Fields {
+		if field.Key == key {
+			return field.Value, true
+		}
+	}
+	return nil, false
+}
@@ -0,0 +1,62 @@
+package kit
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewOperationError(t *testing.T) {
+	t.Run("returns_nil_when_the_error_is_nil", func(t *testing.T) {
+		err := NewOperationError("aPackage.anOperation", nil)
+
+		assert.NoError(t, err)
+	})
+
+	t.Run("formats_the_operation_and_fields_before_the_error", func(t *testing.T) {
+		err := NewOperationError("thePackage.theOperation", errors.New("the error"),
+			ErrorField{Key: "table", Value: "theTable"},
+			ErrorField{Key: "id", Value: 123})
+
+		assert.EqualError(t, err, "thePackage.theOperation table=theTable id=123: the error")
+	})
+
+	t.Run("redacts_sensitive_fields", func(t *testing.T) {
+		err := NewOperationError("aPackage.anOperation", errors.New("the error"),
+			ErrorField{Key: "email", Value: "someone@example.com", Sensitive: true})
+
+		assert.EqualError(t, err, "aPackage.anOperation email=[REDACTED]: the error")
+	})
+
+	t.Run("unwraps_to_the_error", func(t *testing.T) {
+		theError := errors.New("the error")
+
+		err := NewOperationError("aPackage.anOperation", theError)
+
+		assert.ErrorIs(t, err, theError)
+	})
+
+	t.Run("can_be_found_with_errors_as", func(t *testing.T) {
+		err := fmt.Errorf("the context: %w", NewOperationError("thePackage.theOperation", errors.New("an error"), ErrorField{Key: "table", Value: "theTable"}))
+
+		var operationErr *OperationError
+		assert.True(t, errors.As(err, &operationErr))
+		assert.Equal(t, "thePackage.theOperation", operationErr.Op)
+		table, ok := operationErr.Field("table")
+		assert.True(t, ok)
+		assert.Equal(t, "theTable", table)
+		_, ok = operationErr.Field("aMissingField")
+		assert.False(t, ok)
+	})
+
+	t.Run("keeps_an_operation_error_already_in_the_chain", func(t *testing.T) {
+		inner := fmt.Errorf("the context: %w", NewOperationError("thePackage.theInnerOperation", errors.New("the error")))
+
+		err := NewOperationError("aPackage.anOuterOperation", inner)
+
+		assert.Same(t, inner, err)
+		assert.EqualError(t, err, "the context: thePackage.theInnerOperation: the error")
+	})
+}
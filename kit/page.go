@@ -0,0 +1,80 @@
+package kit
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"strings"
+)
+
+// ErrInvalidCursorSignature is returned by VerifyCursor when a cursor wasn't signed with the secret
+var ErrInvalidCursorSignature = errors.New("invalid cursor signature")
+
+// Page is a page of items from a paginated list, with the cursor to pass back for the next page. It's
+// the shape list endpoints return, whichever store the items came from.
+type Page[T any] struct {
+	Items []T `json:"items"`
+	// Cursor is opaque to callers and empty on the last page
+	Cursor  string `json:"cursor,omitempty"`
+	HasMore bool   `json:"has_more"`
+}
+
+// NewPage returns a page of items with the cursor for the next page, or the last page if cursor is nil
+// or empty. Items is never nil, so a page with no items marshals as an empty JSON array.
+func NewPage[T any](items []T, cursor *string) Page[T] {
+	if items == nil {
+		items = []T{}
+	}
+	page := Page[T]{Items: items}
+	if cursor != nil && *cursor != "" {
+		page.Cursor = *cursor
+		page.HasMore = true
+	}
+	return page
+}
+
+// EncodeCursor encodes position, e.g. a DynamoDB LastEvaluatedKey or a keyset key, as an opaque
+// cursor: base64-encoded JSON
+func EncodeCursor(position any) (string, error) {
+	jsonBytes, err := json.Marshal(position)
+	if err != nil {
+		return "", WrapError(err, "failed to marshal cursor")
+	}
+	return base64.StdEncoding.EncodeToString(jsonBytes), nil
+}
+
+// DecodeCursor decodes a cursor from EncodeCursor into position
+func DecodeCursor(cursor string, position any) error {
+	decodedJSON, err := base64.StdEncoding.DecodeString(cursor)
+	if err != nil {
+		return WrapError(err, "failed to decode cursor %s", cursor)
+	}
+	if err := json.Unmarshal(decodedJSON, position); err != nil {
+		return WrapError(err, "failed to unmarshal cursor JSON %s", decodedJSON)
+	}
+	return nil
+}
+
+// SignCursor appends an HMAC-SHA256 signature of cursor made with secret, so a client can't hand back
+// a cursor it made up or edited, e.g. to page into another tenant's partition
+func SignCursor(cursor string, secret []byte) string {
+	return cursor + "." + cursorSignature(cursor, secret)
+}
+
+// VerifyCursor returns the cursor signed by SignCursor, or ErrInvalidCursorSignature if signed wasn't
+// signed with secret
+func VerifyCursor(signed string, secret []byte) (string, error) {
+	cursor, signature, ok := strings.Cut(signed, ".")
+	if !ok || !hmac.Equal([]byte(signature), []byte(cursorSignature(cursor, secret))) {
+		return "", ErrInvalidCursorSignature
+	}
+	return cursor, nil
+}
+
+func cursorSignature(cursor string, secret []byte) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(cursor))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
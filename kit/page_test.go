@@ -0,0 +1,102 @@
+package kit
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewPage(t *testing.T) {
+	t.Run("has_more_with_a_cursor", func(t *testing.T) {
+		cursor := "theCursor"
+
+		page := NewPage([]string{"anItem"}, &cursor)
+
+		assert.Equal(t, Page[string]{Items: []string{"anItem"}, Cursor: "theCursor", HasMore: true}, page)
+	})
+
+	t.Run("is_the_last_page_without_a_cursor", func(t *testing.T) {
+		emptyCursor := ""
+
+		assert.False(t, NewPage([]string{"anItem"}, nil).HasMore)
+		assert.False(t, NewPage([]string{"anItem"}, &emptyCursor).HasMore)
+	})
+
+	t.Run("marshals_nil_items_as_an_empty_array", func(t *testing.T) {
+		page := NewPage[string](nil, nil)
+
+		jsonBytes, err := json.Marshal(page)
+
+		assert.NoError(t, err)
+		assert.JSONEq(t, `{"items":[],"has_more":false}`, string(jsonBytes))
+	})
+}
+
+func TestCursor(t *testing.T) {
+	t.Run("round_trips_a_position", func(t *testing.T) {
+		cursor, err := EncodeCursor(map[string]any{"id": "theID"})
+		assert.NoError(t, err)
+
+		var position map[string]any
+		err = DecodeCursor(cursor, &position)
+
+		assert.NoError(t, err)
+		assert.Equal(t, map[string]any{"id": "theID"}, position)
+	})
+
+	t.Run("returns_an_error_for_a_cursor_that_is_not_base64", func(t *testing.T) {
+		var position any
+
+		err := DecodeCursor("not base64!", &position)
+
+		assert.ErrorContains(t, err, "failed to decode cursor not base64!")
+	})
+
+	t.Run("returns_an_error_for_a_cursor_that_is_not_json", func(t *testing.T) {
+		var position any
+
+		err := DecodeCursor("bm90IGpzb24=", &position)
+
+		assert.ErrorContains(t, err, "failed to unmarshal cursor JSON not json")
+	})
+
+	t.Run("returns_an_error_for_a_position_that_cannot_be_marshaled", func(t *testing.T) {
+		_, err := EncodeCursor(make(chan int))
+
+		assert.ErrorContains(t, err, "failed to marshal cursor")
+	})
+}
+
+func TestSignCursor(t *testing.T) {
+	t.Run("verifies_a_cursor_signed_with_the_secret", func(t *testing.T) {
+		signed := SignCursor("theCursor", []byte("theSecret"))
+
+		cursor, err := VerifyCursor(signed, []byte("theSecret"))
+
+		assert.NoError(t, err)
+		assert.Equal(t, "theCursor", cursor)
+	})
+
+	t.Run("rejects_a_cursor_signed_with_another_secret", func(t *testing.T) {
+		signed := SignCursor("theCursor", []byte("aDifferentSecret"))
+
+		_, err := VerifyCursor(signed, []byte("theSecret"))
+
+		assert.ErrorIs(t, err, ErrInvalidCursorSignature)
+	})
+
+	t.Run("rejects_an_edited_cursor", func(t *testing.T) {
+		signed := SignCursor("theCursor", []byte("theSecret"))
+
+		_, err := VerifyCursor("anEditedCursor"+signed[len("theCursor"):], []byte("theSecret"))
+
+		assert.ErrorIs(t, err, ErrInvalidCursorSignature)
+	})
+
+	t.Run("rejects_an_unsigned_cursor", func(t *testing.T) {
+		_, err := VerifyCursor("theCursor", []byte("theSecret"))
+
+		assert.ErrorIs(t, err, ErrInvalidCursorSignature)
+	})
+}
@@ -0,0 +1,61 @@
+package kit
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"fmt"
+)
+
+// tokenAlphabet is URL, file name, and case-insensitive-safe, like the alphabet of a ULID
+const tokenAlphabet = "0123456789abcdefghijklmnopqrstuvwxyz"
+
+// RandomToken returns n characters of lowercase letters and digits from crypto/rand, for IDs and tokens
+// that must survive URLs, headers, and case-insensitive storage
+func RandomToken(n int) (string, error) {
+	if n < 0 {
+		return "", fmt.Errorf("token length cannot be negative")
+	}
+
+	token := make([]byte, 0, n)
+	buf := make([]byte, n)
+	// Reject bytes past the largest multiple of the alphabet size so every character is equally likely
+	maxByte := byte(256 - 256%len(tokenAlphabet))
+	for len(token) < n {
+		if _, err := rand.Read(buf); err != nil {
+			return "", WrapError(err, "failed to read random bytes")
+		}
+		for _, b := range buf {
+			if b >= maxByte {
+				continue
+			}
+			token = append(token, tokenAlphabet[int(b)%len(tokenAlphabet)])
+			if len(token) == n {
+				break
+			}
+		}
+	}
+
+	return string(token), nil
+}
+
+// RandomBase64URL returns n bytes from crypto/rand encoded as unpadded URL-safe base64, for CSRF
+// tokens, OAuth state, and nonces
+func RandomBase64URL(n int) (string, error) {
+	if n < 0 {
+		return "", fmt.Errorf("byte count cannot be negative")
+	}
+
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return "", WrapError(err, "failed to read random bytes")
+	}
+
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// ConstantTimeEqual reports whether a and b are equal without leaking where they differ through timing,
+// for comparing secrets such as tokens and state
+func ConstantTimeEqual(a string, b string) bool {
+	return subtle.ConstantTimeCompare([]byte(a), []byte(b)) == 1
+}
@@ -0,0 +1,69 @@
+package kit
+
+import (
+	"encoding/base64"
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRandomToken(t *testing.T) {
+	t.Run("returns_n_lowercase_letters_and_digits", func(t *testing.T) {
+		result, err := RandomToken(40)
+
+		assert.NoError(t, err)
+		assert.Regexp(t, regexp.MustCompile(`^[0-9a-z]{40}$`), result)
+	})
+
+	t.Run("returns_different_tokens", func(t *testing.T) {
+		first, err := RandomToken(32)
+		assert.NoError(t, err)
+		second, err := RandomToken(32)
+		assert.NoError(t, err)
+
+		assert.NotEqual(t, first, second)
+	})
+
+	t.Run("returns_an_empty_token_for_zero", func(t *testing.T) {
+		result, err := RandomToken(0)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "", result)
+	})
+
+	t.Run("returns_an_error_for_a_negative_length", func(t *testing.T) {
+		_, err := RandomToken(-1)
+
+		assert.EqualError(t, err, "token length cannot be negative")
+	})
+}
+
+func TestRandomBase64URL(t *testing.T) {
+	t.Run("encodes_n_random_bytes_as_url_safe_base64", func(t *testing.T) {
+		result, err := RandomBase64URL(32)
+
+		assert.NoError(t, err)
+		assert.Regexp(t, regexp.MustCompile(`^[A-Za-z0-9_-]+$`), result)
+		decoded, err := base64.RawURLEncoding.DecodeString(result)
+		assert.NoError(t, err)
+		assert.Len(t, decoded, 32)
+	})
+
+	t.Run("returns_an_error_for_a_negative_count", func(t *testing.T) {
+		_, err := RandomBase64URL(-1)
+
+		assert.EqualError(t, err, "byte count cannot be negative")
+	})
+}
+
+func TestConstantTimeEqual(t *testing.T) {
+	t.Run("returns_true_for_equal_strings", func(t *testing.T) {
+		assert.True(t, ConstantTimeEqual("theToken", "theToken"))
+	})
+
+	t.Run("returns_false_for_different_strings", func(t *testing.T) {
+		assert.False(t, ConstantTimeEqual("theToken", "theOtherToken"))
+		assert.False(t, ConstantTimeEqual("theToken", ""))
+	})
+}
@@ -0,0 +1,80 @@
+package kit
+
+import (
+	"context"
+	"time"
+
+	"github.com/half-ogre/go-kit/backoffkit"
+)
+
+// RetryOption configures Retry
+type RetryOption func(*retryConfig)
+
+type retryConfig struct {
+	maxAttempts int
+	backoff     backoffkit.Strategy
+	retryable   func(error) bool
+}
+
+// WithRetryAttempts sets how many times Retry calls fn before returning its error, including the first
+// call; the default is 3
+func WithRetryAttempts(maxAttempts int) RetryOption {
+	return func(c *retryConfig) {
+		c.maxAttempts = maxAttempts
+	}
+}
+
+// WithRetryBackoff sets how long Retry waits between calls; the default is exponential backoff with
+// full jitter from 100ms up to 5s
+func WithRetryBackoff(strategy backoffkit.Strategy) RetryOption {
+	return func(c *retryConfig) {
+		c.backoff = strategy
+	}
+}
+
+// WithRetryIf sets which errors Retry retries; the default is every error
+func WithRetryIf(retryable func(err error) bool) RetryOption {
+	return func(c *retryConfig) {
+		c.retryable = retryable
+	}
+}
+
+// Retry calls fn until it succeeds, returns an error that isn't retryable, or has been called the
+// maximum number of times, waiting between calls with backoff, and returns its last error. It returns
+// ctx's error if ctx is done while waiting.
+func Retry(ctx context.Context, fn func(ctx context.Context) error, options ...RetryOption) error {
+	_, err := RetryValue(ctx, func(ctx context.Context) (struct{}, error) {
+		return struct{}{}, fn(ctx)
+	}, options...)
+	return err
+}
+
+// RetryValue is Retry for a function returning a value, which is returned when it succeeds
+func RetryValue[T any](ctx context.Context, fn func(ctx context.Context) (T, error), options ...RetryOption) (T, error) {
+	config := retryConfig{
+		maxAttempts: 3,
+		backoff:     backoffkit.FullJitter(backoffkit.Exponential(100*time.Millisecond, 5*time.Second)),
+		retryable:   func(error) bool { return true },
+	}
+	for _, option := range options {
+		option(&config)
+	}
+
+	var value T
+	var err error
+	for attempt := range backoffkit.Attempts(ctx, config.backoff, max(config.maxAttempts, 1)) {
+		value, err = fn(ctx)
+		if err == nil || !config.retryable(err) {
+			return value, err
+		}
+		if attempt >= config.maxAttempts {
+			if attempt > 1 {
+				err = WrapError(err, "failed after %d attempts", attempt)
+			}
+			return value, err
+		}
+	}
+
+	var zero T
+	return zero, ctx.Err()
+}
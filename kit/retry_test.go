@@ -0,0 +1,85 @@
+package kit
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/half-ogre/go-kit/backoffkit"
+)
+
+func TestRetry(t *testing.T) {
+	noWait := WithRetryBackoff(backoffkit.Constant(0))
+
+	t.Run("returns_nil_once_fn_succeeds", func(t *testing.T) {
+		calls := 0
+
+		err := Retry(context.Background(), func(ctx context.Context) error {
+			calls++
+			if calls < 3 {
+				return errors.New("the error")
+			}
+			return nil
+		}, noWait)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 3, calls)
+	})
+
+	t.Run("returns_the_last_error_after_the_most_attempts", func(t *testing.T) {
+		calls := 0
+
+		err := Retry(context.Background(), func(ctx context.Context) error {
+			calls++
+			return errors.New("the error")
+		}, noWait, WithRetryAttempts(5))
+
+		assert.EqualError(t, err, "failed after 5 attempts: the error")
+		assert.Equal(t, 5, calls)
+	})
+
+	t.Run("returns_an_error_that_isnt_retryable_at_once", func(t *testing.T) {
+		theErr := errors.New("the permanent error")
+		calls := 0
+
+		err := Retry(context.Background(), func(ctx context.Context) error {
+			calls++
+			return theErr
+		}, noWait, WithRetryIf(func(err error) bool { return !errors.Is(err, theErr) }))
+
+		assert.Same(t, theErr, err)
+		assert.Equal(t, 1, calls)
+	})
+
+	t.Run("returns_the_context_error_when_done_while_waiting", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		err := Retry(ctx, func(ctx context.Context) error {
+			cancel()
+			return errors.New("the error")
+		}, WithRetryBackoff(backoffkit.Constant(time.Hour)))
+
+		assert.ErrorIs(t, err, context.Canceled)
+	})
+}
+
+func TestRetryValue(t *testing.T) {
+	t.Run("returns_the_value_once_fn_succeeds", func(t *testing.T) {
+		calls := 0
+
+		value, err := RetryValue(context.Background(), func(ctx context.Context) (string, error) {
+			calls++
+			if calls == 1 {
+				return "", errors.New("the error")
+			}
+			return "theValue", nil
+		}, WithRetryBackoff(backoffkit.Constant(0)))
+
+		assert.NoError(t, err)
+		assert.Equal(t, "theValue", value)
+	})
+}
@@ -0,0 +1,121 @@
+package kit
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+// ErrSingleflightPanicked is returned to callers waiting on a call that panicked; the panic itself
+// goes on in the caller that made the call
+var ErrSingleflightPanicked = errors.New("singleflight call panicked")
+
+type singleflightCall[T any] struct {
+	done    chan struct{}
+	value   T
+	err     error
+	waiters int
+}
+
+// Singleflight makes sure only one call for a key is in flight at a time: callers that ask for a key
+// while it's being loaded wait and get the same result. The zero value is ready to use.
+type Singleflight[T any] struct {
+	mu    sync.Mutex
+	calls map[string]*singleflightCall[T]
+}
+
+// Do calls fn and returns its result, unless a call for key is already in flight, in which case it waits
+// for that call and returns its result instead. shared reports whether the result went to more than one
+// caller, for the caller that made the call as well as those that waited.
+func (s *Singleflight[T]) Do(key string, fn func() (T, error)) (value T, err error, shared bool) {
+	s.mu.Lock()
+	if s.calls == nil {
+		s.calls = map[string]*singleflightCall[T]{}
+	}
+	if call, ok := s.calls[key]; ok {
+		call.waiters++
+		s.mu.Unlock()
+		<-call.done
+		return call.value, call.err, true
+	}
+	call := &singleflightCall[T]{done: make(chan struct{}), err: ErrSingleflightPanicked}
+	s.calls[key] = call
+	s.mu.Unlock()
+
+	// shared is set once the call is out of the map, when no more waiters can join
+	defer func() {
+		s.mu.Lock()
+		delete(s.calls, key)
+		shared = call.waiters > 0
+		s.mu.Unlock()
+		close(call.done)
+	}()
+
+	call.value, call.err = fn()
+	return call.value, call.err, shared
+}
+
+type memoEntry[T any] struct {
+	value     T
+	expiresAt time.Time
+}
+
+// Memo remembers loaded values for a TTL, loading each key once at a time so an expired value doesn't
+// send every caller to the source at once. Errors aren't remembered. Values stay until they're replaced
+// or forgotten, so it's meant for a bounded set of keys, e.g. secret names or JWKS URLs.
+type Memo[T any] struct {
+	ttl     time.Duration
+	clock   ClockInterface
+	flight  Singleflight[T]
+	mu      sync.Mutex
+	entries map[string]memoEntry[T]
+}
+
+type MemoOption[T any] func(*Memo[T])
+
+// WithMemoClock sets the clock used to expire values
+func WithMemoClock[T any](clock ClockInterface) MemoOption[T] {
+	return func(m *Memo[T]) {
+		m.clock = clock
+	}
+}
+
+// NewMemo returns a Memo that remembers values for ttl
+func NewMemo[T any](ttl time.Duration, options ...MemoOption[T]) *Memo[T] {
+	m := &Memo[T]{ttl: ttl, clock: NewClock(), entries: map[string]memoEntry[T]{}}
+	for _, option := range options {
+		option(m)
+	}
+	return m
+}
+
+// Get returns the remembered value for key, or calls load to get it when there isn't one or it's
+// expired
+func (m *Memo[T]) Get(key string, load func() (T, error)) (T, error) {
+	m.mu.Lock()
+	entry, ok := m.entries[key]
+	m.mu.Unlock()
+	if ok && m.clock.Now().Before(entry.expiresAt) {
+		return entry.value, nil
+	}
+
+	value, err, _ := m.flight.Do(key, func() (T, error) {
+		value, err := load()
+		if err != nil {
+			return value, err
+		}
+
+		m.mu.Lock()
+		m.entries[key] = memoEntry[T]{value: value, expiresAt: m.clock.Now().Add(m.ttl)}
+		m.mu.Unlock()
+		return value, nil
+	})
+	return value, err
+}
+
+// Forget drops the remembered value for key so the next Get loads it again
+func (m *Memo[T]) Forget(key string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.entries, key)
+}
@@ -0,0 +1,165 @@
+package kit
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// waitForWaiters blocks until n callers are waiting on the call for key
+func waitForWaiters[T any](t *testing.T, s *Singleflight[T], key string, n int) {
+	assert.Eventually(t, func() bool {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		call, ok := s.calls[key]
+		return ok && call.waiters == n
+	}, time.Second, time.Millisecond)
+}
+
+func TestSingleflight(t *testing.T) {
+	t.Run("returns_the_result_of_the_call", func(t *testing.T) {
+		var s Singleflight[string]
+
+		value, err, shared := s.Do("aKey", func() (string, error) { return "theValue", nil })
+
+		assert.NoError(t, err)
+		assert.Equal(t, "theValue", value)
+		assert.False(t, shared)
+	})
+
+	t.Run("returns_the_error_of_the_call", func(t *testing.T) {
+		var s Singleflight[string]
+
+		_, err, _ := s.Do("aKey", func() (string, error) { return "", errors.New("the error") })
+
+		assert.EqualError(t, err, "the error")
+	})
+
+	t.Run("shares_one_call_between_concurrent_callers_of_a_key", func(t *testing.T) {
+		var s Singleflight[int]
+		var calls atomic.Int32
+		release := make(chan struct{})
+		started := make(chan struct{})
+
+		leaderShared := make(chan bool)
+		go func() {
+			_, _, shared := s.Do("aKey", func() (int, error) {
+				close(started)
+				<-release
+				return 0, nil
+			})
+			leaderShared <- shared
+		}()
+		<-started
+
+		var wg sync.WaitGroup
+		values := make([]int, 5)
+		shares := make([]bool, 5)
+		for i := range values {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				values[i], _, shares[i] = s.Do("aKey", func() (int, error) {
+					calls.Add(1)
+					return 1, nil
+				})
+			}()
+		}
+		waitForWaiters(t, &s, "aKey", 5)
+		close(release)
+		wg.Wait()
+
+		assert.Equal(t, int32(0), calls.Load())
+		assert.Equal(t, []int{0, 0, 0, 0, 0}, values)
+		assert.Equal(t, []bool{true, true, true, true, true}, shares)
+		assert.True(t, <-leaderShared)
+	})
+
+	t.Run("calls_again_once_the_call_is_done", func(t *testing.T) {
+		var s Singleflight[int]
+		calls := 0
+
+		s.Do("aKey", func() (int, error) { calls++; return calls, nil })
+		value, _, _ := s.Do("aKey", func() (int, error) { calls++; return calls, nil })
+
+		assert.Equal(t, 2, value)
+	})
+
+	t.Run("returns_an_error_to_waiters_when_the_call_panics", func(t *testing.T) {
+		var s Singleflight[int]
+		started := make(chan struct{})
+		release := make(chan struct{})
+		panicked := make(chan any)
+
+		go func() {
+			defer func() { panicked <- recover() }()
+			s.Do("aKey", func() (int, error) {
+				close(started)
+				<-release
+				panic("the panic")
+			})
+		}()
+		<-started
+
+		errs := make(chan error)
+		go func() {
+			_, err, _ := s.Do("aKey", func() (int, error) { return 1, nil })
+			errs <- err
+		}()
+		waitForWaiters(t, &s, "aKey", 1)
+		close(release)
+
+		assert.Equal(t, "the panic", <-panicked)
+		assert.ErrorIs(t, <-errs, ErrSingleflightPanicked)
+	})
+}
+
+func TestMemo(t *testing.T) {
+	t.Run("remembers_the_value_until_it_expires", func(t *testing.T) {
+		now := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+		m := NewMemo(time.Minute, WithMemoClock[int](NewClock(WithFake(func() time.Time { return now }))))
+		loads := 0
+		load := func() (int, error) { loads++; return loads, nil }
+
+		first, _ := m.Get("aKey", load)
+		now = now.Add(59 * time.Second)
+		second, _ := m.Get("aKey", load)
+		now = now.Add(time.Second)
+		third, _ := m.Get("aKey", load)
+
+		assert.Equal(t, []int{1, 1, 2}, []int{first, second, third})
+	})
+
+	t.Run("does_not_remember_errors", func(t *testing.T) {
+		m := NewMemo[int](time.Minute)
+
+		_, err := m.Get("aKey", func() (int, error) { return 0, errors.New("the error") })
+		value, _ := m.Get("aKey", func() (int, error) { return 42, nil })
+
+		assert.EqualError(t, err, "the error")
+		assert.Equal(t, 42, value)
+	})
+
+	t.Run("keeps_keys_apart", func(t *testing.T) {
+		m := NewMemo[string](time.Minute)
+
+		m.Get("aKey", func() (string, error) { return "theFirstValue", nil })
+		value, _ := m.Get("anotherKey", func() (string, error) { return "theSecondValue", nil })
+
+		assert.Equal(t, "theSecondValue", value)
+	})
+
+	t.Run("loads_again_after_the_key_is_forgotten", func(t *testing.T) {
+		m := NewMemo[string](time.Minute)
+
+		m.Get("aKey", func() (string, error) { return "theOldValue", nil })
+		m.Forget("aKey")
+		value, _ := m.Get("aKey", func() (string, error) { return "theNewValue", nil })
+
+		assert.Equal(t, "theNewValue", value)
+	})
+}
@@ -0,0 +1,92 @@
+package kit
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/half-ogre/go-kit/versionkit"
+)
+
+// SinceVersion reports whether the build is at least version, for gating behavior behind a deploy
+// during a migration, e.g. SinceVersion(versionkit.GetBuildInfo(), "1.4.0"). Builds without a version,
+// such as local dev builds, are treated as newer than any release.
+func SinceVersion(buildInfo *versionkit.BuildInfo, version string) (bool, error) {
+	return VersionSatisfies(buildInfo, ">="+version)
+}
+
+// BeforeVersion reports whether the build is older than version; it is the inverse of SinceVersion
+func BeforeVersion(buildInfo *versionkit.BuildInfo, version string) (bool, error) {
+	since, err := SinceVersion(buildInfo, version)
+	return !since && err == nil, err
+}
+
+// VersionSatisfies reports whether the build's version meets every comma-separated constraint, e.g.
+// ">=1.4.0, <2.0.0". The operators are =, !=, >, >=, <, and <=; a version without one must be equal.
+// Builds without a version satisfy any constraint.
+func VersionSatisfies(buildInfo *versionkit.BuildInfo, constraints string) (bool, error) {
+	type constraint struct {
+		op      string
+		version *versionkit.SemanticVersion
+	}
+
+	parsed := []constraint{}
+	for _, raw := range strings.Split(constraints, ",") {
+		raw = strings.TrimSpace(raw)
+		op := ""
+		for _, candidate := range []string{">=", "<=", "!=", ">", "<", "="} {
+			if strings.HasPrefix(raw, candidate) {
+				op = candidate
+				break
+			}
+		}
+		version, err := parseVersion(strings.TrimSpace(strings.TrimPrefix(raw, op)))
+		if err != nil {
+			return false, WrapError(err, "invalid version constraint %q", raw)
+		}
+		if op == "" {
+			op = "="
+		}
+		parsed = append(parsed, constraint{op: op, version: version})
+	}
+
+	if buildInfo == nil || buildInfo.Version == "" {
+		return true, nil
+	}
+
+	buildVersion, err := parseVersion(buildInfo.Version)
+	if err != nil {
+		return false, WrapError(err, "invalid build version %q", buildInfo.Version)
+	}
+
+	for _, c := range parsed {
+		comparison := buildVersion.Compare(*c.version)
+		var ok bool
+		switch c.op {
+		case "=":
+			ok = comparison == 0
+		case "!=":
+			ok = comparison != 0
+		case ">":
+			ok = comparison > 0
+		case ">=":
+			ok = comparison >= 0
+		case "<":
+			ok = comparison < 0
+		case "<=":
+			ok = comparison <= 0
+		}
+		if !ok {
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
+
+// parseVersion parses a semantic version, allowing the v prefix Go module versions have
+func parseVersion(version string) (*versionkit.SemanticVersion, error) {
+	if version == "" {
+		return nil, fmt.Errorf("version is empty")
+	}
+	return versionkit.ParseSemanticVersion(strings.TrimPrefix(version, "v"))
+}
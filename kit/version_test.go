@@ -0,0 +1,100 @@
+package kit
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/half-ogre/go-kit/versionkit"
+)
+
+func TestSinceVersion(t *testing.T) {
+	t.Run("returns_true_for_the_same_version", func(t *testing.T) {
+		result, err := SinceVersion(&versionkit.BuildInfo{Version: "1.4.0"}, "1.4.0")
+
+		assert.NoError(t, err)
+		assert.True(t, result)
+	})
+
+	t.Run("returns_true_for_a_later_go_module_version", func(t *testing.T) {
+		result, err := SinceVersion(&versionkit.BuildInfo{Version: "v1.10.2"}, "1.4.0")
+
+		assert.NoError(t, err)
+		assert.True(t, result)
+	})
+
+	t.Run("returns_false_for_an_earlier_version", func(t *testing.T) {
+		result, err := SinceVersion(&versionkit.BuildInfo{Version: "1.3.9"}, "1.4.0")
+
+		assert.NoError(t, err)
+		assert.False(t, result)
+	})
+
+	t.Run("returns_false_for_a_prerelease_of_the_version", func(t *testing.T) {
+		result, err := SinceVersion(&versionkit.BuildInfo{Version: "1.4.0-rc.1"}, "1.4.0")
+
+		assert.NoError(t, err)
+		assert.False(t, result)
+	})
+
+	t.Run("returns_true_for_a_build_without_a_version", func(t *testing.T) {
+		result, err := SinceVersion(&versionkit.BuildInfo{}, "1.4.0")
+
+		assert.NoError(t, err)
+		assert.True(t, result)
+	})
+
+	t.Run("returns_an_error_for_an_invalid_version", func(t *testing.T) {
+		_, err := SinceVersion(&versionkit.BuildInfo{Version: "1.4.0"}, "1.4")
+
+		assert.ErrorContains(t, err, `invalid version constraint ">=1.4"`)
+	})
+
+	t.Run("returns_an_error_for_an_invalid_build_version", func(t *testing.T) {
+		_, err := SinceVersion(&versionkit.BuildInfo{Version: "theVersion"}, "1.4.0")
+
+		assert.ErrorContains(t, err, `invalid build version "theVersion"`)
+	})
+}
+
+func TestBeforeVersion(t *testing.T) {
+	t.Run("returns_true_for_an_earlier_version", func(t *testing.T) {
+		result, err := BeforeVersion(&versionkit.BuildInfo{Version: "1.3.9"}, "1.4.0")
+
+		assert.NoError(t, err)
+		assert.True(t, result)
+	})
+
+	t.Run("returns_false_on_error", func(t *testing.T) {
+		result, err := BeforeVersion(&versionkit.BuildInfo{Version: "1.3.9"}, "")
+
+		assert.Error(t, err)
+		assert.False(t, result)
+	})
+}
+
+func TestVersionSatisfies(t *testing.T) {
+	t.Run("checks_every_constraint", func(t *testing.T) {
+		buildInfo := &versionkit.BuildInfo{Version: "1.5.0"}
+
+		inRange, err := VersionSatisfies(buildInfo, ">=1.4.0, <2.0.0")
+		assert.NoError(t, err)
+		assert.True(t, inRange)
+
+		excluded, err := VersionSatisfies(buildInfo, ">1.0.0, !=1.5.0")
+		assert.NoError(t, err)
+		assert.False(t, excluded)
+	})
+
+	t.Run("requires_equality_without_an_operator", func(t *testing.T) {
+		buildInfo := &versionkit.BuildInfo{Version: "1.5.0+theBuild"}
+
+		equal, err := VersionSatisfies(buildInfo, "1.5.0")
+		assert.NoError(t, err)
+		assert.True(t, equal)
+
+		notEqual, err := VersionSatisfies(buildInfo, "<=1.4.9")
+		assert.NoError(t, err)
+		assert.False(t, notEqual)
+	})
+}
@@ -0,0 +1,201 @@
+package kmskit
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/binary"
+	"fmt"
+	"slices"
+
+	"github.com/half-ogre/go-kit/kit"
+)
+
+// envelopeVersion is the first byte of every envelope, so the format can change
+const envelopeVersion byte = 1
+
+// EncrypterOption configures NewEncrypter
+type EncrypterOption func(*Encrypter)
+
+// WithPreviousKeyIDs sets keys that Decrypt still accepts after the encrypter's key was rotated to a
+// new one, so data encrypted under them stays readable until it is re-encrypted with Rotate
+func WithPreviousKeyIDs(keyIDs ...string) EncrypterOption {
+	return func(e *Encrypter) {
+		e.previousKeyIDs = append(e.previousKeyIDs, keyIDs...)
+	}
+}
+
+// Encrypter encrypts data under one key of a KMS and decrypts data encrypted under it or its previous
+// keys. Each Encrypt generates a new data key, so it makes one KMS call; each Decrypt makes one too.
+type Encrypter struct {
+	kms            KMS
+	keyID          string
+	previousKeyIDs []string
+}
+
+// NewEncrypter returns an encrypter encrypting under keyID with kms. Envelopes store keyID as given,
+// e.g. a key ARN or an alias, and Decrypt passes it back to kms.
+func NewEncrypter(kms KMS, keyID string, options ...EncrypterOption) *Encrypter {
+	e := &Encrypter{kms: kms, keyID: keyID}
+	for _, option := range options {
+		option(e)
+	}
+	return e
+}
+
+// Encrypt encrypts plaintext under a new data key and returns the envelope holding both.
+// associatedData, e.g. the ID of the record the data belongs to, isn't stored but must be passed to
+// Decrypt, so an envelope copied to another record fails to decrypt; it may be nil.
+func (e *Encrypter) Encrypt(ctx context.Context, plaintext []byte, associatedData []byte) ([]byte, error) {
+	dataKey, err := e.kms.GenerateDataKey(ctx, e.keyID)
+	if err != nil {
+		return nil, kit.WrapError(err, "error generating data key with %s", e.keyID)
+	}
+
+	aead, err := newAEAD(dataKey.Plaintext)
+	if err != nil {
+		return nil, kit.WrapError(err, "invalid data key from %s", e.keyID)
+	}
+
+	if len(e.keyID) > 0xffff || len(dataKey.Encrypted) > 0xffff {
+		return nil, fmt.Errorf("key ID or encrypted data key is too long for an envelope")
+	}
+
+	envelope := []byte{envelopeVersion}
+	envelope = binary.BigEndian.AppendUint16(envelope, uint16(len(e.keyID)))
+	envelope = append(envelope, e.keyID...)
+	envelope = binary.BigEndian.AppendUint16(envelope, uint16(len(dataKey.Encrypted)))
+	envelope = append(envelope, dataKey.Encrypted...)
+	nonce := randomBytes(aead.NonceSize())
+	envelope = append(envelope, nonce...)
+	return aead.Seal(envelope, nonce, plaintext, associatedData), nil
+}
+
+// Decrypt decrypts an envelope from Encrypt with the associatedData it was encrypted with. It returns
+// ErrUnknownKey if the envelope's key isn't the encrypter's key or one of its previous keys.
+func (e *Encrypter) Decrypt(ctx context.Context, envelope []byte, associatedData []byte) ([]byte, error) {
+	parsed, err := parseEnvelope(envelope)
+	if err != nil {
+		return nil, err
+	}
+	if !e.acceptsKey(parsed.keyID) {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownKey, parsed.keyID)
+	}
+
+	dataKey, err := e.kms.Decrypt(ctx, parsed.keyID, parsed.encryptedKey)
+	if err != nil {
+		return nil, kit.WrapError(err, "error decrypting data key with %s", parsed.keyID)
+	}
+
+	aead, err := newAEAD(dataKey)
+	if err != nil {
+		return nil, kit.WrapError(err, "invalid data key from %s", parsed.keyID)
+	}
+	if len(parsed.ciphertext) < aead.NonceSize() {
+		return nil, ErrInvalidEnvelope
+	}
+
+	nonce, ciphertext := parsed.ciphertext[:aead.NonceSize()], parsed.ciphertext[aead.NonceSize():]
+	plaintext, err := aead.Open(nil, nonce, ciphertext, associatedData)
+	if err != nil {
+		return nil, kit.WrapError(err, "error decrypting envelope")
+	}
+	return plaintext, nil
+}
+
+// KeyID returns the ID of the key an envelope was encrypted under
+func KeyID(envelope []byte) (string, error) {
+	parsed, err := parseEnvelope(envelope)
+	if err != nil {
+		return "", err
+	}
+	return parsed.keyID, nil
+}
+
+// NeedsRotation reports whether an envelope was encrypted under a key other than the encrypter's
+// current key, so Rotate would re-encrypt it
+func (e *Encrypter) NeedsRotation(envelope []byte) (bool, error) {
+	keyID, err := KeyID(envelope)
+	if err != nil {
+		return false, err
+	}
+	return keyID != e.keyID, nil
+}
+
+// Rotate re-encrypts an envelope under the encrypter's current key, e.g. while migrating data off a
+// previous key, and returns the envelope unchanged if it already uses the current key
+func (e *Encrypter) Rotate(ctx context.Context, envelope []byte, associatedData []byte) ([]byte, error) {
+	needsRotation, err := e.NeedsRotation(envelope)
+	if err != nil || !needsRotation {
+		return envelope, err
+	}
+
+	plaintext, err := e.Decrypt(ctx, envelope, associatedData)
+	if err != nil {
+		return nil, err
+	}
+	return e.Encrypt(ctx, plaintext, associatedData)
+}
+
+// EncryptString encrypts plaintext as Encrypt does and returns the envelope base64 encoded, for
+// storing secrets in text fields and configuration
+func (e *Encrypter) EncryptString(ctx context.Context, plaintext string, associatedData []byte) (string, error) {
+	envelope, err := e.Encrypt(ctx, []byte(plaintext), associatedData)
+	if err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(envelope), nil
+}
+
+// DecryptString decrypts a base64 encoded envelope from EncryptString
+func (e *Encrypter) DecryptString(ctx context.Context, envelope string, associatedData []byte) (string, error) {
+	decoded, err := base64.StdEncoding.DecodeString(envelope)
+	if err != nil {
+		return "", fmt.Errorf("%w: %w", ErrInvalidEnvelope, err)
+	}
+	plaintext, err := e.Decrypt(ctx, decoded, associatedData)
+	if err != nil {
+		return "", err
+	}
+	return string(plaintext), nil
+}
+
+func (e *Encrypter) acceptsKey(keyID string) bool {
+	return keyID == e.keyID || slices.Contains(e.previousKeyIDs, keyID)
+}
+
+type parsedEnvelope struct {
+	keyID        string
+	encryptedKey []byte
+	ciphertext   []byte
+}
+
+func parseEnvelope(data []byte) (*parsedEnvelope, error) {
+	if len(data) < 1 || data[0] != envelopeVersion {
+		return nil, ErrInvalidEnvelope
+	}
+	rest := data[1:]
+
+	keyID, rest, ok := readField(rest)
+	if !ok {
+		return nil, ErrInvalidEnvelope
+	}
+	encryptedKey, rest, ok := readField(rest)
+	if !ok {
+		return nil, ErrInvalidEnvelope
+	}
+
+	return &parsedEnvelope{keyID: string(keyID), encryptedKey: encryptedKey, ciphertext: rest}, nil
+}
+
+// readField reads a field prefixed with its 2-byte length
+func readField(data []byte) ([]byte, []byte, bool) {
+	if len(data) < 2 {
+		return nil, nil, false
+	}
+	length := int(binary.BigEndian.Uint16(data))
+	data = data[2:]
+	if len(data) < length {
+		return nil, nil, false
+	}
+	return data[:length], data[length:], true
+}
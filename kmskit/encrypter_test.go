@@ -0,0 +1,139 @@
+package kmskit
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEncrypter(t *testing.T) {
+	kms := NewRandomLocalKMS("aKey", "anotherKey")
+
+	t.Run("decrypts_what_it_encrypts", func(t *testing.T) {
+		encrypter := NewEncrypter(kms, "aKey")
+
+		envelope, err := encrypter.Encrypt(context.Background(), []byte("thePlaintext"), []byte("theRecord"))
+		assert.NoError(t, err)
+		assert.NotContains(t, string(envelope), "thePlaintext")
+
+		plaintext, err := encrypter.Decrypt(context.Background(), envelope, []byte("theRecord"))
+		assert.NoError(t, err)
+		assert.Equal(t, "thePlaintext", string(plaintext))
+	})
+
+	t.Run("uses_a_new_data_key_for_each_encryption", func(t *testing.T) {
+		encrypter := NewEncrypter(kms, "aKey")
+
+		anEnvelope, err := encrypter.Encrypt(context.Background(), []byte("thePlaintext"), nil)
+		assert.NoError(t, err)
+		anotherEnvelope, err := encrypter.Encrypt(context.Background(), []byte("thePlaintext"), nil)
+		assert.NoError(t, err)
+
+		assert.NotEqual(t, anEnvelope, anotherEnvelope)
+	})
+
+	t.Run("fails_to_decrypt_with_other_associated_data", func(t *testing.T) {
+		encrypter := NewEncrypter(kms, "aKey")
+		envelope, err := encrypter.Encrypt(context.Background(), []byte("thePlaintext"), []byte("theRecord"))
+		assert.NoError(t, err)
+
+		_, err = encrypter.Decrypt(context.Background(), envelope, []byte("anotherRecord"))
+
+		assert.ErrorContains(t, err, "error decrypting envelope")
+	})
+
+	t.Run("fails_to_decrypt_a_tampered_envelope", func(t *testing.T) {
+		encrypter := NewEncrypter(kms, "aKey")
+		envelope, err := encrypter.Encrypt(context.Background(), []byte("thePlaintext"), nil)
+		assert.NoError(t, err)
+		envelope[len(envelope)-1] ^= 1
+
+		_, err = encrypter.Decrypt(context.Background(), envelope, nil)
+
+		assert.Error(t, err)
+	})
+
+	t.Run("returns_err_invalid_envelope_for_other_data", func(t *testing.T) {
+		encrypter := NewEncrypter(kms, "aKey")
+
+		for _, data := range [][]byte{nil, []byte("not an envelope"), {envelopeVersion, 0, 9, 'a'}} {
+			_, err := encrypter.Decrypt(context.Background(), data, nil)
+
+			assert.ErrorIs(t, err, ErrInvalidEnvelope)
+		}
+	})
+
+	t.Run("decrypts_envelopes_of_previous_keys", func(t *testing.T) {
+		envelope, err := NewEncrypter(kms, "aKey").Encrypt(context.Background(), []byte("thePlaintext"), nil)
+		assert.NoError(t, err)
+		rotated := NewEncrypter(kms, "anotherKey", WithPreviousKeyIDs("aKey"))
+
+		plaintext, err := rotated.Decrypt(context.Background(), envelope, nil)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "thePlaintext", string(plaintext))
+	})
+
+	t.Run("returns_err_unknown_key_for_envelopes_of_other_keys", func(t *testing.T) {
+		envelope, err := NewEncrypter(kms, "aKey").Encrypt(context.Background(), []byte("thePlaintext"), nil)
+		assert.NoError(t, err)
+
+		_, err = NewEncrypter(kms, "anotherKey").Decrypt(context.Background(), envelope, nil)
+
+		assert.ErrorIs(t, err, ErrUnknownKey)
+	})
+}
+
+func TestEncrypterRotate(t *testing.T) {
+	kms := NewRandomLocalKMS("aKey", "anotherKey")
+
+	t.Run("re_encrypts_envelopes_of_previous_keys_under_the_current_key", func(t *testing.T) {
+		envelope, err := NewEncrypter(kms, "aKey").Encrypt(context.Background(), []byte("thePlaintext"), []byte("theRecord"))
+		assert.NoError(t, err)
+		rotated := NewEncrypter(kms, "anotherKey", WithPreviousKeyIDs("aKey"))
+
+		needsRotation, err := rotated.NeedsRotation(envelope)
+		assert.NoError(t, err)
+		assert.True(t, needsRotation)
+		envelope, err = rotated.Rotate(context.Background(), envelope, []byte("theRecord"))
+		assert.NoError(t, err)
+
+		keyID, err := KeyID(envelope)
+		assert.NoError(t, err)
+		assert.Equal(t, "anotherKey", keyID)
+		plaintext, err := NewEncrypter(kms, "anotherKey").Decrypt(context.Background(), envelope, []byte("theRecord"))
+		assert.NoError(t, err)
+		assert.Equal(t, "thePlaintext", string(plaintext))
+	})
+
+	t.Run("returns_envelopes_of_the_current_key_unchanged", func(t *testing.T) {
+		encrypter := NewEncrypter(kms, "aKey")
+		envelope, err := encrypter.Encrypt(context.Background(), []byte("thePlaintext"), nil)
+		assert.NoError(t, err)
+
+		rotated, err := encrypter.Rotate(context.Background(), envelope, nil)
+
+		assert.NoError(t, err)
+		assert.Equal(t, envelope, rotated)
+	})
+}
+
+func TestEncrypterStrings(t *testing.T) {
+	t.Run("decrypts_what_it_encrypts", func(t *testing.T) {
+		encrypter := NewEncrypter(NewRandomLocalKMS("aKey"), "aKey")
+
+		envelope, err := encrypter.EncryptString(context.Background(), "theSecret", nil)
+		assert.NoError(t, err)
+		secret, err := encrypter.DecryptString(context.Background(), envelope, nil)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "theSecret", secret)
+	})
+
+	t.Run("returns_err_invalid_envelope_for_invalid_base64", func(t *testing.T) {
+		_, err := NewEncrypter(NewRandomLocalKMS("aKey"), "aKey").DecryptString(context.Background(), "not base64!", nil)
+
+		assert.ErrorIs(t, err, ErrInvalidEnvelope)
+	})
+}
@@ -0,0 +1,36 @@
+// Package kmskit encrypts data with envelope encryption: each Encrypt asks a key management service
+// for a new data key, encrypts the data with it using AES-256-GCM, and stores the data key, encrypted
+// by the service's key, alongside the data. Decrypt asks the service to decrypt the data key, so the
+// service's keys never leave it. Envelopes name the key that encrypted them, so the key can be rotated
+// while data encrypted under earlier keys stays readable.
+//
+// The AWS SDK's KMS client isn't a dependency of this module, so encrypters use the small KMS
+// interface instead. Adapting *kms.Client to it takes a few lines: GenerateDataKey with a KeySpec of
+// AES_256, and Decrypt with the KeyId set. LocalKMS implements it with keys held in memory, for tests
+// and local development.
+package kmskit
+
+import (
+	"context"
+	"errors"
+)
+
+// ErrUnknownKey is returned when data was encrypted under a key the encrypter doesn't accept
+var ErrUnknownKey = errors.New("unknown key")
+
+// ErrInvalidEnvelope is returned when data to decrypt isn't an envelope from Encrypt
+var ErrInvalidEnvelope = errors.New("invalid envelope")
+
+// KMS is the part of a key management service envelope encryption uses
+type KMS interface {
+	// GenerateDataKey returns a new 256-bit data key in plaintext and encrypted under keyID
+	GenerateDataKey(ctx context.Context, keyID string) (*DataKey, error)
+	// Decrypt returns the plaintext of a data key encrypted under keyID
+	Decrypt(ctx context.Context, keyID string, encryptedKey []byte) ([]byte, error)
+}
+
+// DataKey is a data key from GenerateDataKey
+type DataKey struct {
+	Plaintext []byte
+	Encrypted []byte
+}
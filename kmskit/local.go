@@ -0,0 +1,91 @@
+package kmskit
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"fmt"
+
+	"github.com/half-ogre/go-kit/kit"
+)
+
+// LocalKMS is a KMS holding its keys in memory, for tests and local development. Don't use it in
+// production: anyone with the keys can decrypt everything, and they can't be rotated out of memory.
+type LocalKMS struct {
+	keys map[string]cipher.AEAD
+}
+
+// NewLocalKMS returns a LocalKMS with keys, which map key IDs to 32-byte AES-256 keys
+func NewLocalKMS(keys map[string][]byte) (*LocalKMS, error) {
+	local := &LocalKMS{keys: map[string]cipher.AEAD{}}
+	for keyID, key := range keys {
+		aead, err := newAEAD(key)
+		if err != nil {
+			return nil, kit.WrapError(err, "invalid key %s", keyID)
+		}
+		local.keys[keyID] = aead
+	}
+	return local, nil
+}
+
+// NewRandomLocalKMS returns a LocalKMS with a random key for each of keyIDs
+func NewRandomLocalKMS(keyIDs ...string) *LocalKMS {
+	keys := map[string][]byte{}
+	for _, keyID := range keyIDs {
+		keys[keyID] = randomBytes(dataKeySize)
+	}
+	local, _ := NewLocalKMS(keys)
+	return local
+}
+
+func (l *LocalKMS) GenerateDataKey(ctx context.Context, keyID string) (*DataKey, error) {
+	aead, ok := l.keys[keyID]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownKey, keyID)
+	}
+
+	plaintext := randomBytes(dataKeySize)
+	nonce := randomBytes(aead.NonceSize())
+	return &DataKey{
+		Plaintext: plaintext,
+		Encrypted: aead.Seal(nonce, nonce, plaintext, []byte(keyID)),
+	}, nil
+}
+
+func (l *LocalKMS) Decrypt(ctx context.Context, keyID string, encryptedKey []byte) ([]byte, error) {
+	aead, ok := l.keys[keyID]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownKey, keyID)
+	}
+	if len(encryptedKey) < aead.NonceSize() {
+		return nil, fmt.Errorf("encrypted data key is too short")
+	}
+
+	nonce, ciphertext := encryptedKey[:aead.NonceSize()], encryptedKey[aead.NonceSize():]
+	plaintext, err := aead.Open(nil, nonce, ciphertext, []byte(keyID))
+	if err != nil {
+		return nil, kit.WrapError(err, "error decrypting data key")
+	}
+	return plaintext, nil
+}
+
+const dataKeySize = 32
+
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	if len(key) != dataKeySize {
+		return nil, fmt.Errorf("key must be %d bytes, got %d", dataKeySize, len(key))
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+func randomBytes(n int) []byte {
+	b := make([]byte, n)
+	// crypto/rand.Read never returns an error
+	_, _ = rand.Read(b)
+	return b
+}
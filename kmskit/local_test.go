@@ -0,0 +1,46 @@
+package kmskit
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewLocalKMS(t *testing.T) {
+	t.Run("returns_an_error_for_a_key_of_the_wrong_size", func(t *testing.T) {
+		_, err := NewLocalKMS(map[string][]byte{"theKey": []byte("too short")})
+
+		assert.EqualError(t, err, "invalid key theKey: key must be 32 bytes, got 9")
+	})
+}
+
+func TestLocalKMS(t *testing.T) {
+	t.Run("decrypts_the_data_keys_it_generates", func(t *testing.T) {
+		kms := NewRandomLocalKMS("theKey")
+
+		dataKey, err := kms.GenerateDataKey(context.Background(), "theKey")
+		assert.NoError(t, err)
+		assert.Len(t, dataKey.Plaintext, 32)
+		plaintext, err := kms.Decrypt(context.Background(), "theKey", dataKey.Encrypted)
+
+		assert.NoError(t, err)
+		assert.Equal(t, dataKey.Plaintext, plaintext)
+	})
+
+	t.Run("does_not_decrypt_a_data_key_with_another_key", func(t *testing.T) {
+		kms := NewRandomLocalKMS("aKey", "anotherKey")
+		dataKey, err := kms.GenerateDataKey(context.Background(), "aKey")
+		assert.NoError(t, err)
+
+		_, err = kms.Decrypt(context.Background(), "anotherKey", dataKey.Encrypted)
+
+		assert.Error(t, err)
+	})
+
+	t.Run("returns_err_unknown_key_for_a_missing_key", func(t *testing.T) {
+		_, err := NewRandomLocalKMS().GenerateDataKey(context.Background(), "theKey")
+
+		assert.ErrorIs(t, err, ErrUnknownKey)
+	})
+}
@@ -0,0 +1,184 @@
+package llmkit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"iter"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awshttp "github.com/aws/aws-sdk-go-v2/aws/transport/http"
+	"github.com/aws/aws-sdk-go-v2/service/bedrockruntime"
+	"github.com/aws/aws-sdk-go-v2/service/bedrockruntime/types"
+)
+
+// BedrockRuntime is the part of *bedrockruntime.Client used by the Bedrock provider
+type BedrockRuntime interface {
+	Converse(ctx context.Context, params *bedrockruntime.ConverseInput, optFns ...func(*bedrockruntime.Options)) (*bedrockruntime.ConverseOutput, error)
+	ConverseStream(ctx context.Context, params *bedrockruntime.ConverseStreamInput, optFns ...func(*bedrockruntime.Options)) (*bedrockruntime.ConverseStreamOutput, error)
+}
+
+// converseEventStream is the part of *bedrockruntime.ConverseStreamEventStream used by Stream
+type converseEventStream interface {
+	Events() <-chan types.ConverseStreamOutput
+	Close() error
+	Err() error
+}
+
+// getConverseStream is a test seam; ConverseStreamOutput's stream cannot be set outside the SDK
+var getConverseStream = func(output *bedrockruntime.ConverseStreamOutput) converseEventStream {
+	return output.GetStream()
+}
+
+// BedrockProvider is a Provider for the Amazon Bedrock Converse API. Request.Model is the model ID or
+// inference profile, e.g. "anthropic.claude-3-5-sonnet-20240620-v1:0".
+type BedrockProvider struct {
+	client BedrockRuntime
+}
+
+// NewBedrockProvider returns a provider that sends requests with client, e.g.
+// bedrockruntime.NewFromConfig(cfg)
+func NewBedrockProvider(client BedrockRuntime) *BedrockProvider {
+	return &BedrockProvider{client: client}
+}
+
+func (p *BedrockProvider) Name() string {
+	return "bedrock"
+}
+
+// Complete sends request with Converse. Bedrock has no JSON response mode, so Request.JSON is ignored;
+// ask for JSON in the prompt and use ExtractJSON.
+func (p *BedrockProvider) Complete(ctx context.Context, request Request) (*Response, error) {
+	system, messages, inferenceConfig := bedrockInput(request)
+
+	output, err := p.client.Converse(ctx, &bedrockruntime.ConverseInput{
+		ModelId:         aws.String(request.Model),
+		System:          system,
+		Messages:        messages,
+		InferenceConfig: inferenceConfig,
+	})
+	if err != nil {
+		return nil, p.wrapError(err)
+	}
+
+	response := &Response{Model: request.Model, FinishReason: string(output.StopReason)}
+	if message, ok := output.Output.(*types.ConverseOutputMemberMessage); ok {
+		response.Content = bedrockText(message.Value.Content)
+	}
+	if output.Usage != nil {
+		response.Usage = bedrockUsage(output.Usage)
+	}
+
+	return response, nil
+}
+
+// Stream sends request with ConverseStream, yielding a chunk for each text delta
+func (p *BedrockProvider) Stream(ctx context.Context, request Request) iter.Seq2[Chunk, error] {
+	return func(yield func(Chunk, error) bool) {
+		system, messages, inferenceConfig := bedrockInput(request)
+
+		output, err := p.client.ConverseStream(ctx, &bedrockruntime.ConverseStreamInput{
+			ModelId:         aws.String(request.Model),
+			System:          system,
+			Messages:        messages,
+			InferenceConfig: inferenceConfig,
+		})
+		if err != nil {
+			yield(Chunk{}, p.wrapError(err))
+			return
+		}
+
+		stream := getConverseStream(output)
+		defer stream.Close()
+
+		for event := range stream.Events() {
+			chunk := Chunk{}
+			switch e := event.(type) {
+			case *types.ConverseStreamOutputMemberContentBlockDelta:
+				text, ok := e.Value.Delta.(*types.ContentBlockDeltaMemberText)
+				if !ok {
+					continue
+				}
+				chunk.Content = text.Value
+			case *types.ConverseStreamOutputMemberMessageStop:
+				chunk.FinishReason = string(e.Value.StopReason)
+			case *types.ConverseStreamOutputMemberMetadata:
+				if e.Value.Usage == nil {
+					continue
+				}
+				usage := bedrockUsage(e.Value.Usage)
+				chunk.Usage = &usage
+			default:
+				continue
+			}
+
+			if !yield(chunk, nil) {
+				return
+			}
+		}
+
+		if err := stream.Err(); err != nil {
+			yield(Chunk{}, p.wrapError(err))
+		}
+	}
+}
+
+// wrapError turns HTTP error responses into a ProviderError so the client can tell which to retry
+func (p *BedrockProvider) wrapError(err error) error {
+	var responseErr *awshttp.ResponseError
+	if errors.As(err, &responseErr) {
+		return &ProviderError{Provider: p.Name(), StatusCode: responseErr.HTTPStatusCode(), Message: responseErr.Err.Error()}
+	}
+	return fmt.Errorf("bedrock request failed: %w", err)
+}
+
+func bedrockInput(request Request) ([]types.SystemContentBlock, []types.Message, *types.InferenceConfiguration) {
+	var system []types.SystemContentBlock
+	var messages []types.Message
+	for _, message := range request.Messages {
+		if message.Role == RoleSystem {
+			system = append(system, &types.SystemContentBlockMemberText{Value: message.Content})
+			continue
+		}
+
+		role := types.ConversationRoleUser
+		if message.Role == RoleAssistant {
+			role = types.ConversationRoleAssistant
+		}
+		messages = append(messages, types.Message{
+			Role:    role,
+			Content: []types.ContentBlock{&types.ContentBlockMemberText{Value: message.Content}},
+		})
+	}
+
+	var inferenceConfig *types.InferenceConfiguration
+	if request.MaxTokens > 0 || request.Temperature != nil {
+		inferenceConfig = &types.InferenceConfiguration{}
+		if request.MaxTokens > 0 {
+			inferenceConfig.MaxTokens = aws.Int32(int32(request.MaxTokens))
+		}
+		if request.Temperature != nil {
+			inferenceConfig.Temperature = aws.Float32(float32(*request.Temperature))
+		}
+	}
+
+	return system, messages, inferenceConfig
+}
+
+func bedrockText(content []types.ContentBlock) string {
+	var text strings.Builder
+	for _, block := range content {
+		if textBlock, ok := block.(*types.ContentBlockMemberText); ok {
+			text.WriteString(textBlock.Value)
+		}
+	}
+	return text.String()
+}
+
+func bedrockUsage(usage *types.TokenUsage) Usage {
+	return Usage{
+		InputTokens:  int(aws.ToInt32(usage.InputTokens)),
+		OutputTokens: int(aws.ToInt32(usage.OutputTokens)),
+	}
+}
@@ -0,0 +1,110 @@
+package llmkit
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awshttp "github.com/aws/aws-sdk-go-v2/aws/transport/http"
+	"github.com/aws/aws-sdk-go-v2/service/bedrockruntime"
+	"github.com/aws/aws-sdk-go-v2/service/bedrockruntime/types"
+	smithyhttp "github.com/aws/smithy-go/transport/http"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeBedrockRuntime struct {
+	ConverseFake       func(ctx context.Context, params *bedrockruntime.ConverseInput) (*bedrockruntime.ConverseOutput, error)
+	ConverseStreamFake func(ctx context.Context, params *bedrockruntime.ConverseStreamInput) (*bedrockruntime.ConverseStreamOutput, error)
+}
+
+func (f *fakeBedrockRuntime) Converse(ctx context.Context, params *bedrockruntime.ConverseInput, optFns ...func(*bedrockruntime.Options)) (*bedrockruntime.ConverseOutput, error) {
+	return f.ConverseFake(ctx, params)
+}
+
+func (f *fakeBedrockRuntime) ConverseStream(ctx context.Context, params *bedrockruntime.ConverseStreamInput, optFns ...func(*bedrockruntime.Options)) (*bedrockruntime.ConverseStreamOutput, error) {
+	return f.ConverseStreamFake(ctx, params)
+}
+
+type fakeConverseStreamReader struct {
+	events chan types.ConverseStreamOutput
+}
+
+func (r *fakeConverseStreamReader) Events() <-chan types.ConverseStreamOutput { return r.events }
+func (r *fakeConverseStreamReader) Close() error                              { return nil }
+func (r *fakeConverseStreamReader) Err() error                                { return nil }
+
+func TestBedrockProviderComplete(t *testing.T) {
+	t.Run("sends_the_request_and_returns_the_completion", func(t *testing.T) {
+		var sent *bedrockruntime.ConverseInput
+		client := &fakeBedrockRuntime{ConverseFake: func(ctx context.Context, params *bedrockruntime.ConverseInput) (*bedrockruntime.ConverseOutput, error) {
+			sent = params
+			return &bedrockruntime.ConverseOutput{
+				Output: &types.ConverseOutputMemberMessage{Value: types.Message{
+					Role:    types.ConversationRoleAssistant,
+					Content: []types.ContentBlock{&types.ContentBlockMemberText{Value: "theAnswer"}},
+				}},
+				StopReason: types.StopReasonEndTurn,
+				Usage:      &types.TokenUsage{InputTokens: aws.Int32(3), OutputTokens: aws.Int32(5)},
+			}, nil
+		}}
+		request := Request{Model: "theModel", Messages: []Message{{Role: RoleSystem, Content: "theInstructions"}, {Role: RoleUser, Content: "theQuestion"}}, MaxTokens: 100}
+
+		result, err := NewBedrockProvider(client).Complete(context.Background(), request)
+
+		assert.NoError(t, err)
+		assert.Equal(t, &Response{Model: "theModel", Content: "theAnswer", FinishReason: "end_turn", Usage: Usage{InputTokens: 3, OutputTokens: 5}}, result)
+		assert.Equal(t, "theModel", aws.ToString(sent.ModelId))
+		assert.Equal(t, []types.SystemContentBlock{&types.SystemContentBlockMemberText{Value: "theInstructions"}}, sent.System)
+		assert.Equal(t, []types.Message{{Role: types.ConversationRoleUser, Content: []types.ContentBlock{&types.ContentBlockMemberText{Value: "theQuestion"}}}}, sent.Messages)
+		assert.Equal(t, aws.Int32(100), sent.InferenceConfig.MaxTokens)
+	})
+
+	t.Run("returns_a_provider_error_for_an_error_response", func(t *testing.T) {
+		client := &fakeBedrockRuntime{ConverseFake: func(ctx context.Context, params *bedrockruntime.ConverseInput) (*bedrockruntime.ConverseOutput, error) {
+			return nil, &awshttp.ResponseError{
+				ResponseError: &smithyhttp.ResponseError{Response: &smithyhttp.Response{Response: &http.Response{StatusCode: http.StatusTooManyRequests}}, Err: assert.AnError},
+			}
+		}}
+
+		_, err := NewBedrockProvider(client).Complete(context.Background(), theRequest)
+
+		var providerErr *ProviderError
+		assert.ErrorAs(t, err, &providerErr)
+		assert.Equal(t, http.StatusTooManyRequests, providerErr.StatusCode)
+		assert.True(t, providerErr.Retryable())
+	})
+}
+
+func TestBedrockProviderStream(t *testing.T) {
+	t.Run("yields_a_chunk_for_each_text_delta", func(t *testing.T) {
+		events := make(chan types.ConverseStreamOutput, 5)
+		events <- &types.ConverseStreamOutputMemberMessageStart{}
+		events <- &types.ConverseStreamOutputMemberContentBlockDelta{Value: types.ContentBlockDeltaEvent{Delta: &types.ContentBlockDeltaMemberText{Value: "the"}}}
+		events <- &types.ConverseStreamOutputMemberContentBlockDelta{Value: types.ContentBlockDeltaEvent{Delta: &types.ContentBlockDeltaMemberText{Value: "Answer"}}}
+		events <- &types.ConverseStreamOutputMemberMessageStop{Value: types.MessageStopEvent{StopReason: types.StopReasonEndTurn}}
+		events <- &types.ConverseStreamOutputMemberMetadata{Value: types.ConverseStreamMetadataEvent{Usage: &types.TokenUsage{InputTokens: aws.Int32(3), OutputTokens: aws.Int32(2)}}}
+		close(events)
+		client := &fakeBedrockRuntime{ConverseStreamFake: func(ctx context.Context, params *bedrockruntime.ConverseStreamInput) (*bedrockruntime.ConverseStreamOutput, error) {
+			return &bedrockruntime.ConverseStreamOutput{}, nil
+		}}
+		previous := getConverseStream
+		getConverseStream = func(output *bedrockruntime.ConverseStreamOutput) converseEventStream {
+			return &fakeConverseStreamReader{events: events}
+		}
+		t.Cleanup(func() { getConverseStream = previous })
+
+		result := []Chunk{}
+		for chunk, err := range NewBedrockProvider(client).Stream(context.Background(), theRequest) {
+			assert.NoError(t, err)
+			result = append(result, chunk)
+		}
+
+		assert.Equal(t, []Chunk{
+			{Content: "the"},
+			{Content: "Answer"},
+			{FinishReason: "end_turn"},
+			{Usage: &Usage{InputTokens: 3, OutputTokens: 2}},
+		}, result)
+	})
+}
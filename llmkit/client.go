@@ -0,0 +1,189 @@
+package llmkit
+
+import (
+	"context"
+	"errors"
+	"iter"
+	"log/slog"
+	"time"
+
+	"github.com/half-ogre/go-kit/logkit/logfields"
+)
+
+// CompletionMetrics describes one Complete or Stream call, including any retries
+type CompletionMetrics struct {
+	Provider string
+	Model    string
+	Stream   bool
+	Attempts int
+	Duration time.Duration
+	Usage    Usage
+	Err      error
+}
+
+// ClientOption configures a Client
+type ClientOption func(*clientConfig)
+
+type clientConfig struct {
+	maxRetries      int
+	retryBackoff    time.Duration
+	instrumentation func(ctx context.Context, metrics CompletionMetrics)
+}
+
+// WithRetries sets how many times a request that failed with a retryable error is sent again and the
+// initial backoff between attempts, which doubles after each retry; the default is 2 retries starting
+// at 1s. Streams are only retried if they fail before yielding any content.
+func WithRetries(maxRetries int, backoff time.Duration) ClientOption {
+	return func(c *clientConfig) {
+		c.maxRetries = maxRetries
+		c.retryBackoff = backoff
+	}
+}
+
+// WithInstrumentation sets a function called with the metrics of each completion, e.g. to record
+// token usage or add trace spans
+func WithInstrumentation(instrumentation func(ctx context.Context, metrics CompletionMetrics)) ClientOption {
+	return func(c *clientConfig) {
+		c.instrumentation = instrumentation
+	}
+}
+
+// Client sends chat-completion requests through a Provider
+type Client struct {
+	provider Provider
+	config   clientConfig
+}
+
+// NewClient returns a client for provider
+func NewClient(provider Provider, options ...ClientOption) *Client {
+	config := clientConfig{
+		maxRetries:   2,
+		retryBackoff: time.Second,
+	}
+	for _, option := range options {
+		option(&config)
+	}
+
+	return &Client{provider: provider, config: config}
+}
+
+// Complete sends request and returns the completion, retrying retryable errors
+func (c *Client) Complete(ctx context.Context, request Request) (*Response, error) {
+	start := time.Now()
+	metrics := CompletionMetrics{Provider: c.provider.Name(), Model: request.Model}
+
+	var response *Response
+	err := c.retry(ctx, &metrics, func() (bool, error) {
+		var err error
+		response, err = c.provider.Complete(ctx, request)
+		return true, err
+	})
+
+	if response != nil {
+		metrics.Usage = response.Usage
+	}
+	c.finish(ctx, metrics, start, err)
+
+	if err != nil {
+		return nil, err
+	}
+	return response, nil
+}
+
+// Stream sends request and yields the completion's content as it arrives. If the provider fails before
+// any content has been yielded the request is retried; once content has been yielded the error is
+// yielded instead. Stop ranging early to cancel the rest of the stream.
+func (c *Client) Stream(ctx context.Context, request Request) iter.Seq2[string, error] {
+	return func(yield func(string, error) bool) {
+		start := time.Now()
+		metrics := CompletionMetrics{Provider: c.provider.Name(), Model: request.Model, Stream: true}
+		stopped := false
+
+		err := c.retry(ctx, &metrics, func() (bool, error) {
+			yielded := false
+			for chunk, err := range c.provider.Stream(ctx, request) {
+				if err != nil {
+					return !yielded, err
+				}
+				if chunk.Usage != nil {
+					metrics.Usage = *chunk.Usage
+				}
+				if chunk.Content == "" {
+					continue
+				}
+				yielded = true
+				if !yield(chunk.Content, nil) {
+					stopped = true
+					return false, nil
+				}
+			}
+			return false, nil
+		})
+
+		c.finish(ctx, metrics, start, err)
+
+		if err != nil && !stopped {
+			yield("", err)
+		}
+	}
+}
+
+// retry calls attempt until it succeeds, returns a non-retryable error, or the retries run out.
+// attempt reports whether its error may be retried at all, e.g. a stream that has not yielded yet.
+func (c *Client) retry(ctx context.Context, metrics *CompletionMetrics, attempt func() (bool, error)) error {
+	backoff := c.config.retryBackoff
+	for {
+		metrics.Attempts++
+		canRetry, err := attempt()
+		if err == nil || !canRetry || !isRetryable(err) || metrics.Attempts > c.config.maxRetries {
+			return err
+		}
+
+		slog.WarnContext(ctx, "retrying llm request", "provider", metrics.Provider, "model", metrics.Model, "attempt", metrics.Attempts, "error", err)
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+	}
+}
+
+func (c *Client) finish(ctx context.Context, metrics CompletionMetrics, start time.Time, err error) {
+	metrics.Duration = time.Since(start)
+	metrics.Err = err
+
+	attrs := []any{
+		"provider", metrics.Provider,
+		"model", metrics.Model,
+		"stream", metrics.Stream,
+		"attempts", metrics.Attempts,
+		"input_tokens", metrics.Usage.InputTokens,
+		"output_tokens", metrics.Usage.OutputTokens,
+		logfields.Duration(metrics.Duration),
+	}
+	if err != nil {
+		slog.ErrorContext(ctx, "llm completion failed", append(attrs, "error", err)...)
+	} else {
+		slog.DebugContext(ctx, "llm completion", attrs...)
+	}
+
+	if c.config.instrumentation != nil {
+		c.config.instrumentation(ctx, metrics)
+	}
+}
+
+func isRetryable(err error) bool {
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+
+	var providerErr *ProviderError
+	if errors.As(err, &providerErr) {
+		return providerErr.Retryable()
+	}
+
+	// Anything else is a transport error, e.g. a dropped connection
+	return true
+}
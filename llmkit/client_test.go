@@ -0,0 +1,194 @@
+package llmkit
+
+import (
+	"context"
+	"errors"
+	"iter"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var theRequest = Request{Model: "theModel", Messages: []Message{{Role: RoleUser, Content: "theQuestion"}}}
+
+func chunks(items ...any) iter.Seq2[Chunk, error] {
+	return func(yield func(Chunk, error) bool) {
+		for _, item := range items {
+			var ok bool
+			switch v := item.(type) {
+			case error:
+				ok = yield(Chunk{}, v)
+			case Chunk:
+				ok = yield(v, nil)
+			}
+			if !ok {
+				return
+			}
+		}
+	}
+}
+
+func TestClientComplete(t *testing.T) {
+	t.Run("returns_the_provider_response", func(t *testing.T) {
+		theResponse := &Response{Model: "theModel", Content: "theAnswer", Usage: Usage{InputTokens: 3, OutputTokens: 5}}
+		provider := &FakeProvider{CompleteFake: func(ctx context.Context, request Request) (*Response, error) {
+			return theResponse, nil
+		}}
+
+		result, err := NewClient(provider).Complete(context.Background(), theRequest)
+
+		assert.NoError(t, err)
+		assert.Equal(t, theResponse, result)
+	})
+
+	t.Run("retries_a_retryable_error", func(t *testing.T) {
+		attempts := 0
+		provider := &FakeProvider{CompleteFake: func(ctx context.Context, request Request) (*Response, error) {
+			attempts++
+			if attempts == 1 {
+				return nil, &ProviderError{Provider: "fake", StatusCode: http.StatusTooManyRequests, Message: "theMessage"}
+			}
+			return &Response{Content: "theAnswer"}, nil
+		}}
+
+		result, err := NewClient(provider, WithRetries(2, time.Millisecond)).Complete(context.Background(), theRequest)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "theAnswer", result.Content)
+		assert.Equal(t, 2, attempts)
+	})
+
+	t.Run("does_not_retry_a_client_error", func(t *testing.T) {
+		attempts := 0
+		provider := &FakeProvider{CompleteFake: func(ctx context.Context, request Request) (*Response, error) {
+			attempts++
+			return nil, &ProviderError{Provider: "fake", StatusCode: http.StatusBadRequest, Message: "theMessage"}
+		}}
+
+		_, err := NewClient(provider, WithRetries(2, time.Millisecond)).Complete(context.Background(), theRequest)
+
+		assert.EqualError(t, err, "fake request failed with status 400: theMessage")
+		assert.Equal(t, 1, attempts)
+	})
+
+	t.Run("returns_the_last_error_when_the_retries_run_out", func(t *testing.T) {
+		attempts := 0
+		provider := &FakeProvider{CompleteFake: func(ctx context.Context, request Request) (*Response, error) {
+			attempts++
+			return nil, assert.AnError
+		}}
+
+		_, err := NewClient(provider, WithRetries(2, time.Millisecond)).Complete(context.Background(), theRequest)
+
+		assert.ErrorIs(t, err, assert.AnError)
+		assert.Equal(t, 3, attempts)
+	})
+
+	t.Run("stops_retrying_when_the_context_is_cancelled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		provider := &FakeProvider{CompleteFake: func(ctx context.Context, request Request) (*Response, error) {
+			cancel()
+			return nil, assert.AnError
+		}}
+
+		_, err := NewClient(provider, WithRetries(2, time.Hour)).Complete(ctx, theRequest)
+
+		assert.ErrorIs(t, err, context.Canceled)
+	})
+
+	t.Run("calls_the_instrumentation_with_the_metrics", func(t *testing.T) {
+		var metrics CompletionMetrics
+		provider := &FakeProvider{CompleteFake: func(ctx context.Context, request Request) (*Response, error) {
+			return &Response{Content: "theAnswer", Usage: Usage{InputTokens: 3, OutputTokens: 5}}, nil
+		}}
+		client := NewClient(provider, WithInstrumentation(func(ctx context.Context, m CompletionMetrics) { metrics = m }))
+
+		_, err := client.Complete(context.Background(), theRequest)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "fake", metrics.Provider)
+		assert.Equal(t, "theModel", metrics.Model)
+		assert.False(t, metrics.Stream)
+		assert.Equal(t, 1, metrics.Attempts)
+		assert.Equal(t, Usage{InputTokens: 3, OutputTokens: 5}, metrics.Usage)
+		assert.NoError(t, metrics.Err)
+	})
+}
+
+func TestClientStream(t *testing.T) {
+	t.Run("yields_the_content_of_each_chunk", func(t *testing.T) {
+		var metrics CompletionMetrics
+		provider := &FakeProvider{StreamFake: func(ctx context.Context, request Request) iter.Seq2[Chunk, error] {
+			return chunks(Chunk{Content: "the"}, Chunk{Content: "Answer"}, Chunk{FinishReason: "stop", Usage: &Usage{InputTokens: 3, OutputTokens: 2}})
+		}}
+		client := NewClient(provider, WithInstrumentation(func(ctx context.Context, m CompletionMetrics) { metrics = m }))
+
+		tokens := []string{}
+		for token, err := range client.Stream(context.Background(), theRequest) {
+			assert.NoError(t, err)
+			tokens = append(tokens, token)
+		}
+
+		assert.Equal(t, []string{"the", "Answer"}, tokens)
+		assert.True(t, metrics.Stream)
+		assert.Equal(t, Usage{InputTokens: 3, OutputTokens: 2}, metrics.Usage)
+	})
+
+	t.Run("retries_an_error_before_any_content", func(t *testing.T) {
+		attempts := 0
+		provider := &FakeProvider{StreamFake: func(ctx context.Context, request Request) iter.Seq2[Chunk, error] {
+			attempts++
+			if attempts == 1 {
+				return chunks(errors.New("theConnectionError"))
+			}
+			return chunks(Chunk{Content: "theAnswer"})
+		}}
+
+		tokens := []string{}
+		for token, err := range NewClient(provider, WithRetries(2, time.Millisecond)).Stream(context.Background(), theRequest) {
+			assert.NoError(t, err)
+			tokens = append(tokens, token)
+		}
+
+		assert.Equal(t, []string{"theAnswer"}, tokens)
+		assert.Equal(t, 2, attempts)
+	})
+
+	t.Run("yields_an_error_after_content_without_retrying", func(t *testing.T) {
+		attempts := 0
+		provider := &FakeProvider{StreamFake: func(ctx context.Context, request Request) iter.Seq2[Chunk, error] {
+			attempts++
+			return chunks(Chunk{Content: "the"}, assert.AnError)
+		}}
+
+		tokens := []string{}
+		var streamErr error
+		for token, err := range NewClient(provider, WithRetries(2, time.Millisecond)).Stream(context.Background(), theRequest) {
+			if err != nil {
+				streamErr = err
+				continue
+			}
+			tokens = append(tokens, token)
+		}
+
+		assert.Equal(t, []string{"the"}, tokens)
+		assert.ErrorIs(t, streamErr, assert.AnError)
+		assert.Equal(t, 1, attempts)
+	})
+
+	t.Run("stops_when_the_caller_stops_ranging", func(t *testing.T) {
+		provider := &FakeProvider{StreamFake: func(ctx context.Context, request Request) iter.Seq2[Chunk, error] {
+			return chunks(Chunk{Content: "the"}, Chunk{Content: "Answer"})
+		}}
+
+		tokens := []string{}
+		for token := range NewClient(provider).Stream(context.Background(), theRequest) {
+			tokens = append(tokens, token)
+			break
+		}
+
+		assert.Equal(t, []string{"the"}, tokens)
+	})
+}
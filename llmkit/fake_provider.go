@@ -0,0 +1,33 @@
+package llmkit
+
+import (
+	"context"
+	"iter"
+)
+
+type FakeProvider struct {
+	NameFake     func() string
+	CompleteFake func(ctx context.Context, request Request) (*Response, error)
+	StreamFake   func(ctx context.Context, request Request) iter.Seq2[Chunk, error]
+}
+
+func (f *FakeProvider) Name() string {
+	if f.NameFake != nil {
+		return f.NameFake()
+	}
+	return "fake"
+}
+
+func (f *FakeProvider) Complete(ctx context.Context, request Request) (*Response, error) {
+	if f.CompleteFake != nil {
+		return f.CompleteFake(ctx, request)
+	}
+	panic("Complete fake not implemented")
+}
+
+func (f *FakeProvider) Stream(ctx context.Context, request Request) iter.Seq2[Chunk, error] {
+	if f.StreamFake != nil {
+		return f.StreamFake(ctx, request)
+	}
+	panic("Stream fake not implemented")
+}
@@ -0,0 +1,115 @@
+package llmkit
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ExtractJSON unmarshals the JSON in a model's response into T. Models often wrap JSON in a Markdown
+// code fence or surround it with prose, so this uses the first fenced block if there is one, otherwise
+// the first valid JSON object or array in content.
+func ExtractJSON[T any](content string) (T, error) {
+	var result T
+
+	text, ok := findJSON(content)
+	if !ok {
+		return result, errors.New("no JSON found in response")
+	}
+
+	if err := json.Unmarshal([]byte(text), &result); err != nil {
+		return result, fmt.Errorf("failed to unmarshal JSON from response: %w", err)
+	}
+
+	return result, nil
+}
+
+// CompleteJSON sends request with JSON responses requested and extracts the response into T. The
+// response is returned too, for its usage and so callers can log content that failed to extract.
+func CompleteJSON[T any](ctx context.Context, client *Client, request Request) (T, *Response, error) {
+	var result T
+
+	request.JSON = true
+	response, err := client.Complete(ctx, request)
+	if err != nil {
+		return result, nil, err
+	}
+
+	result, err = ExtractJSON[T](response.Content)
+	if err != nil {
+		return result, response, err
+	}
+
+	return result, response, nil
+}
+
+func findJSON(content string) (string, bool) {
+	if fenced, ok := fencedBlock(content); ok {
+		return fenced, true
+	}
+
+	start := strings.IndexAny(content, "{[")
+	for start >= 0 {
+		if end, ok := matchingClose(content[start:]); ok && json.Valid([]byte(content[start:start+end+1])) {
+			return content[start : start+end+1], true
+		}
+		next := strings.IndexAny(content[start+1:], "{[")
+		if next < 0 {
+			break
+		}
+		start += next + 1
+	}
+
+	return "", false
+}
+
+func fencedBlock(content string) (string, bool) {
+	_, afterOpen, found := strings.Cut(content, "```")
+	if !found {
+		return "", false
+	}
+
+	// Skip the language tag, e.g. ```json
+	if newline := strings.IndexByte(afterOpen, '\n'); newline >= 0 {
+		afterOpen = afterOpen[newline+1:]
+	}
+
+	block, _, found := strings.Cut(afterOpen, "```")
+	if !found {
+		return "", false
+	}
+
+	block = strings.TrimSpace(block)
+	return block, block != ""
+}
+
+// matchingClose returns the index of the bracket closing the object or array that text starts with,
+// skipping brackets inside strings
+func matchingClose(text string) (int, bool) {
+	depth := 0
+	inString := false
+	escaped := false
+
+	for i, r := range text {
+		switch {
+		case escaped:
+			escaped = false
+		case inString && r == '\\':
+			escaped = true
+		case r == '"':
+			inString = !inString
+		case inString:
+		case r == '{' || r == '[':
+			depth++
+		case r == '}' || r == ']':
+			depth--
+			if depth == 0 {
+				return i, true
+			}
+		}
+	}
+
+	return 0, false
+}
@@ -0,0 +1,83 @@
+package llmkit
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type theAnswer struct {
+	Name  string `json:"name"`
+	Count int    `json:"count"`
+}
+
+func TestExtractJSON(t *testing.T) {
+	t.Run("extracts_a_bare_object", func(t *testing.T) {
+		result, err := ExtractJSON[theAnswer](`{"name":"theName","count":2}`)
+
+		assert.NoError(t, err)
+		assert.Equal(t, theAnswer{Name: "theName", Count: 2}, result)
+	})
+
+	t.Run("extracts_a_fenced_block", func(t *testing.T) {
+		result, err := ExtractJSON[theAnswer]("Here you go:\n```json\n{\"name\":\"theName\",\"count\":2}\n```\nAnything else?")
+
+		assert.NoError(t, err)
+		assert.Equal(t, theAnswer{Name: "theName", Count: 2}, result)
+	})
+
+	t.Run("extracts_an_object_surrounded_by_prose", func(t *testing.T) {
+		result, err := ExtractJSON[theAnswer](`Sure {not json} the answer is {"name":"the } name","count":2} as requested.`)
+
+		assert.NoError(t, err)
+		assert.Equal(t, theAnswer{Name: "the } name", Count: 2}, result)
+	})
+
+	t.Run("extracts_an_array", func(t *testing.T) {
+		result, err := ExtractJSON[[]int](`The numbers are [1, 2, 3].`)
+
+		assert.NoError(t, err)
+		assert.Equal(t, []int{1, 2, 3}, result)
+	})
+
+	t.Run("returns_an_error_without_json", func(t *testing.T) {
+		_, err := ExtractJSON[theAnswer](`I can't help with that.`)
+
+		assert.EqualError(t, err, "no JSON found in response")
+	})
+
+	t.Run("returns_an_error_for_json_of_the_wrong_shape", func(t *testing.T) {
+		_, err := ExtractJSON[theAnswer](`{"name":2}`)
+
+		assert.ErrorContains(t, err, "failed to unmarshal JSON from response")
+	})
+}
+
+func TestCompleteJSON(t *testing.T) {
+	t.Run("requests_json_and_extracts_the_response", func(t *testing.T) {
+		var sent Request
+		provider := &FakeProvider{CompleteFake: func(ctx context.Context, request Request) (*Response, error) {
+			sent = request
+			return &Response{Content: `{"name":"theName","count":2}`}, nil
+		}}
+
+		result, response, err := CompleteJSON[theAnswer](context.Background(), NewClient(provider), theRequest)
+
+		assert.NoError(t, err)
+		assert.True(t, sent.JSON)
+		assert.Equal(t, theAnswer{Name: "theName", Count: 2}, result)
+		assert.Equal(t, `{"name":"theName","count":2}`, response.Content)
+	})
+
+	t.Run("returns_the_response_when_extraction_fails", func(t *testing.T) {
+		provider := &FakeProvider{CompleteFake: func(ctx context.Context, request Request) (*Response, error) {
+			return &Response{Content: "theContent"}, nil
+		}}
+
+		_, response, err := CompleteJSON[theAnswer](context.Background(), NewClient(provider), theRequest)
+
+		assert.Error(t, err)
+		assert.Equal(t, "theContent", response.Content)
+	})
+}
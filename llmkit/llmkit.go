@@ -0,0 +1,84 @@
+// Package llmkit is a small provider-agnostic client for chat-completion APIs. A Client wraps a
+// Provider (OpenAI-compatible HTTP APIs or Amazon Bedrock) with retries, streaming token iterators,
+// slog instrumentation, and helpers for extracting JSON from responses.
+package llmkit
+
+import (
+	"context"
+	"fmt"
+	"iter"
+	"net/http"
+)
+
+// Role is the author of a message
+type Role string
+
+const (
+	RoleSystem    Role = "system"
+	RoleUser      Role = "user"
+	RoleAssistant Role = "assistant"
+)
+
+// Message is one message in a conversation
+type Message struct {
+	Role    Role
+	Content string
+}
+
+// Request is a chat-completion request
+type Request struct {
+	Model    string
+	Messages []Message
+	// MaxTokens limits the tokens generated; zero uses the provider's default
+	MaxTokens int
+	// Temperature is the sampling temperature; nil uses the provider's default
+	Temperature *float64
+	// JSON asks the provider to respond with a JSON object, when it supports doing so
+	JSON bool
+}
+
+// Usage is the tokens used by a completion
+type Usage struct {
+	InputTokens  int
+	OutputTokens int
+}
+
+// Response is a completed chat-completion
+type Response struct {
+	Model        string
+	Content      string
+	FinishReason string
+	Usage        Usage
+}
+
+// Chunk is one piece of a streamed completion. The final chunk may carry only a finish reason or usage.
+type Chunk struct {
+	Content      string
+	FinishReason string
+	Usage        *Usage
+}
+
+// Provider sends requests to one chat-completion API
+type Provider interface {
+	// Name identifies the provider in logs and metrics, e.g. "openai"
+	Name() string
+	Complete(ctx context.Context, request Request) (*Response, error)
+	// Stream yields the completion's chunks as the provider sends them
+	Stream(ctx context.Context, request Request) iter.Seq2[Chunk, error]
+}
+
+// ProviderError is an error response from a provider's API
+type ProviderError struct {
+	Provider   string
+	StatusCode int
+	Message    string
+}
+
+func (e *ProviderError) Error() string {
+	return fmt.Sprintf("%s request failed with status %d: %s", e.Provider, e.StatusCode, e.Message)
+}
+
+// Retryable reports whether the request may succeed if sent again: rate limits and server errors
+func (e *ProviderError) Retryable() bool {
+	return e.StatusCode == http.StatusTooManyRequests || e.StatusCode >= http.StatusInternalServerError
+}
@@ -0,0 +1,216 @@
+package llmkit
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"iter"
+	"net/http"
+	"strings"
+)
+
+const defaultOpenAIBaseURL = "https://api.openai.com/v1"
+
+// OpenAIOption configures an OpenAI provider
+type OpenAIOption func(*OpenAIProvider)
+
+// WithOpenAIBaseURL sets the API's base URL, for OpenAI-compatible APIs such as Azure OpenAI, vLLM,
+// or Ollama; the default is https://api.openai.com/v1
+func WithOpenAIBaseURL(baseURL string) OpenAIOption {
+	return func(p *OpenAIProvider) {
+		p.baseURL = strings.TrimSuffix(baseURL, "/")
+	}
+}
+
+// WithOpenAIHTTPClient sets the client used to send requests
+func WithOpenAIHTTPClient(client *http.Client) OpenAIOption {
+	return func(p *OpenAIProvider) {
+		p.client = client
+	}
+}
+
+// OpenAIProvider is a Provider for the OpenAI chat completions API and APIs compatible with it
+type OpenAIProvider struct {
+	apiKey  string
+	baseURL string
+	client  *http.Client
+}
+
+// NewOpenAIProvider returns a provider that authenticates with apiKey
+func NewOpenAIProvider(apiKey string, options ...OpenAIOption) *OpenAIProvider {
+	provider := &OpenAIProvider{
+		apiKey:  apiKey,
+		baseURL: defaultOpenAIBaseURL,
+		client:  http.DefaultClient,
+	}
+	for _, option := range options {
+		option(provider)
+	}
+	return provider
+}
+
+func (p *OpenAIProvider) Name() string {
+	return "openai"
+}
+
+type openAIMessage struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+type openAIRequest struct {
+	Model          string            `json:"model"`
+	Messages       []openAIMessage   `json:"messages"`
+	MaxTokens      int               `json:"max_tokens,omitempty"`
+	Temperature    *float64          `json:"temperature,omitempty"`
+	ResponseFormat map[string]string `json:"response_format,omitempty"`
+	Stream         bool              `json:"stream,omitempty"`
+	StreamOptions  map[string]bool   `json:"stream_options,omitempty"`
+}
+
+type openAIUsage struct {
+	PromptTokens     int `json:"prompt_tokens"`
+	CompletionTokens int `json:"completion_tokens"`
+}
+
+type openAIResponse struct {
+	Model   string `json:"model"`
+	Choices []struct {
+		Message      openAIMessage `json:"message"`
+		Delta        openAIMessage `json:"delta"`
+		FinishReason string        `json:"finish_reason"`
+	} `json:"choices"`
+	Usage *openAIUsage `json:"usage"`
+}
+
+func (p *OpenAIProvider) Complete(ctx context.Context, request Request) (*Response, error) {
+	res, err := p.post(ctx, request, false)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	var body openAIResponse
+	if err := json.NewDecoder(res.Body).Decode(&body); err != nil {
+		return nil, fmt.Errorf("failed to decode openai response: %w", err)
+	}
+
+	response := &Response{Model: body.Model}
+	if len(body.Choices) > 0 {
+		response.Content = body.Choices[0].Message.Content
+		response.FinishReason = body.Choices[0].FinishReason
+	}
+	if body.Usage != nil {
+		response.Usage = Usage{InputTokens: body.Usage.PromptTokens, OutputTokens: body.Usage.CompletionTokens}
+	}
+
+	return response, nil
+}
+
+// Stream reads the API's server-sent events, yielding a chunk for each delta
+func (p *OpenAIProvider) Stream(ctx context.Context, request Request) iter.Seq2[Chunk, error] {
+	return func(yield func(Chunk, error) bool) {
+		res, err := p.post(ctx, request, true)
+		if err != nil {
+			yield(Chunk{}, err)
+			return
+		}
+		defer res.Body.Close()
+
+		scanner := bufio.NewScanner(res.Body)
+		for scanner.Scan() {
+			data, ok := strings.CutPrefix(scanner.Text(), "data:")
+			if !ok {
+				continue
+			}
+			data = strings.TrimSpace(data)
+			if data == "[DONE]" {
+				return
+			}
+
+			var event openAIResponse
+			if err := json.Unmarshal([]byte(data), &event); err != nil {
+				yield(Chunk{}, fmt.Errorf("failed to decode openai stream event: %w", err))
+				return
+			}
+
+			chunk := Chunk{}
+			if len(event.Choices) > 0 {
+				chunk.Content = event.Choices[0].Delta.Content
+				chunk.FinishReason = event.Choices[0].FinishReason
+			}
+			if event.Usage != nil {
+				chunk.Usage = &Usage{InputTokens: event.Usage.PromptTokens, OutputTokens: event.Usage.CompletionTokens}
+			}
+
+			if !yield(chunk, nil) {
+				return
+			}
+		}
+
+		if err := scanner.Err(); err != nil {
+			yield(Chunk{}, fmt.Errorf("failed to read openai stream: %w", err))
+		}
+	}
+}
+
+func (p *OpenAIProvider) post(ctx context.Context, request Request, stream bool) (*http.Response, error) {
+	body := openAIRequest{
+		Model:       request.Model,
+		MaxTokens:   request.MaxTokens,
+		Temperature: request.Temperature,
+		Stream:      stream,
+	}
+	for _, message := range request.Messages {
+		body.Messages = append(body.Messages, openAIMessage{Role: string(message.Role), Content: message.Content})
+	}
+	if request.JSON {
+		body.ResponseFormat = map[string]string{"type": "json_object"}
+	}
+	if stream {
+		body.StreamOptions = map[string]bool{"include_usage": true}
+	}
+
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal openai request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.baseURL+"/chat/completions", bytes.NewReader(payload))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create openai request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+p.apiKey)
+
+	res, err := p.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send openai request: %w", err)
+	}
+
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		defer res.Body.Close()
+		return nil, &ProviderError{Provider: p.Name(), StatusCode: res.StatusCode, Message: openAIErrorMessage(res.Body)}
+	}
+
+	return res, nil
+}
+
+// openAIErrorMessage returns the message from an error response body, or the body itself
+func openAIErrorMessage(body io.Reader) string {
+	data, _ := io.ReadAll(io.LimitReader(body, 4096))
+
+	var errorBody struct {
+		Error struct {
+			Message string `json:"message"`
+		} `json:"error"`
+	}
+	if err := json.Unmarshal(data, &errorBody); err == nil && errorBody.Error.Message != "" {
+		return errorBody.Error.Message
+	}
+
+	return strings.TrimSpace(string(data))
+}
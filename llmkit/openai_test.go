@@ -0,0 +1,100 @@
+package llmkit
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newOpenAITestServer(t *testing.T, handler func(w http.ResponseWriter, body map[string]any)) *OpenAIProvider {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/v1/chat/completions", r.URL.Path)
+		assert.Equal(t, "Bearer theAPIKey", r.Header.Get("Authorization"))
+		var body map[string]any
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		handler(w, body)
+	}))
+	t.Cleanup(server.Close)
+	return NewOpenAIProvider("theAPIKey", WithOpenAIBaseURL(server.URL+"/v1/"))
+}
+
+func TestOpenAIProviderComplete(t *testing.T) {
+	t.Run("sends_the_request_and_returns_the_completion", func(t *testing.T) {
+		var sent map[string]any
+		provider := newOpenAITestServer(t, func(w http.ResponseWriter, body map[string]any) {
+			sent = body
+			fmt.Fprint(w, `{"model":"theModel","choices":[{"message":{"role":"assistant","content":"theAnswer"},"finish_reason":"stop"}],"usage":{"prompt_tokens":3,"completion_tokens":5}}`)
+		})
+		theTemperature := 0.5
+		request := Request{Model: "theModel", Messages: []Message{{Role: RoleSystem, Content: "theInstructions"}, {Role: RoleUser, Content: "theQuestion"}}, MaxTokens: 100, Temperature: &theTemperature, JSON: true}
+
+		result, err := provider.Complete(context.Background(), request)
+
+		assert.NoError(t, err)
+		assert.Equal(t, &Response{Model: "theModel", Content: "theAnswer", FinishReason: "stop", Usage: Usage{InputTokens: 3, OutputTokens: 5}}, result)
+		assert.Equal(t, map[string]any{
+			"model":           "theModel",
+			"messages":        []any{map[string]any{"role": "system", "content": "theInstructions"}, map[string]any{"role": "user", "content": "theQuestion"}},
+			"max_tokens":      float64(100),
+			"temperature":     0.5,
+			"response_format": map[string]any{"type": "json_object"},
+		}, sent)
+	})
+
+	t.Run("returns_a_provider_error_for_an_error_response", func(t *testing.T) {
+		provider := newOpenAITestServer(t, func(w http.ResponseWriter, body map[string]any) {
+			w.WriteHeader(http.StatusTooManyRequests)
+			fmt.Fprint(w, `{"error":{"message":"theMessage"}}`)
+		})
+
+		_, err := provider.Complete(context.Background(), theRequest)
+
+		assert.Equal(t, &ProviderError{Provider: "openai", StatusCode: http.StatusTooManyRequests, Message: "theMessage"}, err)
+	})
+}
+
+func TestOpenAIProviderStream(t *testing.T) {
+	t.Run("yields_a_chunk_for_each_event", func(t *testing.T) {
+		var sent map[string]any
+		provider := newOpenAITestServer(t, func(w http.ResponseWriter, body map[string]any) {
+			sent = body
+			fmt.Fprint(w, "data: {\"choices\":[{\"delta\":{\"content\":\"the\"}}]}\n\n")
+			fmt.Fprint(w, ": keep-alive\n\n")
+			fmt.Fprint(w, "data: {\"choices\":[{\"delta\":{\"content\":\"Answer\"},\"finish_reason\":\"stop\"}]}\n\n")
+			fmt.Fprint(w, "data: {\"choices\":[],\"usage\":{\"prompt_tokens\":3,\"completion_tokens\":2}}\n\n")
+			fmt.Fprint(w, "data: [DONE]\n\n")
+		})
+
+		result := []Chunk{}
+		for chunk, err := range provider.Stream(context.Background(), theRequest) {
+			assert.NoError(t, err)
+			result = append(result, chunk)
+		}
+
+		assert.Equal(t, []Chunk{
+			{Content: "the"},
+			{Content: "Answer", FinishReason: "stop"},
+			{Usage: &Usage{InputTokens: 3, OutputTokens: 2}},
+		}, result)
+		assert.Equal(t, true, sent["stream"])
+		assert.Equal(t, map[string]any{"include_usage": true}, sent["stream_options"])
+	})
+
+	t.Run("yields_an_error_for_an_invalid_event", func(t *testing.T) {
+		provider := newOpenAITestServer(t, func(w http.ResponseWriter, body map[string]any) {
+			fmt.Fprint(w, "data: {not json}\n\n")
+		})
+
+		var streamErr error
+		for _, err := range provider.Stream(context.Background(), theRequest) {
+			streamErr = err
+		}
+
+		assert.ErrorContains(t, streamErr, "failed to decode openai stream event")
+	})
+}
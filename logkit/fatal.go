@@ -0,0 +1,91 @@
+package logkit
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"runtime/debug"
+	"sync"
+	"time"
+)
+
+// Flusher is a handler, such as ShippingHandler, that buffers records and must be flushed before exit
+type Flusher interface {
+	Flush(ctx context.Context) error
+}
+
+var fatalExitCode = 1
+var fatalFlushTimeout = 5 * time.Second
+var flushers []Flusher
+var fatalMu sync.Mutex
+
+// exit is replaced in tests
+var exit = os.Exit
+
+// SetFatalExitCode sets the code Fatal and RecoverAndExit exit with; the default is 1
+func SetFatalExitCode(code int) {
+	fatalMu.Lock()
+	defer fatalMu.Unlock()
+	fatalExitCode = code
+}
+
+// SetFatalFlushTimeout bounds how long Fatal and RecoverAndExit wait for flushers; the default is 5s
+func SetFatalFlushTimeout(timeout time.Duration) {
+	fatalMu.Lock()
+	defer fatalMu.Unlock()
+	fatalFlushTimeout = timeout
+}
+
+// RegisterFlusher adds a flusher to flush before exiting. The default logger's handler is flushed
+// without registering it if it is a Flusher.
+func RegisterFlusher(flusher Flusher) {
+	fatalMu.Lock()
+	defer fatalMu.Unlock()
+	flushers = append(flushers, flusher)
+}
+
+// Fatal logs err at ERROR with attrs and a stack trace, flushes buffered log records, and exits
+func Fatal(err error, attrs ...slog.Attr) {
+	attrs = append(attrs, slog.String("error", fmt.Sprint(err)), slog.String("stack", string(debug.Stack())))
+	logAndExit("fatal error", attrs)
+}
+
+// RecoverAndExit logs a panic at ERROR with a stack trace, flushes buffered log records, and exits.
+// Defer it at the top of main and of goroutines: defer logkit.RecoverAndExit()
+func RecoverAndExit() {
+	recovered := recover()
+	if recovered == nil {
+		return
+	}
+
+	attrs := []slog.Attr{slog.String("panic", fmt.Sprint(recovered)), slog.String("stack", string(debug.Stack()))}
+	if err, ok := recovered.(error); ok {
+		attrs = append(attrs, slog.String("error", err.Error()))
+	}
+	logAndExit("panic", attrs)
+}
+
+func logAndExit(msg string, attrs []slog.Attr) {
+	slog.LogAttrs(context.Background(), slog.LevelError, msg, attrs...)
+
+	fatalMu.Lock()
+	code := fatalExitCode
+	timeout := fatalFlushTimeout
+	toFlush := append([]Flusher{}, flushers...)
+	fatalMu.Unlock()
+
+	if flusher, ok := slog.Default().Handler().(Flusher); ok {
+		toFlush = append(toFlush, flusher)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	for _, flusher := range toFlush {
+		if err := flusher.Flush(ctx); err != nil {
+			fmt.Fprintf(os.Stderr, "failed to flush logs before exiting: %v\n", err)
+		}
+	}
+
+	exit(code)
+}
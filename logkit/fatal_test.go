@@ -0,0 +1,99 @@
+package logkit
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeFlusher struct {
+	flushed int
+}
+
+func (f *fakeFlusher) Flush(ctx context.Context) error {
+	f.flushed++
+	return nil
+}
+
+func useTestFatal(t *testing.T) (*bytes.Buffer, *int) {
+	var buf bytes.Buffer
+	previousLogger := slog.Default()
+	slog.SetDefault(slog.New(slog.NewJSONHandler(&buf, nil)))
+	previousExit := exit
+	actualCode := -1
+	exit = func(code int) { actualCode = code }
+	t.Cleanup(func() {
+		slog.SetDefault(previousLogger)
+		exit = previousExit
+		SetFatalExitCode(1)
+		SetFatalFlushTimeout(5 * time.Second)
+		flushers = nil
+	})
+	return &buf, &actualCode
+}
+
+func TestFatal(t *testing.T) {
+	t.Run("logs_the_error_with_a_stack_trace_and_exits", func(t *testing.T) {
+		buf, actualCode := useTestFatal(t)
+
+		Fatal(errors.New("the error"), slog.String("theKey", "theValue"))
+
+		assert.Equal(t, 1, *actualCode)
+		assert.Contains(t, buf.String(), `"level":"ERROR"`)
+		assert.Contains(t, buf.String(), `"msg":"fatal error"`)
+		assert.Contains(t, buf.String(), `"error":"the error"`)
+		assert.Contains(t, buf.String(), `"theKey":"theValue"`)
+		assert.Contains(t, buf.String(), `"stack":"goroutine`)
+	})
+
+	t.Run("flushes_registered_flushers_before_exiting", func(t *testing.T) {
+		_, _ = useTestFatal(t)
+		flusher := &fakeFlusher{}
+		RegisterFlusher(flusher)
+
+		Fatal(errors.New("the error"))
+
+		assert.Equal(t, 1, flusher.flushed)
+	})
+
+	t.Run("exits_with_the_configured_code", func(t *testing.T) {
+		_, actualCode := useTestFatal(t)
+		SetFatalExitCode(3)
+
+		Fatal(errors.New("the error"))
+
+		assert.Equal(t, 3, *actualCode)
+	})
+}
+
+func TestRecoverAndExit(t *testing.T) {
+	t.Run("logs_the_panic_with_a_stack_trace_and_exits", func(t *testing.T) {
+		buf, actualCode := useTestFatal(t)
+
+		func() {
+			defer RecoverAndExit()
+			panic("the panic")
+		}()
+
+		assert.Equal(t, 1, *actualCode)
+		assert.Contains(t, buf.String(), `"msg":"panic"`)
+		assert.Contains(t, buf.String(), `"panic":"the panic"`)
+		assert.Contains(t, buf.String(), `"stack":"goroutine`)
+	})
+
+	t.Run("does_nothing_without_a_panic", func(t *testing.T) {
+		buf, actualCode := useTestFatal(t)
+
+		func() {
+			defer RecoverAndExit()
+		}()
+
+		assert.Equal(t, -1, *actualCode)
+		assert.Empty(t, buf.String())
+	})
+}
@@ -0,0 +1,53 @@
+// Package logfields names the log attributes shared across the kit, so every package logs the same
+// thing under the same key
+package logfields
+
+import (
+	"log/slog"
+	"time"
+)
+
+const (
+	RequestIDKey = "request_id"
+	TraceIDKey   = "trace_id"
+	UserSubKey   = "user_sub"
+	TenantKey    = "tenant_id"
+	TableKey     = "table"
+	MigrationKey = "migration"
+	DurationKey  = "duration"
+)
+
+// RequestID is the ID of the request being handled, e.g. from X-Request-ID
+func RequestID(id string) slog.Attr {
+	return slog.String(RequestIDKey, id)
+}
+
+// TraceID is the distributed trace the log belongs to, e.g. from X-Amzn-Trace-Id
+func TraceID(id string) slog.Attr {
+	return slog.String(TraceIDKey, id)
+}
+
+// UserSub is the subject claim of the authenticated user
+func UserSub(sub string) slog.Attr {
+	return slog.String(UserSubKey, sub)
+}
+
+// Tenant is the ID of the tenant the work is for
+func Tenant(id string) slog.Attr {
+	return slog.String(TenantKey, id)
+}
+
+// Table is a database table name
+func Table(name string) slog.Attr {
+	return slog.String(TableKey, name)
+}
+
+// Migration is a migration's file name
+func Migration(name string) slog.Attr {
+	return slog.String(MigrationKey, name)
+}
+
+// Duration is how long an operation took
+func Duration(d time.Duration) slog.Attr {
+	return slog.Duration(DurationKey, d)
+}
@@ -0,0 +1,21 @@
+package logfields
+
+import (
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConstructors(t *testing.T) {
+	t.Run("use_the_shared_keys", func(t *testing.T) {
+		assert.Equal(t, slog.String("request_id", "theRequestID"), RequestID("theRequestID"))
+		assert.Equal(t, slog.String("trace_id", "theTraceID"), TraceID("theTraceID"))
+		assert.Equal(t, slog.String("user_sub", "theSub"), UserSub("theSub"))
+		assert.Equal(t, slog.String("tenant_id", "theTenant"), Tenant("theTenant"))
+		assert.Equal(t, slog.String("table", "theTable"), Table("theTable"))
+		assert.Equal(t, slog.String("migration", "theMigration"), Migration("theMigration"))
+		assert.Equal(t, slog.Duration("duration", time.Second), Duration(time.Second))
+	})
+}
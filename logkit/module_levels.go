@@ -0,0 +1,176 @@
+package logkit
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// LevelSpec is a default level plus per-module overrides, e.g. parsed from LOG_LEVEL=info,dynamodbkit=debug,echokit=warn
+type LevelSpec struct {
+	Default slog.Level
+	Modules map[string]slog.Level
+}
+
+// ParseLevelSpec parses a comma-separated level spec. A bare level sets the default (INFO if omitted)
+// and module=level entries override it for loggers created with Logger(module).
+func ParseLevelSpec(spec string) (LevelSpec, error) {
+	result := LevelSpec{Default: slog.LevelInfo, Modules: map[string]slog.Level{}}
+
+	for _, part := range strings.Split(spec, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+
+		module, levelText, hasModule := strings.Cut(part, "=")
+		if !hasModule {
+			levelText = module
+		}
+
+		var level slog.Level
+		if err := level.UnmarshalText([]byte(strings.TrimSpace(levelText))); err != nil {
+			return LevelSpec{}, fmt.Errorf("invalid log level %q in %q", levelText, spec)
+		}
+
+		if !hasModule {
+			result.Default = level
+			continue
+		}
+
+		module = strings.TrimSpace(module)
+		if module == "" {
+			return LevelSpec{}, fmt.Errorf("missing module name in %q", part)
+		}
+		result.Modules[module] = level
+	}
+
+	return result, nil
+}
+
+// String formats the spec the way ParseLevelSpec reads it, with modules in name order
+func (spec LevelSpec) String() string {
+	parts := []string{strings.ToLower(spec.Default.String())}
+
+	modules := make([]string, 0, len(spec.Modules))
+	for module := range spec.Modules {
+		modules = append(modules, module)
+	}
+	sort.Strings(modules)
+
+	for _, module := range modules {
+		parts = append(parts, module+"="+strings.ToLower(spec.Modules[module].String()))
+	}
+	return strings.Join(parts, ",")
+}
+
+// LevelEnvVar is the environment variable SetDefaultLoggerFromEnv reads the level spec from
+const LevelEnvVar = "LOG_LEVEL"
+
+// SetDefaultLoggerFromEnv sets the default logger (see SetDefaultLogger) and the module levels from the
+// level spec in LOG_LEVEL, e.g. LOG_LEVEL=info,dynamodbkit=debug. An unset LOG_LEVEL means INFO.
+func SetDefaultLoggerFromEnv() (*slog.Logger, *slog.LevelVar, error) {
+	spec, err := ParseLevelSpec(os.Getenv(LevelEnvVar))
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid %s: %w", LevelEnvVar, err)
+	}
+
+	logger, levelVar := SetDefaultLogger(spec.Default)
+	SetLevelSpec(spec)
+	return logger, levelVar, nil
+}
+
+var moduleLevels *LevelSpec
+var moduleLevelsMu sync.RWMutex
+
+// GetLevelSpec returns the spec last passed to SetLevelSpec, or false if it has not been called
+func GetLevelSpec() (LevelSpec, bool) {
+	moduleLevelsMu.RLock()
+	defer moduleLevelsMu.RUnlock()
+
+	if moduleLevels == nil {
+		return LevelSpec{}, false
+	}
+
+	modules := make(map[string]slog.Level, len(moduleLevels.Modules))
+	for module, level := range moduleLevels.Modules {
+		modules[module] = level
+	}
+	return LevelSpec{Default: moduleLevels.Default, Modules: modules}, true
+}
+
+// SetLevelSpec sets the levels used by loggers created with Logger. Until it is called, those loggers
+// use whatever level the default logger's handler is enabled for.
+func SetLevelSpec(spec LevelSpec) {
+	modules := make(map[string]slog.Level, len(spec.Modules))
+	for module, level := range spec.Modules {
+		modules[module] = level
+	}
+
+	moduleLevelsMu.Lock()
+	defer moduleLevelsMu.Unlock()
+	moduleLevels = &LevelSpec{Default: spec.Default, Modules: modules}
+}
+
+func moduleLevel(module string) (slog.Level, bool) {
+	moduleLevelsMu.RLock()
+	defer moduleLevelsMu.RUnlock()
+
+	if moduleLevels == nil {
+		return 0, false
+	}
+	if level, ok := moduleLevels.Modules[module]; ok {
+		return level, true
+	}
+	return moduleLevels.Default, true
+}
+
+// Logger returns a logger for a module (e.g. "dynamodbkit") that writes through the current default
+// logger's handler, tagging records with the module name, but filters by the module's level from
+// SetLevelSpec. This lets operators turn up verbosity for one subsystem at a time.
+func Logger(module string) *slog.Logger {
+	return slog.New(&moduleHandler{module: module})
+}
+
+// moduleHandler resolves the default handler on every record, so loggers created at package init
+// still write through a default logger set later by the application
+type moduleHandler struct {
+	module string
+	with   []func(slog.Handler) slog.Handler
+}
+
+func (h *moduleHandler) handler() slog.Handler {
+	handler := slog.Default().Handler().WithAttrs([]slog.Attr{slog.String("module", h.module)})
+	for _, with := range h.with {
+		handler = with(handler)
+	}
+	return handler
+}
+
+func (h *moduleHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	if minLevel, ok := moduleLevel(h.module); ok {
+		return level >= minLevel
+	}
+	return slog.Default().Handler().Enabled(ctx, level)
+}
+
+// Handle skips the wrapped handler's own level check, which the module level has already replaced
+func (h *moduleHandler) Handle(ctx context.Context, record slog.Record) error {
+	return h.handler().Handle(ctx, record)
+}
+
+func (h *moduleHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return h.withHandler(func(handler slog.Handler) slog.Handler { return handler.WithAttrs(attrs) })
+}
+
+func (h *moduleHandler) WithGroup(name string) slog.Handler {
+	return h.withHandler(func(handler slog.Handler) slog.Handler { return handler.WithGroup(name) })
+}
+
+func (h *moduleHandler) withHandler(with func(slog.Handler) slog.Handler) slog.Handler {
+	return &moduleHandler{module: h.module, with: append(append([]func(slog.Handler) slog.Handler{}, h.with...), with)}
+}
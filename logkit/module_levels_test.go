@@ -0,0 +1,176 @@
+package logkit
+
+import (
+	"bytes"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseLevelSpec(t *testing.T) {
+	t.Run("parses_a_default_level_and_module_overrides", func(t *testing.T) {
+		result, err := ParseLevelSpec("info,dynamodbkit=debug, echokit = warn")
+
+		assert.NoError(t, err)
+		assert.Equal(t, slog.LevelInfo, result.Default)
+		assert.Equal(t, map[string]slog.Level{"dynamodbkit": slog.LevelDebug, "echokit": slog.LevelWarn}, result.Modules)
+	})
+
+	t.Run("defaults_to_info_when_there_is_no_bare_level", func(t *testing.T) {
+		result, err := ParseLevelSpec("dynamodbkit=debug")
+
+		assert.NoError(t, err)
+		assert.Equal(t, slog.LevelInfo, result.Default)
+	})
+
+	t.Run("parses_an_empty_spec", func(t *testing.T) {
+		result, err := ParseLevelSpec("")
+
+		assert.NoError(t, err)
+		assert.Equal(t, slog.LevelInfo, result.Default)
+		assert.Empty(t, result.Modules)
+	})
+
+	t.Run("returns_an_error_for_an_invalid_level", func(t *testing.T) {
+		_, err := ParseLevelSpec("info,dynamodbkit=loud")
+
+		assert.EqualError(t, err, `invalid log level "loud" in "info,dynamodbkit=loud"`)
+	})
+
+	t.Run("returns_an_error_for_a_missing_module_name", func(t *testing.T) {
+		_, err := ParseLevelSpec("=debug")
+
+		assert.EqualError(t, err, `missing module name in "=debug"`)
+	})
+}
+
+func TestLevelSpecString(t *testing.T) {
+	t.Run("formats_the_default_and_modules_in_name_order", func(t *testing.T) {
+		spec := LevelSpec{Default: slog.LevelWarn, Modules: map[string]slog.Level{"pgkit": slog.LevelInfo, "dynamodbkit": slog.LevelDebug}}
+
+		assert.Equal(t, "warn,dynamodbkit=debug,pgkit=info", spec.String())
+	})
+
+	t.Run("round_trips_through_parse_level_spec", func(t *testing.T) {
+		spec, err := ParseLevelSpec("error,echokit=debug")
+		assert.NoError(t, err)
+
+		result, err := ParseLevelSpec(spec.String())
+
+		assert.NoError(t, err)
+		assert.Equal(t, spec, result)
+	})
+}
+
+func TestGetLevelSpec(t *testing.T) {
+	t.Run("returns_false_before_a_spec_is_set", func(t *testing.T) {
+		setModuleLevelTestLogger(t, slog.LevelInfo)
+
+		_, ok := GetLevelSpec()
+
+		assert.False(t, ok)
+	})
+
+	t.Run("returns_the_spec_last_set", func(t *testing.T) {
+		setModuleLevelTestLogger(t, slog.LevelInfo)
+		SetLevelSpec(LevelSpec{Default: slog.LevelWarn, Modules: map[string]slog.Level{"pgkit": slog.LevelDebug}})
+
+		result, ok := GetLevelSpec()
+
+		assert.True(t, ok)
+		assert.Equal(t, LevelSpec{Default: slog.LevelWarn, Modules: map[string]slog.Level{"pgkit": slog.LevelDebug}}, result)
+	})
+}
+
+func setModuleLevelTestLogger(t *testing.T, level slog.Level) *bytes.Buffer {
+	var logBuf bytes.Buffer
+	previous := slog.Default()
+	slog.SetDefault(slog.New(slog.NewJSONHandler(&logBuf, &slog.HandlerOptions{Level: level})))
+	t.Cleanup(func() {
+		slog.SetDefault(previous)
+		moduleLevelsMu.Lock()
+		moduleLevels = nil
+		moduleLevelsMu.Unlock()
+	})
+	return &logBuf
+}
+
+func TestLogger(t *testing.T) {
+	t.Run("logs_debug_for_a_module_set_to_debug_even_when_the_default_is_info", func(t *testing.T) {
+		logBuf := setModuleLevelTestLogger(t, slog.LevelInfo)
+		SetLevelSpec(LevelSpec{Default: slog.LevelInfo, Modules: map[string]slog.Level{"theModule": slog.LevelDebug}})
+
+		Logger("theModule").Debug("theDebugMessage")
+		Logger("anotherModule").Debug("anotherDebugMessage")
+
+		assert.Contains(t, logBuf.String(), "theDebugMessage")
+		assert.Contains(t, logBuf.String(), `"module":"theModule"`)
+		assert.NotContains(t, logBuf.String(), "anotherDebugMessage")
+	})
+
+	t.Run("filters_below_a_module_level", func(t *testing.T) {
+		logBuf := setModuleLevelTestLogger(t, slog.LevelDebug)
+		SetLevelSpec(LevelSpec{Default: slog.LevelDebug, Modules: map[string]slog.Level{"theModule": slog.LevelWarn}})
+
+		Logger("theModule").Info("theInfoMessage")
+		Logger("theModule").Warn("theWarnMessage")
+
+		assert.NotContains(t, logBuf.String(), "theInfoMessage")
+		assert.Contains(t, logBuf.String(), "theWarnMessage")
+	})
+
+	t.Run("uses_the_default_handler_level_when_no_spec_is_set", func(t *testing.T) {
+		logBuf := setModuleLevelTestLogger(t, slog.LevelInfo)
+
+		Logger("theModule").Debug("theDebugMessage")
+		Logger("theModule").Info("theInfoMessage")
+
+		assert.NotContains(t, logBuf.String(), "theDebugMessage")
+		assert.Contains(t, logBuf.String(), "theInfoMessage")
+	})
+
+	t.Run("writes_through_a_default_logger_set_after_the_logger_was_created", func(t *testing.T) {
+		logger := Logger("theModule").With("theKey", "theValue")
+		logBuf := setModuleLevelTestLogger(t, slog.LevelInfo)
+
+		logger.Info("theMessage")
+
+		assert.Contains(t, logBuf.String(), "theMessage")
+		assert.Contains(t, logBuf.String(), `"theKey":"theValue"`)
+	})
+}
+
+func TestSetDefaultLoggerFromEnv(t *testing.T) {
+	t.Run("sets_the_default_level_and_module_levels_from_log_level", func(t *testing.T) {
+		setModuleLevelTestLogger(t, slog.LevelInfo)
+		t.Setenv("LOG_LEVEL", "warn,dynamodbkit=debug")
+
+		_, levelVar, err := SetDefaultLoggerFromEnv()
+
+		assert.NoError(t, err)
+		assert.Equal(t, slog.LevelWarn, levelVar.Level())
+		result, ok := GetLevelSpec()
+		assert.True(t, ok)
+		assert.Equal(t, LevelSpec{Default: slog.LevelWarn, Modules: map[string]slog.Level{"dynamodbkit": slog.LevelDebug}}, result)
+	})
+
+	t.Run("defaults_to_info_when_log_level_is_unset", func(t *testing.T) {
+		setModuleLevelTestLogger(t, slog.LevelInfo)
+		t.Setenv("LOG_LEVEL", "")
+
+		_, levelVar, err := SetDefaultLoggerFromEnv()
+
+		assert.NoError(t, err)
+		assert.Equal(t, slog.LevelInfo, levelVar.Level())
+	})
+
+	t.Run("returns_an_error_for_an_invalid_log_level", func(t *testing.T) {
+		setModuleLevelTestLogger(t, slog.LevelInfo)
+		t.Setenv("LOG_LEVEL", "loud")
+
+		_, _, err := SetDefaultLoggerFromEnv()
+
+		assert.EqualError(t, err, `invalid LOG_LEVEL: invalid log level "loud" in "loud"`)
+	})
+}
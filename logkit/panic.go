@@ -0,0 +1,67 @@
+package logkit
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"runtime/debug"
+
+	"github.com/half-ogre/go-kit/logkit/logfields"
+)
+
+// PanicError is a recovered panic with the stack of the goroutine where it happened. Recovery
+// middleware in echokit and ginkit log one with LogPanic so panics have the same fields on either
+// framework.
+type PanicError struct {
+	// Value is the value passed to panic
+	Value any
+	// Stack is the goroutine's stack when the panic was first captured
+	Stack []byte
+}
+
+func (e *PanicError) Error() string {
+	return fmt.Sprint(e.Value)
+}
+
+// Unwrap returns the panic value if it is an error
+func (e *PanicError) Unwrap() error {
+	err, _ := e.Value.(error)
+	return err
+}
+
+// CapturePanic returns recovered, the result of recover(), as a *PanicError with the current stack. A
+// recovered *PanicError is returned as is, keeping the stack from where it was first captured.
+func CapturePanic(recovered any) *PanicError {
+	if panicErr, ok := recovered.(*PanicError); ok {
+		return panicErr
+	}
+	return &PanicError{Value: recovered, Stack: debug.Stack()}
+}
+
+// CaptureAndRepanic re-panics with a *PanicError holding the panic's stack, so recovery further out
+// logs where the panic happened rather than where it was re-panicked. Defer it in layers that can't
+// handle a panic themselves: defer logkit.CaptureAndRepanic()
+func CaptureAndRepanic() {
+	if recovered := recover(); recovered != nil {
+		panic(CapturePanic(recovered))
+	}
+}
+
+// LogPanic logs a captured panic at ERROR as "panic recovered" with its error and stack, and
+// attrs, e.g. from PanicRequestAttrs
+func LogPanic(ctx context.Context, panicErr *PanicError, attrs ...slog.Attr) {
+	attrs = append([]slog.Attr{
+		slog.String("error", panicErr.Error()),
+		slog.String("stack", string(panicErr.Stack)),
+	}, attrs...)
+	slog.LogAttrs(ctx, slog.LevelError, "panic recovered", attrs...)
+}
+
+// PanicRequestAttrs returns the request fields logged with a panic in an HTTP handler
+func PanicRequestAttrs(method string, uri string, requestID string) []slog.Attr {
+	attrs := []slog.Attr{slog.String("method", method), slog.String("uri", uri)}
+	if requestID != "" {
+		attrs = append(attrs, logfields.RequestID(requestID))
+	}
+	return attrs
+}
@@ -0,0 +1,98 @@
+package logkit
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func useTestPanicLogger(t *testing.T) *bytes.Buffer {
+	var buf bytes.Buffer
+	previous := slog.Default()
+	slog.SetDefault(slog.New(slog.NewJSONHandler(&buf, nil)))
+	t.Cleanup(func() { slog.SetDefault(previous) })
+	return &buf
+}
+
+func panicInner() {
+	panic("thePanic")
+}
+
+func TestCapturePanic(t *testing.T) {
+	t.Run("captures_the_value_and_stack", func(t *testing.T) {
+		var panicErr *PanicError
+		func() {
+			defer func() { panicErr = CapturePanic(recover()) }()
+			panicInner()
+		}()
+
+		assert.Equal(t, "thePanic", panicErr.Value)
+		assert.Equal(t, "thePanic", panicErr.Error())
+		assert.Contains(t, string(panicErr.Stack), "panicInner")
+	})
+
+	t.Run("keeps_a_captured_panic", func(t *testing.T) {
+		captured := &PanicError{Value: "thePanic", Stack: []byte("theStack")}
+
+		assert.Same(t, captured, CapturePanic(captured))
+	})
+
+	t.Run("unwraps_an_error_value", func(t *testing.T) {
+		theError := errors.New("theError")
+
+		assert.ErrorIs(t, CapturePanic(theError), theError)
+		assert.Nil(t, CapturePanic("thePanic").Unwrap())
+	})
+}
+
+func TestCaptureAndRepanic(t *testing.T) {
+	t.Run("repanics_with_the_stack_where_the_panic_happened", func(t *testing.T) {
+		var recovered any
+		func() {
+			defer func() { recovered = recover() }()
+			func() {
+				defer CaptureAndRepanic()
+				panicInner()
+			}()
+		}()
+
+		panicErr, ok := recovered.(*PanicError)
+		assert.True(t, ok)
+		assert.Equal(t, "thePanic", panicErr.Value)
+		assert.Contains(t, string(panicErr.Stack), "panicInner")
+	})
+
+	t.Run("does_nothing_without_a_panic", func(t *testing.T) {
+		assert.NotPanics(t, func() {
+			defer CaptureAndRepanic()
+		})
+	})
+}
+
+func TestLogPanic(t *testing.T) {
+	t.Run("logs_the_panic_and_request_fields", func(t *testing.T) {
+		buf := useTestPanicLogger(t)
+
+		LogPanic(context.Background(), &PanicError{Value: errors.New("theError"), Stack: []byte("theStack")}, PanicRequestAttrs("GET", "/things?id=1", "theRequestID")...)
+
+		assert.Contains(t, buf.String(), `"level":"ERROR"`)
+		assert.Contains(t, buf.String(), `"msg":"panic recovered"`)
+		assert.Contains(t, buf.String(), `"error":"theError"`)
+		assert.Contains(t, buf.String(), `"stack":"theStack"`)
+		assert.Contains(t, buf.String(), `"method":"GET"`)
+		assert.Contains(t, buf.String(), `"uri":"/things?id=1"`)
+		assert.Contains(t, buf.String(), `"request_id":"theRequestID"`)
+	})
+
+	t.Run("omits_an_empty_request_id", func(t *testing.T) {
+		buf := useTestPanicLogger(t)
+
+		LogPanic(context.Background(), &PanicError{Value: "thePanic"}, PanicRequestAttrs("GET", "/things", "")...)
+
+		assert.NotContains(t, buf.String(), `"request_id"`)
+	})
+}
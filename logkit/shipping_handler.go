@@ -0,0 +1,744 @@
+package logkit
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/half-ogre/go-kit/filekit"
+)
+
+// ShippingFormat is the payload format a ShippingHandler posts
+type ShippingFormat int
+
+const (
+	// ShippingFormatJSON posts a JSON array of records, for generic HTTP log endpoints
+	ShippingFormatJSON ShippingFormat = iota
+	// ShippingFormatOTLP posts an OTLP/HTTP JSON logs export request, for OpenTelemetry collectors
+	ShippingFormatOTLP
+)
+
+// ShippingHandlerOption configures a ShippingHandler
+type ShippingHandlerOption func(*shippingHandlerConfig)
+
+type shippingHandlerConfig struct {
+	format        ShippingFormat
+	client        *http.Client
+	headers       map[string]string
+	level         slog.Leveler
+	batchSize     int
+	flushInterval time.Duration
+	maxRetries    int
+	retryBackoff  time.Duration
+	maxBuffered   int
+	overflowDir   string
+	maxOverflow   int
+	serviceName   string
+	onError       func(error)
+}
+
+// WithShippingFormat sets the payload format; the default is ShippingFormatJSON
+func WithShippingFormat(format ShippingFormat) ShippingHandlerOption {
+	return func(c *shippingHandlerConfig) {
+		c.format = format
+	}
+}
+
+// WithShippingHTTPClient sets the client used to post batches
+func WithShippingHTTPClient(client *http.Client) ShippingHandlerOption {
+	return func(c *shippingHandlerConfig) {
+		c.client = client
+	}
+}
+
+// WithShippingHeader adds a header to every request, e.g. an API key
+func WithShippingHeader(key, value string) ShippingHandlerOption {
+	return func(c *shippingHandlerConfig) {
+		c.headers[key] = value
+	}
+}
+
+// WithShippingLevel sets the minimum level shipped; the default is INFO
+func WithShippingLevel(level slog.Leveler) ShippingHandlerOption {
+	return func(c *shippingHandlerConfig) {
+		c.level = level
+	}
+}
+
+// WithShippingBatchSize sets how many records are buffered before a batch is posted; the default is 100
+func WithShippingBatchSize(size int) ShippingHandlerOption {
+	return func(c *shippingHandlerConfig) {
+		c.batchSize = size
+	}
+}
+
+// WithShippingFlushInterval sets how often buffered records are posted regardless of batch size; the default is 5s
+func WithShippingFlushInterval(interval time.Duration) ShippingHandlerOption {
+	return func(c *shippingHandlerConfig) {
+		c.flushInterval = interval
+	}
+}
+
+// WithShippingRetries sets how many times a failed post is retried and the initial backoff between
+// attempts, which doubles after each retry; the default is 3 retries starting at 500ms
+func WithShippingRetries(maxRetries int, backoff time.Duration) ShippingHandlerOption {
+	return func(c *shippingHandlerConfig) {
+		c.maxRetries = maxRetries
+		c.retryBackoff = backoff
+	}
+}
+
+// WithShippingMaxBuffered sets how many records may be held in memory while the endpoint is unreachable;
+// the default is 10000. Records beyond the limit are spilled to the overflow directory or dropped.
+func WithShippingMaxBuffered(max int) ShippingHandlerOption {
+	return func(c *shippingHandlerConfig) {
+		c.maxBuffered = max
+	}
+}
+
+// WithShippingOverflowDir sets a directory where batches that could not be posted are written,
+// to be posted once the endpoint is reachable again. Without one, such batches are dropped.
+func WithShippingOverflowDir(dir string) ShippingHandlerOption {
+	return func(c *shippingHandlerConfig) {
+		c.overflowDir = dir
+	}
+}
+
+// WithShippingMaxOverflowFiles sets how many batches the overflow directory may hold; the default is
+// 1000. When it's full, the oldest batches are dropped to make room.
+func WithShippingMaxOverflowFiles(max int) ShippingHandlerOption {
+	return func(c *shippingHandlerConfig) {
+		c.maxOverflow = max
+	}
+}
+
+// WithShippingServiceName sets the service.name resource attribute of OTLP payloads
+func WithShippingServiceName(name string) ShippingHandlerOption {
+	return func(c *shippingHandlerConfig) {
+		c.serviceName = name
+	}
+}
+
+// WithShippingErrorHandler sets the function called when a batch cannot be shipped. The default writes
+// to stderr, since logging through slog could loop back into the handler.
+func WithShippingErrorHandler(onError func(error)) ShippingHandlerOption {
+	return func(c *shippingHandlerConfig) {
+		c.onError = onError
+	}
+}
+
+// ShippingHandler is a slog.Handler that batches records and posts them, gzipped, to an HTTP endpoint,
+// for environments without a log agent sidecar. Close must be called on shutdown to flush buffered records;
+// records handled after Close are written to the overflow directory, or dropped.
+type ShippingHandler struct {
+	core   *shippingCore
+	attrs  []slog.Attr
+	groups []string
+}
+
+type shippedRecord struct {
+	Time    time.Time      `json:"time"`
+	Level   string         `json:"level"`
+	Message string         `json:"msg"`
+	Attrs   map[string]any `json:"attrs,omitempty"`
+}
+
+type shippingCore struct {
+	endpoint string
+	config   shippingHandlerConfig
+
+	mu        sync.Mutex
+	buffer    []shippedRecord
+	sendMu    sync.Mutex
+	flushReq  chan struct{}
+	done      chan struct{}
+	stopped   chan struct{}
+	closed    bool
+	runCtx    context.Context
+	cancelRun context.CancelFunc
+
+	overflowMu  sync.Mutex
+	overflowSeq atomic.Uint64
+	pending     []shippedRecord // records appended to the newest overflow file, up to a batch
+	pendingPath string
+}
+
+// NewShippingHandler creates a ShippingHandler posting to endpoint and starts its background flushing
+func NewShippingHandler(endpoint string, options ...ShippingHandlerOption) *ShippingHandler {
+	config := shippingHandlerConfig{
+		format:        ShippingFormatJSON,
+		client:        &http.Client{Timeout: 10 * time.Second},
+		headers:       map[string]string{},
+		level:         slog.LevelInfo,
+		batchSize:     100,
+		flushInterval: 5 * time.Second,
+		maxRetries:    3,
+		retryBackoff:  500 * time.Millisecond,
+		maxBuffered:   10000,
+		maxOverflow:   1000,
+		onError: func(err error) {
+			fmt.Fprintf(os.Stderr, "logkit: %v\n", err)
+		},
+	}
+	for _, option := range options {
+		option(&config)
+	}
+
+	runCtx, cancelRun := context.WithCancel(context.Background())
+	core := &shippingCore{
+		endpoint:  endpoint,
+		config:    config,
+		flushReq:  make(chan struct{}, 1),
+		done:      make(chan struct{}),
+		stopped:   make(chan struct{}),
+		runCtx:    runCtx,
+		cancelRun: cancelRun,
+	}
+	go core.run()
+
+	return &ShippingHandler{core: core}
+}
+
+func (h *ShippingHandler) Enabled(_ context.Context, level slog.Level) bool {
+	return level >= h.core.config.level.Level()
+}
+
+func (h *ShippingHandler) Handle(_ context.Context, record slog.Record) error {
+	attrs := make(map[string]any)
+	for _, attr := range h.attrs {
+		addShippedAttr(attrs, "", attr)
+	}
+	prefix := groupPrefix(h.groups)
+	record.Attrs(func(attr slog.Attr) bool {
+		addShippedAttr(attrs, prefix, attr)
+		return true
+	})
+	if len(attrs) == 0 {
+		attrs = nil
+	}
+
+	h.core.add(shippedRecord{
+		Time:    record.Time,
+		Level:   record.Level.String(),
+		Message: record.Message,
+		Attrs:   attrs,
+	})
+
+	return nil
+}
+
+func (h *ShippingHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	prefix := groupPrefix(h.groups)
+	newAttrs := append([]slog.Attr{}, h.attrs...)
+	for _, attr := range attrs {
+		newAttrs = append(newAttrs, slog.Attr{Key: prefix + attr.Key, Value: attr.Value})
+	}
+	return &ShippingHandler{core: h.core, attrs: newAttrs, groups: h.groups}
+}
+
+func (h *ShippingHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	return &ShippingHandler{core: h.core, attrs: h.attrs, groups: append(append([]string{}, h.groups...), name)}
+}
+
+// Flush posts all buffered records, and any overflow batches, now
+func (h *ShippingHandler) Flush(ctx context.Context) error {
+	return h.core.flush(ctx)
+}
+
+// Close stops background flushing and posts any buffered records. Records that can't be posted before
+// ctx is done are written to the overflow directory, or dropped.
+func (h *ShippingHandler) Close(ctx context.Context) error {
+	h.core.mu.Lock()
+	if h.core.closed {
+		h.core.mu.Unlock()
+		return nil
+	}
+	h.core.closed = true
+	h.core.mu.Unlock()
+
+	close(h.core.done)
+	select {
+	case <-h.core.stopped:
+	case <-ctx.Done():
+		// Abandon a background flush stuck posting or backing off; its batch goes to the overflow
+		h.core.cancelRun()
+		<-h.core.stopped
+	}
+	h.core.cancelRun()
+
+	err := h.core.flush(ctx)
+	if err != nil {
+		h.core.mu.Lock()
+		remaining := h.core.buffer
+		h.core.buffer = nil
+		h.core.mu.Unlock()
+		if len(remaining) > 0 {
+			h.core.overflow(remaining, err)
+		}
+	}
+	return err
+}
+
+func groupPrefix(groups []string) string {
+	prefix := ""
+	for _, group := range groups {
+		prefix += group + "."
+	}
+	return prefix
+}
+
+func addShippedAttr(attrs map[string]any, prefix string, attr slog.Attr) {
+	value := attr.Value.Resolve()
+	if value.Kind() == slog.KindGroup {
+		groupAttrs := value.Group()
+		if attr.Key != "" {
+			prefix += attr.Key + "."
+		}
+		for _, groupAttr := range groupAttrs {
+			addShippedAttr(attrs, prefix, groupAttr)
+		}
+		return
+	}
+	if attr.Key == "" {
+		return
+	}
+
+	switch value.Kind() {
+	case slog.KindTime:
+		attrs[prefix+attr.Key] = value.Time().Format(time.RFC3339Nano)
+	case slog.KindDuration:
+		attrs[prefix+attr.Key] = value.Duration().String()
+	case slog.KindAny:
+		if err, ok := value.Any().(error); ok {
+			attrs[prefix+attr.Key] = err.Error()
+		} else {
+			attrs[prefix+attr.Key] = value.Any()
+		}
+	default:
+		attrs[prefix+attr.Key] = value.Any()
+	}
+}
+
+func (c *shippingCore) add(record shippedRecord) {
+	c.mu.Lock()
+	if c.closed {
+		c.mu.Unlock()
+		c.appendOverflow([]shippedRecord{record}, errors.New("log shipping handler is closed"))
+		return
+	}
+	c.buffer = append(c.buffer, record)
+	var spilled []shippedRecord
+	if excess := len(c.buffer) - c.config.maxBuffered; excess > 0 {
+		// The oldest records are spilled, since the overflow is replayed before the buffer
+		spilled = append([]shippedRecord(nil), c.buffer[:excess]...)
+		c.buffer = c.buffer[excess:]
+	}
+	full := len(c.buffer) >= c.config.batchSize
+	c.mu.Unlock()
+
+	if spilled != nil {
+		c.appendOverflow(spilled, fmt.Errorf("log buffer exceeded %d records", c.config.maxBuffered))
+	}
+
+	if full {
+		select {
+		case c.flushReq <- struct{}{}:
+		default:
+		}
+	}
+}
+
+func (c *shippingCore) run() {
+	defer close(c.stopped)
+
+	ticker := time.NewTicker(c.config.flushInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-c.done:
+			return
+		case <-ticker.C:
+		case <-c.flushReq:
+		}
+
+		if err := c.flush(c.runCtx); err != nil {
+			c.config.onError(err)
+		}
+	}
+}
+
+func (c *shippingCore) flush(ctx context.Context) error {
+	// Only one flush sends at a time so overflow batches are replayed in order
+	c.sendMu.Lock()
+	defer c.sendMu.Unlock()
+
+	// Records appended to the overflow from now on start a new file, so the replay below can remove the
+	// newest one without losing them
+	c.sealOverflow()
+
+	// Overflow batches are older than anything buffered, so they go first; while they can't be posted,
+	// buffered records join them to keep the order
+	if err := c.replayOverflow(ctx); err != nil {
+		c.mu.Lock()
+		buffered := c.buffer
+		c.buffer = nil
+		c.mu.Unlock()
+		if len(buffered) > 0 {
+			c.overflow(buffered, err)
+		}
+		return err
+	}
+
+	for {
+		c.mu.Lock()
+		n := min(len(c.buffer), c.config.batchSize)
+		batch := c.buffer[:n:n]
+		c.buffer = c.buffer[n:]
+		c.mu.Unlock()
+
+		if len(batch) == 0 {
+			break
+		}
+
+		if err := c.send(ctx, batch); err != nil {
+			c.overflow(batch, err)
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (c *shippingCore) send(ctx context.Context, batch []shippedRecord) error {
+	payload, err := c.encode(batch)
+	if err != nil {
+		return err
+	}
+
+	var compressed bytes.Buffer
+	gz := gzip.NewWriter(&compressed)
+	if _, err := gz.Write(payload); err != nil {
+		return fmt.Errorf("failed to compress log batch: %w", err)
+	}
+	if err := gz.Close(); err != nil {
+		return fmt.Errorf("failed to compress log batch: %w", err)
+	}
+
+	backoff := c.config.retryBackoff
+	for attempt := 0; ; attempt++ {
+		err = c.post(ctx, compressed.Bytes())
+		if err == nil || attempt >= c.config.maxRetries {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+	}
+}
+
+func (c *shippingCore) post(ctx context.Context, body []byte) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create log shipping request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Content-Encoding", "gzip")
+	for key, value := range c.config.headers {
+		req.Header.Set(key, value)
+	}
+
+	res, err := c.config.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to post logs to %s: %w", c.endpoint, err)
+	}
+	defer res.Body.Close()
+	_, _ = io.Copy(io.Discard, res.Body)
+
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		return fmt.Errorf("failed to post logs to %s: status %d", c.endpoint, res.StatusCode)
+	}
+
+	return nil
+}
+
+func (c *shippingCore) encode(batch []shippedRecord) ([]byte, error) {
+	var payload any = batch
+	if c.config.format == ShippingFormatOTLP {
+		payload = toOTLPLogs(batch, c.config.serviceName)
+	}
+
+	encoded, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal log batch: %w", err)
+	}
+	return encoded, nil
+}
+
+// overflow writes a batch that could not be shipped to its own overflow file, or drops it if there is
+// no overflow directory
+func (c *shippingCore) overflow(batch []shippedRecord, cause error) {
+	if c.config.overflowDir == "" {
+		c.config.onError(fmt.Errorf("dropped %d log records: %w", len(batch), cause))
+		return
+	}
+
+	c.overflowMu.Lock()
+	defer c.overflowMu.Unlock()
+
+	c.pending, c.pendingPath = nil, ""
+	if err := c.writeOverflow(c.nextOverflowPath(), batch); err != nil {
+		c.config.onError(fmt.Errorf("dropped %d log records after failing to write overflow: %w", len(batch), err))
+		return
+	}
+	c.trimOverflow()
+}
+
+// appendOverflow adds records that could not be buffered to the newest overflow file, starting a new
+// one once it holds a batch, so records spilled one at a time don't each get their own file
+func (c *shippingCore) appendOverflow(records []shippedRecord, cause error) {
+	if c.config.overflowDir == "" {
+		c.config.onError(fmt.Errorf("dropped %d log records: %w", len(records), cause))
+		return
+	}
+
+	c.overflowMu.Lock()
+	defer c.overflowMu.Unlock()
+
+	started := false
+	if c.pendingPath == "" || len(c.pending) >= c.config.batchSize {
+		c.pending, c.pendingPath = nil, c.nextOverflowPath()
+		started = true
+	}
+
+	if err := c.writeOverflow(c.pendingPath, append(c.pending, records...)); err != nil {
+		c.config.onError(fmt.Errorf("dropped %d log records after failing to write overflow: %w", len(records), err))
+		return
+	}
+	c.pending = append(c.pending, records...)
+
+	// Only a new file can take the directory past the maximum
+	if started {
+		c.trimOverflow()
+	}
+}
+
+// sealOverflow stops appending to the newest overflow file
+func (c *shippingCore) sealOverflow() {
+	c.overflowMu.Lock()
+	c.pending, c.pendingPath = nil, ""
+	c.overflowMu.Unlock()
+}
+
+// nextOverflowPath returns a path that sorts after every existing overflow file
+func (c *shippingCore) nextOverflowPath() string {
+	// The sequence number keeps names unique and in order within a nanosecond
+	name := fmt.Sprintf("%020d-%010d.json", time.Now().UnixNano(), c.overflowSeq.Add(1))
+	return filepath.Join(c.config.overflowDir, name)
+}
+
+func (c *shippingCore) writeOverflow(path string, batch []shippedRecord) error {
+	encoded, err := json.Marshal(batch)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(c.config.overflowDir, 0o755); err != nil {
+		return err
+	}
+	return filekit.WriteFileAtomic(path, encoded, 0o600)
+}
+
+// trimOverflow drops the oldest overflow batches beyond the maximum
+func (c *shippingCore) trimOverflow() {
+	if c.config.maxOverflow <= 0 {
+		return
+	}
+
+	files, err := overflowFiles(c.config.overflowDir)
+	if err != nil {
+		c.config.onError(err)
+		return
+	}
+
+	for _, file := range files[:max(0, len(files)-c.config.maxOverflow)] {
+		if err := os.Remove(file); err != nil && !errors.Is(err, os.ErrNotExist) {
+			c.config.onError(fmt.Errorf("failed to remove log overflow %s: %w", file, err))
+			continue
+		}
+		c.config.onError(fmt.Errorf("dropped log overflow %s: more than %d batches", file, c.config.maxOverflow))
+	}
+}
+
+// overflowFiles lists the overflow batches oldest first
+func overflowFiles(dir string) ([]string, error) {
+	files, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list log overflow: %w", err)
+	}
+	sort.Strings(files)
+	return files, nil
+}
+
+// replayOverflow posts overflow batches oldest first, stopping at the first failure
+func (c *shippingCore) replayOverflow(ctx context.Context) error {
+	if c.config.overflowDir == "" {
+		return nil
+	}
+
+	files, err := overflowFiles(c.config.overflowDir)
+	if err != nil {
+		return err
+	}
+
+	for _, file := range files {
+		contents, err := os.ReadFile(file)
+		if errors.Is(err, os.ErrNotExist) {
+			// Trimmed since it was listed
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read log overflow %s: %w", file, err)
+		}
+
+		var batch []shippedRecord
+		if err := json.Unmarshal(contents, &batch); err != nil {
+			c.config.onError(fmt.Errorf("discarding corrupt log overflow %s: %w", file, err))
+			_ = os.Remove(file)
+			continue
+		}
+
+		if err := c.send(ctx, batch); err != nil {
+			return err
+		}
+		if err := os.Remove(file); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("failed to remove log overflow %s: %w", file, err)
+		}
+	}
+
+	return nil
+}
+
+type otlpAnyValue struct {
+	StringValue *string  `json:"stringValue,omitempty"`
+	BoolValue   *bool    `json:"boolValue,omitempty"`
+	IntValue    *string  `json:"intValue,omitempty"`
+	DoubleValue *float64 `json:"doubleValue,omitempty"`
+}
+
+type otlpKeyValue struct {
+	Key   string       `json:"key"`
+	Value otlpAnyValue `json:"value"`
+}
+
+type otlpLogRecord struct {
+	TimeUnixNano   string         `json:"timeUnixNano"`
+	SeverityNumber int            `json:"severityNumber"`
+	SeverityText   string         `json:"severityText"`
+	Body           otlpAnyValue   `json:"body"`
+	Attributes     []otlpKeyValue `json:"attributes,omitempty"`
+}
+
+type otlpScopeLogs struct {
+	LogRecords []otlpLogRecord `json:"logRecords"`
+}
+
+type otlpResourceLogs struct {
+	Resource struct {
+		Attributes []otlpKeyValue `json:"attributes,omitempty"`
+	} `json:"resource"`
+	ScopeLogs []otlpScopeLogs `json:"scopeLogs"`
+}
+
+type otlpLogsRequest struct {
+	ResourceLogs []otlpResourceLogs `json:"resourceLogs"`
+}
+
+func toOTLPLogs(batch []shippedRecord, serviceName string) otlpLogsRequest {
+	records := make([]otlpLogRecord, 0, len(batch))
+	for _, record := range batch {
+		keys := make([]string, 0, len(record.Attrs))
+		for key := range record.Attrs {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		attributes := make([]otlpKeyValue, 0, len(keys))
+		for _, key := range keys {
+			attributes = append(attributes, otlpKeyValue{Key: key, Value: toOTLPValue(record.Attrs[key])})
+		}
+
+		message := record.Message
+		records = append(records, otlpLogRecord{
+			TimeUnixNano:   strconv.FormatInt(record.Time.UnixNano(), 10),
+			SeverityNumber: otlpSeverityNumber(record.Level),
+			SeverityText:   record.Level,
+			Body:           otlpAnyValue{StringValue: &message},
+			Attributes:     attributes,
+		})
+	}
+
+	resourceLogs := otlpResourceLogs{ScopeLogs: []otlpScopeLogs{{LogRecords: records}}}
+	if serviceName != "" {
+		resourceLogs.Resource.Attributes = []otlpKeyValue{{Key: "service.name", Value: otlpAnyValue{StringValue: &serviceName}}}
+	}
+
+	return otlpLogsRequest{ResourceLogs: []otlpResourceLogs{resourceLogs}}
+}
+
+func toOTLPValue(value any) otlpAnyValue {
+	switch v := value.(type) {
+	case string:
+		return otlpAnyValue{StringValue: &v}
+	case bool:
+		return otlpAnyValue{BoolValue: &v}
+	case int64:
+		s := strconv.FormatInt(v, 10)
+		return otlpAnyValue{IntValue: &s}
+	case uint64:
+		s := strconv.FormatUint(v, 10)
+		return otlpAnyValue{IntValue: &s}
+	case float64:
+		return otlpAnyValue{DoubleValue: &v}
+	default:
+		s := fmt.Sprint(v)
+		return otlpAnyValue{StringValue: &s}
+	}
+}
+
+// otlpSeverityNumber maps slog levels onto the OpenTelemetry severity number ranges
+func otlpSeverityNumber(level string) int {
+	var l slog.Level
+	if err := l.UnmarshalText([]byte(level)); err != nil {
+		return 0
+	}
+	switch {
+	case l < slog.LevelInfo:
+		return 5 // DEBUG
+	case l < slog.LevelWarn:
+		return 9 // INFO
+	case l < slog.LevelError:
+		return 13 // WARN
+	default:
+		return 17 // ERROR
+	}
+}
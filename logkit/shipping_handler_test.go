@@ -0,0 +1,412 @@
+package logkit
+
+import (
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeLogEndpoint struct {
+	mu       sync.Mutex
+	payloads [][]byte
+	headers  []http.Header
+	failures int
+}
+
+func newFakeLogEndpoint(t *testing.T, failures int) (*fakeLogEndpoint, *httptest.Server) {
+	endpoint := &fakeLogEndpoint{failures: failures}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		endpoint.mu.Lock()
+		defer endpoint.mu.Unlock()
+
+		if endpoint.failures > 0 {
+			endpoint.failures--
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+
+		gz, err := gzip.NewReader(r.Body)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		payload, _ := io.ReadAll(gz)
+		endpoint.payloads = append(endpoint.payloads, payload)
+		endpoint.headers = append(endpoint.headers, r.Header.Clone())
+		w.WriteHeader(http.StatusOK)
+	}))
+	t.Cleanup(server.Close)
+	return endpoint, server
+}
+
+func (e *fakeLogEndpoint) records(t *testing.T) []map[string]any {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	var records []map[string]any
+	for _, payload := range e.payloads {
+		var batch []map[string]any
+		assert.NoError(t, json.Unmarshal(payload, &batch))
+		records = append(records, batch...)
+	}
+	return records
+}
+
+func newTestShippingHandler(t *testing.T, url string, options ...ShippingHandlerOption) *ShippingHandler {
+	options = append([]ShippingHandlerOption{
+		WithShippingFlushInterval(time.Hour),
+		WithShippingRetries(0, time.Millisecond),
+		WithShippingErrorHandler(func(error) {}),
+	}, options...)
+	handler := NewShippingHandler(url, options...)
+	t.Cleanup(func() { _ = handler.Close(context.Background()) })
+	return handler
+}
+
+func TestShippingHandler(t *testing.T) {
+	t.Run("posts_gzipped_json_records_on_flush", func(t *testing.T) {
+		endpoint, server := newFakeLogEndpoint(t, 0)
+		handler := newTestShippingHandler(t, server.URL, WithShippingHeader("X-Api-Key", "theAPIKey"))
+		logger := slog.New(handler)
+
+		logger.Info("theMessage", "theKey", "theValue")
+		err := handler.Flush(context.Background())
+
+		assert.NoError(t, err)
+		records := endpoint.records(t)
+		assert.Len(t, records, 1)
+		assert.Equal(t, "theMessage", records[0]["msg"])
+		assert.Equal(t, "INFO", records[0]["level"])
+		assert.Equal(t, map[string]any{"theKey": "theValue"}, records[0]["attrs"])
+		assert.Equal(t, "gzip", endpoint.headers[0].Get("Content-Encoding"))
+		assert.Equal(t, "theAPIKey", endpoint.headers[0].Get("X-Api-Key"))
+	})
+
+	t.Run("skips_records_below_the_level", func(t *testing.T) {
+		endpoint, server := newFakeLogEndpoint(t, 0)
+		handler := newTestShippingHandler(t, server.URL, WithShippingLevel(slog.LevelWarn))
+		logger := slog.New(handler)
+
+		logger.Info("anInfoMessage")
+		logger.Warn("aWarnMessage")
+		err := handler.Flush(context.Background())
+
+		assert.NoError(t, err)
+		records := endpoint.records(t)
+		assert.Len(t, records, 1)
+		assert.Equal(t, "aWarnMessage", records[0]["msg"])
+	})
+
+	t.Run("prefixes_attrs_with_groups", func(t *testing.T) {
+		endpoint, server := newFakeLogEndpoint(t, 0)
+		handler := newTestShippingHandler(t, server.URL)
+		logger := slog.New(handler).With("aKey", "aValue").WithGroup("theGroup")
+
+		logger.Info("aMessage", "theKey", 42, slog.Group("theSubgroup", "theSubKey", true))
+		err := handler.Flush(context.Background())
+
+		assert.NoError(t, err)
+		records := endpoint.records(t)
+		assert.Equal(t, map[string]any{
+			"aKey":                           "aValue",
+			"theGroup.theKey":                float64(42),
+			"theGroup.theSubgroup.theSubKey": true,
+		}, records[0]["attrs"])
+	})
+
+	t.Run("flushes_in_the_background_when_a_batch_is_full", func(t *testing.T) {
+		endpoint, server := newFakeLogEndpoint(t, 0)
+		handler := newTestShippingHandler(t, server.URL, WithShippingBatchSize(2))
+		logger := slog.New(handler)
+
+		logger.Info("aMessage")
+		logger.Info("anotherMessage")
+
+		assert.Eventually(t, func() bool { return len(endpoint.records(t)) == 2 }, time.Second, 5*time.Millisecond)
+	})
+
+	t.Run("retries_failed_posts", func(t *testing.T) {
+		endpoint, server := newFakeLogEndpoint(t, 2)
+		handler := newTestShippingHandler(t, server.URL, WithShippingRetries(2, time.Millisecond))
+
+		slog.New(handler).Info("theMessage")
+		err := handler.Flush(context.Background())
+
+		assert.NoError(t, err)
+		assert.Len(t, endpoint.records(t), 1)
+	})
+
+	t.Run("writes_unshippable_batches_to_the_overflow_dir_and_replays_them", func(t *testing.T) {
+		endpoint, server := newFakeLogEndpoint(t, 1)
+		theOverflowDir := filepath.Join(t.TempDir(), "overflow")
+		handler := newTestShippingHandler(t, server.URL, WithShippingOverflowDir(theOverflowDir))
+		logger := slog.New(handler)
+
+		logger.Info("theFirstMessage")
+		err := handler.Flush(context.Background())
+
+		assert.Error(t, err)
+		files, _ := os.ReadDir(theOverflowDir)
+		assert.Len(t, files, 1)
+
+		logger.Info("theSecondMessage")
+		err = handler.Flush(context.Background())
+
+		assert.NoError(t, err)
+		records := endpoint.records(t)
+		assert.Len(t, records, 2)
+		assert.Equal(t, "theFirstMessage", records[0]["msg"])
+		assert.Equal(t, "theSecondMessage", records[1]["msg"])
+		files, _ = os.ReadDir(theOverflowDir)
+		assert.Empty(t, files)
+	})
+
+	t.Run("keeps_buffered_records_behind_the_overflow_while_it_cannot_be_replayed", func(t *testing.T) {
+		endpoint, server := newFakeLogEndpoint(t, 2)
+		theOverflowDir := t.TempDir()
+		handler := newTestShippingHandler(t, server.URL, WithShippingOverflowDir(theOverflowDir))
+		logger := slog.New(handler)
+
+		logger.Info("theFirstMessage")
+		assert.Error(t, handler.Flush(context.Background()))
+		logger.Info("theSecondMessage")
+		assert.Error(t, handler.Flush(context.Background()))
+		logger.Info("theThirdMessage")
+		err := handler.Flush(context.Background())
+
+		assert.NoError(t, err)
+		records := endpoint.records(t)
+		assert.Len(t, records, 3)
+		assert.Equal(t, "theFirstMessage", records[0]["msg"])
+		assert.Equal(t, "theSecondMessage", records[1]["msg"])
+		assert.Equal(t, "theThirdMessage", records[2]["msg"])
+	})
+
+	t.Run("gives_each_overflow_batch_its_own_file", func(t *testing.T) {
+		_, server := newFakeLogEndpoint(t, 0)
+		theOverflowDir := t.TempDir()
+		handler := newTestShippingHandler(t, server.URL,
+			WithShippingOverflowDir(theOverflowDir),
+			WithShippingMaxBuffered(0),
+			WithShippingBatchSize(1))
+		logger := slog.New(handler)
+
+		for range 50 {
+			logger.Info("theMessage")
+		}
+
+		files, _ := os.ReadDir(theOverflowDir)
+		assert.Len(t, files, 50)
+	})
+
+	t.Run("spills_only_the_records_beyond_the_max_buffered", func(t *testing.T) {
+		endpoint, server := newFakeLogEndpoint(t, 0)
+		theOverflowDir := t.TempDir()
+		handler := newTestShippingHandler(t, server.URL, WithShippingOverflowDir(theOverflowDir), WithShippingMaxBuffered(2))
+		logger := slog.New(handler)
+
+		logger.Info("theFirstMessage")
+		logger.Info("theSecondMessage")
+		logger.Info("theThirdMessage")
+
+		files, _ := os.ReadDir(theOverflowDir)
+		assert.Len(t, files, 1)
+		contents, _ := os.ReadFile(filepath.Join(theOverflowDir, files[0].Name()))
+		var spilled []shippedRecord
+		assert.NoError(t, json.Unmarshal(contents, &spilled))
+		assert.Len(t, spilled, 1)
+		assert.Equal(t, "theFirstMessage", spilled[0].Message)
+
+		err := handler.Flush(context.Background())
+
+		assert.NoError(t, err)
+		records := endpoint.records(t)
+		assert.Len(t, records, 3)
+		assert.Equal(t, "theFirstMessage", records[0]["msg"])
+		assert.Equal(t, "theSecondMessage", records[1]["msg"])
+		assert.Equal(t, "theThirdMessage", records[2]["msg"])
+	})
+
+	t.Run("appends_spilled_records_to_one_overflow_file_per_batch", func(t *testing.T) {
+		_, server := newFakeLogEndpoint(t, 0)
+		theOverflowDir := t.TempDir()
+		handler := newTestShippingHandler(t, server.URL,
+			WithShippingOverflowDir(theOverflowDir),
+			WithShippingMaxBuffered(0),
+			WithShippingBatchSize(10))
+		logger := slog.New(handler)
+
+		for range 25 {
+			logger.Info("theMessage")
+		}
+
+		files, _ := os.ReadDir(theOverflowDir)
+		assert.Len(t, files, 3)
+	})
+
+	t.Run("drops_the_oldest_overflow_batches_beyond_the_maximum", func(t *testing.T) {
+		_, server := newFakeLogEndpoint(t, 0)
+		theOverflowDir := t.TempDir()
+		var dropped []error
+		handler := newTestShippingHandler(t, server.URL,
+			WithShippingOverflowDir(theOverflowDir),
+			WithShippingMaxBuffered(0),
+			WithShippingBatchSize(1),
+			WithShippingMaxOverflowFiles(2),
+			WithShippingErrorHandler(func(err error) { dropped = append(dropped, err) }))
+		logger := slog.New(handler)
+
+		logger.Info("theFirstMessage")
+		logger.Info("theSecondMessage")
+		logger.Info("theThirdMessage")
+
+		files, _ := os.ReadDir(theOverflowDir)
+		assert.Len(t, files, 2)
+		contents, _ := os.ReadFile(filepath.Join(theOverflowDir, files[0].Name()))
+		assert.Contains(t, string(contents), "theSecondMessage")
+		assert.Len(t, dropped, 1)
+		assert.ErrorContains(t, dropped[0], "more than 2 batches")
+	})
+
+	t.Run("reports_dropped_batches_without_an_overflow_dir", func(t *testing.T) {
+		_, server := newFakeLogEndpoint(t, 1)
+		var actualErr error
+		handler := newTestShippingHandler(t, server.URL, WithShippingErrorHandler(func(err error) { actualErr = err }))
+
+		slog.New(handler).Info("theMessage")
+		err := handler.Flush(context.Background())
+
+		assert.Error(t, err)
+		assert.ErrorContains(t, actualErr, "dropped 1 log records")
+	})
+
+	t.Run("flushes_on_close", func(t *testing.T) {
+		endpoint, server := newFakeLogEndpoint(t, 0)
+		handler := NewShippingHandler(server.URL, WithShippingFlushInterval(time.Hour))
+
+		slog.New(handler).Info("theMessage")
+		err := handler.Close(context.Background())
+
+		assert.NoError(t, err)
+		assert.Len(t, endpoint.records(t), 1)
+		assert.NoError(t, handler.Close(context.Background()))
+	})
+
+	t.Run("writes_records_handled_after_close_to_the_overflow_dir", func(t *testing.T) {
+		endpoint, server := newFakeLogEndpoint(t, 0)
+		theOverflowDir := t.TempDir()
+		handler := newTestShippingHandler(t, server.URL, WithShippingOverflowDir(theOverflowDir))
+		assert.NoError(t, handler.Close(context.Background()))
+
+		slog.New(handler).Info("theLateMessage")
+
+		assert.Empty(t, endpoint.records(t))
+		files, _ := os.ReadDir(theOverflowDir)
+		assert.Len(t, files, 1)
+	})
+
+	t.Run("writes_records_handled_after_close_to_one_overflow_file_per_batch", func(t *testing.T) {
+		_, server := newFakeLogEndpoint(t, 0)
+		theOverflowDir := t.TempDir()
+		handler := newTestShippingHandler(t, server.URL, WithShippingOverflowDir(theOverflowDir), WithShippingBatchSize(2))
+		assert.NoError(t, handler.Close(context.Background()))
+		logger := slog.New(handler)
+
+		logger.Info("theFirstLateMessage")
+		logger.Info("theSecondLateMessage")
+		logger.Info("theThirdLateMessage")
+
+		files, _ := os.ReadDir(theOverflowDir)
+		assert.Len(t, files, 2)
+		contents, _ := os.ReadFile(filepath.Join(theOverflowDir, files[0].Name()))
+		var late []shippedRecord
+		assert.NoError(t, json.Unmarshal(contents, &late))
+		assert.Len(t, late, 2)
+		assert.Equal(t, "theFirstLateMessage", late[0].Message)
+		assert.Equal(t, "theSecondLateMessage", late[1].Message)
+	})
+
+	t.Run("stops_waiting_for_a_background_flush_when_the_close_context_is_done", func(t *testing.T) {
+		posting := make(chan struct{}, 1)
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			// The server only notices the client going away once the body has been read
+			_, _ = io.Copy(io.Discard, r.Body)
+			posting <- struct{}{}
+			<-r.Context().Done()
+		}))
+		t.Cleanup(server.Close)
+		theOverflowDir := t.TempDir()
+		handler := newTestShippingHandler(t, server.URL, WithShippingOverflowDir(theOverflowDir), WithShippingBatchSize(1))
+		slog.New(handler).Info("theMessage")
+		<-posting
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+
+		err := handler.Close(ctx)
+
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+		files, _ := os.ReadDir(theOverflowDir)
+		assert.Len(t, files, 1)
+	})
+
+	t.Run("reports_records_handled_after_close_without_an_overflow_dir", func(t *testing.T) {
+		_, server := newFakeLogEndpoint(t, 0)
+		var actualErr error
+		handler := newTestShippingHandler(t, server.URL, WithShippingErrorHandler(func(err error) { actualErr = err }))
+		assert.NoError(t, handler.Close(context.Background()))
+
+		slog.New(handler).Info("theLateMessage")
+
+		assert.EqualError(t, actualErr, "dropped 1 log records: log shipping handler is closed")
+	})
+
+	t.Run("writes_records_that_cannot_be_posted_on_close_to_the_overflow_dir", func(t *testing.T) {
+		_, server := newFakeLogEndpoint(t, 1)
+		theOverflowDir := t.TempDir()
+		handler := newTestShippingHandler(t, server.URL, WithShippingOverflowDir(theOverflowDir), WithShippingBatchSize(1000))
+		logger := slog.New(handler)
+		logger.Info("theFirstMessage")
+		logger.Info("theSecondMessage")
+
+		err := handler.Close(context.Background())
+
+		assert.Error(t, err)
+		files, _ := os.ReadDir(theOverflowDir)
+		assert.Len(t, files, 1)
+	})
+
+	t.Run("posts_otlp_logs", func(t *testing.T) {
+		endpoint, server := newFakeLogEndpoint(t, 0)
+		handler := newTestShippingHandler(t, server.URL, WithShippingFormat(ShippingFormatOTLP), WithShippingServiceName("theService"))
+
+		slog.New(handler).Error("theMessage", "theError", errors.New("the error"), "theCount", 3)
+		err := handler.Flush(context.Background())
+
+		assert.NoError(t, err)
+		var request otlpLogsRequest
+		assert.NoError(t, json.Unmarshal(endpoint.payloads[0], &request))
+		resourceLogs := request.ResourceLogs[0]
+		assert.Equal(t, "service.name", resourceLogs.Resource.Attributes[0].Key)
+		assert.Equal(t, "theService", *resourceLogs.Resource.Attributes[0].Value.StringValue)
+		record := resourceLogs.ScopeLogs[0].LogRecords[0]
+		assert.Equal(t, "theMessage", *record.Body.StringValue)
+		assert.Equal(t, 17, record.SeverityNumber)
+		assert.Equal(t, "ERROR", record.SeverityText)
+		assert.Equal(t, "theCount", record.Attributes[0].Key)
+		assert.Equal(t, "3", *record.Attributes[0].Value.IntValue)
+		assert.Equal(t, "theError", record.Attributes[1].Key)
+		assert.Equal(t, "the error", *record.Attributes[1].Value.StringValue)
+	})
+}
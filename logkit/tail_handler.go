@@ -0,0 +1,172 @@
+package logkit
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// tailNow is replaced in tests
+var tailNow = time.Now
+
+// TailHandlerOption configures a TailHandler
+type TailHandlerOption func(*tailHandlerConfig)
+
+type tailHandlerConfig struct {
+	level            slog.Leveler
+	latencyThreshold time.Duration
+	maxRecords       int
+}
+
+// WithTailLevel sets the level below which records in a tail are buffered; the default is INFO, so
+// DEBUG records are buffered and everything else is handled right away
+func WithTailLevel(level slog.Leveler) TailHandlerOption {
+	return func(c *tailHandlerConfig) {
+		c.level = level
+	}
+}
+
+// WithTailLatencyThreshold sets how long a tail may run before its buffered records are emitted even
+// though it ended without an error; the default of 0 only emits them on error
+func WithTailLatencyThreshold(threshold time.Duration) TailHandlerOption {
+	return func(c *tailHandlerConfig) {
+		c.latencyThreshold = threshold
+	}
+}
+
+// WithTailMaxRecords sets how many records a tail buffers, keeping the most recent; the default is 1000
+func WithTailMaxRecords(max int) TailHandlerOption {
+	return func(c *tailHandlerConfig) {
+		c.maxRecords = max
+	}
+}
+
+// TailHandler is a slog.Handler that buffers low-level records logged with a context from StartTail
+// and only passes them to the wrapped handler when EndTail reports an error or the tail ran longer
+// than the latency threshold. This gives DEBUG detail for failed and slow requests without writing
+// it for every request. Records at or above the tail level, and records logged without a tail, are
+// passed on right away.
+type TailHandler struct {
+	next   slog.Handler
+	config *tailHandlerConfig
+}
+
+// NewTailHandler creates a TailHandler wrapping next
+func NewTailHandler(next slog.Handler, options ...TailHandlerOption) *TailHandler {
+	config := tailHandlerConfig{
+		level:      slog.LevelInfo,
+		maxRecords: 1000,
+	}
+	for _, option := range options {
+		option(&config)
+	}
+
+	return &TailHandler{next: next, config: &config}
+}
+
+// Enabled reports true for any level below the tail level when ctx has a tail, as the record is
+// buffered rather than checked against the wrapped handler's level
+func (h *TailHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	if level < h.config.level.Level() && tailFrom(ctx) != nil {
+		return true
+	}
+	return h.next.Enabled(ctx, level)
+}
+
+func (h *TailHandler) Handle(ctx context.Context, record slog.Record) error {
+	if record.Level < h.config.level.Level() {
+		if tail := tailFrom(ctx); tail != nil && tail.add(h, record) {
+			return nil
+		}
+		if !h.next.Enabled(ctx, record.Level) {
+			return nil
+		}
+	}
+	return h.next.Handle(ctx, record)
+}
+
+func (h *TailHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &TailHandler{next: h.next.WithAttrs(attrs), config: h.config}
+}
+
+func (h *TailHandler) WithGroup(name string) slog.Handler {
+	return &TailHandler{next: h.next.WithGroup(name), config: h.config}
+}
+
+type tailContextKey struct{}
+
+type tailedRecord struct {
+	handler *TailHandler
+	record  slog.Record
+}
+
+type tail struct {
+	mu      sync.Mutex
+	started time.Time
+	records []tailedRecord
+	ended   bool
+}
+
+func tailFrom(ctx context.Context) *tail {
+	t, _ := ctx.Value(tailContextKey{}).(*tail)
+	return t
+}
+
+// add buffers record, dropping the oldest buffered record when full, unless the tail has ended
+func (t *tail) add(h *TailHandler, record slog.Record) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.ended {
+		return false
+	}
+	if h.config.maxRecords > 0 && len(t.records) >= h.config.maxRecords {
+		t.records = t.records[1:]
+	}
+	t.records = append(t.records, tailedRecord{handler: h, record: record.Clone()})
+	return true
+}
+
+// StartTail returns a context whose records below a TailHandler's tail level are buffered until
+// EndTail, e.g. in request middleware:
+//
+//	ctx := logkit.StartTail(c.Request().Context())
+//	c.SetRequest(c.Request().WithContext(ctx))
+//	err := next(c)
+//	logkit.EndTail(ctx, err)
+func StartTail(ctx context.Context) context.Context {
+	return context.WithValue(ctx, tailContextKey{}, &tail{started: tailNow()})
+}
+
+// EndTail passes the records buffered for ctx to their wrapped handlers if err is non-nil or the tail
+// ran longer than their handler's latency threshold since StartTail, and discards them otherwise.
+// Records logged with ctx after EndTail are handled as though it had no tail. It returns the errors
+// from the wrapped handlers.
+func EndTail(ctx context.Context, err error) error {
+	t := tailFrom(ctx)
+	if t == nil {
+		return nil
+	}
+
+	t.mu.Lock()
+	records := t.records
+	elapsed := tailNow().Sub(t.started)
+	t.records = nil
+	t.ended = true
+	t.mu.Unlock()
+
+	var errs []error
+	for _, tailed := range records {
+		threshold := tailed.handler.config.latencyThreshold
+		slow := threshold > 0 && elapsed > threshold
+		if err == nil && !slow {
+			continue
+		}
+		if handleErr := tailed.handler.next.Handle(ctx, tailed.record); handleErr != nil {
+			errs = append(errs, handleErr)
+		}
+	}
+	return errors.Join(errs...)
+}
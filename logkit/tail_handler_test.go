@@ -0,0 +1,152 @@
+package logkit
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newTailTestLogger(options ...TailHandlerOption) (*slog.Logger, *bytes.Buffer) {
+	var logBuf bytes.Buffer
+	handler := NewTailHandler(slog.NewJSONHandler(&logBuf, &slog.HandlerOptions{Level: slog.LevelInfo}), options...)
+	return slog.New(handler), &logBuf
+}
+
+func setTailTestNow(t *testing.T, times ...time.Time) {
+	previous := tailNow
+	tailNow = func() time.Time {
+		now := times[0]
+		if len(times) > 1 {
+			times = times[1:]
+		}
+		return now
+	}
+	t.Cleanup(func() { tailNow = previous })
+}
+
+func TestTailHandler(t *testing.T) {
+	t.Run("emits_buffered_debug_records_when_the_tail_ends_in_error", func(t *testing.T) {
+		logger, logBuf := newTailTestLogger()
+		ctx := StartTail(context.Background())
+
+		logger.DebugContext(ctx, "theDebugMessage", "theKey", "theValue")
+		assert.Empty(t, logBuf.String())
+
+		err := EndTail(ctx, errors.New("the request error"))
+
+		assert.NoError(t, err)
+		assert.Contains(t, logBuf.String(), "theDebugMessage")
+		assert.Contains(t, logBuf.String(), `"theKey":"theValue"`)
+	})
+
+	t.Run("discards_buffered_records_when_the_tail_ends_without_error", func(t *testing.T) {
+		logger, logBuf := newTailTestLogger()
+		ctx := StartTail(context.Background())
+
+		logger.DebugContext(ctx, "theDebugMessage")
+		err := EndTail(ctx, nil)
+
+		assert.NoError(t, err)
+		assert.Empty(t, logBuf.String())
+	})
+
+	t.Run("emits_buffered_records_when_the_tail_is_slower_than_the_latency_threshold", func(t *testing.T) {
+		start := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+		setTailTestNow(t, start, start.Add(3*time.Second))
+		logger, logBuf := newTailTestLogger(WithTailLatencyThreshold(2 * time.Second))
+		ctx := StartTail(context.Background())
+
+		logger.DebugContext(ctx, "theDebugMessage")
+		err := EndTail(ctx, nil)
+
+		assert.NoError(t, err)
+		assert.Contains(t, logBuf.String(), "theDebugMessage")
+	})
+
+	t.Run("discards_buffered_records_when_the_tail_is_within_the_latency_threshold", func(t *testing.T) {
+		start := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+		setTailTestNow(t, start, start.Add(time.Second))
+		logger, logBuf := newTailTestLogger(WithTailLatencyThreshold(2 * time.Second))
+		ctx := StartTail(context.Background())
+
+		logger.DebugContext(ctx, "theDebugMessage")
+		_ = EndTail(ctx, nil)
+
+		assert.Empty(t, logBuf.String())
+	})
+
+	t.Run("handles_records_at_the_tail_level_right_away", func(t *testing.T) {
+		logger, logBuf := newTailTestLogger()
+		ctx := StartTail(context.Background())
+
+		logger.InfoContext(ctx, "theInfoMessage")
+
+		assert.Contains(t, logBuf.String(), "theInfoMessage")
+	})
+
+	t.Run("buffers_records_below_a_custom_tail_level", func(t *testing.T) {
+		logger, logBuf := newTailTestLogger(WithTailLevel(slog.LevelWarn))
+		ctx := StartTail(context.Background())
+
+		logger.InfoContext(ctx, "theInfoMessage")
+		assert.Empty(t, logBuf.String())
+
+		_ = EndTail(ctx, errors.New("the request error"))
+
+		assert.Contains(t, logBuf.String(), "theInfoMessage")
+	})
+
+	t.Run("filters_debug_records_by_the_wrapped_handler_level_without_a_tail", func(t *testing.T) {
+		logger, logBuf := newTailTestLogger()
+
+		logger.DebugContext(context.Background(), "theDebugMessage")
+
+		assert.Empty(t, logBuf.String())
+	})
+
+	t.Run("filters_debug_records_logged_after_the_tail_ended", func(t *testing.T) {
+		logger, logBuf := newTailTestLogger()
+		ctx := StartTail(context.Background())
+		_ = EndTail(ctx, errors.New("the request error"))
+
+		logger.DebugContext(ctx, "theDebugMessage")
+
+		assert.Empty(t, logBuf.String())
+	})
+
+	t.Run("keeps_the_most_recent_records_up_to_the_max", func(t *testing.T) {
+		logger, logBuf := newTailTestLogger(WithTailMaxRecords(2))
+		ctx := StartTail(context.Background())
+
+		logger.DebugContext(ctx, "theFirstMessage")
+		logger.DebugContext(ctx, "theSecondMessage")
+		logger.DebugContext(ctx, "theThirdMessage")
+		_ = EndTail(ctx, errors.New("the request error"))
+
+		assert.NotContains(t, logBuf.String(), "theFirstMessage")
+		assert.Contains(t, logBuf.String(), "theSecondMessage")
+		assert.Contains(t, logBuf.String(), "theThirdMessage")
+	})
+
+	t.Run("emits_buffered_records_with_the_attrs_and_groups_they_were_logged_with", func(t *testing.T) {
+		logger, logBuf := newTailTestLogger()
+		ctx := StartTail(context.Background())
+
+		logger.With("theAttr", "theAttrValue").WithGroup("theGroup").DebugContext(ctx, "theDebugMessage", "theKey", "theValue")
+		_ = EndTail(ctx, errors.New("the request error"))
+
+		assert.Contains(t, logBuf.String(), `"theAttr":"theAttrValue"`)
+		assert.Contains(t, logBuf.String(), `"theGroup":{"theKey":"theValue"}`)
+	})
+
+	t.Run("ignores_end_tail_for_a_context_without_a_tail", func(t *testing.T) {
+		err := EndTail(context.Background(), errors.New("the request error"))
+
+		assert.NoError(t, err)
+	})
+}
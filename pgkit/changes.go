@@ -0,0 +1,297 @@
+package pgkit
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/half-ogre/go-kit/kit"
+)
+
+// LSN is a Postgres write-ahead log position
+type LSN uint64
+
+// ParseLSN parses an LSN in Postgres's text form, e.g. "16/B374D848"
+func ParseLSN(s string) (LSN, error) {
+	var high, low uint32
+	if _, err := fmt.Sscanf(s, "%X/%X", &high, &low); err != nil {
+		return 0, kit.WrapError(err, "failed to parse LSN %s", s)
+	}
+	return LSN(uint64(high)<<32 | uint64(low)), nil
+}
+
+// String returns the LSN in Postgres's text form
+func (l LSN) String() string {
+	return fmt.Sprintf("%X/%X", uint32(l>>32), uint32(l))
+}
+
+// ChangeAction is the kind of row change a Change is
+type ChangeAction string
+
+const (
+	ChangeInsert   ChangeAction = "I"
+	ChangeUpdate   ChangeAction = "U"
+	ChangeDelete   ChangeAction = "D"
+	ChangeTruncate ChangeAction = "T"
+)
+
+// Change is a row change read from a logical replication slot. New is the row after an insert or
+// update, decoded from its columns into T with encoding/json, so T's json tags name the columns. Old
+// is the row's replica identity before an update or delete, which is only its key columns unless the
+// table has REPLICA IDENTITY FULL. A truncate has neither.
+type Change[T any] struct {
+	LSN    LSN
+	Action ChangeAction
+	Schema string
+	Table  string
+	New    *T
+	Old    *T
+}
+
+// ChangeHandler handles a batch of changes, in commit order. Returning an error leaves the changes
+// unacknowledged, so they're read again.
+type ChangeHandler[T any] func(ctx context.Context, changes []Change[T]) error
+
+// ChangeReaderOption configures a ChangeReader
+type ChangeReaderOption func(*changeReaderConfig)
+
+type changeReaderConfig struct {
+	batchSize    int
+	tables       []string
+	pollInterval time.Duration
+}
+
+// WithChangeBatchSize sets about how many changes each Poll reads; the default is 1000. Whole
+// transactions are always read, so a batch can be bigger.
+func WithChangeBatchSize(n int) ChangeReaderOption {
+	return func(c *changeReaderConfig) {
+		c.batchSize = n
+	}
+}
+
+// WithChangeTables limits the changes read to tables, e.g. "public.orders"; the default is every
+// table
+func WithChangeTables(tables ...string) ChangeReaderOption {
+	return func(c *changeReaderConfig) {
+		c.tables = tables
+	}
+}
+
+// WithChangePollInterval sets how long Run waits to poll again after reading no changes or an error;
+// the default is a second
+func WithChangePollInterval(interval time.Duration) ChangeReaderOption {
+	return func(c *changeReaderConfig) {
+		c.pollInterval = interval
+	}
+}
+
+// ChangeReader reads row changes from a logical replication slot using the wal2json output plugin,
+// e.g. to build read models. Create the slot once with CreateChangeSlot. Changes are acknowledged by
+// advancing the slot after the handler returns nil, so the slot's position is where reading restarts
+// and a change can be handled more than once if the process stops in between; make handlers
+// idempotent. Run one reader per slot.
+type ChangeReader[T any] struct {
+	db      DB
+	slot    string
+	handler ChangeHandler[T]
+	config  changeReaderConfig
+}
+
+// NewChangeReader returns a reader of the changes in slot that passes them to handler
+func NewChangeReader[T any](db DB, slot string, handler ChangeHandler[T], options ...ChangeReaderOption) *ChangeReader[T] {
+	config := changeReaderConfig{batchSize: 1000, pollInterval: time.Second}
+	for _, option := range options {
+		option(&config)
+	}
+	return &ChangeReader[T]{db: db, slot: slot, handler: handler, config: config}
+}
+
+// CreateChangeSlot creates the logical replication slot slot for a ChangeReader if it doesn't exist.
+// The database needs wal_level = logical and the wal2json plugin. A slot keeps the server from
+// removing WAL it hasn't read, so drop slots that are no longer read.
+func CreateChangeSlot(ctx context.Context, db DB, slot string) error {
+	if db == nil {
+		return fmt.Errorf("database connection cannot be nil")
+	}
+	if slot == "" {
+		return fmt.Errorf("slot name cannot be empty")
+	}
+
+	_, err := db.Exec(ctx, `SELECT pg_create_logical_replication_slot($1, 'wal2json')
+WHERE NOT EXISTS (SELECT 1 FROM pg_replication_slots WHERE slot_name = $1)`, slot)
+	if err != nil {
+		return kit.WrapError(err, "failed to create replication slot %s", slot)
+	}
+	return nil
+}
+
+// RestartLSN returns the position of the last acknowledged change, where reading restarts
+func (r *ChangeReader[T]) RestartLSN(ctx context.Context) (LSN, error) {
+	var lsn string
+	err := r.db.QueryRow(ctx, "SELECT confirmed_flush_lsn::text FROM pg_replication_slots WHERE slot_name = $1", r.slot).Scan(&lsn)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return 0, fmt.Errorf("replication slot %s does not exist", r.slot)
+	}
+	if err != nil {
+		return 0, kit.WrapError(err, "failed to get restart LSN of replication slot %s", r.slot)
+	}
+	return ParseLSN(lsn)
+}
+
+// Poll reads the next batch of changes, passes them to the handler, and acknowledges them if it
+// returns nil. It returns how many changes were handled.
+func (r *ChangeReader[T]) Poll(ctx context.Context) (int, error) {
+	if r.slot == "" {
+		return 0, fmt.Errorf("slot name cannot be empty")
+	}
+
+	changes, last, err := r.peek(ctx)
+	if err != nil {
+		return 0, err
+	}
+	if last == 0 {
+		return 0, nil
+	}
+
+	if len(changes) > 0 {
+		if err := r.handler(ctx, changes); err != nil {
+			return 0, kit.WrapError(err, "failed to handle changes up to %s", last)
+		}
+	}
+
+	if _, err := r.db.Exec(ctx, "SELECT pg_replication_slot_advance($1, $2::pg_lsn)", r.slot, last.String()); err != nil {
+		return 0, kit.WrapError(err, "failed to acknowledge changes up to %s", last)
+	}
+	return len(changes), nil
+}
+
+// peek reads the next batch from the slot without consuming it, returning its changes and the LSN of
+// the last message read, which can be a transaction's commit with no changes to handle
+func (r *ChangeReader[T]) peek(ctx context.Context) ([]Change[T], LSN, error) {
+	query := "SELECT lsn::text, data FROM pg_logical_slot_peek_changes($1, NULL, $2, 'format-version', '2'"
+	args := []any{r.slot, r.config.batchSize}
+	if len(r.config.tables) > 0 {
+		query += ", 'add-tables', $3"
+		args = append(args, strings.Join(r.config.tables, ","))
+	}
+	query += ")"
+
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, 0, kit.WrapError(err, "failed to read changes from replication slot %s", r.slot)
+	}
+	defer rows.Close()
+
+	var changes []Change[T]
+	var last LSN
+	for rows.Next() {
+		var lsnText, data string
+		if err := rows.Scan(&lsnText, &data); err != nil {
+			return nil, 0, kit.WrapError(err, "failed to scan change")
+		}
+		lsn, err := ParseLSN(lsnText)
+		if err != nil {
+			return nil, 0, err
+		}
+		last = lsn
+
+		change, ok, err := decodeChange[T](lsn, data)
+		if err != nil {
+			return nil, 0, err
+		}
+		if ok {
+			changes = append(changes, change)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, kit.WrapError(err, "failed to read changes from replication slot %s", r.slot)
+	}
+
+	return changes, last, nil
+}
+
+// Run polls for changes until ctx is canceled, waiting the poll interval whenever there are none.
+// Errors are logged and the same changes are tried again after the poll interval.
+func (r *ChangeReader[T]) Run(ctx context.Context) error {
+	for {
+		handled, err := r.Poll(ctx)
+		if err != nil && ctx.Err() == nil {
+			logger.ErrorContext(ctx, "error reading changes", "slot", r.slot, "error", err)
+		}
+		if err == nil && handled > 0 {
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(r.config.pollInterval):
+		}
+	}
+}
+
+// wal2jsonMessage is a wal2json format-version 2 message
+type wal2jsonMessage struct {
+	Action   string           `json:"action"`
+	Schema   string           `json:"schema"`
+	Table    string           `json:"table"`
+	Columns  []wal2jsonColumn `json:"columns"`
+	Identity []wal2jsonColumn `json:"identity"`
+}
+
+type wal2jsonColumn struct {
+	Name  string          `json:"name"`
+	Value json.RawMessage `json:"value"`
+}
+
+// decodeChange decodes a wal2json message, returning false for messages that aren't row changes,
+// e.g. a transaction's begin and commit
+func decodeChange[T any](lsn LSN, data string) (Change[T], bool, error) {
+	var message wal2jsonMessage
+	if err := json.Unmarshal([]byte(data), &message); err != nil {
+		return Change[T]{}, false, kit.WrapError(err, "failed to unmarshal change at %s", lsn)
+	}
+
+	change := Change[T]{LSN: lsn, Action: ChangeAction(message.Action), Schema: message.Schema, Table: message.Table}
+	switch change.Action {
+	case ChangeInsert, ChangeUpdate, ChangeDelete, ChangeTruncate:
+	default:
+		return Change[T]{}, false, nil
+	}
+
+	var err error
+	if change.New, err = decodeColumns[T](message.Columns); err != nil {
+		return Change[T]{}, false, kit.WrapError(err, "failed to decode new row of %s.%s at %s", message.Schema, message.Table, lsn)
+	}
+	if change.Old, err = decodeColumns[T](message.Identity); err != nil {
+		return Change[T]{}, false, kit.WrapError(err, "failed to decode old row of %s.%s at %s", message.Schema, message.Table, lsn)
+	}
+
+	return change, true, nil
+}
+
+func decodeColumns[T any](columns []wal2jsonColumn) (*T, error) {
+	if len(columns) == 0 {
+		return nil, nil
+	}
+
+	values := make(map[string]json.RawMessage, len(columns))
+	for _, column := range columns {
+		values[column.Name] = column.Value
+	}
+	valuesJSON, err := json.Marshal(values)
+	if err != nil {
+		return nil, err
+	}
+
+	row := new(T)
+	if err := json.Unmarshal(valuesJSON, row); err != nil {
+		return nil, err
+	}
+	return row, nil
+}
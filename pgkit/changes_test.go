@@ -0,0 +1,276 @@
+package pgkit
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/assert"
+)
+
+type testOrder struct {
+	ID     int    `json:"id"`
+	Status string `json:"status"`
+}
+
+func newChangeRows(messages [][2]string) *FakeRows {
+	i := -1
+	return &FakeRows{
+		NextFake: func() bool {
+			i++
+			return i < len(messages)
+		},
+		ScanFake: func(dest ...any) error {
+			*dest[0].(*string) = messages[i][0]
+			*dest[1].(*string) = messages[i][1]
+			return nil
+		},
+		CloseFake: func() error { return nil },
+		ErrFake:   func() error { return nil },
+	}
+}
+
+var testChangeMessages = [][2]string{
+	{"0/16B3748", `{"action":"B"}`},
+	{"0/16B3748", `{"action":"I","schema":"public","table":"orders","columns":[{"name":"id","type":"integer","value":1},{"name":"status","type":"text","value":"new"}]}`},
+	{"0/16B37E0", `{"action":"U","schema":"public","table":"orders","columns":[{"name":"id","type":"integer","value":1},{"name":"status","type":"text","value":"paid"}],"identity":[{"name":"id","type":"integer","value":1}]}`},
+	{"0/16B3850", `{"action":"D","schema":"public","table":"orders","identity":[{"name":"id","type":"integer","value":2}]}`},
+	{"0/16B3880", `{"action":"C"}`},
+}
+
+func TestParseLSN(t *testing.T) {
+	t.Run("parses_the_text_form", func(t *testing.T) {
+		lsn, err := ParseLSN("16/B374D848")
+
+		assert.NoError(t, err)
+		assert.Equal(t, LSN(0x16B374D848), lsn)
+		assert.Equal(t, "16/B374D848", lsn.String())
+	})
+
+	t.Run("returns_an_error_for_an_invalid_lsn", func(t *testing.T) {
+		_, err := ParseLSN("anLSN")
+
+		assert.ErrorContains(t, err, "failed to parse LSN anLSN")
+	})
+}
+
+func TestChangeReaderPoll(t *testing.T) {
+	t.Run("passes_the_decoded_changes_to_the_handler_and_acknowledges_them", func(t *testing.T) {
+		var actualQuery string
+		var actualQueryArgs []any
+		var actualAckArgs []any
+		fakeDB := &FakeDB{
+			QueryFake: func(ctx context.Context, query string, args ...any) (Rows, error) {
+				actualQuery = query
+				actualQueryArgs = args
+				return newChangeRows(testChangeMessages), nil
+			},
+			ExecFake: func(ctx context.Context, query string, args ...any) (sql.Result, error) {
+				assert.Equal(t, "SELECT pg_replication_slot_advance($1, $2::pg_lsn)", query)
+				actualAckArgs = args
+				return pgxResult{cmdTag: pgconn.NewCommandTag("SELECT 1")}, nil
+			},
+		}
+		var actualChanges []Change[testOrder]
+		reader := NewChangeReader(fakeDB, "theSlot", func(ctx context.Context, changes []Change[testOrder]) error {
+			actualChanges = changes
+			return nil
+		})
+
+		handled, err := reader.Poll(context.Background())
+
+		assert.NoError(t, err)
+		assert.Equal(t, 3, handled)
+		assert.Equal(t, "SELECT lsn::text, data FROM pg_logical_slot_peek_changes($1, NULL, $2, 'format-version', '2')", actualQuery)
+		assert.Equal(t, []any{"theSlot", 1000}, actualQueryArgs)
+		assert.Equal(t, []Change[testOrder]{
+			{LSN: 0x16B3748, Action: ChangeInsert, Schema: "public", Table: "orders", New: &testOrder{ID: 1, Status: "new"}},
+			{LSN: 0x16B37E0, Action: ChangeUpdate, Schema: "public", Table: "orders", New: &testOrder{ID: 1, Status: "paid"}, Old: &testOrder{ID: 1}},
+			{LSN: 0x16B3850, Action: ChangeDelete, Schema: "public", Table: "orders", Old: &testOrder{ID: 2}},
+		}, actualChanges)
+		assert.Equal(t, []any{"theSlot", "0/16B3880"}, actualAckArgs)
+	})
+
+	t.Run("reads_only_the_configured_tables_in_batches_of_the_configured_size", func(t *testing.T) {
+		var actualQuery string
+		var actualArgs []any
+		fakeDB := &FakeDB{
+			QueryFake: func(ctx context.Context, query string, args ...any) (Rows, error) {
+				actualQuery = query
+				actualArgs = args
+				return newChangeRows(nil), nil
+			},
+		}
+		reader := NewChangeReader(fakeDB, "aSlot", func(ctx context.Context, changes []Change[testOrder]) error { return nil },
+			WithChangeBatchSize(10), WithChangeTables("public.orders", "public.customers"))
+
+		_, err := reader.Poll(context.Background())
+
+		assert.NoError(t, err)
+		assert.Equal(t, "SELECT lsn::text, data FROM pg_logical_slot_peek_changes($1, NULL, $2, 'format-version', '2', 'add-tables', $3)", actualQuery)
+		assert.Equal(t, []any{"aSlot", 10, "public.orders,public.customers"}, actualArgs)
+	})
+
+	t.Run("does_nothing_when_there_are_no_changes", func(t *testing.T) {
+		fakeDB := &FakeDB{
+			QueryFake: func(ctx context.Context, query string, args ...any) (Rows, error) {
+				return newChangeRows(nil), nil
+			},
+		}
+		handlerCalled := false
+		reader := NewChangeReader(fakeDB, "aSlot", func(ctx context.Context, changes []Change[testOrder]) error {
+			handlerCalled = true
+			return nil
+		})
+
+		handled, err := reader.Poll(context.Background())
+
+		assert.NoError(t, err)
+		assert.Equal(t, 0, handled)
+		assert.False(t, handlerCalled)
+	})
+
+	t.Run("acknowledges_transactions_without_row_changes_without_calling_the_handler", func(t *testing.T) {
+		var actualAckArgs []any
+		fakeDB := &FakeDB{
+			QueryFake: func(ctx context.Context, query string, args ...any) (Rows, error) {
+				return newChangeRows([][2]string{{"0/10", `{"action":"B"}`}, {"0/20", `{"action":"C"}`}}), nil
+			},
+			ExecFake: func(ctx context.Context, query string, args ...any) (sql.Result, error) {
+				actualAckArgs = args
+				return pgxResult{cmdTag: pgconn.NewCommandTag("SELECT 1")}, nil
+			},
+		}
+		handlerCalled := false
+		reader := NewChangeReader(fakeDB, "aSlot", func(ctx context.Context, changes []Change[testOrder]) error {
+			handlerCalled = true
+			return nil
+		})
+
+		_, err := reader.Poll(context.Background())
+
+		assert.NoError(t, err)
+		assert.False(t, handlerCalled)
+		assert.Equal(t, []any{"aSlot", "0/20"}, actualAckArgs)
+	})
+
+	t.Run("does_not_acknowledge_the_changes_when_the_handler_fails", func(t *testing.T) {
+		fakeDB := &FakeDB{
+			QueryFake: func(ctx context.Context, query string, args ...any) (Rows, error) {
+				return newChangeRows(testChangeMessages), nil
+			},
+		}
+		reader := NewChangeReader(fakeDB, "aSlot", func(ctx context.Context, changes []Change[testOrder]) error {
+			return errors.New("the handler error")
+		})
+
+		_, err := reader.Poll(context.Background())
+
+		assert.EqualError(t, err, "failed to handle changes up to 0/16B3880: the handler error")
+	})
+
+	t.Run("returns_an_error_when_the_changes_cannot_be_read", func(t *testing.T) {
+		fakeDB := &FakeDB{
+			QueryFake: func(ctx context.Context, query string, args ...any) (Rows, error) {
+				return nil, errors.New("the query error")
+			},
+		}
+		reader := NewChangeReader(fakeDB, "theSlot", func(ctx context.Context, changes []Change[testOrder]) error { return nil })
+
+		_, err := reader.Poll(context.Background())
+
+		assert.EqualError(t, err, "failed to read changes from replication slot theSlot: the query error")
+	})
+
+	t.Run("returns_an_error_when_a_change_cannot_be_decoded", func(t *testing.T) {
+		fakeDB := &FakeDB{
+			QueryFake: func(ctx context.Context, query string, args ...any) (Rows, error) {
+				return newChangeRows([][2]string{{"0/10", `{"action":"I","schema":"public","table":"orders","columns":[{"name":"id","value":"notAnInt"}]}`}}), nil
+			},
+		}
+		reader := NewChangeReader(fakeDB, "aSlot", func(ctx context.Context, changes []Change[testOrder]) error { return nil })
+
+		_, err := reader.Poll(context.Background())
+
+		assert.ErrorContains(t, err, "failed to decode new row of public.orders at 0/10")
+	})
+
+	t.Run("returns_an_error_when_the_acknowledgement_fails", func(t *testing.T) {
+		fakeDB := &FakeDB{
+			QueryFake: func(ctx context.Context, query string, args ...any) (Rows, error) {
+				return newChangeRows(testChangeMessages), nil
+			},
+			ExecFake: func(ctx context.Context, query string, args ...any) (sql.Result, error) {
+				return nil, errors.New("the exec error")
+			},
+		}
+		reader := NewChangeReader(fakeDB, "aSlot", func(ctx context.Context, changes []Change[testOrder]) error { return nil })
+
+		_, err := reader.Poll(context.Background())
+
+		assert.EqualError(t, err, "failed to acknowledge changes up to 0/16B3880: the exec error")
+	})
+}
+
+func TestChangeReaderRestartLSN(t *testing.T) {
+	t.Run("returns_the_slot_confirmed_flush_lsn", func(t *testing.T) {
+		fakeDB := &FakeDB{
+			QueryRowFake: func(ctx context.Context, query string, args ...any) Row {
+				assert.Equal(t, []any{"theSlot"}, args)
+				return &FakeRow{ScanFake: func(dest ...any) error {
+					*dest[0].(*string) = "0/16B3880"
+					return nil
+				}}
+			},
+		}
+		reader := NewChangeReader(fakeDB, "theSlot", func(ctx context.Context, changes []Change[testOrder]) error { return nil })
+
+		lsn, err := reader.RestartLSN(context.Background())
+
+		assert.NoError(t, err)
+		assert.Equal(t, LSN(0x16B3880), lsn)
+	})
+
+	t.Run("returns_an_error_when_the_slot_does_not_exist", func(t *testing.T) {
+		fakeDB := &FakeDB{
+			QueryRowFake: func(ctx context.Context, query string, args ...any) Row {
+				return &FakeRow{ScanFake: func(dest ...any) error { return pgx.ErrNoRows }}
+			},
+		}
+		reader := NewChangeReader(fakeDB, "theSlot", func(ctx context.Context, changes []Change[testOrder]) error { return nil })
+
+		_, err := reader.RestartLSN(context.Background())
+
+		assert.EqualError(t, err, "replication slot theSlot does not exist")
+	})
+}
+
+func TestCreateChangeSlot(t *testing.T) {
+	t.Run("creates_the_slot_with_wal2json_if_it_does_not_exist", func(t *testing.T) {
+		var actualQuery string
+		var actualArgs []any
+		fakeDB := &FakeDB{
+			ExecFake: func(ctx context.Context, query string, args ...any) (sql.Result, error) {
+				actualQuery = query
+				actualArgs = args
+				return pgxResult{cmdTag: pgconn.NewCommandTag("SELECT 1")}, nil
+			},
+		}
+
+		err := CreateChangeSlot(context.Background(), fakeDB, "theSlot")
+
+		assert.NoError(t, err)
+		assert.Contains(t, actualQuery, "pg_create_logical_replication_slot($1, 'wal2json')")
+		assert.Contains(t, actualQuery, "WHERE NOT EXISTS")
+		assert.Equal(t, []any{"theSlot"}, actualArgs)
+	})
+
+	t.Run("returns_an_error_when_the_slot_name_is_empty", func(t *testing.T) {
+		err := CreateChangeSlot(context.Background(), &FakeDB{}, "")
+
+		assert.EqualError(t, err, "slot name cannot be empty")
+	})
+}
@@ -0,0 +1,128 @@
+package pgkit
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/half-ogre/go-kit/kit"
+)
+
+// NotDeleted is the condition that filters out soft-deleted rows, for use in WHERE clauses
+const NotDeleted = "deleted_at IS NULL"
+
+// TimestampColumnsSQL returns the DDL that adds the conventional created_at, updated_at, and deleted_at
+// columns to a table, for use in a migration. It is safe to run on a table that already has them.
+func TimestampColumnsSQL(table string) string {
+	return fmt.Sprintf(`ALTER TABLE %s
+	ADD COLUMN IF NOT EXISTS created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+	ADD COLUMN IF NOT EXISTS updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+	ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMPTZ`, sanitizeTableName(table))
+}
+
+// TouchUpdatedAtTriggerSQL returns the DDL for a trigger that sets updated_at to NOW() on every update
+// of the table, for use in a migration. The trigger function is shared by every table that uses it.
+func TouchUpdatedAtTriggerSQL(table string) string {
+	trigger := triggerName(table, "touch_updated_at")
+	return fmt.Sprintf(`CREATE OR REPLACE FUNCTION pgkit_touch_updated_at() RETURNS TRIGGER AS $$
+BEGIN
+	NEW.updated_at = NOW();
+	RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS %s ON %s;
+
+CREATE TRIGGER %s BEFORE UPDATE ON %s
+	FOR EACH ROW EXECUTE FUNCTION pgkit_touch_updated_at()`,
+		trigger, sanitizeTableName(table), trigger, sanitizeTableName(table))
+}
+
+// AuditTableSQL returns a migration that creates <table>_audit and a trigger that records every insert,
+// update, and delete on table as a row holding the old and new rows as JSONB. The actor is read from
+// the app.actor setting, so set it per transaction with SET LOCAL app.actor = '...'.
+func AuditTableSQL(table string) string {
+	auditTable := sanitizeTableName(table + "_audit")
+	trigger := triggerName(table, "audit")
+	return fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s (
+	id BIGSERIAL PRIMARY KEY,
+	operation TEXT NOT NULL,
+	old_row JSONB,
+	new_row JSONB,
+	actor TEXT,
+	changed_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE OR REPLACE FUNCTION pgkit_audit_row() RETURNS TRIGGER AS $$
+BEGIN
+	EXECUTE format('INSERT INTO %%s (operation, old_row, new_row, actor) VALUES ($1, $2, $3, $4)', TG_ARGV[0])
+		USING TG_OP,
+			CASE WHEN TG_OP = 'INSERT' THEN NULL ELSE to_jsonb(OLD) END,
+			CASE WHEN TG_OP = 'DELETE' THEN NULL ELSE to_jsonb(NEW) END,
+			NULLIF(current_setting('app.actor', true), '');
+	RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS %s ON %s;
+
+CREATE TRIGGER %s AFTER INSERT OR UPDATE OR DELETE ON %s
+	FOR EACH ROW EXECUTE FUNCTION pgkit_audit_row('%s')`,
+		auditTable, trigger, sanitizeTableName(table), trigger, sanitizeTableName(table), strings.ReplaceAll(auditTable, "'", "''"))
+}
+
+// SelectNotDeletedSQL returns a SELECT of columns from table that skips soft-deleted rows; add more
+// conditions with AND
+func SelectNotDeletedSQL(table string, columns ...string) string {
+	columnList := "*"
+	if len(columns) > 0 {
+		quoted := make([]string, len(columns))
+		for i, column := range columns {
+			quoted[i] = pgx.Identifier{column}.Sanitize()
+		}
+		columnList = strings.Join(quoted, ", ")
+	}
+	return fmt.Sprintf("SELECT %s FROM %s WHERE %s", columnList, sanitizeTableName(table), NotDeleted)
+}
+
+// SoftDelete sets deleted_at on the row with the given id, reporting whether a row that wasn't already
+// deleted was found
+func SoftDelete(ctx context.Context, db DB, table string, id any) (bool, error) {
+	query := fmt.Sprintf("UPDATE %s SET deleted_at = NOW() WHERE id = $1 AND %s", sanitizeTableName(table), NotDeleted)
+	return execAffectsRow(ctx, db, table, query, id, "soft delete")
+}
+
+// Restore clears deleted_at on the row with the given id, reporting whether a soft-deleted row was found
+func Restore(ctx context.Context, db DB, table string, id any) (bool, error) {
+	query := fmt.Sprintf("UPDATE %s SET deleted_at = NULL WHERE id = $1 AND deleted_at IS NOT NULL", sanitizeTableName(table))
+	return execAffectsRow(ctx, db, table, query, id, "restore")
+}
+
+func execAffectsRow(ctx context.Context, db DB, table string, query string, id any, action string) (bool, error) {
+	if db == nil {
+		return false, fmt.Errorf("database connection cannot be nil")
+	}
+	if table == "" {
+		return false, fmt.Errorf("table name cannot be empty")
+	}
+
+	result, err := db.Exec(ctx, query, id)
+	if err != nil {
+		return false, kit.WrapError(err, "failed to %s %v in %s", action, id, table)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, kit.WrapError(err, "failed to get rows affected")
+	}
+
+	return rowsAffected > 0, nil
+}
+
+// triggerName names a trigger after its table without the schema, since triggers belong to their table
+func triggerName(table string, suffix string) string {
+	parts := strings.Split(table, ".")
+	return pgx.Identifier{parts[len(parts)-1] + "_" + suffix}.Sanitize()
+}
@@ -0,0 +1,125 @@
+package pgkit
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTimestampColumnsSQL(t *testing.T) {
+	t.Run("adds_the_conventional_columns_if_missing", func(t *testing.T) {
+		result := TimestampColumnsSQL("app.orders")
+
+		assert.Contains(t, result, `ALTER TABLE "app"."orders"`)
+		assert.Contains(t, result, "ADD COLUMN IF NOT EXISTS created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()")
+		assert.Contains(t, result, "ADD COLUMN IF NOT EXISTS updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()")
+		assert.Contains(t, result, "ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMPTZ")
+	})
+}
+
+func TestTouchUpdatedAtTriggerSQL(t *testing.T) {
+	t.Run("creates_a_before_update_trigger_named_after_the_table", func(t *testing.T) {
+		result := TouchUpdatedAtTriggerSQL("app.orders")
+
+		assert.Contains(t, result, "CREATE OR REPLACE FUNCTION pgkit_touch_updated_at()")
+		assert.Contains(t, result, `DROP TRIGGER IF EXISTS "orders_touch_updated_at" ON "app"."orders"`)
+		assert.Contains(t, result, `CREATE TRIGGER "orders_touch_updated_at" BEFORE UPDATE ON "app"."orders"`)
+	})
+}
+
+func TestAuditTableSQL(t *testing.T) {
+	t.Run("creates_the_audit_table_and_trigger", func(t *testing.T) {
+		result := AuditTableSQL("app.orders")
+
+		assert.Contains(t, result, `CREATE TABLE IF NOT EXISTS "app"."orders_audit"`)
+		assert.Contains(t, result, "INSERT INTO %s (operation, old_row, new_row, actor)")
+		assert.Contains(t, result, "current_setting('app.actor', true)")
+		assert.Contains(t, result, `CREATE TRIGGER "orders_audit" AFTER INSERT OR UPDATE OR DELETE ON "app"."orders"`)
+		assert.Contains(t, result, `EXECUTE FUNCTION pgkit_audit_row('"app"."orders_audit"')`)
+	})
+}
+
+func TestSelectNotDeletedSQL(t *testing.T) {
+	t.Run("selects_all_columns_by_default", func(t *testing.T) {
+		assert.Equal(t, `SELECT * FROM "orders" WHERE deleted_at IS NULL`, SelectNotDeletedSQL("orders"))
+	})
+
+	t.Run("quotes_the_given_columns", func(t *testing.T) {
+		assert.Equal(t, `SELECT "id", "total" FROM "orders" WHERE deleted_at IS NULL`, SelectNotDeletedSQL("orders", "id", "total"))
+	})
+}
+
+func TestSoftDelete(t *testing.T) {
+	t.Run("sets_deleted_at_on_a_row_that_is_not_deleted", func(t *testing.T) {
+		var actualQuery string
+		var actualArgs []any
+		fakeDB := &FakeDB{
+			ExecFake: func(ctx context.Context, query string, args ...any) (sql.Result, error) {
+				actualQuery = query
+				actualArgs = args
+				return pgxResult{cmdTag: pgconn.NewCommandTag("UPDATE 1")}, nil
+			},
+		}
+
+		result, err := SoftDelete(context.Background(), fakeDB, "orders", "theID")
+
+		assert.NoError(t, err)
+		assert.True(t, result)
+		assert.Equal(t, `UPDATE "orders" SET deleted_at = NOW() WHERE id = $1 AND deleted_at IS NULL`, actualQuery)
+		assert.Equal(t, []any{"theID"}, actualArgs)
+	})
+
+	t.Run("returns_false_when_no_row_was_deleted", func(t *testing.T) {
+		fakeDB := &FakeDB{
+			ExecFake: func(ctx context.Context, query string, args ...any) (sql.Result, error) {
+				return pgxResult{cmdTag: pgconn.NewCommandTag("UPDATE 0")}, nil
+			},
+		}
+
+		result, err := SoftDelete(context.Background(), fakeDB, "orders", "theID")
+
+		assert.NoError(t, err)
+		assert.False(t, result)
+	})
+
+	t.Run("returns_an_error_when_exec_fails", func(t *testing.T) {
+		fakeDB := &FakeDB{
+			ExecFake: func(ctx context.Context, query string, args ...any) (sql.Result, error) {
+				return nil, errors.New("the exec error")
+			},
+		}
+
+		result, err := SoftDelete(context.Background(), fakeDB, "orders", "theID")
+
+		assert.False(t, result)
+		assert.EqualError(t, err, "failed to soft delete theID in orders: the exec error")
+	})
+
+	t.Run("returns_an_error_when_db_is_nil", func(t *testing.T) {
+		_, err := SoftDelete(context.Background(), nil, "orders", "theID")
+
+		assert.EqualError(t, err, "database connection cannot be nil")
+	})
+}
+
+func TestRestore(t *testing.T) {
+	t.Run("clears_deleted_at_on_a_deleted_row", func(t *testing.T) {
+		var actualQuery string
+		fakeDB := &FakeDB{
+			ExecFake: func(ctx context.Context, query string, args ...any) (sql.Result, error) {
+				actualQuery = query
+				return pgxResult{cmdTag: pgconn.NewCommandTag("UPDATE 1")}, nil
+			},
+		}
+
+		result, err := Restore(context.Background(), fakeDB, "orders", 42)
+
+		assert.NoError(t, err)
+		assert.True(t, result)
+		assert.Equal(t, `UPDATE "orders" SET deleted_at = NULL WHERE id = $1 AND deleted_at IS NOT NULL`, actualQuery)
+	})
+}
@@ -0,0 +1,120 @@
+package pgkit
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/half-ogre/go-kit/kit"
+)
+
+// ErrDocumentConflict is returned by StoreDocument when a document was changed by someone else
+// since it was read, or when creating a document whose ID already exists
+var ErrDocumentConflict = errors.New("document conflict")
+
+// Document is a typed JSON document stored in a table with the conventional (id, doc, version, updated_at)
+// columns. Version is incremented on every update and used for optimistic concurrency; unlike a
+// timestamp, two writes in the same transaction or clock tick can't share one.
+type Document[T any] struct {
+	ID        string
+	Doc       T
+	Version   int64
+	UpdatedAt time.Time
+}
+
+// CreateDocumentTableSQL returns the DDL for a document table, for use in a migration
+func CreateDocumentTableSQL(table string) string {
+	return fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s (
+	id TEXT PRIMARY KEY,
+	doc JSONB NOT NULL,
+	version BIGINT NOT NULL DEFAULT 1,
+	updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+)`, sanitizeTableName(table))
+}
+
+// GetDocument reads a document by ID, returning nil if it does not exist
+func GetDocument[T any](ctx context.Context, db DB, table string, id string) (*Document[T], error) {
+	if db == nil {
+		return nil, fmt.Errorf("database connection cannot be nil")
+	}
+	if table == "" {
+		return nil, fmt.Errorf("table name cannot be empty")
+	}
+
+	var docJSON []byte
+	var version int64
+	var updatedAt time.Time
+	query := fmt.Sprintf("SELECT doc, version, updated_at FROM %s WHERE id = $1", sanitizeTableName(table))
+	err := db.QueryRow(ctx, query, id).Scan(&docJSON, &version, &updatedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, kit.WrapError(err, "failed to get document %s from %s", id, table)
+	}
+
+	document := &Document[T]{ID: id, Version: version, UpdatedAt: updatedAt}
+	if err := json.Unmarshal(docJSON, &document.Doc); err != nil {
+		return nil, kit.WrapError(err, "failed to unmarshal document %s", id)
+	}
+
+	return document, nil
+}
+
+// StoreDocument creates or updates a document and returns it with its new Version and UpdatedAt.
+// A document with a zero Version is created, and ErrDocumentConflict is returned if the ID is taken.
+// Otherwise the document is only updated if its stored Version still matches, and
+// ErrDocumentConflict is returned if it was modified (or deleted) in the meantime.
+func StoreDocument[T any](ctx context.Context, db DB, table string, document Document[T]) (*Document[T], error) {
+	if db == nil {
+		return nil, fmt.Errorf("database connection cannot be nil")
+	}
+	if table == "" {
+		return nil, fmt.Errorf("table name cannot be empty")
+	}
+	if document.ID == "" {
+		return nil, fmt.Errorf("document ID cannot be empty")
+	}
+
+	docJSON, err := json.Marshal(document.Doc)
+	if err != nil {
+		return nil, kit.WrapError(err, "failed to marshal document %s", document.ID)
+	}
+
+	var query string
+	args := []any{document.ID, string(docJSON)}
+	if document.Version == 0 {
+		query = fmt.Sprintf(
+			"INSERT INTO %s (id, doc, version, updated_at) VALUES ($1, $2, 1, NOW()) ON CONFLICT (id) DO NOTHING RETURNING version, updated_at",
+			sanitizeTableName(table))
+	} else {
+		query = fmt.Sprintf(
+			"UPDATE %s SET doc = $2, version = version + 1, updated_at = NOW() WHERE id = $1 AND version = $3 RETURNING version, updated_at",
+			sanitizeTableName(table))
+		args = append(args, document.Version)
+	}
+
+	var version int64
+	var updatedAt time.Time
+	err = db.QueryRow(ctx, query, args...).Scan(&version, &updatedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrDocumentConflict
+	}
+	if err != nil {
+		return nil, kit.WrapError(err, "failed to store document %s in %s", document.ID, table)
+	}
+
+	document.Version = version
+	document.UpdatedAt = updatedAt
+	return &document, nil
+}
+
+// sanitizeTableName quotes a table name, which may be schema-qualified (e.g. app.documents)
+func sanitizeTableName(table string) string {
+	return pgx.Identifier(strings.Split(table, ".")).Sanitize()
+}
@@ -0,0 +1,178 @@
+package pgkit
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/stretchr/testify/assert"
+)
+
+type testProfile struct {
+	Name  string `json:"name"`
+	Score int    `json:"score"`
+}
+
+func TestCreateDocumentTableSQL(t *testing.T) {
+	t.Run("quotes_schema_qualified_table_names", func(t *testing.T) {
+		result := CreateDocumentTableSQL("app.profiles")
+
+		assert.Contains(t, result, `CREATE TABLE IF NOT EXISTS "app"."profiles"`)
+		assert.Contains(t, result, "doc JSONB NOT NULL")
+		assert.Contains(t, result, "version BIGINT NOT NULL DEFAULT 1")
+	})
+}
+
+func TestGetDocument(t *testing.T) {
+	t.Run("returns_the_document_when_found", func(t *testing.T) {
+		theUpdatedAt := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+		var actualQuery string
+		var actualArgs []any
+		fakeDB := &FakeDB{
+			QueryRowFake: func(ctx context.Context, query string, args ...any) Row {
+				actualQuery = query
+				actualArgs = args
+				return &FakeRow{
+					ScanFake: func(dest ...any) error {
+						*dest[0].(*[]byte) = []byte(`{"name":"theName","score":42}`)
+						*dest[1].(*int64) = 3
+						*dest[2].(*time.Time) = theUpdatedAt
+						return nil
+					},
+				}
+			},
+		}
+
+		result, err := GetDocument[testProfile](context.Background(), fakeDB, "profiles", "theID")
+
+		assert.NoError(t, err)
+		assert.Equal(t, &Document[testProfile]{ID: "theID", Doc: testProfile{Name: "theName", Score: 42}, Version: 3, UpdatedAt: theUpdatedAt}, result)
+		assert.Equal(t, `SELECT doc, version, updated_at FROM "profiles" WHERE id = $1`, actualQuery)
+		assert.Equal(t, []any{"theID"}, actualArgs)
+	})
+
+	t.Run("returns_nil_when_not_found", func(t *testing.T) {
+		fakeDB := &FakeDB{
+			QueryRowFake: func(ctx context.Context, query string, args ...any) Row {
+				return &FakeRow{ScanFake: func(dest ...any) error { return pgx.ErrNoRows }}
+			},
+		}
+
+		result, err := GetDocument[testProfile](context.Background(), fakeDB, "profiles", "theID")
+
+		assert.NoError(t, err)
+		assert.Nil(t, result)
+	})
+
+	t.Run("returns_an_error_when_the_query_fails", func(t *testing.T) {
+		fakeDB := &FakeDB{
+			QueryRowFake: func(ctx context.Context, query string, args ...any) Row {
+				return &FakeRow{ScanFake: func(dest ...any) error { return errors.New("the scan error") }}
+			},
+		}
+
+		result, err := GetDocument[testProfile](context.Background(), fakeDB, "profiles", "theID")
+
+		assert.Nil(t, result)
+		assert.EqualError(t, err, "failed to get document theID from profiles: the scan error")
+	})
+
+	t.Run("returns_an_error_when_the_table_is_empty", func(t *testing.T) {
+		result, err := GetDocument[testProfile](context.Background(), &FakeDB{}, "", "theID")
+
+		assert.Nil(t, result)
+		assert.EqualError(t, err, "table name cannot be empty")
+	})
+}
+
+func TestStoreDocument(t *testing.T) {
+	t.Run("inserts_a_new_document_when_the_version_is_zero", func(t *testing.T) {
+		theUpdatedAt := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+		var actualQuery string
+		var actualArgs []any
+		fakeDB := &FakeDB{
+			QueryRowFake: func(ctx context.Context, query string, args ...any) Row {
+				actualQuery = query
+				actualArgs = args
+				return &FakeRow{
+					ScanFake: func(dest ...any) error {
+						*dest[0].(*int64) = 1
+						*dest[1].(*time.Time) = theUpdatedAt
+						return nil
+					},
+				}
+			},
+		}
+
+		result, err := StoreDocument(context.Background(), fakeDB, "profiles", Document[testProfile]{ID: "theID", Doc: testProfile{Name: "theName"}})
+
+		assert.NoError(t, err)
+		assert.Equal(t, int64(1), result.Version)
+		assert.Equal(t, theUpdatedAt, result.UpdatedAt)
+		assert.Contains(t, actualQuery, `INSERT INTO "profiles"`)
+		assert.Contains(t, actualQuery, "ON CONFLICT (id) DO NOTHING")
+		assert.Equal(t, []any{"theID", `{"name":"theName","score":0}`}, actualArgs)
+	})
+
+	t.Run("updates_only_when_the_version_matches", func(t *testing.T) {
+		thePreviousUpdatedAt := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+		var actualQuery string
+		var actualArgs []any
+		fakeDB := &FakeDB{
+			QueryRowFake: func(ctx context.Context, query string, args ...any) Row {
+				actualQuery = query
+				actualArgs = args
+				return &FakeRow{
+					ScanFake: func(dest ...any) error {
+						*dest[0].(*int64) = 3
+						*dest[1].(*time.Time) = thePreviousUpdatedAt.Add(time.Second)
+						return nil
+					},
+				}
+			},
+		}
+
+		result, err := StoreDocument(context.Background(), fakeDB, "profiles", Document[testProfile]{ID: "theID", Doc: testProfile{Score: 7}, Version: 2, UpdatedAt: thePreviousUpdatedAt})
+
+		assert.NoError(t, err)
+		assert.Equal(t, int64(3), result.Version)
+		assert.Equal(t, thePreviousUpdatedAt.Add(time.Second), result.UpdatedAt)
+		assert.Contains(t, actualQuery, `UPDATE "profiles" SET doc = $2, version = version + 1, updated_at = NOW() WHERE id = $1 AND version = $3`)
+		assert.Equal(t, []any{"theID", `{"name":"","score":7}`, int64(2)}, actualArgs)
+	})
+
+	t.Run("returns_a_conflict_when_no_row_was_written", func(t *testing.T) {
+		fakeDB := &FakeDB{
+			QueryRowFake: func(ctx context.Context, query string, args ...any) Row {
+				return &FakeRow{ScanFake: func(dest ...any) error { return pgx.ErrNoRows }}
+			},
+		}
+
+		result, err := StoreDocument(context.Background(), fakeDB, "profiles", Document[testProfile]{ID: "theID", Version: 1})
+
+		assert.Nil(t, result)
+		assert.ErrorIs(t, err, ErrDocumentConflict)
+	})
+
+	t.Run("returns_an_error_when_the_query_fails", func(t *testing.T) {
+		fakeDB := &FakeDB{
+			QueryRowFake: func(ctx context.Context, query string, args ...any) Row {
+				return &FakeRow{ScanFake: func(dest ...any) error { return errors.New("the scan error") }}
+			},
+		}
+
+		result, err := StoreDocument(context.Background(), fakeDB, "profiles", Document[testProfile]{ID: "theID"})
+
+		assert.Nil(t, result)
+		assert.EqualError(t, err, "failed to store document theID in profiles: the scan error")
+	})
+
+	t.Run("returns_an_error_when_the_id_is_empty", func(t *testing.T) {
+		result, err := StoreDocument(context.Background(), &FakeDB{}, "profiles", Document[testProfile]{})
+
+		assert.Nil(t, result)
+		assert.EqualError(t, err, "document ID cannot be empty")
+	})
+}
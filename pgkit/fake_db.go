@@ -56,6 +56,7 @@ type FakeDB struct {
 	QueryFake    func(ctx context.Context, query string, args ...any) (Rows, error)
 	ExecFake     func(ctx context.Context, query string, args ...any) (sql.Result, error)
 	CloseFake    func() error
+	BeginFake    func(ctx context.Context) (Tx, error)
 }
 
 func (f *FakeDB) QueryRow(ctx context.Context, query string, args ...any) Row {
@@ -86,6 +87,56 @@ func (f *FakeDB) Close() error {
 	panic("Close fake not implemented")
 }
 
+func (f *FakeDB) Begin(ctx context.Context) (Tx, error) {
+	if f.BeginFake != nil {
+		return f.BeginFake(ctx)
+	}
+	panic("Begin fake not implemented")
+}
+
+type FakeTx struct {
+	QueryRowFake func(ctx context.Context, query string, args ...any) Row
+	QueryFake    func(ctx context.Context, query string, args ...any) (Rows, error)
+	ExecFake     func(ctx context.Context, query string, args ...any) (sql.Result, error)
+	CommitFake   func(ctx context.Context) error
+	RollbackFake func(ctx context.Context) error
+}
+
+func (f *FakeTx) QueryRow(ctx context.Context, query string, args ...any) Row {
+	if f.QueryRowFake != nil {
+		return f.QueryRowFake(ctx, query, args...)
+	}
+	panic("QueryRow fake not implemented")
+}
+
+func (f *FakeTx) Query(ctx context.Context, query string, args ...any) (Rows, error) {
+	if f.QueryFake != nil {
+		return f.QueryFake(ctx, query, args...)
+	}
+	panic("Query fake not implemented")
+}
+
+func (f *FakeTx) Exec(ctx context.Context, query string, args ...any) (sql.Result, error) {
+	if f.ExecFake != nil {
+		return f.ExecFake(ctx, query, args...)
+	}
+	panic("Exec fake not implemented")
+}
+
+func (f *FakeTx) Commit(ctx context.Context) error {
+	if f.CommitFake != nil {
+		return f.CommitFake(ctx)
+	}
+	panic("Commit fake not implemented")
+}
+
+func (f *FakeTx) Rollback(ctx context.Context) error {
+	if f.RollbackFake != nil {
+		return f.RollbackFake(ctx)
+	}
+	panic("Rollback fake not implemented")
+}
+
 type FakeMigrator struct {
 	RunMigrationsFake          func(db DB, dirPath string) error
 	RunMigrationsToVersionFake func(db DB, dirPath string, toVersion int) error
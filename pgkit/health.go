@@ -0,0 +1,19 @@
+package pgkit
+
+import (
+	"context"
+
+	"github.com/half-ogre/go-kit/healthkit"
+	"github.com/half-ogre/go-kit/kit"
+)
+
+// HealthCheck returns a health check named "postgres" that runs SELECT 1 against db
+func HealthCheck(db DB) healthkit.HealthCheck {
+	return healthkit.NewCheck("postgres", func(ctx context.Context) error {
+		var one int
+		if err := db.QueryRow(ctx, "SELECT 1").Scan(&one); err != nil {
+			return kit.WrapError(err, "failed to query postgres")
+		}
+		return nil
+	})
+}
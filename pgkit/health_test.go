@@ -0,0 +1,40 @@
+package pgkit
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHealthCheck(t *testing.T) {
+	t.Run("passes_when_the_query_succeeds", func(t *testing.T) {
+		var actualQuery string
+		fakeDB := &FakeDB{
+			QueryRowFake: func(ctx context.Context, query string, args ...any) Row {
+				actualQuery = query
+				return &FakeRow{ScanFake: func(dest ...any) error { return nil }}
+			},
+		}
+
+		err := HealthCheck(fakeDB).Check(context.Background())
+
+		assert.NoError(t, err)
+		assert.Equal(t, "SELECT 1", actualQuery)
+	})
+
+	t.Run("returns_the_error_from_the_query", func(t *testing.T) {
+		fakeDB := &FakeDB{
+			QueryRowFake: func(ctx context.Context, query string, args ...any) Row {
+				return &FakeRow{ScanFake: func(dest ...any) error { return errors.New("the query error") }}
+			},
+		}
+
+		check := HealthCheck(fakeDB)
+		err := check.Check(context.Background())
+
+		assert.Equal(t, "postgres", check.Name())
+		assert.EqualError(t, err, "failed to query postgres: the query error")
+	})
+}
@@ -0,0 +1,208 @@
+package pgkit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/half-ogre/go-kit/kit"
+	"github.com/half-ogre/go-kit/logkit/logfields"
+)
+
+// RefreshOption configures how a materialized view is refreshed
+type RefreshOption func(*refreshConfig)
+
+type refreshConfig struct {
+	concurrently bool
+	timeout      time.Duration
+}
+
+// WithConcurrentRefresh refreshes with REFRESH MATERIALIZED VIEW CONCURRENTLY, so reads aren't blocked
+// while it runs. The view needs a unique index.
+func WithConcurrentRefresh() RefreshOption {
+	return func(c *refreshConfig) {
+		c.concurrently = true
+	}
+}
+
+// WithRefreshTimeout sets a statement timeout for the refresh
+func WithRefreshTimeout(timeout time.Duration) RefreshOption {
+	return func(c *refreshConfig) {
+		c.timeout = timeout
+	}
+}
+
+// RefreshNow refreshes view, e.g. "reporting.daily_totals", waiting for any refresh of it already
+// running on another instance to finish first
+func RefreshNow(ctx context.Context, db DB, view string, options ...RefreshOption) error {
+	config := refreshConfig{}
+	for _, option := range options {
+		option(&config)
+	}
+
+	_, err := refreshView(ctx, db, view, config, true)
+	return err
+}
+
+// refreshView refreshes view in a transaction holding an advisory lock on it, so instances never
+// refresh the same view at once. If wait is false and another instance holds the lock, the refresh is
+// skipped and refreshView returns false.
+func refreshView(ctx context.Context, db DB, view string, config refreshConfig, wait bool) (bool, error) {
+	sql := "REFRESH MATERIALIZED VIEW "
+	if config.concurrently {
+		sql += "CONCURRENTLY "
+	}
+	sql += pgx.Identifier(strings.Split(view, ".")).Sanitize()
+
+	refreshed := false
+	err := InTx(ctx, db, func(tx Tx) error {
+		if wait {
+			if _, err := tx.Exec(ctx, "SELECT pg_advisory_xact_lock($1)", advisoryLockKey("refresh", view)); err != nil {
+				return kit.WrapError(err, "failed to lock materialized view %s", view)
+			}
+		} else {
+			var locked bool
+			if err := tx.QueryRow(ctx, "SELECT pg_try_advisory_xact_lock($1)", advisoryLockKey("refresh", view)).Scan(&locked); err != nil {
+				return kit.WrapError(err, "failed to lock materialized view %s", view)
+			}
+			if !locked {
+				return nil
+			}
+		}
+
+		if err := setLocalStatementTimeout(ctx, tx, config.timeout); err != nil {
+			return err
+		}
+
+		start := time.Now()
+		if _, err := tx.Exec(ctx, sql); err != nil {
+			return kit.WrapError(err, "failed to refresh materialized view %s", view)
+		}
+		refreshed = true
+		logger.InfoContext(ctx, "refreshed materialized view", "view", view, logfields.Duration(time.Since(start)))
+		return nil
+	})
+
+	return refreshed, err
+}
+
+// advisoryLockKey returns the advisory lock key pgkit uses for kind of work on name, e.g. refreshing a
+// view
+func advisoryLockKey(kind string, name string) int64 {
+	hash := fnv.New64a()
+	hash.Write([]byte("pgkit." + kind + ":" + name))
+	return int64(hash.Sum64())
+}
+
+type registeredView struct {
+	name     string
+	interval time.Duration
+	config   refreshConfig
+	next     time.Time
+}
+
+// ViewRefresher refreshes registered materialized views on intervals. Every instance of a service can
+// run one; the advisory lock means each refresh runs on only one of them.
+type ViewRefresher struct {
+	db    DB
+	clock kit.ClockInterface
+	mu    sync.Mutex
+	views []*registeredView
+}
+
+// ViewRefresherOption configures a ViewRefresher
+type ViewRefresherOption func(*ViewRefresher)
+
+// WithViewRefresherClock sets the clock used to schedule refreshes
+func WithViewRefresherClock(clock kit.ClockInterface) ViewRefresherOption {
+	return func(r *ViewRefresher) {
+		r.clock = clock
+	}
+}
+
+// NewViewRefresher returns a refresher for views in db
+func NewViewRefresher(db DB, options ...ViewRefresherOption) *ViewRefresher {
+	refresher := &ViewRefresher{db: db, clock: kit.NewClock()}
+	for _, option := range options {
+		option(refresher)
+	}
+	return refresher
+}
+
+// RegisterView adds view to be refreshed every interval, starting with the next RefreshDue
+func (r *ViewRefresher) RegisterView(view string, interval time.Duration, options ...RefreshOption) error {
+	if view == "" {
+		return errors.New("view cannot be empty")
+	}
+	if interval <= 0 {
+		return fmt.Errorf("interval must be positive, got %s", interval)
+	}
+
+	config := refreshConfig{}
+	for _, option := range options {
+		option(&config)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.views = append(r.views, &registeredView{name: view, interval: interval, config: config})
+	return nil
+}
+
+// Run refreshes each registered view when it's due until ctx is canceled. Refresh errors are logged and
+// the view is tried again at its next interval.
+func (r *ViewRefresher) Run(ctx context.Context) error {
+	for {
+		wait := r.RefreshDue(ctx)
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(wait):
+		}
+	}
+}
+
+// RefreshDue refreshes the views that are due and returns how long until the next one is. A view
+// another instance is refreshing is skipped until its next interval.
+func (r *ViewRefresher) RefreshDue(ctx context.Context) time.Duration {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, view := range r.views {
+		if ctx.Err() != nil {
+			break
+		}
+		if r.clock.Now().Before(view.next) {
+			continue
+		}
+
+		refreshed, err := refreshView(ctx, r.db, view.name, view.config, false)
+		if err != nil && ctx.Err() == nil {
+			logger.ErrorContext(ctx, "error refreshing materialized view", "view", view.name, "error", err)
+		} else if !refreshed && err == nil {
+			logger.DebugContext(ctx, "materialized view is being refreshed elsewhere", "view", view.name)
+		}
+		view.next = r.clock.Now().Add(view.interval)
+	}
+
+	now := r.clock.Now()
+	wait := time.Duration(-1)
+	for _, view := range r.views {
+		until := max(view.next.Sub(now), 0)
+		if wait < 0 || until < wait {
+			wait = until
+		}
+	}
+	if wait < 0 {
+		// No views are registered; check again in case some are
+		wait = time.Minute
+	}
+	return wait
+}
@@ -0,0 +1,155 @@
+package pgkit
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// newRefreshFakeDB returns a DB whose transactions record their statements and report locked for the
+// try-lock query
+func newRefreshFakeDB(locked bool, execErr error) (*FakeDB, *[]string) {
+	statements := []string{}
+	fakeTx := &FakeTx{
+		ExecFake: func(ctx context.Context, query string, args ...any) (sql.Result, error) {
+			statements = append(statements, query)
+			return nil, execErr
+		},
+		QueryRowFake: func(ctx context.Context, query string, args ...any) Row {
+			statements = append(statements, query)
+			return &FakeRow{ScanFake: func(dest ...any) error {
+				*dest[0].(*bool) = locked
+				return nil
+			}}
+		},
+		CommitFake:   func(ctx context.Context) error { return nil },
+		RollbackFake: func(ctx context.Context) error { return nil },
+	}
+	return &FakeDB{BeginFake: func(ctx context.Context) (Tx, error) { return fakeTx, nil }}, &statements
+}
+
+func TestRefreshNow(t *testing.T) {
+	t.Run("waits_for_the_lock_and_refreshes_the_view", func(t *testing.T) {
+		fakeDB, statements := newRefreshFakeDB(true, nil)
+
+		err := RefreshNow(context.Background(), fakeDB, "reporting.daily_totals")
+
+		assert.NoError(t, err)
+		assert.Equal(t, []string{
+			"SELECT pg_advisory_xact_lock($1)",
+			`REFRESH MATERIALIZED VIEW "reporting"."daily_totals"`,
+		}, *statements)
+	})
+
+	t.Run("refreshes_concurrently_with_a_timeout", func(t *testing.T) {
+		fakeDB, statements := newRefreshFakeDB(true, nil)
+
+		err := RefreshNow(context.Background(), fakeDB, "daily_totals", WithConcurrentRefresh(), WithRefreshTimeout(30*time.Second))
+
+		assert.NoError(t, err)
+		assert.Equal(t, []string{
+			"SELECT pg_advisory_xact_lock($1)",
+			"SELECT set_config('statement_timeout', $1, true)",
+			`REFRESH MATERIALIZED VIEW CONCURRENTLY "daily_totals"`,
+		}, *statements)
+	})
+
+	t.Run("returns_an_error_when_the_refresh_fails", func(t *testing.T) {
+		fakeDB, _ := newRefreshFakeDB(true, errors.New("theExecError"))
+
+		err := RefreshNow(context.Background(), fakeDB, "daily_totals")
+
+		assert.EqualError(t, err, "failed to lock materialized view daily_totals: theExecError")
+	})
+}
+
+func TestAdvisoryLockKey(t *testing.T) {
+	t.Run("is_stable_and_differs_by_kind_and_name", func(t *testing.T) {
+		assert.Equal(t, advisoryLockKey("refresh", "a"), advisoryLockKey("refresh", "a"))
+		assert.NotEqual(t, advisoryLockKey("refresh", "a"), advisoryLockKey("refresh", "b"))
+		assert.NotEqual(t, advisoryLockKey("refresh", "a"), advisoryLockKey("partition", "a"))
+	})
+}
+
+type fakeRefreshClock struct {
+	now time.Time
+}
+
+func (c *fakeRefreshClock) Now() time.Time {
+	return c.now
+}
+
+func TestViewRefresher(t *testing.T) {
+	t.Run("refreshes_views_when_they_are_due", func(t *testing.T) {
+		fakeDB, statements := newRefreshFakeDB(true, nil)
+		clock := &fakeRefreshClock{now: time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)}
+		refresher := NewViewRefresher(fakeDB, WithViewRefresherClock(clock))
+		assert.NoError(t, refresher.RegisterView("hourly", time.Hour))
+		assert.NoError(t, refresher.RegisterView("minutely", time.Minute, WithConcurrentRefresh()))
+
+		wait := refresher.RefreshDue(context.Background())
+
+		assert.Equal(t, time.Minute, wait)
+		assert.Equal(t, []string{
+			"SELECT pg_try_advisory_xact_lock($1)",
+			`REFRESH MATERIALIZED VIEW "hourly"`,
+			"SELECT pg_try_advisory_xact_lock($1)",
+			`REFRESH MATERIALIZED VIEW CONCURRENTLY "minutely"`,
+		}, *statements)
+
+		*statements = (*statements)[:0]
+		clock.now = clock.now.Add(time.Minute)
+		wait = refresher.RefreshDue(context.Background())
+
+		assert.Equal(t, time.Minute, wait)
+		assert.Equal(t, []string{
+			"SELECT pg_try_advisory_xact_lock($1)",
+			`REFRESH MATERIALIZED VIEW CONCURRENTLY "minutely"`,
+		}, *statements)
+	})
+
+	t.Run("skips_a_view_another_instance_is_refreshing", func(t *testing.T) {
+		fakeDB, statements := newRefreshFakeDB(false, nil)
+		refresher := NewViewRefresher(fakeDB)
+		assert.NoError(t, refresher.RegisterView("hourly", time.Hour))
+
+		wait := refresher.RefreshDue(context.Background())
+
+		assert.InDelta(t, time.Hour, wait, float64(time.Second))
+		assert.Equal(t, []string{"SELECT pg_try_advisory_xact_lock($1)"}, *statements)
+	})
+
+	t.Run("keeps_refreshing_after_an_error", func(t *testing.T) {
+		fakeDB, statements := newRefreshFakeDB(true, errors.New("theExecError"))
+		clock := &fakeRefreshClock{now: time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)}
+		refresher := NewViewRefresher(fakeDB, WithViewRefresherClock(clock))
+		assert.NoError(t, refresher.RegisterView("a", time.Minute))
+		assert.NoError(t, refresher.RegisterView("b", time.Minute))
+
+		refresher.RefreshDue(context.Background())
+
+		assert.Len(t, *statements, 4)
+	})
+
+	t.Run("runs_until_the_context_is_canceled", func(t *testing.T) {
+		fakeDB, _ := newRefreshFakeDB(true, nil)
+		refresher := NewViewRefresher(fakeDB)
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		err := refresher.Run(ctx)
+
+		assert.NoError(t, err)
+	})
+
+	t.Run("returns_an_error_for_an_invalid_registration", func(t *testing.T) {
+		refresher := NewViewRefresher(&FakeDB{})
+
+		assert.EqualError(t, refresher.RegisterView("", time.Minute), "view cannot be empty")
+		assert.EqualError(t, refresher.RegisterView("a", 0), "interval must be positive, got 0s")
+	})
+}
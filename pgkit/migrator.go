@@ -37,6 +37,13 @@ func WithOnApplied(fn func(MigrationResult)) MigratorOption {
 	return func(m *migrator) { m.onApplied = fn }
 }
 
+// WithAfterMigration adds a hook run after each migration is applied and recorded, e.g. to refresh a
+// materialized view, bust a cache, or NOTIFY other nodes. Hooks run in the order added and can check
+// migration.Version to act on specific migrations. A hook error stops the run; the migration stays applied.
+func WithAfterMigration(fn func(ctx context.Context, migration Migration) error) MigratorOption {
+	return func(m *migrator) { m.afterMigration = append(m.afterMigration, fn) }
+}
+
 // Migrator is an interface for running database migrations
 type Migrator interface {
 	RunMigrations(db DB, dirPath string) error
@@ -46,7 +53,8 @@ type Migrator interface {
 
 // migrator implements Migrator
 type migrator struct {
-	onApplied func(MigrationResult)
+	onApplied      func(MigrationResult)
+	afterMigration []func(ctx context.Context, migration Migration) error
 }
 
 // parseMigrationVersion extracts the version number from a migration filename
@@ -321,6 +329,19 @@ func (m *migrator) runMigrations(db DB, dirPath string, toVersion int) error {
 			m.onApplied(MigrationResult{Filename: filename, Duration: time.Since(start)})
 		}
 
+		if len(m.afterMigration) > 0 {
+			migration, _ := parseMigration(filename) // Already validated above
+			appliedAt := time.Now()
+			migration.Applied = true
+			migration.AppliedAt = &appliedAt
+			for _, hook := range m.afterMigration {
+				err = hook(context.Background(), migration)
+				if err != nil {
+					return kit.WrapError(err, "after-migration hook failed for %s", filename)
+				}
+			}
+		}
+
 		// Stop if we've reached the target version
 		if isTargetVersion {
 			break
@@ -0,0 +1,183 @@
+package pgkit
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/half-ogre/go-kit/kit"
+)
+
+// KeysetQuery describes a query to paginate by a single unique, ordered key column
+type KeysetQuery[T any] struct {
+	// BaseQuery is the SELECT to paginate, without ORDER BY or LIMIT clauses
+	BaseQuery string
+	// Args are the arguments for any placeholders in BaseQuery
+	Args []any
+	// KeyColumn is the column the results are ordered and paginated by; it must be selected by BaseQuery
+	KeyColumn string
+	// Key returns the value of KeyColumn for an item
+	Key func(item T) any
+	// Scan scans a single result row into an item
+	Scan func(row Row) (T, error)
+	// Descending orders results by KeyColumn from highest to lowest
+	Descending bool
+}
+
+// PageOutput holds a page of results and the cursor for the next page
+type PageOutput[T any] struct {
+	Items      []T
+	NextCursor *string
+}
+
+// Page returns the output as a kit.Page, with NextCursor as its cursor
+func (o *PageOutput[T]) Page() kit.Page[T] {
+	return kit.NewPage(o.Items, o.NextCursor)
+}
+
+// keysetCursor is the JSON form of a cursor; the type is kept so the key can be decoded to the same Go type
+type keysetCursor struct {
+	Key  any    `json:"key"`
+	Type string `json:"type"`
+}
+
+// Paginate runs a keyset-paginated query, returning up to limit items after the position described by cursor.
+// An empty cursor starts from the beginning. Cursors are made with kit.EncodeCursor, as dynamodbkit's
+// LastEvaluatedKey is, so list endpoints backed by either store can share a pagination format.
+func Paginate[T any](ctx context.Context, db DB, query KeysetQuery[T], cursor string, limit int) (*PageOutput[T], error) {
+	if db == nil {
+		return nil, fmt.Errorf("database connection cannot be nil")
+	}
+	if query.BaseQuery == "" {
+		return nil, fmt.Errorf("base query cannot be empty")
+	}
+	if query.KeyColumn == "" {
+		return nil, fmt.Errorf("key column cannot be empty")
+	}
+	if query.Key == nil || query.Scan == nil {
+		return nil, fmt.Errorf("key and scan functions are required")
+	}
+	if limit <= 0 {
+		return nil, fmt.Errorf("limit must be greater than 0, got %d", limit)
+	}
+
+	keyColumn := pgx.Identifier{query.KeyColumn}.Sanitize()
+	comparison, direction := ">", "ASC"
+	if query.Descending {
+		comparison, direction = "<", "DESC"
+	}
+
+	args := append([]any{}, query.Args...)
+	sql := fmt.Sprintf("SELECT * FROM (%s) AS pgkit_page", query.BaseQuery)
+
+	if cursor != "" {
+		key, err := decodeKeysetCursor(cursor)
+		if err != nil {
+			return nil, err
+		}
+		args = append(args, key)
+		sql += fmt.Sprintf(" WHERE %s %s $%d", keyColumn, comparison, len(args))
+	}
+
+	// Fetch one extra row to find out whether there is another page
+	args = append(args, limit+1)
+	sql += fmt.Sprintf(" ORDER BY %s %s LIMIT $%d", keyColumn, direction, len(args))
+
+	rows, err := db.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, kit.WrapError(err, "failed to query page")
+	}
+	defer rows.Close()
+
+	result := &PageOutput[T]{
+		Items: make([]T, 0, limit),
+	}
+
+	hasMore := false
+	for rows.Next() {
+		if len(result.Items) == limit {
+			hasMore = true
+			break
+		}
+
+		item, err := query.Scan(rows)
+		if err != nil {
+			return nil, kit.WrapError(err, "failed to scan page row")
+		}
+		result.Items = append(result.Items, item)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, kit.WrapError(err, "error iterating page rows")
+	}
+
+	if hasMore {
+		nextCursor, err := encodeKeysetCursor(query.Key(result.Items[len(result.Items)-1]))
+		if err != nil {
+			return nil, err
+		}
+		result.NextCursor = &nextCursor
+	}
+
+	return result, nil
+}
+
+func encodeKeysetCursor(key any) (string, error) {
+	var c keysetCursor
+	switch k := key.(type) {
+	case string:
+		c = keysetCursor{Key: k, Type: "string"}
+	case int:
+		c = keysetCursor{Key: int64(k), Type: "int"}
+	case int32:
+		c = keysetCursor{Key: int64(k), Type: "int"}
+	case int64:
+		c = keysetCursor{Key: k, Type: "int"}
+	case time.Time:
+		c = keysetCursor{Key: k.Format(time.RFC3339Nano), Type: "time"}
+	default:
+		return "", fmt.Errorf("unsupported key type %T", key)
+	}
+
+	return kit.EncodeCursor(c)
+}
+
+func decodeKeysetCursor(cursor string) (any, error) {
+	var c struct {
+		Key  json.RawMessage `json:"key"`
+		Type string          `json:"type"`
+	}
+	err := kit.DecodeCursor(cursor, &c)
+	if err != nil {
+		return nil, err
+	}
+
+	switch c.Type {
+	case "string":
+		var key string
+		err = json.Unmarshal(c.Key, &key)
+		return key, wrapCursorKeyError(err, c.Type)
+	case "int":
+		var key int64
+		err = json.Unmarshal(c.Key, &key)
+		return key, wrapCursorKeyError(err, c.Type)
+	case "time":
+		var raw string
+		if err := json.Unmarshal(c.Key, &raw); err != nil {
+			return nil, wrapCursorKeyError(err, c.Type)
+		}
+		key, err := time.Parse(time.RFC3339Nano, raw)
+		return key, wrapCursorKeyError(err, c.Type)
+	default:
+		return nil, fmt.Errorf("unsupported cursor key type %q", c.Type)
+	}
+}
+
+func wrapCursorKeyError(err error, keyType string) error {
+	if err == nil {
+		return nil
+	}
+	return kit.WrapError(err, "failed to decode %s cursor key", keyType)
+}
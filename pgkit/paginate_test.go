@@ -0,0 +1,235 @@
+package pgkit
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type testWidget struct {
+	ID   int64
+	Name string
+}
+
+func newTestWidgetQuery() KeysetQuery[testWidget] {
+	return KeysetQuery[testWidget]{
+		BaseQuery: "SELECT id, name FROM widgets WHERE owner = $1",
+		Args:      []any{"anOwner"},
+		KeyColumn: "id",
+		Key:       func(w testWidget) any { return w.ID },
+		Scan: func(row Row) (testWidget, error) {
+			var w testWidget
+			err := row.Scan(&w.ID, &w.Name)
+			return w, err
+		},
+	}
+}
+
+func newFakeWidgetRows(widgets ...testWidget) *FakeRows {
+	i := -1
+	return &FakeRows{
+		NextFake: func() bool {
+			i++
+			return i < len(widgets)
+		},
+		ScanFake: func(dest ...any) error {
+			*dest[0].(*int64) = widgets[i].ID
+			*dest[1].(*string) = widgets[i].Name
+			return nil
+		},
+		CloseFake: func() error { return nil },
+		ErrFake:   func() error { return nil },
+	}
+}
+
+func TestPaginate(t *testing.T) {
+	t.Run("queries_the_first_page_when_cursor_is_empty", func(t *testing.T) {
+		var actualQuery string
+		var actualArgs []any
+		fakeDB := &FakeDB{
+			QueryFake: func(ctx context.Context, query string, args ...any) (Rows, error) {
+				actualQuery = query
+				actualArgs = args
+				return newFakeWidgetRows(testWidget{ID: 1, Name: "aWidget"}), nil
+			},
+		}
+
+		result, err := Paginate(context.Background(), fakeDB, newTestWidgetQuery(), "", 2)
+
+		assert.NoError(t, err)
+		assert.Equal(t, `SELECT * FROM (SELECT id, name FROM widgets WHERE owner = $1) AS pgkit_page ORDER BY "id" ASC LIMIT $2`, actualQuery)
+		assert.Equal(t, []any{"anOwner", 3}, actualArgs)
+		assert.Equal(t, []testWidget{{ID: 1, Name: "aWidget"}}, result.Items)
+		assert.Nil(t, result.NextCursor)
+	})
+
+	t.Run("returns_a_next_cursor_when_there_are_more_rows", func(t *testing.T) {
+		fakeDB := &FakeDB{
+			QueryFake: func(ctx context.Context, query string, args ...any) (Rows, error) {
+				return newFakeWidgetRows(testWidget{ID: 1}, testWidget{ID: 2}, testWidget{ID: 3}), nil
+			},
+		}
+
+		result, err := Paginate(context.Background(), fakeDB, newTestWidgetQuery(), "", 2)
+
+		assert.NoError(t, err)
+		assert.Len(t, result.Items, 2)
+		assert.NotNil(t, result.NextCursor)
+		key, err := decodeKeysetCursor(*result.NextCursor)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(2), key)
+	})
+
+	t.Run("queries_after_the_cursor_key", func(t *testing.T) {
+		var actualQuery string
+		var actualArgs []any
+		fakeDB := &FakeDB{
+			QueryFake: func(ctx context.Context, query string, args ...any) (Rows, error) {
+				actualQuery = query
+				actualArgs = args
+				return newFakeWidgetRows(), nil
+			},
+		}
+		theCursor, err := encodeKeysetCursor(int64(42))
+		assert.NoError(t, err)
+
+		result, err := Paginate(context.Background(), fakeDB, newTestWidgetQuery(), theCursor, 10)
+
+		assert.NoError(t, err)
+		assert.Empty(t, result.Items)
+		assert.Equal(t, `SELECT * FROM (SELECT id, name FROM widgets WHERE owner = $1) AS pgkit_page WHERE "id" > $2 ORDER BY "id" ASC LIMIT $3`, actualQuery)
+		assert.Equal(t, []any{"anOwner", int64(42), 11}, actualArgs)
+	})
+
+	t.Run("orders_descending_when_requested", func(t *testing.T) {
+		var actualQuery string
+		fakeDB := &FakeDB{
+			QueryFake: func(ctx context.Context, query string, args ...any) (Rows, error) {
+				actualQuery = query
+				return newFakeWidgetRows(), nil
+			},
+		}
+		query := newTestWidgetQuery()
+		query.Descending = true
+		theCursor, err := encodeKeysetCursor(int64(42))
+		assert.NoError(t, err)
+
+		_, err = Paginate(context.Background(), fakeDB, query, theCursor, 10)
+
+		assert.NoError(t, err)
+		assert.Contains(t, actualQuery, `WHERE "id" < $2 ORDER BY "id" DESC LIMIT $3`)
+	})
+
+	t.Run("returns_an_error_when_the_cursor_is_invalid", func(t *testing.T) {
+		result, err := Paginate(context.Background(), &FakeDB{}, newTestWidgetQuery(), "not base64!", 10)
+
+		assert.Nil(t, result)
+		assert.Contains(t, err.Error(), "failed to decode cursor")
+	})
+
+	t.Run("returns_an_error_when_the_query_fails", func(t *testing.T) {
+		fakeDB := &FakeDB{
+			QueryFake: func(ctx context.Context, query string, args ...any) (Rows, error) {
+				return nil, errors.New("the query error")
+			},
+		}
+
+		result, err := Paginate(context.Background(), fakeDB, newTestWidgetQuery(), "", 10)
+
+		assert.Nil(t, result)
+		assert.EqualError(t, err, "failed to query page: the query error")
+	})
+
+	t.Run("returns_an_error_when_scanning_fails", func(t *testing.T) {
+		fakeDB := &FakeDB{
+			QueryFake: func(ctx context.Context, query string, args ...any) (Rows, error) {
+				rows := newFakeWidgetRows(testWidget{ID: 1})
+				rows.ScanFake = func(dest ...any) error { return errors.New("the scan error") }
+				return rows, nil
+			},
+		}
+
+		result, err := Paginate(context.Background(), fakeDB, newTestWidgetQuery(), "", 10)
+
+		assert.Nil(t, result)
+		assert.EqualError(t, err, "failed to scan page row: the scan error")
+	})
+
+	t.Run("returns_an_error_when_limit_is_not_positive", func(t *testing.T) {
+		result, err := Paginate(context.Background(), &FakeDB{}, newTestWidgetQuery(), "", 0)
+
+		assert.Nil(t, result)
+		assert.EqualError(t, err, "limit must be greater than 0, got 0")
+	})
+
+	t.Run("returns_an_error_when_key_column_is_empty", func(t *testing.T) {
+		query := newTestWidgetQuery()
+		query.KeyColumn = ""
+
+		result, err := Paginate(context.Background(), &FakeDB{}, query, "", 10)
+
+		assert.Nil(t, result)
+		assert.EqualError(t, err, "key column cannot be empty")
+	})
+}
+
+func TestKeysetCursor(t *testing.T) {
+	t.Run("round_trips_string_keys", func(t *testing.T) {
+		cursor, err := encodeKeysetCursor("theKey")
+		assert.NoError(t, err)
+
+		key, err := decodeKeysetCursor(cursor)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "theKey", key)
+	})
+
+	t.Run("round_trips_large_integer_keys_without_losing_precision", func(t *testing.T) {
+		cursor, err := encodeKeysetCursor(int64(9007199254740993))
+		assert.NoError(t, err)
+
+		key, err := decodeKeysetCursor(cursor)
+
+		assert.NoError(t, err)
+		assert.Equal(t, int64(9007199254740993), key)
+	})
+
+	t.Run("round_trips_time_keys", func(t *testing.T) {
+		theTime := time.Date(2024, 1, 2, 3, 4, 5, 6, time.UTC)
+		cursor, err := encodeKeysetCursor(theTime)
+		assert.NoError(t, err)
+
+		key, err := decodeKeysetCursor(cursor)
+
+		assert.NoError(t, err)
+		assert.True(t, theTime.Equal(key.(time.Time)))
+	})
+
+	t.Run("returns_an_error_for_unsupported_key_types", func(t *testing.T) {
+		_, err := encodeKeysetCursor(1.5)
+
+		assert.EqualError(t, err, "unsupported key type float64")
+	})
+}
+
+func TestPageOutputPage(t *testing.T) {
+	t.Run("returns_the_items_and_next_cursor_as_a_kit_page", func(t *testing.T) {
+		nextCursor := "theCursor"
+		output := &PageOutput[testWidget]{Items: []testWidget{{ID: 1, Name: "aWidget"}}, NextCursor: &nextCursor}
+
+		page := output.Page()
+
+		assert.Equal(t, []testWidget{{ID: 1, Name: "aWidget"}}, page.Items)
+		assert.Equal(t, "theCursor", page.Cursor)
+		assert.True(t, page.HasMore)
+	})
+
+	t.Run("returns_the_last_page_without_a_next_cursor", func(t *testing.T) {
+		output := &PageOutput[testWidget]{Items: []testWidget{{ID: 1, Name: "aWidget"}}}
+
+		assert.False(t, output.Page().HasMore)
+	})
+}
@@ -0,0 +1,305 @@
+package pgkit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/half-ogre/go-kit/kit"
+	"github.com/half-ogre/go-kit/logkit/logfields"
+)
+
+// PartitionInterval is the time range each partition of a table covers
+type PartitionInterval int
+
+const (
+	// PartitionDaily partitions cover one UTC day and are named like events_p20240102
+	PartitionDaily PartitionInterval = iota + 1
+	// PartitionMonthly partitions cover one UTC month and are named like events_p202401
+	PartitionMonthly
+)
+
+// PartitionSpec declares how a table partitioned by range on a time column is maintained, e.g.
+//
+//	pgkit.PartitionSpec{Table: "metrics.samples", Interval: pgkit.PartitionDaily, Premake: 7, Retain: 30}
+//
+// keeps partitions for today, the next 7 days, and the previous 30 days.
+type PartitionSpec struct {
+	// Table is the partitioned table, optionally schema-qualified
+	Table string
+	// Interval is the range each partition covers
+	Interval PartitionInterval
+	// Premake is how many partitions after the current one to create ahead of time
+	Premake int
+	// Retain is how many partitions before the current one to keep; older ones are dropped. Zero keeps
+	// them all.
+	Retain int
+}
+
+// Validate returns an error if the spec can't be maintained
+func (s PartitionSpec) Validate() error {
+	if s.Table == "" {
+		return errors.New("table cannot be empty")
+	}
+	if s.Interval != PartitionDaily && s.Interval != PartitionMonthly {
+		return fmt.Errorf("unknown partition interval %d", s.Interval)
+	}
+	if s.Premake < 0 {
+		return fmt.Errorf("premake cannot be negative, got %d", s.Premake)
+	}
+	if s.Retain < 0 {
+		return fmt.Errorf("retain cannot be negative, got %d", s.Retain)
+	}
+	return nil
+}
+
+// start returns the start of the partition containing t
+func (s PartitionSpec) start(t time.Time) time.Time {
+	t = t.UTC()
+	if s.Interval == PartitionMonthly {
+		return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+	}
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// add returns the start of the partition n partitions after the one starting at start
+func (s PartitionSpec) add(start time.Time, n int) time.Time {
+	if s.Interval == PartitionMonthly {
+		return start.AddDate(0, n, 0)
+	}
+	return start.AddDate(0, 0, n)
+}
+
+func (s PartitionSpec) layout() string {
+	if s.Interval == PartitionMonthly {
+		return "200601"
+	}
+	return "20060102"
+}
+
+// identifier returns the table's schema and name
+func (s PartitionSpec) identifier() pgx.Identifier {
+	return pgx.Identifier(strings.Split(s.Table, "."))
+}
+
+// partitionName returns the unqualified name of the partition starting at start
+func (s PartitionSpec) partitionName(start time.Time) string {
+	identifier := s.identifier()
+	return identifier[len(identifier)-1] + "_p" + start.Format(s.layout())
+}
+
+// partitionIdentifier returns the name of a partition in the table's schema
+func (s PartitionSpec) partitionIdentifier(name string) pgx.Identifier {
+	identifier := s.identifier()
+	return append(identifier[:len(identifier)-1:len(identifier)-1], name)
+}
+
+// parsePartitionName returns the start of the partition named name, or false if it isn't named like the
+// spec's partitions
+func (s PartitionSpec) parsePartitionName(name string) (time.Time, bool) {
+	identifier := s.identifier()
+	suffix, found := strings.CutPrefix(name, identifier[len(identifier)-1]+"_p")
+	if !found || len(suffix) != len(s.layout()) {
+		return time.Time{}, false
+	}
+	start, err := time.Parse(s.layout(), suffix)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return start, true
+}
+
+// PartitionMaintenanceOutput is the partitions MaintainPartitions created and dropped
+type PartitionMaintenanceOutput struct {
+	Created []string
+	Dropped []string
+}
+
+// MaintainPartitions creates the partitions of spec's table from the one containing now through the
+// premade ones, and drops those older than it retains. Only partitions named like the ones it creates
+// are dropped, so a default partition or hand-made ones are left alone. It's safe to run on several
+// instances at once; they take turns.
+func MaintainPartitions(ctx context.Context, db DB, spec PartitionSpec, now time.Time) (*PartitionMaintenanceOutput, error) {
+	if err := spec.Validate(); err != nil {
+		return nil, kit.WrapError(err, "invalid partition spec for %s", spec.Table)
+	}
+
+	output := &PartitionMaintenanceOutput{}
+	err := InTx(ctx, db, func(tx Tx) error {
+		output = &PartitionMaintenanceOutput{}
+
+		if _, err := tx.Exec(ctx, "SELECT pg_advisory_xact_lock($1)", advisoryLockKey("partition", spec.Table)); err != nil {
+			return kit.WrapError(err, "failed to lock partitions of %s", spec.Table)
+		}
+
+		existing, err := listPartitions(ctx, tx, spec)
+		if err != nil {
+			return err
+		}
+
+		current := spec.start(now)
+		for i := 0; i <= spec.Premake; i++ {
+			start := spec.add(current, i)
+			name := spec.partitionName(start)
+			if existing[name] {
+				continue
+			}
+
+			sql := fmt.Sprintf("CREATE TABLE %s PARTITION OF %s FOR VALUES FROM ('%s') TO ('%s')",
+				spec.partitionIdentifier(name).Sanitize(),
+				spec.identifier().Sanitize(),
+				start.Format(partitionBoundLayout),
+				spec.add(start, 1).Format(partitionBoundLayout))
+			if _, err := tx.Exec(ctx, sql); err != nil {
+				return kit.WrapError(err, "failed to create partition %s", name)
+			}
+			output.Created = append(output.Created, name)
+		}
+
+		if spec.Retain == 0 {
+			return nil
+		}
+
+		oldest := spec.add(current, -spec.Retain)
+		for name := range existing {
+			start, ok := spec.parsePartitionName(name)
+			if !ok || !start.Before(oldest) {
+				continue
+			}
+
+			if _, err := tx.Exec(ctx, "DROP TABLE "+spec.partitionIdentifier(name).Sanitize()); err != nil {
+				return kit.WrapError(err, "failed to drop partition %s", name)
+			}
+			output.Dropped = append(output.Dropped, name)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, name := range output.Created {
+		logger.InfoContext(ctx, "created partition", logfields.Table(spec.Table), "partition", name)
+	}
+	for _, name := range output.Dropped {
+		logger.InfoContext(ctx, "dropped partition", logfields.Table(spec.Table), "partition", name)
+	}
+
+	return output, nil
+}
+
+// partitionBoundLayout formats partition bounds so they're the same instant whether the partition key
+// is a date, timestamp, or timestamptz
+const partitionBoundLayout = "2006-01-02 15:04:05+00"
+
+// listPartitions returns the names of the partitions of spec's table
+func listPartitions(ctx context.Context, tx Tx, spec PartitionSpec) (map[string]bool, error) {
+	rows, err := tx.Query(ctx, "SELECT c.relname FROM pg_inherits i JOIN pg_class c ON c.oid = i.inhrelid WHERE i.inhparent = $1::regclass", spec.identifier().Sanitize())
+	if err != nil {
+		return nil, kit.WrapError(err, "failed to list partitions of %s", spec.Table)
+	}
+	defer rows.Close()
+
+	partitions := map[string]bool{}
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, kit.WrapError(err, "failed to scan partition of %s", spec.Table)
+		}
+		partitions[name] = true
+	}
+	if err := rows.Err(); err != nil {
+		return nil, kit.WrapError(err, "error iterating partitions of %s", spec.Table)
+	}
+
+	return partitions, nil
+}
+
+// PartitionManager maintains the partitions of registered tables on an interval. Every instance of a
+// service can run one.
+type PartitionManager struct {
+	db       DB
+	clock    kit.ClockInterface
+	interval time.Duration
+	mu       sync.Mutex
+	specs    []PartitionSpec
+}
+
+// PartitionManagerOption configures a PartitionManager
+type PartitionManagerOption func(*PartitionManager)
+
+// WithPartitionManagerClock sets the clock used to pick the current partition
+func WithPartitionManagerClock(clock kit.ClockInterface) PartitionManagerOption {
+	return func(m *PartitionManager) {
+		m.clock = clock
+	}
+}
+
+// WithPartitionCheckInterval sets how often Run maintains the partitions; the default is an hour
+func WithPartitionCheckInterval(interval time.Duration) PartitionManagerOption {
+	return func(m *PartitionManager) {
+		m.interval = interval
+	}
+}
+
+// NewPartitionManager returns a manager for partitioned tables in db
+func NewPartitionManager(db DB, options ...PartitionManagerOption) *PartitionManager {
+	manager := &PartitionManager{db: db, clock: kit.NewClock(), interval: time.Hour}
+	for _, option := range options {
+		option(manager)
+	}
+	return manager
+}
+
+// RegisterTable adds a table to be maintained as spec declares
+func (m *PartitionManager) RegisterTable(spec PartitionSpec) error {
+	if err := spec.Validate(); err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.specs = append(m.specs, spec)
+	return nil
+}
+
+// MaintainAll maintains the partitions of every registered table, continuing past errors, and returns
+// the errors joined
+func (m *PartitionManager) MaintainAll(ctx context.Context) error {
+	m.mu.Lock()
+	specs := append([]PartitionSpec(nil), m.specs...)
+	m.mu.Unlock()
+
+	var errs []error
+	for _, spec := range specs {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if _, err := MaintainPartitions(ctx, m.db, spec, m.clock.Now()); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Run maintains the registered tables' partitions right away and then on the check interval until ctx
+// is canceled. Errors are logged and maintenance is tried again at the next interval.
+func (m *PartitionManager) Run(ctx context.Context) error {
+	for {
+		if err := m.MaintainAll(ctx); err != nil && ctx.Err() == nil {
+			logger.ErrorContext(ctx, "error maintaining partitions", "error", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(m.interval):
+		}
+	}
+}
@@ -0,0 +1,161 @@
+package pgkit
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/half-ogre/go-kit/kit"
+)
+
+// newPartitionFakeDB returns a DB whose table has the existing partitions and which records the
+// statements executed against it
+func newPartitionFakeDB(existing ...string) (*FakeDB, *[]string) {
+	statements := []string{}
+	fakeTx := &FakeTx{
+		ExecFake: func(ctx context.Context, query string, args ...any) (sql.Result, error) {
+			statements = append(statements, query)
+			return nil, nil
+		},
+		QueryFake: func(ctx context.Context, query string, args ...any) (Rows, error) {
+			i := -1
+			return &FakeRows{
+				NextFake: func() bool {
+					i++
+					return i < len(existing)
+				},
+				ScanFake: func(dest ...any) error {
+					*dest[0].(*string) = existing[i]
+					return nil
+				},
+				CloseFake: func() error { return nil },
+				ErrFake:   func() error { return nil },
+			}, nil
+		},
+		CommitFake:   func(ctx context.Context) error { return nil },
+		RollbackFake: func(ctx context.Context) error { return nil },
+	}
+	return &FakeDB{BeginFake: func(ctx context.Context) (Tx, error) { return fakeTx, nil }}, &statements
+}
+
+func TestMaintainPartitions(t *testing.T) {
+	now := time.Date(2024, 1, 31, 23, 0, 0, 0, time.UTC)
+
+	t.Run("creates_the_current_and_premade_monthly_partitions", func(t *testing.T) {
+		fakeDB, statements := newPartitionFakeDB()
+		spec := PartitionSpec{Table: "metrics.samples", Interval: PartitionMonthly, Premake: 1}
+
+		output, err := MaintainPartitions(context.Background(), fakeDB, spec, now)
+
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"samples_p202401", "samples_p202402"}, output.Created)
+		assert.Empty(t, output.Dropped)
+		assert.Equal(t, []string{
+			"SELECT pg_advisory_xact_lock($1)",
+			`CREATE TABLE "metrics"."samples_p202401" PARTITION OF "metrics"."samples" FOR VALUES FROM ('2024-01-01 00:00:00+00') TO ('2024-02-01 00:00:00+00')`,
+			`CREATE TABLE "metrics"."samples_p202402" PARTITION OF "metrics"."samples" FOR VALUES FROM ('2024-02-01 00:00:00+00') TO ('2024-03-01 00:00:00+00')`,
+		}, *statements)
+	})
+
+	t.Run("skips_daily_partitions_that_exist", func(t *testing.T) {
+		fakeDB, statements := newPartitionFakeDB("events_p20240131")
+		spec := PartitionSpec{Table: "events", Interval: PartitionDaily, Premake: 1}
+
+		output, err := MaintainPartitions(context.Background(), fakeDB, spec, now)
+
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"events_p20240201"}, output.Created)
+		assert.Equal(t, `CREATE TABLE "events_p20240201" PARTITION OF "events" FOR VALUES FROM ('2024-02-01 00:00:00+00') TO ('2024-02-02 00:00:00+00')`, (*statements)[1])
+	})
+
+	t.Run("drops_partitions_older_than_it_retains", func(t *testing.T) {
+		fakeDB, statements := newPartitionFakeDB("events_p20240128", "events_p20240129", "events_p20240130", "events_p20240131", "events_default", "events_p2024")
+		spec := PartitionSpec{Table: "events", Interval: PartitionDaily, Retain: 2}
+
+		output, err := MaintainPartitions(context.Background(), fakeDB, spec, now)
+
+		assert.NoError(t, err)
+		assert.Empty(t, output.Created)
+		sort.Strings(output.Dropped)
+		assert.Equal(t, []string{"events_p20240128"}, output.Dropped)
+		assert.Equal(t, []string{"SELECT pg_advisory_xact_lock($1)", `DROP TABLE "events_p20240128"`}, *statements)
+	})
+
+	t.Run("keeps_every_partition_when_retain_is_zero", func(t *testing.T) {
+		fakeDB, _ := newPartitionFakeDB("events_p20200101", "events_p20240131")
+		spec := PartitionSpec{Table: "events", Interval: PartitionDaily}
+
+		output, err := MaintainPartitions(context.Background(), fakeDB, spec, now)
+
+		assert.NoError(t, err)
+		assert.Empty(t, output.Dropped)
+	})
+
+	t.Run("returns_an_error_for_an_invalid_spec", func(t *testing.T) {
+		_, err := MaintainPartitions(context.Background(), &FakeDB{}, PartitionSpec{Table: "events"}, now)
+
+		assert.EqualError(t, err, "invalid partition spec for events: unknown partition interval 0")
+	})
+
+	t.Run("returns_an_error_when_listing_partitions_fails", func(t *testing.T) {
+		fakeDB, _ := newPartitionFakeDB()
+		tx, _ := fakeDB.BeginFake(context.Background())
+		tx.(*FakeTx).QueryFake = func(ctx context.Context, query string, args ...any) (Rows, error) {
+			return nil, errors.New("theQueryError")
+		}
+
+		_, err := MaintainPartitions(context.Background(), fakeDB, PartitionSpec{Table: "events", Interval: PartitionDaily}, now)
+
+		assert.EqualError(t, err, "failed to list partitions of events: theQueryError")
+	})
+}
+
+func TestPartitionSpecValidate(t *testing.T) {
+	t.Run("returns_an_error_for_an_empty_table", func(t *testing.T) {
+		assert.EqualError(t, PartitionSpec{Interval: PartitionDaily}.Validate(), "table cannot be empty")
+	})
+
+	t.Run("returns_an_error_for_negative_counts", func(t *testing.T) {
+		assert.EqualError(t, PartitionSpec{Table: "a", Interval: PartitionDaily, Premake: -1}.Validate(), "premake cannot be negative, got -1")
+		assert.EqualError(t, PartitionSpec{Table: "a", Interval: PartitionDaily, Retain: -1}.Validate(), "retain cannot be negative, got -1")
+	})
+}
+
+func TestPartitionManager(t *testing.T) {
+	t.Run("maintains_every_registered_table", func(t *testing.T) {
+		fakeDB, statements := newPartitionFakeDB()
+		clock := kit.NewClock(kit.WithFake(func() time.Time { return time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC) }))
+		manager := NewPartitionManager(fakeDB, WithPartitionManagerClock(clock))
+		assert.NoError(t, manager.RegisterTable(PartitionSpec{Table: "events", Interval: PartitionDaily}))
+		assert.NoError(t, manager.RegisterTable(PartitionSpec{Table: "samples", Interval: PartitionMonthly}))
+
+		err := manager.MaintainAll(context.Background())
+
+		assert.NoError(t, err)
+		assert.Contains(t, *statements, `CREATE TABLE "events_p20240102" PARTITION OF "events" FOR VALUES FROM ('2024-01-02 00:00:00+00') TO ('2024-01-03 00:00:00+00')`)
+		assert.Contains(t, *statements, `CREATE TABLE "samples_p202401" PARTITION OF "samples" FOR VALUES FROM ('2024-01-01 00:00:00+00') TO ('2024-02-01 00:00:00+00')`)
+	})
+
+	t.Run("returns_an_error_for_an_invalid_registration", func(t *testing.T) {
+		manager := NewPartitionManager(&FakeDB{})
+
+		err := manager.RegisterTable(PartitionSpec{Interval: PartitionDaily})
+
+		assert.EqualError(t, err, "table cannot be empty")
+	})
+
+	t.Run("runs_until_the_context_is_canceled", func(t *testing.T) {
+		manager := NewPartitionManager(&FakeDB{})
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		err := manager.Run(ctx)
+
+		assert.NoError(t, err)
+	})
+}
@@ -0,0 +1,72 @@
+package pgkit
+
+import (
+	"errors"
+
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// SQLSTATE codes for the errors services most often need to branch on.
+// See https://www.postgresql.org/docs/current/errcodes-appendix.html for the full list.
+const (
+	SQLStateNotNullViolation     = "23502"
+	SQLStateForeignKeyViolation  = "23503"
+	SQLStateUniqueViolation      = "23505"
+	SQLStateCheckViolation       = "23514"
+	SQLStateSerializationFailure = "40001"
+	SQLStateDeadlockDetected     = "40P01"
+	SQLStateQueryCanceled        = "57014"
+)
+
+// Code returns the SQLSTATE code of the PostgreSQL error in err's chain, or "" if there is none
+func Code(err error) string {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		return pgErr.Code
+	}
+	return ""
+}
+
+// ConstraintName returns the name of the constraint violated by the PostgreSQL error in err's chain, or "" if there is none
+func ConstraintName(err error) string {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		return pgErr.ConstraintName
+	}
+	return ""
+}
+
+// IsUniqueViolation reports whether err is a unique constraint violation
+func IsUniqueViolation(err error) bool {
+	return Code(err) == SQLStateUniqueViolation
+}
+
+// IsForeignKeyViolation reports whether err is a foreign key constraint violation
+func IsForeignKeyViolation(err error) bool {
+	return Code(err) == SQLStateForeignKeyViolation
+}
+
+// IsNotNullViolation reports whether err is a not-null constraint violation
+func IsNotNullViolation(err error) bool {
+	return Code(err) == SQLStateNotNullViolation
+}
+
+// IsCheckViolation reports whether err is a check constraint violation
+func IsCheckViolation(err error) bool {
+	return Code(err) == SQLStateCheckViolation
+}
+
+// IsSerializationFailure reports whether err is a serialization failure, meaning the transaction can be retried
+func IsSerializationFailure(err error) bool {
+	return Code(err) == SQLStateSerializationFailure
+}
+
+// IsDeadlockDetected reports whether err is a deadlock, meaning the transaction can be retried
+func IsDeadlockDetected(err error) bool {
+	return Code(err) == SQLStateDeadlockDetected
+}
+
+// IsQueryCanceled reports whether err is a query cancellation, including statement timeouts
+func IsQueryCanceled(err error) bool {
+	return Code(err) == SQLStateQueryCanceled
+}
@@ -0,0 +1,71 @@
+package pgkit
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/half-ogre/go-kit/kit"
+)
+
+func TestCode(t *testing.T) {
+	t.Run("returns_the_sqlstate_of_a_pg_error", func(t *testing.T) {
+		err := &pgconn.PgError{Code: "theCode"}
+
+		assert.Equal(t, "theCode", Code(err))
+	})
+
+	t.Run("returns_the_sqlstate_of_a_wrapped_pg_error", func(t *testing.T) {
+		err := kit.WrapError(&pgconn.PgError{Code: "theCode"}, "a wrapping message")
+
+		assert.Equal(t, "theCode", Code(err))
+	})
+
+	t.Run("returns_empty_for_other_errors", func(t *testing.T) {
+		assert.Equal(t, "", Code(errors.New("an error")))
+	})
+
+	t.Run("returns_empty_for_nil", func(t *testing.T) {
+		assert.Equal(t, "", Code(nil))
+	})
+}
+
+func TestConstraintName(t *testing.T) {
+	t.Run("returns_the_constraint_name_of_a_wrapped_pg_error", func(t *testing.T) {
+		err := kit.WrapError(&pgconn.PgError{Code: SQLStateUniqueViolation, ConstraintName: "theConstraint"}, "a wrapping message")
+
+		assert.Equal(t, "theConstraint", ConstraintName(err))
+	})
+
+	t.Run("returns_empty_for_other_errors", func(t *testing.T) {
+		assert.Equal(t, "", ConstraintName(errors.New("an error")))
+	})
+}
+
+func TestErrorClassification(t *testing.T) {
+	classifiers := map[string]func(error) bool{
+		SQLStateUniqueViolation:      IsUniqueViolation,
+		SQLStateForeignKeyViolation:  IsForeignKeyViolation,
+		SQLStateNotNullViolation:     IsNotNullViolation,
+		SQLStateCheckViolation:       IsCheckViolation,
+		SQLStateSerializationFailure: IsSerializationFailure,
+		SQLStateDeadlockDetected:     IsDeadlockDetected,
+		SQLStateQueryCanceled:        IsQueryCanceled,
+	}
+
+	for code, classifier := range classifiers {
+		t.Run("classifies_"+code, func(t *testing.T) {
+			err := kit.WrapError(&pgconn.PgError{Code: code}, "a wrapping message")
+
+			assert.True(t, classifier(err))
+			for otherCode, otherClassifier := range classifiers {
+				if otherCode != code {
+					assert.False(t, otherClassifier(err), "classifier for %s matched %s", otherCode, code)
+				}
+			}
+			assert.False(t, classifier(errors.New("an error")))
+		})
+	}
+}
@@ -10,8 +10,13 @@ import (
 	"github.com/jackc/pgx/v5/pgxpool"
 
 	"github.com/half-ogre/go-kit/kit"
+	"github.com/half-ogre/go-kit/logkit"
 )
 
+// logger is the logger for the package's own records, so operators can turn them up or down with
+// logkit.SetLevelSpec, e.g. LOG_LEVEL=info,pgkit=debug
+var logger = logkit.Logger("pgkit")
+
 // Row is an interface for scanning a single row result
 type Row interface {
 	Scan(dest ...any) error
@@ -35,8 +40,10 @@ type DB interface {
 
 // dbOptions holds both pool config and context options
 type dbOptions struct {
-	config *pgxpool.Config
-	ctx    context.Context
+	config            *pgxpool.Config
+	ctx               context.Context
+	statementCache    *statementCacheTracer
+	statementTimeouts StatementTimeouts
 }
 
 // DBOption is a functional option for configuring NewDB
@@ -61,6 +68,10 @@ func NewDB(connectionString string, opts ...DBOption) (DB, error) {
 		opt(options)
 	}
 
+	if options.statementCache != nil {
+		options.statementCache.install(options.config.ConnConfig)
+	}
+
 	pool, err := pgxpool.NewWithConfig(options.ctx, options.config)
 	if err != nil {
 		return nil, kit.WrapError(err, "failed to create connection pool")
@@ -71,7 +82,15 @@ func NewDB(connectionString string, opts ...DBOption) (DB, error) {
 		return nil, kit.WrapError(err, "failed to ping database")
 	}
 
-	return &poolDB{pool: pool}, nil
+	var db DB = &poolDB{pool: pool}
+	if options.statementTimeouts != (StatementTimeouts{}) {
+		db = &statementTimeoutDB{DB: db, timeouts: options.statementTimeouts}
+	}
+	if options.statementCache != nil {
+		db = &statementCachingDB{DB: db, cache: options.statementCache}
+	}
+
+	return db, nil
 }
 
 // WithPoolContext sets the context used for creating the connection pool
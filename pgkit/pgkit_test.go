@@ -204,6 +204,86 @@ func TestRunMigrations(t *testing.T) {
 	})
 }
 
+func TestWithAfterMigration(t *testing.T) {
+	newFakeDB := func() *FakeDB {
+		return &FakeDB{
+			ExecFake: func(ctx context.Context, query string, args ...any) (sql.Result, error) {
+				return nil, nil
+			},
+			QueryRowFake: func(ctx context.Context, query string, args ...any) Row {
+				return &FakeRow{
+					ScanFake: func(dest ...any) error {
+						*dest[0].(*bool) = false
+						return nil
+					},
+				}
+			},
+		}
+	}
+
+	t.Run("runs_the_hooks_in_order_after_each_applied_migration", func(t *testing.T) {
+		var calls []string
+		migrator := NewMigrator(
+			WithAfterMigration(func(ctx context.Context, migration Migration) error {
+				assert.True(t, migration.Applied)
+				assert.NotNil(t, migration.AppliedAt)
+				calls = append(calls, "first:"+migration.Description)
+				return nil
+			}),
+			WithAfterMigration(func(ctx context.Context, migration Migration) error {
+				calls = append(calls, "second:"+migration.Filename)
+				return nil
+			}),
+		)
+
+		err := migrator.RunMigrations(newFakeDB(), "testdata")
+
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"first:initial", "second:001_initial.sql", "first:add_email", "second:002_add_email.sql"}, calls)
+	})
+
+	t.Run("does_not_run_the_hooks_for_migrations_already_applied", func(t *testing.T) {
+		fakeDB := newFakeDB()
+		fakeDB.QueryRowFake = func(ctx context.Context, query string, args ...any) Row {
+			return &FakeRow{
+				ScanFake: func(dest ...any) error {
+					*dest[0].(*bool) = args[0] == "001_initial.sql"
+					return nil
+				},
+			}
+		}
+		var versions []int
+		migrator := NewMigrator(WithAfterMigration(func(ctx context.Context, migration Migration) error {
+			versions = append(versions, migration.Version)
+			return nil
+		}))
+
+		err := migrator.RunMigrations(fakeDB, "testdata")
+
+		assert.NoError(t, err)
+		assert.Equal(t, []int{2}, versions)
+	})
+
+	t.Run("stops_the_run_when_a_hook_returns_an_error", func(t *testing.T) {
+		execCallCount := 0
+		fakeDB := newFakeDB()
+		fakeDB.ExecFake = func(ctx context.Context, query string, args ...any) (sql.Result, error) {
+			execCallCount++
+			return nil, nil
+		}
+		migrator := NewMigrator(WithAfterMigration(func(ctx context.Context, migration Migration) error {
+			return assert.AnError
+		}))
+
+		err := migrator.RunMigrations(fakeDB, "testdata")
+
+		assert.ErrorIs(t, err, assert.AnError)
+		assert.Contains(t, err.Error(), "after-migration hook failed for 001_initial.sql")
+		// CREATE TABLE + first migration + its INSERT; the second migration is not run
+		assert.Equal(t, 3, execCallCount)
+	})
+}
+
 func TestRunMigrationsToVersion(t *testing.T) {
 	t.Run("runs_migrations_up_to_specified_version", func(t *testing.T) {
 		execCallCount := 0
@@ -0,0 +1,119 @@
+package pgkit
+
+import (
+	"context"
+	"sync/atomic"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/multitracer"
+)
+
+// StatementCacheStats reports how pgx's per-connection statement caches are doing, counted by a tracer
+// on every connection in the pool, including queries inside transactions
+type StatementCacheStats struct {
+	// Hits are queries whose SQL was already prepared on the connection they ran on
+	Hits int64
+	// Misses are queries that had to prepare their SQL first
+	Misses int64
+	// Capacity is how many statements each connection caches
+	Capacity int
+}
+
+// HitRate returns the fraction of lookups that were cache hits, or 0 if there have been no lookups
+func (s StatementCacheStats) HitRate() float64 {
+	total := s.Hits + s.Misses
+	if total == 0 {
+		return 0
+	}
+	return float64(s.Hits) / float64(total)
+}
+
+// StatementCacheStatsReporter is implemented by DBs created with WithStatementCache
+type StatementCacheStatsReporter interface {
+	StatementCacheStats() StatementCacheStats
+}
+
+// GetStatementCacheStats returns the statement cache stats for db, or false if db does not track them
+func GetStatementCacheStats(db DB) (StatementCacheStats, bool) {
+	reporter, ok := db.(StatementCacheStatsReporter)
+	if !ok {
+		return StatementCacheStats{}, false
+	}
+	return reporter.StatementCacheStats(), true
+}
+
+// WithStatementCache configures each pooled connection to prepare and cache up to capacity
+// statements, keyed by SQL text, and counts cache hits and misses for the pool. A capacity of 0 or
+// less disables prepared statement caching entirely.
+func WithStatementCache(capacity int) DBOption {
+	return func(opts *dbOptions) {
+		if capacity <= 0 {
+			opts.config.ConnConfig.StatementCacheCapacity = 0
+			opts.config.ConnConfig.DefaultQueryExecMode = pgx.QueryExecModeExec
+			opts.statementCache = nil
+			return
+		}
+
+		opts.config.ConnConfig.StatementCacheCapacity = capacity
+		opts.config.ConnConfig.DefaultQueryExecMode = pgx.QueryExecModeCacheStatement
+		opts.statementCache = &statementCacheTracer{capacity: capacity}
+	}
+}
+
+// statementCacheTracer counts pgx's statement cache lookups. With QueryExecModeCacheStatement every
+// query looks its SQL up in its connection's cache and prepares it when it isn't there, so queries
+// that didn't prepare anything were hits.
+type statementCacheTracer struct {
+	capacity int
+	queries  atomic.Int64
+	prepares atomic.Int64
+}
+
+// install adds the tracer to config, alongside any tracer already set
+func (t *statementCacheTracer) install(config *pgx.ConnConfig) {
+	if config.Tracer == nil {
+		config.Tracer = t
+		return
+	}
+	config.Tracer = multitracer.New(config.Tracer, t)
+}
+
+func (t *statementCacheTracer) TraceQueryStart(ctx context.Context, _ *pgx.Conn, _ pgx.TraceQueryStartData) context.Context {
+	t.queries.Add(1)
+	return ctx
+}
+
+func (t *statementCacheTracer) TraceQueryEnd(context.Context, *pgx.Conn, pgx.TraceQueryEndData) {}
+
+func (t *statementCacheTracer) TracePrepareStart(ctx context.Context, _ *pgx.Conn, _ pgx.TracePrepareStartData) context.Context {
+	return ctx
+}
+
+func (t *statementCacheTracer) TracePrepareEnd(_ context.Context, _ *pgx.Conn, data pgx.TracePrepareEndData) {
+	if !data.AlreadyPrepared {
+		t.prepares.Add(1)
+	}
+}
+
+func (t *statementCacheTracer) stats() StatementCacheStats {
+	// Prepares are loaded first: a query is counted before it prepares, so the hits never go negative
+	misses := t.prepares.Load()
+	hits := t.queries.Load() - misses
+	return StatementCacheStats{Hits: hits, Misses: misses, Capacity: t.capacity}
+}
+
+// statementCachingDB wraps a DB to report the stats of its statement cache tracer. Transactions begun
+// on it run on the same traced connections, so their queries are counted too.
+type statementCachingDB struct {
+	DB
+	cache *statementCacheTracer
+}
+
+func (s *statementCachingDB) StatementCacheStats() StatementCacheStats {
+	return s.cache.stats()
+}
+
+func (s *statementCachingDB) StatementTimeouts() StatementTimeouts {
+	timeouts, _ := GetStatementTimeouts(s.DB)
+	return timeouts
+}
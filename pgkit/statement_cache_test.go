@@ -0,0 +1,117 @@
+package pgkit
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/multitracer"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithStatementCache(t *testing.T) {
+	t.Run("sets_the_statement_cache_capacity_and_exec_mode", func(t *testing.T) {
+		config, err := pgxpool.ParseConfig("postgres://localhost/aDatabase")
+		assert.NoError(t, err)
+		options := &dbOptions{config: config}
+
+		WithStatementCache(42)(options)
+
+		assert.Equal(t, 42, config.ConnConfig.StatementCacheCapacity)
+		assert.Equal(t, pgx.QueryExecModeCacheStatement, config.ConnConfig.DefaultQueryExecMode)
+		assert.NotNil(t, options.statementCache)
+		assert.Equal(t, 42, options.statementCache.capacity)
+		assert.Nil(t, config.ConnConfig.Tracer)
+	})
+
+	t.Run("disables_statement_caching_when_capacity_is_zero", func(t *testing.T) {
+		config, err := pgxpool.ParseConfig("postgres://localhost/aDatabase")
+		assert.NoError(t, err)
+		options := &dbOptions{config: config}
+
+		WithStatementCache(0)(options)
+
+		assert.Equal(t, 0, config.ConnConfig.StatementCacheCapacity)
+		assert.Equal(t, pgx.QueryExecModeExec, config.ConnConfig.DefaultQueryExecMode)
+		assert.Nil(t, options.statementCache)
+	})
+}
+
+func TestStatementCacheTracer(t *testing.T) {
+	t.Run("counts_queries_that_prepare_as_misses_and_the_rest_as_hits", func(t *testing.T) {
+		tracer := &statementCacheTracer{capacity: 2}
+		ctx := context.Background()
+
+		tracer.TraceQueryStart(ctx, nil, pgx.TraceQueryStartData{SQL: "SELECT 1"})
+		tracer.TracePrepareEnd(ctx, nil, pgx.TracePrepareEndData{})
+		tracer.TraceQueryStart(ctx, nil, pgx.TraceQueryStartData{SQL: "SELECT 1"})
+		tracer.TraceQueryStart(ctx, nil, pgx.TraceQueryStartData{SQL: "SELECT 1"})
+
+		assert.Equal(t, StatementCacheStats{Hits: 2, Misses: 1, Capacity: 2}, tracer.stats())
+	})
+
+	t.Run("does_not_count_statements_that_were_already_prepared_as_misses", func(t *testing.T) {
+		tracer := &statementCacheTracer{capacity: 2}
+		ctx := context.Background()
+
+		tracer.TraceQueryStart(ctx, nil, pgx.TraceQueryStartData{SQL: "SELECT 1"})
+		tracer.TracePrepareEnd(ctx, nil, pgx.TracePrepareEndData{AlreadyPrepared: true})
+
+		assert.Equal(t, StatementCacheStats{Hits: 1, Misses: 0, Capacity: 2}, tracer.stats())
+	})
+
+	t.Run("is_installed_as_the_tracer_when_there_is_none", func(t *testing.T) {
+		config, err := pgx.ParseConfig("postgres://localhost/aDatabase")
+		assert.NoError(t, err)
+		tracer := &statementCacheTracer{capacity: 2}
+
+		tracer.install(config)
+
+		assert.Same(t, tracer, config.Tracer)
+	})
+
+	t.Run("is_installed_alongside_an_existing_tracer", func(t *testing.T) {
+		config, err := pgx.ParseConfig("postgres://localhost/aDatabase")
+		assert.NoError(t, err)
+		existing := &statementCacheTracer{}
+		config.Tracer = existing
+		tracer := &statementCacheTracer{capacity: 2}
+
+		tracer.install(config)
+
+		multi, ok := config.Tracer.(*multitracer.Tracer)
+		assert.True(t, ok)
+		assert.Equal(t, []pgx.QueryTracer{existing, tracer}, multi.QueryTracers)
+	})
+}
+
+func TestStatementCacheStatsHitRate(t *testing.T) {
+	t.Run("returns_zero_when_there_have_been_no_lookups", func(t *testing.T) {
+		assert.Equal(t, 0.0, StatementCacheStats{}.HitRate())
+	})
+
+	t.Run("returns_the_fraction_of_hits", func(t *testing.T) {
+		assert.Equal(t, 0.75, StatementCacheStats{Hits: 3, Misses: 1}.HitRate())
+	})
+}
+
+func TestGetStatementCacheStats(t *testing.T) {
+	t.Run("returns_false_when_the_db_does_not_track_stats", func(t *testing.T) {
+		_, ok := GetStatementCacheStats(&FakeDB{})
+
+		assert.False(t, ok)
+	})
+
+	t.Run("returns_the_stats_of_the_caching_dbs_tracer", func(t *testing.T) {
+		tracer := &statementCacheTracer{capacity: 10}
+		tracer.TraceQueryStart(context.Background(), nil, pgx.TraceQueryStartData{})
+		tracer.TracePrepareEnd(context.Background(), nil, pgx.TracePrepareEndData{})
+		db := &statementCachingDB{DB: &FakeDB{}, cache: tracer}
+
+		stats, ok := GetStatementCacheStats(db)
+
+		assert.True(t, ok)
+		assert.Equal(t, StatementCacheStats{Hits: 0, Misses: 1, Capacity: 10}, stats)
+	})
+}
@@ -0,0 +1,139 @@
+package pgkit
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/half-ogre/go-kit/kit"
+)
+
+// StatementTimeouts are a DB's default statement timeouts for read and write transactions. A timeout
+// of 0 leaves the server's statement_timeout in place.
+type StatementTimeouts struct {
+	Read  time.Duration
+	Write time.Duration
+}
+
+// StatementTimeoutsProvider is implemented by DBs created with WithReadStatementTimeout or
+// WithWriteStatementTimeout
+type StatementTimeoutsProvider interface {
+	StatementTimeouts() StatementTimeouts
+}
+
+// GetStatementTimeouts returns the default statement timeouts for db, or false if db has none
+func GetStatementTimeouts(db DB) (StatementTimeouts, bool) {
+	provider, ok := db.(StatementTimeoutsProvider)
+	if !ok {
+		return StatementTimeouts{}, false
+	}
+	return provider.StatementTimeouts(), true
+}
+
+// WithReadStatementTimeout sets the default statement timeout for transactions run with InReadTx
+func WithReadStatementTimeout(d time.Duration) DBOption {
+	return func(opts *dbOptions) {
+		opts.statementTimeouts.Read = d
+	}
+}
+
+// WithWriteStatementTimeout sets the default statement timeout for transactions run with InWriteTx
+func WithWriteStatementTimeout(d time.Duration) DBOption {
+	return func(opts *dbOptions) {
+		opts.statementTimeouts.Write = d
+	}
+}
+
+// QueryOption configures a transaction run with InReadTx or InWriteTx
+type QueryOption func(*queryConfig)
+
+type queryConfig struct {
+	timeout    time.Duration
+	timeoutSet bool
+}
+
+// WithQueryTimeout sets the statement timeout for the transaction, overriding the DB's default. A
+// timeout of 0 leaves the server's statement_timeout in place.
+func WithQueryTimeout(d time.Duration) QueryOption {
+	return func(c *queryConfig) {
+		c.timeout = d
+		c.timeoutSet = true
+	}
+}
+
+// InReadTx runs fn in a transaction like InTx, bounding each statement in it by the DB's default read
+// statement timeout or the WithQueryTimeout option. A statement that runs longer is canceled by the
+// server and fails with a query_canceled error; canceling ctx still cancels it sooner.
+func InReadTx(ctx context.Context, db DB, fn func(tx Tx) error, options ...QueryOption) error {
+	if db == nil {
+		return fmt.Errorf("database connection cannot be nil")
+	}
+
+	defaults, _ := GetStatementTimeouts(db)
+	return inTimeoutTx(ctx, db, defaults.Read, fn, options)
+}
+
+// InWriteTx runs fn in a transaction like InTx, bounding each statement in it by the DB's default
+// write statement timeout or the WithQueryTimeout option
+func InWriteTx(ctx context.Context, db DB, fn func(tx Tx) error, options ...QueryOption) error {
+	if db == nil {
+		return fmt.Errorf("database connection cannot be nil")
+	}
+
+	defaults, _ := GetStatementTimeouts(db)
+	return inTimeoutTx(ctx, db, defaults.Write, fn, options)
+}
+
+func inTimeoutTx(ctx context.Context, db DB, timeout time.Duration, fn func(tx Tx) error, options []QueryOption) error {
+	config := queryConfig{}
+	for _, option := range options {
+		option(&config)
+	}
+	if config.timeoutSet {
+		timeout = config.timeout
+	}
+	if timeout < 0 {
+		return fmt.Errorf("statement timeout cannot be negative, got %s", timeout)
+	}
+
+	return InTx(ctx, db, func(tx Tx) error {
+		if err := setLocalStatementTimeout(ctx, tx, timeout); err != nil {
+			return err
+		}
+		return fn(tx)
+	})
+}
+
+// setLocalStatementTimeout sets statement_timeout for the rest of tx, rounding timeout up to a whole
+// millisecond so a small timeout isn't sent as 0, which disables it. A timeout of 0 does nothing.
+func setLocalStatementTimeout(ctx context.Context, tx Tx, timeout time.Duration) error {
+	if timeout <= 0 {
+		return nil
+	}
+
+	milliseconds := int64((timeout + time.Millisecond - 1) / time.Millisecond)
+	// set_config with is_local = true is SET LOCAL with a bind parameter instead of string formatting
+	if _, err := tx.Exec(ctx, "SELECT set_config('statement_timeout', $1, true)", strconv.FormatInt(milliseconds, 10)); err != nil {
+		return kit.WrapError(err, "failed to set statement timeout")
+	}
+	return nil
+}
+
+// statementTimeoutDB wraps a DB to carry its default statement timeouts
+type statementTimeoutDB struct {
+	DB
+	timeouts StatementTimeouts
+}
+
+func (s *statementTimeoutDB) Begin(ctx context.Context) (Tx, error) {
+	beginner, ok := s.DB.(TxBeginner)
+	if !ok {
+		return nil, fmt.Errorf("database connection does not support transactions")
+	}
+	return beginner.Begin(ctx)
+}
+
+func (s *statementTimeoutDB) StatementTimeouts() StatementTimeouts {
+	return s.timeouts
+}
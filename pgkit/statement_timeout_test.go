@@ -0,0 +1,179 @@
+package pgkit
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/assert"
+)
+
+func newRecordingTx(calls *[]string) *FakeTx {
+	return &FakeTx{
+		ExecFake: func(ctx context.Context, query string, args ...any) (sql.Result, error) {
+			*calls = append(*calls, fmt.Sprintf("%s %v", query, args))
+			return pgxResult{cmdTag: pgconn.NewCommandTag("SET")}, nil
+		},
+		CommitFake: func(ctx context.Context) error {
+			*calls = append(*calls, "COMMIT")
+			return nil
+		},
+	}
+}
+
+func TestWithStatementTimeouts(t *testing.T) {
+	t.Run("sets_the_read_and_write_statement_timeouts", func(t *testing.T) {
+		options := &dbOptions{}
+
+		WithReadStatementTimeout(5 * time.Second)(options)
+		WithWriteStatementTimeout(30 * time.Second)(options)
+
+		assert.Equal(t, StatementTimeouts{Read: 5 * time.Second, Write: 30 * time.Second}, options.statementTimeouts)
+	})
+}
+
+func TestInReadTx(t *testing.T) {
+	t.Run("sets_the_default_read_statement_timeout_before_running_fn", func(t *testing.T) {
+		var calls []string
+		fakeTx := newRecordingTx(&calls)
+		db := &statementTimeoutDB{
+			DB:       &FakeDB{BeginFake: func(ctx context.Context) (Tx, error) { return fakeTx, nil }},
+			timeouts: StatementTimeouts{Read: 5 * time.Second, Write: 30 * time.Second},
+		}
+
+		err := InReadTx(context.Background(), db, func(tx Tx) error {
+			calls = append(calls, "fn")
+			return nil
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"SELECT set_config('statement_timeout', $1, true) [5000]", "fn", "COMMIT"}, calls)
+	})
+
+	t.Run("uses_the_query_timeout_option_over_the_default", func(t *testing.T) {
+		var calls []string
+		fakeTx := newRecordingTx(&calls)
+		db := &statementTimeoutDB{
+			DB:       &FakeDB{BeginFake: func(ctx context.Context) (Tx, error) { return fakeTx, nil }},
+			timeouts: StatementTimeouts{Read: 5 * time.Second},
+		}
+
+		err := InReadTx(context.Background(), db, func(tx Tx) error { return nil }, WithQueryTimeout(250*time.Millisecond))
+
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"SELECT set_config('statement_timeout', $1, true) [250]", "COMMIT"}, calls)
+	})
+
+	t.Run("leaves_the_server_timeout_when_the_query_timeout_option_is_zero", func(t *testing.T) {
+		var calls []string
+		fakeTx := newRecordingTx(&calls)
+		db := &statementTimeoutDB{
+			DB:       &FakeDB{BeginFake: func(ctx context.Context) (Tx, error) { return fakeTx, nil }},
+			timeouts: StatementTimeouts{Read: 5 * time.Second},
+		}
+
+		err := InReadTx(context.Background(), db, func(tx Tx) error { return nil }, WithQueryTimeout(0))
+
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"COMMIT"}, calls)
+	})
+
+	t.Run("leaves_the_server_timeout_when_the_db_has_no_defaults", func(t *testing.T) {
+		var calls []string
+		fakeTx := newRecordingTx(&calls)
+		fakeDB := &FakeDB{BeginFake: func(ctx context.Context) (Tx, error) { return fakeTx, nil }}
+
+		err := InReadTx(context.Background(), fakeDB, func(tx Tx) error { return nil })
+
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"COMMIT"}, calls)
+	})
+
+	t.Run("rounds_a_sub_millisecond_timeout_up_to_a_millisecond", func(t *testing.T) {
+		var calls []string
+		fakeTx := newRecordingTx(&calls)
+		fakeDB := &FakeDB{BeginFake: func(ctx context.Context) (Tx, error) { return fakeTx, nil }}
+
+		err := InReadTx(context.Background(), fakeDB, func(tx Tx) error { return nil }, WithQueryTimeout(time.Microsecond))
+
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"SELECT set_config('statement_timeout', $1, true) [1]", "COMMIT"}, calls)
+	})
+
+	t.Run("rolls_back_when_the_timeout_cannot_be_set", func(t *testing.T) {
+		rolledBack := false
+		fakeTx := &FakeTx{
+			ExecFake: func(ctx context.Context, query string, args ...any) (sql.Result, error) {
+				return nil, errors.New("the exec error")
+			},
+			RollbackFake: func(ctx context.Context) error {
+				rolledBack = true
+				return nil
+			},
+		}
+		fakeDB := &FakeDB{BeginFake: func(ctx context.Context) (Tx, error) { return fakeTx, nil }}
+		fnCalled := false
+
+		err := InReadTx(context.Background(), fakeDB, func(tx Tx) error {
+			fnCalled = true
+			return nil
+		}, WithQueryTimeout(time.Second))
+
+		assert.EqualError(t, err, "failed to set statement timeout: the exec error")
+		assert.True(t, rolledBack)
+		assert.False(t, fnCalled)
+	})
+
+	t.Run("returns_an_error_when_the_timeout_is_negative", func(t *testing.T) {
+		err := InReadTx(context.Background(), &FakeDB{}, func(tx Tx) error { return nil }, WithQueryTimeout(-time.Second))
+
+		assert.EqualError(t, err, "statement timeout cannot be negative, got -1s")
+	})
+
+	t.Run("returns_an_error_when_db_is_nil", func(t *testing.T) {
+		err := InReadTx(context.Background(), nil, func(tx Tx) error { return nil })
+
+		assert.EqualError(t, err, "database connection cannot be nil")
+	})
+}
+
+func TestInWriteTx(t *testing.T) {
+	t.Run("sets_the_default_write_statement_timeout_before_running_fn", func(t *testing.T) {
+		var calls []string
+		fakeTx := newRecordingTx(&calls)
+		db := &statementTimeoutDB{
+			DB:       &FakeDB{BeginFake: func(ctx context.Context) (Tx, error) { return fakeTx, nil }},
+			timeouts: StatementTimeouts{Read: 5 * time.Second, Write: 30 * time.Second},
+		}
+
+		err := InWriteTx(context.Background(), db, func(tx Tx) error {
+			calls = append(calls, "fn")
+			return nil
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"SELECT set_config('statement_timeout', $1, true) [30000]", "fn", "COMMIT"}, calls)
+	})
+}
+
+func TestGetStatementTimeouts(t *testing.T) {
+	t.Run("returns_false_when_the_db_has_no_defaults", func(t *testing.T) {
+		_, ok := GetStatementTimeouts(&FakeDB{})
+
+		assert.False(t, ok)
+	})
+
+	t.Run("returns_the_defaults_through_the_statement_caching_db", func(t *testing.T) {
+		timeouts := StatementTimeouts{Read: time.Second, Write: 2 * time.Second}
+		db := &statementCachingDB{DB: &statementTimeoutDB{DB: &FakeDB{}, timeouts: timeouts}, cache: &statementCacheTracer{capacity: 1}}
+
+		actual, ok := GetStatementTimeouts(db)
+
+		assert.True(t, ok)
+		assert.Equal(t, timeouts, actual)
+	})
+}
@@ -0,0 +1,69 @@
+package pgkit
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/half-ogre/go-kit/kit"
+	"github.com/half-ogre/go-kit/tenantkit"
+)
+
+// TenantSetting is the setting WithTenant sets, for use in row level security policies, e.g.
+// USING (tenant_id = current_setting('app.tenant_id'))
+const TenantSetting = "app.tenant_id"
+
+// WithTenant runs fn in a transaction scoped to tenantID. The tenant is set with the equivalent of
+// SET LOCAL app.tenant_id, so it ends with the transaction and can't leak to other users of the pooled
+// connection. Queries made through tx are then filtered by the table's row level security policies.
+func WithTenant(ctx context.Context, db DB, tenantID string, fn func(tx Tx) error) error {
+	if db == nil {
+		return fmt.Errorf("database connection cannot be nil")
+	}
+	if tenantID == "" {
+		return fmt.Errorf("tenant ID cannot be empty")
+	}
+
+	return InTx(ctx, db, func(tx Tx) error {
+		// set_config with is_local = true is SET LOCAL with a bind parameter instead of string formatting
+		_, err := tx.Exec(ctx, "SELECT set_config($1, $2, true)", TenantSetting, tenantID)
+		if err != nil {
+			return kit.WrapError(err, "failed to set tenant %s", tenantID)
+		}
+
+		return fn(tx)
+	})
+}
+
+// WithContextTenant runs fn in a transaction scoped, as with WithTenant, to the tenant carried by ctx,
+// e.g. by tenantkit.Middleware. It returns tenantkit.ErrNoTenant if ctx has no tenant rather than
+// running unscoped.
+func WithContextTenant(ctx context.Context, db DB, fn func(tx Tx) error) error {
+	tenant, err := tenantkit.Require(ctx)
+	if err != nil {
+		return err
+	}
+
+	return WithTenant(ctx, db, tenant.ID, fn)
+}
+
+// WithTenantSchema runs fn in a transaction whose search_path is the schema of the tenant carried by
+// ctx, e.g. tenant_acme, for databases with a schema per tenant instead of row level security. Like
+// WithTenant, the setting ends with the transaction.
+func WithTenantSchema(ctx context.Context, db DB, fn func(tx Tx) error) error {
+	if db == nil {
+		return fmt.Errorf("database connection cannot be nil")
+	}
+	tenant, err := tenantkit.Require(ctx)
+	if err != nil {
+		return err
+	}
+
+	return InTx(ctx, db, func(tx Tx) error {
+		_, err := tx.Exec(ctx, "SELECT set_config('search_path', $1, true)", tenant.SchemaName())
+		if err != nil {
+			return kit.WrapError(err, "failed to set search path for tenant %s", tenant.ID)
+		}
+
+		return fn(tx)
+	})
+}
@@ -0,0 +1,156 @@
+package pgkit
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/half-ogre/go-kit/tenantkit"
+)
+
+func TestWithTenant(t *testing.T) {
+	t.Run("sets_the_tenant_for_the_transaction_before_running_fn", func(t *testing.T) {
+		var calls []string
+		var actualArgs []any
+		fakeTx := &FakeTx{
+			ExecFake: func(ctx context.Context, query string, args ...any) (sql.Result, error) {
+				calls = append(calls, query)
+				actualArgs = args
+				return pgxResult{cmdTag: pgconn.NewCommandTag("SELECT 1")}, nil
+			},
+			CommitFake: func(ctx context.Context) error {
+				calls = append(calls, "COMMIT")
+				return nil
+			},
+		}
+		fakeDB := &FakeDB{
+			BeginFake: func(ctx context.Context) (Tx, error) { return fakeTx, nil },
+		}
+
+		err := WithTenant(context.Background(), fakeDB, "theTenant", func(tx Tx) error {
+			calls = append(calls, "fn")
+			return nil
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"SELECT set_config($1, $2, true)", "fn", "COMMIT"}, calls)
+		assert.Equal(t, []any{"app.tenant_id", "theTenant"}, actualArgs)
+	})
+
+	t.Run("rolls_back_when_the_tenant_cannot_be_set", func(t *testing.T) {
+		rolledBack := false
+		fakeTx := &FakeTx{
+			ExecFake: func(ctx context.Context, query string, args ...any) (sql.Result, error) {
+				return nil, errors.New("the exec error")
+			},
+			RollbackFake: func(ctx context.Context) error {
+				rolledBack = true
+				return nil
+			},
+		}
+		fakeDB := &FakeDB{
+			BeginFake: func(ctx context.Context) (Tx, error) { return fakeTx, nil },
+		}
+		fnCalled := false
+
+		err := WithTenant(context.Background(), fakeDB, "theTenant", func(tx Tx) error {
+			fnCalled = true
+			return nil
+		})
+
+		assert.EqualError(t, err, "failed to set tenant theTenant: the exec error")
+		assert.True(t, rolledBack)
+		assert.False(t, fnCalled)
+	})
+
+	t.Run("returns_an_error_when_tenant_id_is_empty", func(t *testing.T) {
+		err := WithTenant(context.Background(), &FakeDB{}, "", func(tx Tx) error { return nil })
+
+		assert.EqualError(t, err, "tenant ID cannot be empty")
+	})
+
+	t.Run("returns_an_error_when_db_is_nil", func(t *testing.T) {
+		err := WithTenant(context.Background(), nil, "theTenant", func(tx Tx) error { return nil })
+
+		assert.EqualError(t, err, "database connection cannot be nil")
+	})
+}
+
+func newTenantTestContext(t *testing.T, tenantID string) context.Context {
+	ctx, err := tenantkit.WithTenant(context.Background(), tenantkit.Tenant{ID: tenantID})
+	assert.NoError(t, err)
+	return ctx
+}
+
+func TestWithContextTenant(t *testing.T) {
+	t.Run("sets_the_tenant_carried_by_the_context", func(t *testing.T) {
+		var actualArgs []any
+		fakeTx := &FakeTx{
+			ExecFake: func(ctx context.Context, query string, args ...any) (sql.Result, error) {
+				actualArgs = args
+				return pgxResult{cmdTag: pgconn.NewCommandTag("SELECT 1")}, nil
+			},
+			CommitFake: func(ctx context.Context) error { return nil },
+		}
+		fakeDB := &FakeDB{
+			BeginFake: func(ctx context.Context) (Tx, error) { return fakeTx, nil },
+		}
+
+		err := WithContextTenant(newTenantTestContext(t, "the-tenant"), fakeDB, func(tx Tx) error { return nil })
+
+		assert.NoError(t, err)
+		assert.Equal(t, []any{"app.tenant_id", "the-tenant"}, actualArgs)
+	})
+
+	t.Run("returns_an_error_without_a_tenant_in_the_context", func(t *testing.T) {
+		fnCalled := false
+
+		err := WithContextTenant(context.Background(), &FakeDB{}, func(tx Tx) error {
+			fnCalled = true
+			return nil
+		})
+
+		assert.ErrorIs(t, err, tenantkit.ErrNoTenant)
+		assert.False(t, fnCalled)
+	})
+}
+
+func TestWithTenantSchema(t *testing.T) {
+	t.Run("sets_the_search_path_to_the_tenant_schema_before_running_fn", func(t *testing.T) {
+		var calls []string
+		var actualArgs []any
+		fakeTx := &FakeTx{
+			ExecFake: func(ctx context.Context, query string, args ...any) (sql.Result, error) {
+				calls = append(calls, query)
+				actualArgs = args
+				return pgxResult{cmdTag: pgconn.NewCommandTag("SELECT 1")}, nil
+			},
+			CommitFake: func(ctx context.Context) error {
+				calls = append(calls, "COMMIT")
+				return nil
+			},
+		}
+		fakeDB := &FakeDB{
+			BeginFake: func(ctx context.Context) (Tx, error) { return fakeTx, nil },
+		}
+
+		err := WithTenantSchema(newTenantTestContext(t, "the-tenant"), fakeDB, func(tx Tx) error {
+			calls = append(calls, "fn")
+			return nil
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"SELECT set_config('search_path', $1, true)", "fn", "COMMIT"}, calls)
+		assert.Equal(t, []any{"tenant_the_tenant"}, actualArgs)
+	})
+
+	t.Run("returns_an_error_without_a_tenant_in_the_context", func(t *testing.T) {
+		err := WithTenantSchema(context.Background(), &FakeDB{}, func(tx Tx) error { return nil })
+
+		assert.ErrorIs(t, err, tenantkit.ErrNoTenant)
+	})
+}
@@ -0,0 +1,101 @@
+package pgkit
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/half-ogre/go-kit/kit"
+)
+
+// Tx is an interface for database operations within a transaction
+type Tx interface {
+	QueryRow(ctx context.Context, query string, args ...any) Row
+	Query(ctx context.Context, query string, args ...any) (Rows, error)
+	Exec(ctx context.Context, query string, args ...any) (sql.Result, error)
+	Commit(ctx context.Context) error
+	Rollback(ctx context.Context) error
+}
+
+// TxBeginner is implemented by DBs that can start transactions, including DBs created with NewDB
+type TxBeginner interface {
+	Begin(ctx context.Context) (Tx, error)
+}
+
+// InTx runs fn in a transaction on db, committing if fn returns nil and rolling back otherwise
+func InTx(ctx context.Context, db DB, fn func(tx Tx) error) error {
+	beginner, ok := db.(TxBeginner)
+	if !ok {
+		return fmt.Errorf("database connection does not support transactions")
+	}
+
+	tx, err := beginner.Begin(ctx)
+	if err != nil {
+		return kit.WrapError(err, "failed to begin transaction")
+	}
+
+	if err := fn(tx); err != nil {
+		if rollbackErr := tx.Rollback(ctx); rollbackErr != nil {
+			return errors.Join(err, kit.WrapError(rollbackErr, "failed to roll back transaction"))
+		}
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return kit.WrapError(err, "failed to commit transaction")
+	}
+
+	return nil
+}
+
+func (p *poolDB) Begin(ctx context.Context) (Tx, error) {
+	tx, err := p.pool.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &pgxTx{tx: tx}, nil
+}
+
+func (s *statementCachingDB) Begin(ctx context.Context) (Tx, error) {
+	beginner, ok := s.DB.(TxBeginner)
+	if !ok {
+		return nil, fmt.Errorf("database connection does not support transactions")
+	}
+	return beginner.Begin(ctx)
+}
+
+// pgxTx wraps pgx.Tx to implement the Tx interface
+type pgxTx struct {
+	tx pgx.Tx
+}
+
+func (p *pgxTx) QueryRow(ctx context.Context, query string, args ...any) Row {
+	return p.tx.QueryRow(ctx, query, args...)
+}
+
+func (p *pgxTx) Query(ctx context.Context, query string, args ...any) (Rows, error) {
+	rows, err := p.tx.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	return &pgxRows{rows: rows}, nil
+}
+
+func (p *pgxTx) Exec(ctx context.Context, query string, args ...any) (sql.Result, error) {
+	cmdTag, err := p.tx.Exec(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	return pgxResult{cmdTag: cmdTag}, nil
+}
+
+func (p *pgxTx) Commit(ctx context.Context) error {
+	return p.tx.Commit(ctx)
+}
+
+func (p *pgxTx) Rollback(ctx context.Context) error {
+	return p.tx.Rollback(ctx)
+}
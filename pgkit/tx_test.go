@@ -0,0 +1,101 @@
+package pgkit
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInTx(t *testing.T) {
+	t.Run("commits_when_fn_succeeds", func(t *testing.T) {
+		committed := false
+		fakeTx := &FakeTx{
+			CommitFake: func(ctx context.Context) error {
+				committed = true
+				return nil
+			},
+		}
+		fakeDB := &FakeDB{
+			BeginFake: func(ctx context.Context) (Tx, error) { return fakeTx, nil },
+		}
+		var actualTx Tx
+
+		err := InTx(context.Background(), fakeDB, func(tx Tx) error {
+			actualTx = tx
+			return nil
+		})
+
+		assert.NoError(t, err)
+		assert.True(t, committed)
+		assert.Same(t, fakeTx, actualTx)
+	})
+
+	t.Run("rolls_back_and_returns_the_error_when_fn_fails", func(t *testing.T) {
+		rolledBack := false
+		fakeTx := &FakeTx{
+			RollbackFake: func(ctx context.Context) error {
+				rolledBack = true
+				return nil
+			},
+		}
+		fakeDB := &FakeDB{
+			BeginFake: func(ctx context.Context) (Tx, error) { return fakeTx, nil },
+		}
+
+		err := InTx(context.Background(), fakeDB, func(tx Tx) error {
+			return errors.New("the fn error")
+		})
+
+		assert.EqualError(t, err, "the fn error")
+		assert.True(t, rolledBack)
+	})
+
+	t.Run("returns_both_errors_when_rollback_fails", func(t *testing.T) {
+		fakeTx := &FakeTx{
+			RollbackFake: func(ctx context.Context) error { return errors.New("the rollback error") },
+		}
+		fakeDB := &FakeDB{
+			BeginFake: func(ctx context.Context) (Tx, error) { return fakeTx, nil },
+		}
+
+		err := InTx(context.Background(), fakeDB, func(tx Tx) error {
+			return errors.New("the fn error")
+		})
+
+		assert.ErrorContains(t, err, "the fn error")
+		assert.ErrorContains(t, err, "failed to roll back transaction: the rollback error")
+	})
+
+	t.Run("returns_an_error_when_begin_fails", func(t *testing.T) {
+		fakeDB := &FakeDB{
+			BeginFake: func(ctx context.Context) (Tx, error) { return nil, errors.New("the begin error") },
+		}
+
+		err := InTx(context.Background(), fakeDB, func(tx Tx) error { return nil })
+
+		assert.EqualError(t, err, "failed to begin transaction: the begin error")
+	})
+
+	t.Run("returns_an_error_when_commit_fails", func(t *testing.T) {
+		fakeTx := &FakeTx{
+			CommitFake: func(ctx context.Context) error { return errors.New("the commit error") },
+		}
+		fakeDB := &FakeDB{
+			BeginFake: func(ctx context.Context) (Tx, error) { return fakeTx, nil },
+		}
+
+		err := InTx(context.Background(), fakeDB, func(tx Tx) error { return nil })
+
+		assert.EqualError(t, err, "failed to commit transaction: the commit error")
+	})
+
+	t.Run("returns_an_error_when_db_does_not_support_transactions", func(t *testing.T) {
+		db := struct{ DB }{&FakeDB{}}
+
+		err := InTx(context.Background(), db, func(tx Tx) error { return nil })
+
+		assert.EqualError(t, err, "database connection does not support transactions")
+	})
+}
@@ -0,0 +1,220 @@
+// Package promptkit asks the questions of the kit's CLI tools: Confirm for yes or no, Select for one of
+// a list of choices, and Input for text. Every prompt can be answered without asking, from a flag with
+// WithAnswer or an environment variable with WithEnv, which is the only way to answer when input isn't
+// a terminal, e.g. in CI, so scripts never hang on a prompt.
+//
+//	prompter := promptkit.New()
+//	ok, err := prompter.Confirm("Drop database 'app'?", promptkit.WithAnswer(forceFlag))
+package promptkit
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/half-ogre/go-kit/kit"
+	"github.com/half-ogre/go-kit/validatekit"
+)
+
+// ErrNoAnswer is returned by a prompt that can't ask, because input isn't a terminal, and has no
+// answer from a flag, an environment variable, or a default
+var ErrNoAnswer = errors.New("no answer given and input is not a terminal")
+
+// PrompterOption configures a Prompter
+type PrompterOption func(*Prompter)
+
+// WithInput sets where answers are read from; the default is os.Stdin
+func WithInput(in io.Reader) PrompterOption {
+	return func(p *Prompter) {
+		p.in = bufio.NewReader(in)
+	}
+}
+
+// WithOutput sets where prompts are written; the default is os.Stderr, which keeps them out of output
+// piped from stdout
+func WithOutput(out io.Writer) PrompterOption {
+	return func(p *Prompter) {
+		p.out = out
+	}
+}
+
+// WithInteractive sets whether the prompter asks; the default is whether os.Stdin is a terminal
+func WithInteractive(interactive bool) PrompterOption {
+	return func(p *Prompter) {
+		p.interactive = interactive
+	}
+}
+
+// Prompter asks questions on a terminal
+type Prompter struct {
+	in          *bufio.Reader
+	out         io.Writer
+	interactive bool
+}
+
+// New returns a Prompter reading answers from stdin and writing prompts to stderr
+func New(options ...PrompterOption) *Prompter {
+	p := &Prompter{
+		in:          bufio.NewReader(os.Stdin),
+		out:         os.Stderr,
+		interactive: isTerminal(os.Stdin),
+	}
+	for _, option := range options {
+		option(p)
+	}
+	return p
+}
+
+func isTerminal(file *os.File) bool {
+	info, err := file.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+// PromptOption configures a single prompt
+type PromptOption func(*prompt)
+
+type prompt struct {
+	answer       string
+	env          string
+	defaultValue string
+	rules        []validatekit.Rule
+}
+
+// WithAnswer answers the prompt with answer without asking, unless it's empty, e.g. with a flag's value
+func WithAnswer(answer string) PromptOption {
+	return func(p *prompt) {
+		p.answer = answer
+	}
+}
+
+// WithEnv answers the prompt with the environment variable name without asking, when it's set and not
+// empty; an answer from WithAnswer comes first
+func WithEnv(name string) PromptOption {
+	return func(p *prompt) {
+		p.env = name
+	}
+}
+
+// WithDefault sets the answer used when the user enters nothing, or when the prompter can't ask
+func WithDefault(value string) PromptOption {
+	return func(p *prompt) {
+		p.defaultValue = value
+	}
+}
+
+// WithRules checks answers with rules, asking again after an answer that breaks one, or returning its
+// error when the answer wasn't typed. The answer is the value the rules return, e.g. trimmed.
+func WithRules(rules ...validatekit.Rule) PromptOption {
+	return func(p *prompt) {
+		p.rules = rules
+	}
+}
+
+// ask returns the answer to the prompt, from its answer, its environment variable, or the user, checked
+// with check, which returns the answer's value
+func ask[T any](p *Prompter, message string, hint string, options []PromptOption, check func(answer string) (T, error)) (T, error) {
+	config := prompt{}
+	for _, option := range options {
+		option(&config)
+	}
+
+	checkAnswer := func(answer string) (T, error) {
+		answer, err := validatekit.Apply(answer, config.rules...)
+		if err != nil {
+			var zero T
+			return zero, err
+		}
+		return check(answer)
+	}
+
+	if config.answer != "" {
+		return checkAnswer(config.answer)
+	}
+	if config.env != "" {
+		if value := os.Getenv(config.env); value != "" {
+			return checkAnswer(value)
+		}
+	}
+	if !p.interactive {
+		if config.defaultValue != "" {
+			return checkAnswer(config.defaultValue)
+		}
+		var zero T
+		return zero, kit.WrapError(ErrNoAnswer, "%s", message)
+	}
+
+	if config.defaultValue != "" {
+		hint = strings.TrimSpace(hint + " [" + config.defaultValue + "]")
+	}
+	if hint != "" {
+		hint = " " + hint
+	}
+	for {
+		fmt.Fprintf(p.out, "%s%s: ", message, hint)
+		line, err := p.in.ReadString('\n')
+		if err != nil && (err != io.EOF || line == "") {
+			var zero T
+			return zero, kit.WrapError(err, "error reading answer")
+		}
+
+		answer := strings.TrimSpace(line)
+		if answer == "" {
+			answer = config.defaultValue
+		}
+		value, err := checkAnswer(answer)
+		if err == nil {
+			return value, nil
+		}
+		fmt.Fprintln(p.out, err)
+	}
+}
+
+// Confirm asks a yes or no question, accepting y, yes, n, and no in any case, e.g.
+// Confirm("Drop database 'app'?", WithAnswer(strconv.FormatBool(force)))
+func (p *Prompter) Confirm(message string, options ...PromptOption) (bool, error) {
+	return ask(p, message, "(yes/no)", options, func(answer string) (bool, error) {
+		switch strings.ToLower(answer) {
+		case "y", "yes", "true":
+			return true, nil
+		case "n", "no", "false":
+			return false, nil
+		}
+		return false, fmt.Errorf("answer yes or no, not %q", answer)
+	})
+}
+
+// Select asks for one of choices, listing them numbered, and returns the one chosen. The answer can
+// be a choice or its number.
+func (p *Prompter) Select(message string, choices []string, options ...PromptOption) (string, error) {
+	if len(choices) == 0 {
+		return "", errors.New("at least one choice is required")
+	}
+
+	if p.interactive {
+		for i, choice := range choices {
+			fmt.Fprintf(p.out, "  %d) %s\n", i+1, choice)
+		}
+	}
+	return ask(p, message, fmt.Sprintf("(1-%d)", len(choices)), options, func(answer string) (string, error) {
+		for _, choice := range choices {
+			if answer == choice {
+				return choice, nil
+			}
+		}
+		if number, err := strconv.Atoi(answer); err == nil && number >= 1 && number <= len(choices) {
+			return choices[number-1], nil
+		}
+		return "", fmt.Errorf("choose one of %s, not %q", strings.Join(choices, ", "), answer)
+	})
+}
+
+// Input asks for text, e.g. Input("Migration name", WithRules(validatekit.Required, validatekit.MaxLength(64)))
+func (p *Prompter) Input(message string, options ...PromptOption) (string, error) {
+	return ask(p, message, "", options, func(answer string) (string, error) {
+		return answer, nil
+	})
+}
@@ -0,0 +1,191 @@
+package promptkit
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/half-ogre/go-kit/validatekit"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestPrompter(answers string) (*Prompter, *bytes.Buffer) {
+	var out bytes.Buffer
+	return New(WithInput(strings.NewReader(answers)), WithOutput(&out), WithInteractive(true)), &out
+}
+
+func newNonInteractivePrompter() *Prompter {
+	return New(WithInput(strings.NewReader("")), WithOutput(&bytes.Buffer{}), WithInteractive(false))
+}
+
+func TestConfirm(t *testing.T) {
+	t.Run("asks_and_returns_true_for_yes", func(t *testing.T) {
+		for _, answer := range []string{"y", "yes", "YES", " Yes "} {
+			prompter, out := newTestPrompter(answer + "\n")
+
+			confirmed, err := prompter.Confirm("Continue?")
+
+			assert.NoError(t, err)
+			assert.True(t, confirmed, answer)
+			assert.Equal(t, "Continue? (yes/no): ", out.String())
+		}
+	})
+
+	t.Run("returns_false_for_no", func(t *testing.T) {
+		prompter, _ := newTestPrompter("n\n")
+
+		confirmed, err := prompter.Confirm("Continue?")
+
+		assert.NoError(t, err)
+		assert.False(t, confirmed)
+	})
+
+	t.Run("asks_again_after_an_invalid_answer", func(t *testing.T) {
+		prompter, out := newTestPrompter("maybe\nyes\n")
+
+		confirmed, err := prompter.Confirm("Continue?")
+
+		assert.NoError(t, err)
+		assert.True(t, confirmed)
+		assert.Equal(t, "Continue? (yes/no): answer yes or no, not \"maybe\"\nContinue? (yes/no): ", out.String())
+	})
+
+	t.Run("uses_the_default_when_the_answer_is_empty", func(t *testing.T) {
+		prompter, out := newTestPrompter("\n")
+
+		confirmed, err := prompter.Confirm("Continue?", WithDefault("no"))
+
+		assert.NoError(t, err)
+		assert.False(t, confirmed)
+		assert.Equal(t, "Continue? (yes/no) [no]: ", out.String())
+	})
+
+	t.Run("uses_the_answer_without_asking", func(t *testing.T) {
+		prompter, out := newTestPrompter("no\n")
+
+		confirmed, err := prompter.Confirm("Continue?", WithAnswer("true"))
+
+		assert.NoError(t, err)
+		assert.True(t, confirmed)
+		assert.Empty(t, out.String())
+	})
+
+	t.Run("uses_the_environment_variable_without_asking", func(t *testing.T) {
+		t.Setenv("PROMPTKIT_TEST_CONFIRM", "yes")
+		prompter, out := newTestPrompter("no\n")
+
+		confirmed, err := prompter.Confirm("Continue?", WithEnv("PROMPTKIT_TEST_CONFIRM"))
+
+		assert.NoError(t, err)
+		assert.True(t, confirmed)
+		assert.Empty(t, out.String())
+	})
+
+	t.Run("returns_error_for_an_invalid_answer_that_was_not_typed", func(t *testing.T) {
+		_, err := newNonInteractivePrompter().Confirm("Continue?", WithAnswer("maybe"))
+
+		assert.EqualError(t, err, `answer yes or no, not "maybe"`)
+	})
+
+	t.Run("uses_the_default_when_not_interactive", func(t *testing.T) {
+		confirmed, err := newNonInteractivePrompter().Confirm("Continue?", WithDefault("yes"))
+
+		assert.NoError(t, err)
+		assert.True(t, confirmed)
+	})
+
+	t.Run("returns_err_no_answer_when_not_interactive_without_an_answer", func(t *testing.T) {
+		_, err := newNonInteractivePrompter().Confirm("Continue?")
+
+		assert.True(t, errors.Is(err, ErrNoAnswer))
+		assert.EqualError(t, err, "Continue?: no answer given and input is not a terminal")
+	})
+
+	t.Run("returns_error_when_input_ends", func(t *testing.T) {
+		prompter, _ := newTestPrompter("")
+
+		_, err := prompter.Confirm("Continue?")
+
+		assert.EqualError(t, err, "error reading answer: EOF")
+	})
+
+	t.Run("accepts_a_last_answer_without_a_newline", func(t *testing.T) {
+		prompter, _ := newTestPrompter("yes")
+
+		confirmed, err := prompter.Confirm("Continue?")
+
+		assert.NoError(t, err)
+		assert.True(t, confirmed)
+	})
+}
+
+func TestSelect(t *testing.T) {
+	t.Run("lists_the_choices_and_returns_the_one_chosen_by_number", func(t *testing.T) {
+		prompter, out := newTestPrompter("2\n")
+
+		choice, err := prompter.Select("Environment", []string{"dev", "prod"})
+
+		assert.NoError(t, err)
+		assert.Equal(t, "prod", choice)
+		assert.Equal(t, "  1) dev\n  2) prod\nEnvironment (1-2): ", out.String())
+	})
+
+	t.Run("returns_the_one_chosen_by_name", func(t *testing.T) {
+		prompter, _ := newTestPrompter("dev\n")
+
+		choice, err := prompter.Select("Environment", []string{"dev", "prod"})
+
+		assert.NoError(t, err)
+		assert.Equal(t, "dev", choice)
+	})
+
+	t.Run("asks_again_after_a_number_out_of_range", func(t *testing.T) {
+		prompter, _ := newTestPrompter("3\n1\n")
+
+		choice, err := prompter.Select("Environment", []string{"dev", "prod"})
+
+		assert.NoError(t, err)
+		assert.Equal(t, "dev", choice)
+	})
+
+	t.Run("returns_error_for_an_answer_that_is_not_a_choice", func(t *testing.T) {
+		_, err := newNonInteractivePrompter().Select("Environment", []string{"dev", "prod"}, WithAnswer("staging"))
+
+		assert.EqualError(t, err, `choose one of dev, prod, not "staging"`)
+	})
+
+	t.Run("returns_error_without_choices", func(t *testing.T) {
+		_, err := newNonInteractivePrompter().Select("Environment", nil)
+
+		assert.EqualError(t, err, "at least one choice is required")
+	})
+}
+
+func TestInput(t *testing.T) {
+	t.Run("returns_the_trimmed_answer", func(t *testing.T) {
+		prompter, out := newTestPrompter("  theName \n")
+
+		answer, err := prompter.Input("Name")
+
+		assert.NoError(t, err)
+		assert.Equal(t, "theName", answer)
+		assert.Equal(t, "Name: ", out.String())
+	})
+
+	t.Run("asks_again_after_an_answer_that_breaks_a_rule", func(t *testing.T) {
+		prompter, out := newTestPrompter("\ntheName\n")
+
+		answer, err := prompter.Input("Name", WithRules(validatekit.Required))
+
+		assert.NoError(t, err)
+		assert.Equal(t, "theName", answer)
+		assert.Equal(t, 2, strings.Count(out.String(), "Name: "))
+	})
+
+	t.Run("returns_error_for_an_answer_that_breaks_a_rule_and_was_not_typed", func(t *testing.T) {
+		_, err := newNonInteractivePrompter().Input("Name", WithAnswer("toolong"), WithRules(validatekit.MaxLength(3)))
+
+		assert.Error(t, err)
+	})
+}
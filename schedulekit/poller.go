@@ -0,0 +1,228 @@
+package schedulekit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+
+	"github.com/half-ogre/go-kit/kit"
+	"github.com/half-ogre/go-kit/logkit/logfields"
+)
+
+// Run polls for due jobs and runs them until ctx is canceled. Polls run back to back while they find
+// a full batch, then every poll interval. Poll errors are logged and polling continues.
+func (s *Scheduler) Run(ctx context.Context) error {
+	for {
+		claimed, err := s.Poll(ctx)
+		if err != nil && ctx.Err() == nil {
+			slog.ErrorContext(ctx, "error polling for scheduled jobs", "queue", s.config.queue, "error", err)
+		}
+
+		if err == nil && claimed >= int(s.config.batchSize) {
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(s.config.pollInterval):
+		}
+	}
+}
+
+// Poll claims up to a batch of due jobs, runs them one at a time, and returns how many it claimed. Use
+// it instead of Run to poll from a cron-triggered function.
+func (s *Scheduler) Poll(ctx context.Context) (int, error) {
+	now := s.config.clock.Now()
+
+	output, err := s.client.Query(ctx, &dynamodb.QueryInput{
+		TableName:              aws.String(s.tableName),
+		IndexName:              aws.String(s.config.indexName),
+		KeyConditionExpression: aws.String("#queue = :queue AND #due_at <= :now"),
+		ExpressionAttributeNames: map[string]string{
+			"#queue":  "queue",
+			"#due_at": "due_at",
+		},
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":queue": &types.AttributeValueMemberS{Value: s.config.queue},
+			":now":   &types.AttributeValueMemberN{Value: strconv.FormatInt(now.UnixMilli(), 10)},
+		},
+		Limit: aws.Int32(s.config.batchSize),
+	})
+	if err != nil {
+		return 0, kit.WrapError(err, "error querying due jobs")
+	}
+
+	var items []jobItem
+	if err := attributevalue.UnmarshalListOfMaps(output.Items, &items); err != nil {
+		return 0, kit.WrapError(err, "error unmarshaling due jobs")
+	}
+
+	claimed := 0
+	for _, item := range items {
+		if ctx.Err() != nil {
+			return claimed, ctx.Err()
+		}
+
+		ok, err := s.claim(ctx, &item)
+		if err != nil {
+			return claimed, err
+		}
+		if !ok {
+			// Another poller claimed it first
+			continue
+		}
+		claimed++
+
+		s.dispatch(ctx, item)
+	}
+
+	return claimed, nil
+}
+
+// claim leases item to this poller by moving its due time past the lease, so the job becomes due
+// again if this poller dies before finishing it
+func (s *Scheduler) claim(ctx context.Context, item *jobItem) (bool, error) {
+	claimed := *item
+	claimed.Status = StatusRunning
+	claimed.Attempts++
+	claimed.DueAt = s.config.clock.Now().Add(s.config.lease).UnixMilli()
+
+	ok, err := s.replace(ctx, item.Version, &claimed)
+	if err != nil {
+		return false, kit.WrapError(err, "error claiming %s", item)
+	}
+	if ok {
+		*item = claimed
+	}
+	return ok, nil
+}
+
+func (s *Scheduler) dispatch(ctx context.Context, item jobItem) {
+	start := time.Now()
+	err := s.run(ctx, item)
+	if err == nil {
+		slog.DebugContext(ctx, "ran scheduled job", "job_id", item.ID, "job_type", item.Type, "attempts", item.Attempts, logfields.Duration(time.Since(start)))
+		if err := s.complete(ctx, item); err != nil {
+			slog.ErrorContext(ctx, "error completing scheduled job", "job_id", item.ID, "job_type", item.Type, "error", err)
+		}
+		return
+	}
+
+	if err := s.fail(ctx, item, err); err != nil {
+		slog.ErrorContext(ctx, "error recording scheduled job failure", "job_id", item.ID, "job_type", item.Type, "error", err)
+	}
+}
+
+// run calls item's handler, turning a panic into an error
+func (s *Scheduler) run(ctx context.Context, item jobItem) (err error) {
+	if item.Attempts > s.config.maxAttempts {
+		// Its lease expired on every attempt, e.g. the handler outlives the lease or crashes the process
+		return fmt.Errorf("lease expired on all %d attempts", s.config.maxAttempts)
+	}
+
+	handler, ok := s.handlers[item.Type]
+	if !ok {
+		return fmt.Errorf("no handler for job type %q", item.Type)
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("handler panicked: %v", r)
+		}
+	}()
+
+	return handler(ctx, item.job())
+}
+
+// complete deletes a job that ran, unless its lease expired and another poller has claimed it since
+func (s *Scheduler) complete(ctx context.Context, item jobItem) error {
+	_, err := s.client.DeleteItem(ctx, &dynamodb.DeleteItemInput{
+		TableName:           aws.String(s.tableName),
+		Key:                 map[string]types.AttributeValue{"id": &types.AttributeValueMemberS{Value: item.ID}},
+		ConditionExpression: aws.String("#version = :version"),
+		ExpressionAttributeNames: map[string]string{
+			"#version": "version",
+		},
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":version": &types.AttributeValueMemberN{Value: strconv.Itoa(item.Version)},
+		},
+	})
+	if err != nil {
+		var conditionErr *types.ConditionalCheckFailedException
+		if errors.As(err, &conditionErr) {
+			slog.WarnContext(ctx, "scheduled job was claimed again before it finished", "job_id", item.ID, "job_type", item.Type)
+			return nil
+		}
+		return kit.WrapError(err, "error deleting %s", item)
+	}
+	return nil
+}
+
+// fail schedules a retry of a job whose handler failed, or marks it failed once it's out of attempts
+func (s *Scheduler) fail(ctx context.Context, item jobItem, runErr error) error {
+	now := s.config.clock.Now()
+	failed := item
+	failed.LastError = runErr.Error()
+
+	if item.Attempts >= s.config.maxAttempts {
+		slog.ErrorContext(ctx, "scheduled job failed", "job_id", item.ID, "job_type", item.Type, "attempts", item.Attempts, "error", runErr)
+		failed.Status = StatusFailed
+		failed.Queue = ""
+		failed.ExpiresAt = now.Add(s.config.retention).Unix()
+	} else {
+		backoff := s.config.retryBackoff << (item.Attempts - 1)
+		slog.WarnContext(ctx, "scheduled job failed, will retry", "job_id", item.ID, "job_type", item.Type, "attempts", item.Attempts, "retry_in", backoff.String(), "error", runErr)
+		failed.Status = StatusPending
+		failed.DueAt = now.Add(backoff).UnixMilli()
+	}
+
+	ok, err := s.replace(ctx, item.Version, &failed)
+	if err != nil {
+		return kit.WrapError(err, "error updating %s", item)
+	}
+	if !ok {
+		slog.WarnContext(ctx, "scheduled job was claimed again before it finished", "job_id", item.ID, "job_type", item.Type)
+	}
+	return nil
+}
+
+// replace puts item with the next version if the stored item still has version, and reports whether it
+// did
+func (s *Scheduler) replace(ctx context.Context, version int, item *jobItem) (bool, error) {
+	item.Version = version + 1
+
+	av, err := attributevalue.MarshalMap(item)
+	if err != nil {
+		return false, kit.WrapError(err, "error marshaling %s", item)
+	}
+
+	_, err = s.client.PutItem(ctx, &dynamodb.PutItemInput{
+		TableName:           aws.String(s.tableName),
+		Item:                av,
+		ConditionExpression: aws.String("#version = :version"),
+		ExpressionAttributeNames: map[string]string{
+			"#version": "version",
+		},
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":version": &types.AttributeValueMemberN{Value: strconv.Itoa(version)},
+		},
+	})
+	if err != nil {
+		var conditionErr *types.ConditionalCheckFailedException
+		if errors.As(err, &conditionErr) {
+			return false, nil
+		}
+		return false, err
+	}
+
+	return true, nil
+}
@@ -0,0 +1,243 @@
+package schedulekit
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/half-ogre/go-kit/dynamodbkit"
+)
+
+func TestScheduler_Poll(t *testing.T) {
+	t.Run("runs_due_jobs_and_deletes_them", func(t *testing.T) {
+		scheduler, table, _ := newTestScheduler(t)
+		var ran []Job
+		scheduler.Handle("aType", func(ctx context.Context, job Job) error {
+			ran = append(ran, job)
+			return nil
+		})
+		id, _ := scheduler.ScheduleIn(context.Background(), "aType", testPayload{Email: "a@example.com"}, 0)
+
+		claimed, err := scheduler.Poll(context.Background())
+
+		assert.NoError(t, err)
+		assert.Equal(t, 1, claimed)
+		assert.Len(t, ran, 1)
+		assert.Equal(t, id, ran[0].ID)
+		assert.Equal(t, 1, ran[0].Attempts)
+		payload, err := DecodePayload[testPayload](ran[0])
+		assert.NoError(t, err)
+		assert.Equal(t, "a@example.com", payload.Email)
+		_, ok := table.get(id)
+		assert.False(t, ok)
+	})
+
+	t.Run("does_not_run_jobs_that_are_not_due", func(t *testing.T) {
+		scheduler, _, clock := newTestScheduler(t)
+		ran := 0
+		scheduler.Handle("aType", func(ctx context.Context, job Job) error {
+			ran++
+			return nil
+		})
+		_, _ = scheduler.ScheduleIn(context.Background(), "aType", nil, 2*time.Hour)
+
+		claimed, err := scheduler.Poll(context.Background())
+		assert.NoError(t, err)
+		assert.Equal(t, 0, claimed)
+
+		clock.advance(2 * time.Hour)
+		claimed, err = scheduler.Poll(context.Background())
+
+		assert.NoError(t, err)
+		assert.Equal(t, 1, claimed)
+		assert.Equal(t, 1, ran)
+	})
+
+	t.Run("only_polls_its_queue", func(t *testing.T) {
+		scheduler, table, _ := newTestScheduler(t, WithQueue("aQueue"))
+		scheduler.Handle("aType", func(ctx context.Context, job Job) error { return nil })
+		table.items["anotherQueueJob"] = jobItem{ID: "anotherQueueJob", Type: "aType", Queue: "anotherQueue", Status: StatusPending}
+
+		claimed, err := scheduler.Poll(context.Background())
+
+		assert.NoError(t, err)
+		assert.Equal(t, 0, claimed)
+	})
+
+	t.Run("retries_a_failed_job_with_backoff", func(t *testing.T) {
+		scheduler, table, clock := newTestScheduler(t, WithRetryBackoff(time.Minute))
+		attempts := 0
+		scheduler.Handle("aType", func(ctx context.Context, job Job) error {
+			attempts++
+			if attempts < 3 {
+				return errors.New("theHandlerError")
+			}
+			return nil
+		})
+		id, _ := scheduler.ScheduleIn(context.Background(), "aType", nil, 0)
+
+		_, err := scheduler.Poll(context.Background())
+		assert.NoError(t, err)
+		item, _ := table.get(id)
+		assert.Equal(t, StatusPending, item.Status)
+		assert.Equal(t, "theHandlerError", item.LastError)
+		assert.Equal(t, clock.Now().Add(time.Minute).UnixMilli(), item.DueAt)
+
+		clock.advance(time.Minute)
+		_, _ = scheduler.Poll(context.Background())
+		item, _ = table.get(id)
+		assert.Equal(t, clock.Now().Add(2*time.Minute).UnixMilli(), item.DueAt)
+
+		clock.advance(2 * time.Minute)
+		_, _ = scheduler.Poll(context.Background())
+		assert.Equal(t, 3, attempts)
+		_, ok := table.get(id)
+		assert.False(t, ok)
+	})
+
+	t.Run("marks_a_job_failed_when_it_runs_out_of_attempts", func(t *testing.T) {
+		scheduler, table, clock := newTestScheduler(t, WithMaxAttempts(2), WithRetryBackoff(time.Minute), WithRetention(time.Hour))
+		scheduler.Handle("aType", func(ctx context.Context, job Job) error {
+			return errors.New("theHandlerError")
+		})
+		id, _ := scheduler.ScheduleIn(context.Background(), "aType", nil, 0)
+
+		_, _ = scheduler.Poll(context.Background())
+		clock.advance(time.Minute)
+		_, _ = scheduler.Poll(context.Background())
+
+		item, _ := table.get(id)
+		assert.Equal(t, StatusFailed, item.Status)
+		assert.Equal(t, 2, item.Attempts)
+		assert.Empty(t, item.Queue)
+		assert.Equal(t, clock.Now().Add(time.Hour).Unix(), item.ExpiresAt)
+	})
+
+	t.Run("retries_a_job_with_no_handler", func(t *testing.T) {
+		scheduler, table, _ := newTestScheduler(t)
+		id, _ := scheduler.ScheduleIn(context.Background(), "anUnknownType", nil, 0)
+
+		_, err := scheduler.Poll(context.Background())
+
+		assert.NoError(t, err)
+		item, _ := table.get(id)
+		assert.Equal(t, `no handler for job type "anUnknownType"`, item.LastError)
+	})
+
+	t.Run("recovers_a_panicking_handler", func(t *testing.T) {
+		scheduler, table, _ := newTestScheduler(t)
+		scheduler.Handle("aType", func(ctx context.Context, job Job) error {
+			panic("thePanic")
+		})
+		id, _ := scheduler.ScheduleIn(context.Background(), "aType", nil, 0)
+
+		_, err := scheduler.Poll(context.Background())
+
+		assert.NoError(t, err)
+		item, _ := table.get(id)
+		assert.Equal(t, "handler panicked: thePanic", item.LastError)
+	})
+
+	t.Run("runs_a_job_again_when_its_lease_expires", func(t *testing.T) {
+		scheduler, table, clock := newTestScheduler(t, WithLease(time.Minute))
+		id, _ := scheduler.ScheduleIn(context.Background(), "aType", nil, 0)
+		item, _ := table.get(id)
+		ok, err := scheduler.claim(context.Background(), &item)
+		assert.NoError(t, err)
+		assert.True(t, ok)
+		ran := 0
+		scheduler.Handle("aType", func(ctx context.Context, job Job) error {
+			ran++
+			assert.Equal(t, 2, job.Attempts)
+			return nil
+		})
+
+		claimed, _ := scheduler.Poll(context.Background())
+		assert.Equal(t, 0, claimed)
+
+		clock.advance(time.Minute)
+		claimed, _ = scheduler.Poll(context.Background())
+
+		assert.Equal(t, 1, claimed)
+		assert.Equal(t, 1, ran)
+	})
+
+	t.Run("fails_a_job_whose_lease_expired_on_every_attempt", func(t *testing.T) {
+		scheduler, table, _ := newTestScheduler(t, WithMaxAttempts(1))
+		scheduler.Handle("aType", func(ctx context.Context, job Job) error {
+			t.Fatal("the handler should not run")
+			return nil
+		})
+		table.items["theID"] = jobItem{ID: "theID", Type: "aType", Queue: "default", Status: StatusRunning, Attempts: 1, Version: 1}
+
+		_, err := scheduler.Poll(context.Background())
+
+		assert.NoError(t, err)
+		item, _ := table.get("theID")
+		assert.Equal(t, StatusFailed, item.Status)
+		assert.Equal(t, "lease expired on all 1 attempts", item.LastError)
+	})
+
+	t.Run("skips_jobs_another_poller_claimed", func(t *testing.T) {
+		scheduler, table, _ := newTestScheduler(t)
+		scheduler.Handle("aType", func(ctx context.Context, job Job) error {
+			t.Fatal("the handler should not run")
+			return nil
+		})
+		id, _ := scheduler.ScheduleIn(context.Background(), "aType", nil, 0)
+		fakeDB := scheduler.client.(*dynamodbkit.FakeDynamoDB)
+		query := fakeDB.QueryFake
+		fakeDB.QueryFake = func(ctx context.Context, params *dynamodb.QueryInput, optFns ...func(*dynamodb.Options)) (*dynamodb.QueryOutput, error) {
+			output, err := query(ctx, params, optFns...)
+			item, _ := table.get(id)
+			item.Version++
+			table.items[id] = item
+			return output, err
+		}
+
+		claimed, err := scheduler.Poll(context.Background())
+
+		assert.NoError(t, err)
+		assert.Equal(t, 0, claimed)
+	})
+
+	t.Run("returns_an_error_when_the_query_fails", func(t *testing.T) {
+		fakeDB := &dynamodbkit.FakeDynamoDB{
+			QueryFake: func(ctx context.Context, params *dynamodb.QueryInput, optFns ...func(*dynamodb.Options)) (*dynamodb.QueryOutput, error) {
+				assert.Equal(t, "anIndex", *params.IndexName)
+				return nil, errors.New("theQueryError")
+			},
+		}
+		scheduler := NewScheduler(fakeDB, "aTable", WithIndexName("anIndex"))
+
+		_, err := scheduler.Poll(context.Background())
+
+		assert.EqualError(t, err, "error querying due jobs: theQueryError")
+	})
+}
+
+func TestScheduler_Run(t *testing.T) {
+	t.Run("polls_until_the_context_is_canceled", func(t *testing.T) {
+		scheduler, _, _ := newTestScheduler(t, WithPollInterval(time.Millisecond))
+		ctx, cancel := context.WithCancel(context.Background())
+		var ran atomic.Int32
+		scheduler.Handle("aType", func(ctx context.Context, job Job) error {
+			if ran.Add(1) == 2 {
+				cancel()
+			}
+			return nil
+		})
+		_, _ = scheduler.ScheduleIn(context.Background(), "aType", nil, 0)
+		_, _ = scheduler.ScheduleIn(context.Background(), "aType", nil, 0)
+
+		err := scheduler.Run(ctx)
+
+		assert.NoError(t, err)
+		assert.Equal(t, int32(2), ran.Load())
+	})
+}
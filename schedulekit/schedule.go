@@ -0,0 +1,113 @@
+package schedulekit
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+
+	"github.com/half-ogre/go-kit/kit"
+)
+
+// ErrJobExists is returned by Schedule when a job with the ID from WithJobID already exists
+var ErrJobExists = errors.New("job already exists")
+
+// ScheduleOption configures one call to Schedule
+type ScheduleOption func(*scheduleConfig)
+
+type scheduleConfig struct {
+	id string
+}
+
+// WithJobID sets the job's ID instead of generating one, so a job scheduled twice, e.g. by a retried
+// request, is only stored once
+func WithJobID(id string) ScheduleOption {
+	return func(c *scheduleConfig) {
+		c.id = id
+	}
+}
+
+// Schedule stores a job of jobType to run at dueAt with payload marshaled as JSON, and returns its ID.
+// A dueAt in the past runs at the next poll.
+func (s *Scheduler) Schedule(ctx context.Context, jobType string, payload any, dueAt time.Time, options ...ScheduleOption) (string, error) {
+	config := scheduleConfig{}
+	for _, option := range options {
+		option(&config)
+	}
+	if config.id == "" {
+		id, err := kit.RandomToken(16)
+		if err != nil {
+			return "", kit.WrapError(err, "error generating job ID")
+		}
+		config.id = id
+	}
+
+	payloadJSON, err := json.Marshal(payload)
+	if err != nil {
+		return "", kit.WrapError(err, "error marshaling payload of %s job", jobType)
+	}
+
+	item := jobItem{
+		ID:        config.id,
+		Type:      jobType,
+		Payload:   string(payloadJSON),
+		Queue:     s.config.queue,
+		DueAt:     dueAt.UnixMilli(),
+		Status:    StatusPending,
+		CreatedAt: s.config.clock.Now().UnixMilli(),
+	}
+	av, err := attributevalue.MarshalMap(item)
+	if err != nil {
+		return "", kit.WrapError(err, "error marshaling %s", item)
+	}
+
+	_, err = s.client.PutItem(ctx, &dynamodb.PutItemInput{
+		TableName:           aws.String(s.tableName),
+		Item:                av,
+		ConditionExpression: aws.String("attribute_not_exists(id)"),
+	})
+	if err != nil {
+		var conditionErr *types.ConditionalCheckFailedException
+		if errors.As(err, &conditionErr) {
+			return "", ErrJobExists
+		}
+		return "", kit.WrapError(err, "error storing %s", item)
+	}
+
+	return item.ID, nil
+}
+
+// ScheduleIn stores a job of jobType to run after delay; see Schedule
+func (s *Scheduler) ScheduleIn(ctx context.Context, jobType string, payload any, delay time.Duration, options ...ScheduleOption) (string, error) {
+	return s.Schedule(ctx, jobType, payload, s.config.clock.Now().Add(delay), options...)
+}
+
+// Cancel deletes the pending job with id and reports whether it did. Jobs that are running, failed, or
+// already finished aren't affected.
+func (s *Scheduler) Cancel(ctx context.Context, id string) (bool, error) {
+	_, err := s.client.DeleteItem(ctx, &dynamodb.DeleteItemInput{
+		TableName:           aws.String(s.tableName),
+		Key:                 map[string]types.AttributeValue{"id": &types.AttributeValueMemberS{Value: id}},
+		ConditionExpression: aws.String("#status = :pending"),
+		ExpressionAttributeNames: map[string]string{
+			"#status": "status",
+		},
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":pending": &types.AttributeValueMemberS{Value: StatusPending},
+		},
+	})
+	if err != nil {
+		var conditionErr *types.ConditionalCheckFailedException
+		if errors.As(err, &conditionErr) {
+			return false, nil
+		}
+		return false, kit.WrapError(err, "error canceling job %s", id)
+	}
+
+	return true, nil
+}
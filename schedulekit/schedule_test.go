@@ -0,0 +1,125 @@
+package schedulekit
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/half-ogre/go-kit/dynamodbkit"
+)
+
+func TestScheduler_Schedule(t *testing.T) {
+	t.Run("stores_a_pending_job", func(t *testing.T) {
+		scheduler, table, clock := newTestScheduler(t)
+		dueAt := clock.Now().Add(time.Hour)
+
+		id, err := scheduler.Schedule(context.Background(), "aType", testPayload{Email: "a@example.com"}, dueAt)
+
+		assert.NoError(t, err)
+		assert.NotEmpty(t, id)
+		item, ok := table.get(id)
+		assert.True(t, ok)
+		assert.Equal(t, jobItem{
+			ID:        id,
+			Type:      "aType",
+			Payload:   `{"email":"a@example.com"}`,
+			Queue:     "default",
+			DueAt:     dueAt.UnixMilli(),
+			Status:    StatusPending,
+			CreatedAt: clock.Now().UnixMilli(),
+		}, item)
+	})
+
+	t.Run("uses_the_configured_queue", func(t *testing.T) {
+		scheduler, table, clock := newTestScheduler(t, WithQueue("aQueue"))
+
+		id, err := scheduler.Schedule(context.Background(), "aType", nil, clock.Now())
+
+		assert.NoError(t, err)
+		item, _ := table.get(id)
+		assert.Equal(t, "aQueue", item.Queue)
+	})
+
+	t.Run("returns_err_job_exists_for_a_duplicate_job_id", func(t *testing.T) {
+		scheduler, _, clock := newTestScheduler(t)
+		_, err := scheduler.Schedule(context.Background(), "aType", nil, clock.Now(), WithJobID("theID"))
+		assert.NoError(t, err)
+
+		_, err = scheduler.Schedule(context.Background(), "aType", nil, clock.Now(), WithJobID("theID"))
+
+		assert.ErrorIs(t, err, ErrJobExists)
+	})
+
+	t.Run("returns_an_error_when_put_item_fails", func(t *testing.T) {
+		fakeDB := &dynamodbkit.FakeDynamoDB{
+			PutItemFake: func(ctx context.Context, params *dynamodb.PutItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.PutItemOutput, error) {
+				return nil, errors.New("thePutItemError")
+			},
+		}
+		scheduler := NewScheduler(fakeDB, "aTable")
+
+		_, err := scheduler.Schedule(context.Background(), "aType", nil, time.Now(), WithJobID("theID"))
+
+		assert.ErrorContains(t, err, "error storing aType job theID: thePutItemError")
+	})
+
+	t.Run("returns_an_error_when_the_payload_cannot_be_marshaled", func(t *testing.T) {
+		scheduler, _, clock := newTestScheduler(t)
+
+		_, err := scheduler.Schedule(context.Background(), "aType", make(chan int), clock.Now())
+
+		assert.ErrorContains(t, err, "error marshaling payload of aType job")
+	})
+}
+
+func TestScheduler_ScheduleIn(t *testing.T) {
+	t.Run("stores_a_job_due_after_the_delay", func(t *testing.T) {
+		scheduler, table, clock := newTestScheduler(t)
+
+		id, err := scheduler.ScheduleIn(context.Background(), "aType", nil, 2*time.Hour)
+
+		assert.NoError(t, err)
+		item, _ := table.get(id)
+		assert.Equal(t, clock.Now().Add(2*time.Hour).UnixMilli(), item.DueAt)
+	})
+}
+
+func TestScheduler_Cancel(t *testing.T) {
+	t.Run("deletes_a_pending_job", func(t *testing.T) {
+		scheduler, table, _ := newTestScheduler(t)
+		id, _ := scheduler.ScheduleIn(context.Background(), "aType", nil, time.Hour)
+
+		result, err := scheduler.Cancel(context.Background(), id)
+
+		assert.NoError(t, err)
+		assert.True(t, result)
+		_, ok := table.get(id)
+		assert.False(t, ok)
+	})
+
+	t.Run("returns_false_for_a_running_job", func(t *testing.T) {
+		scheduler, table, _ := newTestScheduler(t)
+		id, _ := scheduler.ScheduleIn(context.Background(), "aType", nil, 0)
+		item, _ := table.get(id)
+		item.Status = StatusRunning
+		table.items[id] = item
+
+		result, err := scheduler.Cancel(context.Background(), id)
+
+		assert.NoError(t, err)
+		assert.False(t, result)
+	})
+
+	t.Run("returns_false_for_a_missing_job", func(t *testing.T) {
+		scheduler, _, _ := newTestScheduler(t)
+
+		result, err := scheduler.Cancel(context.Background(), "aMissingID")
+
+		assert.NoError(t, err)
+		assert.False(t, result)
+	})
+}
@@ -0,0 +1,199 @@
+// Package schedulekit runs delayed jobs, e.g. "send this reminder in 2 hours", using only a DynamoDB
+// table. Jobs are stored as items with a due time, a poller queries a due-time index for jobs that are
+// due and claims each with a conditional write, and claimed jobs are dispatched to handlers by type and
+// retried with backoff when they fail.
+//
+// The table's partition key is "id" (string) and it needs a global secondary index, "due_at-index" by
+// default, with partition key "queue" (string), sort key "due_at" (number), and all attributes
+// projected. Only pending and running jobs have a queue, so the index stays small. Failed jobs are kept
+// for inspection with an "expires_at" attribute; enable TTL on it to have DynamoDB delete them. TTL is
+// not used to trigger jobs because DynamoDB can take up to two days to delete expired items.
+package schedulekit
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/half-ogre/go-kit/dynamodbkit"
+	"github.com/half-ogre/go-kit/kit"
+)
+
+const (
+	StatusPending = "pending"
+	StatusRunning = "running"
+	StatusFailed  = "failed"
+)
+
+// Job is a scheduled job as passed to its handler
+type Job struct {
+	ID      string
+	Type    string
+	Payload json.RawMessage
+	DueAt   time.Time
+	// Attempts counts this attempt, so it's 1 the first time a job runs
+	Attempts int
+}
+
+// DecodePayload unmarshals a job's payload into T
+func DecodePayload[T any](job Job) (T, error) {
+	var payload T
+	if err := json.Unmarshal(job.Payload, &payload); err != nil {
+		return payload, kit.WrapError(err, "error unmarshaling payload of %s job %s", job.Type, job.ID)
+	}
+	return payload, nil
+}
+
+// Handler runs a job. Returning an error schedules a retry until the job runs out of attempts.
+type Handler func(ctx context.Context, job Job) error
+
+// SchedulerOption configures a Scheduler
+type SchedulerOption func(*schedulerConfig)
+
+type schedulerConfig struct {
+	queue        string
+	indexName    string
+	lease        time.Duration
+	maxAttempts  int
+	retryBackoff time.Duration
+	retention    time.Duration
+	pollInterval time.Duration
+	batchSize    int32
+	clock        kit.ClockInterface
+}
+
+// WithQueue sets the queue jobs are scheduled on and polled from, so services can share a table;
+// the default is "default"
+func WithQueue(queue string) SchedulerOption {
+	return func(c *schedulerConfig) {
+		c.queue = queue
+	}
+}
+
+// WithIndexName sets the name of the due-time index; the default is "due_at-index"
+func WithIndexName(indexName string) SchedulerOption {
+	return func(c *schedulerConfig) {
+		c.indexName = indexName
+	}
+}
+
+// WithLease sets how long a claimed job is hidden from other pollers. If a handler runs longer, or its
+// poller dies, the job is claimed and run again. The default is 5 minutes.
+func WithLease(lease time.Duration) SchedulerOption {
+	return func(c *schedulerConfig) {
+		c.lease = lease
+	}
+}
+
+// WithMaxAttempts sets how many times a job runs before it's marked failed; the default is 5
+func WithMaxAttempts(maxAttempts int) SchedulerOption {
+	return func(c *schedulerConfig) {
+		c.maxAttempts = maxAttempts
+	}
+}
+
+// WithRetryBackoff sets the delay before a failed job is retried, which doubles after each attempt;
+// the default is 30s
+func WithRetryBackoff(backoff time.Duration) SchedulerOption {
+	return func(c *schedulerConfig) {
+		c.retryBackoff = backoff
+	}
+}
+
+// WithRetention sets how long failed jobs are kept before their TTL expires; the default is 7 days
+func WithRetention(retention time.Duration) SchedulerOption {
+	return func(c *schedulerConfig) {
+		c.retention = retention
+	}
+}
+
+// WithPollInterval sets how long Run waits between polls that find no more due jobs; the default is 5s
+func WithPollInterval(interval time.Duration) SchedulerOption {
+	return func(c *schedulerConfig) {
+		c.pollInterval = interval
+	}
+}
+
+// WithBatchSize sets the most due jobs claimed per poll; the default is 25
+func WithBatchSize(batchSize int32) SchedulerOption {
+	return func(c *schedulerConfig) {
+		c.batchSize = batchSize
+	}
+}
+
+// WithClock sets the clock used for due times and leases
+func WithClock(clock kit.ClockInterface) SchedulerOption {
+	return func(c *schedulerConfig) {
+		c.clock = clock
+	}
+}
+
+// Scheduler schedules jobs and polls for due jobs to run
+type Scheduler struct {
+	client    dynamodbkit.DynamoDB
+	tableName string
+	config    schedulerConfig
+	handlers  map[string]Handler
+}
+
+// NewScheduler returns a scheduler storing jobs in tableName with client, e.g.
+// dynamodb.NewFromConfig(cfg). Register handlers with Handle before calling Run or Poll.
+func NewScheduler(client dynamodbkit.DynamoDB, tableName string, options ...SchedulerOption) *Scheduler {
+	config := schedulerConfig{
+		queue:        "default",
+		indexName:    "due_at-index",
+		lease:        5 * time.Minute,
+		maxAttempts:  5,
+		retryBackoff: 30 * time.Second,
+		retention:    7 * 24 * time.Hour,
+		pollInterval: 5 * time.Second,
+		batchSize:    25,
+		clock:        kit.NewClock(),
+	}
+	for _, option := range options {
+		option(&config)
+	}
+
+	return &Scheduler{
+		client:    client,
+		tableName: tableName,
+		config:    config,
+		handlers:  map[string]Handler{},
+	}
+}
+
+// Handle registers the handler for jobs of jobType. It isn't safe to call while polling.
+func (s *Scheduler) Handle(jobType string, handler Handler) {
+	s.handlers[jobType] = handler
+}
+
+// jobItem is a job as stored in DynamoDB. Times are Unix milliseconds except expires_at, which is in
+// seconds as DynamoDB TTL requires.
+type jobItem struct {
+	ID        string `dynamodbav:"id"`
+	Type      string `dynamodbav:"type"`
+	Payload   string `dynamodbav:"payload"`
+	Queue     string `dynamodbav:"queue,omitempty"`
+	DueAt     int64  `dynamodbav:"due_at"`
+	Status    string `dynamodbav:"status"`
+	Attempts  int    `dynamodbav:"attempts"`
+	Version   int    `dynamodbav:"version"`
+	LastError string `dynamodbav:"last_error,omitempty"`
+	CreatedAt int64  `dynamodbav:"created_at"`
+	ExpiresAt int64  `dynamodbav:"expires_at,omitempty"`
+}
+
+func (i jobItem) job() Job {
+	return Job{
+		ID:       i.ID,
+		Type:     i.Type,
+		Payload:  json.RawMessage(i.Payload),
+		DueAt:    time.UnixMilli(i.DueAt),
+		Attempts: i.Attempts,
+	}
+}
+
+func (i jobItem) String() string {
+	return fmt.Sprintf("%s job %s", i.Type, i.ID)
+}
@@ -0,0 +1,146 @@
+package schedulekit
+
+import (
+	"context"
+	"sort"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/half-ogre/go-kit/dynamodbkit"
+	"github.com/half-ogre/go-kit/kit"
+)
+
+// fakeTable is an in-memory jobs table that evaluates the conditions the scheduler uses
+type fakeTable struct {
+	mu    sync.Mutex
+	items map[string]jobItem
+}
+
+func newFakeTable(t *testing.T) (*fakeTable, *dynamodbkit.FakeDynamoDB) {
+	table := &fakeTable{items: map[string]jobItem{}}
+	fakeDB := &dynamodbkit.FakeDynamoDB{
+		PutItemFake: func(ctx context.Context, params *dynamodb.PutItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.PutItemOutput, error) {
+			table.mu.Lock()
+			defer table.mu.Unlock()
+			var item jobItem
+			require.NoError(t, attributevalue.UnmarshalMap(params.Item, &item))
+			existing, exists := table.items[item.ID]
+			switch aws.ToString(params.ConditionExpression) {
+			case "attribute_not_exists(id)":
+				if exists {
+					return nil, &types.ConditionalCheckFailedException{}
+				}
+			case "#version = :version":
+				if !exists || strconv.Itoa(existing.Version) != params.ExpressionAttributeValues[":version"].(*types.AttributeValueMemberN).Value {
+					return nil, &types.ConditionalCheckFailedException{}
+				}
+			}
+			table.items[item.ID] = item
+			return &dynamodb.PutItemOutput{}, nil
+		},
+		QueryFake: func(ctx context.Context, params *dynamodb.QueryInput, optFns ...func(*dynamodb.Options)) (*dynamodb.QueryOutput, error) {
+			table.mu.Lock()
+			defer table.mu.Unlock()
+			queue := params.ExpressionAttributeValues[":queue"].(*types.AttributeValueMemberS).Value
+			now, _ := strconv.ParseInt(params.ExpressionAttributeValues[":now"].(*types.AttributeValueMemberN).Value, 10, 64)
+			var due []jobItem
+			for _, item := range table.items {
+				if item.Queue == queue && item.DueAt <= now {
+					due = append(due, item)
+				}
+			}
+			sort.Slice(due, func(i, j int) bool { return due[i].DueAt < due[j].DueAt })
+			if len(due) > int(aws.ToInt32(params.Limit)) {
+				due = due[:aws.ToInt32(params.Limit)]
+			}
+			items := make([]map[string]types.AttributeValue, 0, len(due))
+			for _, item := range due {
+				av, err := attributevalue.MarshalMap(item)
+				require.NoError(t, err)
+				items = append(items, av)
+			}
+			return &dynamodb.QueryOutput{Items: items}, nil
+		},
+		DeleteItemFake: func(ctx context.Context, params *dynamodb.DeleteItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.DeleteItemOutput, error) {
+			table.mu.Lock()
+			defer table.mu.Unlock()
+			id := params.Key["id"].(*types.AttributeValueMemberS).Value
+			existing, exists := table.items[id]
+			switch aws.ToString(params.ConditionExpression) {
+			case "#status = :pending":
+				if !exists || existing.Status != StatusPending {
+					return nil, &types.ConditionalCheckFailedException{}
+				}
+			case "#version = :version":
+				if !exists || strconv.Itoa(existing.Version) != params.ExpressionAttributeValues[":version"].(*types.AttributeValueMemberN).Value {
+					return nil, &types.ConditionalCheckFailedException{}
+				}
+			}
+			delete(table.items, id)
+			return &dynamodb.DeleteItemOutput{}, nil
+		},
+	}
+	return table, fakeDB
+}
+
+func (f *fakeTable) get(id string) (jobItem, bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	item, ok := f.items[id]
+	return item, ok
+}
+
+// fakeClock is a clock tests can advance
+type fakeClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *fakeClock) advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+}
+
+var _ kit.ClockInterface = (*fakeClock)(nil)
+
+func newTestScheduler(t *testing.T, options ...SchedulerOption) (*Scheduler, *fakeTable, *fakeClock) {
+	table, fakeDB := newFakeTable(t)
+	clock := &fakeClock{now: time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)}
+	scheduler := NewScheduler(fakeDB, "aTable", append([]SchedulerOption{WithClock(clock)}, options...)...)
+	return scheduler, table, clock
+}
+
+type testPayload struct {
+	Email string `json:"email"`
+}
+
+func TestDecodePayload(t *testing.T) {
+	t.Run("unmarshals_the_payload", func(t *testing.T) {
+		result, err := DecodePayload[testPayload](Job{Payload: []byte(`{"email":"a@example.com"}`)})
+
+		assert.NoError(t, err)
+		assert.Equal(t, testPayload{Email: "a@example.com"}, result)
+	})
+
+	t.Run("returns_an_error_for_an_invalid_payload", func(t *testing.T) {
+		_, err := DecodePayload[testPayload](Job{ID: "anID", Type: "aType", Payload: []byte(`nope`)})
+
+		assert.ErrorContains(t, err, "error unmarshaling payload of aType job anID")
+	})
+}
@@ -0,0 +1,203 @@
+package searchkit
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/half-ogre/go-kit/kit"
+)
+
+// BulkAction is one operation in a bulk request; make one with IndexAction or DeleteAction
+type BulkAction struct {
+	operation string
+	index     string
+	id        string
+	document  any
+}
+
+// IndexAction indexes document as id in index
+func IndexAction(index string, id string, document any) BulkAction {
+	return BulkAction{operation: "index", index: index, id: id, document: document}
+}
+
+// DeleteAction deletes the document with id from index
+func DeleteAction(index string, id string) BulkAction {
+	return BulkAction{operation: "delete", index: index, id: id}
+}
+
+// BulkFailure is an action the engine rejected
+type BulkFailure struct {
+	Index  string
+	ID     string
+	Status int
+	Reason string
+}
+
+// BulkError is returned by Bulk when actions still fail after retries
+type BulkError struct {
+	Failures []BulkFailure
+}
+
+func (e *BulkError) Error() string {
+	reasons := make([]string, 0, min(len(e.Failures), 3))
+	for _, failure := range e.Failures[:min(len(e.Failures), 3)] {
+		reasons = append(reasons, fmt.Sprintf("%s/%s: %s", failure.Index, failure.ID, failure.Reason))
+	}
+	return fmt.Sprintf("%d bulk actions failed: %s", len(e.Failures), strings.Join(reasons, "; "))
+}
+
+// BulkOption configures Bulk
+type BulkOption func(*bulkConfig)
+
+type bulkConfig struct {
+	maxRetries int
+	backoff    time.Duration
+	refresh    RefreshPolicy
+}
+
+// WithBulkRetries sets how many times actions rejected with a retryable status, e.g. 429 when the
+// cluster is overloaded, are sent again and the initial backoff between attempts, which doubles after
+// each retry; the default is 3 retries starting at 500ms
+func WithBulkRetries(maxRetries int, backoff time.Duration) BulkOption {
+	return func(c *bulkConfig) {
+		c.maxRetries = maxRetries
+		c.backoff = backoff
+	}
+}
+
+// WithBulkRefresh sets when the changes become visible to search
+func WithBulkRefresh(policy RefreshPolicy) BulkOption {
+	return func(c *bulkConfig) {
+		c.refresh = policy
+	}
+}
+
+// Bulk sends actions in one bulk request. Actions rejected with a retryable status are retried with
+// backoff, as is the whole request if it fails that way; any that still fail are returned in a
+// *BulkError. Keep batches to a few megabytes.
+func Bulk(ctx context.Context, client *Client, actions []BulkAction, options ...BulkOption) error {
+	if len(actions) == 0 {
+		return nil
+	}
+
+	config := bulkConfig{maxRetries: 3, backoff: 500 * time.Millisecond}
+	for _, option := range options {
+		option(&config)
+	}
+
+	pending := actions
+	backoff := config.backoff
+	for attempt := 0; ; attempt++ {
+		failures, retry, err := sendBulk(ctx, client, pending, config.refresh)
+		if err != nil {
+			var responseErr *ResponseError
+			if !errors.As(err, &responseErr) || !responseErr.Retryable() || attempt >= config.maxRetries {
+				return kit.WrapError(err, "error sending bulk request")
+			}
+			retry = pending
+		}
+
+		if len(retry) == 0 || attempt >= config.maxRetries {
+			for _, action := range retry {
+				failures = append(failures, BulkFailure{Index: action.index, ID: action.id, Status: http.StatusTooManyRequests, Reason: "retries exhausted"})
+			}
+			if len(failures) > 0 {
+				return &BulkError{Failures: failures}
+			}
+			return nil
+		}
+
+		slog.WarnContext(ctx, "retrying bulk actions", "actions", len(retry), "attempt", attempt+1)
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+		pending = retry
+	}
+}
+
+// BulkIndex indexes documents in index, keyed by ID, with Bulk
+func BulkIndex[T any](ctx context.Context, client *Client, index string, documents map[string]T, options ...BulkOption) error {
+	actions := make([]BulkAction, 0, len(documents))
+	for id, document := range documents {
+		actions = append(actions, IndexAction(index, id, document))
+	}
+	return Bulk(ctx, client, actions, options...)
+}
+
+// sendBulk sends one bulk request and sorts the rejected actions into permanent failures and ones to
+// retry
+func sendBulk(ctx context.Context, client *Client, actions []BulkAction, refresh RefreshPolicy) ([]BulkFailure, []BulkAction, error) {
+	var body bytes.Buffer
+	encoder := json.NewEncoder(&body)
+	for _, action := range actions {
+		meta := map[string]any{action.operation: map[string]string{"_index": client.indexName(action.index), "_id": action.id}}
+		if err := encoder.Encode(meta); err != nil {
+			return nil, nil, fmt.Errorf("failed to marshal bulk action: %w", err)
+		}
+		if action.operation == "index" {
+			if err := encoder.Encode(action.document); err != nil {
+				return nil, nil, fmt.Errorf("failed to marshal document %s: %w", action.id, err)
+			}
+		}
+	}
+
+	var response struct {
+		Errors bool `json:"errors"`
+		Items  []map[string]struct {
+			Status int `json:"status"`
+			Error  *struct {
+				Type   string `json:"type"`
+				Reason string `json:"reason"`
+			} `json:"error"`
+		} `json:"items"`
+	}
+
+	query := url.Values{}
+	if refresh != "" {
+		query.Set("refresh", string(refresh))
+	}
+	if err := client.do(ctx, http.MethodPost, "/_bulk", query, body.Bytes(), &response); err != nil {
+		return nil, nil, err
+	}
+	if !response.Errors {
+		return nil, nil, nil
+	}
+
+	var failures []BulkFailure
+	var retry []BulkAction
+	for i, item := range response.Items {
+		if i >= len(actions) {
+			break
+		}
+		for _, result := range item {
+			// Deleting a missing document is a 404 without an error, so it isn't a failure
+			if result.Error == nil {
+				continue
+			}
+			if (&ResponseError{StatusCode: result.Status}).Retryable() {
+				retry = append(retry, actions[i])
+				continue
+			}
+			failures = append(failures, BulkFailure{
+				Index:  actions[i].index,
+				ID:     actions[i].id,
+				Status: result.Status,
+				Reason: result.Error.Type + ": " + result.Error.Reason,
+			})
+		}
+	}
+
+	return failures, retry, nil
+}
@@ -0,0 +1,155 @@
+package searchkit
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// readBulkBody returns the lines of an NDJSON bulk request
+func readBulkBody(t *testing.T, r *http.Request) []map[string]any {
+	var lines []map[string]any
+	scanner := bufio.NewScanner(r.Body)
+	for scanner.Scan() {
+		var line map[string]any
+		assert.NoError(t, json.Unmarshal(scanner.Bytes(), &line))
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+func TestBulk(t *testing.T) {
+	t.Run("sends_the_actions_as_ndjson", func(t *testing.T) {
+		var sent []map[string]any
+		client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "/_bulk", r.URL.Path)
+			assert.Equal(t, "application/x-ndjson", r.Header.Get("Content-Type"))
+			assert.Equal(t, "wait_for", r.URL.Query().Get("refresh"))
+			sent = readBulkBody(t, r)
+			fmt.Fprint(w, `{"errors":false,"items":[{"index":{"status":201}},{"delete":{"status":200}}]}`)
+		}, WithIndexPrefix("dev-"))
+
+		err := Bulk(context.Background(), client, []BulkAction{
+			IndexAction("posts", "a", testDocument{Title: "aTitle"}),
+			DeleteAction("posts", "b"),
+		}, WithBulkRefresh(RefreshWaitFor))
+
+		assert.NoError(t, err)
+		assert.Equal(t, []map[string]any{
+			{"index": map[string]any{"_index": "dev-posts", "_id": "a"}},
+			{"title": "aTitle", "views": float64(0)},
+			{"delete": map[string]any{"_index": "dev-posts", "_id": "b"}},
+		}, sent)
+	})
+
+	t.Run("does_nothing_without_actions", func(t *testing.T) {
+		client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+			t.Fatal("no request should be sent")
+		})
+
+		err := Bulk(context.Background(), client, nil)
+
+		assert.NoError(t, err)
+	})
+
+	t.Run("retries_rejected_actions_with_backoff", func(t *testing.T) {
+		var requests atomic.Int32
+		var retried []map[string]any
+		client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+			if requests.Add(1) == 1 {
+				readBulkBody(t, r)
+				fmt.Fprint(w, `{"errors":true,"items":[{"index":{"status":201}},{"index":{"status":429,"error":{"type":"es_rejected_execution_exception","reason":"busy"}}}]}`)
+				return
+			}
+			retried = readBulkBody(t, r)
+			fmt.Fprint(w, `{"errors":false,"items":[{"index":{"status":201}}]}`)
+		})
+
+		err := Bulk(context.Background(), client, []BulkAction{
+			IndexAction("posts", "a", testDocument{}),
+			IndexAction("posts", "b", testDocument{}),
+		}, WithBulkRetries(1, time.Millisecond))
+
+		assert.NoError(t, err)
+		assert.Equal(t, int32(2), requests.Load())
+		assert.Len(t, retried, 2)
+		assert.Equal(t, map[string]any{"index": map[string]any{"_index": "posts", "_id": "b"}}, retried[0])
+	})
+
+	t.Run("retries_the_request_when_it_is_rate_limited", func(t *testing.T) {
+		var requests atomic.Int32
+		client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+			if requests.Add(1) == 1 {
+				w.WriteHeader(http.StatusTooManyRequests)
+				return
+			}
+			fmt.Fprint(w, `{"errors":false,"items":[{"index":{"status":201}}]}`)
+		})
+
+		err := Bulk(context.Background(), client, []BulkAction{IndexAction("posts", "a", testDocument{})}, WithBulkRetries(1, time.Millisecond))
+
+		assert.NoError(t, err)
+		assert.Equal(t, int32(2), requests.Load())
+	})
+
+	t.Run("returns_a_bulk_error_for_rejected_actions", func(t *testing.T) {
+		client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprint(w, `{"errors":true,"items":[
+				{"index":{"status":400,"error":{"type":"mapper_parsing_exception","reason":"theReason"}}},
+				{"index":{"status":429,"error":{"type":"es_rejected_execution_exception","reason":"busy"}}},
+				{"delete":{"status":404,"result":"not_found"}}
+			]}`)
+		})
+
+		err := Bulk(context.Background(), client, []BulkAction{
+			IndexAction("posts", "a", testDocument{}),
+			IndexAction("posts", "b", testDocument{}),
+			DeleteAction("posts", "c"),
+		}, WithBulkRetries(0, time.Millisecond))
+
+		var bulkErr *BulkError
+		assert.True(t, errors.As(err, &bulkErr))
+		assert.Equal(t, []BulkFailure{
+			{Index: "posts", ID: "a", Status: http.StatusBadRequest, Reason: "mapper_parsing_exception: theReason"},
+			{Index: "posts", ID: "b", Status: http.StatusTooManyRequests, Reason: "retries exhausted"},
+		}, bulkErr.Failures)
+		assert.EqualError(t, err, "2 bulk actions failed: posts/a: mapper_parsing_exception: theReason; posts/b: retries exhausted")
+	})
+
+	t.Run("returns_an_error_for_a_non_retryable_response", func(t *testing.T) {
+		client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusForbidden)
+			fmt.Fprint(w, `{"error":{"type":"security_exception","reason":"theReason"}}`)
+		})
+
+		err := Bulk(context.Background(), client, []BulkAction{DeleteAction("posts", "a")})
+
+		assert.EqualError(t, err, "error sending bulk request: search request failed with status 403: security_exception: theReason")
+	})
+}
+
+func TestBulkIndex(t *testing.T) {
+	t.Run("indexes_each_document_by_id", func(t *testing.T) {
+		var sent []map[string]any
+		client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+			sent = readBulkBody(t, r)
+			fmt.Fprint(w, `{"errors":false,"items":[{"index":{"status":201}}]}`)
+		})
+
+		err := BulkIndex(context.Background(), client, "posts", map[string]testDocument{"a": {Title: "aTitle"}})
+
+		assert.NoError(t, err)
+		assert.Equal(t, []map[string]any{
+			{"index": map[string]any{"_index": "posts", "_id": "a"}},
+			{"title": "aTitle", "views": float64(0)},
+		}, sent)
+	})
+}
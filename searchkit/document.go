@@ -0,0 +1,86 @@
+package searchkit
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+
+	"github.com/half-ogre/go-kit/kit"
+)
+
+// RefreshPolicy is when changes become visible to search
+type RefreshPolicy string
+
+const (
+	// RefreshNone leaves changes to the index's refresh interval; it's the default
+	RefreshNone RefreshPolicy = "false"
+	// RefreshWaitFor waits until the change is visible to search before returning
+	RefreshWaitFor RefreshPolicy = "wait_for"
+	// RefreshImmediate refreshes the affected shards at once, which is expensive; prefer RefreshWaitFor
+	RefreshImmediate RefreshPolicy = "true"
+)
+
+// WriteOption configures Index and Delete
+type WriteOption func(url.Values)
+
+// WithRefresh sets when the change becomes visible to search, e.g. RefreshWaitFor in tests
+func WithRefresh(policy RefreshPolicy) WriteOption {
+	return func(query url.Values) {
+		query.Set("refresh", string(policy))
+	}
+}
+
+// Index stores document as id in index, replacing any document already stored with id
+func Index[T any](ctx context.Context, client *Client, index string, id string, document T, options ...WriteOption) error {
+	query := url.Values{}
+	for _, option := range options {
+		option(query)
+	}
+
+	if err := client.do(ctx, http.MethodPut, client.documentPath(index, id), query, document, nil); err != nil {
+		return kit.WrapError(err, "error indexing document %s in %s", id, index)
+	}
+	return nil
+}
+
+// Get returns the document with id in index, or nil if there isn't one
+func Get[T any](ctx context.Context, client *Client, index string, id string) (*T, error) {
+	var response struct {
+		Found  bool `json:"found"`
+		Source *T   `json:"_source"`
+	}
+
+	err := client.do(ctx, http.MethodGet, client.documentPath(index, id), nil, nil, &response)
+	if err != nil {
+		var responseErr *ResponseError
+		// A missing document is a 404 with found false; a missing index is a 404 with an error body
+		if errors.As(err, &responseErr) && responseErr.StatusCode == http.StatusNotFound && responseErr.Type == "" {
+			return nil, nil
+		}
+		return nil, kit.WrapError(err, "error getting document %s from %s", id, index)
+	}
+
+	if !response.Found {
+		return nil, nil
+	}
+	return response.Source, nil
+}
+
+// Delete deletes the document with id from index. Deleting a missing document isn't an error.
+func Delete(ctx context.Context, client *Client, index string, id string, options ...WriteOption) error {
+	query := url.Values{}
+	for _, option := range options {
+		option(query)
+	}
+
+	err := client.do(ctx, http.MethodDelete, client.documentPath(index, id), query, nil, nil)
+	if err != nil {
+		var responseErr *ResponseError
+		if errors.As(err, &responseErr) && responseErr.StatusCode == http.StatusNotFound && responseErr.Type == "" {
+			return nil
+		}
+		return kit.WrapError(err, "error deleting document %s from %s", id, index)
+	}
+	return nil
+}
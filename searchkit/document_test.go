@@ -0,0 +1,113 @@
+package searchkit
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type testDocument struct {
+	Title string `json:"title" dynamodbav:"title"`
+	Views int    `json:"views" dynamodbav:"views"`
+}
+
+func TestIndex(t *testing.T) {
+	t.Run("puts_the_document_with_its_id", func(t *testing.T) {
+		client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, http.MethodPut, r.Method)
+			assert.Equal(t, "/dev-posts/_doc/theID", r.URL.Path)
+			assert.Equal(t, "wait_for", r.URL.Query().Get("refresh"))
+			var body testDocument
+			assert.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+			assert.Equal(t, testDocument{Title: "aTitle", Views: 3}, body)
+			w.WriteHeader(http.StatusCreated)
+			fmt.Fprint(w, `{"result":"created"}`)
+		}, WithIndexPrefix("dev-"))
+
+		err := Index(context.Background(), client, "posts", "theID", testDocument{Title: "aTitle", Views: 3}, WithRefresh(RefreshWaitFor))
+
+		assert.NoError(t, err)
+	})
+
+	t.Run("returns_an_error_when_the_request_fails", func(t *testing.T) {
+		client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusBadRequest)
+			fmt.Fprint(w, `{"error":{"type":"mapper_parsing_exception","reason":"theReason"}}`)
+		})
+
+		err := Index(context.Background(), client, "posts", "theID", testDocument{})
+
+		assert.EqualError(t, err, "error indexing document theID in posts: search request failed with status 400: mapper_parsing_exception: theReason")
+	})
+}
+
+func TestGet(t *testing.T) {
+	t.Run("returns_the_document", func(t *testing.T) {
+		client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, http.MethodGet, r.Method)
+			assert.Equal(t, "/posts/_doc/theID", r.URL.Path)
+			fmt.Fprint(w, `{"_id":"theID","found":true,"_source":{"title":"aTitle","views":3}}`)
+		})
+
+		result, err := Get[testDocument](context.Background(), client, "posts", "theID")
+
+		assert.NoError(t, err)
+		assert.Equal(t, &testDocument{Title: "aTitle", Views: 3}, result)
+	})
+
+	t.Run("returns_nil_when_the_document_does_not_exist", func(t *testing.T) {
+		client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, `{"_id":"theID","found":false}`)
+		})
+
+		result, err := Get[testDocument](context.Background(), client, "posts", "theID")
+
+		assert.NoError(t, err)
+		assert.Nil(t, result)
+	})
+
+	t.Run("returns_err_not_found_when_the_index_does_not_exist", func(t *testing.T) {
+		client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, `{"error":{"type":"index_not_found_exception","reason":"no such index [posts]"}}`)
+		})
+
+		result, err := Get[testDocument](context.Background(), client, "posts", "theID")
+
+		assert.Nil(t, result)
+		assert.ErrorIs(t, err, ErrNotFound)
+	})
+}
+
+func TestDelete(t *testing.T) {
+	t.Run("deletes_the_document", func(t *testing.T) {
+		called := false
+		client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+			called = true
+			assert.Equal(t, http.MethodDelete, r.Method)
+			assert.Equal(t, "/posts/_doc/theID", r.URL.Path)
+			fmt.Fprint(w, `{"result":"deleted"}`)
+		})
+
+		err := Delete(context.Background(), client, "posts", "theID")
+
+		assert.NoError(t, err)
+		assert.True(t, called)
+	})
+
+	t.Run("ignores_a_missing_document", func(t *testing.T) {
+		client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, `{"result":"not_found"}`)
+		})
+
+		err := Delete(context.Background(), client, "posts", "theID")
+
+		assert.NoError(t, err)
+	})
+}
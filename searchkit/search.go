@@ -0,0 +1,230 @@
+package searchkit
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/url"
+
+	"github.com/half-ogre/go-kit/kit"
+)
+
+// Query is a query in the search engine's query DSL, e.g. {"match": {"title": "go"}}. Build one with
+// the functions below, or write a map for anything they don't cover.
+type Query map[string]any
+
+// MatchAll matches every document
+func MatchAll() Query {
+	return Query{"match_all": map[string]any{}}
+}
+
+// Match matches documents whose analyzed field matches text
+func Match(field string, text string) Query {
+	return Query{"match": map[string]any{field: text}}
+}
+
+// MultiMatch matches documents where any of fields matches text
+func MultiMatch(text string, fields ...string) Query {
+	return Query{"multi_match": map[string]any{"query": text, "fields": fields}}
+}
+
+// Term matches documents whose field is exactly value, e.g. a keyword field
+func Term(field string, value any) Query {
+	return Query{"term": map[string]any{field: value}}
+}
+
+// Terms matches documents whose field is exactly one of values
+func Terms[T any](field string, values ...T) Query {
+	return Query{"terms": map[string]any{field: values}}
+}
+
+// Prefix matches documents whose field starts with prefix
+func Prefix(field string, prefix string) Query {
+	return Query{"prefix": map[string]any{field: prefix}}
+}
+
+// Exists matches documents that have a value for field
+func Exists(field string) Query {
+	return Query{"exists": map[string]any{"field": field}}
+}
+
+// RangeQuery matches documents whose field is within a range; add bounds with Gt, Gte, Lt, and Lte
+type RangeQuery struct {
+	field  string
+	bounds map[string]any
+}
+
+// Range starts a range query on field
+func Range(field string) *RangeQuery {
+	return &RangeQuery{field: field, bounds: map[string]any{}}
+}
+
+func (r *RangeQuery) Gt(value any) *RangeQuery {
+	r.bounds["gt"] = value
+	return r
+}
+
+func (r *RangeQuery) Gte(value any) *RangeQuery {
+	r.bounds["gte"] = value
+	return r
+}
+
+func (r *RangeQuery) Lt(value any) *RangeQuery {
+	r.bounds["lt"] = value
+	return r
+}
+
+func (r *RangeQuery) Lte(value any) *RangeQuery {
+	r.bounds["lte"] = value
+	return r
+}
+
+// Query returns the range query
+func (r *RangeQuery) Query() Query {
+	return Query{"range": map[string]any{r.field: r.bounds}}
+}
+
+// BoolQuery combines queries; add clauses with Must, Filter, Should, and MustNot
+type BoolQuery struct {
+	clauses map[string][]Query
+}
+
+// Bool starts a bool query
+func Bool() *BoolQuery {
+	return &BoolQuery{clauses: map[string][]Query{}}
+}
+
+// Must adds queries documents must match, contributing to their score
+func (b *BoolQuery) Must(queries ...Query) *BoolQuery {
+	b.clauses["must"] = append(b.clauses["must"], queries...)
+	return b
+}
+
+// Filter adds queries documents must match, without scoring them
+func (b *BoolQuery) Filter(queries ...Query) *BoolQuery {
+	b.clauses["filter"] = append(b.clauses["filter"], queries...)
+	return b
+}
+
+// Should adds queries that raise the score of documents that match them
+func (b *BoolQuery) Should(queries ...Query) *BoolQuery {
+	b.clauses["should"] = append(b.clauses["should"], queries...)
+	return b
+}
+
+// MustNot adds queries documents must not match
+func (b *BoolQuery) MustNot(queries ...Query) *BoolQuery {
+	b.clauses["must_not"] = append(b.clauses["must_not"], queries...)
+	return b
+}
+
+// Query returns the bool query
+func (b *BoolQuery) Query() Query {
+	clauses := map[string]any{}
+	for name, queries := range b.clauses {
+		clauses[name] = queries
+	}
+	return Query{"bool": clauses}
+}
+
+// SearchOption configures Search
+type SearchOption func(map[string]any)
+
+// WithSize sets the most hits returned; the engine's default is 10
+func WithSize(size int) SearchOption {
+	return func(body map[string]any) {
+		body["size"] = size
+	}
+}
+
+// WithFrom sets how many hits to skip. Prefer WithSearchAfter to page deeply.
+func WithFrom(from int) SearchOption {
+	return func(body map[string]any) {
+		body["from"] = from
+	}
+}
+
+// WithSort adds a sort on field, descending if desc is true. Sorts apply in the order they're added.
+func WithSort(field string, desc bool) SearchOption {
+	return func(body map[string]any) {
+		order := "asc"
+		if desc {
+			order = "desc"
+		}
+		sorts, _ := body["sort"].([]any)
+		body["sort"] = append(sorts, map[string]any{field: map[string]any{"order": order}})
+	}
+}
+
+// WithSearchAfter returns the hits after the one whose sort values are searchAfter, i.e.
+// SearchOutput.SearchAfter from the previous page. It requires WithSort.
+func WithSearchAfter(searchAfter []any) SearchOption {
+	return func(body map[string]any) {
+		if len(searchAfter) > 0 {
+			body["search_after"] = searchAfter
+		}
+	}
+}
+
+// Hit is one document matching a search
+type Hit[T any] struct {
+	ID     string
+	Score  float64
+	Source T
+}
+
+// SearchOutput is a page of search hits
+type SearchOutput[T any] struct {
+	Hits []Hit[T]
+	// Total is the number of matching documents; the engine may stop counting at 10,000
+	Total int
+	// SearchAfter is the sort values of the last hit, for WithSearchAfter; it's nil if the search
+	// wasn't sorted or had no hits
+	SearchAfter []any
+}
+
+// Search returns the documents in index matching query
+func Search[T any](ctx context.Context, client *Client, index string, query Query, options ...SearchOption) (*SearchOutput[T], error) {
+	body := map[string]any{"query": query}
+	for _, option := range options {
+		option(body)
+	}
+
+	var response struct {
+		Hits struct {
+			Total struct {
+				Value int `json:"value"`
+			} `json:"total"`
+			Hits []struct {
+				ID     string          `json:"_id"`
+				Score  *float64        `json:"_score"`
+				Source json.RawMessage `json:"_source"`
+				Sort   []any           `json:"sort"`
+			} `json:"hits"`
+		} `json:"hits"`
+	}
+
+	path := "/" + url.PathEscape(client.indexName(index)) + "/_search"
+	if err := client.do(ctx, http.MethodPost, path, nil, body, &response); err != nil {
+		return nil, kit.WrapError(err, "error searching %s", index)
+	}
+
+	output := &SearchOutput[T]{
+		Hits:  make([]Hit[T], 0, len(response.Hits.Hits)),
+		Total: response.Hits.Total.Value,
+	}
+	for _, hit := range response.Hits.Hits {
+		var source T
+		if err := json.Unmarshal(hit.Source, &source); err != nil {
+			return nil, kit.WrapError(err, "error unmarshaling document %s from %s", hit.ID, index)
+		}
+		result := Hit[T]{ID: hit.ID, Source: source}
+		if hit.Score != nil {
+			result.Score = *hit.Score
+		}
+		output.Hits = append(output.Hits, result)
+		output.SearchAfter = hit.Sort
+	}
+
+	return output, nil
+}
@@ -0,0 +1,90 @@
+package searchkit
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQueries(t *testing.T) {
+	t.Run("build_the_query_dsl", func(t *testing.T) {
+		query := Bool().
+			Must(Match("title", "go"), MultiMatch("kit", "title", "body")).
+			Filter(Term("status", "published"), Terms("tag", "a", "b"), Range("views").Gte(10).Lt(100).Query(), Exists("author")).
+			Should(Prefix("title", "go")).
+			MustNot(MatchAll()).
+			Query()
+
+		data, err := json.Marshal(query)
+
+		assert.NoError(t, err)
+		assert.JSONEq(t, `{"bool":{
+			"must":[{"match":{"title":"go"}},{"multi_match":{"query":"kit","fields":["title","body"]}}],
+			"filter":[{"term":{"status":"published"}},{"terms":{"tag":["a","b"]}},{"range":{"views":{"gte":10,"lt":100}}},{"exists":{"field":"author"}}],
+			"should":[{"prefix":{"title":"go"}}],
+			"must_not":[{"match_all":{}}]
+		}}`, string(data))
+	})
+}
+
+func TestSearch(t *testing.T) {
+	t.Run("sends_the_query_and_returns_the_hits", func(t *testing.T) {
+		var sent map[string]any
+		client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, http.MethodPost, r.Method)
+			assert.Equal(t, "/posts/_search", r.URL.Path)
+			assert.NoError(t, json.NewDecoder(r.Body).Decode(&sent))
+			fmt.Fprint(w, `{"hits":{"total":{"value":42},"hits":[
+				{"_id":"a","_score":1.5,"_source":{"title":"first","views":1},"sort":[10,"a"]},
+				{"_id":"b","_score":null,"_source":{"title":"second","views":2},"sort":[9,"b"]}
+			]}}`)
+		})
+
+		result, err := Search[testDocument](context.Background(), client, "posts", Match("title", "go"),
+			WithSize(2), WithFrom(4), WithSort("views", true), WithSort("_id", false), WithSearchAfter([]any{11, "z"}))
+
+		assert.NoError(t, err)
+		assert.Equal(t, &SearchOutput[testDocument]{
+			Hits: []Hit[testDocument]{
+				{ID: "a", Score: 1.5, Source: testDocument{Title: "first", Views: 1}},
+				{ID: "b", Source: testDocument{Title: "second", Views: 2}},
+			},
+			Total:       42,
+			SearchAfter: []any{float64(9), "b"},
+		}, result)
+		assert.Equal(t, map[string]any{
+			"query":        map[string]any{"match": map[string]any{"title": "go"}},
+			"size":         float64(2),
+			"from":         float64(4),
+			"sort":         []any{map[string]any{"views": map[string]any{"order": "desc"}}, map[string]any{"_id": map[string]any{"order": "asc"}}},
+			"search_after": []any{float64(11), "z"},
+		}, sent)
+	})
+
+	t.Run("returns_no_hits", func(t *testing.T) {
+		client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprint(w, `{"hits":{"total":{"value":0},"hits":[]}}`)
+		})
+
+		result, err := Search[testDocument](context.Background(), client, "posts", MatchAll())
+
+		assert.NoError(t, err)
+		assert.Empty(t, result.Hits)
+		assert.Nil(t, result.SearchAfter)
+	})
+
+	t.Run("returns_an_error_when_the_request_fails", func(t *testing.T) {
+		client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusBadRequest)
+			fmt.Fprint(w, `{"error":{"type":"parsing_exception","reason":"theReason"}}`)
+		})
+
+		_, err := Search[testDocument](context.Background(), client, "posts", MatchAll())
+
+		assert.EqualError(t, err, "error searching posts: search request failed with status 400: parsing_exception: theReason")
+	})
+}
@@ -0,0 +1,180 @@
+// Package searchkit has typed helpers for OpenSearch and Elasticsearch: Index, Get, and Delete for
+// single documents, Search with a small query builder, bulk indexing with retries, and
+// SyncStreamRecords to keep an index in sync with a DynamoDB table's stream. It talks to the REST API
+// directly, using only the endpoints both engines share.
+package searchkit
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// ErrNotFound is returned when an index or document doesn't exist
+var ErrNotFound = errors.New("not found")
+
+// ResponseError is an error response from the search engine
+type ResponseError struct {
+	StatusCode int
+	Type       string
+	Reason     string
+}
+
+func (e *ResponseError) Error() string {
+	if e.Type == "" {
+		return fmt.Sprintf("search request failed with status %d: %s", e.StatusCode, e.Reason)
+	}
+	return fmt.Sprintf("search request failed with status %d: %s: %s", e.StatusCode, e.Type, e.Reason)
+}
+
+// Is makes errors.Is(err, ErrNotFound) true for 404 responses
+func (e *ResponseError) Is(target error) bool {
+	return target == ErrNotFound && e.StatusCode == http.StatusNotFound
+}
+
+// Retryable reports whether the request may succeed if sent again: rate limits and server errors
+func (e *ResponseError) Retryable() bool {
+	return e.StatusCode == http.StatusTooManyRequests || e.StatusCode >= http.StatusInternalServerError
+}
+
+// ClientOption configures a Client
+type ClientOption func(*Client)
+
+// WithHTTPClient sets the client used to send requests, e.g. one that signs requests for Amazon
+// OpenSearch Service
+func WithHTTPClient(client *http.Client) ClientOption {
+	return func(c *Client) {
+		c.httpClient = client
+	}
+}
+
+// WithBasicAuth sets the username and password sent with each request
+func WithBasicAuth(username string, password string) ClientOption {
+	return func(c *Client) {
+		c.username = username
+		c.password = password
+	}
+}
+
+// WithIndexPrefix sets a prefix added to every index name, e.g. "dev-", like dynamodbkit's table name
+// suffix
+func WithIndexPrefix(prefix string) ClientOption {
+	return func(c *Client) {
+		c.indexPrefix = prefix
+	}
+}
+
+// Client sends requests to an OpenSearch or Elasticsearch cluster
+type Client struct {
+	baseURL     string
+	httpClient  *http.Client
+	username    string
+	password    string
+	indexPrefix string
+}
+
+// NewClient returns a client for the cluster at baseURL, e.g. "https://localhost:9200"
+func NewClient(baseURL string, options ...ClientOption) *Client {
+	client := &Client{
+		baseURL:    strings.TrimSuffix(baseURL, "/"),
+		httpClient: http.DefaultClient,
+	}
+	for _, option := range options {
+		option(client)
+	}
+	return client
+}
+
+func (c *Client) indexName(index string) string {
+	return c.indexPrefix + index
+}
+
+// documentPath returns the path of document id in index
+func (c *Client) documentPath(index string, id string) string {
+	return "/" + url.PathEscape(c.indexName(index)) + "/_doc/" + url.PathEscape(id)
+}
+
+// do sends a request with body marshaled as JSON, or sent as is if it's a []byte, and decodes the
+// response into out when it isn't nil
+func (c *Client) do(ctx context.Context, method string, path string, query url.Values, body any, out any) error {
+	var reader io.Reader
+	contentType := "application/json"
+	switch b := body.(type) {
+	case nil:
+	case []byte:
+		reader = bytes.NewReader(b)
+		contentType = "application/x-ndjson"
+	default:
+		payload, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("failed to marshal search request: %w", err)
+		}
+		reader = bytes.NewReader(payload)
+	}
+
+	target := c.baseURL + path
+	if len(query) > 0 {
+		target += "?" + query.Encode()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, target, reader)
+	if err != nil {
+		return fmt.Errorf("failed to create search request: %w", err)
+	}
+	if reader != nil {
+		req.Header.Set("Content-Type", contentType)
+	}
+	if c.username != "" {
+		req.SetBasicAuth(c.username, c.password)
+	}
+
+	res, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send search request: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		return responseError(res)
+	}
+
+	if out == nil {
+		_, _ = io.Copy(io.Discard, res.Body)
+		return nil
+	}
+
+	if err := json.NewDecoder(res.Body).Decode(out); err != nil {
+		return fmt.Errorf("failed to decode search response: %w", err)
+	}
+	return nil
+}
+
+// responseError reads the engine's error body, e.g. {"error":{"type":"...","reason":"..."}}
+func responseError(res *http.Response) error {
+	data, _ := io.ReadAll(io.LimitReader(res.Body, 4096))
+
+	var body struct {
+		Error json.RawMessage `json:"error"`
+	}
+	if err := json.Unmarshal(data, &body); err == nil && len(body.Error) > 0 {
+		var detail struct {
+			Type   string `json:"type"`
+			Reason string `json:"reason"`
+		}
+		if err := json.Unmarshal(body.Error, &detail); err == nil {
+			return &ResponseError{StatusCode: res.StatusCode, Type: detail.Type, Reason: detail.Reason}
+		}
+		var reason string
+		if err := json.Unmarshal(body.Error, &reason); err == nil {
+			return &ResponseError{StatusCode: res.StatusCode, Reason: reason}
+		}
+	}
+
+	return &ResponseError{StatusCode: res.StatusCode, Reason: strings.TrimSpace(string(data))}
+}
@@ -0,0 +1,80 @@
+package searchkit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestClient(t *testing.T, handler http.HandlerFunc, options ...ClientOption) *Client {
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+	return NewClient(server.URL+"/", options...)
+}
+
+func TestClient_do(t *testing.T) {
+	t.Run("sends_basic_auth_when_configured", func(t *testing.T) {
+		client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+			username, password, ok := r.BasicAuth()
+			assert.True(t, ok)
+			assert.Equal(t, "theUsername", username)
+			assert.Equal(t, "thePassword", password)
+		}, WithBasicAuth("theUsername", "thePassword"))
+
+		err := client.do(context.Background(), http.MethodGet, "/", nil, nil, nil)
+
+		assert.NoError(t, err)
+	})
+
+	t.Run("returns_a_response_error_with_the_error_type_and_reason", func(t *testing.T) {
+		client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusBadRequest)
+			fmt.Fprint(w, `{"error":{"type":"parsing_exception","reason":"theReason"},"status":400}`)
+		})
+
+		err := client.do(context.Background(), http.MethodGet, "/", nil, nil, nil)
+
+		assert.Equal(t, &ResponseError{StatusCode: http.StatusBadRequest, Type: "parsing_exception", Reason: "theReason"}, err)
+		assert.EqualError(t, err, "search request failed with status 400: parsing_exception: theReason")
+	})
+
+	t.Run("returns_a_response_error_with_a_string_error", func(t *testing.T) {
+		client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusUnauthorized)
+			fmt.Fprint(w, `{"error":"theError"}`)
+		})
+
+		err := client.do(context.Background(), http.MethodGet, "/", nil, nil, nil)
+
+		assert.Equal(t, &ResponseError{StatusCode: http.StatusUnauthorized, Reason: "theError"}, err)
+	})
+
+	t.Run("returns_a_response_error_with_the_body_when_it_is_not_an_error_object", func(t *testing.T) {
+		client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusBadGateway)
+			fmt.Fprint(w, "theBody\n")
+		})
+
+		err := client.do(context.Background(), http.MethodGet, "/", nil, nil, nil)
+
+		assert.Equal(t, &ResponseError{StatusCode: http.StatusBadGateway, Reason: "theBody"}, err)
+	})
+}
+
+func TestResponseError(t *testing.T) {
+	t.Run("is_err_not_found_for_404", func(t *testing.T) {
+		assert.True(t, errors.Is(&ResponseError{StatusCode: http.StatusNotFound}, ErrNotFound))
+		assert.False(t, errors.Is(&ResponseError{StatusCode: http.StatusBadRequest}, ErrNotFound))
+	})
+
+	t.Run("is_retryable_for_rate_limits_and_server_errors", func(t *testing.T) {
+		assert.True(t, (&ResponseError{StatusCode: http.StatusTooManyRequests}).Retryable())
+		assert.True(t, (&ResponseError{StatusCode: http.StatusServiceUnavailable}).Retryable())
+		assert.False(t, (&ResponseError{StatusCode: http.StatusBadRequest}).Retryable())
+	})
+}
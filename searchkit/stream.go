@@ -0,0 +1,109 @@
+package searchkit
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	streamtypes "github.com/aws/aws-sdk-go-v2/service/dynamodbstreams/types"
+
+	"github.com/half-ogre/go-kit/kit"
+)
+
+// StreamSyncOption configures SyncStreamRecords
+type StreamSyncOption func(*streamSyncConfig)
+
+type streamSyncConfig struct {
+	documentID  func(keys map[string]types.AttributeValue) (string, error)
+	bulkOptions []BulkOption
+}
+
+// WithDocumentID sets how a document's ID is made from its item's key. The default joins the key's
+// values, ordered by attribute name, with "#".
+func WithDocumentID(documentID func(keys map[string]types.AttributeValue) (string, error)) StreamSyncOption {
+	return func(c *streamSyncConfig) {
+		c.documentID = documentID
+	}
+}
+
+// WithStreamBulkOptions sets the options for the bulk request that applies the records
+func WithStreamBulkOptions(options ...BulkOption) StreamSyncOption {
+	return func(c *streamSyncConfig) {
+		c.bulkOptions = options
+	}
+}
+
+// SyncStreamRecords applies a batch of DynamoDB stream records to index in one bulk request: inserts
+// and modifications index the item's new image unmarshaled into T, and removals delete the document.
+// The stream must include new images (NEW_IMAGE or NEW_AND_OLD_IMAGES). Records apply in order, so
+// pass each shard's records in sequence-number order and return the error to have the batch retried.
+func SyncStreamRecords[T any](ctx context.Context, client *Client, index string, records []streamtypes.Record, options ...StreamSyncOption) error {
+	config := streamSyncConfig{documentID: keyDocumentID}
+	for _, option := range options {
+		option(&config)
+	}
+
+	actions := make([]BulkAction, 0, len(records))
+	for _, record := range records {
+		if record.Dynamodb == nil {
+			continue
+		}
+
+		keys, err := attributevalue.FromDynamoDBStreamsMap(record.Dynamodb.Keys)
+		if err != nil {
+			return kit.WrapError(err, "error converting stream record keys")
+		}
+		id, err := config.documentID(keys)
+		if err != nil {
+			return kit.WrapError(err, "error making document ID")
+		}
+
+		switch record.EventName {
+		case streamtypes.OperationTypeInsert, streamtypes.OperationTypeModify:
+			if record.Dynamodb.NewImage == nil {
+				return fmt.Errorf("stream record for %s has no new image; the stream must include new images", id)
+			}
+			image, err := attributevalue.FromDynamoDBStreamsMap(record.Dynamodb.NewImage)
+			if err != nil {
+				return kit.WrapError(err, "error converting new image of %s", id)
+			}
+			var document T
+			if err := attributevalue.UnmarshalMap(image, &document); err != nil {
+				return kit.WrapError(err, "error unmarshaling new image of %s", id)
+			}
+			actions = append(actions, IndexAction(index, id, document))
+		case streamtypes.OperationTypeRemove:
+			actions = append(actions, DeleteAction(index, id))
+		}
+	}
+
+	if err := Bulk(ctx, client, actions, config.bulkOptions...); err != nil {
+		return kit.WrapError(err, "error syncing stream records to %s", index)
+	}
+	return nil
+}
+
+func keyDocumentID(keys map[string]types.AttributeValue) (string, error) {
+	names := make([]string, 0, len(keys))
+	for name := range keys {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	values := make([]string, 0, len(names))
+	for _, name := range names {
+		switch value := keys[name].(type) {
+		case *types.AttributeValueMemberS:
+			values = append(values, value.Value)
+		case *types.AttributeValueMemberN:
+			values = append(values, value.Value)
+		default:
+			return "", fmt.Errorf("unsupported type %T for key attribute %s", value, name)
+		}
+	}
+
+	return strings.Join(values, "#"), nil
+}
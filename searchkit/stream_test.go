@@ -0,0 +1,110 @@
+package searchkit
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	streamtypes "github.com/aws/aws-sdk-go-v2/service/dynamodbstreams/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSyncStreamRecords(t *testing.T) {
+	t.Run("indexes_inserts_and_modifications_and_deletes_removals", func(t *testing.T) {
+		var sent []map[string]any
+		client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+			sent = readBulkBody(t, r)
+			fmt.Fprint(w, `{"errors":false,"items":[]}`)
+		})
+		records := []streamtypes.Record{
+			{
+				EventName: streamtypes.OperationTypeInsert,
+				Dynamodb: &streamtypes.StreamRecord{
+					Keys: map[string]streamtypes.AttributeValue{
+						"pk": &streamtypes.AttributeValueMemberS{Value: "user"},
+						"sk": &streamtypes.AttributeValueMemberN{Value: "1"},
+					},
+					NewImage: map[string]streamtypes.AttributeValue{
+						"pk":    &streamtypes.AttributeValueMemberS{Value: "user"},
+						"sk":    &streamtypes.AttributeValueMemberN{Value: "1"},
+						"title": &streamtypes.AttributeValueMemberS{Value: "aTitle"},
+						"views": &streamtypes.AttributeValueMemberN{Value: "3"},
+					},
+				},
+			},
+			{
+				EventName: streamtypes.OperationTypeRemove,
+				Dynamodb: &streamtypes.StreamRecord{
+					Keys: map[string]streamtypes.AttributeValue{
+						"pk": &streamtypes.AttributeValueMemberS{Value: "user"},
+						"sk": &streamtypes.AttributeValueMemberN{Value: "2"},
+					},
+				},
+			},
+		}
+
+		err := SyncStreamRecords[testDocument](context.Background(), client, "posts", records)
+
+		assert.NoError(t, err)
+		assert.Equal(t, []map[string]any{
+			{"index": map[string]any{"_index": "posts", "_id": "user#1"}},
+			{"title": "aTitle", "views": float64(3)},
+			{"delete": map[string]any{"_index": "posts", "_id": "user#2"}},
+		}, sent)
+	})
+
+	t.Run("uses_the_configured_document_id", func(t *testing.T) {
+		var sent []map[string]any
+		client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+			sent = readBulkBody(t, r)
+			fmt.Fprint(w, `{"errors":false,"items":[]}`)
+		})
+		records := []streamtypes.Record{{
+			EventName: streamtypes.OperationTypeRemove,
+			Dynamodb: &streamtypes.StreamRecord{
+				Keys: map[string]streamtypes.AttributeValue{"id": &streamtypes.AttributeValueMemberS{Value: "theID"}},
+			},
+		}}
+
+		err := SyncStreamRecords[testDocument](context.Background(), client, "posts", records, WithDocumentID(func(keys map[string]types.AttributeValue) (string, error) {
+			return "post-" + keys["id"].(*types.AttributeValueMemberS).Value, nil
+		}))
+
+		assert.NoError(t, err)
+		assert.Equal(t, map[string]any{"delete": map[string]any{"_index": "posts", "_id": "post-theID"}}, sent[0])
+	})
+
+	t.Run("returns_an_error_when_a_record_has_no_new_image", func(t *testing.T) {
+		client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+			t.Fatal("no request should be sent")
+		})
+		records := []streamtypes.Record{{
+			EventName: streamtypes.OperationTypeModify,
+			Dynamodb: &streamtypes.StreamRecord{
+				Keys: map[string]streamtypes.AttributeValue{"id": &streamtypes.AttributeValueMemberS{Value: "theID"}},
+			},
+		}}
+
+		err := SyncStreamRecords[testDocument](context.Background(), client, "posts", records)
+
+		assert.EqualError(t, err, "stream record for theID has no new image; the stream must include new images")
+	})
+
+	t.Run("returns_an_error_for_an_unsupported_key_type", func(t *testing.T) {
+		client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+			t.Fatal("no request should be sent")
+		})
+		records := []streamtypes.Record{{
+			EventName: streamtypes.OperationTypeRemove,
+			Dynamodb: &streamtypes.StreamRecord{
+				Keys: map[string]streamtypes.AttributeValue{"id": &streamtypes.AttributeValueMemberB{Value: []byte("a")}},
+			},
+		}}
+
+		err := SyncStreamRecords[testDocument](context.Background(), client, "posts", records)
+
+		assert.ErrorContains(t, err, "error making document ID: unsupported type *types.AttributeValueMemberB for key attribute id")
+	})
+}
@@ -0,0 +1,85 @@
+package sesskit
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/half-ogre/go-kit/kit"
+)
+
+// Backend keeps session data on the server by session ID, so the cookie only carries the ID. Values are
+// opaque bytes so the backend can be in process (see MemoryBackend) or shared, such as Redis or
+// DynamoDB, which every service reading the sessions needs.
+type Backend interface {
+	Load(ctx context.Context, id string) ([]byte, bool, error)
+	Save(ctx context.Context, id string, data []byte, ttl time.Duration) error
+	Delete(ctx context.Context, id string) error
+}
+
+// MemoryBackend is an in-process Backend
+type MemoryBackend struct {
+	mu        sync.Mutex
+	entries   map[string]memoryBackendEntry
+	nextSweep int
+	clock     kit.ClockInterface
+}
+
+type memoryBackendEntry struct {
+	data      []byte
+	expiresAt time.Time
+}
+
+// NewMemoryBackend returns an empty MemoryBackend
+func NewMemoryBackend() *MemoryBackend {
+	return &MemoryBackend{
+		entries:   map[string]memoryBackendEntry{},
+		nextSweep: minMemoryBackendSweep,
+		clock:     kit.NewClock(),
+	}
+}
+
+const minMemoryBackendSweep = 1024
+
+func (b *MemoryBackend) Load(ctx context.Context, id string) ([]byte, bool, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	entry, ok := b.entries[id]
+	if !ok {
+		return nil, false, nil
+	}
+	if !b.clock.Now().Before(entry.expiresAt) {
+		delete(b.entries, id)
+		return nil, false, nil
+	}
+	return entry.data, true, nil
+}
+
+func (b *MemoryBackend) Save(ctx context.Context, id string, data []byte, ttl time.Duration) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	now := b.clock.Now()
+	b.entries[id] = memoryBackendEntry{data: data, expiresAt: now.Add(ttl)}
+
+	// Abandoned sessions are never loaded again, so expired entries are swept as the backend grows
+	if len(b.entries) >= b.nextSweep {
+		for key, entry := range b.entries {
+			if !now.Before(entry.expiresAt) {
+				delete(b.entries, key)
+			}
+		}
+		b.nextSweep = max(minMemoryBackendSweep, 2*len(b.entries))
+	}
+
+	return nil
+}
+
+func (b *MemoryBackend) Delete(ctx context.Context, id string) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	delete(b.entries, id)
+	return nil
+}
@@ -0,0 +1,68 @@
+package sesskit
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"errors"
+	"fmt"
+)
+
+// Cipher encrypts session data, authenticating associatedData with it, which is the session's name.
+// A kmskit.Encrypter is a Cipher, for envelope encryption with keys kept in KMS.
+type Cipher interface {
+	Encrypt(ctx context.Context, plaintext []byte, associatedData []byte) ([]byte, error)
+	Decrypt(ctx context.Context, ciphertext []byte, associatedData []byte) ([]byte, error)
+}
+
+type aesCipher struct {
+	aeads []cipher.AEAD
+}
+
+// NewAESCipher returns a Cipher using AES-GCM with 16, 24, or 32 byte keys. It encrypts with the first
+// key and decrypts with any of them, so keys can be rotated by putting the new one first and dropping
+// the old one once the sessions it encrypted have expired.
+func NewAESCipher(keys ...[]byte) (Cipher, error) {
+	if len(keys) == 0 {
+		return nil, errors.New("at least one key is required")
+	}
+
+	c := &aesCipher{}
+	for i, key := range keys {
+		block, err := aes.NewCipher(key)
+		if err != nil {
+			return nil, fmt.Errorf("key %d: %w", i, err)
+		}
+		aead, err := cipher.NewGCM(block)
+		if err != nil {
+			return nil, fmt.Errorf("key %d: %w", i, err)
+		}
+		c.aeads = append(c.aeads, aead)
+	}
+	return c, nil
+}
+
+func (c *aesCipher) Encrypt(ctx context.Context, plaintext []byte, associatedData []byte) ([]byte, error) {
+	aead := c.aeads[0]
+	nonce := make([]byte, aead.NonceSize())
+	_, err := rand.Read(nonce)
+	if err != nil {
+		return nil, err
+	}
+	return aead.Seal(nonce, nonce, plaintext, associatedData), nil
+}
+
+func (c *aesCipher) Decrypt(ctx context.Context, ciphertext []byte, associatedData []byte) ([]byte, error) {
+	for _, aead := range c.aeads {
+		if len(ciphertext) < aead.NonceSize() {
+			break
+		}
+		nonce, sealed := ciphertext[:aead.NonceSize()], ciphertext[aead.NonceSize():]
+		plaintext, err := aead.Open(nil, nonce, sealed, associatedData)
+		if err == nil {
+			return plaintext, nil
+		}
+	}
+	return nil, errors.New("session data could not be decrypted with any key")
+}
@@ -0,0 +1,57 @@
+package sesskit
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/half-ogre/go-kit/kmskit"
+)
+
+// A kmskit.Encrypter is a Cipher
+var _ Cipher = (*kmskit.Encrypter)(nil)
+
+func TestAESCipher(t *testing.T) {
+	oldKey := bytes.Repeat([]byte{1}, 32)
+	newKey := bytes.Repeat([]byte{2}, 32)
+
+	t.Run("decrypts_what_it_encrypts", func(t *testing.T) {
+		cipher, _ := NewAESCipher(newKey)
+		ciphertext, err := cipher.Encrypt(context.Background(), []byte("thePlaintext"), []byte("theSession"))
+		require.NoError(t, err)
+
+		plaintext, err := cipher.Decrypt(context.Background(), ciphertext, []byte("theSession"))
+
+		require.NoError(t, err)
+		assert.Equal(t, "thePlaintext", string(plaintext))
+	})
+
+	t.Run("decrypts_with_a_previous_key", func(t *testing.T) {
+		old, _ := NewAESCipher(oldKey)
+		ciphertext, _ := old.Encrypt(context.Background(), []byte("thePlaintext"), nil)
+		rotated, _ := NewAESCipher(newKey, oldKey)
+
+		plaintext, err := rotated.Decrypt(context.Background(), ciphertext, nil)
+
+		require.NoError(t, err)
+		assert.Equal(t, "thePlaintext", string(plaintext))
+	})
+
+	t.Run("fails_with_different_associated_data", func(t *testing.T) {
+		cipher, _ := NewAESCipher(newKey)
+		ciphertext, _ := cipher.Encrypt(context.Background(), []byte("thePlaintext"), []byte("theSession"))
+
+		_, err := cipher.Decrypt(context.Background(), ciphertext, []byte("otherSession"))
+
+		assert.EqualError(t, err, "session data could not be decrypted with any key")
+	})
+
+	t.Run("returns_an_error_for_an_invalid_key", func(t *testing.T) {
+		_, err := NewAESCipher([]byte("short"))
+
+		assert.ErrorContains(t, err, "key 0")
+	})
+}
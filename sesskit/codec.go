@@ -0,0 +1,56 @@
+package sesskit
+
+import (
+	"bytes"
+	"encoding/gob"
+	"encoding/json"
+	"time"
+)
+
+// Data is what's kept for a session: its values and when it was created and last saved
+type Data struct {
+	Values  map[string]any `json:"values"`
+	Created time.Time      `json:"created"`
+	Saved   time.Time      `json:"saved"`
+}
+
+// Codec turns session data into bytes to keep and back
+type Codec interface {
+	Encode(data *Data) ([]byte, error)
+	Decode(encoded []byte, data *Data) error
+}
+
+// The codecs
+var (
+	// JSONCodec encodes sessions as JSON, which any service can read; values come back as JSON types,
+	// e.g. numbers as float64. It's the default.
+	JSONCodec Codec = jsonCodec{}
+	// GobCodec encodes sessions with encoding/gob, which keeps values' Go types; register the types of
+	// values that aren't basic types with gob.Register
+	GobCodec Codec = gobCodec{}
+)
+
+type jsonCodec struct{}
+
+func (jsonCodec) Encode(data *Data) ([]byte, error) {
+	return json.Marshal(data)
+}
+
+func (jsonCodec) Decode(encoded []byte, data *Data) error {
+	return json.Unmarshal(encoded, data)
+}
+
+type gobCodec struct{}
+
+func (gobCodec) Encode(data *Data) ([]byte, error) {
+	var buf bytes.Buffer
+	err := gob.NewEncoder(&buf).Encode(data)
+	if err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func (gobCodec) Decode(encoded []byte, data *Data) error {
+	return gob.NewDecoder(bytes.NewReader(encoded)).Decode(data)
+}
@@ -0,0 +1,32 @@
+package sesskit
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCodecs(t *testing.T) {
+	theData := &Data{
+		Values:  map[string]any{"user": "theUser"},
+		Created: time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC),
+		Saved:   time.Date(2026, 1, 2, 0, 0, 0, 0, time.UTC),
+	}
+
+	for name, codec := range map[string]Codec{"json": JSONCodec, "gob": GobCodec} {
+		t.Run(name+"_decodes_what_it_encodes", func(t *testing.T) {
+			encoded, err := codec.Encode(theData)
+			require.NoError(t, err)
+
+			var decoded Data
+			err = codec.Decode(encoded, &decoded)
+
+			require.NoError(t, err)
+			assert.Equal(t, theData.Values, decoded.Values)
+			assert.True(t, theData.Created.Equal(decoded.Created))
+			assert.True(t, theData.Saved.Equal(decoded.Saved))
+		})
+	}
+}
@@ -0,0 +1,33 @@
+package sesskit
+
+import "time"
+
+// ExpiryPolicy decides when a session expires, after which it's read as a new, empty session
+type ExpiryPolicy struct {
+	// IdleTimeout expires a session this long after it was last saved; 0 means never
+	IdleTimeout time.Duration
+	// AbsoluteTimeout expires a session this long after it was created, however active it is; 0 means
+	// never
+	AbsoluteTimeout time.Duration
+}
+
+// DefaultExpiryPolicy expires sessions 30 days after they're created
+var DefaultExpiryPolicy = ExpiryPolicy{AbsoluteTimeout: 30 * 24 * time.Hour}
+
+// Expired returns whether the session is expired at now
+func (p ExpiryPolicy) Expired(data *Data, now time.Time) bool {
+	return p.Remaining(data, now) <= 0
+}
+
+// Remaining returns how long after now the session expires, or the longest duration when the policy
+// never expires it
+func (p ExpiryPolicy) Remaining(data *Data, now time.Time) time.Duration {
+	remaining := time.Duration(1<<63 - 1)
+	if p.IdleTimeout > 0 {
+		remaining = min(remaining, data.Saved.Add(p.IdleTimeout).Sub(now))
+	}
+	if p.AbsoluteTimeout > 0 {
+		remaining = min(remaining, data.Created.Add(p.AbsoluteTimeout).Sub(now))
+	}
+	return remaining
+}
@@ -0,0 +1,31 @@
+package sesskit
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExpiryPolicy(t *testing.T) {
+	theCreated := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	theData := &Data{Created: theCreated, Saved: theCreated.Add(time.Hour)}
+
+	t.Run("expires_after_the_idle_timeout_since_last_saved", func(t *testing.T) {
+		policy := ExpiryPolicy{IdleTimeout: 30 * time.Minute}
+
+		assert.False(t, policy.Expired(theData, theCreated.Add(89*time.Minute)))
+		assert.True(t, policy.Expired(theData, theCreated.Add(90*time.Minute)))
+	})
+
+	t.Run("expires_after_the_absolute_timeout_since_created", func(t *testing.T) {
+		policy := ExpiryPolicy{IdleTimeout: time.Hour, AbsoluteTimeout: 90 * time.Minute}
+
+		assert.Equal(t, 10*time.Minute, policy.Remaining(theData, theCreated.Add(80*time.Minute)))
+		assert.True(t, policy.Expired(theData, theCreated.Add(90*time.Minute)))
+	})
+
+	t.Run("never_expires_without_timeouts", func(t *testing.T) {
+		assert.False(t, ExpiryPolicy{}.Expired(theData, theCreated.Add(1000*time.Hour)))
+	})
+}
@@ -0,0 +1,273 @@
+// Package sesskit is the session core echokit and ginkit share: a gorilla/sessions Store that encodes
+// sessions with a Codec, encrypts them with a Cipher, expires them by an ExpiryPolicy, and keeps them
+// in the cookie itself or in a server-side Backend. Services built on either framework, configured with
+// the same codec, cipher keys, and backend, read each other's sessions, e.g. while moving routes from
+// one framework to the other.
+//
+//	cipher, err := sesskit.NewAESCipher(key)
+//	store, err := sesskit.NewStore(sesskit.WithCipher(cipher))
+//	e.Use(echokit.NewSessionMiddleware(store))
+//	router.Use(ginkit.Sessions(store))
+package sesskit
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gorilla/sessions"
+
+	"github.com/half-ogre/go-kit/kit"
+	"github.com/half-ogre/go-kit/logkit"
+)
+
+var logger = logkit.Logger("sesskit")
+
+// maxCookieSize is the most a browser is sure to keep of a cookie
+const maxCookieSize = 4096
+
+// StoreOption configures a Store
+type StoreOption func(*Store)
+
+// WithCodec sets how sessions are encoded; the default is JSONCodec
+func WithCodec(codec Codec) StoreOption {
+	return func(s *Store) {
+		s.codec = codec
+	}
+}
+
+// WithCipher sets how sessions are encrypted, which keeps their values private and stops them being
+// tampered with. A store keeping sessions in cookies requires one.
+func WithCipher(cipher Cipher) StoreOption {
+	return func(s *Store) {
+		s.cipher = cipher
+	}
+}
+
+// WithBackend keeps sessions in backend, with only their IDs in cookies, rather than in the cookies
+// themselves
+func WithBackend(backend Backend) StoreOption {
+	return func(s *Store) {
+		s.backend = backend
+	}
+}
+
+// WithExpiryPolicy sets when sessions expire; the default is DefaultExpiryPolicy
+func WithExpiryPolicy(policy ExpiryPolicy) StoreOption {
+	return func(s *Store) {
+		s.expiry = policy
+	}
+}
+
+// WithCookieOptions sets the options of session cookies; the default is path /, HttpOnly, SameSite
+// Lax, and a Max-Age of the expiry policy's absolute timeout
+func WithCookieOptions(options sessions.Options) StoreOption {
+	return func(s *Store) {
+		s.options = &options
+	}
+}
+
+// WithStoreClock sets the clock sessions expire by
+func WithStoreClock(clock kit.ClockInterface) StoreOption {
+	return func(s *Store) {
+		s.clock = clock
+	}
+}
+
+// Store is a gorilla/sessions Store, so it works with echokit.NewSessionMiddleware, ginkit.Sessions,
+// and anything else built on gorilla/sessions. Session values must have string keys.
+type Store struct {
+	codec   Codec
+	cipher  Cipher
+	backend Backend
+	expiry  ExpiryPolicy
+	options *sessions.Options
+	clock   kit.ClockInterface
+}
+
+// NewStore returns a Store, which requires a cipher unless it has a backend
+func NewStore(options ...StoreOption) (*Store, error) {
+	s := &Store{
+		codec:  JSONCodec,
+		expiry: DefaultExpiryPolicy,
+		clock:  kit.NewClock(),
+	}
+	for _, option := range options {
+		option(s)
+	}
+
+	if s.backend == nil && s.cipher == nil {
+		return nil, errors.New("a store keeping sessions in cookies requires a cipher")
+	}
+	if s.options == nil {
+		s.options = &sessions.Options{
+			Path:     "/",
+			MaxAge:   int(s.expiry.AbsoluteTimeout / time.Second),
+			HttpOnly: true,
+			SameSite: http.SameSiteLaxMode,
+		}
+	}
+
+	return s, nil
+}
+
+// createdKey holds when a session was created in its values while it's in use
+type createdKey struct{}
+
+// Get returns the session named name for r, the same one each time it's called for a request
+func (s *Store) Get(r *http.Request, name string) (*sessions.Session, error) {
+	return sessions.GetRegistry(r).Get(s, name)
+}
+
+// New returns the session named name read from r's cookie, or a new one if there's no cookie or its
+// session is expired or can't be read
+func (s *Store) New(r *http.Request, name string) (*sessions.Session, error) {
+	session := sessions.NewSession(s, name)
+	options := *s.options
+	session.Options = &options
+	session.IsNew = true
+
+	cookie, err := r.Cookie(name)
+	if err != nil {
+		return session, nil
+	}
+
+	id, data, err := s.load(r, name, cookie.Value)
+	if err != nil {
+		logger.DebugContext(r.Context(), "starting a new session in place of one that can't be read", "name", name, "error", err)
+		return session, nil
+	}
+	if data == nil || s.expiry.Expired(data, s.clock.Now()) {
+		return session, nil
+	}
+
+	session.ID = id
+	session.IsNew = false
+	for key, value := range data.Values {
+		session.Values[key] = value
+	}
+	session.Values[createdKey{}] = data.Created
+	return session, nil
+}
+
+// Save writes session to w as a cookie, and to the backend if there is one. A session with a negative
+// MaxAge is deleted.
+func (s *Store) Save(r *http.Request, w http.ResponseWriter, session *sessions.Session) error {
+	if session.Options.MaxAge < 0 {
+		if s.backend != nil && session.ID != "" {
+			err := s.backend.Delete(r.Context(), session.ID)
+			if err != nil {
+				return kit.WrapError(err, "error deleting session %s", session.Name())
+			}
+		}
+		http.SetCookie(w, sessions.NewCookie(session.Name(), "", session.Options))
+		return nil
+	}
+
+	now := s.clock.Now()
+	data := &Data{Values: map[string]any{}, Created: now, Saved: now}
+	for key, value := range session.Values {
+		switch key := key.(type) {
+		case string:
+			data.Values[key] = value
+		case createdKey:
+			data.Created = value.(time.Time)
+		default:
+			return fmt.Errorf("session %s has a value with key %v that isn't a string", session.Name(), key)
+		}
+	}
+	session.Values[createdKey{}] = data.Created
+
+	encoded, err := s.codec.Encode(data)
+	if err != nil {
+		return kit.WrapError(err, "error encoding session %s", session.Name())
+	}
+	if s.cipher != nil {
+		encoded, err = s.cipher.Encrypt(r.Context(), encoded, []byte(session.Name()))
+		if err != nil {
+			return kit.WrapError(err, "error encrypting session %s", session.Name())
+		}
+	}
+
+	value := base64.RawURLEncoding.EncodeToString(encoded)
+	if s.backend != nil {
+		if session.ID == "" {
+			session.ID = newSessionID()
+		}
+		err = s.backend.Save(r.Context(), session.ID, encoded, s.expiry.Remaining(data, now))
+		if err != nil {
+			return kit.WrapError(err, "error saving session %s", session.Name())
+		}
+		value = session.ID
+	}
+
+	cookie := sessions.NewCookie(session.Name(), value, session.Options)
+	if len(cookie.String()) > maxCookieSize {
+		return fmt.Errorf("session %s is too large for a cookie; use a backend", session.Name())
+	}
+	http.SetCookie(w, cookie)
+	return nil
+}
+
+// load reads the session a cookie value refers to, returning nil data when a backend doesn't have it
+func (s *Store) load(r *http.Request, name string, value string) (string, *Data, error) {
+	var id string
+	var encoded []byte
+	if s.backend != nil {
+		id = value
+		var ok bool
+		var err error
+		encoded, ok, err = s.backend.Load(r.Context(), id)
+		if err != nil || !ok {
+			return "", nil, err
+		}
+	} else {
+		var err error
+		encoded, err = base64.RawURLEncoding.DecodeString(value)
+		if err != nil {
+			return "", nil, kit.WrapError(err, "error decoding cookie")
+		}
+	}
+
+	if s.cipher != nil {
+		var err error
+		encoded, err = s.cipher.Decrypt(r.Context(), encoded, []byte(name))
+		if err != nil {
+			return "", nil, err
+		}
+	}
+
+	var data Data
+	err := s.codec.Decode(encoded, &data)
+	if err != nil {
+		return "", nil, kit.WrapError(err, "error decoding session")
+	}
+	return id, &data, nil
+}
+
+// newSessionID returns a random, unguessable session ID
+func newSessionID() string {
+	return rand.Text()
+}
+
+// Delete deletes the session named name, clearing its values and expiring its cookie, whatever store
+// it's in
+func Delete(r *http.Request, w http.ResponseWriter, store sessions.Store, name string) error {
+	session, err := store.Get(r, name)
+	if err != nil {
+		return kit.WrapError(err, "error getting session")
+	}
+
+	session.Values = make(map[interface{}]interface{})
+	session.Options.MaxAge = -1
+
+	err = session.Save(r, w)
+	if err != nil {
+		return kit.WrapError(err, "failed to delete session")
+	}
+
+	return nil
+}
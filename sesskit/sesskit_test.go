@@ -0,0 +1,184 @@
+package sesskit
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/half-ogre/go-kit/kit"
+)
+
+var theKey = bytes.Repeat([]byte{1}, 32)
+
+func newTestStore(t *testing.T, now *time.Time, options ...StoreOption) *Store {
+	cipher, err := NewAESCipher(theKey)
+	require.NoError(t, err)
+	options = append([]StoreOption{WithCipher(cipher), WithStoreClock(kit.NewClock(kit.WithFake(func() time.Time { return *now })))}, options...)
+	store, err := NewStore(options...)
+	require.NoError(t, err)
+	return store
+}
+
+// saveSession saves a session with values and returns the cookie it set
+func saveSession(t *testing.T, store *Store, values map[string]any) *http.Cookie {
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	w := httptest.NewRecorder()
+	session, err := store.Get(req, "theSession")
+	require.NoError(t, err)
+	for key, value := range values {
+		session.Values[key] = value
+	}
+	require.NoError(t, session.Save(req, w))
+	cookies := w.Result().Cookies()
+	require.Len(t, cookies, 1)
+	return cookies[0]
+}
+
+func requestWith(cookie *http.Cookie) *http.Request {
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.AddCookie(cookie)
+	return req
+}
+
+func TestNewStore(t *testing.T) {
+	t.Run("requires_a_cipher_without_a_backend", func(t *testing.T) {
+		_, err := NewStore()
+
+		assert.EqualError(t, err, "a store keeping sessions in cookies requires a cipher")
+	})
+
+	t.Run("does_not_require_a_cipher_with_a_backend", func(t *testing.T) {
+		_, err := NewStore(WithBackend(NewMemoryBackend()))
+
+		assert.NoError(t, err)
+	})
+}
+
+func TestStore(t *testing.T) {
+	t.Run("reads_back_a_session_kept_in_the_cookie", func(t *testing.T) {
+		now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+		store := newTestStore(t, &now)
+		cookie := saveSession(t, store, map[string]any{"user": "theUser"})
+
+		session, err := store.Get(requestWith(cookie), "theSession")
+
+		require.NoError(t, err)
+		assert.False(t, session.IsNew)
+		assert.Equal(t, "theUser", session.Values["user"])
+		assert.True(t, cookie.HttpOnly)
+		assert.NotContains(t, cookie.Value, "theUser")
+	})
+
+	t.Run("reads_back_a_session_kept_in_a_backend", func(t *testing.T) {
+		now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+		store := newTestStore(t, &now, WithBackend(NewMemoryBackend()))
+		cookie := saveSession(t, store, map[string]any{"user": "theUser"})
+
+		session, err := store.Get(requestWith(cookie), "theSession")
+
+		require.NoError(t, err)
+		assert.False(t, session.IsNew)
+		assert.Equal(t, cookie.Value, session.ID)
+		assert.Equal(t, "theUser", session.Values["user"])
+	})
+
+	t.Run("starts_a_new_session_when_the_cookie_was_tampered_with", func(t *testing.T) {
+		now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+		store := newTestStore(t, &now)
+		cookie := saveSession(t, store, map[string]any{"user": "theUser"})
+		cookie.Value = "A" + cookie.Value[1:]
+
+		session, err := store.Get(requestWith(cookie), "theSession")
+
+		require.NoError(t, err)
+		assert.True(t, session.IsNew)
+		assert.Empty(t, session.Values)
+	})
+
+	t.Run("starts_a_new_session_when_a_cookie_is_renamed", func(t *testing.T) {
+		now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+		store := newTestStore(t, &now)
+		cookie := saveSession(t, store, map[string]any{"user": "theUser"})
+		cookie.Name = "otherSession"
+
+		session, err := store.Get(requestWith(cookie), "otherSession")
+
+		require.NoError(t, err)
+		assert.True(t, session.IsNew)
+	})
+
+	t.Run("starts_a_new_session_after_the_idle_timeout", func(t *testing.T) {
+		now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+		store := newTestStore(t, &now, WithExpiryPolicy(ExpiryPolicy{IdleTimeout: time.Hour}))
+		cookie := saveSession(t, store, map[string]any{"user": "theUser"})
+		now = now.Add(time.Hour)
+
+		session, err := store.Get(requestWith(cookie), "theSession")
+
+		require.NoError(t, err)
+		assert.True(t, session.IsNew)
+	})
+
+	t.Run("keeps_the_creation_time_across_saves_for_the_absolute_timeout", func(t *testing.T) {
+		now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+		store := newTestStore(t, &now, WithExpiryPolicy(ExpiryPolicy{IdleTimeout: time.Hour, AbsoluteTimeout: 90 * time.Minute}))
+		cookie := saveSession(t, store, map[string]any{"user": "theUser"})
+		now = now.Add(50 * time.Minute)
+		req := requestWith(cookie)
+		session, _ := store.Get(req, "theSession")
+		w := httptest.NewRecorder()
+		require.NoError(t, session.Save(req, w))
+		now = now.Add(50 * time.Minute)
+
+		session, err := store.Get(requestWith(w.Result().Cookies()[0]), "theSession")
+
+		require.NoError(t, err)
+		assert.True(t, session.IsNew)
+	})
+
+	t.Run("returns_an_error_for_a_value_without_a_string_key", func(t *testing.T) {
+		now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+		store := newTestStore(t, &now)
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		session, _ := store.Get(req, "theSession")
+		session.Values[1] = "theValue"
+
+		err := session.Save(req, httptest.NewRecorder())
+
+		assert.EqualError(t, err, "session theSession has a value with key 1 that isn't a string")
+	})
+
+	t.Run("returns_an_error_when_the_session_is_too_large_for_a_cookie", func(t *testing.T) {
+		now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+		store := newTestStore(t, &now)
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		session, _ := store.Get(req, "theSession")
+		session.Values["large"] = string(bytes.Repeat([]byte("x"), 4096))
+
+		err := session.Save(req, httptest.NewRecorder())
+
+		assert.EqualError(t, err, "session theSession is too large for a cookie; use a backend")
+	})
+}
+
+func TestDelete(t *testing.T) {
+	t.Run("expires_the_cookie_and_removes_the_session_from_the_backend", func(t *testing.T) {
+		now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+		backend := NewMemoryBackend()
+		store := newTestStore(t, &now, WithBackend(backend))
+		cookie := saveSession(t, store, map[string]any{"user": "theUser"})
+		w := httptest.NewRecorder()
+
+		err := Delete(requestWith(cookie), w, store, "theSession")
+
+		require.NoError(t, err)
+		assert.Equal(t, -1, w.Result().Cookies()[0].MaxAge)
+		_, ok, _ := backend.Load(t.Context(), cookie.Value)
+		assert.False(t, ok)
+	})
+}
@@ -0,0 +1,45 @@
+package stepfnkit
+
+import (
+	"context"
+)
+
+// FakeStepFunctions is a StepFunctions for tests
+type FakeStepFunctions struct {
+	GetActivityTaskFake   func(ctx context.Context, activityARN string, workerName string) (*Task, error)
+	SendTaskSuccessFake   func(ctx context.Context, taskToken string, output string) error
+	SendTaskFailureFake   func(ctx context.Context, taskToken string, errorCode string, cause string) error
+	SendTaskHeartbeatFake func(ctx context.Context, taskToken string) error
+}
+
+func (f *FakeStepFunctions) GetActivityTask(ctx context.Context, activityARN string, workerName string) (*Task, error) {
+	if f.GetActivityTaskFake != nil {
+		return f.GetActivityTaskFake(ctx, activityARN, workerName)
+	} else {
+		panic("GetActivityTask fake not implemented")
+	}
+}
+
+func (f *FakeStepFunctions) SendTaskSuccess(ctx context.Context, taskToken string, output string) error {
+	if f.SendTaskSuccessFake != nil {
+		return f.SendTaskSuccessFake(ctx, taskToken, output)
+	} else {
+		panic("SendTaskSuccess fake not implemented")
+	}
+}
+
+func (f *FakeStepFunctions) SendTaskFailure(ctx context.Context, taskToken string, errorCode string, cause string) error {
+	if f.SendTaskFailureFake != nil {
+		return f.SendTaskFailureFake(ctx, taskToken, errorCode, cause)
+	} else {
+		panic("SendTaskFailure fake not implemented")
+	}
+}
+
+func (f *FakeStepFunctions) SendTaskHeartbeat(ctx context.Context, taskToken string) error {
+	if f.SendTaskHeartbeatFake != nil {
+		return f.SendTaskHeartbeatFake(ctx, taskToken)
+	} else {
+		panic("SendTaskHeartbeat fake not implemented")
+	}
+}
@@ -0,0 +1,63 @@
+// Package stepfnkit runs AWS Step Functions task workers. A Worker polls an activity for tasks and runs
+// them with a typed handler, and RunTask runs one task from a task token, e.g. one sent to an SQS queue
+// by a .waitForTaskToken state. Either way the task's JSON input is unmarshaled for the handler, the
+// task is kept alive with heartbeats while the handler runs, and the handler's output or error is sent
+// back with SendTaskSuccess or SendTaskFailure.
+//
+// The AWS SDK's Step Functions client isn't a dependency of this module, so workers use the small
+// StepFunctions interface instead. Adapting *sfn.Client to it takes a few lines: return a Task from
+// GetActivityTask's output, and ErrTaskTimedOut for a *types.TaskTimedOut error.
+package stepfnkit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/half-ogre/go-kit/logkit"
+)
+
+var logger = logkit.Logger("stepfnkit")
+
+// ErrTaskTimedOut is returned by StepFunctions when a task has timed out or its token is no longer
+// valid, so its result can't be sent
+var ErrTaskTimedOut = errors.New("task timed out")
+
+// StepFunctions is the part of the Step Functions API workers use
+type StepFunctions interface {
+	// GetActivityTask long polls for a task of the activity, returning nil if none arrived in time
+	GetActivityTask(ctx context.Context, activityARN string, workerName string) (*Task, error)
+	SendTaskSuccess(ctx context.Context, taskToken string, output string) error
+	SendTaskFailure(ctx context.Context, taskToken string, errorCode string, cause string) error
+	SendTaskHeartbeat(ctx context.Context, taskToken string) error
+}
+
+// Task is a unit of work from Step Functions: the token that identifies it and its JSON input
+type Task struct {
+	Token string
+	Input string
+}
+
+// Handler does a task's work with its input unmarshaled into a TInput. The TOutput it returns is
+// marshaled as the task's output; an error fails the task.
+type Handler[TInput any, TOutput any] func(ctx context.Context, input TInput) (TOutput, error)
+
+// TaskError is a task failure with an error code for the state machine's Retry and Catch rules to
+// match, e.g. "PaymentDeclined". Handlers return it to choose the code; other errors use
+// ErrorCodeHandlerFailed.
+type TaskError struct {
+	Code  string
+	Cause string
+}
+
+func (e *TaskError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Code, e.Cause)
+}
+
+// The error codes tasks fail with when the handler doesn't return a TaskError
+const (
+	ErrorCodeHandlerFailed   = "HandlerFailed"
+	ErrorCodeHandlerPanicked = "HandlerPanicked"
+	ErrorCodeInvalidInput    = "InvalidInput"
+	ErrorCodeInvalidOutput   = "InvalidOutput"
+)
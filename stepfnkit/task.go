@@ -0,0 +1,154 @@
+package stepfnkit
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/half-ogre/go-kit/kit"
+	"github.com/half-ogre/go-kit/logkit/logfields"
+)
+
+// Step Functions' limits on the length of a failure's error code and cause
+const (
+	maxErrorCodeLength = 256
+	maxCauseLength     = 32768
+)
+
+// RunTask runs task with handler, sending heartbeats every heartbeat interval while it runs, then sends
+// the handler's output with SendTaskSuccess or its error with SendTaskFailure. It returns nil once the
+// result has been sent, even for a failed task, so a task token read from a queue can be deleted. If a
+// heartbeat reports the task timed out, the handler's context is canceled and RunTask returns
+// ErrTaskTimedOut. Of the worker options, only WithHeartbeatInterval applies.
+func RunTask[TInput any, TOutput any](ctx context.Context, sfn StepFunctions, task Task, handler Handler[TInput, TOutput], options ...WorkerOption) error {
+	config := newWorkerConfig(options)
+
+	handlerCtx, cancel := context.WithCancelCause(ctx)
+	defer cancel(nil)
+	stopHeartbeats := startHeartbeats(handlerCtx, sfn, task.Token, config.heartbeatInterval, cancel)
+
+	start := time.Now()
+	output, err := runHandler(handlerCtx, task, handler)
+	stopHeartbeats()
+
+	if errors.Is(context.Cause(handlerCtx), ErrTaskTimedOut) {
+		return ErrTaskTimedOut
+	}
+
+	// Send the result even if ctx was canceled for shutdown, as the work is done
+	sendCtx := context.WithoutCancel(ctx)
+	if err != nil {
+		logger.WarnContext(ctx, "step functions task failed", logfields.Duration(time.Since(start)), "error", err)
+		return FailTask(sendCtx, sfn, task.Token, err)
+	}
+
+	logger.DebugContext(ctx, "step functions task succeeded", logfields.Duration(time.Since(start)))
+	if err := sfn.SendTaskSuccess(sendCtx, task.Token, output); err != nil {
+		return kit.WrapError(err, "error sending task success")
+	}
+	return nil
+}
+
+// CompleteTask sends output, marshaled as JSON, as the output of the task with taskToken
+func CompleteTask(ctx context.Context, sfn StepFunctions, taskToken string, output any) error {
+	marshaled, err := json.Marshal(output)
+	if err != nil {
+		return kit.WrapError(err, "error marshalling task output")
+	}
+
+	if err := sfn.SendTaskSuccess(ctx, taskToken, string(marshaled)); err != nil {
+		return kit.WrapError(err, "error sending task success")
+	}
+	return nil
+}
+
+// FailTask fails the task with taskToken with err's code and cause if it is a TaskError, and otherwise
+// with ErrorCodeHandlerFailed and err's message
+func FailTask(ctx context.Context, sfn StepFunctions, taskToken string, err error) error {
+	code, cause := ErrorCodeHandlerFailed, err.Error()
+	var taskErr *TaskError
+	if errors.As(err, &taskErr) {
+		code, cause = taskErr.Code, taskErr.Cause
+	}
+
+	if err := sfn.SendTaskFailure(ctx, taskToken, truncate(code, maxErrorCodeLength), truncate(cause, maxCauseLength)); err != nil {
+		return kit.WrapError(err, "error sending task failure")
+	}
+	return nil
+}
+
+// runHandler unmarshals task's input, calls handler, and marshals its output, turning a panic into a
+// TaskError
+func runHandler[TInput any, TOutput any](ctx context.Context, task Task, handler Handler[TInput, TOutput]) (_ string, err error) {
+	var input TInput
+	if err := json.Unmarshal([]byte(task.Input), &input); err != nil {
+		return "", &TaskError{Code: ErrorCodeInvalidInput, Cause: err.Error()}
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			err = &TaskError{Code: ErrorCodeHandlerPanicked, Cause: fmt.Sprintf("handler panicked: %v", r)}
+		}
+	}()
+
+	output, err := handler(ctx, input)
+	if err != nil {
+		return "", err
+	}
+
+	marshaled, err := json.Marshal(output)
+	if err != nil {
+		return "", &TaskError{Code: ErrorCodeInvalidOutput, Cause: err.Error()}
+	}
+	return string(marshaled), nil
+}
+
+// startHeartbeats sends a heartbeat for taskToken every interval until the returned function is
+// called, canceling the task's context if Step Functions reports that it timed out. Other heartbeat
+// errors are logged, as the next heartbeat may succeed.
+func startHeartbeats(ctx context.Context, sfn StepFunctions, taskToken string, interval time.Duration, cancel context.CancelCauseFunc) func() {
+	if interval <= 0 {
+		return func() {}
+	}
+
+	done := make(chan struct{})
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+
+			err := sfn.SendTaskHeartbeat(ctx, taskToken)
+			if errors.Is(err, ErrTaskTimedOut) {
+				logger.WarnContext(ctx, "step functions task timed out while running")
+				cancel(ErrTaskTimedOut)
+				return
+			}
+			if err != nil && ctx.Err() == nil {
+				logger.ErrorContext(ctx, "error sending step functions task heartbeat", "error", err)
+			}
+		}
+	}()
+
+	return func() {
+		close(done)
+		<-stopped
+	}
+}
+
+func truncate(s string, length int) string {
+	if len(s) <= length {
+		return s
+	}
+	return s[:length]
+}
@@ -0,0 +1,184 @@
+package stepfnkit
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type testInput struct {
+	Name string `json:"name"`
+}
+
+type testOutput struct {
+	Greeting string `json:"greeting"`
+}
+
+func greet(ctx context.Context, input testInput) (testOutput, error) {
+	return testOutput{Greeting: "hello " + input.Name}, nil
+}
+
+type sentResult struct {
+	token  string
+	output string
+	code   string
+	cause  string
+}
+
+func newRecordingStepFunctions(results *[]sentResult) *FakeStepFunctions {
+	var mu sync.Mutex
+	return &FakeStepFunctions{
+		SendTaskSuccessFake: func(ctx context.Context, taskToken string, output string) error {
+			mu.Lock()
+			defer mu.Unlock()
+			*results = append(*results, sentResult{token: taskToken, output: output})
+			return nil
+		},
+		SendTaskFailureFake: func(ctx context.Context, taskToken string, errorCode string, cause string) error {
+			mu.Lock()
+			defer mu.Unlock()
+			*results = append(*results, sentResult{token: taskToken, code: errorCode, cause: cause})
+			return nil
+		},
+		SendTaskHeartbeatFake: func(ctx context.Context, taskToken string) error { return nil },
+	}
+}
+
+func TestRunTask(t *testing.T) {
+	t.Run("sends_the_handler_output", func(t *testing.T) {
+		results := []sentResult{}
+
+		err := RunTask(context.Background(), newRecordingStepFunctions(&results), Task{Token: "theToken", Input: `{"name":"theName"}`}, greet)
+
+		assert.NoError(t, err)
+		assert.Equal(t, []sentResult{{token: "theToken", output: `{"greeting":"hello theName"}`}}, results)
+	})
+
+	t.Run("fails_the_task_with_the_code_of_a_task_error", func(t *testing.T) {
+		results := []sentResult{}
+
+		err := RunTask(context.Background(), newRecordingStepFunctions(&results), Task{Token: "theToken", Input: `{}`}, func(ctx context.Context, input testInput) (testOutput, error) {
+			return testOutput{}, &TaskError{Code: "TheCode", Cause: "theCause"}
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, []sentResult{{token: "theToken", code: "TheCode", cause: "theCause"}}, results)
+	})
+
+	t.Run("fails_the_task_with_handler_failed_for_other_errors", func(t *testing.T) {
+		results := []sentResult{}
+
+		err := RunTask(context.Background(), newRecordingStepFunctions(&results), Task{Token: "theToken", Input: `{}`}, func(ctx context.Context, input testInput) (testOutput, error) {
+			return testOutput{}, errors.New("the error")
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, []sentResult{{token: "theToken", code: ErrorCodeHandlerFailed, cause: "the error"}}, results)
+	})
+
+	t.Run("fails_the_task_for_invalid_input", func(t *testing.T) {
+		results := []sentResult{}
+
+		err := RunTask(context.Background(), newRecordingStepFunctions(&results), Task{Token: "theToken", Input: `not json`}, greet)
+
+		assert.NoError(t, err)
+		assert.Len(t, results, 1)
+		assert.Equal(t, ErrorCodeInvalidInput, results[0].code)
+	})
+
+	t.Run("fails_the_task_when_the_handler_panics", func(t *testing.T) {
+		results := []sentResult{}
+
+		err := RunTask(context.Background(), newRecordingStepFunctions(&results), Task{Token: "theToken", Input: `{}`}, func(ctx context.Context, input testInput) (testOutput, error) {
+			panic("the panic")
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, []sentResult{{token: "theToken", code: ErrorCodeHandlerPanicked, cause: "handler panicked: the panic"}}, results)
+	})
+
+	t.Run("sends_heartbeats_while_the_handler_runs", func(t *testing.T) {
+		results := []sentResult{}
+		sfn := newRecordingStepFunctions(&results)
+		heartbeats := make(chan string, 10)
+		sfn.SendTaskHeartbeatFake = func(ctx context.Context, taskToken string) error {
+			heartbeats <- taskToken
+			return nil
+		}
+
+		err := RunTask(context.Background(), sfn, Task{Token: "theToken", Input: `{"name":"theName"}`}, func(ctx context.Context, input testInput) (testOutput, error) {
+			<-heartbeats
+			<-heartbeats
+			return greet(ctx, input)
+		}, WithHeartbeatInterval(time.Millisecond))
+
+		assert.NoError(t, err)
+		assert.Len(t, results, 1)
+	})
+
+	t.Run("cancels_the_handler_when_a_heartbeat_reports_a_timeout", func(t *testing.T) {
+		results := []sentResult{}
+		sfn := newRecordingStepFunctions(&results)
+		sfn.SendTaskHeartbeatFake = func(ctx context.Context, taskToken string) error {
+			return ErrTaskTimedOut
+		}
+
+		err := RunTask(context.Background(), sfn, Task{Token: "theToken", Input: `{}`}, func(ctx context.Context, input testInput) (testOutput, error) {
+			<-ctx.Done()
+			return testOutput{}, ctx.Err()
+		}, WithHeartbeatInterval(time.Millisecond))
+
+		assert.ErrorIs(t, err, ErrTaskTimedOut)
+		assert.Empty(t, results)
+	})
+
+	t.Run("sends_the_result_when_the_context_is_canceled_after_the_handler_finishes", func(t *testing.T) {
+		results := []sentResult{}
+		ctx, cancel := context.WithCancel(context.Background())
+
+		err := RunTask(ctx, newRecordingStepFunctions(&results), Task{Token: "theToken", Input: `{"name":"theName"}`}, func(ctx context.Context, input testInput) (testOutput, error) {
+			cancel()
+			return greet(ctx, input)
+		})
+
+		assert.NoError(t, err)
+		assert.Len(t, results, 1)
+	})
+}
+
+func TestFailTask(t *testing.T) {
+	t.Run("truncates_the_cause_to_the_step_functions_limit", func(t *testing.T) {
+		results := []sentResult{}
+
+		err := FailTask(context.Background(), newRecordingStepFunctions(&results), "theToken", errors.New(strings.Repeat("a", maxCauseLength+1)))
+
+		assert.NoError(t, err)
+		assert.Len(t, results[0].cause, maxCauseLength)
+	})
+}
+
+func TestCompleteTask(t *testing.T) {
+	t.Run("sends_the_output_as_json", func(t *testing.T) {
+		results := []sentResult{}
+
+		err := CompleteTask(context.Background(), newRecordingStepFunctions(&results), "theToken", testOutput{Greeting: "theGreeting"})
+
+		assert.NoError(t, err)
+		assert.Equal(t, []sentResult{{token: "theToken", output: `{"greeting":"theGreeting"}`}}, results)
+	})
+
+	t.Run("returns_an_error_when_the_output_cannot_be_sent", func(t *testing.T) {
+		sfn := &FakeStepFunctions{SendTaskSuccessFake: func(ctx context.Context, taskToken string, output string) error {
+			return ErrTaskTimedOut
+		}}
+
+		err := CompleteTask(context.Background(), sfn, "theToken", testOutput{})
+
+		assert.ErrorIs(t, err, ErrTaskTimedOut)
+	})
+}
@@ -0,0 +1,158 @@
+package stepfnkit
+
+import (
+	"context"
+	"os"
+	"sync"
+	"time"
+)
+
+// WorkerOption configures a Worker or RunTask
+type WorkerOption func(*workerConfig)
+
+type workerConfig struct {
+	name              string
+	concurrency       int
+	heartbeatInterval time.Duration
+	pollErrorDelay    time.Duration
+	shutdownTimeout   time.Duration
+}
+
+// WithWorkerName sets the name the worker polls with, shown in the Step Functions console; the default
+// is the hostname
+func WithWorkerName(name string) WorkerOption {
+	return func(c *workerConfig) {
+		c.name = name
+	}
+}
+
+// WithConcurrency sets how many tasks the worker runs at once; the default is 1
+func WithConcurrency(concurrency int) WorkerOption {
+	return func(c *workerConfig) {
+		c.concurrency = max(concurrency, 1)
+	}
+}
+
+// WithHeartbeatInterval sets how often a heartbeat is sent while a task runs, which should be well
+// under the state's HeartbeatSeconds; the default is 30s and 0 turns heartbeats off
+func WithHeartbeatInterval(interval time.Duration) WorkerOption {
+	return func(c *workerConfig) {
+		c.heartbeatInterval = interval
+	}
+}
+
+// WithPollErrorDelay sets how long the worker waits after a failed poll before polling again; the
+// default is 5s
+func WithPollErrorDelay(delay time.Duration) WorkerOption {
+	return func(c *workerConfig) {
+		c.pollErrorDelay = delay
+	}
+}
+
+// WithShutdownTimeout sets how long Run waits for running tasks to finish after its context is
+// canceled before canceling their contexts too; the default of 0 waits for them to finish
+func WithShutdownTimeout(timeout time.Duration) WorkerOption {
+	return func(c *workerConfig) {
+		c.shutdownTimeout = timeout
+	}
+}
+
+func newWorkerConfig(options []WorkerOption) workerConfig {
+	config := workerConfig{
+		concurrency:       1,
+		heartbeatInterval: 30 * time.Second,
+		pollErrorDelay:    5 * time.Second,
+	}
+	for _, option := range options {
+		option(&config)
+	}
+	if config.name == "" {
+		config.name, _ = os.Hostname()
+	}
+	return config
+}
+
+// Worker polls a Step Functions activity for tasks and runs them with a handler
+type Worker[TInput any, TOutput any] struct {
+	sfn         StepFunctions
+	activityARN string
+	handler     Handler[TInput, TOutput]
+	config      workerConfig
+}
+
+// NewWorker returns a worker running tasks of the activity with activityARN with handler
+func NewWorker[TInput any, TOutput any](sfn StepFunctions, activityARN string, handler Handler[TInput, TOutput], options ...WorkerOption) *Worker[TInput, TOutput] {
+	return &Worker[TInput, TOutput]{
+		sfn:         sfn,
+		activityARN: activityARN,
+		handler:     handler,
+		config:      newWorkerConfig(options),
+	}
+}
+
+// Run polls for tasks and runs them, up to the worker's concurrency at a time, until ctx is canceled.
+// It then stops polling and waits for running tasks to finish and send their results, canceling them
+// after the shutdown timeout if one is set. Poll errors are logged and polling continues.
+//
+// Step Functions may assign a task to a poll that was canceled; that task times out and is retried
+// per the state's Retry rules, so give activity states a TimeoutSeconds or HeartbeatSeconds.
+func (w *Worker[TInput, TOutput]) Run(ctx context.Context) error {
+	tasksCtx, cancelTasks := context.WithCancel(context.WithoutCancel(ctx))
+	defer cancelTasks()
+
+	slots := make(chan struct{}, w.config.concurrency)
+	var running sync.WaitGroup
+
+	for {
+		select {
+		case <-ctx.Done():
+			w.shutdown(ctx, &running, cancelTasks)
+			return nil
+		case slots <- struct{}{}:
+		}
+
+		task, err := w.sfn.GetActivityTask(ctx, w.activityARN, w.config.name)
+		if err != nil || task == nil || task.Token == "" {
+			<-slots
+			if err != nil && ctx.Err() == nil {
+				logger.ErrorContext(ctx, "error polling for step functions activity task", "activity_arn", w.activityARN, "error", err)
+				select {
+				case <-ctx.Done():
+				case <-time.After(w.config.pollErrorDelay):
+				}
+			}
+			continue
+		}
+
+		running.Add(1)
+		go func() {
+			defer func() { <-slots }()
+			defer running.Done()
+			if err := RunTask(tasksCtx, w.sfn, *task, w.handler, WithHeartbeatInterval(w.config.heartbeatInterval)); err != nil {
+				logger.ErrorContext(tasksCtx, "error running step functions activity task", "activity_arn", w.activityARN, "error", err)
+			}
+		}()
+	}
+}
+
+// shutdown waits for running tasks, canceling them once the shutdown timeout passes
+func (w *Worker[TInput, TOutput]) shutdown(ctx context.Context, running *sync.WaitGroup, cancelTasks context.CancelFunc) {
+	finished := make(chan struct{})
+	go func() {
+		running.Wait()
+		close(finished)
+	}()
+
+	if w.config.shutdownTimeout <= 0 {
+		<-finished
+		return
+	}
+
+	select {
+	case <-finished:
+	case <-time.After(w.config.shutdownTimeout):
+		logger.WarnContext(ctx, "canceling step functions activity tasks still running at shutdown", "activity_arn", w.activityARN)
+		cancelTasks()
+		<-finished
+	}
+}
@@ -0,0 +1,116 @@
+package stepfnkit
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// newQueuedStepFunctions returns tasks from tasks, then blocks polls until ctx is canceled
+func newQueuedStepFunctions(tasks []*Task, results *[]sentResult) *FakeStepFunctions {
+	var mu sync.Mutex
+	sfn := newRecordingStepFunctions(results)
+	sfn.GetActivityTaskFake = func(ctx context.Context, activityARN string, workerName string) (*Task, error) {
+		mu.Lock()
+		if len(tasks) > 0 {
+			task := tasks[0]
+			tasks = tasks[1:]
+			mu.Unlock()
+			return task, nil
+		}
+		mu.Unlock()
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}
+	return sfn
+}
+
+func TestWorkerRun(t *testing.T) {
+	t.Run("runs_the_tasks_it_polls", func(t *testing.T) {
+		results := []sentResult{}
+		ctx, cancel := context.WithCancel(context.Background())
+		sfn := newQueuedStepFunctions([]*Task{
+			{Token: "aToken", Input: `{"name":"a"}`},
+			nil,
+			{Token: "anotherToken", Input: `{"name":"another"}`},
+		}, &results)
+		var ran sync.WaitGroup
+		ran.Add(2)
+		worker := NewWorker(sfn, "theActivityARN", func(ctx context.Context, input testInput) (testOutput, error) {
+			defer ran.Done()
+			return greet(ctx, input)
+		}, WithConcurrency(2), WithWorkerName("theWorker"))
+
+		done := make(chan error)
+		go func() { done <- worker.Run(ctx) }()
+		ran.Wait()
+		cancel()
+
+		assert.NoError(t, <-done)
+		assert.ElementsMatch(t, []sentResult{
+			{token: "aToken", output: `{"greeting":"hello a"}`},
+			{token: "anotherToken", output: `{"greeting":"hello another"}`},
+		}, results)
+	})
+
+	t.Run("polls_with_the_activity_and_worker_name", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		var actualARN, actualName string
+		sfn := &FakeStepFunctions{GetActivityTaskFake: func(ctx context.Context, activityARN string, workerName string) (*Task, error) {
+			actualARN, actualName = activityARN, workerName
+			cancel()
+			return nil, ctx.Err()
+		}}
+
+		err := NewWorker(sfn, "theActivityARN", greet, WithWorkerName("theWorker")).Run(ctx)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "theActivityARN", actualARN)
+		assert.Equal(t, "theWorker", actualName)
+	})
+
+	t.Run("waits_for_running_tasks_to_send_their_results_at_shutdown", func(t *testing.T) {
+		results := []sentResult{}
+		ctx, cancel := context.WithCancel(context.Background())
+		sfn := newQueuedStepFunctions([]*Task{{Token: "theToken", Input: `{"name":"theName"}`}}, &results)
+		started := make(chan struct{})
+		release := make(chan struct{})
+		worker := NewWorker(sfn, "theActivityARN", func(ctx context.Context, input testInput) (testOutput, error) {
+			close(started)
+			<-release
+			return greet(ctx, input)
+		})
+
+		done := make(chan error)
+		go func() { done <- worker.Run(ctx) }()
+		<-started
+		cancel()
+		close(release)
+
+		assert.NoError(t, <-done)
+		assert.Equal(t, []sentResult{{token: "theToken", output: `{"greeting":"hello theName"}`}}, results)
+	})
+
+	t.Run("cancels_running_tasks_after_the_shutdown_timeout", func(t *testing.T) {
+		results := []sentResult{}
+		ctx, cancel := context.WithCancel(context.Background())
+		sfn := newQueuedStepFunctions([]*Task{{Token: "theToken", Input: `{}`}}, &results)
+		started := make(chan struct{})
+		worker := NewWorker(sfn, "theActivityARN", func(ctx context.Context, input testInput) (testOutput, error) {
+			close(started)
+			<-ctx.Done()
+			return testOutput{}, errors.New("canceled at shutdown")
+		}, WithShutdownTimeout(1))
+
+		done := make(chan error)
+		go func() { done <- worker.Run(ctx) }()
+		<-started
+		cancel()
+
+		assert.NoError(t, <-done)
+		assert.Equal(t, []sentResult{{token: "theToken", code: ErrorCodeHandlerFailed, cause: "canceled at shutdown"}}, results)
+	})
+}
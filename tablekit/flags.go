@@ -0,0 +1,40 @@
+package tablekit
+
+import (
+	"io"
+
+	"github.com/spf13/pflag"
+)
+
+// Flags are the output flags shared by the CLIs: --output, --columns, and --no-color
+type Flags struct {
+	Output  string
+	Columns []string
+	NoColor bool
+}
+
+// Register adds the flags to flags, e.g. a root command's persistent flags
+func (f *Flags) Register(flags *pflag.FlagSet) {
+	flags.StringVarP(&f.Output, "output", "o", string(FormatTable), "Output format: table, wide, json, or yaml")
+	flags.StringSliceVar(&f.Columns, "columns", nil, "Table columns to write, in order (comma-separated)")
+	flags.BoolVar(&f.NoColor, "no-color", false, "Don't color table output")
+}
+
+// Format returns the format named by --output
+func (f *Flags) Format() (Format, error) {
+	return ParseFormat(f.Output)
+}
+
+// NewWriter returns a Writer writing to w with the format, columns, and color set by the flags
+func (f *Flags) NewWriter(w io.Writer) (*Writer, error) {
+	format, err := f.Format()
+	if err != nil {
+		return nil, err
+	}
+
+	options := []WriterOption{WithColumns(f.Columns...)}
+	if f.NoColor {
+		options = append(options, WithColor(false))
+	}
+	return NewWriter(w, format, options...), nil
+}
@@ -0,0 +1,45 @@
+package tablekit
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/spf13/pflag"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFlags(t *testing.T) {
+	t.Run("returns_a_writer_for_the_flags", func(t *testing.T) {
+		var flags Flags
+		flagSet := pflag.NewFlagSet("test", pflag.ContinueOnError)
+		flags.Register(flagSet)
+		var buf bytes.Buffer
+
+		err := flagSet.Parse([]string{"-o", "wide", "--columns", "name,id", "--no-color"})
+		assert.NoError(t, err)
+		w, err := flags.NewWriter(&buf)
+
+		assert.NoError(t, err)
+		assert.Equal(t, FormatWide, w.Format())
+		assert.NoError(t, WriteTable(w, testColumns, testRows[:1]))
+		assert.Equal(t, "NAME    ID\naName   1\n", buf.String())
+	})
+
+	t.Run("defaults_to_table", func(t *testing.T) {
+		var flags Flags
+		flags.Register(pflag.NewFlagSet("test", pflag.ContinueOnError))
+
+		format, err := flags.Format()
+
+		assert.NoError(t, err)
+		assert.Equal(t, FormatTable, format)
+	})
+
+	t.Run("returns_an_error_for_an_unknown_format", func(t *testing.T) {
+		flags := Flags{Output: "xml"}
+
+		_, err := flags.NewWriter(&bytes.Buffer{})
+
+		assert.EqualError(t, err, `invalid output format "xml" (use table, wide, json, or yaml)`)
+	})
+}
@@ -0,0 +1,267 @@
+// Package tablekit writes command output as an aligned table, a wide table with extra columns, JSON, or
+// YAML, so every CLI's --output, --columns, and --no-color flags behave the same.
+//
+//	columns := []tablekit.Column[Migration]{
+//		{Name: "version", Value: func(m Migration) string { return strconv.Itoa(m.Version) }},
+//		{Name: "filename", Value: func(m Migration) string { return m.Filename }, Wide: true},
+//	}
+//	w, err := flags.NewWriter(os.Stdout)
+//	...
+//	err = tablekit.WriteTable(w, columns, migrations)
+package tablekit
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/half-ogre/go-kit/kit"
+	"gopkg.in/yaml.v3"
+)
+
+// Format is an output format
+type Format string
+
+const (
+	FormatTable Format = "table"
+	FormatWide  Format = "wide"
+	FormatJSON  Format = "json"
+	FormatYAML  Format = "yaml"
+)
+
+// ParseFormat returns the format named s; "text" is accepted for table, the name the CLIs used first
+func ParseFormat(s string) (Format, error) {
+	switch Format(s) {
+	case FormatTable, FormatWide, FormatJSON, FormatYAML:
+		return Format(s), nil
+	case "text":
+		return FormatTable, nil
+	}
+	return "", fmt.Errorf("invalid output format %q (use table, wide, json, or yaml)", s)
+}
+
+// Structured reports whether the format is JSON or YAML, for output read by programs rather than people
+func (f Format) Structured() bool {
+	return f == FormatJSON || f == FormatYAML
+}
+
+// Color is an ANSI color for a table cell
+type Color string
+
+const (
+	ColorNone   Color = ""
+	ColorRed    Color = "31"
+	ColorGreen  Color = "32"
+	ColorYellow Color = "33"
+)
+
+// Column is a table column of rows of type T
+type Column[T any] struct {
+	// Name is the column's name for WithColumns and its header, upper-cased with underscores as spaces
+	Name string
+	// Value returns the column's cell for row
+	Value func(row T) string
+	// Color returns the color of the column's cell for row; nil is uncolored
+	Color func(row T) Color
+	// Wide columns are only written by the wide format, or when selected with WithColumns
+	Wide bool
+}
+
+// WriterOption configures a Writer
+type WriterOption func(*Writer)
+
+// WithColumns selects the table columns to write, in order, by name
+func WithColumns(names ...string) WriterOption {
+	return func(w *Writer) {
+		w.columns = names
+	}
+}
+
+// WithColor sets whether table cells are colored; the default is ColorEnabled
+func WithColor(enabled bool) WriterOption {
+	return func(w *Writer) {
+		w.color = enabled
+	}
+}
+
+// Writer writes output in a format
+type Writer struct {
+	w       io.Writer
+	format  Format
+	columns []string
+	color   bool
+}
+
+// NewWriter returns a Writer writing to w in format
+func NewWriter(w io.Writer, format Format, options ...WriterOption) *Writer {
+	writer := &Writer{
+		w:      w,
+		format: format,
+		color:  ColorEnabled(w),
+	}
+	for _, option := range options {
+		option(writer)
+	}
+	return writer
+}
+
+// Format returns the writer's format
+func (w *Writer) Format() Format {
+	return w.format
+}
+
+// ColorEnabled reports whether output to w should be colored: w must be a terminal, NO_COLOR
+// (https://no-color.org) must be unset, and TERM must not be dumb
+func ColorEnabled(w io.Writer) bool {
+	if os.Getenv("NO_COLOR") != "" || os.Getenv("TERM") == "dumb" {
+		return false
+	}
+	file, ok := w.(*os.File)
+	if !ok {
+		return false
+	}
+	info, err := file.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+// WriteValue writes v as indented JSON, or as YAML with the keys and order of its JSON, so struct json
+// tags name both. Table formats write JSON too, as there are no columns to write.
+func (w *Writer) WriteValue(v any) error {
+	if w.format != FormatYAML {
+		encoder := json.NewEncoder(w.w)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(v); err != nil {
+			return kit.WrapError(err, "error writing JSON output")
+		}
+		return nil
+	}
+
+	data, err := json.Marshal(v)
+	if err != nil {
+		return kit.WrapError(err, "error writing YAML output")
+	}
+	// JSON is YAML, so decoding it to a node keeps its keys in order
+	var node yaml.Node
+	if err := yaml.Unmarshal(data, &node); err != nil {
+		return kit.WrapError(err, "error writing YAML output")
+	}
+	clearStyle(&node)
+
+	encoder := yaml.NewEncoder(w.w)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(&node); err != nil {
+		return kit.WrapError(err, "error writing YAML output")
+	}
+	if err := encoder.Close(); err != nil {
+		return kit.WrapError(err, "error writing YAML output")
+	}
+	return nil
+}
+
+// clearStyle drops the flow style and quoting of decoded JSON, so it's encoded as block YAML
+func clearStyle(node *yaml.Node) {
+	node.Style = 0
+	for _, child := range node.Content {
+		clearStyle(child)
+	}
+}
+
+// WriteTable writes rows as a table of columns, or with WriteValue in a structured format
+func WriteTable[T any](w *Writer, columns []Column[T], rows []T) error {
+	if w.format.Structured() {
+		if rows == nil {
+			rows = []T{}
+		}
+		return w.WriteValue(rows)
+	}
+
+	selected, err := selectColumns(columns, w.columns, w.format == FormatWide)
+	if err != nil {
+		return err
+	}
+
+	cells := make([][]string, 0, len(rows)+1)
+	colors := make([][]Color, 0, len(rows)+1)
+	header := make([]string, len(selected))
+	for i, column := range selected {
+		header[i] = strings.ToUpper(strings.ReplaceAll(column.Name, "_", " "))
+	}
+	cells = append(cells, header)
+	colors = append(colors, make([]Color, len(selected)))
+	for _, row := range rows {
+		rowCells := make([]string, len(selected))
+		rowColors := make([]Color, len(selected))
+		for i, column := range selected {
+			rowCells[i] = column.Value(row)
+			if column.Color != nil && w.color {
+				rowColors[i] = column.Color(row)
+			}
+		}
+		cells = append(cells, rowCells)
+		colors = append(colors, rowColors)
+	}
+
+	widths := make([]int, len(selected))
+	for _, rowCells := range cells {
+		for i, cell := range rowCells {
+			widths[i] = max(widths[i], utf8.RuneCountInString(cell))
+		}
+	}
+
+	var buf bytes.Buffer
+	for r, rowCells := range cells {
+		for i, cell := range rowCells {
+			if colors[r][i] != ColorNone {
+				fmt.Fprintf(&buf, "\x1b[%sm%s\x1b[0m", colors[r][i], cell)
+			} else {
+				buf.WriteString(cell)
+			}
+			// The last column isn't padded, so lines have no trailing spaces
+			if i < len(rowCells)-1 {
+				buf.WriteString(strings.Repeat(" ", widths[i]-utf8.RuneCountInString(cell)+3))
+			}
+		}
+		buf.WriteByte('\n')
+	}
+
+	if _, err := w.w.Write(buf.Bytes()); err != nil {
+		return kit.WrapError(err, "error writing table output")
+	}
+	return nil
+}
+
+func selectColumns[T any](columns []Column[T], names []string, wide bool) ([]Column[T], error) {
+	if len(names) == 0 {
+		selected := make([]Column[T], 0, len(columns))
+		for _, column := range columns {
+			if wide || !column.Wide {
+				selected = append(selected, column)
+			}
+		}
+		return selected, nil
+	}
+
+	selected := make([]Column[T], 0, len(names))
+	for _, name := range names {
+		found := false
+		for _, column := range columns {
+			if strings.EqualFold(column.Name, name) {
+				selected = append(selected, column)
+				found = true
+				break
+			}
+		}
+		if !found {
+			available := make([]string, len(columns))
+			for i, column := range columns {
+				available[i] = column.Name
+			}
+			return nil, fmt.Errorf("unknown column %q (use %s)", name, strings.Join(available, ", "))
+		}
+	}
+	return selected, nil
+}
@@ -0,0 +1,178 @@
+package tablekit
+
+import (
+	"bytes"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type testRow struct {
+	ID      int    `json:"id"`
+	Name    string `json:"name"`
+	Enabled bool   `json:"enabled"`
+}
+
+var testColumns = []Column[testRow]{
+	{Name: "id", Value: func(r testRow) string { return strconv.Itoa(r.ID) }},
+	{Name: "name", Value: func(r testRow) string { return r.Name }},
+	{
+		Name:  "enabled",
+		Value: func(r testRow) string { return strconv.FormatBool(r.Enabled) },
+		Color: func(r testRow) Color {
+			if r.Enabled {
+				return ColorGreen
+			}
+			return ColorNone
+		},
+		Wide: true,
+	},
+}
+
+var testRows = []testRow{{ID: 1, Name: "aName", Enabled: true}, {ID: 22, Name: "anotherName"}}
+
+func TestParseFormat(t *testing.T) {
+	t.Run("returns_the_format", func(t *testing.T) {
+		for _, format := range []Format{FormatTable, FormatWide, FormatJSON, FormatYAML} {
+			actual, err := ParseFormat(string(format))
+
+			assert.NoError(t, err)
+			assert.Equal(t, format, actual)
+		}
+	})
+
+	t.Run("accepts_text_for_table", func(t *testing.T) {
+		format, err := ParseFormat("text")
+
+		assert.NoError(t, err)
+		assert.Equal(t, FormatTable, format)
+	})
+
+	t.Run("returns_an_error_for_an_unknown_format", func(t *testing.T) {
+		_, err := ParseFormat("xml")
+
+		assert.EqualError(t, err, `invalid output format "xml" (use table, wide, json, or yaml)`)
+	})
+}
+
+func TestFormatStructured(t *testing.T) {
+	t.Run("is_true_for_json_and_yaml", func(t *testing.T) {
+		assert.True(t, FormatJSON.Structured())
+		assert.True(t, FormatYAML.Structured())
+		assert.False(t, FormatTable.Structured())
+		assert.False(t, FormatWide.Structured())
+	})
+}
+
+func TestWriteTable(t *testing.T) {
+	t.Run("writes_aligned_columns_without_wide_columns", func(t *testing.T) {
+		var buf bytes.Buffer
+
+		err := WriteTable(NewWriter(&buf, FormatTable), testColumns, testRows)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "ID   NAME\n1    aName\n22   anotherName\n", buf.String())
+	})
+
+	t.Run("writes_wide_columns_in_the_wide_format", func(t *testing.T) {
+		var buf bytes.Buffer
+
+		err := WriteTable(NewWriter(&buf, FormatWide), testColumns, testRows)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "ID   NAME          ENABLED\n1    aName         true\n22   anotherName   false\n", buf.String())
+	})
+
+	t.Run("writes_the_selected_columns_in_order", func(t *testing.T) {
+		var buf bytes.Buffer
+
+		err := WriteTable(NewWriter(&buf, FormatTable, WithColumns("enabled", "ID")), testColumns, testRows)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "ENABLED   ID\ntrue      1\nfalse     22\n", buf.String())
+	})
+
+	t.Run("returns_an_error_for_an_unknown_column", func(t *testing.T) {
+		err := WriteTable(NewWriter(&bytes.Buffer{}, FormatTable, WithColumns("size")), testColumns, testRows)
+
+		assert.EqualError(t, err, `unknown column "size" (use id, name, enabled)`)
+	})
+
+	t.Run("colors_cells_when_color_is_enabled", func(t *testing.T) {
+		var buf bytes.Buffer
+
+		err := WriteTable(NewWriter(&buf, FormatTable, WithColumns("enabled"), WithColor(true)), testColumns, testRows)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "ENABLED\n\x1b[32mtrue\x1b[0m\nfalse\n", buf.String())
+	})
+
+	t.Run("pads_by_characters_not_bytes", func(t *testing.T) {
+		var buf bytes.Buffer
+
+		err := WriteTable(NewWriter(&buf, FormatTable), testColumns, []testRow{{ID: 1, Name: "✓"}})
+
+		assert.NoError(t, err)
+		assert.Equal(t, "ID   NAME\n1    ✓\n", buf.String())
+	})
+
+	t.Run("writes_the_rows_as_json", func(t *testing.T) {
+		var buf bytes.Buffer
+
+		err := WriteTable(NewWriter(&buf, FormatJSON), testColumns, testRows)
+
+		assert.NoError(t, err)
+		assert.JSONEq(t, `[{"id": 1, "name": "aName", "enabled": true}, {"id": 22, "name": "anotherName", "enabled": false}]`, buf.String())
+	})
+
+	t.Run("writes_no_rows_as_an_empty_json_array", func(t *testing.T) {
+		var buf bytes.Buffer
+
+		err := WriteTable(NewWriter(&buf, FormatJSON), testColumns, nil)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "[]\n", buf.String())
+	})
+}
+
+func TestWriteValue(t *testing.T) {
+	t.Run("writes_indented_json", func(t *testing.T) {
+		var buf bytes.Buffer
+
+		err := NewWriter(&buf, FormatJSON).WriteValue(testRows[0])
+
+		assert.NoError(t, err)
+		assert.Equal(t, "{\n  \"id\": 1,\n  \"name\": \"aName\",\n  \"enabled\": true\n}\n", buf.String())
+	})
+
+	t.Run("writes_yaml_with_json_keys_in_order", func(t *testing.T) {
+		var buf bytes.Buffer
+
+		err := NewWriter(&buf, FormatYAML).WriteValue(map[string]any{"rows": testRows})
+
+		assert.NoError(t, err)
+		assert.Equal(t, "rows:\n  - id: 1\n    name: aName\n    enabled: true\n  - id: 22\n    name: anotherName\n    enabled: false\n", buf.String())
+	})
+
+	t.Run("quotes_yaml_strings_that_would_read_as_other_types", func(t *testing.T) {
+		var buf bytes.Buffer
+
+		err := NewWriter(&buf, FormatYAML).WriteValue(testRow{ID: 1, Name: "true"})
+
+		assert.NoError(t, err)
+		assert.Equal(t, "id: 1\nname: \"true\"\nenabled: false\n", buf.String())
+	})
+}
+
+func TestColorEnabled(t *testing.T) {
+	t.Run("is_false_for_a_writer_that_is_not_a_terminal", func(t *testing.T) {
+		assert.False(t, ColorEnabled(&bytes.Buffer{}))
+	})
+
+	t.Run("is_false_when_no_color_is_set", func(t *testing.T) {
+		t.Setenv("NO_COLOR", "1")
+
+		assert.False(t, ColorEnabled(&bytes.Buffer{}))
+	})
+}
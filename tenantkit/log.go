@@ -0,0 +1,42 @@
+package tenantkit
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/half-ogre/go-kit/logkit"
+	"github.com/half-ogre/go-kit/logkit/logfields"
+)
+
+var logger = logkit.Logger("tenantkit")
+
+// LogHandler is a slog.Handler that adds the tenant_id of the record's context, if it has a tenant, to
+// every record, so logs can be filtered by tenant without each call site adding it
+type LogHandler struct {
+	next slog.Handler
+}
+
+// NewLogHandler creates a LogHandler wrapping next
+func NewLogHandler(next slog.Handler) *LogHandler {
+	return &LogHandler{next: next}
+}
+
+func (h *LogHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.next.Enabled(ctx, level)
+}
+
+func (h *LogHandler) Handle(ctx context.Context, record slog.Record) error {
+	if tenant, ok := FromContext(ctx); ok {
+		record = record.Clone()
+		record.AddAttrs(logfields.Tenant(tenant.ID))
+	}
+	return h.next.Handle(ctx, record)
+}
+
+func (h *LogHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &LogHandler{next: h.next.WithAttrs(attrs)}
+}
+
+func (h *LogHandler) WithGroup(name string) slog.Handler {
+	return &LogHandler{next: h.next.WithGroup(name)}
+}
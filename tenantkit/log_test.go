@@ -0,0 +1,31 @@
+package tenantkit
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLogHandler(t *testing.T) {
+	t.Run("adds_the_tenant_of_the_context", func(t *testing.T) {
+		buf := &bytes.Buffer{}
+		log := slog.New(NewLogHandler(slog.NewTextHandler(buf, nil))).With("theKey", "theValue")
+		ctx, _ := WithTenant(context.Background(), Tenant{ID: "the-tenant"})
+
+		log.InfoContext(ctx, "the message")
+
+		assert.Contains(t, buf.String(), "theKey=theValue tenant_id=the-tenant")
+	})
+
+	t.Run("adds_nothing_without_a_tenant", func(t *testing.T) {
+		buf := &bytes.Buffer{}
+		log := slog.New(NewLogHandler(slog.NewTextHandler(buf, nil)))
+
+		log.InfoContext(context.Background(), "the message")
+
+		assert.NotContains(t, buf.String(), "tenant_id")
+	})
+}
@@ -0,0 +1,115 @@
+package tenantkit
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"strings"
+
+	"github.com/half-ogre/go-kit/kit"
+)
+
+// ErrTenantNotResolved is returned by a Resolver that can't find a tenant in a request
+var ErrTenantNotResolved = errors.New("tenant not resolved")
+
+// Resolver finds the tenant an HTTP request is for
+type Resolver interface {
+	Resolve(r *http.Request) (Tenant, error)
+}
+
+// ResolverFunc adapts a function to the Resolver interface
+type ResolverFunc func(r *http.Request) (Tenant, error)
+
+func (f ResolverFunc) Resolve(r *http.Request) (Tenant, error) {
+	return f(r)
+}
+
+// HeaderResolver reads the tenant ID from a header, e.g. X-Tenant-ID. Only use it behind something that
+// sets or checks the header, as callers can send any value.
+func HeaderResolver(header string) Resolver {
+	return ResolverFunc(func(r *http.Request) (Tenant, error) {
+		id := r.Header.Get(header)
+		if id == "" {
+			return Tenant{}, ErrTenantNotResolved
+		}
+		return Tenant{ID: id}, nil
+	})
+}
+
+// SubdomainResolver reads the tenant ID from the first label of a host under baseDomain, e.g. "acme"
+// for acme.example.com with a baseDomain of "example.com"
+func SubdomainResolver(baseDomain string) Resolver {
+	suffix := "." + strings.ToLower(strings.TrimPrefix(baseDomain, "."))
+	return ResolverFunc(func(r *http.Request) (Tenant, error) {
+		host := strings.ToLower(r.Host)
+		if h, _, err := net.SplitHostPort(host); err == nil {
+			host = h
+		}
+
+		id, found := strings.CutSuffix(host, suffix)
+		if !found || id == "" || strings.Contains(id, ".") {
+			return Tenant{}, ErrTenantNotResolved
+		}
+		return Tenant{ID: id}, nil
+	})
+}
+
+// FirstResolver tries each resolver in turn and returns the first tenant found. A resolver's error other
+// than ErrTenantNotResolved stops the search.
+func FirstResolver(resolvers ...Resolver) Resolver {
+	return ResolverFunc(func(r *http.Request) (Tenant, error) {
+		for _, resolver := range resolvers {
+			tenant, err := resolver.Resolve(r)
+			if errors.Is(err, ErrTenantNotResolved) {
+				continue
+			}
+			return tenant, err
+		}
+		return Tenant{}, ErrTenantNotResolved
+	})
+}
+
+// LookupResolver resolves with resolver and then replaces the tenant with the one lookup returns for
+// its ID, e.g. from a tenants table, so unknown tenants are rejected and the Name and Attributes are
+// filled in. lookup returns ErrTenantNotResolved for an unknown tenant.
+func LookupResolver(resolver Resolver, lookup func(ctx context.Context, id string) (Tenant, error)) Resolver {
+	return ResolverFunc(func(r *http.Request) (Tenant, error) {
+		tenant, err := resolver.Resolve(r)
+		if err != nil {
+			return Tenant{}, err
+		}
+		if err := tenant.Validate(); err != nil {
+			return Tenant{}, kit.WrapError(ErrTenantNotResolved, "%s", err)
+		}
+		return lookup(r.Context(), tenant.ID)
+	})
+}
+
+// Middleware returns net/http middleware that resolves each request's tenant and adds it to the
+// request's context. Requests without a valid tenant get 404 Not Found, so they can't tell which
+// tenants exist; other resolver errors get 500. Use echo.WrapMiddleware to use it with echo.
+func Middleware(resolver Resolver) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			tenant, err := resolver.Resolve(r)
+			if err != nil && !errors.Is(err, ErrTenantNotResolved) {
+				logger.ErrorContext(r.Context(), "error resolving tenant", "error", err)
+				http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+				return
+			}
+
+			var ctx context.Context
+			if err == nil {
+				ctx, err = WithTenant(r.Context(), tenant)
+			}
+			if err != nil {
+				logger.DebugContext(r.Context(), "request has no tenant", "error", err)
+				http.NotFound(w, r)
+				return
+			}
+
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
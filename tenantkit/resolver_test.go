@@ -0,0 +1,160 @@
+package tenantkit
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHeaderResolver(t *testing.T) {
+	t.Run("resolves_the_tenant_from_the_header", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set("X-Tenant-ID", "the-tenant")
+
+		tenant, err := HeaderResolver("X-Tenant-ID").Resolve(req)
+
+		assert.NoError(t, err)
+		assert.Equal(t, Tenant{ID: "the-tenant"}, tenant)
+	})
+
+	t.Run("returns_err_tenant_not_resolved_without_the_header", func(t *testing.T) {
+		_, err := HeaderResolver("X-Tenant-ID").Resolve(httptest.NewRequest(http.MethodGet, "/", nil))
+
+		assert.ErrorIs(t, err, ErrTenantNotResolved)
+	})
+}
+
+func TestSubdomainResolver(t *testing.T) {
+	t.Run("resolves_the_tenant_from_the_subdomain", func(t *testing.T) {
+		for _, host := range []string{"the-tenant.example.com", "THE-TENANT.example.com:8080"} {
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.Host = host
+
+			tenant, err := SubdomainResolver("example.com").Resolve(req)
+
+			assert.NoError(t, err, host)
+			assert.Equal(t, Tenant{ID: "the-tenant"}, tenant, host)
+		}
+	})
+
+	t.Run("returns_err_tenant_not_resolved_for_other_hosts", func(t *testing.T) {
+		for _, host := range []string{"example.com", "the-tenant.example.org", "a.the-tenant.example.com", "theexample.com"} {
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.Host = host
+
+			_, err := SubdomainResolver("example.com").Resolve(req)
+
+			assert.ErrorIs(t, err, ErrTenantNotResolved, host)
+		}
+	})
+}
+
+func TestFirstResolver(t *testing.T) {
+	t.Run("returns_the_first_tenant_resolved", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set("X-Tenant-ID", "the-tenant")
+
+		tenant, err := FirstResolver(HeaderResolver("X-Other"), HeaderResolver("X-Tenant-ID")).Resolve(req)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "the-tenant", tenant.ID)
+	})
+
+	t.Run("stops_at_an_error", func(t *testing.T) {
+		failing := ResolverFunc(func(r *http.Request) (Tenant, error) { return Tenant{}, assert.AnError })
+
+		_, err := FirstResolver(failing, HeaderResolver("X-Tenant-ID")).Resolve(httptest.NewRequest(http.MethodGet, "/", nil))
+
+		assert.ErrorIs(t, err, assert.AnError)
+	})
+
+	t.Run("returns_err_tenant_not_resolved_when_none_resolve", func(t *testing.T) {
+		_, err := FirstResolver(HeaderResolver("X-Tenant-ID")).Resolve(httptest.NewRequest(http.MethodGet, "/", nil))
+
+		assert.ErrorIs(t, err, ErrTenantNotResolved)
+	})
+}
+
+func TestLookupResolver(t *testing.T) {
+	lookup := func(ctx context.Context, id string) (Tenant, error) {
+		if id != "the-tenant" {
+			return Tenant{}, ErrTenantNotResolved
+		}
+		return Tenant{ID: id, Name: "The Tenant"}, nil
+	}
+
+	t.Run("returns_the_tenant_looked_up", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set("X-Tenant-ID", "the-tenant")
+
+		tenant, err := LookupResolver(HeaderResolver("X-Tenant-ID"), lookup).Resolve(req)
+
+		assert.NoError(t, err)
+		assert.Equal(t, Tenant{ID: "the-tenant", Name: "The Tenant"}, tenant)
+	})
+
+	t.Run("does_not_look_up_an_invalid_id", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set("X-Tenant-ID", "The Tenant")
+		lookedUp := false
+
+		_, err := LookupResolver(HeaderResolver("X-Tenant-ID"), func(ctx context.Context, id string) (Tenant, error) {
+			lookedUp = true
+			return Tenant{ID: id}, nil
+		}).Resolve(req)
+
+		assert.ErrorIs(t, err, ErrTenantNotResolved)
+		assert.False(t, lookedUp)
+	})
+}
+
+func TestMiddleware(t *testing.T) {
+	newHandler := func(resolver Resolver, tenants *[]Tenant) http.Handler {
+		return Middleware(resolver)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			tenant, _ := FromContext(r.Context())
+			*tenants = append(*tenants, tenant)
+			w.WriteHeader(http.StatusNoContent)
+		}))
+	}
+
+	t.Run("adds_the_tenant_to_the_request_context", func(t *testing.T) {
+		tenants := []Tenant{}
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set("X-Tenant-ID", "the-tenant")
+		w := httptest.NewRecorder()
+
+		newHandler(HeaderResolver("X-Tenant-ID"), &tenants).ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusNoContent, w.Code)
+		assert.Equal(t, []Tenant{{ID: "the-tenant"}}, tenants)
+	})
+
+	t.Run("returns_not_found_without_a_valid_tenant", func(t *testing.T) {
+		for _, id := range []string{"", "The Tenant"} {
+			tenants := []Tenant{}
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.Header.Set("X-Tenant-ID", id)
+			w := httptest.NewRecorder()
+
+			newHandler(HeaderResolver("X-Tenant-ID"), &tenants).ServeHTTP(w, req)
+
+			assert.Equal(t, http.StatusNotFound, w.Code, id)
+			assert.Empty(t, tenants)
+		}
+	})
+
+	t.Run("returns_an_internal_server_error_when_the_resolver_fails", func(t *testing.T) {
+		tenants := []Tenant{}
+		w := httptest.NewRecorder()
+
+		newHandler(ResolverFunc(func(r *http.Request) (Tenant, error) {
+			return Tenant{}, assert.AnError
+		}), &tenants).ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+		assert.Empty(t, tenants)
+	})
+}
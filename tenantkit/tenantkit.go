@@ -0,0 +1,100 @@
+// Package tenantkit carries the tenant a request is for in its context, so multi-tenancy is handled
+// once: resolvers find the tenant of an HTTP request, Middleware puts it in the request's context, and
+// the rest of the kit scopes to it, e.g. dynamodbkit.WithTenant for table names, pgkit.WithContextTenant
+// for row level security, Key for cache keys, and LogHandler for a tenant_id on every log record.
+package tenantkit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// ErrNoTenant is returned when a context doesn't carry a tenant
+var ErrNoTenant = errors.New("no tenant in context")
+
+// MaxIDLength is the longest tenant ID, short enough to end a DynamoDB table or Postgres schema name
+const MaxIDLength = 48
+
+var idPattern = regexp.MustCompile(`^[a-z0-9][a-z0-9-]*$`)
+
+// Tenant is who a request is for
+type Tenant struct {
+	// ID is lowercase letters, digits, and hyphens, so it is safe in table, schema, and key names
+	ID         string
+	Name       string
+	Attributes map[string]string
+}
+
+// Validate returns an error if the tenant's ID isn't valid
+func (t Tenant) Validate() error {
+	return ValidateID(t.ID)
+}
+
+// ValidateID returns an error unless id is 1 to MaxIDLength lowercase letters, digits, and hyphens,
+// starting with a letter or digit
+func ValidateID(id string) error {
+	if id == "" {
+		return errors.New("tenant ID cannot be empty")
+	}
+	if len(id) > MaxIDLength {
+		return fmt.Errorf("tenant ID %q is longer than %d characters", id, MaxIDLength)
+	}
+	if !idPattern.MatchString(id) {
+		return fmt.Errorf("tenant ID %q must be lowercase letters, digits, and hyphens", id)
+	}
+	return nil
+}
+
+// TableSuffix is the suffix of the tenant's tables, e.g. "-acme" for users-acme
+func (t Tenant) TableSuffix() string {
+	return "-" + t.ID
+}
+
+// SchemaName is the name of the tenant's Postgres schema, e.g. "tenant_acme"; hyphens become
+// underscores so the name doesn't need quoting
+func (t Tenant) SchemaName() string {
+	return "tenant_" + strings.ReplaceAll(t.ID, "-", "_")
+}
+
+// KeyPrefix is the prefix of the tenant's cache and storage keys, e.g. "acme:"
+func (t Tenant) KeyPrefix() string {
+	return t.ID + ":"
+}
+
+type tenantKey struct{}
+
+// WithTenant returns a copy of ctx carrying tenant, or an error if tenant isn't valid
+func WithTenant(ctx context.Context, tenant Tenant) (context.Context, error) {
+	if err := tenant.Validate(); err != nil {
+		return nil, err
+	}
+	return context.WithValue(ctx, tenantKey{}, tenant), nil
+}
+
+// FromContext returns the tenant carried by ctx, and false if there isn't one
+func FromContext(ctx context.Context) (Tenant, bool) {
+	tenant, ok := ctx.Value(tenantKey{}).(Tenant)
+	return tenant, ok
+}
+
+// Require returns the tenant carried by ctx, or ErrNoTenant
+func Require(ctx context.Context) (Tenant, error) {
+	tenant, ok := FromContext(ctx)
+	if !ok {
+		return Tenant{}, ErrNoTenant
+	}
+	return tenant, nil
+}
+
+// Key returns key with the prefix of ctx's tenant, e.g. "acme:users:123", so tenants sharing a cache
+// can't read each other's entries
+func Key(ctx context.Context, key string) (string, error) {
+	tenant, err := Require(ctx)
+	if err != nil {
+		return "", err
+	}
+	return tenant.KeyPrefix() + key, nil
+}
@@ -0,0 +1,94 @@
+package tenantkit
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateID(t *testing.T) {
+	t.Run("accepts_lowercase_letters_digits_and_hyphens", func(t *testing.T) {
+		for _, id := range []string{"acme", "acme-2", "0day", strings.Repeat("a", MaxIDLength)} {
+			assert.NoError(t, ValidateID(id), id)
+		}
+	})
+
+	t.Run("returns_an_error_for_an_invalid_id", func(t *testing.T) {
+		for id, expected := range map[string]string{
+			"":                                 "tenant ID cannot be empty",
+			"Acme":                             "tenant ID \"Acme\" must be lowercase letters, digits, and hyphens",
+			"-acme":                            "tenant ID \"-acme\" must be lowercase letters, digits, and hyphens",
+			"acme:1":                           "tenant ID \"acme:1\" must be lowercase letters, digits, and hyphens",
+			"acme_1":                           "tenant ID \"acme_1\" must be lowercase letters, digits, and hyphens",
+			strings.Repeat("a", MaxIDLength+1): "tenant ID \"" + strings.Repeat("a", MaxIDLength+1) + "\" is longer than 48 characters",
+		} {
+			assert.EqualError(t, ValidateID(id), expected)
+		}
+	})
+}
+
+func TestTenantNames(t *testing.T) {
+	t.Run("scope_names_to_the_tenant", func(t *testing.T) {
+		tenant := Tenant{ID: "the-tenant"}
+
+		assert.Equal(t, "-the-tenant", tenant.TableSuffix())
+		assert.Equal(t, "tenant_the_tenant", tenant.SchemaName())
+		assert.Equal(t, "the-tenant:", tenant.KeyPrefix())
+	})
+}
+
+func TestWithTenant(t *testing.T) {
+	t.Run("returns_a_context_carrying_the_tenant", func(t *testing.T) {
+		theTenant := Tenant{ID: "the-tenant", Name: "The Tenant"}
+
+		ctx, err := WithTenant(context.Background(), theTenant)
+
+		assert.NoError(t, err)
+		actual, ok := FromContext(ctx)
+		assert.True(t, ok)
+		assert.Equal(t, theTenant, actual)
+	})
+
+	t.Run("returns_an_error_for_an_invalid_tenant", func(t *testing.T) {
+		ctx, err := WithTenant(context.Background(), Tenant{ID: "The Tenant"})
+
+		assert.Error(t, err)
+		assert.Nil(t, ctx)
+	})
+}
+
+func TestRequire(t *testing.T) {
+	t.Run("returns_the_tenant_carried_by_the_context", func(t *testing.T) {
+		ctx, _ := WithTenant(context.Background(), Tenant{ID: "the-tenant"})
+
+		tenant, err := Require(ctx)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "the-tenant", tenant.ID)
+	})
+
+	t.Run("returns_err_no_tenant_without_a_tenant", func(t *testing.T) {
+		_, err := Require(context.Background())
+
+		assert.ErrorIs(t, err, ErrNoTenant)
+	})
+}
+
+func TestKey(t *testing.T) {
+	t.Run("prefixes_the_key_with_the_tenant", func(t *testing.T) {
+		ctx, _ := WithTenant(context.Background(), Tenant{ID: "the-tenant"})
+
+		key, err := Key(ctx, "users:theID")
+
+		assert.NoError(t, err)
+		assert.Equal(t, "the-tenant:users:theID", key)
+	})
+
+	t.Run("returns_err_no_tenant_without_a_tenant", func(t *testing.T) {
+		_, err := Key(context.Background(), "users:theID")
+
+		assert.ErrorIs(t, err, ErrNoTenant)
+	})
+}
@@ -0,0 +1,39 @@
+package validatekit
+
+import (
+	"github.com/go-playground/validator/v10"
+
+	"github.com/half-ogre/go-kit/kit"
+)
+
+// tags are the validators Register adds to a go-playground validator; email, url, uuid, and e164 are
+// left to its built in tags
+var tags = map[string]Rule{
+	"ulid":    ULID,
+	"slug":    Slug,
+	"isodate": ISODate,
+}
+
+// Register adds the ulid, slug, and isodate tags to v, e.g. `validate:"required,slug"`
+func Register(v *validator.Validate) error {
+	for tag, rule := range tags {
+		if err := RegisterRule(v, tag, rule); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// RegisterRule adds a tag to v that string fields breaking rule fail, e.g. a domain rule built with
+// Chain. As with the built in tags, an empty field fails too unless the tag follows omitempty. The
+// rule's rewritten value is ignored; use Sanitize to rewrite fields.
+func RegisterRule(v *validator.Validate, tag string, rule Rule) error {
+	err := v.RegisterValidation(tag, func(fl validator.FieldLevel) bool {
+		_, err := Apply(fl.Field().String(), Required, rule)
+		return err == nil
+	})
+	if err != nil {
+		return kit.WrapError(err, "error registering %s validation", tag)
+	}
+	return nil
+}
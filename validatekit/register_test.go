@@ -0,0 +1,47 @@
+package validatekit
+
+import (
+	"testing"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRegister(t *testing.T) {
+	type Post struct {
+		ID        string `validate:"required,ulid"`
+		Slug      string `validate:"required,slug"`
+		Published string `validate:"omitempty,isodate"`
+	}
+	v := validator.New()
+
+	err := Register(v)
+
+	assert.NoError(t, err)
+	assert.NoError(t, v.Struct(Post{ID: "01ARZ3NDEKTSV4RRFFQ69G5FAV", Slug: "the-post", Published: "2024-01-02"}))
+	assert.Error(t, v.Struct(Post{ID: "not-a-ulid", Slug: "the-post"}))
+	assert.Error(t, v.Struct(Post{ID: "01ARZ3NDEKTSV4RRFFQ69G5FAV", Slug: "The Post"}))
+	assert.Error(t, v.Struct(Post{ID: "01ARZ3NDEKTSV4RRFFQ69G5FAV", Slug: "the-post", Published: "01/02/2024"}))
+}
+
+func TestRegisterRule(t *testing.T) {
+	t.Run("adds_a_tag_for_the_rule", func(t *testing.T) {
+		type Account struct {
+			Handle string `validate:"handle"`
+		}
+		v := validator.New()
+
+		err := RegisterRule(v, "handle", Chain(MinLength(3), Slug))
+
+		assert.NoError(t, err)
+		assert.NoError(t, v.Struct(Account{Handle: "ada"}))
+		assert.Error(t, v.Struct(Account{Handle: "ad"}))
+		assert.Error(t, v.Struct(Account{Handle: ""}))
+	})
+
+	t.Run("returns_an_error_for_an_empty_tag", func(t *testing.T) {
+		err := RegisterRule(validator.New(), "", Slug)
+
+		assert.ErrorContains(t, err, "error registering  validation")
+	})
+}
@@ -0,0 +1,120 @@
+package validatekit
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// Sanitizer returns a rule that rewrites a value with sanitize and never fails
+func Sanitizer(sanitize func(value string) string) Rule {
+	return func(value string) (string, error) {
+		return sanitize(value), nil
+	}
+}
+
+// The sanitizers
+var (
+	// Trim removes leading and trailing white space
+	Trim = Sanitizer(strings.TrimSpace)
+	// NormalizeUnicode converts to Unicode normalization form C, so the same text is the same bytes
+	// however it was typed
+	NormalizeUnicode = Sanitizer(norm.NFC.String)
+	// StripControl removes control characters, as StripControlChars does
+	StripControl = Sanitizer(StripControlChars)
+	// Lower converts to lowercase
+	Lower = Sanitizer(strings.ToLower)
+	// Upper converts to uppercase
+	Upper = Sanitizer(strings.ToUpper)
+)
+
+// sanitizers are the sanitizers Sanitize applies by name
+var sanitizers = map[string]Rule{
+	"trim":          Trim,
+	"nfc":           NormalizeUnicode,
+	"strip_control": StripControl,
+	"lower":         Lower,
+	"upper":         Upper,
+}
+
+// StripControlChars returns s without control characters, e.g. NUL and escape, except tabs and
+// newlines
+func StripControlChars(s string) string {
+	return strings.Map(func(r rune) rune {
+		if r != '\t' && r != '\n' && unicode.IsControl(r) {
+			return -1
+		}
+		return r
+	}, s)
+}
+
+// Sanitize applies the sanitizers named in the sanitize tags of the string fields of the struct ptr
+// points to, in order, e.g.
+//
+//	type SignUp struct {
+//		Email string `json:"email" sanitize:"trim,lower"`
+//		Name  string `json:"name" sanitize:"trim,nfc,strip_control"`
+//	}
+//
+// The sanitizers are trim, nfc, strip_control, lower, and upper. Fields that are pointers to strings,
+// slices of strings, or structs are sanitized too.
+func Sanitize(ptr any) error {
+	value := reflect.ValueOf(ptr)
+	if value.Kind() != reflect.Pointer || value.IsNil() || value.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("can only sanitize a pointer to a struct, got %T", ptr)
+	}
+	return sanitizeStruct(value.Elem())
+}
+
+func sanitizeStruct(value reflect.Value) error {
+	for i := range value.NumField() {
+		field := value.Type().Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		var rules []Rule
+		if tag := field.Tag.Get("sanitize"); tag != "" {
+			for _, name := range strings.Split(tag, ",") {
+				rule, ok := sanitizers[strings.TrimSpace(name)]
+				if !ok {
+					return fmt.Errorf("field %s has unknown sanitizer %q", field.Name, name)
+				}
+				rules = append(rules, rule)
+			}
+		}
+
+		if err := sanitizeValue(value.Field(i), rules); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func sanitizeValue(value reflect.Value, rules []Rule) error {
+	switch value.Kind() {
+	case reflect.String:
+		if len(rules) > 0 {
+			sanitized, _ := Apply(value.String(), rules...)
+			value.SetString(sanitized)
+		}
+	case reflect.Pointer:
+		if !value.IsNil() {
+			return sanitizeValue(value.Elem(), rules)
+		}
+	case reflect.Slice:
+		if kind := value.Type().Elem().Kind(); kind == reflect.String || kind == reflect.Struct || kind == reflect.Pointer {
+			for i := range value.Len() {
+				if err := sanitizeValue(value.Index(i), rules); err != nil {
+					return err
+				}
+			}
+		}
+	case reflect.Struct:
+		return sanitizeStruct(value)
+	}
+	return nil
+}
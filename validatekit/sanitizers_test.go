@@ -0,0 +1,76 @@
+package validatekit
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSanitizers(t *testing.T) {
+	t.Run("normalize_unicode_composes_characters", func(t *testing.T) {
+		value, _ := NormalizeUnicode("cafe\u0301")
+
+		assert.Equal(t, "caf\u00e9", value)
+	})
+
+	t.Run("strip_control_keeps_tabs_and_newlines", func(t *testing.T) {
+		value, _ := StripControl("a\x00b\x1b[31m\tc\nd\u0085")
+
+		assert.Equal(t, "ab[31m\tc\nd", value)
+	})
+}
+
+func TestSanitize(t *testing.T) {
+	type Address struct {
+		City string `sanitize:"trim,upper"`
+	}
+	type SignUp struct {
+		Email    string   `sanitize:"trim,lower"`
+		Name     *string  `sanitize:"trim,nfc,strip_control"`
+		Tags     []string `sanitize:"trim"`
+		Raw      string
+		Address  Address
+		Previous []*Address
+		private  string
+	}
+
+	t.Run("applies_the_tagged_sanitizers_to_string_fields", func(t *testing.T) {
+		theName := " René\x00 "
+		signUp := SignUp{
+			Email:    " Ada@Example.com ",
+			Name:     &theName,
+			Tags:     []string{" a ", "b "},
+			Raw:      " raw ",
+			Address:  Address{City: " london "},
+			Previous: []*Address{{City: " paris "}, nil},
+			private:  " private ",
+		}
+
+		err := Sanitize(&signUp)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "ada@example.com", signUp.Email)
+		assert.Equal(t, "René", *signUp.Name)
+		assert.Equal(t, []string{"a", "b"}, signUp.Tags)
+		assert.Equal(t, " raw ", signUp.Raw)
+		assert.Equal(t, "LONDON", signUp.Address.City)
+		assert.Equal(t, "PARIS", signUp.Previous[0].City)
+		assert.Equal(t, " private ", signUp.private)
+	})
+
+	t.Run("returns_an_error_for_an_unknown_sanitizer", func(t *testing.T) {
+		value := struct {
+			Name string `sanitize:"trim,shout"`
+		}{}
+
+		err := Sanitize(&value)
+
+		assert.EqualError(t, err, `field Name has unknown sanitizer "shout"`)
+	})
+
+	t.Run("returns_an_error_for_a_struct_that_isnt_a_pointer", func(t *testing.T) {
+		err := Sanitize(SignUp{})
+
+		assert.EqualError(t, err, "can only sanitize a pointer to a struct, got validatekit.SignUp")
+	})
+}
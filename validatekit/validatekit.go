@@ -0,0 +1,97 @@
+// Package validatekit validates and sanitizes strings with rules shared by request binding, config, and
+// domain code. A Rule checks a value, e.g. Email, or rewrites it, e.g. Trim, and rules compose with
+// Chain, so "trim, lowercase, then check it's an email" is written once:
+//
+//	email, err := validatekit.Apply(input.Email, validatekit.Trim, validatekit.Lower, validatekit.Email)
+//
+// Register adds the validators to a go-playground validator as struct tags, for the echokit and ginkit
+// binding validators, and Sanitize applies sanitizers named in sanitize struct tags.
+package validatekit
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Rule checks value, returning an *Error if it isn't valid, and returns it, possibly rewritten
+type Rule func(value string) (string, error)
+
+// Error is a value breaking a rule
+type Error struct {
+	// Rule is the name of the broken rule, e.g. "email"
+	Rule string
+	// Message says what the value must be, e.g. "must be an email address"
+	Message string
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+// Check returns a rule named name that returns an *Error with message for a value valid reports false
+// for. An empty value is valid, so optional values can be checked; add Required to disallow it.
+func Check(name string, valid func(value string) bool, message string) Rule {
+	return func(value string) (string, error) {
+		if value != "" && !valid(value) {
+			return value, &Error{Rule: name, Message: message}
+		}
+		return value, nil
+	}
+}
+
+// Chain returns a rule applying rules in order, each to the value returned by the one before, and
+// stopping at the first error
+func Chain(rules ...Rule) Rule {
+	return func(value string) (string, error) {
+		return Apply(value, rules...)
+	}
+}
+
+// Apply applies rules to value in order, as Chain does, and returns the value they return
+func Apply(value string, rules ...Rule) (string, error) {
+	for _, rule := range rules {
+		var err error
+		value, err = rule(value)
+		if err != nil {
+			return value, err
+		}
+	}
+	return value, nil
+}
+
+// Errors collects the errors of several fields, e.g. of a request's fields, by field name
+type Errors map[string]error
+
+// Check applies rules to the value of field, as Apply does, recording any error for field, and returns
+// the value they return
+func (e Errors) Check(field string, value string, rules ...Rule) string {
+	value, err := Apply(value, rules...)
+	if err != nil {
+		e[field] = err
+	}
+	return value
+}
+
+// Err returns e if it has any errors, and nil otherwise
+func (e Errors) Err() error {
+	if len(e) == 0 {
+		return nil
+	}
+	return e
+}
+
+// Error lists the errors by field, e.g. "email must be an email address; name is required"
+func (e Errors) Error() string {
+	fields := make([]string, 0, len(e))
+	for field := range e {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+
+	messages := make([]string, len(fields))
+	for i, field := range fields {
+		messages[i] = fmt.Sprintf("%s %s", field, e[field])
+	}
+	return strings.Join(messages, "; ")
+}
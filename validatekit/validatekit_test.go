@@ -0,0 +1,91 @@
+package validatekit
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheck(t *testing.T) {
+	theRule := Check("theRule", func(value string) bool { return value == "valid" }, "must be valid")
+
+	t.Run("returns_the_value_when_valid", func(t *testing.T) {
+		value, err := theRule("valid")
+
+		assert.NoError(t, err)
+		assert.Equal(t, "valid", value)
+	})
+
+	t.Run("returns_an_error_when_invalid", func(t *testing.T) {
+		_, err := theRule("invalid")
+
+		assert.Equal(t, &Error{Rule: "theRule", Message: "must be valid"}, err)
+	})
+
+	t.Run("passes_an_empty_value", func(t *testing.T) {
+		_, err := theRule("")
+
+		assert.NoError(t, err)
+	})
+}
+
+func TestApply(t *testing.T) {
+	t.Run("applies_the_rules_in_order", func(t *testing.T) {
+		value, err := Apply("  Ada@Example.com ", Trim, Lower, Email)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "ada@example.com", value)
+	})
+
+	t.Run("stops_at_the_first_error", func(t *testing.T) {
+		upperCalled := false
+		upper := Sanitizer(func(value string) string {
+			upperCalled = true
+			return value
+		})
+
+		_, err := Apply("", Required, upper)
+
+		assert.EqualError(t, err, "is required")
+		assert.False(t, upperCalled)
+	})
+}
+
+func TestChain(t *testing.T) {
+	t.Run("composes_rules_into_one", func(t *testing.T) {
+		username := Chain(Trim, Lower, Required, MinLength(3), MaxLength(8), Slug)
+
+		value, err := username(" Ada-L ")
+		assert.NoError(t, err)
+		assert.Equal(t, "ada-l", value)
+
+		_, err = username("ab")
+		assert.EqualError(t, err, "must be at least 3 characters")
+
+		_, err = Apply("ada", Chain(username, OneOf("grace", "alan")))
+		assert.EqualError(t, err, "must be one of grace, alan")
+	})
+}
+
+func TestErrors(t *testing.T) {
+	t.Run("collects_the_errors_of_each_field", func(t *testing.T) {
+		errs := Errors{}
+
+		email := errs.Check("email", " ada@example ", Trim, Email)
+		name := errs.Check("name", "", Required)
+		slug := errs.Check("slug", " the-slug", Trim, Slug)
+
+		assert.Equal(t, "ada@example", email)
+		assert.Empty(t, name)
+		assert.Equal(t, "the-slug", slug)
+		assert.EqualError(t, errs.Err(), "email must be an email address; name is required")
+	})
+
+	t.Run("err_returns_nil_without_errors", func(t *testing.T) {
+		errs := Errors{}
+
+		errs.Check("email", "ada@example.com", Email)
+
+		assert.NoError(t, errs.Err())
+	})
+}
@@ -0,0 +1,120 @@
+package validatekit
+
+import (
+	"fmt"
+	"net/mail"
+	"net/url"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+var (
+	uuidPattern = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+	// ulidPattern is 26 Crockford base32 digits, the first at most 7 so the timestamp fits in 48 bits
+	ulidPattern = regexp.MustCompile(`^[0-7][0-9A-HJKMNP-TV-Za-hjkmnp-tv-z]{25}$`)
+	e164Pattern = regexp.MustCompile(`^\+[1-9][0-9]{1,14}$`)
+	slugPattern = regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)*$`)
+	emailDomain = regexp.MustCompile(`^[A-Za-z0-9]([A-Za-z0-9-]*[A-Za-z0-9])?(\.[A-Za-z0-9]([A-Za-z0-9-]*[A-Za-z0-9])?)+$`)
+)
+
+// maxEmailLength is the longest email address that fits in an SMTP path
+const maxEmailLength = 254
+
+// The validators, which pass an empty value; see Check
+var (
+	Email   = Check("email", IsEmail, "must be an email address")
+	URL     = Check("url", IsURL, "must be an http or https URL")
+	UUID    = Check("uuid", IsUUID, "must be a UUID")
+	ULID    = Check("ulid", IsULID, "must be a ULID")
+	E164    = Check("e164", IsE164, "must be a phone number in E.164 format, e.g. +14155550123")
+	Slug    = Check("slug", IsSlug, "must be lowercase letters and digits separated by hyphens")
+	ISODate = Check("isodate", IsISODate, "must be a date in YYYY-MM-DD format")
+)
+
+// Required is a rule that an empty value breaks
+func Required(value string) (string, error) {
+	if value == "" {
+		return value, &Error{Rule: "required", Message: "is required"}
+	}
+	return value, nil
+}
+
+// MinLength returns a rule that a value shorter than n characters breaks
+func MinLength(n int) Rule {
+	return Check("min_length", func(value string) bool {
+		return utf8.RuneCountInString(value) >= n
+	}, fmt.Sprintf("must be at least %d characters", n))
+}
+
+// MaxLength returns a rule that a value longer than n characters breaks
+func MaxLength(n int) Rule {
+	return Check("max_length", func(value string) bool {
+		return utf8.RuneCountInString(value) <= n
+	}, fmt.Sprintf("must be at most %d characters", n))
+}
+
+// OneOf returns a rule that a value other than values breaks
+func OneOf(values ...string) Rule {
+	return Check("one_of", func(value string) bool {
+		return slices.Contains(values, value)
+	}, fmt.Sprintf("must be one of %s", strings.Join(values, ", ")))
+}
+
+// Matches returns a rule named name that a value not matching pattern breaks, with message
+func Matches(name string, pattern *regexp.Regexp, message string) Rule {
+	return Check(name, pattern.MatchString, message)
+}
+
+// IsEmail reports whether s is a bare email address, e.g. "ada@example.com", with a domain name of at
+// least two labels; display names and comments aren't allowed
+func IsEmail(s string) bool {
+	if len(s) > maxEmailLength {
+		return false
+	}
+	address, err := mail.ParseAddress(s)
+	if err != nil || address.Address != s || address.Name != "" {
+		return false
+	}
+	at := strings.LastIndexByte(s, '@')
+	return emailDomain.MatchString(s[at+1:])
+}
+
+// IsURL reports whether s is an absolute http or https URL with a host
+func IsURL(s string) bool {
+	u, err := url.Parse(s)
+	if err != nil {
+		return false
+	}
+	return (u.Scheme == "http" || u.Scheme == "https") && u.Host != ""
+}
+
+// IsUUID reports whether s is a UUID in its hyphenated form, in either case
+func IsUUID(s string) bool {
+	return uuidPattern.MatchString(s)
+}
+
+// IsULID reports whether s is a ULID, 26 Crockford base32 digits, in either case
+func IsULID(s string) bool {
+	return ulidPattern.MatchString(s)
+}
+
+// IsE164 reports whether s is a phone number in E.164 format: a plus and up to 15 digits, not starting
+// with zero
+func IsE164(s string) bool {
+	return e164Pattern.MatchString(s)
+}
+
+// IsSlug reports whether s is lowercase letters and digits separated by single hyphens, e.g.
+// "my-first-post"
+func IsSlug(s string) bool {
+	return slugPattern.MatchString(s)
+}
+
+// IsISODate reports whether s is a date in YYYY-MM-DD format
+func IsISODate(s string) bool {
+	_, err := time.Parse(time.DateOnly, s)
+	return err == nil
+}
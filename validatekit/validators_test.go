@@ -0,0 +1,77 @@
+package validatekit
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidators(t *testing.T) {
+	tests := []struct {
+		name    string
+		valid   func(string) bool
+		value   string
+		isValid bool
+	}{
+		{"email", IsEmail, "ada@example.com", true},
+		{"email_with_plus_and_subdomain", IsEmail, "ada+test@mail.example.co.uk", true},
+		{"email_without_a_dot_in_the_domain", IsEmail, "ada@localhost", false},
+		{"email_with_a_display_name", IsEmail, "Ada <ada@example.com>", false},
+		{"email_without_an_at", IsEmail, "ada.example.com", false},
+		{"email_too_long", IsEmail, strings.Repeat("a", 250) + "@example.com", false},
+		{"url", IsURL, "https://example.com/path?q=1", true},
+		{"url_with_http", IsURL, "http://localhost:8080", true},
+		{"url_with_another_scheme", IsURL, "ftp://example.com", false},
+		{"url_without_a_host", IsURL, "https://", false},
+		{"relative_url", IsURL, "/path", false},
+		{"uuid", IsUUID, "123e4567-e89b-12d3-a456-426614174000", true},
+		{"uppercase_uuid", IsUUID, "123E4567-E89B-12D3-A456-426614174000", true},
+		{"uuid_without_hyphens", IsUUID, "123e4567e89b12d3a456426614174000", false},
+		{"ulid", IsULID, "01ARZ3NDEKTSV4RRFFQ69G5FAV", true},
+		{"lowercase_ulid", IsULID, "01arz3ndektsv4rrffq69g5fav", true},
+		{"ulid_with_an_ambiguous_letter", IsULID, "01ARZ3NDEKTSV4RRFFQ69G5FAI", false},
+		{"ulid_overflowing_its_timestamp", IsULID, "81ARZ3NDEKTSV4RRFFQ69G5FAV", false},
+		{"e164", IsE164, "+14155550123", true},
+		{"e164_without_a_plus", IsE164, "14155550123", false},
+		{"e164_starting_with_zero", IsE164, "+04155550123", false},
+		{"e164_too_long", IsE164, "+1234567890123456", false},
+		{"slug", IsSlug, "my-first-post-2", true},
+		{"slug_with_uppercase", IsSlug, "My-Post", false},
+		{"slug_with_a_double_hyphen", IsSlug, "my--post", false},
+		{"slug_ending_with_a_hyphen", IsSlug, "my-post-", false},
+		{"isodate", IsISODate, "2024-02-29", true},
+		{"isodate_that_doesnt_exist", IsISODate, "2023-02-29", false},
+		{"isodate_in_another_format", IsISODate, "02/29/2024", false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.isValid, test.valid(test.value))
+		})
+	}
+}
+
+func TestRules(t *testing.T) {
+	t.Run("return_an_error_naming_the_rule", func(t *testing.T) {
+		_, err := E164("555-0123")
+
+		assert.Equal(t, &Error{Rule: "e164", Message: "must be a phone number in E.164 format, e.g. +14155550123"}, err)
+	})
+
+	t.Run("max_length_counts_characters", func(t *testing.T) {
+		_, err := MaxLength(4)("café")
+
+		assert.NoError(t, err)
+	})
+
+	t.Run("matches_checks_the_pattern", func(t *testing.T) {
+		sku := Matches("sku", regexp.MustCompile(`^SKU-[0-9]+$`), "must be a SKU")
+
+		_, err := sku("SKU-123")
+		assert.NoError(t, err)
+		_, err = sku("123")
+		assert.EqualError(t, err, "must be a SKU")
+	})
+}
@@ -72,3 +72,25 @@ func (bi *BuildInfo) String() string {
 		bi.GetBuildDate(),
 	)
 }
+
+// WithRuntimeDefaults returns a copy of bi with any empty fields filled in from GetBuildInfo, so a
+// binary built without ldflags, e.g. with go install, still reports its module version and commit. A
+// nil bi is treated as empty.
+func (bi *BuildInfo) WithRuntimeDefaults() *BuildInfo {
+	result := &BuildInfo{}
+	if bi != nil {
+		*result = *bi
+	}
+
+	runtimeInfo := GetBuildInfo()
+	if result.Version == "" {
+		result.Version = runtimeInfo.Version
+	}
+	if result.GitCommit == "" {
+		result.GitCommit = runtimeInfo.GitCommit
+	}
+	if result.BuildDate == "" {
+		result.BuildDate = runtimeInfo.BuildDate
+	}
+	return result
+}
@@ -184,3 +184,31 @@ func TestBuildInfoString(t *testing.T) {
 		assert.Equal(t, "version dev (commit: unknown, built: unknown)", result)
 	})
 }
+
+func TestWithRuntimeDefaults(t *testing.T) {
+	t.Run("keeps_the_fields_that_are_set", func(t *testing.T) {
+		bi := &BuildInfo{Version: "theVersion", GitCommit: "theCommit", BuildDate: "theDate"}
+
+		result := bi.WithRuntimeDefaults()
+
+		assert.Equal(t, &BuildInfo{Version: "theVersion", GitCommit: "theCommit", BuildDate: "theDate"}, result)
+	})
+
+	t.Run("fills_empty_fields_from_the_runtime_without_changing_the_original", func(t *testing.T) {
+		bi := &BuildInfo{Version: "theVersion"}
+		runtimeInfo := GetBuildInfo()
+
+		result := bi.WithRuntimeDefaults()
+
+		assert.Equal(t, &BuildInfo{Version: "theVersion", GitCommit: runtimeInfo.GitCommit, BuildDate: runtimeInfo.BuildDate}, result)
+		assert.Equal(t, &BuildInfo{Version: "theVersion"}, bi)
+	})
+
+	t.Run("treats_a_nil_build_info_as_empty", func(t *testing.T) {
+		var bi *BuildInfo
+
+		result := bi.WithRuntimeDefaults()
+
+		assert.Equal(t, GetBuildInfo(), result)
+	})
+}
@@ -0,0 +1,137 @@
+package versionkit
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// The headers services advertise their build in
+const (
+	HeaderServiceVersion = "X-Service-Version"
+	HeaderServiceCommit  = "X-Service-Commit"
+)
+
+// ErrNoPeerVersion is returned by Handshake.Check when the peer didn't advertise a version and one is
+// required
+var ErrNoPeerVersion = errors.New("peer did not advertise a version")
+
+// IncompatibleVersionError is returned by Handshake.Check when the peer is older than the minimum
+type IncompatibleVersionError struct {
+	PeerVersion    string
+	MinimumVersion string
+}
+
+func (e *IncompatibleVersionError) Error() string {
+	return fmt.Sprintf("peer version %s is older than the minimum %s", e.PeerVersion, e.MinimumVersion)
+}
+
+// Handshake advertises a service's build to its peers and checks theirs, so a breaking internal API
+// change can require callers (or servers) to be upgraded first. Use it with echokit.VersionHandshake,
+// ginkit.VersionHandshake, and Transport.
+type Handshake struct {
+	info     *BuildInfo
+	minimum  string
+	required bool
+}
+
+type HandshakeOption func(*Handshake)
+
+// WithMinimumPeerVersion rejects peers that advertise a version older than minimum, e.g. "v1.4.0".
+// Peers running dev builds are always accepted.
+func WithMinimumPeerVersion(minimum string) HandshakeOption {
+	return func(h *Handshake) {
+		h.minimum = minimum
+	}
+}
+
+// WithPeerVersionRequired rejects peers that don't advertise a version. By default they're accepted,
+// so clients that aren't services, and services not yet using the handshake, still work.
+func WithPeerVersionRequired() HandshakeOption {
+	return func(h *Handshake) {
+		h.required = true
+	}
+}
+
+// NewHandshake returns a Handshake advertising info, e.g. from GetBuildInfo
+func NewHandshake(info *BuildInfo, options ...HandshakeOption) (*Handshake, error) {
+	h := &Handshake{info: info}
+	for _, option := range options {
+		option(h)
+	}
+
+	if h.minimum != "" {
+		if _, err := parseAdvertisedVersion(h.minimum); err != nil {
+			return nil, fmt.Errorf("invalid minimum peer version: %w", err)
+		}
+	}
+
+	return h, nil
+}
+
+// SetHeaders advertises the build in header
+func (h *Handshake) SetHeaders(header http.Header) {
+	header.Set(HeaderServiceVersion, h.info.GetBuildVersion())
+	header.Set(HeaderServiceCommit, h.info.GetBuildCommit())
+}
+
+// Check returns an error if the build advertised in header doesn't meet the minimum version
+func (h *Handshake) Check(header http.Header) error {
+	peerVersion := header.Get(HeaderServiceVersion)
+	if peerVersion == "" {
+		if h.required {
+			return ErrNoPeerVersion
+		}
+		return nil
+	}
+	if h.minimum == "" || peerVersion == "dev" {
+		return nil
+	}
+
+	peer, err := parseAdvertisedVersion(peerVersion)
+	minimum, _ := parseAdvertisedVersion(h.minimum)
+	if err != nil || peer.Compare(*minimum) < 0 {
+		return &IncompatibleVersionError{PeerVersion: peerVersion, MinimumVersion: h.minimum}
+	}
+	return nil
+}
+
+// parseAdvertisedVersion parses a version with or without a leading "v", e.g. a Go module version
+// like "v1.2.3"
+func parseAdvertisedVersion(version string) (*SemanticVersion, error) {
+	return ParseSemanticVersion(strings.TrimPrefix(version, "v"))
+}
+
+// Transport returns a RoundTripper that advertises the build on each request and checks the build the
+// server advertises in its response, returning Check's error instead of the response if it fails. If
+// base is nil, http.DefaultTransport is used.
+func (h *Handshake) Transport(base http.RoundTripper) http.RoundTripper {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return &handshakeTransport{handshake: h, base: base}
+}
+
+type handshakeTransport struct {
+	handshake *Handshake
+	base      http.RoundTripper
+}
+
+func (t *handshakeTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	// A RoundTripper mustn't modify the request it's given
+	req = req.Clone(req.Context())
+	t.handshake.SetHeaders(req.Header)
+
+	res, err := t.base.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := t.handshake.Check(res.Header); err != nil {
+		res.Body.Close()
+		return nil, fmt.Errorf("version handshake with %s failed: %w", req.URL.Host, err)
+	}
+
+	return res, nil
+}
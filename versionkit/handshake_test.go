@@ -0,0 +1,122 @@
+package versionkit
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHandshakeSetHeaders(t *testing.T) {
+	t.Run("advertises_the_build", func(t *testing.T) {
+		handshake, _ := NewHandshake(&BuildInfo{Version: "v1.2.3", GitCommit: "theCommit"})
+		header := http.Header{}
+
+		handshake.SetHeaders(header)
+
+		assert.Equal(t, "v1.2.3", header.Get(HeaderServiceVersion))
+		assert.Equal(t, "theCommit", header.Get(HeaderServiceCommit))
+	})
+
+	t.Run("advertises_dev_for_an_unversioned_build", func(t *testing.T) {
+		handshake, _ := NewHandshake(&BuildInfo{})
+		header := http.Header{}
+
+		handshake.SetHeaders(header)
+
+		assert.Equal(t, "dev", header.Get(HeaderServiceVersion))
+		assert.Equal(t, "unknown", header.Get(HeaderServiceCommit))
+	})
+}
+
+func peerHeader(version string) http.Header {
+	header := http.Header{}
+	if version != "" {
+		header.Set(HeaderServiceVersion, version)
+	}
+	return header
+}
+
+func TestHandshakeCheck(t *testing.T) {
+	handshake, err := NewHandshake(&BuildInfo{}, WithMinimumPeerVersion("v1.4.0"))
+	assert.NoError(t, err)
+
+	t.Run("accepts_peers_at_or_above_the_minimum", func(t *testing.T) {
+		assert.NoError(t, handshake.Check(peerHeader("v1.4.0")))
+		assert.NoError(t, handshake.Check(peerHeader("1.10.0")))
+		assert.NoError(t, handshake.Check(peerHeader("v2.0.0-rc.1")))
+	})
+
+	t.Run("rejects_older_peers", func(t *testing.T) {
+		for _, version := range []string{"v1.3.9", "v1.4.0-rc.1", "notAVersion"} {
+			err := handshake.Check(peerHeader(version))
+
+			var incompatibleErr *IncompatibleVersionError
+			assert.True(t, errors.As(err, &incompatibleErr), version)
+			assert.Equal(t, version, incompatibleErr.PeerVersion)
+		}
+		assert.EqualError(t, handshake.Check(peerHeader("v1.3.9")), "peer version v1.3.9 is older than the minimum v1.4.0")
+	})
+
+	t.Run("accepts_dev_builds", func(t *testing.T) {
+		assert.NoError(t, handshake.Check(peerHeader("dev")))
+	})
+
+	t.Run("accepts_peers_without_a_version_unless_required", func(t *testing.T) {
+		required, _ := NewHandshake(&BuildInfo{}, WithPeerVersionRequired())
+
+		assert.NoError(t, handshake.Check(peerHeader("")))
+		assert.ErrorIs(t, required.Check(peerHeader("")), ErrNoPeerVersion)
+		assert.NoError(t, required.Check(peerHeader("v0.0.1")))
+	})
+}
+
+func TestNewHandshake(t *testing.T) {
+	t.Run("returns_an_error_for_an_invalid_minimum", func(t *testing.T) {
+		_, err := NewHandshake(&BuildInfo{}, WithMinimumPeerVersion("latest"))
+
+		assert.ErrorContains(t, err, "invalid minimum peer version")
+	})
+}
+
+func TestHandshakeTransport(t *testing.T) {
+	t.Run("advertises_the_build_and_checks_the_server", func(t *testing.T) {
+		var actualHeader http.Header
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			actualHeader = r.Header
+			w.Header().Set(HeaderServiceVersion, "v2.0.0")
+			io.WriteString(w, "theResponse")
+		}))
+		defer server.Close()
+		handshake, _ := NewHandshake(&BuildInfo{Version: "v1.0.0"}, WithMinimumPeerVersion("v2.0.0"))
+		client := &http.Client{Transport: handshake.Transport(nil)}
+		req, _ := http.NewRequest(http.MethodGet, server.URL, nil)
+
+		res, err := client.Do(req)
+
+		assert.NoError(t, err)
+		body, _ := io.ReadAll(res.Body)
+		res.Body.Close()
+		assert.Equal(t, "theResponse", string(body))
+		assert.Equal(t, "v1.0.0", actualHeader.Get(HeaderServiceVersion))
+		assert.Empty(t, req.Header.Get(HeaderServiceVersion))
+	})
+
+	t.Run("returns_an_error_for_an_old_server", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set(HeaderServiceVersion, "v1.9.0")
+		}))
+		defer server.Close()
+		handshake, _ := NewHandshake(&BuildInfo{}, WithMinimumPeerVersion("v2.0.0"))
+		client := &http.Client{Transport: handshake.Transport(http.DefaultTransport)}
+
+		_, err := client.Get(server.URL)
+
+		var incompatibleErr *IncompatibleVersionError
+		assert.True(t, errors.As(err, &incompatibleErr))
+		assert.ErrorContains(t, err, "version handshake with 127.0.0.1")
+	})
+}
@@ -0,0 +1,83 @@
+package versionkit
+
+import (
+	"fmt"
+	"strings"
+)
+
+// The variable names stamped by LDFlags. A main package declares them as in cmd/pgkit:
+//
+//	var (
+//		version   = ""
+//		gitCommit = ""
+//		buildDate = ""
+//	)
+const (
+	VersionVariable   = "version"
+	GitCommitVariable = "gitCommit"
+	BuildDateVariable = "buildDate"
+)
+
+// LDFlags returns the linker flags (the value for go build -ldflags) that set pkg's version, gitCommit,
+// and buildDate variables to the values in info. pkg is "main" for a binary's main package, or the full
+// import path for any other package. Empty values are skipped.
+func LDFlags(pkg string, info BuildInfo) string {
+	var flags []string
+	for _, v := range []struct{ name, value string }{
+		{VersionVariable, info.Version},
+		{GitCommitVariable, info.GitCommit},
+		{BuildDateVariable, info.BuildDate},
+	} {
+		if v.value == "" {
+			continue
+		}
+		flag := pkg + "." + v.name + "=" + v.value
+		if strings.ContainsAny(flag, " \t") {
+			flag = "'" + flag + "'"
+		}
+		flags = append(flags, "-X "+flag)
+	}
+
+	return strings.Join(flags, " ")
+}
+
+// MakefileSnippet returns Makefile variables and a build-<binary> target that stamp pkg's version
+// variables from git, the same way the go-kit Makefile builds cmd/pgkit
+func MakefileSnippet(pkg string, binary string, mainPath string) string {
+	ldflags := LDFlags(pkg, BuildInfo{
+		Version:   "$(VERSION)",
+		GitCommit: "$(GIT_COMMIT)",
+		BuildDate: "$(BUILD_DATE)",
+	})
+
+	return fmt.Sprintf(`VERSION ?= $(shell git describe --tags --always --dirty 2>/dev/null || echo "dev")
+GIT_COMMIT ?= $(shell git rev-parse --short HEAD 2>/dev/null || echo "unknown")
+BUILD_DATE ?= $(shell date -u +'%%Y-%%m-%%dT%%H:%%M:%%SZ')
+
+LDFLAGS := -ldflags "%s"
+
+build-%s:
+	@mkdir -p bin
+	go build $(LDFLAGS) -o bin/%s %s
+`, ldflags, binary, binary, mainPath)
+}
+
+// GoreleaserSnippet returns a goreleaser builds entry that stamps pkg's version variables from
+// goreleaser's release metadata
+func GoreleaserSnippet(pkg string, binary string, mainPath string) string {
+	ldflags := LDFlags(pkg, BuildInfo{
+		Version:   "{{.Version}}",
+		GitCommit: "{{.ShortCommit}}",
+		BuildDate: "{{.Date}}",
+	})
+
+	return fmt.Sprintf(`builds:
+  - id: %s
+    binary: %s
+    main: %s
+    env:
+      - CGO_ENABLED=0
+    ldflags:
+      - -s -w %s
+`, binary, binary, mainPath, ldflags)
+}
@@ -0,0 +1,53 @@
+package versionkit
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLDFlags(t *testing.T) {
+	t.Run("sets_all_version_variables_in_main", func(t *testing.T) {
+		result := LDFlags("main", BuildInfo{Version: "v1.2.3", GitCommit: "abc1234", BuildDate: "2024-01-02T03:04:05Z"})
+
+		assert.Equal(t, "-X main.version=v1.2.3 -X main.gitCommit=abc1234 -X main.buildDate=2024-01-02T03:04:05Z", result)
+	})
+
+	t.Run("uses_the_import_path_for_other_packages", func(t *testing.T) {
+		result := LDFlags("github.com/theOrg/theRepo/internal/version", BuildInfo{Version: "v1.2.3"})
+
+		assert.Equal(t, "-X github.com/theOrg/theRepo/internal/version.version=v1.2.3", result)
+	})
+
+	t.Run("skips_empty_values", func(t *testing.T) {
+		result := LDFlags("main", BuildInfo{GitCommit: "abc1234"})
+
+		assert.Equal(t, "-X main.gitCommit=abc1234", result)
+	})
+
+	t.Run("quotes_values_with_spaces", func(t *testing.T) {
+		result := LDFlags("main", BuildInfo{BuildDate: "Tue Jan 2 2024"})
+
+		assert.Equal(t, "-X 'main.buildDate=Tue Jan 2 2024'", result)
+	})
+}
+
+func TestMakefileSnippet(t *testing.T) {
+	t.Run("generates_variables_and_a_build_target", func(t *testing.T) {
+		result := MakefileSnippet("main", "theBinary", "./cmd/theBinary")
+
+		assert.Contains(t, result, `VERSION ?= $(shell git describe --tags --always --dirty 2>/dev/null || echo "dev")`)
+		assert.Contains(t, result, `BUILD_DATE ?= $(shell date -u +'%Y-%m-%dT%H:%M:%SZ')`)
+		assert.Contains(t, result, `LDFLAGS := -ldflags "-X main.version=$(VERSION) -X main.gitCommit=$(GIT_COMMIT) -X main.buildDate=$(BUILD_DATE)"`)
+		assert.Contains(t, result, "build-theBinary:\n\t@mkdir -p bin\n\tgo build $(LDFLAGS) -o bin/theBinary ./cmd/theBinary\n")
+	})
+}
+
+func TestGoreleaserSnippet(t *testing.T) {
+	t.Run("generates_a_build_with_ldflags", func(t *testing.T) {
+		result := GoreleaserSnippet("main", "theBinary", "./cmd/theBinary")
+
+		assert.Contains(t, result, "  - id: theBinary\n    binary: theBinary\n    main: ./cmd/theBinary\n")
+		assert.Contains(t, result, "      - -s -w -X main.version={{.Version}} -X main.gitCommit={{.ShortCommit}} -X main.buildDate={{.Date}}\n")
+	})
+}